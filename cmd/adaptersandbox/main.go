@@ -0,0 +1,144 @@
+// Command adaptersandbox records the REST and websocket traffic between an adapter and a live
+// venue into a fixture file, and serves fixtures back in place of the venue, so adapter changes
+// can be tested offline against the same venue messages every time.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
+)
+
+const (
+	defaultListen   = "127.0.0.1:8089"
+	shutdownTimeout = 5 * time.Second
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := newRootCommand(os.Stdout, os.Stderr).ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		stop()
+		os.Exit(1) //nolint:gocritic // stop already ran
+	}
+}
+
+// app carries the output streams into subcommands.
+type app struct {
+	listen string
+	stdout io.Writer
+	// ready, when set, receives the bound address once the server listens.
+	ready func(addr string)
+}
+
+func newRootCommand(stdout, stderr io.Writer) *cobra.Command {
+	a := &app{listen: defaultListen, stdout: stdout, ready: nil}
+	root := &cobra.Command{
+		Use:           "adaptersandbox",
+		Short:         "Record venue traffic into fixtures and replay it for offline adapter tests",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.PersistentFlags().StringVar(&a.listen, "listen", defaultListen, "Address the sandbox listens on")
+	root.AddCommand(a.newRecordCommand(), a.newReplayCommand())
+	return root
+}
+
+func (a *app) newRecordCommand() *cobra.Command {
+	var restURL, websocketURL, out, proxy string
+	cmd := &cobra.Command{
+		Use:   "record",
+		Short: "Proxy an adapter to a live venue and write the traffic to a fixture on exit",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(out) == "" {
+				return fmt.Errorf("--out required")
+			}
+			proxyCfg, err := shared.ParseProxyConfig(proxy)
+			if err != nil {
+				return fmt.Errorf("invalid --proxy: %w", err)
+			}
+			recorder, err := shared.NewSandboxRecorder(restURL, websocketURL, shared.NewEgress(proxyCfg))
+			if err != nil {
+				return err
+			}
+			serveErr := a.serve(cmd.Context(), recorder, "recording")
+			fixture := recorder.Fixture()
+			if err := fixture.Save(out); err != nil {
+				return errors.Join(serveErr, err)
+			}
+			fmt.Fprintf(a.stdout, "%s: %d REST exchanges, %d websocket sessions\n", out, len(fixture.REST), len(fixture.Websockets))
+			return serveErr
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&restURL, "rest-url", "", "Venue REST base URL, e.g. https://api.binance.com")
+	flags.StringVar(&websocketURL, "ws-url", "", "Venue websocket base URL, e.g. wss://stream.binance.com:9443")
+	flags.StringVar(&out, "out", "", "Fixture file to write (required)")
+	flags.StringVar(&proxy, "proxy", "", "Egress proxy URL for reaching the venue")
+	return cmd
+}
+
+func (a *app) newReplayCommand() *cobra.Command {
+	var fixturePath string
+	var realtime bool
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Serve a fixture in place of the venue",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if strings.TrimSpace(fixturePath) == "" {
+				return fmt.Errorf("--fixture required")
+			}
+			fixture, err := shared.LoadSandboxFixture(fixturePath)
+			if err != nil {
+				return err
+			}
+			return a.serve(cmd.Context(), shared.NewSandboxReplayer(fixture, realtime), "replaying "+fixturePath)
+		},
+	}
+	cmd.Flags().StringVar(&fixturePath, "fixture", "", "Fixture file to serve (required)")
+	cmd.Flags().BoolVar(&realtime, "realtime", false, "Pace websocket frames as recorded instead of sending them at once")
+	return cmd
+}
+
+// serve runs handler until ctx is cancelled.
+func (a *app) serve(ctx context.Context, handler http.Handler, mode string) error {
+	listener, err := net.Listen("tcp", a.listen)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second} //nolint:exhaustruct // defaults suit a local sandbox
+	addr := listener.Addr().String()
+	fmt.Fprintf(a.stdout, "%s on http://%s (ws://%s); point the adapter's REST and websocket base URLs here, Ctrl-C to stop\n", mode, addr, addr)
+	errs := make(chan error, 1)
+	go func() { errs <- server.Serve(listener) }()
+	if a.ready != nil {
+		a.ready(addr)
+	}
+	select {
+	case err := <-errs:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	// Websocket sessions are hijacked and outlive Shutdown; closing drops them.
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		_ = server.Close()
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
+)
+
+func TestRecordRequiresOutAndURLs(t *testing.T) {
+	var stdout bytes.Buffer
+	cmd := newRootCommand(&stdout, io.Discard)
+	cmd.SetArgs([]string{"record", "--rest-url", "https://api.example.com"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--out") {
+		t.Fatalf("expected --out error, got %v", err)
+	}
+	cmd = newRootCommand(&stdout, io.Discard)
+	cmd.SetArgs([]string{"record", "--out", filepath.Join(t.TempDir(), "f.json")})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error without venue URLs")
+	}
+	cmd = newRootCommand(&stdout, io.Discard)
+	cmd.SetArgs([]string{"replay"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--fixture") {
+		t.Fatalf("expected --fixture error, got %v", err)
+	}
+}
+
+func TestReplayServesFixture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	fixture := &shared.SandboxFixture{
+		REST: []shared.RecordedExchange{{
+			Method: http.MethodGet,
+			Path:   "/api/v3/time",
+			Status: http.StatusOK,
+			Body:   `{"serverTime":1}`,
+		}},
+	}
+	if err := fixture.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stdout bytes.Buffer
+	var body string
+	var getErr error
+	a := &app{listen: "127.0.0.1:0", stdout: &stdout, ready: func(addr string) {
+		defer cancel()
+		resp, err := http.Get("http://" + addr + "/api/v3/time")
+		if err != nil {
+			getErr = err
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		data, _ := io.ReadAll(resp.Body)
+		body = string(data)
+	}}
+	cmd := a.newReplayCommand()
+	cmd.SetArgs([]string{"--fixture", path})
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if getErr != nil || body != `{"serverTime":1}` {
+		t.Fatalf("expected the recorded body, got %q (%v)", body, getErr)
+	}
+	if !strings.Contains(stdout.String(), "replaying "+path) {
+		t.Fatalf("expected a startup line, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,310 @@
+// Command backtest replays historical market data downloaded by fetchdata through strategy
+// revisions against a simulated venue, and compares two revisions side by side.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/coachpo/meltica/internal/app/backtest"
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+)
+
+const (
+	defaultStrategiesDir = "strategies"
+	defaultFeeBps        = "10"
+	defaultTradeDiffs    = 20
+)
+
+func main() {
+	if err := newRootCommand(os.Stdout, os.Stderr).Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// globalOptions holds the persistent flags shared by every subcommand.
+type globalOptions struct {
+	strategies string
+	data       string
+	provider   string
+	symbol     string
+	configPath string
+	seed       int64
+	feeBps     string
+	funding    string
+	borrow     string
+	output     string
+	report     string
+	verbose    bool
+}
+
+// app carries resolved options and output streams into subcommands.
+type app struct {
+	opts   globalOptions
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func newRootCommand(stdout, stderr io.Writer) *cobra.Command {
+	a := &app{
+		opts: globalOptions{
+			strategies: defaultStrategiesDir,
+			data:       "",
+			provider:   "",
+			symbol:     "",
+			configPath: "",
+			seed:       backtest.DefaultSeed,
+			feeBps:     defaultFeeBps,
+			funding:    "",
+			borrow:     "",
+			output:     string(outputTable),
+			report:     "",
+			verbose:    false,
+		},
+		stdout: stdout,
+		stderr: stderr,
+	}
+	root := &cobra.Command{
+		Use:           "backtest",
+		Short:         "Backtest strategy revisions over historical market data",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			_, err := parseOutputFormat(a.opts.output)
+			return err
+		},
+	}
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.opts.strategies, "strategies", defaultStrategiesDir, "Strategy directory holding the revision registry")
+	flags.StringVar(&a.opts.data, "data", "", "Kline or trade CSV written by fetchdata (required)")
+	flags.StringVar(&a.opts.provider, "provider", "", "Provider of the data (default: inferred from <provider>/<symbol>/<file>)")
+	flags.StringVar(&a.opts.symbol, "symbol", "", "Symbol of the data (default: inferred from <provider>/<symbol>/<file>)")
+	flags.StringVarP(&a.opts.configPath, "config", "c", "", "Strategy config file, JSON or YAML")
+	flags.Int64Var(&a.opts.seed, "seed", backtest.DefaultSeed, "Math.random seed, unless the config sets one")
+	flags.StringVar(&a.opts.feeBps, "fee-bps", defaultFeeBps, "Fee charged on the notional of every fill, in basis points")
+	flags.StringVar(&a.opts.funding, "funding", "", "Funding rate CSV (timestamp,rate) settled against the open position at each timestamp")
+	flags.StringVar(&a.opts.borrow, "borrow", "", "Annualised borrow rate CSV (timestamp,rate) charged on borrowed quote for longs and borrowed base for shorts")
+	flags.StringVarP(&a.opts.output, "output", "o", string(outputTable), "Output format: table or json")
+	flags.StringVar(&a.opts.report, "report", "", "Also write the full JSON report, including curves and fills, to this file")
+	flags.BoolVarP(&a.opts.verbose, "verbose", "v", false, "Show strategy logs on stderr")
+	_ = root.MarkPersistentFlagRequired("data")
+
+	root.AddCommand(a.newRunCommand(), a.newCompareCommand())
+	return root
+}
+
+func (a *app) newRunCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run <selector>",
+		Short: "Backtest one revision, e.g. grid@<hash> or grid:stable",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			env, err := a.prepare(cmd.Context())
+			if err != nil {
+				return err
+			}
+			result, err := env.run(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if err := a.writeReport(result); err != nil {
+				return err
+			}
+			return a.render(result, func(w io.Writer) error { return writeRunTable(w, result) })
+		},
+	}
+}
+
+func (a *app) newCompareCommand() *cobra.Command {
+	var baseline, candidate string
+	var tradeDiffs int
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Backtest two revisions over the same data and report their differences",
+		Long: "Runs the baseline and candidate revisions over the same dataset, seed and fees and reports " +
+			"PnL and drawdown side by side, the fills that differ and a paired t-test on the per-row PnL " +
+			"changes, so a tag is promoted on evidence.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			env, err := a.prepare(cmd.Context())
+			if err != nil {
+				return err
+			}
+			base, err := env.run(cmd.Context(), baseline)
+			if err != nil {
+				return fmt.Errorf("baseline: %w", err)
+			}
+			cand, err := env.run(cmd.Context(), candidate)
+			if err != nil {
+				return fmt.Errorf("candidate: %w", err)
+			}
+			comparison, err := backtest.Compare(base, cand)
+			if err != nil {
+				return err
+			}
+			if err := a.writeReport(comparison); err != nil {
+				return err
+			}
+			return a.render(comparison, func(w io.Writer) error { return writeComparisonTable(w, comparison, tradeDiffs) })
+		},
+	}
+	cmd.Flags().StringVar(&baseline, "baseline", "", "Baseline revision, e.g. grid@<hashA> (required)")
+	cmd.Flags().StringVar(&candidate, "candidate", "", "Candidate revision, e.g. grid@<hashB> (required)")
+	cmd.Flags().IntVar(&tradeDiffs, "trade-diffs", defaultTradeDiffs, "Differing fills listed in table output")
+	_ = cmd.MarkFlagRequired("baseline")
+	_ = cmd.MarkFlagRequired("candidate")
+	return cmd
+}
+
+// environment holds what both runs of a comparison share.
+type environment struct {
+	loader  *js.Loader
+	data    *backtest.Dataset
+	config  map[string]any
+	feeBps  decimal.Decimal
+	funding *backtest.RateSeries
+	borrow  *backtest.RateSeries
+	logger  *log.Logger
+}
+
+func (a *app) prepare(ctx context.Context) (*environment, error) {
+	feeBps, err := decimal.NewFromString(strings.TrimSpace(a.opts.feeBps))
+	if err != nil || feeBps.IsNegative() {
+		return nil, fmt.Errorf("invalid --fee-bps %q", a.opts.feeBps)
+	}
+	provider, symbol := datasetIdentity(a.opts.data, a.opts.provider, a.opts.symbol)
+	data, err := backtest.LoadDataset(a.opts.data, provider, symbol)
+	if err != nil {
+		return nil, err
+	}
+	funding, err := loadRates(a.opts.funding, "funding")
+	if err != nil {
+		return nil, err
+	}
+	borrow, err := loadRates(a.opts.borrow, "borrow")
+	if err != nil {
+		return nil, err
+	}
+	config, err := loadConfig(a.opts.configPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := js.SeedFromConfig(config); !ok {
+		config[js.SeedConfigKey] = a.opts.seed
+	}
+	loader, err := js.NewLoader(a.opts.strategies)
+	if err != nil {
+		return nil, fmt.Errorf("open strategies: %w", err)
+	}
+	if err := loader.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("load strategies: %w", err)
+	}
+	logger := log.New(io.Discard, "", 0)
+	if a.opts.verbose {
+		logger = log.New(a.stderr, "", 0)
+	}
+	return &environment{loader: loader, data: data, config: config, feeBps: feeBps, funding: funding, borrow: borrow, logger: logger}, nil
+}
+
+func (e *environment) run(ctx context.Context, selector string) (backtest.Result, error) {
+	selector = strings.TrimSpace(selector)
+	module, err := e.loader.Get(selector)
+	if err != nil {
+		return backtest.Result{}, fmt.Errorf("resolve %s: %w", selector, err)
+	}
+	return backtest.Run(ctx, backtest.RunConfig{
+		Selector: selector,
+		Module:   module,
+		Config:   e.config,
+		Data:     e.data,
+		FeeBps:   e.feeBps,
+		Funding:  e.funding,
+		Borrow:   e.borrow,
+		Logger:   e.logger,
+	})
+}
+
+// datasetIdentity fills a missing provider or symbol from a fetchdata path,
+// <out>/<provider>/<SYMBOL>/<file>.
+func datasetIdentity(path, provider, symbol string) (string, string) {
+	dir := filepath.Dir(filepath.Clean(path))
+	if strings.TrimSpace(symbol) == "" {
+		symbol = filepath.Base(dir)
+	}
+	if strings.TrimSpace(provider) == "" {
+		provider = filepath.Base(filepath.Dir(dir))
+	}
+	return provider, symbol
+}
+
+// loadRates reads an optional rate series flag.
+func loadRates(path, kind string) (*backtest.RateSeries, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	series, err := backtest.LoadRateSeries(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s rates: %w", kind, err)
+	}
+	return series, nil
+}
+
+func loadConfig(path string) (map[string]any, error) {
+	config := map[string]any{}
+	if strings.TrimSpace(path) == "" {
+		return config, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- operator supplied config file
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if config == nil {
+		config = map[string]any{}
+	}
+	return config, nil
+}
+
+func (a *app) render(value any, table func(io.Writer) error) error {
+	format, err := parseOutputFormat(a.opts.output)
+	if err != nil {
+		return err
+	}
+	if format == outputJSON {
+		return writeJSONValue(a.stdout, value)
+	}
+	return table(a.stdout)
+}
+
+func (a *app) writeReport(value any) error {
+	if strings.TrimSpace(a.opts.report) == "" {
+		return nil
+	}
+	file, err := os.Create(a.opts.report)
+	if err != nil {
+		return fmt.Errorf("create report: %w", err)
+	}
+	if err := writeJSONValue(file, value); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/backtest"
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+)
+
+const dipBuyerSource = `
+module.exports = {
+  metadata: {
+    name: "dip_buyer",
+    version: "%s",
+    displayName: "Dip Buyer",
+    description: "Buys below a level and sells the rally.",
+    config: [{ name: "size", type: "string", required: false }],
+    events: ["KlineSummary", "ExecReport"]
+  },
+  create: function(env) {
+    var size = env.config.size || "1";
+    var position = 0;
+    var pending = false;
+    return {
+      onKlineSummary: function(ctx, evt, payload) {
+        if (pending) return;
+        var close = parseFloat(payload.closePrice);
+        if (position === 0 && close <= %d) {
+          pending = true;
+          env.runtime.submitMarketOrder("", "buy", size);
+        } else if (position > 0 && close >= 110) {
+          pending = true;
+          env.runtime.submitMarketOrder("", "sell", size);
+        }
+      },
+      onOrderAcknowledged: function() {},
+      onOrderFilled: function(ctx, evt, payload) {
+        pending = false;
+        position += String(payload.side) === "Buy" ? 1 : -1;
+      }
+    };
+  }
+};
+`
+
+// setup stores two dip_buyer revisions and a kline file, returning their selectors and the data path.
+func setup(t *testing.T) (string, string, string, string) {
+	t.Helper()
+	root := t.TempDir()
+	strategiesDir := filepath.Join(root, "strategies")
+	loader, err := js.NewLoader(strategiesDir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	selectors := make([]string, 0, 2)
+	for i, level := range []int{100, 90} {
+		version := fmt.Sprintf("1.%d.0", i)
+		res, err := loader.Store([]byte(fmt.Sprintf(dipBuyerSource, version, level)), js.ModuleWriteOptions{Tag: "v" + version})
+		if err != nil {
+			t.Fatalf("store revision: %v", err)
+		}
+		selectors = append(selectors, "dip_buyer@"+res.Hash)
+	}
+
+	dataPath := filepath.Join(root, "data", "binance", "BTC-USDT", "klines_1m.csv")
+	if err := os.MkdirAll(filepath.Dir(dataPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	var b strings.Builder
+	b.WriteString(strings.Join(backtest.KlineColumns, ",") + "\n")
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 30; i++ {
+		price := []int{100, 90, 110}[i%3]
+		open := base.Add(time.Duration(i) * time.Minute)
+		fmt.Fprintf(&b, "%d,%d,%d,%d,%d,%d,5\n", open.UnixMilli(), open.Add(time.Minute-time.Millisecond).UnixMilli(), price, price, price, price)
+	}
+	if err := os.WriteFile(dataPath, []byte(b.String()), 0o644); err != nil {
+		t.Fatalf("write data: %v", err)
+	}
+	return strategiesDir, selectors[0], selectors[1], dataPath
+}
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	cmd := newRootCommand(&stdout, &stderr)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+func TestCompareCommandReportsDifferences(t *testing.T) {
+	strategiesDir, baseline, candidate, dataPath := setup(t)
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+
+	out, err := execute(t, "compare", "--strategies", strategiesDir, "--data", dataPath, "--baseline", baseline, "--candidate", candidate, "--report", reportPath, "--trade-diffs", "2")
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	for _, want := range []string{"BASELINE " + baseline, "CANDIDATE " + candidate, "pnl", "max drawdown", "Paired t-test on per-row PnL changes (n=30)", "Differing fills (2 of"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	var report backtest.Comparison
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if len(report.Curve) != 30 || !report.PnLDiff.IsPositive() || report.Baseline.Fills == 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+}
+
+func TestRunCommandUsesConfigAndInfersDataset(t *testing.T) {
+	strategiesDir, baseline, _, dataPath := setup(t)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("size: \"2\"\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	out, err := execute(t, "run", baseline, "--strategies", strategiesDir, "--data", dataPath, "-c", configPath, "-o", "json", "--fee-bps", "0")
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	var result backtest.Result
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("decode result: %v\n%s", err, out)
+	}
+	// Ten dips bought at 100 and sold at 110, two units each.
+	if result.Summary.Fills != 20 || result.Summary.PnL.String() != "200" || result.Fills[0].Quantity.String() != "2" {
+		t.Fatalf("unexpected result %+v", result.Summary)
+	}
+
+	if _, err := execute(t, "run", "dip_buyer@missing", "--strategies", strategiesDir, "--data", dataPath); err == nil {
+		t.Fatal("expected unknown revision rejected")
+	}
+	if _, err := execute(t, "run", baseline, "--strategies", strategiesDir, "--data", dataPath, "--fee-bps", "-1"); err == nil {
+		t.Fatal("expected negative fee rejected")
+	}
+}
+
+func TestDatasetIdentity(t *testing.T) {
+	provider, symbol := datasetIdentity(filepath.Join("data", "okx", "ETH-USDT", "trades.csv"), "", "")
+	if provider != "okx" || symbol != "ETH-USDT" {
+		t.Fatalf("inferred %s %s", provider, symbol)
+	}
+	if provider, symbol = datasetIdentity("trades.csv", "binance", "BTC-USDT"); provider != "binance" || symbol != "BTC-USDT" {
+		t.Fatalf("explicit flags ignored: %s %s", provider, symbol)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/app/backtest"
+)
+
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+)
+
+func parseOutputFormat(raw string) (outputFormat, error) {
+	switch outputFormat(strings.ToLower(strings.TrimSpace(raw))) {
+	case "", outputTable:
+		return outputTable, nil
+	case outputJSON:
+		return outputJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported output format %q (want table or json)", raw)
+	}
+}
+
+func writeTable(w io.Writer, headers []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("write table: %w", err)
+	}
+	return nil
+}
+
+func writeJSONValue(w io.Writer, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode output: %w", err)
+	}
+	data = append(data, '\n')
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func summaryRows(s backtest.Summary) [][]string {
+	return [][]string{
+		{"pnl", s.PnL.StringFixed(4)},
+		{"max drawdown", s.MaxDrawdown.StringFixed(4)},
+		{"fills", strconv.Itoa(s.Fills)},
+		{"volume", s.Volume.StringFixed(4)},
+		{"fees", s.Fees.StringFixed(4)},
+		{"funding", s.Funding.StringFixed(4)},
+		{"borrow cost", s.BorrowCost.StringFixed(4)},
+		{"open position", s.Position.String()},
+		{"events", strconv.Itoa(s.Events)},
+		{"handler faults", strconv.FormatInt(s.Faults, 10)},
+	}
+}
+
+func writeRunTable(w io.Writer, result backtest.Result) error {
+	return writeTable(w, []string{"METRIC", result.Summary.Selector}, summaryRows(result.Summary))
+}
+
+func writeComparisonTable(w io.Writer, c backtest.Comparison, tradeDiffLimit int) error {
+	base, cand := summaryRows(c.Baseline), summaryRows(c.Candidate)
+	diffs := []string{
+		signed(c.PnLDiff),
+		signed(c.MaxDrawdownDiff),
+		strconv.Itoa(c.Candidate.Fills - c.Baseline.Fills),
+		signed(c.Candidate.Volume.Sub(c.Baseline.Volume)),
+		signed(c.Candidate.Fees.Sub(c.Baseline.Fees)),
+		signed(c.Candidate.Funding.Sub(c.Baseline.Funding)),
+		signed(c.Candidate.BorrowCost.Sub(c.Baseline.BorrowCost)),
+		signed(c.Candidate.Position.Sub(c.Baseline.Position)),
+		"",
+		strconv.FormatInt(c.Candidate.Faults-c.Baseline.Faults, 10),
+	}
+	rows := make([][]string, len(base))
+	for i := range base {
+		rows[i] = []string{base[i][0], base[i][1], cand[i][1], diffs[i]}
+	}
+	if err := writeTable(w, []string{"METRIC", "BASELINE " + c.Baseline.Selector, "CANDIDATE " + c.Candidate.Selector, "DIFF"}, rows); err != nil {
+		return err
+	}
+
+	sig := c.Significance
+	verdict := "not significant"
+	if sig.Significant {
+		verdict = "significant"
+	}
+	fmt.Fprintf(w, "\nPaired t-test on per-row PnL changes (n=%d): mean diff %.6g, t=%.3f, p=%.4f, %s at %.0f%%\n",
+		sig.Samples, sig.MeanDiff, sig.TStat, sig.PValue, verdict, backtest.SignificanceLevel*100)
+
+	if len(c.TradeDiffs) == 0 {
+		fmt.Fprintln(w, "\nFills are identical.")
+		return nil
+	}
+	shown := c.TradeDiffs
+	if tradeDiffLimit >= 0 && len(shown) > tradeDiffLimit {
+		shown = shown[:tradeDiffLimit]
+	}
+	fmt.Fprintf(w, "\nDiffering fills (%d of %d):\n", len(shown), len(c.TradeDiffs))
+	tradeRows := make([][]string, 0, len(shown))
+	for _, diff := range shown {
+		tradeRows = append(tradeRows, []string{strconv.Itoa(diff.Seq), describeFill(diff.Baseline), describeFill(diff.Candidate), signed(diff.RealizedPnLDiff)})
+	}
+	return writeTable(w, []string{"SEQ", "BASELINE", "CANDIDATE", "REALIZED DIFF"}, tradeRows)
+}
+
+func describeFill(fill *backtest.Fill) string {
+	if fill == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s %s %s @ %s", fill.Time.UTC().Format(time.DateTime), fill.Side, fill.Quantity, fill.Price)
+}
+
+func signed(value decimal.Decimal) string {
+	if value.IsPositive() {
+		return "+" + value.StringFixed(4)
+	}
+	return value.StringFixed(4)
+}
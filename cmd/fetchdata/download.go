@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/backtest"
+)
+
+const (
+	tradesFile = "trades.csv"
+	// tailReadSize bounds how much of an existing file is read to find its last row.
+	tailReadSize = 4 << 10
+)
+
+var errNoProgress = errors.New("source returned no new rows")
+
+// The files use the layouts the backtest command replays.
+var (
+	klineHeader = backtest.KlineColumns
+	tradeHeader = backtest.TradeColumns
+)
+
+func klinesFile(interval string) string {
+	return "klines_" + interval + ".csv"
+}
+
+// outputPath returns <out>/<provider>/<SYMBOL>/<name>.
+func outputPath(out, provider, symbol, name string) string {
+	return filepath.Join(out, strings.ToLower(strings.TrimSpace(provider)), strings.ToUpper(strings.TrimSpace(symbol)), name)
+}
+
+// downloadKlines appends the closed klines opening in [from, to) to path, resuming after the last
+// kline already in the file. It returns the number of rows written.
+func downloadKlines(ctx context.Context, src source, path, symbol, interval string, from, to time.Time) (int, error) {
+	start := from
+	last, err := lastRecord(path)
+	if err != nil {
+		return 0, err
+	}
+	if last != nil {
+		openTime, err := parseMillis(last[0])
+		if err != nil {
+			return 0, fmt.Errorf("resume %s: %w", path, err)
+		}
+		if next := openTime.Add(time.Millisecond); next.After(start) {
+			start = next
+		}
+	}
+	w, err := openCSV(path, klineHeader)
+	if err != nil {
+		return 0, err
+	}
+	defer w.close()
+
+	written := 0
+	for start.Before(to) {
+		page, err := src.Klines(ctx, symbol, interval, start, to)
+		if err != nil {
+			return written, err
+		}
+		now := time.Now()
+		next := start
+		for _, k := range page {
+			if !k.OpenTime.Before(to) || !k.CloseTime.Before(now) {
+				// Past the range or still open.
+				return written, w.flush()
+			}
+			if k.OpenTime.Before(start) {
+				continue
+			}
+			if err := w.write([]string{millis(k.OpenTime), millis(k.CloseTime), k.Open, k.High, k.Low, k.Close, k.Volume}); err != nil {
+				return written, err
+			}
+			written++
+			next = k.OpenTime.Add(time.Millisecond)
+		}
+		if err := w.flush(); err != nil {
+			return written, err
+		}
+		if !next.After(start) {
+			break
+		}
+		start = next
+	}
+	return written, nil
+}
+
+// downloadTrades appends the trades in [from, to) to path, resuming after the last trade ID already
+// in the file. It returns the number of rows written.
+func downloadTrades(ctx context.Context, src source, path, symbol string, from, to time.Time) (int, error) {
+	last, err := lastRecord(path)
+	if err != nil {
+		return 0, err
+	}
+	var nextID int64 = -1
+	if last != nil {
+		id, err := strconv.ParseInt(last[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("resume %s: invalid trade id %q", path, last[0])
+		}
+		nextID = id + 1
+	}
+	w, err := openCSV(path, tradeHeader)
+	if err != nil {
+		return 0, err
+	}
+	defer w.close()
+
+	written := 0
+	// writePage writes the trades before to and reports whether the range end was reached.
+	writePage := func(page []trade) (bool, error) {
+		for _, t := range page {
+			if !t.Timestamp.Before(to) {
+				return true, nil
+			}
+			if t.ID < nextID || t.Timestamp.Before(from) {
+				continue
+			}
+			if err := w.write([]string{strconv.FormatInt(t.ID, 10), millis(t.Timestamp), t.Side, t.Price, t.Quantity}); err != nil {
+				return false, err
+			}
+			written++
+			nextID = t.ID + 1
+		}
+		return false, w.flush()
+	}
+
+	if nextID < 0 {
+		// Find the first trade of the range window by window.
+		for start := from; start.Before(to) && nextID < 0; start = start.Add(src.TradeWindow()) {
+			end := start.Add(src.TradeWindow())
+			if end.After(to) {
+				end = to
+			}
+			page, err := src.TradesBetween(ctx, symbol, start, end)
+			if err != nil {
+				return written, err
+			}
+			if done, err := writePage(page); err != nil || done {
+				return written, err
+			}
+		}
+		if nextID < 0 {
+			return written, nil
+		}
+	}
+	for {
+		page, err := src.TradesFrom(ctx, symbol, nextID)
+		if err != nil {
+			return written, err
+		}
+		if len(page) == 0 {
+			return written, nil
+		}
+		before := nextID
+		done, err := writePage(page)
+		if err != nil || done {
+			return written, err
+		}
+		if nextID == before {
+			return written, fmt.Errorf("trades from %d: %w", before, errNoProgress)
+		}
+	}
+}
+
+// csvWriter appends rows to a CSV file.
+type csvWriter struct {
+	file *os.File
+	csv  *csv.Writer
+}
+
+// openCSV opens path for appending, creating it and its directory with header when missing.
+func openCSV(path string, header []string) (*csvWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create output directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	w := &csvWriter{file: file, csv: csv.NewWriter(file)}
+	size, err := trimPartialLine(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("repair %s: %w", path, err)
+	}
+	if size == 0 {
+		if err := w.write(header); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// trimPartialLine truncates a trailing line left incomplete by an interrupted download and returns
+// the resulting file size.
+func trimPartialLine(file *os.File) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	for size > 0 {
+		offset := size - tailReadSize
+		if offset < 0 {
+			offset = 0
+		}
+		buf := make([]byte, size-offset)
+		if _, err := file.ReadAt(buf, offset); err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+			size = offset + int64(i) + 1
+			break
+		}
+		size = offset
+	}
+	if size == info.Size() {
+		return size, nil
+	}
+	return size, file.Truncate(size)
+}
+
+func (w *csvWriter) write(row []string) error {
+	if err := w.csv.Write(row); err != nil {
+		return fmt.Errorf("write %s: %w", w.file.Name(), err)
+	}
+	return nil
+}
+
+func (w *csvWriter) flush() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return fmt.Errorf("flush %s: %w", w.file.Name(), err)
+	}
+	return nil
+}
+
+func (w *csvWriter) close() {
+	_ = w.flush()
+	_ = w.file.Close()
+}
+
+// lastRecord returns the last data row of a CSV file, or nil when the file is missing or holds
+// only a header. A trailing partial line left by an interrupted download is ignored.
+func lastRecord(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", path, err)
+	}
+	offset := info.Size() - tailReadSize
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(tail, offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	end := bytes.LastIndexByte(tail, '\n')
+	if end < 0 {
+		return nil, nil
+	}
+	lines := strings.Split(string(tail[:end]), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	if line == "" || (offset == 0 && len(lines) == 1) {
+		// Empty file or header only.
+		return nil, nil
+	}
+	record, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return nil, fmt.Errorf("parse last row of %s: %w", path, err)
+	}
+	return record, nil
+}
+
+func millis(ts time.Time) string {
+	return strconv.FormatInt(ts.UnixMilli(), 10)
+}
+
+func parseMillis(raw string) (time.Time, error) {
+	ms, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", raw)
+	}
+	return time.UnixMilli(ms).UTC(), nil
+}
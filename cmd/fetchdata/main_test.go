@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeBinance serves minute klines and one aggregated trade per second from base onwards.
+type fakeBinance struct {
+	base      time.Time
+	pageLimit int
+	throttle  atomic.Int32
+}
+
+func (f *fakeBinance) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.throttle.Add(-1) >= 0 {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	q := r.URL.Query()
+	if q.Get("symbol") != "BTCUSDT" {
+		http.Error(w, `{"code":-1121,"msg":"Invalid symbol."}`, http.StatusBadRequest)
+		return
+	}
+	var rows []string
+	switch r.URL.Path {
+	case "/api/v3/klines":
+		start, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+		end, _ := strconv.ParseInt(q.Get("endTime"), 10, 64)
+		open := f.base.UnixMilli()
+		for ; open < start; open += time.Minute.Milliseconds() {
+		}
+		for ; open <= end && len(rows) < f.pageLimit; open += time.Minute.Milliseconds() {
+			rows = append(rows, fmt.Sprintf(`[%d,"1.0","2.0","0.5","1.5","10",%d,"15",3,"5","7","0"]`, open, open+time.Minute.Milliseconds()-1))
+		}
+	case "/api/v3/aggTrades":
+		base := f.base.UnixMilli()
+		id := int64(0)
+		if raw := q.Get("fromId"); raw != "" {
+			id, _ = strconv.ParseInt(raw, 10, 64)
+		} else {
+			start, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+			if start > base {
+				id = (start - base + 999) / 1000
+			}
+		}
+		end := int64(1 << 62)
+		if raw := q.Get("endTime"); raw != "" {
+			end, _ = strconv.ParseInt(raw, 10, 64)
+		}
+		for ts := base + id*1000; ts <= end && len(rows) < f.pageLimit && ts < time.Now().UnixMilli(); ts += 1000 {
+			rows = append(rows, fmt.Sprintf(`{"a":%d,"p":"100.5","q":"0.25","f":1,"l":1,"T":%d,"m":%t}`, id, ts, id%2 == 1))
+			id++
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte("[" + strings.Join(rows, ",") + "]"))
+}
+
+func newFakeSource(t *testing.T, fake *fakeBinance) (*binanceSource, *[]time.Duration) {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	src := newBinanceSource(server.URL, 1000, 5*time.Second)
+	var slept []time.Duration
+	src.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return src, &slept
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return records
+}
+
+func TestDownloadKlinesPagesAndResumes(t *testing.T) {
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	fake := &fakeBinance{base: base, pageLimit: 4}
+	src, _ := newFakeSource(t, fake)
+	path := outputPath(t.TempDir(), "Binance", "btc-usdt", klinesFile("1m"))
+	if !strings.HasSuffix(path, filepath.Join("binance", "BTC-USDT", "klines_1m.csv")) {
+		t.Fatalf("unexpected output path %s", path)
+	}
+
+	written, err := downloadKlines(context.Background(), src, path, "BTC-USDT", "1m", base, base.Add(6*time.Minute))
+	if err != nil || written != 6 {
+		t.Fatalf("first download = %d, %v", written, err)
+	}
+	written, err = downloadKlines(context.Background(), src, path, "BTC-USDT", "1m", base, base.Add(10*time.Minute))
+	if err != nil || written != 4 {
+		t.Fatalf("resumed download = %d, %v", written, err)
+	}
+
+	records := readCSV(t, path)
+	if len(records) != 11 || strings.Join(records[0], ",") != strings.Join(klineHeader, ",") {
+		t.Fatalf("unexpected klines file %v", records)
+	}
+	for i, record := range records[1:] {
+		want := millis(base.Add(time.Duration(i) * time.Minute))
+		if record[0] != want || record[4] != "0.5" || record[6] != "10" {
+			t.Fatalf("row %d = %v, want open_time %s", i, record, want)
+		}
+	}
+}
+
+func TestDownloadKlinesSkipsOpenKline(t *testing.T) {
+	base := time.Now().UTC().Truncate(time.Millisecond).Add(-150 * time.Second)
+	src, _ := newFakeSource(t, &fakeBinance{base: base, pageLimit: 10})
+	path := filepath.Join(t.TempDir(), "klines_1m.csv")
+
+	written, err := downloadKlines(context.Background(), src, path, "BTC-USDT", "1m", base, base.Add(time.Hour))
+	if err != nil || written != 2 {
+		t.Fatalf("download = %d, %v; want only the 2 closed klines", written, err)
+	}
+}
+
+func TestDownloadTradesResumesAfterPartialLine(t *testing.T) {
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	src, _ := newFakeSource(t, &fakeBinance{base: base, pageLimit: 3})
+	path := filepath.Join(t.TempDir(), tradesFile)
+	from := base.Add(2 * time.Second)
+
+	written, err := downloadTrades(context.Background(), src, path, "BTC-USDT", from, base.Add(7*time.Second))
+	if err != nil || written != 5 {
+		t.Fatalf("first download = %d, %v", written, err)
+	}
+	// Simulate a download interrupted mid-row.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := file.WriteString("7,17567"); err != nil {
+		t.Fatalf("write partial row: %v", err)
+	}
+	_ = file.Close()
+
+	written, err = downloadTrades(context.Background(), src, path, "BTC-USDT", from, base.Add(10*time.Second))
+	if err != nil || written != 3 {
+		t.Fatalf("resumed download = %d, %v", written, err)
+	}
+	records := readCSV(t, path)
+	if len(records) != 9 || strings.Join(records[0], ",") != strings.Join(tradeHeader, ",") {
+		t.Fatalf("unexpected trades file %v", records)
+	}
+	for i, record := range records[1:] {
+		id := int64(i + 2)
+		if record[0] != strconv.FormatInt(id, 10) || record[1] != millis(base.Add(time.Duration(id)*time.Second)) {
+			t.Fatalf("row %d = %v, want trade %d", i, record, id)
+		}
+	}
+	if records[1][2] != "BUY" || records[2][2] != "SELL" {
+		t.Fatalf("unexpected aggressor sides %v %v", records[1], records[2])
+	}
+}
+
+func TestDownloadTradesHeaderOnlyFileStartsFromRange(t *testing.T) {
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	src, _ := newFakeSource(t, &fakeBinance{base: base, pageLimit: 100})
+	path := filepath.Join(t.TempDir(), tradesFile)
+	if err := os.WriteFile(path, []byte(strings.Join(tradeHeader, ",")+"\n"), 0o644); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+
+	written, err := downloadTrades(context.Background(), src, path, "BTC-USDT", base.Add(90*time.Minute), base.Add(90*time.Minute+3*time.Second))
+	if err != nil || written != 3 {
+		t.Fatalf("download = %d, %v", written, err)
+	}
+	if records := readCSV(t, path); len(records) != 4 || records[1][0] != "5400" {
+		t.Fatalf("unexpected trades file %v", records)
+	}
+}
+
+func TestBinanceSourceRetriesRateLimitedRequests(t *testing.T) {
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	fake := &fakeBinance{base: base, pageLimit: 10}
+	fake.throttle.Store(2)
+	src, slept := newFakeSource(t, fake)
+
+	klines, err := src.Klines(context.Background(), "BTC-USDT", "1m", base, base.Add(2*time.Minute))
+	if err != nil || len(klines) != 2 {
+		t.Fatalf("Klines = %d, %v", len(klines), err)
+	}
+	if len(*slept) != 2 || (*slept)[0] != 7*time.Second {
+		t.Fatalf("expected two Retry-After waits, got %v", *slept)
+	}
+
+	fake.throttle.Store(maxRetries + 1)
+	if _, err := src.Klines(context.Background(), "BTC-USDT", "1m", base, base.Add(time.Minute)); err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Fatalf("expected rate limit error after retries, got %v", err)
+	}
+	if _, err := src.Klines(context.Background(), "DOGE", "1m", base, base.Add(time.Minute)); err == nil || !strings.Contains(err.Error(), "Invalid symbol") {
+		t.Fatalf("expected API error surfaced, got %v", err)
+	}
+}
+
+func TestRangeBounds(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	from, to, err := rangeOptions{symbol: "BTC-USDT", from: "2026-09-01"}.bounds(now)
+	if err != nil || !from.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(now) {
+		t.Fatalf("bounds = %v, %v, %v", from, to, err)
+	}
+	_, to, err = rangeOptions{symbol: "BTC-USDT", from: "2026-09-01", to: "2026-09-02T06:00:00+02:00"}.bounds(now)
+	if err != nil || !to.Equal(time.Date(2026, 9, 2, 4, 0, 0, 0, time.UTC)) {
+		t.Fatalf("bounds to = %v, %v", to, err)
+	}
+	for _, rng := range []rangeOptions{
+		{symbol: "BTC-USDT", from: "yesterday"},
+		{symbol: "BTC-USDT", from: "2026-09-02", to: "2026-09-01"},
+		{symbol: " ", from: "2026-09-01"},
+	} {
+		if _, _, err := rng.bounds(now); err == nil {
+			t.Fatalf("expected %+v rejected", rng)
+		}
+	}
+}
+
+func TestRootCommandRejectsUnsupportedProvider(t *testing.T) {
+	var stdout, stderr strings.Builder
+	cmd := newRootCommand(&stdout, &stderr)
+	cmd.SetArgs([]string{"--provider", "kraken", "klines", "--symbol", "BTC-USDT", "--from", "2026-09-01"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "unsupported provider") {
+		t.Fatalf("expected unsupported provider error, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"golang.org/x/time/rate"
+)
+
+const (
+	providerBinance       = "binance"
+	defaultBinanceBaseURL = "https://api.binance.com"
+
+	binanceKlineLimit = 1000
+	binanceTradeLimit = 1000
+	// binanceTradeWindow is the widest startTime/endTime range aggTrades accepts.
+	binanceTradeWindow = time.Hour
+
+	maxRetries       = 5
+	defaultRetryWait = time.Second
+)
+
+// kline is one candle as written to klines CSV files.
+type kline struct {
+	OpenTime  time.Time
+	CloseTime time.Time
+	Open      string
+	High      string
+	Low       string
+	Close     string
+	Volume    string
+}
+
+// trade is one aggregated trade as written to trades CSV files.
+type trade struct {
+	ID        int64
+	Timestamp time.Time
+	// Side is the aggressor side, BUY or SELL.
+	Side     string
+	Price    string
+	Quantity string
+}
+
+// source pages historical data out of a provider's public API, oldest first.
+type source interface {
+	// Klines returns up to one page of klines opening at or after start and before end.
+	Klines(ctx context.Context, symbol, interval string, start, end time.Time) ([]kline, error)
+	// TradesBetween returns up to one page of trades in [start, end); end - start must not exceed
+	// TradeWindow.
+	TradesBetween(ctx context.Context, symbol string, start, end time.Time) ([]trade, error)
+	// TradesFrom returns up to one page of trades with an ID of at least fromID.
+	TradesFrom(ctx context.Context, symbol string, fromID int64) ([]trade, error)
+	// TradeWindow is the widest range TradesBetween accepts.
+	TradeWindow() time.Duration
+}
+
+// binanceSource reads the Binance spot market data endpoints, which need no credentials.
+type binanceSource struct {
+	baseURL string
+	client  *http.Client
+	limiter *rate.Limiter
+	sleep   func(context.Context, time.Duration) error
+}
+
+func newBinanceSource(baseURL string, perSecond float64, timeout time.Duration) *binanceSource {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if baseURL == "" {
+		baseURL = defaultBinanceBaseURL
+	}
+	return &binanceSource{
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: timeout},
+		limiter: rate.NewLimiter(rate.Limit(perSecond), 1),
+		sleep:   sleepContext,
+	}
+}
+
+func (s *binanceSource) TradeWindow() time.Duration {
+	return binanceTradeWindow
+}
+
+func (s *binanceSource) Klines(ctx context.Context, symbol, interval string, start, end time.Time) ([]kline, error) {
+	params := url.Values{}
+	params.Set("symbol", binanceSymbol(symbol))
+	params.Set("interval", interval)
+	params.Set("startTime", strconv.FormatInt(start.UnixMilli(), 10))
+	params.Set("endTime", strconv.FormatInt(end.UnixMilli()-1, 10))
+	params.Set("limit", strconv.Itoa(binanceKlineLimit))
+	var rows [][]json.RawMessage
+	if err := s.get(ctx, "/api/v3/klines", params, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]kline, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 7 {
+			return nil, fmt.Errorf("binance: malformed kline row %s", row)
+		}
+		var openTime, closeTime int64
+		var open, high, low, closePrice, volume string
+		for i, dst := range []any{&openTime, &open, &high, &low, &closePrice, &volume, &closeTime} {
+			if err := json.Unmarshal(row[i], dst); err != nil {
+				return nil, fmt.Errorf("binance: decode kline field %d: %w", i, err)
+			}
+		}
+		out = append(out, kline{
+			OpenTime:  time.UnixMilli(openTime).UTC(),
+			CloseTime: time.UnixMilli(closeTime).UTC(),
+			Open:      open,
+			High:      high,
+			Low:       low,
+			Close:     closePrice,
+			Volume:    volume,
+		})
+	}
+	return out, nil
+}
+
+func (s *binanceSource) TradesBetween(ctx context.Context, symbol string, start, end time.Time) ([]trade, error) {
+	params := url.Values{}
+	params.Set("symbol", binanceSymbol(symbol))
+	params.Set("startTime", strconv.FormatInt(start.UnixMilli(), 10))
+	params.Set("endTime", strconv.FormatInt(end.UnixMilli()-1, 10))
+	params.Set("limit", strconv.Itoa(binanceTradeLimit))
+	return s.aggTrades(ctx, params)
+}
+
+func (s *binanceSource) TradesFrom(ctx context.Context, symbol string, fromID int64) ([]trade, error) {
+	params := url.Values{}
+	params.Set("symbol", binanceSymbol(symbol))
+	params.Set("fromId", strconv.FormatInt(fromID, 10))
+	params.Set("limit", strconv.Itoa(binanceTradeLimit))
+	return s.aggTrades(ctx, params)
+}
+
+type binanceAggTrade struct {
+	ID           int64  `json:"a"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	Time         int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"`
+}
+
+func (s *binanceSource) aggTrades(ctx context.Context, params url.Values) ([]trade, error) {
+	var rows []binanceAggTrade
+	if err := s.get(ctx, "/api/v3/aggTrades", params, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]trade, 0, len(rows))
+	for _, row := range rows {
+		side := "BUY"
+		if row.IsBuyerMaker {
+			side = "SELL"
+		}
+		out = append(out, trade{
+			ID:        row.ID,
+			Timestamp: time.UnixMilli(row.Time).UTC(),
+			Side:      side,
+			Price:     row.Price,
+			Quantity:  row.Quantity,
+		})
+	}
+	return out, nil
+}
+
+// get issues a rate limited GET and decodes the JSON response into out. Responses signalling that
+// the request weight limit was hit (429, or 418 once the IP is banned) are retried after the
+// Retry-After delay.
+func (s *binanceSource) get(ctx context.Context, path string, params url.Values, out any) error {
+	endpoint := s.baseURL + path + "?" + params.Encode()
+	for attempt := 0; ; attempt++ {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("binance: create request: %w", err)
+		}
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("binance: %s: %w", path, err)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("binance: read %s: %w", path, err)
+		}
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot:
+			if attempt >= maxRetries {
+				return fmt.Errorf("binance: %s rate limited after %d retries", path, attempt)
+			}
+			if err := s.sleep(ctx, retryAfter(resp.Header.Get("Retry-After"))); err != nil {
+				return err
+			}
+			continue
+		case resp.StatusCode >= http.StatusBadRequest:
+			return fmt.Errorf("binance: %s: status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("binance: decode %s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+func retryAfter(header string) time.Duration {
+	if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultRetryWait
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// binanceSymbol converts a canonical symbol such as BTC-USDT to the Binance form BTCUSDT.
+func binanceSymbol(symbol string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(symbol), "-", ""))
+}
@@ -10,24 +10,45 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	goruntime "runtime"
+	"runtime/debug"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/coachpo/meltica/internal/app/approvals"
+	"github.com/coachpo/meltica/internal/app/calendar"
 	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/app/dropcopy"
+	"github.com/coachpo/meltica/internal/app/egressip"
+	"github.com/coachpo/meltica/internal/app/eventaudit"
+	"github.com/coachpo/meltica/internal/app/featureflags"
+	"github.com/coachpo/meltica/internal/app/heartbeat"
 	lambdaruntime "github.com/coachpo/meltica/internal/app/lambda/runtime"
 	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/app/session"
+	"github.com/coachpo/meltica/internal/app/sink"
+	"github.com/coachpo/meltica/internal/app/synthetic"
+	"github.com/coachpo/meltica/internal/domain/calendarstore"
+	"github.com/coachpo/meltica/internal/domain/flagstore"
 	"github.com/coachpo/meltica/internal/domain/orderstore"
 	"github.com/coachpo/meltica/internal/domain/outboxstore"
 	"github.com/coachpo/meltica/internal/domain/providerstore"
+	"github.com/coachpo/meltica/internal/domain/riskstore"
 	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+	"github.com/coachpo/meltica/internal/domain/strategyreportstore"
 	"github.com/coachpo/meltica/internal/domain/strategystore"
+	"github.com/coachpo/meltica/internal/domain/usagestore"
 	"github.com/coachpo/meltica/internal/infra/adapters"
 	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
 	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/persistence/filestore"
 	"github.com/coachpo/meltica/internal/infra/persistence/migrations"
 	postgresstore "github.com/coachpo/meltica/internal/infra/persistence/postgres"
+	"github.com/coachpo/meltica/internal/infra/persistence/s3store"
 	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
 	httpserver "github.com/coachpo/meltica/internal/infra/server/http"
 	"github.com/coachpo/meltica/internal/infra/telemetry"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -38,52 +59,105 @@ const (
 	defaultConfigPath            = "config/app.yaml"
 	configPathEnvVar             = "MELTICA_CONFIG_PATH"
 	gatewayLoggerPrefix          = "gateway "
+	accessLoggerPrefix           = "access "
 	eventPoolName                = "Event"
 	orderRequestPoolName         = "OrderRequest"
 	shutdownTimeout              = 30 * time.Second
 	controlServerShutdownTimeout = 5 * time.Second
 	lifecycleShutdownTimeout     = 10 * time.Second
+	orderSnapshotShutdownTimeout = 5 * time.Second
 	dataBusShutdownTimeout       = 2 * time.Second
 	poolManagerShutdownTimeout   = 5 * time.Second
 	telemetryShutdownTimeout     = 5 * time.Second
 	controlReadHeaderTimeout     = 5 * time.Second
 	databaseConnectTimeout       = 15 * time.Second
 	databaseShutdownTimeout      = 5 * time.Second
+	telemetryProbeTimeout        = 5 * time.Second
+)
+
+// Build metadata injected at link time, e.g.
+// go build -ldflags "-X main.version=v1.4.0 -X main.commit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%FT%TZ)".
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
 )
 
 func main() {
-	cfgPathFlag := parseFlags()
+	startedAt := time.Now()
+	cli := parseFlags()
 	ctx, cancel := newSignalContext()
 	defer cancel()
 
+	// Adapters log through the standard logger; redact it like the component loggers.
+	log.SetOutput(redact.Writer(os.Stderr))
 	logger := newGatewayLogger()
+	build := gatewayBuildInfo()
+	logger.Printf("meltica gateway %s (commit %s, %s)", build.Version, build.Commit, build.GoVersion)
 
-	appCfg, err := config.Load(ctx, resolveConfigPath(cfgPathFlag))
+	appCfg, err := config.Load(ctx, resolveConfigPath(cli.configPath))
 	if err != nil {
 		logger.Fatalf("load config: %v", err)
 	}
 	logger.Printf("configuration loaded: env=%s, providers=%d",
 		appCfg.Environment, len(appCfg.Providers))
+	if err := configureRedaction(appCfg); err != nil {
+		logger.Fatalf("configure redaction: %v", err)
+	}
 
 	logger.Printf("providers configured: %d", len(appCfg.Providers))
 
-	if err := runDatabaseMigrations(ctx, logger, appCfg.Database); err != nil {
-		logger.Fatalf("apply database migrations: %v", err)
+	safeMode := newSafeModeTrigger(appCfg.APIServer.SafeMode.Enabled, cli.safeMode)
+	waiter := newDependencyWaiter(appCfg.Startup, logger)
+	databaseReady := awaitDatabase(ctx, logger, waiter, appCfg, safeMode)
+	if safeMode.forced {
+		logger.Printf("safe mode requested; skipping database migrations")
+	} else if databaseReady {
+		if err := runDatabaseMigrations(ctx, logger, appCfg.Database); err != nil {
+			if !safeMode.record(logger, "migrations", err) {
+				logger.Fatalf("apply database migrations: %v", err)
+			}
+		}
 	}
 
-	dbPool, err := initDatabase(ctx, logger, appCfg.Database)
-	if err != nil {
-		logger.Fatalf("connect database: %v", err)
+	var dbPool *pgxpool.Pool
+	if databaseReady {
+		dbPool, err = initDatabase(ctx, logger, appCfg.Database)
+		if err != nil {
+			if !safeMode.record(logger, "database", err) {
+				logger.Fatalf("connect database: %v", err)
+			}
+		}
+	}
+	if dbPool == nil {
+		runSafeMode(ctx, cancel, logger, appCfg, safeMode, nil, nil, nil, httpserver.WithBuildInfo(build, startedAt))
+		return
 	}
 	providerStore := postgresstore.NewProviderStore(dbPool)
 	strategyStore := postgresstore.NewStrategyStore(dbPool)
+	persisted := loadPersistedState(ctx, logger, safeMode, providerStore, strategyStore)
+	if safeMode.active() {
+		runSafeMode(ctx, cancel, logger, appCfg, safeMode, dbPool, providerStore, strategyStore, httpserver.WithBuildInfo(build, startedAt))
+		return
+	}
 	orderStore := postgresstore.NewOrderStore(dbPool)
-	outboxStore := postgresstore.NewOutboxStore(dbPool)
+	outboxCodec, err := outboxstore.ParseCodec(appCfg.Database.OutboxCompression.Codec)
+	if err != nil {
+		logger.Fatalf("outbox compression: %v", err)
+	}
+	outboxStore := postgresstore.NewOutboxStore(dbPool, postgresstore.WithPayloadCompression(outboxCodec, appCfg.Database.OutboxCompression.MinBytes))
+	riskProfileStore := postgresstore.NewRiskProfileStore(dbPool)
+	calendarStore := postgresstore.NewCalendarStore(dbPool)
+	flagStore := postgresstore.NewFeatureFlagStore(dbPool)
+	strategyLogStore := postgresstore.NewStrategyLogStore(dbPool)
+	strategyReportStore := postgresstore.NewStrategyReportStore(dbPool)
+	usageStore := postgresstore.NewInstanceUsageStore(dbPool)
 
-	telemetryProvider, err := initTelemetry(ctx, logger, appCfg)
+	telemetryProvider, err := initTelemetry(ctx, logger, waiter, appCfg)
 	if err != nil {
 		logger.Fatalf("initialize telemetry: %v", err)
 	}
+	objects := connectObjectStore(ctx, appCfg, logger)
 
 	poolMgr, err := buildPoolManager(appCfg.Pools)
 	if err != nil {
@@ -92,23 +166,51 @@ func main() {
 
 	var lifecycle conc.WaitGroup
 
+	flags := loadFeatureFlags(ctx, appCfg, flagStore, logger)
 	bus := newEventBus(appCfg.Eventbus, poolMgr, outboxStore, logger)
 
+	if err := startEventSinks(ctx, &lifecycle, logger, appCfg.Sinks, bus, poolMgr, flags); err != nil {
+		logger.Fatalf("initialise event sinks: %v", err)
+	}
+	if err := startDropCopy(ctx, &lifecycle, logger, appCfg, bus, poolMgr); err != nil {
+		logger.Fatalf("initialise fix drop-copy: %v", err)
+	}
+
 	table := dispatcher.NewTable()
-	providerManager, err := initProviders(ctx, logger, appCfg, poolMgr, table, bus, providerStore)
+	providerManager, err := initProviders(ctx, logger, appCfg, poolMgr, table, bus, providerStore, persisted.providers, orderStore, orderStore)
 	if err != nil {
 		logger.Fatalf("initialise providers: %v", err)
 	}
 
 	registrar := dispatcher.NewRegistrar(table, providerManager)
 
-	lambdaManager, err := startLambdaManager(ctx, appCfg, bus, poolMgr, providerManager, registrar, logger, strategyStore, orderStore)
+	if err := startSynthetics(ctx, &lifecycle, logger, appCfg.Synthetics, bus, poolMgr, registrar); err != nil {
+		logger.Fatalf("initialise synthetic instruments: %v", err)
+	}
+
+	lambdaManager, err := startLambdaManager(ctx, appCfg, bus, poolMgr, providerManager, registrar, logger, strategyStore, persisted.strategies, orderStore, riskProfileStore, strategyLogStore, strategyReportStore, usageStore, flags, objects)
 	if err != nil {
 		logger.Fatalf("initialise lambdas: %v", err)
 	}
 	logger.Printf("strategy instances registered: %d", len(lambdaManager.Instances()))
-
-	apiServer := buildAPIServer(appCfg, lambdaManager, providerManager, orderStore)
+	resyncOpenOrders(ctx, logger, providerManager)
+
+	approvalQueue := newApprovals(appCfg, logger)
+	cal := startCalendar(ctx, &lifecycle, appCfg, bus, poolMgr, lambdaManager, providerManager, approvalQueue, calendarStore, logger)
+	startSessions(ctx, &lifecycle, appCfg, bus, poolMgr, lambdaManager, logger)
+	startHeartbeat(ctx, &lifecycle, appCfg, bus, poolMgr, table, logger)
+	egress := startEgressMonitor(ctx, &lifecycle, appCfg, logger)
+	eventAudit := startEventAudit(ctx, &lifecycle, appCfg, bus, poolMgr, logger)
+	startRuntimeMetrics(ctx, &lifecycle, appCfg.Telemetry.RuntimeMetrics, telemetryProvider, logger)
+
+	apiServer := buildAPIServer(appCfg, lambdaManager, providerManager, orderStore, outboxStore, cal, flags,
+		httpserver.WithBuildInfo(build, startedAt),
+		httpserver.WithEgressMonitor(egress),
+		httpserver.WithEventAudit(eventAudit),
+		httpserver.WithApprovals(approvalQueue),
+		datasetsOption(appCfg, objects, logger),
+		schemaVersionOption(dbPool),
+	)
 	startAPIServer(&lifecycle, logger, apiServer)
 	logger.Printf("control API listening on %s", apiServer.Addr)
 
@@ -124,6 +226,7 @@ func main() {
 		server:     apiServer,
 		mainCancel: cancel,
 		lifecycle:  &lifecycle,
+		providers:  providerManager,
 		dataBus:    bus,
 		poolMgr:    poolMgr,
 		telemetry:  telemetryProvider,
@@ -133,10 +236,16 @@ func main() {
 	logger.Printf("shutdown completed in %v", time.Since(shutdownStart))
 }
 
-func parseFlags() string {
+type cliFlags struct {
+	configPath string
+	safeMode   bool
+}
+
+func parseFlags() cliFlags {
 	cfgPath := flag.String("config", "", fmt.Sprintf("Path to application configuration file (default: %s)", defaultConfigPath))
+	safeMode := flag.Bool("safe-mode", false, "Start only the read-only diagnostics API, without providers or strategies")
 	flag.Parse()
-	return *cfgPath
+	return cliFlags{configPath: *cfgPath, safeMode: *safeMode}
 }
 
 func newSignalContext() (context.Context, context.CancelFunc) {
@@ -144,10 +253,25 @@ func newSignalContext() (context.Context, context.CancelFunc) {
 }
 
 func newGatewayLogger() *log.Logger {
-	return log.New(os.Stdout, gatewayLoggerPrefix, log.LstdFlags|log.Lmicroseconds)
+	return log.New(redact.Stdout, gatewayLoggerPrefix, log.LstdFlags|log.Lmicroseconds)
 }
 
-func initTelemetry(ctx context.Context, logger *log.Logger, appCfg config.AppConfig) (*telemetry.Provider, error) {
+// configureRedaction installs the credential redaction applied to logs and API error messages,
+// or disables it in environments the redaction config excludes.
+func configureRedaction(appCfg config.AppConfig) error {
+	if !appCfg.Redaction.ActiveIn(appCfg.Environment) {
+		redact.Configure(nil)
+		return nil
+	}
+	redactor, err := redact.New(redact.Rules{Keys: appCfg.Redaction.Keys, Patterns: appCfg.Redaction.Patterns})
+	if err != nil {
+		return err
+	}
+	redact.Configure(redactor)
+	return nil
+}
+
+func initTelemetry(ctx context.Context, logger *log.Logger, waiter *dependencyWaiter, appCfg config.AppConfig) (*telemetry.Provider, error) {
 	telemetryCfg := telemetry.DefaultConfig()
 	if appCfg.Telemetry.OTLPEndpoint != "" {
 		telemetryCfg.OTLPEndpoint = appCfg.Telemetry.OTLPEndpoint
@@ -158,6 +282,16 @@ func initTelemetry(ctx context.Context, logger *log.Logger, appCfg config.AppCon
 	telemetryCfg.Environment = string(appCfg.Environment)
 	telemetryCfg.OTLPInsecure = appCfg.Telemetry.OTLPInsecure
 	telemetryCfg.EnableMetrics = appCfg.Telemetry.EnableMetrics
+	telemetryCfg.EnableTracing = appCfg.Telemetry.Tracing.Enabled
+	telemetryCfg.TraceSampleRatio = appCfg.Telemetry.Tracing.SampleRatio
+
+	ready, err := awaitTelemetry(ctx, logger, waiter, appCfg.Startup, telemetryCfg)
+	if err != nil {
+		return nil, fmt.Errorf("wait for telemetry collector: %w", err)
+	}
+	if !ready {
+		telemetryCfg.Enabled = false
+	}
 
 	provider, err := telemetry.NewProvider(ctx, telemetryCfg)
 	if err != nil {
@@ -212,11 +346,13 @@ func initDatabase(ctx context.Context, logger *log.Logger, dbCfg config.Database
 func buildPoolManager(cfg config.PoolConfig) (*pool.PoolManager, error) {
 	manager := pool.NewPoolManager()
 	eventQueueSize := cfg.Event.QueueSize()
-	if err := manager.RegisterPool(eventPoolName, cfg.Event.Size, eventQueueSize, func() interface{} { return new(schema.Event) }); err != nil {
+	if err := manager.RegisterPool(eventPoolName, cfg.Event.Size, eventQueueSize, func() interface{} { return new(schema.Event) },
+		pool.WithExhaustionPolicy(pool.ExhaustionPolicy(cfg.Event.Exhaustion), cfg.Event.WaitTimeout)); err != nil {
 		return nil, fmt.Errorf("register Event pool: %w", err)
 	}
 	orderQueueSize := cfg.OrderRequest.QueueSize()
-	if err := manager.RegisterPool(orderRequestPoolName, cfg.OrderRequest.Size, orderQueueSize, func() interface{} { return new(schema.OrderRequest) }); err != nil {
+	if err := manager.RegisterPool(orderRequestPoolName, cfg.OrderRequest.Size, orderQueueSize, func() interface{} { return new(schema.OrderRequest) },
+		pool.WithExhaustionPolicy(pool.ExhaustionPolicy(cfg.OrderRequest.Exhaustion), cfg.OrderRequest.WaitTimeout)); err != nil {
 		return nil, fmt.Errorf("register OrderRequest pool: %w", err)
 	}
 	return manager, nil
@@ -228,6 +364,8 @@ func newEventBus(cfg config.EventbusConfig, pools *pool.PoolManager, outbox outb
 		FanoutWorkers:            cfg.FanoutWorkerCount(),
 		ExtensionPayloadCapBytes: cfg.ExtensionPayloadCapBytes,
 		Pools:                    pools,
+		Priority:                 cfg.PriorityConfig(),
+		Watermark:                cfg.WatermarkConfig(),
 	})
 	return eventbus.NewDurableBus(
 		memoryBus,
@@ -238,7 +376,7 @@ func newEventBus(cfg config.EventbusConfig, pools *pool.PoolManager, outbox outb
 	)
 }
 
-func initProviders(ctx context.Context, logger *log.Logger, appCfg config.AppConfig, poolMgr *pool.PoolManager, table *dispatcher.Table, bus eventbus.Bus, store providerstore.Store) (*provider.Manager, error) {
+func initProviders(ctx context.Context, logger *log.Logger, appCfg config.AppConfig, poolMgr *pool.PoolManager, table *dispatcher.Table, bus eventbus.Bus, store providerstore.Store, persisted []providerstore.Snapshot, orders orderstore.Store, snapshots orderstore.SnapshotStore) (*provider.Manager, error) {
 	registry := provider.NewRegistry()
 	adapters.RegisterAll(registry)
 
@@ -246,9 +384,17 @@ func initProviders(ctx context.Context, logger *log.Logger, appCfg config.AppCon
 	if store != nil {
 		opts = append(opts, provider.WithPersistence(store))
 	}
+	if orders != nil {
+		opts = append(opts, provider.WithOrderStore(orders))
+	}
+	if snapshots != nil {
+		opts = append(opts, provider.WithOrderSnapshots(snapshots))
+	}
+	opts = append(opts, provider.WithOrderCircuitBreaker(appCfg.Orders.CircuitBreaker))
+	opts = append(opts, provider.WithOrderQueue(appCfg.Orders.Queue))
 	manager := provider.NewManager(registry, poolMgr, bus, table, logger, opts...)
 	manager.SetLifecycleContext(ctx)
-	restoreProviderSnapshots(ctx, logger, store, manager)
+	restoreProviderSnapshots(logger, persisted, manager)
 	specs, err := config.BuildProviderSpecs(appCfg.Providers)
 	if err != nil {
 		return nil, fmt.Errorf("build provider specs: %w", err)
@@ -271,22 +417,27 @@ func initProviders(ctx context.Context, logger *log.Logger, appCfg config.AppCon
 	} else {
 		logger.Printf("no providers configured; skipping provider startup")
 	}
+	if held, err := manager.LoadOpenOrderSnapshots(ctx); err != nil {
+		logger.Printf("open order snapshot load failed: %v", err)
+	} else if held > 0 {
+		logger.Printf("order entry held on %d providers until open orders are resynced", held)
+	}
 
 	return manager, nil
 }
 
-func restoreProviderSnapshots(ctx context.Context, logger *log.Logger, store providerstore.Store, manager *provider.Manager) {
-	if store == nil || manager == nil {
-		return
-	}
-	snapshots, err := store.LoadProviders(ctx)
-	if err != nil {
-		if logger != nil {
-			logger.Printf("provider persistence load failed: %v", err)
+// resyncOpenOrders reconciles the orders left open at the last shutdown once strategy instances
+// are restored, so they receive the execution reports they missed before order entry resumes.
+func resyncOpenOrders(ctx context.Context, logger *log.Logger, providers *provider.Manager) {
+	for _, report := range providers.ResyncOpenOrders(ctx) {
+		for _, msg := range report.Errors {
+			logger.Printf("open order resync %s: %s", report.Provider, msg)
 		}
-		return
 	}
-	if len(snapshots) == 0 {
+}
+
+func restoreProviderSnapshots(logger *log.Logger, snapshots []providerstore.Snapshot, manager *provider.Manager) {
+	if manager == nil || len(snapshots) == 0 {
 		return
 	}
 	for _, snapshot := range snapshots {
@@ -297,18 +448,8 @@ func restoreProviderSnapshots(ctx context.Context, logger *log.Logger, store pro
 	}
 }
 
-func restoreStrategySnapshots(ctx context.Context, logger *log.Logger, store strategystore.Store, manager *lambdaruntime.Manager) {
-	if store == nil || manager == nil {
-		return
-	}
-	snapshots, err := store.Load(ctx)
-	if err != nil {
-		if logger != nil {
-			logger.Printf("strategy persistence load failed: %v", err)
-		}
-		return
-	}
-	if len(snapshots) == 0 {
+func restoreStrategySnapshots(ctx context.Context, logger *log.Logger, snapshots []strategystore.Snapshot, manager *lambdaruntime.Manager) {
+	if manager == nil || len(snapshots) == 0 {
 		return
 	}
 	for _, snapshot := range snapshots {
@@ -319,24 +460,232 @@ func restoreStrategySnapshots(ctx context.Context, logger *log.Logger, store str
 	}
 }
 
-func startLambdaManager(ctx context.Context, appCfg config.AppConfig, bus eventbus.Bus, poolMgr *pool.PoolManager, providers *provider.Manager, registrar lambdaruntime.RouteRegistrar, logger *log.Logger, strategyStore strategystore.Store, orderStore orderstore.Store) (*lambdaruntime.Manager, error) {
+func startLambdaManager(ctx context.Context, appCfg config.AppConfig, bus eventbus.Bus, poolMgr *pool.PoolManager, providers *provider.Manager, registrar lambdaruntime.RouteRegistrar, logger *log.Logger, strategyStore strategystore.Store, persisted []strategystore.Snapshot, orderStore orderstore.Store, riskProfileStore riskstore.Store, strategyLogStore strategylogstore.Store, strategyReportStore strategyreportstore.Store, usageStore usagestore.Store, flags *featureflags.Flags, objects *s3store.Client) (*lambdaruntime.Manager, error) {
+	var mirror lambdaruntime.Option
+	if appCfg.ObjectStore.StrategiesPrefix != "" {
+		mirror = lambdaruntime.WithStrategyMirror(objects.Bucket(appCfg.ObjectStore.StrategiesPrefix))
+	}
 	manager, err := lambdaruntime.NewManager(appCfg, bus, poolMgr, providers, logger, registrar,
+		mirror,
 		lambdaruntime.WithStrategyStore(strategyStore),
 		lambdaruntime.WithOrderStore(orderStore),
+		lambdaruntime.WithRiskProfileStore(riskProfileStore),
+		lambdaruntime.WithStrategyLogStore(strategyLogStore),
+		lambdaruntime.WithStrategyReportStore(strategyReportStore),
+		lambdaruntime.WithUsageStore(usageStore),
+		lambdaruntime.WithFeatureFlags(flags),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("init lambda manager: %w", err)
 	}
 	manager.SetLifecycleContext(ctx)
-	restoreStrategySnapshots(ctx, logger, strategyStore, manager)
+	if err := manager.LoadRiskProfiles(ctx); err != nil && logger != nil {
+		logger.Printf("risk profile persistence load failed: %v", err)
+	}
+	restoreStrategySnapshots(ctx, logger, persisted, manager)
+	manager.StartDeadMansSwitch(ctx)
+	manager.StartAutoRefresh(ctx)
+	manager.StartStrategyLogs(ctx)
+	manager.StartStrategyReports(ctx)
+	manager.StartUsageMetering(ctx)
 	return manager, nil
 }
 
-func buildAPIServer(appCfg config.AppConfig, lambdaManager *lambdaruntime.Manager, providerManager *provider.Manager, orderStore orderstore.Store) *http.Server {
-	handler := httpserver.NewHandler(appCfg, lambdaManager, providerManager, orderStore)
+func startCalendar(ctx context.Context, lifecycle *conc.WaitGroup, appCfg config.AppConfig, bus eventbus.Bus, poolMgr *pool.PoolManager, lambdaManager *lambdaruntime.Manager, providers *provider.Manager, queue *approvals.Queue, store calendarstore.Store, logger *log.Logger) *calendar.Calendar {
+	cal := calendar.New(appCfg.Calendar, calendar.NewGatewayExecutor(lambdaManager, providers, queue),
+		calendar.WithStore(store),
+		calendar.WithNotifier(calendar.BusNotifier(bus, poolMgr, logger)),
+		calendar.WithLogger(logger),
+	)
+	if err := cal.Load(ctx); err != nil && logger != nil {
+		logger.Printf("calendar persistence load failed: %v", err)
+	}
+	lifecycle.Go(func() { cal.Run(ctx) })
+	return cal
+}
+
+// startSessions publishes session-boundary events at each venue's daily rollover when enabled.
+func startSessions(ctx context.Context, lifecycle *conc.WaitGroup, appCfg config.AppConfig, bus eventbus.Bus, poolMgr *pool.PoolManager, lambdaManager *lambdaruntime.Manager, logger *log.Logger) {
+	if !appCfg.Sessions.Enabled {
+		return
+	}
+	scheduler := session.New(appCfg.Sessions, lambdaManager, session.BusPublisher(bus, poolMgr, logger),
+		session.WithLogger(logger),
+	)
+	lifecycle.Go(func() { scheduler.Run(ctx) })
+}
 
+// startHeartbeat publishes per-provider heartbeats with route data freshness when enabled.
+func startHeartbeat(ctx context.Context, lifecycle *conc.WaitGroup, appCfg config.AppConfig, bus eventbus.Bus, poolMgr *pool.PoolManager, table *dispatcher.Table, logger *log.Logger) {
+	if !appCfg.Heartbeat.Enabled {
+		return
+	}
+	monitor := heartbeat.New(appCfg.Heartbeat, bus, poolMgr, heartbeat.BusPublisher(bus, poolMgr, logger),
+		heartbeat.WithRoutes(table),
+		heartbeat.WithLogger(logger),
+	)
+	lifecycle.Go(func() { monitor.Run(ctx) })
+}
+
+// startRuntimeMetrics samples goroutine, heap and GC statistics into the telemetry meter when
+// enabled.
+func startRuntimeMetrics(ctx context.Context, lifecycle *conc.WaitGroup, cfg config.TelemetryRuntimeMetricsConfig, provider *telemetry.Provider, logger *log.Logger) {
+	if !cfg.Enabled {
+		return
+	}
+	sampler, err := telemetry.NewRuntimeSampler(provider.Meter("runtime"))
+	if err != nil {
+		logger.Printf("runtime metrics disabled: %v", err)
+		return
+	}
+	lifecycle.Go(func() { sampler.Run(ctx, cfg.Interval) })
+}
+
+// startEgressMonitor logs the public egress IPs at startup and warns whenever they change, when
+// enabled.
+func startEgressMonitor(ctx context.Context, lifecycle *conc.WaitGroup, appCfg config.AppConfig, logger *log.Logger) *egressip.Monitor {
+	if !appCfg.Egress.Enabled {
+		return nil
+	}
+	monitor := egressip.New(appCfg.Egress, egressip.WithLogger(logger))
+	lifecycle.Go(func() { monitor.Run(ctx) })
+	return monitor
+}
+
+// startEventAudit samples provider events against the golden fixtures and warns when their field
+// distributions drift, when enabled.
+func startEventAudit(ctx context.Context, lifecycle *conc.WaitGroup, appCfg config.AppConfig, bus eventbus.Bus, poolMgr *pool.PoolManager, logger *log.Logger) *eventaudit.Monitor {
+	if !appCfg.EventAudit.Enabled {
+		return nil
+	}
+	fixtures, err := eventaudit.LoadFixtures(appCfg.EventAudit.Fixtures)
+	if err != nil {
+		logger.Fatalf("event audit: %v", err)
+	}
+	if fixtures.Len() == 0 {
+		logger.Printf("event audit: no fixtures in %s, events are sampled but not judged", appCfg.EventAudit.Fixtures)
+	}
+	monitor := eventaudit.New(appCfg.EventAudit, bus, poolMgr, fixtures, eventaudit.WithLogger(logger))
+	lifecycle.Go(func() { monitor.Run(ctx) })
+	return monitor
+}
+
+// newApprovals returns the approval queue guarding high-impact control API actions, or nil when
+// approvals are disabled.
+func newApprovals(appCfg config.AppConfig, logger *log.Logger) *approvals.Queue {
+	if !appCfg.Approvals.Enabled {
+		return nil
+	}
+	return approvals.New(appCfg.Approvals, approvals.WithLogger(logger))
+}
+
+// connectObjectStore connects to the objectStore bucket when strategies or datasets live there.
+func connectObjectStore(ctx context.Context, appCfg config.AppConfig, logger *log.Logger) *s3store.Client {
+	cfg := appCfg.ObjectStore
+	if !cfg.Enabled() {
+		return nil
+	}
+	client, err := s3store.NewClient(s3store.Options{
+		Endpoint:        cfg.Endpoint,
+		Region:          cfg.Region,
+		Bucket:          cfg.Bucket,
+		AccessKeyID:     cfg.AccessKeyID,
+		SecretAccessKey: cfg.SecretAccessKey,
+		Insecure:        cfg.Insecure,
+	})
+	if err != nil {
+		logger.Fatalf("object store: %v", err)
+	}
+	if err := client.Ping(ctx); err != nil {
+		logger.Fatalf("object store: %v", err)
+	}
+	logger.Printf("object store: bucket %s at %s", cfg.Bucket, cfg.Endpoint)
+	return client
+}
+
+// datasetsOption serves /datasets from objectStore.datasetsPrefix or datasets.directory; without
+// either the API answers 503.
+func datasetsOption(appCfg config.AppConfig, objects *s3store.Client, logger *log.Logger) httpserver.HandlerOption {
+	if appCfg.ObjectStore.DatasetsPrefix != "" {
+		store := s3store.NewDatasetStore(objects.Bucket(appCfg.ObjectStore.DatasetsPrefix))
+		return httpserver.WithDatasets(store, appCfg.Datasets.MaxUploadBytes)
+	}
+	if appCfg.Datasets.Directory == "" {
+		return nil
+	}
+	store, err := filestore.NewDatasetStore(appCfg.Datasets.Directory)
+	if err != nil {
+		logger.Fatalf("datasets: %v", err)
+	}
+	return httpserver.WithDatasets(store, appCfg.Datasets.MaxUploadBytes)
+}
+
+func loadFeatureFlags(ctx context.Context, appCfg config.AppConfig, store flagstore.Store, logger *log.Logger) *featureflags.Flags {
+	flags := featureflags.New(appCfg.FeatureFlags,
+		featureflags.WithStore(store),
+		featureflags.WithLogger(logger),
+	)
+	if err := flags.Load(ctx); err != nil && logger != nil {
+		logger.Printf("feature flag persistence load failed: %v", err)
+	}
+	return flags
+}
+
+func startEventSinks(ctx context.Context, lifecycle *conc.WaitGroup, logger *log.Logger, cfgs []config.SinkConfig, bus eventbus.Bus, poolMgr *pool.PoolManager, flags *featureflags.Flags) error {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	sinks, err := sink.NewManager(cfgs, bus, poolMgr, logger, sink.WithFeatureFlags(flags))
+	if err != nil {
+		return fmt.Errorf("init sinks: %w", err)
+	}
+	if err := sinks.Start(ctx); err != nil {
+		return fmt.Errorf("start sinks: %w", err)
+	}
+	lifecycle.Go(sinks.Wait)
+	return nil
+}
+
+func startDropCopy(ctx context.Context, lifecycle *conc.WaitGroup, logger *log.Logger, appCfg config.AppConfig, bus eventbus.Bus, poolMgr *pool.PoolManager) error {
+	if !appCfg.DropCopy.ActiveIn(appCfg.Environment) {
+		return nil
+	}
+	svc := dropcopy.New(appCfg.DropCopy, bus, poolMgr, logger)
+	if err := svc.Start(ctx); err != nil {
+		return fmt.Errorf("start drop-copy: %w", err)
+	}
+	lifecycle.Go(svc.Wait)
+	return nil
+}
+
+func startSynthetics(ctx context.Context, lifecycle *conc.WaitGroup, logger *log.Logger, cfgs []config.SyntheticInstrumentConfig, bus eventbus.Bus, poolMgr *pool.PoolManager, registrar *dispatcher.Registrar) error {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	engine := synthetic.New(cfgs, bus, poolMgr, synthetic.WithRegistrar(registrar), synthetic.WithLogger(logger))
+	if err := engine.Start(ctx); err != nil {
+		return fmt.Errorf("start synthetic instruments: %w", err)
+	}
+	lifecycle.Go(engine.Wait)
+	return nil
+}
+
+func buildAPIServer(appCfg config.AppConfig, lambdaManager *lambdaruntime.Manager, providerManager *provider.Manager, orderStore orderstore.Store, outbox outboxstore.EventBrowser, cal *calendar.Calendar, flags *featureflags.Flags, extra ...httpserver.HandlerOption) *http.Server {
+	accessLogger := log.New(redact.Stdout, accessLoggerPrefix, log.LstdFlags|log.Lmicroseconds)
+	opts := append([]httpserver.HandlerOption{
+		httpserver.WithAccessLogger(accessLogger),
+		httpserver.WithEventHistory(outbox),
+		httpserver.WithOutbox(outbox),
+		httpserver.WithCalendar(cal),
+		httpserver.WithFeatureFlags(flags),
+	}, extra...)
+	handler := httpserver.NewHandler(appCfg, lambdaManager, providerManager, orderStore, opts...)
+	return newControlServer(appCfg.APIServer.Addr, handler)
+}
+
+func newControlServer(addr string, handler http.Handler) *http.Server {
 	return &http.Server{
-		Addr:                         appCfg.APIServer.Addr,
+		Addr:                         addr,
 		Handler:                      handler,
 		DisableGeneralOptionsHandler: false,
 		TLSConfig:                    nil,
@@ -367,6 +716,7 @@ type gracefulShutdownConfig struct {
 	server     *http.Server
 	mainCancel context.CancelFunc
 	lifecycle  *conc.WaitGroup
+	providers  *provider.Manager
 	dataBus    eventbus.Bus
 	poolMgr    *pool.PoolManager
 	telemetry  *telemetry.Provider
@@ -412,6 +762,16 @@ func performGracefulShutdown(ctx context.Context, logger *log.Logger, cfg gracef
 		})
 	}
 
+	if cfg.providers != nil {
+		shutdownStep("snapshotting open orders", orderSnapshotShutdownTimeout, func(stepCtx context.Context) error {
+			count, err := cfg.providers.SnapshotOpenOrders(stepCtx)
+			if err == nil {
+				logger.Printf("shutdown: %d open orders snapshotted", count)
+			}
+			return err
+		})
+	}
+
 	if cfg.dataBus != nil {
 		shutdownStep("closing data bus", dataBusShutdownTimeout, func(stepCtx context.Context) error {
 			done := make(chan struct{})
@@ -460,6 +820,35 @@ func resolveConfigPath(flagValue string) string {
 	return filepath.Clean(defaultConfigPath)
 }
 
+// gatewayBuildInfo reports the link-time build metadata, falling back to the VCS revision the Go
+// toolchain stamps into the binary when no commit was injected.
+func gatewayBuildInfo() httpserver.BuildInfo {
+	info := httpserver.BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: goruntime.Version(),
+	}
+	if info.Commit != "" && info.BuildTime != "" {
+		return info
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+	return info
+}
+
 func runDatabaseMigrations(ctx context.Context, logger *log.Logger, dbCfg config.DatabaseConfig) error {
 	if !dbCfg.RunMigrations {
 		logger.Printf("database migrations disabled; skipping")
@@ -31,3 +31,17 @@ func TestResolveConfigPathDefaults(t *testing.T) {
 		t.Fatalf("expected %s, got %s", want, got)
 	}
 }
+
+func TestGatewayBuildInfoUsesInjectedValues(t *testing.T) {
+	origVersion, origCommit, origBuildTime := version, commit, buildTime
+	t.Cleanup(func() { version, commit, buildTime = origVersion, origCommit, origBuildTime })
+	version, commit, buildTime = "v1.2.3", "abc123", "2025-01-02T03:04:05Z"
+
+	info := gatewayBuildInfo()
+	if info.Version != "v1.2.3" || info.Commit != "abc123" || info.BuildTime != "2025-01-02T03:04:05Z" {
+		t.Fatalf("unexpected build info %+v", info)
+	}
+	if info.GoVersion == "" {
+		t.Fatal("expected go version")
+	}
+}
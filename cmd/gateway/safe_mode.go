@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sourcegraph/conc"
+
+	"github.com/coachpo/meltica/internal/domain/providerstore"
+	"github.com/coachpo/meltica/internal/domain/strategystore"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/persistence/migrations"
+	httpserver "github.com/coachpo/meltica/internal/infra/server/http"
+)
+
+// safeModeTrigger collects the startup failures that put the gateway in safe mode. When safe mode
+// is disabled, record leaves the failure to the caller's existing handling.
+type safeModeTrigger struct {
+	enabled  bool
+	forced   bool
+	failures []httpserver.SafeModeFailure
+}
+
+func newSafeModeTrigger(enabled, forced bool) *safeModeTrigger {
+	return &safeModeTrigger{enabled: enabled || forced, forced: forced, failures: nil}
+}
+
+// record notes a failed startup stage and reports whether safe mode will handle it.
+func (t *safeModeTrigger) record(logger *log.Logger, stage string, err error) bool {
+	if !t.enabled {
+		return false
+	}
+	logger.Printf("startup %s failed, continuing in safe mode: %v", stage, err)
+	t.failures = append(t.failures, httpserver.SafeModeFailure{Stage: stage, Error: err.Error(), At: time.Now().UTC()})
+	return true
+}
+
+func (t *safeModeTrigger) active() bool {
+	return t.forced || len(t.failures) > 0
+}
+
+// persistedState holds the provider and strategy snapshots, loaded before anything starts so a
+// store that cannot be read is caught while safe mode can still take over.
+type persistedState struct {
+	providers  []providerstore.Snapshot
+	strategies []strategystore.Snapshot
+}
+
+func loadPersistedState(ctx context.Context, logger *log.Logger, safeMode *safeModeTrigger, providers providerstore.Store, strategies strategystore.Store) persistedState {
+	state := persistedState{providers: nil, strategies: nil}
+	if providers != nil {
+		snapshots, err := providers.LoadProviders(ctx)
+		if err != nil {
+			if !safeMode.record(logger, "provider-restore", fmt.Errorf("load provider snapshots: %w", err)) {
+				logger.Printf("provider persistence load failed: %v", err)
+			}
+		} else {
+			state.providers = snapshots
+		}
+	}
+	if strategies != nil {
+		snapshots, err := strategies.Load(ctx)
+		if err != nil {
+			if !safeMode.record(logger, "strategy-restore", fmt.Errorf("load strategy snapshots: %w", err)) {
+				logger.Printf("strategy persistence load failed: %v", err)
+			}
+		} else {
+			state.strategies = snapshots
+		}
+	}
+	return state
+}
+
+// runSafeMode serves the read-only diagnostics API until shutdown. Providers, strategies, the
+// event bus and sinks are never started.
+func runSafeMode(ctx context.Context, cancel context.CancelFunc, logger *log.Logger, appCfg config.AppConfig, trigger *safeModeTrigger, dbPool *pgxpool.Pool, providers providerstore.Store, strategies strategystore.Store, opts ...httpserver.HandlerOption) {
+	state := httpserver.SafeModeState{
+		Forced:     trigger.forced,
+		Failures:   trigger.failures,
+		Providers:  providers,
+		Strategies: strategies,
+	}
+	opts = append(opts, schemaVersionOption(dbPool))
+	server := newControlServer(appCfg.APIServer.Addr, httpserver.NewSafeModeHandler(appCfg, state, opts...))
+
+	var lifecycle conc.WaitGroup
+	startAPIServer(&lifecycle, logger, server)
+	logger.Printf("SAFE MODE: providers and strategies not started (%d startup failures); diagnostics at %s/admin/safe-mode", len(trigger.failures), appCfg.APIServer.Addr)
+
+	<-ctx.Done()
+	logger.Print("shutdown signal received, stopping safe mode")
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCancel()
+	performGracefulShutdown(shutdownCtx, logger, gracefulShutdownConfig{
+		server:     server,
+		mainCancel: cancel,
+		lifecycle:  &lifecycle,
+		providers:  nil,
+		dataBus:    nil,
+		poolMgr:    nil,
+		telemetry:  nil,
+		dbPool:     dbPool,
+	})
+}
+
+// schemaVersionOption reports the applied migration version, or why it cannot be read when the
+// database is unavailable.
+func schemaVersionOption(dbPool *pgxpool.Pool) httpserver.HandlerOption {
+	return httpserver.WithSchemaVersion(func(ctx context.Context) (migrations.SchemaVersion, error) {
+		if dbPool == nil {
+			return migrations.CurrentVersion(ctx, nil)
+		}
+		return migrations.CurrentVersion(ctx, dbPool)
+	})
+}
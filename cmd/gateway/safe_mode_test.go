@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/providerstore"
+	"github.com/coachpo/meltica/internal/domain/strategystore"
+)
+
+type stubProviderStore struct {
+	providerstore.Store
+	snapshots []providerstore.Snapshot
+}
+
+func (s stubProviderStore) LoadProviders(context.Context) ([]providerstore.Snapshot, error) {
+	return s.snapshots, nil
+}
+
+type brokenStrategyStore struct {
+	strategystore.Store
+}
+
+func (brokenStrategyStore) Load(context.Context) ([]strategystore.Snapshot, error) {
+	return nil, errors.New("decode metadata")
+}
+
+func TestLoadPersistedStateEntersSafeModeOnFailure(t *testing.T) {
+	logger := log.New(io.Discard, "", 0)
+	providers := stubProviderStore{snapshots: []providerstore.Snapshot{{Name: "binance"}}}
+
+	trigger := newSafeModeTrigger(true, false)
+	state := loadPersistedState(context.Background(), logger, trigger, providers, brokenStrategyStore{})
+	if !trigger.active() || len(trigger.failures) != 1 || trigger.failures[0].Stage != "strategy-restore" {
+		t.Fatalf("expected strategy restore failure recorded, got %+v", trigger.failures)
+	}
+	if len(state.providers) != 1 || state.strategies != nil {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	disabled := newSafeModeTrigger(false, false)
+	state = loadPersistedState(context.Background(), logger, disabled, providers, brokenStrategyStore{})
+	if disabled.active() || len(state.providers) != 1 {
+		t.Fatalf("expected startup to continue without safe mode, got %+v", disabled.failures)
+	}
+	if disabled.record(logger, "migrations", errors.New("boom")) {
+		t.Fatal("expected disabled safe mode to leave failures to the caller")
+	}
+
+	if forced := newSafeModeTrigger(false, true); !forced.active() || !forced.record(logger, "migrations", errors.New("boom")) {
+		t.Fatal("expected forced safe mode to be active and record failures")
+	}
+}
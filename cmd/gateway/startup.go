@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// dependencyWaiter retries startup dependency checks with exponential backoff until they pass or
+// the startup.maxWait budget, shared by every dependency, is spent.
+type dependencyWaiter struct {
+	cfg      config.StartupConfig
+	logger   *log.Logger
+	deadline time.Time
+}
+
+func newDependencyWaiter(cfg config.StartupConfig, logger *log.Logger) *dependencyWaiter {
+	return &dependencyWaiter{cfg: cfg, logger: logger, deadline: time.Now().Add(cfg.MaxWait)}
+}
+
+// wait runs check until it succeeds, ctx ends or the budget runs out. Checks failing with
+// backoff.Permanent errors are not retried.
+func (w *dependencyWaiter) wait(ctx context.Context, name string, check func(context.Context) error) error {
+	started := time.Now()
+	remaining := w.deadline.Sub(started)
+	if remaining <= 0 {
+		if err := check(ctx); err != nil {
+			return fmt.Errorf("%s not ready, startup wait budget spent: %w", name, err)
+		}
+		return nil
+	}
+	policy := backoff.NewExponentialBackOff()
+	policy.InitialInterval = w.cfg.InitialBackoff
+	policy.MaxInterval = w.cfg.MaxBackoff
+	attempts := 0
+	_, err := backoff.Retry(ctx, func() (struct{}, error) {
+		attempts++
+		return struct{}{}, check(ctx)
+	},
+		backoff.WithBackOff(policy),
+		backoff.WithMaxElapsedTime(remaining),
+		backoff.WithNotify(func(err error, next time.Duration) {
+			w.logger.Printf("waiting for %s (attempt %d, retrying in %s): %v", name, attempts, next.Round(time.Millisecond), err)
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("%s not ready after %s: %w", name, time.Since(started).Round(time.Second), err)
+	}
+	if attempts > 1 {
+		w.logger.Printf("%s ready after %d attempts", name, attempts)
+	}
+	return nil
+}
+
+// awaitDatabase waits for Postgres when startup.waitForDatabase is set and reports whether startup
+// should go on to migrate and connect. With onTimeout degrade, a database that never comes up puts
+// the gateway in safe mode even when apiServer.safeMode is off.
+func awaitDatabase(ctx context.Context, logger *log.Logger, waiter *dependencyWaiter, appCfg config.AppConfig, safeMode *safeModeTrigger) bool {
+	if !appCfg.Startup.WaitForDatabase {
+		return true
+	}
+	err := waiter.wait(ctx, "database", func(ctx context.Context) error {
+		return pingDatabase(ctx, appCfg.Database.DSN)
+	})
+	if err == nil {
+		return true
+	}
+	if appCfg.Startup.OnTimeout == config.StartupTimeoutDegrade {
+		safeMode.enabled = true
+	}
+	if !safeMode.record(logger, "database", err) {
+		logger.Fatalf("wait for database: %v", err)
+	}
+	return false
+}
+
+// awaitTelemetry waits for the OTLP collector when startup.waitForTelemetry is set and metrics or
+// traces are exported. It reports whether telemetry export should stay enabled.
+func awaitTelemetry(ctx context.Context, logger *log.Logger, waiter *dependencyWaiter, startup config.StartupConfig, cfg telemetry.Config) (bool, error) {
+	if !startup.WaitForTelemetry || !cfg.Enabled || (!cfg.EnableMetrics && !cfg.EnableTracing) {
+		return true, nil
+	}
+	err := waiter.wait(ctx, "telemetry collector", func(ctx context.Context) error {
+		probeCtx, cancel := context.WithTimeout(ctx, telemetryProbeTimeout)
+		defer cancel()
+		return telemetry.ProbeEndpoint(probeCtx, cfg.OTLPEndpoint)
+	})
+	if err == nil {
+		return true, nil
+	}
+	if startup.OnTimeout == config.StartupTimeoutDegrade {
+		logger.Printf("%v; starting with telemetry export disabled", err)
+		return false, nil
+	}
+	return false, err
+}
+
+func pingDatabase(ctx context.Context, dsn string) error {
+	connCfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return backoff.Permanent(fmt.Errorf("parse database dsn: %w", err))
+	}
+	connCfg.ConnectTimeout = databaseConnectTimeout
+	connectCtx, cancel := context.WithTimeout(ctx, databaseConnectTimeout)
+	defer cancel()
+	conn, err := pgx.ConnectConfig(connectCtx, connCfg)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+	if err := conn.Ping(connectCtx); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+func testStartupConfig(maxWait time.Duration, policy config.StartupTimeoutPolicy) config.StartupConfig {
+	return config.StartupConfig{
+		WaitForDatabase:  true,
+		WaitForTelemetry: true,
+		MaxWait:          maxWait,
+		InitialBackoff:   5 * time.Millisecond,
+		MaxBackoff:       20 * time.Millisecond,
+		OnTimeout:        policy,
+	}
+}
+
+func TestDependencyWaiterRetriesUntilReady(t *testing.T) {
+	waiter := newDependencyWaiter(testStartupConfig(time.Second, config.StartupTimeoutFail), log.New(io.Discard, "", 0))
+	attempts := 0
+	err := waiter.wait(context.Background(), "database", func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("expected success on the third attempt, got %v after %d attempts", err, attempts)
+	}
+
+	attempts = 0
+	err = waiter.wait(context.Background(), "database", func(context.Context) error {
+		attempts++
+		return backoff.Permanent(errors.New("invalid dsn"))
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("expected a permanent error to stop retries, got %v after %d attempts", err, attempts)
+	}
+}
+
+func TestDependencyWaiterGivesUpWhenBudgetSpent(t *testing.T) {
+	waiter := newDependencyWaiter(testStartupConfig(50*time.Millisecond, config.StartupTimeoutFail), log.New(io.Discard, "", 0))
+	started := time.Now()
+	err := waiter.wait(context.Background(), "database", func(context.Context) error {
+		return errors.New("connection refused")
+	})
+	if err == nil || time.Since(started) > time.Second {
+		t.Fatalf("expected the wait to give up within the budget, got %v after %s", err, time.Since(started))
+	}
+
+	// The budget is shared: once it is spent, later dependencies get a single attempt.
+	time.Sleep(time.Until(waiter.deadline))
+	attempts := 0
+	_ = waiter.wait(context.Background(), "telemetry collector", func(context.Context) error {
+		attempts++
+		return errors.New("connection refused")
+	})
+	if attempts != 1 {
+		t.Fatalf("expected one attempt once the budget is spent, got %d", attempts)
+	}
+}
+
+func TestAwaitTelemetryTimeoutPolicy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	endpoint := "http://" + listener.Addr().String()
+	_ = listener.Close()
+	cfg := telemetry.Config{Enabled: true, EnableMetrics: true, OTLPEndpoint: endpoint}
+	logger := log.New(io.Discard, "", 0)
+
+	degrade := testStartupConfig(30*time.Millisecond, config.StartupTimeoutDegrade)
+	if ready, err := awaitTelemetry(context.Background(), logger, newDependencyWaiter(degrade, logger), degrade, cfg); ready || err != nil {
+		t.Fatalf("expected degrade to disable metrics export, got ready=%v err=%v", ready, err)
+	}
+	fail := testStartupConfig(30*time.Millisecond, config.StartupTimeoutFail)
+	if _, err := awaitTelemetry(context.Background(), logger, newDependencyWaiter(fail, logger), fail, cfg); err == nil {
+		t.Fatal("expected fail to report the unreachable collector")
+	}
+	fail.WaitForTelemetry = false
+	if ready, err := awaitTelemetry(context.Background(), logger, newDependencyWaiter(fail, logger), fail, cfg); !ready || err != nil {
+		t.Fatalf("expected no wait when waitForTelemetry is off, got ready=%v err=%v", ready, err)
+	}
+}
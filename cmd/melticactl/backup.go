@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+const contextBackupPath = "/context/backup"
+
+func (a *app) newBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "backup",
+		Aliases: []string{"backups"},
+		Short:   "Export and restore providers, instances and risk settings",
+	}
+
+	var outFile string
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "Export a context backup",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.getJSON(c.Context(), contextBackupPath, nil, nil)
+			if err != nil {
+				return err
+			}
+			if outFile == "" || outFile == "-" {
+				return writeRawJSON(a.stdout, data)
+			}
+			if err := os.WriteFile(outFile, data, 0o600); err != nil {
+				return fmt.Errorf("write %s: %w", outFile, err)
+			}
+			fmt.Fprintf(a.stdout, "backup written to %s\n", outFile)
+			return nil
+		},
+	}
+	export.Flags().StringVarP(&outFile, "file", "f", "", "Write the backup to this file instead of stdout")
+
+	var inFile string
+	restore := &cobra.Command{
+		Use:   "restore -f BACKUP",
+		Short: "Restore a context backup",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			body, err := readSpecFile(inFile)
+			if err != nil {
+				return err
+			}
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.do(c.Context(), http.MethodPost, contextBackupPath, nil, body)
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+	restore.Flags().StringVarP(&inFile, "file", "f", "", "Backup file (JSON or YAML, - for stdin)")
+	_ = restore.MarkFlagRequired("file")
+
+	cmd.AddCommand(export, restore)
+	return cmd
+}
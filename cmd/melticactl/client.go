@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+const userAgent = "melticactl"
+
+// client issues requests against the control API of a single gateway.
+type client struct {
+	target target
+	http   *http.Client
+}
+
+// apiError reports a non-2xx control API response.
+type apiError struct {
+	Status  int
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("control api returned %d: %s", e.Status, e.Message)
+}
+
+func newClient(t target, timeout time.Duration) *client {
+	return &client{
+		target: t,
+		http: &http.Client{
+			Transport:     nil,
+			CheckRedirect: nil,
+			Jar:           nil,
+			Timeout:       timeout,
+		},
+	}
+}
+
+// do sends body (encoded as JSON unless it is already raw bytes) and returns the raw response body.
+func (c *client) do(ctx context.Context, method, path string, query url.Values, body any) ([]byte, error) {
+	var encoded []byte
+	contentType := ""
+	if body != nil {
+		switch typed := body.(type) {
+		case []byte:
+			encoded = typed
+		default:
+			var err error
+			encoded, err = json.Marshal(body)
+			if err != nil {
+				return nil, fmt.Errorf("encode request: %w", err)
+			}
+		}
+		contentType = "application/json"
+	}
+	return c.send(ctx, method, path, query, contentType, encoded)
+}
+
+// send issues a request with a raw body of the given content type; an empty content type sends
+// no body.
+func (c *client) send(ctx context.Context, method, path string, query url.Values, contentType string, body []byte) ([]byte, error) {
+	endpoint := c.target.Endpoint + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if contentType != "" {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.target.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.target.Token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &apiError{Status: resp.StatusCode, Message: errorMessage(data)}
+	}
+	return data, nil
+}
+
+// getJSON issues a GET request and decodes the response into out.
+func (c *client) getJSON(ctx context.Context, path string, query url.Values, out any) ([]byte, error) {
+	data, err := c.do(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+	}
+	return data, nil
+}
+
+func errorMessage(data []byte) string {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &payload); err == nil && payload.Error != "" {
+		return payload.Error
+	}
+	message := strings.TrimSpace(string(data))
+	if message == "" {
+		return "empty response"
+	}
+	return message
+}
+
+func escape(segment string) string {
+	return url.PathEscape(strings.TrimSpace(segment))
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	json "github.com/goccy/go-json"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const instancesPath = "/strategy/instances"
+
+type instanceRow struct {
+	ID                 string   `json:"id"`
+	StrategyIdentifier string   `json:"strategyIdentifier"`
+	StrategyTag        string   `json:"strategyTag"`
+	StrategyHash       string   `json:"strategyHash"`
+	Providers          []string `json:"providers"`
+	AggregatedSymbols  []string `json:"aggregatedSymbols"`
+	Running            bool     `json:"running"`
+}
+
+func (a *app) newInstancesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "instances",
+		Aliases: []string{"instance", "inst"},
+		Short:   "List, create, start and stop strategy instances",
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List strategy instances",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			var resp struct {
+				Instances []instanceRow `json:"instances"`
+			}
+			data, err := cl.getJSON(c.Context(), instancesPath, nil, &resp)
+			if err != nil {
+				return err
+			}
+			return a.render(data, []string{"ID", "STRATEGY", "REVISION", "PROVIDERS", "SYMBOLS", "RUNNING"}, func() [][]string {
+				rows := make([][]string, 0, len(resp.Instances))
+				for _, inst := range resp.Instances {
+					rows = append(rows, []string{
+						inst.ID,
+						inst.StrategyIdentifier,
+						orDash(firstNonEmpty(inst.StrategyTag, shortHash(inst.StrategyHash))),
+						joinOrDash(inst.Providers),
+						joinOrDash(inst.AggregatedSymbols),
+						boolString(inst.Running),
+					})
+				}
+				return rows
+			})
+		},
+	}
+
+	get := &cobra.Command{
+		Use:   "get ID",
+		Short: "Show an instance snapshot",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.getJSON(c.Context(), instancesPath+"/"+escape(args[0]), nil, nil)
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+
+	var specFile string
+	create := &cobra.Command{
+		Use:   "create -f SPEC",
+		Short: "Create an instance from a JSON or YAML spec",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			body, err := readSpecFile(specFile)
+			if err != nil {
+				return err
+			}
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.do(c.Context(), http.MethodPost, instancesPath, nil, body)
+			if err != nil {
+				return err
+			}
+			return a.printInstanceResult(data, "created")
+		},
+	}
+	create.Flags().StringVarP(&specFile, "file", "f", "", "Instance spec file (JSON or YAML, - for stdin)")
+	_ = create.MarkFlagRequired("file")
+
+	cmd.AddCommand(list, get, create,
+		a.instanceActionCommand("start", "Start an instance"),
+		a.instanceActionCommand("stop", "Stop an instance"),
+	)
+	return cmd
+}
+
+func (a *app) instanceActionCommand(action, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   action + " ID",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.do(c.Context(), http.MethodPost, instancesPath+"/"+escape(args[0])+"/"+action, nil, nil)
+			if err != nil {
+				return err
+			}
+			return a.printInstanceResult(data, action+" requested")
+		},
+	}
+}
+
+func (a *app) printInstanceResult(data []byte, verb string) error {
+	if a.format() == outputJSON {
+		return writeRawJSON(a.stdout, data)
+	}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil || resp.ID == "" {
+		return writeRawJSON(a.stdout, data)
+	}
+	fmt.Fprintf(a.stdout, "instance %s %s\n", resp.ID, verb)
+	return nil
+}
+
+// readSpecFile loads a JSON or YAML document and returns it re-encoded as JSON.
+func readSpecFile(path string) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".json" || json.Valid(data) {
+		return data, nil
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", path, err)
+	}
+	return encoded, nil
+}
+
+func shortHash(hash string) string {
+	hash = strings.TrimPrefix(hash, "sha256:")
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
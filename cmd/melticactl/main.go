@@ -0,0 +1,103 @@
+// Command melticactl is an operator CLI for the Meltica control API.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultEndpoint = "http://localhost:8880"
+	defaultTimeout  = 30 * time.Second
+	endpointEnvVar  = "MELTICA_ENDPOINT"
+	tokenEnvVar     = "MELTICA_TOKEN"
+	profileEnvVar   = "MELTICA_PROFILE"
+	configEnvVar    = "MELTICACTL_CONFIG"
+)
+
+func main() {
+	if err := newRootCommand(os.Stdout, os.Stderr).Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// globalOptions holds the persistent flags shared by every subcommand.
+type globalOptions struct {
+	configPath string
+	profile    string
+	endpoint   string
+	token      string
+	output     string
+	timeout    time.Duration
+}
+
+// app carries resolved options and output streams into subcommands.
+type app struct {
+	opts   globalOptions
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func newRootCommand(stdout, stderr io.Writer) *cobra.Command {
+	a := &app{
+		opts: globalOptions{
+			configPath: "",
+			profile:    "",
+			endpoint:   "",
+			token:      "",
+			output:     string(outputTable),
+			timeout:    defaultTimeout,
+		},
+		stdout: stdout,
+		stderr: stderr,
+	}
+	root := &cobra.Command{
+		Use:           "melticactl",
+		Short:         "Manage a Meltica gateway through its control API",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			_, err := parseOutputFormat(a.opts.output)
+			return err
+		},
+	}
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.opts.configPath, "config", "", "Path to the profiles file (default $"+configEnvVar+" or ~/.config/melticactl/config.yaml)")
+	flags.StringVarP(&a.opts.profile, "profile", "p", "", "Profile to use (default $"+profileEnvVar+" or the file's currentProfile)")
+	flags.StringVar(&a.opts.endpoint, "endpoint", "", "Control API base URL, overrides the profile")
+	flags.StringVar(&a.opts.token, "token", "", "Bearer token, overrides the profile")
+	flags.StringVarP(&a.opts.output, "output", "o", string(outputTable), "Output format: table or json")
+	flags.DurationVar(&a.opts.timeout, "timeout", defaultTimeout, "Request timeout")
+
+	root.AddCommand(
+		a.newInstancesCommand(),
+		a.newProvidersCommand(),
+		a.newStrategiesCommand(),
+		a.newRiskCommand(),
+		a.newBackupCommand(),
+		a.newProfilesCommand(),
+	)
+	return root
+}
+
+// client resolves the active profile and returns a control API client for it.
+func (a *app) client() (*client, error) {
+	target, err := resolveTarget(a.opts, os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(target, a.opts.timeout), nil
+}
+
+func (a *app) format() outputFormat {
+	format, _ := parseOutputFormat(a.opts.output)
+	return format
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeProfiles(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write profiles: %v", err)
+	}
+	return path
+}
+
+func TestResolveTargetPrecedence(t *testing.T) {
+	path := writeProfiles(t, `currentProfile: prod
+profiles:
+  prod:
+    endpoint: https://prod.example.com/
+    token: prod-token
+  staging:
+    endpoint: http://staging:8880
+`)
+	env := map[string]string{}
+	getenv := func(key string) string { return env[key] }
+
+	got, err := resolveTarget(globalOptions{configPath: path}, getenv)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got.Profile != "prod" || got.Endpoint != "https://prod.example.com" || got.Token != "prod-token" {
+		t.Fatalf("unexpected current profile target: %+v", got)
+	}
+
+	env[profileEnvVar] = "staging"
+	got, err = resolveTarget(globalOptions{configPath: path}, getenv)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got.Endpoint != "http://staging:8880" || got.Token != "" {
+		t.Fatalf("expected env profile, got %+v", got)
+	}
+
+	env[tokenEnvVar] = "env-token"
+	got, err = resolveTarget(globalOptions{configPath: path, profile: "prod", endpoint: "http://override:1"}, getenv)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got.Profile != "prod" || got.Endpoint != "http://override:1" || got.Token != "env-token" {
+		t.Fatalf("expected flag and env overrides, got %+v", got)
+	}
+
+	if _, err := resolveTarget(globalOptions{configPath: path, profile: "missing"}, getenv); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
+
+func TestResolveTargetDefaultsWithoutFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "absent.yaml")
+	got, err := resolveTarget(globalOptions{configPath: path}, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got.Endpoint != defaultEndpoint || got.Profile != defaultProfileName {
+		t.Fatalf("unexpected default target: %+v", got)
+	}
+	if _, err := resolveTarget(globalOptions{configPath: path, endpoint: "localhost:8880"}, func(string) string { return "" }); err == nil {
+		t.Fatal("expected error for endpoint without scheme")
+	}
+}
+
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	cmd := newRootCommand(&stdout, &stderr)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+func TestInstancesListTableAndJSON(t *testing.T) {
+	var authHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		if r.URL.Path != instancesPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"instances":[{"id":"grid-1","strategyIdentifier":"grid","strategyTag":"v2","providers":["binance"],"aggregatedSymbols":["BTC-USDT"],"running":true}]}`)
+	}))
+	defer srv.Close()
+	path := writeProfiles(t, "profiles:\n  local:\n    endpoint: "+srv.URL+"\n    token: secret\n")
+
+	out, err := runCLI(t, "--config", path, "--profile", "local", "instances", "list")
+	if err != nil {
+		t.Fatalf("instances list: %v", err)
+	}
+	if authHeader != "Bearer secret" {
+		t.Fatalf("expected bearer token, got %q", authHeader)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "ID") {
+		t.Fatalf("unexpected table output:\n%s", out)
+	}
+	for _, want := range []string{"grid-1", "grid", "v2", "binance", "BTC-USDT", "yes"} {
+		if !strings.Contains(lines[1], want) {
+			t.Fatalf("table row missing %q: %s", want, lines[1])
+		}
+	}
+
+	out, err = runCLI(t, "--config", path, "--profile", "local", "-o", "json", "instances", "list")
+	if err != nil {
+		t.Fatalf("instances list json: %v", err)
+	}
+	if !strings.Contains(out, `"id": "grid-1"`) {
+		t.Fatalf("expected indented json, got:\n%s", out)
+	}
+}
+
+func TestCommandSurfacesAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != instancesPath+"/missing/start" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"status":"error","error":"instance missing not found"}`)
+	}))
+	defer srv.Close()
+
+	_, err := runCLI(t, "--config", filepath.Join(t.TempDir(), "none.yaml"), "--endpoint", srv.URL, "instances", "start", "missing")
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "instance missing not found") {
+		t.Fatalf("expected api error, got %v", err)
+	}
+}
+
+func TestCreateInstanceConvertsYAMLSpec(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"id":"grid-2"}`)
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "instance.yaml")
+	if err := os.WriteFile(spec, []byte("id: grid-2\nstrategy:\n  identifier: grid\n"), 0o600); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+
+	out, err := runCLI(t, "--config", filepath.Join(dir, "none.yaml"), "--endpoint", srv.URL, "instances", "create", "-f", spec)
+	if err != nil {
+		t.Fatalf("instances create: %v", err)
+	}
+	if !strings.Contains(body, `"identifier":"grid"`) {
+		t.Fatalf("expected JSON request body, got %s", body)
+	}
+	if strings.TrimSpace(out) != "instance grid-2 created" {
+		t.Fatalf("unexpected output %q", out)
+	}
+}
+
+func TestProfilesSetAndUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+	if _, err := runCLI(t, "--config", path, "profiles", "set", "prod", "--endpoint", "https://prod.example.com", "--token", "abc"); err != nil {
+		t.Fatalf("profiles set: %v", err)
+	}
+	if _, err := runCLI(t, "--config", path, "profiles", "set", "dev", "--endpoint", "http://localhost:8880"); err != nil {
+		t.Fatalf("profiles set: %v", err)
+	}
+	if _, err := runCLI(t, "--config", path, "profiles", "use", "dev"); err != nil {
+		t.Fatalf("profiles use: %v", err)
+	}
+	file, err := loadProfiles(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if file.CurrentProfile != "dev" || file.Profiles["prod"].Token != "abc" {
+		t.Fatalf("unexpected profiles file: %+v", file)
+	}
+	if _, err := runCLI(t, "--config", path, "profiles", "use", "missing"); err == nil {
+		t.Fatal("expected error selecting unknown profile")
+	}
+}
+
+func TestStrategiesExportAndImportArchive(t *testing.T) {
+	archive := []byte("\x1f\x8barchive")
+	var gotQuery, gotType string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != strategyArchivePath {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/gzip")
+			_, _ = w.Write(archive)
+			return
+		}
+		gotQuery = r.URL.RawQuery
+		gotType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, `{"status":"ok","dryRun":true,"result":{"addedRevisions":["grid@sha256:abc"],"tagChanges":[{"strategy":"grid","tag":"latest","hash":"sha256:abc"}],"conflicts":[]}}`)
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "catalog.tar.gz")
+
+	if _, err := runCLI(t, "--config", filepath.Join(dir, "none.yaml"), "--endpoint", srv.URL, "strategies", "export", "-f", file); err != nil {
+		t.Fatalf("strategies export: %v", err)
+	}
+	written, err := os.ReadFile(file)
+	if err != nil || !bytes.Equal(written, archive) {
+		t.Fatalf("expected archive bytes on disk, got %q (%v)", written, err)
+	}
+
+	out, err := runCLI(t, "--config", filepath.Join(dir, "none.yaml"), "--endpoint", srv.URL, "strategies", "import", "-f", file, "--conflict", "overwrite", "--dry-run")
+	if err != nil {
+		t.Fatalf("strategies import: %v", err)
+	}
+	if gotType != "application/gzip" || !bytes.Equal(gotBody, archive) {
+		t.Fatalf("expected raw archive upload, got %q (%d bytes)", gotType, len(gotBody))
+	}
+	if gotQuery != "conflict=overwrite&dryRun=true" {
+		t.Fatalf("unexpected query %q", gotQuery)
+	}
+	if !strings.HasPrefix(out, "would import 1 revision(s), move 1 tag(s)") || !strings.Contains(out, "tag grid:latest - -> abc") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	json "github.com/goccy/go-json"
+)
+
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+)
+
+func parseOutputFormat(raw string) (outputFormat, error) {
+	switch outputFormat(strings.ToLower(strings.TrimSpace(raw))) {
+	case "", outputTable:
+		return outputTable, nil
+	case outputJSON:
+		return outputJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported output format %q (want table or json)", raw)
+	}
+}
+
+func writeTable(w io.Writer, headers []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("write table: %w", err)
+	}
+	return nil
+}
+
+// writeRawJSON pretty-prints a response body exactly as returned by the API.
+func writeRawJSON(w io.Writer, data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		buf.Reset()
+		buf.Write(data)
+	}
+	buf.WriteByte('\n')
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+func writeJSONValue(w io.Writer, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode output: %w", err)
+	}
+	data = append(data, '\n')
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+	return nil
+}
+
+// render prints raw JSON or, in table mode, the rows produced by table.
+func (a *app) render(data []byte, headers []string, table func() [][]string) error {
+	if a.format() == outputJSON || table == nil {
+		return writeRawJSON(a.stdout, data)
+	}
+	return writeTable(a.stdout, headers, table())
+}
+
+func boolString(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func joinOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ",")
+}
+
+func orDash(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "-"
+	}
+	return value
+}
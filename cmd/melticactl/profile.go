@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultProfileName = "default"
+
+// profile stores the endpoint and credentials of one gateway.
+type profile struct {
+	Endpoint string `yaml:"endpoint"`
+	Token    string `yaml:"token,omitempty"`
+}
+
+// profileFile is the on-disk layout of the melticactl configuration.
+type profileFile struct {
+	CurrentProfile string             `yaml:"currentProfile,omitempty"`
+	Profiles       map[string]profile `yaml:"profiles"`
+}
+
+// target is the resolved endpoint and token used for requests.
+type target struct {
+	Profile  string
+	Endpoint string
+	Token    string
+}
+
+func defaultConfigPath(getenv func(string) string) string {
+	if path := strings.TrimSpace(getenv(configEnvVar)); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(".melticactl", "config.yaml")
+	}
+	return filepath.Join(dir, "melticactl", "config.yaml")
+}
+
+// loadProfiles reads the profiles file; a missing file yields an empty configuration.
+func loadProfiles(path string) (profileFile, error) {
+	file := profileFile{CurrentProfile: "", Profiles: map[string]profile{}}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return file, nil
+		}
+		return file, fmt.Errorf("read profiles %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("parse profiles %s: %w", path, err)
+	}
+	if file.Profiles == nil {
+		file.Profiles = map[string]profile{}
+	}
+	return file, nil
+}
+
+func saveProfiles(path string, file profileFile) error {
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("encode profiles: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create profiles directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write profiles %s: %w", path, err)
+	}
+	return nil
+}
+
+// resolveTarget merges flags, environment and the selected profile, in that order of precedence.
+func resolveTarget(opts globalOptions, getenv func(string) string) (target, error) {
+	path := opts.configPath
+	if path == "" {
+		path = defaultConfigPath(getenv)
+	}
+	file, err := loadProfiles(path)
+	if err != nil {
+		return target{}, err
+	}
+	name := firstNonEmpty(opts.profile, getenv(profileEnvVar), file.CurrentProfile)
+	explicit := name != ""
+	if name == "" {
+		name = defaultProfileName
+	}
+	selected, ok := file.Profiles[name]
+	if !ok && explicit {
+		return target{}, fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	resolved := target{
+		Profile:  name,
+		Endpoint: firstNonEmpty(opts.endpoint, getenv(endpointEnvVar), selected.Endpoint, defaultEndpoint),
+		Token:    firstNonEmpty(opts.token, getenv(tokenEnvVar), selected.Token),
+	}
+	resolved.Endpoint = strings.TrimRight(resolved.Endpoint, "/")
+	if !strings.HasPrefix(resolved.Endpoint, "http://") && !strings.HasPrefix(resolved.Endpoint, "https://") {
+		return target{}, fmt.Errorf("endpoint %q must start with http:// or https://", resolved.Endpoint)
+	}
+	return resolved, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+func (a *app) profilesPath() string {
+	if a.opts.configPath != "" {
+		return a.opts.configPath
+	}
+	return defaultConfigPath(os.Getenv)
+}
+
+func (a *app) newProfilesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage endpoint and credential profiles",
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List configured profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			file, err := loadProfiles(a.profilesPath())
+			if err != nil {
+				return err
+			}
+			names := make([]string, 0, len(file.Profiles))
+			for name := range file.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if a.format() == outputJSON {
+				return writeJSONValue(a.stdout, file)
+			}
+			rows := make([][]string, 0, len(names))
+			for _, name := range names {
+				current := ""
+				if name == file.CurrentProfile {
+					current = "*"
+				}
+				token := ""
+				if file.Profiles[name].Token != "" {
+					token = "set"
+				}
+				rows = append(rows, []string{current, name, file.Profiles[name].Endpoint, token})
+			}
+			return writeTable(a.stdout, []string{"CURRENT", "NAME", "ENDPOINT", "TOKEN"}, rows)
+		},
+	}
+
+	var endpoint, token string
+	set := &cobra.Command{
+		Use:   "set NAME",
+		Short: "Create or update a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			path := a.profilesPath()
+			file, err := loadProfiles(path)
+			if err != nil {
+				return err
+			}
+			entry := file.Profiles[args[0]]
+			if endpoint != "" {
+				entry.Endpoint = strings.TrimRight(endpoint, "/")
+			}
+			if token != "" {
+				entry.Token = token
+			}
+			if entry.Endpoint == "" {
+				return errors.New("--endpoint required for a new profile")
+			}
+			file.Profiles[args[0]] = entry
+			if file.CurrentProfile == "" {
+				file.CurrentProfile = args[0]
+			}
+			if err := saveProfiles(path, file); err != nil {
+				return err
+			}
+			fmt.Fprintf(a.stdout, "profile %s saved to %s\n", args[0], path)
+			return nil
+		},
+	}
+	set.Flags().StringVar(&endpoint, "endpoint", "", "Control API base URL")
+	set.Flags().StringVar(&token, "token", "", "Bearer token sent as the Authorization header")
+
+	use := &cobra.Command{
+		Use:   "use NAME",
+		Short: "Select the default profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			path := a.profilesPath()
+			file, err := loadProfiles(path)
+			if err != nil {
+				return err
+			}
+			if _, ok := file.Profiles[args[0]]; !ok {
+				return fmt.Errorf("profile %q not found in %s", args[0], path)
+			}
+			file.CurrentProfile = args[0]
+			if err := saveProfiles(path, file); err != nil {
+				return err
+			}
+			fmt.Fprintf(a.stdout, "using profile %s\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.AddCommand(list, set, use)
+	return cmd
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	json "github.com/goccy/go-json"
+	"github.com/spf13/cobra"
+)
+
+const providersPath = "/providers"
+
+type providerRow struct {
+	Name            string `json:"name"`
+	Adapter         string `json:"adapter"`
+	InstrumentCount int    `json:"instrumentCount"`
+	Running         bool   `json:"running"`
+	Status          string `json:"status"`
+	StartupError    string `json:"startupError"`
+	DependentCount  int    `json:"dependentInstanceCount"`
+}
+
+func (a *app) newProvidersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "providers",
+		Aliases: []string{"provider"},
+		Short:   "Inspect, start and stop providers",
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List providers",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			var resp struct {
+				Providers []providerRow `json:"providers"`
+			}
+			data, err := cl.getJSON(c.Context(), providersPath, nil, &resp)
+			if err != nil {
+				return err
+			}
+			return a.render(data, []string{"NAME", "ADAPTER", "STATUS", "RUNNING", "INSTRUMENTS", "INSTANCES", "ERROR"}, func() [][]string {
+				rows := make([][]string, 0, len(resp.Providers))
+				for _, p := range resp.Providers {
+					rows = append(rows, []string{
+						p.Name,
+						p.Adapter,
+						orDash(p.Status),
+						boolString(p.Running),
+						strconv.Itoa(p.InstrumentCount),
+						strconv.Itoa(p.DependentCount),
+						orDash(p.StartupError),
+					})
+				}
+				return rows
+			})
+		},
+	}
+
+	get := &cobra.Command{
+		Use:   "get NAME",
+		Short: "Show provider details",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.getJSON(c.Context(), providersPath+"/"+escape(args[0]), nil, nil)
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+
+	balances := &cobra.Command{
+		Use:   "balances NAME",
+		Short: "Show cached balances for a provider",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.getJSON(c.Context(), providersPath+"/"+escape(args[0])+"/balances", nil, nil)
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+
+	cmd.AddCommand(list, get, balances,
+		a.providerActionCommand("start", "Start a provider"),
+		a.providerActionCommand("stop", "Stop a provider"),
+	)
+	return cmd
+}
+
+func (a *app) providerActionCommand(action, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   action + " NAME",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.do(c.Context(), http.MethodPost, providersPath+"/"+escape(args[0])+"/"+action, nil, nil)
+			if err != nil {
+				return err
+			}
+			if a.format() == outputJSON {
+				return writeRawJSON(a.stdout, data)
+			}
+			var resp providerRow
+			if err := json.Unmarshal(data, &resp); err != nil || resp.Name == "" {
+				return writeRawJSON(a.stdout, data)
+			}
+			fmt.Fprintf(a.stdout, "provider %s %s requested (status %s)\n", resp.Name, action, orDash(resp.Status))
+			return nil
+		},
+	}
+}
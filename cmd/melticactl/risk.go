@@ -0,0 +1,129 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	riskLimitsPath     = "/risk/limits"
+	riskHeartbeatPath  = "/risk/heartbeat"
+	riskStatusPath     = "/risk/status"
+	riskKillSwitchPath = "/risk/kill-switch"
+)
+
+func (a *app) newRiskCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "risk",
+		Short: "View and update risk limits",
+	}
+
+	limits := &cobra.Command{
+		Use:   "limits",
+		Short: "Show the active risk limits",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.getJSON(c.Context(), riskLimitsPath, nil, nil)
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+
+	var limitsFile string
+	set := &cobra.Command{
+		Use:   "set -f LIMITS",
+		Short: "Replace the risk limits from a JSON or YAML file",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			body, err := readSpecFile(limitsFile)
+			if err != nil {
+				return err
+			}
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.do(c.Context(), http.MethodPut, riskLimitsPath, nil, body)
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+	set.Flags().StringVarP(&limitsFile, "file", "f", "", "Risk limits file (JSON or YAML, - for stdin)")
+	_ = set.MarkFlagRequired("file")
+
+	var send, resume bool
+	heartbeat := &cobra.Command{
+		Use:   "heartbeat",
+		Short: "Show the dead man's switch, or record a heartbeat with --send",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			var data []byte
+			if send || resume {
+				data, err = cl.do(c.Context(), http.MethodPost, riskHeartbeatPath, nil, map[string]bool{"resume": resume})
+			} else {
+				data, err = cl.getJSON(c.Context(), riskHeartbeatPath, nil, nil)
+			}
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+	heartbeat.Flags().BoolVar(&send, "send", false, "Record a controller heartbeat")
+	heartbeat.Flags().BoolVar(&resume, "resume", false, "Record a heartbeat and resume trading after a dead man's switch trip")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Show kill switch state, order rates and limit utilization",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.getJSON(c.Context(), riskStatusPath, nil, nil)
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+
+	var release bool
+	var haltReason string
+	killSwitch := &cobra.Command{
+		Use:   "kill-switch",
+		Short: "Halt all trading, or resume it with --release",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			payload := map[string]any{"engaged": !release, "reason": haltReason}
+			data, err := cl.do(c.Context(), http.MethodPost, riskKillSwitchPath, nil, payload)
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+	killSwitch.Flags().BoolVar(&release, "release", false, "Release the kill switch instead of engaging it")
+	killSwitch.Flags().StringVar(&haltReason, "reason", "", "Reason recorded with the halt")
+
+	cmd.AddCommand(limits, set, status, heartbeat, killSwitch)
+	return cmd
+}
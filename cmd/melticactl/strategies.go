@@ -0,0 +1,309 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	json "github.com/goccy/go-json"
+	"github.com/spf13/cobra"
+)
+
+const (
+	strategyModulesPath = "/strategies/modules"
+	strategyRefreshPath = "/strategies/refresh"
+	strategyArchivePath = "/strategies/archive"
+)
+
+type moduleRow struct {
+	Name     string   `json:"name"`
+	Hash     string   `json:"selectedRevisionHash"`
+	Tag      string   `json:"selectedRevisionTag"`
+	Tags     []string `json:"tags"`
+	Size     int64    `json:"size"`
+	Metadata struct {
+		Version string `json:"version"`
+	} `json:"metadata"`
+}
+
+func (a *app) newStrategiesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "strategies",
+		Aliases: []string{"strategy"},
+		Short:   "Upload, tag and refresh strategy modules",
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List strategy modules",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			var resp struct {
+				Modules []moduleRow `json:"modules"`
+			}
+			data, err := cl.getJSON(c.Context(), strategyModulesPath, nil, &resp)
+			if err != nil {
+				return err
+			}
+			return a.render(data, []string{"NAME", "VERSION", "TAG", "HASH", "TAGS"}, func() [][]string {
+				rows := make([][]string, 0, len(resp.Modules))
+				for _, m := range resp.Modules {
+					rows = append(rows, []string{
+						m.Name,
+						orDash(m.Metadata.Version),
+						orDash(m.Tag),
+						orDash(shortHash(m.Hash)),
+						joinOrDash(m.Tags),
+					})
+				}
+				return rows
+			})
+		},
+	}
+
+	var (
+		validateOnly bool
+		actor        string
+		reason       string
+	)
+	upload := &cobra.Command{
+		Use:   "upload FILE",
+		Short: "Upload a JavaScript strategy module",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			source, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("read %s: %w", args[0], err)
+			}
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			var query url.Values
+			if validateOnly {
+				query = url.Values{"validate": []string{"true"}}
+			}
+			payload := map[string]string{"source": string(source), "actor": actor, "reason": reason}
+			data, err := cl.do(c.Context(), http.MethodPost, strategyModulesPath, query, payload)
+			if err != nil {
+				return err
+			}
+			if a.format() == outputJSON {
+				return writeRawJSON(a.stdout, data)
+			}
+			var resp struct {
+				Status string `json:"status"`
+				Module struct {
+					Name string `json:"name"`
+					Hash string `json:"hash"`
+					Tag  string `json:"tag"`
+				} `json:"module"`
+				Diagnostics []uploadDiagnostic `json:"diagnostics"`
+				Preflight   struct {
+					Diagnostics []uploadDiagnostic `json:"diagnostics"`
+				} `json:"preflight"`
+			}
+			if err := json.Unmarshal(data, &resp); err != nil || resp.Module.Name == "" {
+				return writeRawJSON(a.stdout, data)
+			}
+			fmt.Fprintf(a.stdout, "module %s uploaded as %s (%s): %s\n",
+				resp.Module.Name, orDash(resp.Module.Tag), shortHash(resp.Module.Hash), resp.Status)
+			for _, diag := range append(resp.Diagnostics, resp.Preflight.Diagnostics...) {
+				fmt.Fprintf(a.stdout, "%s: line %d:%d: %s\n", orDash(diag.Severity), diag.Line, diag.Column, diag.Message)
+			}
+			return nil
+		},
+	}
+	upload.Flags().BoolVar(&validateOnly, "validate", false, "Validate the module without storing it")
+	upload.Flags().StringVar(&actor, "actor", "", "Actor recorded in the revision history")
+	upload.Flags().StringVar(&reason, "reason", "", "Reason recorded in the revision history")
+
+	var (
+		tagHash   string
+		noRefresh bool
+	)
+	tag := &cobra.Command{
+		Use:   "tag NAME TAG",
+		Short: "Point a tag at a module revision",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(c *cobra.Command, args []string) error {
+			if strings.TrimSpace(tagHash) == "" {
+				return errors.New("--hash required")
+			}
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			refresh := !noRefresh
+			payload := map[string]any{"hash": tagHash, "refresh": refresh, "actor": actor, "reason": reason}
+			path := strategyModulesPath + "/" + escape(args[0]) + "/tags/" + escape(args[1])
+			data, err := cl.do(c.Context(), http.MethodPut, path, nil, payload)
+			if err != nil {
+				return err
+			}
+			if a.format() == outputJSON {
+				return writeRawJSON(a.stdout, data)
+			}
+			fmt.Fprintf(a.stdout, "tag %s:%s now points at %s\n", args[0], args[1], shortHash(tagHash))
+			return nil
+		},
+	}
+	tag.Flags().StringVar(&tagHash, "hash", "", "Revision hash the tag should reference")
+	tag.Flags().BoolVar(&noRefresh, "no-refresh", false, "Do not refresh instances pinned to the tag")
+	tag.Flags().StringVar(&actor, "actor", "", "Actor recorded in the revision history")
+	tag.Flags().StringVar(&reason, "reason", "", "Reason recorded in the revision history")
+
+	var (
+		hashes     []string
+		strategies []string
+	)
+	refresh := &cobra.Command{
+		Use:   "refresh",
+		Short: "Reload strategy modules and restart affected instances",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			var payload any
+			if len(hashes) > 0 || len(strategies) > 0 {
+				payload = map[string][]string{"hashes": hashes, "strategies": strategies}
+			}
+			data, err := cl.do(c.Context(), http.MethodPost, strategyRefreshPath, nil, payload)
+			if err != nil {
+				return err
+			}
+			return writeRawJSON(a.stdout, data)
+		},
+	}
+	refresh.Flags().StringSliceVar(&hashes, "hash", nil, "Refresh instances running these revision hashes")
+	refresh.Flags().StringSliceVar(&strategies, "strategy", nil, "Refresh instances of these strategies")
+
+	cmd.AddCommand(list, upload, tag, refresh, a.newStrategyExportCommand(), a.newStrategyImportCommand())
+	return cmd
+}
+
+func (a *app) newStrategyExportCommand() *cobra.Command {
+	var outFile string
+	export := &cobra.Command{
+		Use:   "export -f ARCHIVE",
+		Short: "Download the strategy registry and every revision as a signed archive",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			data, err := cl.send(c.Context(), http.MethodGet, strategyArchivePath, nil, "", nil)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(outFile, data, 0o600); err != nil {
+				return fmt.Errorf("write %s: %w", outFile, err)
+			}
+			fmt.Fprintf(a.stdout, "archive written to %s (%d bytes)\n", outFile, len(data))
+			return nil
+		},
+	}
+	export.Flags().StringVarP(&outFile, "file", "f", "", "Archive file to write (.tar.gz)")
+	_ = export.MarkFlagRequired("file")
+	return export
+}
+
+func (a *app) newStrategyImportCommand() *cobra.Command {
+	var (
+		inFile   string
+		conflict string
+		dryRun   bool
+		actor    string
+		reason   string
+	)
+	importCmd := &cobra.Command{
+		Use:   "import -f ARCHIVE",
+		Short: "Import a strategy archive exported by another gateway",
+		Args:  cobra.NoArgs,
+		RunE: func(c *cobra.Command, _ []string) error {
+			archive, err := os.ReadFile(inFile)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", inFile, err)
+			}
+			cl, err := a.client()
+			if err != nil {
+				return err
+			}
+			query := url.Values{"conflict": []string{conflict}}
+			if dryRun {
+				query.Set("dryRun", "true")
+			}
+			if actor != "" {
+				query.Set("actor", actor)
+			}
+			if reason != "" {
+				query.Set("reason", reason)
+			}
+			data, err := cl.send(c.Context(), http.MethodPost, strategyArchivePath, query, "application/gzip", archive)
+			if err != nil {
+				return err
+			}
+			if a.format() == outputJSON {
+				return writeRawJSON(a.stdout, data)
+			}
+			var resp struct {
+				DryRun bool `json:"dryRun"`
+				Result struct {
+					AddedRevisions   []string        `json:"addedRevisions"`
+					TagChanges       []archiveTagRow `json:"tagChanges"`
+					Conflicts        []archiveTagRow `json:"conflicts"`
+					RemovedRevisions []string        `json:"removedRevisions"`
+				} `json:"result"`
+			}
+			if err := json.Unmarshal(data, &resp); err != nil {
+				return writeRawJSON(a.stdout, data)
+			}
+			verb := "imported"
+			if resp.DryRun {
+				verb = "would import"
+			}
+			fmt.Fprintf(a.stdout, "%s %d revision(s), move %d tag(s), unregister %d revision(s)\n", verb,
+				len(resp.Result.AddedRevisions), len(resp.Result.TagChanges), len(resp.Result.RemovedRevisions))
+			for _, change := range resp.Result.TagChanges {
+				fmt.Fprintf(a.stdout, "tag %s:%s %s -> %s\n", change.Strategy, change.Tag, orDash(shortHash(change.Previous)), shortHash(change.Hash))
+			}
+			for _, kept := range resp.Result.Conflicts {
+				fmt.Fprintf(a.stdout, "conflict %s:%s kept at %s (archive %s)\n", kept.Strategy, kept.Tag, shortHash(kept.Previous), shortHash(kept.Hash))
+			}
+			return nil
+		},
+	}
+	importCmd.Flags().StringVarP(&inFile, "file", "f", "", "Archive file to import (.tar.gz)")
+	importCmd.Flags().StringVar(&conflict, "conflict", "fail", "Tag conflict policy: fail, keep, overwrite or replace")
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report the changes without applying them")
+	importCmd.Flags().StringVar(&actor, "actor", "", "Actor recorded in the revision history")
+	importCmd.Flags().StringVar(&reason, "reason", "", "Reason recorded in the revision history")
+	_ = importCmd.MarkFlagRequired("file")
+	return importCmd
+}
+
+// archiveTagRow is a tag moved or held back by an archive import.
+type archiveTagRow struct {
+	Strategy string `json:"strategy"`
+	Tag      string `json:"tag"`
+	Previous string `json:"previous"`
+	Hash     string `json:"hash"`
+}
+
+// uploadDiagnostic is a lint finding returned with an uploaded or validated module.
+type uploadDiagnostic struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+type instanceView struct {
+	ID                 string   `json:"id"`
+	StrategyIdentifier string   `json:"strategyIdentifier"`
+	Providers          []string `json:"providers"`
+	AggregatedSymbols  []string `json:"aggregatedSymbols"`
+	Running            bool     `json:"running"`
+}
+
+type providerView struct {
+	Name            string `json:"name"`
+	Adapter         string `json:"adapter"`
+	Status          string `json:"status"`
+	Running         bool   `json:"running"`
+	InstrumentCount int    `json:"instrumentCount"`
+	StartupError    string `json:"startupError"`
+}
+
+type positionView struct {
+	Symbol                 string   `json:"symbol"`
+	Position               string   `json:"position"`
+	Notional               string   `json:"notional"`
+	InFlight               int      `json:"inFlight"`
+	PositionUtilization    *float64 `json:"positionUtilization"`
+	NotionalUtilization    *float64 `json:"notionalUtilization"`
+	ConcurrencyUtilization *float64 `json:"concurrencyUtilization"`
+}
+
+type riskView struct {
+	TradingHalted       bool           `json:"tradingHalted"`
+	HaltReason          string         `json:"haltReason"`
+	CircuitBreakerOpen  bool           `json:"circuitBreakerOpen"`
+	BreachCount         int            `json:"breachCount"`
+	MaxRiskBreaches     int            `json:"maxRiskBreaches"`
+	OpenOrders          int            `json:"openOrders"`
+	MaxConcurrentOrders int            `json:"maxConcurrentOrders"`
+	OrdersPerMinute     map[string]int `json:"ordersPerMinute"`
+	Positions           []positionView `json:"positions"`
+}
+
+// dashboardState is one poll of the control API.
+type dashboardState struct {
+	FetchedAt time.Time
+	Instances []instanceView
+	Providers []providerView
+	Risk      riskView
+	Errors    []string
+}
+
+// controlClient polls the gateway control API and issues operator actions.
+type controlClient struct {
+	endpoint string
+	token    string
+	http     *http.Client
+}
+
+func newControlClient(endpoint, token string, timeout time.Duration) *controlClient {
+	return &controlClient{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		token:    token,
+		http: &http.Client{
+			Transport:     nil,
+			CheckRedirect: nil,
+			Jar:           nil,
+			Timeout:       timeout,
+		},
+	}
+}
+
+// fetch loads instances, providers and risk status; failures of individual calls are collected
+// so a partially reachable gateway still renders.
+func (c *controlClient) fetch(ctx context.Context) dashboardState {
+	var state dashboardState
+	state.FetchedAt = time.Now()
+	var instances struct {
+		Instances []instanceView `json:"instances"`
+	}
+	if err := c.call(ctx, http.MethodGet, "/strategy/instances", nil, &instances); err != nil {
+		state.Errors = append(state.Errors, "instances: "+err.Error())
+	}
+	state.Instances = instances.Instances
+	var providers struct {
+		Providers []providerView `json:"providers"`
+	}
+	if err := c.call(ctx, http.MethodGet, "/providers", nil, &providers); err != nil {
+		state.Errors = append(state.Errors, "providers: "+err.Error())
+	}
+	state.Providers = providers.Providers
+	if err := c.call(ctx, http.MethodGet, "/risk/status", nil, &state.Risk); err != nil {
+		state.Errors = append(state.Errors, "risk: "+err.Error())
+	}
+	return state
+}
+
+func (c *controlClient) instanceAction(ctx context.Context, id, action string) error {
+	return c.call(ctx, http.MethodPost, "/strategy/instances/"+url.PathEscape(id)+"/"+action, nil, nil)
+}
+
+func (c *controlClient) setKillSwitch(ctx context.Context, engaged bool, reason string) error {
+	payload := map[string]any{"engaged": engaged, "reason": reason}
+	return c.call(ctx, http.MethodPost, "/risk/kill-switch", payload, nil)
+}
+
+func (c *controlClient) call(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, reader)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var payload struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &payload) == nil && payload.Error != "" {
+			return fmt.Errorf("%d: %s", resp.StatusCode, payload.Error)
+		}
+		return fmt.Errorf("%d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
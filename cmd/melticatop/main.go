@@ -0,0 +1,205 @@
+// Command melticatop is a terminal dashboard for monitoring a running Meltica gateway.
+//
+// It polls the control API for instances, providers and risk state and offers keyboard
+// shortcuts for stopping instances and operating the kill switch.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	defaultEndpoint = "http://localhost:8880"
+	defaultInterval = 2 * time.Second
+	requestTimeout  = 5 * time.Second
+	endpointEnvVar  = "MELTICA_ENDPOINT"
+	tokenEnvVar     = "MELTICA_TOKEN"
+	hideCursor      = "\x1b[?25l"
+	showCursor      = "\x1b[?25h"
+	operatorReason  = "melticatop"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		endpoint = flag.String("endpoint", envOr(endpointEnvVar, defaultEndpoint), "Control API base URL (env "+endpointEnvVar+")")
+		token    = flag.String("token", os.Getenv(tokenEnvVar), "Bearer token for the control API (env "+tokenEnvVar+")")
+		interval = flag.Duration("interval", defaultInterval, "Refresh interval")
+		noColor  = flag.Bool("no-color", false, "Disable ANSI colors")
+	)
+	flag.Parse()
+	if *interval <= 0 {
+		return errors.New("-interval must be positive")
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return errors.New("melticatop requires an interactive terminal")
+	}
+	previous, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("enable raw terminal mode: %w", err)
+	}
+	defer func() {
+		_ = term.Restore(fd, previous)
+		fmt.Fprint(os.Stdout, showCursor+"\r\n")
+	}()
+	fmt.Fprint(os.Stdout, hideCursor)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	client := newControlClient(*endpoint, *token, requestTimeout)
+	dash := &dashboard{
+		client:   client,
+		model:    newModel(client.endpoint, !*noColor && os.Getenv("NO_COLOR") == ""),
+		out:      os.Stdout,
+		interval: *interval,
+	}
+	return dash.loop(ctx, readKeys(ctx, os.Stdin))
+}
+
+// dashboard drives polling, keyboard input and redraws.
+type dashboard struct {
+	client   *controlClient
+	model    *model
+	out      io.Writer
+	interval time.Duration
+}
+
+func (d *dashboard) loop(ctx context.Context, keys <-chan rune) error {
+	states := make(chan dashboardState, 1)
+	results := make(chan string, 1)
+	inflight := false
+	poll := func() {
+		if inflight {
+			return
+		}
+		inflight = true
+		go func() {
+			fetchCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+			defer cancel()
+			states <- d.client.fetch(fetchCtx)
+		}()
+	}
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	poll()
+	d.draw()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			poll()
+		case state := <-states:
+			inflight = false
+			if d.model.message == loadingMessage {
+				d.model.message = ""
+			}
+			d.model.update(state)
+			d.draw()
+		case message := <-results:
+			d.model.message = message
+			d.draw()
+			poll()
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			cmd, quit := d.model.handleKey(key)
+			if quit {
+				return nil
+			}
+			switch cmd.kind {
+			case commandNone:
+			case commandRefresh:
+				poll()
+			default:
+				go func() { results <- d.execute(ctx, cmd) }()
+			}
+			d.draw()
+		}
+	}
+}
+
+// execute performs an operator action and returns the status line to display.
+func (d *dashboard) execute(ctx context.Context, cmd command) string {
+	actionCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	var (
+		err  error
+		done string
+	)
+	switch cmd.kind {
+	case commandStopInstance:
+		err = d.client.instanceAction(actionCtx, cmd.instanceID, "stop")
+		done = "stop requested for " + cmd.instanceID
+	case commandStartInstance:
+		err = d.client.instanceAction(actionCtx, cmd.instanceID, "start")
+		done = "start requested for " + cmd.instanceID
+	case commandEngageKillSwitch:
+		err = d.client.setKillSwitch(actionCtx, true, operatorReason)
+		done = "kill switch engaged"
+	case commandReleaseKillSwitch:
+		err = d.client.setKillSwitch(actionCtx, false, "")
+		done = "kill switch released"
+	case commandNone, commandRefresh:
+		return ""
+	}
+	if err != nil {
+		return "action failed: " + err.Error()
+	}
+	return done
+}
+
+func (d *dashboard) draw() {
+	fmt.Fprint(d.out, ansiClear+d.model.render())
+}
+
+// readKeys forwards single key presses from the raw-mode terminal.
+func readKeys(ctx context.Context, in io.Reader) <-chan rune {
+	keys := make(chan rune)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 16)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				return
+			}
+			for _, b := range buf[:n] {
+				select {
+				case keys <- rune(b):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return keys
+}
+
+func envOr(key, fallback string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
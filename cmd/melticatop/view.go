@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	ansiClear   = "\x1b[H\x1b[2J"
+	ansiBold    = "\x1b[1m"
+	ansiReverse = "\x1b[7m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiReset   = "\x1b[0m"
+)
+
+type commandKind int
+
+const (
+	commandNone commandKind = iota
+	commandStopInstance
+	commandStartInstance
+	commandEngageKillSwitch
+	commandReleaseKillSwitch
+	commandRefresh
+)
+
+// command is an operator action requested from the keyboard.
+type command struct {
+	kind       commandKind
+	instanceID string
+}
+
+// model holds the latest poll plus selection and confirmation state.
+type model struct {
+	endpoint string
+	state    dashboardState
+	selected int
+	confirm  command
+	message  string
+	color    bool
+}
+
+const loadingMessage = "loading…"
+
+func newModel(endpoint string, color bool) *model {
+	m := new(model)
+	m.endpoint = endpoint
+	m.message = loadingMessage
+	m.color = color
+	return m
+}
+
+// update applies a poll result, keeping the selection on the same instance when possible.
+func (m *model) update(state dashboardState) {
+	previous := ""
+	if m.selected >= 0 && m.selected < len(m.state.Instances) {
+		previous = m.state.Instances[m.selected].ID
+	}
+	sort.Slice(state.Instances, func(i, j int) bool { return state.Instances[i].ID < state.Instances[j].ID })
+	sort.Slice(state.Providers, func(i, j int) bool { return state.Providers[i].Name < state.Providers[j].Name })
+	m.state = state
+	m.selected = 0
+	for i, inst := range state.Instances {
+		if inst.ID == previous {
+			m.selected = i
+			break
+		}
+	}
+}
+
+// handleKey maps a key press to a command. Destructive actions require a confirming "y".
+func (m *model) handleKey(key rune) (command, bool) {
+	none := command{kind: commandNone, instanceID: ""}
+	if m.confirm.kind != commandNone {
+		pending := m.confirm
+		m.confirm = none
+		if key == 'y' || key == 'Y' {
+			return pending, false
+		}
+		m.message = "cancelled"
+		return none, false
+	}
+	switch key {
+	case 'q', 'Q', 3: // Ctrl-C arrives as a byte in raw mode
+		return none, true
+	case 'j':
+		if m.selected < len(m.state.Instances)-1 {
+			m.selected++
+		}
+	case 'k':
+		if m.selected > 0 {
+			m.selected--
+		}
+	case 'r', 'R':
+		return command{kind: commandRefresh, instanceID: ""}, false
+	case 's':
+		if id := m.selectedInstance(); id != "" {
+			m.confirm = command{kind: commandStopInstance, instanceID: id}
+			m.message = fmt.Sprintf("stop instance %s? (y/n)", id)
+		}
+	case 'S':
+		if id := m.selectedInstance(); id != "" {
+			return command{kind: commandStartInstance, instanceID: id}, false
+		}
+	case 'K':
+		m.confirm = command{kind: commandEngageKillSwitch, instanceID: ""}
+		m.message = "engage kill switch and halt all trading? (y/n)"
+	case 'U':
+		m.confirm = command{kind: commandReleaseKillSwitch, instanceID: ""}
+		m.message = "release kill switch and resume trading? (y/n)"
+	}
+	return none, false
+}
+
+func (m *model) selectedInstance() string {
+	if m.selected < 0 || m.selected >= len(m.state.Instances) {
+		return ""
+	}
+	return m.state.Instances[m.selected].ID
+}
+
+func (m *model) paint(code, text string) string {
+	if !m.color {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// render draws the dashboard. Lines end with CRLF because the terminal runs in raw mode; colors
+// are kept out of table cells so tabwriter alignment is not skewed by escape sequences.
+func (m *model) render() string {
+	var b strings.Builder
+	risk := m.state.Risk
+	trading := m.paint(ansiGreen, "ACTIVE")
+	switch {
+	case risk.CircuitBreakerOpen:
+		trading = m.paint(ansiRed, "CIRCUIT OPEN") + " (" + risk.HaltReason + ")"
+	case risk.TradingHalted:
+		trading = m.paint(ansiRed, "HALTED") + " (" + risk.HaltReason + ")"
+	}
+	fmt.Fprintf(&b, "%s  %s  %s\n", m.paint(ansiBold, "melticatop"), m.endpoint, m.state.FetchedAt.Format("15:04:05"))
+	fmt.Fprintf(&b, "trading: %s  breaches: %s  open orders: %s\n\n",
+		trading, ratio(risk.BreachCount, risk.MaxRiskBreaches), ratio(risk.OpenOrders, risk.MaxConcurrentOrders))
+
+	b.WriteString(m.paint(ansiBold, "PROVIDERS") + "\n")
+	writeTable(&b, []string{"NAME", "ADAPTER", "STATUS", "INSTRUMENTS", "ERROR"}, func(add func(...string)) {
+		for _, p := range m.state.Providers {
+			status := p.Status
+			if status == "" && p.Running {
+				status = "running"
+			} else if status == "" {
+				status = "stopped"
+			}
+			add(p.Name, p.Adapter, status, fmt.Sprint(p.InstrumentCount), dash(p.StartupError))
+		}
+	})
+
+	b.WriteString("\n" + m.paint(ansiBold, "INSTANCES") + "\n")
+	writeTable(&b, []string{"", "ID", "STRATEGY", "PROVIDERS", "SYMBOLS", "STATE", "ORD/MIN"}, func(add func(...string)) {
+		for i, inst := range m.state.Instances {
+			cursor := " "
+			if i == m.selected {
+				cursor = ">"
+			}
+			state := "stopped"
+			if inst.Running {
+				state = "running"
+			}
+			add(cursor, inst.ID, inst.StrategyIdentifier, strings.Join(inst.Providers, ","),
+				strings.Join(inst.AggregatedSymbols, ","), state, fmt.Sprint(risk.OrdersPerMinute[inst.ID]))
+		}
+	})
+
+	b.WriteString("\n" + m.paint(ansiBold, "POSITIONS & RISK UTILIZATION") + "\n")
+	writeTable(&b, []string{"SYMBOL", "POSITION", "NOTIONAL", "POS%", "NOTIONAL%", "IN-FLIGHT"}, func(add func(...string)) {
+		for _, p := range risk.Positions {
+			add(p.Symbol, p.Position, p.Notional, percent(p.PositionUtilization), percent(p.NotionalUtilization),
+				fmt.Sprintf("%d %s", p.InFlight, percent(p.ConcurrencyUtilization)))
+		}
+	})
+
+	b.WriteString("\n")
+	for _, err := range m.state.Errors {
+		b.WriteString(m.paint(ansiRed, "error: "+err) + "\n")
+	}
+	if m.message != "" {
+		b.WriteString(m.paint(ansiReverse, m.message) + "\n")
+	}
+	b.WriteString("j/k select  s stop  S start  K kill switch  U release  r refresh  q quit\n")
+	return strings.ReplaceAll(b.String(), "\n", "\r\n")
+}
+
+func writeTable(b *strings.Builder, headers []string, rows func(add func(...string))) {
+	tw := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	empty := true
+	rows(func(cols ...string) {
+		empty = false
+		fmt.Fprintln(tw, strings.Join(cols, "\t"))
+	})
+	if empty {
+		fmt.Fprintln(tw, "(none)")
+	}
+	_ = tw.Flush()
+}
+
+// percent formats a utilization fraction; limits at or above 100% are flagged with "!".
+func percent(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	text := fmt.Sprintf("%.0f%%", *value*100)
+	if *value >= 1 {
+		text += "!"
+	}
+	return text
+}
+
+func ratio(value, limit int) string {
+	if limit <= 0 {
+		return fmt.Sprint(value)
+	}
+	return fmt.Sprintf("%d/%d", value, limit)
+}
+
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleKeyRequiresConfirmationForStop(t *testing.T) {
+	m := newModel("http://gw", false)
+	m.update(dashboardState{Instances: []instanceView{{ID: "b"}, {ID: "a"}}})
+
+	if cmd, _ := m.handleKey('j'); cmd.kind != commandNone {
+		t.Fatalf("navigation should not issue commands, got %+v", cmd)
+	}
+	if cmd, _ := m.handleKey('s'); cmd.kind != commandNone {
+		t.Fatalf("stop must wait for confirmation, got %+v", cmd)
+	}
+	cmd, quit := m.handleKey('y')
+	if quit || cmd.kind != commandStopInstance || cmd.instanceID != "b" {
+		t.Fatalf("expected confirmed stop of b, got %+v quit=%t", cmd, quit)
+	}
+
+	m.handleKey('K')
+	if cmd, _ := m.handleKey('n'); cmd.kind != commandNone || m.message != "cancelled" {
+		t.Fatalf("expected kill switch cancelled, got %+v (%q)", cmd, m.message)
+	}
+	if _, quit := m.handleKey('q'); !quit {
+		t.Fatal("expected q to quit")
+	}
+}
+
+func TestUpdateKeepsSelectionOnSameInstance(t *testing.T) {
+	m := newModel("http://gw", false)
+	m.update(dashboardState{Instances: []instanceView{{ID: "a"}, {ID: "c"}}})
+	m.handleKey('j')
+	m.update(dashboardState{Instances: []instanceView{{ID: "c"}, {ID: "b"}, {ID: "a"}}})
+	if got := m.selectedInstance(); got != "c" {
+		t.Fatalf("expected selection to follow instance c, got %q", got)
+	}
+}
+
+func TestRenderShowsHaltAndUtilization(t *testing.T) {
+	half := 0.5
+	over := 1.2
+	m := newModel("http://gw", false)
+	m.update(dashboardState{
+		FetchedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Instances: []instanceView{{ID: "grid", StrategyIdentifier: "grid", Running: true}},
+		Risk: riskView{
+			TradingHalted:   true,
+			HaltReason:      "operator halt",
+			OrdersPerMinute: map[string]int{"grid": 7},
+			Positions: []positionView{{
+				Symbol: "BTC-USDT", Position: "1", Notional: "100",
+				PositionUtilization: &half, NotionalUtilization: &over,
+			}},
+		},
+	})
+	out := m.render()
+	for _, want := range []string{"HALTED (operator halt)", "> ", "grid", "running", "7", "BTC-USDT", "50%", "120%!"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("render missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Fatal("expected CRLF line endings for raw terminal mode")
+	}
+}
+
+func TestFetchCollectsPartialFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/strategy/instances":
+			_, _ = io.WriteString(w, `{"instances":[{"id":"grid","running":true}]}`)
+		case "/providers":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = io.WriteString(w, `{"status":"error","error":"provider manager unavailable"}`)
+		case "/risk/status":
+			_, _ = io.WriteString(w, `{"tradingHalted":false,"ordersPerMinute":{"grid":3},"positions":[]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	state := newControlClient(srv.URL, "", time.Second).fetch(context.Background())
+	if len(state.Instances) != 1 || state.Risk.OrdersPerMinute["grid"] != 3 {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+	if len(state.Errors) != 1 || !strings.Contains(state.Errors[0], "provider manager unavailable") {
+		t.Fatalf("expected provider error, got %v", state.Errors)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/coachpo/meltica/internal/infra/persistence/migrations"
+	"github.com/coachpo/meltica/internal/infra/redact"
 )
 
 const (
@@ -48,7 +49,7 @@ func run() error {
 
 	var logger *log.Logger
 	if !*quiet {
-		logger = log.New(os.Stdout, "meltica-migrate ", log.LstdFlags)
+		logger = log.New(redact.Stdout, "meltica-migrate ", log.LstdFlags)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS events_outbox_type_id_idx;
+
+DROP TABLE IF EXISTS event_subscription_offsets;
@@ -0,0 +1,8 @@
+CREATE TABLE event_subscription_offsets (
+    subscriber TEXT PRIMARY KEY,
+    last_event_id BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX events_outbox_type_id_idx
+    ON events_outbox (event_type, id);
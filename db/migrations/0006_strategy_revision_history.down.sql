@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS strategy_revision_history_strategy_idx;
+
+DROP TABLE IF EXISTS strategy_revision_history;
@@ -0,0 +1,15 @@
+CREATE TABLE strategy_revision_history (
+    id BIGSERIAL PRIMARY KEY,
+    strategy TEXT NOT NULL,
+    action TEXT NOT NULL,
+    hash TEXT NOT NULL DEFAULT '',
+    tag TEXT NOT NULL DEFAULT '',
+    previous_hash TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX strategy_revision_history_strategy_idx
+    ON strategy_revision_history (strategy, id DESC);
@@ -0,0 +1,17 @@
+CREATE TABLE calendar_entries (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    notify_at TIMESTAMPTZ,
+    action JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status TEXT NOT NULL,
+    notified_at TIMESTAMPTZ,
+    applied_at TIMESTAMPTZ,
+    error TEXT NOT NULL DEFAULT '',
+    audit JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX calendar_entries_scheduled_at_idx
+    ON calendar_entries (scheduled_at);
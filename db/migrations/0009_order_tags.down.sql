@@ -0,0 +1 @@
+DROP INDEX IF EXISTS orders_metadata_tags_idx;
@@ -0,0 +1,2 @@
+CREATE INDEX orders_metadata_tags_idx
+    ON orders USING GIN ((metadata -> 'tags'));
@@ -0,0 +1,6 @@
+CREATE TABLE feature_flags (
+    name TEXT PRIMARY KEY,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
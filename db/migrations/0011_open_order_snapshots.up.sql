@@ -0,0 +1,5 @@
+CREATE TABLE open_order_snapshots (
+    provider TEXT PRIMARY KEY,
+    taken_at TIMESTAMPTZ NOT NULL,
+    orders JSONB NOT NULL DEFAULT '[]'::jsonb
+);
@@ -0,0 +1,3 @@
+ALTER TABLE events_outbox
+    DROP COLUMN IF EXISTS payload_compressed,
+    DROP COLUMN IF EXISTS payload_codec;
@@ -0,0 +1,3 @@
+ALTER TABLE events_outbox
+    ADD COLUMN payload_codec TEXT NOT NULL DEFAULT 'identity',
+    ADD COLUMN payload_compressed BYTEA;
@@ -0,0 +1,14 @@
+CREATE TABLE strategy_logs (
+    id BIGSERIAL PRIMARY KEY,
+    instance_id TEXT NOT NULL,
+    strategy TEXT NOT NULL DEFAULT '',
+    hash TEXT NOT NULL DEFAULT '',
+    level TEXT NOT NULL,
+    severity SMALLINT NOT NULL,
+    source TEXT NOT NULL,
+    message TEXT NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX strategy_logs_instance_idx ON strategy_logs (instance_id, recorded_at);
+CREATE INDEX strategy_logs_recorded_at_idx ON strategy_logs (recorded_at);
@@ -0,0 +1,8 @@
+CREATE TABLE strategy_reports (
+    id BIGSERIAL PRIMARY KEY,
+    generated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    summary TEXT NOT NULL DEFAULT '',
+    report JSONB NOT NULL
+);
+
+CREATE INDEX strategy_reports_generated_at_idx ON strategy_reports (generated_at);
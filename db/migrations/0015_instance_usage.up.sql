@@ -0,0 +1,11 @@
+CREATE TABLE instance_usage (
+    day DATE NOT NULL,
+    instance_id TEXT NOT NULL,
+    strategy TEXT NOT NULL DEFAULT '',
+    labels JSONB NOT NULL DEFAULT '{}'::jsonb,
+    events BIGINT NOT NULL DEFAULT 0,
+    orders BIGINT NOT NULL DEFAULT 0,
+    handler_us BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (day, instance_id)
+);
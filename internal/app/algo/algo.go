@@ -0,0 +1,619 @@
+// Package algo runs execution algorithms for strategy instances: strategies hand over a parent
+// order with algo parameters and the engine slices it into child orders over time, tracks their
+// fills and reports consolidated progress, so strategy code stays simple.
+package algo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// Kind selects the execution algorithm of a parent order.
+type Kind string
+
+const (
+	// KindTWAP spreads equal child orders evenly across a time window.
+	KindTWAP Kind = "twap"
+	// KindIceberg keeps a single visible child resting and replenishes it as it fills.
+	KindIceberg Kind = "iceberg"
+)
+
+// Status reports the lifecycle state of a parent order.
+type Status string
+
+const (
+	// StatusRunning means children are still being scheduled or are resting on the venue.
+	StatusRunning Status = "running"
+	// StatusCompleted means every child was submitted and reached a terminal state.
+	StatusCompleted Status = "completed"
+	// StatusCancelled means the parent was cancelled by the strategy or the instance stopped.
+	StatusCancelled Status = "cancelled"
+	// StatusFailed means a child could not be submitted or was rejected.
+	StatusFailed Status = "failed"
+)
+
+const (
+	defaultTWAPSlices = 10
+	quantityScale     = 8
+	cancelTimeout     = 5 * time.Second
+	// maxFinishedParents bounds how many completed, cancelled or failed parents stay queryable.
+	maxFinishedParents = 256
+)
+
+var (
+	// ErrUnknownOrder is returned for parent order IDs the engine does not track.
+	ErrUnknownOrder = errors.New("algo: unknown parent order")
+	// ErrEngineStopped is returned when submitting to an engine whose instance is not running.
+	ErrEngineStopped = errors.New("algo: engine not running")
+)
+
+// ParentOrder describes the order a strategy hands to the engine.
+type ParentOrder struct {
+	// ID is assigned by the engine when the order is submitted.
+	ID       string
+	Kind     Kind
+	Provider string
+	Symbol   string
+	Side     schema.TradeSide
+	Quantity string
+	// Price limits every child order. Nil sends market children, which only TWAP supports.
+	Price *string
+	// Duration is the TWAP window; children are spread evenly across it.
+	Duration time.Duration
+	// SliceQuantity caps each TWAP child. When empty the quantity is split into Slices children.
+	SliceQuantity string
+	// Slices is the TWAP child count used when SliceQuantity is empty; defaults to 10.
+	Slices int
+	// DisplayQuantity is the visible size of each iceberg child.
+	DisplayQuantity string
+	// Tags and Metadata are copied onto every child order for attribution.
+	Tags     []string
+	Metadata map[string]string
+}
+
+// Progress is the consolidated view of a parent order and its children. The arrival price,
+// interval VWAP and slippage fields benchmark the average fill price and are filled in by the
+// engine owner, which observes market trades.
+type Progress struct {
+	ID                   string           `json:"id"`
+	Instance             string           `json:"instance"`
+	Kind                 Kind             `json:"kind"`
+	Status               Status           `json:"status"`
+	Provider             string           `json:"provider"`
+	Symbol               string           `json:"symbol"`
+	Side                 schema.TradeSide `json:"side"`
+	Quantity             string           `json:"quantity"`
+	SubmittedQuantity    string           `json:"submittedQuantity"`
+	FilledQuantity       string           `json:"filledQuantity"`
+	AvgFillPrice         string           `json:"avgFillPrice,omitempty"`
+	ArrivalPrice         string           `json:"arrivalPrice,omitempty"`
+	IntervalVWAP         string           `json:"intervalVwap,omitempty"`
+	SlippageVsArrivalBps string           `json:"slippageVsArrivalBps,omitempty"`
+	SlippageVsVWAPBps    string           `json:"slippageVsVwapBps,omitempty"`
+	ChildOrders          int              `json:"childOrders"`
+	OpenChildOrders      int              `json:"openChildOrders"`
+	Error                string           `json:"error,omitempty"`
+	StartedAt            time.Time        `json:"startedAt"`
+	UpdatedAt            time.Time        `json:"updatedAt"`
+}
+
+// Venue places and cancels child orders on behalf of the engine.
+type Venue interface {
+	// SubmitChild places a child order under clientOrderID. It reports false when the order was
+	// accepted but will never produce execution reports (for example in dry-run mode).
+	SubmitChild(ctx context.Context, parent ParentOrder, clientOrderID, quantity string) (bool, error)
+	// CancelChild cancels a resting child order.
+	CancelChild(ctx context.Context, parent ParentOrder, clientOrderID string) error
+}
+
+// Option customises an Engine.
+type Option func(*Engine)
+
+// WithProgressHandler receives a progress snapshot whenever a parent order changes.
+func WithProgressHandler(fn func(Progress)) Option {
+	return func(e *Engine) {
+		e.onProgress = fn
+	}
+}
+
+// WithClock overrides the engine clock.
+func WithClock(clock func() time.Time) Option {
+	return func(e *Engine) {
+		if clock != nil {
+			e.clock = clock
+		}
+	}
+}
+
+// WithLogger overrides the engine logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(e *Engine) {
+		if logger != nil {
+			e.logger = logger
+		}
+	}
+}
+
+// Engine runs the parent orders of one strategy instance.
+type Engine struct {
+	owner      string
+	venue      Venue
+	onProgress func(Progress)
+	clock      func() time.Time
+	logger     *log.Logger
+
+	mu       sync.Mutex
+	ctx      context.Context
+	parents  map[string]*parentState
+	children map[string]*childState
+	seq      atomic.Uint64
+	runners  sync.WaitGroup
+}
+
+type parentState struct {
+	id       string
+	order    ParentOrder
+	plan     plan
+	cancel   context.CancelFunc
+	status   Status
+	err      string
+	slicing  bool
+	failure  error
+	children []*childState
+	open     int
+
+	submitted decimal.Decimal
+	started   time.Time
+	updated   time.Time
+}
+
+type childState struct {
+	id       string
+	parentID string
+	quantity decimal.Decimal
+	filled   decimal.Decimal
+	avgPrice decimal.Decimal
+	state    schema.ExecReportState
+	done     chan struct{}
+}
+
+// NewEngine constructs an engine whose child client order IDs are prefixed with owner.
+func NewEngine(owner string, venue Venue, opts ...Option) *Engine {
+	e := &Engine{
+		owner:      owner,
+		venue:      venue,
+		onProgress: nil,
+		clock:      time.Now,
+		logger:     log.New(redact.Stdout, "algo ", log.LstdFlags|log.Lmicroseconds),
+		mu:         sync.Mutex{},
+		ctx:        nil,
+		parents:    make(map[string]*parentState),
+		children:   make(map[string]*childState),
+		seq:        atomic.Uint64{},
+		runners:    sync.WaitGroup{},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(e)
+		}
+	}
+	return e
+}
+
+// Start binds the engine to the instance lifecycle. Parents still running when ctx ends are
+// cancelled together with their resting children.
+func (e *Engine) Start(ctx context.Context) {
+	e.mu.Lock()
+	e.ctx = ctx
+	e.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		e.stop()
+	}()
+}
+
+// Submit validates the parent order, starts slicing it and returns its ID.
+func (e *Engine) Submit(order ParentOrder) (string, error) {
+	order.Provider = strings.TrimSpace(order.Provider)
+	order.Symbol = strings.TrimSpace(order.Symbol)
+	order.Kind = Kind(strings.ToLower(strings.TrimSpace(string(order.Kind))))
+	p, err := newPlan(order)
+	if err != nil {
+		return "", err
+	}
+
+	e.mu.Lock()
+	if e.ctx == nil || e.ctx.Err() != nil {
+		e.mu.Unlock()
+		return "", ErrEngineStopped
+	}
+	now := e.clock().UTC()
+	id := fmt.Sprintf("%s-a%d", e.owner, e.seq.Add(1))
+	order.ID = id
+	ctx, cancel := context.WithCancel(e.ctx)
+	parent := &parentState{
+		id:        id,
+		order:     order,
+		plan:      p,
+		cancel:    cancel,
+		status:    StatusRunning,
+		err:       "",
+		slicing:   true,
+		failure:   nil,
+		children:  nil,
+		open:      0,
+		submitted: decimal.Zero,
+		started:   now,
+		updated:   now,
+	}
+	e.pruneLocked()
+	e.parents[id] = parent
+	snapshot := e.snapshotLocked(parent)
+	e.runners.Add(1)
+	e.mu.Unlock()
+
+	e.logger.Printf("parent=%s kind=%s provider=%s symbol=%s side=%s qty=%s started", id, order.Kind, order.Provider, order.Symbol, order.Side, order.Quantity)
+	e.publish(snapshot)
+	go func() {
+		defer e.runners.Done()
+		e.run(ctx, parent)
+	}()
+	return id, nil
+}
+
+// Cancel stops slicing the parent order and cancels its resting children.
+func (e *Engine) Cancel(id string) error {
+	e.mu.Lock()
+	parent, ok := e.parents[strings.TrimSpace(id)]
+	if !ok {
+		e.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownOrder, id)
+	}
+	if parent.status != StatusRunning {
+		e.mu.Unlock()
+		return nil
+	}
+	if parent.slicing {
+		// The runner observes the cancelled context and finalises the parent.
+		parent.cancel()
+		e.mu.Unlock()
+		return nil
+	}
+	open := e.finalizeLocked(parent, StatusCancelled, "")
+	snapshot := e.snapshotLocked(parent)
+	e.mu.Unlock()
+	e.cancelChildren(parent, open)
+	e.publish(snapshot)
+	return nil
+}
+
+// Progress returns the current view of a parent order.
+func (e *Engine) Progress(id string) (Progress, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	parent, ok := e.parents[strings.TrimSpace(id)]
+	if !ok {
+		var empty Progress
+		return empty, false
+	}
+	return e.snapshotLocked(parent), true
+}
+
+// List returns every parent order ordered by start time.
+func (e *Engine) List() []Progress {
+	e.mu.Lock()
+	out := make([]Progress, 0, len(e.parents))
+	for _, parent := range e.parents {
+		out = append(out, e.snapshotLocked(parent))
+	}
+	e.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].StartedAt.Equal(out[j].StartedAt) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].StartedAt.Before(out[j].StartedAt)
+	})
+	return out
+}
+
+// OnExecReport folds a child execution report into its parent. It reports whether the report
+// belonged to a child order managed by the engine.
+func (e *Engine) OnExecReport(payload schema.ExecReportPayload) bool {
+	e.mu.Lock()
+	child, ok := e.children[payload.ClientOrderID]
+	if !ok {
+		e.mu.Unlock()
+		return false
+	}
+	parent := e.parents[child.parentID]
+	if filled, ok := parseDecimal(payload.FilledQuantity); ok && filled.GreaterThanOrEqual(child.filled) {
+		child.filled = filled
+	}
+	if avg, ok := parseDecimal(payload.AvgFillPrice); ok && avg.IsPositive() {
+		child.avgPrice = avg
+	}
+	child.state = payload.State
+	parent.updated = e.clock().UTC()
+
+	var open []*childState
+	if terminalState(payload.State) {
+		delete(e.children, child.id)
+		parent.open--
+		close(child.done)
+		if payload.State == schema.ExecReportStateREJECTED && parent.status == StatusRunning {
+			reason := "rejected"
+			if payload.RejectReason != nil && strings.TrimSpace(*payload.RejectReason) != "" {
+				reason = strings.TrimSpace(*payload.RejectReason)
+			}
+			parent.failure = fmt.Errorf("child %s rejected: %s", child.id, reason)
+			if parent.slicing {
+				parent.cancel()
+			} else {
+				open = e.finalizeLocked(parent, StatusFailed, parent.failure.Error())
+			}
+		} else if !parent.slicing && parent.open == 0 && parent.status == StatusRunning {
+			e.finalizeLocked(parent, StatusCompleted, "")
+		}
+	}
+	snapshot := e.snapshotLocked(parent)
+	e.mu.Unlock()
+
+	e.cancelChildren(parent, open)
+	e.publish(snapshot)
+	return true
+}
+
+func (e *Engine) run(ctx context.Context, parent *parentState) {
+	var err error
+	switch parent.order.Kind {
+	case KindTWAP:
+		err = e.runTWAP(ctx, parent)
+	case KindIceberg:
+		err = e.runIceberg(ctx, parent)
+	default:
+		err = fmt.Errorf("unsupported algo kind %q", parent.order.Kind)
+	}
+
+	e.mu.Lock()
+	parent.slicing = false
+	var open []*childState
+	switch {
+	case parent.status != StatusRunning:
+	case parent.failure != nil:
+		open = e.finalizeLocked(parent, StatusFailed, parent.failure.Error())
+	case errors.Is(err, context.Canceled):
+		open = e.finalizeLocked(parent, StatusCancelled, "")
+	case err != nil:
+		open = e.finalizeLocked(parent, StatusFailed, err.Error())
+	case parent.open == 0:
+		e.finalizeLocked(parent, StatusCompleted, "")
+	}
+	snapshot := e.snapshotLocked(parent)
+	e.mu.Unlock()
+
+	e.cancelChildren(parent, open)
+	e.publish(snapshot)
+}
+
+// placeChild registers and submits the next child, returning nil state for untracked children.
+func (e *Engine) placeChild(ctx context.Context, parent *parentState, quantity decimal.Decimal) (*childState, error) {
+	e.mu.Lock()
+	child := &childState{
+		id:       fmt.Sprintf("%s-%d", parent.id, len(parent.children)+1),
+		parentID: parent.id,
+		quantity: quantity,
+		filled:   decimal.Zero,
+		avgPrice: decimal.Zero,
+		state:    "",
+		done:     make(chan struct{}),
+	}
+	// Register before submitting: venues may acknowledge before SubmitChild returns.
+	e.children[child.id] = child
+	parent.children = append(parent.children, child)
+	parent.open++
+	parent.submitted = parent.submitted.Add(quantity)
+	e.mu.Unlock()
+
+	tracked, err := e.venue.SubmitChild(ctx, parent.order, child.id, quantity.String())
+
+	e.mu.Lock()
+	if err != nil || !tracked {
+		if _, pending := e.children[child.id]; pending {
+			delete(e.children, child.id)
+			parent.open--
+			close(child.done)
+		}
+	}
+	if err != nil {
+		parent.children = parent.children[:len(parent.children)-1]
+		parent.submitted = parent.submitted.Sub(quantity)
+	}
+	parent.updated = e.clock().UTC()
+	snapshot := e.snapshotLocked(parent)
+	e.mu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("submit child %s: %w", child.id, err)
+	}
+	e.publish(snapshot)
+	if !tracked {
+		return nil, nil
+	}
+	return child, nil
+}
+
+// remaining returns the quantity not yet handed to children, capped at limit.
+func (e *Engine) remaining(parent *parentState, limit decimal.Decimal) decimal.Decimal {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	left := parent.plan.quantity.Sub(parent.submitted)
+	if left.GreaterThan(limit) {
+		return limit
+	}
+	return left
+}
+
+// finalizeLocked moves a running parent to a terminal status and returns children to cancel.
+func (e *Engine) finalizeLocked(parent *parentState, status Status, reason string) []*childState {
+	if parent.status != StatusRunning {
+		return nil
+	}
+	parent.status = status
+	parent.err = reason
+	parent.updated = e.clock().UTC()
+	parent.cancel()
+	e.logger.Printf("parent=%s status=%s filled=%s/%s %s", parent.id, status, e.filledLocked(parent), parent.plan.quantity, reason)
+	if status == StatusCompleted {
+		return nil
+	}
+	var open []*childState
+	for _, child := range parent.children {
+		if _, pending := e.children[child.id]; pending {
+			open = append(open, child)
+		}
+	}
+	return open
+}
+
+func (e *Engine) cancelChildren(parent *parentState, children []*childState) {
+	for _, child := range children {
+		ctx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+		if err := e.venue.CancelChild(ctx, parent.order, child.id); err != nil {
+			e.logger.Printf("parent=%s child=%s cancel failed: %v", parent.id, child.id, err)
+		}
+		cancel()
+	}
+}
+
+func (e *Engine) stop() {
+	e.mu.Lock()
+	for _, parent := range e.parents {
+		parent.cancel()
+	}
+	e.mu.Unlock()
+	e.runners.Wait()
+
+	// Parents whose children were all submitted are not owned by a runner any more.
+	e.mu.Lock()
+	type pending struct {
+		parent   *parentState
+		children []*childState
+		snapshot Progress
+	}
+	var stopped []pending
+	for _, parent := range e.parents {
+		if parent.status != StatusRunning {
+			continue
+		}
+		open := e.finalizeLocked(parent, StatusCancelled, "instance stopped")
+		stopped = append(stopped, pending{parent: parent, children: open, snapshot: e.snapshotLocked(parent)})
+	}
+	e.mu.Unlock()
+	for _, entry := range stopped {
+		e.cancelChildren(entry.parent, entry.children)
+		e.publish(entry.snapshot)
+	}
+}
+
+// pruneLocked drops the oldest finished parents beyond maxFinishedParents.
+func (e *Engine) pruneLocked() {
+	finished := make([]*parentState, 0, len(e.parents))
+	for _, parent := range e.parents {
+		if parent.status != StatusRunning {
+			finished = append(finished, parent)
+		}
+	}
+	if len(finished) <= maxFinishedParents {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].updated.Before(finished[j].updated)
+	})
+	for _, parent := range finished[:len(finished)-maxFinishedParents] {
+		delete(e.parents, parent.id)
+	}
+}
+
+func (e *Engine) publish(snapshot Progress) {
+	if e.onProgress != nil {
+		e.onProgress(snapshot)
+	}
+}
+
+func (e *Engine) filledLocked(parent *parentState) decimal.Decimal {
+	filled := decimal.Zero
+	for _, child := range parent.children {
+		filled = filled.Add(child.filled)
+	}
+	return filled
+}
+
+func (e *Engine) snapshotLocked(parent *parentState) Progress {
+	filled := decimal.Zero
+	notional := decimal.Zero
+	for _, child := range parent.children {
+		filled = filled.Add(child.filled)
+		notional = notional.Add(child.filled.Mul(child.avgPrice))
+	}
+	avg := ""
+	if filled.IsPositive() && notional.IsPositive() {
+		avg = notional.Div(filled).Round(quantityScale).String()
+	}
+	return Progress{
+		ID:                   parent.id,
+		Instance:             e.owner,
+		Kind:                 parent.order.Kind,
+		Status:               parent.status,
+		Provider:             parent.order.Provider,
+		Symbol:               parent.order.Symbol,
+		Side:                 parent.order.Side,
+		Quantity:             parent.plan.quantity.String(),
+		SubmittedQuantity:    parent.submitted.String(),
+		FilledQuantity:       filled.String(),
+		AvgFillPrice:         avg,
+		ArrivalPrice:         "",
+		IntervalVWAP:         "",
+		SlippageVsArrivalBps: "",
+		SlippageVsVWAPBps:    "",
+		ChildOrders:          len(parent.children),
+		OpenChildOrders:      parent.open,
+		Error:                parent.err,
+		StartedAt:            parent.started,
+		UpdatedAt:            parent.updated,
+	}
+}
+
+func terminalState(state schema.ExecReportState) bool {
+	switch state {
+	case schema.ExecReportStateFILLED, schema.ExecReportStateCANCELLED, schema.ExecReportStateREJECTED, schema.ExecReportStateEXPIRED:
+		return true
+	case schema.ExecReportStateACK, schema.ExecReportStatePARTIAL:
+		return false
+	default:
+		return false
+	}
+}
+
+func parseDecimal(raw string) (decimal.Decimal, bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return decimal.Zero, false
+	}
+	value, err := decimal.NewFromString(trimmed)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return value, true
+}
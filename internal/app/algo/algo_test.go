@@ -0,0 +1,274 @@
+package algo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+type fakeVenue struct {
+	mu        sync.Mutex
+	submitted []fakeChild
+	cancelled []string
+	untracked bool
+	err       error
+	notify    chan fakeChild
+}
+
+type fakeChild struct {
+	id       string
+	quantity string
+}
+
+func newFakeVenue() *fakeVenue {
+	return &fakeVenue{notify: make(chan fakeChild, 64)}
+}
+
+func (v *fakeVenue) SubmitChild(_ context.Context, _ ParentOrder, clientOrderID, quantity string) (bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.err != nil {
+		return false, v.err
+	}
+	child := fakeChild{id: clientOrderID, quantity: quantity}
+	v.submitted = append(v.submitted, child)
+	v.notify <- child
+	return !v.untracked, nil
+}
+
+func (v *fakeVenue) CancelChild(_ context.Context, _ ParentOrder, clientOrderID string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.cancelled = append(v.cancelled, clientOrderID)
+	return nil
+}
+
+func (v *fakeVenue) next(t *testing.T) fakeChild {
+	t.Helper()
+	select {
+	case child := <-v.notify:
+		return child
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for child order")
+	}
+	return fakeChild{}
+}
+
+func startEngine(t *testing.T, venue Venue, opts ...Option) *Engine {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	engine := NewEngine("inst", venue, opts...)
+	engine.Start(ctx)
+	return engine
+}
+
+func waitStatus(t *testing.T, engine *Engine, id string, want Status) Progress {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if progress, ok := engine.Progress(id); ok && progress.Status == want {
+			return progress
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	progress, _ := engine.Progress(id)
+	t.Fatalf("parent %s status %s, want %s", id, progress.Status, want)
+	return progress
+}
+
+func fill(engine *Engine, id, qty, price string) {
+	engine.OnExecReport(schema.ExecReportPayload{
+		ClientOrderID:  id,
+		State:          schema.ExecReportStateFILLED,
+		FilledQuantity: qty,
+		AvgFillPrice:   price,
+	})
+}
+
+func TestPlanValidation(t *testing.T) {
+	price := "100"
+	cases := map[string]ParentOrder{
+		"missing provider":  {Kind: KindTWAP, Side: schema.TradeSideBuy, Quantity: "1", Duration: time.Second},
+		"bad quantity":      {Kind: KindTWAP, Provider: "p", Side: schema.TradeSideBuy, Quantity: "0", Duration: time.Second},
+		"twap no duration":  {Kind: KindTWAP, Provider: "p", Side: schema.TradeSideBuy, Quantity: "1"},
+		"iceberg no price":  {Kind: KindIceberg, Provider: "p", Side: schema.TradeSideBuy, Quantity: "1", DisplayQuantity: "0.1"},
+		"iceberg no slice":  {Kind: KindIceberg, Provider: "p", Side: schema.TradeSideBuy, Quantity: "1", Price: &price},
+		"unknown algorithm": {Kind: "vwap", Provider: "p", Side: schema.TradeSideBuy, Quantity: "1"},
+	}
+	for name, order := range cases {
+		if _, err := newPlan(order); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+	p, err := newPlan(ParentOrder{Kind: KindTWAP, Provider: "p", Side: schema.TradeSideSell, Quantity: "1", SliceQuantity: "0.3", Duration: time.Second})
+	if err != nil {
+		t.Fatalf("newPlan: %v", err)
+	}
+	if p.interval != 250*time.Millisecond || !p.child.Equal(p.child.Round(1)) {
+		t.Fatalf("unexpected twap plan %+v", p)
+	}
+}
+
+func TestTWAPSlicesAndCompletes(t *testing.T) {
+	venue := newFakeVenue()
+	var updates []Progress
+	var mu sync.Mutex
+	engine := startEngine(t, venue, WithProgressHandler(func(p Progress) {
+		mu.Lock()
+		updates = append(updates, p)
+		mu.Unlock()
+	}))
+
+	id, err := engine.Submit(ParentOrder{
+		Kind:          KindTWAP,
+		Provider:      "binance",
+		Symbol:        "BTC-USDT",
+		Side:          schema.TradeSideBuy,
+		Quantity:      "1",
+		SliceQuantity: "0.4",
+		Duration:      30 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	var quantities []string
+	for i := 0; i < 3; i++ {
+		child := venue.next(t)
+		quantities = append(quantities, child.quantity)
+		fill(engine, child.id, child.quantity, "100")
+	}
+	if quantities[0] != "0.4" || quantities[1] != "0.4" || quantities[2] != "0.2" {
+		t.Fatalf("unexpected slices %v", quantities)
+	}
+	progress := waitStatus(t, engine, id, StatusCompleted)
+	if progress.FilledQuantity != "1" || progress.AvgFillPrice != "100" || progress.ChildOrders != 3 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(updates) == 0 || updates[len(updates)-1].Status != StatusCompleted {
+		t.Fatalf("expected completed progress update, got %+v", updates)
+	}
+}
+
+func TestIcebergReplenishesAfterFill(t *testing.T) {
+	venue := newFakeVenue()
+	engine := startEngine(t, venue)
+	price := "50"
+	id, err := engine.Submit(ParentOrder{
+		Kind:            KindIceberg,
+		Provider:        "binance",
+		Side:            schema.TradeSideSell,
+		Quantity:        "2.5",
+		Price:           &price,
+		DisplayQuantity: "1",
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	first := venue.next(t)
+	select {
+	case extra := <-venue.notify:
+		t.Fatalf("iceberg posted %s before the visible child filled", extra.id)
+	case <-time.After(20 * time.Millisecond):
+	}
+	fill(engine, first.id, "1", "50")
+	second := venue.next(t)
+	fill(engine, second.id, "1", "51")
+	third := venue.next(t)
+	if third.quantity != "0.5" {
+		t.Fatalf("expected final child of 0.5, got %s", third.quantity)
+	}
+	fill(engine, third.id, "0.5", "52")
+	progress := waitStatus(t, engine, id, StatusCompleted)
+	if progress.FilledQuantity != "2.5" || progress.AvgFillPrice != "50.8" {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+}
+
+func TestCancelStopsSlicingAndCancelsChildren(t *testing.T) {
+	venue := newFakeVenue()
+	engine := startEngine(t, venue)
+	price := "10"
+	id, err := engine.Submit(ParentOrder{
+		Kind:            KindIceberg,
+		Provider:        "binance",
+		Side:            schema.TradeSideBuy,
+		Quantity:        "5",
+		Price:           &price,
+		DisplayQuantity: "1",
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	child := venue.next(t)
+	if err := engine.Cancel(id); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	waitStatus(t, engine, id, StatusCancelled)
+	venue.mu.Lock()
+	defer venue.mu.Unlock()
+	if len(venue.cancelled) != 1 || venue.cancelled[0] != child.id {
+		t.Fatalf("expected resting child %s cancelled, got %v", child.id, venue.cancelled)
+	}
+	if err := engine.Cancel("missing"); !errors.Is(err, ErrUnknownOrder) {
+		t.Fatalf("expected ErrUnknownOrder, got %v", err)
+	}
+}
+
+func TestRejectedChildFailsParent(t *testing.T) {
+	venue := newFakeVenue()
+	engine := startEngine(t, venue)
+	id, err := engine.Submit(ParentOrder{
+		Kind:     KindTWAP,
+		Provider: "binance",
+		Side:     schema.TradeSideBuy,
+		Quantity: "1",
+		Slices:   2,
+		Duration: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	child := venue.next(t)
+	reason := "insufficient balance"
+	engine.OnExecReport(schema.ExecReportPayload{ClientOrderID: child.id, State: schema.ExecReportStateREJECTED, RejectReason: &reason})
+	progress := waitStatus(t, engine, id, StatusFailed)
+	if progress.Error == "" || progress.ChildOrders != 1 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+}
+
+func TestUntrackedChildrenCompleteImmediately(t *testing.T) {
+	venue := newFakeVenue()
+	venue.untracked = true
+	engine := startEngine(t, venue)
+	price := "10"
+	id, err := engine.Submit(ParentOrder{
+		Kind:            KindIceberg,
+		Provider:        "binance",
+		Side:            schema.TradeSideBuy,
+		Quantity:        "3",
+		Price:           &price,
+		DisplayQuantity: "1",
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	progress := waitStatus(t, engine, id, StatusCompleted)
+	if progress.SubmittedQuantity != "3" || progress.FilledQuantity != "0" || progress.OpenChildOrders != 0 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+}
+
+func TestSubmitAfterStopFails(t *testing.T) {
+	engine := NewEngine("inst", newFakeVenue())
+	if _, err := engine.Submit(ParentOrder{Kind: KindTWAP, Provider: "p", Side: schema.TradeSideBuy, Quantity: "1", Duration: time.Second}); !errors.Is(err, ErrEngineStopped) {
+		t.Fatalf("expected ErrEngineStopped before start, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package algo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// plan holds the validated sizing of a parent order.
+type plan struct {
+	quantity decimal.Decimal
+	// child is the TWAP slice size or the iceberg display size.
+	child    decimal.Decimal
+	interval time.Duration
+}
+
+func newPlan(order ParentOrder) (plan, error) {
+	var empty plan
+	if order.Provider == "" {
+		return empty, fmt.Errorf("algo: provider required")
+	}
+	if order.Side != schema.TradeSideBuy && order.Side != schema.TradeSideSell {
+		return empty, fmt.Errorf("algo: side must be Buy or Sell")
+	}
+	quantity, ok := parseDecimal(order.Quantity)
+	if !ok || !quantity.IsPositive() {
+		return empty, fmt.Errorf("algo: quantity must be a positive decimal")
+	}
+	if order.Price != nil {
+		if price, ok := parseDecimal(*order.Price); !ok || !price.IsPositive() {
+			return empty, fmt.Errorf("algo: price must be a positive decimal")
+		}
+	}
+
+	switch order.Kind {
+	case KindTWAP:
+		if order.Duration <= 0 {
+			return empty, fmt.Errorf("algo: twap duration must be positive")
+		}
+		slices := order.Slices
+		slice := decimal.Zero
+		if strings.TrimSpace(order.SliceQuantity) != "" {
+			parsed, ok := parseDecimal(order.SliceQuantity)
+			if !ok || !parsed.IsPositive() {
+				return empty, fmt.Errorf("algo: twap slice quantity must be a positive decimal")
+			}
+			slice = parsed
+			slices = int(quantity.Div(slice).Ceil().IntPart())
+		} else {
+			if slices < 0 {
+				return empty, fmt.Errorf("algo: twap slices must not be negative")
+			}
+			if slices == 0 {
+				slices = defaultTWAPSlices
+			}
+			slice = quantity.DivRound(decimal.NewFromInt(int64(slices)), quantityScale)
+			if !slice.IsPositive() {
+				return empty, fmt.Errorf("algo: twap quantity too small for %d slices", slices)
+			}
+		}
+		interval := time.Duration(0)
+		if slices > 1 {
+			interval = order.Duration / time.Duration(slices)
+		}
+		return plan{quantity: quantity, child: slice, interval: interval}, nil
+	case KindIceberg:
+		if order.Price == nil {
+			return empty, fmt.Errorf("algo: iceberg requires a limit price")
+		}
+		display, ok := parseDecimal(order.DisplayQuantity)
+		if !ok || !display.IsPositive() {
+			return empty, fmt.Errorf("algo: iceberg display quantity must be a positive decimal")
+		}
+		return plan{quantity: quantity, child: display, interval: 0}, nil
+	default:
+		return empty, fmt.Errorf("algo: kind must be one of twap, iceberg")
+	}
+}
+
+// runTWAP submits one slice per interval until the parent quantity has been handed out.
+func (e *Engine) runTWAP(ctx context.Context, parent *parentState) error {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for i := 0; ; i++ {
+		quantity := e.remaining(parent, parent.plan.child)
+		if !quantity.IsPositive() {
+			return nil
+		}
+		if i > 0 && parent.plan.interval > 0 {
+			if timer == nil {
+				timer = time.NewTimer(parent.plan.interval)
+			} else {
+				timer.Reset(parent.plan.interval)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := e.placeChild(ctx, parent, quantity); err != nil {
+			return err
+		}
+	}
+}
+
+// runIceberg keeps one child of the display size resting and posts the next once it fills.
+func (e *Engine) runIceberg(ctx context.Context, parent *parentState) error {
+	for {
+		quantity := e.remaining(parent, parent.plan.child)
+		if !quantity.IsPositive() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		child, err := e.placeChild(ctx, parent, quantity)
+		if err != nil {
+			return err
+		}
+		if child == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-child.done:
+		}
+		e.mu.Lock()
+		state, filled := child.state, child.filled
+		e.mu.Unlock()
+		if state != schema.ExecReportStateFILLED && filled.LessThan(child.quantity) {
+			return fmt.Errorf("child %s ended %s before filling", child.id, strings.ToLower(string(state)))
+		}
+	}
+}
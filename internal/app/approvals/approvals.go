@@ -0,0 +1,358 @@
+// Package approvals holds high-impact control API actions — enabling live trading, raising risk
+// limits, deleting providers — until a second authenticated operator confirms them.
+package approvals
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// maxClosedApprovals bounds how many decided or expired approvals are kept for review.
+const maxClosedApprovals = 500
+
+// Status reports where an approval is in its lifecycle.
+type Status string
+
+const (
+	// StatusPending marks an approval waiting for a second operator.
+	StatusPending Status = "pending"
+	// StatusApproved marks an approval confirmed and being executed.
+	StatusApproved Status = "approved"
+	// StatusExecuted marks an approval whose action succeeded.
+	StatusExecuted Status = "executed"
+	// StatusFailed marks an approval whose action returned an error.
+	StatusFailed Status = "failed"
+	// StatusRejected marks an approval rejected or withdrawn by an operator.
+	StatusRejected Status = "rejected"
+	// StatusExpired marks an approval nobody confirmed within the configured TTL.
+	StatusExpired Status = "expired"
+)
+
+var (
+	// ErrApprovalNotFound indicates the approval does not exist.
+	ErrApprovalNotFound = errors.New("approval not found")
+	// ErrApprovalClosed indicates the approval is no longer pending.
+	ErrApprovalClosed = errors.New("approval no longer pending")
+	// ErrUnauthenticated indicates the caller has no authenticated identity.
+	ErrUnauthenticated = errors.New("approvals require an authenticated operator")
+	// ErrSelfApproval indicates the requester tried to approve their own action.
+	ErrSelfApproval = errors.New("approval requires a second operator")
+)
+
+// Executor performs the guarded action once approved and returns its response body.
+type Executor func(ctx context.Context) (any, error)
+
+// Approval is a guarded action and its decision.
+type Approval struct {
+	ID          string     `json:"id"`
+	Action      string     `json:"action"`
+	Target      string     `json:"target,omitempty"`
+	Summary     string     `json:"summary"`
+	Status      Status     `json:"status"`
+	RequestedBy string     `json:"requestedBy"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	DecidedBy   string     `json:"decidedBy,omitempty"`
+	DecidedAt   *time.Time `json:"decidedAt,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Result      any        `json:"result,omitempty"`
+}
+
+// Open reports whether the approval can still be approved or rejected.
+func (a Approval) Open() bool {
+	return a.Status == StatusPending
+}
+
+// Option configures a Queue.
+type Option func(*Queue)
+
+// WithClock overrides the time source.
+func WithClock(clock func() time.Time) Option {
+	return func(q *Queue) {
+		if clock != nil {
+			q.clock = clock
+		}
+	}
+}
+
+// WithLogger overrides the queue logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(q *Queue) {
+		if logger != nil {
+			q.logger = logger
+		}
+	}
+}
+
+type entry struct {
+	approval Approval
+	execute  Executor
+	seq      uint64
+}
+
+// Queue keeps pending approvals in memory. They do not survive a restart; the requester simply
+// submits the action again.
+type Queue struct {
+	cfg    config.ApprovalsConfig
+	clock  func() time.Time
+	logger *log.Logger
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	seq     uint64
+}
+
+// New constructs an approval queue.
+func New(cfg config.ApprovalsConfig, opts ...Option) *Queue {
+	q := &Queue{
+		cfg:     cfg,
+		clock:   time.Now,
+		logger:  log.New(redact.Stdout, "approvals ", log.LstdFlags|log.Lmicroseconds),
+		mu:      sync.Mutex{},
+		entries: make(map[string]*entry),
+		seq:     0,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(q)
+		}
+	}
+	return q
+}
+
+// Guards reports whether action must be approved before it runs.
+func (q *Queue) Guards(action string) bool {
+	return q != nil && q.cfg.Guards(action)
+}
+
+// RiskLimitIncreasePercent is how far risk limits may rise without approval.
+func (q *Queue) RiskLimitIncreasePercent() float64 {
+	if q == nil {
+		return 0
+	}
+	return q.cfg.RiskLimitIncreasePercent
+}
+
+// Operator returns the name of the operator whose configured token is token, or "" when none
+// matches.
+func (q *Queue) Operator(token string) string {
+	if q == nil || token == "" {
+		return ""
+	}
+	name := ""
+	for _, operator := range q.cfg.Operators {
+		// Compare against every operator so the timing does not reveal which token matched.
+		if subtle.ConstantTimeCompare([]byte(token), []byte(operator.Token)) == 1 {
+			name = operator.Name
+		}
+	}
+	return name
+}
+
+// TrustedHeader names the request header carrying the proxy-verified operator identity, or "" when
+// none is trusted.
+func (q *Queue) TrustedHeader() string {
+	if q == nil {
+		return ""
+	}
+	return q.cfg.TrustedHeader
+}
+
+// Request records a pending approval for an action requested by principal. execute runs when a
+// different authenticated principal approves it.
+func (q *Queue) Request(action, target, summary, principal string, execute Executor) (Approval, error) {
+	principal = strings.TrimSpace(principal)
+	if principal == "" {
+		var empty Approval
+		return empty, ErrUnauthenticated
+	}
+	if execute == nil {
+		var empty Approval
+		return empty, fmt.Errorf("approval %s: executor required", action)
+	}
+	now := q.clock().UTC()
+	approval := Approval{
+		ID:          uuid.NewString(),
+		Action:      action,
+		Target:      strings.TrimSpace(target),
+		Summary:     strings.TrimSpace(summary),
+		Status:      StatusPending,
+		RequestedBy: principal,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(q.cfg.TTL),
+		DecidedBy:   "",
+		DecidedAt:   nil,
+		Reason:      "",
+		Error:       "",
+		Result:      nil,
+	}
+
+	q.mu.Lock()
+	q.expireLocked(now)
+	q.seq++
+	q.entries[approval.ID] = &entry{approval: approval, execute: execute, seq: q.seq}
+	q.pruneLocked()
+	q.mu.Unlock()
+
+	q.logger.Printf("approval %s requested by %s: %s", approval.ID, principal, approval.Summary)
+	return approval, nil
+}
+
+// Approve confirms a pending approval on behalf of principal, who must differ from the requester,
+// and executes the guarded action. The action keeps running if ctx is cancelled, so an approver
+// disconnecting cannot abandon it halfway.
+func (q *Queue) Approve(ctx context.Context, id, principal string) (Approval, error) {
+	principal = strings.TrimSpace(principal)
+	if principal == "" {
+		var empty Approval
+		return empty, ErrUnauthenticated
+	}
+	now := q.clock().UTC()
+
+	q.mu.Lock()
+	q.expireLocked(now)
+	current, ok := q.entries[strings.TrimSpace(id)]
+	if !ok {
+		q.mu.Unlock()
+		var empty Approval
+		return empty, ErrApprovalNotFound
+	}
+	if !current.approval.Open() {
+		approval := current.approval
+		q.mu.Unlock()
+		return approval, fmt.Errorf("%w: %s", ErrApprovalClosed, approval.Status)
+	}
+	if current.approval.RequestedBy == principal {
+		approval := current.approval
+		q.mu.Unlock()
+		return approval, ErrSelfApproval
+	}
+	current.approval.Status = StatusApproved
+	current.approval.DecidedBy = principal
+	current.approval.DecidedAt = &now
+	execute := current.execute
+	summary := current.approval.Summary
+	q.mu.Unlock()
+
+	q.logger.Printf("approval %s approved by %s: %s", id, principal, summary)
+	result, err := execute(context.WithoutCancel(ctx))
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	current.execute = nil
+	if err != nil {
+		current.approval.Status = StatusFailed
+		current.approval.Error = redact.Error(err)
+		q.logger.Printf("approval %s: action failed: %v", id, err)
+	} else {
+		current.approval.Status = StatusExecuted
+		current.approval.Result = result
+	}
+	return current.approval, nil
+}
+
+// Reject closes a pending approval without executing it. The requester may withdraw their own
+// request.
+func (q *Queue) Reject(id, principal, reason string) (Approval, error) {
+	principal = strings.TrimSpace(principal)
+	if principal == "" {
+		var empty Approval
+		return empty, ErrUnauthenticated
+	}
+	now := q.clock().UTC()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expireLocked(now)
+	current, ok := q.entries[strings.TrimSpace(id)]
+	if !ok {
+		var empty Approval
+		return empty, ErrApprovalNotFound
+	}
+	if !current.approval.Open() {
+		return current.approval, fmt.Errorf("%w: %s", ErrApprovalClosed, current.approval.Status)
+	}
+	current.approval.Status = StatusRejected
+	current.approval.DecidedBy = principal
+	current.approval.DecidedAt = &now
+	current.approval.Reason = strings.TrimSpace(reason)
+	current.execute = nil
+	q.logger.Printf("approval %s rejected by %s: %s", id, principal, current.approval.Summary)
+	return current.approval, nil
+}
+
+// Approval returns a single approval.
+func (q *Queue) Approval(id string) (Approval, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expireLocked(q.clock().UTC())
+	current, ok := q.entries[strings.TrimSpace(id)]
+	if !ok {
+		var empty Approval
+		return empty, false
+	}
+	return current.approval, true
+}
+
+// Approvals lists approvals newest first. Unless all is set only pending approvals are returned.
+func (q *Queue) Approvals(all bool) []Approval {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expireLocked(q.clock().UTC())
+	matched := make([]*entry, 0, len(q.entries))
+	for _, current := range q.entries {
+		if all || current.approval.Open() {
+			matched = append(matched, current)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].seq > matched[j].seq
+	})
+	out := make([]Approval, 0, len(matched))
+	for _, current := range matched {
+		out = append(out, current.approval)
+	}
+	return out
+}
+
+func (q *Queue) expireLocked(now time.Time) {
+	for id, current := range q.entries {
+		if current.approval.Open() && !now.Before(current.approval.ExpiresAt) {
+			current.approval.Status = StatusExpired
+			current.execute = nil
+			q.logger.Printf("approval %s expired: %s", id, current.approval.Summary)
+		}
+	}
+}
+
+// pruneLocked drops the oldest closed approvals beyond maxClosedApprovals.
+func (q *Queue) pruneLocked() {
+	closed := make([]*entry, 0, len(q.entries))
+	for _, current := range q.entries {
+		if !current.approval.Open() && current.approval.Status != StatusApproved {
+			closed = append(closed, current)
+		}
+	}
+	if len(closed) <= maxClosedApprovals {
+		return
+	}
+	sort.Slice(closed, func(i, j int) bool {
+		return closed[i].seq < closed[j].seq
+	})
+	for _, current := range closed[:len(closed)-maxClosedApprovals] {
+		delete(q.entries, current.approval.ID)
+	}
+}
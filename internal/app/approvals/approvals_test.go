@@ -0,0 +1,137 @@
+package approvals
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+func newTestQueue(now *time.Time) *Queue {
+	cfg := config.ApprovalsConfig{
+		Enabled:                  true,
+		Actions:                  []string{config.ApprovalActionLiveTrading},
+		RiskLimitIncreasePercent: 0,
+		TTL:                      time.Hour,
+	}
+	return New(cfg, WithClock(func() time.Time { return *now }), WithLogger(log.New(io.Discard, "", 0)))
+}
+
+func TestApproveRequiresSecondOperator(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	queue := newTestQueue(&now)
+	if !queue.Guards(config.ApprovalActionLiveTrading) || queue.Guards(config.ApprovalActionProviderDelete) {
+		t.Fatal("unexpected guarded actions")
+	}
+
+	executed := 0
+	execute := func(context.Context) (any, error) {
+		executed++
+		return map[string]string{"status": "ok"}, nil
+	}
+	if _, err := queue.Request(config.ApprovalActionLiveTrading, "alpha", "enable live trading", "", execute); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected anonymous request rejected, got %v", err)
+	}
+	approval, err := queue.Request(config.ApprovalActionLiveTrading, "alpha", "enable live trading", "user:\"alice\"", execute)
+	if err != nil || approval.Status != StatusPending || !approval.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("unexpected approval %+v (%v)", approval, err)
+	}
+	if _, err := queue.Approve(context.Background(), approval.ID, "user:\"alice\""); !errors.Is(err, ErrSelfApproval) {
+		t.Fatalf("expected self-approval rejected, got %v", err)
+	}
+	if _, err := queue.Approve(context.Background(), approval.ID, ""); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("expected anonymous approval rejected, got %v", err)
+	}
+	if executed != 0 {
+		t.Fatal("action executed before approval")
+	}
+
+	approved, err := queue.Approve(context.Background(), approval.ID, "user:\"bob\"")
+	if err != nil || approved.Status != StatusExecuted || approved.DecidedBy != "user:\"bob\"" || executed != 1 {
+		t.Fatalf("unexpected approval %+v (%v), executed %d", approved, err, executed)
+	}
+	if _, err := queue.Approve(context.Background(), approval.ID, "user:\"carol\""); !errors.Is(err, ErrApprovalClosed) || executed != 1 {
+		t.Fatalf("expected closed approval, got %v", err)
+	}
+	if pending := queue.Approvals(false); len(pending) != 0 {
+		t.Fatalf("expected no pending approvals, got %+v", pending)
+	}
+}
+
+func TestApprovalsFailRejectAndExpire(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	queue := newTestQueue(&now)
+
+	failing, _ := queue.Request(config.ApprovalActionLiveTrading, "alpha", "enable", "user:\"alice\"", func(context.Context) (any, error) {
+		return nil, errors.New("GET /api/v3/account?timestamp=1&signature=deadbeef returned 401")
+	})
+	approval, err := queue.Approve(context.Background(), failing.ID, "user:\"bob\"")
+	if err != nil || approval.Status != StatusFailed || approval.Error != "GET /api/v3/account?timestamp=1&signature="+redact.Mask+" returned 401" {
+		t.Fatalf("expected failed approval, got %+v (%v)", approval, err)
+	}
+
+	noop := func(context.Context) (any, error) { return nil, nil }
+	withdrawn, _ := queue.Request(config.ApprovalActionLiveTrading, "beta", "enable", "user:\"alice\"", noop)
+	rejected, err := queue.Reject(withdrawn.ID, "user:\"alice\"", "wrong instance")
+	if err != nil || rejected.Status != StatusRejected || rejected.Reason != "wrong instance" {
+		t.Fatalf("expected withdrawn approval, got %+v (%v)", rejected, err)
+	}
+
+	stale, _ := queue.Request(config.ApprovalActionLiveTrading, "gamma", "enable", "user:\"alice\"", noop)
+	now = now.Add(time.Hour)
+	if _, err := queue.Approve(context.Background(), stale.ID, "user:\"bob\""); !errors.Is(err, ErrApprovalClosed) {
+		t.Fatalf("expected expired approval closed, got %v", err)
+	}
+	if expired, _ := queue.Approval(stale.ID); expired.Status != StatusExpired {
+		t.Fatalf("expected expired status, got %s", expired.Status)
+	}
+	if all := queue.Approvals(true); len(all) != 3 || all[0].ID != stale.ID {
+		t.Fatalf("expected three approvals newest first, got %+v", all)
+	}
+	if _, err := queue.Reject("missing", "user:\"bob\"", ""); !errors.Is(err, ErrApprovalNotFound) {
+		t.Fatalf("expected missing approval, got %v", err)
+	}
+}
+
+func TestApproveRunsActionPastCallerCancellation(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	queue := newTestQueue(&now)
+	var actionErr error
+	approval, err := queue.Request(config.ApprovalActionLiveTrading, "alpha", "enable live trading", "operator:alice", func(ctx context.Context) (any, error) {
+		actionErr = ctx.Err()
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	approved, err := queue.Approve(ctx, approval.ID, "operator:bob")
+	if err != nil || approved.Status != StatusExecuted {
+		t.Fatalf("unexpected approval %+v (%v)", approved, err)
+	}
+	if actionErr != nil {
+		t.Fatalf("expected the action context to outlive the approving request, got %v", actionErr)
+	}
+}
+
+func TestOperatorMatchesConfiguredTokens(t *testing.T) {
+	queue := New(config.ApprovalsConfig{
+		Enabled:   true,
+		TTL:       time.Hour,
+		Operators: []config.ApprovalOperatorConfig{{Name: "alice", Token: "alice-token-0123456789"}},
+	}, WithLogger(log.New(io.Discard, "", 0)))
+	if got := queue.Operator("alice-token-0123456789"); got != "alice" {
+		t.Fatalf("expected alice, got %q", got)
+	}
+	for _, token := range []string{"", "alice", "alice-token-012345678"} {
+		if got := queue.Operator(token); got != "" {
+			t.Fatalf("expected no operator for %q, got %q", token, got)
+		}
+	}
+}
@@ -0,0 +1,363 @@
+// Package audit assembles the chronological trail of a strategy instance — the events delivered to
+// it and the orders, executions and risk decisions it produced — from the order store and the
+// event outbox for incident investigations.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/outboxstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// Kind classifies an audit entry.
+type Kind string
+
+const (
+	// KindEvent is an event delivered to the instance.
+	KindEvent Kind = "event"
+	// KindOrder is an order placed by the instance, with its latest state.
+	KindOrder Kind = "order"
+	// KindExecution is a fill recorded against one of the instance's orders.
+	KindExecution Kind = "execution"
+	// KindRiskDecision is a risk control notification raised for the instance.
+	KindRiskDecision Kind = "risk"
+	// KindTruncated marks the end of an export that hit a limit before covering the range.
+	KindTruncated Kind = "truncated"
+)
+
+const (
+	// DefaultLimit caps the entries of an export when the scope does not set one.
+	DefaultLimit = 10000
+	// MaxLimit is the largest accepted export size.
+	MaxLimit = 100000
+
+	storeLimit = 500
+	pageSize   = 1024
+)
+
+// Entry is one line of an audit export.
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Kind      Kind      `json:"kind"`
+	Instance  string    `json:"instance"`
+	Source    string    `json:"source"`
+	EventType string    `json:"eventType,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	Symbol    string    `json:"symbol,omitempty"`
+	OutboxID  int64     `json:"outboxId,omitempty"`
+	Data      any       `json:"data,omitempty"`
+}
+
+// Scope selects the instance and time range to export.
+type Scope struct {
+	Instance string
+	// ProviderSymbols is the instance's routing scope, used to pick the market data it received.
+	ProviderSymbols map[string][]string
+	Since           time.Time
+	Until           time.Time
+	// EventTypes restricts the outbox events considered; empty means all types.
+	EventTypes []string
+	Limit      int
+}
+
+// Exporter builds audit trails. Either store may be nil, in which case its records are omitted.
+type Exporter struct {
+	orders orderstore.Store
+	events outboxstore.EventLister
+}
+
+// NewExporter constructs an exporter over the order store and outbox.
+func NewExporter(orders orderstore.Store, events outboxstore.EventLister) *Exporter {
+	return &Exporter{orders: orders, events: events}
+}
+
+// Export streams the audit trail of scope to emit in chronological order.
+func (e *Exporter) Export(ctx context.Context, scope Scope, emit func(Entry) error) error {
+	instance := strings.TrimSpace(scope.Instance)
+	if instance == "" {
+		return fmt.Errorf("audit: instance required")
+	}
+	limit := scope.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	} else if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	records, truncatedStore, err := e.storeEntries(ctx, instance, scope)
+	if err != nil {
+		return err
+	}
+	filter := newEventFilter(instance, scope.ProviderSymbols)
+	emitted := 0
+	truncated := func(reason string) error {
+		return emit(Entry{
+			Time:      time.Now().UTC(),
+			Kind:      KindTruncated,
+			Instance:  instance,
+			Source:    "audit",
+			EventType: "",
+			Provider:  "",
+			Symbol:    "",
+			OutboxID:  0,
+			Data:      map[string]any{"reason": reason, "entries": emitted},
+		})
+	}
+	next := 0
+	flushUntil := func(t time.Time, all bool) (bool, error) {
+		for next < len(records) && (all || !records[next].Time.After(t)) {
+			if emitted >= limit {
+				return false, truncated("limit reached")
+			}
+			if err := emit(records[next]); err != nil {
+				return false, err
+			}
+			emitted++
+			next++
+		}
+		return true, nil
+	}
+
+	if e.events != nil {
+		afterID := int64(0)
+		for {
+			page, err := e.events.ListEvents(ctx, outboxstore.EventQuery{
+				AfterID:    afterID,
+				Since:      scope.Since,
+				Until:      scope.Until,
+				EventTypes: scope.EventTypes,
+				Providers:  filter.providers(),
+				Limit:      pageSize,
+			})
+			if err != nil {
+				return fmt.Errorf("audit: list events: %w", err)
+			}
+			for _, record := range page {
+				afterID = record.ID
+				entry, ok := filter.entry(record)
+				if !ok {
+					continue
+				}
+				more, err := flushUntil(entry.Time, false)
+				if err != nil || !more {
+					return err
+				}
+				if emitted >= limit {
+					return truncated("limit reached")
+				}
+				if err := emit(entry); err != nil {
+					return err
+				}
+				emitted++
+			}
+			if len(page) < pageSize {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("audit: %w", err)
+			}
+		}
+	}
+
+	more, err := flushUntil(time.Time{}, true)
+	if err != nil || !more {
+		return err
+	}
+	if truncatedStore {
+		return truncated(fmt.Sprintf("order store returned %d records; narrow the time range", storeLimit))
+	}
+	return nil
+}
+
+// storeEntries loads the instance's orders and executions in the range, oldest first.
+func (e *Exporter) storeEntries(ctx context.Context, instance string, scope Scope) ([]Entry, bool, error) {
+	if e.orders == nil {
+		return nil, false, nil
+	}
+	since, until := unixOrZero(scope.Since), unixOrZero(scope.Until)
+	orders, err := e.orders.ListOrders(ctx, orderstore.OrderQuery{
+		StrategyInstance: instance,
+		Provider:         "",
+		States:           nil,
+		Since:            since,
+		Until:            until,
+		Tag:              "",
+		Limit:            storeLimit,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("audit: list orders: %w", err)
+	}
+	executions, err := e.orders.ListExecutions(ctx, orderstore.ExecutionQuery{
+		StrategyInstance: instance,
+		Provider:         "",
+		OrderID:          "",
+		Since:            since,
+		Until:            until,
+		Tag:              "",
+		Limit:            storeLimit,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("audit: list executions: %w", err)
+	}
+	entries := make([]Entry, 0, len(orders)+len(executions))
+	for _, order := range orders {
+		entries = append(entries, Entry{
+			Time:      time.Unix(order.PlacedAt, 0).UTC(),
+			Kind:      KindOrder,
+			Instance:  instance,
+			Source:    "orderstore",
+			EventType: "",
+			Provider:  order.Provider,
+			Symbol:    order.Symbol,
+			OutboxID:  0,
+			Data:      order,
+		})
+	}
+	for _, execution := range executions {
+		entries = append(entries, Entry{
+			Time:      time.Unix(execution.TradedAt, 0).UTC(),
+			Kind:      KindExecution,
+			Instance:  instance,
+			Source:    "orderstore",
+			EventType: "",
+			Provider:  execution.Provider,
+			Symbol:    "",
+			OutboxID:  0,
+			Data:      execution,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+	truncated := len(orders) >= storeLimit || len(executions) >= storeLimit
+	return entries, truncated, nil
+}
+
+// outboxEvent is the subset of a persisted schema.Event needed to attribute it to an instance.
+type outboxEvent struct {
+	EventID  string           `json:"eventId"`
+	Provider string           `json:"provider"`
+	Symbol   string           `json:"symbol"`
+	Type     schema.EventType `json:"type"`
+	EmitTS   time.Time        `json:"emitTs"`
+	Payload  json.RawMessage  `json:"payload"`
+}
+
+type eventAttribution struct {
+	ClientOrderID string `json:"clientOrderId"`
+	StrategyID    string `json:"strategyId"`
+	Currency      string `json:"currency"`
+}
+
+// eventFilter decides which outbox events were delivered to, or produced for, the instance.
+type eventFilter struct {
+	instance   string
+	symbols    map[string]map[string]struct{}
+	currencies map[string]struct{}
+}
+
+func newEventFilter(instance string, providerSymbols map[string][]string) eventFilter {
+	filter := eventFilter{
+		instance:   instance,
+		symbols:    make(map[string]map[string]struct{}, len(providerSymbols)),
+		currencies: make(map[string]struct{}),
+	}
+	for provider, symbols := range providerSymbols {
+		provider = strings.TrimSpace(provider)
+		if provider == "" {
+			continue
+		}
+		set := make(map[string]struct{}, len(symbols))
+		for _, symbol := range symbols {
+			symbol = strings.ToUpper(strings.TrimSpace(symbol))
+			if symbol == "" {
+				continue
+			}
+			set[symbol] = struct{}{}
+			if base, quote, err := schema.InstrumentCurrencies(symbol); err == nil {
+				filter.currencies[strings.ToUpper(base)] = struct{}{}
+				filter.currencies[strings.ToUpper(quote)] = struct{}{}
+			}
+		}
+		filter.symbols[provider] = set
+	}
+	return filter
+}
+
+func (f eventFilter) providers() []string {
+	out := make([]string, 0, len(f.symbols))
+	for provider := range f.symbols {
+		out = append(out, provider)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (f eventFilter) entry(record outboxstore.EventRecord) (Entry, bool) {
+	var evt outboxEvent
+	if err := json.Unmarshal(record.Payload, &evt); err != nil {
+		return Entry{}, false
+	}
+	var attribution eventAttribution
+	if len(evt.Payload) > 0 {
+		_ = json.Unmarshal(evt.Payload, &attribution)
+	}
+	kind := KindEvent
+	switch evt.Type {
+	case schema.EventTypeExecReport:
+		if !strings.HasPrefix(attribution.ClientOrderID, f.instance+"-") {
+			return Entry{}, false
+		}
+	case schema.EventTypeRiskControl:
+		if attribution.StrategyID != f.instance {
+			return Entry{}, false
+		}
+		kind = KindRiskDecision
+	case schema.EventTypeBalanceUpdate:
+		if _, ok := f.currencies[strings.ToUpper(strings.TrimSpace(attribution.Currency))]; !ok {
+			return Entry{}, false
+		}
+	case schema.ExtensionEventType:
+		if !strings.HasPrefix(evt.EventID, "algo:"+f.instance+"-") && !strings.HasPrefix(evt.EventID, "trading:"+f.instance+":") {
+			return Entry{}, false
+		}
+	default:
+		symbols, ok := f.symbols[evt.Provider]
+		if !ok {
+			return Entry{}, false
+		}
+		if _, ok := symbols[strings.ToUpper(strings.TrimSpace(evt.Symbol))]; !ok {
+			return Entry{}, false
+		}
+	}
+	ts := evt.EmitTS
+	if ts.IsZero() {
+		ts = record.CreatedAt
+	}
+	return Entry{
+		Time:      ts.UTC(),
+		Kind:      kind,
+		Instance:  f.instance,
+		Source:    "outbox",
+		EventType: string(evt.Type),
+		Provider:  evt.Provider,
+		Symbol:    evt.Symbol,
+		OutboxID:  record.ID,
+		Data:      evt.Payload,
+	}, true
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
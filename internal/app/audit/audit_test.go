@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/outboxstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+type fakeOrders struct {
+	orderstore.Store
+	orders     []orderstore.OrderRecord
+	executions []orderstore.ExecutionRecord
+	orderQuery orderstore.OrderQuery
+}
+
+func (f *fakeOrders) ListOrders(_ context.Context, query orderstore.OrderQuery) ([]orderstore.OrderRecord, error) {
+	f.orderQuery = query
+	return f.orders, nil
+}
+
+func (f *fakeOrders) ListExecutions(_ context.Context, _ orderstore.ExecutionQuery) ([]orderstore.ExecutionRecord, error) {
+	return f.executions, nil
+}
+
+type fakeEvents struct {
+	records []outboxstore.EventRecord
+	queries []outboxstore.EventQuery
+}
+
+func (f *fakeEvents) ListEvents(_ context.Context, query outboxstore.EventQuery) ([]outboxstore.EventRecord, error) {
+	f.queries = append(f.queries, query)
+	var out []outboxstore.EventRecord
+	for _, record := range f.records {
+		if record.ID > query.AfterID && len(out) < query.Limit {
+			out = append(out, record)
+		}
+	}
+	return out, nil
+}
+
+func outboxRecord(t *testing.T, id int64, evt schema.Event) outboxstore.EventRecord {
+	t.Helper()
+	raw, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	return outboxstore.EventRecord{ID: id, EventType: string(evt.Type), Payload: raw, CreatedAt: evt.EmitTS}
+}
+
+func TestExportMergesStoreAndOutboxChronologically(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	orders := &fakeOrders{
+		orders:     []orderstore.OrderRecord{{Order: orderstore.Order{ID: "inst-1", Provider: "binance", Symbol: "BTC-USDT", PlacedAt: base.Add(2 * time.Second).Unix()}}},
+		executions: []orderstore.ExecutionRecord{{Execution: orderstore.Execution{OrderID: "inst-1", Provider: "binance", TradedAt: base.Add(4 * time.Second).Unix()}}},
+	}
+	events := &fakeEvents{records: []outboxstore.EventRecord{
+		outboxRecord(t, 1, schema.Event{Type: schema.EventTypeTrade, Provider: "binance", Symbol: "BTC-USDT", EmitTS: base.Add(time.Second), Payload: schema.TradePayload{Price: "1"}}),
+		outboxRecord(t, 2, schema.Event{Type: schema.EventTypeTrade, Provider: "binance", Symbol: "ETH-USDT", EmitTS: base.Add(time.Second)}),
+		outboxRecord(t, 3, schema.Event{Type: schema.EventTypeExecReport, Provider: "binance", Symbol: "BTC-USDT", EmitTS: base.Add(3 * time.Second), Payload: schema.ExecReportPayload{ClientOrderID: "inst-1"}}),
+		outboxRecord(t, 4, schema.Event{Type: schema.EventTypeExecReport, Provider: "binance", Symbol: "BTC-USDT", EmitTS: base.Add(3 * time.Second), Payload: schema.ExecReportPayload{ClientOrderID: "other-1"}}),
+		outboxRecord(t, 5, schema.Event{Type: schema.EventTypeRiskControl, Provider: "binance", EmitTS: base.Add(5 * time.Second), Payload: schema.RiskControlPayload{StrategyID: "inst"}}),
+		outboxRecord(t, 6, schema.Event{Type: schema.EventTypeBalanceUpdate, Provider: "binance", Symbol: "USDT", EmitTS: base.Add(6 * time.Second), Payload: schema.BalanceUpdatePayload{Currency: "USDT"}}),
+		outboxRecord(t, 7, schema.Event{Type: schema.EventTypeBalanceUpdate, Provider: "binance", Symbol: "DOGE", EmitTS: base.Add(6 * time.Second), Payload: schema.BalanceUpdatePayload{Currency: "DOGE"}}),
+	}}
+	exporter := NewExporter(orders, events)
+
+	var entries []Entry
+	err := exporter.Export(context.Background(), Scope{
+		Instance:        "inst",
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+		Since:           base,
+		Until:           base.Add(time.Minute),
+	}, func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	want := []Kind{KindEvent, KindOrder, KindEvent, KindExecution, KindRiskDecision, KindEvent}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(entries), entries)
+	}
+	for i, kind := range want {
+		if entries[i].Kind != kind {
+			t.Fatalf("entry %d kind %s, want %s", i, entries[i].Kind, kind)
+		}
+		if i > 0 && entries[i].Time.Before(entries[i-1].Time) {
+			t.Fatalf("entries out of order at %d", i)
+		}
+	}
+	if orders.orderQuery.StrategyInstance != "inst" || orders.orderQuery.Since != base.Unix() {
+		t.Fatalf("unexpected order query %+v", orders.orderQuery)
+	}
+	if len(events.queries) != 1 || len(events.queries[0].Providers) != 1 || events.queries[0].Providers[0] != "binance" {
+		t.Fatalf("unexpected event queries %+v", events.queries)
+	}
+}
+
+func TestExportTruncatesAtLimit(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := &fakeEvents{}
+	for i := int64(1); i <= 5; i++ {
+		events.records = append(events.records, outboxRecord(t, i, schema.Event{Type: schema.EventTypeTicker, Provider: "binance", Symbol: "BTC-USDT", EmitTS: base.Add(time.Duration(i) * time.Second)}))
+	}
+	exporter := NewExporter(nil, events)
+	var entries []Entry
+	err := exporter.Export(context.Background(), Scope{
+		Instance:        "inst",
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+		Limit:           3,
+	}, func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if len(entries) != 4 || entries[3].Kind != KindTruncated {
+		t.Fatalf("expected 3 entries and a truncation marker, got %+v", entries)
+	}
+}
+
+func TestExportRequiresInstance(t *testing.T) {
+	if err := NewExporter(nil, nil).Export(context.Background(), Scope{}, func(Entry) error { return nil }); err == nil {
+		t.Fatal("expected error for missing instance")
+	}
+}
@@ -0,0 +1,188 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+)
+
+// thresholdModule buys one unit at or below buy and sells it at or above sell.
+func thresholdModule(buy, sell string) string {
+	return fmt.Sprintf(`
+module.exports = {
+  metadata: {
+    name: "threshold",
+    version: "1.0.0",
+    displayName: "Threshold",
+    description: "Buys dips and sells rallies.",
+    config: [],
+    events: ["KlineSummary", "ExecReport"]
+  },
+  create: function(env) {
+    var position = 0;
+    var pending = false;
+    return {
+      onKlineSummary: function(ctx, evt, payload) {
+        if (pending) return;
+        var close = parseFloat(payload.closePrice);
+        if (position === 0 && close <= %s) {
+          pending = true;
+          env.runtime.submitMarketOrder("", "buy", "1");
+        } else if (position > 0 && close >= %s) {
+          pending = true;
+          env.runtime.submitOrder("", "sell", "1", String(close + 1));
+        }
+      },
+      onOrderAcknowledged: function() {},
+      onOrderFilled: function(ctx, evt, payload) {
+        pending = false;
+        position += String(payload.side) === "Buy" ? 1 : -1;
+      }
+    };
+  }
+};
+`, buy, sell)
+}
+
+func compileModule(t *testing.T, source string) *js.Module {
+	t.Helper()
+	loader, err := js.NewLoader(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	module, err := loader.Compile([]byte(source))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	return module
+}
+
+// klines builds a one-minute kline file whose closes are prices; highs sit 2 above the close.
+func klines(t *testing.T, prices ...int) *Dataset {
+	t.Helper()
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	b.WriteString(strings.Join(KlineColumns, ",") + "\n")
+	for i, price := range prices {
+		open := base.Add(time.Duration(i) * time.Minute)
+		fmt.Fprintf(&b, "%d,%d,%d,%d,%d,%d,10\n", open.UnixMilli(), open.Add(time.Minute-time.Millisecond).UnixMilli(), price, price+2, price-1, price)
+	}
+	data, err := ReadDataset(strings.NewReader(b.String()), "binance", "btc-usdt")
+	if err != nil {
+		t.Fatalf("ReadDataset: %v", err)
+	}
+	return data
+}
+
+func TestRunFillsMarketAndRestingOrders(t *testing.T) {
+	data := klines(t, 100, 95, 104, 106, 101)
+	result, err := Run(context.Background(), RunConfig{
+		Selector: "threshold@a",
+		Module:   compileModule(t, thresholdModule("95", "104")),
+		Data:     data,
+		FeeBps:   decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// Buy at the 95 close, rest a sell at 105 on the 104 close and fill it when the next kline's
+	// high (108) trades through.
+	if len(result.Fills) != 2 {
+		t.Fatalf("expected 2 fills, got %+v", result.Fills)
+	}
+	buy, sell := result.Fills[0], result.Fills[1]
+	if !buy.Price.Equal(decimal.NewFromInt(95)) || !sell.Price.Equal(decimal.NewFromInt(105)) || !sell.Time.Equal(result.Curve[3].Time) {
+		t.Fatalf("unexpected fills %+v %+v", buy, sell)
+	}
+	if want := decimal.RequireFromString("9.8"); !result.Summary.PnL.Equal(want) {
+		t.Fatalf("PnL = %s, want %s (10 less 0.2 fees)", result.Summary.PnL, want)
+	}
+	if !sell.RealizedPnL.Equal(decimal.RequireFromString("9.895")) {
+		t.Fatalf("realized = %s", sell.RealizedPnL)
+	}
+	if !result.Summary.MaxDrawdown.Equal(decimal.RequireFromString("0.095")) || !result.Summary.Position.IsZero() {
+		t.Fatalf("unexpected summary %+v", result.Summary)
+	}
+
+	again, err := Run(context.Background(), RunConfig{Selector: "threshold@a", Module: compileModule(t, thresholdModule("95", "104")), Data: data, FeeBps: decimal.NewFromInt(10)})
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if cmp, err := Compare(result, again); err != nil || len(cmp.TradeDiffs) != 0 || !cmp.PnLDiff.IsZero() || cmp.Significance.PValue != 1 {
+		t.Fatalf("expected identical replays, got %+v, %v", cmp, err)
+	}
+}
+
+func TestCompareReportsTradeDiffsAndSignificance(t *testing.T) {
+	prices := make([]int, 0, 60)
+	for i := 0; i < 20; i++ {
+		prices = append(prices, 100, 90, 110)
+	}
+	data := klines(t, prices...)
+	run := func(buy string) Result {
+		result, err := Run(context.Background(), RunConfig{Selector: "threshold@" + buy, Module: compileModule(t, thresholdModule(buy, "110")), Data: data})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		return result
+	}
+	baseline, candidate := run("100"), run("90")
+
+	cmp, err := Compare(baseline, candidate)
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+	if !cmp.PnLDiff.IsPositive() || cmp.Baseline.Selector != "threshold@100" || len(cmp.Curve) != len(prices) {
+		t.Fatalf("unexpected comparison %+v", cmp)
+	}
+	if len(cmp.TradeDiffs) == 0 || cmp.TradeDiffs[0].Seq != 1 || !cmp.TradeDiffs[0].Baseline.Price.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("unexpected trade diffs %+v", cmp.TradeDiffs)
+	}
+	if !cmp.Significance.Significant || cmp.Significance.Samples != len(prices) {
+		t.Fatalf("expected a significant difference, got %+v", cmp.Significance)
+	}
+
+	if _, err := Compare(baseline, Result{}); err == nil {
+		t.Fatal("expected runs over different datasets rejected")
+	}
+}
+
+func TestReadDatasetTrades(t *testing.T) {
+	data, err := ReadDataset(strings.NewReader("trade_id,timestamp,side,price,quantity\n1,1756684800000,BUY,100.5,0.2\n2,1756684801000,SELL,100.4,0.1\n"), "binance", "BTC-USDT")
+	if err != nil {
+		t.Fatalf("ReadDataset: %v", err)
+	}
+	if data.Kind != DataKindTrades || data.Len() != 2 || !data.records[1].last.Equal(decimal.RequireFromString("100.4")) {
+		t.Fatalf("unexpected dataset %+v", data)
+	}
+	for _, raw := range []string{
+		"",
+		"a,b\n1,2\n",
+		"trade_id,timestamp,side,price,quantity\n1,1756684801000,BUY,1,1\n2,1756684800000,BUY,1,1\n",
+		"trade_id,timestamp,side,price,quantity\n1,1756684800000,HOLD,1,1\n",
+	} {
+		if _, err := ReadDataset(strings.NewReader(raw), "binance", "BTC-USDT"); err == nil {
+			t.Fatalf("expected %q rejected", raw)
+		}
+	}
+}
+
+func TestPairedTTestPValue(t *testing.T) {
+	// Mean 1, sample standard deviation 0.943 over 10 rows: t = 3.354 with 9 degrees of freedom,
+	// two-sided p = 0.00847.
+	diffs := []float64{0, 2, 0, 2, 0, 2, 0, 2, 1, 1}
+	got := pairedTTest(diffs)
+	if math.Abs(got.TStat-3.3541) > 1e-3 || math.Abs(got.PValue-0.0084682) > 1e-6 || !got.Significant {
+		t.Fatalf("unexpected t-test %+v", got)
+	}
+	if flat := pairedTTest([]float64{0, 0, 0}); flat.PValue != 1 || flat.Significant {
+		t.Fatalf("expected identical paths insignificant, got %+v", flat)
+	}
+}
@@ -0,0 +1,157 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateColumns is the CSV header of funding and borrow rate files. Times are Unix milliseconds and
+// rates are fractions, e.g. 0.0001 for one basis point.
+var RateColumns = []string{"timestamp", "rate"}
+
+// year is the period borrow rates are quoted over.
+var year = decimal.NewFromInt(int64(365 * 24 * time.Hour))
+
+// RatePoint is one entry of a rate series.
+type RatePoint struct {
+	Time time.Time
+	Rate decimal.Decimal
+}
+
+// RateSeries is a time series of funding or borrow rates, oldest first.
+type RateSeries struct {
+	points []RatePoint
+}
+
+// Len returns the number of rates in the series.
+func (s *RateSeries) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.points)
+}
+
+// rateAt returns the latest rate set at or before at.
+func (s *RateSeries) rateAt(at time.Time) (decimal.Decimal, bool) {
+	if s.Len() == 0 {
+		return decimal.Zero, false
+	}
+	idx := sort.Search(len(s.points), func(i int) bool { return s.points[i].Time.After(at) })
+	if idx == 0 {
+		return decimal.Zero, false
+	}
+	return s.points[idx-1].Rate, true
+}
+
+// LoadRateSeries reads a rate CSV file.
+func LoadRateSeries(path string) (*RateSeries, error) {
+	file, err := os.Open(path) // #nosec G304 -- operator supplied rate file
+	if err != nil {
+		return nil, fmt.Errorf("open rate series: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return ReadRateSeries(file)
+}
+
+// ReadRateSeries parses a rate CSV stream with RateColumns.
+func ReadRateSeries(r io.Reader) (*RateSeries, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("rate series holds no rows")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read rate series header: %w", err)
+	}
+	if strings.Join(header, ",") != strings.Join(RateColumns, ",") {
+		return nil, fmt.Errorf("unrecognised rate series header %q", strings.Join(header, ","))
+	}
+	series := &RateSeries{points: nil}
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read rate series line %d: %w", line, err)
+		}
+		at, err := parseMillis(row[0])
+		if err != nil {
+			return nil, fmt.Errorf("rate series line %d: %w", line, err)
+		}
+		rate, err := decimal.NewFromString(strings.TrimSpace(row[1]))
+		if err != nil {
+			return nil, fmt.Errorf("rate series line %d: invalid rate %q", line, row[1])
+		}
+		if n := len(series.points); n > 0 && !at.After(series.points[n-1].Time) {
+			return nil, fmt.Errorf("rate series line %d: rows out of order", line)
+		}
+		series.points = append(series.points, RatePoint{Time: at, Rate: rate})
+	}
+	if len(series.points) == 0 {
+		return nil, fmt.Errorf("rate series holds no rows")
+	}
+	return series, nil
+}
+
+// FundingPayment is one funding settlement of the open position. Amount is what the strategy
+// received; it is negative when the position paid.
+type FundingPayment struct {
+	Time     time.Time       `json:"time"`
+	Rate     decimal.Decimal `json:"rate"`
+	Position decimal.Decimal `json:"position"`
+	Price    decimal.Decimal `json:"price"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+// accrue books the carry between the previous row and at: borrow interest on what the position
+// borrows over the interval, then every funding settlement the interval passed. Callers hold e.mu.
+func (e *exchange) accrue(at time.Time) {
+	if e.borrow != nil && !e.now.IsZero() && at.After(e.now) {
+		if rate, ok := e.borrow.rateAt(e.now); ok {
+			interest := e.borrowedLocked().Mul(rate).Mul(decimal.NewFromInt(int64(at.Sub(e.now)))).Div(year)
+			e.cash = e.cash.Sub(interest)
+			e.borrowCost = e.borrowCost.Add(interest)
+		}
+	}
+	for e.funding.Len() > e.nextFunding && !e.funding.points[e.nextFunding].Time.After(at) {
+		point := e.funding.points[e.nextFunding]
+		e.nextFunding++
+		if e.position.IsZero() || e.last.IsZero() {
+			continue
+		}
+		amount := e.position.Mul(e.last).Mul(point.Rate).Neg()
+		e.cash = e.cash.Add(amount)
+		e.fundingNet = e.fundingNet.Add(amount)
+		e.payments = append(e.payments, FundingPayment{
+			Time:     point.Time,
+			Rate:     point.Rate,
+			Position: e.position,
+			Price:    e.last,
+			Amount:   amount,
+		})
+	}
+}
+
+// borrowedLocked is the notional the position borrows: quote currency financing a long beyond the
+// cash on hand, and the base currency sold short valued at the last price. Callers hold e.mu.
+func (e *exchange) borrowedLocked() decimal.Decimal {
+	borrowed := decimal.Zero
+	if e.cash.IsNegative() {
+		borrowed = borrowed.Add(e.cash.Neg())
+	}
+	if e.position.IsNegative() {
+		borrowed = borrowed.Add(e.position.Neg().Mul(e.last))
+	}
+	return borrowed
+}
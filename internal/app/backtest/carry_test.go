@@ -0,0 +1,79 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func rateSeries(t *testing.T, rows ...string) *RateSeries {
+	t.Helper()
+	series, err := ReadRateSeries(strings.NewReader(strings.Join(RateColumns, ",") + "\n" + strings.Join(rows, "\n") + "\n"))
+	if err != nil {
+		t.Fatalf("ReadRateSeries: %v", err)
+	}
+	return series
+}
+
+func TestRunAppliesFundingAndBorrowCosts(t *testing.T) {
+	data := klines(t, 100, 95, 104, 106, 101)
+	// Rows close a minute apart. Funding settles once while the strategy holds its long, and a
+	// 525.6 annual borrow rate charges 0.1% of the borrowed quote per minute.
+	funding := rateSeries(t, fmt.Sprintf("%d,0.01", data.records[2].at.UnixMilli()))
+	borrow := rateSeries(t, fmt.Sprintf("%d,525.6", data.records[0].at.UnixMilli()))
+	result, err := Run(context.Background(), RunConfig{
+		Selector: "threshold@a",
+		Module:   compileModule(t, thresholdModule("95", "104")),
+		Data:     data,
+		FeeBps:   decimal.NewFromInt(10),
+		Funding:  funding,
+		Borrow:   borrow,
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(result.FundingPayments) != 1 {
+		t.Fatalf("expected one funding payment, got %+v", result.FundingPayments)
+	}
+	payment := result.FundingPayments[0]
+	if !payment.Amount.Equal(decimal.RequireFromString("-0.95")) || !payment.Position.Equal(decimal.NewFromInt(1)) || !payment.Price.Equal(decimal.NewFromInt(95)) {
+		t.Fatalf("unexpected funding payment %+v", payment)
+	}
+	if !result.Summary.Funding.Equal(payment.Amount) {
+		t.Fatalf("funding = %s", result.Summary.Funding)
+	}
+	// 95.095 borrowed for the first minute, then 95.095 plus that interest and the funding paid.
+	wantBorrow := decimal.RequireFromString("0.095095").Add(decimal.RequireFromString("96.140095").Mul(decimal.RequireFromString("0.001")))
+	if !result.Summary.BorrowCost.Equal(wantBorrow) {
+		t.Fatalf("borrow cost = %s, want %s", result.Summary.BorrowCost, wantBorrow)
+	}
+	want := decimal.RequireFromString("9.8").Add(payment.Amount).Sub(wantBorrow)
+	if !result.Summary.PnL.Equal(want) {
+		t.Fatalf("PnL = %s, want %s", result.Summary.PnL, want)
+	}
+}
+
+func TestReadRateSeriesRejectsMalformedFiles(t *testing.T) {
+	series := rateSeries(t, "1756684800000,0.0001", "1756713600000,-0.0002")
+	if rate, ok := series.rateAt(series.points[1].Time.Add(-1)); !ok || !rate.Equal(decimal.RequireFromString("0.0001")) {
+		t.Fatalf("unexpected rate %s (ok=%v)", rate, ok)
+	}
+	if _, ok := series.rateAt(series.points[0].Time.Add(-1)); ok {
+		t.Fatal("expected no rate before the first entry")
+	}
+	for _, raw := range []string{
+		"",
+		"time,rate\n1,0.1\n",
+		"timestamp,rate\n",
+		"timestamp,rate\n1756684800000,abc\n",
+		"timestamp,rate\n1756713600000,0.1\n1756684800000,0.1\n",
+	} {
+		if _, err := ReadRateSeries(strings.NewReader(raw)); err == nil {
+			t.Fatalf("expected %q rejected", raw)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SignificanceLevel is the p-value below which a PnL difference is reported as significant.
+const SignificanceLevel = 0.05
+
+// Comparison sets two runs over the same dataset side by side.
+type Comparison struct {
+	Baseline  Summary `json:"baseline"`
+	Candidate Summary `json:"candidate"`
+	// PnLDiff and MaxDrawdownDiff are candidate minus baseline.
+	PnLDiff         decimal.Decimal `json:"pnlDiff"`
+	MaxDrawdownDiff decimal.Decimal `json:"maxDrawdownDiff"`
+	Curve           []CurvePoint    `json:"curve"`
+	// TradeDiffs lists the fills, paired by sequence, that differ between the runs.
+	TradeDiffs   []TradeDiff  `json:"tradeDiffs"`
+	Significance Significance `json:"significance"`
+}
+
+// CurvePoint pairs the PnL and drawdown of both runs after a replayed row.
+type CurvePoint struct {
+	Time              time.Time       `json:"time"`
+	Baseline          decimal.Decimal `json:"baseline"`
+	Candidate         decimal.Decimal `json:"candidate"`
+	BaselineDrawdown  decimal.Decimal `json:"baselineDrawdown"`
+	CandidateDrawdown decimal.Decimal `json:"candidateDrawdown"`
+}
+
+// TradeDiff pairs the n-th fill of each run; a side is nil when that run filled fewer times.
+type TradeDiff struct {
+	Seq       int   `json:"seq"`
+	Baseline  *Fill `json:"baseline,omitempty"`
+	Candidate *Fill `json:"candidate,omitempty"`
+	// RealizedPnLDiff is the candidate minus the baseline realized PnL of the pair.
+	RealizedPnLDiff decimal.Decimal `json:"realizedPnlDiff"`
+}
+
+// Significance is a paired t-test on the per-row PnL changes of the two runs. A small p-value
+// means the candidate's PnL path is unlikely to differ from the baseline's by chance alone.
+type Significance struct {
+	Samples     int     `json:"samples"`
+	MeanDiff    float64 `json:"meanDiff"`
+	StdDev      float64 `json:"stdDev"`
+	TStat       float64 `json:"tStat"`
+	PValue      float64 `json:"pValue"`
+	Significant bool    `json:"significant"`
+}
+
+// Compare reports how the candidate run differs from the baseline. Both must replay the same
+// dataset.
+func Compare(baseline, candidate Result) (Comparison, error) {
+	if len(baseline.Curve) != len(candidate.Curve) {
+		return Comparison{}, fmt.Errorf("backtest: runs replayed %d and %d rows", len(baseline.Curve), len(candidate.Curve))
+	}
+	curve := make([]CurvePoint, len(baseline.Curve))
+	diffs := make([]float64, len(baseline.Curve))
+	prevBase, prevCand := decimal.Zero, decimal.Zero
+	for i, base := range baseline.Curve {
+		cand := candidate.Curve[i]
+		if !base.Time.Equal(cand.Time) {
+			return Comparison{}, fmt.Errorf("backtest: runs diverge at row %d (%s vs %s)", i, base.Time, cand.Time)
+		}
+		curve[i] = CurvePoint{
+			Time:              base.Time,
+			Baseline:          base.PnL,
+			Candidate:         cand.PnL,
+			BaselineDrawdown:  base.Drawdown,
+			CandidateDrawdown: cand.Drawdown,
+		}
+		diffs[i] = cand.PnL.Sub(prevCand).Sub(base.PnL.Sub(prevBase)).InexactFloat64()
+		prevBase, prevCand = base.PnL, cand.PnL
+	}
+	return Comparison{
+		Baseline:        baseline.Summary,
+		Candidate:       candidate.Summary,
+		PnLDiff:         candidate.Summary.PnL.Sub(baseline.Summary.PnL),
+		MaxDrawdownDiff: candidate.Summary.MaxDrawdown.Sub(baseline.Summary.MaxDrawdown),
+		Curve:           curve,
+		TradeDiffs:      tradeDiffs(baseline.Fills, candidate.Fills),
+		Significance:    pairedTTest(diffs),
+	}, nil
+}
+
+func tradeDiffs(baseline, candidate []Fill) []TradeDiff {
+	var out []TradeDiff
+	for i := 0; i < max(len(baseline), len(candidate)); i++ {
+		diff := TradeDiff{Seq: i + 1, Baseline: nil, Candidate: nil, RealizedPnLDiff: decimal.Zero}
+		if i < len(baseline) {
+			diff.Baseline = &baseline[i]
+			diff.RealizedPnLDiff = diff.RealizedPnLDiff.Sub(baseline[i].RealizedPnL)
+		}
+		if i < len(candidate) {
+			diff.Candidate = &candidate[i]
+			diff.RealizedPnLDiff = diff.RealizedPnLDiff.Add(candidate[i].RealizedPnL)
+		}
+		if diff.Baseline != nil && diff.Candidate != nil && sameExecution(*diff.Baseline, *diff.Candidate) {
+			continue
+		}
+		out = append(out, diff)
+	}
+	return out
+}
+
+func sameExecution(a, b Fill) bool {
+	return a.Time.Equal(b.Time) && a.Side == b.Side && a.OrderType == b.OrderType &&
+		a.Price.Equal(b.Price) && a.Quantity.Equal(b.Quantity)
+}
+
+func pairedTTest(diffs []float64) Significance {
+	result := Significance{Samples: len(diffs), MeanDiff: 0, StdDev: 0, TStat: 0, PValue: 1, Significant: false}
+	n := float64(len(diffs))
+	if len(diffs) < 2 {
+		return result
+	}
+	var sum float64
+	for _, d := range diffs {
+		sum += d
+	}
+	mean := sum / n
+	var squares float64
+	for _, d := range diffs {
+		squares += (d - mean) * (d - mean)
+	}
+	result.MeanDiff = mean
+	result.StdDev = math.Sqrt(squares / (n - 1))
+	if result.StdDev == 0 {
+		// Identical paths, or a constant offset on every row; the t statistic is undefined.
+		if mean != 0 {
+			result.PValue = 0
+			result.Significant = true
+		}
+		return result
+	}
+	result.TStat = mean / (result.StdDev / math.Sqrt(n))
+	df := n - 1
+	result.PValue = regularizedIncompleteBeta(df/2, 0.5, df/(df+result.TStat*result.TStat))
+	result.Significant = result.PValue < SignificanceLevel
+	return result
+}
+
+// regularizedIncompleteBeta evaluates I_x(a, b) with the continued fraction of Numerical Recipes
+// §6.4. The two-sided p-value of a t statistic with df degrees of freedom is I_{df/(df+t²)}(df/2, ½).
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	lgab, _ := math.Lgamma(a + b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	qab, qap, qam := a+b, a+1, a-1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
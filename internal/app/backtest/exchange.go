@@ -0,0 +1,248 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+var basisPoints = decimal.NewFromInt(10000)
+
+// Fill is one simulated execution.
+type Fill struct {
+	// Seq numbers fills in execution order from 1.
+	Seq           int              `json:"seq"`
+	Time          time.Time        `json:"time"`
+	ClientOrderID string           `json:"clientOrderId"`
+	Side          schema.TradeSide `json:"side"`
+	OrderType     schema.OrderType `json:"orderType"`
+	Price         decimal.Decimal  `json:"price"`
+	Quantity      decimal.Decimal  `json:"quantity"`
+	Fee           decimal.Decimal  `json:"fee"`
+	// RealizedPnL is the profit locked in by the part of the fill that reduced the position, net of
+	// the fee.
+	RealizedPnL decimal.Decimal `json:"realizedPnl"`
+}
+
+// restingOrder is a limit order waiting for the market to trade through its price.
+type restingOrder struct {
+	req   schema.OrderRequest
+	id    string
+	price decimal.Decimal
+	qty   decimal.Decimal
+}
+
+// exchange is the simulated venue a backtest lambda submits orders to. Market orders fill at the
+// last replayed price; limit orders fill at their price once the market trades through it. Fills
+// are whole and pay a flat fee on notional. The open position settles funding at the times of the
+// funding series and pays interest on what it borrows at the borrow series rate. Execution reports
+// are queued and delivered by the runner after the handler that placed the order returns, since
+// handlers run on the strategy VM.
+type exchange struct {
+	mu       sync.Mutex
+	feeRate  decimal.Decimal
+	now      time.Time
+	last     decimal.Decimal
+	nextID   int
+	resting  []restingOrder
+	pending  []schema.ExecReportPayload
+	fills    []Fill
+	position decimal.Decimal
+	avgCost  decimal.Decimal
+	cash     decimal.Decimal
+	volume   decimal.Decimal
+	fees     decimal.Decimal
+
+	funding     *RateSeries
+	borrow      *RateSeries
+	nextFunding int
+	fundingNet  decimal.Decimal
+	borrowCost  decimal.Decimal
+	payments    []FundingPayment
+}
+
+func newExchange(feeBps decimal.Decimal, funding, borrow *RateSeries) *exchange {
+	return &exchange{
+		mu:       sync.Mutex{},
+		feeRate:  feeBps.Div(basisPoints),
+		now:      time.Time{},
+		last:     decimal.Zero,
+		nextID:   0,
+		resting:  nil,
+		pending:  nil,
+		fills:    nil,
+		position: decimal.Zero,
+		avgCost:  decimal.Zero,
+		cash:     decimal.Zero,
+		volume:   decimal.Zero,
+		fees:     decimal.Zero,
+
+		funding:     funding,
+		borrow:      borrow,
+		nextFunding: 0,
+		fundingNet:  decimal.Zero,
+		borrowCost:  decimal.Zero,
+		payments:    nil,
+	}
+}
+
+// SubmitOrder accepts an order from the lambda.
+func (e *exchange) SubmitOrder(_ context.Context, req schema.OrderRequest) error {
+	qty, err := decimal.NewFromString(strings.TrimSpace(req.Quantity))
+	if err != nil || !qty.IsPositive() {
+		return fmt.Errorf("backtest: invalid quantity %q", req.Quantity)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.last.IsZero() {
+		return fmt.Errorf("backtest: no market price before first event")
+	}
+	var price decimal.Decimal
+	market := req.OrderType == schema.OrderTypeMarket || req.Price == nil
+	if !market {
+		price, err = decimal.NewFromString(strings.TrimSpace(*req.Price))
+		if err != nil || !price.IsPositive() {
+			return fmt.Errorf("backtest: invalid price %q", *req.Price)
+		}
+	}
+	e.nextID++
+	id := "backtest-" + strconv.Itoa(e.nextID)
+	e.report(req, id, schema.ExecReportStateACK, decimal.Zero, decimal.Zero, decimal.Zero)
+
+	// Market and marketable limit orders take the prevailing price.
+	if market || crosses(req.Side, price, e.last, e.last) {
+		e.fill(req, id, e.last, qty)
+		return nil
+	}
+	e.resting = append(e.resting, restingOrder{req: req, id: id, price: price, qty: qty})
+	return nil
+}
+
+// crosses reports whether a limit order at price trades against a market that ranged over
+// [low, high].
+func crosses(side schema.TradeSide, price, low, high decimal.Decimal) bool {
+	if side == schema.TradeSideSell {
+		return high.GreaterThanOrEqual(price)
+	}
+	return low.LessThanOrEqual(price)
+}
+
+// advance books the carry up to the row, fills resting orders the row traded through, then moves
+// the market to the row.
+func (e *exchange) advance(rec record) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.accrue(rec.at)
+	e.now = rec.at
+	remaining := e.resting[:0]
+	for _, order := range e.resting {
+		if crosses(order.req.Side, order.price, rec.low, rec.high) {
+			e.fill(order.req, order.id, order.price, order.qty)
+			continue
+		}
+		remaining = append(remaining, order)
+	}
+	e.resting = remaining
+	e.last = rec.last
+}
+
+// fill books an execution and queues its report. Callers hold e.mu.
+func (e *exchange) fill(req schema.OrderRequest, id string, price, qty decimal.Decimal) {
+	notional := price.Mul(qty)
+	fee := notional.Mul(e.feeRate)
+	signed := qty
+	if req.Side == schema.TradeSideSell {
+		signed = qty.Neg()
+	}
+
+	realized := fee.Neg()
+	if !e.position.IsZero() && e.position.Sign() != signed.Sign() {
+		closed := decimal.Min(qty, e.position.Abs())
+		pnl := price.Sub(e.avgCost).Mul(closed)
+		if e.position.IsNegative() {
+			pnl = pnl.Neg()
+		}
+		realized = realized.Add(pnl)
+	}
+	next := e.position.Add(signed)
+	switch {
+	case next.IsZero():
+		e.avgCost = decimal.Zero
+	case e.position.IsZero() || e.position.Sign() != next.Sign():
+		// Opened, or flipped through flat: the remainder was entered at this price.
+		e.avgCost = price
+	case e.position.Sign() == signed.Sign():
+		e.avgCost = e.avgCost.Mul(e.position.Abs()).Add(notional).Div(next.Abs())
+	}
+	e.position = next
+	e.cash = e.cash.Sub(signed.Mul(price)).Sub(fee)
+	e.volume = e.volume.Add(notional)
+	e.fees = e.fees.Add(fee)
+
+	e.fills = append(e.fills, Fill{
+		Seq:           len(e.fills) + 1,
+		Time:          e.now,
+		ClientOrderID: req.ClientOrderID,
+		Side:          req.Side,
+		OrderType:     req.OrderType,
+		Price:         price,
+		Quantity:      qty,
+		Fee:           fee,
+		RealizedPnL:   realized,
+	})
+	e.report(req, id, schema.ExecReportStateFILLED, qty, price, fee)
+}
+
+// report queues an execution report. Callers hold e.mu.
+func (e *exchange) report(req schema.OrderRequest, id string, state schema.ExecReportState, filled, avgPrice, fee decimal.Decimal) {
+	price := ""
+	if req.Price != nil {
+		price = *req.Price
+	}
+	qty, _ := decimal.NewFromString(req.Quantity)
+	payload := schema.ExecReportPayload{
+		ClientOrderID:    req.ClientOrderID,
+		ExchangeOrderID:  id,
+		State:            state,
+		Side:             req.Side,
+		OrderType:        req.OrderType,
+		Price:            price,
+		Quantity:         req.Quantity,
+		FilledQuantity:   filled.String(),
+		RemainingQty:     qty.Sub(filled).String(),
+		AvgFillPrice:     "",
+		CommissionAmount: "",
+		CommissionAsset:  "",
+		SubAccount:       req.SubAccount,
+		Timestamp:        e.now,
+		RejectReason:     nil,
+	}
+	if filled.IsPositive() {
+		payload.AvgFillPrice = avgPrice.String()
+		payload.CommissionAmount = fee.String()
+	}
+	e.pending = append(e.pending, payload)
+}
+
+// drain returns and clears the queued execution reports.
+func (e *exchange) drain() []schema.ExecReportPayload {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := e.pending
+	e.pending = nil
+	return out
+}
+
+// pnl marks the book to the last replayed price.
+func (e *exchange) pnl() decimal.Decimal {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cash.Add(e.position.Mul(e.last))
+}
@@ -0,0 +1,244 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+const (
+	// DefaultSeed seeds Math.random when the strategy config carries no seed, so compared revisions
+	// draw the same numbers.
+	DefaultSeed int64 = 1
+
+	lambdaID      = "backtest"
+	eventPoolSize = 256
+	orderPoolSize = 64
+	// maxSettleRounds bounds the execution report rounds delivered after one market event, so a
+	// strategy that answers every fill with a new order cannot stall the replay.
+	maxSettleRounds = 1000
+)
+
+// ErrUnsettled indicates that a strategy kept placing orders in response to its own fills.
+var ErrUnsettled = errors.New("strategy did not settle")
+
+// RunConfig describes one backtest.
+type RunConfig struct {
+	// Selector names the revision, e.g. grid@<hash>; it is echoed in the result.
+	Selector string
+	Module   *js.Module
+	// Config is the strategy config. A missing seed is set to DefaultSeed.
+	Config map[string]any
+	Data   *Dataset
+	// FeeBps is charged on the notional of every fill.
+	FeeBps decimal.Decimal
+	// Funding holds the funding rates of a perpetual. At each of its times the open position pays
+	// position × last price × rate, so longs pay positive rates and shorts receive them.
+	Funding *RateSeries
+	// Borrow holds annualised borrow rates of a margin account. Interest accrues between rows on
+	// the quote currency financing a long and on the base currency sold short.
+	Borrow *RateSeries
+	Logger *log.Logger
+}
+
+// EquityPoint is the marked-to-market PnL after a replayed row.
+type EquityPoint struct {
+	Time     time.Time       `json:"time"`
+	PnL      decimal.Decimal `json:"pnl"`
+	Drawdown decimal.Decimal `json:"drawdown"`
+}
+
+// Summary holds the headline statistics of a run.
+type Summary struct {
+	Selector string          `json:"selector"`
+	Events   int             `json:"events"`
+	Fills    int             `json:"fills"`
+	Volume   decimal.Decimal `json:"volume"`
+	Fees     decimal.Decimal `json:"fees"`
+	// Funding is the net funding received; negative when the position paid.
+	Funding    decimal.Decimal `json:"funding"`
+	BorrowCost decimal.Decimal `json:"borrowCost"`
+	// PnL is net of fees, funding and borrow costs.
+	PnL         decimal.Decimal `json:"pnl"`
+	MaxDrawdown decimal.Decimal `json:"maxDrawdown"`
+	Position    decimal.Decimal `json:"position"`
+	// Faults counts handler exceptions skipped during the run.
+	Faults int64 `json:"faults"`
+}
+
+// Result is the outcome of a backtest.
+type Result struct {
+	Summary         Summary          `json:"summary"`
+	Fills           []Fill           `json:"fills"`
+	FundingPayments []FundingPayment `json:"fundingPayments"`
+	Curve           []EquityPoint    `json:"curve"`
+}
+
+// Run replays the dataset through the strategy against a simulated venue. Events are handled one at
+// a time on the calling goroutine and the strategy clock follows the replayed timestamps, so a
+// revision run twice over the same data with the same seed produces the same fills.
+func Run(ctx context.Context, cfg RunConfig) (Result, error) {
+	if cfg.Module == nil {
+		return Result{}, fmt.Errorf("backtest: strategy module required")
+	}
+	if cfg.Data.Len() == 0 {
+		return Result{}, fmt.Errorf("backtest: %w", ErrEmptyDataset)
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	strategyCfg := make(map[string]any, len(cfg.Config)+1)
+	for key, value := range cfg.Config {
+		strategyCfg[key] = value
+	}
+	if _, ok := js.SeedFromConfig(strategyCfg); !ok {
+		strategyCfg[js.SeedConfigKey] = DefaultSeed
+	}
+
+	pools := pool.NewPoolManager()
+	defer func() {
+		_ = pools.Shutdown(context.Background())
+	}()
+	if err := pools.RegisterPool("Event", eventPoolSize, 0, func() any { return new(schema.Event) }); err != nil {
+		return Result{}, fmt.Errorf("backtest: register event pool: %w", err)
+	}
+	if err := pools.RegisterPool("OrderRequest", orderPoolSize, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		return Result{}, fmt.Errorf("backtest: register order pool: %w", err)
+	}
+
+	strategy, err := js.NewStrategy(cfg.Module, strategyCfg, logger)
+	if err != nil {
+		return Result{}, fmt.Errorf("backtest: %w", err)
+	}
+	defer strategy.Close()
+
+	data := cfg.Data
+	venue := newExchange(cfg.FeeBps, cfg.Funding, cfg.Borrow)
+	lambda := core.NewBaseLambda(lambdaID, core.Config{
+		Providers:           []string{data.Provider},
+		ProviderSymbols:     map[string][]string{data.Provider: {data.Symbol}},
+		DryRun:              false,
+		Delivery:            core.DeliveryConfig{},
+		DurableSubscription: false,
+		SubAccounts:         nil,
+		History:             core.HistoryConfig{},
+		Orders:              core.OrderHistoryConfig{},
+		ThrottleQueue:       core.ThrottleQueueConfig{Enabled: false, MaxDepth: 0, Expiry: 0},
+	}, nil, venue, pools, strategy, nil, nil)
+	lambda.SetLogger(logger)
+	strategy.Attach(lambda)
+	lambda.EnableTrading(true)
+
+	r := &runner{ctx: ctx, data: data, pools: pools, lambda: lambda, venue: venue, seq: 0}
+	curve := make([]EquityPoint, 0, data.Len())
+	peak := decimal.Zero
+	maxDrawdown := decimal.Zero
+	for i, rec := range data.records {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+		venue.advance(rec)
+		if err := r.settle(); err != nil {
+			return Result{}, err
+		}
+		if err := r.deliver(rec.typ, fmt.Sprintf("backtest:%d", i), rec.at, rec.payload); err != nil {
+			return Result{}, err
+		}
+		if err := r.settle(); err != nil {
+			return Result{}, err
+		}
+		pnl := venue.pnl()
+		if i == 0 || pnl.GreaterThan(peak) {
+			peak = pnl
+		}
+		drawdown := peak.Sub(pnl)
+		maxDrawdown = decimal.Max(maxDrawdown, drawdown)
+		curve = append(curve, EquityPoint{Time: rec.at, PnL: pnl, Drawdown: drawdown})
+	}
+
+	venue.mu.Lock()
+	defer venue.mu.Unlock()
+	return Result{
+		Summary: Summary{
+			Selector:    cfg.Selector,
+			Events:      data.Len(),
+			Fills:       len(venue.fills),
+			Volume:      venue.volume,
+			Fees:        venue.fees,
+			Funding:     venue.fundingNet,
+			BorrowCost:  venue.borrowCost,
+			PnL:         curve[len(curve)-1].PnL,
+			MaxDrawdown: maxDrawdown,
+			Position:    venue.position,
+			Faults:      faultCount(strategy.HandlerFaults()),
+		},
+		Fills:           append([]Fill(nil), venue.fills...),
+		FundingPayments: append([]FundingPayment(nil), venue.payments...),
+		Curve:           curve,
+	}, nil
+}
+
+func faultCount(faults []js.HandlerFault) int64 {
+	var total int64
+	for _, fault := range faults {
+		total += fault.Count
+	}
+	return total
+}
+
+// runner feeds events to the lambda.
+type runner struct {
+	ctx    context.Context
+	data   *Dataset
+	pools  *pool.PoolManager
+	lambda *core.BaseLambda
+	venue  *exchange
+	seq    int
+}
+
+// deliver hands one event to the lambda and waits for the strategy to handle it.
+func (r *runner) deliver(typ schema.EventType, id string, at time.Time, payload any) error {
+	evt, err := r.pools.BorrowEventInst(r.ctx)
+	if err != nil {
+		return fmt.Errorf("backtest: borrow event: %w", err)
+	}
+	r.seq++
+	evt.EventID = id
+	evt.Provider = r.data.Provider
+	evt.Symbol = r.data.Symbol
+	evt.Type = typ
+	evt.SeqProvider = uint64(r.seq) // #nosec G115 -- sequence is positive
+	evt.IngestTS = at
+	evt.EmitTS = at
+	evt.Payload = payload
+	r.lambda.Replay(r.ctx, evt)
+	return nil
+}
+
+// settle delivers queued execution reports until the strategy stops placing orders.
+func (r *runner) settle() error {
+	for round := 0; round < maxSettleRounds; round++ {
+		reports := r.venue.drain()
+		if len(reports) == 0 {
+			return nil
+		}
+		for _, report := range reports {
+			id := fmt.Sprintf("backtest:%s:%s", report.ClientOrderID, report.State)
+			if err := r.deliver(schema.EventTypeExecReport, id, report.Timestamp, report); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("backtest: %w after %d execution report rounds", ErrUnsettled, maxSettleRounds)
+}
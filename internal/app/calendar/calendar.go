@@ -0,0 +1,564 @@
+// Package calendar schedules operator actions — risk posture changes, trading halts and provider
+// maintenance windows — and applies them automatically when they fall due, keeping an audit trail
+// and raising advance notifications.
+package calendar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/google/uuid"
+
+	"github.com/coachpo/meltica/internal/domain/calendarstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// ActionKind identifies what a calendar entry does when it falls due.
+type ActionKind string
+
+const (
+	// ActionApplyRiskProfile applies a named risk profile globally or to the listed instances.
+	ActionApplyRiskProfile ActionKind = "apply-risk-profile"
+	// ActionHaltTrading engages the kill switch.
+	ActionHaltTrading ActionKind = "halt-trading"
+	// ActionResumeTrading releases the kill switch.
+	ActionResumeTrading ActionKind = "resume-trading"
+	// ActionStopProvider stops a provider, e.g. for planned exchange maintenance.
+	ActionStopProvider ActionKind = "stop-provider"
+	// ActionStartProvider starts a stopped provider.
+	ActionStartProvider ActionKind = "start-provider"
+)
+
+// Status reports where an entry is in its lifecycle. Statuses double as audit stages.
+type Status string
+
+const (
+	// StatusScheduled marks an entry waiting for its time.
+	StatusScheduled Status = "scheduled"
+	// StatusNotified marks an entry whose advance notification has been raised.
+	StatusNotified Status = "notified"
+	// StatusApplied marks an entry whose action succeeded.
+	StatusApplied Status = "applied"
+	// StatusFailed marks an entry whose action returned an error.
+	StatusFailed Status = "failed"
+	// StatusMissed marks an entry that fell due more than the missed grace ago, e.g. while the
+	// gateway was down, and was therefore not applied.
+	StatusMissed Status = "missed"
+	// StatusCancelled marks an entry cancelled by an operator.
+	StatusCancelled Status = "cancelled"
+)
+
+var (
+	// ErrEntryNotFound indicates the calendar entry does not exist.
+	ErrEntryNotFound = errors.New("calendar entry not found")
+	// ErrEntryClosed indicates the entry has already been applied, failed, missed or cancelled.
+	ErrEntryClosed = errors.New("calendar entry already closed")
+)
+
+// Action describes the change applied when an entry falls due.
+type Action struct {
+	Kind      ActionKind `json:"kind"`
+	Profile   string     `json:"profile,omitempty"`
+	Instances []string   `json:"instances,omitempty"`
+	Provider  string     `json:"provider,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	// RequestedBy is the verified principal that scheduled the action; approvals it needs are
+	// requested on their behalf.
+	RequestedBy string `json:"requestedBy,omitempty"`
+}
+
+// Validate checks that the action carries the fields its kind requires.
+func (a Action) Validate() error {
+	switch a.Kind {
+	case ActionApplyRiskProfile:
+		if strings.TrimSpace(a.Profile) == "" {
+			return fmt.Errorf("%s: profile required", a.Kind)
+		}
+	case ActionStopProvider, ActionStartProvider:
+		if strings.TrimSpace(a.Provider) == "" {
+			return fmt.Errorf("%s: provider required", a.Kind)
+		}
+	case ActionHaltTrading, ActionResumeTrading:
+	case "":
+		return fmt.Errorf("action kind required")
+	default:
+		return fmt.Errorf("unsupported action kind %q", a.Kind)
+	}
+	return nil
+}
+
+// AuditRecord captures one lifecycle transition of an entry.
+type AuditRecord struct {
+	Time    time.Time `json:"time"`
+	Stage   Status    `json:"stage"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Entry is a scheduled action and its audit trail.
+type Entry struct {
+	ID         string        `json:"id"`
+	Title      string        `json:"title"`
+	At         time.Time     `json:"at"`
+	NotifyAt   *time.Time    `json:"notifyAt,omitempty"`
+	Action     Action        `json:"action"`
+	Status     Status        `json:"status"`
+	NotifiedAt *time.Time    `json:"notifiedAt,omitempty"`
+	AppliedAt  *time.Time    `json:"appliedAt,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Audit      []AuditRecord `json:"audit"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+// Open reports whether the entry is still waiting to be applied.
+func (e Entry) Open() bool {
+	return e.Status == StatusScheduled || e.Status == StatusNotified
+}
+
+func (e Entry) clone() Entry {
+	out := e
+	out.Action.Instances = append([]string(nil), e.Action.Instances...)
+	out.Audit = append([]AuditRecord(nil), e.Audit...)
+	return out
+}
+
+// Request schedules a new entry. NotifyBefore overrides the configured lead time of the advance
+// notification; zero disables it.
+type Request struct {
+	Title        string
+	At           time.Time
+	NotifyBefore *time.Duration
+	Action       Action
+}
+
+// Notice is published when an entry is scheduled, comes up, is applied, fails, is missed or is
+// cancelled.
+type Notice struct {
+	Type  string `json:"type"`
+	Stage Status `json:"stage"`
+	Entry Entry  `json:"entry"`
+}
+
+// NoticeType tags calendar notices among extension event payloads.
+const NoticeType = "calendar"
+
+// Executor applies entry actions.
+type Executor interface {
+	Execute(ctx context.Context, action Action) (string, error)
+}
+
+// Notifier delivers calendar notices.
+type Notifier func(ctx context.Context, notice Notice)
+
+// Option configures a Calendar.
+type Option func(*Calendar)
+
+// WithStore persists entries so schedules survive restarts.
+func WithStore(store calendarstore.Store) Option {
+	return func(c *Calendar) {
+		c.store = store
+	}
+}
+
+// WithNotifier delivers notices, typically onto the event bus.
+func WithNotifier(notify Notifier) Option {
+	return func(c *Calendar) {
+		c.notify = notify
+	}
+}
+
+// WithClock overrides the time source.
+func WithClock(clock func() time.Time) Option {
+	return func(c *Calendar) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithLogger overrides the calendar logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Calendar) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// Calendar holds scheduled entries and applies them when due.
+type Calendar struct {
+	cfg    config.CalendarConfig
+	exec   Executor
+	store  calendarstore.Store
+	notify Notifier
+	clock  func() time.Time
+	logger *log.Logger
+
+	mu      sync.Mutex
+	entries map[string]*Entry
+}
+
+// New constructs a calendar applying actions through exec.
+func New(cfg config.CalendarConfig, exec Executor, opts ...Option) *Calendar {
+	c := &Calendar{
+		cfg:     cfg,
+		exec:    exec,
+		store:   nil,
+		notify:  nil,
+		clock:   time.Now,
+		logger:  log.New(redact.Stdout, "calendar ", log.LstdFlags|log.Lmicroseconds),
+		mu:      sync.Mutex{},
+		entries: make(map[string]*Entry),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	return c
+}
+
+// Load hydrates entries from the store.
+func (c *Calendar) Load(ctx context.Context) error {
+	if c.store == nil {
+		return nil
+	}
+	stored, err := c.store.LoadEntries(ctx)
+	if err != nil {
+		return fmt.Errorf("load calendar entries: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, record := range stored {
+		entry, err := entryFromRecord(record)
+		if err != nil {
+			c.logger.Printf("calendar entry %s: decode failed: %v", record.ID, err)
+			continue
+		}
+		c.entries[entry.ID] = &entry
+	}
+	return nil
+}
+
+// Schedule validates and stores a new entry.
+func (c *Calendar) Schedule(ctx context.Context, req Request) (Entry, error) {
+	req.Action.Profile = strings.TrimSpace(req.Action.Profile)
+	req.Action.Provider = strings.TrimSpace(req.Action.Provider)
+	req.Action.Reason = strings.TrimSpace(req.Action.Reason)
+	if err := req.Action.Validate(); err != nil {
+		return Entry{}, err
+	}
+	now := c.clock().UTC()
+	if req.At.IsZero() {
+		return Entry{}, fmt.Errorf("at required")
+	}
+	at := req.At.UTC()
+	if !at.After(now) {
+		return Entry{}, fmt.Errorf("at must be in the future")
+	}
+	lead := c.cfg.NotifyBefore
+	if req.NotifyBefore != nil {
+		lead = *req.NotifyBefore
+	}
+	if lead < 0 {
+		return Entry{}, fmt.Errorf("notifyBefore must be >= 0")
+	}
+	entry := Entry{
+		ID:         uuid.NewString(),
+		Title:      strings.TrimSpace(req.Title),
+		At:         at,
+		NotifyAt:   nil,
+		Action:     req.Action,
+		Status:     StatusScheduled,
+		NotifiedAt: nil,
+		AppliedAt:  nil,
+		Error:      "",
+		Audit:      []AuditRecord{{Time: now, Stage: StatusScheduled, Message: ""}},
+		CreatedAt:  now,
+	}
+	if entry.Title == "" {
+		entry.Title = string(req.Action.Kind)
+	}
+	if lead > 0 {
+		notifyAt := at.Add(-lead)
+		entry.NotifyAt = &notifyAt
+	}
+	if err := c.persist(ctx, entry); err != nil {
+		return Entry{}, err
+	}
+	c.mu.Lock()
+	c.entries[entry.ID] = &entry
+	snapshot := entry.clone()
+	c.mu.Unlock()
+
+	c.logger.Printf("calendar entry %s scheduled: %s at %s", entry.ID, entry.Action.Kind, entry.At.Format(time.RFC3339))
+	c.publish(ctx, StatusScheduled, snapshot)
+	return snapshot, nil
+}
+
+// Cancel cancels an open entry.
+func (c *Calendar) Cancel(ctx context.Context, id, reason string) (Entry, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[strings.TrimSpace(id)]
+	if !ok {
+		c.mu.Unlock()
+		return Entry{}, ErrEntryNotFound
+	}
+	if !entry.Open() {
+		c.mu.Unlock()
+		return Entry{}, fmt.Errorf("%w: %s is %s", ErrEntryClosed, entry.ID, entry.Status)
+	}
+	updated := entry.clone()
+	updated.Status = StatusCancelled
+	updated.Audit = append(updated.Audit, AuditRecord{Time: c.clock().UTC(), Stage: StatusCancelled, Message: strings.TrimSpace(reason)})
+	c.mu.Unlock()
+
+	if err := c.persist(ctx, updated); err != nil {
+		return Entry{}, err
+	}
+	c.mu.Lock()
+	if !entry.Open() {
+		status := entry.Status
+		c.mu.Unlock()
+		return Entry{}, fmt.Errorf("%w: %s is %s", ErrEntryClosed, updated.ID, status)
+	}
+	*entry = updated
+	c.mu.Unlock()
+	c.logger.Printf("calendar entry %s cancelled", updated.ID)
+	c.publish(ctx, StatusCancelled, updated)
+	return updated.clone(), nil
+}
+
+// Entries lists entries ordered by scheduled time. Closed entries are included when all is set.
+func (c *Calendar) Entries(all bool) []Entry {
+	c.mu.Lock()
+	out := make([]Entry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		if all || entry.Open() {
+			out = append(out, entry.clone())
+		}
+	}
+	c.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].At.Equal(out[j].At) {
+			return out[i].ID < out[j].ID
+		}
+		return out[i].At.Before(out[j].At)
+	})
+	return out
+}
+
+// Entry returns the entry with the given id.
+func (c *Calendar) Entry(id string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[strings.TrimSpace(id)]
+	if !ok {
+		return Entry{}, false
+	}
+	return entry.clone(), true
+}
+
+// Run evaluates entries every check interval until ctx is cancelled.
+func (c *Calendar) Run(ctx context.Context) {
+	interval := c.cfg.CheckInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	c.Check(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check(ctx)
+		}
+	}
+}
+
+// Check raises due notifications and applies due entries in scheduled order.
+func (c *Calendar) Check(ctx context.Context) {
+	now := c.clock().UTC()
+	var notify, apply []string
+	c.mu.Lock()
+	for id, entry := range c.entries {
+		switch {
+		case !entry.Open():
+		case !now.Before(entry.At):
+			apply = append(apply, id)
+		case entry.Status == StatusScheduled && entry.NotifyAt != nil && !now.Before(*entry.NotifyAt):
+			notify = append(notify, id)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, id := range c.sortByTime(notify) {
+		c.transition(ctx, id, func(entry *Entry) Status {
+			entry.NotifiedAt = &now
+			return StatusNotified
+		}, fmt.Sprintf("due at %s", now.Format(time.RFC3339)))
+	}
+	for _, id := range c.sortByTime(apply) {
+		c.apply(ctx, id, now)
+	}
+}
+
+func (c *Calendar) apply(ctx context.Context, id string, now time.Time) {
+	entry, ok := c.Entry(id)
+	if !ok || !entry.Open() {
+		return
+	}
+	grace := c.cfg.MissedGrace
+	if grace > 0 && now.Sub(entry.At) > grace {
+		c.transition(ctx, id, func(*Entry) Status { return StatusMissed },
+			fmt.Sprintf("%s late; exceeds missed grace %s", now.Sub(entry.At).Truncate(time.Second), grace))
+		return
+	}
+	if c.exec == nil {
+		c.transition(ctx, id, func(e *Entry) Status {
+			e.Error = "executor unavailable"
+			return StatusFailed
+		}, "executor unavailable")
+		return
+	}
+	summary, err := c.exec.Execute(ctx, entry.Action)
+	if err != nil {
+		c.transition(ctx, id, func(e *Entry) Status {
+			e.Error = redact.Error(err)
+			return StatusFailed
+		}, redact.Error(err))
+		return
+	}
+	c.transition(ctx, id, func(e *Entry) Status {
+		e.AppliedAt = &now
+		return StatusApplied
+	}, summary)
+}
+
+// transition moves an open entry to the status returned by mutate, records the audit line,
+// persists it and publishes a notice.
+func (c *Calendar) transition(ctx context.Context, id string, mutate func(*Entry) Status, message string) {
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	if !ok || !entry.Open() {
+		c.mu.Unlock()
+		return
+	}
+	status := mutate(entry)
+	entry.Status = status
+	entry.Audit = append(entry.Audit, AuditRecord{Time: c.clock().UTC(), Stage: status, Message: message})
+	snapshot := entry.clone()
+	c.mu.Unlock()
+
+	if err := c.persist(ctx, snapshot); err != nil {
+		c.logger.Printf("calendar entry %s: %v", id, err)
+	}
+	if message != "" {
+		c.logger.Printf("calendar entry %s %s: %s", id, status, message)
+	} else {
+		c.logger.Printf("calendar entry %s %s", id, status)
+	}
+	c.publish(ctx, status, snapshot)
+}
+
+func (c *Calendar) sortByTime(ids []string) []string {
+	if len(ids) < 2 {
+		return ids
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := c.entries[ids[i]], c.entries[ids[j]]
+		if a.At.Equal(b.At) {
+			return a.ID < b.ID
+		}
+		return a.At.Before(b.At)
+	})
+	return ids
+}
+
+func (c *Calendar) publish(ctx context.Context, stage Status, entry Entry) {
+	if c.notify == nil {
+		return
+	}
+	c.notify(ctx, Notice{Type: NoticeType, Stage: stage, Entry: entry})
+}
+
+func (c *Calendar) persist(ctx context.Context, entry Entry) error {
+	if c.store == nil {
+		return nil
+	}
+	record, err := recordFromEntry(entry)
+	if err != nil {
+		return err
+	}
+	if err := c.store.SaveEntry(ctx, record); err != nil {
+		return fmt.Errorf("persist calendar entry: %w", err)
+	}
+	return nil
+}
+
+func recordFromEntry(entry Entry) (calendarstore.Entry, error) {
+	raw, err := json.Marshal(entry.Action)
+	if err != nil {
+		return calendarstore.Entry{}, fmt.Errorf("encode calendar action: %w", err)
+	}
+	var action map[string]any
+	if err := json.Unmarshal(raw, &action); err != nil {
+		return calendarstore.Entry{}, fmt.Errorf("encode calendar action: %w", err)
+	}
+	audit := make([]calendarstore.AuditRecord, 0, len(entry.Audit))
+	for _, rec := range entry.Audit {
+		audit = append(audit, calendarstore.AuditRecord{Time: rec.Time, Stage: string(rec.Stage), Message: rec.Message})
+	}
+	return calendarstore.Entry{
+		ID:          entry.ID,
+		Title:       entry.Title,
+		ScheduledAt: entry.At,
+		NotifyAt:    entry.NotifyAt,
+		Action:      action,
+		Status:      string(entry.Status),
+		NotifiedAt:  entry.NotifiedAt,
+		AppliedAt:   entry.AppliedAt,
+		Error:       entry.Error,
+		Audit:       audit,
+		CreatedAt:   entry.CreatedAt,
+	}, nil
+}
+
+func entryFromRecord(record calendarstore.Entry) (Entry, error) {
+	var action Action
+	raw, err := json.Marshal(record.Action)
+	if err != nil {
+		return Entry{}, fmt.Errorf("decode calendar action: %w", err)
+	}
+	if err := json.Unmarshal(raw, &action); err != nil {
+		return Entry{}, fmt.Errorf("decode calendar action: %w", err)
+	}
+	audit := make([]AuditRecord, 0, len(record.Audit))
+	for _, rec := range record.Audit {
+		audit = append(audit, AuditRecord{Time: rec.Time, Stage: Status(rec.Stage), Message: rec.Message})
+	}
+	return Entry{
+		ID:         record.ID,
+		Title:      record.Title,
+		At:         record.ScheduledAt.UTC(),
+		NotifyAt:   record.NotifyAt,
+		Action:     action,
+		Status:     Status(record.Status),
+		NotifiedAt: record.NotifiedAt,
+		AppliedAt:  record.AppliedAt,
+		Error:      record.Error,
+		Audit:      audit,
+		CreatedAt:  record.CreatedAt,
+	}, nil
+}
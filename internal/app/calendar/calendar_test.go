@@ -0,0 +1,204 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/calendarstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type recordingExecutor struct {
+	actions []Action
+	err     error
+}
+
+func (r *recordingExecutor) Execute(_ context.Context, action Action) (string, error) {
+	r.actions = append(r.actions, action)
+	if r.err != nil {
+		return "", r.err
+	}
+	return "done", nil
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string]calendarstore.Entry
+}
+
+func (m *memoryStore) SaveEntry(_ context.Context, entry calendarstore.Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[entry.ID] = entry
+	return nil
+}
+
+func (m *memoryStore) LoadEntries(context.Context) ([]calendarstore.Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]calendarstore.Entry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		out = append(out, entry)
+	}
+	return out, nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+func newTestCalendar(exec Executor, clock *fakeClock, opts ...Option) (*Calendar, *[]Notice) {
+	var notices []Notice
+	cfg := config.CalendarConfig{CheckInterval: time.Second, NotifyBefore: 10 * time.Minute, MissedGrace: time.Minute}
+	opts = append([]Option{
+		WithClock(clock.Now),
+		WithLogger(log.New(io.Discard, "", 0)),
+		WithNotifier(func(_ context.Context, notice Notice) { notices = append(notices, notice) }),
+	}, opts...)
+	return New(cfg, exec, opts...), &notices
+}
+
+func TestCalendarNotifiesThenApplies(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 18, 17, 0, 0, 0, time.UTC)}
+	exec := &recordingExecutor{}
+	cal, notices := newTestCalendar(exec, clock)
+	ctx := context.Background()
+
+	entry, err := cal.Schedule(ctx, Request{
+		Title:  "FOMC",
+		At:     clock.now.Add(time.Hour),
+		Action: Action{Kind: ActionApplyRiskProfile, Profile: "conservative"},
+	})
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if entry.NotifyAt == nil || !entry.NotifyAt.Equal(clock.now.Add(50*time.Minute)) {
+		t.Fatalf("unexpected notifyAt %v", entry.NotifyAt)
+	}
+
+	clock.now = clock.now.Add(30 * time.Minute)
+	cal.Check(ctx)
+	if got, _ := cal.Entry(entry.ID); got.Status != StatusScheduled {
+		t.Fatalf("status before lead time = %s", got.Status)
+	}
+
+	clock.now = clock.now.Add(25 * time.Minute)
+	cal.Check(ctx)
+	if got, _ := cal.Entry(entry.ID); got.Status != StatusNotified || got.NotifiedAt == nil {
+		t.Fatalf("expected notified entry, got %+v", got)
+	}
+	if len(exec.actions) != 0 {
+		t.Fatalf("action applied before due time")
+	}
+
+	clock.now = clock.now.Add(5 * time.Minute)
+	cal.Check(ctx)
+	got, _ := cal.Entry(entry.ID)
+	if got.Status != StatusApplied || got.AppliedAt == nil {
+		t.Fatalf("expected applied entry, got %+v", got)
+	}
+	if len(exec.actions) != 1 || exec.actions[0].Profile != "conservative" {
+		t.Fatalf("unexpected actions %+v", exec.actions)
+	}
+	stages := make([]Status, 0, len(got.Audit))
+	for _, rec := range got.Audit {
+		stages = append(stages, rec.Stage)
+	}
+	want := []Status{StatusScheduled, StatusNotified, StatusApplied}
+	if len(stages) != len(want) || len(*notices) != len(want) {
+		t.Fatalf("audit stages %v, notices %d; want %v", stages, len(*notices), want)
+	}
+	for i := range want {
+		if stages[i] != want[i] || (*notices)[i].Stage != want[i] {
+			t.Fatalf("audit stages %v; want %v", stages, want)
+		}
+	}
+
+	cal.Check(ctx)
+	if len(exec.actions) != 1 {
+		t.Fatalf("applied entry re-executed")
+	}
+	if _, err := cal.Cancel(ctx, entry.ID, ""); !errors.Is(err, ErrEntryClosed) {
+		t.Fatalf("expected ErrEntryClosed, got %v", err)
+	}
+}
+
+func TestCalendarFailuresMissesAndCancellation(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}
+	exec := &recordingExecutor{err: errors.New("provider not running")}
+	store := &memoryStore{entries: make(map[string]calendarstore.Entry)}
+	cal, _ := newTestCalendar(exec, clock, WithStore(store))
+	ctx := context.Background()
+	zero := time.Duration(0)
+
+	if _, err := cal.Schedule(ctx, Request{At: clock.now.Add(-time.Minute), Action: Action{Kind: ActionHaltTrading}}); err == nil {
+		t.Fatalf("expected error for past entry")
+	}
+	if _, err := cal.Schedule(ctx, Request{At: clock.now.Add(time.Minute), Action: Action{Kind: ActionStopProvider}}); err == nil {
+		t.Fatalf("expected error for missing provider")
+	}
+
+	failing, err := cal.Schedule(ctx, Request{At: clock.now.Add(time.Minute), NotifyBefore: &zero, Action: Action{Kind: ActionStopProvider, Provider: "binance"}})
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if failing.NotifyAt != nil {
+		t.Fatalf("expected notification disabled")
+	}
+	cancelled, err := cal.Schedule(ctx, Request{At: clock.now.Add(2 * time.Minute), Action: Action{Kind: ActionResumeTrading}})
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	late, err := cal.Schedule(ctx, Request{At: clock.now.Add(3 * time.Minute), Action: Action{Kind: ActionHaltTrading}})
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if _, err := cal.Cancel(ctx, cancelled.ID, "event postponed"); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+
+	// A restart that comes back well after the last entry fell due.
+	clock.now = clock.now.Add(10 * time.Minute)
+	restored, _ := newTestCalendar(exec, clock, WithStore(store))
+	if err := restored.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if open := restored.Entries(false); len(open) != 2 {
+		t.Fatalf("expected 2 open entries after reload, got %d", len(open))
+	}
+	restored.Check(ctx)
+
+	if got, _ := restored.Entry(failing.ID); got.Status != StatusMissed {
+		t.Fatalf("late entry status = %s, want missed", got.Status)
+	}
+	if got, _ := restored.Entry(late.ID); got.Status != StatusMissed {
+		t.Fatalf("late entry status = %s, want missed", got.Status)
+	}
+	if got, _ := restored.Entry(cancelled.ID); got.Status != StatusCancelled {
+		t.Fatalf("cancelled entry status = %s", got.Status)
+	}
+	if len(exec.actions) != 0 {
+		t.Fatalf("missed entries must not execute, got %+v", exec.actions)
+	}
+
+	due, err := restored.Schedule(ctx, Request{At: clock.now.Add(time.Second), Action: Action{Kind: ActionStopProvider, Provider: "okx"}})
+	if err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	clock.now = clock.now.Add(2 * time.Second)
+	restored.Check(ctx)
+	got, _ := restored.Entry(due.ID)
+	if got.Status != StatusFailed || got.Error != "provider not running" {
+		t.Fatalf("expected failed entry, got %+v", got)
+	}
+	if all := restored.Entries(true); len(all) != 4 {
+		t.Fatalf("expected 4 entries including closed, got %d", len(all))
+	}
+}
@@ -0,0 +1,134 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/approvals"
+	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// schedulerPrincipal requests approvals for entries scheduled without verified credentials.
+const schedulerPrincipal = "calendar"
+
+// RiskController is the subset of the lambda manager that calendar actions drive.
+type RiskController interface {
+	ApplyRiskProfile(name string) (risk.Limits, error)
+	AssignInstanceRiskProfile(id, name string) error
+	RiskProfileIncreases(name string, instances []string, percent float64) ([]string, error)
+	HaltTrading(reason string) risk.Snapshot
+	ResumeTrading() risk.Snapshot
+}
+
+// ProviderController is the subset of the provider manager that calendar actions drive.
+type ProviderController interface {
+	StartProvider(ctx context.Context, name string) (provider.RuntimeDetail, error)
+	StopProvider(name string) (provider.RuntimeDetail, error)
+}
+
+// GatewayExecutor applies calendar actions to the running gateway.
+type GatewayExecutor struct {
+	risk      RiskController
+	providers ProviderController
+	approvals *approvals.Queue
+}
+
+// NewGatewayExecutor constructs an executor over the lambda and provider managers. Actions whose
+// controller is nil fail when applied. Risk profiles that raise limits beyond what queue lets
+// through are filed for approval instead of applied; queue may be nil when approvals are disabled.
+func NewGatewayExecutor(riskCtl RiskController, providers ProviderController, queue *approvals.Queue) *GatewayExecutor {
+	return &GatewayExecutor{risk: riskCtl, providers: providers, approvals: queue}
+}
+
+// Execute applies the action and returns a short summary for the audit trail.
+func (g *GatewayExecutor) Execute(ctx context.Context, action Action) (string, error) {
+	switch action.Kind {
+	case ActionApplyRiskProfile:
+		if g.risk == nil {
+			return "", fmt.Errorf("lambda manager unavailable")
+		}
+		if g.approvals.Guards(config.ApprovalActionRiskLimits) {
+			raised, err := g.risk.RiskProfileIncreases(action.Profile, action.Instances, g.approvals.RiskLimitIncreasePercent())
+			if err != nil {
+				return "", err
+			}
+			if len(raised) > 0 {
+				return g.requestRiskProfileApproval(action, raised)
+			}
+		}
+		return g.applyRiskProfile(action)
+	case ActionHaltTrading:
+		if g.risk == nil {
+			return "", fmt.Errorf("lambda manager unavailable")
+		}
+		reason := action.Reason
+		if reason == "" {
+			reason = "scheduled"
+		}
+		g.risk.HaltTrading(reason)
+		return "trading halted", nil
+	case ActionResumeTrading:
+		if g.risk == nil {
+			return "", fmt.Errorf("lambda manager unavailable")
+		}
+		g.risk.ResumeTrading()
+		return "trading resumed", nil
+	case ActionStopProvider:
+		if g.providers == nil {
+			return "", fmt.Errorf("provider manager unavailable")
+		}
+		if _, err := g.providers.StopProvider(action.Provider); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("provider %s stopped", action.Provider), nil
+	case ActionStartProvider:
+		if g.providers == nil {
+			return "", fmt.Errorf("provider manager unavailable")
+		}
+		if _, err := g.providers.StartProvider(ctx, action.Provider); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("provider %s started", action.Provider), nil
+	default:
+		return "", fmt.Errorf("unsupported action kind %q", action.Kind)
+	}
+}
+
+func (g *GatewayExecutor) applyRiskProfile(action Action) (string, error) {
+	if len(action.Instances) == 0 {
+		if _, err := g.risk.ApplyRiskProfile(action.Profile); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("risk profile %s applied globally", action.Profile), nil
+	}
+	for _, id := range action.Instances {
+		if err := g.risk.AssignInstanceRiskProfile(id, action.Profile); err != nil {
+			return "", fmt.Errorf("instance %s: %w", id, err)
+		}
+	}
+	return fmt.Sprintf("risk profile %s assigned to %s", action.Profile, strings.Join(action.Instances, ", ")), nil
+}
+
+// requestRiskProfileApproval files a scheduled risk profile that raises limits for approval on
+// behalf of whoever scheduled it. The profile is applied when a different operator approves.
+func (g *GatewayExecutor) requestRiskProfileApproval(action Action, raised []string) (string, error) {
+	principal := strings.TrimSpace(action.RequestedBy)
+	if principal == "" {
+		principal = schedulerPrincipal
+	}
+	summary := fmt.Sprintf("scheduled: apply risk profile %s: %s", action.Profile, strings.Join(raised, ", "))
+	approval, err := g.approvals.Request(config.ApprovalActionRiskLimits, action.Profile, summary, principal, func(context.Context) (any, error) {
+		result, err := g.applyRiskProfile(action)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"status": "applied", "summary": result}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("risk profile %s raises limits; awaiting approval %s", action.Profile, approval.ID), nil
+}
@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"context"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/approvals"
+	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type fakeRiskController struct {
+	raised  []string
+	applied []string
+}
+
+func (f *fakeRiskController) ApplyRiskProfile(name string) (risk.Limits, error) {
+	f.applied = append(f.applied, name)
+	return risk.Limits{}, nil
+}
+
+func (f *fakeRiskController) AssignInstanceRiskProfile(id, name string) error {
+	f.applied = append(f.applied, id+"="+name)
+	return nil
+}
+
+func (f *fakeRiskController) RiskProfileIncreases(string, []string, float64) ([]string, error) {
+	return f.raised, nil
+}
+
+func (f *fakeRiskController) HaltTrading(string) risk.Snapshot { return risk.Snapshot{} }
+
+func (f *fakeRiskController) ResumeTrading() risk.Snapshot { return risk.Snapshot{} }
+
+func TestGatewayExecutorFilesRaisingProfilesForApproval(t *testing.T) {
+	queue := approvals.New(config.ApprovalsConfig{
+		Enabled: true,
+		Actions: config.ApprovalActions,
+		TTL:     time.Hour,
+	}, approvals.WithLogger(log.New(io.Discard, "", 0)))
+	ctl := &fakeRiskController{raised: []string{"maxNotionalValue 500 → 9000"}}
+	exec := NewGatewayExecutor(ctl, nil, queue)
+	ctx := context.Background()
+
+	summary, err := exec.Execute(ctx, Action{Kind: ActionApplyRiskProfile, Profile: "aggressive", RequestedBy: `user:"alice"`})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(ctl.applied) != 0 || !strings.Contains(summary, "awaiting approval") {
+		t.Fatalf("expected the raise held for approval, got %q (applied %v)", summary, ctl.applied)
+	}
+	pending := queue.Approvals(false)
+	if len(pending) != 1 || pending[0].RequestedBy != `user:"alice"` || !strings.Contains(pending[0].Summary, "maxNotionalValue 500 → 9000") {
+		t.Fatalf("unexpected approvals %+v", pending)
+	}
+	if _, err := queue.Approve(ctx, pending[0].ID, `user:"alice"`); err == nil {
+		t.Fatal("expected the scheduler unable to approve their own raise")
+	}
+	if _, err := queue.Approve(ctx, pending[0].ID, `user:"bob"`); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if len(ctl.applied) != 1 || ctl.applied[0] != "aggressive" {
+		t.Fatalf("expected profile applied after approval, got %v", ctl.applied)
+	}
+
+	ctl.raised = nil
+	if _, err := exec.Execute(ctx, Action{Kind: ActionApplyRiskProfile, Profile: "conservative", Instances: []string{"desk"}}); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(ctl.applied) != 2 || ctl.applied[1] != "desk=conservative" {
+		t.Fatalf("expected tightening applied directly, got %v", ctl.applied)
+	}
+}
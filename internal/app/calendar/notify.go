@@ -0,0 +1,44 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+// BusNotifier publishes notices as extension events so sinks, the outbox and any subscribed
+// controller see upcoming and applied entries.
+func BusNotifier(bus eventbus.Bus, pools *pool.PoolManager, logger *log.Logger) Notifier {
+	return func(ctx context.Context, notice Notice) {
+		if bus == nil || pools == nil {
+			return
+		}
+		evt, err := pools.BorrowEventInst(ctx)
+		if err != nil {
+			if logger != nil {
+				logger.Printf("calendar notice skipped: %v", err)
+			}
+			return
+		}
+		ts := notice.Entry.At
+		if n := len(notice.Entry.Audit); n > 0 {
+			ts = notice.Entry.Audit[n-1].Time
+		}
+		evt.EventID = fmt.Sprintf("calendar:%s:%s", notice.Entry.ID, notice.Stage)
+		evt.Provider = notice.Entry.Action.Provider
+		evt.Type = schema.ExtensionEventType
+		evt.IngestTS = ts
+		evt.EmitTS = ts
+		evt.Payload = notice
+		if err := bus.Publish(ctx, evt); err != nil {
+			if logger != nil {
+				logger.Printf("publish calendar notice: %v", err)
+			}
+			pools.ReturnEventInst(evt)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package dispatcher
+
+import (
+	"fmt"
+	"time"
+)
+
+// BookOptions shape the snapshots an order book route delivers. Depth caps the price levels per
+// side (for example 5, 20 or 100) and Interval is the minimum time between two snapshots of an
+// instrument; zero leaves the adapter's configured depth and native cadence in place.
+type BookOptions struct {
+	Depth    int
+	Interval time.Duration
+}
+
+// IsZero reports whether the options leave the adapter defaults in place.
+func (o BookOptions) IsZero() bool {
+	return o.Depth == 0 && o.Interval == 0
+}
+
+// Validate ensures depth and interval are not negative.
+func (o BookOptions) Validate() error {
+	if o.Depth < 0 {
+		return fmt.Errorf("book depth must be >=0, got %d", o.Depth)
+	}
+	if o.Interval < 0 {
+		return fmt.Errorf("book interval must be >=0, got %s", o.Interval)
+	}
+	return nil
+}
+
+// mergeBookOptions combines the options of two lambdas sharing a route so both are served: the
+// deeper book and the faster cadence win, and a lambda leaving either unset keeps the default.
+func mergeBookOptions(a, b BookOptions) BookOptions {
+	merged := BookOptions{Depth: 0, Interval: 0}
+	if a.Depth > 0 && b.Depth > 0 {
+		merged.Depth = max(a.Depth, b.Depth)
+	}
+	if a.Interval > 0 && b.Interval > 0 {
+		merged.Interval = min(a.Interval, b.Interval)
+	}
+	return merged
+}
@@ -0,0 +1,405 @@
+package dispatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// Filter operators understood by FilterRule.
+const (
+	FilterOpEq      = "eq"
+	FilterOpNeq     = "neq"
+	FilterOpIn      = "in"
+	FilterOpPrefix  = "prefix"
+	FilterOpGt      = "gt"
+	FilterOpGte     = "gte"
+	FilterOpLt      = "lt"
+	FilterOpLte     = "lte"
+	FilterOpBetween = "between"
+)
+
+// FilterRule defines a predicate applied to raw instances before canonicalisation.
+//
+// A leaf rule compares the value at the dotted Field path using Op: eq, neq, in and prefix
+// compare text, while gt, gte, lt, lte and between (Value [min, max], inclusive) compare numbers
+// such as price or size. A rule with AnyOf is an OR group instead: it matches when every rule
+// of at least one alternative matches, and Field, Op and Value are unused. Not inverts the
+// result, so a negated leaf also matches instances that lack the field.
+type FilterRule struct {
+	Field string
+	Op    string
+	Value any
+	Not   bool
+	AnyOf [][]FilterRule
+}
+
+// Validate ensures the rule definition is well-formed.
+func (rule FilterRule) Validate() error {
+	if len(rule.AnyOf) > 0 {
+		for i, alternative := range rule.AnyOf {
+			if len(alternative) == 0 {
+				return fmt.Errorf("anyOf[%d]: at least one rule required", i)
+			}
+			for j, nested := range alternative {
+				if err := nested.Validate(); err != nil {
+					return fmt.Errorf("anyOf[%d][%d]: %w", i, j, err)
+				}
+			}
+		}
+		return nil
+	}
+	if strings.TrimSpace(rule.Field) == "" {
+		return fmt.Errorf("field required")
+	}
+	if strings.TrimSpace(rule.Op) == "" {
+		return fmt.Errorf("operator required")
+	}
+	switch strings.ToLower(strings.TrimSpace(rule.Op)) {
+	case FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		if _, ok := toFloat(rule.Value); !ok {
+			return fmt.Errorf("%s requires a numeric value, got %v", rule.Op, rule.Value)
+		}
+	case FilterOpBetween:
+		if _, _, err := numericRange(rule.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Selects reports whether the rule narrows a route to the listed values by equality or
+// membership. Only such rules name the instruments a provider must subscribe to.
+func (rule FilterRule) Selects() bool {
+	if rule.Not || len(rule.AnyOf) > 0 {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(rule.Op)) {
+	case FilterOpEq, FilterOpIn:
+		return true
+	default:
+		return false
+	}
+}
+
+// Match evaluates the rule against a raw instance. Routes in a Table are matched with filters
+// compiled once on upsert; Match compiles the rule on every call.
+func (rule FilterRule) Match(raw schema.RawInstance) bool {
+	compiled, err := compileRule(rule)
+	if err != nil {
+		return false
+	}
+	return compiled.match(raw)
+}
+
+// FilterMatcher evaluates a conjunction of filter rules compiled ahead of time: field paths are
+// split, operators resolved, sets built and numeric bounds parsed once instead of per event.
+type FilterMatcher struct {
+	rules []compiledRule
+}
+
+// CompileFilters compiles the rules of a route into a matcher that accepts an instance when
+// every rule matches.
+func CompileFilters(rules []FilterRule) (*FilterMatcher, error) {
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return &FilterMatcher{rules: compiled}, nil
+}
+
+// Match reports whether every compiled rule accepts the raw instance. A nil matcher accepts
+// everything.
+func (m *FilterMatcher) Match(raw schema.RawInstance) bool {
+	if m == nil {
+		return true
+	}
+	return matchAll(m.rules, raw)
+}
+
+type filterOp int
+
+const (
+	opUnknown filterOp = iota
+	opEq
+	opNeq
+	opIn
+	opContains
+	opPrefix
+	opGt
+	opGte
+	opLt
+	opLte
+	opBetween
+	opAnyOf
+)
+
+type compiledRule struct {
+	path   []string
+	op     filterOp
+	not    bool
+	text   string
+	set    map[string]struct{}
+	number float64
+	max    float64
+	anyOf  [][]compiledRule
+}
+
+func compileRules(rules []FilterRule) ([]compiledRule, error) {
+	out := make([]compiledRule, 0, len(rules))
+	for i, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("filter[%d]: %w", i, err)
+		}
+		out = append(out, compiled)
+	}
+	return out, nil
+}
+
+func compileRule(rule FilterRule) (compiledRule, error) {
+	compiled := compiledRule{
+		path:   nil,
+		op:     opUnknown,
+		not:    rule.Not,
+		text:   "",
+		set:    nil,
+		number: 0,
+		max:    0,
+		anyOf:  nil,
+	}
+	if err := rule.Validate(); err != nil {
+		return compiled, err
+	}
+	if len(rule.AnyOf) > 0 {
+		compiled.op = opAnyOf
+		compiled.anyOf = make([][]compiledRule, len(rule.AnyOf))
+		for i, alternative := range rule.AnyOf {
+			nested, err := compileRules(alternative)
+			if err != nil {
+				return compiled, fmt.Errorf("anyOf[%d]: %w", i, err)
+			}
+			compiled.anyOf[i] = nested
+		}
+		return compiled, nil
+	}
+	compiled.path = strings.Split(strings.TrimSpace(rule.Field), ".")
+	switch strings.ToLower(strings.TrimSpace(rule.Op)) {
+	case FilterOpEq:
+		compiled.op = opEq
+		compiled.text = fmt.Sprint(rule.Value)
+	case FilterOpNeq:
+		compiled.op = opNeq
+		compiled.text = fmt.Sprint(rule.Value)
+	case FilterOpIn:
+		compiled.op = opIn
+		switch values := rule.Value.(type) {
+		case string:
+			// A string set matches values it contains, case-insensitively.
+			compiled.op = opContains
+			compiled.text = strings.ToUpper(values)
+		case []string:
+			compiled.set = make(map[string]struct{}, len(values))
+			for _, value := range values {
+				compiled.set[value] = struct{}{}
+			}
+		case []any:
+			compiled.set = make(map[string]struct{}, len(values))
+			for _, value := range values {
+				compiled.set[fmt.Sprint(value)] = struct{}{}
+			}
+		case map[string]any:
+			compiled.set = make(map[string]struct{}, len(values))
+			for key := range values {
+				compiled.set[key] = struct{}{}
+			}
+		default:
+			compiled.set = map[string]struct{}{}
+		}
+	case FilterOpPrefix:
+		compiled.op = opPrefix
+		compiled.text = strings.ToUpper(fmt.Sprint(rule.Value))
+	case FilterOpGt:
+		compiled.op = opGt
+		compiled.number, _ = toFloat(rule.Value)
+	case FilterOpGte:
+		compiled.op = opGte
+		compiled.number, _ = toFloat(rule.Value)
+	case FilterOpLt:
+		compiled.op = opLt
+		compiled.number, _ = toFloat(rule.Value)
+	case FilterOpLte:
+		compiled.op = opLte
+		compiled.number, _ = toFloat(rule.Value)
+	case FilterOpBetween:
+		compiled.op = opBetween
+		compiled.number, compiled.max, _ = numericRange(rule.Value)
+	}
+	return compiled, nil
+}
+
+func matchAll(rules []compiledRule, raw any) bool {
+	for i := range rules {
+		if !rules[i].match(raw) {
+			return false
+		}
+	}
+	return true
+}
+
+func (rule *compiledRule) match(raw any) bool {
+	return rule.evaluate(raw) != rule.not
+}
+
+func (rule *compiledRule) evaluate(raw any) bool {
+	if rule.op == opAnyOf {
+		for _, alternative := range rule.anyOf {
+			if matchAll(alternative, raw) {
+				return true
+			}
+		}
+		return false
+	}
+	value, ok := resolvePath(rule.path, raw)
+	if !ok {
+		return false
+	}
+	switch rule.op {
+	case opEq:
+		return fmt.Sprint(value) == rule.text
+	case opNeq:
+		return fmt.Sprint(value) != rule.text
+	case opIn:
+		_, ok := rule.set[fmt.Sprint(value)]
+		return ok
+	case opContains:
+		return strings.Contains(rule.text, strings.ToUpper(fmt.Sprint(value)))
+	case opPrefix:
+		return strings.HasPrefix(strings.ToUpper(fmt.Sprint(value)), rule.text)
+	case opGt, opGte, opLt, opLte, opBetween:
+		number, ok := toFloat(value)
+		if !ok {
+			return false
+		}
+		return rule.compare(number)
+	default:
+		return false
+	}
+}
+
+func (rule *compiledRule) compare(number float64) bool {
+	switch rule.op {
+	case opGt:
+		return number > rule.number
+	case opGte:
+		return number >= rule.number
+	case opLt:
+		return number < rule.number
+	case opLte:
+		return number <= rule.number
+	case opBetween:
+		return number >= rule.number && number <= rule.max
+	default:
+		return false
+	}
+}
+
+func resolvePath(path []string, raw any) (any, bool) {
+	if len(path) == 0 {
+		return raw, true
+	}
+	var current map[string]any
+	switch v := raw.(type) {
+	case map[string]any:
+		current = v
+	case schema.RawInstance:
+		current = map[string]any(v)
+	default:
+		return nil, false
+	}
+	value, ok := current[path[0]]
+	if !ok {
+		return nil, false
+	}
+	return resolvePath(path[1:], value)
+}
+
+// toFloat reads numbers and numeric strings, the two forms prices and sizes take in raw
+// payloads.
+func toFloat(value any) (float64, bool) {
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case float32:
+		number = float64(v)
+	case int:
+		number = float64(v)
+	case int32:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case uint32:
+		number = float64(v)
+	case uint64:
+		number = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	case fmt.Stringer:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	default:
+		return 0, false
+	}
+	if math.IsNaN(number) {
+		return 0, false
+	}
+	return number, true
+}
+
+func numericRange(value any) (float64, float64, error) {
+	var bounds []any
+	switch v := value.(type) {
+	case []any:
+		bounds = v
+	case []string:
+		bounds = make([]any, len(v))
+		for i, entry := range v {
+			bounds[i] = entry
+		}
+	case []float64:
+		bounds = make([]any, len(v))
+		for i, entry := range v {
+			bounds[i] = entry
+		}
+	}
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("between requires [min, max], got %v", value)
+	}
+	lo, okLo := toFloat(bounds[0])
+	hi, okHi := toFloat(bounds[1])
+	if !okLo || !okHi {
+		return 0, 0, fmt.Errorf("between requires numeric bounds, got %v", value)
+	}
+	if lo > hi {
+		return 0, 0, fmt.Errorf("between requires min <= max, got %v", value)
+	}
+	return lo, hi, nil
+}
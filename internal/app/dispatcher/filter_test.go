@@ -0,0 +1,150 @@
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestFilterRuleNumericAndNegation(t *testing.T) {
+	raw := schema.RawInstance{
+		"instrument": "BTC-USDT",
+		"payload":    map[string]any{"price": "101.5", "size": 0.25},
+	}
+	tests := []struct {
+		name  string
+		rule  FilterRule
+		match bool
+	}{
+		{"gt string price", FilterRule{Field: "payload.price", Op: "gt", Value: 100}, true},
+		{"lte float size", FilterRule{Field: "payload.size", Op: "lte", Value: "0.2"}, false},
+		{"between inclusive", FilterRule{Field: "payload.price", Op: "between", Value: []any{"100", 101.5}}, true},
+		{"between outside", FilterRule{Field: "payload.size", Op: "between", Value: []float64{1, 2}}, false},
+		{"numeric op on text", FilterRule{Field: "instrument", Op: "gt", Value: 1}, false},
+		{"negated eq", FilterRule{Field: "instrument", Op: "eq", Value: "ETH-USDT", Not: true}, true},
+		{"negated missing field", FilterRule{Field: "payload.side", Op: "eq", Value: "buy", Not: true}, true},
+		{"negated range", FilterRule{Field: "payload.price", Op: "lt", Value: 200, Not: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Match(raw); got != tt.match {
+				t.Fatalf("Match() = %v, want %v", got, tt.match)
+			}
+		})
+	}
+}
+
+func TestFilterRuleAnyOfGroups(t *testing.T) {
+	rule := FilterRule{AnyOf: [][]FilterRule{
+		{{Field: "instrument", Op: "eq", Value: "BTC-USDT"}, {Field: "price", Op: "gte", Value: 100}},
+		{{Field: "instrument", Op: "prefix", Value: "eth"}},
+	}}
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	cases := map[string]struct {
+		raw   schema.RawInstance
+		match bool
+	}{
+		"first alternative":  {schema.RawInstance{"instrument": "BTC-USDT", "price": 150}, true},
+		"first partially":    {schema.RawInstance{"instrument": "BTC-USDT", "price": 50}, false},
+		"second alternative": {schema.RawInstance{"instrument": "ETH-USDT", "price": 1}, true},
+		"no alternative":     {schema.RawInstance{"instrument": "SOL-USDT", "price": 150}, false},
+	}
+	for name, tc := range cases {
+		if got := rule.Match(tc.raw); got != tc.match {
+			t.Fatalf("%s: Match() = %v, want %v", name, got, tc.match)
+		}
+	}
+	rule.Not = true
+	if rule.Match(cases["first alternative"].raw) {
+		t.Fatal("negated group must reject a matching instance")
+	}
+	if rule.Selects() {
+		t.Fatal("groups must not select instruments")
+	}
+}
+
+func TestFilterRuleValidateRichOperators(t *testing.T) {
+	invalid := []FilterRule{
+		{Field: "price", Op: "gt", Value: "cheap"},
+		{Field: "price", Op: "between", Value: []any{1}},
+		{Field: "price", Op: "between", Value: []any{5, 1}},
+		{AnyOf: [][]FilterRule{{}}},
+		{AnyOf: [][]FilterRule{{{Field: "", Op: "eq"}}}},
+	}
+	for i, rule := range invalid {
+		if err := rule.Validate(); err == nil {
+			t.Fatalf("rule %d: expected validation error", i)
+		}
+	}
+	if _, err := CompileFilters(invalid[:1]); err == nil {
+		t.Fatal("expected compile error")
+	}
+}
+
+func TestTableMatchUsesCompiledFilters(t *testing.T) {
+	table := NewTable()
+	route := Route{
+		Provider: "binance",
+		Type:     schema.RouteTypeTrade,
+		WSTopics: []string{"trade"},
+		RestFns:  nil,
+		Filters: []FilterRule{
+			{Field: "instrument", Op: "in", Value: []string{"BTC-USDT", "ETH-USDT"}},
+			{Field: "size", Op: "gte", Value: "1"},
+		},
+	}
+	if err := table.Upsert(route); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+	if !table.Match("Binance", schema.RouteTypeTrade, schema.RawInstance{"instrument": "BTC-USDT", "size": "2"}) {
+		t.Fatal("expected large BTC trade to match")
+	}
+	if table.Match("binance", schema.RouteTypeTrade, schema.RawInstance{"instrument": "BTC-USDT", "size": "0.5"}) {
+		t.Fatal("expected small trade rejected")
+	}
+	if table.Match("okx", schema.RouteTypeTrade, schema.RawInstance{"instrument": "BTC-USDT", "size": "2"}) {
+		t.Fatal("expected unrouted provider rejected")
+	}
+
+	route.Filters = append(route.Filters, FilterRule{Field: "price", Op: "between", Value: []any{"9", "1"}})
+	if err := table.Upsert(route); err == nil {
+		t.Fatal("expected invalid range rejected on upsert")
+	}
+	table.Remove("binance", schema.RouteTypeTrade)
+	if table.Match("binance", schema.RouteTypeTrade, schema.RawInstance{"instrument": "BTC-USDT", "size": "2"}) {
+		t.Fatal("expected removed route to stop matching")
+	}
+}
+
+func TestMergeFiltersKeepsNonSelectingRules(t *testing.T) {
+	existing := []FilterRule{
+		{Field: "instrument", Op: "eq", Value: "BTC-USDT"},
+		{Field: "price", Op: "between", Value: []any{"9", "10"}},
+		{Field: "instrument", Op: "prefix", Value: "DOGE", Not: true},
+	}
+	merged := mergeFilters(existing, map[string]any{"instrument": "ETH-USDT"})
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 rules, got %+v", merged)
+	}
+	var sawRange, sawNegation, sawSet bool
+	for _, rule := range merged {
+		switch {
+		case rule.Op == FilterOpBetween:
+			bounds, ok := rule.Value.([]any)
+			sawRange = ok && bounds[0] == "9" && bounds[1] == "10"
+		case rule.Not:
+			sawNegation = rule.Op == FilterOpPrefix
+		case rule.Op == FilterOpIn:
+			values, ok := rule.Value.([]string)
+			sawSet = ok && len(values) == 2
+		}
+	}
+	if !sawRange || !sawNegation || !sawSet {
+		t.Fatalf("unexpected merge %+v", merged)
+	}
+	if !equalFilters(merged, normalizeFilters(merged)) {
+		t.Fatal("expected normalized merge to be stable")
+	}
+}
@@ -23,6 +23,8 @@ type ProviderRouter interface {
 type RouteDeclaration struct {
 	Type    schema.RouteType
 	Filters map[string]any
+	// Book requests the depth and cadence of order book snapshots.
+	Book BookOptions
 }
 
 type lambdaRegistration struct {
@@ -37,6 +39,19 @@ type LambdaBatchRegistration struct {
 	Routes    []RouteDeclaration
 }
 
+// LambdaRoute is a route a lambda declared on one provider, next to the route the table holds for
+// that provider and type once every lambda's filters are merged.
+type LambdaRoute struct {
+	Provider string
+	Type     schema.RouteType
+	// Filters and Book are what the lambda declared.
+	Filters map[string]any
+	Book    BookOptions
+	// Active reports whether the table holds the route; Route is the merged route when it does.
+	Active bool
+	Route  Route
+}
+
 // Registrar coordinates dynamic routing updates based on active lambdas.
 type Registrar struct {
 	mu      sync.Mutex
@@ -143,6 +158,34 @@ func (r *Registrar) UnregisterLambda(ctx context.Context, lambdaID string) error
 	return nil
 }
 
+// LambdaRoutes returns the routes registered for lambdaID, one per provider and declaration, and
+// whether the lambda is registered at all. Routes still waiting for the asynchronous rebuild, or
+// whose activation failed, are reported inactive.
+func (r *Registrar) LambdaRoutes(lambdaID string) ([]LambdaRoute, bool) {
+	lambdaID = strings.TrimSpace(lambdaID)
+	r.mu.Lock()
+	reg, ok := r.lambdas[lambdaID]
+	r.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	out := make([]LambdaRoute, 0, len(reg.Providers)*len(reg.Routes))
+	for _, provider := range reg.Providers {
+		for _, decl := range reg.Routes {
+			route, active := r.table.Lookup(provider, decl.Type)
+			out = append(out, LambdaRoute{
+				Provider: provider,
+				Type:     decl.Type,
+				Filters:  cloneFilterMap(decl.Filters),
+				Book:     decl.Book,
+				Active:   active,
+				Route:    route,
+			})
+		}
+	}
+	return out, true
+}
+
 func (r *Registrar) scheduleRebuild() {
 	r.startWorker()
 	select {
@@ -199,6 +242,7 @@ func (r *Registrar) snapshot() map[string]lambdaRegistration {
 			copiedRoutes[i] = RouteDeclaration{
 				Type:    route.Type,
 				Filters: cloneFilterMap(route.Filters),
+				Book:    route.Book,
 			}
 		}
 		out[id] = lambdaRegistration{
@@ -234,7 +278,10 @@ func (r *Registrar) applyRoutes(ctx context.Context, state map[string]lambdaRegi
 						WSTopics: []string{},
 						RestFns:  []RestFn{},
 						Filters:  []FilterRule{},
+						Book:     decl.Book,
 					}
+				} else {
+					route.Book = mergeBookOptions(route.Book, decl.Book)
 				}
 				merged := mergeFilters(route.Filters, decl.Filters)
 				route.Filters = merged
@@ -330,10 +377,14 @@ func normalizeLambdaRegistration(lambdaID string, providers []string, routes []R
 		if err := route.Type.Validate(); err != nil {
 			return "", lambdaRegistration{}, fmt.Errorf("lambda route[%d]: %w", i, err)
 		}
+		if err := route.Book.Validate(); err != nil {
+			return "", lambdaRegistration{}, fmt.Errorf("lambda route[%d]: %w", i, err)
+		}
 		normalized := schema.NormalizeRouteType(route.Type)
 		copied[i] = RouteDeclaration{
 			Type:    normalized,
 			Filters: cloneFilterMap(route.Filters),
+			Book:    route.Book,
 		}
 	}
 
@@ -396,7 +447,14 @@ func mergeFilters(existing []FilterRule, overrides map[string]any) []FilterRule
 		}
 	}
 
+	// Only equality and membership rules name values to widen; ranges, negations and groups
+	// are kept as they are.
+	out := make([]FilterRule, 0, len(existing)+len(overrides))
 	for _, rule := range existing {
+		if !rule.Selects() {
+			out = append(out, rule)
+			continue
+		}
 		accumulate(rule.Field, rule.Value)
 	}
 
@@ -404,7 +462,6 @@ func mergeFilters(existing []FilterRule, overrides map[string]any) []FilterRule
 		accumulate(field, value)
 	}
 
-	out := make([]FilterRule, 0, len(fieldSets))
 	for field, values := range fieldSets {
 		normValues := make([]string, 0, len(values))
 		for value := range values {
@@ -415,6 +472,8 @@ func mergeFilters(existing []FilterRule, overrides map[string]any) []FilterRule
 			Field: field,
 			Op:    "",
 			Value: nil,
+			Not:   false,
+			AnyOf: nil,
 		}
 		if len(normValues) == 1 {
 			rule.Op = "eq"
@@ -458,8 +517,22 @@ func normalizeFilters(filters []FilterRule) []FilterRule {
 	}
 	out := make([]FilterRule, len(filters))
 	for i, filter := range filters {
+		if len(filter.AnyOf) > 0 {
+			alternatives := make([][]FilterRule, len(filter.AnyOf))
+			for j, alternative := range filter.AnyOf {
+				alternatives[j] = normalizeFilters(alternative)
+			}
+			filter.AnyOf = alternatives
+			out[i] = filter
+			continue
+		}
 		filter.Field = strings.TrimSpace(filter.Field)
 		filter.Op = strings.TrimSpace(strings.ToLower(filter.Op))
+		if filter.Op == FilterOpBetween {
+			// Range bounds are ordered and numeric; sorting them as text would swap them.
+			out[i] = filter
+			continue
+		}
 		if filter.Op == "" {
 			if _, ok := filter.Value.([]string); ok {
 				filter.Op = "in"
@@ -494,8 +567,11 @@ func normalizeFilters(filters []FilterRule) []FilterRule {
 		}
 		out[i] = filter
 	}
-	sort.Slice(out, func(i, j int) bool {
+	sort.SliceStable(out, func(i, j int) bool {
 		if out[i].Field == out[j].Field {
+			if out[i].Op == out[j].Op {
+				return filterSignature(out[i]) < filterSignature(out[j])
+			}
 			return out[i].Op < out[j].Op
 		}
 		return out[i].Field < out[j].Field
@@ -54,3 +54,86 @@ func TestRegistrarRegisterLambdaMultipleProviders(t *testing.T) {
 		}
 	}
 }
+
+func TestRegistrarMergesBookOptions(t *testing.T) {
+	ctx := context.Background()
+	table := NewTable()
+	registrar := NewRegistrar(table, nil)
+	t.Cleanup(func() { registrar.Close() })
+
+	light := []RouteDeclaration{{
+		Type: schema.RouteTypeOrderbookSnapshot,
+		Book: BookOptions{Depth: 5, Interval: time.Second},
+	}}
+	deeper := []RouteDeclaration{{
+		Type: schema.RouteTypeOrderbookSnapshot,
+		Book: BookOptions{Depth: 20, Interval: 2 * time.Second},
+	}}
+	if err := registrar.RegisterLambdaBatch(ctx, []LambdaBatchRegistration{
+		{ID: "light", Providers: []string{"alpha"}, Routes: light},
+		{ID: "deeper", Providers: []string{"alpha"}, Routes: deeper},
+	}); err != nil {
+		t.Fatalf("register lambdas: %v", err)
+	}
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for {
+		route, ok := table.Lookup("alpha", schema.RouteTypeOrderbookSnapshot)
+		if ok {
+			if route.Book != (BookOptions{Depth: 20, Interval: time.Second}) {
+				t.Fatalf("expected the deeper book at the faster cadence, got %+v", route.Book)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected an order book route")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if merged := mergeBookOptions(BookOptions{Depth: 5, Interval: time.Second}, BookOptions{}); !merged.IsZero() {
+		t.Fatalf("expected a lambda without options to keep the adapter defaults, got %+v", merged)
+	}
+	if err := registrar.RegisterLambda(ctx, "bad", []string{"alpha"}, []RouteDeclaration{{
+		Type: schema.RouteTypeOrderbookSnapshot,
+		Book: BookOptions{Depth: -1},
+	}}); err == nil {
+		t.Fatal("expected negative depth to be rejected")
+	}
+}
+
+func TestRegistrarLambdaRoutes(t *testing.T) {
+	ctx := context.Background()
+	table := NewTable()
+	registrar := NewRegistrar(table, nil)
+	t.Cleanup(func() { registrar.Close() })
+
+	if _, ok := registrar.LambdaRoutes("missing"); ok {
+		t.Fatal("expected unknown lambda reported unregistered")
+	}
+	routes := []RouteDeclaration{{Type: schema.RouteTypeTrade, Filters: map[string]any{"instrument": "BTC-USDT"}}}
+	if err := registrar.RegisterLambda(ctx, "alpha", []string{"okx"}, routes); err != nil {
+		t.Fatalf("register lambda: %v", err)
+	}
+	if err := registrar.RegisterLambda(ctx, "beta", []string{"okx"}, []RouteDeclaration{{Type: schema.RouteTypeTrade, Filters: map[string]any{"instrument": "ETH-USDT"}}}); err != nil {
+		t.Fatalf("register lambda: %v", err)
+	}
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for {
+		got, ok := registrar.LambdaRoutes(" alpha ")
+		if !ok || len(got) != 1 {
+			t.Fatalf("expected one route, got %+v (ok=%v)", got, ok)
+		}
+		if got[0].Active && len(got[0].Route.Filters) == 1 && got[0].Route.Filters[0].Op == "in" {
+			if got[0].Provider != "okx" || got[0].Type != schema.RouteTypeTrade || got[0].Filters["instrument"] != "BTC-USDT" {
+				t.Fatalf("unexpected route %+v", got[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the merged route to become active, got %+v", got[0])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
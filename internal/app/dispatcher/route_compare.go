@@ -20,6 +20,9 @@ func EqualRoutes(a, b Route) bool {
 	if !equalRestFns(a.RestFns, b.RestFns) {
 		return false
 	}
+	if a.Book != b.Book {
+		return false
+	}
 	return equalFilters(a.Filters, b.Filters)
 }
 
@@ -103,7 +106,27 @@ func equalFilters(a, b []FilterRule) bool {
 }
 
 func filterSignature(filter FilterRule) string {
+	op := strings.TrimSpace(strings.ToLower(filter.Op))
 	values := flattenValues(filter.Value)
-	sort.Strings(values)
-	return fmt.Sprintf("%s|%s|%s", strings.TrimSpace(strings.ToLower(filter.Field)), strings.TrimSpace(strings.ToLower(filter.Op)), strings.Join(values, ","))
+	if op != FilterOpBetween {
+		sort.Strings(values)
+	}
+	signature := fmt.Sprintf("%s|%s|%s", strings.TrimSpace(strings.ToLower(filter.Field)), op, strings.Join(values, ","))
+	if filter.Not {
+		signature = "!" + signature
+	}
+	if len(filter.AnyOf) > 0 {
+		alternatives := make([]string, len(filter.AnyOf))
+		for i, alternative := range filter.AnyOf {
+			rules := make([]string, len(alternative))
+			for j, rule := range alternative {
+				rules[j] = filterSignature(rule)
+			}
+			sort.Strings(rules)
+			alternatives[i] = "(" + strings.Join(rules, "&") + ")"
+		}
+		sort.Strings(alternatives)
+		signature += "|" + strings.Join(alternatives, "/")
+	}
+	return signature
 }
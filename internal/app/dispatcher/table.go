@@ -18,6 +18,8 @@ type Route struct {
 	WSTopics []string
 	RestFns  []RestFn
 	Filters  []FilterRule
+	// Book shapes order book snapshots; other route types ignore it.
+	Book BookOptions
 }
 
 // RestFn configures a REST polling routine used by the dispatcher.
@@ -28,18 +30,12 @@ type RestFn struct {
 	Parser   string
 }
 
-// FilterRule defines a predicate applied to raw instances before canonicalisation.
-type FilterRule struct {
-	Field string
-	Op    string
-	Value any
-}
-
 // Table stores canonical routes keyed by type.
 type Table struct {
-	mu      sync.RWMutex
-	routes  map[RouteKey]Route
-	version atomic.Int64
+	mu       sync.RWMutex
+	routes   map[RouteKey]Route
+	matchers map[RouteKey]*FilterMatcher
+	version  atomic.Int64
 }
 
 // RouteKey uniquely identifies a route dimension for a provider and canonical type.
@@ -59,6 +55,7 @@ func (k RouteKey) normalize() RouteKey {
 func NewTable() *Table {
 	table := new(Table)
 	table.routes = make(map[RouteKey]Route)
+	table.matchers = make(map[RouteKey]*FilterMatcher)
 	return table
 }
 
@@ -77,16 +74,19 @@ func (t *Table) Upsert(route Route) error {
 			return err
 		}
 	}
-	for i, filter := range route.Filters {
-		if err := filter.Validate(); err != nil {
-			return errs.New("dispatcher/route", errs.CodeInvalid, errs.WithMessage(fmt.Sprintf("filter[%d]: %v", i, err)))
-		}
+	if err := route.Book.Validate(); err != nil {
+		return errs.New("dispatcher/route", errs.CodeInvalid, errs.WithMessage(err.Error()))
+	}
+	matcher, err := CompileFilters(route.Filters)
+	if err != nil {
+		return errs.New("dispatcher/route", errs.CodeInvalid, errs.WithMessage(err.Error()))
 	}
 
 	key := RouteKey{Provider: route.Provider, Type: route.Type}.normalize()
 
 	t.mu.Lock()
 	t.routes[key] = route
+	t.matchers[key] = matcher
 	t.mu.Unlock()
 	return nil
 }
@@ -96,9 +96,21 @@ func (t *Table) Remove(provider string, typ schema.RouteType) {
 	key := RouteKey{Provider: provider, Type: typ}.normalize()
 	t.mu.Lock()
 	delete(t.routes, key)
+	delete(t.matchers, key)
 	t.mu.Unlock()
 }
 
+// Match reports whether the route for provider and type accepts the raw instance using the
+// filters compiled when the route was upserted. Instances without a route are rejected.
+func (t *Table) Match(provider string, typ schema.RouteType, raw schema.RawInstance) bool {
+	key := RouteKey{Provider: provider, Type: typ}.normalize()
+	t.mu.RLock()
+	_, ok := t.routes[key]
+	matcher := t.matchers[key]
+	t.mu.RUnlock()
+	return ok && matcher.Match(raw)
+}
+
 // Lookup returns the route if present.
 func (t *Table) Lookup(provider string, typ schema.RouteType) (Route, bool) {
 	key := RouteKey{Provider: provider, Type: typ}.normalize()
@@ -134,46 +146,14 @@ func (t *Table) NextVersion() int64 {
 	return t.version.Add(1)
 }
 
-// Match reports whether the route accepts the provided raw instance.
+// Match reports whether the route accepts the provided raw instance. It compiles the filters on
+// every call; Table.Match reuses the filters compiled on upsert.
 func (r Route) Match(raw schema.RawInstance) bool {
-	for _, filter := range r.Filters {
-		if !filter.Match(raw) {
-			return false
-		}
-	}
-	return true
-}
-
-// Validate ensures the rule definition is well-formed.
-func (rule FilterRule) Validate() error {
-	if strings.TrimSpace(rule.Field) == "" {
-		return fmt.Errorf("field required")
-	}
-	if strings.TrimSpace(rule.Op) == "" {
-		return fmt.Errorf("operator required")
-	}
-	return nil
-}
-
-// Match evaluates the rule against a raw instance.
-func (rule FilterRule) Match(raw schema.RawInstance) bool {
-	path := strings.Split(rule.Field, ".")
-	value, ok := resolvePath(path, raw)
-	if !ok {
-		return false
-	}
-	switch strings.ToLower(rule.Op) {
-	case "eq":
-		return compareEqual(value, rule.Value)
-	case "neq":
-		return !compareEqual(value, rule.Value)
-	case "in":
-		return contains(value, rule.Value)
-	case "prefix":
-		return hasPrefix(value, rule.Value)
-	default:
+	matcher, err := CompileFilters(r.Filters)
+	if err != nil {
 		return false
 	}
+	return matcher.Match(raw)
 }
 
 func validateRestFn(fn RestFn) error {
@@ -188,56 +168,3 @@ func validateRestFn(fn RestFn) error {
 	}
 	return nil
 }
-
-func resolvePath(path []string, raw any) (any, bool) {
-	if len(path) == 0 {
-		return raw, true
-	}
-	var current map[string]any
-	switch v := raw.(type) {
-	case map[string]any:
-		current = v
-	case schema.RawInstance:
-		current = map[string]any(v)
-	default:
-		return nil, false
-	}
-	value, ok := current[path[0]]
-	if !ok {
-		return nil, false
-	}
-	return resolvePath(path[1:], value)
-}
-
-func compareEqual(lhs any, rhs any) bool {
-	return fmt.Sprint(lhs) == fmt.Sprint(rhs)
-}
-
-func hasPrefix(lhs any, rhs any) bool {
-	needle := fmt.Sprint(rhs)
-	return strings.HasPrefix(strings.ToUpper(fmt.Sprint(lhs)), strings.ToUpper(needle))
-}
-
-func contains(value any, set any) bool {
-	switch s := set.(type) {
-	case []any:
-		for _, item := range s {
-			if compareEqual(value, item) {
-				return true
-			}
-		}
-	case []string:
-		lhs := fmt.Sprint(value)
-		for _, item := range s {
-			if lhs == item {
-				return true
-			}
-		}
-	case string:
-		return strings.Contains(strings.ToUpper(s), strings.ToUpper(fmt.Sprint(value)))
-	case map[string]any:
-		_, ok := s[fmt.Sprint(value)]
-		return ok
-	}
-	return false
-}
@@ -0,0 +1,260 @@
+// Package dropcopy streams the gateway's execution reports to downstream systems over a FIX 4.4
+// drop-copy session, for institutional consumers that only ingest FIX.
+package dropcopy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// Service copies execution reports off the event bus and writes them to the drop-copy session,
+// accepting the counterparty's connection or dialling it depending on the configured mode. Only
+// one session is logged on at a time.
+type Service struct {
+	cfg       config.DropCopyConfig
+	bus       eventbus.Bus
+	pools     *pool.PoolManager
+	logger    *log.Logger
+	providers map[string]struct{}
+	outbox    *outbox
+	seqs      *sequences
+	counter   metric.Int64Counter
+	execSeq   atomic.Uint64
+	active    atomic.Bool
+	wg        sync.WaitGroup
+
+	mu       sync.Mutex
+	listener net.Listener
+}
+
+// New builds a drop-copy service for cfg.
+func New(cfg config.DropCopyConfig, bus eventbus.Bus, pools *pool.PoolManager, logger *log.Logger) *Service {
+	if logger == nil {
+		logger = log.New(redact.Stdout, "dropcopy ", log.LstdFlags|log.Lmicroseconds)
+	}
+	providers := make(map[string]struct{}, len(cfg.Providers))
+	for _, name := range cfg.Providers {
+		if trimmed := strings.ToLower(strings.TrimSpace(name)); trimmed != "" {
+			providers[trimmed] = struct{}{}
+		}
+	}
+	svc := &Service{
+		cfg:       cfg,
+		bus:       bus,
+		pools:     pools,
+		logger:    logger,
+		providers: providers,
+		outbox:    newOutbox(cfg.BufferSize),
+		seqs:      newSequences(cfg.ResendStoreSize),
+		counter:   nil,
+		execSeq:   atomic.Uint64{},
+		active:    atomic.Bool{},
+		wg:        sync.WaitGroup{},
+		mu:        sync.Mutex{},
+		listener:  nil,
+	}
+	if counter, err := otel.Meter("dropcopy").Int64Counter("dropcopy_reports_total",
+		metric.WithDescription("Execution reports handled by the FIX drop-copy session by outcome"),
+		metric.WithUnit("{report}")); err == nil {
+		svc.counter = counter
+	}
+	return svc
+}
+
+// Start subscribes to execution reports and runs the session until ctx is cancelled.
+func (s *Service) Start(ctx context.Context) error {
+	if s.bus == nil {
+		return errors.New("dropcopy: event bus not configured")
+	}
+	var listener net.Listener
+	if s.cfg.Mode == config.DropCopyModeAcceptor {
+		var lc net.ListenConfig
+		ln, err := lc.Listen(ctx, "tcp", s.cfg.Addr)
+		if err != nil {
+			return fmt.Errorf("dropcopy: listen %s: %w", s.cfg.Addr, err)
+		}
+		listener = ln
+		s.mu.Lock()
+		s.listener = ln
+		s.mu.Unlock()
+	}
+	id, ch, err := s.bus.Subscribe(ctx, schema.EventTypeExecReport)
+	if err != nil {
+		if listener != nil {
+			_ = listener.Close()
+		}
+		return fmt.Errorf("dropcopy: subscribe: %w", err)
+	}
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		defer s.bus.Unsubscribe(id)
+		s.consume(ctx, ch)
+	}()
+	go func() {
+		defer s.wg.Done()
+		if listener != nil {
+			s.accept(ctx, listener)
+			return
+		}
+		s.initiate(ctx)
+	}()
+	s.logger.Printf("fix drop-copy %s started on %s as %s -> %s", s.cfg.Mode, s.cfg.Addr, s.cfg.SenderCompID, s.cfg.TargetCompID)
+	return nil
+}
+
+// Wait blocks until the session and the bus subscription have stopped after ctx cancellation.
+func (s *Service) Wait() {
+	s.wg.Wait()
+	if pending := s.outbox.len(); pending > 0 {
+		s.logger.Printf("fix drop-copy stopped with %d unsent execution report(s)", pending)
+	}
+}
+
+// Addr returns the address an acceptor listens on, or nil for an initiator.
+func (s *Service) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+func (s *Service) consume(ctx context.Context, ch <-chan *schema.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-ch:
+			if !ok {
+				return
+			}
+			if evt == nil {
+				continue
+			}
+			r, ok := s.reportFromEvent(evt)
+			if s.pools != nil {
+				s.pools.TryReturnEventInst(evt)
+			}
+			if !ok {
+				continue
+			}
+			if s.outbox.push(r) {
+				s.record(ctx, "dropped")
+			}
+		}
+	}
+}
+
+// reportFromEvent copies the report out of evt before the event is recycled.
+func (s *Service) reportFromEvent(evt *schema.Event) (report, bool) {
+	var empty report
+	payload, ok := evt.Payload.(schema.ExecReportPayload)
+	if !ok {
+		return empty, false
+	}
+	if len(s.providers) > 0 {
+		if _, ok := s.providers[strings.ToLower(evt.Provider)]; !ok {
+			return empty, false
+		}
+	}
+	if payload.RejectReason != nil {
+		reason := *payload.RejectReason
+		payload.RejectReason = &reason
+	}
+	execID := evt.EventID
+	if execID == "" {
+		execID = payload.ClientOrderID + "-" + strconv.FormatUint(s.execSeq.Add(1), 10)
+	}
+	return report{execID: execID, provider: evt.Provider, symbol: evt.Symbol, payload: payload}, true
+}
+
+func (s *Service) accept(ctx context.Context, listener net.Listener) {
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Printf("fix drop-copy accept: %v", err)
+			}
+			return
+		}
+		if !s.active.CompareAndSwap(false, true) {
+			s.logger.Printf("fix drop-copy: session already active, rejected connection from %s", conn.RemoteAddr())
+			_ = conn.Close()
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.active.Store(false)
+			s.serve(ctx, conn, false)
+		}()
+	}
+}
+
+func (s *Service) initiate(ctx context.Context) {
+	var dialer net.Dialer
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", s.cfg.Addr)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Printf("fix drop-copy dial %s: %v", s.cfg.Addr, err)
+		} else {
+			s.serve(ctx, conn, true)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.ReconnectInterval):
+		}
+	}
+}
+
+func (s *Service) serve(ctx context.Context, conn net.Conn, initiator bool) {
+	defer func() {
+		_ = conn.Close()
+	}()
+	sess := newSession(s, conn)
+	if err := sess.run(ctx, initiator); err != nil && ctx.Err() == nil {
+		s.logger.Printf("fix drop-copy session with %s ended: %v", conn.RemoteAddr(), err)
+		return
+	}
+	s.logger.Printf("fix drop-copy session with %s closed", conn.RemoteAddr())
+}
+
+func (s *Service) record(ctx context.Context, outcome string) {
+	if s.counter == nil {
+		return
+	}
+	s.counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("environment", telemetry.Environment()),
+		attribute.String("mode", string(s.cfg.Mode)),
+		attribute.String("outcome", outcome),
+	))
+}
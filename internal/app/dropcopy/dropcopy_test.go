@@ -0,0 +1,339 @@
+package dropcopy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type fakeBus struct {
+	mu  sync.Mutex
+	sub chan *schema.Event
+}
+
+func (b *fakeBus) Publish(_ context.Context, evt *schema.Event) error {
+	b.mu.Lock()
+	ch := b.sub
+	b.mu.Unlock()
+	ch <- evt
+	return nil
+}
+
+func (b *fakeBus) Subscribe(context.Context, schema.EventType) (eventbus.SubscriptionID, <-chan *schema.Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sub = make(chan *schema.Event, 16)
+	return "exec", b.sub, nil
+}
+
+func (b *fakeBus) Unsubscribe(eventbus.SubscriptionID) {}
+func (b *fakeBus) Close()                              {}
+
+func TestFIXRoundTrip(t *testing.T) {
+	sent := time.Date(2026, 1, 2, 3, 4, 5, 6_000_000, time.UTC)
+	data := encodeFIX(msgTypeExecutionReport, "GW", "DC", 7, sent, []fixField{
+		{tag: tagClOrdID, value: "ord-1"},
+		{tag: tagPrice, value: ""},
+		{tag: tagSymbol, value: "BTC-USDT"},
+	})
+	if !bytes.Contains(data, []byte("52=20260102-03:04:05.006\x01")) || bytes.Contains(data, []byte("44=")) {
+		t.Fatalf("unexpected encoding %q", data)
+	}
+	msg, err := readFIX(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if msg.msgType != msgTypeExecutionReport || msg.seqNum() != 7 || msg.get(tagClOrdID) != "ord-1" || msg.get(tagSymbol) != "BTC-USDT" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+
+	corrupt := bytes.Replace(data, []byte("ord-1"), []byte("ord-2"), 1)
+	if _, err := readFIX(bufio.NewReader(bytes.NewReader(corrupt))); !errors.Is(err, errFIXMalformed) {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+}
+
+func TestExecutionReportFieldsSkipsUnknownState(t *testing.T) {
+	if _, ok := executionReportFields(report{execID: "x", provider: "p", symbol: "s", payload: schema.ExecReportPayload{State: "PENDING"}}); ok {
+		t.Fatal("expected unknown state skipped")
+	}
+}
+
+func TestOutboxDropsOldest(t *testing.T) {
+	box := newOutbox(2)
+	for _, id := range []string{"a", "b", "c"} {
+		box.push(report{execID: id})
+	}
+	if r, _ := box.peek(); r.execID != "b" || box.len() != 2 {
+		t.Fatalf("expected oldest dropped, head %q len %d", r.execID, box.len())
+	}
+}
+
+func TestAcceptorSessionStreamsExecutionReports(t *testing.T) {
+	bus := &fakeBus{}
+	svc := New(config.DropCopyConfig{
+		Enabled:           true,
+		Mode:              config.DropCopyModeAcceptor,
+		Addr:              "127.0.0.1:0",
+		SenderCompID:      "MELTICA",
+		TargetCompID:      "BACKOFFICE",
+		HeartbeatInterval: 5 * time.Second,
+		Providers:         []string{"Binance"},
+		BufferSize:        8,
+		ResendStoreSize:   8,
+	}, bus, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		svc.Wait()
+	}()
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	reason := "insufficient balance"
+	publish := func(provider, clientOrderID string, state schema.ExecReportState) {
+		t.Helper()
+		_ = bus.Publish(ctx, &schema.Event{
+			EventID:  "evt-" + clientOrderID + "-" + string(state),
+			Type:     schema.EventTypeExecReport,
+			Provider: provider,
+			Symbol:   "BTC-USDT",
+			Payload: schema.ExecReportPayload{
+				ClientOrderID:  clientOrderID,
+				State:          state,
+				Side:           schema.TradeSideSell,
+				OrderType:      schema.OrderTypeLimit,
+				Price:          "100",
+				Quantity:       "2",
+				FilledQuantity: "2",
+				RejectReason:   &reason,
+			},
+		})
+	}
+	// Reports published before the counterparty connects are buffered.
+	publish("okx", "ord-0", schema.ExecReportStateFILLED)
+	publish("binance", "ord-1", schema.ExecReportStateFILLED)
+	waitFor(t, func() bool { return svc.outbox.len() == 1 })
+
+	conn, err := net.Dial("tcp", svc.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	seq := 0
+	send := func(msgType string, body []fixField) {
+		t.Helper()
+		seq++
+		if _, err := conn.Write(encodeFIX(msgType, "BACKOFFICE", "MELTICA", seq, time.Now(), body)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	expect := func(msgType string) fixMessage {
+		t.Helper()
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		msg, err := readFIX(reader)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if msg.msgType != msgType {
+			t.Fatalf("expected message type %s, got %+v", msgType, msg)
+		}
+		return msg
+	}
+
+	send(msgTypeLogon, []fixField{{tag: tagEncryptMethod, value: "0"}, {tag: tagHeartBtInt, value: "10"}})
+	if logon := expect(msgTypeLogon); logon.get(tagHeartBtInt) != "10" || logon.get(tagResetSeqNumFlag) != "Y" {
+		t.Fatalf("unexpected logon reply %+v", logon)
+	}
+	report := expect(msgTypeExecutionReport)
+	if report.get(tagClOrdID) != "ord-1" || report.get(tagExecType) != "F" || report.get(tagOrdStatus) != "2" ||
+		report.get(tagSide) != "2" || report.get(tagOrdType) != "2" || report.get(tagOrderID) != "NONE" ||
+		report.get(tagExecID) != "evt-ord-1-FILLED" || report.get(tagSecurityExchange) != "binance" {
+		t.Fatalf("unexpected execution report %+v", report)
+	}
+
+	publish("binance", "ord-2", schema.ExecReportStateREJECTED)
+	if rejected := expect(msgTypeExecutionReport); rejected.get(tagOrdStatus) != "8" || rejected.get(tagText) != reason {
+		t.Fatalf("unexpected rejection %+v", rejected)
+	}
+
+	send(msgTypeTestRequest, []fixField{{tag: tagTestReqID, value: "probe"}})
+	if hb := expect(msgTypeHeartbeat); hb.get(tagTestReqID) != "probe" {
+		t.Fatalf("expected heartbeat answering the test request, got %+v", hb)
+	}
+	// A resend replays the execution reports and gap-fills the session messages around them.
+	send(msgTypeResendRequest, []fixField{{tag: tagBeginSeqNo, value: "1"}, {tag: tagEndSeqNo, value: "0"}})
+	if reset := expect(msgTypeSequenceReset); reset.seqNum() != 1 || reset.get(tagGapFillFlag) != "Y" || reset.get(tagNewSeqNo) != "2" {
+		t.Fatalf("unexpected gap fill %+v", reset)
+	}
+	for i, clOrdID := range []string{"ord-1", "ord-2"} {
+		replay := expect(msgTypeExecutionReport)
+		if replay.seqNum() != 2+i || replay.get(tagPossDupFlag) != "Y" || replay.get(tagOrigSendingTime) == "" || replay.get(tagClOrdID) != clOrdID {
+			t.Fatalf("unexpected replay %+v", replay)
+		}
+	}
+	if reset := expect(msgTypeSequenceReset); reset.seqNum() != 4 || reset.get(tagNewSeqNo) != "5" {
+		t.Fatalf("unexpected trailing gap fill %+v", reset)
+	}
+
+	// A second counterparty is turned away while the session is active.
+	other, err := net.Dial("tcp", svc.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	_ = other.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := other.Read(make([]byte, 1)); err == nil {
+		t.Fatal("expected second connection closed")
+	}
+	other.Close()
+
+	send(msgTypeLogout, nil)
+	expect(msgTypeLogout)
+}
+
+func TestAcceptorSequenceContinuesAcrossLogons(t *testing.T) {
+	bus := &fakeBus{}
+	svc := New(config.DropCopyConfig{
+		Enabled:           true,
+		Mode:              config.DropCopyModeAcceptor,
+		Addr:              "127.0.0.1:0",
+		SenderCompID:      "MELTICA",
+		TargetCompID:      "BACKOFFICE",
+		HeartbeatInterval: 5 * time.Second,
+		BufferSize:        8,
+		ResendStoreSize:   8,
+	}, bus, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		svc.Wait()
+	}()
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	connect := func() (func(int, string, []fixField), func(string) fixMessage, func()) {
+		t.Helper()
+		conn, err := net.Dial("tcp", svc.Addr().String())
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		reader := bufio.NewReader(conn)
+		send := func(seq int, msgType string, body []fixField) {
+			t.Helper()
+			if _, err := conn.Write(encodeFIX(msgType, "BACKOFFICE", "MELTICA", seq, time.Now(), body)); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+		}
+		expect := func(msgType string) fixMessage {
+			t.Helper()
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			msg, err := readFIX(reader)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if msg.msgType != msgType {
+				t.Fatalf("expected message type %s, got %+v", msgType, msg)
+			}
+			return msg
+		}
+		return send, expect, func() { _ = conn.Close() }
+	}
+	logon := []fixField{{tag: tagHeartBtInt, value: "10"}}
+
+	send, expect, closeConn := connect()
+	send(1, msgTypeLogon, logon)
+	expect(msgTypeLogon)
+	_ = bus.Publish(ctx, &schema.Event{
+		EventID: "evt-1",
+		Type:    schema.EventTypeExecReport,
+		Symbol:  "BTC-USDT",
+		Payload: schema.ExecReportPayload{ClientOrderID: "ord-1", State: schema.ExecReportStateFILLED, Side: schema.TradeSideBuy},
+	})
+	expect(msgTypeExecutionReport)
+	send(2, msgTypeLogout, nil)
+	expect(msgTypeLogout)
+	closeConn()
+	waitFor(t, func() bool { return !svc.active.Load() })
+
+	// The next logon continues both sequences, so the missed report can be asked for again.
+	send, expect, closeConn = connect()
+	defer closeConn()
+	send(3, msgTypeLogon, logon)
+	if reply := expect(msgTypeLogon); reply.seqNum() != 4 || reply.get(tagResetSeqNumFlag) != "" {
+		t.Fatalf("expected logon continuing the sequence, got %+v", reply)
+	}
+	send(4, msgTypeResendRequest, []fixField{{tag: tagBeginSeqNo, value: "2"}, {tag: tagEndSeqNo, value: "2"}})
+	if replay := expect(msgTypeExecutionReport); replay.seqNum() != 2 || replay.get(tagPossDupFlag) != "Y" || replay.get(tagClOrdID) != "ord-1" {
+		t.Fatalf("unexpected replay %+v", replay)
+	}
+
+	// A gap in the counterparty's numbers is requested again, and a number already seen without
+	// PossDupFlag ends the session.
+	send(7, msgTypeHeartbeat, nil)
+	if req := expect(msgTypeResendRequest); req.get(tagBeginSeqNo) != "5" || req.get(tagEndSeqNo) != "6" {
+		t.Fatalf("unexpected resend request %+v", req)
+	}
+	send(5, msgTypeHeartbeat, []fixField{{tag: tagPossDupFlag, value: "Y"}})
+	send(5, msgTypeHeartbeat, nil)
+	if logout := expect(msgTypeLogout); logout.get(tagText) != "MsgSeqNum too low, expecting 8 but received 5" {
+		t.Fatalf("unexpected logout %+v", logout)
+	}
+}
+
+func TestAcceptorRejectsUnknownCompID(t *testing.T) {
+	bus := &fakeBus{}
+	svc := New(config.DropCopyConfig{
+		Enabled:           true,
+		Mode:              config.DropCopyModeAcceptor,
+		Addr:              "127.0.0.1:0",
+		SenderCompID:      "MELTICA",
+		TargetCompID:      "BACKOFFICE",
+		HeartbeatInterval: 5 * time.Second,
+		BufferSize:        8,
+		ResendStoreSize:   8,
+	}, bus, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		svc.Wait()
+	}()
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	conn, err := net.Dial("tcp", svc.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write(encodeFIX(msgTypeLogon, "INTRUDER", "MELTICA", 1, time.Now(), []fixField{{tag: tagHeartBtInt, value: "10"}})); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	msg, err := readFIX(bufio.NewReader(conn))
+	if err != nil || msg.msgType != msgTypeLogout {
+		t.Fatalf("expected logout, got %+v (%v)", msg, err)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
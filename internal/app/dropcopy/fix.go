@@ -0,0 +1,215 @@
+package dropcopy
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	fixBeginString = "FIX.4.4"
+	fixSOH         = '\x01'
+	// fixMaxBodyLength bounds the body a counterparty may announce, so a corrupt BodyLength cannot
+	// make the session allocate without limit.
+	fixMaxBodyLength = 64 << 10
+	fixTimeLayout    = "20060102-15:04:05.000"
+)
+
+// FIX tags used by the drop-copy session.
+const (
+	tagAccount          = 1
+	tagAvgPx            = 6
+	tagBeginSeqNo       = 7
+	tagBeginString      = 8
+	tagBodyLength       = 9
+	tagCheckSum         = 10
+	tagClOrdID          = 11
+	tagCommission       = 12
+	tagCumQty           = 14
+	tagEndSeqNo         = 16
+	tagExecID           = 17
+	tagMsgSeqNum        = 34
+	tagMsgType          = 35
+	tagNewSeqNo         = 36
+	tagPossDupFlag      = 43
+	tagRefSeqNum        = 45
+	tagOrderID          = 37
+	tagOrderQty         = 38
+	tagOrdStatus        = 39
+	tagOrdType          = 40
+	tagPrice            = 44
+	tagSenderCompID     = 49
+	tagSendingTime      = 52
+	tagSide             = 54
+	tagSymbol           = 55
+	tagTargetCompID     = 56
+	tagText             = 58
+	tagTransactTime     = 60
+	tagEncryptMethod    = 98
+	tagHeartBtInt       = 108
+	tagTestReqID        = 112
+	tagOrigSendingTime  = 122
+	tagGapFillFlag      = 123
+	tagResetSeqNumFlag  = 141
+	tagExecType         = 150
+	tagLeavesQty        = 151
+	tagSecurityExchange = 207
+	tagCommCurrency     = 479
+)
+
+// FIX message types used by the drop-copy session.
+const (
+	msgTypeHeartbeat       = "0"
+	msgTypeTestRequest     = "1"
+	msgTypeResendRequest   = "2"
+	msgTypeReject          = "3"
+	msgTypeSequenceReset   = "4"
+	msgTypeLogout          = "5"
+	msgTypeExecutionReport = "8"
+	msgTypeLogon           = "A"
+)
+
+var errFIXMalformed = errors.New("malformed fix message")
+
+type fixField struct {
+	tag   int
+	value string
+}
+
+// fixMessage is a decoded FIX message. Fields keeps the body in wire order; the drop-copy
+// messages carry no repeating groups, so lookups take the first occurrence of a tag.
+type fixMessage struct {
+	msgType string
+	fields  []fixField
+}
+
+func (m fixMessage) get(tag int) string {
+	for _, field := range m.fields {
+		if field.tag == tag {
+			return field.value
+		}
+	}
+	return ""
+}
+
+func (m fixMessage) seqNum() int {
+	seq, _ := strconv.Atoi(m.get(tagMsgSeqNum))
+	return seq
+}
+
+// encodeFIX frames a message: the standard header, body fields in order, BodyLength and CheckSum.
+// Fields with an empty value are omitted, as FIX does not allow them.
+func encodeFIX(msgType, sender, target string, seq int, sent time.Time, body []fixField) []byte {
+	var inner bytes.Buffer
+	writeFIXField(&inner, tagMsgType, msgType)
+	writeFIXField(&inner, tagSenderCompID, sender)
+	writeFIXField(&inner, tagTargetCompID, target)
+	writeFIXField(&inner, tagMsgSeqNum, strconv.Itoa(seq))
+	writeFIXField(&inner, tagSendingTime, sent.UTC().Format(fixTimeLayout))
+	for _, field := range body {
+		if field.value != "" {
+			writeFIXField(&inner, field.tag, field.value)
+		}
+	}
+	var out bytes.Buffer
+	writeFIXField(&out, tagBeginString, fixBeginString)
+	writeFIXField(&out, tagBodyLength, strconv.Itoa(inner.Len()))
+	out.Write(inner.Bytes())
+	writeFIXField(&out, tagCheckSum, fmt.Sprintf("%03d", fixChecksum(out.Bytes())))
+	return out.Bytes()
+}
+
+func writeFIXField(buf *bytes.Buffer, tag int, value string) {
+	buf.WriteString(strconv.Itoa(tag))
+	buf.WriteByte('=')
+	buf.WriteString(value)
+	buf.WriteByte(fixSOH)
+}
+
+func fixChecksum(data []byte) int {
+	sum := 0
+	for _, b := range data {
+		sum += int(b)
+	}
+	return sum % 256
+}
+
+// readFIX reads one message, verifying its BeginString, BodyLength and CheckSum.
+func readFIX(r *bufio.Reader) (fixMessage, error) {
+	var empty fixMessage
+	begin, err := r.ReadBytes(fixSOH)
+	if err != nil {
+		return empty, err //nolint:wrapcheck // io errors end the session as-is
+	}
+	if string(begin) != "8="+fixBeginString+string(fixSOH) {
+		return empty, fmt.Errorf("%w: unexpected begin string %q", errFIXMalformed, bytes.TrimSuffix(begin, []byte{fixSOH}))
+	}
+	lengthField, err := r.ReadBytes(fixSOH)
+	if err != nil {
+		return empty, err //nolint:wrapcheck // io errors end the session as-is
+	}
+	tag, value, ok := splitFIXField(lengthField)
+	if !ok || tag != tagBodyLength {
+		return empty, fmt.Errorf("%w: body length missing", errFIXMalformed)
+	}
+	length, err := strconv.Atoi(value)
+	if err != nil || length <= 0 || length > fixMaxBodyLength {
+		return empty, fmt.Errorf("%w: invalid body length %q", errFIXMalformed, value)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return empty, err //nolint:wrapcheck // io errors end the session as-is
+	}
+	trailer, err := r.ReadBytes(fixSOH)
+	if err != nil {
+		return empty, err //nolint:wrapcheck // io errors end the session as-is
+	}
+	tag, value, ok = splitFIXField(trailer)
+	if !ok || tag != tagCheckSum {
+		return empty, fmt.Errorf("%w: checksum missing", errFIXMalformed)
+	}
+	expected := fixChecksum(begin) + fixChecksum(lengthField) + fixChecksum(body)
+	if sum, err := strconv.Atoi(value); err != nil || sum != expected%256 {
+		return empty, fmt.Errorf("%w: checksum mismatch", errFIXMalformed)
+	}
+
+	msg := fixMessage{msgType: "", fields: nil}
+	for len(body) > 0 {
+		end := bytes.IndexByte(body, fixSOH)
+		if end < 0 {
+			return empty, fmt.Errorf("%w: unterminated field", errFIXMalformed)
+		}
+		tag, value, ok := splitFIXField(body[:end+1])
+		if !ok {
+			return empty, fmt.Errorf("%w: invalid field %q", errFIXMalformed, body[:end])
+		}
+		body = body[end+1:]
+		if tag == tagMsgType && msg.msgType == "" {
+			msg.msgType = value
+			continue
+		}
+		msg.fields = append(msg.fields, fixField{tag: tag, value: value})
+	}
+	if msg.msgType == "" {
+		return empty, fmt.Errorf("%w: message type missing", errFIXMalformed)
+	}
+	return msg, nil
+}
+
+// splitFIXField splits a SOH-terminated tag=value field.
+func splitFIXField(raw []byte) (int, string, bool) {
+	raw = bytes.TrimSuffix(raw, []byte{fixSOH})
+	tagPart, value, ok := bytes.Cut(raw, []byte{'='})
+	if !ok {
+		return 0, "", false
+	}
+	tag, err := strconv.Atoi(string(tagPart))
+	if err != nil || tag <= 0 {
+		return 0, "", false
+	}
+	return tag, string(value), true
+}
@@ -0,0 +1,159 @@
+package dropcopy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// report is an execution report copied off the bus, with the ExecID it keeps across resends.
+type report struct {
+	execID   string
+	provider string
+	symbol   string
+	payload  schema.ExecReportPayload
+}
+
+// executionReportFields maps a report onto the body of a FIX 4.4 ExecutionReport. Reports in a
+// state FIX cannot express are skipped.
+func executionReportFields(r report) ([]fixField, bool) {
+	execType, ordStatus, ok := fixExecStatus(r.payload.State)
+	if !ok {
+		return nil, false
+	}
+	p := r.payload
+	transact := p.Timestamp
+	if transact.IsZero() {
+		transact = time.Now()
+	}
+	reason := ""
+	if p.RejectReason != nil {
+		reason = *p.RejectReason
+	}
+	return []fixField{
+		{tag: tagAccount, value: p.SubAccount},
+		{tag: tagOrderID, value: orDefault(p.ExchangeOrderID, "NONE")},
+		{tag: tagClOrdID, value: p.ClientOrderID},
+		{tag: tagExecID, value: r.execID},
+		{tag: tagExecType, value: execType},
+		{tag: tagOrdStatus, value: ordStatus},
+		{tag: tagSymbol, value: r.symbol},
+		{tag: tagSecurityExchange, value: r.provider},
+		{tag: tagSide, value: fixSide(p.Side)},
+		{tag: tagOrdType, value: fixOrdType(p.OrderType)},
+		{tag: tagPrice, value: p.Price},
+		{tag: tagOrderQty, value: p.Quantity},
+		{tag: tagLeavesQty, value: orDefault(p.RemainingQty, "0")},
+		{tag: tagCumQty, value: orDefault(p.FilledQuantity, "0")},
+		{tag: tagAvgPx, value: orDefault(p.AvgFillPrice, "0")},
+		{tag: tagCommission, value: p.CommissionAmount},
+		{tag: tagCommCurrency, value: p.CommissionAsset},
+		{tag: tagTransactTime, value: transact.UTC().Format(fixTimeLayout)},
+		{tag: tagText, value: reason},
+	}, true
+}
+
+// fixExecStatus returns the ExecType and OrdStatus of a gateway order state.
+func fixExecStatus(state schema.ExecReportState) (string, string, bool) {
+	switch state {
+	case schema.ExecReportStateACK:
+		return "0", "0", true
+	case schema.ExecReportStatePARTIAL:
+		return "F", "1", true
+	case schema.ExecReportStateFILLED:
+		return "F", "2", true
+	case schema.ExecReportStateCANCELLED:
+		return "4", "4", true
+	case schema.ExecReportStateREJECTED:
+		return "8", "8", true
+	case schema.ExecReportStateEXPIRED:
+		return "C", "C", true
+	default:
+		return "", "", false
+	}
+}
+
+func fixSide(side schema.TradeSide) string {
+	switch side {
+	case schema.TradeSideBuy:
+		return "1"
+	case schema.TradeSideSell:
+		return "2"
+	default:
+		return ""
+	}
+}
+
+func fixOrdType(orderType schema.OrderType) string {
+	switch orderType {
+	case schema.OrderTypeMarket:
+		return "1"
+	case schema.OrderTypeLimit:
+		return "2"
+	default:
+		return ""
+	}
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// outbox holds the reports not yet written to a session. A report leaves the outbox only once
+// written, so reports survive a dropped session and are sent on the next logon.
+type outbox struct {
+	mu    sync.Mutex
+	items []report
+	limit int
+	ready chan struct{}
+}
+
+func newOutbox(limit int) *outbox {
+	return &outbox{mu: sync.Mutex{}, items: nil, limit: limit, ready: make(chan struct{}, 1)}
+}
+
+// push appends r, dropping the oldest report when the outbox is full. It reports whether a report
+// was dropped.
+func (o *outbox) push(r report) bool {
+	o.mu.Lock()
+	dropped := false
+	if len(o.items) >= o.limit {
+		o.items = o.items[1:]
+		dropped = true
+	}
+	o.items = append(o.items, r)
+	o.mu.Unlock()
+	select {
+	case o.ready <- struct{}{}:
+	default:
+	}
+	return dropped
+}
+
+func (o *outbox) peek() (report, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.items) == 0 {
+		var empty report
+		return empty, false
+	}
+	return o.items[0], true
+}
+
+func (o *outbox) pop() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.items) > 0 {
+		o.items = o.items[1:]
+	}
+}
+
+func (o *outbox) len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.items)
+}
@@ -0,0 +1,121 @@
+package dropcopy
+
+import (
+	"sync"
+	"time"
+)
+
+// sentMessage is an application message kept for replay under its original sequence number.
+type sentMessage struct {
+	seq     int
+	msgType string
+	body    []fixField
+	sentAt  time.Time
+}
+
+// sequences holds the session sequence numbers and the recently sent execution reports. It lives
+// on the Service rather than the session so both carry over when the counterparty logs on again.
+type sequences struct {
+	mu sync.Mutex
+	// nextOut is the MsgSeqNum of the next message sent, nextIn the one expected next from the
+	// counterparty.
+	nextOut int
+	nextIn  int
+	// sent holds application messages in sequence order, at most limit of them; evictedThrough is
+	// the highest sequence number dropped from it.
+	sent           []sentMessage
+	limit          int
+	evictedThrough int
+	started        bool
+}
+
+func newSequences(limit int) *sequences {
+	return &sequences{
+		mu:             sync.Mutex{},
+		nextOut:        1,
+		nextIn:         1,
+		sent:           nil,
+		limit:          limit,
+		evictedThrough: 0,
+		started:        false,
+	}
+}
+
+// logon reports whether this logon must reset the sequence numbers: always when forced, and on
+// the first logon since start because nothing earlier is stored to replay.
+func (q *sequences) logon(force bool) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	reset := force || !q.started
+	q.started = true
+	if reset {
+		q.nextOut = 1
+		q.nextIn = 1
+		q.sent = nil
+		q.evictedThrough = 0
+	}
+	return reset
+}
+
+func (q *sequences) nextOutbound() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.nextOut
+}
+
+// record advances the outbound sequence past seq, keeping application messages for replay.
+func (q *sequences) record(seq int, msgType string, body []fixField, sentAt time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextOut = seq + 1
+	if isAdminMessage(msgType) {
+		return
+	}
+	if len(q.sent) >= q.limit {
+		q.evictedThrough = q.sent[0].seq
+		q.sent = q.sent[1:]
+	}
+	q.sent = append(q.sent, sentMessage{seq: seq, msgType: msgType, body: body, sentAt: sentAt})
+}
+
+// between returns the stored messages numbered begin through end, the last sequence number sent
+// and whether reports in that range were evicted.
+func (q *sequences) between(begin, end int) ([]sentMessage, int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	last := q.nextOut - 1
+	if end == 0 || end > last {
+		end = last
+	}
+	var out []sentMessage
+	for _, msg := range q.sent {
+		if msg.seq >= begin && msg.seq <= end {
+			out = append(out, msg)
+		}
+	}
+	return out, end, begin <= q.evictedThrough
+}
+
+func (q *sequences) expectedIn() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.nextIn
+}
+
+func (q *sequences) setExpectedIn(seq int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextIn = seq
+}
+
+// isAdminMessage reports whether msgType is a session-level message, which is gap-filled rather
+// than replayed.
+func isAdminMessage(msgType string) bool {
+	switch msgType {
+	case msgTypeHeartbeat, msgTypeTestRequest, msgTypeResendRequest, msgTypeReject,
+		msgTypeSequenceReset, msgTypeLogout, msgTypeLogon:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,334 @@
+package dropcopy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	sessionWriteTimeout = 5 * time.Second
+	// logoutGrace is how long a closing session waits for the counterparty's Logout.
+	logoutGrace = 2 * time.Second
+)
+
+var errLoggedOut = errors.New("counterparty logged out")
+
+// session is one logged-on FIX connection. Its sequence numbers come from the Service, so they
+// continue across logons; ResendRequests replay the stored execution reports and gap-fill the
+// rest, and gaps in the counterparty's sequence are requested again.
+type session struct {
+	svc    *Service
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu  sync.Mutex
+	lastSent time.Time
+
+	heartbeat time.Duration
+	lastRecv  atomic.Int64
+}
+
+func newSession(svc *Service, conn net.Conn) *session {
+	return &session{
+		svc:       svc,
+		conn:      conn,
+		reader:    bufio.NewReader(conn),
+		writeMu:   sync.Mutex{},
+		lastSent:  time.Time{},
+		heartbeat: svc.cfg.HeartbeatInterval,
+		lastRecv:  atomic.Int64{},
+	}
+}
+
+func (s *session) run(ctx context.Context, initiator bool) error {
+	if err := s.logon(initiator); err != nil {
+		return err
+	}
+	s.svc.logger.Printf("fix drop-copy session with %s logged on (heartbeat %s)", s.conn.RemoteAddr(), s.heartbeat)
+
+	readErr := make(chan error, 1)
+	go func() {
+		readErr <- s.readLoop()
+	}()
+	ticker := time.NewTicker(s.heartbeat / 2)
+	defer ticker.Stop()
+	testRequested := false
+	for {
+		if err := s.flush(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			s.logout(readErr, "gateway shutting down")
+			return nil
+		case err := <-readErr:
+			if errors.Is(err, errLoggedOut) {
+				_ = s.send(msgTypeLogout, nil)
+				return nil
+			}
+			return err
+		case <-s.svc.outbox.ready:
+		case now := <-ticker.C:
+			silence := now.Sub(time.Unix(0, s.lastRecv.Load()))
+			switch {
+			case silence > s.heartbeat+s.heartbeat/5 && !testRequested:
+				testRequested = true
+				if err := s.send(msgTypeTestRequest, []fixField{{tag: tagTestReqID, value: strconv.FormatInt(now.UnixMilli(), 10)}}); err != nil {
+					return err
+				}
+			case silence <= s.heartbeat:
+				testRequested = false
+			}
+			if now.Sub(s.sentAt()) >= s.heartbeat {
+				if err := s.send(msgTypeHeartbeat, nil); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// logon exchanges Logon messages. The initiator proposes the heartbeat interval and the acceptor
+// adopts it, checking that the counterparty's CompIDs mirror the configured ones. Sequence numbers
+// are reset when either side sets ResetSeqNumFlag; otherwise the counterparty's Logon is checked
+// against the sequence expected from the previous session.
+func (s *session) logon(initiator bool) error {
+	cfg := s.svc.cfg
+	reset := false
+	if initiator {
+		reset = s.svc.seqs.logon(cfg.ResetSeqNumOnLogon)
+		if err := s.sendLogon(reset); err != nil {
+			return err
+		}
+	}
+	_ = s.conn.SetReadDeadline(time.Now().Add(s.heartbeat))
+	msg, err := readFIX(s.reader)
+	if err != nil {
+		return fmt.Errorf("await logon: %w", err)
+	}
+	s.received()
+	if msg.msgType != msgTypeLogon {
+		return fmt.Errorf("expected logon, got message type %s", msg.msgType)
+	}
+	if msg.get(tagSenderCompID) != cfg.TargetCompID || msg.get(tagTargetCompID) != cfg.SenderCompID {
+		// The refusal goes out of sequence so an unknown peer cannot advance the session's numbers.
+		s.writeMu.Lock()
+		_ = s.write(encodeFIX(msgTypeLogout, cfg.SenderCompID, msg.get(tagSenderCompID), 1, time.Now(), []fixField{{tag: tagText, value: "unknown CompID"}}))
+		s.writeMu.Unlock()
+		return fmt.Errorf("logon from unexpected CompIDs %s -> %s", msg.get(tagSenderCompID), msg.get(tagTargetCompID))
+	}
+	peerReset := msg.get(tagResetSeqNumFlag) == "Y"
+	if !initiator {
+		reset = s.svc.seqs.logon(cfg.ResetSeqNumOnLogon || peerReset)
+		if seconds, err := strconv.Atoi(msg.get(tagHeartBtInt)); err == nil && seconds > 0 {
+			s.heartbeat = time.Duration(seconds) * time.Second
+		}
+		if err := s.sendLogon(reset); err != nil {
+			return err
+		}
+	}
+	if reset || peerReset {
+		s.svc.seqs.setExpectedIn(msg.seqNum() + 1)
+		return nil
+	}
+	_, err = s.sequenced(msg)
+	return err
+}
+
+func (s *session) sendLogon(reset bool) error {
+	body := []fixField{
+		{tag: tagEncryptMethod, value: "0"},
+		{tag: tagHeartBtInt, value: strconv.Itoa(int(s.heartbeat / time.Second))},
+	}
+	if reset {
+		body = append(body, fixField{tag: tagResetSeqNumFlag, value: "Y"})
+	}
+	return s.send(msgTypeLogon, body)
+}
+
+// readLoop answers the counterparty's session messages until the connection fails or it logs out.
+// A counterparty silent for two heartbeat intervals after a TestRequest is disconnected.
+func (s *session) readLoop() error {
+	for {
+		_ = s.conn.SetReadDeadline(time.Now().Add(2*s.heartbeat + s.heartbeat/5))
+		msg, err := readFIX(s.reader)
+		if err != nil {
+			return err
+		}
+		s.received()
+		newSeq, _ := strconv.Atoi(msg.get(tagNewSeqNo))
+		if msg.msgType == msgTypeSequenceReset && msg.get(tagGapFillFlag) != "Y" {
+			// Reset mode ignores MsgSeqNum and moves the expected number outright.
+			if newSeq > 0 {
+				s.svc.seqs.setExpectedIn(newSeq)
+			}
+			continue
+		}
+		if ok, err := s.sequenced(msg); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		switch msg.msgType {
+		case msgTypeTestRequest:
+			if err := s.send(msgTypeHeartbeat, []fixField{{tag: tagTestReqID, value: msg.get(tagTestReqID)}}); err != nil {
+				return err
+			}
+		case msgTypeResendRequest:
+			begin, _ := strconv.Atoi(msg.get(tagBeginSeqNo))
+			end, _ := strconv.Atoi(msg.get(tagEndSeqNo))
+			if err := s.resend(begin, end); err != nil {
+				return err
+			}
+		case msgTypeSequenceReset:
+			if newSeq > msg.seqNum()+1 {
+				s.svc.seqs.setExpectedIn(newSeq)
+			}
+		case msgTypeLogout:
+			return errLoggedOut
+		case msgTypeReject:
+			s.svc.logger.Printf("fix drop-copy: counterparty rejected message %s: %s", msg.get(tagRefSeqNum), msg.get(tagText))
+		}
+	}
+}
+
+// sequenced checks msg's MsgSeqNum against the one expected and reports whether msg should be
+// processed. A gap is asked for again with a ResendRequest; a lower number is a duplicate when
+// PossDupFlag is set and ends the session otherwise.
+func (s *session) sequenced(msg fixMessage) (bool, error) {
+	seq := msg.seqNum()
+	expected := s.svc.seqs.expectedIn()
+	switch {
+	case seq == expected:
+	case seq > expected:
+		s.svc.logger.Printf("fix drop-copy: counterparty sequence gap, expected %d but received %d", expected, seq)
+		if err := s.send(msgTypeResendRequest, []fixField{
+			{tag: tagBeginSeqNo, value: strconv.Itoa(expected)},
+			{tag: tagEndSeqNo, value: strconv.Itoa(seq - 1)},
+		}); err != nil {
+			return false, err
+		}
+	case msg.get(tagPossDupFlag) == "Y":
+		return false, nil
+	default:
+		text := fmt.Sprintf("MsgSeqNum too low, expecting %d but received %d", expected, seq)
+		_ = s.send(msgTypeLogout, []fixField{{tag: tagText, value: text}})
+		return false, errors.New(text)
+	}
+	s.svc.seqs.setExpectedIn(seq + 1)
+	return true, nil
+}
+
+// flush writes the outbox, removing each report only once written.
+func (s *session) flush(ctx context.Context) error {
+	for {
+		r, ok := s.svc.outbox.peek()
+		if !ok {
+			return nil
+		}
+		body, ok := executionReportFields(r)
+		if ok {
+			if err := s.send(msgTypeExecutionReport, body); err != nil {
+				return err
+			}
+			s.svc.record(ctx, "sent")
+		}
+		s.svc.outbox.pop()
+	}
+}
+
+// logout sends a Logout and waits briefly for the counterparty's answer.
+func (s *session) logout(readErr <-chan error, reason string) {
+	if s.send(msgTypeLogout, []fixField{{tag: tagText, value: reason}}) != nil {
+		return
+	}
+	select {
+	case <-readErr:
+	case <-time.After(logoutGrace):
+	}
+}
+
+func (s *session) send(msgType string, body []fixField) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	seq := s.svc.seqs.nextOutbound()
+	now := time.Now()
+	if err := s.write(encodeFIX(msgType, s.svc.cfg.SenderCompID, s.svc.cfg.TargetCompID, seq, now, body)); err != nil {
+		return err
+	}
+	s.svc.seqs.record(seq, msgType, body, now)
+	return nil
+}
+
+// resend answers a ResendRequest for begin through end, where end 0 means everything sent.
+// Stored execution reports go out again under their original numbers with PossDupFlag and
+// OrigSendingTime; session messages and reports no longer stored are skipped with
+// SequenceReset-GapFill.
+func (s *session) resend(begin, end int) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	begin = max(begin, 1)
+	stored, end, evicted := s.svc.seqs.between(begin, end)
+	if begin > end {
+		return nil
+	}
+	if evicted {
+		s.svc.logger.Printf("fix drop-copy: resend from %d reaches reports no longer stored; gap-filling them", begin)
+	}
+	next := begin
+	for _, msg := range stored {
+		if msg.seq > next {
+			if err := s.gapFill(next, msg.seq); err != nil {
+				return err
+			}
+		}
+		body := append([]fixField{
+			{tag: tagPossDupFlag, value: "Y"},
+			{tag: tagOrigSendingTime, value: msg.sentAt.UTC().Format(fixTimeLayout)},
+		}, msg.body...)
+		if err := s.write(encodeFIX(msg.msgType, s.svc.cfg.SenderCompID, s.svc.cfg.TargetCompID, msg.seq, time.Now(), body)); err != nil {
+			return err
+		}
+		next = msg.seq + 1
+	}
+	if next <= end {
+		return s.gapFill(next, end+1)
+	}
+	return nil
+}
+
+// gapFill sends a SequenceReset-GapFill numbered begin that skips to next. The caller holds
+// writeMu.
+func (s *session) gapFill(begin, next int) error {
+	return s.write(encodeFIX(msgTypeSequenceReset, s.svc.cfg.SenderCompID, s.svc.cfg.TargetCompID, begin, time.Now(), []fixField{
+		{tag: tagPossDupFlag, value: "Y"},
+		{tag: tagGapFillFlag, value: "Y"},
+		{tag: tagNewSeqNo, value: strconv.Itoa(next)},
+	}))
+}
+
+func (s *session) write(data []byte) error {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(sessionWriteTimeout))
+	if _, err := s.conn.Write(data); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	s.lastSent = time.Now()
+	return nil
+}
+
+func (s *session) sentAt() time.Time {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.lastSent
+}
+
+func (s *session) received() {
+	s.lastRecv.Store(time.Now().UnixNano())
+}
@@ -0,0 +1,277 @@
+// Package egressip reports the gateway's public egress IPs. Exchanges enforce API-key IP
+// allow-lists, so the monitor looks the addresses up through configurable checker services,
+// warns when they change and flags addresses missing from the configured allow-list.
+package egressip
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// maxResponseBytes bounds how much of a checker response is read.
+const maxResponseBytes = 256
+
+// CheckerResult is the answer of one checker.
+type CheckerResult struct {
+	Checker   string  `json:"checker"`
+	IP        string  `json:"ip,omitempty"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latencyMs"`
+}
+
+// Report describes the egress IPs seen by the latest check.
+type Report struct {
+	// IPs are the distinct addresses reported by the checkers. When every checker fails they
+	// carry over from the last successful check and Stale is set.
+	IPs       []string        `json:"ips"`
+	Stale     bool            `json:"stale"`
+	Checkers  []CheckerResult `json:"checkers"`
+	CheckedAt time.Time       `json:"checkedAt"`
+	// ChangedAt is when the IPs last differed from the previous successful check.
+	ChangedAt   *time.Time `json:"changedAt,omitempty"`
+	PreviousIPs []string   `json:"previousIps,omitempty"`
+	AllowList   []string   `json:"allowList,omitempty"`
+	// NotAllowed lists egress IPs outside the allow-list.
+	NotAllowed []string `json:"notAllowed,omitempty"`
+}
+
+// Option configures a Monitor.
+type Option func(*Monitor)
+
+// WithClock overrides the time source.
+func WithClock(clock func() time.Time) Option {
+	return func(m *Monitor) {
+		if clock != nil {
+			m.clock = clock
+		}
+	}
+}
+
+// WithLogger overrides the monitor logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(m *Monitor) {
+		if logger != nil {
+			m.logger = logger
+		}
+	}
+}
+
+// WithHTTPClient overrides the client used to query checkers.
+func WithHTTPClient(client *http.Client) Option {
+	return func(m *Monitor) {
+		if client != nil {
+			m.client = client
+		}
+	}
+}
+
+// Monitor periodically looks up the gateway's public egress IPs.
+type Monitor struct {
+	cfg       config.EgressConfig
+	allowList []netip.Prefix
+	client    *http.Client
+	clock     func() time.Time
+	logger    *log.Logger
+
+	mu      sync.Mutex
+	report  Report
+	checked bool
+}
+
+// New constructs a monitor from cfg. The configuration is expected to be validated.
+func New(cfg config.EgressConfig, opts ...Option) *Monitor {
+	allowList, _ := config.ParseEgressAllowList(cfg.AllowList)
+	m := &Monitor{
+		cfg:       cfg,
+		allowList: allowList,
+		client: &http.Client{
+			Transport:     nil,
+			CheckRedirect: nil,
+			Jar:           nil,
+			Timeout:       cfg.Timeout,
+		},
+		clock:   time.Now,
+		logger:  log.New(redact.Stdout, "egress ", log.LstdFlags|log.Lmicroseconds),
+		mu:      sync.Mutex{},
+		report:  Report{IPs: nil, Stale: false, Checkers: nil, CheckedAt: time.Time{}, ChangedAt: nil, PreviousIPs: nil, AllowList: nil, NotAllowed: nil},
+		checked: false,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	return m
+}
+
+// Run checks immediately and then every configured interval until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	m.Check(ctx)
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Check(ctx)
+		}
+	}
+}
+
+// Snapshot returns the latest report and whether a check has completed.
+func (m *Monitor) Snapshot() (Report, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return cloneReport(m.report), m.checked
+}
+
+// Check queries every checker, records the result and logs a warning when the egress IPs
+// changed or fall outside the allow-list.
+func (m *Monitor) Check(ctx context.Context) Report {
+	results := make([]CheckerResult, len(m.cfg.Checkers))
+	var wg sync.WaitGroup
+	for i, checker := range m.cfg.Checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = m.query(ctx, checker)
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]struct{}, len(results))
+	ips := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.IP == "" {
+			continue
+		}
+		if _, ok := seen[result.IP]; !ok {
+			seen[result.IP] = struct{}{}
+			ips = append(ips, result.IP)
+		}
+	}
+	slices.Sort(ips)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	previous := m.report
+	report := Report{
+		IPs:         ips,
+		Stale:       false,
+		Checkers:    results,
+		CheckedAt:   m.clock().UTC(),
+		ChangedAt:   previous.ChangedAt,
+		PreviousIPs: previous.PreviousIPs,
+		AllowList:   slices.Clone(m.cfg.AllowList),
+		NotAllowed:  nil,
+	}
+	switch {
+	case len(ips) == 0:
+		report.IPs = previous.IPs
+		report.Stale = true
+		m.logger.Printf("egress IP check failed on all %d checker(s); keeping %s", len(results), describeIPs(previous.IPs))
+	case m.checked && len(previous.IPs) > 0 && !slices.Equal(previous.IPs, ips):
+		changedAt := report.CheckedAt
+		report.ChangedAt = &changedAt
+		report.PreviousIPs = previous.IPs
+		m.logger.Printf("WARNING egress IP changed from %s to %s; exchange API keys restricted to the old address will reject signed requests",
+			describeIPs(previous.IPs), describeIPs(ips))
+	case !m.checked || len(previous.IPs) == 0:
+		m.logger.Printf("egress IP: %s", describeIPs(ips))
+	}
+	report.NotAllowed = m.notAllowed(report.IPs)
+	if len(report.NotAllowed) > 0 && (!slices.Equal(report.NotAllowed, previous.NotAllowed) || !m.checked) {
+		m.logger.Printf("WARNING egress IP %s not in the configured allow-list", strings.Join(report.NotAllowed, ", "))
+	}
+	m.report = report
+	m.checked = true
+	return cloneReport(report)
+}
+
+func (m *Monitor) query(ctx context.Context, checker string) CheckerResult {
+	result := CheckerResult{Checker: checker, IP: "", Error: "", LatencyMs: 0}
+	started := m.clock()
+	ip, err := m.fetch(ctx, checker)
+	result.LatencyMs = float64(m.clock().Sub(started)) / float64(time.Millisecond)
+	if err != nil {
+		result.Error = redact.Error(err)
+		return result
+	}
+	result.IP = ip
+	return result
+}
+
+func (m *Monitor) fetch(ctx context.Context, checker string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checker, nil)
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "text/plain")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("query checker: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("checker responded %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return "", fmt.Errorf("read checker response: %w", err)
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(string(body)))
+	if err != nil {
+		return "", fmt.Errorf("checker response is not an IP address")
+	}
+	return addr.Unmap().String(), nil
+}
+
+func (m *Monitor) notAllowed(ips []string) []string {
+	if len(m.allowList) == 0 {
+		return nil
+	}
+	var out []string
+	for _, ip := range ips {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			continue
+		}
+		if !slices.ContainsFunc(m.allowList, func(prefix netip.Prefix) bool { return prefix.Contains(addr) }) {
+			out = append(out, ip)
+		}
+	}
+	return out
+}
+
+func describeIPs(ips []string) string {
+	if len(ips) == 0 {
+		return "no known address"
+	}
+	return strings.Join(ips, ", ")
+}
+
+func cloneReport(report Report) Report {
+	clone := report
+	clone.IPs = slices.Clone(report.IPs)
+	clone.Checkers = slices.Clone(report.Checkers)
+	clone.PreviousIPs = slices.Clone(report.PreviousIPs)
+	clone.AllowList = slices.Clone(report.AllowList)
+	clone.NotAllowed = slices.Clone(report.NotAllowed)
+	if report.ChangedAt != nil {
+		changedAt := *report.ChangedAt
+		clone.ChangedAt = &changedAt
+	}
+	return clone
+}
@@ -0,0 +1,98 @@
+package egressip
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+func newChecker(t *testing.T, ip *atomic.Value) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		value, _ := ip.Load().(string)
+		if value == "" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = io.WriteString(w, value+"\n")
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestMonitorDetectsChanges(t *testing.T) {
+	var primary, secondary atomic.Value
+	primary.Store("203.0.113.7")
+	secondary.Store("203.0.113.7")
+	var logs bytes.Buffer
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	monitor := New(config.EgressConfig{
+		Enabled:   true,
+		Checkers:  []string{newChecker(t, &primary), newChecker(t, &secondary)},
+		Interval:  time.Minute,
+		Timeout:   time.Second,
+		AllowList: []string{"203.0.113.0/28"},
+	}, WithLogger(log.New(&logs, "", 0)), WithClock(func() time.Time { return now }))
+
+	if _, checked := monitor.Snapshot(); checked {
+		t.Fatal("expected no report before the first check")
+	}
+	report := monitor.Check(context.Background())
+	if len(report.IPs) != 1 || report.IPs[0] != "203.0.113.7" || report.ChangedAt != nil || len(report.NotAllowed) != 0 {
+		t.Fatalf("unexpected first report %+v", report)
+	}
+	if !strings.Contains(logs.String(), "egress IP: 203.0.113.7") {
+		t.Fatalf("expected startup log, got %q", logs.String())
+	}
+
+	now = now.Add(time.Minute)
+	secondary.Store("198.51.100.20")
+	report = monitor.Check(context.Background())
+	if strings.Join(report.IPs, ",") != "198.51.100.20,203.0.113.7" || report.ChangedAt == nil || !report.ChangedAt.Equal(now) {
+		t.Fatalf("expected change to two IPs, got %+v", report)
+	}
+	if strings.Join(report.PreviousIPs, ",") != "203.0.113.7" || strings.Join(report.NotAllowed, ",") != "198.51.100.20" {
+		t.Fatalf("expected previous IPs and allow-list violation, got %+v", report)
+	}
+	if !strings.Contains(logs.String(), "WARNING egress IP changed from 203.0.113.7 to 198.51.100.20, 203.0.113.7") ||
+		!strings.Contains(logs.String(), "WARNING egress IP 198.51.100.20 not in the configured allow-list") {
+		t.Fatalf("expected change warnings, got %q", logs.String())
+	}
+
+	now = now.Add(time.Minute)
+	primary.Store("")
+	secondary.Store("")
+	report = monitor.Check(context.Background())
+	if !report.Stale || len(report.IPs) != 2 || report.Checkers[0].Error == "" {
+		t.Fatalf("expected stale report keeping the last IPs, got %+v", report)
+	}
+	snapshot, checked := monitor.Snapshot()
+	if !checked || !snapshot.Stale || !snapshot.ChangedAt.Equal(now.Add(-time.Minute)) {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+}
+
+func TestMonitorRejectsNonIPResponses(t *testing.T) {
+	var body atomic.Value
+	body.Store("<html>captive portal</html>")
+	monitor := New(config.EgressConfig{
+		Enabled:   true,
+		Checkers:  []string{newChecker(t, &body)},
+		Interval:  time.Minute,
+		Timeout:   time.Second,
+		AllowList: nil,
+	}, WithLogger(log.New(io.Discard, "", 0)))
+	report := monitor.Check(context.Background())
+	if len(report.IPs) != 0 || !report.Stale || !strings.Contains(report.Checkers[0].Error, "not an IP address") {
+		t.Fatalf("expected rejected response, got %+v", report)
+	}
+}
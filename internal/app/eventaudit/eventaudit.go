@@ -0,0 +1,473 @@
+// Package eventaudit samples live provider events and compares the shape of their fields with
+// golden fixtures. Adapters normalise venue payloads but pass values such as prices through as
+// received, so a venue that starts sending scientific notation, empty strings or new fields shows
+// up here as a change in field distribution before strategies trip over it.
+package eventaudit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// auditedEventTypes are the provider data streams whose payloads are sampled.
+var auditedEventTypes = []schema.EventType{
+	schema.EventTypeTrade,
+	schema.EventTypeTicker,
+	schema.EventTypeBookSnapshot,
+	schema.EventTypeKlineSummary,
+	schema.EventTypeExecReport,
+	schema.EventTypeBalanceUpdate,
+	schema.EventTypeInstrumentUpdate,
+}
+
+// Reasons a field drifts.
+const (
+	// ReasonUnexpectedKind means the field held kinds of value its fixture does not.
+	ReasonUnexpectedKind = "unexpected_kind"
+	// ReasonMissing means a field every fixture sample carries was absent.
+	ReasonMissing = "missing"
+	// ReasonUnexpectedField means the field does not appear in the fixture.
+	ReasonUnexpectedField = "unexpected_field"
+)
+
+// FieldStats is the distribution of one field over a window's samples.
+type FieldStats struct {
+	Field string `json:"field"`
+	// Kinds counts the samples holding each kind of value; a sample may count under several when
+	// the field is inside an array.
+	Kinds   map[Kind]int64 `json:"kinds"`
+	Missing int64          `json:"missing,omitempty"`
+}
+
+// Drift reports a field whose distribution departs from its fixture.
+type Drift struct {
+	Provider  string           `json:"provider"`
+	EventType schema.EventType `json:"eventType"`
+	Field     string           `json:"field"`
+	Reason    string           `json:"reason"`
+	Expected  []Kind           `json:"expected,omitempty"`
+	Observed  map[Kind]int64   `json:"observed,omitempty"`
+	// Share is the fraction of the window's samples that deviated.
+	Share   float64   `json:"share"`
+	Samples int64     `json:"samples"`
+	Since   time.Time `json:"since"`
+}
+
+// RouteReport describes the latest evaluated window of one provider's event type.
+type RouteReport struct {
+	Provider  string           `json:"provider"`
+	EventType schema.EventType `json:"eventType"`
+	// Fixture is the golden fixture file; routes without one are sampled but not judged.
+	Fixture string       `json:"fixture,omitempty"`
+	Samples int64        `json:"samples"`
+	Fields  []FieldStats `json:"fields,omitempty"`
+	Drift   []Drift      `json:"drift,omitempty"`
+}
+
+// Report is the outcome of the latest evaluated window.
+type Report struct {
+	SampleRatio float64       `json:"sampleRatio"`
+	Fixtures    int           `json:"fixtures"`
+	WindowStart time.Time     `json:"windowStart"`
+	EvaluatedAt *time.Time    `json:"evaluatedAt,omitempty"`
+	Routes      []RouteReport `json:"routes"`
+}
+
+// Option configures a Monitor.
+type Option func(*Monitor)
+
+// WithClock overrides the time source.
+func WithClock(clock func() time.Time) Option {
+	return func(m *Monitor) {
+		if clock != nil {
+			m.clock = clock
+		}
+	}
+}
+
+// WithLogger overrides the monitor logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(m *Monitor) {
+		if logger != nil {
+			m.logger = logger
+		}
+	}
+}
+
+// WithSampler overrides the sampling decision, which defaults to a random draw against the
+// configured ratio.
+func WithSampler(sample func() bool) Option {
+	return func(m *Monitor) {
+		if sample != nil {
+			m.sample = sample
+		}
+	}
+}
+
+type routeKey struct {
+	provider  string
+	eventType schema.EventType
+}
+
+type fieldCounts struct {
+	kinds   map[Kind]int64
+	missing int64
+	// deviating counts samples whose value kinds fall outside the fixture.
+	deviating int64
+}
+
+type routeWindow struct {
+	provider string
+	samples  int64
+	fields   map[string]*fieldCounts
+}
+
+// Monitor samples provider events and raises drift alerts at the end of every window.
+type Monitor struct {
+	cfg      config.EventAuditConfig
+	bus      eventbus.Bus
+	pools    *pool.PoolManager
+	fixtures Fixtures
+	clock    func() time.Time
+	logger   *log.Logger
+	sample   func() bool
+	alerts   metric.Int64Counter
+
+	mu          sync.Mutex
+	windowStart time.Time
+	window      map[routeKey]*routeWindow
+	report      Report
+	// active holds the drifting fields of the latest window with when they started drifting.
+	active map[string]time.Time
+}
+
+// New constructs a monitor reading provider events from bus and judging them against fixtures.
+func New(cfg config.EventAuditConfig, bus eventbus.Bus, pools *pool.PoolManager, fixtures Fixtures, opts ...Option) *Monitor {
+	m := &Monitor{
+		cfg:         cfg,
+		bus:         bus,
+		pools:       pools,
+		fixtures:    fixtures,
+		clock:       time.Now,
+		logger:      log.New(redact.Stdout, "event-audit ", log.LstdFlags|log.Lmicroseconds),
+		sample:      nil,
+		alerts:      nil,
+		mu:          sync.Mutex{},
+		windowStart: time.Time{},
+		window:      make(map[routeKey]*routeWindow),
+		report:      Report{SampleRatio: cfg.SampleRatio, Fixtures: fixtures.Len(), WindowStart: time.Time{}, EvaluatedAt: nil, Routes: []RouteReport{}},
+		active:      make(map[string]time.Time),
+	}
+	m.sample = func() bool { return rand.Float64() < m.cfg.SampleRatio } //nolint:gosec // sampling, not security
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	m.windowStart = m.clock().UTC()
+	m.report.WindowStart = m.windowStart
+	alerts, err := otel.Meter("eventaudit").Int64Counter("meltica_event_audit_drift_total",
+		metric.WithDescription("Payload fields found drifting from their golden fixture, per window"),
+		metric.WithUnit("{field}"),
+	)
+	if err != nil {
+		m.logger.Printf("event audit: register drift counter: %v", err)
+		alerts = nil
+	}
+	m.alerts = alerts
+	return m
+}
+
+// Run samples provider events and evaluates them every window until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	if m.bus != nil {
+		for _, typ := range auditedEventTypes {
+			id, ch, err := m.bus.Subscribe(ctx, typ)
+			if err != nil {
+				m.logger.Printf("event audit: subscribe %s: %v", typ, err)
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer m.bus.Unsubscribe(id)
+				m.consume(ctx, ch)
+			}()
+		}
+	}
+	defer wg.Wait()
+
+	ticker := time.NewTicker(m.cfg.Window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Evaluate()
+		}
+	}
+}
+
+func (m *Monitor) consume(ctx context.Context, ch <-chan *schema.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-ch:
+			if !ok {
+				return
+			}
+			if evt == nil {
+				continue
+			}
+			if m.sample() {
+				m.Observe(evt)
+			}
+			if m.pools != nil {
+				m.pools.TryReturnEventInst(evt)
+			}
+		}
+	}
+}
+
+// Observe records the shape of a sampled event into the current window.
+func (m *Monitor) Observe(evt *schema.Event) {
+	if evt == nil || evt.Payload == nil {
+		return
+	}
+	provider := strings.TrimSpace(evt.Provider)
+	if provider == "" {
+		return
+	}
+	shape, err := ShapeOf(evt.Payload)
+	if err != nil {
+		return
+	}
+	fixture, hasFixture := m.fixtures.Lookup(provider, evt.Type)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := routeKey{provider: strings.ToLower(provider), eventType: evt.Type}
+	window, ok := m.window[key]
+	if !ok {
+		window = &routeWindow{provider: provider, samples: 0, fields: make(map[string]*fieldCounts)}
+		m.window[key] = window
+	}
+	window.samples++
+	for name, kinds := range shape {
+		counts := window.counts(name)
+		for kind := range kinds {
+			counts.kinds[kind]++
+		}
+		if hasFixture && fixture.deviates(name, kinds) {
+			counts.deviating++
+		}
+	}
+	if !hasFixture {
+		return
+	}
+	for name, golden := range fixture.fields {
+		if _, present := shape[name]; present || !golden.required {
+			continue
+		}
+		if parent, nested := parentPath(name); nested {
+			if _, present := shape[parent]; !present {
+				continue
+			}
+		}
+		window.counts(name).missing++
+	}
+}
+
+func (w *routeWindow) counts(name string) *fieldCounts {
+	counts, ok := w.fields[name]
+	if !ok {
+		counts = &fieldCounts{kinds: make(map[Kind]int64), missing: 0, deviating: 0}
+		w.fields[name] = counts
+	}
+	return counts
+}
+
+// deviates reports whether a sampled field holds a kind its fixture does not allow, or is absent
+// from the fixture altogether.
+func (f *Fixture) deviates(name string, kinds map[Kind]struct{}) bool {
+	golden, ok := f.fields[name]
+	if !ok {
+		return true
+	}
+	for kind := range kinds {
+		if _, allowed := golden.kinds[kind]; !allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// parentPath returns the path of the object or array holding the field, if it is nested.
+func parentPath(name string) (string, bool) {
+	if parent, ok := strings.CutSuffix(name, "[]"); ok {
+		return parent, true
+	}
+	if i := strings.LastIndex(name, "."); i > 0 {
+		return name[:i], true
+	}
+	return "", false
+}
+
+// Evaluate closes the current window, compares each route's field distributions with its fixture,
+// logs fields that started or stopped drifting and starts a new window.
+func (m *Monitor) Evaluate() Report {
+	now := m.clock().UTC()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	routes := make([]RouteReport, 0, len(m.window))
+	active := make(map[string]time.Time)
+	for key, window := range m.window {
+		route := RouteReport{
+			Provider:  window.provider,
+			EventType: key.eventType,
+			Fixture:   "",
+			Samples:   window.samples,
+			Fields:    make([]FieldStats, 0, len(window.fields)),
+			Drift:     nil,
+		}
+		for name, counts := range window.fields {
+			route.Fields = append(route.Fields, FieldStats{Field: name, Kinds: counts.kinds, Missing: counts.missing})
+		}
+		slices.SortFunc(route.Fields, func(a, b FieldStats) int { return strings.Compare(a.Field, b.Field) })
+		if fixture, ok := m.fixtures.Lookup(window.provider, key.eventType); ok {
+			route.Fixture = fixture.Path
+			if window.samples >= int64(m.cfg.MinSamples) {
+				route.Drift = m.judge(window, key.eventType, fixture)
+			}
+		}
+		for i := range route.Drift {
+			drift := &route.Drift[i]
+			alertKey := window.provider + "|" + string(key.eventType) + "|" + drift.Field
+			since, ongoing := m.active[alertKey]
+			if !ongoing {
+				since = now
+				m.raise(*drift)
+			}
+			drift.Since = since
+			active[alertKey] = since
+		}
+		routes = append(routes, route)
+	}
+	for alertKey := range m.active {
+		if _, ok := active[alertKey]; !ok {
+			m.logger.Printf("event audit: drift cleared on %s", strings.ReplaceAll(alertKey, "|", " "))
+		}
+	}
+	slices.SortFunc(routes, func(a, b RouteReport) int {
+		if c := strings.Compare(a.Provider, b.Provider); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.EventType), string(b.EventType))
+	})
+
+	evaluatedAt := now
+	m.report = Report{
+		SampleRatio: m.cfg.SampleRatio,
+		Fixtures:    m.fixtures.Len(),
+		WindowStart: m.windowStart,
+		EvaluatedAt: &evaluatedAt,
+		Routes:      routes,
+	}
+	m.active = active
+	m.window = make(map[routeKey]*routeWindow)
+	m.windowStart = now
+	return m.report
+}
+
+// judge returns the fields of a window deviating from the fixture on at least the threshold share
+// of samples.
+func (m *Monitor) judge(window *routeWindow, eventType schema.EventType, fixture *Fixture) []Drift {
+	var drifts []Drift
+	for name, counts := range window.fields {
+		golden, known := fixture.fields[name]
+		reason := ReasonUnexpectedKind
+		deviating := counts.deviating
+		switch {
+		case !known:
+			reason = ReasonUnexpectedField
+		case counts.missing > counts.deviating:
+			reason = ReasonMissing
+			deviating = counts.missing
+		}
+		share := float64(deviating) / float64(window.samples)
+		if deviating == 0 || share < m.cfg.Threshold {
+			continue
+		}
+		drift := Drift{
+			Provider:  window.provider,
+			EventType: eventType,
+			Field:     name,
+			Reason:    reason,
+			Expected:  nil,
+			Observed:  counts.kinds,
+			Share:     share,
+			Samples:   window.samples,
+			Since:     time.Time{},
+		}
+		if known {
+			drift.Expected = sortedKinds(golden.kinds)
+		}
+		drifts = append(drifts, drift)
+	}
+	slices.SortFunc(drifts, func(a, b Drift) int { return strings.Compare(a.Field, b.Field) })
+	return drifts
+}
+
+func (m *Monitor) raise(drift Drift) {
+	m.logger.Printf("event audit: drift on %s %s field %s: %s in %.1f%% of %d samples (expected %v, observed %s)",
+		drift.Provider, drift.EventType, drift.Field, drift.Reason, drift.Share*100, drift.Samples, drift.Expected, formatKinds(drift.Observed))
+	if m.alerts != nil {
+		m.alerts.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("environment", telemetry.Environment()),
+			attribute.String("provider", drift.Provider),
+			attribute.String("event_type", string(drift.EventType)),
+			attribute.String("field", drift.Field),
+			attribute.String("reason", drift.Reason),
+		))
+	}
+}
+
+// Snapshot returns the report of the latest evaluated window.
+func (m *Monitor) Snapshot() Report {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.report
+}
+
+func formatKinds(kinds map[Kind]int64) string {
+	names := make(map[Kind]struct{}, len(kinds))
+	for kind := range kinds {
+		names[kind] = struct{}{}
+	}
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range sortedKinds(names) {
+		parts = append(parts, fmt.Sprintf("%s=%d", kind, kinds[kind]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,159 @@
+package eventaudit
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+func TestShapeOfClassifiesValues(t *testing.T) {
+	shape, err := ShapeOf(map[string]any{
+		"price":   "1.5e-8",
+		"qty":     "0.001",
+		"at":      "2026-03-01T12:00:00.123Z",
+		"id":      "abc-1",
+		"reason":  "",
+		"levels":  []any{map[string]any{"price": "1"}},
+		"count":   3,
+		"enabled": true,
+	})
+	if err != nil {
+		t.Fatalf("ShapeOf: %v", err)
+	}
+	want := map[string]Kind{
+		"price": KindScientific, "qty": KindDecimal, "at": KindTimestamp, "id": KindText, "reason": KindEmpty,
+		"levels": KindArray, "levels[]": KindObject, "levels[].price": KindDecimal, "count": KindNumber, "enabled": KindBool,
+	}
+	for path, kind := range want {
+		if _, ok := shape[path][kind]; !ok || len(shape[path]) != 1 {
+			t.Fatalf("%s: expected %s, got %v", path, kind, shape[path])
+		}
+	}
+}
+
+func TestLoadFixturesFallsBackToDefault(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, DefaultProvider, "Trade", `{"price":"1","quantity":"2"}`)
+	writeFixture(t, dir, "okx-spot", "Trade", `[{"price":"1"},{"price":"1","quantity":"2"}]`)
+	fixtures, err := LoadFixtures(dir)
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	own, ok := fixtures.Lookup("OKX-SPOT", schema.EventTypeTrade)
+	if !ok || own.fields["quantity"].required || !own.fields["price"].required {
+		t.Fatalf("unexpected provider fixture %+v", own)
+	}
+	fallback, ok := fixtures.Lookup("binance-spot", schema.EventTypeTrade)
+	if !ok || filepath.Base(filepath.Dir(fallback.Path)) != DefaultProvider {
+		t.Fatalf("expected default fixture, got %+v", fallback)
+	}
+	if _, ok := fixtures.Lookup("binance-spot", schema.EventTypeTicker); ok {
+		t.Fatal("expected no ticker fixture")
+	}
+	missing, err := LoadFixtures(filepath.Join(dir, "absent"))
+	if err != nil || missing.Len() != 0 {
+		t.Fatalf("expected no fixtures for a missing directory, got %d, %v", missing.Len(), err)
+	}
+}
+
+func TestShippedFixturesParse(t *testing.T) {
+	fixtures, err := LoadFixtures(filepath.Join("..", "..", "..", "config", "event-fixtures"))
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	for _, typ := range []schema.EventType{schema.EventTypeTrade, schema.EventTypeBookSnapshot, schema.EventTypeExecReport} {
+		if _, ok := fixtures.Lookup("binance-spot", typ); !ok {
+			t.Fatalf("expected a default %s fixture", typ)
+		}
+	}
+}
+
+func TestEvaluateRaisesAndClearsDrift(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, DefaultProvider, "Trade", `{"tradeId":"1","price":"64250.12","quantity":"0.5"}`)
+	fixtures, err := LoadFixtures(dir)
+	if err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	var logs bytes.Buffer
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	cfg := config.EventAuditConfig{Enabled: true, SampleRatio: 1, Fixtures: dir, Window: time.Minute, Threshold: 0.2, MinSamples: 4}
+	monitor := New(cfg, nil, nil, fixtures, WithClock(func() time.Time { return now }), WithLogger(log.New(&logs, "", 0)))
+
+	trade := func(payload map[string]any) {
+		monitor.Observe(&schema.Event{Provider: "binance-spot", Type: schema.EventTypeTrade, Payload: payload})
+	}
+	for i := 0; i < 3; i++ {
+		trade(map[string]any{"tradeId": "1", "price": "64250.12", "quantity": "0.5"})
+	}
+	trade(map[string]any{"tradeId": "1", "price": "6.425012e4", "venueFlag": true})
+
+	report := monitor.Evaluate()
+	if len(report.Routes) != 1 || report.Routes[0].Samples != 4 {
+		t.Fatalf("unexpected routes %+v", report.Routes)
+	}
+	drift := report.Routes[0].Drift
+	reasons := make(map[string]string, len(drift))
+	for _, entry := range drift {
+		reasons[entry.Field] = entry.Reason
+		if !entry.Since.Equal(now) {
+			t.Fatalf("expected drift since %v, got %v", now, entry.Since)
+		}
+	}
+	want := map[string]string{"price": ReasonUnexpectedKind, "quantity": ReasonMissing, "venueFlag": ReasonUnexpectedField}
+	if len(reasons) != len(want) {
+		t.Fatalf("unexpected drift %+v", drift)
+	}
+	for field, reason := range want {
+		if reasons[field] != reason {
+			t.Fatalf("%s: expected %s, got %q", field, reason, reasons[field])
+		}
+	}
+	if !strings.Contains(logs.String(), "drift on binance-spot Trade field price") {
+		t.Fatalf("expected drift logged, got %q", logs.String())
+	}
+
+	now = now.Add(time.Minute)
+	for i := 0; i < 4; i++ {
+		trade(map[string]any{"tradeId": "1", "price": "64250.12", "quantity": "0.5"})
+	}
+	if report := monitor.Evaluate(); len(report.Routes[0].Drift) != 0 {
+		t.Fatalf("expected drift cleared, got %+v", report.Routes[0].Drift)
+	}
+	if !strings.Contains(logs.String(), "drift cleared on binance-spot Trade price") {
+		t.Fatalf("expected clearing logged, got %q", logs.String())
+	}
+}
+
+func TestEvaluateSkipsSmallWindows(t *testing.T) {
+	fixture, err := ParseFixture("Trade.json", []byte(`{"price":"1"}`))
+	if err != nil {
+		t.Fatalf("ParseFixture: %v", err)
+	}
+	fixtures := Fixtures{byKey: map[fixtureKey]*Fixture{{provider: DefaultProvider, eventType: schema.EventTypeTrade}: fixture}}
+	cfg := config.EventAuditConfig{Enabled: true, SampleRatio: 1, Window: time.Minute, Threshold: 0.01, MinSamples: 10}
+	monitor := New(cfg, nil, nil, fixtures, WithLogger(log.New(io.Discard, "", 0)))
+	monitor.Observe(&schema.Event{Provider: "okx-spot", Type: schema.EventTypeTrade, Payload: map[string]any{"price": "1e-8"}})
+	report := monitor.Evaluate()
+	if len(report.Routes) != 1 || report.Routes[0].Fixture != "Trade.json" || len(report.Routes[0].Drift) != 0 {
+		t.Fatalf("expected an unjudged route, got %+v", report.Routes)
+	}
+}
+
+func writeFixture(t *testing.T, dir, provider, eventType, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, provider), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, provider, eventType+".json"), []byte(body), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package eventaudit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// DefaultProvider is the fixture directory applying to providers without their own.
+const DefaultProvider = "_default"
+
+// field is the golden profile of one payload field.
+type field struct {
+	kinds map[Kind]struct{}
+	// required is set when every golden sample carries the field.
+	required bool
+}
+
+// Fixture is the golden shape of one provider's payloads of an event type, built from one or more
+// sample payloads.
+type Fixture struct {
+	Path   string
+	fields map[string]field
+}
+
+type fixtureKey struct {
+	provider  string
+	eventType schema.EventType
+}
+
+// Fixtures holds the golden fixtures by provider and event type.
+type Fixtures struct {
+	byKey map[fixtureKey]*Fixture
+}
+
+// LoadFixtures reads <dir>/<provider>/<EventType>.json files. Each holds a sample payload, or an
+// array of them when a field legitimately varies, such as a price absent from market orders. A
+// missing directory yields no fixtures.
+func LoadFixtures(dir string) (Fixtures, error) {
+	fixtures := Fixtures{byKey: make(map[fixtureKey]*Fixture)}
+	providers, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fixtures, nil
+	}
+	if err != nil {
+		return fixtures, fmt.Errorf("event audit: read fixtures: %w", err)
+	}
+	for _, provider := range providers {
+		if !provider.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(dir, provider.Name()))
+		if err != nil {
+			return fixtures, fmt.Errorf("event audit: read fixtures: %w", err)
+		}
+		for _, file := range files {
+			if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+				continue
+			}
+			path := filepath.Join(dir, provider.Name(), file.Name())
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return fixtures, fmt.Errorf("event audit: read fixture: %w", err)
+			}
+			fixture, err := ParseFixture(path, raw)
+			if err != nil {
+				return fixtures, err
+			}
+			key := fixtureKey{
+				provider:  strings.ToLower(provider.Name()),
+				eventType: schema.EventType(strings.TrimSuffix(file.Name(), ".json")),
+			}
+			fixtures.byKey[key] = fixture
+		}
+	}
+	return fixtures, nil
+}
+
+// ParseFixture builds a fixture from a sample payload or an array of them.
+func ParseFixture(path string, raw []byte) (*Fixture, error) {
+	var samples []json.RawMessage
+	if err := json.Unmarshal(raw, &samples); err != nil {
+		samples = []json.RawMessage{raw}
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("event audit: fixture %s holds no sample", path)
+	}
+	fixture := &Fixture{Path: path, fields: make(map[string]field)}
+	seen := make(map[string]int)
+	for _, sample := range samples {
+		shape, err := shapeOfJSON(sample)
+		if err != nil {
+			return nil, fmt.Errorf("event audit: fixture %s: %w", path, err)
+		}
+		for name, kinds := range shape {
+			entry, ok := fixture.fields[name]
+			if !ok {
+				entry = field{kinds: make(map[Kind]struct{}), required: false}
+			}
+			for kind := range kinds {
+				entry.kinds[kind] = struct{}{}
+			}
+			fixture.fields[name] = entry
+			seen[name]++
+		}
+	}
+	for name, entry := range fixture.fields {
+		entry.required = seen[name] == len(samples)
+		fixture.fields[name] = entry
+	}
+	return fixture, nil
+}
+
+// Lookup returns the fixture of provider and event type, falling back to the default provider.
+func (f Fixtures) Lookup(provider string, eventType schema.EventType) (*Fixture, bool) {
+	if fixture, ok := f.byKey[fixtureKey{provider: strings.ToLower(provider), eventType: eventType}]; ok {
+		return fixture, true
+	}
+	fixture, ok := f.byKey[fixtureKey{provider: DefaultProvider, eventType: eventType}]
+	return fixture, ok
+}
+
+// Len reports the number of fixtures.
+func (f Fixtures) Len() int {
+	return len(f.byKey)
+}
@@ -0,0 +1,143 @@
+package eventaudit
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+// Kind classifies the value of one payload field.
+type Kind string
+
+const (
+	// KindDecimal is a string holding a plain decimal number, such as "64000.5".
+	KindDecimal Kind = "decimal"
+	// KindScientific is a string holding a number in exponent notation, such as "1e-8".
+	KindScientific Kind = "scientific"
+	// KindTimestamp is an RFC 3339 timestamp string.
+	KindTimestamp Kind = "timestamp"
+	// KindEmpty is an empty string.
+	KindEmpty Kind = "empty"
+	// KindText is any other string.
+	KindText Kind = "text"
+	// KindNumber is a JSON number.
+	KindNumber Kind = "number"
+	// KindNumberExponent is a JSON number written in exponent notation.
+	KindNumberExponent Kind = "number-exponent"
+	// KindBool is a JSON boolean.
+	KindBool Kind = "bool"
+	// KindNull is a JSON null.
+	KindNull Kind = "null"
+	// KindObject is a JSON object.
+	KindObject Kind = "object"
+	// KindArray is a JSON array.
+	KindArray Kind = "array"
+)
+
+var (
+	decimalPattern    = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)$`)
+	scientificPattern = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)[eE][-+]?\d+$`)
+)
+
+// Shape maps the dotted path of every field of a payload to the kinds of value it held. Array
+// elements share the path of the array with a "[]" suffix, so an array contributes every kind its
+// elements held.
+type Shape map[string]map[Kind]struct{}
+
+// ShapeOf returns the shape of payload as it is encoded to JSON.
+func ShapeOf(payload any) (Shape, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode payload: %w", err)
+	}
+	return shapeOfJSON(raw)
+}
+
+func shapeOfJSON(raw []byte) (Shape, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+	shape := make(Shape)
+	if object, ok := value.(map[string]any); ok {
+		for key, child := range object {
+			shape.add(key, child)
+		}
+		return shape, nil
+	}
+	shape.add("$", value)
+	return shape, nil
+}
+
+func (s Shape) add(path string, value any) {
+	kind := kindOf(value)
+	kinds, ok := s[path]
+	if !ok {
+		kinds = make(map[Kind]struct{}, 1)
+		s[path] = kinds
+	}
+	kinds[kind] = struct{}{}
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, child := range typed {
+			s.add(path+"."+key, child)
+		}
+	case []any:
+		for _, child := range typed {
+			s.add(path+"[]", child)
+		}
+	}
+}
+
+func kindOf(value any) Kind {
+	switch typed := value.(type) {
+	case nil:
+		return KindNull
+	case bool:
+		return KindBool
+	case json.Number:
+		if strings.ContainsAny(string(typed), "eE") {
+			return KindNumberExponent
+		}
+		return KindNumber
+	case string:
+		return stringKind(typed)
+	case map[string]any:
+		return KindObject
+	case []any:
+		return KindArray
+	default:
+		return KindText
+	}
+}
+
+func stringKind(value string) Kind {
+	switch {
+	case value == "":
+		return KindEmpty
+	case decimalPattern.MatchString(value):
+		return KindDecimal
+	case scientificPattern.MatchString(value):
+		return KindScientific
+	}
+	if _, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return KindTimestamp
+	}
+	return KindText
+}
+
+func sortedKinds(kinds map[Kind]struct{}) []Kind {
+	out := make([]Kind, 0, len(kinds))
+	for kind := range kinds {
+		out = append(out, kind)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
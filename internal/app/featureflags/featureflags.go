@@ -0,0 +1,290 @@
+// Package featureflags gates risky gateway capabilities behind named flags. Flags are seeded from
+// configuration, can be toggled at runtime through the control API and keep their runtime
+// overrides across restarts when a store is configured.
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/flagstore"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// Built-in flags consulted by gateway capabilities.
+const (
+	// DurableSubscriptions lets instances configured with durable_subscription replay the
+	// execution reports and balance updates published while they were stopped.
+	DurableSubscriptions = "durable_subscriptions"
+	// SinkBatching lets event sinks deliver records in batches of batchSize; when disabled every
+	// record is delivered on its own.
+	SinkBatching = "sink_batching"
+	// TagRollouts lets strategy auto-refresh move instances following a tag such as canary or
+	// prod onto the revision the tag moved to.
+	TagRollouts = "tag_rollouts"
+)
+
+// Source reports where the effective value of a flag comes from.
+type Source string
+
+const (
+	// SourceBuiltIn marks a flag at its built-in default.
+	SourceBuiltIn Source = "builtin"
+	// SourceConfig marks a flag seeded from the featureFlags configuration section.
+	SourceConfig Source = "config"
+	// SourceRuntime marks a flag toggled through the control API.
+	SourceRuntime Source = "runtime"
+)
+
+// ErrFlagNotFound indicates the flag is neither built in nor configured.
+var ErrFlagNotFound = errors.New("feature flag not found")
+
+type definition struct {
+	description string
+	enabled     bool
+}
+
+// builtIn lists the flags known to the gateway. They default to enabled so existing deployments
+// keep their behaviour until an environment opts out.
+var builtIn = map[string]definition{
+	DurableSubscriptions: {
+		description: "Replay missed execution reports and balance updates to instances with durable_subscription.",
+		enabled:     true,
+	},
+	SinkBatching: {
+		description: "Deliver event sink records in batches instead of one at a time.",
+		enabled:     true,
+	},
+	TagRollouts: {
+		description: "Move instances following a strategy tag to the new revision when auto-refresh sees the tag move.",
+		enabled:     true,
+	},
+}
+
+// Flag describes the effective state of a feature flag.
+type Flag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	// Default is the configured or built-in value that applies without a runtime override.
+	Default   bool       `json:"default"`
+	Source    Source     `json:"source"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+type state struct {
+	description string
+	seed        bool
+	seedSource  Source
+	override    *bool
+	updatedAt   time.Time
+}
+
+func (s state) flag(name string) Flag {
+	out := Flag{
+		Name:        name,
+		Description: s.description,
+		Enabled:     s.seed,
+		Default:     s.seed,
+		Source:      s.seedSource,
+		UpdatedAt:   nil,
+	}
+	if s.override != nil {
+		out.Enabled = *s.override
+		out.Source = SourceRuntime
+		updated := s.updatedAt
+		out.UpdatedAt = &updated
+	}
+	return out
+}
+
+// Option configures Flags.
+type Option func(*Flags)
+
+// WithStore persists runtime overrides so toggles survive restarts.
+func WithStore(store flagstore.Store) Option {
+	return func(f *Flags) {
+		f.store = store
+	}
+}
+
+// WithClock overrides the time source.
+func WithClock(clock func() time.Time) Option {
+	return func(f *Flags) {
+		if clock != nil {
+			f.clock = clock
+		}
+	}
+}
+
+// WithLogger overrides the feature flag logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(f *Flags) {
+		if logger != nil {
+			f.logger = logger
+		}
+	}
+}
+
+// Flags holds the effective feature flag values. A nil *Flags reports built-in defaults.
+type Flags struct {
+	store  flagstore.Store
+	clock  func() time.Time
+	logger *log.Logger
+
+	mu    sync.RWMutex
+	flags map[string]*state
+}
+
+// New seeds the built-in flags with the configured values. Configured names that are not built in
+// become custom flags.
+func New(seeds map[string]bool, opts ...Option) *Flags {
+	f := &Flags{
+		store:  nil,
+		clock:  time.Now,
+		logger: log.New(redact.Stdout, "flags ", log.LstdFlags|log.Lmicroseconds),
+		mu:     sync.RWMutex{},
+		flags:  make(map[string]*state, len(builtIn)+len(seeds)),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(f)
+		}
+	}
+	for name, def := range builtIn {
+		f.flags[name] = &state{description: def.description, seed: def.enabled, seedSource: SourceBuiltIn, override: nil, updatedAt: time.Time{}}
+	}
+	for raw, enabled := range seeds {
+		name := normalizeName(raw)
+		if name == "" {
+			continue
+		}
+		if existing, ok := f.flags[name]; ok {
+			existing.seed = enabled
+			existing.seedSource = SourceConfig
+			continue
+		}
+		f.flags[name] = &state{description: "", seed: enabled, seedSource: SourceConfig, override: nil, updatedAt: time.Time{}}
+	}
+	return f
+}
+
+// Load applies the runtime overrides kept in the store. Overrides of flags that are no longer
+// built in or configured are ignored.
+func (f *Flags) Load(ctx context.Context) error {
+	if f.store == nil {
+		return nil
+	}
+	stored, err := f.store.LoadFlags(ctx)
+	if err != nil {
+		return fmt.Errorf("load feature flags: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, record := range stored {
+		current, ok := f.flags[normalizeName(record.Name)]
+		if !ok {
+			f.logger.Printf("feature flag %s: ignoring override of unknown flag", record.Name)
+			continue
+		}
+		enabled := record.Enabled
+		current.override = &enabled
+		current.updatedAt = record.UpdatedAt.UTC()
+	}
+	return nil
+}
+
+// Enabled reports whether the named flag is on. Unknown flags are off.
+func (f *Flags) Enabled(name string) bool {
+	name = normalizeName(name)
+	if f == nil {
+		return builtIn[name].enabled
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	current, ok := f.flags[name]
+	if !ok {
+		return false
+	}
+	if current.override != nil {
+		return *current.override
+	}
+	return current.seed
+}
+
+// List returns every flag sorted by name.
+func (f *Flags) List() []Flag {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	out := make([]Flag, 0, len(f.flags))
+	for name, current := range f.flags {
+		out = append(out, current.flag(name))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Get returns a single flag.
+func (f *Flags) Get(name string) (Flag, error) {
+	name = normalizeName(name)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	current, ok := f.flags[name]
+	if !ok {
+		return Flag{}, fmt.Errorf("%w: %s", ErrFlagNotFound, name)
+	}
+	return current.flag(name), nil
+}
+
+// Set overrides a flag at runtime and persists the override.
+func (f *Flags) Set(ctx context.Context, name string, enabled bool) (Flag, error) {
+	name = normalizeName(name)
+	if _, err := f.Get(name); err != nil {
+		return Flag{}, err
+	}
+	now := f.clock().UTC()
+	if f.store != nil {
+		if err := f.store.SaveFlag(ctx, flagstore.Flag{Name: name, Enabled: enabled, UpdatedAt: now}); err != nil {
+			return Flag{}, fmt.Errorf("persist feature flag %s: %w", name, err)
+		}
+	}
+	f.mu.Lock()
+	current := f.flags[name]
+	current.override = &enabled
+	current.updatedAt = now
+	flag := current.flag(name)
+	f.mu.Unlock()
+	f.logger.Printf("feature flag %s set to %t", name, enabled)
+	return flag, nil
+}
+
+// Reset drops the runtime override so the flag reverts to its configured or built-in value.
+func (f *Flags) Reset(ctx context.Context, name string) (Flag, error) {
+	name = normalizeName(name)
+	if _, err := f.Get(name); err != nil {
+		return Flag{}, err
+	}
+	if f.store != nil {
+		if err := f.store.DeleteFlag(ctx, name); err != nil {
+			return Flag{}, fmt.Errorf("delete feature flag %s: %w", name, err)
+		}
+	}
+	f.mu.Lock()
+	current := f.flags[name]
+	current.override = nil
+	current.updatedAt = time.Time{}
+	flag := current.flag(name)
+	f.mu.Unlock()
+	f.logger.Printf("feature flag %s reset to %t (%s)", name, flag.Enabled, flag.Source)
+	return flag, nil
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,94 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/flagstore"
+)
+
+type memoryStore struct {
+	flags map[string]flagstore.Flag
+}
+
+func (s *memoryStore) SaveFlag(_ context.Context, flag flagstore.Flag) error {
+	s.flags[flag.Name] = flag
+	return nil
+}
+
+func (s *memoryStore) DeleteFlag(_ context.Context, name string) error {
+	delete(s.flags, name)
+	return nil
+}
+
+func (s *memoryStore) LoadFlags(context.Context) ([]flagstore.Flag, error) {
+	out := make([]flagstore.Flag, 0, len(s.flags))
+	for _, flag := range s.flags {
+		out = append(out, flag)
+	}
+	return out, nil
+}
+
+func TestFlagsSeedToggleAndReset(t *testing.T) {
+	store := &memoryStore{flags: make(map[string]flagstore.Flag)}
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	opts := []Option{WithStore(store), WithClock(func() time.Time { return now }), WithLogger(log.New(io.Discard, "", 0))}
+	flags := New(map[string]bool{" Sink_Batching ": false, "order_batching": true}, opts...)
+
+	if !flags.Enabled(DurableSubscriptions) {
+		t.Fatal("built-in flags should default to enabled")
+	}
+	if flags.Enabled(SinkBatching) {
+		t.Fatal("config seed should disable sink batching")
+	}
+	if !flags.Enabled("order_batching") || flags.Enabled("unknown") {
+		t.Fatal("custom flags should follow config and unknown flags should be off")
+	}
+	if got, _ := flags.Get(SinkBatching); got.Source != SourceConfig || got.Default {
+		t.Fatalf("unexpected seeded flag %+v", got)
+	}
+
+	flag, err := flags.Set(context.Background(), "SINK_BATCHING", true)
+	if err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if !flag.Enabled || flag.Source != SourceRuntime || flag.UpdatedAt == nil || !flag.UpdatedAt.Equal(now) {
+		t.Fatalf("unexpected toggled flag %+v", flag)
+	}
+	if stored, ok := store.flags[SinkBatching]; !ok || !stored.Enabled {
+		t.Fatalf("expected override to be persisted, got %+v", store.flags)
+	}
+	if _, err := flags.Set(context.Background(), "missing", true); !errors.Is(err, ErrFlagNotFound) {
+		t.Fatalf("expected ErrFlagNotFound, got %v", err)
+	}
+
+	restarted := New(map[string]bool{SinkBatching: false}, opts...)
+	if err := restarted.Load(context.Background()); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !restarted.Enabled(SinkBatching) {
+		t.Fatal("runtime override should survive a restart")
+	}
+
+	flag, err = restarted.Reset(context.Background(), SinkBatching)
+	if err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if flag.Enabled || flag.Source != SourceConfig || flag.UpdatedAt != nil {
+		t.Fatalf("unexpected reset flag %+v", flag)
+	}
+	if len(store.flags) != 0 {
+		t.Fatalf("expected override to be deleted, got %+v", store.flags)
+	}
+}
+
+func TestNilFlagsReportBuiltInDefaults(t *testing.T) {
+	var flags *Flags
+	if !flags.Enabled(TagRollouts) || flags.Enabled("custom") {
+		t.Fatal("nil flags should report built-in defaults")
+	}
+}
@@ -0,0 +1,320 @@
+// Package heartbeat publishes a periodic liveness event per provider. Each heartbeat carries when
+// every route of the provider last delivered data, so strategies can implement their own
+// staleness handling, such as widening quotes, without polling the control API.
+package heartbeat
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// Kind identifies heartbeat payloads among extension events.
+const Kind = "provider.heartbeat"
+
+// dataEventTypes are the provider data streams whose freshness heartbeats report.
+var dataEventTypes = []schema.EventType{
+	schema.EventTypeTrade,
+	schema.EventTypeTicker,
+	schema.EventTypeBookSnapshot,
+	schema.EventTypeKlineSummary,
+	schema.EventTypeExecReport,
+	schema.EventTypeBalanceUpdate,
+	schema.EventTypeInstrumentUpdate,
+}
+
+// SymbolStatus reports when one symbol of a route last delivered data.
+type SymbolStatus struct {
+	Symbol     string    `json:"symbol"`
+	LastDataAt time.Time `json:"lastDataAt"`
+	AgeMs      int64     `json:"ageMs"`
+}
+
+// RouteStatus reports the freshness of one route. LastDataAt and AgeMs are absent while the
+// route has not delivered any data since the gateway started.
+type RouteStatus struct {
+	Route      schema.RouteType `json:"route"`
+	LastDataAt *time.Time       `json:"lastDataAt,omitempty"`
+	AgeMs      *int64           `json:"ageMs,omitempty"`
+	Symbols    []SymbolStatus   `json:"symbols,omitempty"`
+}
+
+// Heartbeat is published for every provider with active routes or recent data.
+type Heartbeat struct {
+	Kind     string `json:"kind"`
+	Provider string `json:"provider"`
+	// At is when the heartbeat was generated; ages are relative to it.
+	At time.Time `json:"at"`
+	// LastDataAt is the most recent data across all routes.
+	LastDataAt *time.Time    `json:"lastDataAt,omitempty"`
+	Routes     []RouteStatus `json:"routes"`
+}
+
+// RouteLister reports the active routes. *dispatcher.Table implements it.
+type RouteLister interface {
+	Routes() map[dispatcher.RouteKey]dispatcher.Route
+}
+
+// Publisher delivers heartbeats.
+type Publisher func(ctx context.Context, heartbeat Heartbeat)
+
+// Option configures a Monitor.
+type Option func(*Monitor)
+
+// WithClock overrides the time source.
+func WithClock(clock func() time.Time) Option {
+	return func(m *Monitor) {
+		if clock != nil {
+			m.clock = clock
+		}
+	}
+}
+
+// WithLogger overrides the monitor logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(m *Monitor) {
+		if logger != nil {
+			m.logger = logger
+		}
+	}
+}
+
+// WithRoutes adds active routes that have not delivered data yet to heartbeats, and stops
+// heartbeats for providers left without any active route.
+func WithRoutes(routes RouteLister) Option {
+	return func(m *Monitor) {
+		m.routes = routes
+	}
+}
+
+type routeKey struct {
+	provider string
+	route    schema.RouteType
+}
+
+// Monitor tracks when each provider route last delivered data and publishes heartbeats.
+type Monitor struct {
+	cfg     config.HeartbeatConfig
+	bus     eventbus.Bus
+	pools   *pool.PoolManager
+	publish Publisher
+	routes  RouteLister
+	clock   func() time.Time
+	logger  *log.Logger
+
+	mu       sync.Mutex
+	lastData map[routeKey]map[string]time.Time
+	// names maps normalised provider keys to the provider names events carry.
+	names map[string]string
+}
+
+// New constructs a monitor reading data events from bus and delivering heartbeats to publish.
+func New(cfg config.HeartbeatConfig, bus eventbus.Bus, pools *pool.PoolManager, publish Publisher, opts ...Option) *Monitor {
+	m := &Monitor{
+		cfg:      cfg,
+		bus:      bus,
+		pools:    pools,
+		publish:  publish,
+		routes:   nil,
+		clock:    time.Now,
+		logger:   log.New(redact.Stdout, "heartbeat ", log.LstdFlags|log.Lmicroseconds),
+		mu:       sync.Mutex{},
+		lastData: make(map[routeKey]map[string]time.Time),
+		names:    make(map[string]string),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	return m
+}
+
+// Run observes provider data and publishes heartbeats every interval until ctx is cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	if m.bus != nil {
+		for _, typ := range dataEventTypes {
+			id, ch, err := m.bus.Subscribe(ctx, typ)
+			if err != nil {
+				m.logger.Printf("heartbeat: subscribe %s: %v", typ, err)
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer m.bus.Unsubscribe(id)
+				m.consume(ctx, ch)
+			}()
+		}
+	}
+	defer wg.Wait()
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, heartbeat := range m.Snapshot() {
+				if m.publish != nil {
+					m.publish(ctx, heartbeat)
+				}
+			}
+		}
+	}
+}
+
+func (m *Monitor) consume(ctx context.Context, ch <-chan *schema.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-ch:
+			if !ok {
+				return
+			}
+			if evt == nil {
+				continue
+			}
+			m.Observe(evt)
+			if m.pools != nil {
+				m.pools.TryReturnEventInst(evt)
+			}
+		}
+	}
+}
+
+// Observe records the arrival of a provider data event.
+func (m *Monitor) Observe(evt *schema.Event) {
+	if evt == nil {
+		return
+	}
+	provider := strings.TrimSpace(evt.Provider)
+	routes := schema.RoutesForEvent(evt.Type)
+	if provider == "" || len(routes) == 0 {
+		return
+	}
+	now := m.clock().UTC()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.names[strings.ToLower(provider)] = provider
+	for _, route := range routes {
+		key := routeKey{provider: strings.ToLower(provider), route: route}
+		symbols, ok := m.lastData[key]
+		if !ok {
+			symbols = make(map[string]time.Time)
+			m.lastData[key] = symbols
+		}
+		symbols[strings.TrimSpace(evt.Symbol)] = now
+	}
+}
+
+// Snapshot builds the current heartbeat of every provider, ordered by provider name.
+func (m *Monitor) Snapshot() []Heartbeat {
+	now := m.clock().UTC()
+	active := m.activeRoutes()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if active != nil {
+		// Private streams such as execution reports are not dispatch routes, so observed data is
+		// only dropped once the provider has no active route at all.
+		providers := make(map[string]struct{}, len(active))
+		for key := range active {
+			providers[key.provider] = struct{}{}
+		}
+		for key := range m.lastData {
+			if _, ok := providers[key.provider]; !ok {
+				delete(m.lastData, key)
+			}
+		}
+	}
+	keys := make(map[routeKey]struct{}, len(m.lastData)+len(active))
+	for key := range m.lastData {
+		keys[key] = struct{}{}
+	}
+	for key := range active {
+		keys[key] = struct{}{}
+	}
+
+	byProvider := make(map[string]*Heartbeat)
+	for key := range keys {
+		heartbeat, ok := byProvider[key.provider]
+		if !ok {
+			name, named := m.names[key.provider]
+			if !named {
+				name = key.provider
+			}
+			heartbeat = &Heartbeat{Kind: Kind, Provider: name, At: now, LastDataAt: nil, Routes: nil}
+			byProvider[key.provider] = heartbeat
+		}
+		status := routeStatus(key.route, m.lastData[key], now)
+		if status.LastDataAt != nil && (heartbeat.LastDataAt == nil || status.LastDataAt.After(*heartbeat.LastDataAt)) {
+			lastDataAt := *status.LastDataAt
+			heartbeat.LastDataAt = &lastDataAt
+		}
+		heartbeat.Routes = append(heartbeat.Routes, status)
+	}
+
+	out := make([]Heartbeat, 0, len(byProvider))
+	for _, heartbeat := range byProvider {
+		slices.SortFunc(heartbeat.Routes, func(a, b RouteStatus) int {
+			return strings.Compare(string(a.Route), string(b.Route))
+		})
+		out = append(out, *heartbeat)
+	}
+	slices.SortFunc(out, func(a, b Heartbeat) int {
+		return strings.Compare(a.Provider, b.Provider)
+	})
+	return out
+}
+
+// activeRoutes returns the routes of the dispatch table, or nil when no lister is configured.
+func (m *Monitor) activeRoutes() map[routeKey]struct{} {
+	if m.routes == nil {
+		return nil
+	}
+	routes := m.routes.Routes()
+	active := make(map[routeKey]struct{}, len(routes))
+	for key := range routes {
+		active[routeKey{provider: strings.ToLower(strings.TrimSpace(key.Provider)), route: schema.NormalizeRouteType(key.Type)}] = struct{}{}
+	}
+	return active
+}
+
+func routeStatus(route schema.RouteType, symbols map[string]time.Time, now time.Time) RouteStatus {
+	status := RouteStatus{Route: route, LastDataAt: nil, AgeMs: nil, Symbols: nil}
+	for symbol, at := range symbols {
+		if status.LastDataAt == nil || at.After(*status.LastDataAt) {
+			lastDataAt := at
+			status.LastDataAt = &lastDataAt
+		}
+		if symbol != "" {
+			status.Symbols = append(status.Symbols, SymbolStatus{Symbol: symbol, LastDataAt: at, AgeMs: ageMs(now, at)})
+		}
+	}
+	if status.LastDataAt != nil {
+		age := ageMs(now, *status.LastDataAt)
+		status.AgeMs = &age
+	}
+	slices.SortFunc(status.Symbols, func(a, b SymbolStatus) int {
+		return strings.Compare(a.Symbol, b.Symbol)
+	})
+	return status
+}
+
+func ageMs(now, at time.Time) int64 {
+	return max(now.Sub(at).Milliseconds(), 0)
+}
@@ -0,0 +1,114 @@
+package heartbeat
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+type stubRoutes struct {
+	keys []dispatcher.RouteKey
+}
+
+func (s *stubRoutes) Routes() map[dispatcher.RouteKey]dispatcher.Route {
+	out := make(map[dispatcher.RouteKey]dispatcher.Route, len(s.keys))
+	for _, key := range s.keys {
+		out[key] = dispatcher.Route{Provider: key.Provider, Type: key.Type}
+	}
+	return out
+}
+
+func TestSnapshotReportsRouteFreshness(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	routes := &stubRoutes{keys: []dispatcher.RouteKey{
+		{Provider: "binance", Type: schema.RouteTypeTrade},
+		{Provider: "binance", Type: schema.RouteTypeOrderbookSnapshot},
+	}}
+	monitor := New(config.HeartbeatConfig{Enabled: true, Interval: time.Second}, nil, nil, nil,
+		WithClock(func() time.Time { return now }), WithRoutes(routes), WithLogger(log.New(io.Discard, "", 0)))
+
+	monitor.Observe(&schema.Event{Provider: "binance", Symbol: "BTC-USDT", Type: schema.EventTypeTrade})
+	now = now.Add(2 * time.Second)
+	monitor.Observe(&schema.Event{Provider: "binance", Symbol: "ETH-USDT", Type: schema.EventTypeTrade})
+	monitor.Observe(&schema.Event{Provider: "binance", Type: schema.EventTypeExecReport})
+	now = now.Add(500 * time.Millisecond)
+
+	heartbeats := monitor.Snapshot()
+	if len(heartbeats) != 1 || heartbeats[0].Provider != "binance" || heartbeats[0].Kind != Kind {
+		t.Fatalf("unexpected heartbeats %+v", heartbeats)
+	}
+	heartbeat := heartbeats[0]
+	if len(heartbeat.Routes) != 3 || heartbeat.LastDataAt == nil || !heartbeat.LastDataAt.Equal(now.Add(-500*time.Millisecond)) {
+		t.Fatalf("unexpected heartbeat %+v", heartbeat)
+	}
+	execution, book, trade := heartbeat.Routes[0], heartbeat.Routes[1], heartbeat.Routes[2]
+	if execution.Route != schema.RouteTypeExecutionReport || execution.AgeMs == nil || *execution.AgeMs != 500 || len(execution.Symbols) != 0 {
+		t.Fatalf("unexpected execution route %+v", execution)
+	}
+	if book.Route != schema.RouteTypeOrderbookSnapshot || book.LastDataAt != nil || book.AgeMs != nil {
+		t.Fatalf("expected book route without data, got %+v", book)
+	}
+	if trade.Route != schema.RouteTypeTrade || len(trade.Symbols) != 2 || trade.Symbols[0].Symbol != "BTC-USDT" || trade.Symbols[0].AgeMs != 2500 || *trade.AgeMs != 500 {
+		t.Fatalf("unexpected trade route %+v", trade)
+	}
+
+	routes.keys = nil
+	if heartbeats := monitor.Snapshot(); len(heartbeats) != 0 {
+		t.Fatalf("expected no heartbeat once the provider has no routes, got %+v", heartbeats)
+	}
+}
+
+func TestRunPublishesHeartbeats(t *testing.T) {
+	pools := pool.NewPoolManager()
+	if err := pools.RegisterPool("Event", 16, 16, func() interface{} { return new(schema.Event) }); err != nil {
+		t.Fatalf("register Event pool: %v", err)
+	}
+	bus := eventbus.NewMemoryBus(eventbus.MemoryConfig{BufferSize: 16, FanoutWorkers: 1, Pools: pools})
+	defer bus.Close()
+	published := make(chan Heartbeat, 16)
+	monitor := New(config.HeartbeatConfig{Enabled: true, Interval: 20 * time.Millisecond}, bus, pools,
+		func(_ context.Context, heartbeat Heartbeat) { published <- heartbeat },
+		WithLogger(log.New(io.Discard, "", 0)))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		monitor.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		evt, err := pools.BorrowEventInst(ctx)
+		if err != nil {
+			t.Fatalf("borrow event: %v", err)
+		}
+		evt.Provider = "okx"
+		evt.Symbol = "BTC-USDT"
+		evt.Type = schema.EventTypeTicker
+		if err := bus.Publish(ctx, evt); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		select {
+		case heartbeat := <-published:
+			if heartbeat.Provider != "okx" || len(heartbeat.Routes) != 1 || heartbeat.Routes[0].Route != schema.RouteTypeTicker {
+				t.Fatalf("unexpected heartbeat %+v", heartbeat)
+			}
+			return
+		case <-deadline:
+			t.Fatal("no heartbeat published")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
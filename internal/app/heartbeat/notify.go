@@ -0,0 +1,40 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+// BusPublisher publishes heartbeats as extension events stamped with the provider, so instances
+// trading it receive them through onExtensionEvent.
+func BusPublisher(bus eventbus.Bus, pools *pool.PoolManager, logger *log.Logger) Publisher {
+	return func(ctx context.Context, heartbeat Heartbeat) {
+		if bus == nil || pools == nil {
+			return
+		}
+		evt, err := pools.BorrowEventInst(ctx)
+		if err != nil {
+			if logger != nil {
+				logger.Printf("heartbeat skipped: %v", err)
+			}
+			return
+		}
+		evt.EventID = fmt.Sprintf("heartbeat:%s:%d", heartbeat.Provider, heartbeat.At.UnixNano())
+		evt.Provider = heartbeat.Provider
+		evt.Type = schema.ExtensionEventType
+		evt.IngestTS = heartbeat.At
+		evt.EmitTS = heartbeat.At
+		evt.Payload = heartbeat
+		if err := bus.Publish(ctx, evt); err != nil {
+			if logger != nil {
+				logger.Printf("publish heartbeat: %v", err)
+			}
+			pools.ReturnEventInst(evt)
+		}
+	}
+}
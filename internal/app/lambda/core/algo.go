@@ -0,0 +1,161 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/algo"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// OrderCanceller is implemented by order submitters that can cancel a single order by client
+// order ID. Execution algos use it to pull resting child orders when a parent is cancelled.
+type OrderCanceller interface {
+	CancelOrder(ctx context.Context, provider, symbol, clientOrderID string) error
+}
+
+// algoVenue routes algo child orders through the lambda's regular order path so they are
+// normalised, risk checked and persisted like any other order.
+type algoVenue struct {
+	lambda *BaseLambda
+}
+
+func (v algoVenue) SubmitChild(ctx context.Context, parent algo.ParentOrder, clientOrderID, quantity string) (bool, error) {
+	intent := orderIntent{
+		clientOrderID: clientOrderID,
+		provider:      parent.Provider,
+		symbol:        parent.Symbol,
+		side:          parent.Side,
+		orderType:     schema.OrderTypeLimit,
+		quantity:      quantity,
+		price:         parent.Price,
+		tif:           schema.TimeInForceGTC,
+		postOnly:      false,
+		reduceOnly:    false,
+		labels:        childLabels(parent),
+		throttle:      throttleModeWait,
+		external:      false,
+	}
+	if parent.Price == nil {
+		intent.orderType = schema.OrderTypeMarket
+		intent.tif = schema.TimeInForceIOC
+	}
+	return v.lambda.placeOrder(ctx, intent)
+}
+
+// childLabels copies the parent's labels onto a child order and attributes it to the parent.
+func childLabels(parent algo.ParentOrder) OrderLabels {
+	metadata := make(map[string]string, len(parent.Metadata)+1)
+	for key, value := range parent.Metadata {
+		metadata[key] = value
+	}
+	if parent.ID != "" {
+		metadata[parentAlgoIDKey] = parent.ID
+	}
+	return OrderLabels{Tags: parent.Tags, Metadata: metadata}
+}
+
+func (v algoVenue) CancelChild(ctx context.Context, parent algo.ParentOrder, clientOrderID string) error {
+	canceller, ok := v.lambda.orderSubmitter.(OrderCanceller)
+	if !ok {
+		return fmt.Errorf("order submitter does not support order cancellation")
+	}
+	if err := canceller.CancelOrder(ctx, parent.Provider, parent.Symbol, clientOrderID); err != nil {
+		return fmt.Errorf("cancel child order %s: %w", clientOrderID, err)
+	}
+	return nil
+}
+
+// SubmitAlgoOrder hands a parent order to the execution algo engine, which slices it into child
+// orders over time. The symbol defaults to the provider's primary symbol.
+func (l *BaseLambda) SubmitAlgoOrder(order algo.ParentOrder) (string, error) {
+	order.Provider = strings.TrimSpace(order.Provider)
+	if order.Provider == "" {
+		return "", fmt.Errorf("order provider required")
+	}
+	if len(l.config.Providers) > 0 {
+		if _, ok := l.providerSet[order.Provider]; !ok {
+			return "", fmt.Errorf("order provider %q not configured for lambda %s", order.Provider, l.id)
+		}
+	}
+	if strings.TrimSpace(order.Symbol) == "" {
+		order.Symbol = l.symbolForProvider(order.Provider)
+	}
+	id, err := l.algos.Submit(order)
+	if err != nil {
+		return "", fmt.Errorf("submit algo order: %w", err)
+	}
+	l.tape.mark(id, order.Provider, order.Symbol, order.Side)
+	return id, nil
+}
+
+// CancelAlgoOrder stops slicing the parent order and cancels its resting children.
+func (l *BaseLambda) CancelAlgoOrder(id string) error {
+	if err := l.algos.Cancel(strings.TrimSpace(id)); err != nil {
+		return fmt.Errorf("cancel algo order: %w", err)
+	}
+	return nil
+}
+
+// AlgoOrders returns the progress of the lambda's recent algo parent orders, benchmarked against
+// arrival price and interval VWAP.
+func (l *BaseLambda) AlgoOrders() []algo.Progress {
+	list := l.algos.List()
+	for i := range list {
+		list[i] = l.benchmarkAlgo(list[i])
+	}
+	return list
+}
+
+// AlgoOrder returns the progress of a single algo parent order.
+func (l *BaseLambda) AlgoOrder(id string) (algo.Progress, bool) {
+	progress, ok := l.algos.Progress(strings.TrimSpace(id))
+	if !ok {
+		return progress, false
+	}
+	return l.benchmarkAlgo(progress), true
+}
+
+func (l *BaseLambda) benchmarkAlgo(progress algo.Progress) algo.Progress {
+	result, ok := l.tape.evaluate(progress.ID, progress.AvgFillPrice)
+	if !ok {
+		return progress
+	}
+	progress.ArrivalPrice = result.ArrivalPrice
+	progress.IntervalVWAP = result.IntervalVWAP
+	progress.SlippageVsArrivalBps = result.SlippageVsArrivalBps
+	progress.SlippageVsVWAPBps = result.SlippageVsVWAPBps
+	return progress
+}
+
+// emitAlgoProgress publishes algo progress as an extension event so strategies and the control
+// plane observe parent orders without polling.
+func (l *BaseLambda) emitAlgoProgress(progress algo.Progress) {
+	if l.bus == nil || l.pools == nil {
+		return
+	}
+	ctx := context.Background()
+	evt, err := l.pools.BorrowEventInst(ctx)
+	if err != nil {
+		l.logger.Printf("[%s] unable to borrow event from pool: %v", l.id, err)
+		return
+	}
+	ts := progress.UpdatedAt
+	if ts.IsZero() {
+		ts = time.Now().UTC()
+	}
+	evt.EventID = fmt.Sprintf("algo:%s:%d", progress.ID, ts.UnixNano())
+	evt.Provider = progress.Provider
+	evt.Symbol = progress.Symbol
+	evt.Type = schema.ExtensionEventType
+	evt.IngestTS = ts
+	evt.EmitTS = ts
+	evt.Payload = l.benchmarkAlgo(progress)
+
+	if err := l.bus.Publish(ctx, evt); err != nil {
+		l.logger.Printf("[%s] publish algo progress: %v", l.id, err)
+		l.pools.ReturnEventInst(evt)
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/algo"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+type algoSubmitter struct {
+	mu        sync.Mutex
+	orders    []schema.OrderRequest
+	cancelled []string
+}
+
+func (s *algoSubmitter) SubmitOrder(_ context.Context, req schema.OrderRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders = append(s.orders, req)
+	return nil
+}
+
+func (s *algoSubmitter) CancelOrder(_ context.Context, _, _, clientOrderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelled = append(s.cancelled, clientOrderID)
+	return nil
+}
+
+func (s *algoSubmitter) snapshot() ([]schema.OrderRequest, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]schema.OrderRequest(nil), s.orders...), append([]string(nil), s.cancelled...)
+}
+
+func TestAlgoChildrenUseLambdaOrderPath(t *testing.T) {
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("OrderRequest", 4, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	submitter := &algoSubmitter{}
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+		SubAccounts:     map[string]string{"binance": "desk-a"},
+	}
+	lambda := NewBaseLambda("lambda-algo", cfg, nil, submitter, poolMgr, nil, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lambda.algos.Start(ctx)
+
+	if _, err := lambda.SubmitAlgoOrder(algo.ParentOrder{Kind: algo.KindTWAP, Provider: "okx", Side: schema.TradeSideBuy, Quantity: "1", Duration: time.Second}); err == nil {
+		t.Fatal("expected unconfigured provider to be rejected")
+	}
+
+	price := "100"
+	id, err := lambda.SubmitAlgoOrder(algo.ParentOrder{
+		Kind:            algo.KindIceberg,
+		Provider:        "binance",
+		Side:            schema.TradeSideSell,
+		Quantity:        "3",
+		Price:           &price,
+		DisplayQuantity: "1",
+		Tags:            []string{"rebalance"},
+		Metadata:        map[string]string{"signal": "drift"},
+	})
+	if err != nil {
+		t.Fatalf("SubmitAlgoOrder: %v", err)
+	}
+
+	var orders []schema.OrderRequest
+	deadline := time.Now().Add(2 * time.Second)
+	for len(orders) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		orders, _ = submitter.snapshot()
+	}
+	if len(orders) != 1 {
+		t.Fatalf("expected one resting child, got %d", len(orders))
+	}
+	child := orders[0]
+	if !lambda.IsMyOrder(child.ClientOrderID) || !strings.HasPrefix(child.ClientOrderID, id+"-") {
+		t.Fatalf("unexpected child order id %q for parent %q", child.ClientOrderID, id)
+	}
+	if child.Symbol != "BTC-USDT" || child.Quantity != "1" || child.OrderType != schema.OrderTypeLimit || child.SubAccount != "desk-a" {
+		t.Fatalf("unexpected child order %+v", child)
+	}
+	if len(child.Tags) != 1 || child.Tags[0] != "rebalance" || child.Metadata["signal"] != "drift" || child.Metadata["parentAlgoId"] != id {
+		t.Fatalf("child should carry parent labels, got %v %v", child.Tags, child.Metadata)
+	}
+
+	if err := lambda.CancelAlgoOrder(id); err != nil {
+		t.Fatalf("CancelAlgoOrder: %v", err)
+	}
+	deadline = time.Now().Add(2 * time.Second)
+	var cancelled []string
+	for len(cancelled) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		_, cancelled = submitter.snapshot()
+	}
+	if len(cancelled) != 1 || cancelled[0] != child.ClientOrderID {
+		t.Fatalf("expected resting child cancelled, got %v", cancelled)
+	}
+	progress, ok := lambda.AlgoOrder(id)
+	if !ok || progress.Status != algo.StatusCancelled {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+}
@@ -7,17 +7,18 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
 	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/coachpo/meltica/internal/app/algo"
 	"github.com/coachpo/meltica/internal/app/risk"
 	"github.com/coachpo/meltica/internal/domain/orderstore"
 	"github.com/coachpo/meltica/internal/domain/schema"
 	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
 	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
 	"github.com/shopspring/decimal"
 	"github.com/sourcegraph/conc"
 )
@@ -80,7 +81,7 @@ type BaseLambda struct {
 	pools             *pool.PoolManager
 	logger            *log.Logger
 	strategy          TradingStrategy
-	riskManager       *risk.Manager
+	riskManager       atomic.Pointer[risk.Manager]
 	baseCurrency      string
 	quoteCurrency     string
 	providerSet       map[string]struct{}
@@ -99,6 +100,34 @@ type BaseLambda struct {
 	tradingActive atomic.Bool
 	orderCount    atomic.Int64
 	dryRun        atomic.Bool
+
+	scheduler atomic.Pointer[deliveryScheduler]
+	// intentObserver sees every order the strategy places; see SetOrderIntentObserver.
+	intentObserver atomic.Pointer[func(OrderIntent)]
+	// execObserver sees the execution reports of the lambda's orders; see SetExecReportObserver.
+	execObserver atomic.Pointer[func(ExecReport)]
+
+	// bookDeltas routes book updates to BookDeltaConsumer.OnBookDelta instead of OnBookSnapshot.
+	bookDeltas bool
+
+	algos   *algo.Engine
+	tape    *marketTape
+	history *marketHistory
+	orders  *orderHistory
+	fills   *fillTracker
+	books   *bookMirror
+	labels  *orderLabelBook
+	book    *positionBook
+	queue   *throttleQueue
+	warmup  *warmup
+	freeze  *freezeBuffer
+	traces  *executionTraces
+	// callbacks tracks execution report callbacks; nil when acknowledgement deadlines are off.
+	callbacks *orderCallbackTracker
+	// deliveries counts the events reaching the lambda; see DeliveryStats.
+	deliveries *deliveryStats
+	// handlerNanos accumulates the time spent handling delivered events; see Usage.
+	handlerNanos atomic.Int64
 }
 
 // Config defines configuration for a lambda trading bot instance.
@@ -106,6 +135,28 @@ type Config struct {
 	Providers       []string
 	ProviderSymbols map[string][]string
 	DryRun          bool
+	// Delivery controls handler ordering and concurrency. The zero value selects serial delivery.
+	Delivery DeliveryConfig
+	// DurableSubscription replays execution reports and balance updates published while the
+	// lambda was stopped, using offsets tracked against the event outbox.
+	DurableSubscription bool
+	// SubAccounts maps providers to the sub-account this lambda trades through. Orders are tagged
+	// with the sub-account and balance updates from other sub-accounts are ignored.
+	SubAccounts map[string]string
+	// History sets the rolling windows of recent trades and klines kept for the strategy.
+	History HistoryConfig
+	// Orders sets how many of its recent orders the host keeps for the strategy to query.
+	Orders OrderHistoryConfig
+	// BookDeltas bounds the rate of book deltas for strategies that subscribe to them.
+	BookDeltas BookDeltaConfig
+	// ThrottleQueue parks orders that exceed the risk throttle instead of rejecting them.
+	ThrottleQueue ThrottleQueueConfig
+	// Warmup holds trading back after Start until market data, balances and providers are ready.
+	Warmup WarmupConfig
+	// FreezeBuffer bounds the market data events held while frozen; zero uses DefaultFreezeBuffer.
+	FreezeBuffer int
+	// OrderCallbacks retries and escalates execution report callbacks the strategy does not handle.
+	OrderCallbacks OrderCallbackConfig
 }
 
 // OrderSubmitter defines the interface for submitting orders to a provider.
@@ -113,10 +164,18 @@ type OrderSubmitter interface {
 	SubmitOrder(ctx context.Context, req schema.OrderRequest) error
 }
 
+// OrderNormalizer is implemented by order submitters that align orders with venue instrument filters
+// before risk checks and submission.
+type OrderNormalizer interface {
+	NormalizeOrder(ctx context.Context, req *schema.OrderRequest) error
+}
+
 // NewBaseLambda creates a new base lambda with the provided strategy.
 func NewBaseLambda(id string, config Config, bus eventbus.Bus, orderSubmitter OrderSubmitter, pools *pool.PoolManager, strategy TradingStrategy, riskManager *risk.Manager, orderStore orderstore.Store) *BaseLambda {
 	config.Providers = normalizeProviders(config.Providers)
 	config.ProviderSymbols = normalizeProviderSymbols(config.ProviderSymbols)
+	config.Delivery = config.Delivery.normalize()
+	config.SubAccounts = normalizeSubAccounts(config.SubAccounts)
 	providerSet := make(map[string]struct{}, len(config.Providers))
 	for _, provider := range config.Providers {
 		providerSet[provider] = struct{}{}
@@ -161,9 +220,9 @@ func NewBaseLambda(id string, config Config, bus eventbus.Bus, orderSubmitter Or
 		orderSubmitter:    orderSubmitter,
 		orderStore:        orderStore,
 		pools:             pools,
-		logger:            log.New(os.Stdout, "", log.LstdFlags),
+		logger:            log.New(redact.Stdout, "", log.LstdFlags),
 		strategy:          strategy,
-		riskManager:       riskManager,
+		riskManager:       atomic.Pointer[risk.Manager]{},
 		baseCurrency:      "",
 		quoteCurrency:     "",
 		providerSet:       providerSet,
@@ -178,7 +237,27 @@ func NewBaseLambda(id string, config Config, bus eventbus.Bus, orderSubmitter Or
 		tradingActive:     atomic.Bool{},
 		orderCount:        atomic.Int64{},
 		dryRun:            atomic.Bool{},
-	}
+		scheduler:         atomic.Pointer[deliveryScheduler]{},
+		intentObserver:    atomic.Pointer[func(OrderIntent)]{},
+		execObserver:      atomic.Pointer[func(ExecReport)]{},
+		algos:             nil,
+		tape:              newMarketTape(),
+		history:           newMarketHistory(config.History),
+		orders:            newOrderHistory(config.Orders),
+		fills:             newFillTracker(),
+		books:             newBookMirror(config.BookDeltas),
+		bookDeltas:        wantsBookDeltas(strategy),
+		labels:            newOrderLabelBook(),
+		book:              newPositionBook(),
+		deliveries:        newDeliveryStats(),
+		handlerNanos:      atomic.Int64{},
+		queue:             newThrottleQueue(config.ThrottleQueue),
+		warmup:            newWarmup(config.Warmup),
+		freeze:            newFreezeBuffer(config.FreezeBuffer),
+		callbacks:         newOrderCallbackTracker(config.OrderCallbacks),
+		traces:            newExecutionTraces(),
+	}
+	lambda.algos = algo.NewEngine(lambda.id, algoVenue{lambda: lambda}, algo.WithProgressHandler(lambda.emitAlgoProgress), algo.WithLogger(lambda.logger))
 
 	if lambda.globalPrimary != "" {
 		if base, quote, err := schema.InstrumentCurrencies(lambda.globalPrimary); err == nil {
@@ -202,8 +281,9 @@ func NewBaseLambda(id string, config Config, bus eventbus.Bus, orderSubmitter Or
 	lambda.lastPrice.Store(float64(0))
 	lambda.bidPrice.Store(float64(0))
 	lambda.askPrice.Store(float64(0))
-	lambda.tradingActive.Store(false)
+	lambda.tradingActive.Store(!config.DryRun)
 	lambda.dryRun.Store(config.DryRun)
+	lambda.riskManager.Store(riskManager)
 
 	return lambda
 }
@@ -243,6 +323,22 @@ func normalizeProviders(providers []string) []string {
 	return out
 }
 
+func normalizeSubAccounts(input map[string]string) map[string]string {
+	if len(input) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(input))
+	for provider, subAccount := range input {
+		provider = strings.TrimSpace(provider)
+		subAccount = strings.TrimSpace(subAccount)
+		if provider == "" || subAccount == "" {
+			continue
+		}
+		out[provider] = subAccount
+	}
+	return out
+}
+
 func normalizeProviderSymbols(input map[string][]string) map[string][]string {
 	if len(input) == 0 {
 		return nil
@@ -343,6 +439,10 @@ func (l *BaseLambda) Start(ctx context.Context) (<-chan error, error) {
 			if normalized == "" {
 				continue
 			}
+			if normalized == schema.EventTypeBookDelta {
+				// Deltas are derived from the book snapshots every lambda subscribes to.
+				continue
+			}
 			if normalized == schema.ExtensionEventType {
 				if _, ok := l.strategy.(ExtensionEventConsumer); !ok {
 					continue
@@ -371,6 +471,12 @@ func (l *BaseLambda) Start(ctx context.Context) (<-chan error, error) {
 		subs = append(subs, subscription{id: subID, typ: typ, ch: ch})
 	}
 
+	l.seedOrderHistory(ctx)
+	l.algos.Start(ctx)
+	l.startWarmup(ctx)
+	if l.queue != nil {
+		go l.drainThrottleQueue(ctx)
+	}
 	go l.consume(ctx, subs, errs)
 
 	l.logger.Printf("[%s] started for providers=%v scope=%v", l.id, l.config.Providers, l.config.ProviderSymbols)
@@ -383,9 +489,40 @@ type subscription struct {
 	ch  <-chan *schema.Event
 }
 
+// consume reads every subscription and hands events to the delivery scheduler, which enforces
+// the configured ordering and concurrency guarantees before invoking strategy callbacks.
 func (l *BaseLambda) consume(ctx context.Context, subs []subscription, errs chan<- error) {
 	defer close(errs)
 
+	cursor := l.durableCursorFor()
+	handle := l.handleEvent
+	if cursor != nil {
+		handle = func(ctx context.Context, typ schema.EventType, evt *schema.Event) {
+			offset := evt.OutboxID
+			l.handleEvent(ctx, typ, evt)
+			if isDurableEventType(typ) {
+				cursor.advance(offset)
+			}
+		}
+	}
+	scheduler := newDeliveryScheduler(l.id, l.config.Delivery, handle, l.recycleEvent)
+	l.scheduler.Store(scheduler)
+	scheduler.start(ctx)
+
+	var committerDone chan struct{}
+	var committerStopped chan struct{}
+	if cursor != nil {
+		// Live subscriptions are already open, so events published while replaying are buffered
+		// and de-duplicated against the replayed range.
+		l.replay(ctx, cursor, subs, scheduler)
+		committerDone = make(chan struct{})
+		committerStopped = make(chan struct{})
+		go func() {
+			defer close(committerStopped)
+			l.runCommitter(ctx, cursor, committerDone)
+		}()
+	}
+
 	var wg conc.WaitGroup
 	for _, sub := range subs {
 		subscription := sub
@@ -409,18 +546,41 @@ func (l *BaseLambda) consume(ctx context.Context, subs []subscription, errs chan
 					if !ok {
 						return
 					}
-					l.handleEvent(ctx, subscription.typ, evt)
+					if evt == nil {
+						continue
+					}
+					if cursor.skip(subscription.typ, evt) {
+						l.recycleEvent(evt)
+						continue
+					}
+					l.deliver(ctx, scheduler, subscription.typ, evt)
 				}
 			}
 		})
 	}
 
 	wg.Wait()
+	l.discardFrozen()
+	scheduler.close()
+	if committerDone != nil {
+		close(committerDone)
+		<-committerStopped
+	}
 	for _, sub := range subs {
 		l.bus.Unsubscribe(sub.id)
 	}
 }
 
+// Replay handles evt synchronously on the calling goroutine, bypassing the bus and the delivery
+// scheduler, so backtests can drive the lambda one event at a time. The event is returned to the
+// pool once handled.
+func (l *BaseLambda) Replay(ctx context.Context, evt *schema.Event) {
+	if evt == nil {
+		return
+	}
+	l.handleEvent(ctx, evt.Type, evt)
+}
+
 func (l *BaseLambda) handleEvent(ctx context.Context, typ schema.EventType, evt *schema.Event) {
 	if evt == nil {
 		return
@@ -438,12 +598,20 @@ func (l *BaseLambda) handleEvent(ctx context.Context, typ schema.EventType, evt
 	if !extensionEvent {
 		if typ == schema.EventTypeBalanceUpdate {
 			if !l.matchesBalanceCurrency(evt.Symbol) {
+				l.deliveries.filtered(evt.Provider, typ)
 				return
 			}
 		} else if !l.matchesSymbol(evt) {
+			l.deliveries.filtered(evt.Provider, typ)
 			return
 		}
 	}
+	start := time.Now()
+	l.deliveries.delivered(evt.Provider, typ, start)
+	defer func() { l.handlerNanos.Add(int64(time.Since(start))) }()
+
+	ctx, endSpans := l.traces.startEvent(ctx, l.id, evt)
+	defer endSpans()
 
 	switch typ {
 	case schema.EventTypeTrade:
@@ -479,9 +647,12 @@ func (l *BaseLambda) handleTrade(ctx context.Context, evt *schema.Event) {
 	}
 
 	l.lastPrice.Store(price)
-	if l.riskManager != nil {
+	l.tape.observeTrade(evt.Provider, evt.Symbol, payload.Price, payload.Quantity)
+	l.book.observePrice(evt.Symbol, payload.Price)
+	l.history.observeTrade(evt.Provider, evt.Symbol, payload)
+	if rm := l.riskManager.Load(); rm != nil {
 		if decPrice, convErr := decimal.NewFromString(payload.Price); convErr == nil {
-			l.riskManager.ObserveMarketPrice(evt.Symbol, decPrice)
+			rm.ObserveMarketPrice(evt.Symbol, decPrice)
 		}
 	}
 
@@ -503,9 +674,12 @@ func (l *BaseLambda) handleTicker(ctx context.Context, evt *schema.Event) {
 	l.lastPrice.Store(lastPrice)
 	l.bidPrice.Store(bidPrice)
 	l.askPrice.Store(askPrice)
-	if l.riskManager != nil {
+	l.tape.observeQuote(evt.Provider, evt.Symbol, payload.LastPrice, payload.BidPrice, payload.AskPrice)
+	l.warmup.observeMarketData(evt.Provider, evt.Symbol, time.Now())
+	l.book.observePrice(evt.Symbol, payload.LastPrice)
+	if rm := l.riskManager.Load(); rm != nil {
 		if decPrice, convErr := decimal.NewFromString(payload.LastPrice); convErr == nil {
-			l.riskManager.ObserveMarketPrice(evt.Symbol, decPrice)
+			rm.ObserveMarketPrice(evt.Symbol, decPrice)
 		}
 	}
 
@@ -530,22 +704,51 @@ func (l *BaseLambda) handleBookSnapshot(ctx context.Context, evt *schema.Event)
 		l.askPrice.Store(askPrice)
 	}
 
-	if l.riskManager != nil {
+	if rm := l.riskManager.Load(); rm != nil {
 		if len(payload.Bids) > 0 && len(payload.Asks) > 0 {
 			if bidDec, errBid := decimal.NewFromString(payload.Bids[0].Price); errBid == nil {
 				if askDec, errAsk := decimal.NewFromString(payload.Asks[0].Price); errAsk == nil {
 					mid := bidDec.Add(askDec).Div(decimal.NewFromInt(2))
-					l.riskManager.ObserveMarketPrice(evt.Symbol, mid)
+					rm.ObserveMarketPrice(evt.Symbol, mid)
 				}
 			}
 		}
 	}
 
-	if l.strategy != nil {
+	now := time.Now()
+	l.warmup.observeMarketData(evt.Provider, evt.Symbol, now)
+	delta, deliver := l.books.observe(evt.Provider, evt.Symbol, payload, l.bookDeltas, now)
+	if l.strategy == nil {
+		return
+	}
+	if !l.bookDeltas {
 		l.strategy.OnBookSnapshot(ctx, evt, payload)
+		return
+	}
+	if deliver {
+		deltaEvt := *evt
+		deltaEvt.Type = schema.EventTypeBookDelta
+		deltaEvt.Payload = delta
+		l.strategy.(BookDeltaConsumer).OnBookDelta(ctx, &deltaEvt, delta)
 	}
 }
 
+// wantsBookDeltas reports whether strategy consumes book deltas and subscribed to them.
+func wantsBookDeltas(strategy TradingStrategy) bool {
+	if strategy == nil {
+		return false
+	}
+	if _, ok := strategy.(BookDeltaConsumer); !ok {
+		return false
+	}
+	for _, typ := range strategy.SubscribedEvents() {
+		if schema.EventType(strings.TrimSpace(string(typ))) == schema.EventTypeBookDelta {
+			return true
+		}
+	}
+	return false
+}
+
 func (l *BaseLambda) handleExecReport(ctx context.Context, evt *schema.Event) {
 	payload, ok := evt.Payload.(schema.ExecReportPayload)
 	if !ok {
@@ -557,53 +760,42 @@ func (l *BaseLambda) handleExecReport(ctx context.Context, evt *schema.Event) {
 		return
 	}
 
-	l.persistExecReport(ctx, evt, payload)
+	fills := l.fills.apply(payload)
+	l.persistExecReport(ctx, evt, payload, fills)
+	l.orders.apply(evt, payload, fills)
+	l.observeExecReport(evt, payload)
+	l.book.apply(evt.Symbol, payload)
 
-	if l.riskManager != nil {
-		l.riskManager.HandleExecution(evt.Symbol, payload)
+	if rm := l.riskManager.Load(); rm != nil {
+		rm.HandleExecution(evt.Symbol, payload)
+	}
+	l.algos.OnExecReport(payload)
+	switch payload.State {
+	case schema.ExecReportStateFILLED, schema.ExecReportStateCANCELLED, schema.ExecReportStateREJECTED, schema.ExecReportStateEXPIRED:
+		l.tape.forget(payload.ClientOrderID)
+		l.labels.forget(payload.ClientOrderID)
+		l.traces.forget(payload.ClientOrderID)
+		l.fills.forget(payload.ClientOrderID)
 	}
 
 	// Delegate to strategy based on state
 	if l.strategy == nil {
 		return
 	}
-
-	switch payload.State {
-	case schema.ExecReportStateFILLED:
-		l.strategy.OnOrderFilled(ctx, evt, payload)
-
-	case schema.ExecReportStateREJECTED:
-		reason := ""
-		if payload.RejectReason != nil {
-			reason = *payload.RejectReason
-		}
-		l.strategy.OnOrderRejected(ctx, evt, payload, reason)
-
-	case schema.ExecReportStatePARTIAL:
-		l.strategy.OnOrderPartialFill(ctx, evt, payload)
-
-	case schema.ExecReportStateCANCELLED:
-		l.strategy.OnOrderCancelled(ctx, evt, payload)
-
-	case schema.ExecReportStateACK:
-		// Order acknowledged by exchange - useful for persistence, auditing, reconciliation
-		l.strategy.OnOrderAcknowledged(ctx, evt, payload)
-
-	case schema.ExecReportStateEXPIRED:
-		// Order expired (e.g., GTD orders that reached time limit)
-		// Useful for tracking order lifecycle, metrics, compliance
-		l.strategy.OnOrderExpired(ctx, evt, payload)
-	}
+	l.dispatchOrderEvent(ctx, evt, payload)
 }
 
 func (l *BaseLambda) handleInstrumentUpdate(ctx context.Context, evt *schema.Event) {
-	if l.strategy == nil {
-		return
-	}
 	payload, ok := evt.Payload.(schema.InstrumentUpdatePayload)
 	if !ok {
 		return
 	}
+	if rm := l.riskManager.Load(); rm != nil {
+		rm.ObserveInstrumentStatus(evt.Provider, payload.Instrument.Symbol, payload.Instrument.Status)
+	}
+	if l.strategy == nil {
+		return
+	}
 	l.strategy.OnInstrumentUpdate(ctx, evt, payload)
 }
 
@@ -612,6 +804,7 @@ func (l *BaseLambda) handleKlineSummary(ctx context.Context, evt *schema.Event)
 	if !ok {
 		return
 	}
+	l.history.observeKline(evt.Provider, evt.Symbol, payload)
 
 	if l.strategy != nil {
 		l.strategy.OnKlineSummary(ctx, evt, payload)
@@ -619,13 +812,20 @@ func (l *BaseLambda) handleKlineSummary(ctx context.Context, evt *schema.Event)
 }
 
 func (l *BaseLambda) handleBalanceUpdate(ctx context.Context, evt *schema.Event) {
-	if l.strategy == nil {
-		return
-	}
 	payload, ok := evt.Payload.(schema.BalanceUpdatePayload)
 	if !ok {
 		return
 	}
+	if payload.SubAccount != l.SubAccount(evt.Provider) {
+		return
+	}
+	if rm := l.riskManager.Load(); rm != nil {
+		rm.ObserveBalance(evt.Provider, payload)
+	}
+	l.warmup.observeBalance(evt.Provider)
+	if l.strategy == nil {
+		return
+	}
 	l.persistBalance(ctx, evt, payload)
 	l.strategy.OnBalanceUpdate(ctx, evt, payload)
 }
@@ -666,135 +866,262 @@ func (l *BaseLambda) handleExtension(ctx context.Context, evt *schema.Event) {
 	consumer.OnExtensionEvent(ctx, evt, evt.Payload)
 }
 
-// SubmitOrder submits an order request to the specified provider.
+// SubmitOrder submits an order request to the specified provider. When the lambda has a
+// throttle queue and the risk throttle is exhausted, the order is queued and ErrOrderQueued is
+// returned.
 func (l *BaseLambda) SubmitOrder(ctx context.Context, provider string, side schema.TradeSide, quantity string, price *string) error {
-	provider = strings.TrimSpace(provider)
-	if provider == "" {
-		return fmt.Errorf("order provider required")
-	}
-	if len(l.config.Providers) > 0 {
-		if _, ok := l.providerSet[provider]; !ok {
-			return fmt.Errorf("order provider %q not configured for lambda %s", provider, l.id)
-		}
-	}
+	return l.SubmitTaggedOrder(ctx, provider, side, quantity, price, OrderLabels{Tags: nil, Metadata: nil})
+}
 
-	if l.IsDryRun() {
-		var priceStr string
-		if price != nil {
-			priceStr = *price
-		} else {
-			priceStr = "market"
-		}
-		l.logger.Printf("[%s] dry-run: skip submit order provider=%s side=%s qty=%s price=%s", l.id, provider, side, quantity, priceStr)
-		return nil
-	}
+// SubmitTaggedOrder submits a limit order carrying attribution labels, which are persisted with
+// the order and echoed on its executions.
+func (l *BaseLambda) SubmitTaggedOrder(ctx context.Context, provider string, side schema.TradeSide, quantity string, price *string, labels OrderLabels) error {
+	return l.SubmitOrderWithOptions(ctx, provider, side, quantity, price, OrderOptions{Labels: labels, TIF: "", PostOnly: false, ReduceOnly: false})
+}
 
-	if l.orderSubmitter == nil {
-		return fmt.Errorf("order submitter not configured")
-	}
-	if l.pools == nil {
-		return fmt.Errorf("pool manager not configured")
-	}
+// SubmitOrderWithOptions submits a limit order with the given labels and time-in-force.
+func (l *BaseLambda) SubmitOrderWithOptions(ctx context.Context, provider string, side schema.TradeSide, quantity string, price *string, opts OrderOptions) error {
+	_, err := l.placeOrder(ctx, orderIntent{
+		clientOrderID: "",
+		provider:      provider,
+		symbol:        "",
+		side:          side,
+		orderType:     schema.OrderTypeLimit,
+		quantity:      quantity,
+		price:         price,
+		tif:           opts.TIF,
+		postOnly:      opts.PostOnly,
+		reduceOnly:    opts.ReduceOnly,
+		labels:        opts.Labels,
+		throttle:      throttleModeQueue,
+		external:      false,
+	})
+	return err
+}
 
-	orderID := fmt.Sprintf("%s-%d-%d", l.id, time.Now().UnixNano(), l.orderCount.Load())
+// SubmitMarketOrder submits a market order.
+func (l *BaseLambda) SubmitMarketOrder(ctx context.Context, provider string, side schema.TradeSide, quantity string) error {
+	return l.SubmitTaggedMarketOrder(ctx, provider, side, quantity, OrderLabels{Tags: nil, Metadata: nil})
+}
 
-	orderReq, release, err := pool.AcquireOrderRequest(ctx, l.pools)
-	if err != nil {
-		return fmt.Errorf("acquire order request from pool: %w", err)
-	}
-	defer release()
+// SubmitTaggedMarketOrder submits a market order carrying attribution labels.
+func (l *BaseLambda) SubmitTaggedMarketOrder(ctx context.Context, provider string, side schema.TradeSide, quantity string, labels OrderLabels) error {
+	return l.SubmitMarketOrderWithOptions(ctx, provider, side, quantity, OrderOptions{Labels: labels, TIF: "", PostOnly: false, ReduceOnly: false})
+}
 
-	orderReq.ClientOrderID = orderID
-	orderReq.ConsumerID = l.id
-	orderReq.Provider = provider
-	orderReq.Symbol = l.symbolForProvider(provider)
-	orderReq.Side = side
-	orderReq.OrderType = schema.OrderTypeLimit
-	orderReq.Price = price
-	orderReq.Quantity = quantity
-	orderReq.TIF = "GTC"
-	orderReq.Timestamp = time.Now().UTC()
+// SubmitMarketOrderWithOptions submits a market order with the given labels and time-in-force,
+// which must be IOC or FOK.
+func (l *BaseLambda) SubmitMarketOrderWithOptions(ctx context.Context, provider string, side schema.TradeSide, quantity string, opts OrderOptions) error {
+	_, err := l.placeOrder(ctx, orderIntent{
+		clientOrderID: "",
+		provider:      provider,
+		symbol:        "",
+		side:          side,
+		orderType:     schema.OrderTypeMarket,
+		quantity:      quantity,
+		price:         nil,
+		tif:           opts.TIF,
+		postOnly:      opts.PostOnly,
+		reduceOnly:    opts.ReduceOnly,
+		labels:        opts.Labels,
+		throttle:      throttleModeQueue,
+		external:      false,
+	})
+	return err
+}
 
-	if l.riskManager != nil {
-		if err := l.riskManager.CheckOrder(ctx, orderReq); err != nil {
-			l.emitRiskControlEvent(ctx, l.buildRiskControlPayload(provider, err))
-			return fmt.Errorf("risk check failed: %w", err)
-		}
-	}
+// OrderOptions carries the optional fields of a strategy order.
+type OrderOptions struct {
+	Labels OrderLabels
+	// TIF defaults to GTC for limit orders and IOC for market orders.
+	TIF schema.TimeInForce
+	// PostOnly rejects a limit order that would take liquidity; it is equivalent to TIF GTX.
+	PostOnly bool
+	// ReduceOnly rejects an order that would grow the lambda's position on the symbol.
+	ReduceOnly bool
+}
 
-	if err := l.persistNewOrder(ctx, orderReq); err != nil {
-		return err
-	}
+// orderIntent carries the caller-controlled fields of an order before it is pooled and submitted.
+type orderIntent struct {
+	// clientOrderID is generated from the lambda ID when empty.
+	clientOrderID string
+	provider      string
+	// symbol defaults to the provider's primary symbol when empty.
+	symbol    string
+	side      schema.TradeSide
+	orderType schema.OrderType
+	quantity  string
+	price     *string
+	// tif defaults by order type when empty.
+	tif        schema.TimeInForce
+	postOnly   bool
+	reduceOnly bool
+	labels     OrderLabels
+	throttle   throttleMode
+	// external marks orders entered by systems outside the strategy; see SubmitExternalOrder.
+	external bool
+}
 
-	if err := l.orderSubmitter.SubmitOrder(ctx, *orderReq); err != nil {
-		l.persistOrderFailure(ctx, orderReq.ClientOrderID, err)
-		return fmt.Errorf("submit order: %w", err)
+// orderTimeInForce validates tif for the order type, defaulting it when empty. Market orders
+// never rest, so only IOC and FOK apply to them.
+func orderTimeInForce(orderType schema.OrderType, tif schema.TimeInForce) (schema.TimeInForce, error) {
+	parsed, err := schema.ParseTimeInForce(string(tif))
+	if err != nil {
+		return "", err //nolint:wrapcheck // the message names the rejected value
 	}
-
-	l.orderCount.Add(1)
-	return nil
+	if parsed == "" {
+		return schema.DefaultTimeInForce(orderType), nil
+	}
+	if orderType == schema.OrderTypeMarket && parsed != schema.TimeInForceIOC && parsed != schema.TimeInForceFOK {
+		return "", fmt.Errorf("time in force %s not supported for market orders", parsed)
+	}
+	return parsed, nil
 }
 
-// SubmitMarketOrder submits a market order.
-func (l *BaseLambda) SubmitMarketOrder(ctx context.Context, provider string, side schema.TradeSide, quantity string) error {
-	provider = strings.TrimSpace(provider)
+// placeOrder validates, risk checks, persists and submits an order. It reports false when the
+// order was skipped because the lambda runs in dry-run mode or was parked in the throttle queue.
+func (l *BaseLambda) placeOrder(ctx context.Context, intent orderIntent) (bool, error) {
+	market := intent.orderType == schema.OrderTypeMarket
+	provider := strings.TrimSpace(intent.provider)
 	if provider == "" {
-		return fmt.Errorf("order provider required")
+		return false, fmt.Errorf("order provider required")
 	}
 	if len(l.config.Providers) > 0 {
 		if _, ok := l.providerSet[provider]; !ok {
-			return fmt.Errorf("order provider %q not configured for lambda %s", provider, l.id)
+			return false, fmt.Errorf("order provider %q not configured for lambda %s", provider, l.id)
 		}
 	}
+	tif, err := orderTimeInForce(intent.orderType, intent.tif)
+	if err != nil {
+		return false, err
+	}
+	intent.tif = tif
+	if err := applyPostOnly(&intent); err != nil {
+		return false, err
+	}
+	l.observeIntent(provider, intent)
 
 	if l.IsDryRun() {
-		l.logger.Printf("[%s] dry-run: skip submit market order provider=%s side=%s qty=%s", l.id, provider, side, quantity)
-		return nil
+		if market {
+			l.logger.Printf("[%s] dry-run: skip submit market order provider=%s side=%s qty=%s", l.id, provider, intent.side, intent.quantity)
+			return false, nil
+		}
+		var priceStr string
+		if intent.price != nil {
+			priceStr = *intent.price
+		} else {
+			priceStr = "market"
+		}
+		l.logger.Printf("[%s] dry-run: skip submit order provider=%s side=%s qty=%s price=%s", l.id, provider, intent.side, intent.quantity, priceStr)
+		return false, nil
+	}
+
+	if l.IsWarmingUp() {
+		return false, ErrWarmingUp
+	}
+	if l.IsFrozen() {
+		return false, ErrInstanceFrozen
 	}
 
 	if l.orderSubmitter == nil {
-		return fmt.Errorf("order submitter not configured")
+		return false, fmt.Errorf("order submitter not configured")
 	}
 	if l.pools == nil {
-		return fmt.Errorf("pool manager not configured")
+		return false, fmt.Errorf("pool manager not configured")
 	}
 
-	orderID := fmt.Sprintf("%s-%d-%d", l.id, time.Now().UnixNano(), l.orderCount.Load())
+	orderID := intent.clientOrderID
+	if orderID == "" {
+		orderID = l.newClientOrderID()
+	}
+	symbol := strings.TrimSpace(intent.symbol)
+	if symbol == "" {
+		symbol = l.symbolForProvider(provider)
+	}
 
 	orderReq, release, err := pool.AcquireOrderRequest(ctx, l.pools)
 	if err != nil {
-		return fmt.Errorf("acquire order request from pool: %w", err)
+		return false, fmt.Errorf("acquire order request from pool: %w", err)
 	}
 	defer release()
 
 	orderReq.ClientOrderID = orderID
 	orderReq.ConsumerID = l.id
 	orderReq.Provider = provider
-	orderReq.Symbol = l.symbolForProvider(provider)
-	orderReq.Side = side
-	orderReq.OrderType = schema.OrderTypeMarket
-	orderReq.Quantity = quantity
-	orderReq.TIF = "IOC"
+	orderReq.Symbol = symbol
+	orderReq.Side = intent.side
+	orderReq.OrderType = intent.orderType
+	if !market {
+		orderReq.Price = intent.price
+	}
+	orderReq.Quantity = intent.quantity
+	orderReq.TIF = intent.tif
+	orderReq.PostOnly = intent.postOnly
+	orderReq.ReduceOnly = intent.reduceOnly
+	orderReq.SubAccount = l.SubAccount(provider)
+	labels := intent.labels.normalized()
+	orderReq.Tags = labels.Tags
+	orderReq.Metadata = labels.Metadata
 	orderReq.Timestamp = time.Now().UTC()
 
-	if l.riskManager != nil {
-		if err := l.riskManager.CheckOrder(ctx, orderReq); err != nil {
+	if normalizer, ok := l.orderSubmitter.(OrderNormalizer); ok {
+		if err := normalizer.NormalizeOrder(ctx, orderReq); err != nil {
+			return false, fmt.Errorf("normalize order: %w", err)
+		}
+	}
+	if err := l.emulateOrderFlags(orderReq); err != nil {
+		return false, err
+	}
+
+	if rm := l.riskManager.Load(); rm != nil {
+		var err error
+		if intent.throttle != throttleModeWait && l.queue != nil {
+			err = rm.CheckOrderNoWait(ctx, orderReq)
+		} else {
+			err = rm.CheckOrder(ctx, orderReq)
+		}
+		if _, throttled := risk.ThrottleRetryAfter(err); throttled && intent.throttle != throttleModeWait && l.queue != nil {
+			if intent.throttle == throttleModeProbe {
+				return false, err
+			}
+			intent.clientOrderID = orderID
+			intent.symbol = symbol
+			return false, l.parkOrder(intent)
+		}
+		if err != nil {
 			l.emitRiskControlEvent(ctx, l.buildRiskControlPayload(provider, err))
-			return fmt.Errorf("risk check failed: %w", err)
+			return false, fmt.Errorf("risk check failed: %w", err)
 		}
 	}
 
 	if err := l.persistNewOrder(ctx, orderReq); err != nil {
-		return err
+		return false, err
 	}
 
-	if err := l.orderSubmitter.SubmitOrder(ctx, *orderReq); err != nil {
+	l.tape.mark(orderReq.ClientOrderID, provider, orderReq.Symbol, orderReq.Side)
+	l.labels.mark(orderReq.ClientOrderID, labels)
+	l.orders.placedOrder(orderReq)
+	submitCtx, span := l.traces.startOrder(ctx, l.id, orderReq)
+	err = l.orderSubmitter.SubmitOrder(submitCtx, *orderReq)
+	l.traces.finishOrder(span, orderReq.ClientOrderID, err)
+	if err != nil {
+		l.tape.forget(orderReq.ClientOrderID)
+		l.labels.forget(orderReq.ClientOrderID)
 		l.persistOrderFailure(ctx, orderReq.ClientOrderID, err)
-		return fmt.Errorf("submit market order: %w", err)
+		l.orders.rejected(orderReq.ClientOrderID, err)
+		if market {
+			return false, fmt.Errorf("submit market order: %w", err)
+		}
+		return false, fmt.Errorf("submit order: %w", err)
 	}
 
 	l.orderCount.Add(1)
-	return nil
+	return true, nil
+}
+
+// newClientOrderID returns a client order ID carrying the lambda ID, so IsMyOrder recognises it.
+func (l *BaseLambda) newClientOrderID() string {
+	return fmt.Sprintf("%s-%d-%d", l.id, time.Now().UnixNano(), l.orderCount.Load())
 }
 
 // Protected accessor methods for subclasses
@@ -807,19 +1134,34 @@ func (l *BaseLambda) ID() string {
 // Config returns the lambda configuration.
 func (l *BaseLambda) Config() Config {
 	copyCfg := Config{
-		Providers:       make([]string, len(l.config.Providers)),
-		ProviderSymbols: copyProviderSymbolMap(l.config.ProviderSymbols),
-		DryRun:          l.config.DryRun,
+		Providers:           make([]string, len(l.config.Providers)),
+		ProviderSymbols:     copyProviderSymbolMap(l.config.ProviderSymbols),
+		DryRun:              l.IsDryRun(),
+		Delivery:            l.config.Delivery,
+		DurableSubscription: l.config.DurableSubscription,
+		SubAccounts:         normalizeSubAccounts(l.config.SubAccounts),
 	}
 	copy(copyCfg.Providers, l.config.Providers)
 	return copyCfg
 }
 
+// SubAccount returns the sub-account selected for the provider, or empty for the primary account.
+func (l *BaseLambda) SubAccount(provider string) string {
+	return l.config.SubAccounts[strings.TrimSpace(provider)]
+}
+
 // Logger returns the logger instance.
 func (l *BaseLambda) Logger() *log.Logger {
 	return l.logger
 }
 
+// SetLogger replaces the logger before the lambda starts handling events.
+func (l *BaseLambda) SetLogger(logger *log.Logger) {
+	if logger != nil {
+		l.logger = logger
+	}
+}
+
 // GetMarketState returns the current market state.
 func (l *BaseLambda) GetMarketState() MarketState {
 	lastPrice := l.lastPrice.Load().(float64)
@@ -916,14 +1258,42 @@ func (l *BaseLambda) IsTradingActive() bool {
 	return l.tradingActive.Load()
 }
 
+// Exposure returns the net positions the lambda has built from its own fills, one entry per
+// symbol that is not flat.
+func (l *BaseLambda) Exposure() []Exposure {
+	return l.book.snapshot()
+}
+
+// CloseSession reports the end-of-session position and PnL of every symbol the lambda trades on
+// provider and starts a new PnL session for them. Positions carry over; realised PnL restarts at
+// zero.
+func (l *BaseLambda) CloseSession(provider string) []PositionPnL {
+	symbols, scoped := l.providerSymbols[strings.TrimSpace(provider)]
+	if !scoped || len(symbols) == 0 {
+		return l.book.closeSession(nil)
+	}
+	return l.book.closeSession(func(symbol string) bool {
+		_, ok := symbols[symbol]
+		return ok
+	})
+}
+
+// SetRiskManager swaps the risk manager that checks the lambda's orders, for example when the
+// instance is assigned a different risk profile. Subsequent orders and fills use the new manager.
+func (l *BaseLambda) SetRiskManager(rm *risk.Manager) {
+	l.riskManager.Store(rm)
+}
+
 // IsDryRun reports whether the lambda is operating in dry-run mode.
 func (l *BaseLambda) IsDryRun() bool {
 	return l.dryRun.Load()
 }
 
-// EnableTrading enables or disables trading for this lambda instance.
+// EnableTrading enables or disables live trading for this lambda instance. Disabling it puts the
+// lambda in dry-run mode, so subsequent orders are skipped instead of submitted.
 func (l *BaseLambda) EnableTrading(enabled bool) {
 	l.tradingActive.Store(enabled)
+	l.dryRun.Store(!enabled)
 	status := "DISABLED"
 	if enabled {
 		status = "ENABLED"
@@ -1019,12 +1389,22 @@ func (l *BaseLambda) persistNewOrder(ctx context.Context, req *schema.OrderReque
 		return nil
 	}
 	metadata := map[string]any{}
-	if strings.TrimSpace(req.TIF) != "" {
-		metadata["tif"] = strings.ToUpper(strings.TrimSpace(req.TIF))
+	if req.TIF != "" {
+		metadata["tif"] = string(req.TIF)
 	}
 	if req.Price != nil {
 		metadata["price"] = *req.Price
 	}
+	if req.PostOnly {
+		metadata["postOnly"] = true
+	}
+	if req.ReduceOnly {
+		metadata["reduceOnly"] = true
+	}
+	if req.SubAccount != "" {
+		metadata["subAccount"] = req.SubAccount
+	}
+	OrderLabels{Tags: req.Tags, Metadata: req.Metadata}.apply(metadata)
 	if len(metadata) == 0 {
 		metadata = nil
 	}
@@ -1071,7 +1451,7 @@ func (l *BaseLambda) persistOrderFailure(ctx context.Context, orderID string, su
 	}
 }
 
-func (l *BaseLambda) persistExecReport(ctx context.Context, evt *schema.Event, payload schema.ExecReportPayload) {
+func (l *BaseLambda) persistExecReport(ctx context.Context, evt *schema.Event, payload schema.ExecReportPayload, fills fillUpdate) {
 	if l == nil || l.orderStore == nil || l.IsDryRun() {
 		return
 	}
@@ -1090,6 +1470,9 @@ func (l *BaseLambda) persistExecReport(ctx context.Context, evt *schema.Event, p
 	if strings.TrimSpace(payload.CommissionAsset) != "" {
 		meta["commissionAsset"] = payload.CommissionAsset
 	}
+	if fills.added {
+		meta[orderstore.FillsMetadataKey] = fills.summary
+	}
 	timestamp := payload.Timestamp
 	if timestamp.IsZero() && evt != nil {
 		timestamp = evt.EmitTS
@@ -1115,6 +1498,10 @@ func (l *BaseLambda) persistExecReport(ctx context.Context, evt *schema.Event, p
 	var exec *orderstore.Execution
 	if payload.State == schema.ExecReportStateFILLED || payload.State == schema.ExecReportStatePARTIAL {
 		execution := l.buildExecutionSnapshot(evt, payload)
+		if fills.added {
+			execution.Metadata["fillQuantity"] = fills.fill.quantity.String()
+			execution.Metadata["fillPrice"] = fills.fill.price.String()
+		}
 		exec = &execution
 	}
 	if err := l.orderStore.WithTransaction(ctx, func(txCtx context.Context, tx orderstore.Tx) error {
@@ -1153,6 +1540,12 @@ func (l *BaseLambda) buildExecutionSnapshot(evt *schema.Event, payload schema.Ex
 		exec.Provider = evt.Provider
 		exec.Metadata["eventSymbol"] = evt.Symbol
 	}
+	if result, ok := l.tape.evaluate(payload.ClientOrderID, payload.AvgFillPrice); ok {
+		result.metadata(exec.Metadata)
+	}
+	if labels, ok := l.labels.lookup(payload.ClientOrderID); ok {
+		labels.apply(exec.Metadata)
+	}
 	if strings.TrimSpace(exec.ExecutionID) == "" {
 		exec.ExecutionID = fmt.Sprintf("%s-%d", payload.ClientOrderID, payload.Timestamp.UnixNano())
 	}
@@ -1187,6 +1580,9 @@ func (l *BaseLambda) persistBalance(ctx context.Context, evt *schema.Event, payl
 	if evt != nil {
 		meta["symbol"] = evt.Symbol
 	}
+	if payload.SubAccount != "" {
+		meta["subAccount"] = payload.SubAccount
+	}
 	balance := orderstore.BalanceSnapshot{
 		Provider:   evt.Provider,
 		Asset:      strings.ToUpper(strings.TrimSpace(payload.Currency)),
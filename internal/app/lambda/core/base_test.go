@@ -115,3 +115,73 @@ func (s *testExtensionStrategy) SubscribedEvents() []schema.EventType {
 	return []schema.EventType{schema.ExtensionEventType}
 }
 func (s *testExtensionStrategy) WantsCrossProviderEvents() bool { return false }
+
+type captureSubmitter struct {
+	orders []schema.OrderRequest
+}
+
+func (s *captureSubmitter) SubmitOrder(_ context.Context, req schema.OrderRequest) error {
+	s.orders = append(s.orders, req)
+	return nil
+}
+
+type balanceRecordingStrategy struct {
+	testExtensionStrategy
+	balances []schema.BalanceUpdatePayload
+}
+
+func (s *balanceRecordingStrategy) OnBalanceUpdate(_ context.Context, _ *schema.Event, payload schema.BalanceUpdatePayload) {
+	s.balances = append(s.balances, payload)
+}
+
+func TestSubmitOrderTagsSubAccount(t *testing.T) {
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("OrderRequest", 4, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	submitter := &captureSubmitter{}
+	cfg := Config{
+		Providers:       []string{"binance", "okx"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}, "okx": {"BTC-USDT"}},
+		SubAccounts:     map[string]string{" binance ": " desk-a "},
+	}
+	lambda := NewBaseLambda("lambda-sub", cfg, nil, submitter, poolMgr, nil, nil, nil)
+
+	price := "100"
+	if err := lambda.SubmitOrder(context.Background(), "binance", schema.TradeSideBuy, "1", &price); err != nil {
+		t.Fatalf("SubmitOrder: %v", err)
+	}
+	if err := lambda.SubmitMarketOrder(context.Background(), "okx", schema.TradeSideSell, "1"); err != nil {
+		t.Fatalf("SubmitMarketOrder: %v", err)
+	}
+	if len(submitter.orders) != 2 {
+		t.Fatalf("expected 2 orders, got %d", len(submitter.orders))
+	}
+	if got := submitter.orders[0].SubAccount; got != "desk-a" {
+		t.Fatalf("expected binance order routed to desk-a, got %q", got)
+	}
+	if got := submitter.orders[1].SubAccount; got != "" {
+		t.Fatalf("expected okx order on primary account, got %q", got)
+	}
+}
+
+func TestBalanceUpdatesFilteredBySubAccount(t *testing.T) {
+	strategy := &balanceRecordingStrategy{}
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+		SubAccounts:     map[string]string{"binance": "desk-a"},
+	}
+	lambda := NewBaseLambda("lambda-sub-balance", cfg, nil, nil, nil, strategy, nil, nil)
+
+	for _, subAccount := range []string{"", "desk-b", "desk-a"} {
+		lambda.handleBalanceUpdate(context.Background(), &schema.Event{
+			Provider: "binance",
+			Symbol:   "USDT",
+			Payload:  schema.BalanceUpdatePayload{Currency: "USDT", Total: "1", Available: "1", SubAccount: subAccount},
+		})
+	}
+	if len(strategy.balances) != 1 || strategy.balances[0].SubAccount != "desk-a" {
+		t.Fatalf("expected only desk-a balance, got %+v", strategy.balances)
+	}
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// maxBenchmarkMarks bounds the orders whose benchmarks are retained; the oldest are evicted first.
+const maxBenchmarkMarks = 4096
+
+var basisPoints = decimal.NewFromInt(10000)
+
+// benchmarkMark captures the market when an order (or algo parent) was submitted.
+type benchmarkMark struct {
+	key      string
+	side     schema.TradeSide
+	arrival  decimal.Decimal
+	notional decimal.Decimal
+	volume   decimal.Decimal
+}
+
+// benchmarkResult compares a fill price with the arrival price and the interval VWAP of market
+// trades observed since submission. Slippage is in basis points and positive when the fill was
+// worse than the benchmark for the order side.
+type benchmarkResult struct {
+	ArrivalPrice         string
+	IntervalVWAP         string
+	SlippageVsArrivalBps string
+	SlippageVsVWAPBps    string
+}
+
+// metadata renders the populated benchmark fields for order store metadata.
+func (r benchmarkResult) metadata(dst map[string]any) {
+	if r.ArrivalPrice != "" {
+		dst["arrivalPrice"] = r.ArrivalPrice
+		dst["slippageVsArrivalBps"] = r.SlippageVsArrivalBps
+	}
+	if r.IntervalVWAP != "" {
+		dst["intervalVwap"] = r.IntervalVWAP
+		dst["slippageVsVwapBps"] = r.SlippageVsVWAPBps
+	}
+}
+
+// tapeState accumulates the traded notional and volume of one instrument plus its latest quote.
+type tapeState struct {
+	notional decimal.Decimal
+	volume   decimal.Decimal
+	last     decimal.Decimal
+	bid      decimal.Decimal
+	ask      decimal.Decimal
+}
+
+// marketTape tracks cumulative trade VWAP inputs per provider and symbol and the benchmark marks
+// of in-flight orders, so executions can be scored against the market they traded into.
+type marketTape struct {
+	mu     sync.Mutex
+	states map[string]*tapeState
+	marks  map[string]benchmarkMark
+	order  []string
+}
+
+func newMarketTape() *marketTape {
+	return &marketTape{
+		mu:     sync.Mutex{},
+		states: make(map[string]*tapeState),
+		marks:  make(map[string]benchmarkMark),
+		order:  nil,
+	}
+}
+
+func tapeKey(provider, symbol string) string {
+	return strings.TrimSpace(provider) + "|" + strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+func (t *marketTape) stateLocked(key string) *tapeState {
+	state, ok := t.states[key]
+	if !ok {
+		state = &tapeState{
+			notional: decimal.Zero,
+			volume:   decimal.Zero,
+			last:     decimal.Zero,
+			bid:      decimal.Zero,
+			ask:      decimal.Zero,
+		}
+		t.states[key] = state
+	}
+	return state
+}
+
+func (t *marketTape) observeTrade(provider, symbol, price, quantity string) {
+	px, err := decimal.NewFromString(strings.TrimSpace(price))
+	if err != nil || !px.IsPositive() {
+		return
+	}
+	qty, err := decimal.NewFromString(strings.TrimSpace(quantity))
+	if err != nil || !qty.IsPositive() {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.stateLocked(tapeKey(provider, symbol))
+	state.notional = state.notional.Add(px.Mul(qty))
+	state.volume = state.volume.Add(qty)
+	state.last = px
+}
+
+func (t *marketTape) observeQuote(provider, symbol, last, bid, ask string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.stateLocked(tapeKey(provider, symbol))
+	if px, err := decimal.NewFromString(strings.TrimSpace(last)); err == nil && px.IsPositive() {
+		state.last = px
+	}
+	if px, err := decimal.NewFromString(strings.TrimSpace(bid)); err == nil && px.IsPositive() {
+		state.bid = px
+	}
+	if px, err := decimal.NewFromString(strings.TrimSpace(ask)); err == nil && px.IsPositive() {
+		state.ask = px
+	}
+}
+
+// mark records the arrival price and tape position for id. The arrival price is the quote mid
+// when both sides are known and the last trade otherwise.
+func (t *marketTape) mark(id, provider, symbol string, side schema.TradeSide) {
+	if id == "" {
+		return
+	}
+	key := tapeKey(provider, symbol)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.stateLocked(key)
+	arrival := state.last
+	if state.bid.IsPositive() && state.ask.IsPositive() {
+		arrival = state.bid.Add(state.ask).Div(decimal.NewFromInt(2))
+	}
+	if _, exists := t.marks[id]; !exists {
+		t.order = append(t.order, id)
+	}
+	t.marks[id] = benchmarkMark{
+		key:      key,
+		side:     side,
+		arrival:  arrival,
+		notional: state.notional,
+		volume:   state.volume,
+	}
+	for len(t.order) > maxBenchmarkMarks {
+		delete(t.marks, t.order[0])
+		t.order = t.order[1:]
+	}
+}
+
+// evaluate scores fillPrice against the benchmarks recorded for id.
+func (t *marketTape) evaluate(id, fillPrice string) (benchmarkResult, bool) {
+	var result benchmarkResult
+	fill, err := decimal.NewFromString(strings.TrimSpace(fillPrice))
+	if err != nil || !fill.IsPositive() {
+		return result, false
+	}
+	t.mu.Lock()
+	mark, ok := t.marks[id]
+	var notional, volume decimal.Decimal
+	if ok {
+		state := t.stateLocked(mark.key)
+		notional = state.notional.Sub(mark.notional)
+		volume = state.volume.Sub(mark.volume)
+	}
+	t.mu.Unlock()
+	if !ok {
+		return result, false
+	}
+	if mark.arrival.IsPositive() {
+		result.ArrivalPrice = mark.arrival.String()
+		result.SlippageVsArrivalBps = slippageBps(mark.side, fill, mark.arrival).String()
+	}
+	if volume.IsPositive() {
+		vwap := notional.Div(volume)
+		result.IntervalVWAP = vwap.Round(8).String()
+		result.SlippageVsVWAPBps = slippageBps(mark.side, fill, vwap).String()
+	}
+	return result, result.ArrivalPrice != "" || result.IntervalVWAP != ""
+}
+
+func (t *marketTape) forget(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.marks[id]; !ok {
+		return
+	}
+	delete(t.marks, id)
+	for i, key := range t.order {
+		if key == id {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func slippageBps(side schema.TradeSide, fill, benchmark decimal.Decimal) decimal.Decimal {
+	diff := fill.Sub(benchmark)
+	if side == schema.TradeSideSell {
+		diff = diff.Neg()
+	}
+	return diff.Div(benchmark).Mul(basisPoints).Round(2)
+}
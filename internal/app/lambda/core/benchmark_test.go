@@ -0,0 +1,92 @@
+package core
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestMarketTapeBenchmarksAgainstArrivalAndIntervalVWAP(t *testing.T) {
+	tape := newMarketTape()
+	tape.observeTrade("binance", "BTC-USDT", "90", "5")
+	tape.observeQuote("binance", "BTC-USDT", "100", "99", "101")
+	tape.mark("buy-1", "binance", "BTC-USDT", schema.TradeSideBuy)
+	tape.mark("sell-1", "binance", "BTC-USDT", schema.TradeSideSell)
+
+	// Trades before the mark are excluded from the interval VWAP.
+	tape.observeTrade("binance", "BTC-USDT", "100", "1")
+	tape.observeTrade("binance", "BTC-USDT", "103", "2")
+	tape.observeTrade("okx", "BTC-USDT", "500", "10")
+
+	buy, ok := tape.evaluate("buy-1", "102")
+	if !ok {
+		t.Fatal("expected buy benchmark")
+	}
+	if buy.ArrivalPrice != "100" || buy.IntervalVWAP != "102" {
+		t.Fatalf("unexpected buy benchmarks %+v", buy)
+	}
+	if buy.SlippageVsArrivalBps != "200" || buy.SlippageVsVWAPBps != "0" {
+		t.Fatalf("unexpected buy slippage %+v", buy)
+	}
+
+	sell, ok := tape.evaluate("sell-1", "101")
+	if !ok {
+		t.Fatal("expected sell benchmark")
+	}
+	if sell.SlippageVsArrivalBps != "-100" || sell.SlippageVsVWAPBps != "98.04" {
+		t.Fatalf("unexpected sell slippage %+v", sell)
+	}
+
+	tape.forget("buy-1")
+	if _, ok := tape.evaluate("buy-1", "102"); ok {
+		t.Fatal("expected forgotten order to have no benchmark")
+	}
+	if _, ok := tape.evaluate("sell-1", ""); ok {
+		t.Fatal("expected missing fill price to skip benchmarking")
+	}
+}
+
+func TestMarketTapeEvictsOldestMarks(t *testing.T) {
+	tape := newMarketTape()
+	tape.observeTrade("binance", "BTC-USDT", "100", "1")
+	for i := 0; i <= maxBenchmarkMarks; i++ {
+		tape.mark("order-"+strconv.Itoa(i), "binance", "BTC-USDT", schema.TradeSideBuy)
+	}
+	if len(tape.marks) != maxBenchmarkMarks || len(tape.order) != maxBenchmarkMarks {
+		t.Fatalf("expected %d retained marks, got %d/%d", maxBenchmarkMarks, len(tape.marks), len(tape.order))
+	}
+}
+
+func TestExecutionSnapshotCarriesBenchmarks(t *testing.T) {
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+	}
+	lambda := NewBaseLambda("lambda-bench", cfg, nil, nil, nil, nil, nil, nil)
+	lambda.handleTicker(context.Background(), &schema.Event{
+		Provider: "binance",
+		Symbol:   "BTC-USDT",
+		Payload:  schema.TickerPayload{LastPrice: "100", BidPrice: "99.5", AskPrice: "100.5"},
+	})
+	lambda.tape.mark("lambda-bench-1", "binance", "BTC-USDT", schema.TradeSideBuy)
+	lambda.handleTrade(context.Background(), &schema.Event{
+		Provider: "binance",
+		Symbol:   "BTC-USDT",
+		Payload:  schema.TradePayload{Price: "101", Quantity: "2"},
+	})
+
+	exec := lambda.buildExecutionSnapshot(&schema.Event{Provider: "binance", Symbol: "BTC-USDT"}, schema.ExecReportPayload{
+		ClientOrderID:  "lambda-bench-1",
+		State:          schema.ExecReportStateFILLED,
+		FilledQuantity: "1",
+		AvgFillPrice:   "100.5",
+	})
+	if exec.Metadata["arrivalPrice"] != "100" || exec.Metadata["intervalVwap"] != "101" {
+		t.Fatalf("unexpected benchmark metadata %+v", exec.Metadata)
+	}
+	if exec.Metadata["slippageVsArrivalBps"] != "50" || exec.Metadata["slippageVsVwapBps"] != "-49.5" {
+		t.Fatalf("unexpected slippage metadata %+v", exec.Metadata)
+	}
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// DefaultBookDeltaMaxRate is the number of book deltas delivered per instrument and second by default.
+const DefaultBookDeltaMaxRate = 10
+
+// BookDeltaConfig bounds how often book deltas reach strategies that subscribe to
+// schema.EventTypeBookDelta. Snapshots arriving faster are folded into the next delivered delta.
+// Zero selects the default and a negative value delivers a delta for every snapshot.
+type BookDeltaConfig struct {
+	MaxRate int
+}
+
+func (c BookDeltaConfig) interval() time.Duration {
+	switch {
+	case c.MaxRate < 0:
+		return 0
+	case c.MaxRate == 0:
+		return time.Second / DefaultBookDeltaMaxRate
+	default:
+		return time.Second / time.Duration(c.MaxRate)
+	}
+}
+
+// BookDeltaConsumer marks strategies that take incremental book updates. When such a strategy also
+// subscribes to schema.EventTypeBookDelta, book snapshots are diffed against the host book mirror
+// and OnBookDelta receives the changed levels instead of OnBookSnapshot receiving the full book.
+type BookDeltaConsumer interface {
+	OnBookDelta(ctx context.Context, evt *schema.Event, payload schema.BookDeltaPayload)
+}
+
+// BookView is the mirrored order book of one instrument, best levels first.
+type BookView struct {
+	Provider   string              `json:"provider"`
+	Symbol     string              `json:"symbol"`
+	Bids       []schema.PriceLevel `json:"bids"`
+	Asks       []schema.PriceLevel `json:"asks"`
+	LastUpdate time.Time           `json:"lastUpdate"`
+}
+
+// BookTop is the best bid and ask of a mirrored book; a side is nil while it is empty.
+type BookTop struct {
+	Bid *schema.PriceLevel `json:"bid"`
+	Ask *schema.PriceLevel `json:"ask"`
+}
+
+type mirroredBook struct {
+	bids       []schema.PriceLevel
+	asks       []schema.PriceLevel
+	lastUpdate time.Time
+
+	// sentBids and sentAsks hold the book as of the last delivered delta.
+	sentBids []schema.PriceLevel
+	sentAsks []schema.PriceLevel
+	sent     bool
+	lastSent time.Time
+}
+
+// bookMirror keeps the latest book of every instrument the lambda receives snapshots for, so
+// strategies read depth from the host and deltas can be derived from consecutive snapshots.
+type bookMirror struct {
+	mu       sync.Mutex
+	interval time.Duration
+	books    map[string]*mirroredBook
+}
+
+func newBookMirror(cfg BookDeltaConfig) *bookMirror {
+	return &bookMirror{
+		mu:       sync.Mutex{},
+		interval: cfg.interval(),
+		books:    make(map[string]*mirroredBook),
+	}
+}
+
+// observe stores the snapshot. When deltas is set it also returns the levels changed since the
+// last delivered delta, reporting false while the rate bound holds the delta back or nothing changed.
+func (m *bookMirror) observe(provider, symbol string, payload schema.BookSnapshotPayload, deltas bool, now time.Time) (schema.BookDeltaPayload, bool) {
+	var empty schema.BookDeltaPayload
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := tapeKey(provider, symbol)
+	book, ok := m.books[key]
+	if !ok {
+		book = &mirroredBook{
+			bids:       nil,
+			asks:       nil,
+			lastUpdate: time.Time{},
+			sentBids:   nil,
+			sentAsks:   nil,
+			sent:       false,
+			lastSent:   time.Time{},
+		}
+		m.books[key] = book
+	}
+	book.bids = append(book.bids[:0], payload.Bids...)
+	book.asks = append(book.asks[:0], payload.Asks...)
+	book.lastUpdate = payload.LastUpdate
+	if !deltas {
+		return empty, false
+	}
+	if book.sent && now.Sub(book.lastSent) < m.interval {
+		return empty, false
+	}
+
+	delta := schema.BookDeltaPayload{
+		Bids:       diffLevels(book.sentBids, book.bids),
+		Asks:       diffLevels(book.sentAsks, book.asks),
+		Reset:      !book.sent,
+		LastUpdate: book.lastUpdate,
+	}
+	book.sentBids = append(book.sentBids[:0], book.bids...)
+	book.sentAsks = append(book.sentAsks[:0], book.asks...)
+	book.sent = true
+	book.lastSent = now
+	if !delta.Reset && len(delta.Bids) == 0 && len(delta.Asks) == 0 {
+		return empty, false
+	}
+	return delta, true
+}
+
+// diffLevels returns the levels of current that differ from sent, followed by the levels of sent
+// that are gone with a zero quantity. Prices are compared verbatim as adapters format them
+// consistently per instrument.
+func diffLevels(sent, current []schema.PriceLevel) []schema.PriceLevel {
+	previous := make(map[string]string, len(sent))
+	for _, level := range sent {
+		previous[level.Price] = level.Quantity
+	}
+	present := make(map[string]struct{}, len(current))
+	changed := make([]schema.PriceLevel, 0)
+	for _, level := range current {
+		present[level.Price] = struct{}{}
+		if qty, ok := previous[level.Price]; !ok || qty != level.Quantity {
+			changed = append(changed, level)
+		}
+	}
+	for _, level := range sent {
+		if _, ok := present[level.Price]; !ok {
+			changed = append(changed, schema.PriceLevel{Price: level.Price, Quantity: "0"})
+		}
+	}
+	return changed
+}
+
+// view copies up to depth levels per side of the book; depth <= 0 copies the whole book.
+func (m *bookMirror) view(provider, symbol string, depth int) (BookView, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	book, ok := m.books[tapeKey(provider, symbol)]
+	if !ok {
+		var empty BookView
+		return empty, false
+	}
+	return BookView{
+		Provider:   provider,
+		Symbol:     strings.ToUpper(strings.TrimSpace(symbol)),
+		Bids:       copyLevels(book.bids, depth),
+		Asks:       copyLevels(book.asks, depth),
+		LastUpdate: book.lastUpdate,
+	}, true
+}
+
+func copyLevels(levels []schema.PriceLevel, depth int) []schema.PriceLevel {
+	if depth <= 0 || depth > len(levels) {
+		depth = len(levels)
+	}
+	return append(make([]schema.PriceLevel, 0, depth), levels[:depth]...)
+}
+
+// OrderBook returns up to depth levels per side of the mirrored book of symbol; depth <= 0 returns
+// the whole book. When provider is empty the first configured provider routing the symbol is used.
+func (l *BaseLambda) OrderBook(symbol, provider string, depth int) (BookView, bool) {
+	symbol, provider = l.resolveInstrument(symbol, provider)
+	return l.books.view(provider, symbol, depth)
+}
+
+// BookTop returns the best bid and ask of the mirrored book of symbol.
+func (l *BaseLambda) BookTop(symbol, provider string) (BookTop, bool) {
+	view, ok := l.OrderBook(symbol, provider, 1)
+	top := BookTop{Bid: nil, Ask: nil}
+	if !ok {
+		return top, false
+	}
+	if len(view.Bids) > 0 {
+		top.Bid = &view.Bids[0]
+	}
+	if len(view.Asks) > 0 {
+		top.Ask = &view.Asks[0]
+	}
+	return top, true
+}
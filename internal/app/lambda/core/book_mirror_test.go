@@ -0,0 +1,120 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func levels(pairs ...string) []schema.PriceLevel {
+	out := make([]schema.PriceLevel, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		out = append(out, schema.PriceLevel{Price: pairs[i], Quantity: pairs[i+1]})
+	}
+	return out
+}
+
+func TestBookMirrorDerivesDeltas(t *testing.T) {
+	mirror := newBookMirror(BookDeltaConfig{MaxRate: -1})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	first, ok := mirror.observe("binance", "BTC-USDT", schema.BookSnapshotPayload{
+		Bids: levels("100", "1", "99", "2"),
+		Asks: levels("101", "1"),
+	}, true, now)
+	if !ok || !first.Reset || len(first.Bids) != 2 || len(first.Asks) != 1 {
+		t.Fatalf("expected the first delta to reset the book, got %+v (ok=%v)", first, ok)
+	}
+
+	delta, ok := mirror.observe("binance", "BTC-USDT", schema.BookSnapshotPayload{
+		Bids: levels("100", "3"),
+		Asks: levels("101", "1", "102", "4"),
+	}, true, now.Add(time.Millisecond))
+	if !ok || delta.Reset {
+		t.Fatalf("expected an incremental delta, got %+v (ok=%v)", delta, ok)
+	}
+	if len(delta.Bids) != 2 || delta.Bids[0] != (schema.PriceLevel{Price: "100", Quantity: "3"}) || delta.Bids[1] != (schema.PriceLevel{Price: "99", Quantity: "0"}) {
+		t.Fatalf("unexpected bid changes %+v", delta.Bids)
+	}
+	if len(delta.Asks) != 1 || delta.Asks[0].Price != "102" {
+		t.Fatalf("unexpected ask changes %+v", delta.Asks)
+	}
+
+	if _, ok := mirror.observe("binance", "BTC-USDT", schema.BookSnapshotPayload{
+		Bids: levels("100", "3"),
+		Asks: levels("101", "1", "102", "4"),
+	}, true, now.Add(2*time.Millisecond)); ok {
+		t.Fatal("expected an unchanged book to yield no delta")
+	}
+}
+
+func TestBookMirrorCoalescesAboveMaxRate(t *testing.T) {
+	mirror := newBookMirror(BookDeltaConfig{MaxRate: 10})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mirror.observe("okx", "ETH-USDT", schema.BookSnapshotPayload{Bids: levels("10", "1")}, true, now)
+	if _, ok := mirror.observe("okx", "ETH-USDT", schema.BookSnapshotPayload{Bids: levels("10", "2")}, true, now.Add(20*time.Millisecond)); ok {
+		t.Fatal("expected the rate bound to hold the delta back")
+	}
+	delta, ok := mirror.observe("okx", "ETH-USDT", schema.BookSnapshotPayload{Bids: levels("10", "2", "9", "5")}, true, now.Add(100*time.Millisecond))
+	if !ok || len(delta.Bids) != 2 || delta.Bids[0].Quantity != "2" {
+		t.Fatalf("expected the held-back change folded into the next delta, got %+v (ok=%v)", delta, ok)
+	}
+}
+
+type bookDeltaStrategy struct {
+	testExtensionStrategy
+	snapshots int
+	deltas    []schema.BookDeltaPayload
+	events    []schema.EventType
+}
+
+func (s *bookDeltaStrategy) OnBookSnapshot(context.Context, *schema.Event, schema.BookSnapshotPayload) {
+	s.snapshots++
+}
+
+func (s *bookDeltaStrategy) OnBookDelta(_ context.Context, evt *schema.Event, payload schema.BookDeltaPayload) {
+	s.events = append(s.events, evt.Type)
+	s.deltas = append(s.deltas, payload)
+}
+
+func (s *bookDeltaStrategy) SubscribedEvents() []schema.EventType {
+	return []schema.EventType{schema.EventTypeBookDelta}
+}
+
+func TestBaseLambdaDispatchesBookDeltasAndMirrorsBook(t *testing.T) {
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+		BookDeltas:      BookDeltaConfig{MaxRate: -1},
+	}
+	strategy := &bookDeltaStrategy{}
+	lambda := NewBaseLambda("lambda-book", cfg, nil, nil, nil, strategy, nil, nil)
+	for _, bids := range [][]schema.PriceLevel{levels("100", "1", "99", "2"), levels("100", "1", "99", "3")} {
+		lambda.handleBookSnapshot(context.Background(), &schema.Event{
+			Type:     schema.EventTypeBookSnapshot,
+			Provider: "binance",
+			Symbol:   "BTC-USDT",
+			Payload:  schema.BookSnapshotPayload{Bids: bids, Asks: levels("101", "4")},
+		})
+	}
+	if strategy.snapshots != 0 || len(strategy.deltas) != 2 {
+		t.Fatalf("expected deltas instead of snapshots, got %d snapshots and %d deltas", strategy.snapshots, len(strategy.deltas))
+	}
+	if strategy.events[1] != schema.EventTypeBookDelta || len(strategy.deltas[1].Bids) != 1 || strategy.deltas[1].Bids[0].Price != "99" {
+		t.Fatalf("unexpected delta %+v", strategy.deltas[1])
+	}
+
+	top, ok := lambda.BookTop("btc-usdt", "")
+	if !ok || top.Bid == nil || top.Bid.Price != "100" || top.Ask == nil || top.Ask.Price != "101" {
+		t.Fatalf("unexpected top of book %+v (ok=%v)", top, ok)
+	}
+	view, ok := lambda.OrderBook("BTC-USDT", "binance", 1)
+	if !ok || len(view.Bids) != 1 || len(view.Asks) != 1 {
+		t.Fatalf("expected one level per side, got %+v", view)
+	}
+	if full, _ := lambda.OrderBook("BTC-USDT", "binance", 0); len(full.Bids) != 2 || full.Bids[1].Quantity != "3" {
+		t.Fatalf("expected the whole mirrored book, got %+v", full)
+	}
+}
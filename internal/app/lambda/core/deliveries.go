@@ -0,0 +1,105 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// DeliveryStat counts the events of one provider and type that reached the lambda since it was
+// created.
+type DeliveryStat struct {
+	Provider  string           `json:"provider"`
+	EventType schema.EventType `json:"eventType"`
+	// Delivered counts events handed to the strategy. Filtered counts events from a scoped
+	// provider that were dropped because their symbol or balance currency is out of scope.
+	Delivered    int64      `json:"delivered"`
+	Filtered     int64      `json:"filtered"`
+	LastDelivery *time.Time `json:"lastDelivery,omitempty"`
+}
+
+type deliveryKey struct {
+	provider string
+	typ      schema.EventType
+}
+
+type deliveryCounter struct {
+	delivered atomic.Int64
+	filtered  atomic.Int64
+	// last is the Unix nanosecond time of the latest delivery.
+	last atomic.Int64
+}
+
+// deliveryStats tracks per provider and event type delivery counters. Counters are created on
+// first use and never removed, so the hot path only takes the read lock.
+type deliveryStats struct {
+	mu       sync.RWMutex
+	counters map[deliveryKey]*deliveryCounter
+}
+
+func newDeliveryStats() *deliveryStats {
+	return &deliveryStats{mu: sync.RWMutex{}, counters: make(map[deliveryKey]*deliveryCounter)}
+}
+
+func (s *deliveryStats) counter(provider string, typ schema.EventType) *deliveryCounter {
+	key := deliveryKey{provider: provider, typ: typ}
+	s.mu.RLock()
+	counter, ok := s.counters[key]
+	s.mu.RUnlock()
+	if ok {
+		return counter
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if counter, ok = s.counters[key]; !ok {
+		counter = new(deliveryCounter)
+		s.counters[key] = counter
+	}
+	return counter
+}
+
+func (s *deliveryStats) delivered(provider string, typ schema.EventType, at time.Time) {
+	counter := s.counter(provider, typ)
+	counter.delivered.Add(1)
+	counter.last.Store(at.UnixNano())
+}
+
+func (s *deliveryStats) filtered(provider string, typ schema.EventType) {
+	s.counter(provider, typ).filtered.Add(1)
+}
+
+func (s *deliveryStats) snapshot() []DeliveryStat {
+	s.mu.RLock()
+	out := make([]DeliveryStat, 0, len(s.counters))
+	for key, counter := range s.counters {
+		stat := DeliveryStat{
+			Provider:     key.provider,
+			EventType:    key.typ,
+			Delivered:    counter.delivered.Load(),
+			Filtered:     counter.filtered.Load(),
+			LastDelivery: nil,
+		}
+		if last := counter.last.Load(); last > 0 {
+			at := time.Unix(0, last).UTC()
+			stat.LastDelivery = &at
+		}
+		out = append(out, stat)
+	}
+	s.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].EventType < out[j].EventType
+	})
+	return out
+}
+
+// DeliveryStats reports, per provider and event type, how many events reached the lambda, how
+// many its scope filtered out and when the latest was delivered.
+func (l *BaseLambda) DeliveryStats() []DeliveryStat {
+	return l.deliveries.snapshot()
+}
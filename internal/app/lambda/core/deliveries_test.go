@@ -0,0 +1,27 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestDeliveryStatsCountScopedEvents(t *testing.T) {
+	cfg := Config{Providers: []string{"binance"}, ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}}}
+	lambda := NewBaseLambda("deliveries", cfg, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+	trade := schema.TradePayload{Price: "100", Quantity: "1", Side: schema.TradeSideBuy}
+	lambda.handleEvent(ctx, schema.EventTypeTrade, &schema.Event{Type: schema.EventTypeTrade, Provider: "binance", Symbol: "BTC-USDT", Payload: trade})
+	lambda.handleEvent(ctx, schema.EventTypeTrade, &schema.Event{Type: schema.EventTypeTrade, Provider: "binance", Symbol: "BTC-USDT", Payload: trade})
+	lambda.handleEvent(ctx, schema.EventTypeTrade, &schema.Event{Type: schema.EventTypeTrade, Provider: "binance", Symbol: "ETH-USDT", Payload: trade})
+	lambda.handleEvent(ctx, schema.EventTypeTrade, &schema.Event{Type: schema.EventTypeTrade, Provider: "okx", Symbol: "BTC-USDT", Payload: trade})
+
+	stats := lambda.DeliveryStats()
+	if len(stats) != 1 {
+		t.Fatalf("expected events of unscoped providers ignored, got %+v", stats)
+	}
+	if got := stats[0]; got.Provider != "binance" || got.EventType != schema.EventTypeTrade || got.Delivered != 2 || got.Filtered != 1 || got.LastDelivery == nil {
+		t.Fatalf("unexpected stats %+v", got)
+	}
+}
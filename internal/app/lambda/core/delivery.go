@@ -0,0 +1,228 @@
+package core
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// DeliveryMode selects how events are handed to strategy callbacks.
+type DeliveryMode string
+
+const (
+	// DeliveryModeSerial invokes every strategy callback from a single goroutine in arrival order.
+	DeliveryModeSerial DeliveryMode = "serial"
+	// DeliveryModeWorkerPool spreads callbacks across a fixed set of workers. Events sharing the
+	// same provider and instrument always land on the same worker, preserving their relative order.
+	// JavaScript strategies run every handler on their single VM goroutine, so for them the workers
+	// only overlap queueing with handling and add no handler concurrency.
+	DeliveryModeWorkerPool DeliveryMode = "worker_pool"
+)
+
+const (
+	defaultDeliveryWorkers     = 4
+	defaultMaxInFlightEvents   = 1024
+	minDeliveryLaneBufferDepth = 1
+	deliveryIdlePoll           = 5 * time.Millisecond
+)
+
+// DeliveryConfig describes the ordering and concurrency guarantees applied to strategy callbacks.
+//
+// Regardless of mode, callbacks for a given provider/instrument pair are never invoked concurrently
+// and are observed in the order the events left the bus. MaxInFlight bounds the number of events
+// queued for handlers; once reached, consumption blocks and the bus applies its own backpressure.
+// When Scheduler is set, every callback first takes a slot from it at the instance Priority.
+type DeliveryConfig struct {
+	Mode        DeliveryMode
+	Workers     int
+	MaxInFlight int
+	Priority    Priority
+	Scheduler   *HandlerScheduler
+}
+
+// normalize fills defaults and clamps invalid values.
+func (c DeliveryConfig) normalize() DeliveryConfig {
+	mode := DeliveryMode(strings.ToLower(strings.TrimSpace(string(c.Mode))))
+	switch mode {
+	case DeliveryModeWorkerPool:
+		if c.Workers <= 0 {
+			c.Workers = defaultDeliveryWorkers
+		}
+	default:
+		mode = DeliveryModeSerial
+		c.Workers = 1
+	}
+	c.Mode = mode
+	c.Priority = c.Priority.normalize()
+	if c.MaxInFlight <= 0 {
+		c.MaxInFlight = defaultMaxInFlightEvents
+	}
+	if c.MaxInFlight < c.Workers {
+		c.MaxInFlight = c.Workers
+	}
+	return c
+}
+
+type deliveryItem struct {
+	typ schema.EventType
+	evt *schema.Event
+}
+
+// deliveryScheduler routes events into ordered lanes served by dedicated workers. depth counts the
+// events waiting in the lanes; pending counts them from admission until their handler returns, so
+// it also covers events still blocked on a full lane and the ones being handled.
+type deliveryScheduler struct {
+	cfg        DeliveryConfig
+	lanes      []chan deliveryItem
+	depth      atomic.Int64
+	pending    atomic.Int64
+	handle     func(context.Context, schema.EventType, *schema.Event)
+	recycle    func(*schema.Event)
+	queueDepth metric.Int64UpDownCounter
+	attrs      metric.MeasurementOption
+	wg         sync.WaitGroup
+}
+
+func newDeliveryScheduler(lambdaID string, cfg DeliveryConfig, handle func(context.Context, schema.EventType, *schema.Event), recycle func(*schema.Event)) *deliveryScheduler {
+	cfg = cfg.normalize()
+	perLane := cfg.MaxInFlight / cfg.Workers
+	if perLane < minDeliveryLaneBufferDepth {
+		perLane = minDeliveryLaneBufferDepth
+	}
+	lanes := make([]chan deliveryItem, cfg.Workers)
+	for i := range lanes {
+		lanes[i] = make(chan deliveryItem, perLane)
+	}
+	meter := otel.Meter("lambda")
+	queueDepth, _ := meter.Int64UpDownCounter("lambda.delivery.queue_depth",
+		metric.WithDescription("Number of events queued for strategy handlers"),
+		metric.WithUnit("{event}"))
+	return &deliveryScheduler{
+		cfg:        cfg,
+		lanes:      lanes,
+		depth:      atomic.Int64{},
+		pending:    atomic.Int64{},
+		handle:     handle,
+		recycle:    recycle,
+		queueDepth: queueDepth,
+		attrs: metric.WithAttributes(
+			attribute.String("environment", telemetry.Environment()),
+			attribute.String("lambda", lambdaID),
+			attribute.String("mode", string(cfg.Mode)),
+			attribute.String("priority", string(cfg.Priority))),
+		wg: sync.WaitGroup{},
+	}
+}
+
+// start launches one worker per lane.
+func (s *deliveryScheduler) start(ctx context.Context) {
+	for _, lane := range s.lanes {
+		s.wg.Add(1)
+		go s.run(ctx, lane)
+	}
+}
+
+func (s *deliveryScheduler) run(ctx context.Context, lane <-chan deliveryItem) {
+	defer s.wg.Done()
+	for item := range lane {
+		s.adjustDepth(ctx, -1)
+		if ctx.Err() != nil || !s.cfg.Scheduler.acquire(ctx, s.cfg.Priority) {
+			s.recycle(item.evt)
+			s.pending.Add(-1)
+			continue
+		}
+		s.handle(ctx, item.typ, item.evt)
+		s.cfg.Scheduler.release()
+		s.pending.Add(-1)
+	}
+}
+
+// admit counts events about to be enqueued with enqueueAdmitted. Callers deciding under a lock
+// whether to deliver admit before releasing it, so waitIdle cannot miss an event in between.
+func (s *deliveryScheduler) admit(n int) {
+	s.pending.Add(int64(n))
+}
+
+// enqueue places the event on its lane, blocking while the lane is full. It returns false
+// when the context is cancelled before the event could be queued; the event is recycled.
+func (s *deliveryScheduler) enqueue(ctx context.Context, typ schema.EventType, evt *schema.Event) bool {
+	s.admit(1)
+	return s.enqueueAdmitted(ctx, typ, evt)
+}
+
+// enqueueAdmitted is enqueue for an event already counted by admit.
+func (s *deliveryScheduler) enqueueAdmitted(ctx context.Context, typ schema.EventType, evt *schema.Event) bool {
+	lane := s.lanes[s.laneFor(evt)]
+	select {
+	case lane <- deliveryItem{typ: typ, evt: evt}:
+		s.adjustDepth(ctx, 1)
+		return true
+	case <-ctx.Done():
+		s.recycle(evt)
+		s.pending.Add(-1)
+		return false
+	}
+}
+
+// waitIdle blocks until every admitted event has been handled or ctx ends, and reports whether
+// the scheduler went idle.
+func (s *deliveryScheduler) waitIdle(ctx context.Context) bool {
+	if s.pending.Load() <= 0 {
+		return true
+	}
+	ticker := time.NewTicker(deliveryIdlePoll)
+	defer ticker.Stop()
+	for s.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+// close stops accepting events and waits for the workers to drain their lanes.
+func (s *deliveryScheduler) close() {
+	for _, lane := range s.lanes {
+		close(lane)
+	}
+	s.wg.Wait()
+}
+
+func (s *deliveryScheduler) laneFor(evt *schema.Event) int {
+	if len(s.lanes) == 1 || evt == nil {
+		return 0
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(evt.Provider))
+	_, _ = hasher.Write([]byte{0})
+	_, _ = hasher.Write([]byte(evt.Symbol))
+	return int(hasher.Sum32() % uint32(len(s.lanes)))
+}
+
+func (s *deliveryScheduler) adjustDepth(ctx context.Context, delta int64) {
+	s.depth.Add(delta)
+	if s.queueDepth != nil {
+		s.queueDepth.Add(context.WithoutCancel(ctx), delta, s.attrs)
+	}
+}
+
+// QueueDepth reports the number of events queued for strategy handlers but not yet dispatched.
+func (l *BaseLambda) QueueDepth() int64 {
+	scheduler := l.scheduler.Load()
+	if scheduler == nil {
+		return 0
+	}
+	return scheduler.depth.Load()
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestDeliveryConfig_NormalizeDefaults(t *testing.T) {
+	serial := DeliveryConfig{}.normalize()
+	if serial.Mode != DeliveryModeSerial || serial.Workers != 1 || serial.MaxInFlight != defaultMaxInFlightEvents {
+		t.Fatalf("unexpected serial defaults: %+v", serial)
+	}
+	pool := DeliveryConfig{Mode: "Worker_Pool", MaxInFlight: 2}.normalize()
+	if pool.Mode != DeliveryModeWorkerPool || pool.Workers != defaultDeliveryWorkers {
+		t.Fatalf("unexpected worker pool defaults: %+v", pool)
+	}
+	if pool.MaxInFlight != pool.Workers {
+		t.Fatalf("expected max in-flight clamped to worker count, got %d", pool.MaxInFlight)
+	}
+}
+
+func TestDeliveryScheduler_PreservesPerInstrumentOrder(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		seen    = make(map[string][]int)
+		active  = make(map[string]*atomic.Int32)
+		overlap atomic.Bool
+	)
+	symbols := []string{"BTC-USDT", "ETH-USDT", "SOL-USDT", "XRP-USDT"}
+	for _, sym := range symbols {
+		active[sym] = &atomic.Int32{}
+	}
+	handle := func(_ context.Context, _ schema.EventType, evt *schema.Event) {
+		counter := active[evt.Symbol]
+		if counter.Add(1) > 1 {
+			overlap.Store(true)
+		}
+		time.Sleep(time.Microsecond)
+		var seq int
+		_, _ = fmt.Sscanf(evt.EventID, "%d", &seq)
+		mu.Lock()
+		seen[evt.Symbol] = append(seen[evt.Symbol], seq)
+		mu.Unlock()
+		counter.Add(-1)
+	}
+	scheduler := newDeliveryScheduler("lambda-order", DeliveryConfig{Mode: DeliveryModeWorkerPool, Workers: 3, MaxInFlight: 6}, handle, func(*schema.Event) {})
+	ctx := context.Background()
+	scheduler.start(ctx)
+
+	const perSymbol = 50
+	for i := 0; i < perSymbol; i++ {
+		for _, sym := range symbols {
+			evt := &schema.Event{EventID: fmt.Sprintf("%d", i), Provider: "binance", Symbol: sym}
+			if !scheduler.enqueue(ctx, schema.EventTypeTrade, evt) {
+				t.Fatalf("enqueue rejected event %d for %s", i, sym)
+			}
+		}
+	}
+	scheduler.close()
+
+	if overlap.Load() {
+		t.Fatal("expected callbacks for the same instrument to be serialised")
+	}
+	for _, sym := range symbols {
+		got := seen[sym]
+		if len(got) != perSymbol {
+			t.Fatalf("%s: expected %d events, got %d", sym, perSymbol, len(got))
+		}
+		for i, seq := range got {
+			if seq != i {
+				t.Fatalf("%s: event %d delivered out of order (got seq %d)", sym, i, seq)
+			}
+		}
+	}
+	if depth := scheduler.depth.Load(); depth != 0 {
+		t.Fatalf("expected empty queue after close, got depth %d", depth)
+	}
+}
+
+func TestDeliveryScheduler_EnqueueRespectsCancellation(t *testing.T) {
+	release := make(chan struct{})
+	var recycled atomic.Int32
+	handle := func(context.Context, schema.EventType, *schema.Event) { <-release }
+	scheduler := newDeliveryScheduler("lambda-cancel", DeliveryConfig{MaxInFlight: 1}, handle, func(*schema.Event) { recycled.Add(1) })
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.start(ctx)
+
+	// First event occupies the worker, second fills the lane buffer.
+	scheduler.enqueue(ctx, schema.EventTypeTrade, &schema.Event{Symbol: "BTC-USDT"})
+	scheduler.enqueue(ctx, schema.EventTypeTrade, &schema.Event{Symbol: "BTC-USDT"})
+
+	done := make(chan bool, 1)
+	go func() {
+		done <- scheduler.enqueue(ctx, schema.EventTypeTrade, &schema.Event{Symbol: "BTC-USDT"})
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected enqueue to block while max in-flight is reached")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	if accepted := <-done; accepted {
+		t.Fatal("expected enqueue to fail after cancellation")
+	}
+	close(release)
+	scheduler.close()
+	if recycled.Load() < 2 {
+		t.Fatalf("expected rejected and cancelled events to be recycled, got %d", recycled.Load())
+	}
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+)
+
+const (
+	offsetCommitInterval = time.Second
+	offsetCommitTimeout  = 5 * time.Second
+)
+
+// durableEventTypes lists the event types replayed to durable subscribers after a restart.
+var durableEventTypes = map[schema.EventType]struct{}{
+	schema.EventTypeExecReport:    {},
+	schema.EventTypeBalanceUpdate: {},
+}
+
+func isDurableEventType(typ schema.EventType) bool {
+	_, ok := durableEventTypes[typ]
+	return ok
+}
+
+// durableCursor tracks the outbox offsets handled by a lambda and periodically commits them.
+//
+// With worker pool delivery, events on different lanes may complete out of order; the cursor
+// commits the highest handled offset, so a crash can skip unhandled events on slower lanes.
+// Serial delivery provides strict at-least-once semantics.
+type durableCursor struct {
+	subscriber string
+	bus        eventbus.DurableSubscriber
+	replayHigh int64
+	handled    atomic.Int64
+	committed  atomic.Int64
+}
+
+// durableCursorFor returns a cursor when durable subscriptions are enabled and supported by the bus.
+func (l *BaseLambda) durableCursorFor() *durableCursor {
+	if !l.config.DurableSubscription {
+		return nil
+	}
+	durable, ok := l.bus.(eventbus.DurableSubscriber)
+	if !ok {
+		l.logger.Printf("[%s] durable subscription requested but bus does not support offsets", l.id)
+		return nil
+	}
+	return &durableCursor{
+		subscriber: "lambda:" + l.id,
+		bus:        durable,
+		replayHigh: 0,
+		handled:    atomic.Int64{},
+		committed:  atomic.Int64{},
+	}
+}
+
+// replay feeds events missed since the last committed offset into the scheduler.
+func (l *BaseLambda) replay(ctx context.Context, cursor *durableCursor, subs []subscription, scheduler *deliveryScheduler) {
+	types := make([]schema.EventType, 0, len(durableEventTypes))
+	for _, sub := range subs {
+		if isDurableEventType(sub.typ) {
+			types = append(types, sub.typ)
+		}
+	}
+	replayed := 0
+	high, err := cursor.bus.Replay(ctx, cursor.subscriber, types, func(evt *schema.Event) {
+		if scheduler.enqueue(ctx, evt.Type, evt) {
+			replayed++
+		}
+	})
+	if err != nil {
+		if !errors.Is(err, eventbus.ErrDurableSubscriptionsUnsupported) {
+			l.logger.Printf("[%s] durable replay failed: %v", l.id, err)
+		}
+		return
+	}
+	cursor.replayHigh = high
+	cursor.handled.Store(high)
+	cursor.committed.Store(high)
+	if replayed > 0 {
+		l.logger.Printf("[%s] replayed %d durable events up to offset %d", l.id, replayed, high)
+	}
+}
+
+// skip reports whether a live event was already delivered during replay.
+func (c *durableCursor) skip(typ schema.EventType, evt *schema.Event) bool {
+	if c == nil || evt == nil || !isDurableEventType(typ) {
+		return false
+	}
+	return evt.OutboxID > 0 && evt.OutboxID <= c.replayHigh
+}
+
+// advance records that the event with the given outbox identifier has been handled.
+func (c *durableCursor) advance(offset int64) {
+	if c == nil || offset <= 0 {
+		return
+	}
+	for {
+		current := c.handled.Load()
+		if offset <= current || c.handled.CompareAndSwap(current, offset) {
+			return
+		}
+	}
+}
+
+// commit flushes the handled offset when it moved since the previous commit.
+func (c *durableCursor) commit(ctx context.Context) error {
+	if c == nil {
+		return nil
+	}
+	handled := c.handled.Load()
+	if handled <= c.committed.Load() {
+		return nil
+	}
+	if err := c.bus.CommitOffset(ctx, c.subscriber, handled); err != nil {
+		return err
+	}
+	c.committed.Store(handled)
+	return nil
+}
+
+// runCommitter periodically commits offsets until ctx is cancelled, then performs a final flush.
+func (l *BaseLambda) runCommitter(ctx context.Context, cursor *durableCursor, done <-chan struct{}) {
+	ticker := time.NewTicker(offsetCommitInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), offsetCommitTimeout)
+			if err := cursor.commit(flushCtx); err != nil {
+				l.logger.Printf("[%s] final offset commit failed: %v", l.id, err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := cursor.commit(ctx); err != nil && ctx.Err() == nil {
+				l.logger.Printf("[%s] offset commit failed: %v", l.id, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// Exposure reports the net position a lambda built on one symbol from its own fills.
+type Exposure struct {
+	Symbol string `json:"symbol"`
+	// Position is signed: positive when long, negative when short.
+	Position decimal.Decimal `json:"position"`
+	// Notional is the absolute position valued at the latest observed price in quote currency.
+	Notional decimal.Decimal `json:"notional"`
+}
+
+// PositionPnL reports the position of one symbol together with its profit and loss in quote
+// currency. Realized PnL uses average-cost accounting.
+type PositionPnL struct {
+	Symbol   string          `json:"symbol"`
+	Position decimal.Decimal `json:"position"`
+	// AvgPrice is the average entry price of the open position.
+	AvgPrice  decimal.Decimal `json:"avgPrice"`
+	MarkPrice decimal.Decimal `json:"markPrice"`
+	// RealizedPnL covers the positions closed since the current session started.
+	RealizedPnL   decimal.Decimal `json:"realizedPnl"`
+	UnrealizedPnL decimal.Decimal `json:"unrealizedPnl"`
+}
+
+// appliedFill is the cumulative fill of one order the book has already accounted for.
+type appliedFill struct {
+	quantity decimal.Decimal
+	notional decimal.Decimal
+}
+
+// positionBook nets the fills of a lambda's orders per symbol. Exec reports carry cumulative
+// filled quantities, so the book remembers what it already applied per order.
+type positionBook struct {
+	mu        sync.Mutex
+	applied   map[string]appliedFill
+	positions map[string]decimal.Decimal
+	costs     map[string]decimal.Decimal
+	realized  map[string]decimal.Decimal
+	prices    map[string]decimal.Decimal
+}
+
+func newPositionBook() *positionBook {
+	return &positionBook{
+		mu:        sync.Mutex{},
+		applied:   make(map[string]appliedFill),
+		positions: make(map[string]decimal.Decimal),
+		costs:     make(map[string]decimal.Decimal),
+		realized:  make(map[string]decimal.Decimal),
+		prices:    make(map[string]decimal.Decimal),
+	}
+}
+
+// observePrice records the latest market price for symbol. Non-positive prices are ignored.
+func (b *positionBook) observePrice(symbol, price string) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	value, err := decimal.NewFromString(strings.TrimSpace(price))
+	if symbol == "" || err != nil || !value.IsPositive() {
+		return
+	}
+	b.mu.Lock()
+	b.prices[symbol] = value
+	b.mu.Unlock()
+}
+
+// apply folds the newly filled quantity of an exec report into the symbol position.
+func (b *positionBook) apply(symbol string, payload schema.ExecReportPayload) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" || payload.ClientOrderID == "" {
+		return
+	}
+	filled, err := decimal.NewFromString(strings.TrimSpace(payload.FilledQuantity))
+	if err != nil {
+		filled = decimal.Zero
+	}
+	avgPrice, err := decimal.NewFromString(strings.TrimSpace(payload.AvgFillPrice))
+	if err != nil || !avgPrice.IsPositive() {
+		avgPrice = decimal.Zero
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	prev := b.applied[payload.ClientOrderID]
+	delta := filled.Sub(prev.quantity)
+	if delta.IsPositive() {
+		if _, ok := b.prices[symbol]; !ok && avgPrice.IsPositive() {
+			b.prices[symbol] = avgPrice
+		}
+		// The average price covers the cumulative fill, so the price of this increment is
+		// derived from the notional not yet applied.
+		notional := filled.Mul(avgPrice)
+		price := b.prices[symbol]
+		if avgPrice.IsPositive() && prev.notional.LessThan(notional) {
+			price = notional.Sub(prev.notional).Div(delta)
+		} else {
+			notional = prev.notional.Add(delta.Mul(price))
+		}
+		switch payload.Side {
+		case schema.TradeSideBuy:
+			b.fillLocked(symbol, delta, price)
+		case schema.TradeSideSell:
+			b.fillLocked(symbol, delta.Neg(), price)
+		}
+		b.applied[payload.ClientOrderID] = appliedFill{quantity: filled, notional: notional}
+	}
+	switch payload.State {
+	case schema.ExecReportStateFILLED, schema.ExecReportStateCANCELLED, schema.ExecReportStateREJECTED, schema.ExecReportStateEXPIRED:
+		delete(b.applied, payload.ClientOrderID)
+	}
+}
+
+// fillLocked moves the position of symbol by the signed quantity at price, realising PnL on the
+// part that reduces the position.
+func (b *positionBook) fillLocked(symbol string, quantity, price decimal.Decimal) {
+	position := b.positions[symbol]
+	cost := b.costs[symbol]
+	next := position.Add(quantity)
+	switch {
+	case position.IsZero() || position.Sign() == quantity.Sign():
+		b.costs[symbol] = position.Abs().Mul(cost).Add(quantity.Abs().Mul(price)).Div(next.Abs())
+	default:
+		closed := decimal.Min(quantity.Abs(), position.Abs())
+		pnl := price.Sub(cost).Mul(closed)
+		if position.IsNegative() {
+			pnl = pnl.Neg()
+		}
+		b.realized[symbol] = b.realized[symbol].Add(pnl)
+		switch {
+		case next.IsZero():
+			delete(b.costs, symbol)
+		case next.Sign() != position.Sign():
+			b.costs[symbol] = price
+		}
+	}
+	b.positions[symbol] = next
+}
+
+// closeSession reports the positions and session PnL of the symbols accepted by include and
+// starts a new session for them. Symbols that are flat and realised nothing are omitted.
+func (b *positionBook) closeSession(include func(symbol string) bool) []PositionPnL {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	symbols := make(map[string]struct{}, len(b.positions))
+	for symbol := range b.positions {
+		symbols[symbol] = struct{}{}
+	}
+	for symbol := range b.realized {
+		symbols[symbol] = struct{}{}
+	}
+	out := make([]PositionPnL, 0, len(symbols))
+	for symbol := range symbols {
+		if include != nil && !include(symbol) {
+			continue
+		}
+		position := b.positions[symbol]
+		realized := b.realized[symbol]
+		delete(b.realized, symbol)
+		if position.IsZero() {
+			delete(b.positions, symbol)
+			if realized.IsZero() {
+				continue
+			}
+		}
+		cost := b.costs[symbol]
+		mark := b.prices[symbol]
+		unrealized := decimal.Zero
+		if !position.IsZero() && mark.IsPositive() {
+			unrealized = mark.Sub(cost).Mul(position)
+		}
+		out = append(out, PositionPnL{
+			Symbol:        symbol,
+			Position:      position,
+			AvgPrice:      cost,
+			MarkPrice:     mark,
+			RealizedPnL:   realized,
+			UnrealizedPnL: unrealized,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out
+}
+
+// position returns the signed position on symbol.
+func (b *positionBook) position(symbol string) decimal.Decimal {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.positions[symbol]
+}
+
+// snapshot returns the non-flat positions sorted by symbol.
+func (b *positionBook) snapshot() []Exposure {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]Exposure, 0, len(b.positions))
+	for symbol, position := range b.positions {
+		if position.IsZero() {
+			continue
+		}
+		out = append(out, Exposure{
+			Symbol:   symbol,
+			Position: position,
+			Notional: position.Abs().Mul(b.prices[symbol]),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out
+}
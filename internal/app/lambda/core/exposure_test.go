@@ -0,0 +1,62 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestPositionBookAppliesCumulativeFills(t *testing.T) {
+	book := newPositionBook()
+	book.apply("btc-usdt", schema.ExecReportPayload{ClientOrderID: "b1", Side: schema.TradeSideBuy, State: schema.ExecReportStatePARTIAL, FilledQuantity: "0.4", AvgFillPrice: "100"})
+	book.apply("BTC-USDT", schema.ExecReportPayload{ClientOrderID: "b1", Side: schema.TradeSideBuy, State: schema.ExecReportStatePARTIAL, FilledQuantity: "0.4", AvgFillPrice: "100"})
+	book.apply("BTC-USDT", schema.ExecReportPayload{ClientOrderID: "b1", Side: schema.TradeSideBuy, State: schema.ExecReportStateFILLED, FilledQuantity: "1", AvgFillPrice: "100"})
+	book.apply("BTC-USDT", schema.ExecReportPayload{ClientOrderID: "s1", Side: schema.TradeSideSell, State: schema.ExecReportStateCANCELLED, FilledQuantity: "0.25"})
+	book.apply("ETH-USDT", schema.ExecReportPayload{ClientOrderID: "s2", Side: schema.TradeSideSell, State: schema.ExecReportStateFILLED, FilledQuantity: "2", AvgFillPrice: "10"})
+	book.apply("ETH-USDT", schema.ExecReportPayload{ClientOrderID: "b2", Side: schema.TradeSideBuy, State: schema.ExecReportStateFILLED, FilledQuantity: "2", AvgFillPrice: "10"})
+	book.observePrice("BTC-USDT", "120")
+
+	exposure := book.snapshot()
+	if len(exposure) != 1 {
+		t.Fatalf("expected flat ETH to be omitted, got %+v", exposure)
+	}
+	got := exposure[0]
+	if got.Symbol != "BTC-USDT" || got.Position.String() != "0.75" || got.Notional.String() != "90" {
+		t.Fatalf("unexpected exposure %+v", got)
+	}
+	if len(book.applied) != 0 {
+		t.Fatalf("expected terminal orders to be forgotten, got %v", book.applied)
+	}
+}
+
+func TestPositionBookSessionPnL(t *testing.T) {
+	book := newPositionBook()
+	book.apply("BTC-USDT", schema.ExecReportPayload{ClientOrderID: "b1", Side: schema.TradeSideBuy, State: schema.ExecReportStatePARTIAL, FilledQuantity: "1", AvgFillPrice: "100"})
+	book.apply("BTC-USDT", schema.ExecReportPayload{ClientOrderID: "b1", Side: schema.TradeSideBuy, State: schema.ExecReportStateFILLED, FilledQuantity: "2", AvgFillPrice: "110"})
+	book.apply("BTC-USDT", schema.ExecReportPayload{ClientOrderID: "s1", Side: schema.TradeSideSell, State: schema.ExecReportStateFILLED, FilledQuantity: "1", AvgFillPrice: "130"})
+	book.apply("ETH-USDT", schema.ExecReportPayload{ClientOrderID: "s2", Side: schema.TradeSideSell, State: schema.ExecReportStateFILLED, FilledQuantity: "1", AvgFillPrice: "10"})
+	book.apply("ETH-USDT", schema.ExecReportPayload{ClientOrderID: "b2", Side: schema.TradeSideBuy, State: schema.ExecReportStateFILLED, FilledQuantity: "1", AvgFillPrice: "8"})
+	book.observePrice("BTC-USDT", "140")
+
+	session := book.closeSession(nil)
+	if len(session) != 2 {
+		t.Fatalf("expected BTC and ETH in the session, got %+v", session)
+	}
+	// Bought 1 @ 100 and 1 @ 120 (cumulative average 110), sold 1 @ 130.
+	btc := session[0]
+	if btc.Position.String() != "1" || btc.AvgPrice.String() != "110" || btc.RealizedPnL.String() != "20" || btc.UnrealizedPnL.String() != "30" {
+		t.Fatalf("unexpected BTC session %+v", btc)
+	}
+	eth := session[1]
+	if !eth.Position.IsZero() || eth.RealizedPnL.String() != "2" {
+		t.Fatalf("unexpected ETH session %+v", eth)
+	}
+
+	next := book.closeSession(func(symbol string) bool { return symbol == "BTC-USDT" })
+	if len(next) != 1 || !next[0].RealizedPnL.IsZero() || next[0].Position.String() != "1" {
+		t.Fatalf("expected the BTC position to carry over without realised PnL, got %+v", next)
+	}
+	if after := book.closeSession(nil); len(after) != 1 {
+		t.Fatalf("expected flat ETH to be dropped after its session closed, got %+v", after)
+	}
+}
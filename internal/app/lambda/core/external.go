@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// ExternalOrder is an order entered by a system outside the strategy and attributed to the lambda.
+type ExternalOrder struct {
+	Provider string
+	// Symbol defaults to the provider's primary symbol when empty.
+	Symbol    string
+	Side      schema.TradeSide
+	OrderType schema.OrderType
+	Quantity  string
+	// Price is required for limit orders and ignored for market orders.
+	Price *string
+	// TIF defaults to GTC for limit orders and IOC for market orders.
+	TIF schema.TimeInForce
+	// PostOnly and ReduceOnly behave as on OrderOptions.
+	PostOnly   bool
+	ReduceOnly bool
+	Labels     OrderLabels
+}
+
+// ExecReport is an execution report of one of the lambda's orders.
+type ExecReport struct {
+	Provider string
+	Symbol   string
+	Payload  schema.ExecReportPayload
+}
+
+// SubmitExternalOrder places an order entered outside the strategy through the lambda's regular
+// order path, so it is risk checked, persisted, throttled and attributed like a strategy order.
+// It returns the client order ID and whether the order was sent to the provider, which it is not
+// in dry-run mode or when parked in the throttle queue (ErrOrderQueued). External orders are not
+// reported to the order intent observer.
+func (l *BaseLambda) SubmitExternalOrder(ctx context.Context, order ExternalOrder) (string, bool, error) {
+	provider := strings.TrimSpace(order.Provider)
+	symbol := strings.ToUpper(strings.TrimSpace(order.Symbol))
+	if symbol != "" && !l.scopesSymbol(provider, symbol) {
+		return "", false, fmt.Errorf("symbol %s not in scope of lambda %s on %s", symbol, l.id, provider)
+	}
+	switch order.OrderType {
+	case schema.OrderTypeLimit:
+		if order.Price == nil || strings.TrimSpace(*order.Price) == "" {
+			return "", false, fmt.Errorf("limit order price required")
+		}
+	case schema.OrderTypeMarket:
+	default:
+		return "", false, fmt.Errorf("unsupported order type %q", order.OrderType)
+	}
+	if order.Side != schema.TradeSideBuy && order.Side != schema.TradeSideSell {
+		return "", false, fmt.Errorf("unsupported order side %q", order.Side)
+	}
+	if strings.TrimSpace(order.Quantity) == "" {
+		return "", false, fmt.Errorf("order quantity required")
+	}
+	clientOrderID := l.newClientOrderID()
+	submitted, err := l.placeOrder(ctx, orderIntent{
+		clientOrderID: clientOrderID,
+		provider:      provider,
+		symbol:        symbol,
+		side:          order.Side,
+		orderType:     order.OrderType,
+		quantity:      strings.TrimSpace(order.Quantity),
+		price:         order.Price,
+		tif:           order.TIF,
+		postOnly:      order.PostOnly,
+		reduceOnly:    order.ReduceOnly,
+		labels:        order.Labels,
+		throttle:      throttleModeQueue,
+		external:      true,
+	})
+	return clientOrderID, submitted, err
+}
+
+// SetExecReportObserver registers fn to receive the execution reports of the lambda's orders,
+// strategy and external alike; nil removes the observer. fn runs on the delivery goroutine and
+// must not block.
+func (l *BaseLambda) SetExecReportObserver(fn func(ExecReport)) {
+	if fn == nil {
+		l.execObserver.Store(nil)
+		return
+	}
+	l.execObserver.Store(&fn)
+}
+
+func (l *BaseLambda) observeExecReport(evt *schema.Event, payload schema.ExecReportPayload) {
+	fn := l.execObserver.Load()
+	if fn == nil {
+		return
+	}
+	// The event is recycled after delivery, so the observer gets its own reject reason.
+	if payload.RejectReason != nil {
+		reason := *payload.RejectReason
+		payload.RejectReason = &reason
+	}
+	(*fn)(ExecReport{Provider: evt.Provider, Symbol: evt.Symbol, Payload: payload})
+}
+
+// scopesSymbol reports whether symbol is in the lambda's scope on provider. Providers without a
+// symbol list accept every symbol.
+func (l *BaseLambda) scopesSymbol(provider, symbol string) bool {
+	symbols, scoped := l.providerSymbols[provider]
+	if !scoped || len(symbols) == 0 {
+		return true
+	}
+	_, ok := symbols[symbol]
+	return ok
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+func TestSubmitExternalOrder(t *testing.T) {
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("OrderRequest", 4, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	submitter := &captureSubmitter{}
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT", "ETH-USDT"}},
+	}
+	lambda := NewBaseLambda("desk", cfg, nil, submitter, poolMgr, nil, nil, nil)
+	var intents []OrderIntent
+	lambda.SetOrderIntentObserver(func(intent OrderIntent) { intents = append(intents, intent) })
+	var reports []ExecReport
+	lambda.SetExecReportObserver(func(report ExecReport) { reports = append(reports, report) })
+
+	price := "2500"
+	id, submitted, err := lambda.SubmitExternalOrder(context.Background(), ExternalOrder{
+		Provider:  "binance",
+		Symbol:    "eth-usdt",
+		Side:      schema.TradeSideBuy,
+		OrderType: schema.OrderTypeLimit,
+		Quantity:  "2",
+		Price:     &price,
+		Labels:    OrderLabels{Metadata: map[string]string{"desk": "otc"}},
+	})
+	if err != nil || !submitted || !lambda.IsMyOrder(id) {
+		t.Fatalf("SubmitExternalOrder = %q %v %v", id, submitted, err)
+	}
+	if len(submitter.orders) != 1 {
+		t.Fatalf("expected 1 submitted order, got %d", len(submitter.orders))
+	}
+	if req := submitter.orders[0]; req.ClientOrderID != id || req.Symbol != "ETH-USDT" || req.TIF != "GTC" || req.Metadata["desk"] != "otc" {
+		t.Fatalf("unexpected order %+v", req)
+	}
+	if len(intents) != 0 {
+		t.Fatalf("external orders must not reach the intent observer, got %+v", intents)
+	}
+
+	if _, _, err := lambda.SubmitExternalOrder(context.Background(), ExternalOrder{
+		Provider: "binance", Symbol: "SOL-USDT", Side: schema.TradeSideBuy, OrderType: schema.OrderTypeMarket, Quantity: "1",
+	}); err == nil {
+		t.Fatal("expected symbol outside the scope rejected")
+	}
+	if _, _, err := lambda.SubmitExternalOrder(context.Background(), ExternalOrder{
+		Provider: "binance", Side: schema.TradeSideBuy, OrderType: schema.OrderTypeLimit, Quantity: "1",
+	}); err == nil {
+		t.Fatal("expected limit order without price rejected")
+	}
+
+	reason := "insufficient balance"
+	lambda.handleExecReport(context.Background(), &schema.Event{
+		Type:     schema.EventTypeExecReport,
+		Provider: "binance",
+		Symbol:   "ETH-USDT",
+		Payload: schema.ExecReportPayload{
+			ClientOrderID: id,
+			State:         schema.ExecReportStateREJECTED,
+			Timestamp:     time.Now(),
+			RejectReason:  &reason,
+		},
+	})
+	lambda.handleExecReport(context.Background(), &schema.Event{
+		Type:    schema.EventTypeExecReport,
+		Payload: schema.ExecReportPayload{ClientOrderID: "other-1-1", State: schema.ExecReportStateFILLED},
+	})
+	if len(reports) != 1 || reports[0].Symbol != "ETH-USDT" || reports[0].Payload.ClientOrderID != id {
+		t.Fatalf("expected one report for the lambda's order, got %+v", reports)
+	}
+	reason = "changed"
+	if *reports[0].Payload.RejectReason != "insufficient balance" {
+		t.Fatal("observer must receive its own copy of the reject reason")
+	}
+}
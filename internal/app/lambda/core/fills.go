@@ -0,0 +1,154 @@
+package core
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// fill is the quantity and price one execution report adds to its order.
+type fill struct {
+	quantity decimal.Decimal
+	price    decimal.Decimal
+}
+
+// fillUpdate is the effect of one execution report on the fills of its order.
+type fillUpdate struct {
+	// summary is the order's roll-up; it is zero until the order has a fill.
+	summary orderstore.FillSummary
+	fill    fill
+	// added reports whether the report carried a new fill.
+	added bool
+}
+
+// orderFills accumulates the executions of one order.
+type orderFills struct {
+	filled      decimal.Decimal
+	notional    decimal.Decimal
+	commissions map[string]decimal.Decimal
+	count       int
+}
+
+func (f *orderFills) summary() orderstore.FillSummary {
+	summary := orderstore.FillSummary{
+		FilledQuantity: f.filled.String(),
+		AvgFillPrice:   "",
+		Commissions:    nil,
+		Fills:          f.count,
+	}
+	if f.filled.IsPositive() {
+		summary.AvgFillPrice = f.notional.Div(f.filled).String()
+	}
+	if len(f.commissions) > 0 {
+		summary.Commissions = make(map[string]string, len(f.commissions))
+		for asset, amount := range f.commissions {
+			summary.Commissions[asset] = amount.String()
+		}
+	}
+	return summary
+}
+
+// fillTracker rolls up the executions of the lambda's open orders. Venues report the cumulative
+// filled quantity and average price of an order with every update, and the commission of the
+// latest fill, so each report is reduced to the fill it adds and the commissions are summed.
+type fillTracker struct {
+	mu     sync.Mutex
+	orders map[string]*orderFills
+}
+
+func newFillTracker() *fillTracker {
+	return &fillTracker{mu: sync.Mutex{}, orders: make(map[string]*orderFills)}
+}
+
+// apply folds an execution report into its order. Reports that add no fill, such as
+// acknowledgements and duplicates, leave the roll-up unchanged.
+func (t *fillTracker) apply(payload schema.ExecReportPayload) fillUpdate {
+	var update fillUpdate
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.orders[payload.ClientOrderID]
+	if ok {
+		update.summary = state.summary()
+	} else {
+		state = &orderFills{filled: decimal.Zero, notional: decimal.Zero, commissions: nil, count: 0}
+	}
+	cumulative, err := decimal.NewFromString(strings.TrimSpace(payload.FilledQuantity))
+	if err != nil {
+		return update
+	}
+	delta := cumulative.Sub(state.filled)
+	if !delta.IsPositive() {
+		return update
+	}
+	t.orders[payload.ClientOrderID] = state
+
+	var notional decimal.Decimal
+	avg, avgErr := decimal.NewFromString(strings.TrimSpace(payload.AvgFillPrice))
+	if avgErr == nil && avg.IsPositive() {
+		notional = avg.Mul(cumulative).Sub(state.notional)
+	}
+	if !notional.IsPositive() {
+		price, priceErr := decimal.NewFromString(strings.TrimSpace(payload.Price))
+		if priceErr == nil && price.IsPositive() {
+			notional = price.Mul(delta)
+		}
+	}
+	if notional.IsNegative() {
+		notional = decimal.Zero
+	}
+	state.filled = cumulative
+	state.notional = state.notional.Add(notional)
+	state.count++
+	if amount, err := decimal.NewFromString(strings.TrimSpace(payload.CommissionAmount)); err == nil && !amount.IsZero() {
+		if state.commissions == nil {
+			state.commissions = make(map[string]decimal.Decimal)
+		}
+		asset := strings.ToUpper(strings.TrimSpace(payload.CommissionAsset))
+		state.commissions[asset] = state.commissions[asset].Add(amount)
+	}
+	update.summary = state.summary()
+	update.fill = fill{quantity: delta, price: notional.Div(delta)}
+	update.added = true
+	return update
+}
+
+// restore resumes tracking of an open order from its persisted roll-up, so fills reported after a
+// restart are not counted twice and earlier commissions are kept.
+func (t *fillTracker) restore(clientOrderID string, summary *orderstore.FillSummary) {
+	if summary == nil || strings.TrimSpace(clientOrderID) == "" {
+		return
+	}
+	filled, err := decimal.NewFromString(summary.FilledQuantity)
+	if err != nil || !filled.IsPositive() {
+		return
+	}
+	state := &orderFills{filled: filled, notional: decimal.Zero, commissions: nil, count: summary.Fills}
+	if avg, err := decimal.NewFromString(summary.AvgFillPrice); err == nil {
+		state.notional = avg.Mul(filled)
+	}
+	for asset, raw := range summary.Commissions {
+		amount, err := decimal.NewFromString(raw)
+		if err != nil {
+			continue
+		}
+		if state.commissions == nil {
+			state.commissions = make(map[string]decimal.Decimal, len(summary.Commissions))
+		}
+		state.commissions[asset] = amount
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.orders[clientOrderID]; !ok {
+		t.orders[clientOrderID] = state
+	}
+}
+
+func (t *fillTracker) forget(clientOrderID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.orders, clientOrderID)
+}
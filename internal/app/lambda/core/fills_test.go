@@ -0,0 +1,121 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+func TestFillTrackerRollsUpCumulativeReports(t *testing.T) {
+	tracker := newFillTracker()
+	if update := tracker.apply(schema.ExecReportPayload{ClientOrderID: "o1", State: schema.ExecReportStateACK, FilledQuantity: "0"}); update.added {
+		t.Fatalf("expected no fill on acknowledgement, got %+v", update)
+	}
+	first := tracker.apply(schema.ExecReportPayload{
+		ClientOrderID: "o1", State: schema.ExecReportStatePARTIAL, FilledQuantity: "0.4", AvgFillPrice: "100",
+		CommissionAmount: "0.0004", CommissionAsset: "bnb",
+	})
+	if !first.added || first.fill.quantity.String() != "0.4" || first.fill.price.String() != "100" {
+		t.Fatalf("unexpected first fill %+v", first)
+	}
+	second := tracker.apply(schema.ExecReportPayload{
+		ClientOrderID: "o1", State: schema.ExecReportStateFILLED, FilledQuantity: "1", AvgFillPrice: "101.2",
+		CommissionAmount: "0.0006", CommissionAsset: "BNB",
+	})
+	if !second.added || second.fill.quantity.String() != "0.6" || second.fill.price.String() != "102" {
+		t.Fatalf("expected the second fill of 0.6 at 102, got %+v", second.fill)
+	}
+	want := orderstore.FillSummary{FilledQuantity: "1", AvgFillPrice: "101.2", Commissions: map[string]string{"BNB": "0.001"}, Fills: 2}
+	if got := second.summary; got.FilledQuantity != want.FilledQuantity || got.AvgFillPrice != want.AvgFillPrice || got.Commissions["BNB"] != "0.001" || got.Fills != 2 {
+		t.Fatalf("summary = %+v, want %+v", got, want)
+	}
+	duplicate := tracker.apply(schema.ExecReportPayload{ClientOrderID: "o1", State: schema.ExecReportStateFILLED, FilledQuantity: "1", AvgFillPrice: "101.2", CommissionAmount: "0.0006"})
+	if duplicate.added || duplicate.summary.Commissions["BNB"] != "0.001" {
+		t.Fatalf("expected a duplicate report to change nothing, got %+v", duplicate)
+	}
+
+	// Without an average price the fill is valued at the order price.
+	priced := tracker.apply(schema.ExecReportPayload{ClientOrderID: "o2", State: schema.ExecReportStatePARTIAL, FilledQuantity: "2", Price: "50"})
+	if priced.summary.AvgFillPrice != "50" {
+		t.Fatalf("expected the order price as average, got %+v", priced.summary)
+	}
+
+	restored := newFillTracker()
+	restored.restore("o3", &orderstore.FillSummary{FilledQuantity: "1", AvgFillPrice: "10", Commissions: map[string]string{"USDT": "0.01"}, Fills: 1})
+	resumed := restored.apply(schema.ExecReportPayload{ClientOrderID: "o3", State: schema.ExecReportStateFILLED, FilledQuantity: "2", AvgFillPrice: "11", CommissionAmount: "0.01", CommissionAsset: "USDT"})
+	if resumed.fill.quantity.String() != "1" || resumed.fill.price.String() != "12" || resumed.summary.Commissions["USDT"] != "0.02" || resumed.summary.Fills != 2 {
+		t.Fatalf("expected the restored roll-up to continue, got %+v", resumed)
+	}
+}
+
+func TestExecReportsPersistFillRollUps(t *testing.T) {
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("OrderRequest", 4, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	store := &recordingOrderStore{}
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+	}
+	lambda := NewBaseLambda("lambda-fills", cfg, nil, &captureSubmitter{}, poolMgr, nil, nil, store)
+	price := "100"
+	if err := lambda.SubmitOrder(context.Background(), "binance", schema.TradeSideBuy, "1", &price); err != nil {
+		t.Fatalf("SubmitOrder: %v", err)
+	}
+	id := store.orders[0].ClientOrderID
+	report := func(state schema.ExecReportState, filled, avg, commission string) {
+		lambda.handleExecReport(context.Background(), &schema.Event{
+			EventID:  "exec-" + filled,
+			Type:     schema.EventTypeExecReport,
+			Provider: "binance",
+			Symbol:   "BTC-USDT",
+			Payload: schema.ExecReportPayload{
+				ClientOrderID:    id,
+				State:            state,
+				FilledQuantity:   filled,
+				AvgFillPrice:     avg,
+				CommissionAmount: commission,
+				CommissionAsset:  "USDT",
+				Timestamp:        time.Now(),
+			},
+		})
+	}
+	report(schema.ExecReportStatePARTIAL, "0.25", "96", "0.024")
+	if view, ok := lambda.Order(id); !ok || view.FilledQuantity != "0.25" || view.Fills != 1 || view.Commissions["USDT"] != "0.024" {
+		t.Fatalf("unexpected order view %+v", view)
+	}
+	report(schema.ExecReportStateFILLED, "1", "99", "0.075")
+
+	last := store.updates[len(store.updates)-1].Metadata[orderstore.FillsMetadataKey]
+	summary := orderstore.FillSummaryFromMetadata(map[string]any{orderstore.FillsMetadataKey: last})
+	if summary == nil || summary.FilledQuantity != "1" || summary.AvgFillPrice != "99" || summary.Commissions["USDT"] != "0.099" || summary.Fills != 2 {
+		t.Fatalf("unexpected persisted roll-up %+v", summary)
+	}
+	if len(store.executions) != 2 || store.executions[1].Metadata["fillQuantity"] != "0.75" || store.executions[1].Metadata["fillPrice"] != "100" {
+		t.Fatalf("expected the second execution to carry its own fill, got %+v", store.executions)
+	}
+	if _, tracked := lambda.fills.orders[id]; tracked {
+		t.Fatal("expected the filled order to leave the tracker")
+	}
+	if view, _ := lambda.Order(id); view.AvgFillPrice != "99" || view.Fills != 2 {
+		t.Fatalf("expected the roll-up on the cached order, got %+v", view)
+	}
+}
+
+func TestFillSummaryFromDecodedMetadata(t *testing.T) {
+	decoded := map[string]any{orderstore.FillsMetadataKey: map[string]any{
+		"filledQuantity": "2", "avgFillPrice": "5", "fills": float64(3), "commissions": map[string]any{"BNB": "0.1"},
+	}}
+	summary := orderstore.FillSummaryFromMetadata(decoded)
+	if summary == nil || summary.Fills != 3 || summary.Commissions["BNB"] != "0.1" || summary.AvgFillPrice != "5" {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if orderstore.FillSummaryFromMetadata(nil) != nil {
+		t.Fatal("expected nil without fills")
+	}
+}
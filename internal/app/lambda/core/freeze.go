@@ -0,0 +1,243 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// DefaultFreezeBuffer bounds the events a frozen lambda holds when no bound is set.
+const DefaultFreezeBuffer = 4096
+
+// ErrInstanceFrozen is returned by the order helpers while the lambda is frozen for maintenance.
+var ErrInstanceFrozen = errors.New("instance frozen for maintenance")
+
+// StateSnapshotter is implemented by strategies that can hand their working state to a freeze, so
+// a gateway restarted mid-upgrade restores it instead of cold-starting the strategy. The state
+// must survive a JSON round trip.
+type StateSnapshotter interface {
+	SnapshotState() (map[string]any, error)
+	RestoreState(state map[string]any) error
+}
+
+// FreezeStatus describes a lambda frozen for maintenance. Buffered counts the events held for
+// replay; Dropped counts the events discarded because the buffer was full.
+type FreezeStatus struct {
+	Frozen   bool      `json:"frozen"`
+	Since    time.Time `json:"since,omitempty"`
+	Buffered int       `json:"buffered"`
+	Dropped  int64     `json:"dropped"`
+}
+
+type frozenEvent struct {
+	typ schema.EventType
+	evt *schema.Event
+}
+
+// freezeBuffer holds the events a frozen lambda receives until it is unfrozen. Every event counts
+// toward the bound. When full, the oldest market data event is dropped first, then the oldest
+// event other than an execution report or balance, and only then the oldest event of any kind.
+// While an unfreeze drains the buffer, new events keep queueing behind the replayed ones.
+type freezeBuffer struct {
+	limit int
+
+	mu       sync.Mutex
+	frozen   bool
+	draining bool
+	stopped  bool
+	since    time.Time
+	events   []frozenEvent
+	dropped  int64
+	// drains tracks an unfreeze replaying outside mu, so a stopping lambda waits for it before
+	// closing the scheduler.
+	drains sync.WaitGroup
+}
+
+func newFreezeBuffer(limit int) *freezeBuffer {
+	if limit <= 0 {
+		limit = DefaultFreezeBuffer
+	}
+	return &freezeBuffer{
+		limit:    limit,
+		mu:       sync.Mutex{},
+		frozen:   false,
+		draining: false,
+		stopped:  false,
+		since:    time.Time{},
+		events:   nil,
+		dropped:  0,
+		drains:   sync.WaitGroup{},
+	}
+}
+
+// freezeDropRank orders event types by how readily a full buffer drops them: market data first,
+// execution reports and balances last so order state stays complete after the replay.
+func freezeDropRank(typ schema.EventType) int {
+	switch typ {
+	case schema.EventTypeTrade, schema.EventTypeTicker, schema.EventTypeBookSnapshot, schema.EventTypeBookDelta, schema.EventTypeKlineSummary:
+		return 0
+	case schema.EventTypeExecReport, schema.EventTypeBalanceUpdate:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// holdLocked buffers an event and returns the event it displaced, if any.
+func (b *freezeBuffer) holdLocked(typ schema.EventType, evt *schema.Event) (frozenEvent, bool) {
+	b.events = append(b.events, frozenEvent{typ: typ, evt: evt})
+	if len(b.events) <= b.limit {
+		var none frozenEvent
+		return none, false
+	}
+	victim := 0
+	for rank := 0; rank <= 2; rank++ {
+		if i := slices.IndexFunc(b.events, func(held frozenEvent) bool { return freezeDropRank(held.typ) == rank }); i >= 0 {
+			victim = i
+			break
+		}
+	}
+	displaced := b.events[victim]
+	b.events = slices.Delete(b.events, victim, victim+1)
+	b.dropped++
+	return displaced, true
+}
+
+// takeLocked empties the buffer and returns the held events in arrival order.
+func (b *freezeBuffer) takeLocked() []frozenEvent {
+	events := b.events
+	b.events = nil
+	return events
+}
+
+// Freeze stops the lambda from handling new events and placing orders. Incoming events are held,
+// within the freeze buffer bound, until Unfreeze replays them. Open orders are left untouched.
+func (l *BaseLambda) Freeze() {
+	l.freeze.mu.Lock()
+	defer l.freeze.mu.Unlock()
+	if l.freeze.frozen {
+		return
+	}
+	l.freeze.frozen = true
+	l.freeze.since = time.Now()
+	if len(l.freeze.events) == 0 {
+		l.freeze.dropped = 0
+	}
+	l.logger.Printf("[%s] frozen for maintenance", l.id)
+}
+
+// Unfreeze resumes trading and replays the held events in arrival order. Events arriving during
+// the replay are queued behind it, and the scheduler is fed outside the freeze lock so a full
+// delivery lane cannot stall event intake. It returns the number of events replayed.
+func (l *BaseLambda) Unfreeze(ctx context.Context) int {
+	l.freeze.mu.Lock()
+	if !l.freeze.frozen || l.freeze.draining || l.freeze.stopped {
+		// A replay already under way picks the lifted freeze up on its next batch.
+		l.freeze.frozen = false
+		l.freeze.since = time.Time{}
+		l.freeze.mu.Unlock()
+		return 0
+	}
+	l.freeze.frozen = false
+	l.freeze.since = time.Time{}
+	l.freeze.draining = true
+	l.freeze.drains.Add(1)
+	l.freeze.mu.Unlock()
+	defer l.freeze.drains.Done()
+
+	scheduler := l.scheduler.Load()
+	replayed := 0
+	for {
+		l.freeze.mu.Lock()
+		// A new freeze or a stopping lambda leaves the rest buffered.
+		if l.freeze.frozen || l.freeze.stopped || len(l.freeze.events) == 0 {
+			l.freeze.draining = false
+			dropped := l.freeze.dropped
+			l.freeze.mu.Unlock()
+			l.logger.Printf("[%s] unfrozen, replayed %d events (%d dropped)", l.id, replayed, dropped)
+			return replayed
+		}
+		events := l.freeze.takeLocked()
+		if scheduler != nil {
+			scheduler.admit(len(events))
+		}
+		l.freeze.mu.Unlock()
+		for _, held := range events {
+			if scheduler == nil {
+				l.recycleEvent(held.evt)
+				continue
+			}
+			if scheduler.enqueueAdmitted(ctx, held.typ, held.evt) {
+				replayed++
+			}
+		}
+	}
+}
+
+// IsFrozen reports whether the lambda is frozen for maintenance.
+func (l *BaseLambda) IsFrozen() bool {
+	l.freeze.mu.Lock()
+	defer l.freeze.mu.Unlock()
+	return l.freeze.frozen
+}
+
+// FreezeStatus reports whether the lambda is frozen and how many events it holds.
+func (l *BaseLambda) FreezeStatus() FreezeStatus {
+	l.freeze.mu.Lock()
+	defer l.freeze.mu.Unlock()
+	return FreezeStatus{
+		Frozen:   l.freeze.frozen,
+		Since:    l.freeze.since,
+		Buffered: len(l.freeze.events),
+		Dropped:  l.freeze.dropped,
+	}
+}
+
+// deliver hands an event to the scheduler, or holds it while the lambda is frozen or replaying.
+func (l *BaseLambda) deliver(ctx context.Context, scheduler *deliveryScheduler, typ schema.EventType, evt *schema.Event) {
+	l.freeze.mu.Lock()
+	if l.freeze.frozen || l.freeze.draining {
+		displaced, ok := l.freeze.holdLocked(typ, evt)
+		l.freeze.mu.Unlock()
+		if ok {
+			if freezeDropRank(displaced.typ) == 2 {
+				l.logger.Printf("[%s] freeze buffer full, dropped %s event %s", l.id, displaced.typ, displaced.evt.EventID)
+			}
+			l.recycleEvent(displaced.evt)
+		}
+		return
+	}
+	scheduler.admit(1)
+	l.freeze.mu.Unlock()
+	scheduler.enqueueAdmitted(ctx, typ, evt)
+}
+
+// WaitIdle blocks until the events handed to the strategy before a freeze have been handled, or
+// ctx ends, and reports whether the lambda went idle. Call it after Freeze so the strategy state
+// captured for the snapshot reflects every event that left the freeze buffer's reach.
+func (l *BaseLambda) WaitIdle(ctx context.Context) bool {
+	scheduler := l.scheduler.Load()
+	if scheduler == nil {
+		return true
+	}
+	return scheduler.waitIdle(ctx)
+}
+
+// discardFrozen returns the events still held when the lambda stops to their pools, after any
+// replay in progress has handed over its batch.
+func (l *BaseLambda) discardFrozen() {
+	l.freeze.mu.Lock()
+	l.freeze.stopped = true
+	l.freeze.mu.Unlock()
+	l.freeze.drains.Wait()
+	l.freeze.mu.Lock()
+	events := l.freeze.takeLocked()
+	l.freeze.mu.Unlock()
+	for _, held := range events {
+		l.recycleEvent(held.evt)
+	}
+}
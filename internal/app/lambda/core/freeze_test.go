@@ -0,0 +1,163 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestFrozenLambdaBuffersAndReplaysEvents(t *testing.T) {
+	lambda := NewBaseLambda("lambda-freeze", Config{Providers: []string{"binance"}, FreezeBuffer: 3}, nil, nil, nil, &testExtensionStrategy{}, nil, nil)
+	lambda.SetLogger(log.New(io.Discard, "", 0))
+	var (
+		mu      sync.Mutex
+		handled []string
+	)
+	scheduler := newDeliveryScheduler(lambda.id, DeliveryConfig{}, func(_ context.Context, _ schema.EventType, evt *schema.Event) {
+		mu.Lock()
+		handled = append(handled, evt.EventID)
+		mu.Unlock()
+	}, func(*schema.Event) {})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler.start(ctx)
+	lambda.scheduler.Store(scheduler)
+
+	lambda.Freeze()
+	deliver := func(typ schema.EventType, id string) {
+		lambda.deliver(ctx, scheduler, typ, &schema.Event{EventID: id, Type: typ, Provider: "binance", Symbol: "BTC-USDT"})
+	}
+	deliver(schema.EventTypeExecReport, "exec-1")
+	deliver(schema.EventTypeTrade, "trade-1")
+	deliver(schema.EventTypeBookDelta, "delta-2")
+	deliver(schema.ExtensionEventType, "ext-2")
+	deliver(schema.EventTypeTrade, "trade-3")
+
+	// Every event counts toward the bound, and market data is dropped first.
+	status := lambda.FreezeStatus()
+	if !status.Frozen || status.Buffered != 3 || status.Dropped != 2 {
+		t.Fatalf("expected three held events and two market data events dropped, got %+v", status)
+	}
+	if err := lambda.SubmitOrder(ctx, "binance", schema.TradeSideBuy, "1", nil); !errors.Is(err, ErrInstanceFrozen) {
+		t.Fatalf("expected ErrInstanceFrozen, got %v", err)
+	}
+	mu.Lock()
+	if len(handled) != 0 {
+		t.Fatalf("expected nothing handled while frozen, got %v", handled)
+	}
+	mu.Unlock()
+
+	if replayed := lambda.Unfreeze(ctx); replayed != 3 {
+		t.Fatalf("expected three replayed events, got %d", replayed)
+	}
+	deliver(schema.EventTypeTrade, "trade-4")
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		count := len(handled)
+		mu.Unlock()
+		if count == 4 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"exec-1", "ext-2", "trade-3", "trade-4"}
+	if len(handled) != len(want) {
+		t.Fatalf("handled %v, want %v", handled, want)
+	}
+	for i := range want {
+		if handled[i] != want[i] {
+			t.Fatalf("handled %v, want %v", handled, want)
+		}
+	}
+	if lambda.IsFrozen() {
+		t.Fatal("expected the lambda to be unfrozen")
+	}
+}
+
+func TestUnfreezeReplaysOutsideTheFreezeLock(t *testing.T) {
+	lambda := NewBaseLambda("lambda-drain", Config{Providers: []string{"binance"}}, nil, nil, nil, &testExtensionStrategy{}, nil, nil)
+	lambda.SetLogger(log.New(io.Discard, "", 0))
+	release := make(chan struct{})
+	var (
+		mu      sync.Mutex
+		handled []string
+	)
+	// One in-flight event and a blocked handler make the replay wait on the delivery lane.
+	scheduler := newDeliveryScheduler(lambda.id, DeliveryConfig{MaxInFlight: 1}, func(_ context.Context, _ schema.EventType, evt *schema.Event) {
+		<-release
+		mu.Lock()
+		handled = append(handled, evt.EventID)
+		mu.Unlock()
+	}, func(*schema.Event) {})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler.start(ctx)
+	lambda.scheduler.Store(scheduler)
+	deliver := func(id string) {
+		lambda.deliver(ctx, scheduler, schema.EventTypeTrade, &schema.Event{EventID: id, Type: schema.EventTypeTrade, Provider: "binance", Symbol: "BTC-USDT"})
+	}
+
+	lambda.Freeze()
+	for _, id := range []string{"held-1", "held-2", "held-3", "held-4"} {
+		deliver(id)
+	}
+	replayed := make(chan int, 1)
+	go func() { replayed <- lambda.Unfreeze(ctx) }()
+
+	// Trading resumes once the replay starts. Intake and status stay responsive while the replay
+	// is blocked, and new events queue behind the replayed ones.
+	done := make(chan struct{})
+	go func() {
+		for lambda.IsFrozen() {
+			time.Sleep(time.Millisecond)
+		}
+		deliver("live-5")
+		_ = lambda.FreezeStatus()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("event intake blocked behind the replay")
+	}
+
+	close(release)
+	select {
+	case count := <-replayed:
+		if count != 5 {
+			t.Fatalf("expected five replayed events, got %d", count)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("unfreeze did not finish")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		count := len(handled)
+		mu.Unlock()
+		if count == 5 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"held-1", "held-2", "held-3", "held-4", "live-5"}
+	if len(handled) != len(want) {
+		t.Fatalf("handled %v, want %v", handled, want)
+	}
+	for i := range want {
+		if handled[i] != want[i] {
+			t.Fatalf("handled %v, want %v", handled, want)
+		}
+	}
+}
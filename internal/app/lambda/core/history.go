@@ -0,0 +1,192 @@
+package core
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+const (
+	// DefaultHistoryTrades is the number of recent trades retained per instrument by default.
+	DefaultHistoryTrades = 500
+	// DefaultHistoryKlines is the number of recent klines retained per instrument by default.
+	DefaultHistoryKlines = 200
+)
+
+// HistoryConfig sets how much recent market data the host retains per subscribed instrument on
+// behalf of the strategy. Zero selects the default and a negative value disables the window.
+type HistoryConfig struct {
+	Trades int
+	Klines int
+}
+
+func (c HistoryConfig) normalize() HistoryConfig {
+	if c.Trades == 0 {
+		c.Trades = DefaultHistoryTrades
+	}
+	if c.Klines == 0 {
+		c.Klines = DefaultHistoryKlines
+	}
+	return c
+}
+
+// MarketHistory is the rolling window of recent trades and klines of one instrument, oldest first.
+type MarketHistory struct {
+	Provider string                       `json:"provider"`
+	Symbol   string                       `json:"symbol"`
+	Trades   []schema.TradePayload        `json:"trades"`
+	Klines   []schema.KlineSummaryPayload `json:"klines"`
+}
+
+// ring is a fixed-capacity buffer that overwrites its oldest element when full.
+type ring[T any] struct {
+	buf   []T
+	start int
+	size  int
+}
+
+func newRing[T any](capacity int) *ring[T] {
+	if capacity <= 0 {
+		return nil
+	}
+	return &ring[T]{buf: make([]T, capacity), start: 0, size: 0}
+}
+
+func (r *ring[T]) push(value T) {
+	if r == nil {
+		return
+	}
+	if r.size < len(r.buf) {
+		r.buf[(r.start+r.size)%len(r.buf)] = value
+		r.size++
+		return
+	}
+	r.buf[r.start] = value
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+// last returns a pointer to the newest element so in-progress values can be replaced.
+func (r *ring[T]) last() *T {
+	if r == nil || r.size == 0 {
+		return nil
+	}
+	return &r.buf[(r.start+r.size-1)%len(r.buf)]
+}
+
+func (r *ring[T]) slice() []T {
+	if r == nil {
+		return []T{}
+	}
+	out := make([]T, r.size)
+	for i := range out {
+		out[i] = r.buf[(r.start+i)%len(r.buf)]
+	}
+	return out
+}
+
+type historyWindow struct {
+	trades *ring[schema.TradePayload]
+	klines *ring[schema.KlineSummaryPayload]
+}
+
+// marketHistory keeps the rolling windows of every instrument the lambda receives data for, so
+// strategies query recent market state from the host instead of growing arrays in their VM.
+type marketHistory struct {
+	mu      sync.Mutex
+	cfg     HistoryConfig
+	windows map[string]*historyWindow
+}
+
+func newMarketHistory(cfg HistoryConfig) *marketHistory {
+	return &marketHistory{
+		mu:      sync.Mutex{},
+		cfg:     cfg.normalize(),
+		windows: make(map[string]*historyWindow),
+	}
+}
+
+func (h *marketHistory) windowLocked(provider, symbol string) *historyWindow {
+	key := tapeKey(provider, symbol)
+	window, ok := h.windows[key]
+	if !ok {
+		window = &historyWindow{
+			trades: newRing[schema.TradePayload](h.cfg.Trades),
+			klines: newRing[schema.KlineSummaryPayload](h.cfg.Klines),
+		}
+		h.windows[key] = window
+	}
+	return window
+}
+
+func (h *marketHistory) observeTrade(provider, symbol string, payload schema.TradePayload) {
+	if h.cfg.Trades < 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.windowLocked(provider, symbol).trades.push(payload)
+}
+
+// observeKline appends a kline, replacing the newest one when it is an update of the same bar.
+func (h *marketHistory) observeKline(provider, symbol string, payload schema.KlineSummaryPayload) {
+	if h.cfg.Klines < 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	klines := h.windowLocked(provider, symbol).klines
+	if last := klines.last(); last != nil && !payload.OpenTime.IsZero() && last.OpenTime.Equal(payload.OpenTime) {
+		*last = payload
+		return
+	}
+	klines.push(payload)
+}
+
+func (h *marketHistory) get(provider, symbol string) (MarketHistory, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	window, ok := h.windows[tapeKey(provider, symbol)]
+	if !ok {
+		return MarketHistory{}, false
+	}
+	return MarketHistory{
+		Provider: provider,
+		Symbol:   strings.ToUpper(strings.TrimSpace(symbol)),
+		Trades:   window.trades.slice(),
+		Klines:   window.klines.slice(),
+	}, true
+}
+
+// MarketHistory returns the recent trades and klines of symbol. When provider is empty the first
+// configured provider routing the symbol is used.
+func (l *BaseLambda) MarketHistory(symbol, provider string) (MarketHistory, bool) {
+	symbol, provider = l.resolveInstrument(symbol, provider)
+	return l.history.get(provider, symbol)
+}
+
+// resolveInstrument fills in the default symbol of provider or the first provider routing symbol.
+func (l *BaseLambda) resolveInstrument(symbol, provider string) (string, string) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	provider = strings.TrimSpace(provider)
+	if symbol == "" {
+		symbol = l.symbolForProvider(provider)
+	}
+	if provider == "" {
+		provider = l.providerForSymbol(symbol)
+	}
+	return symbol, provider
+}
+
+func (l *BaseLambda) providerForSymbol(symbol string) string {
+	for _, provider := range l.config.Providers {
+		set, ok := l.providerSymbols[provider]
+		if !ok {
+			return provider
+		}
+		if _, ok := set[symbol]; ok {
+			return provider
+		}
+	}
+	return ""
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestMarketHistoryRetainsRollingWindows(t *testing.T) {
+	history := newMarketHistory(HistoryConfig{Trades: 3, Klines: 2})
+	for _, price := range []string{"1", "2", "3", "4", "5"} {
+		history.observeTrade("binance", "btc-usdt", schema.TradePayload{Price: price})
+	}
+	open := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	history.observeKline("binance", "BTC-USDT", schema.KlineSummaryPayload{OpenTime: open, ClosePrice: "10"})
+	history.observeKline("binance", "BTC-USDT", schema.KlineSummaryPayload{OpenTime: open, ClosePrice: "11"})
+	history.observeKline("binance", "BTC-USDT", schema.KlineSummaryPayload{OpenTime: open.Add(time.Minute), ClosePrice: "12"})
+	history.observeKline("binance", "BTC-USDT", schema.KlineSummaryPayload{OpenTime: open.Add(2 * time.Minute), ClosePrice: "13"})
+
+	window, ok := history.get("binance", "BTC-USDT")
+	if !ok {
+		t.Fatal("expected history for BTC-USDT")
+	}
+	if len(window.Trades) != 3 || window.Trades[0].Price != "3" || window.Trades[2].Price != "5" {
+		t.Fatalf("unexpected trade window %+v", window.Trades)
+	}
+	if len(window.Klines) != 2 || window.Klines[0].ClosePrice != "12" || window.Klines[1].ClosePrice != "13" {
+		t.Fatalf("unexpected kline window %+v", window.Klines)
+	}
+	if _, ok := history.get("okx", "BTC-USDT"); ok {
+		t.Fatal("expected no history for an unobserved provider")
+	}
+}
+
+func TestMarketHistoryUpdatesInProgressKline(t *testing.T) {
+	history := newMarketHistory(HistoryConfig{})
+	open := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	history.observeKline("binance", "BTC-USDT", schema.KlineSummaryPayload{OpenTime: open, ClosePrice: "10"})
+	history.observeKline("binance", "BTC-USDT", schema.KlineSummaryPayload{OpenTime: open, ClosePrice: "11"})
+	window, _ := history.get("binance", "BTC-USDT")
+	if len(window.Klines) != 1 || window.Klines[0].ClosePrice != "11" {
+		t.Fatalf("expected the in-progress bar to be replaced, got %+v", window.Klines)
+	}
+}
+
+func TestMarketHistoryDisabledWindow(t *testing.T) {
+	history := newMarketHistory(HistoryConfig{Trades: -1, Klines: 5})
+	history.observeTrade("binance", "BTC-USDT", schema.TradePayload{Price: "1"})
+	if _, ok := history.get("binance", "BTC-USDT"); ok {
+		t.Fatal("expected disabled trade window to record nothing")
+	}
+}
+
+func TestBaseLambdaMarketHistoryResolvesProvider(t *testing.T) {
+	cfg := Config{
+		Providers:       []string{"binance", "okx"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}, "okx": {"ETH-USDT"}},
+	}
+	lambda := NewBaseLambda("lambda-history", cfg, nil, nil, nil, nil, nil, nil)
+	lambda.handleTrade(context.Background(), &schema.Event{
+		Type:     schema.EventTypeTrade,
+		Provider: "okx",
+		Symbol:   "ETH-USDT",
+		Payload:  schema.TradePayload{Price: "2000", Quantity: "1"},
+	})
+	lambda.handleKlineSummary(context.Background(), &schema.Event{
+		Type:     schema.EventTypeKlineSummary,
+		Provider: "okx",
+		Symbol:   "ETH-USDT",
+		Payload:  schema.KlineSummaryPayload{ClosePrice: "2001"},
+	})
+	window, ok := lambda.MarketHistory("eth-usdt", "")
+	if !ok || window.Provider != "okx" || len(window.Trades) != 1 || len(window.Klines) != 1 {
+		t.Fatalf("unexpected history %+v (ok=%v)", window, ok)
+	}
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// OrderIntent is an order a strategy asked to place, observed before dry-run, warm-up, risk and
+// throttle handling decide whether it is submitted.
+type OrderIntent struct {
+	At       time.Time
+	Provider string
+	Symbol   string
+	Side     schema.TradeSide
+	Type     schema.OrderType
+	Quantity string
+	// Price is empty for market orders.
+	Price string
+	// DryRun reports that the lambda skipped submitting the order.
+	DryRun bool
+}
+
+// SetOrderIntentObserver registers fn to receive every order the strategy places; nil removes the
+// observer. fn runs on the strategy's goroutine and must not block.
+func (l *BaseLambda) SetOrderIntentObserver(fn func(OrderIntent)) {
+	if fn == nil {
+		l.intentObserver.Store(nil)
+		return
+	}
+	l.intentObserver.Store(&fn)
+}
+
+func (l *BaseLambda) observeIntent(provider string, intent orderIntent) {
+	fn := l.intentObserver.Load()
+	if fn == nil || intent.external {
+		return
+	}
+	symbol := strings.TrimSpace(intent.symbol)
+	if symbol == "" {
+		symbol = l.symbolForProvider(provider)
+	}
+	var price string
+	if intent.price != nil && intent.orderType != schema.OrderTypeMarket {
+		price = *intent.price
+	}
+	(*fn)(OrderIntent{
+		At:       time.Now().UTC(),
+		Provider: provider,
+		Symbol:   symbol,
+		Side:     intent.side,
+		Type:     intent.orderType,
+		Quantity: intent.quantity,
+		Price:    price,
+		DryRun:   l.IsDryRun(),
+	})
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestOrderIntentObserverSeesDryRunOrders(t *testing.T) {
+	lambda := NewBaseLambda("intents", Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+		DryRun:          true,
+	}, nil, nil, nil, nil, nil, nil)
+	var seen []OrderIntent
+	lambda.SetOrderIntentObserver(func(intent OrderIntent) { seen = append(seen, intent) })
+
+	price := "100.5"
+	if err := lambda.SubmitOrder(context.Background(), "binance", schema.TradeSideBuy, "0.1", &price); err != nil {
+		t.Fatalf("SubmitOrder: %v", err)
+	}
+	if err := lambda.SubmitMarketOrder(context.Background(), "binance", schema.TradeSideSell, "0.2"); err != nil {
+		t.Fatalf("SubmitMarketOrder: %v", err)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("expected two intents, got %+v", seen)
+	}
+	if got := seen[0]; got.Symbol != "BTC-USDT" || got.Price != "100.5" || got.Type != schema.OrderTypeLimit || !got.DryRun || got.At.IsZero() {
+		t.Fatalf("unexpected limit intent %+v", got)
+	}
+	if got := seen[1]; got.Price != "" || got.Type != schema.OrderTypeMarket || got.Quantity != "0.2" {
+		t.Fatalf("unexpected market intent %+v", got)
+	}
+
+	lambda.SetOrderIntentObserver(nil)
+	_ = lambda.SubmitMarketOrder(context.Background(), "binance", schema.TradeSideSell, "0.2")
+	if len(seen) != 2 {
+		t.Fatal("expected removed observer to stop receiving intents")
+	}
+}
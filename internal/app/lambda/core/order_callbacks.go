@@ -0,0 +1,299 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+const (
+	defaultOrderCallbackRetries = 2
+	defaultOrderCallbackBackoff = 100 * time.Millisecond
+	maxOrderCallbackAlerts      = 32
+)
+
+// criticalExecReportStates are the execution report states tracked when no states are configured:
+// losing one of these callbacks leaves a strategy unaware of a position change or a failed order.
+var criticalExecReportStates = []schema.ExecReportState{
+	schema.ExecReportStateFILLED,
+	schema.ExecReportStatePARTIAL,
+	schema.ExecReportStateREJECTED,
+}
+
+// OrderCallbackConfig makes the host track whether the strategy handled its execution report
+// callbacks. A callback that throws is retried; one that throws on every attempt, or runs past
+// Deadline, is escalated with an OrderCallbackAlert extension event.
+type OrderCallbackConfig struct {
+	// Deadline is how long a callback may run before it is escalated as stalled; zero disables
+	// tracking.
+	Deadline time.Duration
+	// Retries is how many times a callback that threw is called again; negative disables retries.
+	Retries int
+	// Backoff is the pause between attempts.
+	Backoff time.Duration
+	// States lists the tracked report states; empty tracks fills, partial fills and rejections.
+	States []schema.ExecReportState
+}
+
+func (c OrderCallbackConfig) normalize() OrderCallbackConfig {
+	if c.Retries == 0 {
+		c.Retries = defaultOrderCallbackRetries
+	}
+	if c.Retries < 0 {
+		c.Retries = 0
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = defaultOrderCallbackBackoff
+	}
+	if len(c.States) == 0 {
+		c.States = append([]schema.ExecReportState(nil), criticalExecReportStates...)
+	}
+	return c
+}
+
+// CheckedOrderCallbacks is implemented by strategies whose execution report callbacks report
+// failure, so the host can tell a handled report from one whose handler threw.
+type CheckedOrderCallbacks interface {
+	HandleOrderEvent(ctx context.Context, evt *schema.Event, payload schema.ExecReportPayload) error
+}
+
+// OrderCallbackReason tells why an order callback was escalated.
+type OrderCallbackReason string
+
+const (
+	// OrderCallbackStalled means the callback was still running at the deadline.
+	OrderCallbackStalled OrderCallbackReason = "STALLED"
+	// OrderCallbackFailed means the callback threw on every attempt and the report was not handled.
+	OrderCallbackFailed OrderCallbackReason = "FAILED"
+)
+
+// OrderCallbackAlert is published as an extension event when an execution report callback is not
+// acknowledged in time, so sinks can notify operators.
+type OrderCallbackAlert struct {
+	LambdaID      string                 `json:"lambdaId"`
+	Provider      string                 `json:"provider"`
+	Symbol        string                 `json:"symbol"`
+	ClientOrderID string                 `json:"clientOrderId"`
+	State         schema.ExecReportState `json:"state"`
+	EventID       string                 `json:"eventId"`
+	Reason        OrderCallbackReason    `json:"reason"`
+	Attempts      int                    `json:"attempts"`
+	Error         string                 `json:"error,omitempty"`
+	Deadline      string                 `json:"deadline"`
+	Timestamp     time.Time              `json:"timestamp"`
+}
+
+// OrderCallbackStats counts the tracked execution report callbacks of a lambda. Alerts holds the
+// most recent escalations, newest last.
+type OrderCallbackStats struct {
+	Enabled  bool                 `json:"enabled"`
+	Deadline string               `json:"deadline,omitempty"`
+	Tracked  int64                `json:"tracked"`
+	Acked    int64                `json:"acked"`
+	Retried  int64                `json:"retried"`
+	Stalled  int64                `json:"stalled"`
+	Failed   int64                `json:"failed"`
+	Alerts   []OrderCallbackAlert `json:"alerts"`
+}
+
+type orderCallbackTracker struct {
+	cfg    OrderCallbackConfig
+	states map[schema.ExecReportState]struct{}
+
+	mu    sync.Mutex
+	stats OrderCallbackStats
+}
+
+func newOrderCallbackTracker(cfg OrderCallbackConfig) *orderCallbackTracker {
+	if cfg.Deadline <= 0 {
+		return nil
+	}
+	cfg = cfg.normalize()
+	states := make(map[schema.ExecReportState]struct{}, len(cfg.States))
+	for _, state := range cfg.States {
+		states[schema.ExecReportState(strings.ToUpper(strings.TrimSpace(string(state))))] = struct{}{}
+	}
+	return &orderCallbackTracker{
+		cfg:    cfg,
+		states: states,
+		mu:     sync.Mutex{},
+		stats: OrderCallbackStats{
+			Enabled:  true,
+			Deadline: cfg.Deadline.String(),
+			Tracked:  0,
+			Acked:    0,
+			Retried:  0,
+			Stalled:  0,
+			Failed:   0,
+			Alerts:   nil,
+		},
+	}
+}
+
+func (t *orderCallbackTracker) tracks(state schema.ExecReportState) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.states[state]
+	return ok
+}
+
+func (t *orderCallbackTracker) count(fn func(*OrderCallbackStats)) {
+	t.mu.Lock()
+	fn(&t.stats)
+	t.mu.Unlock()
+}
+
+func (t *orderCallbackTracker) record(alert OrderCallbackAlert) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch alert.Reason {
+	case OrderCallbackStalled:
+		t.stats.Stalled++
+	case OrderCallbackFailed:
+		t.stats.Failed++
+	}
+	t.stats.Alerts = append(t.stats.Alerts, alert)
+	if len(t.stats.Alerts) > maxOrderCallbackAlerts {
+		t.stats.Alerts = t.stats.Alerts[len(t.stats.Alerts)-maxOrderCallbackAlerts:]
+	}
+}
+
+func (t *orderCallbackTracker) snapshot() OrderCallbackStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := t.stats
+	stats.Alerts = append([]OrderCallbackAlert{}, t.stats.Alerts...)
+	return stats
+}
+
+// OrderCallbacks reports how the lambda's execution report callbacks were acknowledged.
+func (l *BaseLambda) OrderCallbacks() OrderCallbackStats {
+	if l.callbacks == nil {
+		return OrderCallbackStats{Enabled: false, Deadline: "", Tracked: 0, Acked: 0, Retried: 0, Stalled: 0, Failed: 0, Alerts: []OrderCallbackAlert{}}
+	}
+	return l.callbacks.snapshot()
+}
+
+// dispatchOrderEvent hands an execution report to the strategy, tracking the callback when the
+// strategy reports failures and the report state is tracked.
+func (l *BaseLambda) dispatchOrderEvent(ctx context.Context, evt *schema.Event, payload schema.ExecReportPayload) {
+	checked, ok := l.strategy.(CheckedOrderCallbacks)
+	if !ok || !l.callbacks.tracks(payload.State) {
+		l.invokeOrderCallback(ctx, evt, payload)
+		return
+	}
+	tracker := l.callbacks
+	tracker.count(func(s *OrderCallbackStats) { s.Tracked++ })
+	// The alert copies the event fields up front: the stall timer may fire after the event is
+	// recycled.
+	alert := l.orderCallbackAlert(evt, payload)
+	attempts := 0
+	var err error
+	for {
+		attempts++
+		attempt := attempts
+		// The deadline escalates a stalled callback while it still runs; it is not interrupted.
+		timer := time.AfterFunc(tracker.cfg.Deadline, func() {
+			l.escalateOrderCallback(alert, OrderCallbackStalled, attempt, nil)
+		})
+		err = checked.HandleOrderEvent(ctx, evt, payload)
+		timer.Stop()
+		if err == nil {
+			tracker.count(func(s *OrderCallbackStats) { s.Acked++ })
+			return
+		}
+		if attempts > tracker.cfg.Retries || ctx.Err() != nil {
+			break
+		}
+		tracker.count(func(s *OrderCallbackStats) { s.Retried++ })
+		select {
+		case <-ctx.Done():
+		case <-time.After(tracker.cfg.Backoff):
+		}
+	}
+	l.escalateOrderCallback(alert, OrderCallbackFailed, attempts, err)
+}
+
+func (l *BaseLambda) invokeOrderCallback(ctx context.Context, evt *schema.Event, payload schema.ExecReportPayload) {
+	switch payload.State {
+	case schema.ExecReportStateFILLED:
+		l.strategy.OnOrderFilled(ctx, evt, payload)
+
+	case schema.ExecReportStateREJECTED:
+		reason := ""
+		if payload.RejectReason != nil {
+			reason = *payload.RejectReason
+		}
+		l.strategy.OnOrderRejected(ctx, evt, payload, reason)
+
+	case schema.ExecReportStatePARTIAL:
+		l.strategy.OnOrderPartialFill(ctx, evt, payload)
+
+	case schema.ExecReportStateCANCELLED:
+		l.strategy.OnOrderCancelled(ctx, evt, payload)
+
+	case schema.ExecReportStateACK:
+		// Order acknowledged by exchange - useful for persistence, auditing, reconciliation
+		l.strategy.OnOrderAcknowledged(ctx, evt, payload)
+
+	case schema.ExecReportStateEXPIRED:
+		// Order expired (e.g., GTD orders that reached time limit)
+		// Useful for tracking order lifecycle, metrics, compliance
+		l.strategy.OnOrderExpired(ctx, evt, payload)
+	}
+}
+
+func (l *BaseLambda) orderCallbackAlert(evt *schema.Event, payload schema.ExecReportPayload) OrderCallbackAlert {
+	return OrderCallbackAlert{
+		LambdaID:      l.id,
+		Provider:      evt.Provider,
+		Symbol:        evt.Symbol,
+		ClientOrderID: payload.ClientOrderID,
+		State:         payload.State,
+		EventID:       evt.EventID,
+		Reason:        "",
+		Attempts:      0,
+		Error:         "",
+		Deadline:      l.callbacks.cfg.Deadline.String(),
+		Timestamp:     time.Time{},
+	}
+}
+
+// escalateOrderCallback records an unacknowledged callback and publishes it for notification.
+func (l *BaseLambda) escalateOrderCallback(alert OrderCallbackAlert, reason OrderCallbackReason, attempts int, cause error) {
+	ts := time.Now().UTC()
+	alert.Reason = reason
+	alert.Attempts = attempts
+	alert.Timestamp = ts
+	if cause != nil {
+		alert.Error = cause.Error()
+	}
+	l.callbacks.record(alert)
+	l.logger.Printf("[%s] order callback %s for %s %s (attempt %d): %s", l.id, strings.ToLower(string(reason)), alert.ClientOrderID, alert.State, attempts, alert.Error)
+	if l.bus == nil || l.pools == nil {
+		return
+	}
+	ctx := context.Background()
+	out, err := l.pools.BorrowEventInst(ctx)
+	if err != nil {
+		l.logger.Printf("[%s] unable to borrow event from pool: %v", l.id, err)
+		return
+	}
+	out.EventID = fmt.Sprintf("order-callback:%s:%s:%d", l.id, alert.ClientOrderID, ts.UnixNano())
+	out.Provider = alert.Provider
+	out.Symbol = alert.Symbol
+	out.Type = schema.ExtensionEventType
+	out.IngestTS = ts
+	out.EmitTS = ts
+	out.Payload = alert
+
+	if err := l.bus.Publish(ctx, out); err != nil {
+		l.logger.Printf("[%s] publish order callback alert: %v", l.id, err)
+		l.pools.ReturnEventInst(out)
+	}
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+type checkedCallbackStrategy struct {
+	testExtensionStrategy
+	calls  atomic.Int32
+	handle func(call int32) error
+}
+
+func (s *checkedCallbackStrategy) HandleOrderEvent(context.Context, *schema.Event, schema.ExecReportPayload) error {
+	return s.handle(s.calls.Add(1))
+}
+
+func newCallbackLambda(t *testing.T, cfg OrderCallbackConfig, strategy TradingStrategy) *BaseLambda {
+	t.Helper()
+	lambda := NewBaseLambda("lambda-callbacks", Config{Providers: []string{"binance"}, OrderCallbacks: cfg}, nil, nil, nil, strategy, nil, nil)
+	lambda.SetLogger(log.New(io.Discard, "", 0))
+	return lambda
+}
+
+func execReportEvent(state schema.ExecReportState) (*schema.Event, schema.ExecReportPayload) {
+	payload := schema.ExecReportPayload{ClientOrderID: "coid-1", State: state}
+	return &schema.Event{EventID: "exec-1", Type: schema.EventTypeExecReport, Provider: "binance", Symbol: "BTC-USDT", Payload: payload}, payload
+}
+
+func TestOrderCallbackRetriesUntilAcknowledged(t *testing.T) {
+	strategy := &checkedCallbackStrategy{handle: func(call int32) error {
+		if call < 2 {
+			return errors.New("boom")
+		}
+		return nil
+	}}
+	lambda := newCallbackLambda(t, OrderCallbackConfig{Deadline: time.Second, Backoff: time.Millisecond}, strategy)
+	evt, payload := execReportEvent(schema.ExecReportStateFILLED)
+	lambda.dispatchOrderEvent(context.Background(), evt, payload)
+
+	stats := lambda.OrderCallbacks()
+	if !stats.Enabled || stats.Tracked != 1 || stats.Acked != 1 || stats.Retried != 1 || stats.Failed != 0 || len(stats.Alerts) != 0 {
+		t.Fatalf("expected one retried and acknowledged callback, got %+v", stats)
+	}
+}
+
+func TestOrderCallbackEscalatesFailure(t *testing.T) {
+	strategy := &checkedCallbackStrategy{handle: func(int32) error { return errors.New("boom") }}
+	lambda := newCallbackLambda(t, OrderCallbackConfig{Deadline: time.Second, Retries: 1, Backoff: time.Millisecond}, strategy)
+	evt, payload := execReportEvent(schema.ExecReportStateREJECTED)
+	lambda.dispatchOrderEvent(context.Background(), evt, payload)
+
+	if calls := strategy.calls.Load(); calls != 2 {
+		t.Fatalf("expected two attempts, got %d", calls)
+	}
+	stats := lambda.OrderCallbacks()
+	if stats.Failed != 1 || stats.Acked != 0 || len(stats.Alerts) != 1 {
+		t.Fatalf("expected one failed callback, got %+v", stats)
+	}
+	alert := stats.Alerts[0]
+	if alert.Reason != OrderCallbackFailed || alert.Attempts != 2 || alert.ClientOrderID != "coid-1" || alert.Error != "boom" {
+		t.Fatalf("unexpected alert %+v", alert)
+	}
+}
+
+func TestOrderCallbackEscalatesStall(t *testing.T) {
+	strategy := &checkedCallbackStrategy{handle: func(int32) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}}
+	lambda := newCallbackLambda(t, OrderCallbackConfig{Deadline: 5 * time.Millisecond}, strategy)
+	evt, payload := execReportEvent(schema.ExecReportStatePARTIAL)
+	lambda.dispatchOrderEvent(context.Background(), evt, payload)
+
+	stats := lambda.OrderCallbacks()
+	if stats.Stalled != 1 || stats.Acked != 1 || len(stats.Alerts) != 1 || stats.Alerts[0].Reason != OrderCallbackStalled {
+		t.Fatalf("expected a stalled but acknowledged callback, got %+v", stats)
+	}
+}
+
+func TestOrderCallbackUntrackedStates(t *testing.T) {
+	strategy := &checkedCallbackStrategy{handle: func(int32) error { return errors.New("boom") }}
+	lambda := newCallbackLambda(t, OrderCallbackConfig{Deadline: time.Second}, strategy)
+	evt, payload := execReportEvent(schema.ExecReportStateACK)
+	lambda.dispatchOrderEvent(context.Background(), evt, payload)
+
+	if calls := strategy.calls.Load(); calls != 0 {
+		t.Fatalf("expected untracked reports to use the plain callbacks, got %d checked calls", calls)
+	}
+	if stats := lambda.OrderCallbacks(); stats.Tracked != 0 {
+		t.Fatalf("expected nothing tracked, got %+v", stats)
+	}
+	if stats := newCallbackLambda(t, OrderCallbackConfig{}, strategy).OrderCallbacks(); stats.Enabled {
+		t.Fatalf("expected tracking disabled without a deadline, got %+v", stats)
+	}
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+var (
+	// ErrPostOnlyWouldCross is returned when an emulated post-only order would take liquidity.
+	ErrPostOnlyWouldCross = errors.New("post-only order would cross the book")
+	// ErrReduceOnlyWouldIncrease is returned when an emulated reduce-only order would open or grow
+	// a position instead of shrinking it.
+	ErrReduceOnlyWouldIncrease = errors.New("reduce-only order would increase the position")
+)
+
+// OrderFlagSupport is implemented by order submitters that know which order flags a provider's
+// venue enforces itself. The lambda emulates the flags a venue lacks, and both flags when the
+// submitter does not implement the interface.
+type OrderFlagSupport interface {
+	NativeOrderFlags(provider string) (postOnly, reduceOnly bool)
+}
+
+// applyPostOnly reconciles the post-only flag with the time in force: GTX implies post-only and
+// post-only implies GTX. Post-only orders must rest, so they are limit orders with no IOC or FOK.
+func applyPostOnly(intent *orderIntent) error {
+	if intent.tif == schema.TimeInForceGTX {
+		intent.postOnly = true
+	}
+	if !intent.postOnly {
+		return nil
+	}
+	if intent.orderType != schema.OrderTypeLimit {
+		return fmt.Errorf("post-only requires a limit order")
+	}
+	if intent.tif != schema.TimeInForceGTC && intent.tif != schema.TimeInForceGTX {
+		return fmt.Errorf("post-only orders cannot use time in force %s", intent.tif)
+	}
+	intent.tif = schema.TimeInForceGTX
+	return nil
+}
+
+// emulateOrderFlags enforces the flags the order's venue lacks. A post-only order is checked
+// against the mirrored book and sent as GTC; a reduce-only order is checked against the position
+// the lambda built from its own fills.
+func (l *BaseLambda) emulateOrderFlags(req *schema.OrderRequest) error {
+	if !req.PostOnly && !req.ReduceOnly {
+		return nil
+	}
+	nativePostOnly, nativeReduceOnly := false, false
+	if support, ok := l.orderSubmitter.(OrderFlagSupport); ok {
+		nativePostOnly, nativeReduceOnly = support.NativeOrderFlags(req.Provider)
+	}
+	if req.PostOnly && !nativePostOnly {
+		if err := l.checkPostOnly(req); err != nil {
+			return err
+		}
+		req.TIF = schema.TimeInForceGTC
+	}
+	if req.ReduceOnly && !nativeReduceOnly {
+		if err := l.checkReduceOnly(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPostOnly rejects a limit order priced through the opposite side of the book. Without a
+// mirrored book there is nothing to check against, so the order is rejected rather than risk
+// taking liquidity.
+func (l *BaseLambda) checkPostOnly(req *schema.OrderRequest) error {
+	if req.Price == nil {
+		return fmt.Errorf("post-only requires a limit price")
+	}
+	price, err := decimal.NewFromString(strings.TrimSpace(*req.Price))
+	if err != nil {
+		return fmt.Errorf("invalid order price %q", *req.Price)
+	}
+	top, ok := l.BookTop(req.Symbol, req.Provider)
+	if !ok {
+		return fmt.Errorf("%w: no book for %s on %s to check against", ErrPostOnlyWouldCross, req.Symbol, req.Provider)
+	}
+	level := top.Ask
+	if req.Side == schema.TradeSideSell {
+		level = top.Bid
+	}
+	if level == nil {
+		return nil
+	}
+	opposite, err := decimal.NewFromString(level.Price)
+	crosses := err == nil &&
+		((req.Side == schema.TradeSideBuy && price.GreaterThanOrEqual(opposite)) ||
+			(req.Side == schema.TradeSideSell && price.LessThanOrEqual(opposite)))
+	if crosses {
+		return fmt.Errorf("%w: %s %s at %s against %s", ErrPostOnlyWouldCross, req.Side, req.Symbol, price, opposite)
+	}
+	return nil
+}
+
+// checkReduceOnly rejects an order on the side of the position, or larger than the position.
+func (l *BaseLambda) checkReduceOnly(req *schema.OrderRequest) error {
+	quantity, err := decimal.NewFromString(strings.TrimSpace(req.Quantity))
+	if err != nil {
+		return fmt.Errorf("invalid order quantity %q", req.Quantity)
+	}
+	position := l.book.position(req.Symbol)
+	reducible := position.Neg()
+	if req.Side == schema.TradeSideSell {
+		reducible = position
+	}
+	if !reducible.IsPositive() || quantity.GreaterThan(reducible) {
+		return fmt.Errorf("%w: %s %s %s against position %s", ErrReduceOnlyWouldIncrease, req.Side, quantity, req.Symbol, position)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+type flagSubmitter struct {
+	captureSubmitter
+	postOnly, reduceOnly bool
+}
+
+func (s *flagSubmitter) NativeOrderFlags(string) (bool, bool) { return s.postOnly, s.reduceOnly }
+
+func newFlagLambda(t *testing.T, submitter OrderSubmitter) *BaseLambda {
+	t.Helper()
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("OrderRequest", 4, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	cfg := Config{Providers: []string{"binance"}, ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}}}
+	return NewBaseLambda("flags", cfg, nil, submitter, poolMgr, nil, nil, nil)
+}
+
+func TestPostOnlyEmulatedAgainstBook(t *testing.T) {
+	submitter := &flagSubmitter{}
+	lambda := newFlagLambda(t, submitter)
+	ctx := context.Background()
+	price := "101"
+	opts := OrderOptions{PostOnly: true}
+	if err := lambda.SubmitOrderWithOptions(ctx, "binance", schema.TradeSideBuy, "1", &price, opts); !errors.Is(err, ErrPostOnlyWouldCross) {
+		t.Fatalf("expected rejection without a book, got %v", err)
+	}
+
+	lambda.books.observe("binance", "BTC-USDT", schema.BookSnapshotPayload{Bids: levels("100", "1"), Asks: levels("101", "1")}, true, time.Now())
+	if err := lambda.SubmitOrderWithOptions(ctx, "binance", schema.TradeSideBuy, "1", &price, opts); !errors.Is(err, ErrPostOnlyWouldCross) {
+		t.Fatalf("expected a buy at the ask rejected, got %v", err)
+	}
+	price = "100.5"
+	if err := lambda.SubmitOrderWithOptions(ctx, "binance", schema.TradeSideBuy, "1", &price, opts); err != nil {
+		t.Fatalf("expected a passive buy accepted, got %v", err)
+	}
+	if len(submitter.orders) != 1 || !submitter.orders[0].PostOnly || submitter.orders[0].TIF != schema.TimeInForceGTC {
+		t.Fatalf("expected an emulated post-only order sent as GTC, got %+v", submitter.orders)
+	}
+
+	// Venues that enforce post-only receive GTX without a book check.
+	submitter.postOnly = true
+	price = "105"
+	if err := lambda.SubmitOrderWithOptions(ctx, "binance", schema.TradeSideBuy, "1", &price, opts); err != nil {
+		t.Fatalf("expected native post-only left to the venue, got %v", err)
+	}
+	if got := submitter.orders[1]; got.TIF != schema.TimeInForceGTX || !got.PostOnly {
+		t.Fatalf("expected a native post-only order sent as GTX, got %+v", got)
+	}
+	if err := lambda.SubmitMarketOrderWithOptions(ctx, "binance", schema.TradeSideBuy, "1", opts); err == nil {
+		t.Fatal("expected a post-only market order rejected")
+	}
+}
+
+func TestReduceOnlyEmulatedAgainstPosition(t *testing.T) {
+	submitter := &flagSubmitter{}
+	lambda := newFlagLambda(t, submitter)
+	ctx := context.Background()
+	opts := OrderOptions{ReduceOnly: true}
+	if err := lambda.SubmitMarketOrderWithOptions(ctx, "binance", schema.TradeSideSell, "1", opts); !errors.Is(err, ErrReduceOnlyWouldIncrease) {
+		t.Fatalf("expected rejection while flat, got %v", err)
+	}
+	lambda.book.apply("BTC-USDT", schema.ExecReportPayload{ClientOrderID: "b1", Side: schema.TradeSideBuy, State: schema.ExecReportStateFILLED, FilledQuantity: "2", AvgFillPrice: "100"})
+	if err := lambda.SubmitMarketOrderWithOptions(ctx, "binance", schema.TradeSideBuy, "1", opts); !errors.Is(err, ErrReduceOnlyWouldIncrease) {
+		t.Fatalf("expected a buy against a long rejected, got %v", err)
+	}
+	if err := lambda.SubmitMarketOrderWithOptions(ctx, "binance", schema.TradeSideSell, "3", opts); !errors.Is(err, ErrReduceOnlyWouldIncrease) {
+		t.Fatalf("expected a sell larger than the long rejected, got %v", err)
+	}
+	if err := lambda.SubmitMarketOrderWithOptions(ctx, "binance", schema.TradeSideSell, "2", opts); err != nil {
+		t.Fatalf("expected a closing sell accepted, got %v", err)
+	}
+	if len(submitter.orders) != 1 || !submitter.orders[0].ReduceOnly {
+		t.Fatalf("expected one reduce-only order submitted, got %+v", submitter.orders)
+	}
+}
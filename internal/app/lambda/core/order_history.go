@@ -0,0 +1,434 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// DefaultOrderHistorySize is the number of orders retained per instance by default.
+const DefaultOrderHistorySize = 200
+
+// orderStatePending marks an order that was submitted but not yet acknowledged, matching the state
+// persisted by persistNewOrder.
+const orderStatePending = "PENDING"
+
+// OrderHistoryConfig sets how many of its orders the host keeps on behalf of the strategy. Zero
+// selects the default and a negative value disables the cache.
+type OrderHistoryConfig struct {
+	Size int
+}
+
+func (c OrderHistoryConfig) normalize() OrderHistoryConfig {
+	if c.Size == 0 {
+		c.Size = DefaultOrderHistorySize
+	}
+	return c
+}
+
+// OrderView is the host's record of one of the lambda's orders as of its latest execution report.
+// FilledQuantity and AvgFillPrice roll up every fill of the order and Commissions sums the
+// commission paid per asset. Times are Unix milliseconds.
+type OrderView struct {
+	ClientOrderID   string            `json:"clientOrderId"`
+	ExchangeOrderID string            `json:"exchangeOrderId,omitempty"`
+	Provider        string            `json:"provider"`
+	Symbol          string            `json:"symbol"`
+	Side            string            `json:"side"`
+	Type            string            `json:"type"`
+	Quantity        string            `json:"quantity"`
+	Price           string            `json:"price,omitempty"`
+	State           string            `json:"state"`
+	FilledQuantity  string            `json:"filledQuantity,omitempty"`
+	RemainingQty    string            `json:"remainingQty,omitempty"`
+	AvgFillPrice    string            `json:"avgFillPrice,omitempty"`
+	RejectReason    string            `json:"rejectReason,omitempty"`
+	Commissions     map[string]string `json:"commissions,omitempty"`
+	Fills           int               `json:"fills"`
+	Tags            []string          `json:"tags,omitempty"`
+	PlacedAt        int64             `json:"placedAt"`
+	UpdatedAt       int64             `json:"updatedAt"`
+}
+
+// Open reports whether the order can still trade.
+func (v OrderView) Open() bool {
+	switch v.State {
+	case orderStatePending, string(schema.ExecReportStateACK), string(schema.ExecReportStatePARTIAL):
+		return true
+	default:
+		return false
+	}
+}
+
+// orderHistory keeps the lambda's most recent orders in placement order, updated from submissions
+// and execution reports, so strategies query their orders from the host instead of keeping their
+// own bookkeeping in the VM. When full, the oldest closed order is evicted first.
+type orderHistory struct {
+	mu     sync.Mutex
+	size   int
+	orders map[string]*OrderView
+	// placed lists client order IDs oldest first.
+	placed []string
+}
+
+func newOrderHistory(cfg OrderHistoryConfig) *orderHistory {
+	return &orderHistory{
+		mu:     sync.Mutex{},
+		size:   cfg.normalize().Size,
+		orders: make(map[string]*OrderView),
+		placed: nil,
+	}
+}
+
+func (h *orderHistory) enabled() bool {
+	return h != nil && h.size > 0
+}
+
+// insertLocked adds view unless an order with the same ID is already held.
+func (h *orderHistory) insertLocked(view OrderView) *OrderView {
+	if existing, ok := h.orders[view.ClientOrderID]; ok {
+		return existing
+	}
+	if len(h.placed) >= h.size {
+		h.evictLocked()
+	}
+	stored := view
+	h.orders[view.ClientOrderID] = &stored
+	h.placed = append(h.placed, view.ClientOrderID)
+	return &stored
+}
+
+func (h *orderHistory) evictLocked() {
+	victim := 0
+	for i, id := range h.placed {
+		if !h.orders[id].Open() {
+			victim = i
+			break
+		}
+	}
+	delete(h.orders, h.placed[victim])
+	h.placed = append(h.placed[:victim], h.placed[victim+1:]...)
+}
+
+// placedOrder records an order handed to the submitter.
+func (h *orderHistory) placedOrder(req *schema.OrderRequest) {
+	if !h.enabled() || req == nil {
+		return
+	}
+	placedAt := req.Timestamp
+	if placedAt.IsZero() {
+		placedAt = time.Now()
+	}
+	view := OrderView{
+		ClientOrderID:   req.ClientOrderID,
+		ExchangeOrderID: "",
+		Provider:        req.Provider,
+		Symbol:          req.Symbol,
+		Side:            string(req.Side),
+		Type:            string(req.OrderType),
+		Quantity:        req.Quantity,
+		Price:           "",
+		State:           orderStatePending,
+		FilledQuantity:  "",
+		RemainingQty:    "",
+		AvgFillPrice:    "",
+		RejectReason:    "",
+		Commissions:     nil,
+		Fills:           0,
+		Tags:            append([]string(nil), req.Tags...),
+		PlacedAt:        placedAt.UnixMilli(),
+		UpdatedAt:       placedAt.UnixMilli(),
+	}
+	if req.Price != nil {
+		view.Price = *req.Price
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.insertLocked(view)
+}
+
+// rejected marks an order the submitter refused.
+func (h *orderHistory) rejected(clientOrderID string, err error) {
+	if !h.enabled() {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	view, ok := h.orders[clientOrderID]
+	if !ok {
+		return
+	}
+	view.State = string(schema.ExecReportStateREJECTED)
+	view.UpdatedAt = time.Now().UnixMilli()
+	if err != nil {
+		view.RejectReason = err.Error()
+	}
+}
+
+// apply updates an order from its execution report and fill roll-up. Reports for orders the cache
+// does not hold, such as ones replayed after a restart, add them.
+func (h *orderHistory) apply(evt *schema.Event, payload schema.ExecReportPayload, fills fillUpdate) {
+	if !h.enabled() || strings.TrimSpace(payload.ClientOrderID) == "" {
+		return
+	}
+	at := payload.Timestamp
+	if at.IsZero() && evt != nil {
+		at = evt.EmitTS
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	view, ok := h.orders[payload.ClientOrderID]
+	if !ok {
+		var provider, symbol string
+		if evt != nil {
+			provider, symbol = evt.Provider, evt.Symbol
+		}
+		view = h.insertLocked(OrderView{
+			ClientOrderID:   payload.ClientOrderID,
+			ExchangeOrderID: "",
+			Provider:        provider,
+			Symbol:          symbol,
+			Side:            string(payload.Side),
+			Type:            string(payload.OrderType),
+			Quantity:        payload.Quantity,
+			Price:           payload.Price,
+			State:           "",
+			FilledQuantity:  "",
+			RemainingQty:    "",
+			AvgFillPrice:    "",
+			RejectReason:    "",
+			Commissions:     nil,
+			Fills:           0,
+			Tags:            nil,
+			PlacedAt:        at.UnixMilli(),
+			UpdatedAt:       0,
+		})
+	}
+	view.State = string(payload.State)
+	view.UpdatedAt = at.UnixMilli()
+	if payload.ExchangeOrderID != "" {
+		view.ExchangeOrderID = payload.ExchangeOrderID
+	}
+	if fills.summary.FilledQuantity != "" {
+		view.FilledQuantity = fills.summary.FilledQuantity
+		view.AvgFillPrice = fills.summary.AvgFillPrice
+		view.Commissions = fills.summary.Commissions
+		view.Fills = fills.summary.Fills
+	}
+	if payload.RemainingQty != "" {
+		view.RemainingQty = payload.RemainingQty
+	}
+	if payload.RejectReason != nil {
+		view.RejectReason = *payload.RejectReason
+	}
+}
+
+// seed loads persisted orders, newest first as returned by the store, beneath the ones already
+// held.
+func (h *orderHistory) seed(records []orderstore.OrderRecord) {
+	if !h.enabled() || len(records) == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		id := record.ClientOrderID
+		if id == "" {
+			id = record.ID
+		}
+		if _, ok := h.orders[id]; ok {
+			continue
+		}
+		updatedAt := record.UpdatedAt
+		if updatedAt == 0 {
+			updatedAt = record.PlacedAt
+		}
+		view := OrderView{
+			ClientOrderID:   id,
+			ExchangeOrderID: metadataString(record.Metadata, "exchangeOrderId"),
+			Provider:        record.Provider,
+			Symbol:          record.Symbol,
+			Side:            record.Side,
+			Type:            record.Type,
+			Quantity:        record.Quantity,
+			Price:           "",
+			State:           record.State,
+			FilledQuantity:  metadataString(record.Metadata, "filledQuantity"),
+			RemainingQty:    metadataString(record.Metadata, "remainingQty"),
+			AvgFillPrice:    metadataString(record.Metadata, "avgFillPrice"),
+			RejectReason:    metadataString(record.Metadata, "rejectReason"),
+			Commissions:     nil,
+			Fills:           0,
+			Tags:            metadataStrings(record.Metadata, "tags"),
+			PlacedAt:        record.PlacedAt * 1000,
+			UpdatedAt:       updatedAt * 1000,
+		}
+		if record.Price != nil {
+			view.Price = *record.Price
+		}
+		if view.ExchangeOrderID == "" {
+			view.ExchangeOrderID = record.ExternalReference
+		}
+		if fills := recordFills(record); fills != nil {
+			view.FilledQuantity = fills.FilledQuantity
+			view.AvgFillPrice = fills.AvgFillPrice
+			view.Commissions = fills.Commissions
+			view.Fills = fills.Fills
+		}
+		h.insertLocked(view)
+	}
+	h.sortLocked()
+}
+
+// sortLocked restores placement order after seeding interleaved older orders.
+func (h *orderHistory) sortLocked() {
+	for i := 1; i < len(h.placed); i++ {
+		for j := i; j > 0 && h.orders[h.placed[j]].PlacedAt < h.orders[h.placed[j-1]].PlacedAt; j-- {
+			h.placed[j], h.placed[j-1] = h.placed[j-1], h.placed[j]
+		}
+	}
+}
+
+// open returns the orders that can still trade, oldest first.
+func (h *orderHistory) open() []OrderView {
+	out := []OrderView{}
+	if !h.enabled() {
+		return out
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, id := range h.placed {
+		if view := h.orders[id]; view.Open() {
+			out = append(out, cloneOrderView(*view))
+		}
+	}
+	return out
+}
+
+// get returns the order with the client order ID.
+func (h *orderHistory) get(clientOrderID string) (OrderView, bool) {
+	if !h.enabled() {
+		return OrderView{}, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	view, ok := h.orders[strings.TrimSpace(clientOrderID)]
+	if !ok {
+		return OrderView{}, false
+	}
+	return cloneOrderView(*view), true
+}
+
+// recent returns up to n orders, newest first; n <= 0 returns every order held.
+func (h *orderHistory) recent(n int) []OrderView {
+	out := []OrderView{}
+	if !h.enabled() {
+		return out
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if n <= 0 || n > len(h.placed) {
+		n = len(h.placed)
+	}
+	for i := len(h.placed) - 1; i >= len(h.placed)-n; i-- {
+		out = append(out, cloneOrderView(*h.orders[h.placed[i]]))
+	}
+	return out
+}
+
+func cloneOrderView(view OrderView) OrderView {
+	view.Tags = append([]string(nil), view.Tags...)
+	if view.Commissions != nil {
+		commissions := make(map[string]string, len(view.Commissions))
+		for asset, amount := range view.Commissions {
+			commissions[asset] = amount
+		}
+		view.Commissions = commissions
+	}
+	return view
+}
+
+// recordFills returns the fill roll-up of a persisted order, whether the store decoded it or left
+// it in the metadata.
+func recordFills(record orderstore.OrderRecord) *orderstore.FillSummary {
+	if record.Fills != nil {
+		return record.Fills
+	}
+	return orderstore.FillSummaryFromMetadata(record.Metadata)
+}
+
+func metadataString(metadata map[string]any, key string) string {
+	if value, ok := metadata[key].(string); ok {
+		return value
+	}
+	return ""
+}
+
+func metadataStrings(metadata map[string]any, key string) []string {
+	switch values := metadata[key].(type) {
+	case []string:
+		return append([]string(nil), values...)
+	case []any:
+		out := make([]string, 0, len(values))
+		for _, value := range values {
+			if text, ok := value.(string); ok {
+				out = append(out, text)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// OpenOrders returns the lambda's orders that can still trade, oldest first.
+func (l *BaseLambda) OpenOrders() []OrderView {
+	return l.orders.open()
+}
+
+// Order returns the lambda's order with the client order ID while the host retains it.
+func (l *BaseLambda) Order(clientOrderID string) (OrderView, bool) {
+	return l.orders.get(clientOrderID)
+}
+
+// RecentOrders returns the lambda's n most recent orders, newest first; n <= 0 returns every
+// order the host retains.
+func (l *BaseLambda) RecentOrders(n int) []OrderView {
+	return l.orders.recent(n)
+}
+
+// seedOrderHistory loads the instance's most recent persisted orders so a restarted lambda sees
+// the orders it placed before the restart, and resumes the fill roll-ups of those still open.
+func (l *BaseLambda) seedOrderHistory(ctx context.Context) {
+	if l.orderStore == nil || !l.orders.enabled() {
+		return
+	}
+	records, err := l.orderStore.ListOrders(ctx, orderstore.OrderQuery{
+		StrategyInstance: l.id,
+		Provider:         "",
+		States:           nil,
+		Since:            0,
+		Until:            0,
+		Tag:              "",
+		Limit:            l.orders.size,
+	})
+	if err != nil {
+		l.logger.Printf("[%s] load order history: %v", l.id, err)
+		return
+	}
+	l.orders.seed(records)
+	for _, record := range records {
+		switch record.State {
+		case orderStatePending, string(schema.ExecReportStateACK), string(schema.ExecReportStatePARTIAL):
+			l.fills.restore(record.ClientOrderID, recordFills(record))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+type failingSubmitter struct{}
+
+func (failingSubmitter) SubmitOrder(context.Context, schema.OrderRequest) error {
+	return errors.New("venue down")
+}
+
+type seededOrderStore struct {
+	recordingOrderStore
+	records []orderstore.OrderRecord
+	query   orderstore.OrderQuery
+}
+
+func (s *seededOrderStore) ListOrders(_ context.Context, query orderstore.OrderQuery) ([]orderstore.OrderRecord, error) {
+	s.query = query
+	return s.records, nil
+}
+
+func TestOrderHistoryEvictsClosedOrdersFirst(t *testing.T) {
+	history := newOrderHistory(OrderHistoryConfig{Size: 3})
+	for _, id := range []string{"a", "b", "c"} {
+		history.placedOrder(&schema.OrderRequest{ClientOrderID: id, Side: schema.TradeSideBuy, Quantity: "1"})
+	}
+	history.apply(nil, schema.ExecReportPayload{ClientOrderID: "b", State: schema.ExecReportStateFILLED, FilledQuantity: "1"}, fillUpdate{})
+	history.placedOrder(&schema.OrderRequest{ClientOrderID: "d", Side: schema.TradeSideSell, Quantity: "2"})
+
+	recent := history.recent(0)
+	if len(recent) != 3 || recent[0].ClientOrderID != "d" || recent[1].ClientOrderID != "c" || recent[2].ClientOrderID != "a" {
+		t.Fatalf("expected the filled order to be evicted, got %+v", recent)
+	}
+	open := history.open()
+	if len(open) != 3 || open[0].ClientOrderID != "a" || open[0].State != orderStatePending {
+		t.Fatalf("unexpected open orders %+v", open)
+	}
+	if got := history.recent(1); len(got) != 1 || got[0].ClientOrderID != "d" {
+		t.Fatalf("recent(1) = %+v", got)
+	}
+	disabled := newOrderHistory(OrderHistoryConfig{Size: -1})
+	disabled.placedOrder(&schema.OrderRequest{ClientOrderID: "x"})
+	if len(disabled.recent(0)) != 0 {
+		t.Fatal("expected a disabled cache to hold nothing")
+	}
+}
+
+func TestBaseLambdaOrderHistoryFollowsExecReports(t *testing.T) {
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("OrderRequest", 4, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	store := &seededOrderStore{records: []orderstore.OrderRecord{
+		{Order: orderstore.Order{ID: "old-2", ClientOrderID: "old-2", Provider: "binance", Symbol: "BTC-USDT", Side: "Sell", Quantity: "1", State: "ACK", PlacedAt: 200, Metadata: map[string]any{"tags": []any{"grid"}}}},
+		{Order: orderstore.Order{ID: "old-1", ClientOrderID: "old-1", Provider: "binance", Symbol: "BTC-USDT", Side: "Buy", Quantity: "1", State: "FILLED", PlacedAt: 100, Metadata: map[string]any{"filledQuantity": "1"}}},
+	}}
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+	}
+	lambda := NewBaseLambda("lambda-orders", cfg, nil, &captureSubmitter{}, poolMgr, nil, nil, store)
+	lambda.seedOrderHistory(context.Background())
+	if store.query.StrategyInstance != "lambda-orders" || store.query.Limit != DefaultOrderHistorySize {
+		t.Fatalf("unexpected seed query %+v", store.query)
+	}
+	if open := lambda.OpenOrders(); len(open) != 1 || open[0].ClientOrderID != "old-2" || len(open[0].Tags) != 1 {
+		t.Fatalf("expected the persisted open order, got %+v", open)
+	}
+
+	price := "100"
+	if err := lambda.SubmitOrder(context.Background(), "binance", schema.TradeSideBuy, "2", &price); err != nil {
+		t.Fatalf("SubmitOrder: %v", err)
+	}
+	placed := lambda.RecentOrders(1)[0]
+	if placed.State != orderStatePending || placed.Price != "100" || placed.Quantity != "2" {
+		t.Fatalf("unexpected placed order %+v", placed)
+	}
+	lambda.handleExecReport(context.Background(), &schema.Event{
+		Type:     schema.EventTypeExecReport,
+		Provider: "binance",
+		Symbol:   "BTC-USDT",
+		Payload: schema.ExecReportPayload{
+			ClientOrderID:   placed.ClientOrderID,
+			ExchangeOrderID: "9001",
+			State:           schema.ExecReportStatePARTIAL,
+			FilledQuantity:  "0.5",
+			RemainingQty:    "1.5",
+			AvgFillPrice:    "100",
+			Timestamp:       time.Now(),
+		},
+	})
+	recent := lambda.RecentOrders(0)
+	if len(recent) != 3 || recent[0].State != string(schema.ExecReportStatePARTIAL) || recent[0].ExchangeOrderID != "9001" || recent[0].FilledQuantity != "0.5" {
+		t.Fatalf("expected the partial fill on the newest order, got %+v", recent)
+	}
+	if recent[2].ClientOrderID != "old-1" || recent[2].FilledQuantity != "1" {
+		t.Fatalf("expected the oldest persisted order last, got %+v", recent[2])
+	}
+	if open := lambda.OpenOrders(); len(open) != 2 {
+		t.Fatalf("expected two open orders, got %+v", open)
+	}
+
+	rejecting := NewBaseLambda("lambda-rejects", cfg, nil, failingSubmitter{}, poolMgr, nil, nil, nil)
+	if err := rejecting.SubmitMarketOrder(context.Background(), "binance", schema.TradeSideSell, "1"); err == nil {
+		t.Fatal("expected the submission to fail")
+	}
+	if got := rejecting.RecentOrders(1); len(got) != 1 || got[0].State != string(schema.ExecReportStateREJECTED) || got[0].RejectReason != "venue down" || len(rejecting.OpenOrders()) != 0 {
+		t.Fatalf("expected a rejected order, got %+v", got)
+	}
+}
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// Priority ranks an instance when strategy handlers compete for the shared handler scheduler.
+type Priority string
+
+const (
+	// PriorityHigh suits latency-critical strategies such as market makers.
+	PriorityHigh Priority = "high"
+	// PriorityNormal is the default priority.
+	PriorityNormal Priority = "normal"
+	// PriorityLow suits reporting strategies that can lag under load.
+	PriorityLow Priority = "low"
+)
+
+const (
+	priorityLevels              = 3
+	defaultHighPriorityWeight   = 8
+	defaultNormalPriorityWeight = 4
+	defaultLowPriorityWeight    = 1
+)
+
+// ParsePriority reads a priority case-insensitively; unknown or empty values select PriorityNormal.
+func ParsePriority(raw string) Priority {
+	switch Priority(strings.ToLower(strings.TrimSpace(raw))) {
+	case PriorityHigh:
+		return PriorityHigh
+	case PriorityLow:
+		return PriorityLow
+	default:
+		return PriorityNormal
+	}
+}
+
+func (p Priority) normalize() Priority {
+	return ParsePriority(string(p))
+}
+
+func (p Priority) level() int {
+	switch p.normalize() {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// HandlerSchedulerConfig sizes the shared handler scheduler.
+type HandlerSchedulerConfig struct {
+	// Slots bounds the strategy handlers running at once across all instances.
+	Slots int
+	// Weights are the handlers admitted per priority and scheduling round while handlers wait.
+	HighWeight   int
+	NormalWeight int
+	LowWeight    int
+}
+
+// HandlerScheduler bounds strategy handlers running at once across instances. While every slot is
+// busy, waiting handlers are admitted in weighted round-robin by instance priority, so the event
+// queues of high-priority instances drain first and low-priority instances lag without starving.
+// A nil scheduler admits every handler immediately.
+type HandlerScheduler struct {
+	mu      sync.Mutex
+	slots   int
+	busy    int
+	weights [priorityLevels]int
+	waiters [priorityLevels][]chan struct{}
+	cursor  int
+	credit  int
+
+	waitTime metric.Float64Histogram
+}
+
+// NewHandlerScheduler builds a scheduler; non-positive slots or weights fall back to defaults.
+func NewHandlerScheduler(cfg HandlerSchedulerConfig) *HandlerScheduler {
+	if cfg.Slots <= 0 {
+		cfg.Slots = 1
+	}
+	weights := [priorityLevels]int{cfg.HighWeight, cfg.NormalWeight, cfg.LowWeight}
+	for i, fallback := range [priorityLevels]int{defaultHighPriorityWeight, defaultNormalPriorityWeight, defaultLowPriorityWeight} {
+		if weights[i] <= 0 {
+			weights[i] = fallback
+		}
+	}
+	waitTime, _ := otel.Meter("lambda").Float64Histogram("lambda.handler.schedule_wait",
+		metric.WithDescription("Time strategy handlers wait for a scheduler slot"),
+		metric.WithUnit("ms"))
+	return &HandlerScheduler{
+		mu:       sync.Mutex{},
+		slots:    cfg.Slots,
+		busy:     0,
+		weights:  weights,
+		waiters:  [priorityLevels][]chan struct{}{},
+		cursor:   0,
+		credit:   weights[0],
+		waitTime: waitTime,
+	}
+}
+
+// acquire blocks until a slot is granted to a handler of the given priority. It returns false when
+// ctx is cancelled first; otherwise the caller must invoke release once the handler returns.
+func (s *HandlerScheduler) acquire(ctx context.Context, priority Priority) bool {
+	if s == nil {
+		return true
+	}
+	level := priority.level()
+	s.mu.Lock()
+	if s.busy < s.slots && s.waitingLocked() == 0 {
+		s.busy++
+		s.mu.Unlock()
+		return true
+	}
+	grant := make(chan struct{})
+	s.waiters[level] = append(s.waiters[level], grant)
+	s.mu.Unlock()
+
+	start := time.Now()
+	select {
+	case <-grant:
+		s.recordWait(ctx, priority, start)
+		return true
+	case <-ctx.Done():
+		s.mu.Lock()
+		if s.removeWaiterLocked(level, grant) {
+			s.mu.Unlock()
+			return false
+		}
+		s.mu.Unlock()
+		// The slot was granted concurrently with the cancellation; hand it on.
+		s.release()
+		return false
+	}
+}
+
+// release frees the slot, passing it straight to the next waiter when there is one.
+func (s *HandlerScheduler) release() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if next := s.nextWaiterLocked(); next != nil {
+		close(next)
+		return
+	}
+	s.busy--
+}
+
+func (s *HandlerScheduler) waitingLocked() int {
+	total := 0
+	for _, queue := range s.waiters {
+		total += len(queue)
+	}
+	return total
+}
+
+// nextWaiterLocked pops the next waiter in weighted round-robin order: up to weight grants per
+// priority before moving to the next one.
+func (s *HandlerScheduler) nextWaiterLocked() chan struct{} {
+	for i := 0; i <= priorityLevels; i++ {
+		if queue := s.waiters[s.cursor]; s.credit > 0 && len(queue) > 0 {
+			s.waiters[s.cursor] = queue[1:]
+			s.credit--
+			return queue[0]
+		}
+		s.cursor = (s.cursor + 1) % priorityLevels
+		s.credit = s.weights[s.cursor]
+	}
+	return nil
+}
+
+func (s *HandlerScheduler) removeWaiterLocked(level int, grant chan struct{}) bool {
+	queue := s.waiters[level]
+	for i, waiter := range queue {
+		if waiter == grant {
+			s.waiters[level] = append(queue[:i:i], queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *HandlerScheduler) recordWait(ctx context.Context, priority Priority, start time.Time) {
+	if s.waitTime == nil {
+		return
+	}
+	s.waitTime.Record(context.WithoutCancel(ctx), float64(time.Since(start).Microseconds())/1000, metric.WithAttributes(
+		attribute.String("environment", telemetry.Environment()),
+		attribute.String("priority", string(priority.normalize()))))
+}
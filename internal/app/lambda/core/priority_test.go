@@ -0,0 +1,107 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParsePriority(t *testing.T) {
+	cases := map[string]Priority{
+		"HIGH":    PriorityHigh,
+		" low ":   PriorityLow,
+		"normal":  PriorityNormal,
+		"":        PriorityNormal,
+		"urgent!": PriorityNormal,
+	}
+	for raw, want := range cases {
+		if got := ParsePriority(raw); got != want {
+			t.Fatalf("ParsePriority(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestHandlerScheduler_GrantsHighPriorityFirst(t *testing.T) {
+	scheduler := NewHandlerScheduler(HandlerSchedulerConfig{Slots: 1, HighWeight: 2, NormalWeight: 1, LowWeight: 1})
+	ctx := context.Background()
+	if !scheduler.acquire(ctx, PriorityNormal) {
+		t.Fatal("expected free slot to be granted")
+	}
+
+	granted := make(chan Priority, 4)
+	enqueue := func(priority Priority) {
+		go func() {
+			if scheduler.acquire(ctx, priority) {
+				granted <- priority
+			}
+		}()
+		waitForWaiters(t, scheduler, queued(scheduler)+1)
+	}
+	enqueue(PriorityLow)
+	enqueue(PriorityLow)
+	enqueue(PriorityHigh)
+	enqueue(PriorityHigh)
+
+	want := []Priority{PriorityHigh, PriorityHigh, PriorityLow, PriorityLow}
+	for i, expected := range want {
+		scheduler.release()
+		select {
+		case got := <-granted:
+			if got != expected {
+				t.Fatalf("grant %d = %s, want %s", i, got, expected)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("grant %d timed out", i)
+		}
+	}
+	scheduler.release()
+	if scheduler.busy != 0 {
+		t.Fatalf("busy = %d after releasing every slot", scheduler.busy)
+	}
+}
+
+func TestHandlerScheduler_CancelledWaiterDoesNotHoldSlot(t *testing.T) {
+	scheduler := NewHandlerScheduler(HandlerSchedulerConfig{Slots: 1})
+	if !scheduler.acquire(context.Background(), PriorityHigh) {
+		t.Fatal("expected free slot to be granted")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool, 1)
+	go func() { done <- scheduler.acquire(ctx, PriorityHigh) }()
+	waitForWaiters(t, scheduler, 1)
+	cancel()
+	if <-done {
+		t.Fatal("cancelled waiter should not be granted a slot")
+	}
+	scheduler.release()
+	if !scheduler.acquire(context.Background(), PriorityLow) {
+		t.Fatal("expected slot to be free after release")
+	}
+}
+
+func TestHandlerScheduler_NilAdmitsImmediately(t *testing.T) {
+	var scheduler *HandlerScheduler
+	if !scheduler.acquire(context.Background(), PriorityLow) {
+		t.Fatal("nil scheduler should admit handlers")
+	}
+	scheduler.release()
+}
+
+func queued(scheduler *HandlerScheduler) int {
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	return scheduler.waitingLocked()
+}
+
+// waitForWaiters blocks until at least want handlers are queued on the scheduler.
+func waitForWaiters(t *testing.T, scheduler *HandlerScheduler, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if queued(scheduler) >= want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("waiter did not queue")
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"strings"
+	"sync"
+)
+
+const (
+	// orderTagsKey and orderAttributesKey hold order attribution in persisted order and
+	// execution metadata. The orders and executions endpoints filter on orderTagsKey.
+	orderTagsKey       = "tags"
+	orderAttributesKey = "attributes"
+	// parentAlgoIDKey attributes algo child orders to their parent order.
+	parentAlgoIDKey = "parentAlgoId"
+)
+
+// OrderLabels attaches attribution tags and free-form metadata to an order.
+type OrderLabels struct {
+	Tags     []string
+	Metadata map[string]string
+}
+
+// normalized trims tags and metadata keys and drops empty and duplicate entries. Tags keep
+// their submission order.
+func (o OrderLabels) normalized() OrderLabels {
+	out := OrderLabels{Tags: nil, Metadata: nil}
+	seen := make(map[string]struct{}, len(o.Tags))
+	for _, tag := range o.Tags {
+		trimmed := strings.TrimSpace(tag)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		out.Tags = append(out.Tags, trimmed)
+	}
+	for key, value := range o.Metadata {
+		trimmed := strings.TrimSpace(key)
+		if trimmed == "" {
+			continue
+		}
+		if out.Metadata == nil {
+			out.Metadata = make(map[string]string, len(o.Metadata))
+		}
+		out.Metadata[trimmed] = value
+	}
+	return out
+}
+
+func (o OrderLabels) empty() bool {
+	return len(o.Tags) == 0 && len(o.Metadata) == 0
+}
+
+// apply writes the labels into persisted metadata.
+func (o OrderLabels) apply(meta map[string]any) {
+	if len(o.Tags) > 0 {
+		tags := make([]string, len(o.Tags))
+		copy(tags, o.Tags)
+		meta[orderTagsKey] = tags
+	}
+	if len(o.Metadata) > 0 {
+		attrs := make(map[string]string, len(o.Metadata))
+		for key, value := range o.Metadata {
+			attrs[key] = value
+		}
+		meta[orderAttributesKey] = attrs
+	}
+}
+
+// orderLabelBook remembers the labels of in-flight orders so their executions echo them.
+type orderLabelBook struct {
+	mu      sync.Mutex
+	byOrder map[string]OrderLabels
+}
+
+func newOrderLabelBook() *orderLabelBook {
+	return &orderLabelBook{mu: sync.Mutex{}, byOrder: make(map[string]OrderLabels)}
+}
+
+func (b *orderLabelBook) mark(id string, labels OrderLabels) {
+	if labels.empty() {
+		return
+	}
+	b.mu.Lock()
+	b.byOrder[id] = labels
+	b.mu.Unlock()
+}
+
+func (b *orderLabelBook) lookup(id string) (OrderLabels, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	labels, ok := b.byOrder[id]
+	return labels, ok
+}
+
+func (b *orderLabelBook) forget(id string) {
+	b.mu.Lock()
+	delete(b.byOrder, id)
+	b.mu.Unlock()
+}
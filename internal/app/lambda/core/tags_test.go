@@ -0,0 +1,149 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+type recordingOrderStore struct {
+	mu         sync.Mutex
+	orders     []orderstore.Order
+	updates    []orderstore.OrderUpdate
+	executions []orderstore.Execution
+}
+
+func (s *recordingOrderStore) CreateOrder(_ context.Context, order orderstore.Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders = append(s.orders, order)
+	return nil
+}
+
+func (s *recordingOrderStore) UpdateOrder(_ context.Context, update orderstore.OrderUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updates = append(s.updates, update)
+	return nil
+}
+
+func (s *recordingOrderStore) RecordExecution(_ context.Context, execution orderstore.Execution) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.executions = append(s.executions, execution)
+	return nil
+}
+
+func (s *recordingOrderStore) UpsertBalance(context.Context, orderstore.BalanceSnapshot) error {
+	return nil
+}
+
+func (s *recordingOrderStore) WithTransaction(ctx context.Context, fn func(context.Context, orderstore.Tx) error) error {
+	return fn(ctx, s)
+}
+
+func (s *recordingOrderStore) ListOrders(context.Context, orderstore.OrderQuery) ([]orderstore.OrderRecord, error) {
+	return nil, nil
+}
+
+func (s *recordingOrderStore) ListExecutions(context.Context, orderstore.ExecutionQuery) ([]orderstore.ExecutionRecord, error) {
+	return nil, nil
+}
+
+func (s *recordingOrderStore) ListBalances(context.Context, orderstore.BalanceQuery) ([]orderstore.BalanceRecord, error) {
+	return nil, nil
+}
+
+func TestTaggedOrderLabelsPersistedAndEchoedOnExecutions(t *testing.T) {
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("OrderRequest", 4, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	submitter := &captureSubmitter{}
+	store := &recordingOrderStore{}
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+	}
+	lambda := NewBaseLambda("lambda-tags", cfg, nil, submitter, poolMgr, nil, nil, store)
+
+	price := "100"
+	labels := OrderLabels{
+		Tags:     []string{" breakout ", "breakout", "", "leg-1"},
+		Metadata: map[string]string{"signal": "breakout-4h", " ": "dropped"},
+	}
+	if err := lambda.SubmitTaggedOrder(context.Background(), "binance", schema.TradeSideBuy, "1", &price, labels); err != nil {
+		t.Fatalf("SubmitTaggedOrder: %v", err)
+	}
+	if len(submitter.orders) != 1 {
+		t.Fatalf("expected 1 submitted order, got %d", len(submitter.orders))
+	}
+	req := submitter.orders[0]
+	wantTags := []string{"breakout", "leg-1"}
+	wantMeta := map[string]string{"signal": "breakout-4h"}
+	if !reflect.DeepEqual(req.Tags, wantTags) || !reflect.DeepEqual(req.Metadata, wantMeta) {
+		t.Fatalf("submitted labels = %v %v", req.Tags, req.Metadata)
+	}
+	if len(store.orders) != 1 {
+		t.Fatalf("expected 1 persisted order, got %d", len(store.orders))
+	}
+	persisted := store.orders[0].Metadata
+	if !reflect.DeepEqual(persisted["tags"], wantTags) || !reflect.DeepEqual(persisted["attributes"], wantMeta) {
+		t.Fatalf("persisted metadata = %v", persisted)
+	}
+
+	evt := &schema.Event{
+		EventID:  "exec-1",
+		Type:     schema.EventTypeExecReport,
+		Provider: "binance",
+		Symbol:   "BTC-USDT",
+		Payload: schema.ExecReportPayload{
+			ClientOrderID:  req.ClientOrderID,
+			State:          schema.ExecReportStateFILLED,
+			FilledQuantity: "1",
+			RemainingQty:   "0",
+			AvgFillPrice:   "100",
+			Timestamp:      time.Now(),
+		},
+	}
+	lambda.handleExecReport(context.Background(), evt)
+	if len(store.executions) != 1 {
+		t.Fatalf("expected 1 execution, got %d", len(store.executions))
+	}
+	execMeta := store.executions[0].Metadata
+	if !reflect.DeepEqual(execMeta["tags"], wantTags) || !reflect.DeepEqual(execMeta["attributes"], wantMeta) {
+		t.Fatalf("execution metadata = %v", execMeta)
+	}
+	if _, ok := lambda.labels.lookup(req.ClientOrderID); ok {
+		t.Fatal("labels should be forgotten once the order completes")
+	}
+}
+
+func TestUntaggedOrderCarriesNoLabels(t *testing.T) {
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("OrderRequest", 4, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	submitter := &captureSubmitter{}
+	store := &recordingOrderStore{}
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+	}
+	lambda := NewBaseLambda("lambda-plain", cfg, nil, submitter, poolMgr, nil, nil, store)
+	if err := lambda.SubmitMarketOrder(context.Background(), "binance", schema.TradeSideSell, "1"); err != nil {
+		t.Fatalf("SubmitMarketOrder: %v", err)
+	}
+	if req := submitter.orders[0]; req.Tags != nil || req.Metadata != nil {
+		t.Fatalf("unexpected labels %v %v", req.Tags, req.Metadata)
+	}
+	if _, ok := store.orders[0].Metadata["tags"]; ok {
+		t.Fatalf("unexpected tags in %v", store.orders[0].Metadata)
+	}
+}
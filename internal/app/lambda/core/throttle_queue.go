@@ -0,0 +1,360 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+const (
+	// DefaultThrottleQueueDepth bounds the throttle queue when no depth is configured.
+	DefaultThrottleQueueDepth = 32
+	// DefaultThrottleQueueExpiry is how long an order may wait in the throttle queue by default.
+	DefaultThrottleQueueExpiry = 30 * time.Second
+)
+
+var (
+	// ErrOrderQueued is returned by the order helpers when the risk throttle is exhausted and the
+	// order was parked in the throttle queue instead of being rejected. The order keeps its client
+	// order ID; a QueuedOrderEvent reports whether it was eventually submitted.
+	ErrOrderQueued = errors.New("order queued by risk throttle")
+	// ErrThrottleQueueFull is returned when the risk throttle is exhausted and the queue is full.
+	ErrThrottleQueueFull = errors.New("throttle queue full")
+	// ErrQueuedOrderNotFound is returned when cancelling an order that is not in the queue.
+	ErrQueuedOrderNotFound = errors.New("queued order not found")
+)
+
+// ThrottleQueueConfig lets a lambda park orders that exceed the risk throttle instead of having
+// them rejected. Queued orders are submitted in arrival order as throttle tokens free up.
+type ThrottleQueueConfig struct {
+	Enabled bool
+	// MaxDepth bounds the number of queued orders. Zero selects DefaultThrottleQueueDepth.
+	MaxDepth int
+	// Expiry drops orders still queued this long after they were parked. Zero selects
+	// DefaultThrottleQueueExpiry.
+	Expiry time.Duration
+}
+
+func (c ThrottleQueueConfig) normalize() ThrottleQueueConfig {
+	if c.MaxDepth <= 0 {
+		c.MaxDepth = DefaultThrottleQueueDepth
+	}
+	if c.Expiry <= 0 {
+		c.Expiry = DefaultThrottleQueueExpiry
+	}
+	return c
+}
+
+// QueuedOrderStatus describes how an order left the throttle queue.
+type QueuedOrderStatus string
+
+const (
+	// QueuedOrderSubmitted means the order passed the risk checks and was sent to the provider.
+	QueuedOrderSubmitted QueuedOrderStatus = "SUBMITTED"
+	// QueuedOrderExpired means the throttle did not free up before the order expired.
+	QueuedOrderExpired QueuedOrderStatus = "EXPIRED"
+	// QueuedOrderCancelled means the order was cancelled while queued or the lambda stopped.
+	QueuedOrderCancelled QueuedOrderStatus = "CANCELLED"
+	// QueuedOrderFailed means a risk check other than the throttle or the submission failed.
+	QueuedOrderFailed QueuedOrderStatus = "FAILED"
+)
+
+// QueuedOrder describes an order waiting in the throttle queue.
+type QueuedOrder struct {
+	ClientOrderID string           `json:"clientOrderId"`
+	Provider      string           `json:"provider"`
+	Symbol        string           `json:"symbol"`
+	Side          schema.TradeSide `json:"side"`
+	OrderType     schema.OrderType `json:"orderType"`
+	Quantity      string           `json:"quantity"`
+	Price         *string          `json:"price,omitempty"`
+	QueuedAt      time.Time        `json:"queuedAt"`
+	ExpiresAt     time.Time        `json:"expiresAt"`
+}
+
+// QueuedOrderEvent is published as an extension event when an order leaves the throttle queue.
+type QueuedOrderEvent struct {
+	LambdaID  string            `json:"lambdaId"`
+	Order     QueuedOrder       `json:"order"`
+	Status    QueuedOrderStatus `json:"status"`
+	Reason    string            `json:"reason,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// ThrottleQueueStatus reports the throttle queue of a lambda.
+type ThrottleQueueStatus struct {
+	Enabled  bool          `json:"enabled"`
+	Depth    int           `json:"depth"`
+	MaxDepth int           `json:"maxDepth"`
+	Expiry   string        `json:"expiry"`
+	Orders   []QueuedOrder `json:"orders"`
+}
+
+// throttleMode selects how placeOrder treats an exhausted risk throttle.
+type throttleMode int
+
+const (
+	// throttleModeWait blocks until throttle tokens are available.
+	throttleModeWait throttleMode = iota
+	// throttleModeQueue parks the order in the throttle queue when one is configured.
+	throttleModeQueue
+	// throttleModeProbe fails fast so the queue drainer can keep the order at the head of the queue.
+	throttleModeProbe
+)
+
+type queuedEntry struct {
+	order  QueuedOrder
+	intent orderIntent
+}
+
+// throttleQueue holds orders in arrival order until the drainer submits or expires them.
+type throttleQueue struct {
+	cfg     ThrottleQueueConfig
+	mu      sync.Mutex
+	entries []queuedEntry
+	wake    chan struct{}
+}
+
+func newThrottleQueue(cfg ThrottleQueueConfig) *throttleQueue {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &throttleQueue{
+		cfg:     cfg.normalize(),
+		mu:      sync.Mutex{},
+		entries: nil,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+func (q *throttleQueue) push(intent orderIntent, now time.Time) (QueuedOrder, error) {
+	order := QueuedOrder{
+		ClientOrderID: intent.clientOrderID,
+		Provider:      intent.provider,
+		Symbol:        intent.symbol,
+		Side:          intent.side,
+		OrderType:     intent.orderType,
+		Quantity:      intent.quantity,
+		Price:         intent.price,
+		QueuedAt:      now.UTC(),
+		ExpiresAt:     now.Add(q.cfg.Expiry).UTC(),
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.entries) >= q.cfg.MaxDepth {
+		return order, fmt.Errorf("%w: %d orders queued", ErrThrottleQueueFull, len(q.entries))
+	}
+	intent.throttle = throttleModeProbe
+	q.entries = append(q.entries, queuedEntry{order: order, intent: intent})
+	q.signal()
+	return order, nil
+}
+
+// pushFront returns an entry the drainer popped but could not submit yet.
+func (q *throttleQueue) pushFront(entry queuedEntry) {
+	q.mu.Lock()
+	q.entries = append([]queuedEntry{entry}, q.entries...)
+	q.mu.Unlock()
+}
+
+func (q *throttleQueue) pop() (queuedEntry, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.entries) == 0 {
+		var empty queuedEntry
+		return empty, false
+	}
+	entry := q.entries[0]
+	q.entries = q.entries[1:]
+	return entry, true
+}
+
+// expire removes the entries that expired at now.
+func (q *throttleQueue) expire(now time.Time) []QueuedOrder {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var expired []QueuedOrder
+	kept := q.entries[:0]
+	for _, entry := range q.entries {
+		if now.Before(entry.order.ExpiresAt) {
+			kept = append(kept, entry)
+			continue
+		}
+		expired = append(expired, entry.order)
+	}
+	q.entries = kept
+	return expired
+}
+
+func (q *throttleQueue) remove(clientOrderID string) (QueuedOrder, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, entry := range q.entries {
+		if entry.order.ClientOrderID != clientOrderID {
+			continue
+		}
+		q.entries = append(q.entries[:i], q.entries[i+1:]...)
+		q.signal()
+		return entry.order, true
+	}
+	var empty QueuedOrder
+	return empty, false
+}
+
+func (q *throttleQueue) drain() []QueuedOrder {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]QueuedOrder, 0, len(q.entries))
+	for _, entry := range q.entries {
+		out = append(out, entry.order)
+	}
+	q.entries = nil
+	return out
+}
+
+func (q *throttleQueue) status() ThrottleQueueStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	orders := make([]QueuedOrder, 0, len(q.entries))
+	for _, entry := range q.entries {
+		orders = append(orders, entry.order)
+	}
+	return ThrottleQueueStatus{
+		Enabled:  true,
+		Depth:    len(q.entries),
+		MaxDepth: q.cfg.MaxDepth,
+		Expiry:   q.cfg.Expiry.String(),
+		Orders:   orders,
+	}
+}
+
+// signal wakes the drainer. Callers hold q.mu.
+func (q *throttleQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// parkOrder queues an intent that tripped the risk throttle.
+func (l *BaseLambda) parkOrder(intent orderIntent) error {
+	order, err := l.queue.push(intent, time.Now())
+	if err != nil {
+		return err
+	}
+	l.logger.Printf("[%s] order %s queued by risk throttle until %s", l.id, order.ClientOrderID, order.ExpiresAt.Format(time.RFC3339))
+	return fmt.Errorf("%w: %s", ErrOrderQueued, order.ClientOrderID)
+}
+
+// drainThrottleQueue submits queued orders in arrival order as the risk throttle refills. Orders
+// still queued when ctx ends are cancelled.
+func (l *BaseLambda) drainThrottleQueue(ctx context.Context) {
+	q := l.queue
+	for {
+		for _, order := range q.expire(time.Now()) {
+			l.emitQueuedOrder(order, QueuedOrderExpired, "throttle did not free up before expiry")
+		}
+		entry, ok := q.pop()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				l.cancelQueuedOrders()
+				return
+			case <-q.wake:
+				continue
+			}
+		}
+		_, err := l.placeOrder(ctx, entry.intent)
+		if retryAfter, throttled := risk.ThrottleRetryAfter(err); throttled {
+			q.pushFront(entry)
+			wait := time.Until(entry.order.ExpiresAt)
+			if retryAfter > 0 && retryAfter < wait {
+				wait = retryAfter
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				l.cancelQueuedOrders()
+				return
+			case <-q.wake:
+				timer.Stop()
+			case <-timer.C:
+			}
+			continue
+		}
+		if err != nil {
+			l.emitQueuedOrder(entry.order, QueuedOrderFailed, err.Error())
+			continue
+		}
+		l.emitQueuedOrder(entry.order, QueuedOrderSubmitted, "")
+	}
+}
+
+func (l *BaseLambda) cancelQueuedOrders() {
+	for _, order := range l.queue.drain() {
+		l.emitQueuedOrder(order, QueuedOrderCancelled, "lambda stopped")
+	}
+}
+
+// ThrottleQueue reports the orders waiting in the lambda's throttle queue.
+func (l *BaseLambda) ThrottleQueue() ThrottleQueueStatus {
+	if l.queue == nil {
+		return ThrottleQueueStatus{Enabled: false, Depth: 0, MaxDepth: 0, Expiry: "", Orders: []QueuedOrder{}}
+	}
+	return l.queue.status()
+}
+
+// CancelQueuedOrder removes an order from the throttle queue before it is submitted.
+func (l *BaseLambda) CancelQueuedOrder(clientOrderID string) (QueuedOrder, error) {
+	clientOrderID = strings.TrimSpace(clientOrderID)
+	var empty QueuedOrder
+	if l.queue == nil {
+		return empty, fmt.Errorf("%w: %s", ErrQueuedOrderNotFound, clientOrderID)
+	}
+	order, ok := l.queue.remove(clientOrderID)
+	if !ok {
+		return empty, fmt.Errorf("%w: %s", ErrQueuedOrderNotFound, clientOrderID)
+	}
+	l.emitQueuedOrder(order, QueuedOrderCancelled, "cancelled while queued")
+	return order, nil
+}
+
+// emitQueuedOrder publishes the outcome of a queued order as an extension event.
+func (l *BaseLambda) emitQueuedOrder(order QueuedOrder, status QueuedOrderStatus, reason string) {
+	l.logger.Printf("[%s] queued order %s %s", l.id, order.ClientOrderID, strings.ToLower(string(status)))
+	if l.bus == nil || l.pools == nil {
+		return
+	}
+	ctx := context.Background()
+	evt, err := l.pools.BorrowEventInst(ctx)
+	if err != nil {
+		l.logger.Printf("[%s] unable to borrow event from pool: %v", l.id, err)
+		return
+	}
+	ts := time.Now().UTC()
+	evt.EventID = fmt.Sprintf("queued-order:%s:%d", order.ClientOrderID, ts.UnixNano())
+	evt.Provider = order.Provider
+	evt.Symbol = order.Symbol
+	evt.Type = schema.ExtensionEventType
+	evt.IngestTS = ts
+	evt.EmitTS = ts
+	evt.Payload = QueuedOrderEvent{
+		LambdaID:  l.id,
+		Order:     order,
+		Status:    status,
+		Reason:    reason,
+		Timestamp: ts,
+	}
+
+	if err := l.bus.Publish(ctx, evt); err != nil {
+		l.logger.Printf("[%s] publish queued order event: %v", l.id, err)
+		l.pools.ReturnEventInst(evt)
+	}
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+type lockedSubmitter struct {
+	mu     sync.Mutex
+	orders []schema.OrderRequest
+}
+
+func (s *lockedSubmitter) SubmitOrder(_ context.Context, req schema.OrderRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders = append(s.orders, req)
+	return nil
+}
+
+func (s *lockedSubmitter) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.orders)
+}
+
+func newQueueTestLambda(t *testing.T, throttle float64, queue ThrottleQueueConfig) (*BaseLambda, *lockedSubmitter, <-chan *schema.Event) {
+	t.Helper()
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("Event", 16, 0, func() any { return new(schema.Event) }); err != nil {
+		t.Fatalf("register event pool: %v", err)
+	}
+	if err := poolMgr.RegisterPool("OrderRequest", 8, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		t.Fatalf("register order pool: %v", err)
+	}
+	bus := eventbus.NewMemoryBus(eventbus.MemoryConfig{
+		BufferSize:    16,
+		FanoutWorkers: 1,
+		Pools:         poolMgr,
+	})
+	t.Cleanup(bus.Close)
+	_, events, err := bus.Subscribe(context.Background(), schema.ExtensionEventType)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	rm := risk.NewManager(risk.Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(1_000_000),
+		OrderThrottle:    throttle,
+		OrderBurst:       1,
+	})
+	submitter := &lockedSubmitter{}
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}},
+		ThrottleQueue:   queue,
+	}
+	lambda := NewBaseLambda("lambda-queue", cfg, bus, submitter, poolMgr, nil, rm, nil)
+	return lambda, submitter, events
+}
+
+func nextQueuedOrderEvent(t *testing.T, events <-chan *schema.Event) QueuedOrderEvent {
+	t.Helper()
+	select {
+	case evt := <-events:
+		payload, ok := evt.Payload.(QueuedOrderEvent)
+		if !ok {
+			t.Fatalf("unexpected payload %T", evt.Payload)
+		}
+		return payload
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for queued order event")
+	}
+	return QueuedOrderEvent{}
+}
+
+func TestThrottleQueueSubmitsParkedOrder(t *testing.T) {
+	lambda, submitter, events := newQueueTestLambda(t, 20, ThrottleQueueConfig{Enabled: true, MaxDepth: 4, Expiry: time.Second})
+	price := "100"
+	ctx := context.Background()
+	if err := lambda.SubmitOrder(ctx, "binance", schema.TradeSideBuy, "1", &price); err != nil {
+		t.Fatalf("first order: %v", err)
+	}
+	err := lambda.SubmitOrder(ctx, "binance", schema.TradeSideBuy, "1", &price)
+	if !errors.Is(err, ErrOrderQueued) {
+		t.Fatalf("expected ErrOrderQueued, got %v", err)
+	}
+	status := lambda.ThrottleQueue()
+	if !status.Enabled || status.Depth != 1 || status.MaxDepth != 4 {
+		t.Fatalf("unexpected queue status %+v", status)
+	}
+	queuedID := status.Orders[0].ClientOrderID
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go lambda.drainThrottleQueue(runCtx)
+
+	event := nextQueuedOrderEvent(t, events)
+	if event.Status != QueuedOrderSubmitted || event.Order.ClientOrderID != queuedID {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if submitter.count() != 2 {
+		t.Fatalf("expected 2 submitted orders, got %d", submitter.count())
+	}
+	if depth := lambda.ThrottleQueue().Depth; depth != 0 {
+		t.Fatalf("expected empty queue, got depth %d", depth)
+	}
+}
+
+func TestThrottleQueueExpiresAndCancels(t *testing.T) {
+	lambda, submitter, events := newQueueTestLambda(t, 0.01, ThrottleQueueConfig{Enabled: true, MaxDepth: 2, Expiry: 50 * time.Millisecond})
+	price := "100"
+	ctx := context.Background()
+	if err := lambda.SubmitOrder(ctx, "binance", schema.TradeSideBuy, "1", &price); err != nil {
+		t.Fatalf("first order: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := lambda.SubmitOrder(ctx, "binance", schema.TradeSideBuy, "1", &price); !errors.Is(err, ErrOrderQueued) {
+			t.Fatalf("expected ErrOrderQueued, got %v", err)
+		}
+	}
+	if err := lambda.SubmitOrder(ctx, "binance", schema.TradeSideBuy, "1", &price); !errors.Is(err, ErrThrottleQueueFull) {
+		t.Fatalf("expected ErrThrottleQueueFull, got %v", err)
+	}
+
+	orders := lambda.ThrottleQueue().Orders
+	if _, err := lambda.CancelQueuedOrder(orders[0].ClientOrderID); err != nil {
+		t.Fatalf("cancel queued order: %v", err)
+	}
+	if event := nextQueuedOrderEvent(t, events); event.Status != QueuedOrderCancelled {
+		t.Fatalf("expected cancelled event, got %+v", event)
+	}
+	if _, err := lambda.CancelQueuedOrder(orders[0].ClientOrderID); !errors.Is(err, ErrQueuedOrderNotFound) {
+		t.Fatalf("expected ErrQueuedOrderNotFound, got %v", err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go lambda.drainThrottleQueue(runCtx)
+
+	event := nextQueuedOrderEvent(t, events)
+	if event.Status != QueuedOrderExpired || event.Order.ClientOrderID != orders[1].ClientOrderID {
+		t.Fatalf("expected expired event for %s, got %+v", orders[1].ClientOrderID, event)
+	}
+	if submitter.count() != 1 {
+		t.Fatalf("expired order must not be submitted, got %d orders", submitter.count())
+	}
+}
+
+func TestThrottleQueueDisabled(t *testing.T) {
+	lambda, _, _ := newQueueTestLambda(t, 20, ThrottleQueueConfig{})
+	if status := lambda.ThrottleQueue(); status.Enabled || len(status.Orders) != 0 {
+		t.Fatalf("unexpected queue status %+v", status)
+	}
+	if _, err := lambda.CancelQueuedOrder("missing"); !errors.Is(err, ErrQueuedOrderNotFound) {
+		t.Fatalf("expected ErrQueuedOrderNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// executionTraces builds strategy execution traces. A sampled event opens a trace whose root span
+// starts at the event's ingestion, so the gap before the handler span is the bus and delivery
+// delay. Orders submitted by the handler become child spans, and the execution reports of those
+// orders, matched by client order ID, continue the same trace until the order finishes.
+type executionTraces struct {
+	tracer trace.Tracer
+
+	mu      sync.Mutex
+	byOrder map[string]trace.SpanContext
+}
+
+func newExecutionTraces() *executionTraces {
+	return &executionTraces{
+		tracer:  otel.Tracer("lambda.core"),
+		mu:      sync.Mutex{},
+		byOrder: make(map[string]trace.SpanContext),
+	}
+}
+
+// startEvent opens the spans of one handled event and returns the context handlers run under and
+// the function ending the spans. Sampling is left to the tracer provider.
+func (t *executionTraces) startEvent(ctx context.Context, lambdaID string, evt *schema.Event) (context.Context, func()) {
+	attrs := []attribute.KeyValue{
+		telemetry.AttrLambdaID.String(lambdaID),
+		telemetry.AttrEventID.String(evt.EventID),
+		telemetry.AttrEventType.String(string(evt.Type)),
+		telemetry.AttrProvider.String(evt.Provider),
+		telemetry.AttrSymbol.String(evt.Symbol),
+	}
+	if payload, ok := evt.Payload.(schema.ExecReportPayload); ok {
+		if parent, traced := t.order(payload.ClientOrderID); traced {
+			attrs = append(attrs,
+				telemetry.AttrClientOrderID.String(payload.ClientOrderID),
+				telemetry.AttrOrderState.String(string(payload.State)))
+			ctx, span := t.tracer.Start(trace.ContextWithSpanContext(ctx, parent), "order.exec_report",
+				trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(attrs...))
+			return ctx, func() { span.End() }
+		}
+	}
+
+	opts := []trace.SpanStartOption{trace.WithNewRoot(), trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(attrs...)}
+	if !evt.IngestTS.IsZero() {
+		opts = append(opts, trace.WithTimestamp(evt.IngestTS))
+	}
+	ctx, root := t.tracer.Start(ctx, "event.ingest", opts...)
+	if !root.SpanContext().IsSampled() {
+		return ctx, func() { root.End() }
+	}
+	ctx, handler := t.tracer.Start(ctx, "strategy.handle")
+	return ctx, func() {
+		handler.End()
+		root.End()
+	}
+}
+
+// startOrder opens the span of an order submission, a child of the handler span in ctx.
+func (t *executionTraces) startOrder(ctx context.Context, lambdaID string, req *schema.OrderRequest) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, "order.submit",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			telemetry.AttrLambdaID.String(lambdaID),
+			telemetry.AttrClientOrderID.String(req.ClientOrderID),
+			telemetry.AttrProvider.String(req.Provider),
+			telemetry.AttrSymbol.String(req.Symbol),
+			telemetry.AttrOrderSide.String(string(req.Side)),
+			telemetry.AttrOrderType.String(string(req.OrderType)),
+		))
+}
+
+// finishOrder ends the submission span. Sampled orders that reached the venue are remembered so
+// their execution reports join the trace.
+func (t *executionTraces) finishOrder(span trace.Span, clientOrderID string, err error) {
+	defer span.End()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	if sc := span.SpanContext(); sc.IsSampled() {
+		t.mu.Lock()
+		t.byOrder[clientOrderID] = sc
+		t.mu.Unlock()
+	}
+}
+
+func (t *executionTraces) order(clientOrderID string) (trace.SpanContext, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sc, ok := t.byOrder[clientOrderID]
+	return sc, ok
+}
+
+// forget drops a finished order.
+func (t *executionTraces) forget(clientOrderID string) {
+	t.mu.Lock()
+	delete(t.byOrder, clientOrderID)
+	t.mu.Unlock()
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func newRecordedTraces(sampler sdktrace.Sampler) (*executionTraces, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder))
+	traces := newExecutionTraces()
+	traces.tracer = tp.Tracer("test")
+	return traces, recorder
+}
+
+func TestExecutionTracesLinkEventOrderAndFill(t *testing.T) {
+	traces, recorder := newRecordedTraces(sdktrace.AlwaysSample())
+	ingested := time.Now().Add(-5 * time.Millisecond)
+	trade := &schema.Event{EventID: "evt-1", Type: schema.EventTypeTrade, Provider: "binance", Symbol: "BTC-USDT", IngestTS: ingested}
+
+	ctx, end := traces.startEvent(context.Background(), "lambda-1", trade)
+	submitCtx, span := traces.startOrder(ctx, "lambda-1", &schema.OrderRequest{ClientOrderID: "ord-1", Provider: "binance", Symbol: "BTC-USDT"})
+	if trace.SpanContextFromContext(submitCtx).TraceID() != trace.SpanContextFromContext(ctx).TraceID() {
+		t.Fatal("expected the order span in the event trace")
+	}
+	traces.finishOrder(span, "ord-1", nil)
+	end()
+
+	fill := &schema.Event{EventID: "evt-2", Type: schema.EventTypeExecReport, Provider: "binance", Payload: schema.ExecReportPayload{ClientOrderID: "ord-1", State: schema.ExecReportStateFILLED}}
+	_, endFill := traces.startEvent(context.Background(), "lambda-1", fill)
+	endFill()
+	traces.forget("ord-1")
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("expected 4 spans, got %d", len(spans))
+	}
+	byName := make(map[string]sdktrace.ReadOnlySpan, len(spans))
+	for _, s := range spans {
+		byName[s.Name()] = s
+	}
+	root := byName["event.ingest"]
+	if root == nil || !root.StartTime().Equal(ingested) {
+		t.Fatalf("expected the root span to start at ingestion")
+	}
+	exec := byName["order.exec_report"]
+	if exec == nil || exec.SpanContext().TraceID() != root.SpanContext().TraceID() {
+		t.Fatal("expected the execution report in the event trace")
+	}
+	if exec.Parent().SpanID() != byName["order.submit"].SpanContext().SpanID() {
+		t.Fatal("expected the execution report span under the order span")
+	}
+	if _, traced := traces.order("ord-1"); traced {
+		t.Fatal("expected the finished order forgotten")
+	}
+}
+
+func TestExecutionTracesSkipUnsampledAndFailedOrders(t *testing.T) {
+	traces, recorder := newRecordedTraces(sdktrace.NeverSample())
+	_, end := traces.startEvent(context.Background(), "lambda-1", &schema.Event{Type: schema.EventTypeTicker})
+	end()
+	if len(recorder.Ended()) != 0 {
+		t.Fatal("expected no spans for an unsampled event")
+	}
+
+	traces, _ = newRecordedTraces(sdktrace.AlwaysSample())
+	_, span := traces.startOrder(context.Background(), "lambda-1", &schema.OrderRequest{ClientOrderID: "ord-2"})
+	traces.finishOrder(span, "ord-2", errors.New("venue down"))
+	if _, traced := traces.order("ord-2"); traced {
+		t.Fatal("expected a failed submission not to be tracked")
+	}
+}
@@ -0,0 +1,40 @@
+package core
+
+import "time"
+
+// Usage is the resource usage of a lambda since it was created.
+type Usage struct {
+	// Events counts the events handed to the strategy.
+	Events int64
+	// Orders counts the orders submitted to providers.
+	Orders int64
+	// HandlerTime is the time spent handling the events, strategy callbacks included.
+	HandlerTime time.Duration
+}
+
+// Usage reports the resource usage of the lambda, for metering shared gateways.
+func (l *BaseLambda) Usage() Usage {
+	var events int64
+	for _, stat := range l.deliveries.snapshot() {
+		events += stat.Delivered
+	}
+	return Usage{
+		Events:      events,
+		Orders:      l.orderCount.Load(),
+		HandlerTime: time.Duration(l.handlerNanos.Load()),
+	}
+}
+
+// Sub returns the usage accumulated since earlier.
+func (u Usage) Sub(earlier Usage) Usage {
+	return Usage{
+		Events:      u.Events - earlier.Events,
+		Orders:      u.Orders - earlier.Orders,
+		HandlerTime: u.HandlerTime - earlier.HandlerTime,
+	}
+}
+
+// IsZero reports whether no usage was recorded.
+func (u Usage) IsZero() bool {
+	return u.Events == 0 && u.Orders == 0 && u.HandlerTime == 0
+}
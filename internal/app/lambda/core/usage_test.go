@@ -0,0 +1,27 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestUsageCountsDeliveredEvents(t *testing.T) {
+	cfg := Config{Providers: []string{"binance"}, ProviderSymbols: map[string][]string{"binance": {"BTC-USDT"}}}
+	lambda := NewBaseLambda("usage", cfg, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+	trade := schema.TradePayload{Price: "100", Quantity: "1", Side: schema.TradeSideBuy}
+	lambda.handleEvent(ctx, schema.EventTypeTrade, &schema.Event{Type: schema.EventTypeTrade, Provider: "binance", Symbol: "BTC-USDT", Payload: trade})
+	first := lambda.Usage()
+	lambda.handleEvent(ctx, schema.EventTypeTrade, &schema.Event{Type: schema.EventTypeTrade, Provider: "binance", Symbol: "BTC-USDT", Payload: trade})
+	lambda.handleEvent(ctx, schema.EventTypeTrade, &schema.Event{Type: schema.EventTypeTrade, Provider: "binance", Symbol: "ETH-USDT", Payload: trade})
+
+	usage := lambda.Usage()
+	if usage.Events != 2 || usage.Orders != 0 || usage.HandlerTime < first.HandlerTime {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+	if delta := usage.Sub(first); delta.Events != 1 || delta.IsZero() {
+		t.Fatalf("unexpected delta %+v", delta)
+	}
+}
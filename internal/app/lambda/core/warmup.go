@@ -0,0 +1,190 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// warmupPollInterval is how often start preconditions are re-evaluated while warming up.
+const warmupPollInterval = 250 * time.Millisecond
+
+// ErrWarmingUp is returned by the order helpers while the lambda waits for its start preconditions.
+var ErrWarmingUp = errors.New("instance warming up: start preconditions not met")
+
+// WarmupConfig holds trading back after Start until the lambda has the data it needs. Events are
+// delivered to the strategy while warming up; only order submission is refused.
+type WarmupConfig struct {
+	// MarketDataWithin requires a ticker or book update for every subscribed symbol no older than
+	// this. Zero disables the check.
+	MarketDataWithin time.Duration
+	// RequireBalances waits for a balance update from every provider.
+	RequireBalances bool
+	// ProviderRunning, when set, must report every provider as running.
+	ProviderRunning func(provider string) bool
+}
+
+func (c WarmupConfig) enabled() bool {
+	return c.MarketDataWithin > 0 || c.RequireBalances || c.ProviderRunning != nil
+}
+
+// WarmupState reports whether a lambda may trade.
+type WarmupState string
+
+const (
+	// WarmupStateWarming means at least one start precondition is not met yet.
+	WarmupStateWarming WarmupState = "warming"
+	// WarmupStateReady means trading is enabled.
+	WarmupStateReady WarmupState = "ready"
+)
+
+// WarmupStatus describes the start preconditions of a lambda. Pending lists the unmet ones.
+type WarmupStatus struct {
+	State   WarmupState `json:"state"`
+	Since   time.Time   `json:"since"`
+	Pending []string    `json:"pending,omitempty"`
+}
+
+// warmup tracks the start preconditions. Once they are met the lambda stays ready for the rest of
+// its run, even if market data later goes stale.
+type warmup struct {
+	cfg   WarmupConfig
+	ready atomic.Bool
+
+	mu         sync.Mutex
+	since      time.Time
+	marketData map[string]time.Time
+	balances   map[string]struct{}
+}
+
+func newWarmup(cfg WarmupConfig) *warmup {
+	if !cfg.enabled() {
+		return nil
+	}
+	return &warmup{
+		cfg:        cfg,
+		ready:      atomic.Bool{},
+		mu:         sync.Mutex{},
+		since:      time.Time{},
+		marketData: make(map[string]time.Time),
+		balances:   make(map[string]struct{}),
+	}
+}
+
+func (w *warmup) observeMarketData(provider, symbol string, now time.Time) {
+	if w == nil || w.ready.Load() {
+		return
+	}
+	w.mu.Lock()
+	w.marketData[tapeKey(provider, symbol)] = now
+	w.mu.Unlock()
+}
+
+func (w *warmup) observeBalance(provider string) {
+	if w == nil || w.ready.Load() {
+		return
+	}
+	w.mu.Lock()
+	w.balances[provider] = struct{}{}
+	w.mu.Unlock()
+}
+
+// pending lists the preconditions not met at now for the given providers and their symbols.
+func (w *warmup) pending(providers []string, symbols map[string]map[string]struct{}, now time.Time) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []string
+	for _, provider := range providers {
+		if w.cfg.ProviderRunning != nil && !w.cfg.ProviderRunning(provider) {
+			out = append(out, fmt.Sprintf("provider %s not running", provider))
+		}
+		if w.cfg.RequireBalances {
+			if _, ok := w.balances[provider]; !ok {
+				out = append(out, fmt.Sprintf("no balances from %s", provider))
+			}
+		}
+		if w.cfg.MarketDataWithin <= 0 {
+			continue
+		}
+		names := make([]string, 0, len(symbols[provider]))
+		for symbol := range symbols[provider] {
+			names = append(names, symbol)
+		}
+		sort.Strings(names)
+		for _, symbol := range names {
+			seen, ok := w.marketData[tapeKey(provider, symbol)]
+			if !ok || now.Sub(seen) > w.cfg.MarketDataWithin {
+				out = append(out, fmt.Sprintf("no market data for %s %s within %s", provider, symbol, w.cfg.MarketDataWithin))
+			}
+		}
+	}
+	return out
+}
+
+// watchWarmup re-evaluates the start preconditions until they are met or ctx ends.
+func (l *BaseLambda) watchWarmup(ctx context.Context) {
+	ticker := time.NewTicker(warmupPollInterval)
+	defer ticker.Stop()
+	for !l.checkWarmup(time.Now()) {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkWarmup marks the lambda ready when every precondition holds at now and reports whether it is.
+func (l *BaseLambda) checkWarmup(now time.Time) bool {
+	w := l.warmup
+	if w == nil || w.ready.Load() {
+		return true
+	}
+	if len(w.pending(l.config.Providers, l.providerSymbols, now)) > 0 {
+		return false
+	}
+	w.mu.Lock()
+	started := w.since
+	w.since = now
+	w.mu.Unlock()
+	w.ready.Store(true)
+	l.logger.Printf("[%s] start preconditions met after %s; trading enabled", l.id, now.Sub(started).Round(time.Millisecond))
+	return true
+}
+
+// IsWarmingUp reports whether the lambda is still waiting for its start preconditions.
+func (l *BaseLambda) IsWarmingUp() bool {
+	return l.warmup != nil && !l.warmup.ready.Load()
+}
+
+// Warmup reports the start preconditions of the lambda. Lambdas without preconditions are ready.
+func (l *BaseLambda) Warmup() WarmupStatus {
+	w := l.warmup
+	if w == nil {
+		return WarmupStatus{State: WarmupStateReady, Since: time.Time{}, Pending: nil}
+	}
+	if w.ready.Load() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return WarmupStatus{State: WarmupStateReady, Since: w.since, Pending: nil}
+	}
+	pending := w.pending(l.config.Providers, l.providerSymbols, time.Now())
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return WarmupStatus{State: WarmupStateWarming, Since: w.since, Pending: pending}
+}
+
+func (l *BaseLambda) startWarmup(ctx context.Context) {
+	if l.warmup == nil {
+		return
+	}
+	l.warmup.mu.Lock()
+	l.warmup.since = time.Now()
+	l.warmup.mu.Unlock()
+	l.logger.Printf("[%s] warming up until start preconditions are met", l.id)
+	go l.watchWarmup(ctx)
+}
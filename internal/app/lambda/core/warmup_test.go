@@ -0,0 +1,62 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestBaseLambdaHoldsTradingUntilPreconditionsMet(t *testing.T) {
+	var running atomic.Bool
+	cfg := Config{
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string][]string{"binance": {"BTC-USDT", "ETH-USDT"}},
+		Warmup: WarmupConfig{
+			MarketDataWithin: time.Minute,
+			RequireBalances:  true,
+			ProviderRunning:  func(string) bool { return running.Load() },
+		},
+	}
+	lambda := NewBaseLambda("lambda-warmup", cfg, nil, nil, nil, &testExtensionStrategy{}, nil, nil)
+
+	status := lambda.Warmup()
+	if status.State != WarmupStateWarming || len(status.Pending) != 4 {
+		t.Fatalf("expected four pending preconditions, got %+v", status)
+	}
+	if err := lambda.SubmitOrder(context.Background(), "binance", schema.TradeSideBuy, "1", nil); !errors.Is(err, ErrWarmingUp) {
+		t.Fatalf("expected ErrWarmingUp, got %v", err)
+	}
+
+	ctx := context.Background()
+	lambda.handleTicker(ctx, &schema.Event{Provider: "binance", Symbol: "BTC-USDT", Payload: schema.TickerPayload{LastPrice: "100"}})
+	lambda.handleBookSnapshot(ctx, &schema.Event{Provider: "binance", Symbol: "ETH-USDT", Payload: schema.BookSnapshotPayload{}})
+	lambda.handleBalanceUpdate(ctx, &schema.Event{Provider: "binance", Payload: schema.BalanceUpdatePayload{Currency: "USDT", Total: "1", Available: "1"}})
+	if lambda.checkWarmup(time.Now()) {
+		t.Fatal("expected the stopped provider to keep the lambda warming")
+	}
+	if pending := lambda.Warmup().Pending; len(pending) != 1 || pending[0] != "provider binance not running" {
+		t.Fatalf("unexpected pending preconditions %v", pending)
+	}
+
+	running.Store(true)
+	if lambda.checkWarmup(time.Now().Add(2 * time.Minute)) {
+		t.Fatal("expected stale market data to keep the lambda warming")
+	}
+	if !lambda.checkWarmup(time.Now()) || lambda.IsWarmingUp() {
+		t.Fatal("expected the lambda to become ready")
+	}
+	if status := lambda.Warmup(); status.State != WarmupStateReady || len(status.Pending) != 0 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestBaseLambdaWithoutPreconditionsIsReady(t *testing.T) {
+	lambda := NewBaseLambda("lambda-ready", Config{Providers: []string{"binance"}}, nil, nil, nil, &testExtensionStrategy{}, nil, nil)
+	if lambda.IsWarmingUp() || lambda.Warmup().State != WarmupStateReady {
+		t.Fatalf("expected a lambda without preconditions to be ready, got %+v", lambda.Warmup())
+	}
+}
@@ -0,0 +1,493 @@
+package js
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+)
+
+const (
+	// ArchiveFormatVersion identifies the layout of strategy registry archives.
+	ArchiveFormatVersion = 1
+	// MaxArchiveBytes bounds the uncompressed size of an imported archive.
+	MaxArchiveBytes = 256 << 20
+
+	archiveManifestName  = "manifest.json"
+	archiveSignatureName = "manifest.sig"
+	archiveRegistryName  = "registry.json"
+)
+
+var (
+	// ErrArchiveKeyRequired reports that no signing key is configured for registry archives.
+	ErrArchiveKeyRequired = errors.New("strategy archive: signing key not configured")
+	// ErrArchiveSignature reports an archive whose manifest signature does not verify.
+	ErrArchiveSignature = errors.New("strategy archive: signature mismatch")
+	// ErrArchiveConflict reports tags that point to different revisions here and in the archive.
+	ErrArchiveConflict = errors.New("strategy archive: conflicting tags")
+)
+
+// ArchiveConflictPolicy decides how an import treats tags that already point to another revision.
+type ArchiveConflictPolicy string
+
+const (
+	// ArchiveConflictFail aborts the import when any tag conflicts.
+	ArchiveConflictFail ArchiveConflictPolicy = "fail"
+	// ArchiveConflictKeep keeps the local tag and imports everything else.
+	ArchiveConflictKeep ArchiveConflictPolicy = "keep"
+	// ArchiveConflictOverwrite moves conflicting tags to the archive's revision.
+	ArchiveConflictOverwrite ArchiveConflictPolicy = "overwrite"
+	// ArchiveConflictReplace makes the archive's registry the whole catalog; local revisions
+	// missing from it are unregistered.
+	ArchiveConflictReplace ArchiveConflictPolicy = "replace"
+)
+
+// ParseArchiveConflictPolicy validates a conflict policy, defaulting to fail.
+func ParseArchiveConflictPolicy(raw string) (ArchiveConflictPolicy, error) {
+	switch policy := ArchiveConflictPolicy(strings.ToLower(strings.TrimSpace(raw))); policy {
+	case "":
+		return ArchiveConflictFail, nil
+	case ArchiveConflictFail, ArchiveConflictKeep, ArchiveConflictOverwrite, ArchiveConflictReplace:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("strategy archive: unknown conflict policy %q", raw)
+	}
+}
+
+// ArchiveManifest lists the files of a registry archive with their digests. The manifest is
+// signed, so verifying it covers every file.
+type ArchiveManifest struct {
+	Version   int           `json:"version"`
+	CreatedAt time.Time     `json:"createdAt"`
+	Modules   int           `json:"modules"`
+	Revisions int           `json:"revisions"`
+	Files     []ArchiveFile `json:"files"`
+}
+
+// ArchiveFile records one file of a registry archive.
+type ArchiveFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// ArchiveTagChange describes a tag moved, added or held back by an import.
+type ArchiveTagChange struct {
+	Strategy string `json:"strategy"`
+	Tag      string `json:"tag"`
+	Previous string `json:"previous,omitempty"`
+	Hash     string `json:"hash"`
+}
+
+// ArchiveImportResult summarises what an import changes. Revisions are reported as name@hash.
+type ArchiveImportResult struct {
+	Policy            ArchiveConflictPolicy `json:"policy"`
+	CreatedAt         time.Time             `json:"createdAt"`
+	Modules           int                   `json:"modules"`
+	AddedRevisions    []string              `json:"addedRevisions"`
+	ExistingRevisions int                   `json:"existingRevisions"`
+	TagChanges        []ArchiveTagChange    `json:"tagChanges"`
+	Conflicts         []ArchiveTagChange    `json:"conflicts"`
+	RemovedRevisions  []string              `json:"removedRevisions,omitempty"`
+}
+
+// ArchiveImport is a verified archive merged with the local registry, ready to be applied.
+type ArchiveImport struct {
+	Manifest ArchiveManifest
+	Result   ArchiveImportResult
+	registry registry
+	files    map[string][]byte
+}
+
+// ExportArchive writes the registry and every registered revision as a gzip-compressed tarball.
+// The archive carries a manifest of file digests signed with HMAC-SHA256 under key.
+func (l *Loader) ExportArchive(w io.Writer, key []byte, now time.Time) (ArchiveManifest, error) {
+	var empty ArchiveManifest
+	if l == nil {
+		return empty, fmt.Errorf("strategy loader: nil receiver")
+	}
+	if len(key) == 0 {
+		return empty, ErrArchiveKeyRequired
+	}
+	reg, err := loadRegistry(l.root)
+	if err != nil {
+		return empty, fmt.Errorf("strategy archive: load registry: %w", err)
+	}
+	registryData, err := json.MarshalIndent(reg, "", "  ")
+	if err != nil {
+		return empty, fmt.Errorf("strategy archive: marshal registry: %w", err)
+	}
+	contents := map[string][]byte{archiveRegistryName: registryData}
+	revisions := 0
+	for name, entry := range reg {
+		for hash, loc := range entry.Hashes {
+			if err := validateRegistryLocation(name, hash, loc.Path); err != nil {
+				return empty, fmt.Errorf("strategy archive: %w", err)
+			}
+			rel := filepath.ToSlash(filepath.Clean(loc.Path))
+			// #nosec G304 -- path validated against the registry layout
+			data, err := os.ReadFile(filepath.Join(l.root, filepath.FromSlash(rel)))
+			if err != nil {
+				return empty, fmt.Errorf("strategy archive: read %s@%s: %w", name, hash, err)
+			}
+			contents[rel] = data
+			revisions++
+		}
+	}
+
+	paths := make([]string, 0, len(contents))
+	for p := range contents {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	manifest := ArchiveManifest{
+		Version:   ArchiveFormatVersion,
+		CreatedAt: now.UTC(),
+		Modules:   len(reg),
+		Revisions: revisions,
+		Files:     make([]ArchiveFile, 0, len(paths)),
+	}
+	for _, p := range paths {
+		manifest.Files = append(manifest.Files, ArchiveFile{Path: p, SHA256: digestHex(contents[p]), Size: int64(len(contents[p]))})
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return empty, fmt.Errorf("strategy archive: marshal manifest: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	write := func(name string, data []byte) error {
+		header := new(tar.Header)
+		header.Typeflag = tar.TypeReg
+		header.Name = name
+		header.Mode = 0o600
+		header.Size = int64(len(data))
+		header.ModTime = manifest.CreatedAt
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("strategy archive: write %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("strategy archive: write %s: %w", name, err)
+		}
+		return nil
+	}
+	if err := write(archiveManifestName, manifestData); err != nil {
+		return empty, err
+	}
+	if err := write(archiveSignatureName, []byte(signManifest(key, manifestData))); err != nil {
+		return empty, err
+	}
+	for _, p := range paths {
+		if err := write(p, contents[p]); err != nil {
+			return empty, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return empty, fmt.Errorf("strategy archive: close tar: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return empty, fmt.Errorf("strategy archive: close gzip: %w", err)
+	}
+	return manifest, nil
+}
+
+// PlanArchiveImport reads and verifies an archive produced by ExportArchive and merges its
+// registry with the local one under policy. Nothing is written until ApplyArchiveImport. Every
+// revision is compiled and checked against its registry hash.
+func (l *Loader) PlanArchiveImport(r io.Reader, key []byte, policy ArchiveConflictPolicy) (*ArchiveImport, error) {
+	if l == nil {
+		return nil, fmt.Errorf("strategy loader: nil receiver")
+	}
+	if len(key) == 0 {
+		return nil, ErrArchiveKeyRequired
+	}
+	entries, err := readArchiveEntries(r)
+	if err != nil {
+		return nil, err
+	}
+	manifestData, ok := entries[archiveManifestName]
+	if !ok {
+		return nil, fmt.Errorf("strategy archive: %s missing", archiveManifestName)
+	}
+	signature := strings.TrimSpace(string(entries[archiveSignatureName]))
+	if !hmac.Equal([]byte(signature), []byte(signManifest(key, manifestData))) {
+		return nil, ErrArchiveSignature
+	}
+	var manifest ArchiveManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("strategy archive: decode manifest: %w", err)
+	}
+	if manifest.Version != ArchiveFormatVersion {
+		return nil, fmt.Errorf("strategy archive: unsupported format version %d", manifest.Version)
+	}
+	listed := make(map[string]struct{}, len(manifest.Files))
+	for _, file := range manifest.Files {
+		data, ok := entries[file.Path]
+		if !ok {
+			return nil, fmt.Errorf("strategy archive: %s listed in manifest but missing", file.Path)
+		}
+		if digestHex(data) != file.SHA256 {
+			return nil, fmt.Errorf("strategy archive: digest mismatch for %s", file.Path)
+		}
+		listed[file.Path] = struct{}{}
+	}
+	for name := range entries {
+		if _, ok := listed[name]; !ok && name != archiveManifestName && name != archiveSignatureName {
+			return nil, fmt.Errorf("strategy archive: %s not listed in manifest", name)
+		}
+	}
+
+	var incoming registry
+	if err := json.Unmarshal(entries[archiveRegistryName], &incoming); err != nil {
+		return nil, fmt.Errorf("strategy archive: decode registry: %w", err)
+	}
+	files := make(map[string][]byte)
+	for name, entry := range incoming {
+		if err := validatePathSegment(name); err != nil || name != strings.ToLower(name) {
+			return nil, fmt.Errorf("strategy archive: invalid strategy name %q", name)
+		}
+		for hash, loc := range entry.Hashes {
+			if err := validateRegistryLocation(name, hash, loc.Path); err != nil {
+				return nil, fmt.Errorf("strategy archive: %w", err)
+			}
+			rel := filepath.ToSlash(filepath.Clean(loc.Path))
+			source, ok := entries[rel]
+			if !ok {
+				return nil, fmt.Errorf("strategy archive: revision %s@%s missing", name, hash)
+			}
+			module, err := compileSource(rel, source, int64(len(source)))
+			if err != nil {
+				return nil, fmt.Errorf("strategy archive: %s@%s: %w", name, hash, err)
+			}
+			if module.Name != name || module.Hash != hash {
+				return nil, fmt.Errorf("strategy archive: revision %s does not match registry entry %s@%s", rel, name, hash)
+			}
+			files[rel] = source
+		}
+		for tag, hash := range entry.Tags {
+			if _, ok := entry.Hashes[hash]; !ok {
+				return nil, fmt.Errorf("strategy archive: tag %s:%s points to unknown revision %s", name, tag, hash)
+			}
+		}
+	}
+
+	current, err := l.currentRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("strategy archive: load registry: %w", err)
+	}
+	plan := &ArchiveImport{
+		Manifest: manifest,
+		Result: ArchiveImportResult{
+			Policy:            policy,
+			CreatedAt:         manifest.CreatedAt,
+			Modules:           len(incoming),
+			AddedRevisions:    []string{},
+			ExistingRevisions: 0,
+			TagChanges:        []ArchiveTagChange{},
+			Conflicts:         []ArchiveTagChange{},
+			RemovedRevisions:  nil,
+		},
+		registry: nil,
+		files:    files,
+	}
+	plan.registry = mergeArchiveRegistry(current, incoming, policy, &plan.Result)
+	return plan, nil
+}
+
+// ApplyArchiveImport writes the revisions of a planned import and then swaps the registry in a
+// single rename, so the loader sees either the old catalog or the new one. Revision files are
+// content-addressed and never overwrite a different revision; unregistered files are left on disk.
+func (l *Loader) ApplyArchiveImport(plan *ArchiveImport) error {
+	if l == nil {
+		return fmt.Errorf("strategy loader: nil receiver")
+	}
+	if plan == nil {
+		return fmt.Errorf("strategy archive: import plan required")
+	}
+	if plan.Result.Policy == ArchiveConflictFail && len(plan.Result.Conflicts) > 0 {
+		return fmt.Errorf("%w: %d tag(s)", ErrArchiveConflict, len(plan.Result.Conflicts))
+	}
+	rels := make([]string, 0, len(plan.files))
+	for rel := range plan.files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+	for _, rel := range rels {
+		if err := l.writeArchiveFile(rel, plan.files[rel]); err != nil {
+			return err
+		}
+	}
+	return l.saveRegistry(plan.registry)
+}
+
+func (l *Loader) writeArchiveFile(rel string, data []byte) error {
+	dest := filepath.Join(l.root, filepath.FromSlash(rel))
+	// #nosec G304 -- path validated against the registry layout
+	if existing, err := os.ReadFile(dest); err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+		return fmt.Errorf("strategy archive: ensure directory for %s: %w", rel, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "import-*.js")
+	if err != nil {
+		return fmt.Errorf("strategy archive: create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("strategy archive: write %s: %w", rel, err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("strategy archive: close %s: %w", rel, err)
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("strategy archive: persist %s: %w", rel, err)
+	}
+	return nil
+}
+
+// mergeArchiveRegistry combines the local and archived registries. Revisions are unioned; tags
+// that point elsewhere locally are resolved by policy and reported in result.
+func mergeArchiveRegistry(current, incoming registry, policy ArchiveConflictPolicy, result *ArchiveImportResult) registry {
+	next := make(registry, len(current)+len(incoming))
+	if policy != ArchiveConflictReplace {
+		for name, entry := range current {
+			next[name] = cloneRegistryEntry(entry)
+		}
+	}
+	names := make([]string, 0, len(incoming))
+	for name := range incoming {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry := incoming[name]
+		local := current[name]
+		merged, ok := next[name]
+		if !ok {
+			merged = registryEntry{Tags: make(map[string]string), Hashes: make(map[string]registryLocation)}
+		}
+		for _, hash := range sortedKeys(entry.Hashes) {
+			if _, exists := local.Hashes[hash]; exists {
+				result.ExistingRevisions++
+			} else {
+				result.AddedRevisions = append(result.AddedRevisions, name+"@"+hash)
+			}
+			merged.Hashes[hash] = entry.Hashes[hash]
+		}
+		for _, tag := range sortedKeys(entry.Tags) {
+			hash := entry.Tags[tag]
+			previous, exists := local.Tags[tag]
+			change := ArchiveTagChange{Strategy: name, Tag: tag, Previous: previous, Hash: hash}
+			switch {
+			case exists && previous == hash:
+				// Replace starts from an empty entry, so unchanged tags are carried over too.
+				merged.Tags[tag] = hash
+			case exists && policy != ArchiveConflictReplace && policy != ArchiveConflictOverwrite:
+				result.Conflicts = append(result.Conflicts, change)
+			default:
+				merged.Tags[tag] = hash
+				result.TagChanges = append(result.TagChanges, change)
+			}
+		}
+		next[name] = merged
+	}
+	if policy == ArchiveConflictReplace {
+		for _, name := range sortedKeys(current) {
+			for _, hash := range sortedKeys(current[name].Hashes) {
+				if _, kept := next[name].Hashes[hash]; !kept {
+					result.RemovedRevisions = append(result.RemovedRevisions, name+"@"+hash)
+				}
+			}
+		}
+	}
+	return next
+}
+
+func readArchiveEntries(r io.Reader) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("strategy archive: open gzip: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+	limited := &io.LimitedReader{R: gz, N: MaxArchiveBytes + 1}
+	tr := tar.NewReader(limited)
+	entries := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("strategy archive: read tar: %w", err)
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("strategy archive: unsupported entry %s", header.Name)
+		}
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("strategy archive: unsafe entry path %s", header.Name)
+		}
+		if _, dup := entries[name]; dup {
+			return nil, fmt.Errorf("strategy archive: duplicate entry %s", name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("strategy archive: read %s: %w", name, err)
+		}
+		if limited.N <= 0 {
+			return nil, fmt.Errorf("strategy archive: archive exceeds %d bytes", MaxArchiveBytes)
+		}
+		entries[name] = data
+	}
+	return entries, nil
+}
+
+func cloneRegistryEntry(entry registryEntry) registryEntry {
+	hashes := make(map[string]registryLocation, len(entry.Hashes))
+	for hash, loc := range entry.Hashes {
+		hashes[hash] = loc
+	}
+	return registryEntry{Tags: cloneStringMap(entry.Tags), Hashes: hashes}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func signManifest(key, manifest []byte) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(manifest)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func digestHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,195 @@
+package js
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var archiveKey = []byte("archive-signing-key")
+
+func newRegistryLoader(t *testing.T, sources ...string) *Loader {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "registry.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write registry stub: %v", err)
+	}
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	for _, source := range sources {
+		if _, err := loader.Store([]byte(source), ModuleWriteOptions{PromoteLatest: true}); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+	if err := loader.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	return loader
+}
+
+func exportArchive(t *testing.T, loader *Loader) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	manifest, err := loader.ExportArchive(&buf, archiveKey, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ExportArchive: %v", err)
+	}
+	if manifest.Modules != 2 || manifest.Revisions != 2 || len(manifest.Files) != 3 {
+		t.Fatalf("unexpected manifest %+v", manifest)
+	}
+	return buf.Bytes()
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	source := newRegistryLoader(t, sampleModule, gridModule)
+	archive := exportArchive(t, source)
+
+	target := newRegistryLoader(t)
+	plan, err := target.PlanArchiveImport(bytes.NewReader(archive), archiveKey, ArchiveConflictFail)
+	if err != nil {
+		t.Fatalf("PlanArchiveImport: %v", err)
+	}
+	if len(plan.Result.AddedRevisions) != 2 || len(plan.Result.Conflicts) != 0 {
+		t.Fatalf("unexpected plan %+v", plan.Result)
+	}
+	if len(target.List()) != 0 {
+		t.Fatal("planning must not change the registry")
+	}
+	if err := target.ApplyArchiveImport(plan); err != nil {
+		t.Fatalf("ApplyArchiveImport: %v", err)
+	}
+	if err := target.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	modules := target.List()
+	if len(modules) != 2 || modules[0].Name != "grid" || modules[1].Name != "noop" {
+		t.Fatalf("unexpected imported modules %+v", modules)
+	}
+	want, _ := source.Module("grid")
+	if modules[0].Hash != want.Hash {
+		t.Fatalf("expected grid %s, got %s", want.Hash, modules[0].Hash)
+	}
+
+	again, err := target.PlanArchiveImport(bytes.NewReader(archive), archiveKey, ArchiveConflictFail)
+	if err != nil {
+		t.Fatalf("re-plan: %v", err)
+	}
+	if len(again.Result.AddedRevisions) != 0 || again.Result.ExistingRevisions != 2 || len(again.Result.TagChanges) != 0 {
+		t.Fatalf("re-importing the same archive must be a no-op, got %+v", again.Result)
+	}
+}
+
+func TestArchiveRejectsBadSignature(t *testing.T) {
+	archive := exportArchive(t, newRegistryLoader(t, sampleModule, gridModule))
+	target := newRegistryLoader(t)
+
+	if _, err := target.PlanArchiveImport(bytes.NewReader(archive), []byte("another-signing-key"), ArchiveConflictFail); !errors.Is(err, ErrArchiveSignature) {
+		t.Fatalf("expected ErrArchiveSignature, got %v", err)
+	}
+	if _, err := target.PlanArchiveImport(bytes.NewReader(archive), nil, ArchiveConflictFail); !errors.Is(err, ErrArchiveKeyRequired) {
+		t.Fatalf("expected ErrArchiveKeyRequired, got %v", err)
+	}
+	if _, err := target.ExportArchive(&bytes.Buffer{}, nil, time.Now()); !errors.Is(err, ErrArchiveKeyRequired) {
+		t.Fatalf("expected ErrArchiveKeyRequired on export, got %v", err)
+	}
+}
+
+func TestArchiveConflictPolicies(t *testing.T) {
+	archive := exportArchive(t, newRegistryLoader(t, sampleModule, gridModule))
+	changed := strings.Replace(sampleModule, `"ok"`, `"changed"`, 1)
+
+	cases := []struct {
+		policy    ArchiveConflictPolicy
+		conflicts int
+		applyErr  error
+		latest    string
+		removed   int
+	}{
+		{policy: ArchiveConflictFail, conflicts: 2, applyErr: ErrArchiveConflict, latest: "local", removed: 0},
+		{policy: ArchiveConflictKeep, conflicts: 2, applyErr: nil, latest: "local", removed: 0},
+		{policy: ArchiveConflictOverwrite, conflicts: 0, applyErr: nil, latest: "archive", removed: 0},
+		{policy: ArchiveConflictReplace, conflicts: 0, applyErr: nil, latest: "archive", removed: 1},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			target := newRegistryLoader(t, changed)
+			local, _ := target.Module("noop")
+			plan, err := target.PlanArchiveImport(bytes.NewReader(archive), archiveKey, tc.policy)
+			if err != nil {
+				t.Fatalf("PlanArchiveImport: %v", err)
+			}
+			if len(plan.Result.Conflicts) != tc.conflicts || len(plan.Result.RemovedRevisions) != tc.removed {
+				t.Fatalf("unexpected plan %+v", plan.Result)
+			}
+			err = target.ApplyArchiveImport(plan)
+			if !errors.Is(err, tc.applyErr) {
+				t.Fatalf("expected %v, got %v", tc.applyErr, err)
+			}
+			if err := target.Refresh(context.Background()); err != nil {
+				t.Fatalf("Refresh: %v", err)
+			}
+			noop, err := target.Module("noop")
+			if err != nil {
+				t.Fatalf("Module: %v", err)
+			}
+			if (noop.Hash == local.Hash) != (tc.latest == "local") {
+				t.Fatalf("expected %s latest revision, got %s", tc.latest, noop.Hash)
+			}
+			if tc.policy == ArchiveConflictReplace && len(noop.Revisions) != 1 {
+				t.Fatalf("replace must drop local revisions, got %+v", noop.Revisions)
+			}
+		})
+	}
+}
+
+func TestArchiveReplaceKeepsUnchangedTags(t *testing.T) {
+	archive := exportArchive(t, newRegistryLoader(t, sampleModule, gridModule))
+	target := newRegistryLoader(t, sampleModule, gridModule)
+	before, err := target.Module("grid")
+	if err != nil {
+		t.Fatalf("Module: %v", err)
+	}
+
+	plan, err := target.PlanArchiveImport(bytes.NewReader(archive), archiveKey, ArchiveConflictReplace)
+	if err != nil {
+		t.Fatalf("PlanArchiveImport: %v", err)
+	}
+	if len(plan.Result.TagChanges) != 0 || len(plan.Result.RemovedRevisions) != 0 {
+		t.Fatalf("replacing with an identical archive must change nothing, got %+v", plan.Result)
+	}
+	if err := target.ApplyArchiveImport(plan); err != nil {
+		t.Fatalf("ApplyArchiveImport: %v", err)
+	}
+	if err := target.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	after, err := target.Module("grid")
+	if err != nil {
+		t.Fatalf("expected grid to survive the replace: %v", err)
+	}
+	if after.Hash != before.Hash || after.TagAliases["latest"] != before.Hash {
+		t.Fatalf("expected latest to stay on %s, got %+v", before.Hash, after.TagAliases)
+	}
+	if len(target.List()) != 2 {
+		t.Fatalf("expected both modules after the replace, got %+v", target.List())
+	}
+}
+
+func TestParseArchiveConflictPolicy(t *testing.T) {
+	if policy, err := ParseArchiveConflictPolicy(""); err != nil || policy != ArchiveConflictFail {
+		t.Fatalf("expected default fail policy, got %q %v", policy, err)
+	}
+	if policy, err := ParseArchiveConflictPolicy(" Overwrite "); err != nil || policy != ArchiveConflictOverwrite {
+		t.Fatalf("expected overwrite, got %q %v", policy, err)
+	}
+	if _, err := ParseArchiveConflictPolicy("merge"); err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
@@ -0,0 +1,100 @@
+package js
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/dop251/goja"
+
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+)
+
+// LogFunc receives a strategy log line with its source (see strategylogstore) and level: debug,
+// info, warn or error.
+type LogFunc func(source, level, message string)
+
+// WithConsole routes console.debug, console.log, console.info, console.warn and console.error
+// to fn. Without it the sandbox console discards everything.
+func WithConsole(fn LogFunc) InstanceOption {
+	return func(rt *goja.Runtime) {
+		if fn == nil {
+			return
+		}
+		_ = rt.Set("console", buildLoggingConsole(rt, fn))
+	}
+}
+
+func buildLoggingConsole(rt *goja.Runtime, fn LogFunc) *goja.Object {
+	console := rt.NewObject()
+	method := func(level string) func(goja.FunctionCall) goja.Value {
+		return func(call goja.FunctionCall) goja.Value {
+			if message := formatConsoleArgs(rt, call.Arguments); message != "" {
+				fn(strategylogstore.SourceConsole, level, message)
+			}
+			return goja.Undefined()
+		}
+	}
+	_ = console.Set("debug", method(strategylogstore.LevelDebug))
+	_ = console.Set("log", method(strategylogstore.LevelInfo))
+	_ = console.Set("info", method(strategylogstore.LevelInfo))
+	_ = console.Set("warn", method(strategylogstore.LevelWarn))
+	_ = console.Set("error", method(strategylogstore.LevelError))
+	return console
+}
+
+// formatConsoleArgs joins console arguments with spaces, rendering plain objects and arrays as
+// JSON the way browser consoles show them.
+func formatConsoleArgs(rt *goja.Runtime, args []goja.Value) string {
+	if len(args) == 0 {
+		return ""
+	}
+	var stringify goja.Callable
+	if jsonObj := rt.Get("JSON"); jsonObj != nil {
+		stringify, _ = goja.AssertFunction(jsonObj.ToObject(rt).Get("stringify"))
+	}
+	var builder strings.Builder
+	for i, arg := range args {
+		if i > 0 {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString(formatConsoleArg(arg, stringify))
+	}
+	return builder.String()
+}
+
+func formatConsoleArg(arg goja.Value, stringify goja.Callable) string {
+	if arg == nil || goja.IsUndefined(arg) {
+		return "undefined"
+	}
+	if obj, ok := arg.(*goja.Object); ok && stringify != nil {
+		if _, callable := goja.AssertFunction(obj); !callable && obj.ClassName() != "Error" {
+			if encoded, err := stringify(goja.Undefined(), obj); err == nil && !goja.IsUndefined(encoded) {
+				return encoded.String()
+			}
+		}
+	}
+	return arg.String()
+}
+
+// logSink forwards strategy log lines to the LogFunc installed by the instance owner. Lines
+// emitted before one is installed are dropped.
+type logSink struct {
+	fn atomic.Pointer[LogFunc]
+}
+
+func (s *logSink) set(fn LogFunc) {
+	if fn == nil {
+		s.fn.Store(nil)
+		return
+	}
+	s.fn.Store(&fn)
+}
+
+func (s *logSink) emit(source, level, message string) {
+	if s == nil {
+		return
+	}
+	if fn := s.fn.Load(); fn != nil {
+		(*fn)(source, level, message)
+	}
+}
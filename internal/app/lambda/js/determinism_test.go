@@ -0,0 +1,80 @@
+package js
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestSeededRandIsReproducible(t *testing.T) {
+	first, second, other := newSeededRand(42), newSeededRand(42), newSeededRand(43)
+	diverged := false
+	for i := 0; i < 16; i++ {
+		a, b, c := first.Float64(), second.Float64(), other.Float64()
+		if a != b {
+			t.Fatalf("draw %d differs for the same seed: %v != %v", i, a, b)
+		}
+		if a < 0 || a >= 1 {
+			t.Fatalf("draw %d out of range: %v", i, a)
+		}
+		if a != c {
+			diverged = true
+		}
+	}
+	if !diverged {
+		t.Fatal("expected different seeds to produce different sequences")
+	}
+}
+
+func TestEventClockFollowsEventTime(t *testing.T) {
+	wall := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	clock := newEventClock()
+	clock.wall = func() time.Time { return wall }
+	if got := clock.now(); !got.Equal(wall) {
+		t.Fatalf("expected wall clock before first event, got %v", got)
+	}
+
+	emitted := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock.observe(&schema.Event{EmitTS: emitted})
+	if got := clock.now(); !got.Equal(emitted) {
+		t.Fatalf("expected event time %v, got %v", emitted, got)
+	}
+
+	clock.observe(&schema.Event{EmitTS: emitted.Add(-time.Minute)})
+	if got := clock.now(); !got.Equal(emitted) {
+		t.Fatalf("expected clock not to move backwards, got %v", got)
+	}
+
+	ingested := emitted.Add(time.Second)
+	clock.observe(&schema.Event{IngestTS: ingested})
+	if got := clock.now(); !got.Equal(ingested) {
+		t.Fatalf("expected ingest time fallback %v, got %v", ingested, got)
+	}
+}
+
+func TestSeedFromConfig(t *testing.T) {
+	cases := []struct {
+		raw  any
+		want int64
+		ok   bool
+	}{
+		{raw: 7, want: 7, ok: true},
+		{raw: float64(1234), want: 1234, ok: true},
+		{raw: "99", want: 99, ok: true},
+		{raw: 1.5, ok: false},
+		{raw: -1, ok: false},
+		{raw: int64(maxSeed), ok: false},
+		{raw: "seed", ok: false},
+		{raw: nil, ok: false},
+	}
+	for _, tc := range cases {
+		got, ok := SeedFromConfig(map[string]any{SeedConfigKey: tc.raw})
+		if ok != tc.ok || got != tc.want {
+			t.Fatalf("SeedFromConfig(%v) = %d, %v; want %d, %v", tc.raw, got, ok, tc.want, tc.ok)
+		}
+	}
+	if seed := NewSeed(); seed < 0 || seed >= maxSeed {
+		t.Fatalf("NewSeed out of range: %d", seed)
+	}
+}
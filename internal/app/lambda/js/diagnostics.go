@@ -20,15 +20,28 @@ const (
 	DiagnosticStageExecute DiagnosticStage = "execute"
 	// DiagnosticStageValidation captures metadata validation issues.
 	DiagnosticStageValidation DiagnosticStage = "validation"
+	// DiagnosticStageLint captures footguns that compile and validate but misbehave at runtime.
+	DiagnosticStageLint DiagnosticStage = "lint"
+)
+
+// DiagnosticSeverity grades a diagnostic; errors reject the upload while warnings are advisory.
+type DiagnosticSeverity string
+
+const (
+	// DiagnosticSeverityError marks findings that block the module from being stored.
+	DiagnosticSeverityError DiagnosticSeverity = "error"
+	// DiagnosticSeverityWarning marks findings reported alongside a stored module.
+	DiagnosticSeverityWarning DiagnosticSeverity = "warning"
 )
 
 // Diagnostic describes a single validation finding that should be returned to clients.
 type Diagnostic struct {
-	Stage   DiagnosticStage `json:"stage"`
-	Message string          `json:"message"`
-	Line    int             `json:"line,omitempty"`
-	Column  int             `json:"column,omitempty"`
-	Hint    string          `json:"hint,omitempty"`
+	Stage    DiagnosticStage    `json:"stage"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Message  string             `json:"message"`
+	Line     int                `json:"line,omitempty"`
+	Column   int                `json:"column,omitempty"`
+	Hint     string             `json:"hint,omitempty"`
 }
 
 // DiagnosticError aggregates diagnostics for downstream handlers.
@@ -40,9 +53,15 @@ type DiagnosticError struct {
 
 // NewDiagnosticError constructs a diagnostic error with an optional message and cause.
 func NewDiagnosticError(message string, cause error, diagnostics ...Diagnostic) *DiagnosticError {
+	copied := append([]Diagnostic(nil), diagnostics...)
+	for i := range copied {
+		if copied[i].Severity == "" {
+			copied[i].Severity = DiagnosticSeverityError
+		}
+	}
 	return &DiagnosticError{
 		message:     strings.TrimSpace(message),
-		diagnostics: append([]Diagnostic(nil), diagnostics...),
+		diagnostics: copied,
 		cause:       cause,
 	}
 }
@@ -91,6 +110,9 @@ func (e *DiagnosticError) Append(diags ...Diagnostic) {
 		if diag.Stage == "" {
 			diag.Stage = DiagnosticStageValidation
 		}
+		if diag.Severity == "" {
+			diag.Severity = DiagnosticSeverityError
+		}
 		diag.Message = strings.TrimSpace(diag.Message)
 		if diag.Message == "" {
 			continue
@@ -103,11 +125,12 @@ func (e *DiagnosticError) Append(diags ...Diagnostic) {
 func compileDiagnostic(err error) Diagnostic {
 	msg := diagnosticMessage(err)
 	diag := Diagnostic{
-		Stage:   DiagnosticStageCompile,
-		Message: msg,
-		Line:    0,
-		Column:  0,
-		Hint:    "Fix the JavaScript syntax near the reported location.",
+		Stage:    DiagnosticStageCompile,
+		Severity: DiagnosticSeverityError,
+		Message:  msg,
+		Line:     0,
+		Column:   0,
+		Hint:     "Fix the JavaScript syntax near the reported location.",
 	}
 	var syntaxErr *goja.CompilerSyntaxError
 	if errors.As(err, &syntaxErr) && syntaxErr != nil {
@@ -130,11 +153,12 @@ func compileDiagnostic(err error) Diagnostic {
 // executeDiagnostic converts runtime evaluation failures into diagnostics.
 func executeDiagnostic(err error) Diagnostic {
 	diag := Diagnostic{
-		Stage:   DiagnosticStageExecute,
-		Message: diagnosticMessage(err),
-		Line:    0,
-		Column:  0,
-		Hint:    "Check module initialization and ensure metadata export executes without throwing.",
+		Stage:    DiagnosticStageExecute,
+		Severity: DiagnosticSeverityError,
+		Message:  diagnosticMessage(err),
+		Line:     0,
+		Column:   0,
+		Hint:     "Check module initialization and ensure metadata export executes without throwing.",
 	}
 	var jsErr *goja.Exception
 	if errors.As(err, &jsErr) && jsErr != nil {
@@ -198,11 +222,12 @@ func validationDiagnosticsFromIssues(issues []strategies.MetadataIssue) []Diagno
 		}
 		hint := strings.TrimSpace(issue.Path)
 		diag := Diagnostic{
-			Stage:   DiagnosticStageValidation,
-			Message: message,
-			Line:    0,
-			Column:  0,
-			Hint:    hint,
+			Stage:    DiagnosticStageValidation,
+			Severity: DiagnosticSeverityError,
+			Message:  message,
+			Line:     0,
+			Column:   0,
+			Hint:     hint,
 		}
 		out = append(out, diag)
 	}
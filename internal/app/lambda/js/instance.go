@@ -20,12 +20,18 @@ type Instance struct {
 	once   sync.Once
 }
 
-// NewInstance creates an isolated runtime for the provided module.
-func NewInstance(module *Module) (*Instance, error) {
+// NewInstance creates an isolated runtime for the provided module. Options are applied before
+// the module executes so top-level code already sees the configured sandbox.
+func NewInstance(module *Module, opts ...InstanceOption) (*Instance, error) {
 	if module == nil {
 		return nil, fmt.Errorf("strategy instance: module required")
 	}
 	rt := goja.New()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(rt)
+		}
+	}
 	export, err := runModule(rt, module.Program)
 	if err != nil {
 		return nil, fmt.Errorf("strategy instance: execute %s: %w", module.Path, err)
@@ -98,7 +104,7 @@ func (i *Instance) Call(function string, args ...any) (goja.Value, error) {
 
 	return i.Execute(func(rt *goja.Runtime, exports *goja.Object) (goja.Value, error) {
 		value := exports.Get(fn)
-		if goja.IsUndefined(value) || goja.IsNull(value) {
+		if value == nil || goja.IsUndefined(value) || goja.IsNull(value) {
 			return nil, ErrFunctionMissing
 		}
 		callable, ok := goja.AssertFunction(value)
@@ -119,6 +125,13 @@ func (i *Instance) Call(function string, args ...any) (goja.Value, error) {
 
 // CallMethod invokes a method on the provided object within the instance goroutine.
 func (i *Instance) CallMethod(target *goja.Object, method string, args ...any) (goja.Value, error) {
+	return i.callMethod(target, method, nil, args...)
+}
+
+// callMethod invokes a method like CallMethod. scope, when set, runs on the instance goroutine
+// just before the method and returns a func run right after it, so per-call state is only ever
+// changed by the goroutine that owns the VM.
+func (i *Instance) callMethod(target *goja.Object, method string, scope func() func(), args ...any) (goja.Value, error) {
 	if i == nil {
 		return nil, fmt.Errorf("strategy instance: nil receiver")
 	}
@@ -129,9 +142,21 @@ func (i *Instance) CallMethod(target *goja.Object, method string, args ...any) (
 	if name == "" {
 		return nil, fmt.Errorf("strategy instance: method name required")
 	}
-	return i.Execute(func(rt *goja.Runtime, _ *goja.Object) (goja.Value, error) {
+	return i.Execute(func(rt *goja.Runtime, _ *goja.Object) (val goja.Value, err error) {
+		// A panic raised while handling an event is isolated to that call so the handler's
+		// owner can count it against the error budget rather than crash the VM goroutine.
+		defer func() {
+			if rec := recover(); rec != nil {
+				val, err = nil, fmt.Errorf("strategy instance: method %q panicked: %v", name, rec)
+			}
+		}()
+		if scope != nil {
+			if done := scope(); done != nil {
+				defer done()
+			}
+		}
 		value := target.Get(name)
-		if goja.IsUndefined(value) || goja.IsNull(value) {
+		if value == nil || goja.IsUndefined(value) || goja.IsNull(value) {
 			return nil, ErrFunctionMissing
 		}
 		callable, ok := goja.AssertFunction(value)
@@ -0,0 +1,365 @@
+package js
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dop251/goja/ast"
+	"github.com/dop251/goja/file"
+	"github.com/dop251/goja/parser"
+	"github.com/dop251/goja/token"
+)
+
+// orderSubmitters are the runtime helpers that emit orders to a venue.
+var orderSubmitters = map[string]struct{}{
+	"submitOrder":       {},
+	"submitMarketOrder": {},
+	"submitAlgoOrder":   {},
+}
+
+// execReportHandlers receive the execution reports of submitted orders. A report whose handler
+// is missing counts as a handler fault against the instance's error budget.
+var execReportHandlers = []string{
+	"onOrderAcknowledged",
+	"onOrderPartialFill",
+	"onOrderFilled",
+	"onOrderCancelled",
+	"onOrderRejected",
+	"onOrderExpired",
+}
+
+// Lint flags patterns in a strategy module that compile and validate but misbehave once the
+// module trades: non-deterministic inputs, arrays grown by handlers without ever being trimmed,
+// order submission without execution report handlers and synchronous busy loops. Findings are
+// warnings; sources that fail to parse produce none, since compilation reports those.
+func Lint(source []byte) []Diagnostic {
+	program, err := parser.ParseFile(nil, "", string(source), 0)
+	if err != nil || program == nil {
+		return nil
+	}
+	l := &linter{
+		file:        program.File,
+		diagnostics: nil,
+		handlers:    make(map[string]struct{}),
+		submit:      nil,
+		pushes:      make(map[string]ast.Node),
+		pushOrder:   nil,
+		trimmed:     make(map[string]struct{}),
+	}
+	for _, stmt := range program.Body {
+		l.walk(stmt, 0)
+	}
+	l.finish()
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		a, b := l.diagnostics[i], l.diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.diagnostics
+}
+
+type linter struct {
+	file        *file.File
+	diagnostics []Diagnostic
+	// handlers holds every name defined as a function, property or member assignment.
+	handlers map[string]struct{}
+	// submit is the first order submission call.
+	submit ast.Node
+	// pushes maps an array expression to its first push inside a function.
+	pushes    map[string]ast.Node
+	pushOrder []string
+	// trimmed holds array expressions that are shifted, spliced, popped or reassigned.
+	trimmed map[string]struct{}
+}
+
+func (l *linter) walk(node ast.Node, depth int) {
+	inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FunctionLiteral:
+			if n.Name != nil {
+				l.handlers[n.Name.Name.String()] = struct{}{}
+			}
+			if n.Body != nil {
+				l.walk(n.Body, depth+1)
+			}
+			return false
+		case *ast.ArrowFunctionLiteral:
+			if n.Body != nil {
+				l.walk(n.Body, depth+1)
+			}
+			return false
+		case *ast.PropertyKeyed:
+			if name, ok := literalName(n.Key); ok {
+				l.handlers[name] = struct{}{}
+			}
+		case *ast.PropertyShort:
+			l.handlers[n.Name.Name.String()] = struct{}{}
+		case *ast.MethodDefinition:
+			if name, ok := literalName(n.Key); ok {
+				l.handlers[name] = struct{}{}
+			}
+		case *ast.AssignExpression:
+			l.assign(n)
+		case *ast.CallExpression:
+			l.call(n, depth)
+		case *ast.DotExpression:
+			l.nondeterministic(n)
+		case *ast.WhileStatement:
+			l.loop(n, n.Test, n.Body, false)
+		case *ast.DoWhileStatement:
+			l.loop(n, n.Test, n.Body, false)
+		case *ast.ForStatement:
+			l.loop(n, n.Test, n.Body, n.Test == nil)
+		}
+		return true
+	})
+}
+
+func (l *linter) assign(n *ast.AssignExpression) {
+	if dot, ok := n.Left.(*ast.DotExpression); ok {
+		name := dot.Identifier.Name.String()
+		l.handlers[name] = struct{}{}
+		if name == "length" {
+			if target, ok := expressionPath(dot.Left); ok {
+				l.trimmed[target] = struct{}{}
+			}
+			return
+		}
+	}
+	if target, ok := expressionPath(n.Left); ok {
+		l.trimmed[target] = struct{}{}
+	}
+}
+
+func (l *linter) call(n *ast.CallExpression, depth int) {
+	dot, ok := n.Callee.(*ast.DotExpression)
+	if !ok {
+		return
+	}
+	method := dot.Identifier.Name.String()
+	if _, ok := orderSubmitters[method]; ok && l.submit == nil {
+		l.submit = n
+	}
+	target, ok := expressionPath(dot.Left)
+	if !ok {
+		return
+	}
+	switch method {
+	case "push", "unshift":
+		// Pushes while the module loads run once; only handlers grow arrays per event.
+		if depth == 0 {
+			return
+		}
+		if _, seen := l.pushes[target]; !seen {
+			l.pushes[target] = n
+			l.pushOrder = append(l.pushOrder, target)
+		}
+	case "shift", "pop", "splice":
+		l.trimmed[target] = struct{}{}
+	}
+}
+
+func (l *linter) nondeterministic(n *ast.DotExpression) {
+	object, ok := n.Left.(*ast.Identifier)
+	if !ok {
+		return
+	}
+	switch object.Name.String() + "." + n.Identifier.Name.String() {
+	case "Date.now":
+		l.warn(n, "Date.now reads the sandbox clock, which follows event time rather than the wall clock",
+			"Read timestamps from the event (evt.emitTs) or payload so the logic is explicit in live trading, replay and backtests.")
+	case "Math.random":
+		l.warn(n, "Math.random makes decisions depend on the instance seed rather than market data",
+			"Derive decisions from market data, or pin the seed config key so runs are reproducible.")
+	}
+}
+
+func (l *linter) loop(n ast.Node, test ast.Expression, body ast.Statement, infinite bool) {
+	if readsClock(test) {
+		l.warn(n, "loop busy-waits on the clock, which does not advance while a handler runs",
+			"Keep state between events and act on a later event instead of spinning until a deadline.")
+		return
+	}
+	if !infinite && !alwaysTrue(test) {
+		return
+	}
+	if !exits(body) {
+		l.warn(n, "loop never exits and blocks the strategy's event handling",
+			"Handlers must return promptly; add a break or return, or react to the next event instead.")
+	}
+}
+
+func (l *linter) finish() {
+	for _, target := range l.pushOrder {
+		if _, ok := l.trimmed[target]; ok {
+			continue
+		}
+		l.warn(l.pushes[target], fmt.Sprintf("array %s grows on every call and is never trimmed", target),
+			"Bound it with shift/splice or by reassigning a slice, otherwise memory grows for the life of the instance.")
+	}
+	if l.submit == nil {
+		return
+	}
+	missing := make([]string, 0, len(execReportHandlers))
+	for _, name := range execReportHandlers {
+		if _, ok := l.handlers[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		l.warn(l.submit, fmt.Sprintf("module submits orders but does not define %s", strings.Join(missing, ", ")),
+			"Execution reports without a handler count as handler faults; define them, even as no-ops, and subscribe to ExecReport.")
+	}
+}
+
+func (l *linter) warn(node ast.Node, message, hint string) {
+	diag := Diagnostic{
+		Stage:    DiagnosticStageLint,
+		Severity: DiagnosticSeverityWarning,
+		Message:  message,
+		Line:     0,
+		Column:   0,
+		Hint:     hint,
+	}
+	if l.file != nil && node != nil {
+		pos := l.file.Position(int(node.Idx0()) - l.file.Base())
+		diag.Line = pos.Line
+		diag.Column = pos.Column
+	}
+	l.diagnostics = append(l.diagnostics, diag)
+}
+
+// literalName returns the name of a non-computed property key.
+func literalName(key ast.Expression) (string, bool) {
+	switch key := key.(type) {
+	case *ast.Identifier:
+		return key.Name.String(), true
+	case *ast.StringLiteral:
+		return key.Value.String(), true
+	}
+	return "", false
+}
+
+// expressionPath renders identifiers and dotted member chains, such as this.history or
+// state.fills, so pushes and trims of the same array can be matched.
+func expressionPath(expr ast.Expression) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.Identifier:
+		return expr.Name.String(), true
+	case *ast.ThisExpression:
+		return "this", true
+	case *ast.DotExpression:
+		left, ok := expressionPath(expr.Left)
+		if !ok {
+			return "", false
+		}
+		return left + "." + expr.Identifier.Name.String(), true
+	}
+	return "", false
+}
+
+func alwaysTrue(expr ast.Expression) bool {
+	switch expr := expr.(type) {
+	case *ast.BooleanLiteral:
+		return expr.Value
+	case *ast.NumberLiteral:
+		return expr.Value != int64(0) && expr.Value != float64(0)
+	}
+	return false
+}
+
+// readsClock reports whether a loop condition calls Date.now or constructs a Date.
+func readsClock(test ast.Expression) bool {
+	if test == nil {
+		return false
+	}
+	found := false
+	inspect(test, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.DotExpression:
+			if object, ok := n.Left.(*ast.Identifier); ok && object.Name.String() == "Date" && n.Identifier.Name.String() == "now" {
+				found = true
+			}
+		case *ast.NewExpression:
+			if callee, ok := n.Callee.(*ast.Identifier); ok && callee.Name.String() == "Date" {
+				found = true
+			}
+		case *ast.FunctionLiteral, *ast.ArrowFunctionLiteral:
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+// exits reports whether a loop body can leave the loop, ignoring nested functions.
+func exits(body ast.Statement) bool {
+	found := false
+	inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BranchStatement:
+			found = n.Token == token.BREAK
+		case *ast.ReturnStatement, *ast.ThrowStatement:
+			found = true
+		case *ast.FunctionLiteral, *ast.ArrowFunctionLiteral:
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+var astPackage = reflect.TypeOf(ast.Program{}).PkgPath()
+
+// inspect visits node and its descendants in source order; visit returns false to skip the
+// children of a node. goja's ast package has no walker, so children are found by reflection.
+func inspect(node ast.Node, visit func(ast.Node) bool) {
+	value := reflect.ValueOf(node)
+	if node == nil || (value.Kind() == reflect.Pointer && value.IsNil()) {
+		return
+	}
+	if !visit(node) {
+		return
+	}
+	inspectValue(value, visit)
+}
+
+func inspectValue(value reflect.Value, visit func(ast.Node) bool) {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() {
+			inspectValue(value.Elem(), visit)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			inspectChild(value.Index(i), visit)
+		}
+	case reflect.Struct:
+		if value.Type().PkgPath() != astPackage {
+			return
+		}
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			// DeclarationList repeats the hoisted declarations already present in the body.
+			if !field.IsExported() || field.Name == "DeclarationList" {
+				continue
+			}
+			inspectChild(value.Field(i), visit)
+		}
+	default:
+	}
+}
+
+func inspectChild(value reflect.Value, visit func(ast.Node) bool) {
+	if (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && !value.IsNil() {
+		if node, ok := value.Interface().(ast.Node); ok {
+			inspect(node, visit)
+			return
+		}
+	}
+	inspectValue(value, visit)
+}
@@ -0,0 +1,95 @@
+package js
+
+import (
+	"strings"
+	"testing"
+)
+
+const footgunModule = `
+var seen = [];
+seen.push("loaded");
+
+module.exports = {
+  metadata: {
+    name: "footguns",
+    tag: "v1.0.0",
+    displayName: "Footguns",
+    description: "Everything the linter flags",
+    config: [],
+    events: ["Trade", "ExecReport"]
+  },
+  create: function(env) {
+    var prices = [];
+    var fills = [];
+    return {
+      onTrade: function(ctx, evt, payload) {
+        prices.push(payload.price);
+        fills.push(payload.price);
+        if (fills.length > 100) fills.shift();
+        if (Math.random() < 0.5) {
+          env.runtime.submitMarketOrder("", "buy", "1");
+        }
+        var until = Date.now() + 10;
+        while (Date.now() < until) {}
+        while (true) {
+          if (prices.length > 10) break;
+        }
+        for (;;) {}
+      },
+      onOrderFilled: function() {},
+      onOrderRejected: function() {}
+    };
+  }
+};
+`
+
+func TestLintFlagsFootguns(t *testing.T) {
+	diags := Lint([]byte(footgunModule))
+	want := []struct {
+		line    int
+		message string
+	}{
+		{19, "array prices grows on every call and is never trimmed"},
+		{22, "Math.random"},
+		{23, "module submits orders but does not define onOrderAcknowledged, onOrderPartialFill, onOrderCancelled, onOrderExpired"},
+		{25, "Date.now"},
+		{26, "loop busy-waits on the clock"},
+		{26, "Date.now"},
+		{30, "loop never exits"},
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("expected %d diagnostics, got %+v", len(want), diags)
+	}
+	for i, w := range want {
+		diag := diags[i]
+		if diag.Line != w.line || !strings.Contains(diag.Message, w.message) {
+			t.Fatalf("diagnostic %d: expected line %d %q, got %+v", i, w.line, w.message, diag)
+		}
+		if diag.Stage != DiagnosticStageLint || diag.Severity != DiagnosticSeverityWarning || diag.Hint == "" || diag.Column == 0 {
+			t.Fatalf("diagnostic %d: unexpected %+v", i, diag)
+		}
+	}
+}
+
+func TestLintCleanModule(t *testing.T) {
+	if diags := Lint([]byte(sampleModule)); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics, got %+v", diags)
+	}
+	if diags := Lint([]byte("module.exports = {")); diags != nil {
+		t.Fatalf("expected parse failures left to the compiler, got %+v", diags)
+	}
+}
+
+func TestStoreReportsLintWarnings(t *testing.T) {
+	loader, err := NewLoader(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	resolution, err := loader.Store([]byte(footgunModule), ModuleWriteOptions{Filename: "", Tag: "", Aliases: nil, ReassignTags: nil, PromoteLatest: true})
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	if resolution.Hash == "" || len(resolution.Warnings) == 0 {
+		t.Fatalf("expected stored module with warnings, got %+v", resolution)
+	}
+}
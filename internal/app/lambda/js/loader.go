@@ -20,6 +20,7 @@ import (
 	json "github.com/goccy/go-json"
 
 	"github.com/coachpo/meltica/internal/app/lambda/strategies"
+	"github.com/coachpo/meltica/internal/domain/objectstore"
 	"github.com/coachpo/meltica/internal/domain/schema"
 )
 
@@ -73,6 +74,12 @@ type Loader struct {
 	resolutionCache    map[string]*list.Element
 	resolutionOrder    *list.List
 	resolutionCapacity int
+
+	// mirrorMu serializes pulls from and pushes to the mirror.
+	mirrorMu    sync.Mutex
+	mirror      objectstore.Bucket
+	mirrored    map[string]struct{} // keys known to exist in the mirror
+	mirrorPaths map[string]struct{} // revision keys of the last registry synced with the mirror
 }
 
 // Module encapsulates the compiled program and metadata for a strategy.
@@ -95,6 +102,11 @@ type ModuleResolution struct {
 	Tag    string
 	Alias  string
 	Module *Module
+	// Warnings lists lint findings for a stored upload; they never block the write.
+	Warnings []Diagnostic
+	// Tests reports the inline tests of a stored upload, nil when the module ships none. A
+	// revision that fails them is stored but not tagged latest.
+	Tests *SelfTestReport
 }
 
 // ModuleWriteOptions configures how a module revision should be stored.
@@ -182,6 +194,10 @@ func NewLoader(root string) (*Loader, error) {
 		resolutionCache:    make(map[string]*list.Element),
 		resolutionOrder:    list.New(),
 		resolutionCapacity: defaultResolutionCacheSize,
+		mirrorMu:           sync.Mutex{},
+		mirror:             nil,
+		mirrored:           nil,
+		mirrorPaths:        nil,
 	}, nil
 }
 
@@ -198,6 +214,9 @@ func (l *Loader) Refresh(ctx context.Context) error {
 	if l == nil {
 		return fmt.Errorf("strategy loader: nil receiver")
 	}
+	if err := l.pullMirror(ctx); err != nil {
+		return fmt.Errorf("strategy loader: %w", err)
+	}
 	reg, err := loadRegistry(l.root)
 	if err != nil {
 		return fmt.Errorf("strategy loader: load registry: %w", err)
@@ -477,11 +496,13 @@ func (l *Loader) ResolveReference(identifier string) (ModuleResolution, error) {
 			canonical = alias
 		}
 		resolution := ModuleResolution{
-			Name:   module.Name,
-			Hash:   hash,
-			Tag:    canonical,
-			Alias:  alias,
-			Module: module,
+			Name:     module.Name,
+			Hash:     hash,
+			Tag:      canonical,
+			Alias:    alias,
+			Module:   module,
+			Warnings: nil,
+			Tests:    nil,
 		}
 		l.storeResolutionLocked(key, resolution)
 		return resolution, nil
@@ -551,11 +572,13 @@ func (l *Loader) ResolveReference(identifier string) (ModuleResolution, error) {
 		canonical = resolvedAlias
 	}
 	resolution := ModuleResolution{
-		Name:   module.Name,
-		Hash:   resolvedHash,
-		Tag:    canonical,
-		Alias:  resolvedAlias,
-		Module: module,
+		Name:     module.Name,
+		Hash:     resolvedHash,
+		Tag:      canonical,
+		Alias:    resolvedAlias,
+		Module:   module,
+		Warnings: nil,
+		Tests:    nil,
 	}
 	l.storeResolutionLocked(key, resolution)
 	return resolution, nil
@@ -623,7 +646,7 @@ func (l *Loader) Delete(name string) error {
 		return fmt.Errorf("strategy loader: nil receiver")
 	}
 
-	reg, err := loadRegistry(l.root)
+	reg, err := l.currentRegistry()
 	if err != nil {
 		return fmt.Errorf("strategy loader: load registry: %w", err)
 	}
@@ -637,7 +660,7 @@ func (l *Loader) Write(source []byte) error {
 		return fmt.Errorf("strategy loader: nil receiver")
 	}
 
-	reg, err := loadRegistry(l.root)
+	reg, err := l.currentRegistry()
 	if err != nil {
 		return fmt.Errorf("strategy loader: load registry: %w", err)
 	}
@@ -651,7 +674,7 @@ func (l *Loader) Store(source []byte, opts ModuleWriteOptions) (ModuleResolution
 	if l == nil {
 		return empty, fmt.Errorf("strategy loader: nil receiver")
 	}
-	reg, err := loadRegistry(l.root)
+	reg, err := l.currentRegistry()
 	if err != nil {
 		return empty, fmt.Errorf("strategy loader: load registry: %w", err)
 	}
@@ -662,6 +685,7 @@ func (l *Loader) Store(source []byte, opts ModuleWriteOptions) (ModuleResolution
 	if err != nil {
 		return empty, err
 	}
+	resolution.Warnings = Lint(source)
 	return resolution, nil
 }
 
@@ -670,7 +694,7 @@ func (l *Loader) AssignTag(name, tag, hash string) (string, error) {
 	if l == nil {
 		return "", fmt.Errorf("strategy loader: nil receiver")
 	}
-	reg, err := loadRegistry(l.root)
+	reg, err := l.currentRegistry()
 	if err != nil {
 		return "", fmt.Errorf("strategy loader: load registry: %w", err)
 	}
@@ -708,7 +732,7 @@ func (l *Loader) AssignTag(name, tag, hash string) (string, error) {
 	previous := entry.Tags[normalizedTag]
 	entry.Tags[normalizedTag] = normalizedHash
 	reg[lower] = entry
-	if err := writeRegistryFile(l.root, reg); err != nil {
+	if err := l.saveRegistry(reg); err != nil {
 		return "", err
 	}
 	l.applyRegistryTagUpdate(lower, entry)
@@ -729,7 +753,7 @@ func (l *Loader) deleteTag(name, tag string, opts TagDeleteOptions) (string, err
 	if l == nil {
 		return "", fmt.Errorf("strategy loader: nil receiver")
 	}
-	reg, err := loadRegistry(l.root)
+	reg, err := l.currentRegistry()
 	if err != nil {
 		return "", fmt.Errorf("strategy loader: load registry: %w", err)
 	}
@@ -760,7 +784,7 @@ func (l *Loader) deleteTag(name, tag string, opts TagDeleteOptions) (string, err
 	}
 	delete(entry.Tags, normalizedTag)
 	reg[lower] = entry
-	if err := writeRegistryFile(l.root, reg); err != nil {
+	if err := l.saveRegistry(reg); err != nil {
 		return "", err
 	}
 	l.applyRegistryTagUpdate(lower, entry)
@@ -911,12 +935,31 @@ func cloneTagsWithFallback(src []string, fallback string) []string {
 	return out
 }
 
+// Compile validates the provided JavaScript source and returns the compiled module without
+// persisting it. The returned module has no path and is not registered with the loader.
+func (l *Loader) Compile(source []byte) (*Module, error) {
+	if l == nil {
+		return nil, fmt.Errorf("strategy loader: nil receiver")
+	}
+	module, err := compileSource("upload.js", source, int64(len(source)))
+	if err != nil {
+		return nil, err
+	}
+	module.Path = ""
+	module.Filename = fmt.Sprintf("%s.js", module.Name)
+	return module, nil
+}
+
 func compileModule(fullPath string, info fs.FileInfo) (*Module, error) {
 	// #nosec G304
 	source, err := os.ReadFile(fullPath)
 	if err != nil {
 		return nil, fmt.Errorf("strategy loader: read %q: %w", fullPath, err)
 	}
+	return compileSource(fullPath, source, info.Size())
+}
+
+func compileSource(fullPath string, source []byte, size int64) (*Module, error) {
 	program, err := goja.Compile(fullPath, string(source), true)
 	if err != nil {
 		diagErr := NewDiagnosticError(
@@ -943,7 +986,7 @@ func compileModule(fullPath string, info fs.FileInfo) (*Module, error) {
 		Tags:     nil,
 		Metadata: meta,
 		Program:  program,
-		Size:     info.Size(),
+		Size:     size,
 	}
 	module.Metadata.Name = module.Name
 	if module.Metadata.Tag != "" {
@@ -965,11 +1008,12 @@ func extractMetadata(program *goja.Program) (strategies.Metadata, error) {
 			"metadata export missing",
 			errors.New("metadata export missing"),
 			Diagnostic{
-				Stage:   DiagnosticStageValidation,
-				Message: "metadata export missing",
-				Line:    0,
-				Column:  0,
-				Hint:    "Expose module.exports.metadata with required fields.",
+				Stage:    DiagnosticStageValidation,
+				Severity: DiagnosticSeverityError,
+				Message:  "metadata export missing",
+				Line:     0,
+				Column:   0,
+				Hint:     "Expose module.exports.metadata with required fields.",
 			},
 		)
 		return strategies.Metadata{}, diagErr
@@ -981,11 +1025,12 @@ func extractMetadata(program *goja.Program) (strategies.Metadata, error) {
 			"metadata export invalid",
 			err,
 			Diagnostic{
-				Stage:   DiagnosticStageValidation,
-				Message: diagnosticMessage(err),
-				Line:    0,
-				Column:  0,
-				Hint:    "Ensure metadata export matches the strategies.Metadata schema.",
+				Stage:    DiagnosticStageValidation,
+				Severity: DiagnosticSeverityError,
+				Message:  diagnosticMessage(err),
+				Line:     0,
+				Column:   0,
+				Hint:     "Ensure metadata export matches the strategies.Metadata schema.",
 			},
 		)
 		return strategies.Metadata{}, diagErr
@@ -1036,6 +1081,7 @@ func normalizeMetadata(meta *strategies.Metadata) {
 	} else {
 		meta.Events = meta.Events[:writeIdx]
 	}
+	meta.Capabilities = strategies.NormalizeCapabilities(meta.Capabilities)
 }
 
 func runModule(rt *goja.Runtime, program *goja.Program) (*goja.Object, error) {
@@ -1052,8 +1098,10 @@ func runModule(rt *goja.Runtime, program *goja.Program) (*goja.Object, error) {
 		return nil, fmt.Errorf("module init: %w", err)
 	}
 
-	if err := rt.Set("console", buildConsole(rt)); err != nil {
-		return nil, fmt.Errorf("module init: %w", err)
+	if existing := rt.Get("console"); existing == nil || goja.IsUndefined(existing) {
+		if err := rt.Set("console", buildConsole(rt)); err != nil {
+			return nil, fmt.Errorf("module init: %w", err)
+		}
 	}
 
 	if _, err := rt.RunProgram(program); err != nil {
@@ -1076,6 +1124,7 @@ func runModule(rt *goja.Runtime, program *goja.Program) (*goja.Object, error) {
 func buildConsole(rt *goja.Runtime) *goja.Object {
 	console := rt.NewObject()
 	noop := func(goja.FunctionCall) goja.Value { return goja.Undefined() }
+	_ = console.Set("debug", noop)
 	_ = console.Set("log", noop)
 	_ = console.Set("error", noop)
 	_ = console.Set("warn", noop)
@@ -1115,6 +1164,12 @@ func (l *Loader) writeModuleWithRegistry(source []byte, opts ModuleWriteOptions,
 		_ = os.Remove(tempPath)
 		return empty, err
 	}
+	tests, err := RunSelfTests(module)
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return empty, fmt.Errorf("strategy loader: %w", err)
+	}
+	testsFailed := tests != nil && !tests.Passed
 
 	name := strings.ToLower(strings.TrimSpace(module.Metadata.Name))
 	if name == "" {
@@ -1138,6 +1193,10 @@ func (l *Loader) writeModuleWithRegistry(source []byte, opts ModuleWriteOptions,
 		_ = os.Remove(tempPath)
 		return empty, fmt.Errorf("strategy loader: %w", err)
 	}
+	if testsFailed && tag == "latest" {
+		_ = os.Remove(tempPath)
+		return empty, fmt.Errorf("strategy loader: %w", tests.failure())
+	}
 
 	hash := module.Hash
 	if hash == "" {
@@ -1180,6 +1239,9 @@ func (l *Loader) writeModuleWithRegistry(source []byte, opts ModuleWriteOptions,
 		if existingHash, ok := entry.Tags[alias]; ok && existingHash != hash {
 			continue
 		}
+		if testsFailed && alias == "latest" {
+			continue
+		}
 		entry.Tags[alias] = hash
 	}
 	for _, move := range opts.ReassignTags {
@@ -1190,16 +1252,19 @@ func (l *Loader) writeModuleWithRegistry(source []byte, opts ModuleWriteOptions,
 		if err := validatePathSegment(move); err != nil {
 			continue
 		}
+		if testsFailed && move == "latest" {
+			continue
+		}
 		entry.Tags[move] = hash
 	}
 
-	if opts.PromoteLatest || entry.Tags["latest"] == "" {
+	if (opts.PromoteLatest || entry.Tags["latest"] == "") && !testsFailed {
 		entry.Tags["latest"] = hash
 	}
 
 	reg[name] = entry
 
-	if err := writeRegistryFile(l.root, reg); err != nil {
+	if err := l.saveRegistry(reg); err != nil {
 		return empty, err
 	}
 
@@ -1209,11 +1274,13 @@ func (l *Loader) writeModuleWithRegistry(source []byte, opts ModuleWriteOptions,
 	module.Tag = tag
 
 	return ModuleResolution{
-		Name:   name,
-		Hash:   hash,
-		Tag:    tag,
-		Alias:  tag,
-		Module: module,
+		Name:     name,
+		Hash:     hash,
+		Tag:      tag,
+		Alias:    tag,
+		Module:   module,
+		Warnings: nil,
+		Tests:    tests,
 	}, nil
 }
 
@@ -1395,7 +1462,7 @@ func (l *Loader) deleteWithRegistry(selector string, reg registry) error {
 			}
 		}
 		delete(reg, sel.Name)
-		return writeRegistryFile(l.root, reg)
+		return l.saveRegistry(reg)
 	}
 
 	loc, ok := entry.Hashes[removeHash]
@@ -1424,7 +1491,7 @@ func (l *Loader) deleteWithRegistry(selector string, reg registry) error {
 		}
 		reg[sel.Name] = entry
 	}
-	return writeRegistryFile(l.root, reg)
+	return l.saveRegistry(reg)
 }
 
 func (l *Loader) removeModuleFiles(loc registryLocation) error {
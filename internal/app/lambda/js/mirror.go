@@ -0,0 +1,222 @@
+package js
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/domain/objectstore"
+)
+
+const (
+	registryObject = "registry.json"
+	// mirrorTimeout bounds the bucket round trips of one registry change.
+	mirrorTimeout = 30 * time.Second
+)
+
+// SetMirror makes bucket the shared source of truth for the registry tree, keyed like the loader
+// root, which becomes a local cache. Refresh and every registry change first pull registry.json
+// and any revision files missing from the cache; changes are pushed back revision files first, so
+// other gateways never see a registry that references missing code. An empty bucket is seeded
+// from the local tree. Concurrent changes from several gateways are last-writer-wins on
+// registry.json. Call SetMirror before the first Refresh.
+func (l *Loader) SetMirror(bucket objectstore.Bucket) {
+	if l == nil {
+		return
+	}
+	l.mirrorMu.Lock()
+	defer l.mirrorMu.Unlock()
+	l.mirror = bucket
+	l.mirrored = make(map[string]struct{})
+	l.mirrorPaths = make(map[string]struct{})
+}
+
+// Sync pulls the registry tree from the mirror into the local root without reloading modules. It
+// does nothing without a mirror.
+func (l *Loader) Sync(ctx context.Context) error {
+	if l == nil {
+		return fmt.Errorf("strategy loader: nil receiver")
+	}
+	if err := l.pullMirror(ctx); err != nil {
+		return fmt.Errorf("strategy loader: %w", err)
+	}
+	return nil
+}
+
+// currentRegistry returns the registry a change should start from, pulling the shared copy first.
+func (l *Loader) currentRegistry() (registry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorTimeout)
+	defer cancel()
+	if err := l.pullMirror(ctx); err != nil {
+		return nil, err
+	}
+	return loadRegistry(l.root)
+}
+
+// saveRegistry writes the registry to the local root and pushes the change to the mirror.
+func (l *Loader) saveRegistry(reg registry) error {
+	if err := writeRegistryFile(l.root, reg); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorTimeout)
+	defer cancel()
+	l.mirrorMu.Lock()
+	defer l.mirrorMu.Unlock()
+	if l.mirror == nil {
+		return nil
+	}
+	if err := l.pushMirrorLocked(ctx, reg); err != nil {
+		return fmt.Errorf("strategy loader: %w", err)
+	}
+	return nil
+}
+
+func (l *Loader) pullMirror(ctx context.Context) error {
+	l.mirrorMu.Lock()
+	defer l.mirrorMu.Unlock()
+	if l.mirror == nil {
+		return nil
+	}
+	objects, err := l.mirror.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	l.mirrored = make(map[string]struct{}, len(objects))
+	for _, object := range objects {
+		l.mirrored[object.Key] = struct{}{}
+	}
+
+	raw, err := objectstore.ReadAll(ctx, l.mirror, registryObject)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return l.seedMirrorLocked(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	reg := make(registry)
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err := json.Unmarshal(raw, &reg); err != nil {
+			return fmt.Errorf("mirror: decode registry: %w", err)
+		}
+	}
+	paths := make(map[string]struct{})
+	for name, entry := range reg {
+		for hash, loc := range entry.Hashes {
+			if err := validateRegistryLocation(name, hash, loc.Path); err != nil {
+				return fmt.Errorf("mirror: %w", err)
+			}
+			key := filepath.ToSlash(filepath.Clean(loc.Path))
+			if err := l.fetchRevisionLocked(ctx, key, hash); err != nil {
+				return err
+			}
+			paths[key] = struct{}{}
+		}
+	}
+	if err := writeRegistryFile(l.root, reg); err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	l.mirrorPaths = paths
+	return nil
+}
+
+// seedMirrorLocked uploads an existing local tree to an empty mirror.
+func (l *Loader) seedMirrorLocked(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(l.root, registryObject)); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	reg, err := loadRegistry(l.root)
+	if err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	if len(reg) == 0 {
+		return nil
+	}
+	return l.pushMirrorLocked(ctx, reg)
+}
+
+// fetchRevisionLocked downloads a revision file missing from the local cache and checks it
+// against the content hash it is registered under.
+func (l *Loader) fetchRevisionLocked(ctx context.Context, key, hash string) error {
+	dest := filepath.Join(l.root, filepath.FromSlash(key))
+	if info, err := os.Stat(dest); err == nil && !info.IsDir() {
+		return nil
+	}
+	data, err := objectstore.ReadAll(ctx, l.mirror, key)
+	if err != nil {
+		return fmt.Errorf("mirror: fetch %s: %w", key, err)
+	}
+	sum := sha256.Sum256(data)
+	if digest, _ := hashDirectoryComponent(hash); hex.EncodeToString(sum[:]) != digest {
+		return fmt.Errorf("mirror: %s does not match hash %s", key, hash)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o750); err != nil {
+		return fmt.Errorf("mirror: ensure directory %q: %w", filepath.Dir(dest), err)
+	}
+	tmp := dest + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("mirror: cache %s: %w", key, err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("mirror: cache %s: %w", key, err)
+	}
+	return nil
+}
+
+// pushMirrorLocked uploads revision files the mirror lacks, then registry.json, then removes the
+// revisions the change deleted locally.
+func (l *Loader) pushMirrorLocked(ctx context.Context, reg registry) error {
+	paths := make(map[string]struct{})
+	for _, entry := range reg {
+		for _, loc := range entry.Hashes {
+			key := filepath.ToSlash(filepath.Clean(loc.Path))
+			paths[key] = struct{}{}
+			if _, ok := l.mirrored[key]; ok {
+				continue
+			}
+			// #nosec G304 -- key is a validated registry location under the loader root
+			data, err := os.ReadFile(filepath.Join(l.root, filepath.FromSlash(key)))
+			if err != nil {
+				return fmt.Errorf("mirror: read %s: %w", key, err)
+			}
+			if err := l.mirror.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "text/javascript"); err != nil {
+				return fmt.Errorf("mirror: %w", err)
+			}
+			l.mirrored[key] = struct{}{}
+		}
+	}
+
+	// #nosec G304 -- fixed file name under the loader root
+	raw, err := os.ReadFile(filepath.Join(l.root, registryObject))
+	if err != nil {
+		return fmt.Errorf("mirror: read registry: %w", err)
+	}
+	if err := l.mirror.Put(ctx, registryObject, bytes.NewReader(raw), int64(len(raw)), "application/json"); err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+
+	for key := range l.mirrorPaths {
+		if _, kept := paths[key]; kept || !strings.HasSuffix(key, ".js") {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(l.root, filepath.FromSlash(key))); !errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := l.mirror.Delete(ctx, key); err != nil {
+			return fmt.Errorf("mirror: %w", err)
+		}
+		delete(l.mirrored, key)
+	}
+	l.mirrorPaths = paths
+	return nil
+}
@@ -0,0 +1,161 @@
+package js
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/objectstore"
+)
+
+type memBucket struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemBucket() *memBucket {
+	return &memBucket{objects: make(map[string][]byte)}
+}
+
+func (b *memBucket) List(_ context.Context, prefix string) ([]objectstore.Object, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []objectstore.Object
+	for key, data := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			out = append(out, objectstore.Object{Key: key, Size: int64(len(data))})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+func (b *memBucket) Get(_ context.Context, key string) (io.ReadCloser, objectstore.Object, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.objects[key]
+	if !ok {
+		return nil, objectstore.Object{}, objectstore.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), objectstore.Object{Key: key, Size: int64(len(data))}, nil
+}
+
+func (b *memBucket) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = data
+	return nil
+}
+
+func (b *memBucket) Delete(_ context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.objects, key)
+	return nil
+}
+
+func mirroredLoader(t *testing.T, bucket objectstore.Bucket) *Loader {
+	t.Helper()
+	loader, err := NewLoader(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	loader.SetMirror(bucket)
+	if err := loader.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	return loader
+}
+
+func TestMirrorSharesRevisionsBetweenLoaders(t *testing.T) {
+	bucket := newMemBucket()
+	first := mirroredLoader(t, bucket)
+	second := mirroredLoader(t, bucket)
+
+	stored, err := first.Store([]byte(sampleModule), ModuleWriteOptions{PromoteLatest: true})
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	revisionKey := filepath.ToSlash(filepath.Join("noop", strings.TrimPrefix(stored.Hash, "sha256:"), "noop.js"))
+	if _, ok := bucket.objects[revisionKey]; !ok {
+		t.Fatalf("expected revision %s uploaded, bucket holds %d objects", revisionKey, len(bucket.objects))
+	}
+
+	if err := second.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh second: %v", err)
+	}
+	resolved, err := second.ResolveReference("noop:v1.0.0")
+	if err != nil || resolved.Hash != stored.Hash {
+		t.Fatalf("expected second loader to resolve the shared revision, got %+v, %v", resolved, err)
+	}
+	if _, err := os.Stat(resolved.Module.Path); err != nil {
+		t.Fatalf("expected revision cached locally: %v", err)
+	}
+
+	if _, err := second.AssignTag("noop", "stable", stored.Hash); err != nil {
+		t.Fatalf("AssignTag: %v", err)
+	}
+	if err := second.Delete("noop:v1.0.0"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := bucket.objects[revisionKey]; ok {
+		t.Fatal("expected deleted revision removed from the bucket")
+	}
+	if err := first.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh first: %v", err)
+	}
+	if _, err := first.ResolveReference("noop"); err == nil {
+		t.Fatal("expected the deletion to reach the first loader")
+	}
+}
+
+func TestMirrorSeedsEmptyBucketAndRejectsTamperedRevisions(t *testing.T) {
+	dir := t.TempDir()
+	local, err := NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	stored, err := local.Store([]byte(sampleModule), ModuleWriteOptions{PromoteLatest: true})
+	if err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+
+	bucket := newMemBucket()
+	local.SetMirror(bucket)
+	if err := local.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, ok := bucket.objects[registryObject]; !ok {
+		t.Fatal("expected the local tree seeded into the empty bucket")
+	}
+
+	for key := range bucket.objects {
+		if strings.HasSuffix(key, ".js") {
+			bucket.objects[key] = []byte("tampered")
+		}
+	}
+	other := mirroredLoaderErr(t, bucket)
+	if other == nil || !strings.Contains(other.Error(), "does not match hash "+stored.Hash) {
+		t.Fatalf("expected tampered revision rejected, got %v", other)
+	}
+}
+
+func mirroredLoaderErr(t *testing.T, bucket objectstore.Bucket) error {
+	t.Helper()
+	loader, err := NewLoader(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	loader.SetMirror(bucket)
+	return loader.Refresh(context.Background())
+}
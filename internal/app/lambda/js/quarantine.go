@@ -0,0 +1,179 @@
+package js
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+const (
+	// DefaultErrorBudget is the number of handler exceptions tolerated within DefaultErrorBudgetWindow
+	// before the instance is stopped.
+	DefaultErrorBudget = 50
+	// DefaultErrorBudgetWindow is the sliding window over which handler exceptions are counted.
+	DefaultErrorBudgetWindow = time.Minute
+)
+
+// ErrorBudget bounds the handler exceptions a strategy may raise before its instance is stopped.
+// Exceptions are counted across all handlers within Window; a zero Window counts them for the
+// lifetime of the instance and a non-positive MaxErrors disables stopping altogether.
+type ErrorBudget struct {
+	MaxErrors int
+	Window    time.Duration
+}
+
+// DefaultBudget returns the error budget applied when the instance does not configure one.
+func DefaultBudget() ErrorBudget {
+	return ErrorBudget{MaxErrors: DefaultErrorBudget, Window: DefaultErrorBudgetWindow}
+}
+
+// HandlerFault summarises the exceptions raised by one handler for one event type.
+type HandlerFault struct {
+	Handler   string           `json:"handler"`
+	EventType schema.EventType `json:"eventType,omitempty"`
+	Count     int64            `json:"count"`
+	LastError string           `json:"lastError"`
+	FirstAt   time.Time        `json:"firstAt"`
+	LastAt    time.Time        `json:"lastAt"`
+}
+
+type faultKey struct {
+	handler   string
+	eventType schema.EventType
+}
+
+// handlerFaults isolates handler exceptions: each one is counted against its handler and event
+// type, the offending event is dropped, and the budget is consulted to decide whether the
+// instance as a whole has become unhealthy.
+type handlerFaults struct {
+	mu        sync.Mutex
+	budget    ErrorBudget
+	faults    map[faultKey]*HandlerFault
+	recent    []time.Time
+	total     int64
+	exhausted bool
+	clock     func() time.Time
+}
+
+func newHandlerFaults(budget ErrorBudget) *handlerFaults {
+	return &handlerFaults{
+		mu:        sync.Mutex{},
+		budget:    budget,
+		faults:    make(map[faultKey]*HandlerFault),
+		recent:    nil,
+		total:     0,
+		exhausted: false,
+		clock:     time.Now,
+	}
+}
+
+func (f *handlerFaults) setBudget(budget ErrorBudget) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.budget = budget
+}
+
+func (f *handlerFaults) currentBudget() ErrorBudget {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.budget
+}
+
+// budgetLimit renders the exception limit for log lines; "unlimited" when stopping is disabled.
+func (f *handlerFaults) budgetLimit() string {
+	budget := f.currentBudget()
+	if budget.MaxErrors <= 0 {
+		return "unlimited"
+	}
+	return strconv.Itoa(budget.MaxErrors)
+}
+
+// record counts err against handler and reports the budgeted exception count and whether this
+// exception exhausted the budget. Exhaustion is reported only once.
+func (f *handlerFaults) record(handler string, eventType schema.EventType, err error) (int, bool) {
+	now := f.clock().UTC()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := faultKey{handler: handler, eventType: eventType}
+	fault, ok := f.faults[key]
+	if !ok {
+		fault = &HandlerFault{
+			Handler:   handler,
+			EventType: eventType,
+			Count:     0,
+			LastError: "",
+			FirstAt:   now,
+			LastAt:    now,
+		}
+		f.faults[key] = fault
+	}
+	fault.Count++
+	fault.LastError = redact.Error(err)
+	fault.LastAt = now
+	f.total++
+
+	if f.budget.MaxErrors <= 0 {
+		return int(f.total), false
+	}
+	f.recent = append(f.recent, now)
+	if f.budget.Window > 0 {
+		cutoff := now.Add(-f.budget.Window)
+		drop := 0
+		for drop < len(f.recent) && f.recent[drop].Before(cutoff) {
+			drop++
+		}
+		f.recent = f.recent[drop:]
+	}
+	if len(f.recent) > f.budget.MaxErrors {
+		f.recent = f.recent[len(f.recent)-f.budget.MaxErrors:]
+	}
+	count := len(f.recent)
+	if count < f.budget.MaxErrors || f.exhausted {
+		return count, false
+	}
+	f.exhausted = true
+	return count, true
+}
+
+func (f *handlerFaults) snapshot() []HandlerFault {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]HandlerFault, 0, len(f.faults))
+	for _, fault := range f.faults {
+		out = append(out, *fault)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Handler != out[j].Handler {
+			return out[i].Handler < out[j].Handler
+		}
+		return out[i].EventType < out[j].EventType
+	})
+	return out
+}
+
+// BudgetExhaustedError is reported to the exhaustion handler when a strategy raised more
+// handler exceptions than its budget allows.
+type BudgetExhaustedError struct {
+	Strategy string
+	Budget   ErrorBudget
+	Handler  string
+	Cause    error
+}
+
+func (e *BudgetExhaustedError) Error() string {
+	window := "instance lifetime"
+	if e.Budget.Window > 0 {
+		window = e.Budget.Window.String()
+	}
+	return fmt.Sprintf("js strategy %s: error budget of %d exceptions per %s exhausted (last in %s: %v)",
+		e.Strategy, e.Budget.MaxErrors, window, e.Handler, e.Cause)
+}
+
+func (e *BudgetExhaustedError) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,129 @@
+package js
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+const faultyModule = `
+module.exports = {
+  metadata: {
+    name: "faulty",
+    version: "1.0.0",
+    displayName: "Faulty",
+    description: "Throws on every odd trade.",
+    config: [],
+    events: ["Trade"]
+  },
+  create: function(env) {
+    var seen = 0;
+    return {
+      onTrade: function() {
+        seen++;
+        if (seen % 2 === 1) {
+          throw new Error("boom " + seen);
+        }
+      },
+      seen: function() { return seen; }
+    };
+  }
+};
+`
+
+func newFaultyStrategy(t *testing.T) *Strategy {
+	t.Helper()
+	dir := t.TempDir()
+	modulePath := writeVersionedModule(t, dir, "faulty", "v1.0.0", []byte(faultyModule))
+	writeRegistry(t, dir, "faulty", "v1.0.0", modulePath)
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	if err := loader.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	module, err := loader.Get("faulty")
+	if err != nil {
+		t.Fatalf("Get faulty: %v", err)
+	}
+	strat, err := NewStrategy(module, nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("NewStrategy: %v", err)
+	}
+	t.Cleanup(strat.Close)
+	return strat
+}
+
+func TestStrategyIsolatesHandlerExceptionsUntilBudgetExhausted(t *testing.T) {
+	strat := newFaultyStrategy(t)
+	var exhausted []error
+	strat.SetErrorBudget(ErrorBudget{MaxErrors: 3, Window: 0}, func(err error) {
+		exhausted = append(exhausted, err)
+	})
+
+	evt := &schema.Event{Type: schema.EventTypeTrade, Provider: "binance", Symbol: "BTC-USDT"}
+	for i := 0; i < 5; i++ {
+		strat.OnTrade(context.Background(), evt, schema.TradePayload{}, 1)
+		if i < 4 && len(exhausted) != 0 {
+			t.Fatalf("budget exhausted early after %d trades", i+1)
+		}
+	}
+	if len(exhausted) != 1 {
+		t.Fatalf("expected budget exhaustion to be reported once, got %d", len(exhausted))
+	}
+	var budgetErr *BudgetExhaustedError
+	if !errors.As(exhausted[0], &budgetErr) || budgetErr.Handler != "onTrade" {
+		t.Fatalf("unexpected exhaustion error %v", exhausted[0])
+	}
+
+	// Even trades still reached the handler after earlier ones threw.
+	seen, err := strat.instance.CallMethod(strat.handler, "seen")
+	if err != nil || seen.ToInteger() != 5 {
+		t.Fatalf("expected handler to observe 5 trades, got %v (%v)", seen, err)
+	}
+
+	faults := strat.HandlerFaults()
+	if len(faults) != 1 || faults[0].Handler != "onTrade" || faults[0].EventType != schema.EventTypeTrade || faults[0].Count != 3 {
+		t.Fatalf("unexpected faults %+v", faults)
+	}
+	if faults[0].LastError == "" {
+		t.Fatal("expected last error to be recorded")
+	}
+}
+
+func TestHandlerFaultsBudgetWindowSlides(t *testing.T) {
+	faults := newHandlerFaults(ErrorBudget{MaxErrors: 2, Window: time.Minute})
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	faults.clock = func() time.Time { return now }
+	cause := errors.New("boom")
+
+	if _, exhausted := faults.record("onTrade", schema.EventTypeTrade, cause); exhausted {
+		t.Fatal("first exception must not exhaust the budget")
+	}
+	now = now.Add(2 * time.Minute)
+	if count, exhausted := faults.record("onTicker", schema.EventTypeTicker, cause); exhausted || count != 1 {
+		t.Fatalf("expected expired exception to be dropped, count=%d exhausted=%v", count, exhausted)
+	}
+	now = now.Add(time.Second)
+	if _, exhausted := faults.record("onTicker", schema.EventTypeTicker, cause); !exhausted {
+		t.Fatal("expected budget to be exhausted within the window")
+	}
+}
+
+func TestHandlerFaultsUnlimitedBudgetNeverExhausts(t *testing.T) {
+	faults := newHandlerFaults(ErrorBudget{MaxErrors: -1, Window: 0})
+	for i := 0; i < 100; i++ {
+		if _, exhausted := faults.record("onTrade", schema.EventTypeTrade, errors.New("boom")); exhausted {
+			t.Fatal("negative budget must never exhaust")
+		}
+	}
+	if got := faults.snapshot()[0].Count; got != 100 {
+		t.Fatalf("expected 100 recorded faults, got %d", got)
+	}
+}
@@ -0,0 +1,206 @@
+package js
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultModuleSearchLimit caps the matches returned when the caller does not set a limit.
+	DefaultModuleSearchLimit = 50
+	maxSourceHitsPerRevision = 5
+	maxSourceSnippetRunes    = 160
+)
+
+// Fields reported by module search hits.
+const (
+	SearchFieldName              = "name"
+	SearchFieldDisplayName       = "displayName"
+	SearchFieldDescription       = "description"
+	SearchFieldConfig            = "config"
+	SearchFieldConfigDescription = "configDescription"
+	SearchFieldSource            = "source"
+)
+
+var searchFieldWeights = map[string]int{
+	SearchFieldName:              40,
+	SearchFieldDisplayName:       30,
+	SearchFieldDescription:       20,
+	SearchFieldConfig:            25,
+	SearchFieldConfigDescription: 10,
+	SearchFieldSource:            5,
+}
+
+// exactNameBonus ranks a revision whose name equals a query term above partial matches.
+const exactNameBonus = 60
+
+// ModuleSearchOptions describes a search across loaded module revisions.
+type ModuleSearchOptions struct {
+	// Query holds whitespace-separated terms; every term must match somewhere in a revision.
+	Query string
+	// IncludeSource also scans the JavaScript source of each revision.
+	IncludeSource bool
+	// Limit caps the number of matches; zero applies DefaultModuleSearchLimit.
+	Limit int
+}
+
+// ModuleSearchHit records one field of a revision that matched a query term.
+type ModuleSearchHit struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+	Line  int    `json:"line,omitempty"`
+}
+
+// ModuleSearchMatch is a module revision matching a search, ranked by Score.
+type ModuleSearchMatch struct {
+	Name        string            `json:"name"`
+	Hash        string            `json:"hash"`
+	Tag         string            `json:"tag,omitempty"`
+	Latest      bool              `json:"latest"`
+	DisplayName string            `json:"displayName"`
+	Score       int               `json:"score"`
+	Hits        []ModuleSearchHit `json:"hits"`
+}
+
+// Search ranks the loaded module revisions against the query terms, matching case-insensitively on
+// strategy names, display names, descriptions, config field names and descriptions and, when
+// requested, source text. Revisions missing any term are excluded.
+func (l *Loader) Search(opts ModuleSearchOptions) []ModuleSearchMatch {
+	terms := strings.Fields(strings.ToLower(opts.Query))
+	if l == nil || len(terms) == 0 {
+		return []ModuleSearchMatch{}
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultModuleSearchLimit
+	}
+
+	type candidate struct {
+		name   string
+		latest bool
+		module *Module
+	}
+	l.mu.RLock()
+	candidates := make([]candidate, 0, len(l.byHash))
+	for name, revisions := range l.modulesByName {
+		latest := l.byName[name]
+		for _, module := range revisions {
+			candidates = append(candidates, candidate{name: name, latest: module == latest, module: module})
+		}
+	}
+	l.mu.RUnlock()
+
+	matches := make([]ModuleSearchMatch, 0)
+	for _, c := range candidates {
+		var source []byte
+		if opts.IncludeSource {
+			// #nosec G304
+			if data, err := os.ReadFile(c.module.Path); err == nil {
+				source = data
+			}
+		}
+		match, ok := searchRevision(c.name, c.module, source, terms)
+		if !ok {
+			continue
+		}
+		match.Latest = c.latest
+		matches = append(matches, match)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if matches[i].Latest != matches[j].Latest {
+			return matches[i].Latest
+		}
+		if matches[i].Name != matches[j].Name {
+			return matches[i].Name < matches[j].Name
+		}
+		return matches[i].Hash < matches[j].Hash
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func searchRevision(name string, module *Module, source []byte, terms []string) (ModuleSearchMatch, bool) {
+	meta := module.Metadata
+	match := ModuleSearchMatch{
+		Name:        name,
+		Hash:        module.Hash,
+		Tag:         module.Tag,
+		Latest:      false,
+		DisplayName: meta.DisplayName,
+		Score:       0,
+		Hits:        nil,
+	}
+	seen := make(map[ModuleSearchHit]struct{})
+	record := func(hit ModuleSearchHit) {
+		if _, ok := seen[hit]; ok {
+			return
+		}
+		seen[hit] = struct{}{}
+		match.Hits = append(match.Hits, hit)
+		match.Score += searchFieldWeights[hit.Field]
+	}
+	for _, term := range terms {
+		found := false
+		check := func(field, value string) {
+			if value != "" && strings.Contains(strings.ToLower(value), term) {
+				found = true
+				record(ModuleSearchHit{Field: field, Value: value, Line: 0})
+			}
+		}
+		check(SearchFieldName, name)
+		if strings.EqualFold(name, term) {
+			match.Score += exactNameBonus
+		}
+		check(SearchFieldDisplayName, meta.DisplayName)
+		check(SearchFieldDescription, meta.Description)
+		for _, field := range meta.Config {
+			check(SearchFieldConfig, field.Name)
+			if field.Description != "" && strings.Contains(strings.ToLower(field.Description), term) {
+				found = true
+				record(ModuleSearchHit{Field: SearchFieldConfigDescription, Value: field.Name, Line: 0})
+			}
+		}
+		for _, hit := range searchSource(source, term) {
+			found = true
+			record(hit)
+		}
+		if !found {
+			var empty ModuleSearchMatch
+			return empty, false
+		}
+	}
+	return match, true
+}
+
+// searchSource returns the first source lines containing term, trimmed to short snippets.
+func searchSource(source []byte, term string) []ModuleSearchHit {
+	if len(source) == 0 {
+		return nil
+	}
+	var hits []ModuleSearchHit
+	scanner := bufio.NewScanner(bytes.NewReader(source))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if !strings.Contains(strings.ToLower(text), term) {
+			continue
+		}
+		snippet := []rune(strings.TrimSpace(text))
+		if len(snippet) > maxSourceSnippetRunes {
+			snippet = snippet[:maxSourceSnippetRunes]
+		}
+		hits = append(hits, ModuleSearchHit{Field: SearchFieldSource, Value: string(snippet), Line: line})
+		if len(hits) == maxSourceHitsPerRevision {
+			break
+		}
+	}
+	return hits
+}
@@ -0,0 +1,99 @@
+package js
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const gridModule = `
+module.exports = {
+  metadata: {
+    name: "grid",
+    tag: "v1.0.0",
+    displayName: "Grid Maker",
+    description: "Places a ladder of resting orders",
+    config: [
+      { name: "levels", type: "int", description: "Number of grid levels", required: true },
+      { name: "spacing_bps", type: "float", description: "Distance between levels", required: true }
+    ],
+    events: ["Trade"]
+  },
+  create: function() {
+    var symbol = "ETH-USDT";
+    return { onTrade: function() { return symbol; } };
+  }
+};
+`
+
+func newSearchLoader(t *testing.T) *Loader {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "registry.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write registry stub: %v", err)
+	}
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	for _, source := range []string{sampleModule, gridModule} {
+		if _, err := loader.Store([]byte(source), ModuleWriteOptions{PromoteLatest: true}); err != nil {
+			t.Fatalf("Store: %v", err)
+		}
+	}
+	if err := loader.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	return loader
+}
+
+func TestLoaderSearchRanksMetadataMatches(t *testing.T) {
+	loader := newSearchLoader(t)
+
+	matches := loader.Search(ModuleSearchOptions{Query: "levels", IncludeSource: false, Limit: 0})
+	if len(matches) != 1 || matches[0].Name != "grid" || !matches[0].Latest {
+		t.Fatalf("expected grid match, got %+v", matches)
+	}
+	fields := make(map[string]bool)
+	for _, hit := range matches[0].Hits {
+		fields[hit.Field] = true
+	}
+	if !fields[SearchFieldConfig] || !fields[SearchFieldConfigDescription] {
+		t.Fatalf("expected config hits, got %+v", matches[0].Hits)
+	}
+
+	matches = loader.Search(ModuleSearchOptions{Query: "Grid", IncludeSource: false, Limit: 0})
+	if len(matches) != 1 || matches[0].Score <= searchFieldWeights[SearchFieldName] {
+		t.Fatalf("expected exact name bonus, got %+v", matches)
+	}
+
+	matches = loader.Search(ModuleSearchOptions{Query: "operation strategy", IncludeSource: false, Limit: 0})
+	if len(matches) != 1 || matches[0].Name != "noop" {
+		t.Fatalf("expected all terms to match noop, got %+v", matches)
+	}
+	if got := loader.Search(ModuleSearchOptions{Query: "grid operation", IncludeSource: false, Limit: 0}); len(got) != 0 {
+		t.Fatalf("expected no revision matching both terms, got %+v", got)
+	}
+}
+
+func TestLoaderSearchSource(t *testing.T) {
+	loader := newSearchLoader(t)
+
+	if got := loader.Search(ModuleSearchOptions{Query: "eth-usdt", IncludeSource: false, Limit: 0}); len(got) != 0 {
+		t.Fatalf("source must not be searched unless requested, got %+v", got)
+	}
+	matches := loader.Search(ModuleSearchOptions{Query: "eth-usdt", IncludeSource: true, Limit: 0})
+	if len(matches) != 1 || len(matches[0].Hits) != 1 {
+		t.Fatalf("expected one source hit, got %+v", matches)
+	}
+	hit := matches[0].Hits[0]
+	if hit.Field != SearchFieldSource || hit.Line == 0 || !strings.Contains(hit.Value, "ETH-USDT") {
+		t.Fatalf("unexpected source hit %+v", hit)
+	}
+
+	if got := loader.Search(ModuleSearchOptions{Query: "return", IncludeSource: true, Limit: 1}); len(got) != 1 {
+		t.Fatalf("expected limit to apply, got %d matches", len(got))
+	}
+}
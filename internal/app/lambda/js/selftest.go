@@ -0,0 +1,487 @@
+package js
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dop251/goja"
+	json "github.com/goccy/go-json"
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+const (
+	// SelfTestTimeout bounds the handler time of one inline test.
+	SelfTestTimeout = 2 * time.Second
+
+	selfTestLambdaID = "selftest"
+	// Inline tests run against this provider and symbol unless they name their own.
+	selfTestProvider = "selftest"
+	selfTestSymbol   = "BTC-USDT"
+	selfTestSeed     = int64(1)
+	selfTestPoolSize = 64
+)
+
+var (
+	// ErrSelfTestsInvalid is returned when exports.tests cannot be read as a list of tests.
+	ErrSelfTestsInvalid = errors.New("strategy self-tests invalid")
+	// ErrSelfTestsFailed is returned when a revision failing its inline tests is written under
+	// the latest tag.
+	ErrSelfTestsFailed = errors.New("strategy self-tests failed")
+)
+
+// SelfTestOrder is an order placed by a strategy under test. As an expectation, empty fields
+// match any value.
+type SelfTestOrder struct {
+	Provider string            `json:"provider,omitempty"`
+	Symbol   string            `json:"symbol,omitempty"`
+	Side     string            `json:"side,omitempty"`
+	Type     string            `json:"type,omitempty"`
+	Quantity string            `json:"quantity,omitempty"`
+	Price    string            `json:"price,omitempty"`
+	TIF      string            `json:"tif,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// SelfTestResult is the outcome of one inline test.
+type SelfTestResult struct {
+	Name     string          `json:"name"`
+	Passed   bool            `json:"passed"`
+	Failures []string        `json:"failures,omitempty"`
+	Orders   []SelfTestOrder `json:"orders"`
+	Logs     []string        `json:"logs,omitempty"`
+}
+
+// SelfTestReport is the outcome of the inline tests a module ships in exports.tests.
+type SelfTestReport struct {
+	Passed  bool             `json:"passed"`
+	Total   int              `json:"total"`
+	Failed  int              `json:"failed"`
+	Results []SelfTestResult `json:"results"`
+}
+
+// selfTest is one entry of exports.tests: synthetic events fed to a fresh strategy and the orders
+// and log lines they are expected to produce.
+type selfTest struct {
+	Name     string          `json:"name"`
+	Config   map[string]any  `json:"config"`
+	Provider string          `json:"provider"`
+	Symbol   string          `json:"symbol"`
+	Events   []selfTestEvent `json:"events"`
+	Expect   struct {
+		// Orders is compared in full when present; an empty list expects no orders.
+		Orders *[]SelfTestOrder `json:"orders"`
+		// Logs lists substrings each of which must appear in some log line.
+		Logs []string `json:"logs"`
+	} `json:"expect"`
+}
+
+type selfTestEvent struct {
+	Type schema.EventType `json:"type"`
+	// Symbol overrides the test symbol, e.g. with the currency of a balance update.
+	Symbol  string          `json:"symbol"`
+	Time    *time.Time      `json:"time"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// RunSelfTests runs the inline tests of module, each against a fresh sandboxed strategy whose
+// orders are recorded instead of sent. It returns nil when the module exports no tests.
+func RunSelfTests(module *Module) (*SelfTestReport, error) {
+	if module == nil {
+		return nil, fmt.Errorf("js self-tests: module required")
+	}
+	tests, err := readSelfTests(module)
+	if err != nil || len(tests) == 0 {
+		return nil, err
+	}
+	report := &SelfTestReport{Passed: true, Total: len(tests), Failed: 0, Results: make([]SelfTestResult, 0, len(tests))}
+	for i, test := range tests {
+		result := runSelfTest(module, test)
+		if result.Name == "" {
+			result.Name = fmt.Sprintf("test %d", i+1)
+		}
+		if !result.Passed {
+			report.Passed = false
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// failure reports the failed tests as diagnostics, for writes the failure blocks.
+func (r *SelfTestReport) failure() error {
+	diagnostics := make([]Diagnostic, 0, r.Failed)
+	for _, result := range r.Results {
+		if result.Passed {
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Stage:    DiagnosticStageValidation,
+			Severity: DiagnosticSeverityError,
+			Message:  fmt.Sprintf("%s: %s", result.Name, strings.Join(result.Failures, "; ")),
+			Line:     0,
+			Column:   0,
+			Hint:     "Fix the strategy or its tests, or upload under a tag other than latest.",
+		})
+	}
+	return NewDiagnosticError(fmt.Sprintf("%d of %d inline tests failed", r.Failed, r.Total), ErrSelfTestsFailed, diagnostics...)
+}
+
+// readSelfTests runs the module in a scratch VM and decodes exports.tests through JSON, so
+// tests are plain data whatever the module computes them from.
+func readSelfTests(module *Module) ([]selfTest, error) {
+	rt := goja.New()
+	exports, err := runModule(rt, module.Program)
+	if err != nil {
+		return nil, err
+	}
+	raw := exports.Get("tests")
+	if raw == nil || goja.IsUndefined(raw) || goja.IsNull(raw) {
+		return nil, nil
+	}
+	stringify, ok := goja.AssertFunction(rt.Get("JSON").ToObject(rt).Get("stringify"))
+	if !ok {
+		return nil, invalidSelfTests(fmt.Errorf("JSON.stringify unavailable"))
+	}
+	encoded, err := stringify(goja.Undefined(), raw)
+	if err != nil {
+		return nil, invalidSelfTests(err)
+	}
+	var tests []selfTest
+	if err := json.Unmarshal([]byte(encoded.String()), &tests); err != nil {
+		return nil, invalidSelfTests(err)
+	}
+	return tests, nil
+}
+
+func invalidSelfTests(cause error) error {
+	return NewDiagnosticError("tests export invalid", fmt.Errorf("%w: %w", ErrSelfTestsInvalid, cause), Diagnostic{
+		Stage:    DiagnosticStageValidation,
+		Severity: DiagnosticSeverityError,
+		Message:  diagnosticMessage(cause),
+		Line:     0,
+		Column:   0,
+		Hint:     "Export module.exports.tests as a list of {name, config, events, expect} objects.",
+	})
+}
+
+func runSelfTest(module *Module, test selfTest) SelfTestResult {
+	result := SelfTestResult{Name: strings.TrimSpace(test.Name), Passed: false, Failures: nil, Orders: []SelfTestOrder{}, Logs: nil}
+	fail := func(format string, args ...any) {
+		result.Failures = append(result.Failures, fmt.Sprintf(format, args...))
+	}
+	provider := strings.TrimSpace(test.Provider)
+	if provider == "" {
+		provider = selfTestProvider
+	}
+	symbol := strings.ToUpper(strings.TrimSpace(test.Symbol))
+	if symbol == "" {
+		symbol = selfTestSymbol
+	}
+	steps := make([]selfTestStep, 0, len(test.Events))
+	for i, spec := range test.Events {
+		step, err := selfTestStepFor(spec, i, provider, symbol)
+		if err != nil {
+			fail("event %d: %v", i, err)
+			return result
+		}
+		steps = append(steps, step)
+	}
+
+	pools := pool.NewPoolManager()
+	defer func() {
+		_ = pools.Shutdown(context.Background())
+	}()
+	if err := pools.RegisterPool("Event", selfTestPoolSize, 0, func() any { return new(schema.Event) }); err != nil {
+		fail("register event pool: %v", err)
+		return result
+	}
+	if err := pools.RegisterPool("OrderRequest", selfTestPoolSize, 0, func() any { return new(schema.OrderRequest) }); err != nil {
+		fail("register order pool: %v", err)
+		return result
+	}
+
+	cfg := cloneConfig(test.Config)
+	if _, ok := SeedFromConfig(cfg); !ok {
+		cfg[SeedConfigKey] = selfTestSeed
+	}
+	discard := log.New(io.Discard, "", 0)
+	strategy, err := NewStrategy(module, cfg, discard)
+	if err != nil {
+		fail("create: %v", err)
+		return result
+	}
+	defer strategy.Close()
+	var logsMu sync.Mutex
+	strategy.SetLogFunc(func(_, _, message string) {
+		logsMu.Lock()
+		result.Logs = append(result.Logs, message)
+		logsMu.Unlock()
+	})
+
+	recorder := &selfTestRecorder{mu: sync.Mutex{}, orders: nil}
+	lambda := core.NewBaseLambda(selfTestLambdaID, core.Config{
+		Providers:           []string{provider},
+		ProviderSymbols:     map[string][]string{provider: {symbol}},
+		DryRun:              false,
+		Delivery:            core.DeliveryConfig{},
+		DurableSubscription: false,
+		SubAccounts:         nil,
+		History:             core.HistoryConfig{},
+		Orders:              core.OrderHistoryConfig{},
+		BookDeltas:          core.BookDeltaConfig{},
+		ThrottleQueue:       core.ThrottleQueueConfig{Enabled: false, MaxDepth: 0, Expiry: 0},
+		Warmup:              core.WarmupConfig{},
+		FreezeBuffer:        0,
+		OrderCallbacks:      core.OrderCallbackConfig{},
+	}, nil, recorder, pools, strategy, nil, nil)
+	lambda.SetLogger(discard)
+	strategy.Attach(lambda)
+	lambda.EnableTrading(true)
+
+	// A handler that never returns is interrupted and the remaining events are skipped.
+	var timedOut atomic.Bool
+	watchdog := time.AfterFunc(SelfTestTimeout, func() {
+		timedOut.Store(true)
+		strategy.instance.rt.Interrupt("self-test timed out")
+	})
+	ctx := context.Background()
+	for i, step := range steps {
+		if timedOut.Load() {
+			break
+		}
+		evt, err := pools.BorrowEventInst(ctx)
+		if err != nil {
+			fail("event %d: borrow event: %v", i, err)
+			break
+		}
+		step.fill(evt, i)
+		lambda.Replay(ctx, evt)
+	}
+	watchdog.Stop()
+	if timedOut.Load() {
+		fail("timed out after %s", SelfTestTimeout)
+	}
+	for _, fault := range strategy.HandlerFaults() {
+		fail("%s raised %d exception(s): %s", fault.Handler, fault.Count, fault.LastError)
+	}
+
+	result.Orders = recorder.snapshot()
+	if test.Expect.Orders != nil {
+		expected := *test.Expect.Orders
+		if len(result.Orders) != len(expected) {
+			fail("expected %d order(s), got %d", len(expected), len(result.Orders))
+		}
+		for i := 0; i < len(expected) && i < len(result.Orders); i++ {
+			if mismatch := expected[i].mismatch(result.Orders[i]); mismatch != "" {
+				fail("order %d: %s", i, mismatch)
+			}
+		}
+	}
+	logsMu.Lock()
+	for _, want := range test.Expect.Logs {
+		if !containsLog(result.Logs, want) {
+			fail("no log line contains %q", want)
+		}
+	}
+	logsMu.Unlock()
+	result.Passed = len(result.Failures) == 0
+	return result
+}
+
+// selfTestStep is a decoded test event.
+type selfTestStep struct {
+	typ      schema.EventType
+	provider string
+	symbol   string
+	at       time.Time
+	payload  any
+}
+
+// selfTestStepFor decodes a test event. Events without a time are spaced a second apart so the
+// strategy clock advances.
+func selfTestStepFor(spec selfTestEvent, index int, provider, symbol string) (selfTestStep, error) {
+	var empty selfTestStep
+	payload, err := selfTestPayload(spec.Type, spec.Payload)
+	if err != nil {
+		return empty, err
+	}
+	step := selfTestStep{
+		typ:      spec.Type,
+		provider: provider,
+		symbol:   symbol,
+		at:       time.Date(2024, time.January, 1, 0, 0, index, 0, time.UTC),
+		payload:  payload,
+	}
+	if spec.Time != nil {
+		step.at = spec.Time.UTC()
+	}
+	if s := strings.TrimSpace(spec.Symbol); s != "" {
+		step.symbol = strings.ToUpper(s)
+	}
+	return step, nil
+}
+
+func (s selfTestStep) fill(evt *schema.Event, index int) {
+	evt.EventID = fmt.Sprintf("%s:%d", selfTestLambdaID, index)
+	evt.Provider = s.provider
+	evt.Symbol = s.symbol
+	evt.Type = s.typ
+	evt.SeqProvider = uint64(index) + 1 // #nosec G115 -- index is non-negative
+	evt.EventTS = s.at
+	evt.IngestTS = s.at
+	evt.EmitTS = s.at
+	evt.Payload = s.payload
+}
+
+func selfTestPayload(typ schema.EventType, raw json.RawMessage) (any, error) {
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	var target any
+	switch typ {
+	case schema.EventTypeTrade:
+		target = new(schema.TradePayload)
+	case schema.EventTypeTicker:
+		target = new(schema.TickerPayload)
+	case schema.EventTypeBookSnapshot:
+		target = new(schema.BookSnapshotPayload)
+	case schema.EventTypeBookDelta:
+		target = new(schema.BookDeltaPayload)
+	case schema.EventTypeExecReport:
+		target = new(schema.ExecReportPayload)
+	case schema.EventTypeKlineSummary:
+		target = new(schema.KlineSummaryPayload)
+	case schema.EventTypeInstrumentUpdate:
+		target = new(schema.InstrumentUpdatePayload)
+	case schema.EventTypeBalanceUpdate:
+		target = new(schema.BalanceUpdatePayload)
+	case schema.EventTypeRiskControl:
+		target = new(schema.RiskControlPayload)
+	case schema.ExtensionEventType:
+		var payload any
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return nil, fmt.Errorf("decode %s payload: %w", typ, err)
+		}
+		return payload, nil
+	default:
+		return nil, fmt.Errorf("unsupported event type %q", typ)
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return nil, fmt.Errorf("decode %s payload: %w", typ, err)
+	}
+	return reflect.ValueOf(target).Elem().Interface(), nil
+}
+
+// mismatch describes how got differs from the fields set on the expectation.
+func (want SelfTestOrder) mismatch(got SelfTestOrder) string {
+	fields := []struct{ name, want, got string }{
+		{"provider", want.Provider, got.Provider},
+		{"symbol", strings.ToUpper(want.Symbol), got.Symbol},
+		{"side", strings.ToLower(want.Side), got.Side},
+		{"type", strings.ToLower(want.Type), got.Type},
+		{"quantity", want.Quantity, got.Quantity},
+		{"price", want.Price, got.Price},
+		{"tif", strings.ToUpper(want.TIF), got.TIF},
+	}
+	var diffs []string
+	for _, field := range fields {
+		if field.want != "" && !decimalEqual(field.want, field.got) {
+			diffs = append(diffs, fmt.Sprintf("%s %q, want %q", field.name, field.got, field.want))
+		}
+	}
+	for _, tag := range want.Tags {
+		if !containsString(got.Tags, tag) {
+			diffs = append(diffs, fmt.Sprintf("missing tag %q", tag))
+		}
+	}
+	for key, value := range want.Metadata {
+		if got.Metadata[key] != value {
+			diffs = append(diffs, fmt.Sprintf("metadata %s %q, want %q", key, got.Metadata[key], value))
+		}
+	}
+	return strings.Join(diffs, ", ")
+}
+
+// decimalEqual compares quantities and prices numerically, so "1" matches "1.0".
+func decimalEqual(want, got string) bool {
+	if want == got {
+		return true
+	}
+	a, err := decimal.NewFromString(strings.TrimSpace(want))
+	if err != nil {
+		return false
+	}
+	b, err := decimal.NewFromString(strings.TrimSpace(got))
+	return err == nil && a.Equal(b)
+}
+
+func containsLog(lines []string, want string) bool {
+	for _, line := range lines {
+		if strings.Contains(line, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
+
+// selfTestRecorder stands in for the venue, recording the orders that pass the lambda's checks.
+type selfTestRecorder struct {
+	mu     sync.Mutex
+	orders []SelfTestOrder
+}
+
+func (r *selfTestRecorder) SubmitOrder(_ context.Context, req schema.OrderRequest) error {
+	order := SelfTestOrder{
+		Provider: req.Provider,
+		Symbol:   req.Symbol,
+		Side:     strings.ToLower(string(req.Side)),
+		Type:     strings.ToLower(string(req.OrderType)),
+		Quantity: req.Quantity,
+		Price:    "",
+		TIF:      string(req.TIF),
+		Tags:     append([]string(nil), req.Tags...),
+		Metadata: nil,
+	}
+	if req.Price != nil {
+		order.Price = *req.Price
+	}
+	if len(req.Metadata) > 0 {
+		order.Metadata = make(map[string]string, len(req.Metadata))
+		for key, value := range req.Metadata {
+			order.Metadata[key] = value
+		}
+	}
+	r.mu.Lock()
+	r.orders = append(r.orders, order)
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *selfTestRecorder) snapshot() []SelfTestOrder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]SelfTestOrder{}, r.orders...)
+}
@@ -0,0 +1,141 @@
+package js
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const selfTestModule = `
+module.exports = {
+  metadata: {
+    name: "dip_buyer",
+    tag: "v1",
+    displayName: "Dip Buyer",
+    description: "Buys trades below a threshold.",
+    config: [{ name: "below", type: "number", default: 100 }],
+    events: ["Trade"]
+  },
+  tests: [
+    {
+      name: "buys the dip",
+      config: { below: 100 },
+      events: [
+        { type: "Trade", payload: { price: "105", quantity: "1" } },
+        { type: "Trade", payload: { price: "95", quantity: "1" } }
+      ],
+      expect: {
+        orders: [{ side: "buy", type: "limit", quantity: "1", price: "95.0", tags: ["dip"] }],
+        logs: ["dip at 95"]
+      }
+    },
+    {
+      name: "ignores rallies",
+      events: [{ type: "Trade", payload: { price: "120", quantity: "1" } }],
+      expect: { orders: [] }
+    }
+  ],
+  create: function(env) {
+    var below = env.config.below || 100;
+    return {
+      onTrade: function(ctx, evt, payload, price) {
+        if (price < below) {
+          env.helpers.log("dip at " + price);
+          env.runtime.submitOrder("", "buy", "1", payload.price, { tags: ["dip"] });
+        }
+      }
+    };
+  }
+};
+`
+
+func TestRunSelfTestsReportsResults(t *testing.T) {
+	module, err := compileSource("dip_buyer.js", []byte(selfTestModule), int64(len(selfTestModule)))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	report, err := RunSelfTests(module)
+	if err != nil {
+		t.Fatalf("RunSelfTests: %v", err)
+	}
+	if report == nil || !report.Passed || report.Total != 2 || report.Failed != 0 {
+		t.Fatalf("expected both tests to pass, got %+v", report)
+	}
+	if orders := report.Results[0].Orders; len(orders) != 1 || orders[0].Symbol != selfTestSymbol || orders[0].Provider != selfTestProvider {
+		t.Fatalf("unexpected recorded orders %+v", orders)
+	}
+
+	broken := strings.Replace(selfTestModule, `price: "95.0"`, `price: "90"`, 1)
+	module, err = compileSource("dip_buyer.js", []byte(broken), int64(len(broken)))
+	if err != nil {
+		t.Fatalf("compile broken: %v", err)
+	}
+	report, err = RunSelfTests(module)
+	if err != nil {
+		t.Fatalf("RunSelfTests broken: %v", err)
+	}
+	if report.Passed || report.Failed != 1 || report.Results[0].Passed || !strings.Contains(report.Results[0].Failures[0], "price") {
+		t.Fatalf("expected the price mismatch to fail the first test, got %+v", report)
+	}
+}
+
+func TestRunSelfTestsInterruptsRunawayHandlers(t *testing.T) {
+	source := strings.Replace(selfTestModule, `if (price < below) {`, `while (true) {}
+        if (price < below) {`, 1)
+	module, err := compileSource("dip_buyer.js", []byte(source), int64(len(source)))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	report, err := RunSelfTests(module)
+	if err != nil {
+		t.Fatalf("RunSelfTests: %v", err)
+	}
+	if report.Passed || !strings.Contains(strings.Join(report.Results[0].Failures, ";"), "timed out") {
+		t.Fatalf("expected a timeout failure, got %+v", report.Results[0])
+	}
+}
+
+func TestStoreWithFailingSelfTestsKeepsLatest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "registry.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write registry stub: %v", err)
+	}
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	first, err := loader.Store([]byte(selfTestModule), ModuleWriteOptions{PromoteLatest: true})
+	if err != nil {
+		t.Fatalf("Store passing: %v", err)
+	}
+	if first.Tests == nil || !first.Tests.Passed {
+		t.Fatalf("expected passing tests in the resolution, got %+v", first.Tests)
+	}
+
+	failing := strings.Replace(strings.Replace(selfTestModule, `price: "95.0"`, `price: "90"`, 1), `tag: "v1"`, `tag: "v2"`, 1)
+	second, err := loader.Store([]byte(failing), ModuleWriteOptions{PromoteLatest: true})
+	if err != nil {
+		t.Fatalf("Store failing: %v", err)
+	}
+	if second.Tests == nil || second.Tests.Passed {
+		t.Fatalf("expected failing tests in the resolution, got %+v", second.Tests)
+	}
+	snapshot, err := loader.RegistrySnapshot()
+	if err != nil {
+		t.Fatalf("RegistrySnapshot: %v", err)
+	}
+	tags := snapshot[first.Name].Tags
+	if tags["latest"] != first.Hash || tags["v2"] != second.Hash {
+		t.Fatalf("expected latest to stay on %s and v2 on %s, got %v", first.Hash, second.Hash, tags)
+	}
+
+	_, err = loader.Store([]byte(failing), ModuleWriteOptions{Tag: "latest", PromoteLatest: true})
+	if !errors.Is(err, ErrSelfTestsFailed) {
+		t.Fatalf("expected ErrSelfTestsFailed writing under latest, got %v", err)
+	}
+	if _, err := loader.Store([]byte(strings.Replace(selfTestModule, "tests: [", "tests: 7, unused: [", 1)), ModuleWriteOptions{}); !errors.Is(err, ErrSelfTestsInvalid) {
+		t.Fatalf("expected ErrSelfTestsInvalid, got %v", err)
+	}
+}
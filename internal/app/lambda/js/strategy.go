@@ -5,17 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/dop251/goja"
+	json "github.com/goccy/go-json"
 
+	"github.com/coachpo/meltica/internal/app/algo"
 	"github.com/coachpo/meltica/internal/app/lambda/core"
 	"github.com/coachpo/meltica/internal/app/lambda/strategies"
 	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+	"github.com/coachpo/meltica/internal/infra/redact"
 )
 
 // Strategy wraps a JavaScript strategy instance to satisfy core.TradingStrategy.
@@ -26,40 +30,59 @@ type Strategy struct {
 	logger              *log.Logger
 	runtime             *lambdaBridge
 	crossProviderEvents atomic.Bool
+	faults              *handlerFaults
+	seed                int64
+	clock               *eventClock
+	exhaustedMu         sync.Mutex
+	onExhausted         func(error)
+	logs                *logSink
 }
 
 type envConfig struct {
 	Config   map[string]any      `json:"config"`
+	Seed     int64               `json:"seed"`
 	Metadata strategies.Metadata `json:"metadata"`
 	Events   []schema.EventType  `json:"events"`
 	Helpers  map[string]any      `json:"helpers,omitempty"`
 	Runtime  map[string]any      `json:"runtime,omitempty"`
 }
 
-// NewStrategy instantiates a JavaScript strategy from the supplied module.
+// NewStrategy instantiates a JavaScript strategy from the supplied module. Math.random is
+// seeded from the config seed (a fresh one when absent) and Date follows the emit time of the
+// event being handled, so the same revision, seed and events produce the same decisions.
 func NewStrategy(module *Module, cfg map[string]any, logger *log.Logger) (*Strategy, error) {
 	if module == nil {
 		return nil, fmt.Errorf("js strategy: module required")
 	}
-	baseLogger := defaultStrategyLogger(logger)
-
-	instance, err := NewInstance(module)
+	seed, ok := SeedFromConfig(cfg)
+	if !ok {
+		seed = NewSeed()
+	}
+	vm, err := newWarmVM(module, seed)
 	if err != nil {
 		return nil, err
 	}
+	return newStrategy(module, cfg, logger, vm)
+}
 
+// newStrategy calls create on a VM whose module already ran and wraps the result.
+func newStrategy(module *Module, cfg map[string]any, logger *log.Logger, vm *warmVM) (*Strategy, error) {
+	baseLogger := defaultStrategyLogger(logger)
+	instance, seed, clock, logs := vm.instance, vm.seed, vm.clock, vm.logs
 	bridge := newLambdaBridge()
 
 	env := envConfig{
 		Config:   cloneConfig(cfg),
+		Seed:     seed,
 		Metadata: strategies.CloneMetadata(module.Metadata),
 		Events:   append([]schema.EventType(nil), module.Metadata.Events...),
 		Helpers:  map[string]any{},
 		Runtime:  bridge.helpers(),
 	}
 
-	env.Helpers["log"] = makeLogHelper(baseLogger)
+	env.Helpers["log"] = makeLogHelper(baseLogger, logs)
 	env.Helpers["sleep"] = makeSleepHelper()
+	env.Helpers["now"] = func() int64 { return clock.now().UnixMilli() }
 
 	value, err := instance.Call("create", env)
 	if err != nil {
@@ -90,6 +113,12 @@ func NewStrategy(module *Module, cfg map[string]any, logger *log.Logger) (*Strat
 		logger:              baseLogger,
 		runtime:             bridge,
 		crossProviderEvents: atomic.Bool{},
+		faults:              newHandlerFaults(DefaultBudget()),
+		seed:                seed,
+		clock:               clock,
+		exhaustedMu:         sync.Mutex{},
+		onExhausted:         nil,
+		logs:                logs,
 	}
 	strategy.crossProviderEvents.Store(strategy.detectCrossProviderPreference())
 	return strategy, nil
@@ -167,6 +196,11 @@ func (s *Strategy) OnBookSnapshot(ctx context.Context, evt *schema.Event, payloa
 	s.invoke("onBookSnapshot", ctx, evt, payload)
 }
 
+// OnBookDelta handles incremental book updates when the strategy subscribes to BookDelta events.
+func (s *Strategy) OnBookDelta(ctx context.Context, evt *schema.Event, payload schema.BookDeltaPayload) {
+	s.invoke("onBookDelta", ctx, evt, payload)
+}
+
 // OnKlineSummary handles kline summary events.
 func (s *Strategy) OnKlineSummary(ctx context.Context, evt *schema.Event, payload schema.KlineSummaryPayload) {
 	s.invoke("onKlineSummary", ctx, evt, payload)
@@ -212,6 +246,31 @@ func (s *Strategy) OnOrderExpired(ctx context.Context, evt *schema.Event, payloa
 	s.invoke("onOrderExpired", ctx, evt, payload)
 }
 
+// HandleOrderEvent calls the execution report callback matching the report state and returns the
+// exception it raised, so the host can retry reports whose handler threw.
+func (s *Strategy) HandleOrderEvent(ctx context.Context, evt *schema.Event, payload schema.ExecReportPayload) error {
+	switch payload.State {
+	case schema.ExecReportStateFILLED:
+		return s.call("onOrderFilled", ctx, evt, payload)
+	case schema.ExecReportStateREJECTED:
+		reason := ""
+		if payload.RejectReason != nil {
+			reason = *payload.RejectReason
+		}
+		return s.call("onOrderRejected", ctx, evt, payload, reason)
+	case schema.ExecReportStatePARTIAL:
+		return s.call("onOrderPartialFill", ctx, evt, payload)
+	case schema.ExecReportStateCANCELLED:
+		return s.call("onOrderCancelled", ctx, evt, payload)
+	case schema.ExecReportStateACK:
+		return s.call("onOrderAcknowledged", ctx, evt, payload)
+	case schema.ExecReportStateEXPIRED:
+		return s.call("onOrderExpired", ctx, evt, payload)
+	default:
+		return nil
+	}
+}
+
 // OnRiskControl handles risk notifications.
 func (s *Strategy) OnRiskControl(ctx context.Context, evt *schema.Event, payload schema.RiskControlPayload) {
 	s.invoke("onRiskControl", ctx, evt, payload)
@@ -222,16 +281,171 @@ func (s *Strategy) OnExtensionEvent(ctx context.Context, evt *schema.Event, payl
 	s.invoke("onExtensionEvent", ctx, evt, payload)
 }
 
-func (s *Strategy) invoke(method string, args ...any) {
+// SnapshotState returns what the handler's optional snapshotState() returns, so a freeze can
+// persist the strategy's working state. It returns nil when the handler has no snapshotState.
+func (s *Strategy) SnapshotState() (map[string]any, error) {
+	if s == nil {
+		return nil, nil
+	}
+	var state map[string]any
+	_, err := s.instance.Execute(func(_ *goja.Runtime, _ *goja.Object) (goja.Value, error) {
+		fn, ok := goja.AssertFunction(s.handler.Get("snapshotState"))
+		if !ok {
+			return nil, nil
+		}
+		value, err := fn(s.handler)
+		if err != nil {
+			return nil, err
+		}
+		if goja.IsUndefined(value) || goja.IsNull(value) {
+			return nil, nil
+		}
+		exported, ok := value.Export().(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("snapshotState must return an object")
+		}
+		state = exported
+		return nil, nil
+	})
+	if err != nil || state == nil {
+		return nil, err
+	}
+	// The state is persisted with the instance snapshot, so it must survive a JSON round trip.
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("snapshotState result not serializable: %w", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("snapshotState result not serializable: %w", err)
+	}
+	return decoded, nil
+}
+
+// RestoreState passes a state captured by SnapshotState to the handler's optional restoreState.
+func (s *Strategy) RestoreState(state map[string]any) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.instance.CallMethod(s.handler, "restoreState", state)
+	if errors.Is(err, ErrFunctionMissing) {
+		return nil
+	}
+	return err
+}
+
+// Seed reports the seed of the strategy's random source.
+func (s *Strategy) Seed() int64 {
+	if s == nil {
+		return 0
+	}
+	return s.seed
+}
+
+// SetErrorBudget configures how many handler exceptions the strategy tolerates before
+// onExhausted is called, once, with a *BudgetExhaustedError so the owner can stop the instance.
+func (s *Strategy) SetErrorBudget(budget ErrorBudget, onExhausted func(error)) {
 	if s == nil {
 		return
 	}
-	if _, err := s.instance.CallMethod(s.handler, method, args...); err != nil {
-		if errors.Is(err, ErrFunctionMissing) {
-			return
+	s.faults.setBudget(budget)
+	s.exhaustedMu.Lock()
+	s.onExhausted = onExhausted
+	s.exhaustedMu.Unlock()
+}
+
+// SetLogFunc routes the strategy's console output, helpers.log lines and handler exceptions to
+// fn. Passing nil stops forwarding.
+func (s *Strategy) SetLogFunc(fn LogFunc) {
+	if s == nil {
+		return
+	}
+	s.logs.set(fn)
+}
+
+// HandlerFaults reports the exceptions raised by the strategy handlers, per handler and event type.
+func (s *Strategy) HandlerFaults() []HandlerFault {
+	if s == nil {
+		return nil
+	}
+	return s.faults.snapshot()
+}
+
+// invoke calls a handler method. An exception is isolated to the event that raised it: the event
+// is skipped, the fault is counted and logged, and the instance keeps running until the error
+// budget is exhausted.
+func (s *Strategy) invoke(method string, args ...any) {
+	_ = s.call(method, args...)
+}
+
+// call runs a handler method like invoke and returns the exception it raised; a missing handler
+// is not an error.
+func (s *Strategy) call(method string, args ...any) error {
+	if s == nil {
+		return nil
+	}
+	evt := invokedEvent(args)
+	// The event clock and invocation context are set on the VM goroutine, so handlers delivered by
+	// several workers cannot overwrite each other's event time or trace.
+	_, err := s.instance.callMethod(s.handler, method, func() func() {
+		s.clock.observe(evt)
+		if s.runtime == nil {
+			return nil
 		}
-		s.logError(method, err)
+		s.runtime.enter(invokedContext(args))
+		return s.runtime.leave
+	}, args...)
+	if err == nil || errors.Is(err, ErrFunctionMissing) {
+		return nil
+	}
+	var eventType schema.EventType
+	if evt != nil {
+		eventType = evt.Type
+	}
+	count, exhausted := s.faults.record(method, eventType, err)
+	if s.logger != nil {
+		s.logger.Printf("js strategy %s.%s: skipped %s event after exception %d/%s: %v",
+			s.strategyName(), method, eventType, count, s.faults.budgetLimit(), err)
+	}
+	s.logs.emit(strategylogstore.SourceRuntime, strategylogstore.LevelError, fmt.Sprintf("%s: skipped %s event after exception %d/%s: %v",
+		method, eventType, count, s.faults.budgetLimit(), err))
+	if !exhausted {
+		return err
+	}
+	s.exhaustedMu.Lock()
+	handler := s.onExhausted
+	s.exhaustedMu.Unlock()
+	if handler != nil {
+		handler(&BudgetExhaustedError{
+			Strategy: s.strategyName(),
+			Budget:   s.faults.currentBudget(),
+			Handler:  method,
+			Cause:    err,
+		})
 	}
+	return err
+}
+
+func invokedContext(args []any) context.Context {
+	for _, arg := range args {
+		if ctx, ok := arg.(context.Context); ok && ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+func invokedEvent(args []any) *schema.Event {
+	for _, arg := range args {
+		if evt, ok := arg.(*schema.Event); ok && evt != nil {
+			return evt
+		}
+	}
+	return nil
+}
+
+func (s *Strategy) strategyName() string {
+	return strings.ToLower(strings.TrimSpace(s.metadata.Name))
 }
 
 func (s *Strategy) logError(method string, err error) {
@@ -239,27 +453,28 @@ func (s *Strategy) logError(method string, err error) {
 		return
 	}
 	if s.logger != nil {
-		s.logger.Printf("js strategy %s.%s: %v", strings.ToLower(strings.TrimSpace(s.metadata.Name)), method, err)
+		s.logger.Printf("js strategy %s.%s: %v", s.strategyName(), method, err)
 	}
+	s.logs.emit(strategylogstore.SourceRuntime, strategylogstore.LevelError, fmt.Sprintf("%s: %v", method, err))
 }
 
 func defaultStrategyLogger(logger *log.Logger) *log.Logger {
 	if logger != nil {
 		return logger
 	}
-	return log.New(os.Stdout, "", log.LstdFlags|log.Lmicroseconds)
+	return log.New(redact.Stdout, "", log.LstdFlags|log.Lmicroseconds)
 }
 
-func makeLogHelper(logger *log.Logger) func(args ...any) {
+func makeLogHelper(logger *log.Logger, logs *logSink) func(args ...any) {
 	return func(args ...any) {
-		if logger == nil {
-			return
-		}
 		msg := stringifyLogArgs(args...)
 		if msg == "" {
 			return
 		}
-		logger.Print(msg)
+		if logger != nil {
+			logger.Print(msg)
+		}
+		logs.emit(strategylogstore.SourceConsole, strategylogstore.LevelInfo, msg)
 	}
 }
 
@@ -334,14 +549,35 @@ func parseSleepDuration(value any) time.Duration {
 
 type lambdaBridge struct {
 	base atomic.Pointer[core.BaseLambda]
+	// invocation is the context of the handler call in progress, so orders the handler submits
+	// join its execution trace.
+	invocation atomic.Pointer[context.Context]
 }
 
 func newLambdaBridge() *lambdaBridge {
 	return &lambdaBridge{
-		base: atomic.Pointer[core.BaseLambda]{},
+		base:       atomic.Pointer[core.BaseLambda]{},
+		invocation: atomic.Pointer[context.Context]{},
 	}
 }
 
+func (b *lambdaBridge) enter(ctx context.Context) {
+	b.invocation.Store(&ctx)
+}
+
+func (b *lambdaBridge) leave() {
+	b.invocation.Store(nil)
+}
+
+// orderContext carries the trace of the current handler call without its cancellation, matching
+// submissions made outside a handler.
+func (b *lambdaBridge) orderContext() context.Context {
+	if ctx := b.invocation.Load(); ctx != nil {
+		return context.WithoutCancel(*ctx)
+	}
+	return context.Background()
+}
+
 func (b *lambdaBridge) attach(base *core.BaseLambda) {
 	b.base.Store(base)
 }
@@ -353,11 +589,17 @@ func (b *lambdaBridge) helpers() map[string]any {
 		"selectProvider":    b.selectProvider,
 		"submitMarketOrder": b.submitMarketOrder,
 		"submitOrder":       b.submitOrder,
+		"submitAlgoOrder":   b.submitAlgoOrder,
+		"cancelAlgoOrder":   b.cancelAlgoOrder,
+		"algoOrders":        b.algoOrders,
 		"getMarketState":    b.marketState,
 		"getBidPrice":       b.bidPrice,
 		"getAskPrice":       b.askPrice,
 		"isDryRun":          b.isDryRun,
 		"getLastPrice":      b.getLastPrice,
+		"marketHistory":     map[string]any{"get": b.marketHistory},
+		"orders":            map[string]any{"open": b.openOrders, "recent": b.recentOrders, "get": b.order},
+		"book":              b.book,
 	}
 }
 
@@ -445,7 +687,9 @@ func (b *lambdaBridge) selectProvider(seed any) (string, error) {
 	return providers[0], nil
 }
 
-func (b *lambdaBridge) submitMarketOrder(provider string, side any, quantity string) error {
+// submitMarketOrder and submitOrder accept an optional trailing {tags, metadata, tif} object that
+// attributes the order to a signal, leg or parent and sets its time in force.
+func (b *lambdaBridge) submitMarketOrder(provider string, side any, quantity string, options map[string]any) error {
 	base := b.snapshot()
 	if base == nil {
 		return fmt.Errorf("lambda unavailable")
@@ -454,6 +698,10 @@ func (b *lambdaBridge) submitMarketOrder(provider string, side any, quantity str
 	if err != nil {
 		return err
 	}
+	opts, err := parseOrderOptions(options)
+	if err != nil {
+		return err
+	}
 	if strings.TrimSpace(provider) == "" {
 		providers := base.Providers()
 		if len(providers) == 0 {
@@ -461,13 +709,13 @@ func (b *lambdaBridge) submitMarketOrder(provider string, side any, quantity str
 		}
 		provider = providers[0]
 	}
-	if err := base.SubmitMarketOrder(context.Background(), provider, sideValue, quantity); err != nil {
+	if err := base.SubmitMarketOrderWithOptions(b.orderContext(), provider, sideValue, quantity, opts); err != nil {
 		return fmt.Errorf("submit market order: %w", err)
 	}
 	return nil
 }
 
-func (b *lambdaBridge) submitOrder(provider string, side any, quantity string, price any) error {
+func (b *lambdaBridge) submitOrder(provider string, side any, quantity string, price any, options map[string]any) error {
 	base := b.snapshot()
 	if base == nil {
 		return fmt.Errorf("lambda unavailable")
@@ -476,6 +724,10 @@ func (b *lambdaBridge) submitOrder(provider string, side any, quantity string, p
 	if err != nil {
 		return err
 	}
+	opts, err := parseOrderOptions(options)
+	if err != nil {
+		return err
+	}
 	if strings.TrimSpace(provider) == "" {
 		providers := base.Providers()
 		if len(providers) == 0 {
@@ -487,12 +739,262 @@ func (b *lambdaBridge) submitOrder(provider string, side any, quantity string, p
 	if err != nil {
 		return err
 	}
-	if err := base.SubmitOrder(context.Background(), provider, sideValue, quantity, priceStr); err != nil {
+	if err := base.SubmitOrderWithOptions(b.orderContext(), provider, sideValue, quantity, priceStr, opts); err != nil {
 		return fmt.Errorf("submit order: %w", err)
 	}
 	return nil
 }
 
+// submitAlgoOrder accepts {kind, provider, symbol, side, quantity, price, durationMs,
+// sliceQuantity, slices, displayQuantity, tags, metadata} and returns the parent order ID.
+func (b *lambdaBridge) submitAlgoOrder(spec map[string]any) (string, error) {
+	base := b.snapshot()
+	if base == nil {
+		return "", fmt.Errorf("lambda unavailable")
+	}
+	sideValue, err := parseTradeSide(spec["side"])
+	if err != nil {
+		return "", err
+	}
+	provider := stringField(spec, "provider")
+	if provider == "" {
+		providers := base.Providers()
+		if len(providers) == 0 {
+			return "", fmt.Errorf("provider required")
+		}
+		provider = providers[0]
+	}
+	price, err := parsePriceString(spec["price"])
+	if err != nil {
+		return "", err
+	}
+	quantity, err := decimalField(spec, "quantity")
+	if err != nil {
+		return "", err
+	}
+	sliceQuantity, err := decimalField(spec, "sliceQuantity")
+	if err != nil {
+		return "", err
+	}
+	displayQuantity, err := decimalField(spec, "displayQuantity")
+	if err != nil {
+		return "", err
+	}
+	duration := parseSleepDuration(spec["durationMs"])
+	if raw, ok := spec["duration"]; ok && duration == 0 {
+		duration = parseSleepDuration(raw)
+	}
+	slices := 0
+	if raw, ok := spec["slices"]; ok {
+		slices = int(convertSeed(raw))
+	}
+	labels, err := parseOrderLabels(spec)
+	if err != nil {
+		return "", err
+	}
+	id, err := base.SubmitAlgoOrder(algo.ParentOrder{
+		ID:              "",
+		Kind:            algo.Kind(stringField(spec, "kind")),
+		Provider:        provider,
+		Symbol:          stringField(spec, "symbol"),
+		Side:            sideValue,
+		Quantity:        quantity,
+		Price:           price,
+		Duration:        duration,
+		SliceQuantity:   sliceQuantity,
+		Slices:          slices,
+		DisplayQuantity: displayQuantity,
+		Tags:            labels.Tags,
+		Metadata:        labels.Metadata,
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (b *lambdaBridge) cancelAlgoOrder(id string) error {
+	base := b.snapshot()
+	if base == nil {
+		return fmt.Errorf("lambda unavailable")
+	}
+	return base.CancelAlgoOrder(id)
+}
+
+func (b *lambdaBridge) algoOrders() []algo.Progress {
+	base := b.snapshot()
+	if base == nil {
+		return nil
+	}
+	return base.AlgoOrders()
+}
+
+// marketHistory returns the host-managed rolling window of recent trades and klines for symbol,
+// oldest first. Unknown instruments yield empty windows.
+func (b *lambdaBridge) marketHistory(symbol string, provider string) core.MarketHistory {
+	empty := core.MarketHistory{Provider: provider, Symbol: symbol, Trades: []schema.TradePayload{}, Klines: []schema.KlineSummaryPayload{}}
+	base := b.snapshot()
+	if base == nil {
+		return empty
+	}
+	history, ok := base.MarketHistory(symbol, provider)
+	if !ok {
+		return empty
+	}
+	return history
+}
+
+// openOrders returns the lambda's orders that can still trade, oldest first, as tracked by the host
+// from submissions and execution reports.
+func (b *lambdaBridge) openOrders() []core.OrderView {
+	base := b.snapshot()
+	if base == nil {
+		return []core.OrderView{}
+	}
+	return base.OpenOrders()
+}
+
+// recentOrders returns the lambda's n most recent orders, newest first; a missing or non-positive n
+// returns every order the host retains.
+func (b *lambdaBridge) recentOrders(n int) []core.OrderView {
+	base := b.snapshot()
+	if base == nil {
+		return []core.OrderView{}
+	}
+	return base.RecentOrders(n)
+}
+
+// order returns the lambda's order with the client order ID, or null once the host no longer
+// retains it.
+func (b *lambdaBridge) order(clientOrderID string) *core.OrderView {
+	base := b.snapshot()
+	if base == nil {
+		return nil
+	}
+	view, ok := base.Order(clientOrderID)
+	if !ok {
+		return nil
+	}
+	return &view
+}
+
+// book returns a handle on the host-maintained order book of symbol. best() yields the top of book
+// and depth(n) the best n levels per side (all levels when n <= 0), both read at call time so the
+// handle can be kept across events.
+func (b *lambdaBridge) book(symbol string, provider string) map[string]any {
+	return map[string]any{
+		"best": func() core.BookTop {
+			base := b.snapshot()
+			if base == nil {
+				return core.BookTop{Bid: nil, Ask: nil}
+			}
+			top, _ := base.BookTop(symbol, provider)
+			return top
+		},
+		"depth": func(levels int) core.BookView {
+			empty := core.BookView{Provider: provider, Symbol: symbol, Bids: []schema.PriceLevel{}, Asks: []schema.PriceLevel{}, LastUpdate: time.Time{}}
+			base := b.snapshot()
+			if base == nil {
+				return empty
+			}
+			view, ok := base.OrderBook(symbol, provider, levels)
+			if !ok {
+				return empty
+			}
+			return view
+		},
+	}
+}
+
+func stringField(spec map[string]any, key string) string {
+	value, ok := spec[key].(string)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// parseOrderOptions reads the labels, time in force and post-only and reduce-only flags of an
+// order options object.
+func parseOrderOptions(spec map[string]any) (core.OrderOptions, error) {
+	opts := core.OrderOptions{Labels: core.OrderLabels{Tags: nil, Metadata: nil}, TIF: "", PostOnly: false, ReduceOnly: false}
+	labels, err := parseOrderLabels(spec)
+	if err != nil {
+		return opts, err
+	}
+	opts.Labels = labels
+	if opts.TIF, err = schema.ParseTimeInForce(stringField(spec, "tif")); err != nil {
+		return opts, fmt.Errorf("tif: %w", err)
+	}
+	if opts.PostOnly, err = boolField(spec, "postOnly"); err != nil {
+		return opts, err
+	}
+	if opts.ReduceOnly, err = boolField(spec, "reduceOnly"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// boolField reads an optional boolean of an options object.
+func boolField(spec map[string]any, key string) (bool, error) {
+	switch value := spec[key].(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return value, nil
+	default:
+		return false, fmt.Errorf("%s must be a boolean", key)
+	}
+}
+
+// parseOrderLabels reads the optional tags array and metadata object of an order spec.
+// Metadata values are stringified.
+func parseOrderLabels(spec map[string]any) (core.OrderLabels, error) {
+	labels := core.OrderLabels{Tags: nil, Metadata: nil}
+	switch raw := spec["tags"].(type) {
+	case nil:
+	case string:
+		labels.Tags = []string{raw}
+	case []string:
+		labels.Tags = append(labels.Tags, raw...)
+	case []any:
+		for _, item := range raw {
+			tag, ok := item.(string)
+			if !ok {
+				return labels, fmt.Errorf("tags: expected strings, got %T", item)
+			}
+			labels.Tags = append(labels.Tags, tag)
+		}
+	default:
+		return labels, fmt.Errorf("tags: expected an array of strings, got %T", raw)
+	}
+	switch raw := spec["metadata"].(type) {
+	case nil:
+	case map[string]any:
+		labels.Metadata = make(map[string]string, len(raw))
+		for key, value := range raw {
+			if value == nil {
+				continue
+			}
+			labels.Metadata[key] = fmt.Sprint(value)
+		}
+	default:
+		return labels, fmt.Errorf("metadata: expected an object, got %T", raw)
+	}
+	return labels, nil
+}
+
+func decimalField(spec map[string]any, key string) (string, error) {
+	value, err := parsePriceString(spec[key])
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", key, err)
+	}
+	if value == nil {
+		return "", nil
+	}
+	return *value, nil
+}
+
 func convertSeed(seed any) uint64 {
 	switch v := seed.(type) {
 	case uint64:
@@ -4,7 +4,16 @@ import (
 	"context"
 	"io"
 	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/dop251/goja"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
 )
 
 const configAwareModule = `
@@ -60,3 +69,357 @@ func TestNewStrategyReceivesConfig(t *testing.T) {
 	}
 	t.Cleanup(func() { strat.Close() })
 }
+
+func TestParseOrderLabels(t *testing.T) {
+	labels, err := parseOrderLabels(map[string]any{
+		"tags":     []any{"breakout", "leg-1"},
+		"metadata": map[string]any{"signal": "breakout-4h", "leg": int64(1), "skip": nil},
+	})
+	if err != nil {
+		t.Fatalf("parseOrderLabels: %v", err)
+	}
+	if !reflect.DeepEqual(labels.Tags, []string{"breakout", "leg-1"}) {
+		t.Fatalf("tags = %v", labels.Tags)
+	}
+	if !reflect.DeepEqual(labels.Metadata, map[string]string{"signal": "breakout-4h", "leg": "1"}) {
+		t.Fatalf("metadata = %v", labels.Metadata)
+	}
+
+	if labels, err := parseOrderLabels(nil); err != nil || labels.Tags != nil || labels.Metadata != nil {
+		t.Fatalf("nil options = %+v, %v", labels, err)
+	}
+	if _, err := parseOrderLabels(map[string]any{"tags": []any{1}}); err == nil {
+		t.Fatal("expected non-string tag to be rejected")
+	}
+	if _, err := parseOrderLabels(map[string]any{"metadata": "signal"}); err == nil {
+		t.Fatal("expected non-object metadata to be rejected")
+	}
+}
+
+const consoleModule = `
+module.exports = {
+  metadata: {
+    name: "console_probe",
+    version: "1.0.0",
+    displayName: "Console Probe",
+    description: "Writes to the sandbox console.",
+    events: ["Trade"]
+  },
+  create: function(env) {
+    return {
+      onTrade: function() {
+        console.warn("spread", {bps: 12});
+        env.helpers.log("filled", 3);
+        throw new Error("boom");
+      }
+    };
+  }
+};
+`
+
+func TestStrategyForwardsConsoleOutput(t *testing.T) {
+	dir := t.TempDir()
+	modulePath := writeVersionedModule(t, dir, "console_probe", "v1.0.0", []byte(consoleModule))
+	writeRegistry(t, dir, "console_probe", "v1.0.0", modulePath)
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	if err := loader.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	module, err := loader.Get("console_probe")
+	if err != nil {
+		t.Fatalf("Get console_probe: %v", err)
+	}
+	strat, err := NewStrategy(module, nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("NewStrategy: %v", err)
+	}
+	defer strat.Close()
+
+	var lines []string
+	strat.SetLogFunc(func(source, level, message string) {
+		lines = append(lines, source+"/"+level+": "+message)
+	})
+	strat.invoke("onTrade", context.Background())
+
+	want := []string{
+		`console/warn: spread {"bps":12}`,
+		"console/info: filled 3",
+	}
+	if len(lines) != 3 || !reflect.DeepEqual(lines[:2], want) || !strings.HasPrefix(lines[2], "runtime/error: onTrade: skipped") {
+		t.Fatalf("unexpected log lines %q", lines)
+	}
+}
+
+const orderQueryModule = `
+module.exports = {
+  metadata: {
+    name: "order_probe",
+    tag: "v1",
+    displayName: "Order Probe",
+    description: "Reads its orders back from the host.",
+    events: ["Trade"]
+  },
+  tests: [
+    {
+      name: "sees its own orders",
+      events: [
+        { type: "Trade", payload: { price: "100", quantity: "1" } },
+        { type: "Trade", payload: { price: "101", quantity: "1" } }
+      ],
+      expect: { logs: ["open=1 recent=Buy@100 state=PENDING"] }
+    }
+  ],
+  create: function(env) {
+    return {
+      onTrade: function(ctx, evt, payload) {
+        if (env.runtime.orders.recent().length === 0) {
+          env.runtime.submitOrder("", "buy", "1", payload.price);
+          return;
+        }
+        var last = env.runtime.orders.recent(1)[0];
+        env.helpers.log("open=" + env.runtime.orders.open().length + " recent=" + last.side + "@" + last.price + " state=" + last.state);
+      }
+    };
+  }
+};
+`
+
+func TestStrategyQueriesHostOrderHistory(t *testing.T) {
+	module, err := compileSource("order_probe.js", []byte(orderQueryModule), int64(len(orderQueryModule)))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	report, err := RunSelfTests(module)
+	if err != nil {
+		t.Fatalf("RunSelfTests: %v", err)
+	}
+	if report == nil || !report.Passed {
+		t.Fatalf("expected the order queries to succeed, got %+v", report)
+	}
+}
+
+const flakyFillModule = `
+module.exports = {
+  metadata: {
+    name: "flaky_fill",
+    tag: "v1",
+    displayName: "Flaky Fill",
+    description: "Throws on its first fill callback.",
+    events: ["ExecReport"]
+  },
+  create: function(env) {
+    var fills = 0;
+    return {
+      onOrderFilled: function(ctx, evt, payload) {
+        fills++;
+        if (fills === 1) {
+          throw new Error("not ready");
+        }
+      }
+    };
+  }
+};
+`
+
+func TestStrategyHandleOrderEventReportsExceptions(t *testing.T) {
+	dir := t.TempDir()
+	modulePath := writeVersionedModule(t, dir, "flaky_fill", "v1.0.0", []byte(flakyFillModule))
+	writeRegistry(t, dir, "flaky_fill", "v1.0.0", modulePath)
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	if err := loader.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	module, err := loader.Get("flaky_fill")
+	if err != nil {
+		t.Fatalf("Get flaky_fill: %v", err)
+	}
+	strat, err := NewStrategy(module, nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("NewStrategy: %v", err)
+	}
+	defer strat.Close()
+
+	ctx := context.Background()
+	evt := &schema.Event{EventID: "exec-1", Type: schema.EventTypeExecReport}
+	payload := schema.ExecReportPayload{ClientOrderID: "coid-1", State: schema.ExecReportStateFILLED}
+	if err := strat.HandleOrderEvent(ctx, evt, payload); err == nil || !strings.Contains(err.Error(), "not ready") {
+		t.Fatalf("expected the thrown error, got %v", err)
+	}
+	if err := strat.HandleOrderEvent(ctx, evt, payload); err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	payload.State = schema.ExecReportStateEXPIRED
+	if err := strat.HandleOrderEvent(ctx, evt, payload); err != nil {
+		t.Fatalf("expected a missing handler to be acknowledged, got %v", err)
+	}
+}
+
+const invocationProbeModule = `
+module.exports = {
+  metadata: {
+    name: "invocation_probe",
+    tag: "v1",
+    displayName: "Invocation Probe",
+    description: "Reports the invocation context of each trade.",
+    events: ["Trade"]
+  },
+  create: function(env) {
+    return {
+      onTrade: function(ctx, evt, payload, price) {
+        probe();
+      }
+    };
+  }
+};
+`
+
+type probeKey struct{}
+
+func TestStrategyConcurrentCallsKeepTheirInvocationContext(t *testing.T) {
+	dir := t.TempDir()
+	modulePath := writeVersionedModule(t, dir, "invocation_probe", "v1.0.0", []byte(invocationProbeModule))
+	writeRegistry(t, dir, "invocation_probe", "v1.0.0", modulePath)
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	if err := loader.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	module, err := loader.Get("invocation_probe")
+	if err != nil {
+		t.Fatalf("Get invocation_probe: %v", err)
+	}
+	strat, err := NewStrategy(module, nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("NewStrategy: %v", err)
+	}
+	defer strat.Close()
+
+	// The probe runs inside the handler and records the invocation context and event time it sees.
+	var (
+		mu   sync.Mutex
+		seen = make(map[int]time.Time)
+	)
+	if _, err := strat.instance.Execute(func(rt *goja.Runtime, _ *goja.Object) (goja.Value, error) {
+		return nil, rt.Set("probe", func() {
+			// Give the other callers time to queue their calls while this one is running.
+			time.Sleep(time.Millisecond)
+			id, _ := strat.runtime.orderContext().Value(probeKey{}).(int)
+			mu.Lock()
+			seen[id] = strat.clock.now()
+			mu.Unlock()
+		})
+	}); err != nil {
+		t.Fatalf("install probe: %v", err)
+	}
+
+	const callers, calls = 4, 50
+	var wg sync.WaitGroup
+	for caller := 1; caller <= callers; caller++ {
+		wg.Add(1)
+		go func(caller int) {
+			defer wg.Done()
+			for i := 0; i < calls; i++ {
+				id := caller*1000 + i
+				ctx := context.WithValue(context.Background(), probeKey{}, id)
+				evt := &schema.Event{EventID: strconv.Itoa(id), Type: schema.EventTypeTrade, EmitTS: time.Unix(int64(id), 0)}
+				strat.OnTrade(ctx, evt, schema.TradePayload{}, 1)
+			}
+		}(caller)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != callers*calls {
+		t.Fatalf("expected every call to see its own context, got %d distinct of %d", len(seen), callers*calls)
+	}
+	for id, now := range seen {
+		if now.Before(time.Unix(int64(id), 0)) {
+			t.Fatalf("call %d saw the event clock at %v, before its own event", id, now)
+		}
+	}
+}
+
+const statefulModule = `
+module.exports = {
+  metadata: {
+    name: "stateful_counter",
+    tag: "v1",
+    displayName: "Stateful Counter",
+    description: "Counts trades and hands the count to a freeze.",
+    events: ["Trade"]
+  },
+  create: function(env) {
+    var trades = 0;
+    return {
+      onTrade: function(ctx, evt, payload, price) {
+        trades++;
+      },
+      snapshotState: function() {
+        return { trades: trades, updated: new Date(0) };
+      },
+      restoreState: function(state) {
+        trades = state.trades;
+      }
+    };
+  }
+};
+`
+
+func TestStrategySnapshotAndRestoreState(t *testing.T) {
+	dir := t.TempDir()
+	modulePath := writeVersionedModule(t, dir, "stateful_counter", "v1.0.0", []byte(statefulModule))
+	writeRegistry(t, dir, "stateful_counter", "v1.0.0", modulePath)
+	loader, err := NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	if err := loader.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	module, err := loader.Get("stateful_counter")
+	if err != nil {
+		t.Fatalf("Get stateful_counter: %v", err)
+	}
+	newStrat := func() *Strategy {
+		t.Helper()
+		strat, err := NewStrategy(module, nil, log.New(io.Discard, "", 0))
+		if err != nil {
+			t.Fatalf("NewStrategy: %v", err)
+		}
+		t.Cleanup(strat.Close)
+		return strat
+	}
+	trade := func(strat *Strategy) {
+		strat.OnTrade(context.Background(), &schema.Event{EventID: "t", Type: schema.EventTypeTrade}, schema.TradePayload{}, 1)
+	}
+
+	frozen := newStrat()
+	trade(frozen)
+	trade(frozen)
+	state, err := frozen.SnapshotState()
+	if err != nil {
+		t.Fatalf("SnapshotState: %v", err)
+	}
+	// The state comes back as it would from a persisted snapshot.
+	if state["trades"] != float64(2) || state["updated"] != "1970-01-01T00:00:00Z" {
+		t.Fatalf("unexpected state %v", state)
+	}
+
+	restored := newStrat()
+	if err := restored.RestoreState(state); err != nil {
+		t.Fatalf("RestoreState: %v", err)
+	}
+	trade(restored)
+	if state, err := restored.SnapshotState(); err != nil || state["trades"] != float64(3) {
+		t.Fatalf("expected the restored count to continue, got %v (%v)", state, err)
+	}
+}
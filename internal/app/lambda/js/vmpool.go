@@ -0,0 +1,209 @@
+package js
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sourcegraph/conc/pool"
+)
+
+// warmVM is a strategy VM whose module already ran with its sandbox in place, waiting for
+// create to be called. It holds everything NewStrategy sets up before create.
+type warmVM struct {
+	instance *Instance
+	seed     int64
+	clock    *eventClock
+	logs     *logSink
+}
+
+// newWarmVM builds the sandbox for seed and runs the module's top-level code in it.
+func newWarmVM(module *Module, seed int64) (*warmVM, error) {
+	clock := newEventClock()
+	logs := &logSink{fn: atomic.Pointer[LogFunc]{}}
+	instance, err := NewInstance(module, WithSeed(seed), WithClock(clock.now), WithConsole(logs.emit))
+	if err != nil {
+		return nil, err
+	}
+	return &warmVM{instance: instance, seed: seed, clock: clock, logs: logs}, nil
+}
+
+// vmKey identifies interchangeable warm VMs. VMs warmed without a seed drew their own and serve
+// instances that have none configured.
+type vmKey struct {
+	hash   string
+	seed   int64
+	seeded bool
+}
+
+// VMPoolStats reports the warm VM pool usage.
+type VMPoolStats struct {
+	Idle   int   `json:"idle"`
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// VMPool keeps strategy VMs warmed ahead of instance starts, keyed by revision hash and seed,
+// so restarting many instances onto a new revision skips running each module from scratch.
+// A VM's module runs with the seed and console of the strategy that claims it, so warm and cold
+// starts behave the same. Idle VMs beyond the capacity are closed, oldest first.
+type VMPool struct {
+	mu       sync.Mutex
+	capacity int
+	idle     map[vmKey][]*warmVM
+	order    []vmKey
+	hits     atomic.Int64
+	misses   atomic.Int64
+}
+
+// NewVMPool returns a pool holding at most capacity idle VMs.
+func NewVMPool(capacity int) *VMPool {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &VMPool{
+		mu:       sync.Mutex{},
+		capacity: capacity,
+		idle:     make(map[vmKey][]*warmVM),
+		order:    nil,
+		hits:     atomic.Int64{},
+		misses:   atomic.Int64{},
+	}
+}
+
+// WarmTarget asks for one VM of a revision. Seed is the instance's configured seed; nil warms a
+// VM for an instance without one.
+type WarmTarget struct {
+	Module *Module
+	Seed   *int64
+}
+
+// Warm prepares a VM per target in parallel and returns once all are idle in the pool. Targets
+// whose module fails to run are skipped; starting the instance reports the error.
+func (p *VMPool) Warm(targets []WarmTarget) int {
+	if p == nil || len(targets) == 0 {
+		return 0
+	}
+	var warmed atomic.Int64
+	workers := pool.New().WithMaxGoroutines(runtime.GOMAXPROCS(0))
+	for _, target := range targets {
+		if target.Module == nil {
+			continue
+		}
+		workers.Go(func() {
+			key := vmKey{hash: target.Module.Hash, seed: 0, seeded: target.Seed != nil}
+			seed := NewSeed()
+			if target.Seed != nil {
+				seed = *target.Seed
+				key.seed = seed
+			}
+			vm, err := newWarmVM(target.Module, seed)
+			if err != nil {
+				return
+			}
+			p.put(key, vm)
+			warmed.Add(1)
+		})
+	}
+	workers.Wait()
+	return int(warmed.Load())
+}
+
+// NewStrategy instantiates a strategy like the package-level NewStrategy, claiming a warm VM
+// for the module and configured seed when one is idle. A nil pool always starts cold.
+func (p *VMPool) NewStrategy(module *Module, cfg map[string]any, logger *log.Logger) (*Strategy, error) {
+	if p == nil || module == nil {
+		return NewStrategy(module, cfg, logger)
+	}
+	key := vmKey{hash: module.Hash, seed: 0, seeded: false}
+	if seed, ok := SeedFromConfig(cfg); ok {
+		key.seed, key.seeded = seed, true
+	}
+	vm := p.take(key)
+	if vm == nil {
+		p.misses.Add(1)
+		return NewStrategy(module, cfg, logger)
+	}
+	p.hits.Add(1)
+	return newStrategy(module, cfg, logger, vm)
+}
+
+// Stats reports the idle VMs and how many strategy starts found a warm VM.
+func (p *VMPool) Stats() VMPoolStats {
+	if p == nil {
+		return VMPoolStats{Idle: 0, Hits: 0, Misses: 0}
+	}
+	p.mu.Lock()
+	idle := len(p.order)
+	p.mu.Unlock()
+	return VMPoolStats{Idle: idle, Hits: p.hits.Load(), Misses: p.misses.Load()}
+}
+
+// Close closes every idle VM.
+func (p *VMPool) Close() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = make(map[vmKey][]*warmVM)
+	p.order = nil
+	p.mu.Unlock()
+	for _, vms := range idle {
+		for _, vm := range vms {
+			vm.instance.Close()
+		}
+	}
+}
+
+func (p *VMPool) put(key vmKey, vm *warmVM) {
+	var evicted *warmVM
+	p.mu.Lock()
+	if len(p.order) >= p.capacity {
+		oldest := p.order[0]
+		p.order = p.order[1:]
+		vms := p.idle[oldest]
+		evicted = vms[0]
+		if len(vms) == 1 {
+			delete(p.idle, oldest)
+		} else {
+			p.idle[oldest] = vms[1:]
+		}
+	}
+	p.idle[key] = append(p.idle[key], vm)
+	p.order = append(p.order, key)
+	p.mu.Unlock()
+	if evicted != nil {
+		evicted.instance.Close()
+	}
+}
+
+func (p *VMPool) take(key vmKey) *warmVM {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	vms := p.idle[key]
+	if len(vms) == 0 {
+		return nil
+	}
+	vm := vms[len(vms)-1]
+	if len(vms) == 1 {
+		delete(p.idle, key)
+	} else {
+		p.idle[key] = vms[:len(vms)-1]
+	}
+	// Drop the newest matching entry from the eviction order.
+	for i := len(p.order) - 1; i >= 0; i-- {
+		if p.order[i] == key {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+	return vm
+}
+
+// String describes the pool for logs.
+func (s VMPoolStats) String() string {
+	return fmt.Sprintf("idle=%d hits=%d misses=%d", s.Idle, s.Hits, s.Misses)
+}
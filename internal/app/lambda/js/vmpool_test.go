@@ -0,0 +1,86 @@
+package js
+
+import (
+	"io"
+	"log"
+	"testing"
+)
+
+const warmModule = `
+var draw = Math.random();
+module.exports = {
+  metadata: {
+    name: "warm_probe",
+    version: "1.0.0",
+    displayName: "Warm Probe",
+    description: "Draws at load time.",
+    config: [],
+    events: ["Trade"]
+  },
+  create: function(env) {
+    return { draw: draw };
+  }
+};
+`
+
+func compileWarmModule(t *testing.T) *Module {
+	t.Helper()
+	module, err := compileSource("warm_probe.js", []byte(warmModule), int64(len(warmModule)))
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	return module
+}
+
+func TestVMPoolClaimsWarmVMForSeed(t *testing.T) {
+	module := compileWarmModule(t)
+	pool := NewVMPool(4)
+	defer pool.Close()
+	seed := int64(7)
+	if warmed := pool.Warm([]WarmTarget{{Module: module, Seed: &seed}}); warmed != 1 {
+		t.Fatalf("expected 1 warmed VM, got %d", warmed)
+	}
+
+	logger := log.New(io.Discard, "", 0)
+	other, err := pool.NewStrategy(module, map[string]any{SeedConfigKey: int64(8)}, logger)
+	if err != nil {
+		t.Fatalf("NewStrategy seed 8: %v", err)
+	}
+	defer other.Close()
+	strat, err := pool.NewStrategy(module, map[string]any{SeedConfigKey: seed}, logger)
+	if err != nil {
+		t.Fatalf("NewStrategy seed 7: %v", err)
+	}
+	defer strat.Close()
+	if strat.Seed() != seed {
+		t.Fatalf("expected seed %d, got %d", seed, strat.Seed())
+	}
+	if got := pool.Stats(); got.Idle != 0 || got.Hits != 1 || got.Misses != 1 {
+		t.Fatalf("unexpected stats %s", got)
+	}
+}
+
+func TestVMPoolEvictsOldestBeyondCapacity(t *testing.T) {
+	module := compileWarmModule(t)
+	pool := NewVMPool(1)
+	defer pool.Close()
+	first, second := int64(1), int64(2)
+	if warmed := pool.Warm([]WarmTarget{{Module: module, Seed: &first}, {Module: module, Seed: &second}}); warmed != 2 {
+		t.Fatalf("expected 2 warmed VMs, got %d", warmed)
+	}
+	if got := pool.Stats(); got.Idle != 1 {
+		t.Fatalf("expected 1 idle VM, got %s", got)
+	}
+}
+
+func TestNilVMPoolStartsCold(t *testing.T) {
+	var pool *VMPool
+	strat, err := pool.NewStrategy(compileWarmModule(t), nil, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("NewStrategy: %v", err)
+	}
+	strat.Close()
+	if got := pool.Stats(); got.Hits != 0 || got.Misses != 0 {
+		t.Fatalf("unexpected stats %s", got)
+	}
+}
@@ -0,0 +1,53 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/algo"
+)
+
+// ErrAlgoOrderNotFound is returned when an algo parent order is unknown to the instance.
+var ErrAlgoOrderNotFound = errors.New("algo order not found")
+
+// InstanceAlgoOrders returns the progress of the running instance's recent algo parent orders.
+func (m *Manager) InstanceAlgoOrders(id string) ([]algo.Progress, error) {
+	inst, err := m.runningInstance(id)
+	if err != nil {
+		return nil, err
+	}
+	return inst.base.AlgoOrders(), nil
+}
+
+// CancelInstanceAlgoOrder cancels an algo parent order of a running instance together with its
+// resting child orders.
+func (m *Manager) CancelInstanceAlgoOrder(id, algoID string) (algo.Progress, error) {
+	var empty algo.Progress
+	inst, err := m.runningInstance(id)
+	if err != nil {
+		return empty, err
+	}
+	if err := inst.base.CancelAlgoOrder(algoID); err != nil {
+		if errors.Is(err, algo.ErrUnknownOrder) {
+			return empty, fmt.Errorf("%w: %s", ErrAlgoOrderNotFound, algoID)
+		}
+		return empty, err
+	}
+	progress, _ := inst.base.AlgoOrder(algoID)
+	return progress, nil
+}
+
+func (m *Manager) runningInstance(id string) (*lambdaInstance, error) {
+	id = strings.TrimSpace(id)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	inst, ok := m.instances[id]
+	if !ok || inst == nil || inst.base == nil {
+		if _, exists := m.specs[id]; !exists {
+			return nil, ErrInstanceNotFound
+		}
+		return nil, ErrInstanceNotRunning
+	}
+	return inst, nil
+}
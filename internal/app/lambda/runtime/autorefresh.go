@@ -0,0 +1,282 @@
+package runtime
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/app/featureflags"
+	"github.com/coachpo/meltica/internal/infra/redact"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// RegistryRevisionChange identifies a revision that appeared in or disappeared from the registry.
+type RegistryRevisionChange struct {
+	Strategy string `json:"strategy"`
+	Hash     string `json:"hash"`
+}
+
+// RegistryTagChange describes a tag alias that was created, moved, or removed.
+type RegistryTagChange struct {
+	Strategy     string `json:"strategy"`
+	Tag          string `json:"tag"`
+	Hash         string `json:"hash,omitempty"`
+	PreviousHash string `json:"previousHash,omitempty"`
+}
+
+// RegistryChangeSummary reports the differences detected by a scheduled registry refresh.
+type RegistryChangeSummary struct {
+	CheckedAt time.Time                `json:"checkedAt"`
+	Added     []RegistryRevisionChange `json:"added,omitempty"`
+	Removed   []RegistryRevisionChange `json:"removed,omitempty"`
+	TagMoves  []RegistryTagChange      `json:"tagMoves,omitempty"`
+	Applied   []RefreshResult          `json:"applied,omitempty"`
+	Error     string                   `json:"error,omitempty"`
+}
+
+// Empty reports whether the summary carries no registry changes.
+func (s RegistryChangeSummary) Empty() bool {
+	return len(s.Added) == 0 && len(s.Removed) == 0 && len(s.TagMoves) == 0
+}
+
+// AutoRefreshStatus describes the scheduled registry refresh job.
+type AutoRefreshStatus struct {
+	Enabled           bool                   `json:"enabled"`
+	Interval          string                 `json:"interval,omitempty"`
+	ApplyTagFollowers bool                   `json:"applyTagFollowers"`
+	Runs              int64                  `json:"runs"`
+	LastRun           *time.Time             `json:"lastRun,omitempty"`
+	LastChange        *RegistryChangeSummary `json:"lastChange,omitempty"`
+}
+
+type registryState struct {
+	revisions map[string]map[string]struct{}
+	tags      map[string]map[string]string
+}
+
+// StartAutoRefresh periodically reloads the strategy registry until ctx is cancelled.
+// It is a no-op when automatic refresh is not enabled.
+func (m *Manager) StartAutoRefresh(ctx context.Context) {
+	if m == nil || m.jsLoader == nil || !m.autoRefreshCfg.Enabled || m.autoRefreshCfg.Interval <= 0 {
+		return
+	}
+	m.autoRefreshMu.Lock()
+	m.registryState = m.captureRegistryState()
+	m.autoRefreshMu.Unlock()
+	if m.logger != nil {
+		m.logger.Printf("strategy auto-refresh enabled: interval=%s applyTagFollowers=%t", m.autoRefreshCfg.Interval, m.autoRefreshCfg.ApplyTagFollowers)
+	}
+	go func() {
+		ticker := time.NewTicker(m.autoRefreshCfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.runAutoRefresh(ctx)
+			}
+		}
+	}()
+}
+
+// AutoRefreshStatus reports the configuration and last outcome of the scheduled registry refresh.
+func (m *Manager) AutoRefreshStatus() AutoRefreshStatus {
+	status := AutoRefreshStatus{
+		Enabled:           m.autoRefreshCfg.Enabled,
+		Interval:          "",
+		ApplyTagFollowers: m.autoRefreshCfg.ApplyTagFollowers && m.flags.Enabled(featureflags.TagRollouts),
+		Runs:              0,
+		LastRun:           nil,
+		LastChange:        nil,
+	}
+	if m.autoRefreshCfg.Enabled {
+		status.Interval = m.autoRefreshCfg.Interval.String()
+	}
+	m.autoRefreshMu.Lock()
+	defer m.autoRefreshMu.Unlock()
+	status.Runs = m.autoRefreshRuns
+	status.LastRun = timePointer(m.autoRefreshLastRun)
+	if m.lastRegistryChange != nil {
+		summary := *m.lastRegistryChange
+		status.LastChange = &summary
+	}
+	return status
+}
+
+// runAutoRefresh reloads the registry, diffs it against the previous snapshot and, when configured,
+// re-resolves instances of the changed strategies so tag followers move to the new revisions.
+func (m *Manager) runAutoRefresh(ctx context.Context) RegistryChangeSummary {
+	m.autoRefreshMu.Lock()
+	defer m.autoRefreshMu.Unlock()
+
+	now := m.clock().UTC()
+	m.autoRefreshRuns++
+	m.autoRefreshLastRun = now
+
+	summary := RegistryChangeSummary{
+		CheckedAt: now,
+		Added:     nil,
+		Removed:   nil,
+		TagMoves:  nil,
+		Applied:   nil,
+		Error:     "",
+	}
+	if _, err := m.installJavaScriptStrategies(ctx); err != nil {
+		summary.Error = redact.Error(err)
+		if m.logger != nil {
+			m.logger.Printf("strategy auto-refresh: %v", err)
+		}
+		return summary
+	}
+	current := m.captureRegistryState()
+	diffRegistryStates(m.registryState, current, &summary)
+	m.registryState = current
+	if summary.Empty() {
+		return summary
+	}
+
+	if m.autoRefreshCfg.ApplyTagFollowers && m.flags.Enabled(featureflags.TagRollouts) {
+		results, err := m.refreshJavaScriptStrategies(ctx, RefreshTargets{Strategies: summary.changedStrategies(), Hashes: nil})
+		if err != nil {
+			summary.Error = redact.Error(err)
+		}
+		for _, result := range results {
+			if result.Reason == "refreshed" || result.Reason == "retired" {
+				summary.Applied = append(summary.Applied, result)
+			}
+		}
+	}
+	m.lastRegistryChange = &summary
+	m.logRegistryChange(summary)
+	if m.registryChangeCounter != nil {
+		env := telemetry.Environment()
+		for _, strategy := range summary.changedStrategies() {
+			m.registryChangeCounter.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("environment", env),
+				attribute.String("strategy", strategy),
+			))
+		}
+	}
+	return summary
+}
+
+func (m *Manager) logRegistryChange(summary RegistryChangeSummary) {
+	if m.logger == nil {
+		return
+	}
+	m.logger.Printf("strategy auto-refresh: registry changed (added=%d removed=%d tagMoves=%d applied=%d)",
+		len(summary.Added), len(summary.Removed), len(summary.TagMoves), len(summary.Applied))
+	for _, rev := range summary.Added {
+		m.logger.Printf("strategy auto-refresh: + %s@%s", rev.Strategy, rev.Hash)
+	}
+	for _, rev := range summary.Removed {
+		m.logger.Printf("strategy auto-refresh: - %s@%s", rev.Strategy, rev.Hash)
+	}
+	for _, move := range summary.TagMoves {
+		m.logger.Printf("strategy auto-refresh: %s:%s %s -> %s", move.Strategy, move.Tag, move.PreviousHash, move.Hash)
+	}
+	for _, applied := range summary.Applied {
+		m.logger.Printf("strategy auto-refresh: instances %v %s (%s -> %s)", applied.Instances, applied.Reason, applied.PreviousHash, applied.Hash)
+	}
+	if summary.Error != "" {
+		m.logger.Printf("strategy auto-refresh: apply failed: %s", summary.Error)
+	}
+}
+
+func (m *Manager) captureRegistryState() registryState {
+	state := registryState{
+		revisions: make(map[string]map[string]struct{}),
+		tags:      make(map[string]map[string]string),
+	}
+	if m.jsLoader == nil {
+		return state
+	}
+	for _, module := range m.jsLoader.List() {
+		name := normalizeStrategyName(module.Name)
+		hashes := make(map[string]struct{}, len(module.Revisions))
+		for _, rev := range module.Revisions {
+			hashes[rev.Hash] = struct{}{}
+		}
+		state.revisions[name] = hashes
+		tags := make(map[string]string, len(module.TagAliases))
+		for tag, hash := range module.TagAliases {
+			tags[tag] = hash
+		}
+		state.tags[name] = tags
+	}
+	return state
+}
+
+func diffRegistryStates(previous, current registryState, summary *RegistryChangeSummary) {
+	for name, hashes := range current.revisions {
+		for hash := range hashes {
+			if _, ok := previous.revisions[name][hash]; !ok {
+				summary.Added = append(summary.Added, RegistryRevisionChange{Strategy: name, Hash: hash})
+			}
+		}
+	}
+	for name, hashes := range previous.revisions {
+		for hash := range hashes {
+			if _, ok := current.revisions[name][hash]; !ok {
+				summary.Removed = append(summary.Removed, RegistryRevisionChange{Strategy: name, Hash: hash})
+			}
+		}
+	}
+	for name, tags := range current.tags {
+		for tag, hash := range tags {
+			if prev := previous.tags[name][tag]; prev != hash {
+				summary.TagMoves = append(summary.TagMoves, RegistryTagChange{Strategy: name, Tag: tag, Hash: hash, PreviousHash: prev})
+			}
+		}
+	}
+	for name, tags := range previous.tags {
+		for tag, hash := range tags {
+			if _, ok := current.tags[name][tag]; !ok {
+				summary.TagMoves = append(summary.TagMoves, RegistryTagChange{Strategy: name, Tag: tag, Hash: "", PreviousHash: hash})
+			}
+		}
+	}
+	sortRevisionChanges(summary.Added)
+	sortRevisionChanges(summary.Removed)
+	sort.Slice(summary.TagMoves, func(i, j int) bool {
+		if summary.TagMoves[i].Strategy != summary.TagMoves[j].Strategy {
+			return summary.TagMoves[i].Strategy < summary.TagMoves[j].Strategy
+		}
+		return summary.TagMoves[i].Tag < summary.TagMoves[j].Tag
+	})
+}
+
+func sortRevisionChanges(changes []RegistryRevisionChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Strategy != changes[j].Strategy {
+			return changes[i].Strategy < changes[j].Strategy
+		}
+		return changes[i].Hash < changes[j].Hash
+	})
+}
+
+func (s RegistryChangeSummary) changedStrategies() []string {
+	set := make(map[string]struct{})
+	for _, rev := range s.Added {
+		set[rev.Strategy] = struct{}{}
+	}
+	for _, rev := range s.Removed {
+		set[rev.Strategy] = struct{}{}
+	}
+	for _, move := range s.TagMoves {
+		set[move.Strategy] = struct{}{}
+	}
+	out := make([]string, 0, len(set))
+	for name := range set {
+		if trimmed := strings.TrimSpace(name); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,65 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRegistryStates_DetectsRevisionsAndTagMoves(t *testing.T) {
+	previous := registryState{
+		revisions: map[string]map[string]struct{}{
+			"alpha": {"h1": {}, "h2": {}},
+			"gone":  {"g1": {}},
+		},
+		tags: map[string]map[string]string{
+			"alpha": {"latest": "h1", "prod": "h1", "canary": "h2"},
+			"gone":  {"latest": "g1"},
+		},
+	}
+	current := registryState{
+		revisions: map[string]map[string]struct{}{
+			"alpha": {"h1": {}, "h3": {}},
+			"beta":  {"b1": {}},
+		},
+		tags: map[string]map[string]string{
+			"alpha": {"latest": "h3", "prod": "h1"},
+			"beta":  {"latest": "b1"},
+		},
+	}
+
+	var summary RegistryChangeSummary
+	diffRegistryStates(previous, current, &summary)
+
+	wantAdded := []RegistryRevisionChange{{Strategy: "alpha", Hash: "h3"}, {Strategy: "beta", Hash: "b1"}}
+	if !reflect.DeepEqual(summary.Added, wantAdded) {
+		t.Fatalf("unexpected added revisions: %+v", summary.Added)
+	}
+	wantRemoved := []RegistryRevisionChange{{Strategy: "alpha", Hash: "h2"}, {Strategy: "gone", Hash: "g1"}}
+	if !reflect.DeepEqual(summary.Removed, wantRemoved) {
+		t.Fatalf("unexpected removed revisions: %+v", summary.Removed)
+	}
+	wantMoves := []RegistryTagChange{
+		{Strategy: "alpha", Tag: "canary", Hash: "", PreviousHash: "h2"},
+		{Strategy: "alpha", Tag: "latest", Hash: "h3", PreviousHash: "h1"},
+		{Strategy: "beta", Tag: "latest", Hash: "b1", PreviousHash: ""},
+		{Strategy: "gone", Tag: "latest", Hash: "", PreviousHash: "g1"},
+	}
+	if !reflect.DeepEqual(summary.TagMoves, wantMoves) {
+		t.Fatalf("unexpected tag moves: %+v", summary.TagMoves)
+	}
+	if got := summary.changedStrategies(); !reflect.DeepEqual(got, []string{"alpha", "beta", "gone"}) {
+		t.Fatalf("unexpected changed strategies: %v", got)
+	}
+}
+
+func TestDiffRegistryStates_NoChanges(t *testing.T) {
+	state := registryState{
+		revisions: map[string]map[string]struct{}{"alpha": {"h1": {}}},
+		tags:      map[string]map[string]string{"alpha": {"latest": "h1"}},
+	}
+	var summary RegistryChangeSummary
+	diffRegistryStates(state, state, &summary)
+	if !summary.Empty() {
+		t.Fatalf("expected empty summary, got %+v", summary)
+	}
+}
@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/app/lambda/strategies"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// ErrCapabilityNotAllowed is returned when an instance's revision requires a capability the
+// environment policy does not allow.
+var ErrCapabilityNotAllowed = errors.New("strategy capability not allowed")
+
+// capabilityPolicy resolves the capabilities allowed in the configured environment. A nil set
+// means the environment is unrestricted.
+func capabilityPolicy(cfg config.AppConfig) (map[strategies.Capability]struct{}, error) {
+	raw, restricted := cfg.Strategies.Capabilities.Allowed(cfg.Environment)
+	if !restricted {
+		return nil, nil
+	}
+	allowed := make(map[strategies.Capability]struct{}, len(raw))
+	for _, name := range raw {
+		capability := strategies.Capability(name)
+		if !strategies.KnownCapability(capability) {
+			return nil, fmt.Errorf("strategies capabilities: unknown capability %q for %s", name, cfg.Environment)
+		}
+		allowed[capability] = struct{}{}
+	}
+	return allowed, nil
+}
+
+// deniedCapabilities lists the capabilities required by meta that the environment policy does
+// not allow.
+func (m *Manager) deniedCapabilities(meta strategies.Metadata) []strategies.Capability {
+	if m.allowedCapabilities == nil {
+		return nil
+	}
+	var denied []strategies.Capability
+	for _, capability := range meta.Capabilities {
+		if _, ok := m.allowedCapabilities[capability]; !ok {
+			denied = append(denied, capability)
+		}
+	}
+	return denied
+}
+
+// checkCapabilities rejects specs whose resolved revision requires capabilities the environment
+// policy does not allow.
+func (m *Manager) checkCapabilities(spec config.LambdaStrategySpec) error {
+	if m.allowedCapabilities == nil {
+		return nil
+	}
+	meta, err := m.revisionMetadata(spec)
+	if err != nil {
+		return err
+	}
+	denied := m.deniedCapabilities(meta)
+	if len(denied) == 0 {
+		return nil
+	}
+	names := make([]string, len(denied))
+	for i, capability := range denied {
+		names[i] = string(capability)
+	}
+	return fmt.Errorf("%w: %s requires %s, which %s does not allow", ErrCapabilityNotAllowed, spec.Identifier, strings.Join(names, ", "), m.environment)
+}
+
+// revisionMetadata returns the metadata of the pinned revision, or of the registered strategy
+// when the spec is not pinned to a hash.
+func (m *Manager) revisionMetadata(spec config.LambdaStrategySpec) (strategies.Metadata, error) {
+	name := strings.ToLower(strings.TrimSpace(spec.Identifier))
+	if spec.Hash != "" && m.jsLoader != nil {
+		module, err := m.jsLoader.Get(spec.Hash)
+		if err == nil && module == nil {
+			err = js.ErrModuleNotFound
+		}
+		if err != nil {
+			if errors.Is(err, js.ErrModuleNotFound) {
+				return strategies.Metadata{}, fmt.Errorf("strategy %s: revision %s unavailable", name, spec.Hash)
+			}
+			return strategies.Metadata{}, fmt.Errorf("strategy %s: %w", name, err)
+		}
+		return module.Metadata, nil
+	}
+	m.mu.RLock()
+	def, ok := m.strategies[name]
+	m.mu.RUnlock()
+	if !ok {
+		return strategies.Metadata{}, fmt.Errorf("strategy %q not registered", spec.Identifier)
+	}
+	return def.Metadata(), nil
+}
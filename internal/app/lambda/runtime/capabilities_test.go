@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+const capabilityModule = `
+module.exports = {
+  metadata: {
+    name: "scraper",
+    tag: "v1.0.0",
+    displayName: "Scraper",
+    description: "Trades on scraped signals",
+    config: [],
+    events: ["Trade"],
+    capabilities: ["LIVE_TRADING", "http_access"]
+  },
+  create: function () {
+    return {};
+  }
+};
+`
+
+func newCapabilityManager(t *testing.T, env config.Environment, policy config.StrategyCapabilityPolicy) *Manager {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "registry.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write registry stub: %v", err)
+	}
+	loader, err := js.NewLoader(dir)
+	if err != nil {
+		t.Fatalf("NewLoader: %v", err)
+	}
+	if _, err := loader.Store([]byte(capabilityModule), js.ModuleWriteOptions{PromoteLatest: true}); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	cfg := config.AppConfig{Environment: env, Strategies: config.StrategiesConfig{Directory: dir, Capabilities: policy}}
+	mgr, err := NewManager(cfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if err := mgr.RefreshJavaScriptStrategies(context.Background()); err != nil {
+		t.Fatalf("Refresh strategies: %v", err)
+	}
+	return mgr
+}
+
+func capabilitySpec() config.LambdaSpec {
+	return config.LambdaSpec{
+		ID:        "scraper-1",
+		Strategy:  config.LambdaStrategySpec{Identifier: "scraper:latest", Config: map[string]any{}},
+		Providers: []string{"okx-spot"},
+		ProviderSymbols: map[string]config.ProviderSymbols{
+			"okx-spot": {Symbols: []string{"BTC-USDT"}},
+		},
+	}
+}
+
+func TestCreateRejectsCapabilitiesDeniedByEnvironment(t *testing.T) {
+	policy := config.StrategyCapabilityPolicy{
+		config.EnvProd:    {"live_trading", "state_storage"},
+		config.EnvStaging: {"live_trading", "http_access"},
+	}
+
+	prod := newCapabilityManager(t, config.EnvProd, policy)
+	_, err := prod.Create(capabilitySpec())
+	if !errors.Is(err, ErrCapabilityNotAllowed) || !strings.Contains(err.Error(), "http_access") || strings.Contains(err.Error(), "live_trading") {
+		t.Fatalf("expected http_access denied in prod, got %v", err)
+	}
+	if _, ok := prod.Instance("scraper-1"); ok {
+		t.Fatal("rejected instance must not be registered")
+	}
+
+	for _, env := range []config.Environment{config.EnvStaging, config.EnvDev} {
+		mgr := newCapabilityManager(t, env, policy)
+		if _, err := mgr.Create(capabilitySpec()); err != nil {
+			t.Fatalf("%s: expected instance allowed, got %v", env, err)
+		}
+	}
+}
+
+func TestCapabilityPolicyRejectsUnknownCapabilities(t *testing.T) {
+	cfg := config.AppConfig{
+		Environment: config.EnvProd,
+		Strategies:  config.StrategiesConfig{Capabilities: config.StrategyCapabilityPolicy{config.EnvProd: {"teleport"}}},
+	}
+	if _, err := capabilityPolicy(cfg); err == nil {
+		t.Fatal("expected unknown capability rejected")
+	}
+	cfg.Environment = config.EnvDev
+	if allowed, err := capabilityPolicy(cfg); err != nil || allowed != nil {
+		t.Fatalf("expected dev unrestricted, got %v %v", allowed, err)
+	}
+}
@@ -0,0 +1,160 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/app/risk"
+)
+
+const deadMansCancelTimeout = 10 * time.Second
+
+// ErrDeadMansSwitchDisabled is returned when heartbeats are sent while the switch is not configured.
+var ErrDeadMansSwitchDisabled = errors.New("dead man's switch disabled")
+
+// DeadMansSwitchStatus describes the dead man's switch and the resulting trading state.
+type DeadMansSwitchStatus struct {
+	Enabled          bool       `json:"enabled"`
+	Interval         string     `json:"interval,omitempty"`
+	CancelOpenOrders bool       `json:"cancelOpenOrders"`
+	LastHeartbeat    *time.Time `json:"lastHeartbeat,omitempty"`
+	Deadline         *time.Time `json:"deadline,omitempty"`
+	Tripped          bool       `json:"tripped"`
+	TrippedAt        *time.Time `json:"trippedAt,omitempty"`
+	TradingHalted    bool       `json:"tradingHalted"`
+	HaltReason       string     `json:"haltReason,omitempty"`
+}
+
+// StartDeadMansSwitch arms the dead man's switch and monitors it until ctx is cancelled.
+// It is a no-op when the switch is not enabled.
+func (m *Manager) StartDeadMansSwitch(ctx context.Context) {
+	if m == nil || m.deadMans == nil {
+		return
+	}
+	m.deadMans.Heartbeat()
+	if m.logger != nil {
+		m.logger.Printf("dead man's switch armed: interval=%s cancelOpenOrders=%t", m.deadMansCfg.Interval, m.deadMansCfg.CancelOpenOrders)
+	}
+	go m.deadMans.Run(ctx)
+}
+
+// RecordControllerHeartbeat re-arms the dead man's switch. When resume is true and trading was
+// halted by the switch, the halt is lifted; halts raised for other reasons are left untouched.
+func (m *Manager) RecordControllerHeartbeat(resume bool) (DeadMansSwitchStatus, error) {
+	if m == nil || m.deadMans == nil {
+		return DeadMansSwitchStatus{}, ErrDeadMansSwitchDisabled
+	}
+	m.deadMans.Heartbeat()
+	if resume {
+		resumed := false
+		for _, rm := range m.allRiskManagers() {
+			if rm.Resume(risk.DeadMansSwitchReason) {
+				resumed = true
+			}
+		}
+		if resumed && m.logger != nil {
+			m.logger.Printf("dead man's switch: trading resumed by controller heartbeat")
+		}
+	}
+	return m.DeadMansSwitchStatus(), nil
+}
+
+// DeadMansSwitchStatus reports the current dead man's switch state.
+func (m *Manager) DeadMansSwitchStatus() DeadMansSwitchStatus {
+	halted, reason := m.riskManager.KillSwitchStatus()
+	status := DeadMansSwitchStatus{
+		Enabled:          m.deadMans != nil,
+		Interval:         "",
+		CancelOpenOrders: m.deadMansCfg.CancelOpenOrders,
+		LastHeartbeat:    nil,
+		Deadline:         nil,
+		Tripped:          false,
+		TrippedAt:        nil,
+		TradingHalted:    halted,
+		HaltReason:       reason,
+	}
+	if m.deadMans == nil {
+		return status
+	}
+	snapshot := m.deadMans.Status()
+	status.Interval = snapshot.Interval.String()
+	status.LastHeartbeat = timePointer(snapshot.LastHeartbeat)
+	status.Deadline = timePointer(snapshot.Deadline)
+	status.Tripped = snapshot.Tripped
+	status.TrippedAt = timePointer(snapshot.TrippedAt)
+	return status
+}
+
+func (m *Manager) tripDeadMansSwitch(ctx context.Context) {
+	for _, rm := range m.allRiskManagers() {
+		rm.Halt(risk.DeadMansSwitchReason)
+	}
+	if m.logger != nil {
+		m.logger.Printf("dead man's switch tripped: no controller heartbeat within %s; trading disabled", m.deadMansCfg.Interval)
+	}
+	if m.deadMansCfg.CancelOpenOrders {
+		m.cancelOpenOrders(ctx)
+	}
+}
+
+// cancelOpenOrders cancels resting orders on every instrument traded by a running instance.
+func (m *Manager) cancelOpenOrders(ctx context.Context) {
+	if m.providers == nil {
+		return
+	}
+	m.mu.RLock()
+	scopes := make(map[string]map[string]struct{})
+	for id := range m.instances {
+		spec, ok := m.specs[id]
+		if !ok {
+			continue
+		}
+		for providerName, symbols := range spec.ProviderSymbolMap() {
+			set, ok := scopes[providerName]
+			if !ok {
+				set = make(map[string]struct{}, len(symbols))
+				scopes[providerName] = set
+			}
+			for _, symbol := range symbols {
+				set[symbol] = struct{}{}
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deadMansCancelTimeout)
+	defer cancel()
+	for providerName, set := range scopes {
+		inst, ok := m.providers.Provider(providerName)
+		if !ok || inst == nil {
+			m.logger.Printf("dead man's switch: provider %s unavailable; open orders not cancelled", providerName)
+			continue
+		}
+		canceller, ok := inst.(provider.OrderCanceller)
+		if !ok {
+			m.logger.Printf("dead man's switch: provider %s does not support order cancellation", providerName)
+			continue
+		}
+		symbols := make([]string, 0, len(set))
+		for symbol := range set {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+		for _, symbol := range symbols {
+			if err := canceller.CancelOpenOrders(cancelCtx, symbol); err != nil {
+				m.logger.Printf("dead man's switch: cancel open orders %s/%s: %v", providerName, symbol, err)
+			}
+		}
+	}
+}
+
+func timePointer(ts time.Time) *time.Time {
+	if ts.IsZero() {
+		return nil
+	}
+	copied := ts.UTC()
+	return &copied
+}
@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// InstanceFaults reports the handler exceptions of a strategy instance. After the error budget
+// stopped an instance, the report captured at that moment is retained until it is started again.
+type InstanceFaults struct {
+	ID            string            `json:"id"`
+	Running       bool              `json:"running"`
+	MaxErrors     int               `json:"maxErrors"`
+	WindowSeconds float64           `json:"windowSeconds"`
+	Faults        []js.HandlerFault `json:"faults"`
+	StoppedAt     *time.Time        `json:"stoppedAt,omitempty"`
+	StopReason    string            `json:"stopReason,omitempty"`
+}
+
+// InstanceHandlerFaults returns the handler exceptions recorded for the instance.
+func (m *Manager) InstanceHandlerFaults(id string) (InstanceFaults, error) {
+	id = strings.TrimSpace(id)
+	m.mu.RLock()
+	inst, running := m.instances[id]
+	spec, exists := m.specs[id]
+	m.mu.RUnlock()
+	if !exists {
+		var empty InstanceFaults
+		return empty, ErrInstanceNotFound
+	}
+	if running && inst != nil {
+		return faultReport(id, spec, inst.strat), nil
+	}
+	m.faultsMu.Lock()
+	report, ok := m.stoppedFaults[id]
+	m.faultsMu.Unlock()
+	if ok {
+		return report, nil
+	}
+	return faultReport(id, spec, nil), nil
+}
+
+// bindErrorBudget arms the error budget of JavaScript strategies. Each handler exception only
+// skips the event that raised it; once the budget is exhausted the instance is stopped.
+func (m *Manager) bindErrorBudget(spec config.LambdaSpec, strategy core.TradingStrategy) {
+	jsStrategy, ok := strategy.(*js.Strategy)
+	if !ok {
+		return
+	}
+	m.faultsMu.Lock()
+	delete(m.stoppedFaults, spec.ID)
+	m.faultsMu.Unlock()
+	jsStrategy.SetErrorBudget(errorBudgetFromStrategy(spec.Strategy.Config), func(err error) {
+		// Stopping closes the VM the failing handler runs on, so it happens off the delivery path.
+		go m.stopFaultedInstance(spec.ID, strategy, err)
+	})
+}
+
+func (m *Manager) stopFaultedInstance(id string, strategy core.TradingStrategy, cause error) {
+	m.mu.RLock()
+	inst, running := m.instances[id]
+	spec := m.specs[id]
+	m.mu.RUnlock()
+	if !running || inst == nil || inst.strat != strategy {
+		return
+	}
+	report := faultReport(id, spec, strategy)
+	stoppedAt := m.clock().UTC()
+	report.Running = false
+	report.StoppedAt = &stoppedAt
+	report.StopReason = cause.Error()
+	m.faultsMu.Lock()
+	m.stoppedFaults[id] = report
+	m.faultsMu.Unlock()
+
+	if m.logger != nil {
+		m.logger.Printf("strategy instance %s: stopping: %v", id, cause)
+	}
+	m.recordStrategyLog(spec, strategylogstore.SourceRuntime, strategylogstore.LevelError, "stopping: "+cause.Error())
+	if err := m.Stop(id); err != nil && !errors.Is(err, ErrInstanceNotRunning) && m.logger != nil {
+		m.logger.Printf("strategy instance %s: stop after error budget exhausted: %v", id, err)
+	}
+}
+
+func faultReport(id string, spec config.LambdaSpec, strategy core.TradingStrategy) InstanceFaults {
+	budget := errorBudgetFromStrategy(spec.Strategy.Config)
+	report := InstanceFaults{
+		ID:            id,
+		Running:       strategy != nil,
+		MaxErrors:     budget.MaxErrors,
+		WindowSeconds: budget.Window.Seconds(),
+		Faults:        []js.HandlerFault{},
+		StoppedAt:     nil,
+		StopReason:    "",
+	}
+	if jsStrategy, ok := strategy.(*js.Strategy); ok {
+		report.Faults = jsStrategy.HandlerFaults()
+	}
+	return report
+}
+
+// errorBudgetFromStrategy extracts the handler error budget from the strategy config. Recognised
+// keys are error_budget (exceptions tolerated per window; negative disables stopping) and
+// error_budget_window (a duration string such as "30s" or a number of seconds; "0" counts over
+// the instance lifetime). Missing or malformed values fall back to the js defaults.
+func errorBudgetFromStrategy(cfg map[string]any) js.ErrorBudget {
+	budget := js.DefaultBudget()
+	if maxErrors, ok := intFromConfig(cfg["error_budget"]); ok && maxErrors != 0 {
+		budget.MaxErrors = maxErrors
+	}
+	if window, ok := durationFromConfig(cfg["error_budget_window"]); ok {
+		budget.Window = window
+	}
+	return budget
+}
+
+func durationFromConfig(raw any) (time.Duration, bool) {
+	switch v := raw.(type) {
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if parsed, err := time.ParseDuration(trimmed); err == nil && parsed >= 0 {
+			return parsed, true
+		}
+		if seconds, ok := intFromConfig(trimmed); ok && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		return 0, false
+	case int, int64, float64:
+		seconds, _ := intFromConfig(v)
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	default:
+		return 0, false
+	}
+}
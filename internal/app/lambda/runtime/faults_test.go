@@ -0,0 +1,30 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+)
+
+func TestErrorBudgetFromStrategy(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  map[string]any
+		want js.ErrorBudget
+	}{
+		{name: "defaults", cfg: nil, want: js.DefaultBudget()},
+		{name: "explicit", cfg: map[string]any{"error_budget": 5, "error_budget_window": "30s"}, want: js.ErrorBudget{MaxErrors: 5, Window: 30 * time.Second}},
+		{name: "seconds", cfg: map[string]any{"error_budget": float64(10), "error_budget_window": float64(90)}, want: js.ErrorBudget{MaxErrors: 10, Window: 90 * time.Second}},
+		{name: "lifetime", cfg: map[string]any{"error_budget_window": "0"}, want: js.ErrorBudget{MaxErrors: js.DefaultErrorBudget, Window: 0}},
+		{name: "disabled", cfg: map[string]any{"error_budget": -1}, want: js.ErrorBudget{MaxErrors: -1, Window: js.DefaultErrorBudgetWindow}},
+		{name: "malformed", cfg: map[string]any{"error_budget": "many", "error_budget_window": "soon"}, want: js.DefaultBudget()},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errorBudgetFromStrategy(tc.cfg); got != tc.want {
+				t.Fatalf("errorBudgetFromStrategy(%v) = %+v, want %+v", tc.cfg, got, tc.want)
+			}
+		})
+	}
+}
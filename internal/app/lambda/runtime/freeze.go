@@ -0,0 +1,229 @@
+package runtime
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+)
+
+// Strategy snapshot metadata keys recording a freeze, so instances restored by a restarted gateway
+// come back frozen, with the strategy state captured by the freeze, instead of trading on stale
+// state.
+const (
+	freezeSinceKey  = "frozenSince"
+	freezeReasonKey = "freezeReason"
+	freezeStateKey  = "frozenState"
+)
+
+// freezeDrainTimeout bounds how long a freeze waits for each instance to finish handling the
+// events queued before it froze.
+const freezeDrainTimeout = 5 * time.Second
+
+// freezeState is the gateway freeze. states holds the working state of each instance whose
+// strategy implements core.StateSnapshotter, captured when it froze or read back from its snapshot.
+type freezeState struct {
+	frozen bool
+	since  time.Time
+	reason string
+	states map[string]map[string]any
+}
+
+// FreezeReport describes the gateway freeze and the events each running instance holds.
+// Replayed counts the events handed back to each instance by an unfreeze.
+type FreezeReport struct {
+	Frozen    bool                         `json:"frozen"`
+	Since     time.Time                    `json:"since,omitempty"`
+	Reason    string                       `json:"reason,omitempty"`
+	Instances map[string]core.FreezeStatus `json:"instances"`
+	Replayed  map[string]int               `json:"replayed,omitempty"`
+}
+
+func freezeBufferFromStrategy(cfg map[string]any) int {
+	if size, ok := intFromConfig(cfg["freeze_buffer"]); ok && size > 0 {
+		return size
+	}
+	return core.DefaultFreezeBuffer
+}
+
+// Frozen reports whether the gateway is frozen for maintenance.
+func (m *Manager) Frozen() bool {
+	m.freezeMu.Lock()
+	defer m.freezeMu.Unlock()
+	return m.freeze.frozen
+}
+
+// Freeze prepares the gateway for an upgrade. Running instances, and those started while frozen,
+// stop handling events and placing orders; events they receive are buffered for replay. Open orders
+// are left on the venues, and each instance's snapshot records the freeze so a restarted gateway
+// restores its instances frozen. Events already queued for a strategy are handled before its
+// state is captured, so the snapshot does not lose them.
+func (m *Manager) Freeze(reason string) FreezeReport {
+	m.freezeMu.Lock()
+	if !m.freeze.frozen {
+		m.freeze = freezeState{frozen: true, since: m.clock(), reason: strings.TrimSpace(reason), states: map[string]map[string]any{}}
+	}
+	m.freezeMu.Unlock()
+
+	ids := m.runningInstanceIDs()
+	for _, id := range ids {
+		if inst, err := m.runningInstance(id); err == nil {
+			inst.base.Freeze()
+		}
+	}
+	for _, id := range ids {
+		if inst, err := m.runningInstance(id); err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), freezeDrainTimeout)
+			idle := inst.base.WaitIdle(ctx)
+			cancel()
+			if !idle && m.logger != nil {
+				m.logger.Printf("lambda %s: events still being handled after %s; freezing its current state", id, freezeDrainTimeout)
+			}
+			m.captureFrozenState(id, inst.strat)
+		}
+		m.persistStrategy(id)
+	}
+	if m.logger != nil {
+		m.logger.Printf("gateway frozen for maintenance (%d instances): %s", len(ids), reason)
+	}
+	return m.FreezeStatus()
+}
+
+// Unfreeze lifts the freeze and replays the events each running instance buffered. The replay
+// outlives ctx, so a caller going away does not discard the buffered events.
+func (m *Manager) Unfreeze(ctx context.Context) FreezeReport {
+	m.freezeMu.Lock()
+	m.freeze = freezeState{frozen: false, since: time.Time{}, reason: "", states: nil}
+	m.freezeMu.Unlock()
+
+	replayed := make(map[string]int)
+	for _, id := range m.runningInstanceIDs() {
+		if inst, err := m.runningInstance(id); err == nil {
+			replayed[id] = inst.base.Unfreeze(context.WithoutCancel(ctx))
+		}
+		m.persistStrategy(id)
+	}
+	if m.logger != nil {
+		m.logger.Printf("gateway unfrozen (%d instances)", len(replayed))
+	}
+	report := m.FreezeStatus()
+	report.Replayed = replayed
+	return report
+}
+
+// FreezeStatus reports the gateway freeze and the events held by each running instance.
+func (m *Manager) FreezeStatus() FreezeReport {
+	m.freezeMu.Lock()
+	state := m.freeze
+	m.freezeMu.Unlock()
+	report := FreezeReport{
+		Frozen:    state.frozen,
+		Since:     state.since,
+		Reason:    state.reason,
+		Instances: make(map[string]core.FreezeStatus),
+		Replayed:  nil,
+	}
+	for _, id := range m.runningInstanceIDs() {
+		if inst, err := m.runningInstance(id); err == nil {
+			report.Instances[id] = inst.base.FreezeStatus()
+		}
+	}
+	return report
+}
+
+func (m *Manager) runningInstanceIDs() []string {
+	m.mu.RLock()
+	ids := make([]string, 0, len(m.instances))
+	for id := range m.instances {
+		ids = append(ids, id)
+	}
+	m.mu.RUnlock()
+	sort.Strings(ids)
+	return ids
+}
+
+// captureFrozenState keeps the working state of a frozen instance's strategy for its snapshot.
+func (m *Manager) captureFrozenState(id string, strategy core.TradingStrategy) {
+	snapshotter, ok := strategy.(core.StateSnapshotter)
+	if !ok {
+		return
+	}
+	state, err := snapshotter.SnapshotState()
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Printf("strategy/%s: freeze state snapshot failed: %v", id, err)
+		}
+		return
+	}
+	m.freezeMu.Lock()
+	defer m.freezeMu.Unlock()
+	if m.freeze.frozen && state != nil {
+		m.freeze.states[id] = state
+	}
+}
+
+// restoreFrozenState hands an instance started while frozen the state captured by the freeze.
+func (m *Manager) restoreFrozenState(id string, strategy core.TradingStrategy) {
+	m.freezeMu.Lock()
+	state := m.freeze.states[id]
+	m.freezeMu.Unlock()
+	snapshotter, ok := strategy.(core.StateSnapshotter)
+	if state == nil || !ok {
+		return
+	}
+	if err := snapshotter.RestoreState(state); err != nil {
+		if m.logger != nil {
+			m.logger.Printf("strategy/%s: restoring freeze state failed, starting cold: %v", id, err)
+		}
+		return
+	}
+	if m.logger != nil {
+		m.logger.Printf("strategy/%s: restored state captured by the freeze", id)
+	}
+}
+
+// freezeMetadata records the freeze, and the strategy state it captured, in the snapshot of a
+// running instance.
+func (m *Manager) freezeMetadata(id string, running bool) map[string]any {
+	metadata := map[string]any{}
+	m.freezeMu.Lock()
+	defer m.freezeMu.Unlock()
+	if running && m.freeze.frozen {
+		metadata[freezeSinceKey] = m.freeze.since.UTC().Format(time.RFC3339Nano)
+		if m.freeze.reason != "" {
+			metadata[freezeReasonKey] = m.freeze.reason
+		}
+		if state := m.freeze.states[id]; state != nil {
+			metadata[freezeStateKey] = state
+		}
+	}
+	return metadata
+}
+
+// restoreFreeze re-engages a freeze recorded in a persisted snapshot before its instance starts,
+// keeping the recorded strategy state for the start to restore.
+func (m *Manager) restoreFreeze(id string, metadata map[string]any) {
+	raw, _ := metadata[freezeSinceKey].(string)
+	if raw == "" {
+		return
+	}
+	since, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		since = m.clock()
+	}
+	reason, _ := metadata[freezeReasonKey].(string)
+	m.freezeMu.Lock()
+	engaged := !m.freeze.frozen
+	if engaged {
+		m.freeze = freezeState{frozen: true, since: since, reason: reason, states: map[string]map[string]any{}}
+	}
+	if state, ok := metadata[freezeStateKey].(map[string]any); ok {
+		m.freeze.states[id] = state
+	}
+	m.freezeMu.Unlock()
+	if engaged && m.logger != nil {
+		m.logger.Printf("strategy/%s: restored frozen; gateway stays frozen until unfrozen", id)
+	}
+}
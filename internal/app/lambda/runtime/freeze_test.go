@@ -0,0 +1,162 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+// snapshotStrategy is a strategy whose working state a freeze can capture and restore.
+type snapshotStrategy struct {
+	core.TradingStrategy
+	state    map[string]any
+	restored map[string]any
+}
+
+func (s *snapshotStrategy) SnapshotState() (map[string]any, error) { return s.state, nil }
+
+func (s *snapshotStrategy) RestoreState(state map[string]any) error {
+	s.restored = state
+	return nil
+}
+
+func TestFreezeIsRecordedInSnapshotsAndRestored(t *testing.T) {
+	manager := newTestManager(t)
+	report := manager.Freeze("rolling upgrade")
+	if !report.Frozen || report.Reason != "rolling upgrade" || report.Since.IsZero() {
+		t.Fatalf("unexpected freeze report %+v", report)
+	}
+	manager.captureFrozenState("alpha", &snapshotStrategy{state: map[string]any{"trades": float64(2)}, restored: nil})
+	if metadata := manager.freezeMetadata("alpha", false); len(metadata) != 0 {
+		t.Fatalf("expected stopped instances not to record the freeze, got %v", metadata)
+	}
+	metadata := manager.freezeMetadata("alpha", true)
+	if metadata[freezeSinceKey] == nil || metadata[freezeReasonKey] != "rolling upgrade" || metadata[freezeStateKey] == nil {
+		t.Fatalf("expected the freeze in running snapshots, got %v", metadata)
+	}
+
+	restarted := newTestManager(t)
+	restarted.restoreFreeze("alpha", metadata)
+	status := restarted.FreezeStatus()
+	if !status.Frozen || status.Reason != "rolling upgrade" || !status.Since.Equal(report.Since) {
+		t.Fatalf("expected the restarted gateway to come back frozen, got %+v", status)
+	}
+	strategy := &snapshotStrategy{state: nil, restored: nil}
+	restarted.restoreFrozenState("alpha", strategy)
+	if strategy.restored["trades"] != float64(2) {
+		t.Fatalf("expected the captured strategy state restored, got %v", strategy.restored)
+	}
+
+	if unfrozen := restarted.Unfreeze(context.Background()); unfrozen.Frozen {
+		t.Fatalf("expected unfreeze to lift the freeze, got %+v", unfrozen)
+	}
+	if metadata := restarted.freezeMetadata("alpha", true); len(metadata) != 0 {
+		t.Fatalf("expected no freeze recorded after unfreeze, got %v", metadata)
+	}
+}
+
+// gatedTradeStrategy counts trades, holding its first callback until gate closes.
+type gatedTradeStrategy struct {
+	core.TradingStrategy
+	entered chan struct{}
+	gate    chan struct{}
+
+	mu     sync.Mutex
+	trades int
+}
+
+func (s *gatedTradeStrategy) OnTrade(context.Context, *schema.Event, schema.TradePayload, float64) {
+	select {
+	case s.entered <- struct{}{}:
+	default:
+	}
+	<-s.gate
+	s.mu.Lock()
+	s.trades++
+	s.mu.Unlock()
+}
+
+func (s *gatedTradeStrategy) SubscribedEvents() []schema.EventType {
+	return []schema.EventType{schema.EventTypeTrade}
+}
+
+func (s *gatedTradeStrategy) WantsCrossProviderEvents() bool { return false }
+
+func (s *gatedTradeStrategy) SnapshotState() (map[string]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return map[string]any{"trades": float64(s.trades)}, nil
+}
+
+func (s *gatedTradeStrategy) RestoreState(map[string]any) error { return nil }
+
+func TestFreezeCapturesStateAfterQueuedEvents(t *testing.T) {
+	manager := newTestManager(t)
+	pools := pool.NewPoolManager()
+	if err := pools.RegisterPool("Event", 16, 16, func() interface{} { return new(schema.Event) }); err != nil {
+		t.Fatalf("register Event pool: %v", err)
+	}
+	bus := eventbus.NewMemoryBus(eventbus.MemoryConfig{BufferSize: 16, FanoutWorkers: 1, Pools: pools})
+	defer bus.Close()
+
+	strategy := &gatedTradeStrategy{entered: make(chan struct{}, 1), gate: make(chan struct{})}
+	base := core.NewBaseLambda("alpha", core.Config{Providers: []string{"binance"}}, bus, nil, pools, strategy, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := base.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	manager.mu.Lock()
+	manager.instances["alpha"] = &lambdaInstance{base: base, strat: strategy}
+	manager.mu.Unlock()
+
+	for i := 0; i < 3; i++ {
+		evt, err := pools.BorrowEventInst(ctx)
+		if err != nil {
+			t.Fatalf("borrow event: %v", err)
+		}
+		evt.Type = schema.EventTypeTrade
+		evt.Provider = "binance"
+		evt.Symbol = "BTC-USDT"
+		evt.Payload = schema.TradePayload{Price: "100", Quantity: "1"}
+		if err := bus.Publish(ctx, evt); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	select {
+	case <-strategy.entered:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the first trade")
+	}
+	// Let the rest of the trades reach the delivery lane behind the held callback.
+	deadline := time.Now().Add(time.Second)
+	for base.QueueDepth() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	frozen := make(chan FreezeReport, 1)
+	go func() { frozen <- manager.Freeze("rolling upgrade") }()
+	select {
+	case <-frozen:
+		t.Fatal("expected the freeze to wait for the queued trades")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(strategy.gate)
+	select {
+	case <-frozen:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the freeze")
+	}
+	manager.freezeMu.Lock()
+	state := manager.freeze.states["alpha"]
+	manager.freezeMu.Unlock()
+	if state["trades"] != float64(3) {
+		t.Fatalf("expected the captured state to include every queued trade, got %v", state)
+	}
+}
@@ -0,0 +1,114 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/domain/strategystore"
+)
+
+const (
+	defaultStrategyHistoryLimit = 100
+	maxInMemoryStrategyHistory  = 500
+)
+
+// StrategyChange carries the operator-supplied attribution recorded with a strategy change.
+// RequestID links the change to the control API request that triggered it.
+type StrategyChange struct {
+	Actor     string
+	Reason    string
+	RequestID string
+}
+
+func (c StrategyChange) logSuffix() string {
+	return requestIDLogSuffix(c.RequestID)
+}
+
+// requestIDLogSuffix renders the control API request identifier for manager log lines so they
+// can be cross-referenced with the access log.
+func requestIDLogSuffix(id string) string {
+	if id = strings.TrimSpace(id); id != "" {
+		return " requestId=" + id
+	}
+	return ""
+}
+
+// StrategyHistory returns the changelog for the named strategy, newest first.
+// Entries come from the persistent store when it supports history, otherwise from memory.
+func (m *Manager) StrategyHistory(ctx context.Context, name string, limit int) ([]strategystore.HistoryEntry, error) {
+	if m == nil {
+		return nil, fmt.Errorf("strategy manager unavailable")
+	}
+	strategy := normalizeStrategyName(name)
+	if strategy == "" {
+		return nil, fmt.Errorf("strategy history: name required")
+	}
+	if limit <= 0 {
+		limit = defaultStrategyHistoryLimit
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if store, ok := m.strategyStore.(strategystore.HistoryStore); ok {
+		entries, err := store.ListHistory(ctx, strategy, limit)
+		if err != nil {
+			return nil, fmt.Errorf("strategy history %s: %w", strategy, err)
+		}
+		return entries, nil
+	}
+
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	recorded := m.history[strategy]
+	out := make([]strategystore.HistoryEntry, 0, min(limit, len(recorded)))
+	for i := len(recorded) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, recorded[i])
+	}
+	return out, nil
+}
+
+func (m *Manager) recordStrategyHistory(entry strategystore.HistoryEntry, change StrategyChange) {
+	if m == nil {
+		return
+	}
+	entry.Strategy = normalizeStrategyName(entry.Strategy)
+	if entry.Strategy == "" {
+		return
+	}
+	entry.Actor = strings.TrimSpace(change.Actor)
+	entry.Reason = strings.TrimSpace(change.Reason)
+	if id := strings.TrimSpace(change.RequestID); id != "" {
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]any, 1)
+		}
+		entry.Metadata["requestId"] = id
+	}
+	entry.RecordedAt = m.clock().UTC()
+
+	if store, ok := m.strategyStore.(strategystore.HistoryStore); ok {
+		if err := store.AppendHistory(m.parentContext(), entry); err != nil && m.logger != nil {
+			m.logger.Printf("strategy %s: record %s history failed: %v", entry.Strategy, entry.Action, err)
+		}
+		return
+	}
+
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+	m.historySeq++
+	entry.ID = m.historySeq
+	recorded := append(m.history[entry.Strategy], entry)
+	if len(recorded) > maxInMemoryStrategyHistory {
+		recorded = recorded[len(recorded)-maxInMemoryStrategyHistory:]
+	}
+	m.history[entry.Strategy] = recorded
+}
+
+// strategyNameFromSelector strips any "@hash" or ":tag" suffix from a module selector.
+func strategyNameFromSelector(selector string) string {
+	trimmed := strings.TrimSpace(selector)
+	if idx := strings.IndexAny(trimmed, "@:"); idx >= 0 {
+		trimmed = trimmed[:idx]
+	}
+	return normalizeStrategyName(trimmed)
+}
@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/strategystore"
+)
+
+func TestManagerStrategyHistory_InMemoryNewestFirst(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	mgr := &Manager{
+		clock:   func() time.Time { return now },
+		history: make(map[string][]strategystore.HistoryEntry),
+	}
+
+	mgr.recordStrategyHistory(strategystore.HistoryEntry{
+		Strategy: "Alpha",
+		Action:   strategystore.HistoryActionUpload,
+		Hash:     "sha256:one",
+		Tag:      "v1.0.0",
+	}, StrategyChange{Actor: " alice ", Reason: "initial release"})
+	now = now.Add(time.Minute)
+	mgr.recordStrategyHistory(strategystore.HistoryEntry{
+		Strategy:     "alpha",
+		Action:       strategystore.HistoryActionTagAssigned,
+		Hash:         "sha256:two",
+		Tag:          "prod",
+		PreviousHash: "sha256:one",
+	}, StrategyChange{Actor: "bob", Reason: "promote hotfix"})
+	mgr.recordStrategyHistory(strategystore.HistoryEntry{
+		Strategy: "beta",
+		Action:   strategystore.HistoryActionUpload,
+	}, StrategyChange{})
+
+	entries, err := mgr.StrategyHistory(context.Background(), "ALPHA", 0)
+	if err != nil {
+		t.Fatalf("StrategyHistory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	latest := entries[0]
+	if latest.Action != strategystore.HistoryActionTagAssigned || latest.PreviousHash != "sha256:one" {
+		t.Fatalf("unexpected latest entry: %+v", latest)
+	}
+	if latest.Actor != "bob" || latest.Reason != "promote hotfix" || !latest.RecordedAt.Equal(now) {
+		t.Fatalf("unexpected attribution on latest entry: %+v", latest)
+	}
+	if entries[1].Actor != "alice" || entries[1].ID >= latest.ID {
+		t.Fatalf("unexpected first entry: %+v", entries[1])
+	}
+
+	limited, err := mgr.StrategyHistory(context.Background(), "alpha", 1)
+	if err != nil {
+		t.Fatalf("StrategyHistory limited: %v", err)
+	}
+	if len(limited) != 1 || limited[0].ID != latest.ID {
+		t.Fatalf("expected only the newest entry, got %+v", limited)
+	}
+}
+
+func TestRecordStrategyHistoryKeepsRequestID(t *testing.T) {
+	mgr := &Manager{
+		clock:   time.Now,
+		history: make(map[string][]strategystore.HistoryEntry),
+	}
+	mgr.recordStrategyHistory(strategystore.HistoryEntry{
+		Strategy: "alpha",
+		Action:   strategystore.HistoryActionUpload,
+	}, StrategyChange{Actor: "ops", RequestID: "req-7"})
+
+	entries, err := mgr.StrategyHistory(context.Background(), "alpha", 0)
+	if err != nil {
+		t.Fatalf("StrategyHistory: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Metadata["requestId"] != "req-7" {
+		t.Fatalf("expected requestId metadata, got %+v", entries)
+	}
+}
+
+func TestStrategyNameFromSelector(t *testing.T) {
+	cases := map[string]string{
+		"Alpha":             "alpha",
+		"alpha:prod":        "alpha",
+		"alpha@sha256:abcd": "alpha",
+		"  ":                "",
+	}
+	for selector, want := range cases {
+		if got := strategyNameFromSelector(selector); got != want {
+			t.Fatalf("strategyNameFromSelector(%q) = %q, want %q", selector, got, want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+)
+
+// RiskSnapshot reports the live risk state shared by all strategy instances.
+func (m *Manager) RiskSnapshot() risk.Snapshot {
+	return m.riskManager.Snapshot()
+}
+
+// RiskConcentration reports how the gateway-wide portfolio is spread over assets and venues,
+// including positions of instances pinned to their own risk profile.
+func (m *Manager) RiskConcentration() risk.ConcentrationReport {
+	return m.riskManager.Concentration()
+}
+
+// ConversionRate returns the multiplier converting an amount of asset from into asset to, from
+// the market prices and fx rates known to the shared risk limits.
+func (m *Manager) ConversionRate(from, to string) (decimal.Decimal, bool) {
+	return m.riskManager.ConversionRate(from, to)
+}
+
+// HaltTrading engages the kill switch on behalf of an operator, including on instances pinned to
+// their own risk profile.
+func (m *Manager) HaltTrading(reason string) risk.Snapshot {
+	message := risk.ManualHaltPrefix
+	if trimmed := strings.TrimSpace(reason); trimmed != "" {
+		message += ": " + trimmed
+	}
+	for _, rm := range m.allRiskManagers() {
+		rm.Halt(message)
+	}
+	if m.logger != nil {
+		m.logger.Printf("kill switch engaged by operator: %s", message)
+	}
+	return m.riskManager.Snapshot()
+}
+
+// ResumeTrading clears the kill switch regardless of what engaged it.
+func (m *Manager) ResumeTrading() risk.Snapshot {
+	halted, reason := m.riskManager.KillSwitchStatus()
+	for _, rm := range m.allRiskManagers() {
+		rm.ResetKillSwitch()
+	}
+	if halted && m.logger != nil {
+		m.logger.Printf("kill switch released by operator (was: %s)", reason)
+	}
+	return m.riskManager.Snapshot()
+}
@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// ErrInvalidLabel is returned when an instance label or label selector is malformed.
+var ErrInvalidLabel = errors.New("invalid instance label")
+
+// LabelSelector matches instances carrying a label. An empty Value matches any value.
+type LabelSelector struct {
+	Key   string
+	Value string
+}
+
+// ParseLabelSelector parses "key:value" or a bare "key" that matches any value of the label.
+func ParseLabelSelector(raw string) (LabelSelector, error) {
+	key, value, _ := strings.Cut(raw, ":")
+	selector := LabelSelector{
+		Key:   strings.ToLower(strings.TrimSpace(key)),
+		Value: strings.TrimSpace(value),
+	}
+	if selector.Key == "" {
+		return LabelSelector{Key: "", Value: ""}, fmt.Errorf("%w: selector %q requires a key", ErrInvalidLabel, raw)
+	}
+	return selector, nil
+}
+
+// Matches reports whether labels satisfy the selector.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	value, ok := labels[s.Key]
+	if !ok {
+		return false
+	}
+	return s.Value == "" || value == s.Value
+}
+
+// MatchesAll reports whether labels satisfy every selector.
+func MatchesAll(selectors []LabelSelector, labels map[string]string) bool {
+	for _, selector := range selectors {
+		if !selector.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// InstanceGroup aggregates the instances sharing one value of a label.
+type InstanceGroup struct {
+	Label string `json:"label"`
+	// Value is empty for instances that do not carry the label.
+	Value     string   `json:"value"`
+	Instances []string `json:"instances"`
+	Total     int      `json:"total"`
+	Running   int      `json:"running"`
+	// Exposure nets positions across running instances per symbol; Notional is the gross sum of
+	// the instances' notionals, so offsetting books still show the capital they tie up.
+	Exposure []core.Exposure `json:"exposure"`
+}
+
+func normalizeSpecLabels(spec *config.LambdaSpec) error {
+	labels, err := config.NormalizeLabels(spec.Labels)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLabel, err)
+	}
+	spec.Labels = labels
+	return nil
+}
+
+func cloneLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(labels))
+	for key, value := range labels {
+		out[key] = value
+	}
+	return out
+}
+
+// InstanceGroups groups the instances matching selectors by the value of label and sums the
+// exposure of their running lambdas per symbol. Groups are ordered by value.
+func (m *Manager) InstanceGroups(label string, selectors []LabelSelector) []InstanceGroup {
+	label = strings.ToLower(strings.TrimSpace(label))
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	groups := make(map[string]*InstanceGroup)
+	positions := make(map[string]map[string]core.Exposure)
+	for id, spec := range m.specs {
+		if !MatchesAll(selectors, spec.Labels) {
+			continue
+		}
+		value := spec.Labels[label]
+		group, ok := groups[value]
+		if !ok {
+			group = &InstanceGroup{
+				Label:     label,
+				Value:     value,
+				Instances: nil,
+				Total:     0,
+				Running:   0,
+				Exposure:  nil,
+			}
+			groups[value] = group
+			positions[value] = make(map[string]core.Exposure)
+		}
+		group.Instances = append(group.Instances, id)
+		group.Total++
+		inst, running := m.instances[id]
+		if !running {
+			continue
+		}
+		group.Running++
+		if inst == nil || inst.base == nil {
+			continue
+		}
+		for _, exposure := range inst.base.Exposure() {
+			combined := positions[value][exposure.Symbol]
+			combined.Symbol = exposure.Symbol
+			combined.Position = combined.Position.Add(exposure.Position)
+			combined.Notional = combined.Notional.Add(exposure.Notional)
+			positions[value][exposure.Symbol] = combined
+		}
+	}
+	out := make([]InstanceGroup, 0, len(groups))
+	for value, group := range groups {
+		sort.Strings(group.Instances)
+		group.Exposure = make([]core.Exposure, 0, len(positions[value]))
+		for _, exposure := range positions[value] {
+			group.Exposure = append(group.Exposure, exposure)
+		}
+		sort.Slice(group.Exposure, func(i, j int) bool { return group.Exposure[i].Symbol < group.Exposure[j].Symbol })
+		out = append(out, *group)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Value < out[j].Value })
+	return out
+}
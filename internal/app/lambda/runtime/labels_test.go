@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCreateNormalizesAndPersistsLabels(t *testing.T) {
+	store := &recordingStrategyStore{}
+	mgr := newTestManager(t, WithStrategyStore(store))
+	spec := baseLambdaSpec()
+	spec.Labels = map[string]string{" Desk ": " futures ", "team": "delta"}
+	if _, err := mgr.Create(spec); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	snapshot, ok := mgr.Instance(spec.ID)
+	if !ok || snapshot.Labels["desk"] != "futures" || snapshot.Labels["team"] != "delta" {
+		t.Fatalf("unexpected labels %v", snapshot.Labels)
+	}
+	saved := store.saved[len(store.saved)-1]
+	if saved.Labels["desk"] != "futures" {
+		t.Fatalf("expected labels persisted, got %v", saved.Labels)
+	}
+	if restored := specFromSnapshot(saved); restored.Labels["team"] != "delta" {
+		t.Fatalf("expected labels restored, got %v", restored.Labels)
+	}
+
+	invalid := baseLambdaSpec()
+	invalid.ID = "beta"
+	invalid.Labels = map[string]string{"desk:eu": "futures"}
+	if _, err := mgr.Create(invalid); !errors.Is(err, ErrInvalidLabel) {
+		t.Fatalf("expected invalid label error, got %v", err)
+	}
+	invalid.Labels = map[string]string{"desk": " "}
+	if _, err := mgr.Create(invalid); !errors.Is(err, ErrInvalidLabel) {
+		t.Fatalf("expected empty value rejected, got %v", err)
+	}
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	selector, err := ParseLabelSelector(" Desk:futures:eu ")
+	if err != nil {
+		t.Fatalf("ParseLabelSelector: %v", err)
+	}
+	if selector.Key != "desk" || selector.Value != "futures:eu" {
+		t.Fatalf("unexpected selector %+v", selector)
+	}
+	if !selector.Matches(map[string]string{"desk": "futures:eu"}) || selector.Matches(map[string]string{"desk": "spot"}) {
+		t.Fatal("unexpected selector match")
+	}
+	presence, err := ParseLabelSelector("team")
+	if err != nil || !presence.Matches(map[string]string{"team": "delta"}) || presence.Matches(nil) {
+		t.Fatalf("expected bare key to match any value, got %+v %v", presence, err)
+	}
+	if _, err := ParseLabelSelector(":futures"); !errors.Is(err, ErrInvalidLabel) {
+		t.Fatalf("expected missing key rejected, got %v", err)
+	}
+}
+
+func TestInstanceGroupsAggregateByLabel(t *testing.T) {
+	mgr := newTestManager(t)
+	for _, entry := range []struct {
+		id     string
+		labels map[string]string
+	}{
+		{"alpha", map[string]string{"desk": "futures", "team": "delta"}},
+		{"beta", map[string]string{"desk": "futures", "team": "gamma"}},
+		{"gamma", map[string]string{"desk": "spot", "team": "delta"}},
+		{"delta", nil},
+	} {
+		spec := baseLambdaSpec()
+		spec.ID = entry.id
+		spec.Labels = entry.labels
+		if _, err := mgr.Create(spec); err != nil {
+			t.Fatalf("Create %s: %v", entry.id, err)
+		}
+	}
+	mgr.mu.Lock()
+	mgr.instances["beta"] = &lambdaInstance{}
+	mgr.mu.Unlock()
+
+	groups := mgr.InstanceGroups("Desk", nil)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %+v", groups)
+	}
+	if groups[0].Value != "" || groups[0].Total != 1 || groups[0].Instances[0] != "delta" {
+		t.Fatalf("expected unlabelled group first, got %+v", groups[0])
+	}
+	futures := groups[1]
+	if futures.Value != "futures" || futures.Total != 2 || futures.Running != 1 || len(futures.Exposure) != 0 {
+		t.Fatalf("unexpected futures group %+v", futures)
+	}
+
+	filtered := mgr.InstanceGroups("desk", []LabelSelector{{Key: "team", Value: "delta"}})
+	if len(filtered) != 2 || filtered[0].Value != "futures" || filtered[0].Instances[0] != "alpha" || filtered[1].Value != "spot" {
+		t.Fatalf("unexpected filtered groups %+v", filtered)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -21,17 +22,24 @@ import (
 	"go.opentelemetry.io/otel/metric"
 
 	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/app/featureflags"
 	"github.com/coachpo/meltica/internal/app/lambda/core"
 	"github.com/coachpo/meltica/internal/app/lambda/js"
 	"github.com/coachpo/meltica/internal/app/lambda/strategies"
 	"github.com/coachpo/meltica/internal/app/provider"
 	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/domain/objectstore"
 	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/riskstore"
 	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+	"github.com/coachpo/meltica/internal/domain/strategyreportstore"
 	"github.com/coachpo/meltica/internal/domain/strategystore"
+	"github.com/coachpo/meltica/internal/domain/usagestore"
 	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
 	"github.com/coachpo/meltica/internal/infra/config"
 	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
 	"github.com/coachpo/meltica/internal/infra/telemetry"
 )
 
@@ -195,6 +203,15 @@ func buildRiskLimits(cfg config.RiskConfig, logger *log.Logger) risk.Limits {
 		}
 	}
 
+	var allowedTIF []schema.TimeInForce
+	normalizedTIF, err := config.NormalizeTimeInForce(cfg.AllowedTimeInForce)
+	if err != nil && logger != nil {
+		logger.Printf("risk: %v; time in force not restricted", err)
+	}
+	for _, tif := range normalizedTIF {
+		allowedTIF = append(allowedTIF, schema.TimeInForce(tif))
+	}
+
 	var breakerCooldown time.Duration
 	if cfg.CircuitBreaker.Cooldown != "" {
 		parsed, err := time.ParseDuration(strings.TrimSpace(cfg.CircuitBreaker.Cooldown))
@@ -207,6 +224,41 @@ func buildRiskLimits(cfg config.RiskConfig, logger *log.Logger) risk.Limits {
 		}
 	}
 
+	var fxRates map[string]decimal.Decimal
+	if len(cfg.FX.Rates) > 0 {
+		fxRates = make(map[string]decimal.Decimal, len(cfg.FX.Rates))
+		for currency, raw := range cfg.FX.Rates {
+			rate, err := decimal.NewFromString(strings.TrimSpace(raw))
+			if err != nil || rate.LessThanOrEqual(decimal.Zero) {
+				if logger != nil {
+					logger.Printf("risk: invalid fx rate %q for %s", raw, currency)
+				}
+				continue
+			}
+			fxRates[strings.ToUpper(strings.TrimSpace(currency))] = rate
+		}
+	}
+
+	minPortfolio := decimal.Zero
+	if raw := strings.TrimSpace(cfg.Concentration.MinPortfolioNotional); raw != "" {
+		parsed, err := decimal.NewFromString(raw)
+		if err != nil || parsed.IsNegative() {
+			if logger != nil {
+				logger.Printf("risk: invalid concentration minPortfolioNotional %q", raw)
+			}
+		} else {
+			minPortfolio = parsed
+		}
+	}
+
+	selfTrade, err := risk.ParseSelfTradePolicy(cfg.SelfTradePrevention)
+	if err != nil {
+		if logger != nil {
+			logger.Printf("risk: %v; self-trade prevention disabled", err)
+		}
+		selfTrade = risk.SelfTradeAllow
+	}
+
 	return risk.Limits{
 		MaxPositionSize:     maxPosSize,
 		MaxNotionalValue:    maxNotional,
@@ -216,6 +268,7 @@ func buildRiskLimits(cfg config.RiskConfig, logger *log.Logger) risk.Limits {
 		MaxConcurrentOrders: cfg.MaxConcurrentOrders,
 		PriceBandPercent:    cfg.PriceBandPercent,
 		AllowedOrderTypes:   allowedOrderTypes,
+		AllowedTimeInForce:  allowedTIF,
 		KillSwitchEnabled:   cfg.KillSwitchEnabled,
 		MaxRiskBreaches:     cfg.MaxRiskBreaches,
 		CircuitBreaker: risk.CircuitBreaker{
@@ -223,6 +276,15 @@ func buildRiskLimits(cfg config.RiskConfig, logger *log.Logger) risk.Limits {
 			Threshold: cfg.CircuitBreaker.Threshold,
 			Cooldown:  breakerCooldown,
 		},
+		FXRates:             fxRates,
+		UseMarketFXRates:    cfg.FX.UseMarketRates,
+		SelfTradePrevention: selfTrade,
+		BalanceCheck:        cfg.BalanceCheck,
+		Concentration: risk.Concentration{
+			MaxAssetPercent:      cfg.Concentration.MaxAssetPercent,
+			MaxVenuePercent:      cfg.Concentration.MaxVenuePercent,
+			MinPortfolioNotional: minPortfolio,
+		},
 	}
 }
 
@@ -259,13 +321,17 @@ type Manager struct {
 	lifecycleMu  sync.RWMutex
 	lifecycleCtx context.Context
 
-	bus              eventbus.Bus
-	pools            *pool.PoolManager
-	providers        ProviderCatalog
-	logger           *log.Logger
-	registrar        RouteRegistrar
-	riskManager      *risk.Manager
-	jsLoader         *js.Loader
+	bus         eventbus.Bus
+	pools       *pool.PoolManager
+	providers   ProviderCatalog
+	logger      *log.Logger
+	registrar   RouteRegistrar
+	riskManager *risk.Manager
+	deadMans    *risk.DeadMansSwitch
+	deadMansCfg config.DeadMansSwitchConfig
+	jsLoader    *js.Loader
+	// vmPool holds VMs warmed for instances restarting onto a new revision; nil when disabled.
+	vmPool           *js.VMPool
 	dynamic          map[string]struct{}
 	baseline         map[string]struct{}
 	dynamicInstances map[string]struct{}
@@ -278,6 +344,74 @@ type Manager struct {
 	instances     map[string]*lambdaInstance
 	strategyStore strategystore.Store
 	orderStore    orderstore.Store
+	flags         *featureflags.Flags
+
+	historyMu  sync.Mutex
+	history    map[string][]strategystore.HistoryEntry
+	historySeq int64
+
+	faultsMu      sync.Mutex
+	stoppedFaults map[string]InstanceFaults
+
+	// Strategy log lines go to logStore through logQueue, or stay in the bounded logs buffer
+	// when no store is configured.
+	logsCfg     config.StrategyLogsConfig
+	logStore    strategylogstore.Store
+	logQueue    chan strategylogstore.Entry
+	logsMu      sync.Mutex
+	logs        map[string][]strategylogstore.Entry
+	logSeq      int64
+	logsDropped int64
+
+	// reportStore persists strategy reports; without it the recent reports are kept in reports.
+	reportStore strategyreportstore.Store
+	reportsCfg  config.StrategyReportsConfig
+	reportsMu   sync.Mutex
+	reports     []StrategyReport
+	reportSeq   int64
+
+	// usage meters instance resource usage per day; usageStore persists it.
+	usageCfg   config.StrategyUsageConfig
+	usage      *usageMeter
+	usageStore usagestore.Store
+
+	riskProfilesMu    sync.Mutex
+	riskProfiles      map[string]RiskProfile
+	builtinProfiles   map[string]RiskProfile
+	riskProfileStore  riskstore.Store
+	globalRiskProfile string
+	instanceRisk      map[string]*pinnedRisk
+
+	// shadows holds the running shadow instances by the ID of the live instance they follow.
+	shadowsMu sync.Mutex
+	shadows   map[string]*shadowRun
+
+	// execTaps fans the execution reports of each instance out to order entry sessions.
+	execTapsMu sync.Mutex
+	execTaps   map[string]map[*execReportTap]struct{}
+
+	// freeze holds every instance's event processing for a gateway upgrade; see Freeze.
+	freezeMu sync.Mutex
+	freeze   freezeState
+
+	orderNormalization config.OrderNormalizationMode
+	// handlerScheduler admits strategy handlers by instance priority; nil when scheduling is disabled.
+	handlerScheduler *core.HandlerScheduler
+	// syntheticLegs maps provider → synthetic symbol → leg symbols streamed from that provider.
+	syntheticLegs map[string]map[string][]string
+	// environment and allowedCapabilities gate instances by the capabilities their revision
+	// requires; a nil set allows every capability.
+	environment         config.Environment
+	allowedCapabilities map[strategies.Capability]struct{}
+	// archiveKey signs and verifies registry archives; empty disables them.
+	archiveKey []byte
+
+	autoRefreshCfg     config.StrategyAutoRefreshConfig
+	autoRefreshMu      sync.Mutex
+	registryState      registryState
+	autoRefreshRuns    int64
+	autoRefreshLastRun time.Time
+	lastRegistryChange *RegistryChangeSummary
 
 	revisionUsage            map[string]*revisionUsage
 	revisionGauge            metric.Int64ObservableGauge
@@ -285,6 +419,7 @@ type Manager struct {
 	uploadValidationFailures metric.Int64Counter
 	tagAssignmentCounter     metric.Int64Counter
 	tagDeleteCounter         metric.Int64Counter
+	registryChangeCounter    metric.Int64Counter
 }
 
 // Option configures manager behaviour.
@@ -304,6 +439,21 @@ func WithOrderStore(store orderstore.Store) Option {
 	}
 }
 
+// WithStrategyMirror shares the strategy registry tree through bucket, keeping the strategy
+// directory as a local cache.
+func WithStrategyMirror(bucket objectstore.Bucket) Option {
+	return func(m *Manager) {
+		m.jsLoader.SetMirror(bucket)
+	}
+}
+
+// WithFeatureFlags gates durable subscriptions and tag rollouts behind the gateway feature flags.
+func WithFeatureFlags(flags *featureflags.Flags) Option {
+	return func(m *Manager) {
+		m.flags = flags
+	}
+}
+
 type lambdaInstance struct {
 	base   *core.BaseLambda
 	cancel context.CancelFunc
@@ -315,7 +465,7 @@ type lambdaInstance struct {
 // NewManager creates a new lambda manager with the specified dependencies.
 func NewManager(cfg config.AppConfig, bus eventbus.Bus, pools *pool.PoolManager, providers ProviderCatalog, logger *log.Logger, registrar RouteRegistrar, opts ...Option) (*Manager, error) {
 	if logger == nil {
-		logger = log.New(os.Stdout, "lambda-manager ", log.LstdFlags|log.Lmicroseconds)
+		logger = log.New(redact.Stdout, "lambda-manager ", log.LstdFlags|log.Lmicroseconds)
 	}
 
 	rm := risk.NewManager(buildRiskLimits(cfg.Risk, logger))
@@ -329,14 +479,9 @@ func NewManager(cfg config.AppConfig, bus eventbus.Bus, pools *pool.PoolManager,
 	if err != nil {
 		return nil, fmt.Errorf("lambda manager: create loader: %w", err)
 	}
-	if cfg.Strategies.RequireRegistry {
-		registryPath := filepath.Join(loader.Root(), "registry.json")
-		if _, err := os.Stat(registryPath); err != nil {
-			if errors.Is(err, fs.ErrNotExist) {
-				return nil, fmt.Errorf("lambda manager: registry required but %s missing", registryPath)
-			}
-			return nil, fmt.Errorf("lambda manager: check registry: %w", err)
-		}
+	allowed, err := capabilityPolicy(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("lambda manager: %w", err)
 	}
 
 	mgr := &Manager{
@@ -349,7 +494,10 @@ func NewManager(cfg config.AppConfig, bus eventbus.Bus, pools *pool.PoolManager,
 		logger:                   logger,
 		registrar:                registrar,
 		riskManager:              rm,
+		deadMans:                 nil,
+		deadMansCfg:              cfg.DeadMansSwitch,
 		jsLoader:                 loader,
+		vmPool:                   nil,
 		dynamic:                  make(map[string]struct{}),
 		baseline:                 make(map[string]struct{}),
 		dynamicInstances:         make(map[string]struct{}),
@@ -361,22 +509,90 @@ func NewManager(cfg config.AppConfig, bus eventbus.Bus, pools *pool.PoolManager,
 		instances:                make(map[string]*lambdaInstance),
 		strategyStore:            nil,
 		orderStore:               nil,
+		flags:                    nil,
+		historyMu:                sync.Mutex{},
+		history:                  make(map[string][]strategystore.HistoryEntry),
+		historySeq:               0,
+		faultsMu:                 sync.Mutex{},
+		stoppedFaults:            make(map[string]InstanceFaults),
+		logsCfg:                  cfg.Strategies.Logs,
+		logStore:                 nil,
+		logQueue:                 make(chan strategylogstore.Entry, strategyLogQueueSize),
+		logsMu:                   sync.Mutex{},
+		logs:                     make(map[string][]strategylogstore.Entry),
+		logSeq:                   0,
+		logsDropped:              0,
+		reportStore:              nil,
+		reportsCfg:               cfg.Strategies.Reports,
+		reportsMu:                sync.Mutex{},
+		reports:                  nil,
+		reportSeq:                0,
+		usageCfg:                 cfg.Strategies.Usage,
+		usage:                    newUsageMeter(),
+		usageStore:               nil,
+		riskProfilesMu:           sync.Mutex{},
+		riskProfiles:             make(map[string]RiskProfile),
+		builtinProfiles:          builtinRiskProfiles(cfg.Risk),
+		riskProfileStore:         nil,
+		globalRiskProfile:        "",
+		instanceRisk:             make(map[string]*pinnedRisk),
+		shadowsMu:                sync.Mutex{},
+		shadows:                  make(map[string]*shadowRun),
+		execTapsMu:               sync.Mutex{},
+		execTaps:                 make(map[string]map[*execReportTap]struct{}),
+		freezeMu:                 sync.Mutex{},
+		freeze:                   freezeState{frozen: false, since: time.Time{}, reason: "", states: nil},
+		orderNormalization:       cfg.Orders.Normalization,
+		handlerScheduler:         newHandlerScheduler(cfg.Strategies.Scheduling),
+		syntheticLegs:            syntheticLegsByProvider(cfg.Synthetics),
+		environment:              cfg.Environment,
+		allowedCapabilities:      allowed,
+		archiveKey:               []byte(cfg.Strategies.Archive.SigningKey),
+		autoRefreshCfg:           cfg.Strategies.AutoRefresh,
+		autoRefreshMu:            sync.Mutex{},
+		registryState:            registryState{revisions: nil, tags: nil},
+		autoRefreshRuns:          0,
+		autoRefreshLastRun:       time.Time{},
+		lastRegistryChange:       nil,
 		revisionUsage:            make(map[string]*revisionUsage),
 		revisionGauge:            nil,
 		revisionLifecycleMetric:  nil,
 		uploadValidationFailures: nil,
 		tagAssignmentCounter:     nil,
 		tagDeleteCounter:         nil,
+		registryChangeCounter:    nil,
 	}
 	for _, opt := range opts {
 		if opt != nil {
 			opt(mgr)
 		}
 	}
+	if cfg.Strategies.RequireRegistry {
+		// With a mirror the shared registry counts; pull it before checking the local copy.
+		if err := loader.Sync(context.Background()); err != nil {
+			return nil, fmt.Errorf("lambda manager: %w", err)
+		}
+		registryPath := filepath.Join(loader.Root(), "registry.json")
+		if _, err := os.Stat(registryPath); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("lambda manager: registry required but %s missing", registryPath)
+			}
+			return nil, fmt.Errorf("lambda manager: check registry: %w", err)
+		}
+	}
+	rm.SetRestingOrderCanceller(mgr.cancelRestingOrder)
+	mgr.installSymbolPolicy(cfg.SymbolPolicy)
+	if cfg.DeadMansSwitch.Enabled && cfg.DeadMansSwitch.Interval > 0 {
+		mgr.deadMans = risk.NewDeadMansSwitch(cfg.DeadMansSwitch.Interval, mgr.clock, mgr.tripDeadMansSwitch)
+	}
 	mgr.setupMetrics()
 	if _, err := mgr.installJavaScriptStrategies(context.Background()); err != nil {
 		return nil, fmt.Errorf("lambda manager: install javascript strategies: %w", err)
 	}
+	if cfg.Strategies.WarmPool.Enabled {
+		mgr.vmPool = js.NewVMPool(cfg.Strategies.WarmPool.Capacity)
+	}
+
 	return mgr, nil
 }
 
@@ -431,6 +647,15 @@ func (m *Manager) setupMetrics() {
 	} else if m.logger != nil {
 		m.logger.Printf("lambda manager: register tag deletion counter: %v", err)
 	}
+	changes, err := meter.Int64Counter("strategy_registry_auto_refresh_changes_total",
+		metric.WithDescription("Registry changes detected by scheduled refreshes per strategy"),
+		metric.WithUnit("{event}"),
+	)
+	if err == nil {
+		m.registryChangeCounter = changes
+	} else if m.logger != nil {
+		m.logger.Printf("lambda manager: register registry change counter: %v", err)
+	}
 }
 
 func (m *Manager) observeRevisionUsage(_ context.Context, observer metric.Int64Observer) error {
@@ -874,6 +1099,8 @@ func (m *Manager) RevisionUsageFor(strategy, hash string) RevisionUsageSummary {
 		Strategy:        config.LambdaStrategySpec{Identifier: strategy, Config: nil, Selector: "", Tag: "", Hash: hash},
 		ProviderSymbols: nil,
 		Providers:       nil,
+		Labels:          nil,
+		DryRun:          nil,
 	}
 	summary := m.revisionUsageSummary(spec)
 	if summary == nil {
@@ -915,7 +1142,7 @@ func (m *Manager) RevisionInstances(strategy, hash string, includeStopped bool)
 			continue
 		}
 		usage := m.revisionUsageSummaryLocked(spec)
-		out = append(out, summaryOf(spec, running, usage))
+		out = append(out, summaryOf(spec, running, usage, m.instanceWarmupLocked(id)))
 	}
 	if len(out) == 0 {
 		return nil
@@ -963,8 +1190,16 @@ func (m *Manager) UpdateRiskLimits(limits risk.Limits) {
 			}
 			allowed = strings.Join(names, ",")
 		}
+		allowedTIF := "any"
+		if len(limits.AllowedTimeInForce) > 0 {
+			names := make([]string, 0, len(limits.AllowedTimeInForce))
+			for _, tif := range limits.AllowedTimeInForce {
+				names = append(names, string(tif))
+			}
+			allowedTIF = strings.Join(names, ",")
+		}
 		m.logger.Printf(
-			"risk limits applied: throttle=%.2f burst=%d maxPosition=%s maxNotional=%s concurrent=%d killSwitch=%t priceBand=%.2f allowedTypes=%s circuitBreaker(enabled=%t threshold=%d cooldown=%s)",
+			"risk limits applied: throttle=%.2f burst=%d maxPosition=%s maxNotional=%s concurrent=%d killSwitch=%t priceBand=%.2f allowedTypes=%s allowedTIF=%s circuitBreaker(enabled=%t threshold=%d cooldown=%s)",
 			limits.OrderThrottle,
 			limits.OrderBurst,
 			limits.MaxPositionSize.String(),
@@ -973,6 +1208,7 @@ func (m *Manager) UpdateRiskLimits(limits risk.Limits) {
 			limits.KillSwitchEnabled,
 			limits.PriceBandPercent,
 			allowed,
+			allowedTIF,
 			limits.CircuitBreaker.Enabled,
 			limits.CircuitBreaker.Threshold,
 			limits.CircuitBreaker.Cooldown,
@@ -981,12 +1217,22 @@ func (m *Manager) UpdateRiskLimits(limits risk.Limits) {
 }
 
 // ApplyRiskConfig converts the supplied risk configuration into limits and applies them.
+// Hand-edited limits detach the shared limits from any globally applied risk profile.
 func (m *Manager) ApplyRiskConfig(cfg config.RiskConfig) risk.Limits {
 	limits := buildRiskLimits(cfg, m.logger)
 	m.UpdateRiskLimits(limits)
+	m.riskProfilesMu.Lock()
+	m.globalRiskProfile = ""
+	m.riskProfilesMu.Unlock()
 	return limits
 }
 
+// RiskConfigIncreases describes the position and notional limits cfg raises by more than percent
+// above the shared limits.
+func (m *Manager) RiskConfigIncreases(cfg config.RiskConfig, percent float64) []string {
+	return risk.LimitIncreases(m.RiskLimits(), buildRiskLimits(cfg, nil), percent)
+}
+
 // RefreshJavaScriptStrategies reloads JavaScript modules and restarts affected instances.
 func (m *Manager) RefreshJavaScriptStrategies(ctx context.Context) error {
 	var targets RefreshTargets
@@ -999,6 +1245,31 @@ func (m *Manager) RefreshJavaScriptStrategiesWithTargets(ctx context.Context, ta
 	return m.refreshJavaScriptStrategies(ctx, targets)
 }
 
+// warmRestarts prepares a VM per instance about to restart onto a new revision, so the restarts
+// claim VMs whose modules already ran instead of running each module in turn.
+func (m *Manager) warmRestarts(updates map[string]config.LambdaSpec, restartIDs []string) {
+	if m.vmPool == nil || m.jsLoader == nil || len(restartIDs) == 0 {
+		return
+	}
+	targets := make([]js.WarmTarget, 0, len(restartIDs))
+	for _, id := range restartIDs {
+		spec := updates[id]
+		module, err := m.jsLoader.Get(spec.Strategy.Hash)
+		if err != nil || module == nil {
+			continue
+		}
+		target := js.WarmTarget{Module: module, Seed: nil}
+		if seed, ok := js.SeedFromConfig(spec.Strategy.Config); ok {
+			target.Seed = &seed
+		}
+		targets = append(targets, target)
+	}
+	warmed := m.vmPool.Warm(targets)
+	if m.logger != nil {
+		m.logger.Printf("strategy refresh: warmed %d of %d VM(s) for restarts (%s)", warmed, len(restartIDs), m.vmPool.Stats())
+	}
+}
+
 func (m *Manager) refreshJavaScriptStrategies(ctx context.Context, targets RefreshTargets) ([]RefreshResult, error) {
 	if _, err := m.installJavaScriptStrategies(ctx); err != nil {
 		return nil, err
@@ -1075,6 +1346,7 @@ func (m *Manager) refreshJavaScriptStrategies(ctx context.Context, targets Refre
 		m.mu.Unlock()
 	}
 
+	m.warmRestarts(updates, restartIDs)
 	for _, id := range restartIDs {
 		if err := m.Stop(id); err != nil && !errors.Is(err, ErrInstanceNotRunning) {
 			if m.logger != nil {
@@ -1167,7 +1439,7 @@ func (m *Manager) installJavaScriptStrategies(ctx context.Context) (map[string]s
 		def := StrategyDefinition{
 			meta: strategies.CloneMetadata(summary.Metadata),
 			factory: func(cfg map[string]any) (core.TradingStrategy, error) {
-				return js.NewStrategy(mod, cfg, m.logger)
+				return m.vmPool.NewStrategy(mod, cfg, m.logger)
 			},
 		}
 		normalized, err := normalizeStrategyDefinition(def)
@@ -1233,6 +1505,12 @@ func (m *Manager) Create(spec config.LambdaSpec) (*core.BaseLambda, error) {
 	if len(spec.AllSymbols()) == 0 {
 		return nil, fmt.Errorf("strategy %s: instrument symbols required", spec.ID)
 	}
+	if err := normalizeSpecLabels(&spec); err != nil {
+		return nil, fmt.Errorf("strategy %s: %w", spec.ID, err)
+	}
+	if err := m.validateSymbols(spec); err != nil {
+		return nil, fmt.Errorf("strategy %s: %w", spec.ID, err)
+	}
 	if err := m.ensureSpec(&spec, false); err != nil {
 		return nil, fmt.Errorf("ensure spec %s: %w", spec.ID, err)
 	}
@@ -1284,6 +1562,9 @@ func (m *Manager) ensureSpec(spec *config.LambdaSpec, allowReplace bool) error {
 	if _, ok := m.strategies[name]; !ok {
 		return fmt.Errorf("strategy %q not registered", spec.Strategy.Identifier)
 	}
+	if err := m.checkCapabilities(spec.Strategy); err != nil {
+		return err
+	}
 
 	m.mu.Lock()
 	if _, exists := m.specs[spec.ID]; exists && !allowReplace {
@@ -1312,39 +1593,32 @@ func (m *Manager) Start(ctx context.Context, id string) error {
 	}
 	m.mu.Unlock()
 
-	_, _, _, err = m.launch(ctx, spec, true)
-	return err
+	if _, _, _, err = m.launch(ctx, spec, true); err != nil {
+		return err
+	}
+	if m.logger != nil {
+		m.logger.Printf("strategy instance %s started%s", spec.ID, requestIDLogSuffix(telemetry.RequestIDFromContext(ctx)))
+	}
+	return nil
 }
 
 func (m *Manager) launch(ctx context.Context, spec config.LambdaSpec, registerNow bool) (*core.BaseLambda, []string, []dispatcher.RouteDeclaration, error) {
-	providers := spec.Providers
-	if len(providers) == 0 {
-		return nil, nil, nil, fmt.Errorf("strategy %s: providers required", spec.ID)
-	}
-	resolvedProviders := make([]string, 0, len(providers))
-	for _, name := range providers {
-		name = strings.TrimSpace(name)
-		if name == "" {
-			continue
-		}
-		if _, ok := m.providers.Provider(name); !ok {
-			return nil, nil, nil, fmt.Errorf("provider %q unavailable", name)
-		}
-		resolvedProviders = append(resolvedProviders, name)
-	}
-	if len(resolvedProviders) == 0 {
-		return nil, nil, nil, fmt.Errorf("strategy %s: no valid providers resolved", spec.ID)
+	resolvedProviders, err := m.resolveProviders(spec)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	strategy, err := m.buildStrategy(spec.Strategy)
 	if err != nil {
+		m.recordStrategyLog(spec, strategylogstore.SourceValidation, strategylogstore.LevelError, err.Error())
 		return nil, nil, nil, fmt.Errorf("strategy %s: %w", spec.ID, err)
 	}
 	if strategy != nil && len(resolvedProviders) > 1 && !strategy.WantsCrossProviderEvents() {
+		m.recordStrategyLog(spec, strategylogstore.SourceValidation, strategylogstore.LevelError, "strategy does not support cross-provider feeds")
 		return nil, nil, nil, fmt.Errorf("strategy %s does not support cross-provider feeds", spec.Strategy.Identifier)
 	}
 
-	routes := buildRouteDeclarations(strategy, spec)
+	routes := buildRouteDeclarations(strategy, m.routeSpec(spec))
 	var registered bool
 	if registerNow && m.registrar != nil && len(routes) > 0 {
 		if err := m.registrar.RegisterLambda(ctx, spec.ID, resolvedProviders, routes); err != nil {
@@ -1353,16 +1627,27 @@ func (m *Manager) launch(ctx context.Context, spec config.LambdaSpec, registerNo
 		registered = true
 	}
 
-	orderRouter := &providerOrderRouter{catalog: m.providers}
-	dryRun := true
-	if raw, ok := spec.Strategy.Config["dry_run"]; ok {
-		if val, ok := raw.(bool); ok {
-			dryRun = val
+	orderRouter := &providerOrderRouter{catalog: m.providers, normalization: m.orderNormalization}
+	baseCfg := m.baseConfig(spec, resolvedProviders)
+	if raw, ok := spec.Strategy.Config["durable_subscription"].(bool); ok && raw {
+		baseCfg.DurableSubscription = m.flags.Enabled(featureflags.DurableSubscriptions)
+		if !baseCfg.DurableSubscription && m.logger != nil {
+			m.logger.Printf("lambda %s: durable subscription disabled by feature flag %s", spec.ID, featureflags.DurableSubscriptions)
 		}
 	}
-	baseCfg := core.Config{Providers: resolvedProviders, ProviderSymbols: spec.ProviderSymbolMap(), DryRun: dryRun}
-	base := core.NewBaseLambda(spec.ID, baseCfg, m.bus, orderRouter, m.pools, strategy, m.riskManager, m.orderStore)
+	riskManager := m.instanceRiskManager(spec.ID, spec.Strategy.Config)
+	base := core.NewBaseLambda(spec.ID, baseCfg, m.bus, orderRouter, m.pools, strategy, riskManager, m.orderStore)
+	if m.Frozen() {
+		base.Freeze()
+	}
 	bindStrategy(strategy, base, m.logger)
+	if base.IsFrozen() {
+		m.restoreFrozenState(spec.ID, strategy)
+	}
+	m.bindErrorBudget(spec, strategy)
+	m.bindStrategyLogs(spec, strategy)
+	m.attachShadowObserver(spec.ID, base)
+	m.attachExecReportObserver(spec.ID, base)
 
 	runCtx, cancel := context.WithCancel(m.parentContext())
 	errs, err := base.Start(runCtx)
@@ -1371,19 +1656,62 @@ func (m *Manager) launch(ctx context.Context, spec config.LambdaSpec, registerNo
 		if registered && m.registrar != nil {
 			_ = m.registrar.UnregisterLambda(ctx, spec.ID)
 		}
+		m.recordStrategyLog(spec, strategylogstore.SourceRuntime, strategylogstore.LevelError, "start failed: "+err.Error())
 		return nil, nil, nil, fmt.Errorf("start strategy %s: %w", spec.ID, err)
 	}
 
 	m.mu.Lock()
 	revisionKey := m.markInstanceRunningLocked(spec, spec.ID)
 	m.instances[spec.ID] = &lambdaInstance{base: base, cancel: cancel, errs: errs, strat: strategy, revKey: revisionKey}
+	m.recordSeedLocked(spec.ID, strategy)
 	m.mu.Unlock()
 
-	go m.observe(runCtx, spec.ID, errs, strategy)
+	go m.observe(runCtx, spec, errs, strategy)
 	m.persistStrategy(spec.ID)
 	return base, resolvedProviders, routes, nil
 }
 
+// resolveProviders returns the spec's providers, which must all be available.
+func (m *Manager) resolveProviders(spec config.LambdaSpec) ([]string, error) {
+	if len(spec.Providers) == 0 {
+		return nil, fmt.Errorf("strategy %s: providers required", spec.ID)
+	}
+	resolved := make([]string, 0, len(spec.Providers))
+	for _, name := range spec.Providers {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := m.providers.Provider(name); !ok {
+			return nil, fmt.Errorf("provider %q unavailable", name)
+		}
+		resolved = append(resolved, name)
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("strategy %s: no valid providers resolved", spec.ID)
+	}
+	return resolved, nil
+}
+
+// baseConfig derives the lambda configuration of an instance from its spec.
+func (m *Manager) baseConfig(spec config.LambdaSpec, providers []string) core.Config {
+	return core.Config{
+		Providers:           providers,
+		ProviderSymbols:     spec.ProviderSymbolMap(),
+		DryRun:              spec.DryRunEnabled(),
+		Delivery:            m.deliveryConfig(spec.Strategy.Config),
+		DurableSubscription: false,
+		SubAccounts:         spec.SubAccountMap(),
+		History:             historyConfigFromStrategy(spec.Strategy.Config),
+		Orders:              orderHistoryConfigFromStrategy(spec.Strategy.Config),
+		BookDeltas:          bookDeltaConfigFromStrategy(spec.Strategy.Config),
+		ThrottleQueue:       throttleQueueConfigFromStrategy(spec.Strategy.Config),
+		Warmup:              m.warmupConfigFromStrategy(spec.Strategy.Config),
+		FreezeBuffer:        freezeBufferFromStrategy(spec.Strategy.Config),
+		OrderCallbacks:      orderCallbackConfigFromStrategy(spec.Strategy.Config),
+	}
+}
+
 func (m *Manager) specForID(id string) (config.LambdaSpec, error) {
 	id = strings.TrimSpace(id)
 	if id == "" {
@@ -1414,11 +1742,13 @@ func (m *Manager) Stop(id string) error {
 		return ErrInstanceNotRunning
 	}
 	revKey := inst.revKey
+	spec := m.specs[id]
 	delete(m.instances, id)
 	m.markInstanceStoppedLocked(revKey, id)
 	m.mu.Unlock()
 
 	inst.cancel()
+	m.retireUsage(id, spec, inst.base)
 	if m.registrar != nil {
 		_ = m.registrar.UnregisterLambda(context.Background(), id)
 	}
@@ -1433,6 +1763,8 @@ func (m *Manager) Remove(id string) error {
 	if err != nil && !errors.Is(err, ErrInstanceNotRunning) {
 		return err
 	}
+	m.dropInstanceRiskManager(id)
+	_, _ = m.StopShadow(id)
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -1469,6 +1801,12 @@ func (m *Manager) Update(ctx context.Context, spec config.LambdaSpec) error {
 	if current.Strategy.Identifier != spec.Strategy.Identifier {
 		return fmt.Errorf("strategy is immutable for %s", spec.ID)
 	}
+	if err := normalizeSpecLabels(&spec); err != nil {
+		return fmt.Errorf("strategy %s: %w", spec.ID, err)
+	}
+	if err := m.validateSymbols(spec); err != nil {
+		return fmt.Errorf("strategy %s: %w", spec.ID, err)
+	}
 	if err := m.ensureSpec(&spec, true); err != nil {
 		return err
 	}
@@ -1482,6 +1820,9 @@ func (m *Manager) Update(ctx context.Context, spec config.LambdaSpec) error {
 			return err
 		}
 	}
+	if m.logger != nil {
+		m.logger.Printf("strategy instance %s updated (restarted=%t)%s", spec.ID, startAfterUpdate, requestIDLogSuffix(telemetry.RequestIDFromContext(ctx)))
+	}
 	return nil
 }
 
@@ -1492,21 +1833,30 @@ type InstanceSummary struct {
 	StrategyTag        string                `json:"strategyTag,omitempty"`
 	StrategyHash       string                `json:"strategyHash,omitempty"`
 	StrategySelector   string                `json:"strategySelector,omitempty"`
+	Labels             map[string]string     `json:"labels,omitempty"`
 	Providers          []string              `json:"providers"`
 	AggregatedSymbols  []string              `json:"aggregatedSymbols"`
 	Running            bool                  `json:"running"`
+	DryRun             bool                  `json:"dryRun"`
+	Priority           core.Priority         `json:"priority"`
 	Usage              *RevisionUsageSummary `json:"usage,omitempty"`
+	// Warmup reports the start preconditions of a running instance.
+	Warmup *core.WarmupStatus `json:"warmup,omitempty"`
 }
 
 // InstanceSnapshot captures the detailed state of a lambda instance.
 type InstanceSnapshot struct {
 	ID                string                            `json:"id"`
 	Strategy          config.LambdaStrategySpec         `json:"strategy"`
+	Labels            map[string]string                 `json:"labels,omitempty"`
 	Providers         []string                          `json:"providers"`
 	ProviderSymbols   map[string]config.ProviderSymbols `json:"scope"`
 	AggregatedSymbols []string                          `json:"aggregatedSymbols"`
 	Running           bool                              `json:"running"`
+	DryRun            bool                              `json:"dryRun"`
 	Usage             *RevisionUsageSummary             `json:"usage,omitempty"`
+	// Warmup reports the start preconditions of a running instance.
+	Warmup *core.WarmupStatus `json:"warmup,omitempty"`
 }
 
 // Instances returns summaries of all lambda instances.
@@ -1517,7 +1867,7 @@ func (m *Manager) Instances() []InstanceSummary {
 	for id, spec := range m.specs {
 		_, running := m.instances[id]
 		usage := m.revisionUsageSummaryLocked(spec)
-		out = append(out, summaryOf(spec, running, usage))
+		out = append(out, summaryOf(spec, running, usage, m.instanceWarmupLocked(id)))
 	}
 	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
 	return out
@@ -1536,18 +1886,22 @@ func (m *Manager) Instance(id string) (InstanceSnapshot, bool) {
 				Hash:       "",
 				Config:     map[string]any{},
 			},
+			Labels:            nil,
 			Providers:         []string{},
 			ProviderSymbols:   map[string]config.ProviderSymbols{},
 			AggregatedSymbols: []string{},
 			Running:           false,
+			DryRun:            true,
 			Usage:             nil,
+			Warmup:            nil,
 		}, false
 	}
 	m.mu.RLock()
 	_, running := m.instances[spec.ID]
 	usage := m.revisionUsageSummaryLocked(spec)
+	warmup := m.instanceWarmupLocked(spec.ID)
 	m.mu.RUnlock()
-	return snapshotOf(spec, running, usage), true
+	return snapshotOf(spec, running, usage, warmup), true
 }
 
 // IsBaseline reports whether the instance originated from the baseline manifest.
@@ -1560,7 +1914,7 @@ func (m *Manager) IsDynamic(id string) bool {
 	return m.isDynamicInstance(id)
 }
 
-func summaryOf(spec config.LambdaSpec, running bool, usage *RevisionUsageSummary) InstanceSummary {
+func summaryOf(spec config.LambdaSpec, running bool, usage *RevisionUsageSummary, warmup *core.WarmupStatus) InstanceSummary {
 	providers := append([]string(nil), spec.Providers...)
 	aggregated := spec.AllSymbols()
 	return InstanceSummary{
@@ -1569,14 +1923,18 @@ func summaryOf(spec config.LambdaSpec, running bool, usage *RevisionUsageSummary
 		StrategyTag:        spec.Strategy.Tag,
 		StrategyHash:       spec.Strategy.Hash,
 		StrategySelector:   spec.Strategy.Selector,
+		Labels:             cloneLabels(spec.Labels),
 		Providers:          providers,
 		AggregatedSymbols:  aggregated,
 		Running:            running,
+		DryRun:             spec.DryRunEnabled(),
+		Priority:           instancePriority(spec.Strategy.Config),
 		Usage:              cloneRevisionUsage(usage),
+		Warmup:             warmup,
 	}
 }
 
-func snapshotOf(spec config.LambdaSpec, running bool, usage *RevisionUsageSummary) InstanceSnapshot {
+func snapshotOf(spec config.LambdaSpec, running bool, usage *RevisionUsageSummary, warmup *core.WarmupStatus) InstanceSnapshot {
 	strategyConfig := copyMap(spec.Strategy.Config)
 	providers := append([]string(nil), spec.Providers...)
 	assignments := cloneProviderSymbols(spec.ProviderSymbols)
@@ -1590,15 +1948,18 @@ func snapshotOf(spec config.LambdaSpec, running bool, usage *RevisionUsageSummar
 			Tag:        spec.Strategy.Tag,
 			Hash:       spec.Strategy.Hash,
 		},
+		Labels:            cloneLabels(spec.Labels),
 		Providers:         providers,
 		ProviderSymbols:   assignments,
 		AggregatedSymbols: aggregated,
 		Running:           running,
+		DryRun:            spec.DryRunEnabled(),
 		Usage:             cloneRevisionUsage(usage),
+		Warmup:            warmup,
 	}
 }
 
-func (m *Manager) observe(ctx context.Context, id string, errs <-chan error, strat core.TradingStrategy) {
+func (m *Manager) observe(ctx context.Context, spec config.LambdaSpec, errs <-chan error, strat core.TradingStrategy) {
 	defer closeStrategy(strat)
 	for {
 		select {
@@ -1609,14 +1970,16 @@ func (m *Manager) observe(ctx context.Context, id string, errs <-chan error, str
 				return
 			}
 			if err != nil {
-				m.logger.Printf("strategy %s: %v", id, err)
+				m.logger.Printf("strategy %s: %v", spec.ID, err)
+				m.recordStrategyLog(spec, strategylogstore.SourceRuntime, strategylogstore.LevelError, err.Error())
 			}
 		}
 	}
 }
 
 type providerOrderRouter struct {
-	catalog ProviderCatalog
+	catalog       ProviderCatalog
+	normalization config.OrderNormalizationMode
 }
 
 func (r *providerOrderRouter) SubmitOrder(ctx context.Context, req schema.OrderRequest) error {
@@ -1627,6 +1990,10 @@ func (r *providerOrderRouter) SubmitOrder(ctx context.Context, req schema.OrderR
 	if providerName == "" {
 		return fmt.Errorf("order provider required")
 	}
+	if submitter, ok := r.catalog.(core.OrderSubmitter); ok {
+		// The provider manager applies drains, resyncs and the per-venue order circuit.
+		return submitter.SubmitOrder(ctx, req) //nolint:wrapcheck // the provider manager names the provider in its errors
+	}
 	inst, ok := r.catalog.Provider(providerName)
 	if !ok {
 		return fmt.Errorf("provider %q unavailable", providerName)
@@ -1637,6 +2004,41 @@ func (r *providerOrderRouter) SubmitOrder(ctx context.Context, req schema.OrderR
 	return nil
 }
 
+// NativeOrderFlags reports which order flags the provider's venue enforces itself, so the lambda
+// emulates only the others.
+func (r *providerOrderRouter) NativeOrderFlags(providerName string) (postOnly, reduceOnly bool) {
+	if r == nil {
+		return false, false
+	}
+	if support, ok := r.catalog.(core.OrderFlagSupport); ok {
+		return support.NativeOrderFlags(providerName)
+	}
+	return false, false
+}
+
+func (r *providerOrderRouter) CancelOrder(ctx context.Context, providerName, symbol, clientOrderID string) error {
+	if r == nil || r.catalog == nil {
+		return fmt.Errorf("order router not configured")
+	}
+	providerName = strings.TrimSpace(providerName)
+	if canceller, ok := r.catalog.(core.OrderCanceller); ok {
+		// The provider manager puts cancels ahead of queued order placements.
+		return canceller.CancelOrder(ctx, providerName, symbol, clientOrderID) //nolint:wrapcheck // the provider manager names the provider in its errors
+	}
+	inst, ok := r.catalog.Provider(providerName)
+	if !ok || inst == nil {
+		return fmt.Errorf("provider %q unavailable", providerName)
+	}
+	canceller, ok := inst.(provider.ClientOrderCanceller)
+	if !ok {
+		return fmt.Errorf("provider %q does not support single order cancellation", providerName)
+	}
+	if err := canceller.CancelOrder(ctx, symbol, clientOrderID); err != nil {
+		return fmt.Errorf("cancel order on provider %q: %w", providerName, err)
+	}
+	return nil
+}
+
 func closeStrategy(strat core.TradingStrategy) {
 	if strat == nil {
 		return
@@ -1671,7 +2073,7 @@ func (m *Manager) buildStrategy(spec config.LambdaStrategySpec) (core.TradingStr
 		if !strings.EqualFold(module.Name, name) {
 			return nil, fmt.Errorf("strategy %s: revision %s belongs to %s", name, spec.Hash, module.Name)
 		}
-		strategy, buildErr := js.NewStrategy(module, spec.Config, m.logger)
+		strategy, buildErr := m.vmPool.NewStrategy(module, spec.Config, m.logger)
 		if buildErr != nil {
 			return nil, fmt.Errorf("strategy %s: %w", name, buildErr)
 		}
@@ -1695,6 +2097,50 @@ func sanitizeSpec(spec config.LambdaSpec) config.LambdaSpec {
 	return spec
 }
 
+// routeSpec swaps synthetic symbols in the instance scope for their legs on the same provider,
+// since adapters only stream listed instruments. The instance itself keeps the synthetic scope
+// and receives the tickers the synthetic engine publishes.
+func (m *Manager) routeSpec(spec config.LambdaSpec) config.LambdaSpec {
+	if len(m.syntheticLegs) == 0 {
+		return spec
+	}
+	out := cloneSpec(spec)
+	for provider, assignment := range out.ProviderSymbols {
+		synthetics := m.syntheticLegs[strings.TrimSpace(provider)]
+		if len(synthetics) == 0 {
+			continue
+		}
+		symbols := make([]string, 0, len(assignment.Symbols))
+		for _, symbol := range assignment.Symbols {
+			if legs, ok := synthetics[strings.ToUpper(strings.TrimSpace(symbol))]; ok {
+				symbols = append(symbols, legs...)
+				continue
+			}
+			symbols = append(symbols, symbol)
+		}
+		assignment.Symbols = symbols
+		out.ProviderSymbols[provider] = assignment
+	}
+	return out
+}
+
+func syntheticLegsByProvider(cfgs []config.SyntheticInstrumentConfig) map[string]map[string][]string {
+	out := make(map[string]map[string][]string)
+	for _, cfg := range cfgs {
+		legs := make([]string, 0, len(cfg.Legs))
+		for _, leg := range cfg.Legs {
+			if leg.Provider == cfg.Provider {
+				legs = append(legs, leg.Symbol)
+			}
+		}
+		if out[cfg.Provider] == nil {
+			out[cfg.Provider] = make(map[string][]string)
+		}
+		out[cfg.Provider][cfg.Symbol] = legs
+	}
+	return out
+}
+
 func cloneSpec(spec config.LambdaSpec) config.LambdaSpec {
 	clone := spec
 	clone.Strategy.Config = copyMap(spec.Strategy.Config)
@@ -1703,6 +2149,7 @@ func cloneSpec(spec config.LambdaSpec) config.LambdaSpec {
 	clone.Strategy.Hash = spec.Strategy.Hash
 	clone.Providers = append([]string(nil), spec.Providers...)
 	clone.ProviderSymbols = cloneProviderSymbols(spec.ProviderSymbols)
+	clone.Labels = cloneLabels(spec.Labels)
 	return clone
 }
 
@@ -1713,7 +2160,8 @@ func cloneProviderSymbols(src map[string]config.ProviderSymbols) map[string]conf
 	dst := make(map[string]config.ProviderSymbols, len(src))
 	for name, assignment := range src {
 		cloned := config.ProviderSymbols{
-			Symbols: append([]string(nil), assignment.Symbols...),
+			Symbols:    append([]string(nil), assignment.Symbols...),
+			SubAccount: assignment.SubAccount,
 		}
 		dst[name] = cloned
 	}
@@ -1742,13 +2190,13 @@ func cloneSymbolMap(src map[string]config.ProviderSymbols) map[string][]string {
 	return out
 }
 
-func buildProviderSymbols(symbols map[string][]string) map[string]config.ProviderSymbols {
+func buildProviderSymbols(symbols map[string][]string, subAccounts map[string]string) map[string]config.ProviderSymbols {
 	if len(symbols) == 0 {
 		return make(map[string]config.ProviderSymbols)
 	}
 	out := make(map[string]config.ProviderSymbols, len(symbols))
 	for provider, vals := range symbols {
-		out[provider] = config.ProviderSymbols{Symbols: append([]string(nil), vals...)}
+		out[provider] = config.ProviderSymbols{Symbols: append([]string(nil), vals...), SubAccount: subAccounts[provider]}
 	}
 	return out
 }
@@ -1780,10 +2228,13 @@ func (m *Manager) strategySnapshot(id string) (strategystore.Snapshot, bool) {
 		Strategy:        strategystore.Strategy{Identifier: spec.Strategy.Identifier, Selector: spec.Strategy.Selector, Tag: spec.Strategy.Tag, Hash: spec.Strategy.Hash, Config: copyMap(spec.Strategy.Config)},
 		Providers:       append([]string(nil), spec.Providers...),
 		ProviderSymbols: cloneSymbolMap(spec.ProviderSymbols),
+		SubAccounts:     spec.SubAccountMap(),
+		Labels:          cloneLabels(spec.Labels),
+		DryRun:          spec.DryRun,
 		Running:         running,
 		Dynamic:         m.isDynamicInstance(spec.ID),
 		Baseline:        m.isBaselineInstance(spec.ID),
-		Metadata:        map[string]any{},
+		Metadata:        m.freezeMetadata(spec.ID, running),
 		UpdatedAt:       m.clock(),
 	}
 	return snapshot, true
@@ -1826,6 +2277,7 @@ func (m *Manager) restoreStrategySnapshot(ctx context.Context, snapshot strategy
 	}
 	m.setBaselineInstance(snapshot.ID, snapshot.Baseline)
 	m.setDynamicInstance(snapshot.ID, snapshot.Dynamic)
+	m.restoreFreeze(snapshot.ID, snapshot.Metadata)
 	if snapshot.Running {
 		if err := m.Start(ctx, snapshot.ID); err != nil && m.logger != nil {
 			if !errors.Is(err, ErrInstanceAlreadyRunning) {
@@ -1848,7 +2300,9 @@ func specFromSnapshot(snapshot strategystore.Snapshot) config.LambdaSpec {
 		ID:              snapshot.ID,
 		Strategy:        config.LambdaStrategySpec{Identifier: snapshot.Strategy.Identifier, Config: copyMap(snapshot.Strategy.Config), Selector: snapshot.Strategy.Selector, Tag: snapshot.Strategy.Tag, Hash: snapshot.Strategy.Hash},
 		Providers:       append([]string(nil), snapshot.Providers...),
-		ProviderSymbols: buildProviderSymbols(snapshot.ProviderSymbols),
+		ProviderSymbols: buildProviderSymbols(snapshot.ProviderSymbols, snapshot.SubAccounts),
+		Labels:          cloneLabels(snapshot.Labels),
+		DryRun:          snapshot.DryRun,
 	}
 	if len(snapshot.Providers) > 0 && len(spec.ProviderSymbols) == 0 {
 		spec.Providers = append([]string(nil), snapshot.Providers...)
@@ -1964,6 +2418,14 @@ func (m *Manager) StrategyModule(name string) (js.ModuleSummary, error) {
 	return summary, nil
 }
 
+// SearchStrategyModules ranks loaded module revisions against a free-text query.
+func (m *Manager) SearchStrategyModules(opts js.ModuleSearchOptions) []js.ModuleSearchMatch {
+	if m == nil || m.jsLoader == nil {
+		return []js.ModuleSearchMatch{}
+	}
+	return m.jsLoader.Search(opts)
+}
+
 // ResolveStrategySelector resolves a module selector into the corresponding revision.
 func (m *Manager) ResolveStrategySelector(selector string) (js.ModuleResolution, error) {
 	if m == nil || m.jsLoader == nil {
@@ -2019,28 +2481,48 @@ func (m *Manager) StrategySource(name string) ([]byte, error) {
 	return source, nil
 }
 
-// UpsertStrategy writes or replaces a JavaScript strategy module.
-func (m *Manager) UpsertStrategy(source []byte, opts js.ModuleWriteOptions) (js.ModuleResolution, error) {
+// UpsertStrategy writes or replaces a JavaScript strategy module and records the upload
+// in the strategy history.
+func (m *Manager) UpsertStrategy(source []byte, opts js.ModuleWriteOptions, change StrategyChange) (js.ModuleResolution, error) {
 	if m == nil || m.jsLoader == nil {
-		return js.ModuleResolution{Name: "", Hash: "", Tag: "", Alias: "", Module: nil}, fmt.Errorf("strategy loader unavailable")
+		return js.ModuleResolution{Name: "", Hash: "", Tag: "", Alias: "", Module: nil, Warnings: nil, Tests: nil}, fmt.Errorf("strategy loader unavailable")
 	}
 	resolution, err := m.jsLoader.Store(source, opts)
 	if err == nil {
+		metadata := map[string]any{"promoteLatest": opts.PromoteLatest}
+		if tests := resolution.Tests; tests != nil {
+			metadata["testsPassed"] = tests.Passed
+			if !tests.Passed && m.logger != nil {
+				m.logger.Printf("strategy %s@%s failed %d of %d inline tests; latest not moved", resolution.Name, resolution.Hash, tests.Failed, tests.Total)
+			}
+		}
+		m.recordStrategyHistory(strategystore.HistoryEntry{
+			ID:           0,
+			Strategy:     resolution.Name,
+			Action:       strategystore.HistoryActionUpload,
+			Hash:         resolution.Hash,
+			Tag:          resolution.Tag,
+			PreviousHash: "",
+			Actor:        "",
+			Reason:       "",
+			Metadata:     metadata,
+			RecordedAt:   time.Time{},
+		}, change)
 		return resolution, nil
 	}
 	m.recordStrategyValidationFailure(err)
 	if !errors.Is(err, js.ErrRegistryUnavailable) {
-		return js.ModuleResolution{Name: "", Hash: "", Tag: "", Alias: "", Module: nil}, fmt.Errorf("strategy upsert: %w", err)
+		return js.ModuleResolution{Name: "", Hash: "", Tag: "", Alias: "", Module: nil, Warnings: nil, Tests: nil}, fmt.Errorf("strategy upsert: %w", err)
 	}
 	if err := m.jsLoader.Write(source); err != nil {
 		m.recordStrategyValidationFailure(err)
-		return js.ModuleResolution{Name: "", Hash: "", Tag: "", Alias: "", Module: nil}, fmt.Errorf("strategy upsert: %w", err)
+		return js.ModuleResolution{Name: "", Hash: "", Tag: "", Alias: "", Module: nil, Warnings: nil, Tests: nil}, fmt.Errorf("strategy upsert: %w", err)
 	}
-	return js.ModuleResolution{Name: "", Hash: "", Tag: "", Alias: "", Module: nil}, nil
+	return js.ModuleResolution{Name: "", Hash: "", Tag: "", Alias: "", Module: nil, Warnings: nil, Tests: nil}, nil
 }
 
 // AssignStrategyTag re-points the supplied tag alias to the provided revision hash.
-func (m *Manager) AssignStrategyTag(ctx context.Context, name, tag, hash string, refresh bool) (string, error) {
+func (m *Manager) AssignStrategyTag(ctx context.Context, name, tag, hash string, refresh bool, change StrategyChange) (string, error) {
 	if m == nil || m.jsLoader == nil {
 		return "", fmt.Errorf("strategy loader unavailable")
 	}
@@ -2052,8 +2534,20 @@ func (m *Manager) AssignStrategyTag(ctx context.Context, name, tag, hash string,
 		return "", fmt.Errorf("assign tag %s:%s: %w", name, tag, err)
 	}
 	if m.logger != nil {
-		m.logger.Printf("strategy tag %s:%s moved from %s to %s", name, tag, previous, hash)
-	}
+		m.logger.Printf("strategy tag %s:%s moved from %s to %s%s", name, tag, previous, hash, change.logSuffix())
+	}
+	m.recordStrategyHistory(strategystore.HistoryEntry{
+		ID:           0,
+		Strategy:     name,
+		Action:       strategystore.HistoryActionTagAssigned,
+		Hash:         hash,
+		Tag:          tag,
+		PreviousHash: previous,
+		Actor:        "",
+		Reason:       "",
+		Metadata:     map[string]any{"refresh": refresh},
+		RecordedAt:   time.Time{},
+	}, change)
 	if refresh && !strings.EqualFold(previous, hash) {
 		_, refreshErr := m.RefreshJavaScriptStrategiesWithTargets(ctx, RefreshTargets{Strategies: []string{name}, Hashes: nil})
 		if refreshErr != nil {
@@ -2072,7 +2566,7 @@ func (m *Manager) AssignStrategyTag(ctx context.Context, name, tag, hash string,
 }
 
 // DeleteStrategyTag removes a tag alias while honoring guard rails.
-func (m *Manager) DeleteStrategyTag(name, tag string, allowOrphan bool) (string, error) {
+func (m *Manager) DeleteStrategyTag(name, tag string, allowOrphan bool, change StrategyChange) (string, error) {
 	if m == nil || m.jsLoader == nil {
 		return "", fmt.Errorf("strategy loader unavailable")
 	}
@@ -2082,8 +2576,20 @@ func (m *Manager) DeleteStrategyTag(name, tag string, allowOrphan bool) (string,
 		return "", fmt.Errorf("delete tag %s:%s: %w", name, tag, err)
 	}
 	if m.logger != nil {
-		m.logger.Printf("strategy tag %s:%s removed (hash %s)", name, tag, hash)
-	}
+		m.logger.Printf("strategy tag %s:%s removed (hash %s)%s", name, tag, hash, change.logSuffix())
+	}
+	m.recordStrategyHistory(strategystore.HistoryEntry{
+		ID:           0,
+		Strategy:     name,
+		Action:       strategystore.HistoryActionTagDeleted,
+		Hash:         "",
+		Tag:          tag,
+		PreviousHash: hash,
+		Actor:        "",
+		Reason:       "",
+		Metadata:     map[string]any{"allowOrphan": allowOrphan},
+		RecordedAt:   time.Time{},
+	}, change)
 	if m.tagDeleteCounter != nil {
 		env := telemetry.Environment()
 		m.tagDeleteCounter.Add(context.Background(), 1, metric.WithAttributes(
@@ -2097,7 +2603,7 @@ func (m *Manager) DeleteStrategyTag(name, tag string, allowOrphan bool) (string,
 }
 
 // RemoveStrategy deletes the JavaScript strategy file by name.
-func (m *Manager) RemoveStrategy(name string) error {
+func (m *Manager) RemoveStrategy(name string, change StrategyChange) error {
 	if m == nil || m.jsLoader == nil {
 		return js.ErrModuleNotFound
 	}
@@ -2109,12 +2615,14 @@ func (m *Manager) RemoveStrategy(name string) error {
 
 	var (
 		inUseErr error
+		hash     string
 	)
 	if strings.ContainsAny(selector, "@:") {
 		resolution, err := m.jsLoader.ResolveReference(selector)
 		if err != nil {
 			return fmt.Errorf("strategy remove %q: %w", selector, err)
 		}
+		hash = resolution.Hash
 		if resolution.Hash != "" && m.hashInUse(resolution.Hash) {
 			inUseErr = fmt.Errorf("strategy revision %s is in use", resolution.Hash)
 		}
@@ -2128,6 +2636,18 @@ func (m *Manager) RemoveStrategy(name string) error {
 	if err := m.jsLoader.Delete(selector); err != nil {
 		return fmt.Errorf("strategy remove %q: %w", name, err)
 	}
+	m.recordStrategyHistory(strategystore.HistoryEntry{
+		ID:           0,
+		Strategy:     strategyNameFromSelector(selector),
+		Action:       strategystore.HistoryActionDelete,
+		Hash:         "",
+		Tag:          "",
+		PreviousHash: hash,
+		Actor:        "",
+		Reason:       "",
+		Metadata:     map[string]any{"selector": selector},
+		RecordedAt:   time.Time{},
+	}, change)
 	return nil
 }
 
@@ -2218,6 +2738,9 @@ func equalProviderSymbols(a, b map[string]config.ProviderSymbols) bool {
 		if !ok {
 			return false
 		}
+		if assignmentA.SubAccount != assignmentB.SubAccount {
+			return false
+		}
 		if len(assignmentA.Symbols) != len(assignmentB.Symbols) {
 			return false
 		}
@@ -2276,6 +2799,7 @@ func buildRouteDeclarations(strategy core.TradingStrategy, spec config.LambdaSpe
 					routes = append(routes, dispatcher.RouteDeclaration{
 						Type:    routeName,
 						Filters: copyMap(routeFilters),
+						Book:    dispatcher.BookOptions{Depth: 0, Interval: 0},
 					})
 				}
 				continue
@@ -2295,18 +2819,155 @@ func buildRouteDeclarations(strategy core.TradingStrategy, spec config.LambdaSpe
 				key := providerInstrumentField(provider)
 				routeFilters[key] = symbols
 			}
-			routes = append(routes, dispatcher.RouteDeclaration{
+			declaration := dispatcher.RouteDeclaration{
 				Type:    routeName,
 				Filters: copyMap(routeFilters),
-			})
+				Book:    dispatcher.BookOptions{Depth: 0, Interval: 0},
+			}
+			if routeName == schema.RouteTypeOrderbookSnapshot {
+				declaration.Book = bookOptionsFromStrategy(spec.Strategy.Config)
+			}
+			routes = append(routes, declaration)
 		}
 	}
 	return routes
 }
 
+// recordSeedLocked stores the seed drawn for a JavaScript strategy in the instance config so
+// the instance reports it and later restarts and replays reuse it.
+func (m *Manager) recordSeedLocked(id string, strategy core.TradingStrategy) {
+	jsStrategy, ok := strategy.(*js.Strategy)
+	if !ok {
+		return
+	}
+	stored, ok := m.specs[id]
+	if !ok {
+		return
+	}
+	if _, configured := js.SeedFromConfig(stored.Strategy.Config); configured {
+		return
+	}
+	cfg := copyMap(stored.Strategy.Config)
+	cfg[js.SeedConfigKey] = jsStrategy.Seed()
+	stored.Strategy.Config = cfg
+	m.specs[id] = stored
+}
+
 func bindStrategy(strategy core.TradingStrategy, base *core.BaseLambda, _ *log.Logger) {
 	switch s := strategy.(type) {
 	case *js.Strategy:
 		s.Attach(base)
 	}
 }
+
+// deliveryConfig resolves the delivery settings of an instance and attaches the shared handler
+// scheduler.
+func (m *Manager) deliveryConfig(cfg map[string]any) core.DeliveryConfig {
+	delivery := deliveryConfigFromStrategy(cfg)
+	delivery.Scheduler = m.handlerScheduler
+	return delivery
+}
+
+// instancePriority reads the priority key of a strategy config.
+func instancePriority(cfg map[string]any) core.Priority {
+	raw, _ := cfg["priority"].(string)
+	return core.ParsePriority(raw)
+}
+
+func newHandlerScheduler(cfg config.StrategySchedulingConfig) *core.HandlerScheduler {
+	if !cfg.Enabled {
+		return nil
+	}
+	return core.NewHandlerScheduler(core.HandlerSchedulerConfig{
+		Slots:        cfg.Slots,
+		HighWeight:   cfg.HighWeight,
+		NormalWeight: cfg.NormalWeight,
+		LowWeight:    cfg.LowWeight,
+	})
+}
+
+// deliveryConfigFromStrategy extracts handler delivery settings from the strategy config.
+// Recognised keys are delivery_mode ("serial" or "worker_pool"), handler_workers,
+// max_in_flight_events and priority ("high", "normal" or "low"); missing or malformed values
+// fall back to the core defaults.
+func deliveryConfigFromStrategy(cfg map[string]any) core.DeliveryConfig {
+	var delivery core.DeliveryConfig
+	delivery.Priority = instancePriority(cfg)
+	if raw, ok := cfg["delivery_mode"].(string); ok {
+		delivery.Mode = core.DeliveryMode(strings.TrimSpace(raw))
+	}
+	if workers, ok := intFromConfig(cfg["handler_workers"]); ok {
+		delivery.Workers = workers
+	}
+	if inflight, ok := intFromConfig(cfg["max_in_flight_events"]); ok {
+		delivery.MaxInFlight = inflight
+	}
+	return delivery
+}
+
+// historyConfigFromStrategy extracts the market history retention from the strategy config.
+// Recognised keys are market_history_trades and market_history_klines; 0 or missing selects the
+// core defaults and a negative value disables the window.
+func historyConfigFromStrategy(cfg map[string]any) core.HistoryConfig {
+	var history core.HistoryConfig
+	if trades, ok := intFromConfig(cfg["market_history_trades"]); ok {
+		history.Trades = trades
+	}
+	if klines, ok := intFromConfig(cfg["market_history_klines"]); ok {
+		history.Klines = klines
+	}
+	return history
+}
+
+// orderHistoryConfigFromStrategy extracts order_history_size, the number of recent orders the host
+// keeps for the strategy; 0 or missing selects the core default and a negative value disables it.
+func orderHistoryConfigFromStrategy(cfg map[string]any) core.OrderHistoryConfig {
+	var orders core.OrderHistoryConfig
+	if size, ok := intFromConfig(cfg["order_history_size"]); ok {
+		orders.Size = size
+	}
+	return orders
+}
+
+// bookDeltaConfigFromStrategy extracts book_delta_max_rate, the book deltas delivered per
+// instrument and second; 0 or missing selects the core default and a negative value removes the bound.
+func bookDeltaConfigFromStrategy(cfg map[string]any) core.BookDeltaConfig {
+	var deltas core.BookDeltaConfig
+	if rate, ok := intFromConfig(cfg["book_delta_max_rate"]); ok {
+		deltas.MaxRate = rate
+	}
+	return deltas
+}
+
+// bookOptionsFromStrategy extracts the order book route options from the strategy config:
+// book_depth caps the levels per side and book_snapshot_interval (a duration string or a number of
+// seconds) spaces snapshots of an instrument. Missing or malformed values keep the adapter defaults.
+func bookOptionsFromStrategy(cfg map[string]any) dispatcher.BookOptions {
+	var opts dispatcher.BookOptions
+	if depth, ok := intFromConfig(cfg["book_depth"]); ok && depth > 0 {
+		opts.Depth = depth
+	}
+	if interval, ok := durationFromConfig(cfg["book_snapshot_interval"]); ok {
+		opts.Interval = interval
+	}
+	return opts
+}
+
+func intFromConfig(raw any) (int, bool) {
+	switch v := raw.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	case string:
+		parsed, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, false
+		}
+		return parsed, true
+	default:
+		return 0, false
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/coachpo/meltica/internal/app/lambda/core"
 	"github.com/coachpo/meltica/internal/app/lambda/js"
 	"github.com/coachpo/meltica/internal/domain/schema"
 	"github.com/coachpo/meltica/internal/domain/strategystore"
@@ -106,7 +107,7 @@ func TestManagerRefreshJavaScriptStrategies(t *testing.T) {
 	}
 
 	updatedSource := strings.ReplaceAll(initialSource, "Alpha v1", "Alpha v2")
-	if _, err := mgr.UpsertStrategy([]byte(updatedSource), js.ModuleWriteOptions{Filename: "alpha.js", PromoteLatest: true}); err != nil {
+	if _, err := mgr.UpsertStrategy([]byte(updatedSource), js.ModuleWriteOptions{Filename: "alpha.js", PromoteLatest: true}, StrategyChange{}); err != nil {
 		t.Fatalf("UpsertStrategy: %v", err)
 	}
 
@@ -209,7 +210,7 @@ func TestManagerAssignStrategyTag(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ResolveStrategySelector: %v", err)
 	}
-	if _, err := mgr.AssignStrategyTag(context.Background(), "tagdemo", "prod", res.Hash, false); err != nil {
+	if _, err := mgr.AssignStrategyTag(context.Background(), "tagdemo", "prod", res.Hash, false, StrategyChange{}); err != nil {
 		t.Fatalf("AssignStrategyTag: %v", err)
 	}
 	if _, err := mgr.ResolveStrategySelector("tagdemo:prod"); err != nil {
@@ -241,10 +242,10 @@ func TestManagerDeleteStrategyTagForce(t *testing.T) {
 	if err := mgr.RefreshJavaScriptStrategies(context.Background()); err != nil {
 		t.Fatalf("Refresh strategies: %v", err)
 	}
-	if _, err := mgr.DeleteStrategyTag("tagdemo", "v1.0.0", false); err == nil {
+	if _, err := mgr.DeleteStrategyTag("tagdemo", "v1.0.0", false, StrategyChange{}); err == nil {
 		t.Fatalf("expected guard rail preventing orphan deletion")
 	}
-	if _, err := mgr.DeleteStrategyTag("tagdemo", "v1.0.0", true); err != nil {
+	if _, err := mgr.DeleteStrategyTag("tagdemo", "v1.0.0", true, StrategyChange{}); err != nil {
 		t.Fatalf("DeleteStrategyTag force: %v", err)
 	}
 	if _, err := mgr.ResolveStrategySelector("tagdemo:v1.0.0"); err == nil {
@@ -490,10 +491,66 @@ func TestRemoveStrategyGuardedWhenHashInUse(t *testing.T) {
 	if err != nil {
 		t.Fatalf("specForID: %v", err)
 	}
-	if err := mgr.RemoveStrategy("logging@" + stored.Strategy.Hash); err == nil {
+	if err := mgr.RemoveStrategy("logging@"+stored.Strategy.Hash, StrategyChange{}); err == nil {
 		t.Fatalf("expected removal to fail for hash in use")
 	}
-	if err := mgr.RemoveStrategy("logging"); err == nil {
+	if err := mgr.RemoveStrategy("logging", StrategyChange{}); err == nil {
 		t.Fatalf("expected removal to fail for strategy in use")
 	}
 }
+
+func TestRouteSpecExpandsSyntheticSymbols(t *testing.T) {
+	mgr := newTestManager(t)
+	mgr.syntheticLegs = syntheticLegsByProvider([]config.SyntheticInstrumentConfig{{
+		Symbol:   "BTC-ETH-SPREAD",
+		Provider: "binance",
+		Kind:     config.SyntheticKindSpread,
+		Legs: []config.SyntheticLeg{
+			{Provider: "binance", Symbol: "BTC-USDT"},
+			{Provider: "okx", Symbol: "ETH-USDT"},
+		},
+	}})
+	spec := config.LambdaSpec{
+		ID: "pairs",
+		ProviderSymbols: map[string]config.ProviderSymbols{
+			"binance": {Symbols: []string{"btc-eth-spread", "SOL-USDT"}},
+			"okx":     {Symbols: []string{"BTC-ETH-SPREAD"}},
+		},
+	}
+
+	routed := mgr.routeSpec(spec)
+	binance := routed.ProviderSymbols["binance"].Symbols
+	if strings.Join(binance, ",") != "BTC-USDT,SOL-USDT" {
+		t.Fatalf("binance route symbols = %v", binance)
+	}
+	if okx := routed.ProviderSymbols["okx"].Symbols; strings.Join(okx, ",") != "BTC-ETH-SPREAD" {
+		t.Fatalf("synthetic published under binance must not expand on okx, got %v", okx)
+	}
+	if spec.ProviderSymbols["binance"].Symbols[0] != "btc-eth-spread" {
+		t.Fatalf("routeSpec must not modify the instance scope")
+	}
+}
+
+func TestManagerDeliveryConfigAppliesPriorityAndScheduler(t *testing.T) {
+	mgr := newTestManager(t)
+	if delivery := mgr.deliveryConfig(map[string]any{}); delivery.Priority != core.PriorityNormal || delivery.Scheduler != nil {
+		t.Fatalf("expected normal priority without scheduler, got %+v", delivery)
+	}
+
+	mgr.handlerScheduler = newHandlerScheduler(config.StrategySchedulingConfig{Enabled: true, Slots: 2})
+	delivery := mgr.deliveryConfig(map[string]any{"priority": "High"})
+	if delivery.Priority != core.PriorityHigh || delivery.Scheduler == nil {
+		t.Fatalf("expected high priority with scheduler, got %+v", delivery)
+	}
+
+	spec := baseLambdaSpec()
+	spec.Strategy.Config["priority"] = "low"
+	if err := mgr.ensureSpec(&spec, false); err != nil {
+		t.Fatalf("ensureSpec: %v", err)
+	}
+	for _, summary := range mgr.Instances() {
+		if summary.ID == spec.ID && summary.Priority != core.PriorityLow {
+			t.Fatalf("summary priority = %q, want low", summary.Priority)
+		}
+	}
+}
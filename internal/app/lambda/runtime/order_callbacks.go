@@ -0,0 +1,65 @@
+package runtime
+
+import (
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// orderCallbackConfigFromStrategy extracts the order callback tracking settings from the strategy
+// config. Recognised keys are order_callback_deadline and order_callback_backoff (a duration string
+// such as "2s" or a number of seconds), order_callback_retries and order_callback_states (a list or
+// comma separated string of report states); tracking stays off without a deadline.
+func orderCallbackConfigFromStrategy(cfg map[string]any) core.OrderCallbackConfig {
+	var callbacks core.OrderCallbackConfig
+	if deadline, ok := durationFromConfig(cfg["order_callback_deadline"]); ok {
+		callbacks.Deadline = deadline
+	}
+	if retries, ok := intFromConfig(cfg["order_callback_retries"]); ok {
+		callbacks.Retries = retries
+		if retries == 0 {
+			// Zero would select the default; an explicit zero means no retries.
+			callbacks.Retries = -1
+		}
+	}
+	if backoff, ok := durationFromConfig(cfg["order_callback_backoff"]); ok {
+		callbacks.Backoff = backoff
+	}
+	callbacks.States = execReportStatesFromConfig(cfg["order_callback_states"])
+	return callbacks
+}
+
+func execReportStatesFromConfig(raw any) []schema.ExecReportState {
+	var values []string
+	switch typed := raw.(type) {
+	case string:
+		values = strings.Split(typed, ",")
+	case []string:
+		values = typed
+	case []any:
+		for _, item := range typed {
+			if value, ok := item.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	states := make([]schema.ExecReportState, 0, len(values))
+	for _, value := range values {
+		if value = strings.ToUpper(strings.TrimSpace(value)); value != "" {
+			states = append(states, schema.ExecReportState(value))
+		}
+	}
+	return states
+}
+
+// InstanceOrderCallbacks reports how a running instance acknowledged its execution report
+// callbacks.
+func (m *Manager) InstanceOrderCallbacks(id string) (core.OrderCallbackStats, error) {
+	inst, err := m.runningInstance(id)
+	if err != nil {
+		var empty core.OrderCallbackStats
+		return empty, err
+	}
+	return inst.base.OrderCallbacks(), nil
+}
@@ -0,0 +1,43 @@
+package runtime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestOrderCallbackConfigFromStrategy(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  map[string]any
+		want core.OrderCallbackConfig
+	}{
+		{name: "disabled", cfg: nil, want: core.OrderCallbackConfig{States: []schema.ExecReportState{}}},
+		{name: "explicit", cfg: map[string]any{
+			"order_callback_deadline": "2s",
+			"order_callback_retries":  float64(3),
+			"order_callback_backoff":  "250ms",
+			"order_callback_states":   []any{"filled", " rejected "},
+		}, want: core.OrderCallbackConfig{
+			Deadline: 2 * time.Second,
+			Retries:  3,
+			Backoff:  250 * time.Millisecond,
+			States:   []schema.ExecReportState{schema.ExecReportStateFILLED, schema.ExecReportStateREJECTED},
+		}},
+		{name: "no retries", cfg: map[string]any{"order_callback_deadline": 1, "order_callback_retries": 0, "order_callback_states": "FILLED,CANCELLED"}, want: core.OrderCallbackConfig{
+			Deadline: time.Second,
+			Retries:  -1,
+			States:   []schema.ExecReportState{schema.ExecReportStateFILLED, schema.ExecReportStateCANCELLED},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := orderCallbackConfigFromStrategy(tc.cfg); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("orderCallbackConfigFromStrategy(%v) = %+v, want %+v", tc.cfg, got, tc.want)
+			}
+		})
+	}
+}
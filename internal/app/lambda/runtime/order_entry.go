@@ -0,0 +1,235 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+)
+
+// Outcomes of an external order.
+const (
+	// ExternalOrderSubmitted means the order passed the risk checks and was sent to the provider.
+	ExternalOrderSubmitted = "submitted"
+	// ExternalOrderQueued means the risk throttle was exhausted and the order waits in the
+	// instance's throttle queue.
+	ExternalOrderQueued = "queued"
+	// ExternalOrderDryRun means the instance does not trade live, so the order was not sent.
+	ExternalOrderDryRun = "dry_run"
+)
+
+// orderEntrySourceKey is the order metadata key naming the external system that entered an order.
+const orderEntrySourceKey = "orderEntry"
+
+// ExternalOrderRequest is an order entered by an external system on behalf of an instance.
+// Provider and Symbol default to the instance scope.
+type ExternalOrderRequest struct {
+	Provider string `json:"provider,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	// Side is buy or sell.
+	Side string `json:"side"`
+	// OrderType is limit or market, defaulting to limit when a price is supplied.
+	OrderType string  `json:"orderType,omitempty"`
+	Quantity  string  `json:"quantity"`
+	Price     *string `json:"price,omitempty"`
+	TIF       string  `json:"tif,omitempty"`
+	// PostOnly and ReduceOnly are emulated by the gateway on venues that lack them.
+	PostOnly   bool              `json:"postOnly,omitempty"`
+	ReduceOnly bool              `json:"reduceOnly,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// ExternalOrderResult reports the client order ID assigned to an external order and its outcome.
+type ExternalOrderResult struct {
+	ClientOrderID string `json:"clientOrderId"`
+	Status        string `json:"status"`
+}
+
+// ExternalExecReport is an execution report of an instance order, as streamed to order entry
+// sessions.
+type ExternalExecReport struct {
+	Instance string                   `json:"instance"`
+	Provider string                   `json:"provider"`
+	Symbol   string                   `json:"symbol"`
+	Report   schema.ExecReportPayload `json:"report"`
+}
+
+type execReportTap struct {
+	reports chan ExternalExecReport
+}
+
+// SubmitExternalOrder places an order entered by the external system source through the instance,
+// so it is risk checked, throttled, persisted and attributed like the instance's own orders. The
+// instance must be running.
+func (m *Manager) SubmitExternalOrder(ctx context.Context, instanceID, source string, req ExternalOrderRequest) (ExternalOrderResult, error) {
+	var empty ExternalOrderResult
+	instanceID = strings.TrimSpace(instanceID)
+	m.mu.RLock()
+	spec, ok := m.specs[instanceID]
+	inst, running := m.instances[instanceID]
+	m.mu.RUnlock()
+	if !ok {
+		return empty, ErrInstanceNotFound
+	}
+	if !running || inst.base == nil {
+		return empty, ErrInstanceNotRunning
+	}
+	order, err := externalOrder(spec.Providers, req)
+	if err != nil {
+		return empty, err
+	}
+	if source = strings.TrimSpace(source); source != "" {
+		metadata := make(map[string]string, len(order.Labels.Metadata)+1)
+		for key, value := range order.Labels.Metadata {
+			metadata[key] = value
+		}
+		metadata[orderEntrySourceKey] = source
+		order.Labels.Metadata = metadata
+	}
+
+	clientOrderID, submitted, err := inst.base.SubmitExternalOrder(ctx, order)
+	result := ExternalOrderResult{ClientOrderID: clientOrderID, Status: ExternalOrderSubmitted}
+	switch {
+	case errors.Is(err, core.ErrOrderQueued):
+		result.Status = ExternalOrderQueued
+	case err != nil:
+		m.recordStrategyLog(spec, strategylogstore.SourceRuntime, strategylogstore.LevelWarn,
+			fmt.Sprintf("external order from %s rejected: %v", source, err))
+		return empty, fmt.Errorf("external order: %w", err)
+	case !submitted:
+		result.Status = ExternalOrderDryRun
+	}
+	m.recordStrategyLog(spec, strategylogstore.SourceRuntime, strategylogstore.LevelInfo,
+		fmt.Sprintf("external order %s from %s %s", clientOrderID, source, result.Status))
+	return result, nil
+}
+
+// SubscribeExecReports streams the execution reports of the instance's orders, external and
+// strategy alike, until cancel is called. Rather than holding up the instance or silently losing a
+// report, a subscriber that falls a full buffer behind is cut off: its channel is closed, so it
+// can reconnect and reconcile its orders.
+func (m *Manager) SubscribeExecReports(instanceID string, buffer int) (<-chan ExternalExecReport, func(), error) {
+	instanceID = strings.TrimSpace(instanceID)
+	m.mu.RLock()
+	_, ok := m.specs[instanceID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, nil, ErrInstanceNotFound
+	}
+	if buffer <= 0 {
+		buffer = 1
+	}
+	tap := &execReportTap{reports: make(chan ExternalExecReport, buffer)}
+	m.execTapsMu.Lock()
+	taps := m.execTaps[instanceID]
+	if taps == nil {
+		taps = make(map[*execReportTap]struct{})
+		m.execTaps[instanceID] = taps
+	}
+	taps[tap] = struct{}{}
+	m.execTapsMu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			m.execTapsMu.Lock()
+			delete(m.execTaps[instanceID], tap)
+			if len(m.execTaps[instanceID]) == 0 {
+				delete(m.execTaps, instanceID)
+			}
+			m.execTapsMu.Unlock()
+		})
+	}
+	return tap.reports, cancel, nil
+}
+
+// attachExecReportObserver forwards the execution reports of a starting instance to its
+// subscribers.
+func (m *Manager) attachExecReportObserver(id string, base *core.BaseLambda) {
+	base.SetExecReportObserver(func(report core.ExecReport) {
+		m.publishExecReport(id, report)
+	})
+}
+
+func (m *Manager) publishExecReport(id string, report core.ExecReport) {
+	m.execTapsMu.Lock()
+	defer m.execTapsMu.Unlock()
+	taps := m.execTaps[id]
+	if len(taps) == 0 {
+		return
+	}
+	out := ExternalExecReport{Instance: id, Provider: report.Provider, Symbol: report.Symbol, Report: report.Payload}
+	for tap := range taps {
+		select {
+		case tap.reports <- out:
+		default:
+			delete(taps, tap)
+			close(tap.reports)
+			m.logger.Printf("strategy instance %s: execution report subscriber fell %d reports behind, disconnecting it", id, cap(tap.reports))
+		}
+	}
+	if len(taps) == 0 {
+		delete(m.execTaps, id)
+	}
+}
+
+// externalOrder validates req and applies the instance defaults.
+func externalOrder(providers []string, req ExternalOrderRequest) (core.ExternalOrder, error) {
+	var empty core.ExternalOrder
+	order := core.ExternalOrder{
+		Provider:   strings.TrimSpace(req.Provider),
+		Symbol:     strings.ToUpper(strings.TrimSpace(req.Symbol)),
+		Side:       "",
+		OrderType:  "",
+		Quantity:   strings.TrimSpace(req.Quantity),
+		Price:      req.Price,
+		TIF:        schema.TimeInForce(strings.TrimSpace(req.TIF)),
+		PostOnly:   req.PostOnly,
+		ReduceOnly: req.ReduceOnly,
+		Labels:     core.OrderLabels{Tags: req.Tags, Metadata: req.Metadata},
+	}
+	if order.Provider == "" {
+		if len(providers) != 1 {
+			return empty, fmt.Errorf("provider required for instances trading on several providers")
+		}
+		order.Provider = providers[0]
+	}
+	if order.Price != nil {
+		trimmed := strings.TrimSpace(*order.Price)
+		order.Price = &trimmed
+		if trimmed == "" {
+			order.Price = nil
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(req.Side)) {
+	case "buy":
+		order.Side = schema.TradeSideBuy
+	case "sell":
+		order.Side = schema.TradeSideSell
+	default:
+		return empty, fmt.Errorf("side must be buy or sell")
+	}
+	switch strings.ToLower(strings.TrimSpace(req.OrderType)) {
+	case "":
+		order.OrderType = schema.OrderTypeMarket
+		if order.Price != nil {
+			order.OrderType = schema.OrderTypeLimit
+		}
+	case "limit":
+		order.OrderType = schema.OrderTypeLimit
+	case "market":
+		order.OrderType = schema.OrderTypeMarket
+	default:
+		return empty, fmt.Errorf("orderType must be limit or market")
+	}
+	if order.Quantity == "" {
+		return empty, fmt.Errorf("quantity required")
+	}
+	return order, nil
+}
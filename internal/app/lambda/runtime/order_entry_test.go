@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestExternalOrderDefaults(t *testing.T) {
+	price := " 101 "
+	order, err := externalOrder([]string{"okx-spot"}, ExternalOrderRequest{Side: "BUY", Quantity: "1", Price: &price, Symbol: "btc-usdt"})
+	if err != nil {
+		t.Fatalf("externalOrder: %v", err)
+	}
+	if order.Provider != "okx-spot" || order.Symbol != "BTC-USDT" || order.OrderType != schema.OrderTypeLimit || *order.Price != "101" {
+		t.Fatalf("unexpected order %+v", order)
+	}
+	if _, err := externalOrder([]string{"okx-spot", "binance"}, ExternalOrderRequest{Side: "buy", Quantity: "1"}); err == nil {
+		t.Fatal("expected provider required for multi-provider instances")
+	}
+	if _, err := externalOrder([]string{"okx-spot"}, ExternalOrderRequest{Side: "hold", Quantity: "1"}); err == nil {
+		t.Fatal("expected unknown side rejected")
+	}
+	if _, err := externalOrder([]string{"okx-spot"}, ExternalOrderRequest{Side: "sell", OrderType: "stop", Quantity: "1"}); err == nil {
+		t.Fatal("expected unknown order type rejected")
+	}
+}
+
+func TestSubscribeExecReports(t *testing.T) {
+	mgr := newTestManager(t)
+	spec := baseLambdaSpec()
+	if _, err := mgr.Create(spec); err != nil {
+		t.Fatalf("Create lambda: %v", err)
+	}
+	if _, _, err := mgr.SubscribeExecReports("missing", 1); !errors.Is(err, ErrInstanceNotFound) {
+		t.Fatalf("expected unknown instance rejected, got %v", err)
+	}
+	if _, err := mgr.SubmitExternalOrder(context.Background(), spec.ID, "desk", ExternalOrderRequest{Side: "buy", Quantity: "1"}); !errors.Is(err, ErrInstanceNotRunning) {
+		t.Fatalf("expected stopped instance rejected, got %v", err)
+	}
+
+	reports, cancel, err := mgr.SubscribeExecReports(spec.ID, 1)
+	if err != nil {
+		t.Fatalf("SubscribeExecReports: %v", err)
+	}
+	report := core.ExecReport{Provider: "okx-spot", Symbol: "BTC-USDT", Payload: schema.ExecReportPayload{ClientOrderID: "alpha-1-1", State: schema.ExecReportStateACK}}
+	mgr.publishExecReport(spec.ID, report)
+	mgr.publishExecReport("other", report)
+	got := <-reports
+	if got.Instance != spec.ID || got.Symbol != "BTC-USDT" || got.Report.ClientOrderID != "alpha-1-1" {
+		t.Fatalf("unexpected report %+v", got)
+	}
+	cancel()
+	cancel()
+	mgr.publishExecReport(spec.ID, report)
+	if len(reports) != 0 || len(mgr.execTaps) != 0 {
+		t.Fatal("expected cancelled subscription to stop receiving reports")
+	}
+
+	// A subscriber a full buffer behind is cut off instead of missing reports.
+	reports, cancel, err = mgr.SubscribeExecReports(spec.ID, 1)
+	if err != nil {
+		t.Fatalf("SubscribeExecReports: %v", err)
+	}
+	defer cancel()
+	mgr.publishExecReport(spec.ID, report)
+	mgr.publishExecReport(spec.ID, report)
+	if _, ok := <-reports; !ok {
+		t.Fatal("expected the buffered report delivered before the cut-off")
+	}
+	if _, ok := <-reports; ok {
+		t.Fatal("expected an overflowing subscription closed")
+	}
+	if len(mgr.execTaps) != 0 {
+		t.Fatal("expected an overflowing subscription removed")
+	}
+	mgr.publishExecReport(spec.ID, report)
+}
@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// ErrOrderFilter indicates that an order cannot satisfy the instrument's tick size, lot size or notional filters.
+var ErrOrderFilter = errors.New("order violates instrument filters")
+
+// NormalizeOrder aligns the order price and quantity with the instrument filters published by the provider.
+// Unknown providers or instruments are left untouched so the venue remains the final arbiter.
+func (r *providerOrderRouter) NormalizeOrder(_ context.Context, req *schema.OrderRequest) error {
+	if r == nil || r.catalog == nil || req == nil || r.normalization == config.OrderNormalizationOff {
+		return nil
+	}
+	inst, ok := r.catalog.Provider(strings.TrimSpace(req.Provider))
+	if !ok {
+		return nil
+	}
+	symbol := strings.ToUpper(strings.TrimSpace(req.Symbol))
+	for _, instrument := range inst.Instruments() {
+		if strings.EqualFold(instrument.Symbol, symbol) {
+			return normalizeOrder(req, instrument, r.normalization)
+		}
+	}
+	return nil
+}
+
+// normalizeOrder rounds or validates req against instrument. Prices round toward the passive side
+// (down for buys, up for sells) and quantities always round down so exposure never grows.
+func normalizeOrder(req *schema.OrderRequest, instrument schema.Instrument, mode config.OrderNormalizationMode) error {
+	round := mode != config.OrderNormalizationReject
+
+	quantity, err := decimal.NewFromString(strings.TrimSpace(req.Quantity))
+	if err != nil {
+		return fmt.Errorf("%w: invalid quantity %q", ErrOrderFilter, req.Quantity)
+	}
+	if step := filterIncrement(instrument.QuantityIncrement, instrument.QuantityPrecision); step.IsPositive() {
+		aligned := quantity.Div(step).Floor().Mul(step)
+		if !aligned.Equal(quantity) {
+			if !round {
+				return fmt.Errorf("%w: quantity %s is not a multiple of step size %s", ErrOrderFilter, quantity, step)
+			}
+			quantity = aligned
+		}
+	}
+	if !quantity.IsPositive() {
+		return fmt.Errorf("%w: quantity %s rounds to zero", ErrOrderFilter, req.Quantity)
+	}
+	if minQty, ok := parseFilter(instrument.MinQuantity); ok && quantity.LessThan(minQty) {
+		return fmt.Errorf("%w: quantity %s below minimum %s", ErrOrderFilter, quantity, minQty)
+	}
+	if maxQty, ok := parseFilter(instrument.MaxQuantity); ok && maxQty.IsPositive() && quantity.GreaterThan(maxQty) {
+		return fmt.Errorf("%w: quantity %s above maximum %s", ErrOrderFilter, quantity, maxQty)
+	}
+	req.Quantity = quantity.String()
+
+	if req.Price == nil || strings.TrimSpace(*req.Price) == "" {
+		return nil
+	}
+	price, err := decimal.NewFromString(strings.TrimSpace(*req.Price))
+	if err != nil {
+		return fmt.Errorf("%w: invalid price %q", ErrOrderFilter, *req.Price)
+	}
+	if tick := filterIncrement(instrument.PriceIncrement, instrument.PricePrecision); tick.IsPositive() {
+		ticks := price.Div(tick)
+		if req.Side == schema.TradeSideSell {
+			ticks = ticks.Ceil()
+		} else {
+			ticks = ticks.Floor()
+		}
+		aligned := ticks.Mul(tick)
+		if !aligned.Equal(price) {
+			if !round {
+				return fmt.Errorf("%w: price %s is not a multiple of tick size %s", ErrOrderFilter, price, tick)
+			}
+			price = aligned
+		}
+	}
+	if !price.IsPositive() {
+		return fmt.Errorf("%w: price %s rounds to zero", ErrOrderFilter, *req.Price)
+	}
+	if minNotional, ok := parseFilter(instrument.MinNotional); ok {
+		if notional := price.Mul(quantity); notional.LessThan(minNotional) {
+			return fmt.Errorf("%w: notional %s below minimum %s", ErrOrderFilter, notional, minNotional)
+		}
+	}
+	normalized := price.String()
+	req.Price = &normalized
+	return nil
+}
+
+// filterIncrement resolves an increment from its explicit value or, failing that, from a decimal precision.
+func filterIncrement(increment string, precision *int) decimal.Decimal {
+	if value, ok := parseFilter(increment); ok && value.IsPositive() {
+		return value
+	}
+	if precision != nil && *precision >= 0 {
+		return decimal.New(1, int32(-*precision))
+	}
+	return decimal.Zero
+}
+
+func parseFilter(raw string) (decimal.Decimal, bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return decimal.Zero, false
+	}
+	value, err := decimal.NewFromString(trimmed)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	return value, true
+}
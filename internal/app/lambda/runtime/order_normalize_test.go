@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+func normalizeTestInstrument() schema.Instrument {
+	return schema.Instrument{
+		Symbol:            "BTC-USDT",
+		PriceIncrement:    "0.10",
+		QuantityIncrement: "0.001",
+		MinQuantity:       "0.001",
+		MinNotional:       "5",
+	}
+}
+
+func TestNormalizeOrder_RoundsTowardPassiveSide(t *testing.T) {
+	price := "100.27"
+	buy := &schema.OrderRequest{Symbol: "BTC-USDT", Side: schema.TradeSideBuy, Quantity: "0.12345", Price: &price}
+	if err := normalizeOrder(buy, normalizeTestInstrument(), config.OrderNormalizationRound); err != nil {
+		t.Fatalf("normalize buy: %v", err)
+	}
+	if buy.Quantity != "0.123" || *buy.Price != "100.2" {
+		t.Fatalf("unexpected buy normalisation qty=%s price=%s", buy.Quantity, *buy.Price)
+	}
+
+	sellPrice := "100.21"
+	sell := &schema.OrderRequest{Symbol: "BTC-USDT", Side: schema.TradeSideSell, Quantity: "0.1", Price: &sellPrice}
+	if err := normalizeOrder(sell, normalizeTestInstrument(), config.OrderNormalizationRound); err != nil {
+		t.Fatalf("normalize sell: %v", err)
+	}
+	if *sell.Price != "100.3" {
+		t.Fatalf("expected sell price rounded up, got %s", *sell.Price)
+	}
+}
+
+func TestNormalizeOrder_RejectMode(t *testing.T) {
+	price := "100.25"
+	req := &schema.OrderRequest{Symbol: "BTC-USDT", Side: schema.TradeSideBuy, Quantity: "0.1", Price: &price}
+	err := normalizeOrder(req, normalizeTestInstrument(), config.OrderNormalizationReject)
+	if !errors.Is(err, ErrOrderFilter) {
+		t.Fatalf("expected filter error, got %v", err)
+	}
+	if *req.Price != "100.25" {
+		t.Fatalf("reject mode must not modify price, got %s", *req.Price)
+	}
+}
+
+func TestNormalizeOrder_MinimumsEnforced(t *testing.T) {
+	price := "100"
+	tooSmall := &schema.OrderRequest{Symbol: "BTC-USDT", Side: schema.TradeSideBuy, Quantity: "0.0004", Price: &price}
+	if err := normalizeOrder(tooSmall, normalizeTestInstrument(), config.OrderNormalizationRound); !errors.Is(err, ErrOrderFilter) {
+		t.Fatalf("expected quantity rounding to zero to fail, got %v", err)
+	}
+	lowNotional := &schema.OrderRequest{Symbol: "BTC-USDT", Side: schema.TradeSideBuy, Quantity: "0.01", Price: &price}
+	if err := normalizeOrder(lowNotional, normalizeTestInstrument(), config.OrderNormalizationRound); !errors.Is(err, ErrOrderFilter) {
+		t.Fatalf("expected min notional breach, got %v", err)
+	}
+	market := &schema.OrderRequest{Symbol: "BTC-USDT", Side: schema.TradeSideBuy, Quantity: "0.01"}
+	if err := normalizeOrder(market, normalizeTestInstrument(), config.OrderNormalizationRound); err != nil {
+		t.Fatalf("market orders skip notional checks, got %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+package runtime
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/app/lambda/strategies"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// Preflight issue severities.
+const (
+	PreflightSeverityError   = "error"
+	PreflightSeverityWarning = "warning"
+)
+
+// runtimeConfigKeys are strategy config keys consumed by the gateway rather than the revision,
+// so they are never reported as undeclared.
+var runtimeConfigKeys = map[string]struct{}{
+	"dry_run":                 {},
+	"durable_subscription":    {},
+	"delivery_mode":           {},
+	"handler_workers":         {},
+	"max_in_flight_events":    {},
+	"priority":                {},
+	"error_budget":            {},
+	"error_budget_window":     {},
+	"market_history_trades":   {},
+	"market_history_klines":   {},
+	"order_history_size":      {},
+	"book_delta_max_rate":     {},
+	"book_depth":              {},
+	"book_snapshot_interval":  {},
+	"warmup_market_data":      {},
+	"warmup_balances":         {},
+	"warmup_provider_running": {},
+	RiskProfileConfigKey:      {},
+	js.SeedConfigKey:          {},
+}
+
+// PreflightIssue describes a single incompatibility discovered while dry-binding a module.
+type PreflightIssue struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// InstancePreflight reports how an existing instance would behave on the candidate revision.
+type InstancePreflight struct {
+	ID          string           `json:"id"`
+	Selector    string           `json:"selector"`
+	CurrentHash string           `json:"currentHash,omitempty"`
+	Running     bool             `json:"running"`
+	FollowsTag  bool             `json:"followsTag"`
+	Compatible  bool             `json:"compatible"`
+	Issues      []PreflightIssue `json:"issues,omitempty"`
+}
+
+// StrategyPreflightReport summarises a validate-only module upload.
+type StrategyPreflightReport struct {
+	Strategy  string              `json:"strategy"`
+	Hash      string              `json:"hash"`
+	Tag       string              `json:"tag"`
+	MovesTags []string            `json:"movesTags"`
+	Metadata  strategies.Metadata `json:"metadata"`
+	Instances []InstancePreflight `json:"instances"`
+	Breaking  []string            `json:"breaking"`
+	// Diagnostics holds the lint warnings the upload would be stored with.
+	Diagnostics []js.Diagnostic `json:"diagnostics"`
+	// Tests reports the module's inline tests; a failing revision would not move latest.
+	Tests *js.SelfTestReport `json:"tests,omitempty"`
+}
+
+// PreflightStrategy compiles the module without persisting it and dry-binds it against every
+// instance pinned to the same strategy. Instances following a tag the upload would move are
+// listed in Breaking when the dry-bind reports errors.
+func (m *Manager) PreflightStrategy(source []byte, opts js.ModuleWriteOptions) (StrategyPreflightReport, error) {
+	var report StrategyPreflightReport
+	if m == nil || m.jsLoader == nil {
+		return report, fmt.Errorf("strategy loader unavailable")
+	}
+	module, err := m.jsLoader.Compile(source)
+	if err != nil {
+		m.recordStrategyValidationFailure(err)
+		return report, fmt.Errorf("strategy preflight: %w", err)
+	}
+
+	tag := strings.TrimSpace(opts.Tag)
+	if tag == "" {
+		tag = strings.TrimSpace(module.Metadata.Tag)
+	}
+	moved := make(map[string]struct{}, 2+len(opts.ReassignTags))
+	if tag != "" {
+		moved[tag] = struct{}{}
+	}
+	for _, reassigned := range opts.ReassignTags {
+		if trimmed := strings.TrimSpace(reassigned); trimmed != "" {
+			moved[trimmed] = struct{}{}
+		}
+	}
+	tests, err := js.RunSelfTests(module)
+	if err != nil {
+		m.recordStrategyValidationFailure(err)
+		return report, fmt.Errorf("strategy preflight: %w", err)
+	}
+	if opts.PromoteLatest {
+		moved["latest"] = struct{}{}
+	}
+	if tests != nil && !tests.Passed && tag != "latest" {
+		delete(moved, "latest")
+	}
+
+	report.Strategy = module.Name
+	report.Hash = module.Hash
+	report.Tag = tag
+	report.Metadata = strategies.CloneMetadata(module.Metadata)
+	report.Tests = tests
+	report.MovesTags = make([]string, 0, len(moved))
+	for name := range moved {
+		report.MovesTags = append(report.MovesTags, name)
+	}
+	sort.Strings(report.MovesTags)
+	report.Instances = make([]InstancePreflight, 0)
+	report.Breaking = make([]string, 0)
+	report.Diagnostics = js.Lint(source)
+	if report.Diagnostics == nil {
+		report.Diagnostics = make([]js.Diagnostic, 0)
+	}
+
+	type candidate struct {
+		spec    config.LambdaSpec
+		running bool
+	}
+	m.mu.RLock()
+	candidates := make([]candidate, 0)
+	for id, spec := range m.specs {
+		if normalizeStrategyName(spec.Strategy.Identifier) != module.Name {
+			continue
+		}
+		_, running := m.instances[id]
+		candidates = append(candidates, candidate{spec: cloneSpec(spec), running: running})
+	}
+	m.mu.RUnlock()
+
+	for _, cand := range candidates {
+		result := m.preflightInstance(module, cand.spec, moved)
+		result.Running = cand.running
+		if result.FollowsTag && !result.Compatible {
+			report.Breaking = append(report.Breaking, result.ID)
+		}
+		report.Instances = append(report.Instances, result)
+	}
+	sort.Slice(report.Instances, func(i, j int) bool {
+		return report.Instances[i].ID < report.Instances[j].ID
+	})
+	sort.Strings(report.Breaking)
+	return report, nil
+}
+
+func (m *Manager) preflightInstance(module *js.Module, spec config.LambdaSpec, moved map[string]struct{}) InstancePreflight {
+	selector := spec.Strategy.Selector
+	if selector == "" {
+		selector = spec.Strategy.Identifier
+	}
+	result := InstancePreflight{
+		ID:          spec.ID,
+		Selector:    selector,
+		CurrentHash: spec.Strategy.Hash,
+		Running:     false,
+		FollowsTag:  selectorFollowsTags(selector, moved),
+		Compatible:  true,
+		Issues:      nil,
+	}
+	addIssue := func(code, severity, format string, args ...any) {
+		result.Issues = append(result.Issues, PreflightIssue{
+			Code:     code,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+		if severity == PreflightSeverityError {
+			result.Compatible = false
+		}
+	}
+
+	fields := make(map[string]strategies.ConfigField, len(module.Metadata.Config))
+	for _, field := range module.Metadata.Config {
+		fields[field.Name] = field
+	}
+	for _, field := range module.Metadata.Config {
+		value, ok := spec.Strategy.Config[field.Name]
+		if !ok {
+			if field.Required && field.Default == nil {
+				addIssue("config_missing", PreflightSeverityError, "required config %q not set", field.Name)
+			}
+			continue
+		}
+		if !configValueMatchesType(field.Type, value) {
+			addIssue("config_type_mismatch", PreflightSeverityError, "config %q expects %s, got %T", field.Name, field.Type, value)
+		}
+	}
+	for key := range spec.Strategy.Config {
+		if _, reserved := runtimeConfigKeys[key]; reserved {
+			continue
+		}
+		if _, ok := fields[key]; !ok {
+			addIssue("config_unknown", PreflightSeverityWarning, "config %q is not declared by the revision", key)
+		}
+	}
+
+	for _, capability := range m.deniedCapabilities(module.Metadata) {
+		addIssue("capability_denied", PreflightSeverityError, "capability %q is not allowed in %s", capability, m.environment)
+	}
+
+	spec.RefreshProviders()
+	providers := spec.Providers
+	for _, name := range providers {
+		if m.providers == nil {
+			break
+		}
+		if inst, ok := m.providers.Provider(name); !ok || inst == nil {
+			addIssue("provider_unavailable", PreflightSeverityError, "provider %q unavailable", name)
+		}
+	}
+	for _, problem := range m.symbolProblems(spec) {
+		addIssue(problem.code, PreflightSeverityError, "%s", problem.String())
+	}
+	for _, evt := range module.Metadata.Events {
+		if evt == schema.EventTypeRiskControl || evt == schema.ExtensionEventType {
+			continue
+		}
+		if len(schema.RoutesForEvent(evt)) == 0 {
+			addIssue("event_unroutable", PreflightSeverityError, "event %s has no provider route", evt)
+		}
+	}
+
+	strategy, err := js.NewStrategy(module, copyMap(spec.Strategy.Config), m.logger)
+	if err != nil {
+		addIssue("bind_failed", PreflightSeverityError, "instantiate strategy: %v", err)
+		return result
+	}
+	defer strategy.Close()
+	if len(providers) > 1 && !strategy.WantsCrossProviderEvents() {
+		addIssue("cross_provider_unsupported", PreflightSeverityError, "revision does not support cross-provider feeds (%d providers)", len(providers))
+	}
+	return result
+}
+
+// selectorFollowsTags reports whether an instance selector tracks any of the supplied tags.
+// Hash-pinned selectors never follow tags; bare strategy names follow "latest".
+func selectorFollowsTags(selector string, tags map[string]struct{}) bool {
+	trimmed := strings.TrimSpace(selector)
+	if trimmed == "" || strings.Contains(trimmed, "@") {
+		return false
+	}
+	tag := "latest"
+	if colon := strings.LastIndex(trimmed, ":"); colon >= 0 {
+		tag = strings.TrimSpace(trimmed[colon+1:])
+	}
+	_, ok := tags[tag]
+	return ok
+}
+
+func configValueMatchesType(fieldType string, value any) bool {
+	if value == nil {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(fieldType)) {
+	case "bool", "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "int", "integer", "float", "number", "decimal":
+		switch value.(type) {
+		case int, int32, int64, float32, float64, uint, uint32, uint64:
+			return true
+		case string:
+			// Numeric values are frequently supplied as strings to avoid float rounding.
+			return true
+		default:
+			return false
+		}
+	default:
+		return true
+	}
+}
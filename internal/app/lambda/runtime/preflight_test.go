@@ -0,0 +1,44 @@
+package runtime
+
+import "testing"
+
+func TestSelectorFollowsTags(t *testing.T) {
+	moved := map[string]struct{}{"latest": {}, "prod": {}}
+	cases := []struct {
+		selector string
+		want     bool
+	}{
+		{selector: "alpha", want: true},
+		{selector: "alpha:prod", want: true},
+		{selector: "alpha:canary", want: false},
+		{selector: "alpha@sha256:abc", want: false},
+		{selector: "", want: false},
+	}
+	for _, tc := range cases {
+		if got := selectorFollowsTags(tc.selector, moved); got != tc.want {
+			t.Fatalf("selectorFollowsTags(%q) = %v, want %v", tc.selector, got, tc.want)
+		}
+	}
+}
+
+func TestConfigValueMatchesType(t *testing.T) {
+	cases := []struct {
+		fieldType string
+		value     any
+		want      bool
+	}{
+		{fieldType: "bool", value: true, want: true},
+		{fieldType: "bool", value: "true", want: false},
+		{fieldType: "string", value: 1.5, want: false},
+		{fieldType: "number", value: 1.5, want: true},
+		{fieldType: "int", value: "42", want: true},
+		{fieldType: "int", value: []any{1}, want: false},
+		{fieldType: "custom", value: map[string]any{}, want: true},
+		{fieldType: "string", value: nil, want: true},
+	}
+	for _, tc := range cases {
+		if got := configValueMatchesType(tc.fieldType, tc.value); got != tc.want {
+			t.Fatalf("configValueMatchesType(%q, %#v) = %v, want %v", tc.fieldType, tc.value, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package runtime
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// RiskLimitBreach names a running instance whose live exposure proposed shared risk limits would
+// not allow.
+type RiskLimitBreach struct {
+	Instance string `json:"instance"`
+	Symbol   string `json:"symbol"`
+	Limit    string `json:"limit"`
+	Current  string `json:"current"`
+	Proposed string `json:"proposed"`
+}
+
+// PreviewRiskConfig builds the limits a risk config would apply and reports the running instances
+// on the shared limits that would be in breach of them, without applying anything. Instances
+// pinned to their own risk profile are not affected by the shared limits and are left out.
+func (m *Manager) PreviewRiskConfig(cfg config.RiskConfig) (risk.Limits, []RiskLimitBreach) {
+	limits := buildRiskLimits(cfg, m.logger)
+	excesses := m.riskManager.Excesses(limits)
+	if len(excesses) == 0 {
+		return limits, []RiskLimitBreach{}
+	}
+
+	m.riskProfilesMu.Lock()
+	pinned := make(map[string]struct{}, len(m.instanceRisk))
+	for id := range m.instanceRisk {
+		pinned[id] = struct{}{}
+	}
+	m.riskProfilesMu.Unlock()
+
+	// scoped maps each symbol to the running instances on the shared limits that trade it.
+	scoped := make(map[string][]string)
+	running := make(map[string]struct{})
+	m.mu.RLock()
+	for id := range m.instances {
+		if _, ok := pinned[id]; ok {
+			continue
+		}
+		running[id] = struct{}{}
+		for _, symbol := range m.specs[id].AllSymbols() {
+			key := strings.ToUpper(symbol)
+			scoped[key] = append(scoped[key], id)
+		}
+	}
+	m.mu.RUnlock()
+
+	breaches := make([]RiskLimitBreach, 0, len(excesses))
+	for _, excess := range excesses {
+		instances := make(map[string]struct{})
+		for _, consumer := range excess.Consumers {
+			if _, ok := running[consumer]; ok {
+				instances[consumer] = struct{}{}
+			}
+		}
+		if excess.Limit != "allowedOrderTypes" {
+			// Positions are netted across the instances sharing the limits, so every instance
+			// trading the symbol is held back by the excess.
+			for _, id := range scoped[strings.ToUpper(excess.Symbol)] {
+				instances[id] = struct{}{}
+			}
+		}
+		for id := range instances {
+			breaches = append(breaches, RiskLimitBreach{
+				Instance: id,
+				Symbol:   excess.Symbol,
+				Limit:    excess.Limit,
+				Current:  excess.Current,
+				Proposed: excess.Proposed,
+			})
+		}
+	}
+	sort.Slice(breaches, func(i, j int) bool {
+		if breaches[i].Instance != breaches[j].Instance {
+			return breaches[i].Instance < breaches[j].Instance
+		}
+		if breaches[i].Symbol != breaches[j].Symbol {
+			return breaches[i].Symbol < breaches[j].Symbol
+		}
+		if breaches[i].Limit != breaches[j].Limit {
+			return breaches[i].Limit < breaches[j].Limit
+		}
+		return breaches[i].Current < breaches[j].Current
+	})
+	return limits, breaches
+}
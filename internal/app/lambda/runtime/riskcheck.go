@@ -0,0 +1,169 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// OrderCheck describes a hypothetical order for SimulateOrder. Instance is optional; when set the
+// order is evaluated against the limits governing that instance and Provider and Symbol default
+// to its scope.
+type OrderCheck struct {
+	Instance string
+	Provider string
+	Symbol   string
+	// Side is buy or sell.
+	Side string
+	// OrderType is limit or market, defaulting to limit when a price is supplied.
+	OrderType string
+	Quantity  string
+	Price     *string
+	// TIF defaults to GTC for limit orders and IOC for market orders.
+	TIF string
+}
+
+// OrderCheckResult is the simulated risk outcome of an OrderCheck after defaults are applied.
+type OrderCheckResult struct {
+	Instance  string             `json:"instance,omitempty"`
+	Provider  string             `json:"provider"`
+	Symbol    string             `json:"symbol"`
+	Side      schema.TradeSide   `json:"side"`
+	OrderType schema.OrderType   `json:"orderType"`
+	Quantity  string             `json:"quantity"`
+	Price     *string            `json:"price,omitempty"`
+	TIF       schema.TimeInForce `json:"tif"`
+	// RiskProfile names the profile the instance is pinned to; empty means the shared limits.
+	RiskProfile string `json:"riskProfile,omitempty"`
+	risk.Simulation
+}
+
+// SimulateOrder evaluates a hypothetical order against the risk limits without submitting it or
+// consuming throttle budget.
+func (m *Manager) SimulateOrder(check OrderCheck) (OrderCheckResult, error) {
+	var result OrderCheckResult
+	order := schema.OrderRequest{
+		ClientOrderID: "",
+		ConsumerID:    strings.TrimSpace(check.Instance),
+		Provider:      strings.TrimSpace(check.Provider),
+		Symbol:        strings.ToUpper(strings.TrimSpace(check.Symbol)),
+		Side:          "",
+		OrderType:     "",
+		Price:         check.Price,
+		Quantity:      strings.TrimSpace(check.Quantity),
+		TIF:           "",
+		PostOnly:      false,
+		ReduceOnly:    false,
+		SubAccount:    "",
+		Tags:          nil,
+		Metadata:      nil,
+		Timestamp:     m.now(),
+	}
+	if order.Price != nil {
+		trimmed := strings.TrimSpace(*order.Price)
+		order.Price = &trimmed
+		if trimmed == "" {
+			order.Price = nil
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(check.Side)) {
+	case "buy":
+		order.Side = schema.TradeSideBuy
+	case "sell":
+		order.Side = schema.TradeSideSell
+	default:
+		return result, fmt.Errorf("side must be buy or sell")
+	}
+	switch strings.ToLower(strings.TrimSpace(check.OrderType)) {
+	case "":
+		order.OrderType = schema.OrderTypeMarket
+		if order.Price != nil {
+			order.OrderType = schema.OrderTypeLimit
+		}
+	case "limit":
+		order.OrderType = schema.OrderTypeLimit
+	case "market":
+		order.OrderType = schema.OrderTypeMarket
+	default:
+		return result, fmt.Errorf("orderType must be limit or market")
+	}
+	tif, err := schema.ParseTimeInForce(check.TIF)
+	if err != nil {
+		return result, err //nolint:wrapcheck // the message names the rejected value
+	}
+	if tif == "" {
+		tif = schema.DefaultTimeInForce(order.OrderType)
+	}
+	order.TIF = tif
+
+	manager := m.riskManager
+	profile := ""
+	if order.ConsumerID != "" {
+		spec, err := m.specForID(order.ConsumerID)
+		if err != nil {
+			return result, err
+		}
+		if order.Provider == "" {
+			if len(spec.Providers) != 1 {
+				return result, fmt.Errorf("provider required for instance %s", spec.ID)
+			}
+			order.Provider = spec.Providers[0]
+		}
+		symbols := spec.SymbolsForProvider(order.Provider)
+		if len(symbols) == 0 {
+			return result, fmt.Errorf("provider %s not in scope of instance %s", order.Provider, spec.ID)
+		}
+		if order.Symbol == "" {
+			if len(symbols) != 1 {
+				return result, fmt.Errorf("symbol required for instance %s", spec.ID)
+			}
+			order.Symbol = symbols[0]
+		}
+		order.SubAccount = spec.SubAccountForProvider(order.Provider)
+		manager, profile = m.simulationRiskManager(spec.ID, riskProfileFromConfig(spec.Strategy.Config))
+	}
+	if order.Provider == "" {
+		return result, fmt.Errorf("provider required")
+	}
+	if order.Symbol == "" {
+		return result, fmt.Errorf("symbol required")
+	}
+
+	result = OrderCheckResult{
+		Instance:    order.ConsumerID,
+		Provider:    order.Provider,
+		Symbol:      order.Symbol,
+		Side:        order.Side,
+		OrderType:   order.OrderType,
+		Quantity:    order.Quantity,
+		Price:       order.Price,
+		TIF:         order.TIF,
+		RiskProfile: profile,
+		Simulation:  manager.Simulate(order),
+	}
+	return result, nil
+}
+
+// simulationRiskManager returns the manager that would check orders of instance id. An instance
+// pinned to a profile but not launched yet is checked against a fresh manager for that profile.
+func (m *Manager) simulationRiskManager(id, profileName string) (*risk.Manager, string) {
+	m.riskProfilesMu.Lock()
+	defer m.riskProfilesMu.Unlock()
+	if pinned, ok := m.instanceRisk[id]; ok {
+		return pinned.manager, pinned.profile
+	}
+	if profileName == "" {
+		return m.riskManager, ""
+	}
+	profile, ok := m.lookupRiskProfileLocked(profileName)
+	if !ok {
+		return m.riskManager, ""
+	}
+	rm := risk.NewManager(buildRiskLimits(profile.Limits, m.logger))
+	rm.SetRuleSet(m.riskManager.RuleSet())
+	rm.SetPortfolio(m.riskManager.Portfolio())
+	rm.SetSymbolPolicy(m.riskManager.SymbolPolicy())
+	return rm, profileName
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+)
+
+func TestManagerSimulateOrderUsesInstanceScopeAndProfile(t *testing.T) {
+	mgr := newTestManager(t)
+	spec := baseLambdaSpec()
+	spec.Strategy.Config[RiskProfileConfigKey] = RiskProfileConservative
+	if err := mgr.ensureSpec(&spec, false); err != nil {
+		t.Fatalf("ensureSpec: %v", err)
+	}
+
+	result, err := mgr.SimulateOrder(OrderCheck{Instance: "alpha", Side: "BUY", Quantity: "150"})
+	if err != nil {
+		t.Fatalf("SimulateOrder: %v", err)
+	}
+	if result.Provider != "okx-spot" || result.Symbol != "BTC-USDT" || result.OrderType != "Market" {
+		t.Fatalf("unexpected defaults %+v", result)
+	}
+	if result.RiskProfile != RiskProfileConservative {
+		t.Fatalf("risk profile = %q, want %q", result.RiskProfile, RiskProfileConservative)
+	}
+	if result.Allowed {
+		t.Fatalf("expected conservative position limit to reject 150, got %+v", result.Checks)
+	}
+	for _, check := range result.Checks {
+		if check.Check == risk.CheckPosition && check.Passed {
+			t.Fatalf("position check should fail: %+v", check)
+		}
+	}
+
+	if _, err := mgr.SimulateOrder(OrderCheck{Instance: "ghost", Side: "buy", Quantity: "1"}); !errors.Is(err, ErrInstanceNotFound) {
+		t.Fatalf("expected ErrInstanceNotFound, got %v", err)
+	}
+	if _, err := mgr.SimulateOrder(OrderCheck{Instance: "alpha", Provider: "binance-spot", Side: "buy", Quantity: "1"}); err == nil {
+		t.Fatal("expected out-of-scope provider to be rejected")
+	}
+	if _, err := mgr.SimulateOrder(OrderCheck{Provider: "okx-spot", Symbol: "BTC-USDT", Side: "hold", Quantity: "1"}); err == nil {
+		t.Fatal("expected invalid side to be rejected")
+	}
+	if _, err := mgr.SimulateOrder(OrderCheck{Provider: "okx-spot", Side: "sell", Quantity: "1"}); err == nil {
+		t.Fatal("expected missing symbol to be rejected")
+	}
+}
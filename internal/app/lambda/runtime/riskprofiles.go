@@ -0,0 +1,555 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/domain/riskstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// RiskProfileConfigKey is the strategy config key that pins an instance to a named risk profile.
+const RiskProfileConfigKey = "risk_profile"
+
+// Built-in risk profile names.
+const (
+	RiskProfileConservative = "conservative"
+	RiskProfileStandard     = "standard"
+	RiskProfileAggressive   = "aggressive"
+)
+
+var (
+	// ErrRiskProfileNotFound indicates the requested risk profile does not exist.
+	ErrRiskProfileNotFound = errors.New("risk profile not found")
+	// ErrRiskProfileBuiltIn indicates an attempt to modify or delete a built-in risk profile.
+	ErrRiskProfileBuiltIn = errors.New("built-in risk profiles are read-only")
+	// ErrRiskProfileInUse indicates the risk profile is applied globally or assigned to an instance.
+	ErrRiskProfileInUse = errors.New("risk profile in use")
+)
+
+// RiskProfile is a named preset of risk limits.
+type RiskProfile struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Limits      config.RiskConfig `json:"limits"`
+	BuiltIn     bool              `json:"builtIn"`
+	UpdatedAt   *time.Time        `json:"updatedAt,omitempty"`
+}
+
+// RiskProfileAssignments reports which profile governs the shared limits and each pinned instance.
+type RiskProfileAssignments struct {
+	Global    string            `json:"global,omitempty"`
+	Instances map[string]string `json:"instances"`
+}
+
+// WithRiskProfileStore wires a risk profile persistence store into the manager.
+func WithRiskProfileStore(store riskstore.Store) Option {
+	return func(m *Manager) {
+		m.riskProfileStore = store
+	}
+}
+
+// builtinRiskProfiles returns the read-only presets. They inherit the notional currency, FX and
+// concentration settings of the configured limits so that switching posture never changes units or
+// the portfolio-wide caps.
+func builtinRiskProfiles(base config.RiskConfig) map[string]RiskProfile {
+	notional := strings.TrimSpace(base.NotionalCurrency)
+	if notional == "" {
+		notional = "USDT"
+	}
+	fx := base.FX
+	preset := func(name, description string, cfg config.RiskConfig) RiskProfile {
+		cfg.NotionalCurrency = notional
+		cfg.FX = fx
+		cfg.Concentration = base.Concentration
+		return RiskProfile{Name: name, Description: description, Limits: cfg, BuiltIn: true, UpdatedAt: nil}
+	}
+	return map[string]RiskProfile{
+		RiskProfileConservative: preset(RiskProfileConservative, "Small positions, limit orders only, quick circuit breaker", config.RiskConfig{
+			MaxPositionSize:     "100",
+			MaxNotionalValue:    "10000",
+			OrderThrottle:       2,
+			OrderBurst:          1,
+			MaxConcurrentOrders: 2,
+			PriceBandPercent:    0.5,
+			AllowedOrderTypes:   []string{"Limit"},
+			KillSwitchEnabled:   true,
+			MaxRiskBreaches:     2,
+			CircuitBreaker:      config.CircuitBreakerConfig{Enabled: true, Threshold: 2, Cooldown: "5m"},
+			SelfTradePrevention: string(risk.SelfTradeReject),
+			BalanceCheck:        true,
+		}),
+		RiskProfileStandard: preset(RiskProfileStandard, "Gateway default limits", config.RiskConfig{
+			MaxPositionSize:     "250",
+			MaxNotionalValue:    "50000",
+			OrderThrottle:       5,
+			OrderBurst:          3,
+			MaxConcurrentOrders: 6,
+			PriceBandPercent:    1.0,
+			AllowedOrderTypes:   []string{"Limit", "Market"},
+			KillSwitchEnabled:   true,
+			MaxRiskBreaches:     3,
+			CircuitBreaker:      config.CircuitBreakerConfig{Enabled: true, Threshold: 4, Cooldown: "90s"},
+			SelfTradePrevention: string(risk.SelfTradeReject),
+			BalanceCheck:        false,
+		}),
+		RiskProfileAggressive: preset(RiskProfileAggressive, "Large positions and high order rates for liquid markets", config.RiskConfig{
+			MaxPositionSize:     "1000",
+			MaxNotionalValue:    "250000",
+			OrderThrottle:       20,
+			OrderBurst:          10,
+			MaxConcurrentOrders: 20,
+			PriceBandPercent:    2.5,
+			AllowedOrderTypes:   []string{"Limit", "Market"},
+			KillSwitchEnabled:   true,
+			MaxRiskBreaches:     5,
+			CircuitBreaker:      config.CircuitBreakerConfig{Enabled: true, Threshold: 8, Cooldown: "30s"},
+			SelfTradePrevention: string(risk.SelfTradeReject),
+			BalanceCheck:        false,
+		}),
+	}
+}
+
+func normalizeRiskProfileName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// riskProfileFromConfig returns the profile name pinned in a strategy config, if any.
+func riskProfileFromConfig(cfg map[string]any) string {
+	raw, _ := cfg[RiskProfileConfigKey].(string)
+	return normalizeRiskProfileName(raw)
+}
+
+// LoadRiskProfiles hydrates custom risk profiles from the configured store.
+func (m *Manager) LoadRiskProfiles(ctx context.Context) error {
+	if m == nil || m.riskProfileStore == nil {
+		return nil
+	}
+	stored, err := m.riskProfileStore.LoadProfiles(ctx)
+	if err != nil {
+		return fmt.Errorf("load risk profiles: %w", err)
+	}
+	loaded := make(map[string]RiskProfile, len(stored))
+	for _, entry := range stored {
+		name := normalizeRiskProfileName(entry.Name)
+		if _, builtin := m.builtinProfiles[name]; builtin || name == "" {
+			continue
+		}
+		limits, err := riskConfigFromMap(entry.Limits)
+		if err != nil {
+			if m.logger != nil {
+				m.logger.Printf("risk profile %s: decode failed: %v", entry.Name, err)
+			}
+			continue
+		}
+		updated := entry.UpdatedAt
+		loaded[name] = RiskProfile{Name: name, Description: entry.Description, Limits: limits, BuiltIn: false, UpdatedAt: &updated}
+	}
+	m.riskProfilesMu.Lock()
+	for name, profile := range loaded {
+		m.riskProfiles[name] = profile
+	}
+	m.riskProfilesMu.Unlock()
+	return nil
+}
+
+// RiskProfiles lists built-in and stored risk profiles ordered by name.
+func (m *Manager) RiskProfiles() []RiskProfile {
+	m.riskProfilesMu.Lock()
+	defer m.riskProfilesMu.Unlock()
+	out := make([]RiskProfile, 0, len(m.builtinProfiles)+len(m.riskProfiles))
+	for _, profile := range m.builtinProfiles {
+		out = append(out, profile)
+	}
+	for _, profile := range m.riskProfiles {
+		out = append(out, profile)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// RiskProfile returns the named risk profile.
+func (m *Manager) RiskProfile(name string) (RiskProfile, bool) {
+	m.riskProfilesMu.Lock()
+	defer m.riskProfilesMu.Unlock()
+	return m.lookupRiskProfileLocked(normalizeRiskProfileName(name))
+}
+
+func (m *Manager) lookupRiskProfileLocked(name string) (RiskProfile, bool) {
+	if profile, ok := m.builtinProfiles[name]; ok {
+		return profile, true
+	}
+	profile, ok := m.riskProfiles[name]
+	return profile, ok
+}
+
+// SaveRiskProfile creates or replaces a custom risk profile. Instances pinned to the profile and
+// the shared limits, when the profile is applied globally, pick up the new values immediately.
+func (m *Manager) SaveRiskProfile(ctx context.Context, profile RiskProfile) (RiskProfile, error) {
+	name := normalizeRiskProfileName(profile.Name)
+	if name == "" {
+		return RiskProfile{}, fmt.Errorf("risk profile name required")
+	}
+	if _, builtin := m.builtinProfiles[name]; builtin {
+		return RiskProfile{}, fmt.Errorf("%w: %s", ErrRiskProfileBuiltIn, name)
+	}
+	now := m.clock().UTC()
+	saved := RiskProfile{
+		Name:        name,
+		Description: strings.TrimSpace(profile.Description),
+		Limits:      profile.Limits,
+		BuiltIn:     false,
+		UpdatedAt:   &now,
+	}
+	if m.riskProfileStore != nil {
+		limits, err := riskConfigToMap(saved.Limits)
+		if err != nil {
+			return RiskProfile{}, err
+		}
+		if err := m.riskProfileStore.SaveProfile(ctx, riskstore.Profile{
+			Name:        saved.Name,
+			Description: saved.Description,
+			Limits:      limits,
+			UpdatedAt:   now,
+		}); err != nil {
+			return RiskProfile{}, fmt.Errorf("persist risk profile: %w", err)
+		}
+	}
+
+	m.riskProfilesMu.Lock()
+	m.riskProfiles[name] = saved
+	global := m.globalRiskProfile == name
+	pinned := m.instancesPinnedToLocked(name)
+	m.riskProfilesMu.Unlock()
+
+	limits := buildRiskLimits(saved.Limits, m.logger)
+	if global {
+		m.UpdateRiskLimits(limits)
+	}
+	for _, rm := range pinned {
+		rm.UpdateLimits(limits)
+	}
+	return saved, nil
+}
+
+// DeleteRiskProfile removes a custom risk profile that is not currently in use.
+func (m *Manager) DeleteRiskProfile(ctx context.Context, name string) error {
+	name = normalizeRiskProfileName(name)
+	if _, builtin := m.builtinProfiles[name]; builtin {
+		return fmt.Errorf("%w: %s", ErrRiskProfileBuiltIn, name)
+	}
+	m.riskProfilesMu.Lock()
+	defer m.riskProfilesMu.Unlock()
+	if _, ok := m.riskProfiles[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrRiskProfileNotFound, name)
+	}
+	if m.globalRiskProfile == name {
+		return fmt.Errorf("%w: %s is applied globally", ErrRiskProfileInUse, name)
+	}
+	if ids := m.instanceIDsPinnedTo(name); len(ids) > 0 {
+		return fmt.Errorf("%w: %s is assigned to %s", ErrRiskProfileInUse, name, strings.Join(ids, ", "))
+	}
+	if m.riskProfileStore != nil {
+		if err := m.riskProfileStore.DeleteProfile(ctx, name); err != nil {
+			return fmt.Errorf("delete risk profile: %w", err)
+		}
+	}
+	delete(m.riskProfiles, name)
+	return nil
+}
+
+// ApplyRiskProfile replaces the shared risk limits with the named profile. Instances pinned to
+// their own profile are unaffected.
+func (m *Manager) ApplyRiskProfile(name string) (risk.Limits, error) {
+	name = normalizeRiskProfileName(name)
+	m.riskProfilesMu.Lock()
+	profile, ok := m.lookupRiskProfileLocked(name)
+	if !ok {
+		m.riskProfilesMu.Unlock()
+		return risk.Limits{}, fmt.Errorf("%w: %s", ErrRiskProfileNotFound, name)
+	}
+	m.globalRiskProfile = name
+	m.riskProfilesMu.Unlock()
+
+	limits := buildRiskLimits(profile.Limits, m.logger)
+	m.UpdateRiskLimits(limits)
+	if m.logger != nil {
+		m.logger.Printf("risk profile %s applied globally", name)
+	}
+	return limits, nil
+}
+
+// AssignInstanceRiskProfile pins an instance to the named profile, giving it a dedicated risk
+// manager. An empty name returns the instance to the shared limits. The assignment is stored in
+// the instance's strategy config so it survives restarts.
+func (m *Manager) AssignInstanceRiskProfile(id, name string) error {
+	id = strings.TrimSpace(id)
+	name = normalizeRiskProfileName(name)
+
+	m.riskProfilesMu.Lock()
+	var profile RiskProfile
+	if name != "" {
+		var ok bool
+		profile, ok = m.lookupRiskProfileLocked(name)
+		if !ok {
+			m.riskProfilesMu.Unlock()
+			return fmt.Errorf("%w: %s", ErrRiskProfileNotFound, name)
+		}
+	}
+
+	m.mu.Lock()
+	spec, ok := m.specs[id]
+	if !ok {
+		m.mu.Unlock()
+		m.riskProfilesMu.Unlock()
+		return ErrInstanceNotFound
+	}
+	cfg := make(map[string]any, len(spec.Strategy.Config)+1)
+	for key, value := range spec.Strategy.Config {
+		cfg[key] = value
+	}
+	if name == "" {
+		delete(cfg, RiskProfileConfigKey)
+	} else {
+		cfg[RiskProfileConfigKey] = name
+	}
+	spec.Strategy.Config = cfg
+	m.specs[id] = spec
+	var base *lambdaInstance
+	if inst, running := m.instances[id]; running {
+		base = inst
+	}
+	m.mu.Unlock()
+
+	rm := m.riskProfileManagerLocked(id, name, profile)
+	m.riskProfilesMu.Unlock()
+
+	if base != nil && base.base != nil {
+		base.base.SetRiskManager(rm)
+	}
+	m.persistStrategy(id)
+	if m.logger != nil {
+		if name == "" {
+			m.logger.Printf("strategy/%s: risk profile cleared; using shared limits", id)
+		} else {
+			m.logger.Printf("strategy/%s: risk profile %s assigned", id, name)
+		}
+	}
+	return nil
+}
+
+// RiskProfileAssignments reports the globally applied profile and per-instance assignments.
+func (m *Manager) RiskProfileAssignments() RiskProfileAssignments {
+	m.riskProfilesMu.Lock()
+	global := m.globalRiskProfile
+	m.riskProfilesMu.Unlock()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	instances := make(map[string]string)
+	for id, spec := range m.specs {
+		if name := riskProfileFromConfig(spec.Strategy.Config); name != "" {
+			instances[id] = name
+		}
+	}
+	return RiskProfileAssignments{Global: global, Instances: instances}
+}
+
+// RiskProfileIncreases describes the position and notional limits applying the named profile would
+// raise by more than percent: the shared limits, or the limits of each listed instance when any
+// are named. An empty name with instances stands for clearing their profile, which returns them
+// to the shared limits.
+func (m *Manager) RiskProfileIncreases(name string, instances []string, percent float64) ([]string, error) {
+	var next risk.Limits
+	if normalizeRiskProfileName(name) == "" && len(instances) > 0 {
+		next = m.RiskLimits()
+	} else {
+		profile, ok := m.RiskProfile(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrRiskProfileNotFound, normalizeRiskProfileName(name))
+		}
+		next = buildRiskLimits(profile.Limits, nil)
+	}
+	if len(instances) == 0 {
+		return risk.LimitIncreases(m.RiskLimits(), next, percent), nil
+	}
+	var raised []string
+	for _, id := range instances {
+		for _, change := range risk.LimitIncreases(m.instanceRiskLimits(strings.TrimSpace(id)), next, percent) {
+			raised = append(raised, id+" "+change)
+		}
+	}
+	return raised, nil
+}
+
+// RiskProfileSaveIncreases describes the position and notional limits saving limits onto the named
+// profile would raise by more than percent, for the shared limits when the profile is applied
+// globally and for every instance pinned to it.
+func (m *Manager) RiskProfileSaveIncreases(name string, limits config.RiskConfig, percent float64) []string {
+	name = normalizeRiskProfileName(name)
+	next := buildRiskLimits(limits, nil)
+	m.riskProfilesMu.Lock()
+	global := m.globalRiskProfile == name
+	m.riskProfilesMu.Unlock()
+
+	var raised []string
+	if global {
+		raised = append(raised, risk.LimitIncreases(m.RiskLimits(), next, percent)...)
+	}
+	for _, id := range m.instanceIDsPinnedTo(name) {
+		for _, change := range risk.LimitIncreases(m.instanceRiskLimits(id), next, percent) {
+			raised = append(raised, id+" "+change)
+		}
+	}
+	return raised
+}
+
+// instanceRiskLimits returns the limits governing id: its dedicated manager's, the limits of the
+// profile its spec pins when it has not launched yet, or the shared limits.
+func (m *Manager) instanceRiskLimits(id string) risk.Limits {
+	m.mu.RLock()
+	spec, ok := m.specs[id]
+	m.mu.RUnlock()
+	name := ""
+	if ok {
+		name = riskProfileFromConfig(spec.Strategy.Config)
+	}
+
+	m.riskProfilesMu.Lock()
+	defer m.riskProfilesMu.Unlock()
+	if pinned, ok := m.instanceRisk[id]; ok {
+		return pinned.manager.Limits()
+	}
+	if name != "" {
+		if profile, ok := m.lookupRiskProfileLocked(name); ok {
+			return buildRiskLimits(profile.Limits, nil)
+		}
+	}
+	return m.riskManager.Limits()
+}
+
+// instanceRiskManager resolves the risk manager for a launching instance: a dedicated manager when
+// the spec pins a known profile, the shared manager otherwise.
+func (m *Manager) instanceRiskManager(id string, cfg map[string]any) *risk.Manager {
+	name := riskProfileFromConfig(cfg)
+	m.riskProfilesMu.Lock()
+	defer m.riskProfilesMu.Unlock()
+	var profile RiskProfile
+	if name != "" {
+		var ok bool
+		profile, ok = m.lookupRiskProfileLocked(name)
+		if !ok {
+			if m.logger != nil {
+				m.logger.Printf("strategy/%s: risk profile %s not found; using shared limits", id, name)
+			}
+			name = ""
+		}
+	}
+	return m.riskProfileManagerLocked(id, name, profile)
+}
+
+// riskProfileManagerLocked returns the manager governing id. Dedicated managers are kept across
+// restarts of the instance so positions and breach counters carry over. Callers must hold
+// riskProfilesMu.
+func (m *Manager) riskProfileManagerLocked(id, name string, profile RiskProfile) *risk.Manager {
+	if name == "" {
+		delete(m.instanceRisk, id)
+		return m.riskManager
+	}
+	limits := buildRiskLimits(profile.Limits, m.logger)
+	if pinned, ok := m.instanceRisk[id]; ok {
+		pinned.manager.UpdateLimits(limits)
+		pinned.profile = name
+		return pinned.manager
+	}
+	rm := risk.NewManager(limits)
+	rm.SetRuleSet(m.riskManager.RuleSet())
+	rm.SetPortfolio(m.riskManager.Portfolio())
+	rm.SetSymbolPolicy(m.riskManager.SymbolPolicy())
+	rm.SetRestingOrderCanceller(m.cancelRestingOrder)
+	if halted, reason := m.riskManager.KillSwitchStatus(); halted {
+		rm.Halt(reason)
+	}
+	m.instanceRisk[id] = &pinnedRisk{profile: name, manager: rm}
+	return rm
+}
+
+// dropInstanceRiskManager forgets the dedicated risk manager of a removed instance.
+func (m *Manager) dropInstanceRiskManager(id string) {
+	m.riskProfilesMu.Lock()
+	delete(m.instanceRisk, id)
+	m.riskProfilesMu.Unlock()
+}
+
+// instancesPinnedToLocked returns the dedicated managers currently bound to the named profile.
+// Callers must hold riskProfilesMu.
+func (m *Manager) instancesPinnedToLocked(name string) []*risk.Manager {
+	var out []*risk.Manager
+	for _, pinned := range m.instanceRisk {
+		if pinned.profile == name {
+			out = append(out, pinned.manager)
+		}
+	}
+	return out
+}
+
+// instanceIDsPinnedTo lists instances whose strategy config references the named profile.
+func (m *Manager) instanceIDsPinnedTo(name string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var ids []string
+	for id, spec := range m.specs {
+		if riskProfileFromConfig(spec.Strategy.Config) == name {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// allRiskManagers returns the shared manager followed by every dedicated instance manager.
+func (m *Manager) allRiskManagers() []*risk.Manager {
+	m.riskProfilesMu.Lock()
+	defer m.riskProfilesMu.Unlock()
+	out := make([]*risk.Manager, 0, len(m.instanceRisk)+1)
+	out = append(out, m.riskManager)
+	for _, pinned := range m.instanceRisk {
+		out = append(out, pinned.manager)
+	}
+	return out
+}
+
+type pinnedRisk struct {
+	profile string
+	manager *risk.Manager
+}
+
+func riskConfigToMap(cfg config.RiskConfig) (map[string]any, error) {
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("encode risk profile limits: %w", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("encode risk profile limits: %w", err)
+	}
+	return out, nil
+}
+
+func riskConfigFromMap(values map[string]any) (config.RiskConfig, error) {
+	var cfg config.RiskConfig
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return cfg, fmt.Errorf("decode risk profile limits: %w", err)
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return cfg, fmt.Errorf("decode risk profile limits: %w", err)
+	}
+	return cfg, nil
+}
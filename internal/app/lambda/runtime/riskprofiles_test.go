@@ -0,0 +1,133 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+func TestManagerRiskProfilesBuiltIn(t *testing.T) {
+	mgr := newTestManager(t)
+	profiles := mgr.RiskProfiles()
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		if !profile.BuiltIn {
+			t.Fatalf("unexpected custom profile %s", profile.Name)
+		}
+		names = append(names, profile.Name)
+	}
+	want := []string{RiskProfileAggressive, RiskProfileConservative, RiskProfileStandard}
+	if len(names) != len(want) {
+		t.Fatalf("profiles = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("profiles = %v, want %v", names, want)
+		}
+	}
+
+	if _, err := mgr.SaveRiskProfile(context.Background(), RiskProfile{Name: "Standard"}); !errors.Is(err, ErrRiskProfileBuiltIn) {
+		t.Fatalf("expected ErrRiskProfileBuiltIn, got %v", err)
+	}
+	if err := mgr.DeleteRiskProfile(context.Background(), RiskProfileConservative); !errors.Is(err, ErrRiskProfileBuiltIn) {
+		t.Fatalf("expected ErrRiskProfileBuiltIn, got %v", err)
+	}
+}
+
+func TestManagerApplyRiskProfileGlobally(t *testing.T) {
+	mgr := newTestManager(t)
+	limits, err := mgr.ApplyRiskProfile("Conservative")
+	if err != nil {
+		t.Fatalf("ApplyRiskProfile: %v", err)
+	}
+	if limits.MaxPositionSize.String() != "100" || limits.OrderBurst != 1 {
+		t.Fatalf("unexpected limits %+v", limits)
+	}
+	if got := mgr.RiskLimits().MaxPositionSize.String(); got != "100" {
+		t.Fatalf("shared max position = %s, want 100", got)
+	}
+	if got := mgr.RiskProfileAssignments().Global; got != RiskProfileConservative {
+		t.Fatalf("global profile = %q", got)
+	}
+
+	custom := config.RiskConfig{
+		MaxPositionSize:  "5",
+		MaxNotionalValue: "500",
+		NotionalCurrency: "USDT",
+		OrderThrottle:    1,
+		OrderBurst:       1,
+	}
+	if _, err := mgr.SaveRiskProfile(context.Background(), RiskProfile{Name: "fomc", Limits: custom}); err != nil {
+		t.Fatalf("SaveRiskProfile: %v", err)
+	}
+	if _, err := mgr.ApplyRiskProfile("fomc"); err != nil {
+		t.Fatalf("ApplyRiskProfile: %v", err)
+	}
+	if err := mgr.DeleteRiskProfile(context.Background(), "fomc"); !errors.Is(err, ErrRiskProfileInUse) {
+		t.Fatalf("expected ErrRiskProfileInUse, got %v", err)
+	}
+
+	custom.MaxPositionSize = "7"
+	if _, err := mgr.SaveRiskProfile(context.Background(), RiskProfile{Name: "fomc", Limits: custom}); err != nil {
+		t.Fatalf("SaveRiskProfile: %v", err)
+	}
+	if got := mgr.RiskLimits().MaxPositionSize.String(); got != "7" {
+		t.Fatalf("shared max position after profile edit = %s, want 7", got)
+	}
+
+	mgr.ApplyRiskConfig(custom)
+	if got := mgr.RiskProfileAssignments().Global; got != "" {
+		t.Fatalf("global profile after manual edit = %q, want empty", got)
+	}
+	if err := mgr.DeleteRiskProfile(context.Background(), "fomc"); err != nil {
+		t.Fatalf("DeleteRiskProfile: %v", err)
+	}
+	if _, err := mgr.ApplyRiskProfile("fomc"); !errors.Is(err, ErrRiskProfileNotFound) {
+		t.Fatalf("expected ErrRiskProfileNotFound, got %v", err)
+	}
+}
+
+func TestManagerInstanceRiskManagerPinning(t *testing.T) {
+	mgr := newTestManager(t)
+	shared := mgr.instanceRiskManager("alpha", nil)
+	if shared != mgr.riskManager {
+		t.Fatalf("expected shared manager without a profile")
+	}
+	if rm := mgr.instanceRiskManager("alpha", map[string]any{RiskProfileConfigKey: "missing"}); rm != mgr.riskManager {
+		t.Fatalf("expected shared manager for unknown profile")
+	}
+
+	mgr.HaltTrading("maintenance")
+	pinned := mgr.instanceRiskManager("alpha", map[string]any{RiskProfileConfigKey: "Aggressive"})
+	if pinned == mgr.riskManager {
+		t.Fatalf("expected dedicated manager for pinned profile")
+	}
+	if got := pinned.Limits().MaxPositionSize.String(); got != "1000" {
+		t.Fatalf("pinned max position = %s, want 1000", got)
+	}
+	if halted, _ := pinned.KillSwitchStatus(); !halted {
+		t.Fatalf("expected dedicated manager to inherit the active halt")
+	}
+	if pinned.Portfolio() != mgr.riskManager.Portfolio() {
+		t.Fatalf("expected dedicated manager to share the portfolio for concentration limits")
+	}
+	mgr.ResumeTrading()
+	if halted, _ := pinned.KillSwitchStatus(); halted {
+		t.Fatalf("expected resume to release dedicated manager")
+	}
+	if again := mgr.instanceRiskManager("alpha", map[string]any{RiskProfileConfigKey: RiskProfileConservative}); again != pinned {
+		t.Fatalf("expected dedicated manager to be reused across restarts")
+	}
+	if got := pinned.Limits().MaxPositionSize.String(); got != "100" {
+		t.Fatalf("reused manager max position = %s, want 100", got)
+	}
+
+	if err := mgr.AssignInstanceRiskProfile("ghost", RiskProfileStandard); !errors.Is(err, ErrInstanceNotFound) {
+		t.Fatalf("expected ErrInstanceNotFound, got %v", err)
+	}
+	if err := mgr.AssignInstanceRiskProfile("ghost", "missing"); !errors.Is(err, ErrRiskProfileNotFound) {
+		t.Fatalf("expected ErrRiskProfileNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+)
+
+// ErrRiskRuleNotFound indicates the requested custom risk rule is not registered.
+var ErrRiskRuleNotFound = errors.New("risk rule not found")
+
+// RiskRules reports the registered custom risk rules and their most recent decisions.
+type RiskRules struct {
+	Rules     []risk.RuleStatus   `json:"rules"`
+	Decisions []risk.RuleDecision `json:"decisions"`
+}
+
+// WithRiskRule registers a custom risk rule evaluated for every strategy instance. Registration
+// failures such as duplicate names are logged and the rule is skipped.
+func WithRiskRule(rule risk.RiskRule, opts ...risk.RuleOption) Option {
+	return func(m *Manager) {
+		if err := m.RegisterRiskRule(rule, opts...); err != nil {
+			m.logger.Printf("risk rule: %v", err)
+		}
+	}
+}
+
+// RegisterRiskRule adds a custom risk rule shared by the global manager and every profile-pinned
+// instance manager.
+func (m *Manager) RegisterRiskRule(rule risk.RiskRule, opts ...risk.RuleOption) error {
+	if err := m.riskManager.RegisterRule(rule, opts...); err != nil {
+		return fmt.Errorf("register risk rule: %w", err)
+	}
+	return nil
+}
+
+// RiskRules returns the custom risk rules in evaluation order with their recent decisions.
+func (m *Manager) RiskRules() RiskRules {
+	rules := m.riskManager.RuleSet()
+	return RiskRules{Rules: rules.Rules(), Decisions: rules.Decisions()}
+}
+
+// SetRiskRuleEnabled switches a custom risk rule on or off for every instance.
+func (m *Manager) SetRiskRuleEnabled(name string, enabled bool) (risk.RuleStatus, error) {
+	status, ok := m.riskManager.RuleSet().SetEnabled(name, enabled)
+	if !ok {
+		return status, fmt.Errorf("%w: %s", ErrRiskRuleNotFound, name)
+	}
+	return status, nil
+}
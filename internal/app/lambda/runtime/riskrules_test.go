@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+type denySymbolRule struct{ symbol string }
+
+func (denySymbolRule) Name() string { return "deny-symbol" }
+
+func (r denySymbolRule) Evaluate(_ context.Context, order schema.OrderRequest, _ risk.RuleState) risk.Decision {
+	if order.Symbol == r.symbol {
+		return risk.Deny("symbol blocked", nil)
+	}
+	return risk.Allow()
+}
+
+func TestManagerRiskRulesApplyToPinnedInstances(t *testing.T) {
+	mgr := newTestManager(t)
+	WithRiskRule(denySymbolRule{symbol: "BTC-USDT"})(mgr)
+	if err := mgr.RegisterRiskRule(denySymbolRule{symbol: "ETH-USDT"}); err == nil {
+		t.Fatal("expected duplicate rule registration to fail")
+	}
+
+	pinned := mgr.instanceRiskManager("alpha", map[string]any{RiskProfileConfigKey: RiskProfileAggressive})
+	if pinned == mgr.riskManager || pinned.RuleSet() != mgr.riskManager.RuleSet() {
+		t.Fatal("expected the pinned manager to share the registered rules")
+	}
+
+	status, err := mgr.SetRiskRuleEnabled("deny-symbol", false)
+	if err != nil || status.Enabled {
+		t.Fatalf("SetRiskRuleEnabled = %+v, %v", status, err)
+	}
+	if rules := mgr.RiskRules(); len(rules.Rules) != 1 || rules.Rules[0].Enabled {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+	if _, err := mgr.SetRiskRuleEnabled("missing", true); !errors.Is(err, ErrRiskRuleNotFound) {
+		t.Fatalf("expected ErrRiskRuleNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// cancelRestingOrder cancels another instance's resting order on behalf of the cancel-resting
+// self-trade prevention policy.
+func (m *Manager) cancelRestingOrder(ctx context.Context, providerName, symbol, clientOrderID string) error {
+	if m.providers == nil {
+		return fmt.Errorf("provider catalog not configured")
+	}
+	name := strings.TrimSpace(providerName)
+	router := &providerOrderRouter{catalog: m.providers, normalization: m.orderNormalization}
+	if err := router.CancelOrder(ctx, name, symbol, clientOrderID); err != nil {
+		return fmt.Errorf("cancel resting order %s: %w", clientOrderID, err)
+	}
+	m.logger.Printf("self-trade prevention: cancelled resting order %s on %s/%s", clientOrderID, name, symbol)
+	return nil
+}
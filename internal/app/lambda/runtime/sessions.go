@@ -0,0 +1,48 @@
+package runtime
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/session"
+)
+
+// SessionProviders lists the providers traded by running instances.
+func (m *Manager) SessionProviders() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	seen := make(map[string]struct{})
+	for id := range m.instances {
+		for _, provider := range m.specs[id].Providers {
+			seen[provider] = struct{}{}
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for provider := range seen {
+		out = append(out, provider)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// CloseSessions reports the end-of-session positions and PnL of the running instances trading
+// provider and starts their next session.
+func (m *Manager) CloseSessions(provider string) []session.InstanceSummary {
+	provider = strings.TrimSpace(provider)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]session.InstanceSummary, 0)
+	for id, inst := range m.instances {
+		if inst == nil || inst.base == nil || !slices.Contains(m.specs[id].Providers, provider) {
+			continue
+		}
+		out = append(out, session.InstanceSummary{
+			ID:        id,
+			Labels:    cloneLabels(m.specs[id].Labels),
+			Positions: inst.base.CloseSession(provider),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
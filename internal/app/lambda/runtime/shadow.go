@@ -0,0 +1,507 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+var (
+	// ErrShadowExists indicates the instance already has a shadow running.
+	ErrShadowExists = errors.New("shadow instance already running")
+	// ErrShadowNotFound indicates the instance has no shadow running.
+	ErrShadowNotFound = errors.New("shadow instance not found")
+)
+
+const (
+	shadowIDSuffix           = "-shadow"
+	defaultShadowMatchWindow = 5 * time.Second
+	// maxShadowOrders bounds the orders recorded per side of a shadow run; later orders are
+	// counted but not compared.
+	maxShadowOrders = 10000
+	// shadowReportSamples bounds the unmatched orders listed per side of a report.
+	shadowReportSamples = 50
+)
+
+// ShadowOptions configures a shadow run.
+type ShadowOptions struct {
+	// Candidate selects the revision the shadow runs: name:tag or name@hash of the live strategy.
+	Candidate string
+	// MatchWindow is how far apart a live and a shadow order may be placed and still match.
+	MatchWindow time.Duration
+	// PriceToleranceBps is how far apart, in basis points of the live price, the limit prices of
+	// matching orders may be.
+	PriceToleranceBps float64
+}
+
+// ShadowRevision identifies the strategy revision one side of a shadow run executes.
+type ShadowRevision struct {
+	Strategy string `json:"strategy"`
+	Hash     string `json:"hash,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+}
+
+// ShadowOrder is an order one side of a shadow run placed without a counterpart on the other.
+type ShadowOrder struct {
+	At       time.Time `json:"at"`
+	Provider string    `json:"provider"`
+	Symbol   string    `json:"symbol"`
+	Side     string    `json:"side"`
+	Type     string    `json:"type"`
+	Quantity string    `json:"quantity"`
+	Price    string    `json:"price,omitempty"`
+}
+
+// ShadowSymbolDivergence compares the net quantity, buys minus sells, each side ordered on a symbol.
+type ShadowSymbolDivergence struct {
+	Provider  string `json:"provider"`
+	Symbol    string `json:"symbol"`
+	LiveNet   string `json:"liveNet"`
+	ShadowNet string `json:"shadowNet"`
+}
+
+// ShadowReport compares the orders a live instance placed with those its shadow would have placed
+// over the same events.
+type ShadowReport struct {
+	Instance          string         `json:"instance"`
+	Shadow            string         `json:"shadow"`
+	Live              ShadowRevision `json:"live"`
+	Candidate         ShadowRevision `json:"candidate"`
+	MatchWindow       string         `json:"matchWindow"`
+	PriceToleranceBps float64        `json:"priceToleranceBps"`
+	StartedAt         time.Time      `json:"startedAt"`
+	GeneratedAt       time.Time      `json:"generatedAt"`
+	Running           bool           `json:"running"`
+	LiveOrders        int            `json:"liveOrders"`
+	ShadowOrders      int            `json:"shadowOrders"`
+	Matched           int            `json:"matched"`
+	// MatchRate is matched orders over the larger side; 1 when neither side ordered.
+	MatchRate float64 `json:"matchRate"`
+	// Truncated reports that a side placed more than maxShadowOrders orders and only the first
+	// ones were compared.
+	Truncated  bool                     `json:"truncated,omitempty"`
+	LiveOnly   []ShadowOrder            `json:"liveOnly,omitempty"`
+	ShadowOnly []ShadowOrder            `json:"shadowOnly,omitempty"`
+	Symbols    []ShadowSymbolDivergence `json:"symbols,omitempty"`
+}
+
+// shadowRun is a dry-run instance of a candidate revision following a live instance.
+type shadowRun struct {
+	id        string
+	liveID    string
+	live      ShadowRevision
+	candidate ShadowRevision
+	opts      ShadowOptions
+	startedAt time.Time
+	base      *core.BaseLambda
+	cancel    context.CancelFunc
+	strat     core.TradingStrategy
+	routed    bool
+
+	mu           sync.Mutex
+	running      bool
+	liveOrders   []core.OrderIntent
+	shadowOrders []core.OrderIntent
+	liveTotal    int
+	shadowTotal  int
+}
+
+// StartShadow runs the candidate revision next to the running instance id. The shadow receives
+// the same events with trading forced to dry-run, and the orders both place are recorded for
+// ShadowReport. Shadows are not persisted and stop with the gateway.
+func (m *Manager) StartShadow(ctx context.Context, id string, opts ShadowOptions) error {
+	if m == nil {
+		return fmt.Errorf("strategy manager unavailable")
+	}
+	spec, err := m.specForID(id)
+	if err != nil {
+		return err
+	}
+	m.mu.RLock()
+	live, running := m.instances[spec.ID]
+	_, taken := m.specs[spec.ID+shadowIDSuffix]
+	m.mu.RUnlock()
+	if !running || live.base == nil {
+		return ErrInstanceNotRunning
+	}
+	if taken {
+		return fmt.Errorf("shadow id %s%s: %w", spec.ID, shadowIDSuffix, ErrInstanceExists)
+	}
+	if opts.MatchWindow <= 0 {
+		opts.MatchWindow = defaultShadowMatchWindow
+	}
+	if opts.PriceToleranceBps < 0 {
+		return fmt.Errorf("price tolerance must not be negative")
+	}
+	shadowSpec, err := m.shadowSpec(spec, opts.Candidate)
+	if err != nil {
+		return err
+	}
+
+	m.shadowsMu.Lock()
+	defer m.shadowsMu.Unlock()
+	if _, exists := m.shadows[spec.ID]; exists {
+		return ErrShadowExists
+	}
+	run, err := m.launchShadow(ctx, shadowSpec)
+	if err != nil {
+		return err
+	}
+	run.liveID = spec.ID
+	run.live = ShadowRevision{Strategy: spec.Strategy.Identifier, Hash: spec.Strategy.Hash, Tag: spec.Strategy.Tag}
+	run.opts = opts
+	m.shadows[spec.ID] = run
+	live.base.SetOrderIntentObserver(run.recordLive)
+	if m.logger != nil {
+		m.logger.Printf("strategy instance %s: shadow %s started on %s%s", spec.ID, run.id, shadowSpec.Strategy.Selector, requestIDLogSuffix(telemetry.RequestIDFromContext(ctx)))
+	}
+	return nil
+}
+
+// ShadowReport compares the orders placed so far by instance id and its shadow.
+func (m *Manager) ShadowReport(id string) (ShadowReport, error) {
+	var empty ShadowReport
+	if m == nil {
+		return empty, fmt.Errorf("strategy manager unavailable")
+	}
+	m.shadowsMu.Lock()
+	run, ok := m.shadows[strings.TrimSpace(id)]
+	m.shadowsMu.Unlock()
+	if !ok {
+		return empty, ErrShadowNotFound
+	}
+	return run.report(m.clock().UTC()), nil
+}
+
+// StopShadow stops the shadow of instance id and returns its final report.
+func (m *Manager) StopShadow(id string) (ShadowReport, error) {
+	var empty ShadowReport
+	if m == nil {
+		return empty, fmt.Errorf("strategy manager unavailable")
+	}
+	id = strings.TrimSpace(id)
+	m.shadowsMu.Lock()
+	run, ok := m.shadows[id]
+	delete(m.shadows, id)
+	m.shadowsMu.Unlock()
+	if !ok {
+		return empty, ErrShadowNotFound
+	}
+
+	m.mu.RLock()
+	live, running := m.instances[id]
+	m.mu.RUnlock()
+	if running && live.base != nil {
+		live.base.SetOrderIntentObserver(nil)
+	}
+	run.base.SetOrderIntentObserver(nil)
+	run.cancel()
+	if run.routed && m.registrar != nil {
+		_ = m.registrar.UnregisterLambda(context.Background(), run.id)
+	}
+	closeStrategy(run.strat)
+	run.mu.Lock()
+	run.running = false
+	run.mu.Unlock()
+	if m.logger != nil {
+		m.logger.Printf("strategy instance %s: shadow %s stopped", id, run.id)
+	}
+	return run.report(m.clock().UTC()), nil
+}
+
+// attachShadowObserver reconnects a restarted live instance to its running shadow.
+func (m *Manager) attachShadowObserver(id string, base *core.BaseLambda) {
+	m.shadowsMu.Lock()
+	run, ok := m.shadows[id]
+	m.shadowsMu.Unlock()
+	if ok {
+		base.SetOrderIntentObserver(run.recordLive)
+	}
+}
+
+// shadowSpec derives the shadow instance spec: the live spec pinned to the candidate revision of
+// the same strategy, with trading disabled.
+func (m *Manager) shadowSpec(spec config.LambdaSpec, candidate string) (config.LambdaSpec, error) {
+	var empty config.LambdaSpec
+	candidate = strings.TrimSpace(candidate)
+	if candidate == "" {
+		return empty, fmt.Errorf("candidate revision required")
+	}
+	if m.jsLoader == nil {
+		return empty, fmt.Errorf("strategy loader unavailable")
+	}
+	res, err := m.jsLoader.ResolveReference(candidate)
+	if err != nil {
+		return empty, fmt.Errorf("resolve candidate %q: %w", candidate, err)
+	}
+	if !strings.EqualFold(res.Name, spec.Strategy.Identifier) {
+		return empty, fmt.Errorf("candidate %q is not a revision of strategy %s", candidate, spec.Strategy.Identifier)
+	}
+	shadow := cloneSpec(spec)
+	shadow.ID = spec.ID + shadowIDSuffix
+	shadow.Strategy.Identifier = res.Name
+	shadow.Strategy.Hash = res.Hash
+	shadow.Strategy.Tag = res.Tag
+	shadow.Strategy.Selector = canonicalSelector(candidate, res)
+	dryRun := true
+	shadow.DryRun = &dryRun
+	if err := m.checkCapabilities(shadow.Strategy); err != nil {
+		return empty, err
+	}
+	return shadow, nil
+}
+
+// launchShadow starts the shadow lambda. Unlike launch it keeps the instance out of the instance
+// table, and without an order router, risk manager or order store the shadow cannot trade.
+func (m *Manager) launchShadow(ctx context.Context, spec config.LambdaSpec) (*shadowRun, error) {
+	providers, err := m.resolveProviders(spec)
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := m.buildStrategy(spec.Strategy)
+	if err != nil {
+		return nil, fmt.Errorf("shadow %s: %w", spec.ID, err)
+	}
+	if strategy != nil && len(providers) > 1 && !strategy.WantsCrossProviderEvents() {
+		closeStrategy(strategy)
+		return nil, fmt.Errorf("strategy %s does not support cross-provider feeds", spec.Strategy.Identifier)
+	}
+	routes := buildRouteDeclarations(strategy, m.routeSpec(spec))
+	routed := false
+	if m.registrar != nil && len(routes) > 0 {
+		if err := m.registrar.RegisterLambda(ctx, spec.ID, providers, routes); err != nil {
+			closeStrategy(strategy)
+			return nil, fmt.Errorf("shadow %s: register routes: %w", spec.ID, err)
+		}
+		routed = true
+	}
+
+	base := core.NewBaseLambda(spec.ID, m.baseConfig(spec, providers), m.bus, nil, m.pools, strategy, nil, nil)
+	bindStrategy(strategy, base, m.logger)
+	run := &shadowRun{
+		id:           spec.ID,
+		liveID:       "",
+		live:         ShadowRevision{Strategy: "", Hash: "", Tag: ""},
+		candidate:    ShadowRevision{Strategy: spec.Strategy.Identifier, Hash: spec.Strategy.Hash, Tag: spec.Strategy.Tag},
+		opts:         ShadowOptions{Candidate: spec.Strategy.Selector, MatchWindow: 0, PriceToleranceBps: 0},
+		startedAt:    m.clock().UTC(),
+		base:         base,
+		cancel:       nil,
+		strat:        strategy,
+		routed:       routed,
+		mu:           sync.Mutex{},
+		running:      true,
+		liveOrders:   nil,
+		shadowOrders: nil,
+		liveTotal:    0,
+		shadowTotal:  0,
+	}
+	base.SetOrderIntentObserver(run.recordShadow)
+
+	runCtx, cancel := context.WithCancel(m.parentContext())
+	errs, err := base.Start(runCtx)
+	if err != nil {
+		cancel()
+		if routed {
+			_ = m.registrar.UnregisterLambda(ctx, spec.ID)
+		}
+		closeStrategy(strategy)
+		return nil, fmt.Errorf("start shadow %s: %w", spec.ID, err)
+	}
+	run.cancel = cancel
+	go m.observe(runCtx, spec, errs, strategy)
+	return run, nil
+}
+
+func (r *shadowRun) recordLive(intent core.OrderIntent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.liveTotal++
+	if len(r.liveOrders) < maxShadowOrders {
+		r.liveOrders = append(r.liveOrders, intent)
+	}
+}
+
+func (r *shadowRun) recordShadow(intent core.OrderIntent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shadowTotal++
+	if len(r.shadowOrders) < maxShadowOrders {
+		r.shadowOrders = append(r.shadowOrders, intent)
+	}
+}
+
+// report pairs each live order with the earliest unmatched shadow order on the same provider,
+// symbol, side and type with an equal quantity, a limit price within the tolerance and a
+// placement time within the match window.
+func (r *shadowRun) report(now time.Time) ShadowReport {
+	r.mu.Lock()
+	live := append([]core.OrderIntent(nil), r.liveOrders...)
+	shadow := append([]core.OrderIntent(nil), r.shadowOrders...)
+	liveTotal, shadowTotal, running := r.liveTotal, r.shadowTotal, r.running
+	r.mu.Unlock()
+
+	matchedShadow := make([]bool, len(shadow))
+	var liveOnly []core.OrderIntent
+	matched := 0
+	for _, order := range live {
+		found := false
+		for i, candidate := range shadow {
+			if matchedShadow[i] || !r.matches(order, candidate) {
+				continue
+			}
+			matchedShadow[i] = true
+			found = true
+			matched++
+			break
+		}
+		if !found {
+			liveOnly = append(liveOnly, order)
+		}
+	}
+	var shadowOnly []core.OrderIntent
+	for i, order := range shadow {
+		if !matchedShadow[i] {
+			shadowOnly = append(shadowOnly, order)
+		}
+	}
+
+	rate := 1.0
+	if larger := max(len(live), len(shadow)); larger > 0 {
+		rate = float64(matched) / float64(larger)
+	}
+	return ShadowReport{
+		Instance:          r.liveID,
+		Shadow:            r.id,
+		Live:              r.live,
+		Candidate:         r.candidate,
+		MatchWindow:       r.opts.MatchWindow.String(),
+		PriceToleranceBps: r.opts.PriceToleranceBps,
+		StartedAt:         r.startedAt,
+		GeneratedAt:       now,
+		Running:           running,
+		LiveOrders:        liveTotal,
+		ShadowOrders:      shadowTotal,
+		Matched:           matched,
+		MatchRate:         rate,
+		Truncated:         liveTotal > len(live) || shadowTotal > len(shadow),
+		LiveOnly:          shadowOrderSamples(liveOnly),
+		ShadowOnly:        shadowOrderSamples(shadowOnly),
+		Symbols:           shadowSymbolDivergence(live, shadow),
+	}
+}
+
+func (r *shadowRun) matches(live, shadow core.OrderIntent) bool {
+	if live.Provider != shadow.Provider || live.Symbol != shadow.Symbol || live.Side != shadow.Side || live.Type != shadow.Type {
+		return false
+	}
+	if gap := live.At.Sub(shadow.At); gap > r.opts.MatchWindow || -gap > r.opts.MatchWindow {
+		return false
+	}
+	liveQty, err := decimal.NewFromString(live.Quantity)
+	if err != nil {
+		return live.Quantity == shadow.Quantity
+	}
+	shadowQty, err := decimal.NewFromString(shadow.Quantity)
+	if err != nil || !liveQty.Equal(shadowQty) {
+		return false
+	}
+	if live.Price == "" || shadow.Price == "" {
+		return live.Price == shadow.Price
+	}
+	livePrice, err := decimal.NewFromString(live.Price)
+	if err != nil {
+		return live.Price == shadow.Price
+	}
+	shadowPrice, err := decimal.NewFromString(shadow.Price)
+	if err != nil {
+		return false
+	}
+	if livePrice.IsZero() {
+		return shadowPrice.IsZero()
+	}
+	bps := shadowPrice.Sub(livePrice).Abs().Div(livePrice.Abs()).Mul(decimal.NewFromInt(10000))
+	return bps.LessThanOrEqual(decimal.NewFromFloat(r.opts.PriceToleranceBps))
+}
+
+// shadowOrderSamples lists the most recent unmatched orders.
+func shadowOrderSamples(orders []core.OrderIntent) []ShadowOrder {
+	if len(orders) > shadowReportSamples {
+		orders = orders[len(orders)-shadowReportSamples:]
+	}
+	if len(orders) == 0 {
+		return nil
+	}
+	out := make([]ShadowOrder, 0, len(orders))
+	for _, order := range orders {
+		out = append(out, ShadowOrder{
+			At:       order.At,
+			Provider: order.Provider,
+			Symbol:   order.Symbol,
+			Side:     string(order.Side),
+			Type:     string(order.Type),
+			Quantity: order.Quantity,
+			Price:    order.Price,
+		})
+	}
+	return out
+}
+
+// shadowSymbolDivergence lists the symbols on which the two sides ordered different net quantities.
+func shadowSymbolDivergence(live, shadow []core.OrderIntent) []ShadowSymbolDivergence {
+	type key struct{ provider, symbol string }
+	type nets struct{ live, shadow decimal.Decimal }
+	totals := make(map[key]*nets)
+	add := func(orders []core.OrderIntent, pick func(*nets) *decimal.Decimal) {
+		for _, order := range orders {
+			qty, err := decimal.NewFromString(order.Quantity)
+			if err != nil {
+				continue
+			}
+			if order.Side == schema.TradeSideSell {
+				qty = qty.Neg()
+			}
+			k := key{provider: order.Provider, symbol: order.Symbol}
+			entry, ok := totals[k]
+			if !ok {
+				entry = &nets{live: decimal.Zero, shadow: decimal.Zero}
+				totals[k] = entry
+			}
+			net := pick(entry)
+			*net = net.Add(qty)
+		}
+	}
+	add(live, func(n *nets) *decimal.Decimal { return &n.live })
+	add(shadow, func(n *nets) *decimal.Decimal { return &n.shadow })
+
+	var out []ShadowSymbolDivergence
+	for k, entry := range totals {
+		if entry.live.Equal(entry.shadow) {
+			continue
+		}
+		out = append(out, ShadowSymbolDivergence{
+			Provider:  k.provider,
+			Symbol:    k.symbol,
+			LiveNet:   entry.live.String(),
+			ShadowNet: entry.shadow.String(),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].Symbol < out[j].Symbol
+	})
+	return out
+}
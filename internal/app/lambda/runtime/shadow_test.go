@@ -0,0 +1,107 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestShadowReportMatchesOrders(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	run := &shadowRun{
+		id:     "alpha-shadow",
+		liveID: "alpha",
+		opts:   ShadowOptions{MatchWindow: 2 * time.Second, PriceToleranceBps: 10},
+	}
+	intent := func(offset time.Duration, side schema.TradeSide, qty, price string) core.OrderIntent {
+		orderType := schema.OrderTypeLimit
+		if price == "" {
+			orderType = schema.OrderTypeMarket
+		}
+		return core.OrderIntent{
+			At:       start.Add(offset),
+			Provider: "binance",
+			Symbol:   "BTC-USDT",
+			Side:     side,
+			Type:     orderType,
+			Quantity: qty,
+			Price:    price,
+		}
+	}
+
+	// Matched: same order a second apart, prices 5 bps apart.
+	run.recordLive(intent(0, schema.TradeSideBuy, "1", "100.00"))
+	run.recordShadow(intent(time.Second, schema.TradeSideBuy, "1.0", "100.05"))
+	// Matched market orders.
+	run.recordLive(intent(10*time.Second, schema.TradeSideSell, "0.5", ""))
+	run.recordShadow(intent(11*time.Second, schema.TradeSideSell, "0.5", ""))
+	// Price outside tolerance.
+	run.recordLive(intent(20*time.Second, schema.TradeSideBuy, "1", "100"))
+	run.recordShadow(intent(20*time.Second, schema.TradeSideBuy, "1", "101"))
+	// Outside the match window.
+	run.recordShadow(intent(40*time.Second, schema.TradeSideSell, "2", ""))
+	run.recordLive(intent(30*time.Second, schema.TradeSideSell, "2", ""))
+
+	report := run.report(start.Add(time.Minute))
+	if report.LiveOrders != 4 || report.ShadowOrders != 4 || report.Matched != 2 {
+		t.Fatalf("unexpected counts %+v", report)
+	}
+	if report.MatchRate != 0.5 {
+		t.Fatalf("expected match rate 0.5, got %v", report.MatchRate)
+	}
+	if len(report.LiveOnly) != 2 || len(report.ShadowOnly) != 2 {
+		t.Fatalf("expected two unmatched orders per side, got %+v %+v", report.LiveOnly, report.ShadowOnly)
+	}
+	if report.ShadowOnly[0].Price != "101" {
+		t.Fatalf("expected unmatched shadow orders in placement order, got %+v", report.ShadowOnly)
+	}
+	// Both sides net +1 - 0.5 + 1 - 2 = -0.5, so no symbol diverges.
+	if len(report.Symbols) != 0 {
+		t.Fatalf("expected no net divergence, got %+v", report.Symbols)
+	}
+
+	run.recordShadow(intent(50*time.Second, schema.TradeSideBuy, "3", ""))
+	report = run.report(start.Add(time.Minute))
+	if len(report.Symbols) != 1 || report.Symbols[0].LiveNet != "-0.5" || report.Symbols[0].ShadowNet != "2.5" {
+		t.Fatalf("unexpected divergence %+v", report.Symbols)
+	}
+}
+
+func TestShadowReportWithoutOrders(t *testing.T) {
+	run := &shadowRun{opts: ShadowOptions{MatchWindow: time.Second}}
+	if report := run.report(time.Now()); report.MatchRate != 1 || report.Matched != 0 {
+		t.Fatalf("expected an empty run to match fully, got %+v", report)
+	}
+}
+
+func TestStartShadowRequiresRunningInstance(t *testing.T) {
+	mgr := newTestManager(t)
+	spec := baseLambdaSpec()
+	if err := mgr.StartShadow(context.Background(), spec.ID, ShadowOptions{Candidate: "noop:latest"}); !errors.Is(err, ErrInstanceNotFound) {
+		t.Fatalf("expected unknown instance rejected, got %v", err)
+	}
+	if _, err := mgr.Create(spec); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := mgr.StartShadow(context.Background(), spec.ID, ShadowOptions{Candidate: "noop:latest"}); !errors.Is(err, ErrInstanceNotRunning) {
+		t.Fatalf("expected stopped instance rejected, got %v", err)
+	}
+
+	base := core.NewBaseLambda(spec.ID, core.Config{Providers: spec.Providers, DryRun: true}, nil, nil, nil, nil, nil, nil)
+	mgr.mu.Lock()
+	mgr.instances[spec.ID] = &lambdaInstance{base: base}
+	mgr.mu.Unlock()
+	if err := mgr.StartShadow(context.Background(), spec.ID, ShadowOptions{}); err == nil {
+		t.Fatal("expected candidate revision required")
+	}
+	if _, err := mgr.ShadowReport(spec.ID); !errors.Is(err, ErrShadowNotFound) {
+		t.Fatalf("expected no shadow, got %v", err)
+	}
+	if _, err := mgr.StopShadow(spec.ID); !errors.Is(err, ErrShadowNotFound) {
+		t.Fatalf("expected no shadow to stop, got %v", err)
+	}
+}
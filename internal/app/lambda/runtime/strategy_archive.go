@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/domain/strategystore"
+)
+
+// ErrArchiveRevisionInUse reports a replacing import that would unregister revisions instances use.
+var ErrArchiveRevisionInUse = errors.New("strategy archive: revisions in use")
+
+// ExportStrategyArchive writes the strategy registry and every registered revision to w as a
+// signed tarball.
+func (m *Manager) ExportStrategyArchive(w io.Writer) (js.ArchiveManifest, error) {
+	if m == nil || m.jsLoader == nil {
+		var empty js.ArchiveManifest
+		return empty, fmt.Errorf("strategy loader unavailable")
+	}
+	manifest, err := m.jsLoader.ExportArchive(w, m.archiveKey, m.clock())
+	if err != nil {
+		return manifest, fmt.Errorf("export strategy archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// ImportStrategyArchive verifies an archive and merges it into the local registry under policy.
+// With dryRun the planned changes are reported without writing anything. Otherwise the registry
+// is swapped in one step and strategies are refreshed, restarting instances whose revision moved.
+// Conflicting tags under the fail policy are reported in the result alongside
+// js.ErrArchiveConflict.
+func (m *Manager) ImportStrategyArchive(ctx context.Context, r io.Reader, policy js.ArchiveConflictPolicy, dryRun bool, change StrategyChange) (js.ArchiveImportResult, error) {
+	var empty js.ArchiveImportResult
+	if m == nil || m.jsLoader == nil {
+		return empty, fmt.Errorf("strategy loader unavailable")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	plan, err := m.jsLoader.PlanArchiveImport(r, m.archiveKey, policy)
+	if err != nil {
+		return empty, fmt.Errorf("import strategy archive: %w", err)
+	}
+	if inUse := m.revisionsInUse(plan.Result.RemovedRevisions); len(inUse) > 0 {
+		return plan.Result, fmt.Errorf("import strategy archive: %w: %s", ErrArchiveRevisionInUse, strings.Join(inUse, ", "))
+	}
+	if dryRun {
+		return plan.Result, nil
+	}
+	if err := m.jsLoader.ApplyArchiveImport(plan); err != nil {
+		return plan.Result, fmt.Errorf("import strategy archive: %w", err)
+	}
+	if m.logger != nil {
+		m.logger.Printf("strategy archive imported (%s): %d revision(s) added, %d tag(s) moved%s",
+			policy, len(plan.Result.AddedRevisions), len(plan.Result.TagChanges), change.logSuffix())
+	}
+	metadata := map[string]any{"policy": string(policy), "archiveCreatedAt": plan.Result.CreatedAt.Format(time.RFC3339)}
+	for _, revision := range plan.Result.AddedRevisions {
+		name, hash, _ := strings.Cut(revision, "@")
+		m.recordStrategyHistory(strategystore.HistoryEntry{
+			ID:           0,
+			Strategy:     name,
+			Action:       strategystore.HistoryActionImport,
+			Hash:         hash,
+			Tag:          "",
+			PreviousHash: "",
+			Actor:        "",
+			Reason:       "",
+			Metadata:     metadata,
+			RecordedAt:   time.Time{},
+		}, change)
+	}
+	for _, moved := range plan.Result.TagChanges {
+		m.recordStrategyHistory(strategystore.HistoryEntry{
+			ID:           0,
+			Strategy:     moved.Strategy,
+			Action:       strategystore.HistoryActionTagAssigned,
+			Hash:         moved.Hash,
+			Tag:          moved.Tag,
+			PreviousHash: moved.Previous,
+			Actor:        "",
+			Reason:       "",
+			Metadata:     metadata,
+			RecordedAt:   time.Time{},
+		}, change)
+	}
+	if err := m.RefreshJavaScriptStrategies(ctx); err != nil {
+		return plan.Result, fmt.Errorf("refresh after archive import: %w", err)
+	}
+	return plan.Result, nil
+}
+
+// revisionsInUse returns the name@hash revisions that an instance is configured with.
+func (m *Manager) revisionsInUse(revisions []string) []string {
+	if len(revisions) == 0 {
+		return nil
+	}
+	used := make(map[string]struct{})
+	for _, usage := range m.RevisionUsageSnapshot() {
+		if usage.Count > 0 {
+			used[normalizeStrategyName(usage.Strategy)+"@"+normalizeRevisionHash(usage.Hash)] = struct{}{}
+		}
+	}
+	var out []string
+	for _, revision := range revisions {
+		if _, ok := used[revision]; ok {
+			out = append(out, revision)
+		}
+	}
+	return out
+}
@@ -0,0 +1,427 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/domain/strategyreportstore"
+)
+
+// ErrStrategyReportNotFound is returned when no strategy report has the requested identifier.
+var ErrStrategyReportNotFound = errors.New("strategy report not found")
+
+const (
+	defaultStrategyReportLimit = 30
+	maxInMemoryStrategyReports = 30
+	strategyReportHashWidth    = 12
+)
+
+// StrategyReport combines revision usage, the registry and the instances of every strategy into
+// one document, so operators can review what runs and which revisions can be retired.
+type StrategyReport struct {
+	ID          int64                 `json:"id"`
+	GeneratedAt time.Time             `json:"generatedAt"`
+	Totals      StrategyReportTotals  `json:"totals"`
+	Strategies  []StrategyReportEntry `json:"strategies"`
+	// Unused lists revisions no instance references, oldest use first.
+	Unused   []StrategyReportRevision `json:"unusedRevisions"`
+	Usage    []RevisionUsageSummary   `json:"usage"`
+	Registry js.RegistrySnapshot      `json:"registry,omitempty"`
+}
+
+// StrategyReportTotals counts the strategies, revisions and instances in a report.
+type StrategyReportTotals struct {
+	Strategies       int `json:"strategies"`
+	Revisions        int `json:"revisions"`
+	UnusedRevisions  int `json:"unusedRevisions"`
+	Instances        int `json:"instances"`
+	RunningInstances int `json:"runningInstances"`
+}
+
+// StrategyReportEntry describes one strategy, its revisions and the instances running it.
+type StrategyReportEntry struct {
+	Strategy    string                   `json:"strategy"`
+	DisplayName string                   `json:"displayName,omitempty"`
+	Tags        map[string]string        `json:"tags,omitempty"`
+	Revisions   []StrategyReportRevision `json:"revisions"`
+	Instances   []string                 `json:"instances"`
+	Running     int                      `json:"running"`
+}
+
+// StrategyReportRevision describes one revision and the instances pinned to it.
+type StrategyReportRevision struct {
+	Strategy  string   `json:"strategy"`
+	Hash      string   `json:"hash"`
+	Tags      []string `json:"tags,omitempty"`
+	Retired   bool     `json:"retired,omitempty"`
+	Instances []string `json:"instances"`
+	Running   int      `json:"running"`
+	// LastSeen is when an instance last used the revision; nil when none ever did.
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+// StrategyReportSummary identifies a stored report in listings.
+type StrategyReportSummary struct {
+	ID          int64     `json:"id"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Summary     string    `json:"summary"`
+}
+
+// WithStrategyReportStore persists generated strategy reports. Without a store the most recent
+// reports are kept in memory.
+func WithStrategyReportStore(store strategyreportstore.Store) Option {
+	return func(m *Manager) {
+		m.reportStore = store
+	}
+}
+
+// Summary describes the report totals in one line.
+func (r StrategyReport) Summary() string {
+	return fmt.Sprintf("%d strategies, %d revisions (%d unused), %d instances (%d running)",
+		r.Totals.Strategies, r.Totals.Revisions, r.Totals.UnusedRevisions, r.Totals.Instances, r.Totals.RunningInstances)
+}
+
+// Text renders the report for people: one block per strategy followed by the unused revisions.
+func (r StrategyReport) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Strategy report generated %s\n", r.GeneratedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "%s\n", r.Summary())
+	for _, entry := range r.Strategies {
+		b.WriteString("\n")
+		b.WriteString(entry.Strategy)
+		if entry.DisplayName != "" && !strings.EqualFold(entry.DisplayName, entry.Strategy) {
+			fmt.Fprintf(&b, " (%s)", entry.DisplayName)
+		}
+		fmt.Fprintf(&b, ": %d instance(s), %d running\n", len(entry.Instances), entry.Running)
+		for _, rev := range entry.Revisions {
+			fmt.Fprintf(&b, "  %-*s", strategyReportHashWidth, shortReportHash(rev.Hash))
+			if len(rev.Tags) > 0 {
+				fmt.Fprintf(&b, " [%s]", strings.Join(rev.Tags, ", "))
+			}
+			if rev.Retired {
+				b.WriteString(" retired")
+			}
+			switch {
+			case len(rev.Instances) > 0:
+				fmt.Fprintf(&b, " instances: %s (%d running)", strings.Join(rev.Instances, ", "), rev.Running)
+			case rev.LastSeen != nil:
+				fmt.Fprintf(&b, " unused since %s", rev.LastSeen.UTC().Format(time.RFC3339))
+			default:
+				b.WriteString(" never used")
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(r.Unused) > 0 {
+		b.WriteString("\nUnused revisions:\n")
+		for _, rev := range r.Unused {
+			fmt.Fprintf(&b, "  %s@%s", rev.Strategy, shortReportHash(rev.Hash))
+			if len(rev.Tags) > 0 {
+				fmt.Fprintf(&b, " [%s]", strings.Join(rev.Tags, ", "))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func shortReportHash(hash string) string {
+	trimmed := strings.TrimPrefix(hash, "sha256:")
+	if trimmed == "" {
+		return "-"
+	}
+	if len(trimmed) > strategyReportHashWidth {
+		return trimmed[:strategyReportHashWidth]
+	}
+	return trimmed
+}
+
+// BuildStrategyReport assembles a report from the loaded modules, the registry, revision usage and
+// the instances without storing it.
+func (m *Manager) BuildStrategyReport() (StrategyReport, error) {
+	var empty StrategyReport
+	if m == nil || m.jsLoader == nil {
+		return empty, fmt.Errorf("strategy loader unavailable")
+	}
+	registry, err := m.jsLoader.RegistrySnapshot()
+	if err != nil {
+		return empty, fmt.Errorf("registry snapshot: %w", err)
+	}
+	usage := m.RevisionUsageSnapshot()
+	report := StrategyReport{
+		ID:          0,
+		GeneratedAt: m.clock().UTC(),
+		Totals:      StrategyReportTotals{Strategies: 0, Revisions: 0, UnusedRevisions: 0, Instances: 0, RunningInstances: 0},
+		Strategies:  nil,
+		Unused:      []StrategyReportRevision{},
+		Usage:       usage,
+		Registry:    registry,
+	}
+
+	lastSeen := make(map[string]time.Time, len(usage))
+	for _, summary := range usage {
+		lastSeen[buildRevisionKey(summary.Strategy, summary.Hash)] = summary.LastSeen
+	}
+	entries := make(map[string]*StrategyReportEntry)
+	// index locates each revision in the Revisions slice of its strategy entry.
+	index := make(map[string]int)
+	revision := func(entry *StrategyReportEntry, hash string) *StrategyReportRevision {
+		key := buildRevisionKey(entry.Strategy, hash)
+		if i, ok := index[key]; ok {
+			return &entry.Revisions[i]
+		}
+		rev := StrategyReportRevision{
+			Strategy:  entry.Strategy,
+			Hash:      hash,
+			Tags:      nil,
+			Retired:   false,
+			Instances: []string{},
+			Running:   0,
+			LastSeen:  nil,
+		}
+		if seen, ok := lastSeen[key]; ok && !seen.IsZero() {
+			rev.LastSeen = &seen
+		}
+		index[key] = len(entry.Revisions)
+		entry.Revisions = append(entry.Revisions, rev)
+		return &entry.Revisions[len(entry.Revisions)-1]
+	}
+	entryFor := func(name string) *StrategyReportEntry {
+		key := normalizeStrategyName(name)
+		if entry, ok := entries[key]; ok {
+			return entry
+		}
+		entry := &StrategyReportEntry{
+			Strategy:    key,
+			DisplayName: "",
+			Tags:        nil,
+			Revisions:   nil,
+			Instances:   []string{},
+			Running:     0,
+		}
+		entries[key] = entry
+		return entry
+	}
+
+	for _, module := range m.jsLoader.ListWithUsage(nil) {
+		entry := entryFor(module.Name)
+		entry.DisplayName = module.Metadata.DisplayName
+		entry.Tags = module.TagAliases
+		for _, moduleRev := range module.Revisions {
+			revision(entry, normalizeRevisionHash(moduleRev.Hash)).Retired = moduleRev.Retired
+		}
+		if len(module.Revisions) == 0 && module.Hash != "" {
+			revision(entry, normalizeRevisionHash(module.Hash))
+		}
+		for tag, hash := range module.TagAliases {
+			rev := revision(entry, normalizeRevisionHash(hash))
+			rev.Tags = append(rev.Tags, tag)
+		}
+	}
+	for _, inst := range m.Instances() {
+		entry := entryFor(inst.StrategyIdentifier)
+		rev := revision(entry, normalizeRevisionHash(inst.StrategyHash))
+		rev.Instances = append(rev.Instances, inst.ID)
+		entry.Instances = append(entry.Instances, inst.ID)
+		report.Totals.Instances++
+		if inst.Running {
+			rev.Running++
+			entry.Running++
+			report.Totals.RunningInstances++
+		}
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	report.Strategies = make([]StrategyReportEntry, 0, len(names))
+	for _, name := range names {
+		entry := entries[name]
+		sort.Strings(entry.Instances)
+		for i := range entry.Revisions {
+			rev := &entry.Revisions[i]
+			sort.Strings(rev.Tags)
+			sort.Strings(rev.Instances)
+			if len(rev.Instances) == 0 {
+				report.Unused = append(report.Unused, *rev)
+			}
+		}
+		sort.SliceStable(entry.Revisions, func(i, j int) bool {
+			return len(entry.Revisions[i].Instances) > len(entry.Revisions[j].Instances)
+		})
+		report.Totals.Revisions += len(entry.Revisions)
+		report.Strategies = append(report.Strategies, *entry)
+	}
+	sort.SliceStable(report.Unused, func(i, j int) bool {
+		a, b := report.Unused[i].LastSeen, report.Unused[j].LastSeen
+		switch {
+		case a == nil || b == nil:
+			return a == nil && b != nil
+		default:
+			return a.Before(*b)
+		}
+	})
+	report.Totals.Strategies = len(report.Strategies)
+	report.Totals.UnusedRevisions = len(report.Unused)
+	return report, nil
+}
+
+// GenerateStrategyReport builds a report and stores it.
+func (m *Manager) GenerateStrategyReport(ctx context.Context) (StrategyReport, error) {
+	report, err := m.BuildStrategyReport()
+	if err != nil {
+		return report, err
+	}
+	if m.reportStore != nil {
+		document, err := json.Marshal(report)
+		if err != nil {
+			return report, fmt.Errorf("encode strategy report: %w", err)
+		}
+		id, err := m.reportStore.SaveReport(ctx, strategyreportstore.Entry{
+			ID:          0,
+			GeneratedAt: report.GeneratedAt,
+			Summary:     report.Summary(),
+			Report:      document,
+		})
+		if err != nil {
+			return report, fmt.Errorf("save strategy report: %w", err)
+		}
+		report.ID = id
+		return report, nil
+	}
+	m.reportsMu.Lock()
+	defer m.reportsMu.Unlock()
+	m.reportSeq++
+	report.ID = m.reportSeq
+	m.reports = append(m.reports, report)
+	if len(m.reports) > maxInMemoryStrategyReports {
+		m.reports = append([]StrategyReport(nil), m.reports[len(m.reports)-maxInMemoryStrategyReports:]...)
+	}
+	return report, nil
+}
+
+// StrategyReports lists stored reports, newest first.
+func (m *Manager) StrategyReports(ctx context.Context, limit int) ([]StrategyReportSummary, error) {
+	if limit <= 0 {
+		limit = defaultStrategyReportLimit
+	}
+	if m.reportStore != nil {
+		entries, err := m.reportStore.ListReports(ctx, limit)
+		if err != nil {
+			return nil, fmt.Errorf("list strategy reports: %w", err)
+		}
+		out := make([]StrategyReportSummary, 0, len(entries))
+		for _, entry := range entries {
+			out = append(out, StrategyReportSummary{ID: entry.ID, GeneratedAt: entry.GeneratedAt, Summary: entry.Summary})
+		}
+		return out, nil
+	}
+	m.reportsMu.Lock()
+	defer m.reportsMu.Unlock()
+	out := make([]StrategyReportSummary, 0, min(limit, len(m.reports)))
+	for i := len(m.reports) - 1; i >= 0 && len(out) < limit; i-- {
+		report := m.reports[i]
+		out = append(out, StrategyReportSummary{ID: report.ID, GeneratedAt: report.GeneratedAt, Summary: report.Summary()})
+	}
+	return out, nil
+}
+
+// StrategyReport returns the stored report with the identifier, or the newest report when id is 0.
+func (m *Manager) StrategyReport(ctx context.Context, id int64) (StrategyReport, error) {
+	var empty StrategyReport
+	if m.reportStore != nil {
+		var (
+			entry strategyreportstore.Entry
+			err   error
+		)
+		if id == 0 {
+			entry, err = m.reportStore.LatestReport(ctx)
+		} else {
+			entry, err = m.reportStore.Report(ctx, id)
+		}
+		if errors.Is(err, strategyreportstore.ErrNotFound) {
+			return empty, strategyReportNotFound(id)
+		}
+		if err != nil {
+			return empty, fmt.Errorf("load strategy report: %w", err)
+		}
+		var report StrategyReport
+		if err := json.Unmarshal(entry.Report, &report); err != nil {
+			return empty, fmt.Errorf("decode strategy report %d: %w", entry.ID, err)
+		}
+		report.ID = entry.ID
+		return report, nil
+	}
+	m.reportsMu.Lock()
+	defer m.reportsMu.Unlock()
+	for i := len(m.reports) - 1; i >= 0; i-- {
+		if id == 0 || m.reports[i].ID == id {
+			return m.reports[i], nil
+		}
+	}
+	return empty, strategyReportNotFound(id)
+}
+
+func strategyReportNotFound(id int64) error {
+	if id == 0 {
+		return ErrStrategyReportNotFound
+	}
+	return fmt.Errorf("%w: %d", ErrStrategyReportNotFound, id)
+}
+
+// StartStrategyReports generates a report every configured interval and purges reports past the
+// retention until ctx is cancelled. A report is generated at once when the newest is older than
+// the interval, so restarts neither skip nor duplicate a period.
+func (m *Manager) StartStrategyReports(ctx context.Context) {
+	if m == nil || !m.reportsCfg.Enabled || m.reportsCfg.Interval <= 0 {
+		return
+	}
+	go func() {
+		if latest, err := m.StrategyReport(ctx, 0); err != nil || m.clock().Sub(latest.GeneratedAt) >= m.reportsCfg.Interval {
+			m.runStrategyReport(ctx)
+		}
+		ticker := time.NewTicker(m.reportsCfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.runStrategyReport(ctx)
+			}
+		}
+	}()
+}
+
+func (m *Manager) runStrategyReport(ctx context.Context) {
+	report, err := m.GenerateStrategyReport(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			m.logger.Printf("strategy report: %v", err)
+		}
+		return
+	}
+	m.logger.Printf("strategy report %d: %s", report.ID, report.Summary())
+	if m.reportStore == nil || m.reportsCfg.Retention <= 0 {
+		return
+	}
+	removed, err := m.reportStore.PurgeReports(ctx, m.clock().Add(-m.reportsCfg.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			m.logger.Printf("strategy report: purge failed: %v", err)
+		}
+		return
+	}
+	if removed > 0 {
+		m.logger.Printf("strategy report: purged %d report(s) older than %s", removed, m.reportsCfg.Retention)
+	}
+}
@@ -0,0 +1,63 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestManagerStrategyReport(t *testing.T) {
+	mgr := newTestManager(t)
+	spec := baseLambdaSpec()
+	if _, err := mgr.Create(spec); err != nil {
+		t.Fatalf("Create lambda: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := mgr.StrategyReport(ctx, 0); !errors.Is(err, ErrStrategyReportNotFound) {
+		t.Fatalf("expected no report before generation, got %v", err)
+	}
+	first, err := mgr.GenerateStrategyReport(ctx)
+	if err != nil {
+		t.Fatalf("GenerateStrategyReport: %v", err)
+	}
+	if first.ID != 1 || first.Totals.Strategies == 0 || first.Totals.Instances != 1 {
+		t.Fatalf("unexpected report %+v", first.Totals)
+	}
+	var owner *StrategyReportEntry
+	for i := range first.Strategies {
+		if len(first.Strategies[i].Instances) > 0 {
+			owner = &first.Strategies[i]
+		}
+	}
+	if owner == nil || owner.Instances[0] != spec.ID {
+		t.Fatalf("expected %s listed under its strategy, got %+v", spec.ID, first.Strategies)
+	}
+	if first.Totals.UnusedRevisions != len(first.Unused) || first.Totals.UnusedRevisions != first.Totals.Revisions-1 {
+		t.Fatalf("expected every revision but the pinned one unused, got %+v", first.Totals)
+	}
+
+	second, err := mgr.GenerateStrategyReport(ctx)
+	if err != nil {
+		t.Fatalf("GenerateStrategyReport: %v", err)
+	}
+	summaries, err := mgr.StrategyReports(ctx, 0)
+	if err != nil {
+		t.Fatalf("StrategyReports: %v", err)
+	}
+	if len(summaries) != 2 || summaries[0].ID != second.ID || summaries[1].Summary != first.Summary() {
+		t.Fatalf("expected newest first, got %+v", summaries)
+	}
+	latest, err := mgr.StrategyReport(ctx, 0)
+	if err != nil || latest.ID != second.ID {
+		t.Fatalf("expected latest report %d, got %d (%v)", second.ID, latest.ID, err)
+	}
+	if _, err := mgr.StrategyReport(ctx, 99); !errors.Is(err, ErrStrategyReportNotFound) {
+		t.Fatalf("expected unknown report rejected, got %v", err)
+	}
+	text := latest.Text()
+	if !strings.Contains(text, latest.Summary()) || !strings.Contains(text, spec.ID) || !strings.Contains(text, "Unused revisions:") {
+		t.Fatalf("unexpected text report:\n%s", text)
+	}
+}
@@ -0,0 +1,245 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+const (
+	defaultStrategyLogLimit    = 200
+	maxStrategyLogLimit        = 5000
+	maxInMemoryStrategyLogs    = 1000
+	maxStrategyLogMessageBytes = 4096
+	strategyLogQueueSize       = 4096
+	strategyLogBatchSize       = 256
+	strategyLogFlushInterval   = time.Second
+	strategyLogPurgeInterval   = time.Hour
+	strategyLogShutdownTimeout = 2 * time.Second
+)
+
+// WithStrategyLogStore persists strategy console logs and diagnostics so they outlive restarts.
+// StartStrategyLogs must be called to write and purge them.
+func WithStrategyLogStore(store strategylogstore.Store) Option {
+	return func(m *Manager) {
+		m.logStore = store
+	}
+}
+
+// StrategyLogQuery selects the log lines of one instance. A zero From returns the most recent
+// lines; otherwise lines are returned starting at From. Lines are always ordered oldest first.
+type StrategyLogQuery struct {
+	From     time.Time
+	MinLevel string
+	Limit    int
+}
+
+// StrategyLogs returns the recorded log lines of an instance. Lines come from the log store when
+// one is configured, so they remain available after the instance was removed or the gateway
+// restarted; otherwise from memory.
+func (m *Manager) StrategyLogs(ctx context.Context, id string, query StrategyLogQuery) ([]strategylogstore.Entry, error) {
+	if m == nil {
+		return nil, fmt.Errorf("strategy manager unavailable")
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, fmt.Errorf("strategy logs: instance id required")
+	}
+	minLevel := strategylogstore.LevelDebug
+	if strings.TrimSpace(query.MinLevel) != "" {
+		level, ok := strategylogstore.NormalizeLevel(query.MinLevel)
+		if !ok {
+			return nil, fmt.Errorf("strategy logs: unknown level %q", query.MinLevel)
+		}
+		minLevel = level
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultStrategyLogLimit
+	}
+	limit = min(limit, maxStrategyLogLimit)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if m.logStore != nil {
+		entries, err := m.logStore.ListLogs(ctx, strategylogstore.Query{
+			InstanceID: id,
+			From:       query.From,
+			MinLevel:   minLevel,
+			Limit:      limit,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("strategy logs %s: %w", id, err)
+		}
+		return entries, nil
+	}
+
+	severity := strategylogstore.Severity(minLevel)
+	m.logsMu.Lock()
+	defer m.logsMu.Unlock()
+	matched := make([]strategylogstore.Entry, 0, min(limit, len(m.logs[id])))
+	for _, entry := range m.logs[id] {
+		if strategylogstore.Severity(entry.Level) < severity || entry.RecordedAt.Before(query.From) {
+			continue
+		}
+		matched = append(matched, entry)
+		if !query.From.IsZero() && len(matched) == limit {
+			break
+		}
+	}
+	if len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	return matched, nil
+}
+
+// StartStrategyLogs writes queued log lines to the log store in batches and purges lines older
+// than the configured retention until ctx is cancelled.
+func (m *Manager) StartStrategyLogs(ctx context.Context) {
+	if m == nil || m.logStore == nil {
+		return
+	}
+	go m.flushStrategyLogs(ctx)
+	if m.logsCfg.Retention <= 0 {
+		return
+	}
+	go func() {
+		m.purgeStrategyLogs(ctx)
+		ticker := time.NewTicker(strategyLogPurgeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.purgeStrategyLogs(ctx)
+			}
+		}
+	}()
+}
+
+func (m *Manager) flushStrategyLogs(ctx context.Context) {
+	ticker := time.NewTicker(strategyLogFlushInterval)
+	defer ticker.Stop()
+	batch := make([]strategylogstore.Entry, 0, strategyLogBatchSize)
+	write := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := m.logStore.AppendLogs(ctx, batch); err != nil && m.logger != nil {
+			m.logger.Printf("strategy logs: persist %d line(s) failed: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			// Drain what is queued so the lines leading up to a shutdown are kept.
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), strategyLogShutdownTimeout)
+			defer cancel()
+			for {
+				select {
+				case entry := <-m.logQueue:
+					batch = append(batch, entry)
+					if len(batch) == strategyLogBatchSize {
+						write(shutdownCtx)
+					}
+				default:
+					write(shutdownCtx)
+					return
+				}
+			}
+		case entry := <-m.logQueue:
+			batch = append(batch, entry)
+			if len(batch) == strategyLogBatchSize {
+				write(ctx)
+			}
+		case <-ticker.C:
+			write(ctx)
+		}
+	}
+}
+
+func (m *Manager) purgeStrategyLogs(ctx context.Context) {
+	cutoff := m.clock().Add(-m.logsCfg.Retention)
+	removed, err := m.logStore.PurgeLogs(ctx, cutoff)
+	if err != nil {
+		if m.logger != nil && ctx.Err() == nil {
+			m.logger.Printf("strategy logs: purge failed: %v", err)
+		}
+		return
+	}
+	if removed > 0 && m.logger != nil {
+		m.logger.Printf("strategy logs: purged %d line(s) older than %s", removed, m.logsCfg.Retention)
+	}
+}
+
+// bindStrategyLogs records the console output, helpers.log lines and handler exceptions of
+// JavaScript strategies.
+func (m *Manager) bindStrategyLogs(spec config.LambdaSpec, strategy core.TradingStrategy) {
+	jsStrategy, ok := strategy.(*js.Strategy)
+	if !ok {
+		return
+	}
+	jsStrategy.SetLogFunc(func(source, level, message string) {
+		m.recordStrategyLog(spec, source, level, message)
+	})
+}
+
+func (m *Manager) recordStrategyLog(spec config.LambdaSpec, source, level, message string) {
+	if m == nil || strings.TrimSpace(spec.ID) == "" {
+		return
+	}
+	level, ok := strategylogstore.NormalizeLevel(level)
+	if !ok {
+		level = strategylogstore.LevelInfo
+	}
+	if strategylogstore.Severity(level) < strategylogstore.Severity(m.logsCfg.Level) {
+		return
+	}
+	if len(message) > maxStrategyLogMessageBytes {
+		message = strings.ToValidUTF8(message[:maxStrategyLogMessageBytes], "") + "…"
+	}
+	entry := strategylogstore.Entry{
+		ID:         0,
+		InstanceID: spec.ID,
+		Strategy:   spec.Strategy.Identifier,
+		Hash:       spec.Strategy.Hash,
+		Level:      level,
+		Source:     source,
+		Message:    message,
+		RecordedAt: m.clock().UTC(),
+	}
+
+	if m.logStore != nil {
+		select {
+		case m.logQueue <- entry:
+		default:
+			// Never block a strategy on log persistence; report drops in bulk instead.
+			m.logsMu.Lock()
+			m.logsDropped++
+			dropped := m.logsDropped
+			m.logsMu.Unlock()
+			if m.logger != nil && (dropped == 1 || dropped%1000 == 0) {
+				m.logger.Printf("strategy logs: queue full, %d line(s) dropped", dropped)
+			}
+		}
+		return
+	}
+
+	m.logsMu.Lock()
+	defer m.logsMu.Unlock()
+	m.logSeq++
+	entry.ID = m.logSeq
+	recorded := append(m.logs[spec.ID], entry)
+	if len(recorded) > maxInMemoryStrategyLogs {
+		recorded = recorded[len(recorded)-maxInMemoryStrategyLogs:]
+	}
+	m.logs[spec.ID] = recorded
+}
@@ -0,0 +1,110 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+func TestManagerStrategyLogs_InMemory(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	mgr := &Manager{
+		clock:   func() time.Time { return now },
+		logsCfg: config.StrategyLogsConfig{Level: "info", Retention: time.Hour},
+		logs:    make(map[string][]strategylogstore.Entry),
+	}
+	spec := config.LambdaSpec{ID: "alpha-1", Strategy: config.LambdaStrategySpec{Identifier: "alpha", Hash: "sha256:abc"}}
+
+	mgr.recordStrategyLog(spec, strategylogstore.SourceConsole, "debug", "dropped below the configured level")
+	mgr.recordStrategyLog(spec, strategylogstore.SourceConsole, "info", "first")
+	now = now.Add(time.Minute)
+	mgr.recordStrategyLog(spec, strategylogstore.SourceConsole, "warning", "second")
+	now = now.Add(time.Minute)
+	mgr.recordStrategyLog(spec, strategylogstore.SourceValidation, "error", "third")
+
+	entries, err := mgr.StrategyLogs(context.Background(), "alpha-1", StrategyLogQuery{})
+	if err != nil {
+		t.Fatalf("StrategyLogs: %v", err)
+	}
+	if len(entries) != 3 || entries[0].Message != "first" || entries[1].Level != "warn" || entries[2].Hash != "sha256:abc" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+
+	entries, _ = mgr.StrategyLogs(context.Background(), "alpha-1", StrategyLogQuery{MinLevel: "warn", Limit: 1})
+	if len(entries) != 1 || entries[0].Message != "third" {
+		t.Fatalf("expected most recent warn+ line, got %+v", entries)
+	}
+	entries, _ = mgr.StrategyLogs(context.Background(), "alpha-1", StrategyLogQuery{From: now.Add(-time.Minute), Limit: 1})
+	if len(entries) != 1 || entries[0].Message != "second" {
+		t.Fatalf("expected first line from cutoff, got %+v", entries)
+	}
+	if _, err := mgr.StrategyLogs(context.Background(), "alpha-1", StrategyLogQuery{MinLevel: "verbose"}); err == nil {
+		t.Fatal("expected unknown level rejected")
+	}
+}
+
+type memoryStrategyLogStore struct {
+	mu      sync.Mutex
+	entries []strategylogstore.Entry
+	purged  time.Time
+}
+
+func (s *memoryStrategyLogStore) AppendLogs(_ context.Context, entries []strategylogstore.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func (s *memoryStrategyLogStore) ListLogs(_ context.Context, query strategylogstore.Query) ([]strategylogstore.Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []strategylogstore.Entry
+	for _, entry := range s.entries {
+		if entry.InstanceID == query.InstanceID {
+			out = append(out, entry)
+		}
+	}
+	return out, nil
+}
+
+func (s *memoryStrategyLogStore) PurgeLogs(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purged = before
+	return 0, nil
+}
+
+func TestManagerStrategyLogs_PersistsOnShutdown(t *testing.T) {
+	store := &memoryStrategyLogStore{}
+	mgr := newTestManager(t, WithStrategyLogStore(store))
+	mgr.logsCfg = config.StrategyLogsConfig{Level: "info", Retention: 24 * time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	mgr.StartStrategyLogs(ctx)
+
+	spec := config.LambdaSpec{ID: "beta-1", Strategy: config.LambdaStrategySpec{Identifier: "beta"}}
+	mgr.recordStrategyLog(spec, strategylogstore.SourceRuntime, "error", "handler failed")
+	cancel()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		entries, err := mgr.StrategyLogs(context.Background(), "beta-1", StrategyLogQuery{})
+		if err != nil {
+			t.Fatalf("StrategyLogs: %v", err)
+		}
+		store.mu.Lock()
+		purged := store.purged
+		store.mu.Unlock()
+		if len(entries) == 1 && entries[0].Source == strategylogstore.SourceRuntime && !purged.IsZero() {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected persisted entry and retention purge, got %+v (purged %v)", entries, purged)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
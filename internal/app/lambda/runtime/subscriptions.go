@@ -0,0 +1,141 @@
+package runtime
+
+import (
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// RouteInspector is implemented by route registrars that can report the routes registered for a
+// lambda.
+type RouteInspector interface {
+	LambdaRoutes(lambdaID string) ([]dispatcher.LambdaRoute, bool)
+}
+
+// InstanceSubscriptions reports the dispatcher routes of an instance and the events it received
+// through them, to debug strategies that do not see the data they expect.
+type InstanceSubscriptions struct {
+	Instance string `json:"instance"`
+	Running  bool   `json:"running"`
+	// Registered reports whether the dispatcher holds a registration for the instance.
+	Registered bool                `json:"registered"`
+	Routes     []SubscriptionRoute `json:"routes"`
+	// Unrouted counts deliveries of event types no route was declared for, such as risk control
+	// events or events of a provider the instance no longer routes.
+	Unrouted []core.DeliveryStat `json:"unrouted,omitempty"`
+}
+
+// SubscriptionRoute is one declared route of an instance on one provider.
+type SubscriptionRoute struct {
+	Provider  string           `json:"provider"`
+	Type      schema.RouteType `json:"type"`
+	EventType schema.EventType `json:"eventType,omitempty"`
+	// Filters are the filters the instance declared.
+	Filters map[string]any `json:"filters,omitempty"`
+	// Active reports whether the route is live in the dispatcher table. ActiveFilters are its
+	// filters there, merged with those of every other instance on the provider.
+	Active        bool                 `json:"active"`
+	ActiveFilters []SubscriptionFilter `json:"activeFilters,omitempty"`
+	Delivered     int64                `json:"delivered"`
+	Filtered      int64                `json:"filtered"`
+	LastDelivery  *time.Time           `json:"lastDelivery,omitempty"`
+}
+
+// SubscriptionFilter is a dispatcher filter rule.
+type SubscriptionFilter struct {
+	Field string                 `json:"field,omitempty"`
+	Op    string                 `json:"op,omitempty"`
+	Value any                    `json:"value,omitempty"`
+	Not   bool                   `json:"not,omitempty"`
+	AnyOf [][]SubscriptionFilter `json:"anyOf,omitempty"`
+}
+
+// InstanceSubscriptions returns the routes the dispatcher holds for the instance together with
+// the delivery counters of the running instance. Stopped instances report their routes, if any,
+// without counters.
+func (m *Manager) InstanceSubscriptions(id string) (InstanceSubscriptions, error) {
+	var empty InstanceSubscriptions
+	id = strings.TrimSpace(id)
+	m.mu.RLock()
+	_, exists := m.specs[id]
+	inst, running := m.instances[id]
+	m.mu.RUnlock()
+	if !exists {
+		return empty, ErrInstanceNotFound
+	}
+	result := InstanceSubscriptions{
+		Instance:   id,
+		Running:    running && inst != nil && inst.base != nil,
+		Registered: false,
+		Routes:     []SubscriptionRoute{},
+		Unrouted:   nil,
+	}
+	var routes []dispatcher.LambdaRoute
+	if inspector, ok := m.registrar.(RouteInspector); ok {
+		routes, result.Registered = inspector.LambdaRoutes(id)
+	}
+	var stats []core.DeliveryStat
+	if result.Running {
+		stats = inst.base.DeliveryStats()
+	}
+
+	used := make([]bool, len(stats))
+	for _, route := range routes {
+		entry := SubscriptionRoute{
+			Provider:      route.Provider,
+			Type:          route.Type,
+			EventType:     "",
+			Filters:       route.Filters,
+			Active:        route.Active,
+			ActiveFilters: nil,
+			Delivered:     0,
+			Filtered:      0,
+			LastDelivery:  nil,
+		}
+		if eventType, ok := schema.EventTypeForRoute(route.Type); ok {
+			entry.EventType = eventType
+		}
+		if route.Active {
+			entry.ActiveFilters = subscriptionFilters(route.Route.Filters)
+		}
+		for i, stat := range stats {
+			if stat.Provider != route.Provider || stat.EventType != entry.EventType {
+				continue
+			}
+			used[i] = true
+			entry.Delivered = stat.Delivered
+			entry.Filtered = stat.Filtered
+			entry.LastDelivery = stat.LastDelivery
+		}
+		result.Routes = append(result.Routes, entry)
+	}
+	for i, stat := range stats {
+		if !used[i] {
+			result.Unrouted = append(result.Unrouted, stat)
+		}
+	}
+	return result, nil
+}
+
+func subscriptionFilters(rules []dispatcher.FilterRule) []SubscriptionFilter {
+	if len(rules) == 0 {
+		return nil
+	}
+	out := make([]SubscriptionFilter, len(rules))
+	for i, rule := range rules {
+		out[i] = SubscriptionFilter{
+			Field: rule.Field,
+			Op:    rule.Op,
+			Value: rule.Value,
+			Not:   rule.Not,
+			AnyOf: nil,
+		}
+		for _, alternative := range rule.AnyOf {
+			out[i].AnyOf = append(out[i].AnyOf, subscriptionFilters(alternative))
+		}
+	}
+	return out
+}
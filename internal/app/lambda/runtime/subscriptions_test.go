@@ -0,0 +1,39 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+)
+
+func TestSubscriptionFilters(t *testing.T) {
+	filters := subscriptionFilters([]dispatcher.FilterRule{
+		{Field: "instrument", Op: "in", Value: []string{"BTC-USDT", "ETH-USDT"}},
+		{AnyOf: [][]dispatcher.FilterRule{{{Field: "side", Op: "eq", Value: "buy"}}}},
+	})
+	if len(filters) != 2 || filters[0].Field != "instrument" || filters[0].Op != "in" {
+		t.Fatalf("unexpected filters %+v", filters)
+	}
+	if len(filters[1].AnyOf) != 1 || filters[1].AnyOf[0][0].Value != "buy" {
+		t.Fatalf("expected nested alternatives converted, got %+v", filters[1])
+	}
+}
+
+func TestInstanceSubscriptionsWithoutRegistrar(t *testing.T) {
+	mgr := newTestManager(t)
+	spec := baseLambdaSpec()
+	if _, err := mgr.Create(spec); err != nil {
+		t.Fatalf("Create lambda: %v", err)
+	}
+	if _, err := mgr.InstanceSubscriptions("missing"); !errors.Is(err, ErrInstanceNotFound) {
+		t.Fatalf("expected unknown instance rejected, got %v", err)
+	}
+	subs, err := mgr.InstanceSubscriptions(spec.ID)
+	if err != nil {
+		t.Fatalf("InstanceSubscriptions: %v", err)
+	}
+	if subs.Instance != spec.ID || subs.Running || subs.Registered || len(subs.Routes) != 0 {
+		t.Fatalf("unexpected subscriptions %+v", subs)
+	}
+}
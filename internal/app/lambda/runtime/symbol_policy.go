@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// installSymbolPolicy shares the configured symbol allow and deny lists with every risk manager,
+// logging and counting each order they reject.
+func (m *Manager) installSymbolPolicy(cfg config.SymbolPolicyConfig) {
+	providers := make(map[string]risk.SymbolLists, len(cfg.Providers))
+	for name, lists := range cfg.Providers {
+		providers[name] = risk.SymbolLists{Allow: lists.Allow, Deny: lists.Deny}
+	}
+	policy := risk.NewSymbolPolicy(risk.SymbolLists{Allow: cfg.Allow, Deny: cfg.Deny}, providers)
+
+	rejections, err := otel.Meter("lambda-manager").Int64Counter("risk_symbol_policy_rejections_total",
+		metric.WithDescription("Orders rejected by the symbol allow and deny lists"),
+		metric.WithUnit("{order}"),
+	)
+	if err != nil {
+		m.logger.Printf("lambda manager: register symbol policy counter: %v", err)
+		rejections = nil
+	}
+	policy.OnViolation(func(violation risk.SymbolViolation) {
+		m.logger.Printf("risk: order for %s on %s rejected: %s (%d rejected)",
+			violation.Symbol, violation.Provider, violation.Reason, violation.Count)
+		if rejections != nil {
+			rejections.Add(context.Background(), 1, metric.WithAttributes(
+				attribute.String("environment", telemetry.Environment()),
+				attribute.String("provider", violation.Provider),
+				attribute.String("symbol", violation.Symbol),
+			))
+		}
+	})
+	m.riskManager.SetSymbolPolicy(policy)
+}
+
+// SymbolPolicy reports the symbol allow and deny lists and the orders they rejected.
+func (m *Manager) SymbolPolicy() risk.SymbolPolicyStatus {
+	policy := m.riskManager.SymbolPolicy()
+	if policy == nil {
+		return risk.NewSymbolPolicy(risk.SymbolLists{Allow: nil, Deny: nil}, nil).Status()
+	}
+	return policy.Status()
+}
+
+// symbolPolicyProblems lists the scoped symbols the symbol policy bars, so instances that could
+// never trade them are refused up front.
+func (m *Manager) symbolPolicyProblems(spec config.LambdaSpec) []symbolProblem {
+	policy := m.riskManager.SymbolPolicy()
+	if policy.Empty() {
+		return nil
+	}
+	var problems []symbolProblem
+	symbolsByProvider := spec.ProviderSymbolMap()
+	for _, name := range spec.Providers {
+		for _, symbol := range symbolsByProvider[name] {
+			if reason := policy.Evaluate(name, symbol); reason != "" {
+				problems = append(problems, symbolProblem{
+					provider:    name,
+					symbol:      symbol,
+					code:        "symbol_denied",
+					status:      "",
+					suggestions: nil,
+					reason:      reason,
+				})
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,196 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// ErrInvalidSymbol is returned when an instance scope names instruments its provider does not
+// list or does not currently trade.
+var ErrInvalidSymbol = errors.New("invalid instrument symbol")
+
+const maxSymbolSuggestions = 3
+
+// symbolProblem describes one scoped symbol that fails catalogue validation.
+type symbolProblem struct {
+	provider    string
+	symbol      string
+	code        string
+	status      schema.InstrumentStatus
+	suggestions []string
+	// reason explains why the symbol policy bars the symbol.
+	reason string
+}
+
+func (p symbolProblem) String() string {
+	switch p.code {
+	case "symbol_denied":
+		return fmt.Sprintf("%s on %s is barred: %s", p.symbol, p.provider, p.reason)
+	case "instrument_not_trading":
+		return fmt.Sprintf("%s on %s is %s", p.symbol, p.provider, p.status)
+	default:
+		msg := fmt.Sprintf("provider %q does not list instrument %s", p.provider, p.symbol)
+		if len(p.suggestions) > 0 {
+			msg += fmt.Sprintf(" (did you mean %s?)", strings.Join(p.suggestions, ", "))
+		}
+		return msg
+	}
+}
+
+// symbolProblems checks every scoped symbol against the symbol policy and the live instrument
+// catalogue of its provider. Providers that are unknown or have not loaded a catalogue yet are
+// skipped, as are configured synthetic symbols.
+func (m *Manager) symbolProblems(spec config.LambdaSpec) []symbolProblem {
+	problems := m.symbolPolicyProblems(spec)
+	if m.providers == nil {
+		return problems
+	}
+	symbolsByProvider := spec.ProviderSymbolMap()
+	for _, name := range spec.Providers {
+		inst, ok := m.providers.Provider(name)
+		if !ok || inst == nil {
+			continue
+		}
+		instruments := inst.Instruments()
+		if len(instruments) == 0 {
+			continue
+		}
+		catalogue := make(map[string]schema.Instrument, len(instruments))
+		for _, instrument := range instruments {
+			catalogue[strings.ToUpper(strings.TrimSpace(instrument.Symbol))] = instrument
+		}
+		for _, symbol := range symbolsByProvider[name] {
+			if _, ok := m.syntheticLegs[name][symbol]; ok {
+				continue
+			}
+			instrument, ok := catalogue[symbol]
+			switch {
+			case !ok:
+				problems = append(problems, symbolProblem{
+					provider:    name,
+					symbol:      symbol,
+					code:        "instrument_unsupported",
+					status:      "",
+					suggestions: suggestSymbols(symbol, catalogue),
+					reason:      "",
+				})
+			case !instrument.Status.Tradable():
+				problems = append(problems, symbolProblem{
+					provider:    name,
+					symbol:      symbol,
+					code:        "instrument_not_trading",
+					status:      instrument.Status,
+					suggestions: nil,
+					reason:      "",
+				})
+			}
+		}
+	}
+	return problems
+}
+
+// validateSymbols rejects specs whose scope fails catalogue validation, listing every problem
+// so operators can fix the scope in one pass.
+func (m *Manager) validateSymbols(spec config.LambdaSpec) error {
+	problems := m.symbolProblems(spec)
+	if len(problems) == 0 {
+		return nil
+	}
+	messages := make([]string, len(problems))
+	for i, problem := range problems {
+		messages[i] = problem.String()
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidSymbol, strings.Join(messages, "; "))
+}
+
+// suggestSymbols returns catalogue symbols that differ from symbol only by separators or, when
+// none do, by a couple of characters, closest first.
+func suggestSymbols(symbol string, catalogue map[string]schema.Instrument) []string {
+	target := compactSymbol(symbol)
+	if target == "" {
+		return nil
+	}
+	maxDistance := 2
+	if len(target) < 6 {
+		maxDistance = 1
+	}
+	type candidate struct {
+		symbol   string
+		distance int
+	}
+	var candidates []candidate
+	for listed := range catalogue {
+		distance := editDistance(target, compactSymbol(listed), maxDistance)
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{symbol: listed, distance: distance})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].symbol < candidates[j].symbol
+	})
+	// A listed symbol spelled with other separators is the answer; skip looser matches.
+	if len(candidates) > 0 && candidates[0].distance == 0 {
+		exact := 1
+		for exact < len(candidates) && candidates[exact].distance == 0 {
+			exact++
+		}
+		candidates = candidates[:exact]
+	}
+	if len(candidates) > maxSymbolSuggestions {
+		candidates = candidates[:maxSymbolSuggestions]
+	}
+	out := make([]string, len(candidates))
+	for i, c := range candidates {
+		out[i] = c.symbol
+	}
+	return out
+}
+
+// compactSymbol drops separators so venue spellings such as BTCUSDT, BTC/USDT and BTC_USDT
+// compare equal to BTC-USDT.
+func compactSymbol(symbol string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '/', ' ', '.':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(symbol)))
+}
+
+// editDistance is the Levenshtein distance between a and b, cut short once it exceeds limit.
+func editDistance(a, b string, limit int) int {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return limit + 1
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
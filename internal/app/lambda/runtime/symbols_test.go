@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type catalogueProvider struct {
+	provider.Instance
+	instruments []schema.Instrument
+}
+
+func (p catalogueProvider) Instruments() []schema.Instrument { return p.instruments }
+
+type staticCatalog map[string]provider.Instance
+
+func (c staticCatalog) Provider(name string) (provider.Instance, bool) {
+	inst, ok := c[name]
+	return inst, ok
+}
+
+func TestCreateValidatesSymbolsAgainstCatalogue(t *testing.T) {
+	catalog := staticCatalog{
+		"binance-spot": catalogueProvider{instruments: []schema.Instrument{
+			{Symbol: "BTC-USDT", Status: schema.InstrumentStatusTrading},
+			{Symbol: "ETH-USDT", Status: schema.InstrumentStatusTrading},
+			{Symbol: "LUNA-USDT", Status: schema.InstrumentStatusHalted},
+		}},
+		"okx-spot": catalogueProvider{instruments: nil},
+	}
+	mgr, err := NewManager(config.AppConfig{}, nil, nil, catalog, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	spec := config.LambdaSpec{
+		ID:        "scoped",
+		Strategy:  config.LambdaStrategySpec{Identifier: "noop", Config: map[string]any{}},
+		Providers: []string{"binance-spot"},
+		ProviderSymbols: map[string]config.ProviderSymbols{
+			"binance-spot": {Symbols: []string{"btcusdt", "eth-usdt", "LUNA-USDT", "DOGE-USDT"}},
+		},
+	}
+	_, err = mgr.Create(spec)
+	if !errors.Is(err, ErrInvalidSymbol) {
+		t.Fatalf("expected invalid symbol error, got %v", err)
+	}
+	for _, want := range []string{"BTCUSDT (did you mean BTC-USDT?)", "LUNA-USDT on binance-spot is halted", "DOGE-USDT"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "ETH-USDT") {
+		t.Fatalf("lower-case listed symbol must be accepted: %v", err)
+	}
+
+	// Providers without a loaded catalogue are not validated.
+	spec.Providers = []string{"okx-spot"}
+	spec.ProviderSymbols = map[string]config.ProviderSymbols{"okx-spot": {Symbols: []string{"ANY-THING"}}}
+	if problems := mgr.symbolProblems(sanitizeSpec(spec)); len(problems) != 0 {
+		t.Fatalf("expected no problems without catalogue, got %v", problems)
+	}
+}
+
+func TestSuggestSymbols(t *testing.T) {
+	catalogue := map[string]schema.Instrument{"BTC-USDT": {}, "BTC-USDC": {}, "ETH-USDT": {}, "BTC-USD": {}}
+	got := suggestSymbols("BTC/USDT", catalogue)
+	if len(got) == 0 || got[0] != "BTC-USDT" {
+		t.Fatalf("expected BTC-USDT first, got %v", got)
+	}
+	if got := suggestSymbols("SOL-EUR", catalogue); len(got) != 0 {
+		t.Fatalf("expected no suggestions, got %v", got)
+	}
+}
+
+func TestCreateRejectsSymbolsBarredByPolicy(t *testing.T) {
+	cfg := config.AppConfig{
+		SymbolPolicy: config.SymbolPolicyConfig{
+			Deny:      []string{"XMR-*"},
+			Providers: map[string]config.SymbolListConfig{"binance-spot": {Allow: []string{"BTC-USDT", "XMR-USDT"}}},
+		},
+	}
+	mgr, err := NewManager(cfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	spec := config.LambdaSpec{
+		ID:        "barred",
+		Strategy:  config.LambdaStrategySpec{Identifier: "noop", Config: map[string]any{}},
+		Providers: []string{"binance-spot"},
+		ProviderSymbols: map[string]config.ProviderSymbols{
+			"binance-spot": {Symbols: []string{"BTC-USDT", "XMR-USDT", "ETH-USDT"}},
+		},
+	}
+	_, err = mgr.Create(spec)
+	if !errors.Is(err, ErrInvalidSymbol) {
+		t.Fatalf("expected invalid symbol error, got %v", err)
+	}
+	for _, want := range []string{"XMR-USDT on binance-spot is barred: symbol denied by XMR-*", "ETH-USDT on binance-spot is barred: symbol not in provider allow list"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "BTC-USDT") {
+		t.Fatalf("allowed symbol must be accepted: %v", err)
+	}
+	if status := mgr.SymbolPolicy(); len(status.Global.Deny) != 1 || status.Rejected != 0 {
+		t.Fatalf("unexpected policy status %+v", status)
+	}
+}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+)
+
+// ErrQueuedOrderNotFound is returned when an order is not waiting in the instance throttle queue.
+var ErrQueuedOrderNotFound = errors.New("queued order not found")
+
+// throttleQueueConfigFromStrategy extracts the throttle queue settings from the strategy config.
+// Recognised keys are throttle_queue (bool), throttle_queue_depth and throttle_queue_expiry (a
+// duration string such as "30s" or a number of seconds); missing values select the core defaults.
+func throttleQueueConfigFromStrategy(cfg map[string]any) core.ThrottleQueueConfig {
+	var queue core.ThrottleQueueConfig
+	queue.Enabled, _ = cfg["throttle_queue"].(bool)
+	if depth, ok := intFromConfig(cfg["throttle_queue_depth"]); ok {
+		queue.MaxDepth = depth
+	}
+	if expiry, ok := durationFromConfig(cfg["throttle_queue_expiry"]); ok {
+		queue.Expiry = expiry
+	}
+	return queue
+}
+
+// InstanceThrottleQueue reports the orders a running instance holds back for the risk throttle.
+func (m *Manager) InstanceThrottleQueue(id string) (core.ThrottleQueueStatus, error) {
+	inst, err := m.runningInstance(id)
+	if err != nil {
+		var empty core.ThrottleQueueStatus
+		return empty, err
+	}
+	return inst.base.ThrottleQueue(), nil
+}
+
+// CancelInstanceQueuedOrder drops an order from the throttle queue of a running instance before
+// it is submitted.
+func (m *Manager) CancelInstanceQueuedOrder(id, clientOrderID string) (core.QueuedOrder, error) {
+	var empty core.QueuedOrder
+	inst, err := m.runningInstance(id)
+	if err != nil {
+		return empty, err
+	}
+	order, err := inst.base.CancelQueuedOrder(clientOrderID)
+	if err != nil {
+		if errors.Is(err, core.ErrQueuedOrderNotFound) {
+			return empty, fmt.Errorf("%w: %s", ErrQueuedOrderNotFound, clientOrderID)
+		}
+		return empty, err
+	}
+	return order, nil
+}
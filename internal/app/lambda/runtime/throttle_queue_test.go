@@ -0,0 +1,29 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+)
+
+func TestThrottleQueueConfigFromStrategy(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  map[string]any
+		want core.ThrottleQueueConfig
+	}{
+		{name: "disabled", cfg: nil, want: core.ThrottleQueueConfig{}},
+		{name: "defaults", cfg: map[string]any{"throttle_queue": true}, want: core.ThrottleQueueConfig{Enabled: true}},
+		{name: "explicit", cfg: map[string]any{"throttle_queue": true, "throttle_queue_depth": float64(8), "throttle_queue_expiry": "5s"}, want: core.ThrottleQueueConfig{Enabled: true, MaxDepth: 8, Expiry: 5 * time.Second}},
+		{name: "seconds", cfg: map[string]any{"throttle_queue": true, "throttle_queue_expiry": 10}, want: core.ThrottleQueueConfig{Enabled: true, Expiry: 10 * time.Second}},
+		{name: "malformed", cfg: map[string]any{"throttle_queue": "yes", "throttle_queue_depth": "deep"}, want: core.ThrottleQueueConfig{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := throttleQueueConfigFromStrategy(tc.cfg); got != tc.want {
+				t.Fatalf("throttleQueueConfigFromStrategy(%v) = %+v, want %+v", tc.cfg, got, tc.want)
+			}
+		})
+	}
+}
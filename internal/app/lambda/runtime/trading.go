@@ -0,0 +1,123 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+	"github.com/coachpo/meltica/internal/domain/strategystore"
+)
+
+// TradingChangeKind identifies live trading toggles among extension events.
+const TradingChangeKind = "instance.trading"
+
+// TradingChange is published as an extension event when live trading of an instance is toggled.
+type TradingChange struct {
+	Kind     string    `json:"kind"`
+	Instance string    `json:"instance"`
+	Enabled  bool      `json:"enabled"`
+	Actor    string    `json:"actor,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// SetInstanceTrading enables or disables live order submission for a running instance without
+// restarting it. Disabled instances keep running in dry-run mode. The choice is stored in the
+// instance spec so it survives restarts, recorded in the strategy history and the instance log,
+// and published as an extension event the instance receives through onExtensionEvent.
+func (m *Manager) SetInstanceTrading(id string, enabled bool, change StrategyChange) error {
+	if m == nil {
+		return fmt.Errorf("strategy manager unavailable")
+	}
+	id = strings.TrimSpace(id)
+	dryRun := !enabled
+
+	m.mu.Lock()
+	spec, ok := m.specs[id]
+	if !ok {
+		m.mu.Unlock()
+		return ErrInstanceNotFound
+	}
+	inst, running := m.instances[id]
+	if !running || inst.base == nil {
+		m.mu.Unlock()
+		return ErrInstanceNotRunning
+	}
+	spec.DryRun = &dryRun
+	m.specs[id] = spec
+	m.mu.Unlock()
+
+	inst.base.EnableTrading(enabled)
+	m.persistStrategy(id)
+
+	action := strategystore.HistoryActionTradingDisabled
+	state := "disabled"
+	if enabled {
+		action = strategystore.HistoryActionTradingEnabled
+		state = "enabled"
+	}
+	m.recordStrategyHistory(strategystore.HistoryEntry{
+		ID:           0,
+		Strategy:     spec.Strategy.Identifier,
+		Action:       action,
+		Hash:         spec.Strategy.Hash,
+		Tag:          spec.Strategy.Tag,
+		PreviousHash: "",
+		Actor:        "",
+		Reason:       "",
+		Metadata:     map[string]any{"instance": id},
+		RecordedAt:   time.Time{},
+	}, change)
+	message := "live trading " + state
+	if actor := strings.TrimSpace(change.Actor); actor != "" {
+		message += " by " + actor
+	}
+	if reason := strings.TrimSpace(change.Reason); reason != "" {
+		message += ": " + reason
+	}
+	m.recordStrategyLog(spec, strategylogstore.SourceRuntime, strategylogstore.LevelWarn, message)
+	m.publishTradingChange(spec.Providers, TradingChange{
+		Kind:     TradingChangeKind,
+		Instance: id,
+		Enabled:  enabled,
+		Actor:    strings.TrimSpace(change.Actor),
+		Reason:   strings.TrimSpace(change.Reason),
+		At:       m.clock().UTC(),
+	})
+	if m.logger != nil {
+		m.logger.Printf("strategy/%s: %s%s", id, message, change.logSuffix())
+	}
+	return nil
+}
+
+// publishTradingChange stamps the event with the instance's first provider so the instance itself
+// receives it; instances filter extension events by provider.
+func (m *Manager) publishTradingChange(providers []string, change TradingChange) {
+	if m.bus == nil || m.pools == nil {
+		return
+	}
+	ctx := m.parentContext()
+	evt, err := m.pools.BorrowEventInst(ctx)
+	if err != nil {
+		if m.logger != nil {
+			m.logger.Printf("strategy/%s: borrow trading change event: %v", change.Instance, err)
+		}
+		return
+	}
+	if len(providers) > 0 {
+		evt.Provider = providers[0]
+	}
+	evt.EventID = fmt.Sprintf("trading:%s:%d", change.Instance, change.At.UnixNano())
+	evt.Type = schema.ExtensionEventType
+	evt.IngestTS = change.At
+	evt.EmitTS = change.At
+	evt.Payload = change
+	if err := m.bus.Publish(ctx, evt); err != nil {
+		if m.logger != nil {
+			m.logger.Printf("strategy/%s: publish trading change: %v", change.Instance, err)
+		}
+		m.pools.ReturnEventInst(evt)
+	}
+}
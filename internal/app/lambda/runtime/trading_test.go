@@ -0,0 +1,96 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/domain/strategystore"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+func TestSetInstanceTradingTogglesRunningInstance(t *testing.T) {
+	store := &recordingStrategyStore{}
+	mgr := newTestManager(t, WithStrategyStore(store))
+	pools := pool.NewPoolManager()
+	if err := pools.RegisterPool("Event", 16, 16, func() interface{} { return new(schema.Event) }); err != nil {
+		t.Fatalf("register Event pool: %v", err)
+	}
+	bus := eventbus.NewMemoryBus(eventbus.MemoryConfig{BufferSize: 16, FanoutWorkers: 1, Pools: pools})
+	defer bus.Close()
+	mgr.bus = bus
+	mgr.pools = pools
+	_, events, err := bus.Subscribe(context.Background(), schema.ExtensionEventType)
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	spec := baseLambdaSpec()
+	if err := mgr.SetInstanceTrading(spec.ID, true, StrategyChange{}); !errors.Is(err, ErrInstanceNotFound) {
+		t.Fatalf("expected unknown instance rejected, got %v", err)
+	}
+	if _, err := mgr.Create(spec); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := mgr.SetInstanceTrading(spec.ID, true, StrategyChange{}); !errors.Is(err, ErrInstanceNotRunning) {
+		t.Fatalf("expected stopped instance rejected, got %v", err)
+	}
+	if snapshot, _ := mgr.Instance(spec.ID); !snapshot.DryRun {
+		t.Fatal("expected instances to run dry by default")
+	}
+
+	base := core.NewBaseLambda(spec.ID, core.Config{Providers: spec.Providers, DryRun: true}, nil, nil, nil, nil, nil, nil)
+	mgr.mu.Lock()
+	mgr.instances[spec.ID] = &lambdaInstance{base: base}
+	mgr.mu.Unlock()
+
+	if err := mgr.SetInstanceTrading(spec.ID, true, StrategyChange{Actor: "ops", Reason: "go live"}); err != nil {
+		t.Fatalf("SetInstanceTrading: %v", err)
+	}
+	if base.IsDryRun() || !base.IsTradingActive() {
+		t.Fatal("expected live trading enabled on the running lambda")
+	}
+	if snapshot, _ := mgr.Instance(spec.ID); snapshot.DryRun {
+		t.Fatal("expected instance snapshot to report live trading")
+	}
+	saved := store.saved[len(store.saved)-1]
+	if saved.DryRun == nil || *saved.DryRun {
+		t.Fatalf("expected live trading persisted, got %v", saved.DryRun)
+	}
+	if restored := specFromSnapshot(saved); restored.DryRunEnabled() {
+		t.Fatal("expected restored spec to trade live")
+	}
+
+	history, err := mgr.StrategyHistory(context.Background(), spec.Strategy.Identifier, 1)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("StrategyHistory: %v %+v", err, history)
+	}
+	if history[0].Action != strategystore.HistoryActionTradingEnabled || history[0].Actor != "ops" || history[0].Metadata["instance"] != spec.ID {
+		t.Fatalf("unexpected history entry %+v", history[0])
+	}
+	logs, _ := mgr.StrategyLogs(context.Background(), spec.ID, StrategyLogQuery{})
+	if len(logs) != 1 || logs[0].Message != "live trading enabled by ops: go live" {
+		t.Fatalf("unexpected log lines %+v", logs)
+	}
+
+	select {
+	case evt := <-events:
+		change, ok := evt.Payload.(TradingChange)
+		if !ok || evt.Provider != "okx-spot" || change.Kind != TradingChangeKind || !change.Enabled || change.Instance != spec.ID {
+			t.Fatalf("unexpected trading change event %+v", evt)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected trading change event")
+	}
+
+	if err := mgr.SetInstanceTrading(spec.ID, false, StrategyChange{}); err != nil {
+		t.Fatalf("SetInstanceTrading: %v", err)
+	}
+	if !base.IsDryRun() || base.IsTradingActive() {
+		t.Fatal("expected dry-run mode after disabling trading")
+	}
+}
@@ -0,0 +1,415 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/domain/usagestore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+var (
+	// ErrUsageDisabled is returned by usage reports when usage metering is disabled.
+	ErrUsageDisabled = errors.New("usage metering disabled")
+	// ErrInvalidUsageRange is returned when a usage report starts after it ends.
+	ErrInvalidUsageRange = errors.New("invalid usage range")
+)
+
+const (
+	usageDay           = 24 * time.Hour
+	defaultUsageDays   = 30
+	usagePurgeInterval = time.Hour
+	usageFlushTimeout  = 10 * time.Second
+)
+
+// UsageQuery selects the days and grouping of a usage report. From and To are inclusive UTC days.
+type UsageQuery struct {
+	From time.Time
+	To   time.Time
+	// GroupBy is the instance label to group by; empty uses strategies.usage.groupBy.
+	GroupBy string
+	// Daily adds a per-day breakdown to every group.
+	Daily bool
+}
+
+// UsageTotals sums resource usage.
+type UsageTotals struct {
+	Events    int64 `json:"events"`
+	Orders    int64 `json:"orders"`
+	HandlerMs int64 `json:"handlerMs"`
+}
+
+// UsageReport is the resource usage of the instances over a range of days, grouped by a label.
+type UsageReport struct {
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	GroupBy string       `json:"groupBy"`
+	Totals  UsageTotals  `json:"totals"`
+	Groups  []UsageGroup `json:"groups"`
+}
+
+// UsageGroup is the usage of the instances sharing a label value. Instances without the label
+// form the group with an empty value.
+type UsageGroup struct {
+	Group string `json:"group"`
+	UsageTotals
+	Instances []UsageInstance `json:"instances"`
+	Days      []UsageDay      `json:"days,omitempty"`
+}
+
+// UsageInstance is the usage of one instance within a group.
+type UsageInstance struct {
+	Instance string `json:"instance"`
+	Strategy string `json:"strategy,omitempty"`
+	UsageTotals
+}
+
+// UsageDay is the usage of a group on one day.
+type UsageDay struct {
+	Day string `json:"day"`
+	UsageTotals
+}
+
+// WithUsageStore persists metered instance usage. Without a store usage is kept in memory.
+func WithUsageStore(store usagestore.Store) Option {
+	return func(m *Manager) {
+		m.usageStore = store
+	}
+}
+
+type usageKey struct {
+	day      time.Time
+	instance string
+}
+
+// usageMeter folds the cumulative usage counters of instances into per-day records.
+type usageMeter struct {
+	mu sync.Mutex
+	// seen is the usage of each live lambda already folded into pending.
+	seen map[*core.BaseLambda]core.Usage
+	// pending holds the usage not flushed yet.
+	pending map[usageKey]*usagestore.Record
+	// days holds the flushed usage when no store is configured.
+	days map[usageKey]*usagestore.Record
+	// purged is when days past the retention were last purged.
+	purged time.Time
+}
+
+func newUsageMeter() *usageMeter {
+	return &usageMeter{
+		mu:      sync.Mutex{},
+		seen:    make(map[*core.BaseLambda]core.Usage),
+		pending: make(map[usageKey]*usagestore.Record),
+		days:    make(map[usageKey]*usagestore.Record),
+		purged:  time.Time{},
+	}
+}
+
+// fold adds the usage of base since it was last folded to the instance's record for day.
+func (u *usageMeter) fold(day time.Time, spec config.LambdaSpec, base *core.BaseLambda) {
+	current := base.Usage()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delta := current.Sub(u.seen[base])
+	u.seen[base] = current
+	if delta.IsZero() {
+		return
+	}
+	addUsage(u.pending, usagestore.Record{
+		Day:         day,
+		Instance:    spec.ID,
+		Strategy:    spec.Strategy.Identifier,
+		Labels:      maps.Clone(spec.Labels),
+		Events:      delta.Events,
+		Orders:      delta.Orders,
+		HandlerTime: delta.HandlerTime,
+	})
+}
+
+func (u *usageMeter) forget(base *core.BaseLambda) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.seen, base)
+}
+
+func (u *usageMeter) takePending() []usagestore.Record {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	out := make([]usagestore.Record, 0, len(u.pending))
+	for _, record := range u.pending {
+		out = append(out, *record)
+	}
+	u.pending = make(map[usageKey]*usagestore.Record)
+	return out
+}
+
+// restore puts records whose flush failed back in front of newer usage.
+func (u *usageMeter) restore(records []usagestore.Record) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, record := range records {
+		addUsage(u.pending, record)
+	}
+}
+
+func (u *usageMeter) keep(records []usagestore.Record, retainFrom time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, record := range records {
+		addUsage(u.days, record)
+	}
+	for key := range u.days {
+		if key.day.Before(retainFrom) {
+			delete(u.days, key)
+		}
+	}
+}
+
+func (u *usageMeter) list(query usagestore.Query) []usagestore.Record {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var out []usagestore.Record
+	for key, record := range u.days {
+		if !key.day.Before(query.From) && !key.day.After(query.To) {
+			out = append(out, *record)
+		}
+	}
+	return out
+}
+
+func addUsage(into map[usageKey]*usagestore.Record, record usagestore.Record) {
+	key := usageKey{day: record.Day, instance: record.Instance}
+	existing, ok := into[key]
+	if !ok {
+		copied := record
+		into[key] = &copied
+		return
+	}
+	existing.Strategy = record.Strategy
+	existing.Labels = record.Labels
+	existing.Events += record.Events
+	existing.Orders += record.Orders
+	existing.HandlerTime += record.HandlerTime
+}
+
+func usageDayOf(at time.Time) time.Time {
+	return at.UTC().Truncate(usageDay)
+}
+
+// collectUsage folds the usage of every running instance.
+func (m *Manager) collectUsage() {
+	type running struct {
+		spec config.LambdaSpec
+		base *core.BaseLambda
+	}
+	m.mu.RLock()
+	instances := make([]running, 0, len(m.instances))
+	for id, inst := range m.instances {
+		if inst == nil || inst.base == nil {
+			continue
+		}
+		instances = append(instances, running{spec: m.specs[id], base: inst.base})
+	}
+	m.mu.RUnlock()
+	day := usageDayOf(m.clock())
+	for _, inst := range instances {
+		m.usage.fold(day, inst.spec, inst.base)
+	}
+}
+
+// retireUsage folds the final usage of a stopping instance.
+func (m *Manager) retireUsage(id string, spec config.LambdaSpec, base *core.BaseLambda) {
+	if !m.usageCfg.Enabled || base == nil {
+		return
+	}
+	spec.ID = id
+	m.usage.fold(usageDayOf(m.clock()), spec, base)
+	m.usage.forget(base)
+}
+
+// FlushUsage folds the usage of running instances and writes everything not yet written to the
+// usage store, or to memory without one.
+func (m *Manager) FlushUsage(ctx context.Context) error {
+	if !m.usageCfg.Enabled {
+		return ErrUsageDisabled
+	}
+	m.collectUsage()
+	records := m.usage.takePending()
+	retainFrom := usageDayOf(m.clock().Add(-m.usageCfg.Retention))
+	if m.usageStore == nil {
+		m.usage.keep(records, retainFrom)
+		return nil
+	}
+	if err := m.usageStore.AddUsage(ctx, records); err != nil {
+		m.usage.restore(records)
+		return fmt.Errorf("flush usage: %w", err)
+	}
+	return nil
+}
+
+// StartUsageMetering flushes instance usage every configured interval and purges days past the
+// retention hourly until ctx is cancelled, flushing once more on the way out.
+func (m *Manager) StartUsageMetering(ctx context.Context) {
+	if m == nil || !m.usageCfg.Enabled || m.usageCfg.Interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(m.usageCfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				flushCtx, cancel := context.WithTimeout(context.Background(), usageFlushTimeout)
+				if err := m.FlushUsage(flushCtx); err != nil {
+					m.logger.Printf("usage: final flush failed: %v", err)
+				}
+				cancel()
+				return
+			case <-ticker.C:
+				if err := m.FlushUsage(ctx); err != nil && ctx.Err() == nil {
+					m.logger.Printf("usage: %v", err)
+				}
+				m.purgeUsage(ctx)
+			}
+		}
+	}()
+}
+
+func (m *Manager) purgeUsage(ctx context.Context) {
+	if m.usageStore == nil || m.usageCfg.Retention <= 0 {
+		return
+	}
+	now := m.clock()
+	if now.Sub(m.usage.purged) < usagePurgeInterval {
+		return
+	}
+	m.usage.purged = now
+	removed, err := m.usageStore.PurgeUsage(ctx, usageDayOf(now.Add(-m.usageCfg.Retention)))
+	if err != nil {
+		if ctx.Err() == nil {
+			m.logger.Printf("usage: purge failed: %v", err)
+		}
+		return
+	}
+	if removed > 0 {
+		m.logger.Printf("usage: purged %d day record(s) older than %s", removed, m.usageCfg.Retention)
+	}
+}
+
+// UsageReport flushes pending usage and reports the usage of the queried days grouped by an
+// instance label. The range defaults to the last 30 days.
+func (m *Manager) UsageReport(ctx context.Context, query UsageQuery) (UsageReport, error) {
+	var empty UsageReport
+	if err := m.FlushUsage(ctx); err != nil {
+		return empty, err
+	}
+	to := usageDayOf(m.clock())
+	if !query.To.IsZero() {
+		to = usageDayOf(query.To)
+	}
+	from := to.Add(-(defaultUsageDays - 1) * usageDay)
+	if !query.From.IsZero() {
+		from = usageDayOf(query.From)
+	}
+	if from.After(to) {
+		return empty, fmt.Errorf("%w: from %s is after to %s", ErrInvalidUsageRange, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	}
+	groupBy := strings.ToLower(strings.TrimSpace(query.GroupBy))
+	if groupBy == "" {
+		groupBy = m.usageCfg.GroupBy
+	}
+
+	var records []usagestore.Record
+	if m.usageStore != nil {
+		stored, err := m.usageStore.ListUsage(ctx, usagestore.Query{From: from, To: to})
+		if err != nil {
+			return empty, fmt.Errorf("list usage: %w", err)
+		}
+		records = stored
+	} else {
+		records = m.usage.list(usagestore.Query{From: from, To: to})
+	}
+	return buildUsageReport(records, from, to, groupBy, query.Daily), nil
+}
+
+type usageSum struct {
+	events  int64
+	orders  int64
+	handler time.Duration
+}
+
+func (s *usageSum) add(record usagestore.Record) {
+	s.events += record.Events
+	s.orders += record.Orders
+	s.handler += record.HandlerTime
+}
+
+func (s usageSum) totals() UsageTotals {
+	return UsageTotals{Events: s.events, Orders: s.orders, HandlerMs: s.handler.Milliseconds()}
+}
+
+func buildUsageReport(records []usagestore.Record, from, to time.Time, groupBy string, daily bool) UsageReport {
+	type instanceSum struct {
+		strategy string
+		sum      usageSum
+	}
+	type groupSum struct {
+		sum       usageSum
+		instances map[string]*instanceSum
+		days      map[time.Time]*usageSum
+	}
+	var total usageSum
+	groups := make(map[string]*groupSum)
+	for _, record := range records {
+		name := record.Labels[groupBy]
+		group, ok := groups[name]
+		if !ok {
+			group = &groupSum{sum: usageSum{events: 0, orders: 0, handler: 0}, instances: make(map[string]*instanceSum), days: make(map[time.Time]*usageSum)}
+			groups[name] = group
+		}
+		inst, ok := group.instances[record.Instance]
+		if !ok {
+			inst = &instanceSum{strategy: record.Strategy, sum: usageSum{events: 0, orders: 0, handler: 0}}
+			group.instances[record.Instance] = inst
+		}
+		day, ok := group.days[record.Day]
+		if !ok {
+			day = new(usageSum)
+			group.days[record.Day] = day
+		}
+		total.add(record)
+		group.sum.add(record)
+		inst.sum.add(record)
+		day.add(record)
+	}
+
+	report := UsageReport{
+		From:    from.Format(time.DateOnly),
+		To:      to.Format(time.DateOnly),
+		GroupBy: groupBy,
+		Totals:  total.totals(),
+		Groups:  make([]UsageGroup, 0, len(groups)),
+	}
+	for _, name := range slices.Sorted(maps.Keys(groups)) {
+		group := groups[name]
+		entry := UsageGroup{Group: name, UsageTotals: group.sum.totals(), Instances: make([]UsageInstance, 0, len(group.instances)), Days: nil}
+		for _, id := range slices.Sorted(maps.Keys(group.instances)) {
+			inst := group.instances[id]
+			entry.Instances = append(entry.Instances, UsageInstance{Instance: id, Strategy: inst.strategy, UsageTotals: inst.sum.totals()})
+		}
+		if daily {
+			for _, day := range slices.SortedFunc(maps.Keys(group.days), time.Time.Compare) {
+				entry.Days = append(entry.Days, UsageDay{Day: day.Format(time.DateOnly), UsageTotals: group.days[day].totals()})
+			}
+		}
+		report.Groups = append(report.Groups, entry)
+	}
+	return report
+}
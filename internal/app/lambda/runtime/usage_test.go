@@ -0,0 +1,92 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/usagestore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type stubUsageStore struct {
+	added   []usagestore.Record
+	failAdd bool
+}
+
+func (s *stubUsageStore) AddUsage(_ context.Context, records []usagestore.Record) error {
+	if s.failAdd {
+		return errors.New("database down")
+	}
+	s.added = append(s.added, records...)
+	return nil
+}
+
+func (s *stubUsageStore) ListUsage(_ context.Context, query usagestore.Query) ([]usagestore.Record, error) {
+	var out []usagestore.Record
+	for _, record := range s.added {
+		if !record.Day.Before(query.From) && !record.Day.After(query.To) {
+			out = append(out, record)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubUsageStore) PurgeUsage(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestUsageReportGroupsByLabel(t *testing.T) {
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	records := []usagestore.Record{
+		{Day: day, Instance: "alpha", Strategy: "grid", Labels: map[string]string{"team": "desk-a"}, Events: 10, Orders: 2, HandlerTime: 1500 * time.Millisecond},
+		{Day: day.Add(usageDay), Instance: "alpha", Strategy: "grid", Labels: map[string]string{"team": "desk-a"}, Events: 5, Orders: 1, HandlerTime: 500 * time.Millisecond},
+		{Day: day, Instance: "beta", Strategy: "momentum", Labels: map[string]string{"team": "desk-a"}, Events: 1},
+		{Day: day, Instance: "gamma", Strategy: "noop", Events: 7},
+	}
+	report := buildUsageReport(records, day, day.Add(usageDay), "team", true)
+	if report.From != "2026-10-15" || report.To != "2026-10-16" || report.Totals.Events != 23 || report.Totals.HandlerMs != 2000 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if len(report.Groups) != 2 || report.Groups[0].Group != "" || report.Groups[1].Group != "desk-a" {
+		t.Fatalf("unexpected groups %+v", report.Groups)
+	}
+	desk := report.Groups[1]
+	if desk.Events != 16 || desk.Orders != 3 || len(desk.Instances) != 2 || desk.Instances[0].Instance != "alpha" || desk.Instances[0].Events != 15 {
+		t.Fatalf("unexpected desk-a usage %+v", desk)
+	}
+	if len(desk.Days) != 2 || desk.Days[0].Day != "2026-10-15" || desk.Days[0].Events != 11 {
+		t.Fatalf("unexpected daily breakdown %+v", desk.Days)
+	}
+}
+
+func TestUsageReportFlushesPendingUsage(t *testing.T) {
+	store := &stubUsageStore{}
+	manager := newTestManager(t, WithUsageStore(store))
+	manager.usageCfg = config.StrategyUsageConfig{Enabled: true, Interval: time.Minute, Retention: time.Hour, GroupBy: "team"}
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	manager.clock = func() time.Time { return now }
+	addUsage(manager.usage.pending, usagestore.Record{Day: usageDayOf(now), Instance: "alpha", Labels: map[string]string{"team": "desk-a"}, Events: 3})
+
+	store.failAdd = true
+	if _, err := manager.UsageReport(context.Background(), UsageQuery{}); err == nil {
+		t.Fatal("expected flush failure reported")
+	}
+	store.failAdd = false
+	report, err := manager.UsageReport(context.Background(), UsageQuery{})
+	if err != nil {
+		t.Fatalf("UsageReport: %v", err)
+	}
+	if len(store.added) != 1 || report.Totals.Events != 3 || report.From != "2026-09-17" || report.To != "2026-10-16" {
+		t.Fatalf("expected restored usage flushed once, got %+v and %+v", store.added, report)
+	}
+	if _, err := manager.UsageReport(context.Background(), UsageQuery{From: now.Add(usageDay)}); !errors.Is(err, ErrInvalidUsageRange) {
+		t.Fatalf("expected invalid range, got %v", err)
+	}
+
+	manager.usageCfg.Enabled = false
+	if _, err := manager.UsageReport(context.Background(), UsageQuery{}); !errors.Is(err, ErrUsageDisabled) {
+		t.Fatalf("expected usage disabled, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package runtime
+
+import (
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+)
+
+// warmupConfigFromStrategy extracts the start preconditions from the strategy config. Recognised
+// keys are warmup_market_data (a duration string such as "30s" or a number of seconds),
+// warmup_balances and warmup_provider_running (bools); missing keys leave the check off.
+func (m *Manager) warmupConfigFromStrategy(cfg map[string]any) core.WarmupConfig {
+	var warmup core.WarmupConfig
+	if within, ok := durationFromConfig(cfg["warmup_market_data"]); ok {
+		warmup.MarketDataWithin = within
+	}
+	warmup.RequireBalances, _ = cfg["warmup_balances"].(bool)
+	if running, _ := cfg["warmup_provider_running"].(bool); running && m.providers != nil {
+		warmup.ProviderRunning = func(provider string) bool {
+			_, ok := m.providers.Provider(provider)
+			return ok
+		}
+	}
+	return warmup
+}
+
+// instanceWarmupLocked reports the start preconditions of a running instance. Callers must hold mu.
+func (m *Manager) instanceWarmupLocked(id string) *core.WarmupStatus {
+	inst, ok := m.instances[id]
+	if !ok || inst.base == nil {
+		return nil
+	}
+	status := inst.base.Warmup()
+	return &status
+}
@@ -0,0 +1,25 @@
+package runtime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWarmupConfigFromStrategy(t *testing.T) {
+	mgr := newTestManager(t)
+	cfg := mgr.warmupConfigFromStrategy(map[string]any{
+		"warmup_market_data":      "15s",
+		"warmup_balances":         true,
+		"warmup_provider_running": true,
+	})
+	if cfg.MarketDataWithin != 15*time.Second || !cfg.RequireBalances {
+		t.Fatalf("unexpected warmup config %+v", cfg)
+	}
+	if cfg.ProviderRunning != nil && cfg.ProviderRunning("binance") {
+		t.Fatal("expected an unknown provider to be reported as not running")
+	}
+
+	if cfg := mgr.warmupConfigFromStrategy(map[string]any{}); cfg.MarketDataWithin != 0 || cfg.RequireBalances || cfg.ProviderRunning != nil {
+		t.Fatalf("expected no preconditions by default, got %+v", cfg)
+	}
+}
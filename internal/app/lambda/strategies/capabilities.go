@@ -0,0 +1,69 @@
+package strategies
+
+import (
+	"sort"
+	"strings"
+)
+
+// Capability names something a strategy revision needs from the gateway beyond market data.
+// Revisions declare them in metadata.capabilities, and the environment policy decides which
+// ones instances may be created with.
+type Capability string
+
+const (
+	// CapabilityLiveTrading marks revisions that submit orders to venues.
+	CapabilityLiveTrading Capability = "live_trading"
+	// CapabilityHTTPAccess marks revisions that call external HTTP endpoints.
+	CapabilityHTTPAccess Capability = "http_access"
+	// CapabilityStateStorage marks revisions that persist state across restarts.
+	CapabilityStateStorage Capability = "state_storage"
+	// CapabilityCrossProvider marks revisions that trade or consume feeds across providers.
+	CapabilityCrossProvider Capability = "cross_provider"
+)
+
+var knownCapabilities = map[Capability]struct{}{
+	CapabilityLiveTrading:   {},
+	CapabilityHTTPAccess:    {},
+	CapabilityStateStorage:  {},
+	CapabilityCrossProvider: {},
+}
+
+// KnownCapability reports whether the capability is one the gateway understands.
+func KnownCapability(capability Capability) bool {
+	_, ok := knownCapabilities[capability]
+	return ok
+}
+
+// Capabilities lists every capability the gateway understands, sorted by name.
+func Capabilities() []Capability {
+	out := make([]Capability, 0, len(knownCapabilities))
+	for capability := range knownCapabilities {
+		out = append(out, capability)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// NormalizeCapabilities lower-cases, trims and de-duplicates capabilities, keeping their order.
+func NormalizeCapabilities(capabilities []Capability) []Capability {
+	if len(capabilities) == 0 {
+		return nil
+	}
+	out := make([]Capability, 0, len(capabilities))
+	seen := make(map[Capability]struct{}, len(capabilities))
+	for _, raw := range capabilities {
+		capability := Capability(strings.ToLower(strings.TrimSpace(string(raw))))
+		if capability == "" {
+			continue
+		}
+		if _, dup := seen[capability]; dup {
+			continue
+		}
+		seen[capability] = struct{}{}
+		out = append(out, capability)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
@@ -20,6 +20,9 @@ type Metadata struct {
 	Description string             `json:"description,omitempty"`
 	Config      []ConfigField      `json:"config"`
 	Events      []schema.EventType `json:"events"`
+	// Capabilities lists what the revision needs beyond market data; the environment policy
+	// rejects instances whose revision requires a capability it does not allow.
+	Capabilities []Capability `json:"capabilities,omitempty"`
 }
 
 var dryRunConfigField = ConfigField{
@@ -56,5 +59,6 @@ func CloneMetadata(meta Metadata) Metadata {
 	clone := meta
 	clone.Config = CloneConfigFields(meta.Config)
 	clone.Events = append([]schema.EventType(nil), meta.Events...)
+	clone.Capabilities = append([]Capability(nil), meta.Capabilities...)
 	return clone
 }
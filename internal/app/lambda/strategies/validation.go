@@ -72,6 +72,15 @@ func ValidateMetadata(meta Metadata) []MetadataIssue {
 		}
 	}
 
+	for idx, capability := range meta.Capabilities {
+		if !KnownCapability(capability) {
+			issues = append(issues, MetadataIssue{
+				Path:    fmt.Sprintf("metadata.capabilities[%d]", idx),
+				Message: fmt.Sprintf("unsupported capability %q", capability),
+			})
+		}
+	}
+
 	return issues
 }
 
@@ -79,6 +88,7 @@ func isValidEventType(evt schema.EventType) bool {
 	trimmed := schema.EventType(strings.TrimSpace(string(evt)))
 	switch trimmed {
 	case schema.EventTypeBookSnapshot,
+		schema.EventTypeBookDelta,
 		schema.EventTypeTrade,
 		schema.EventTypeTicker,
 		schema.EventTypeExecReport,
@@ -0,0 +1,287 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// ErrOrderCircuitOpen matches the OrderCircuitError of orders failed fast by an open circuit.
+var ErrOrderCircuitOpen = errors.New("provider order circuit open")
+
+// CircuitState is the state of a provider's order circuit.
+type CircuitState string
+
+const (
+	// CircuitClosed submits orders normally.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen fails orders without contacting the venue until the cooldown ends.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets one probe order through to decide whether to close or reopen.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// OrderCircuitError reports an order failed fast because the provider's circuit is open.
+type OrderCircuitError struct {
+	Provider string
+	// Reason describes what opened the circuit.
+	Reason   string
+	OpenedAt time.Time
+	// RetryAt is when the circuit lets a probe order through.
+	RetryAt time.Time
+}
+
+func (e *OrderCircuitError) Error() string {
+	return fmt.Sprintf("provider %s order circuit open since %s (%s); retry after %s",
+		e.Provider, e.OpenedAt.UTC().Format(time.RFC3339), e.Reason, e.RetryAt.UTC().Format(time.RFC3339))
+}
+
+// Unwrap makes the error match ErrOrderCircuitOpen.
+func (e *OrderCircuitError) Unwrap() error {
+	return ErrOrderCircuitOpen
+}
+
+// OrderCircuitStatus describes a provider's order circuit.
+type OrderCircuitStatus struct {
+	Provider            string       `json:"provider"`
+	Enabled             bool         `json:"enabled"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	LastError           string       `json:"lastError,omitempty"`
+	LastLatencyMs       int64        `json:"lastLatencyMs"`
+	Reason              string       `json:"reason,omitempty"`
+	OpenedAt            *time.Time   `json:"openedAt,omitempty"`
+	RetryAt             *time.Time   `json:"retryAt,omitempty"`
+	// Rejected counts the orders failed fast since the circuit last opened.
+	Rejected int64 `json:"rejected"`
+}
+
+// WithOrderCircuitBreaker fails orders fast on providers whose submissions keep failing or
+// stalling, so strategies do not hammer a broken venue and exhaust its rate limits.
+func WithOrderCircuitBreaker(cfg config.OrderCircuitBreakerConfig) Option {
+	return func(m *Manager) {
+		m.circuitCfg = cfg
+	}
+}
+
+// OrderCircuit returns the order circuit status of a provider.
+func (m *Manager) OrderCircuit(name string) (OrderCircuitStatus, error) {
+	var empty OrderCircuitStatus
+	name = strings.TrimSpace(name)
+	if !m.HasProvider(name) {
+		return empty, fmt.Errorf("%w: %s", ErrProviderNotFound, name)
+	}
+	circuit := m.orderCircuit(name)
+	if circuit == nil {
+		return OrderCircuitStatus{
+			Provider:            name,
+			Enabled:             false,
+			State:               CircuitClosed,
+			ConsecutiveFailures: 0,
+			LastError:           "",
+			LastLatencyMs:       0,
+			Reason:              "",
+			OpenedAt:            nil,
+			RetryAt:             nil,
+			Rejected:            0,
+		}, nil
+	}
+	return circuit.status(name), nil
+}
+
+// ResetOrderCircuit closes a provider's order circuit, for operators who know the venue recovered.
+func (m *Manager) ResetOrderCircuit(name string) (OrderCircuitStatus, error) {
+	var empty OrderCircuitStatus
+	name = strings.TrimSpace(name)
+	if !m.HasProvider(name) {
+		return empty, fmt.Errorf("%w: %s", ErrProviderNotFound, name)
+	}
+	if circuit := m.orderCircuit(name); circuit != nil {
+		circuit.reset()
+		m.logger.Printf("provider %s: order circuit reset", name)
+	}
+	return m.OrderCircuit(name)
+}
+
+// orderCircuit returns the circuit of a provider, creating it on first use; nil when disabled.
+func (m *Manager) orderCircuit(name string) *orderCircuit {
+	if !m.circuitCfg.Enabled {
+		return nil
+	}
+	m.circuitsMu.Lock()
+	defer m.circuitsMu.Unlock()
+	circuit, ok := m.circuits[name]
+	if !ok {
+		circuit = newOrderCircuit(m.circuitCfg)
+		m.circuits[name] = circuit
+	}
+	return circuit
+}
+
+func (m *Manager) dropOrderCircuit(name string) {
+	m.circuitsMu.Lock()
+	delete(m.circuits, name)
+	m.circuitsMu.Unlock()
+}
+
+// submitThroughCircuit submits an order unless the provider's circuit is open and feeds the
+// outcome back into the circuit.
+func (m *Manager) submitThroughCircuit(ctx context.Context, name string, submit func() error) error {
+	circuit := m.orderCircuit(name)
+	if circuit == nil {
+		return submit()
+	}
+	if err := circuit.admit(name, time.Now()); err != nil {
+		return err
+	}
+	started := time.Now()
+	err := submit()
+	if changed, state := circuit.record(time.Now(), time.Since(started), err, ctx.Err() != nil); changed {
+		status := circuit.status(name)
+		m.logger.Printf("provider %s: order circuit %s (%s)", name, state, status.Reason)
+	}
+	return err
+}
+
+type orderCircuit struct {
+	cfg config.OrderCircuitBreakerConfig
+
+	mu          sync.Mutex
+	state       CircuitState
+	failures    int
+	lastErr     string
+	lastLatency time.Duration
+	reason      string
+	openedAt    time.Time
+	retryAt     time.Time
+	// probing is set while the half-open probe order is in flight.
+	probing  bool
+	rejected int64
+}
+
+func newOrderCircuit(cfg config.OrderCircuitBreakerConfig) *orderCircuit {
+	return &orderCircuit{
+		cfg:         cfg,
+		mu:          sync.Mutex{},
+		state:       CircuitClosed,
+		failures:    0,
+		lastErr:     "",
+		lastLatency: 0,
+		reason:      "",
+		openedAt:    time.Time{},
+		retryAt:     time.Time{},
+		probing:     false,
+		rejected:    0,
+	}
+}
+
+// admit lets an order through unless the circuit is open, moving to half-open once the cooldown
+// has elapsed. Only one probe order is admitted while half-open.
+func (c *orderCircuit) admit(provider string, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == CircuitOpen && !now.Before(c.retryAt) {
+		c.state = CircuitHalfOpen
+	}
+	switch c.state {
+	case CircuitClosed:
+		return nil
+	case CircuitHalfOpen:
+		if !c.probing {
+			c.probing = true
+			return nil
+		}
+	case CircuitOpen:
+	}
+	c.rejected++
+	return &OrderCircuitError{Provider: provider, Reason: c.reason, OpenedAt: c.openedAt, RetryAt: c.retryAt}
+}
+
+// record applies the outcome of an admitted order and reports whether the state changed. Orders
+// abandoned by their caller say nothing about the venue and only release the probe.
+func (c *orderCircuit) record(now time.Time, latency time.Duration, err error, cancelled bool) (bool, CircuitState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	probe := c.state == CircuitHalfOpen
+	if probe {
+		c.probing = false
+	}
+	if cancelled {
+		return false, c.state
+	}
+	c.lastLatency = latency
+	slow := c.cfg.LatencyThreshold > 0 && latency > c.cfg.LatencyThreshold
+	if err == nil && !slow {
+		c.failures = 0
+		if probe {
+			c.state = CircuitClosed
+			c.reason = ""
+			return true, c.state
+		}
+		return false, c.state
+	}
+
+	c.failures++
+	if err != nil {
+		c.lastErr = err.Error()
+	} else {
+		c.lastErr = fmt.Sprintf("submission took %s", latency.Round(time.Millisecond))
+	}
+	if !probe && c.failures < c.cfg.FailureThreshold {
+		return false, c.state
+	}
+	if probe {
+		c.reason = "probe order failed: " + c.lastErr
+	} else {
+		c.reason = fmt.Sprintf("%d consecutive failures, last: %s", c.failures, c.lastErr)
+	}
+	c.state = CircuitOpen
+	c.openedAt = now
+	c.retryAt = now.Add(c.cfg.Cooldown)
+	c.rejected = 0
+	return true, c.state
+}
+
+func (c *orderCircuit) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state = CircuitClosed
+	c.failures = 0
+	c.reason = ""
+	c.probing = false
+	c.openedAt = time.Time{}
+	c.retryAt = time.Time{}
+	c.rejected = 0
+}
+
+func (c *orderCircuit) status(provider string) OrderCircuitStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state := c.state
+	if state == CircuitOpen && !time.Now().Before(c.retryAt) {
+		state = CircuitHalfOpen
+	}
+	out := OrderCircuitStatus{
+		Provider:            provider,
+		Enabled:             true,
+		State:               state,
+		ConsecutiveFailures: c.failures,
+		LastError:           c.lastErr,
+		LastLatencyMs:       c.lastLatency.Milliseconds(),
+		Reason:              c.reason,
+		OpenedAt:            nil,
+		RetryAt:             nil,
+		Rejected:            c.rejected,
+	}
+	if state != CircuitClosed {
+		openedAt, retryAt := c.openedAt, c.retryAt
+		out.OpenedAt = &openedAt
+		out.RetryAt = &retryAt
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type failingProviderInstance struct {
+	testProviderInstance
+	err   error
+	calls int
+}
+
+func (i *failingProviderInstance) SubmitOrder(context.Context, schema.OrderRequest) error {
+	i.calls++
+	return i.err
+}
+
+func TestManagerSubmitOrder_OpensOrderCircuit(t *testing.T) {
+	inst := &failingProviderInstance{testProviderInstance: testProviderInstance{name: "binance"}, err: errors.New("503 service unavailable")}
+	manager := newDrainTestManager(t, &drainOrderStore{}, inst)
+	manager.circuitCfg = config.OrderCircuitBreakerConfig{Enabled: true, FailureThreshold: 3, Cooldown: time.Hour}
+	req := schema.OrderRequest{Provider: "binance", Symbol: "BTC-USDT"}
+
+	for range 3 {
+		if err := manager.SubmitOrder(context.Background(), req); err == nil || errors.Is(err, ErrOrderCircuitOpen) {
+			t.Fatalf("expected venue failure passed through, got %v", err)
+		}
+	}
+	err := manager.SubmitOrder(context.Background(), req)
+	var circuitErr *OrderCircuitError
+	if !errors.As(err, &circuitErr) || !errors.Is(err, ErrOrderCircuitOpen) {
+		t.Fatalf("expected fail-fast circuit error, got %v", err)
+	}
+	if inst.calls != 3 || circuitErr.Provider != "binance" || circuitErr.RetryAt.Sub(circuitErr.OpenedAt) != time.Hour {
+		t.Fatalf("unexpected circuit error %+v after %d venue calls", circuitErr, inst.calls)
+	}
+	status, err := manager.OrderCircuit("binance")
+	if err != nil || status.State != CircuitOpen || status.Rejected != 1 || status.LastError != "503 service unavailable" {
+		t.Fatalf("unexpected status %+v %v", status, err)
+	}
+
+	inst.err = nil
+	if status, err := manager.ResetOrderCircuit("binance"); err != nil || status.State != CircuitClosed {
+		t.Fatalf("expected reset to close the circuit, got %+v %v", status, err)
+	}
+	if err := manager.SubmitOrder(context.Background(), req); err != nil {
+		t.Fatalf("expected orders through after reset, got %v", err)
+	}
+	if _, err := manager.OrderCircuit("ghost"); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected unknown provider, got %v", err)
+	}
+}
+
+func TestOrderCircuitHalfOpenProbe(t *testing.T) {
+	circuit := newOrderCircuit(config.OrderCircuitBreakerConfig{Enabled: true, FailureThreshold: 2, LatencyThreshold: time.Second, Cooldown: time.Minute})
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	// Slow successes count as failures.
+	circuit.record(now, 2*time.Second, nil, false)
+	if changed, state := circuit.record(now, 3*time.Second, nil, false); !changed || state != CircuitOpen {
+		t.Fatalf("expected slow submissions to open the circuit, got %v %s", changed, state)
+	}
+	if err := circuit.admit("okx", now.Add(30*time.Second)); !errors.Is(err, ErrOrderCircuitOpen) {
+		t.Fatalf("expected orders refused during cooldown, got %v", err)
+	}
+
+	probeAt := now.Add(time.Minute)
+	if err := circuit.admit("okx", probeAt); err != nil {
+		t.Fatalf("expected probe admitted after cooldown, got %v", err)
+	}
+	if err := circuit.admit("okx", probeAt); !errors.Is(err, ErrOrderCircuitOpen) {
+		t.Fatalf("expected a single probe in flight, got %v", err)
+	}
+	if changed, state := circuit.record(probeAt, time.Millisecond, errors.New("timeout"), false); !changed || state != CircuitOpen {
+		t.Fatalf("expected failed probe to reopen, got %v %s", changed, state)
+	}
+
+	retryAt := probeAt.Add(time.Minute)
+	if err := circuit.admit("okx", retryAt); err != nil {
+		t.Fatalf("expected second probe admitted, got %v", err)
+	}
+	if changed, _ := circuit.record(retryAt, time.Millisecond, context.Canceled, true); changed {
+		t.Fatal("expected abandoned probe to leave the circuit half-open")
+	}
+	if err := circuit.admit("okx", retryAt); err != nil {
+		t.Fatalf("expected probe slot released, got %v", err)
+	}
+	if changed, state := circuit.record(retryAt, time.Millisecond, nil, false); !changed || state != CircuitClosed {
+		t.Fatalf("expected successful probe to close, got %v %s", changed, state)
+	}
+}
@@ -0,0 +1,127 @@
+package provider
+
+// configSchemaDialect is the JSON Schema draft the config-schema documents follow.
+const configSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ConfigSchema is a JSON Schema document describing an adapter's config, derived from its settings
+// schema so frontends can render provider forms. Nested settings use the same shape; only the root
+// carries $schema and title. Order lists properties in the adapter's declaration order, since JSON
+// objects carry none. Go durations use the "go-duration" format and secrets are write-only
+// "password" fields.
+type ConfigSchema struct {
+	Schema               string                  `json:"$schema,omitempty"`
+	Title                string                  `json:"title,omitempty"`
+	Type                 string                  `json:"type"`
+	Format               string                  `json:"format,omitempty"`
+	Description          string                  `json:"description,omitempty"`
+	Default              any                     `json:"default,omitempty"`
+	Enum                 []string                `json:"enum,omitempty"`
+	WriteOnly            bool                    `json:"writeOnly,omitempty"`
+	Properties           map[string]ConfigSchema `json:"properties,omitempty"`
+	AdditionalProperties any                     `json:"additionalProperties,omitempty"`
+	Required             []string                `json:"required,omitempty"`
+	Order                []string                `json:"x-order,omitempty"`
+}
+
+// BuildConfigSchema derives the config JSON Schema of an adapter from its metadata.
+func BuildConfigSchema(meta AdapterMetadata) ConfigSchema {
+	title := meta.DisplayName
+	if title == "" {
+		title = meta.Identifier
+	}
+	schema := objectSchema(meta.Description, meta.SettingsSchema)
+	schema.Schema = configSchemaDialect
+	schema.Title = title
+	return schema
+}
+
+func objectSchema(description string, settings []AdapterSetting) ConfigSchema {
+	properties := make(map[string]ConfigSchema, len(settings))
+	order := make([]string, 0, len(settings))
+	var required []string
+	for _, setting := range settings {
+		properties[setting.Name] = settingSchema(setting)
+		order = append(order, setting.Name)
+		if setting.Required {
+			required = append(required, setting.Name)
+		}
+	}
+	return ConfigSchema{
+		Schema:               "",
+		Title:                "",
+		Type:                 "object",
+		Format:               "",
+		Description:          description,
+		Default:              nil,
+		Enum:                 nil,
+		WriteOnly:            false,
+		Properties:           properties,
+		AdditionalProperties: false,
+		Required:             required,
+		Order:                order,
+	}
+}
+
+func settingSchema(setting AdapterSetting) ConfigSchema {
+	if setting.Type == SettingTypeMap {
+		var property ConfigSchema
+		switch {
+		case setting.Keyed:
+			entry := objectSchema("", setting.Fields)
+			property = ConfigSchema{
+				Schema:               "",
+				Title:                "",
+				Type:                 "object",
+				Format:               "",
+				Description:          setting.Description,
+				Default:              nil,
+				Enum:                 nil,
+				WriteOnly:            false,
+				Properties:           nil,
+				AdditionalProperties: entry,
+				Required:             nil,
+				Order:                nil,
+			}
+		case len(setting.Fields) > 0:
+			property = objectSchema(setting.Description, setting.Fields)
+		default:
+			property = objectSchema(setting.Description, nil)
+			property.Properties = nil
+			property.Order = nil
+			property.AdditionalProperties = true
+		}
+		property.Default = setting.Default
+		return property
+	}
+	property := ConfigSchema{
+		Schema:               "",
+		Title:                "",
+		Type:                 "string",
+		Format:               "",
+		Description:          setting.Description,
+		Default:              setting.Default,
+		Enum:                 append([]string(nil), setting.Enum...),
+		WriteOnly:            setting.Secret,
+		Properties:           nil,
+		AdditionalProperties: nil,
+		Required:             nil,
+		Order:                nil,
+	}
+	switch setting.Type {
+	case SettingTypeInt:
+		property.Type = "integer"
+	case SettingTypeFloat:
+		property.Type = "number"
+	case SettingTypeBool:
+		property.Type = "boolean"
+	case SettingTypeDuration:
+		property.Format = "go-duration"
+	}
+	if setting.Secret {
+		property.Format = "password"
+		if setting.Default == "" {
+			property.Default = nil
+		}
+	}
+	return property
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	json "github.com/goccy/go-json"
+)
+
+func TestBuildConfigSchemaDescribesSettings(t *testing.T) {
+	mode := plainSetting("trade_mode", SettingTypeString, "Account trade mode")
+	mode.Enum = []string{"cash", "cross"}
+	mode.Default = "cash"
+	accounts := plainSetting("sub_accounts", SettingTypeMap, "Named sub-accounts")
+	accounts.Keyed = true
+	accounts.Fields = []AdapterSetting{{Name: "api_key", Type: SettingTypeString, Required: true, Secret: true}}
+
+	schema := BuildConfigSchema(AdapterMetadata{
+		Identifier:     "test",
+		DisplayName:    "Test Venue",
+		SettingsSchema: append(testSettingsSchema(), mode, accounts),
+	})
+
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("marshal schema: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	if doc["$schema"] != configSchemaDialect || doc["type"] != "object" || doc["title"] != "Test Venue" || doc["additionalProperties"] != false {
+		t.Fatalf("unexpected schema header %v", doc)
+	}
+	if !reflect.DeepEqual(doc["required"], []any{"signing_seed"}) {
+		t.Fatalf("expected signing_seed required, got %v", doc["required"])
+	}
+	order, _ := doc["x-order"].([]any)
+	if len(order) != 8 || order[0] != "signing_seed" || order[7] != "sub_accounts" {
+		t.Fatalf("expected declaration order, got %v", order)
+	}
+
+	properties := doc["properties"].(map[string]any)
+	seed := properties["signing_seed"].(map[string]any)
+	if seed["writeOnly"] != true || seed["format"] != "password" {
+		t.Fatalf("expected secret to be a write-only password, got %v", seed)
+	}
+	if properties["depth"].(map[string]any)["type"] != "integer" {
+		t.Fatalf("expected depth to be an integer, got %v", properties["depth"])
+	}
+	if properties["timeout"].(map[string]any)["format"] != "go-duration" {
+		t.Fatalf("expected timeout to be a go-duration, got %v", properties["timeout"])
+	}
+	tradeMode := properties["trade_mode"].(map[string]any)
+	if !reflect.DeepEqual(tradeMode["enum"], []any{"cash", "cross"}) || tradeMode["default"] != "cash" {
+		t.Fatalf("expected enum choices and default, got %v", tradeMode)
+	}
+	reconnect := properties["reconnect"].(map[string]any)
+	if _, ok := reconnect["properties"].(map[string]any)["jitter"]; !ok {
+		t.Fatalf("expected reconnect fields, got %v", reconnect)
+	}
+	entry := properties["sub_accounts"].(map[string]any)["additionalProperties"].(map[string]any)
+	if !reflect.DeepEqual(entry["required"], []any{"api_key"}) {
+		t.Fatalf("expected keyed entries to describe their fields, got %v", entry)
+	}
+}
+
+func TestValidateAdapterSettingsChecksEnumAndKeyedEntries(t *testing.T) {
+	mode := plainSetting("trade_mode", SettingTypeString, "Account trade mode")
+	mode.Enum = []string{"cash", "cross"}
+	accounts := plainSetting("sub_accounts", SettingTypeMap, "Named sub-accounts")
+	accounts.Keyed = true
+	accounts.Fields = []AdapterSetting{{Name: "api_key", Type: SettingTypeString, Required: true, Secret: true}}
+
+	err := ValidateAdapterSettings("test", []AdapterSetting{mode, accounts}, map[string]any{
+		"trade_mode":   "margin",
+		"sub_accounts": map[string]any{"desk": map[string]any{}},
+	})
+	settingsErr, ok := err.(*SettingsError)
+	if !ok || len(settingsErr.Fields) != 2 {
+		t.Fatalf("expected two field errors, got %v", err)
+	}
+	if settingsErr.Fields[0].Field != "sub_accounts.desk.api_key" || settingsErr.Fields[1].Field != "trade_mode" {
+		t.Fatalf("unexpected fields %+v", settingsErr.Fields)
+	}
+}
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// DrainMode selects how open orders are handled while a provider is drained.
+type DrainMode string
+
+const (
+	// DrainModeCancel cancels resting orders on the venue and waits for the cancellations to settle.
+	DrainModeCancel DrainMode = "cancel"
+	// DrainModeWait leaves resting orders untouched and waits for them to complete.
+	DrainModeWait DrainMode = "wait"
+)
+
+const (
+	// DefaultDrainTimeout bounds how long a drain waits for open orders to close.
+	DefaultDrainTimeout = 30 * time.Second
+
+	drainPollInterval = 500 * time.Millisecond
+	drainOrderLimit   = 1000
+)
+
+var (
+	// ErrProviderDraining indicates that the provider is being drained and rejects new orders.
+	ErrProviderDraining = errors.New("provider draining")
+	// ErrProviderDrainTimeout indicates that open orders remained when the drain timeout elapsed.
+	ErrProviderDrainTimeout = errors.New("provider drain timed out with open orders")
+)
+
+// openOrderStates lists the persisted order states that still rest on the venue.
+var openOrderStates = []string{
+	"PENDING",
+	string(schema.ExecReportStateACK),
+	string(schema.ExecReportStatePARTIAL),
+}
+
+// WithOrderStore wires the order store used to discover open orders when draining providers.
+func WithOrderStore(store orderstore.Store) Option {
+	return func(m *Manager) {
+		m.orders = store
+	}
+}
+
+// DrainOptions configures a provider drain.
+type DrainOptions struct {
+	Mode    DrainMode
+	Timeout time.Duration
+}
+
+// DrainReport summarises the outcome of a provider drain.
+type DrainReport struct {
+	Provider          string         `json:"provider"`
+	Mode              DrainMode      `json:"mode"`
+	OpenOrders        map[string]int `json:"openOrders,omitempty"`
+	CancelledSymbols  []string       `json:"cancelledSymbols,omitempty"`
+	CancelErrors      []string       `json:"cancelErrors,omitempty"`
+	RemainingOrders   map[string]int `json:"remainingOrders,omitempty"`
+	OpenOrdersUnknown bool           `json:"openOrdersUnknown,omitempty"`
+	Removed           bool           `json:"removed"`
+	Duration          string         `json:"duration"`
+}
+
+// ParseDrainMode validates a drain mode, defaulting to cancel when empty.
+func ParseDrainMode(raw string) (DrainMode, error) {
+	switch DrainMode(strings.ToLower(strings.TrimSpace(raw))) {
+	case "", DrainModeCancel:
+		return DrainModeCancel, nil
+	case DrainModeWait:
+		return DrainModeWait, nil
+	default:
+		return "", fmt.Errorf("unsupported drain mode %q", raw)
+	}
+}
+
+// DrainAndRemove rejects new orders for the provider, cancels or waits for its open orders,
+// stops its streams and finally removes it. When open orders remain after the timeout the
+// provider is kept, order entry is re-enabled and ErrProviderDrainTimeout is returned.
+func (m *Manager) DrainAndRemove(ctx context.Context, name string, opts DrainOptions) (DrainReport, error) {
+	trimmed := strings.TrimSpace(name)
+	start := time.Now()
+	report := DrainReport{
+		Provider:          trimmed,
+		Mode:              opts.Mode,
+		OpenOrders:        nil,
+		CancelledSymbols:  nil,
+		CancelErrors:      nil,
+		RemainingOrders:   nil,
+		OpenOrdersUnknown: m.orders == nil,
+		Removed:           false,
+		Duration:          "",
+	}
+	if report.Mode == "" {
+		report.Mode = DrainModeCancel
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+	if trimmed == "" {
+		return report, fmt.Errorf("provider name required")
+	}
+
+	m.mu.Lock()
+	state, ok := m.states[trimmed]
+	if !ok {
+		m.mu.Unlock()
+		return report, fmt.Errorf("%w: %s", ErrProviderNotFound, trimmed)
+	}
+	state.draining = true
+	inst := state.instance
+	m.mu.Unlock()
+	m.logger.Printf("provider/%s: draining (mode=%s timeout=%s)", trimmed, report.Mode, timeout)
+
+	open, err := m.openOrdersBySymbol(ctx, trimmed)
+	if err != nil {
+		m.setDraining(trimmed, false)
+		return report, err
+	}
+	report.OpenOrders = open
+
+	if report.Mode == DrainModeCancel && len(open) > 0 {
+		canceller, ok := inst.(OrderCanceller)
+		if !ok {
+			m.setDraining(trimmed, false)
+			return report, fmt.Errorf("provider %s does not support order cancellation", trimmed)
+		}
+		for _, symbol := range sortedSymbols(open) {
+			if err := canceller.CancelOpenOrders(ctx, symbol); err != nil {
+				report.CancelErrors = append(report.CancelErrors, symbol+": "+redact.Error(err))
+				continue
+			}
+			report.CancelledSymbols = append(report.CancelledSymbols, symbol)
+		}
+	}
+
+	remaining, err := m.awaitOpenOrders(ctx, trimmed, open, timeout)
+	report.RemainingOrders = remaining
+	if err != nil {
+		m.setDraining(trimmed, false)
+		report.Duration = time.Since(start).String()
+		return report, err
+	}
+
+	if err := m.Remove(trimmed); err != nil {
+		m.setDraining(trimmed, false)
+		report.Duration = time.Since(start).String()
+		return report, err
+	}
+	report.Removed = true
+	report.Duration = time.Since(start).String()
+	m.logger.Printf("provider/%s: drained and removed in %s", trimmed, report.Duration)
+	return report, nil
+}
+
+func (m *Manager) setDraining(name string, draining bool) {
+	m.mu.Lock()
+	if state, ok := m.states[name]; ok {
+		state.draining = draining
+	}
+	m.mu.Unlock()
+}
+
+// awaitOpenOrders polls the order store until no open orders remain or the timeout elapses.
+func (m *Manager) awaitOpenOrders(ctx context.Context, name string, open map[string]int, timeout time.Duration) (map[string]int, error) {
+	if len(open) == 0 {
+		return nil, nil
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	remaining := open
+	for {
+		select {
+		case <-ctx.Done():
+			return remaining, fmt.Errorf("drain provider %s: %w", name, ctx.Err())
+		case <-deadline.C:
+			return remaining, fmt.Errorf("%w: %s", ErrProviderDrainTimeout, name)
+		case <-ticker.C:
+			current, err := m.openOrdersBySymbol(ctx, name)
+			if err != nil {
+				return remaining, err
+			}
+			if len(current) == 0 {
+				return nil, nil
+			}
+			remaining = current
+		}
+	}
+}
+
+func (m *Manager) openOrdersBySymbol(ctx context.Context, name string) (map[string]int, error) {
+	if m.orders == nil {
+		return nil, nil
+	}
+	records, err := m.orders.ListOrders(ctx, orderstore.OrderQuery{
+		StrategyInstance: "",
+		Provider:         name,
+		States:           openOrderStates,
+		Since:            0,
+		Until:            0,
+		Tag:              "",
+		Limit:            drainOrderLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("drain provider %s: list open orders: %w", name, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]int)
+	for _, record := range records {
+		out[record.Symbol]++
+	}
+	return out, nil
+}
+
+func sortedSymbols(counts map[string]int) []string {
+	out := make([]string, 0, len(counts))
+	for symbol := range counts {
+		out = append(out, symbol)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type drainOrderStore struct {
+	orderstore.Store
+
+	mu    sync.Mutex
+	open  []orderstore.OrderRecord
+	query orderstore.OrderQuery
+}
+
+func (s *drainOrderStore) ListOrders(_ context.Context, query orderstore.OrderQuery) ([]orderstore.OrderRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.query = query
+	return append([]orderstore.OrderRecord(nil), s.open...), nil
+}
+
+func (s *drainOrderStore) clear() {
+	s.mu.Lock()
+	s.open = nil
+	s.mu.Unlock()
+}
+
+type cancellingProviderInstance struct {
+	testProviderInstance
+	store     *drainOrderStore
+	cancelled []string
+}
+
+func (i *cancellingProviderInstance) CancelOpenOrders(_ context.Context, symbol string) error {
+	i.cancelled = append(i.cancelled, symbol)
+	i.store.clear()
+	return nil
+}
+
+func newDrainTestManager(t *testing.T, store *drainOrderStore, inst Instance) *Manager {
+	t.Helper()
+	manager := NewManager(nil, nil, nil, dispatcher.NewTable(), log.New(io.Discard, "", 0), WithOrderStore(store))
+	spec := config.ProviderSpec{
+		Name:    "binance",
+		Adapter: "binance",
+		Config:  map[string]any{"identifier": "binance"},
+	}
+	if _, err := manager.Create(context.Background(), spec, false); err != nil {
+		t.Fatalf("create provider: %v", err)
+	}
+	manager.mu.Lock()
+	state := manager.states["binance"]
+	state.running = true
+	state.status = StatusRunning
+	state.instance = inst
+	manager.mu.Unlock()
+	return manager
+}
+
+func openOrder(symbol string) orderstore.OrderRecord {
+	return orderstore.OrderRecord{Order: orderstore.Order{Provider: "binance", Symbol: symbol, State: "ACK"}}
+}
+
+func TestManagerDrainAndRemove_CancelsOpenOrders(t *testing.T) {
+	store := &drainOrderStore{open: []orderstore.OrderRecord{openOrder("ETH-USDT"), openOrder("BTC-USDT"), openOrder("BTC-USDT")}}
+	inst := &cancellingProviderInstance{testProviderInstance: testProviderInstance{name: "binance"}, store: store}
+	manager := newDrainTestManager(t, store, inst)
+
+	report, err := manager.DrainAndRemove(context.Background(), "binance", DrainOptions{Mode: DrainModeCancel, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("DrainAndRemove: %v", err)
+	}
+	if !report.Removed || manager.HasProvider("binance") {
+		t.Fatalf("expected provider removed, report=%+v", report)
+	}
+	if report.OpenOrders["BTC-USDT"] != 2 || report.OpenOrders["ETH-USDT"] != 1 {
+		t.Fatalf("unexpected open order counts: %+v", report.OpenOrders)
+	}
+	if len(inst.cancelled) != 2 || inst.cancelled[0] != "BTC-USDT" {
+		t.Fatalf("expected cancellation per symbol, got %v", inst.cancelled)
+	}
+	if store.query.Provider != "binance" || len(store.query.States) == 0 {
+		t.Fatalf("expected open-order query scoped to provider, got %+v", store.query)
+	}
+}
+
+func TestManagerDrainAndRemove_WaitTimeoutKeepsProvider(t *testing.T) {
+	store := &drainOrderStore{open: []orderstore.OrderRecord{openOrder("BTC-USDT")}}
+	manager := newDrainTestManager(t, store, &testProviderInstance{name: "binance"})
+
+	report, err := manager.DrainAndRemove(context.Background(), "binance", DrainOptions{Mode: DrainModeWait, Timeout: 50 * time.Millisecond})
+	if !errors.Is(err, ErrProviderDrainTimeout) {
+		t.Fatalf("expected drain timeout, got %v", err)
+	}
+	if report.Removed || !manager.HasProvider("binance") {
+		t.Fatalf("expected provider retained after timeout, report=%+v", report)
+	}
+	if report.RemainingOrders["BTC-USDT"] != 1 {
+		t.Fatalf("expected remaining order reported, got %+v", report.RemainingOrders)
+	}
+	req := schema.OrderRequest{Provider: "binance", Symbol: "BTC-USDT"}
+	if err := manager.SubmitOrder(context.Background(), req); err != nil {
+		t.Fatalf("expected order entry re-enabled after failed drain, got %v", err)
+	}
+}
+
+func TestManagerSubmitOrder_RejectedWhileDraining(t *testing.T) {
+	manager := newDrainTestManager(t, &drainOrderStore{}, &testProviderInstance{name: "binance"})
+	manager.setDraining("binance", true)
+
+	err := manager.SubmitOrder(context.Background(), schema.OrderRequest{Provider: "binance", Symbol: "BTC-USDT"})
+	if !errors.Is(err, ErrProviderDraining) {
+		t.Fatalf("expected draining rejection, got %v", err)
+	}
+}
@@ -6,18 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
 	"sort"
 	"strings"
 	"sync"
 
 	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/orderstore"
 	"github.com/coachpo/meltica/internal/domain/providerstore"
 	"github.com/coachpo/meltica/internal/domain/schema"
 	"github.com/coachpo/meltica/internal/infra/adapters/shared"
 	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
 	"github.com/coachpo/meltica/internal/infra/config"
 	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
 	"github.com/coachpo/meltica/internal/infra/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -36,8 +37,23 @@ type Manager struct {
 	lifecycleMu  sync.RWMutex
 	lifecycleCtx context.Context
 
-	persistence providerstore.Store
-	states      map[string]*providerState
+	persistence   providerstore.Store
+	orders        orderstore.Store
+	snapshots     orderstore.SnapshotStore
+	pendingResync map[string]orderstore.OpenOrderSnapshot
+	states        map[string]*providerState
+
+	// circuits holds the order circuit of each provider that submitted an order; circuitCfg
+	// disables them unless enabled.
+	circuitCfg config.OrderCircuitBreakerConfig
+	circuitsMu sync.Mutex
+	circuits   map[string]*orderCircuit
+
+	// queues holds the order queue of each provider that received an order action; queueCfg
+	// disables them unless enabled.
+	queueCfg config.OrderQueueConfig
+	queuesMu sync.Mutex
+	queues   map[string]*orderQueue
 
 	cacheHitCounter  metric.Int64Counter
 	cacheMissCounter metric.Int64Counter
@@ -61,6 +77,8 @@ type providerState struct {
 	cachedRoutes      []dispatcher.Route
 	cachedInstruments []schema.Instrument
 	running           bool
+	draining          bool
+	resyncing         bool
 	status            Status
 	startupErr        error
 }
@@ -111,6 +129,11 @@ var (
 	ErrProviderStarting = errors.New("provider starting")
 	// ErrProviderNotRunning indicates that the provider is not currently running.
 	ErrProviderNotRunning = errors.New("provider not running")
+	// ErrTimeInForceUnsupported indicates that the provider's adapter cannot submit the order's
+	// time in force.
+	ErrTimeInForceUnsupported = errors.New("time in force not supported")
+	// ErrOrderFlagInvalid indicates that a post-only or reduce-only flag cannot apply to the order.
+	ErrOrderFlagInvalid = errors.New("invalid order flag")
 )
 
 // NewManager creates a new provider manager.
@@ -119,7 +142,7 @@ func NewManager(reg *Registry, pools *pool.PoolManager, bus eventbus.Bus, table
 		reg = NewRegistry()
 	}
 	if logger == nil {
-		logger = log.New(os.Stdout, "provider-manager ", log.LstdFlags|log.Lmicroseconds)
+		logger = log.New(redact.Stdout, "provider-manager ", log.LstdFlags|log.Lmicroseconds)
 	}
 	if table == nil {
 		table = dispatcher.NewTable()
@@ -135,6 +158,15 @@ func NewManager(reg *Registry, pools *pool.PoolManager, bus eventbus.Bus, table
 		lifecycleCtx:     context.Background(),
 		states:           make(map[string]*providerState),
 		persistence:      nil,
+		orders:           nil,
+		snapshots:        nil,
+		pendingResync:    nil,
+		circuitCfg:       config.OrderCircuitBreakerConfig{Enabled: false, FailureThreshold: 0, LatencyThreshold: 0, Cooldown: 0},
+		circuitsMu:       sync.Mutex{},
+		circuits:         make(map[string]*orderCircuit),
+		queueCfg:         config.OrderQueueConfig{Enabled: false, Size: 0, Concurrency: 0},
+		queuesMu:         sync.Mutex{},
+		queues:           make(map[string]*orderQueue),
 		cacheHitCounter:  nil,
 		cacheMissCounter: nil,
 	}
@@ -221,6 +253,8 @@ func (m *Manager) Create(ctx context.Context, spec config.ProviderSpec, start bo
 		cachedRoutes:      nil,
 		cachedInstruments: nil,
 		running:           false,
+		draining:          false,
+		resyncing:         false,
 		status:            StatusPending,
 		startupErr:        nil,
 	}
@@ -313,6 +347,8 @@ func (m *Manager) Remove(name string) error {
 	delete(m.states, trimmed)
 	m.mu.Unlock()
 
+	m.dropOrderCircuit(trimmed)
+	m.dropOrderQueue(trimmed)
 	m.deleteSnapshot(trimmed)
 	m.deleteRoutes(trimmed)
 	return nil
@@ -666,6 +702,8 @@ func (m *Manager) Restore(snapshot providerstore.Snapshot) {
 		cachedRoutes:      nil,
 		cachedInstruments: nil,
 		running:           false,
+		draining:          false,
+		resyncing:         false,
 		status:            normalizeRestoredStatus(status),
 		startupErr:        nil,
 	}
@@ -757,10 +795,12 @@ func routeSnapshotsFromRoutes(routes []dispatcher.Route) []providerstore.RouteSn
 	out := make([]providerstore.RouteSnapshot, 0, len(routes))
 	for _, route := range routes {
 		snapshot := providerstore.RouteSnapshot{
-			Type:     route.Type,
-			WSTopics: append([]string(nil), route.WSTopics...),
-			RestFns:  make([]providerstore.RouteRestFn, len(route.RestFns)),
-			Filters:  make([]providerstore.RouteFilter, len(route.Filters)),
+			Type:         route.Type,
+			WSTopics:     append([]string(nil), route.WSTopics...),
+			RestFns:      make([]providerstore.RouteRestFn, len(route.RestFns)),
+			Filters:      make([]providerstore.RouteFilter, len(route.Filters)),
+			BookDepth:    route.Book.Depth,
+			BookInterval: route.Book.Interval,
 		}
 		for i, fn := range route.RestFns {
 			snapshot.RestFns[i] = providerstore.RouteRestFn{
@@ -771,11 +811,7 @@ func routeSnapshotsFromRoutes(routes []dispatcher.Route) []providerstore.RouteSn
 			}
 		}
 		for i, filter := range route.Filters {
-			snapshot.Filters[i] = providerstore.RouteFilter{
-				Field: filter.Field,
-				Op:    filter.Op,
-				Value: filter.Value,
-			}
+			snapshot.Filters[i] = routeFilterSnapshot(filter)
 		}
 		out = append(out, snapshot)
 	}
@@ -794,6 +830,7 @@ func dispatcherRoutesFromSnapshots(provider string, snapshots []providerstore.Ro
 			WSTopics: append([]string(nil), snapshot.WSTopics...),
 			RestFns:  make([]dispatcher.RestFn, len(snapshot.RestFns)),
 			Filters:  make([]dispatcher.FilterRule, len(snapshot.Filters)),
+			Book:     dispatcher.BookOptions{Depth: snapshot.BookDepth, Interval: snapshot.BookInterval},
 		}
 		for i, fn := range snapshot.RestFns {
 			route.RestFns[i] = dispatcher.RestFn{
@@ -804,17 +841,53 @@ func dispatcherRoutesFromSnapshots(provider string, snapshots []providerstore.Ro
 			}
 		}
 		for i, filter := range snapshot.Filters {
-			route.Filters[i] = dispatcher.FilterRule{
-				Field: filter.Field,
-				Op:    filter.Op,
-				Value: filter.Value,
-			}
+			route.Filters[i] = dispatcherFilterFromSnapshot(filter)
 		}
 		out = append(out, route)
 	}
 	return out
 }
 
+func routeFilterSnapshot(filter dispatcher.FilterRule) providerstore.RouteFilter {
+	snapshot := providerstore.RouteFilter{
+		Field: filter.Field,
+		Op:    filter.Op,
+		Value: filter.Value,
+		Not:   filter.Not,
+		AnyOf: nil,
+	}
+	if len(filter.AnyOf) > 0 {
+		snapshot.AnyOf = make([][]providerstore.RouteFilter, len(filter.AnyOf))
+		for i, alternative := range filter.AnyOf {
+			snapshot.AnyOf[i] = make([]providerstore.RouteFilter, len(alternative))
+			for j, nested := range alternative {
+				snapshot.AnyOf[i][j] = routeFilterSnapshot(nested)
+			}
+		}
+	}
+	return snapshot
+}
+
+func dispatcherFilterFromSnapshot(snapshot providerstore.RouteFilter) dispatcher.FilterRule {
+	filter := dispatcher.FilterRule{
+		Field: snapshot.Field,
+		Op:    snapshot.Op,
+		Value: snapshot.Value,
+		Not:   snapshot.Not,
+		AnyOf: nil,
+	}
+	if len(snapshot.AnyOf) > 0 {
+		filter.AnyOf = make([][]dispatcher.FilterRule, len(snapshot.AnyOf))
+		for i, alternative := range snapshot.AnyOf {
+			filter.AnyOf[i] = make([]dispatcher.FilterRule, len(alternative))
+			for j, nested := range alternative {
+				filter.AnyOf[i][j] = dispatcherFilterFromSnapshot(nested)
+			}
+		}
+	}
+	return filter
+}
+
 // Providers returns a copy of the provider map.
 func (m *Manager) Providers() map[string]Instance {
 	m.mu.RLock()
@@ -858,7 +931,10 @@ func (m *Manager) ProviderMetadataSnapshot() []RuntimeMetadata {
 		if state.running && state.instance != nil {
 			instrumentCount = len(state.instance.Instruments())
 		}
-		runtime := buildRuntimeMetadata(state.spec, instrumentCount, state.running, state.status, state.startupErr)
+		runtime := buildRuntimeMetadata(m.registry.SanitizeProviderSpec(state.spec), instrumentCount, state.running, state.status, state.startupErr)
+		if state.running {
+			runtime.Connection = connectionDiagnostics(state.instance)
+		}
 		out = append(out, runtime)
 	}
 	m.mu.RUnlock()
@@ -908,7 +984,10 @@ func (m *Manager) ProviderMetadataFor(name string) (RuntimeDetail, bool) {
 		instruments = cachedInstruments
 		instrumentCount = len(instruments)
 	}
-	meta := buildRuntimeMetadata(spec, instrumentCount, running, status, startupErr)
+	meta := buildRuntimeMetadata(m.registry.SanitizeProviderSpec(spec), instrumentCount, running, status, startupErr)
+	if running {
+		meta.Connection = connectionDiagnostics(instance)
+	}
 	adapterMeta, _ := m.registry.AdapterMetadata(spec.Adapter)
 	detail := RuntimeDetail{
 		RuntimeMetadata: meta,
@@ -921,10 +1000,7 @@ func (m *Manager) ProviderMetadataFor(name string) (RuntimeDetail, bool) {
 
 func buildRuntimeMetadata(spec config.ProviderSpec, instrumentCount int, running bool, status Status, startupErr error) RuntimeMetadata {
 	settings := extractProviderSettings(spec.Config)
-	errMsg := ""
-	if startupErr != nil {
-		errMsg = startupErr.Error()
-	}
+	errMsg := redact.Error(startupErr)
 	meta := RuntimeMetadata{
 		Name:                   spec.Name,
 		Adapter:                spec.Adapter,
@@ -936,10 +1012,20 @@ func buildRuntimeMetadata(spec config.ProviderSpec, instrumentCount int, running
 		StartupError:           errMsg,
 		DependentInstances:     nil,
 		DependentInstanceCount: 0,
+		Connection:             nil,
 	}
 	return CloneRuntimeMetadata(meta)
 }
 
+func connectionDiagnostics(instance Instance) *shared.ConnectionDiagnostics {
+	diagnoser, ok := instance.(ConnectionDiagnoser)
+	if !ok {
+		return nil
+	}
+	diagnostics := diagnoser.ConnectionDiagnostics()
+	return &diagnostics
+}
+
 func extractProviderSettings(cfg map[string]any) map[string]any {
 	if len(cfg) == 0 {
 		return nil
@@ -1017,7 +1103,7 @@ func (m *Manager) SanitizedProviderSpecs() []config.ProviderSpec {
 	}
 	specs := make([]config.ProviderSpec, 0, len(m.states))
 	for _, state := range m.states {
-		specs = append(specs, SanitizeProviderSpec(state.spec))
+		specs = append(specs, m.registry.SanitizeProviderSpec(state.spec))
 	}
 	sort.Slice(specs, func(i, j int) bool {
 		return specs[i].Name < specs[j].Name
@@ -1077,7 +1163,25 @@ func (m *Manager) DeactivateRoute(ctx context.Context, route dispatcher.Route) e
 	return nil
 }
 
-// SubmitOrder delegates order submission to the addressed provider.
+// NativeOrderFlags reports which of the post-only and reduce-only flags the provider's venue
+// enforces itself. Flags it does not are left to the caller to emulate.
+func (m *Manager) NativeOrderFlags(providerName string) (postOnly, reduceOnly bool) {
+	m.mu.RLock()
+	state, ok := m.states[strings.TrimSpace(providerName)]
+	var adapter string
+	if ok {
+		adapter = state.spec.Adapter
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return false, false
+	}
+	return m.registry.NativeOrderFlags(adapter)
+}
+
+// SubmitOrder delegates order submission to the addressed provider. Orders fail fast with an
+// OrderCircuitError while the provider's order circuit is open, and wait behind cancels when the
+// order queue is enabled.
 func (m *Manager) SubmitOrder(ctx context.Context, req schema.OrderRequest) error {
 	providerName := strings.TrimSpace(req.Provider)
 	if providerName == "" {
@@ -1086,20 +1190,77 @@ func (m *Manager) SubmitOrder(ctx context.Context, req schema.OrderRequest) erro
 	m.mu.RLock()
 	state, ok := m.states[providerName]
 	var inst Instance
-	var running bool
+	var adapter string
+	var running, draining, resyncing bool
 	if ok {
 		inst = state.instance
+		adapter = state.spec.Adapter
 		running = state.running && inst != nil
+		draining = state.draining
+		resyncing = state.resyncing
 	}
 	m.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("%w: %s", ErrProviderNotFound, providerName)
 	}
+	if draining {
+		return fmt.Errorf("%w: %s", ErrProviderDraining, providerName)
+	}
+	if resyncing {
+		return fmt.Errorf("%w: %s", ErrProviderResyncing, providerName)
+	}
 	if !running {
 		return fmt.Errorf("%w: %s", ErrProviderNotRunning, providerName)
 	}
-	if err := inst.SubmitOrder(ctx, req); err != nil {
+	if !m.registry.SupportsTimeInForce(adapter, req.TIF) {
+		return fmt.Errorf("%w: %s on provider %s", ErrTimeInForceUnsupported, req.TIF, providerName)
+	}
+	if req.PostOnly && req.OrderType != schema.OrderTypeLimit {
+		return fmt.Errorf("%w: post-only requires a limit order", ErrOrderFlagInvalid)
+	}
+	err := m.runQueued(ctx, providerName, OrderPriorityNew, func() error {
+		return m.submitThroughCircuit(ctx, providerName, func() error { return inst.SubmitOrder(ctx, req) })
+	})
+	if err != nil {
+		if errors.Is(err, ErrOrderCircuitOpen) || errors.Is(err, ErrOrderQueueFull) {
+			return err
+		}
 		return fmt.Errorf("submit order to provider %q: %w", providerName, err)
 	}
 	return nil
 }
+
+// CancelOrder cancels a single order by client order ID on the addressed provider. Cancels reduce
+// risk, so they are allowed while the provider drains or resyncs and jump the order queue.
+func (m *Manager) CancelOrder(ctx context.Context, providerName, symbol, clientOrderID string) error {
+	providerName = strings.TrimSpace(providerName)
+	m.mu.RLock()
+	state, ok := m.states[providerName]
+	var inst Instance
+	var running bool
+	if ok {
+		inst = state.instance
+		running = state.running && inst != nil
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrProviderNotFound, providerName)
+	}
+	if !running {
+		return fmt.Errorf("%w: %s", ErrProviderNotRunning, providerName)
+	}
+	canceller, ok := inst.(ClientOrderCanceller)
+	if !ok {
+		return fmt.Errorf("provider %q does not support single order cancellation", providerName)
+	}
+	err := m.runQueued(ctx, providerName, OrderPriorityCancel, func() error {
+		return canceller.CancelOrder(ctx, symbol, clientOrderID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrOrderQueueFull) {
+			return err
+		}
+		return fmt.Errorf("cancel order on provider %q: %w", providerName, err)
+	}
+	return nil
+}
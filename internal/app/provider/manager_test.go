@@ -12,6 +12,7 @@ import (
 	"github.com/coachpo/meltica/internal/app/dispatcher"
 	"github.com/coachpo/meltica/internal/domain/providerstore"
 	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
 	"github.com/coachpo/meltica/internal/infra/config"
 	"github.com/coachpo/meltica/internal/infra/pool"
 )
@@ -306,6 +307,44 @@ func TestStartProviderAsyncFailureRecordsError(t *testing.T) {
 	}
 }
 
+func TestProviderMetadataIncludesConnectionDiagnostics(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("stub", func(ctx context.Context, pools *pool.PoolManager, cfg map[string]any) (Instance, error) {
+		return &diagnosingProviderInstance{testProviderInstance: testProviderInstance{name: "stub"}}, nil
+	})
+	manager := NewManager(registry, nil, nil, dispatcher.NewTable(), log.New(io.Discard, "", 0))
+	spec := config.ProviderSpec{Name: "stub", Adapter: "stub", Config: map[string]any{"identifier": "stub"}}
+	if _, err := manager.Create(context.Background(), spec, false); err != nil {
+		t.Fatalf("create provider: %v", err)
+	}
+	if meta, _ := manager.ProviderMetadataFor("stub"); meta.Connection != nil {
+		t.Fatalf("expected no diagnostics before start, got %+v", meta.Connection)
+	}
+	detail, err := manager.StartProvider(context.Background(), "stub")
+	if err != nil {
+		t.Fatalf("start provider: %v", err)
+	}
+	if detail.Connection == nil || detail.Connection.Proxy != "socks5://proxy.internal:1080" || detail.Connection.REST.Requests != 3 {
+		t.Fatalf("expected connection diagnostics in detail, got %+v", detail.Connection)
+	}
+	snapshot := manager.ProviderMetadataSnapshot()
+	if len(snapshot) != 1 || snapshot[0].Connection == nil || snapshot[0].Connection.Websocket.Failures != 1 {
+		t.Fatalf("expected connection diagnostics in snapshot, got %+v", snapshot)
+	}
+}
+
+type diagnosingProviderInstance struct {
+	testProviderInstance
+}
+
+func (i *diagnosingProviderInstance) ConnectionDiagnostics() shared.ConnectionDiagnostics {
+	return shared.ConnectionDiagnostics{
+		Proxy:     "socks5://proxy.internal:1080",
+		REST:      shared.EndpointDiagnostics{Requests: 3},
+		Websocket: shared.EndpointDiagnostics{Requests: 2, Failures: 1, LastError: "proxy refused"},
+	}
+}
+
 type testProviderInstance struct {
 	name string
 }
@@ -1,18 +1,25 @@
 package provider
 
 import (
+	"slices"
 	"sort"
 
 	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
 )
 
 // AdapterMetadata describes static metadata about a provider adapter.
 type AdapterMetadata struct {
-	Identifier     string           `json:"identifier"`
-	DisplayName    string           `json:"displayName,omitempty"`
-	Venue          string           `json:"venue,omitempty"`
-	Description    string           `json:"description,omitempty"`
-	Capabilities   []string         `json:"capabilities,omitempty"`
+	Identifier   string   `json:"identifier"`
+	DisplayName  string   `json:"displayName,omitempty"`
+	Venue        string   `json:"venue,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	// TimeInForce lists the time-in-force values the adapter can submit; empty accepts any.
+	TimeInForce []schema.TimeInForce `json:"timeInForce,omitempty"`
+	// ReduceOnly reports that the venue enforces reduce-only orders itself; otherwise the gateway
+	// emulates the flag against the strategy's position.
+	ReduceOnly     bool             `json:"reduceOnly,omitempty"`
 	SettingsSchema []AdapterSetting `json:"settingsSchema"`
 }
 
@@ -22,24 +29,51 @@ type AdapterSetting struct {
 	Type        string `json:"type"`
 	Description string `json:"description,omitempty"`
 	Default     any    `json:"default,omitempty"`
-	Required    bool   `json:"required"`
+	// Enum lists the accepted values of a string setting.
+	Enum     []string `json:"enum,omitempty"`
+	Required bool     `json:"required"`
+	// Secret marks credentials; they are never echoed back by the control API or kept in backups.
+	Secret bool `json:"secret,omitempty"`
+	// Fields describes the keys of a map setting, or of each entry when Keyed is set.
+	Fields []AdapterSetting `json:"fields,omitempty"`
+	// Keyed marks a map of user-named entries, such as sub-accounts, that each follow Fields.
+	Keyed bool `json:"keyed,omitempty"`
+	// Validate replaces the type check for settings with their own parsing rules, such as maps
+	// keyed by user-chosen names.
+	Validate func(value any) error `json:"-"`
 }
 
 // Clone returns a deep copy of the adapter metadata.
 func (m AdapterMetadata) Clone() AdapterMetadata {
 	clone := m
 	clone.Capabilities = append([]string(nil), m.Capabilities...)
+	clone.TimeInForce = append([]schema.TimeInForce(nil), m.TimeInForce...)
 	clone.SettingsSchema = CloneAdapterSettings(m.SettingsSchema)
 	return clone
 }
 
-// CloneAdapterSettings returns a shallow copy of the adapter settings slice.
+// SupportsTimeInForce reports whether the adapter can submit orders with tif.
+func (m AdapterMetadata) SupportsTimeInForce(tif schema.TimeInForce) bool {
+	return len(m.TimeInForce) == 0 || tif == "" || slices.Contains(m.TimeInForce, tif)
+}
+
+// NativePostOnly reports whether the adapter submits post-only orders to the venue, which it does
+// when it lists the GTX time in force.
+func (m AdapterMetadata) NativePostOnly() bool {
+	return slices.Contains(m.TimeInForce, schema.TimeInForceGTX)
+}
+
+// CloneAdapterSettings returns a copy of the adapter settings slice, including nested fields.
 func CloneAdapterSettings(settings []AdapterSetting) []AdapterSetting {
 	if len(settings) == 0 {
 		return nil
 	}
 	out := make([]AdapterSetting, len(settings))
 	copy(out, settings)
+	for i := range out {
+		out[i].Enum = append([]string(nil), settings[i].Enum...)
+		out[i].Fields = CloneAdapterSettings(settings[i].Fields)
+	}
 	return out
 }
 
@@ -55,6 +89,8 @@ type RuntimeMetadata struct {
 	StartupError           string         `json:"startupError,omitempty"`
 	DependentInstances     []string       `json:"dependentInstances,omitempty"`
 	DependentInstanceCount int            `json:"dependentInstanceCount,omitempty"`
+	// Connection reports REST and websocket connectivity while the provider runs.
+	Connection *shared.ConnectionDiagnostics `json:"connection,omitempty"`
 }
 
 // RuntimeDetail contains the detailed metadata for a provider instance.
@@ -76,6 +112,12 @@ func CloneRuntimeMetadata(meta RuntimeMetadata) RuntimeMetadata {
 	if len(meta.DependentInstances) > 0 {
 		clone.DependentInstances = cloneStringSlice(meta.DependentInstances)
 	}
+	if meta.Connection != nil {
+		connection := *meta.Connection
+		connection.Endpoints = meta.Connection.Endpoints.Clone()
+		connection.Streams = append([]shared.StreamDiagnostics(nil), meta.Connection.Streams...)
+		clone.Connection = &connection
+	}
 	return clone
 }
 
@@ -0,0 +1,311 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+// ErrOrderQueueFull reports an order action rejected because the provider's queue is full.
+var ErrOrderQueueFull = errors.New("provider order queue full")
+
+// OrderPriority ranks the order actions waiting for a provider. Cancels reduce risk and run first.
+type OrderPriority int
+
+const (
+	// OrderPriorityCancel is used for order cancellations.
+	OrderPriorityCancel OrderPriority = iota
+	// OrderPriorityNew is used for new order placements.
+	OrderPriorityNew
+)
+
+const orderPriorityLevels = 2
+
+func (p OrderPriority) String() string {
+	switch p {
+	case OrderPriorityCancel:
+		return "cancel"
+	default:
+		return "new"
+	}
+}
+
+func (p OrderPriority) level() int {
+	if p < OrderPriorityCancel || p > OrderPriorityNew {
+		return int(OrderPriorityNew)
+	}
+	return int(p)
+}
+
+// WithOrderQueue routes order actions through a per-provider priority queue so cancels are never
+// stuck behind a burst of new order placements.
+func WithOrderQueue(cfg config.OrderQueueConfig) Option {
+	return func(m *Manager) {
+		m.queueCfg = cfg
+	}
+}
+
+// OrderQueueStatus describes a provider's order queue.
+type OrderQueueStatus struct {
+	Provider string `json:"provider"`
+	Enabled  bool   `json:"enabled"`
+	InFlight int    `json:"inFlight"`
+	// Queued counts the waiting actions by priority name.
+	Queued   map[string]int `json:"queued"`
+	Rejected int64          `json:"rejected"`
+}
+
+// OrderQueue returns the order queue status of a provider.
+func (m *Manager) OrderQueue(name string) (OrderQueueStatus, error) {
+	var empty OrderQueueStatus
+	name = strings.TrimSpace(name)
+	if !m.HasProvider(name) {
+		return empty, fmt.Errorf("%w: %s", ErrProviderNotFound, name)
+	}
+	queue := m.orderQueue(name)
+	if queue == nil {
+		return OrderQueueStatus{Provider: name, Enabled: false, InFlight: 0, Queued: map[string]int{}, Rejected: 0}, nil
+	}
+	return queue.status(name), nil
+}
+
+// orderQueue returns the queue of a provider, creating it on first use; nil when disabled.
+func (m *Manager) orderQueue(name string) *orderQueue {
+	if !m.queueCfg.Enabled {
+		return nil
+	}
+	m.queuesMu.Lock()
+	defer m.queuesMu.Unlock()
+	queue, ok := m.queues[name]
+	if !ok {
+		queue = newOrderQueue(name, m.queueCfg)
+		m.queues[name] = queue
+	}
+	return queue
+}
+
+func (m *Manager) dropOrderQueue(name string) {
+	m.queuesMu.Lock()
+	delete(m.queues, name)
+	m.queuesMu.Unlock()
+}
+
+// runQueued runs an order action once the provider's queue grants it a slot. Without a queue the
+// action runs immediately.
+func (m *Manager) runQueued(ctx context.Context, name string, priority OrderPriority, action func() error) error {
+	queue := m.orderQueue(name)
+	if queue == nil {
+		return action()
+	}
+	return queue.run(ctx, priority, action)
+}
+
+// queuedAction is an order action waiting for a slot. grant receives nil when the action may run
+// and ErrOrderQueueFull when a more urgent action displaced it.
+type queuedAction struct {
+	grant chan error
+}
+
+// orderQueue admits up to Concurrency actions to a provider at once. Waiting actions are served
+// strictly by priority and first-in, first-out within a priority.
+type orderQueue struct {
+	provider string
+	cfg      config.OrderQueueConfig
+
+	mu       sync.Mutex
+	busy     int
+	waiters  [orderPriorityLevels][]*queuedAction
+	rejected int64
+
+	waitTime metric.Float64Histogram
+	latency  metric.Float64Histogram
+	depth    metric.Int64UpDownCounter
+	rejects  metric.Int64Counter
+}
+
+func newOrderQueue(provider string, cfg config.OrderQueueConfig) *orderQueue {
+	if cfg.Size <= 0 {
+		cfg.Size = 1
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	meter := otel.Meter("provider.order_queue")
+	waitTime, _ := meter.Float64Histogram("provider.order_queue.wait",
+		metric.WithDescription("Time order actions wait in the provider order queue"),
+		metric.WithUnit("ms"))
+	latency, _ := meter.Float64Histogram("provider.order_queue.latency",
+		metric.WithDescription("Time from queueing an order action until the provider answered it"),
+		metric.WithUnit("ms"))
+	depth, _ := meter.Int64UpDownCounter("provider.order_queue.depth",
+		metric.WithDescription("Order actions waiting in the provider order queue"),
+		metric.WithUnit("{action}"))
+	rejects, _ := meter.Int64Counter("provider.order_queue.rejected",
+		metric.WithDescription("Order actions rejected or displaced by a full provider order queue"),
+		metric.WithUnit("{action}"))
+	return &orderQueue{
+		provider: provider,
+		cfg:      cfg,
+		mu:       sync.Mutex{},
+		busy:     0,
+		waiters:  [orderPriorityLevels][]*queuedAction{},
+		rejected: 0,
+		waitTime: waitTime,
+		latency:  latency,
+		depth:    depth,
+		rejects:  rejects,
+	}
+}
+
+// run waits for a slot and runs the action in the caller's goroutine. Actions abandoned by their
+// caller while queued leave the queue without running.
+func (q *orderQueue) run(ctx context.Context, priority OrderPriority, action func() error) error {
+	start := time.Now()
+	if err := q.acquire(ctx, priority, start); err != nil {
+		return err
+	}
+	defer q.release()
+	err := action()
+	q.record(ctx, q.latency, priority, start)
+	return err
+}
+
+func (q *orderQueue) acquire(ctx context.Context, priority OrderPriority, start time.Time) error {
+	level := priority.level()
+	q.mu.Lock()
+	if q.busy < q.cfg.Concurrency && q.waitingLocked() == 0 {
+		q.busy++
+		q.mu.Unlock()
+		q.record(ctx, q.waitTime, priority, start)
+		return nil
+	}
+	if q.waitingLocked() >= q.cfg.Size && !q.displaceLocked(level) {
+		q.rejected++
+		q.mu.Unlock()
+		q.count(ctx, priority)
+		return fmt.Errorf("%w: %s rejected on provider %s", ErrOrderQueueFull, priority, q.provider)
+	}
+	waiter := &queuedAction{grant: make(chan error, 1)}
+	q.waiters[level] = append(q.waiters[level], waiter)
+	q.mu.Unlock()
+	q.adjustDepth(ctx, priority, 1)
+
+	select {
+	case err := <-waiter.grant:
+		q.adjustDepth(ctx, priority, -1)
+		if err != nil {
+			q.count(ctx, priority)
+			return fmt.Errorf("%w: %s displaced on provider %s", err, priority, q.provider)
+		}
+		q.record(ctx, q.waitTime, priority, start)
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		removed := q.removeWaiterLocked(level, waiter)
+		q.mu.Unlock()
+		if !removed {
+			// The slot was granted, or the action displaced, while the caller gave up.
+			if err := <-waiter.grant; err == nil {
+				q.release()
+			}
+		}
+		q.adjustDepth(ctx, priority, -1)
+		return fmt.Errorf("order queue on provider %s: %w", q.provider, ctx.Err())
+	}
+}
+
+// release hands the slot to the most urgent waiting action, or frees it.
+func (q *orderQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for level := range q.waiters {
+		if len(q.waiters[level]) == 0 {
+			continue
+		}
+		next := q.waiters[level][0]
+		q.waiters[level] = q.waiters[level][1:]
+		next.grant <- nil
+		return
+	}
+	q.busy--
+}
+
+// displaceLocked makes room for an action of the given level by dropping the most recently queued
+// action of a less urgent level. New orders never displace anything.
+func (q *orderQueue) displaceLocked(level int) bool {
+	for lower := orderPriorityLevels - 1; lower > level; lower-- {
+		waiting := q.waiters[lower]
+		if len(waiting) == 0 {
+			continue
+		}
+		last := waiting[len(waiting)-1]
+		q.waiters[lower] = waiting[:len(waiting)-1]
+		q.rejected++
+		last.grant <- ErrOrderQueueFull
+		return true
+	}
+	return false
+}
+
+func (q *orderQueue) removeWaiterLocked(level int, waiter *queuedAction) bool {
+	for i, candidate := range q.waiters[level] {
+		if candidate == waiter {
+			q.waiters[level] = append(q.waiters[level][:i], q.waiters[level][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (q *orderQueue) waitingLocked() int {
+	total := 0
+	for _, waiting := range q.waiters {
+		total += len(waiting)
+	}
+	return total
+}
+
+func (q *orderQueue) status(name string) OrderQueueStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	queued := make(map[string]int, orderPriorityLevels)
+	for level, waiting := range q.waiters {
+		queued[OrderPriority(level).String()] = len(waiting)
+	}
+	return OrderQueueStatus{Provider: name, Enabled: true, InFlight: q.busy, Queued: queued, Rejected: q.rejected}
+}
+
+func (q *orderQueue) attrs(priority OrderPriority) metric.MeasurementOption {
+	return metric.WithAttributes(
+		attribute.String("provider", q.provider),
+		attribute.String("priority", priority.String()),
+	)
+}
+
+func (q *orderQueue) record(ctx context.Context, histogram metric.Float64Histogram, priority OrderPriority, start time.Time) {
+	if histogram == nil {
+		return
+	}
+	histogram.Record(ctx, float64(time.Since(start).Microseconds())/1000, q.attrs(priority))
+}
+
+func (q *orderQueue) adjustDepth(ctx context.Context, priority OrderPriority, delta int64) {
+	if q.depth != nil {
+		q.depth.Add(ctx, delta, q.attrs(priority))
+	}
+}
+
+func (q *orderQueue) count(ctx context.Context, priority OrderPriority) {
+	if q.rejects != nil {
+		q.rejects.Add(ctx, 1, q.attrs(priority))
+	}
+}
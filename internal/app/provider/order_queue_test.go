@@ -0,0 +1,148 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+func waitForQueued(t *testing.T, queue *orderQueue, priority OrderPriority, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if queue.status("").Queued[priority.String()] == want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d queued %s actions", want, priority)
+}
+
+func TestOrderQueueRunsCancelsFirst(t *testing.T) {
+	queue := newOrderQueue("binance", config.OrderQueueConfig{Enabled: true, Size: 2, Concurrency: 1})
+	ctx := context.Background()
+
+	blocked := make(chan struct{})
+	running := make(chan struct{})
+	go func() {
+		_ = queue.run(ctx, OrderPriorityNew, func() error {
+			close(running)
+			<-blocked
+			return nil
+		})
+	}()
+	<-running
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	errs := make(map[string]error)
+	submit := func(name string, priority OrderPriority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := queue.run(ctx, priority, func() error {
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+				return nil
+			})
+			mu.Lock()
+			errs[name] = err
+			mu.Unlock()
+		}()
+	}
+	submit("new-1", OrderPriorityNew)
+	waitForQueued(t, queue, OrderPriorityNew, 1)
+	submit("new-2", OrderPriorityNew)
+	waitForQueued(t, queue, OrderPriorityNew, 2)
+
+	if err := queue.run(ctx, OrderPriorityNew, func() error { return nil }); !errors.Is(err, ErrOrderQueueFull) {
+		t.Fatalf("expected a full queue to reject new orders, got %v", err)
+	}
+	// The cancel displaces the most recently queued new order instead of being rejected.
+	submit("cancel", OrderPriorityCancel)
+	waitForQueued(t, queue, OrderPriorityCancel, 1)
+	status := queue.status("binance")
+	if status.InFlight != 1 || status.Queued["cancel"] != 1 || status.Queued["new"] != 1 || status.Rejected != 2 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	close(blocked)
+	wg.Wait()
+	if len(order) != 2 || order[0] != "cancel" || order[1] != "new-1" {
+		t.Fatalf("expected the cancel to run first, got %v", order)
+	}
+	if !errors.Is(errs["new-2"], ErrOrderQueueFull) || errs["new-1"] != nil || errs["cancel"] != nil {
+		t.Fatalf("unexpected results %v", errs)
+	}
+	if status := queue.status("binance"); status.InFlight != 0 {
+		t.Fatalf("expected every slot released, got %+v", status)
+	}
+}
+
+func TestOrderQueueDropsAbandonedActions(t *testing.T) {
+	queue := newOrderQueue("okx", config.OrderQueueConfig{Enabled: true, Size: 4, Concurrency: 1})
+	blocked := make(chan struct{})
+	running := make(chan struct{})
+	go func() {
+		_ = queue.run(context.Background(), OrderPriorityNew, func() error {
+			close(running)
+			<-blocked
+			return nil
+		})
+	}()
+	<-running
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	ran := false
+	go func() {
+		done <- queue.run(ctx, OrderPriorityNew, func() error {
+			ran = true
+			return nil
+		})
+	}()
+	waitForQueued(t, queue, OrderPriorityNew, 1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) || ran {
+		t.Fatalf("expected the abandoned order to leave the queue unsent, got %v ran=%v", err, ran)
+	}
+	close(blocked)
+	deadline := time.Now().Add(5 * time.Second)
+	for queue.status("okx").InFlight != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if status := queue.status("okx"); status.InFlight != 0 || status.Queued["new"] != 0 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+}
+
+func TestManagerOrderQueueStatus(t *testing.T) {
+	inst := &failingProviderInstance{testProviderInstance: testProviderInstance{name: "binance"}}
+	manager := newDrainTestManager(t, &drainOrderStore{}, inst)
+	if status, err := manager.OrderQueue("binance"); err != nil || status.Enabled {
+		t.Fatalf("expected a disabled queue by default, got %+v %v", status, err)
+	}
+	manager.queueCfg = config.OrderQueueConfig{Enabled: true, Size: 8, Concurrency: 2}
+	if err := manager.SubmitOrder(context.Background(), schema.OrderRequest{Provider: "binance", Symbol: "BTC-USDT"}); err != nil {
+		t.Fatalf("SubmitOrder: %v", err)
+	}
+	status, err := manager.OrderQueue("binance")
+	if err != nil || !status.Enabled || status.InFlight != 0 || inst.calls != 1 {
+		t.Fatalf("unexpected status %+v %v after %d calls", status, err, inst.calls)
+	}
+	if err := manager.CancelOrder(context.Background(), "binance", "BTC-USDT", "c1"); err == nil {
+		t.Fatal("expected providers without single order cancellation to be refused")
+	}
+	if _, err := manager.OrderQueue("ghost"); !errors.Is(err, ErrProviderNotFound) {
+		t.Fatalf("expected unknown provider, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/coachpo/meltica/internal/app/dispatcher"
 	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
 )
 
 // Instance represents a runtime provider with event streaming capabilities.
@@ -18,3 +19,25 @@ type Instance interface {
 	UnsubscribeRoute(route dispatcher.Route) error
 	Instruments() []schema.Instrument
 }
+
+// OrderCanceller is implemented by providers that can cancel resting orders on the venue.
+type OrderCanceller interface {
+	CancelOpenOrders(ctx context.Context, symbol string) error
+}
+
+// ClientOrderCanceller is implemented by providers that can cancel a single order by client order ID.
+type ClientOrderCanceller interface {
+	CancelOrder(ctx context.Context, symbol, clientOrderID string) error
+}
+
+// ConnectionDiagnoser is implemented by providers that report the health of their venue
+// connections, including any egress proxy they route through.
+type ConnectionDiagnoser interface {
+	ConnectionDiagnostics() shared.ConnectionDiagnostics
+}
+
+// OrderStatusQuerier is implemented by providers that can report the venue state of an order by
+// client order ID, for example to reconcile orders left open across a restart.
+type OrderStatusQuerier interface {
+	QueryOrder(ctx context.Context, symbol, subAccount, clientOrderID string) (schema.ExecReportPayload, error)
+}
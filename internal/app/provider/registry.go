@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/coachpo/meltica/internal/domain/schema"
 	"github.com/coachpo/meltica/internal/infra/config"
 	"github.com/coachpo/meltica/internal/infra/pool"
 )
@@ -82,6 +83,62 @@ func (r *Registry) AdapterMetadata(identifier string) (AdapterMetadata, bool) {
 	return meta.Clone(), true
 }
 
+// ValidateConfig checks an adapter config against the adapter's settings schema and returns a
+// *SettingsError listing invalid fields. Adapters registered without metadata accept any config.
+func (r *Registry) ValidateConfig(identifier string, cfg map[string]any) error {
+	r.mu.RLock()
+	meta, ok := r.metadata[identifier]
+	r.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return ValidateAdapterSettings(identifier, meta.SettingsSchema, cfg)
+}
+
+// SanitizeProviderSpec returns the specification without the settings its adapter marks secret or
+// that look like credentials.
+func (r *Registry) SanitizeProviderSpec(spec config.ProviderSpec) config.ProviderSpec {
+	return config.ProviderSpec{
+		Name:    spec.Name,
+		Adapter: spec.Adapter,
+		Config:  sanitizeProviderConfig(spec.Config, r.secretSettings(spec.Adapter)),
+	}
+}
+
+func (r *Registry) secretSettings(identifier string) map[string]struct{} {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	meta, ok := r.metadata[identifier]
+	if !ok {
+		return nil
+	}
+	return secretSettingNames(meta.SettingsSchema)
+}
+
+// SupportsTimeInForce reports whether the adapter registered under identifier can submit orders
+// with tif. Unknown adapters are left to reject unsupported orders themselves.
+func (r *Registry) SupportsTimeInForce(identifier string, tif schema.TimeInForce) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	meta, ok := r.metadata[identifier]
+	return !ok || meta.SupportsTimeInForce(tif)
+}
+
+// NativeOrderFlags reports which of the post-only and reduce-only flags the adapter registered
+// under identifier enforces at the venue. Unknown adapters enforce neither.
+func (r *Registry) NativeOrderFlags(identifier string) (postOnly, reduceOnly bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	meta, ok := r.metadata[identifier]
+	if !ok {
+		return false, false
+	}
+	return meta.NativePostOnly(), meta.ReduceOnly
+}
+
 // AdapterMetadataSnapshot returns metadata for all registered adapters.
 func (r *Registry) AdapterMetadataSnapshot() []AdapterMetadata {
 	r.mu.RLock()
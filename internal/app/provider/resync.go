@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// ErrProviderResyncing indicates that orders resting on the provider across a restart are still
+// being reconciled and new orders are rejected.
+var ErrProviderResyncing = errors.New("provider resyncing open orders")
+
+// WithOrderSnapshots wires the store that keeps the open orders of each provider across restarts.
+func WithOrderSnapshots(store orderstore.SnapshotStore) Option {
+	return func(m *Manager) {
+		m.snapshots = store
+	}
+}
+
+// ResyncReport summarises the reconciliation of the orders a provider had open at shutdown.
+type ResyncReport struct {
+	Provider string `json:"provider"`
+	// TakenAt is when the snapshot was taken, in Unix seconds.
+	TakenAt int64 `json:"takenAt"`
+	Orders  int   `json:"orders"`
+	// Updated counts orders whose venue state moved on while the gateway was down; an execution
+	// report was delivered for each.
+	Updated int      `json:"updated"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// SnapshotOpenOrders persists, per provider, the orders the order store still reports open. It
+// runs on graceful shutdown once strategy instances have stopped and returns the number of orders
+// recorded.
+func (m *Manager) SnapshotOpenOrders(ctx context.Context) (int, error) {
+	if m.snapshots == nil || m.orders == nil {
+		return 0, nil
+	}
+	m.mu.RLock()
+	names := make([]string, 0, len(m.states))
+	for name := range m.states {
+		names = append(names, name)
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+
+	takenAt := time.Now().UTC().Unix()
+	total := 0
+	var errs []error
+	for _, name := range names {
+		records, err := m.orders.ListOrders(ctx, orderstore.OrderQuery{
+			StrategyInstance: "",
+			Provider:         name,
+			States:           openOrderStates,
+			Since:            0,
+			Until:            0,
+			Tag:              "",
+			Limit:            drainOrderLimit,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list open orders of %s: %w", name, err))
+			continue
+		}
+		if len(records) == 0 {
+			if err := m.snapshots.DeleteOpenOrderSnapshot(ctx, name); err != nil {
+				errs = append(errs, fmt.Errorf("clear open order snapshot of %s: %w", name, err))
+			}
+			continue
+		}
+		snapshot := orderstore.OpenOrderSnapshot{Provider: name, TakenAt: takenAt, Orders: records}
+		if err := m.snapshots.SaveOpenOrderSnapshot(ctx, snapshot); err != nil {
+			errs = append(errs, fmt.Errorf("save open order snapshot of %s: %w", name, err))
+			continue
+		}
+		total += len(records)
+		m.logger.Printf("provider/%s: snapshotted %d open orders", name, len(records))
+	}
+	return total, errors.Join(errs...)
+}
+
+// LoadOpenOrderSnapshots loads the snapshots taken on the last shutdown and rejects new orders on
+// the providers they cover until ResyncOpenOrders reconciles them. It returns the number of
+// providers held.
+func (m *Manager) LoadOpenOrderSnapshots(ctx context.Context) (int, error) {
+	if m.snapshots == nil {
+		return 0, nil
+	}
+	snapshots, err := m.snapshots.LoadOpenOrderSnapshots(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("load open order snapshots: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pendingResync = make(map[string]orderstore.OpenOrderSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		m.pendingResync[snapshot.Provider] = snapshot
+		if state, ok := m.states[snapshot.Provider]; ok {
+			state.resyncing = true
+		}
+	}
+	return len(snapshots), nil
+}
+
+// ResyncOpenOrders queries the venue state of every order held by LoadOpenOrderSnapshots. Orders
+// that filled, partially filled or closed while the gateway was down are updated in the order
+// store and an execution report is published so restored instances see what they missed. Order
+// entry is re-enabled on each provider once its orders are reconciled.
+func (m *Manager) ResyncOpenOrders(ctx context.Context) []ResyncReport {
+	m.mu.Lock()
+	pending := m.pendingResync
+	m.pendingResync = nil
+	m.mu.Unlock()
+
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	reports := make([]ResyncReport, 0, len(names))
+	for _, name := range names {
+		report := m.resyncProvider(ctx, pending[name])
+		m.setResyncing(name, false)
+		if len(report.Errors) == 0 {
+			if err := m.snapshots.DeleteOpenOrderSnapshot(ctx, name); err != nil {
+				report.Errors = append(report.Errors, "delete snapshot: "+redact.Error(err))
+			}
+		}
+		m.logger.Printf("provider/%s: resynced %d open orders (%d updated, %d errors)", name, report.Orders, report.Updated, len(report.Errors))
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+func (m *Manager) resyncProvider(ctx context.Context, snapshot orderstore.OpenOrderSnapshot) ResyncReport {
+	report := ResyncReport{
+		Provider: snapshot.Provider,
+		TakenAt:  snapshot.TakenAt,
+		Orders:   len(snapshot.Orders),
+		Updated:  0,
+		Errors:   nil,
+	}
+	m.mu.RLock()
+	state, ok := m.states[snapshot.Provider]
+	var inst Instance
+	running := false
+	if ok {
+		inst = state.instance
+		running = state.running && inst != nil
+	}
+	m.mu.RUnlock()
+	if !running {
+		report.Errors = append(report.Errors, fmt.Sprintf("%v: %s", ErrProviderNotRunning, snapshot.Provider))
+		return report
+	}
+	querier, ok := inst.(OrderStatusQuerier)
+	if !ok {
+		report.Errors = append(report.Errors, fmt.Sprintf("provider %s does not support order queries", snapshot.Provider))
+		return report
+	}
+	for _, record := range snapshot.Orders {
+		subAccount, _ := record.Metadata["subAccount"].(string)
+		payload, err := querier.QueryOrder(ctx, record.Symbol, subAccount, record.ClientOrderID)
+		if err != nil {
+			report.Errors = append(report.Errors, record.ClientOrderID+": "+redact.Error(err))
+			continue
+		}
+		if payload.ClientOrderID == "" {
+			payload.ClientOrderID = record.ClientOrderID
+		}
+		if !orderMovedOn(record, payload) {
+			continue
+		}
+		if err := m.recordResyncedOrder(ctx, record, payload); err != nil {
+			report.Errors = append(report.Errors, record.ClientOrderID+": "+redact.Error(err))
+			continue
+		}
+		m.publishResyncedOrder(ctx, snapshot.Provider, record.Symbol, payload)
+		report.Updated++
+	}
+	return report
+}
+
+// orderMovedOn reports whether the venue state differs from the state persisted at shutdown.
+func orderMovedOn(record orderstore.OrderRecord, payload schema.ExecReportPayload) bool {
+	if !strings.EqualFold(record.State, string(payload.State)) {
+		return true
+	}
+	before, _ := record.Metadata["filledQuantity"].(string)
+	return !decimalsEqual(before, payload.FilledQuantity)
+}
+
+func decimalsEqual(a, b string) bool {
+	left, err := decimal.NewFromString(defaultZero(a))
+	if err != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	right, err := decimal.NewFromString(defaultZero(b))
+	if err != nil {
+		return false
+	}
+	return left.Equal(right)
+}
+
+func defaultZero(value string) string {
+	if trimmed := strings.TrimSpace(value); trimmed != "" {
+		return trimmed
+	}
+	return "0"
+}
+
+func (m *Manager) recordResyncedOrder(ctx context.Context, record orderstore.OrderRecord, payload schema.ExecReportPayload) error {
+	if m.orders == nil {
+		return nil
+	}
+	at := payload.Timestamp.Unix()
+	update := orderstore.OrderUpdate{
+		ID:             record.ID,
+		State:          string(payload.State),
+		AcknowledgedAt: &at,
+		CompletedAt:    nil,
+		Metadata: map[string]any{
+			"exchangeOrderId": payload.ExchangeOrderID,
+			"filledQuantity":  payload.FilledQuantity,
+			"remainingQty":    payload.RemainingQty,
+			"avgFillPrice":    payload.AvgFillPrice,
+			"resyncedAt":      time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+	switch payload.State {
+	case schema.ExecReportStateFILLED, schema.ExecReportStateCANCELLED, schema.ExecReportStateREJECTED, schema.ExecReportStateEXPIRED:
+		update.CompletedAt = &at
+	}
+	if err := m.orders.UpdateOrder(ctx, update); err != nil {
+		return fmt.Errorf("update order: %w", err)
+	}
+	return nil
+}
+
+// publishResyncedOrder delivers the venue state as an execution report. The event ID is derived
+// from the order state so a repeated resync does not record the same execution twice.
+func (m *Manager) publishResyncedOrder(ctx context.Context, provider, symbol string, payload schema.ExecReportPayload) {
+	if m.bus == nil || m.pools == nil {
+		return
+	}
+	evt, err := m.pools.BorrowEventInst(ctx)
+	if err != nil {
+		m.logger.Printf("provider/%s: resync exec report for %s skipped: %v", provider, payload.ClientOrderID, err)
+		return
+	}
+	evt.EventID = fmt.Sprintf("%s:resync:%s:%s:%s", provider, payload.ClientOrderID, payload.State, defaultZero(payload.FilledQuantity))
+	evt.Provider = provider
+	evt.Symbol = symbol
+	evt.Type = schema.EventTypeExecReport
+	evt.IngestTS = payload.Timestamp
+	evt.EmitTS = payload.Timestamp
+	evt.Payload = payload
+	if err := m.bus.Publish(ctx, evt); err != nil {
+		m.logger.Printf("provider/%s: publish resync exec report for %s: %v", provider, payload.ClientOrderID, err)
+		m.pools.ReturnEventInst(evt)
+	}
+}
+
+func (m *Manager) setResyncing(name string, resyncing bool) {
+	m.mu.Lock()
+	if state, ok := m.states[name]; ok {
+		state.resyncing = resyncing
+	}
+	m.mu.Unlock()
+}
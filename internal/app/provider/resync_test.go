@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+type resyncOrderStore struct {
+	drainOrderStore
+
+	updates []orderstore.OrderUpdate
+}
+
+func (s *resyncOrderStore) UpdateOrder(_ context.Context, update orderstore.OrderUpdate) error {
+	s.updates = append(s.updates, update)
+	return nil
+}
+
+type memorySnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string]orderstore.OpenOrderSnapshot
+}
+
+func (s *memorySnapshotStore) SaveOpenOrderSnapshot(_ context.Context, snapshot orderstore.OpenOrderSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[snapshot.Provider] = snapshot
+	return nil
+}
+
+func (s *memorySnapshotStore) LoadOpenOrderSnapshots(context.Context) ([]orderstore.OpenOrderSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]orderstore.OpenOrderSnapshot, 0, len(s.snapshots))
+	for _, snapshot := range s.snapshots {
+		out = append(out, snapshot)
+	}
+	return out, nil
+}
+
+func (s *memorySnapshotStore) DeleteOpenOrderSnapshot(_ context.Context, provider string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.snapshots, provider)
+	return nil
+}
+
+type recordingBus struct {
+	eventbus.Bus
+
+	mu        sync.Mutex
+	published []*schema.Event
+}
+
+func (b *recordingBus) Publish(_ context.Context, evt *schema.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, evt)
+	return nil
+}
+
+type queryingProviderInstance struct {
+	testProviderInstance
+	venue map[string]schema.ExecReportPayload
+}
+
+func (i *queryingProviderInstance) QueryOrder(_ context.Context, _, _, clientOrderID string) (schema.ExecReportPayload, error) {
+	payload, ok := i.venue[clientOrderID]
+	if !ok {
+		return schema.ExecReportPayload{}, errors.New("order does not exist")
+	}
+	return payload, nil
+}
+
+func TestManagerSnapshotAndResyncOpenOrders(t *testing.T) {
+	orders := &resyncOrderStore{drainOrderStore: drainOrderStore{open: []orderstore.OrderRecord{
+		{Order: orderstore.Order{ID: "filled-id", Provider: "binance", ClientOrderID: "alpha-1", Symbol: "BTC-USDT", State: "ACK",
+			Metadata: map[string]any{"filledQuantity": "0"}}},
+		{Order: orderstore.Order{ID: "resting-id", Provider: "binance", ClientOrderID: "alpha-2", Symbol: "BTC-USDT", State: "ACK",
+			Metadata: map[string]any{"filledQuantity": "0"}}},
+	}}}
+	snapshots := &memorySnapshotStore{snapshots: make(map[string]orderstore.OpenOrderSnapshot)}
+	pools := pool.NewPoolManager()
+	if err := pools.RegisterPool("Event", 4, 0, func() any { return &schema.Event{} }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	bus := &recordingBus{}
+	manager := NewManager(nil, pools, bus, dispatcher.NewTable(), log.New(io.Discard, "", 0),
+		WithOrderStore(orders), WithOrderSnapshots(snapshots))
+	spec := config.ProviderSpec{Name: "binance", Adapter: "binance", Config: map[string]any{"identifier": "binance"}}
+	if _, err := manager.Create(context.Background(), spec, false); err != nil {
+		t.Fatalf("create provider: %v", err)
+	}
+
+	count, err := manager.SnapshotOpenOrders(context.Background())
+	if err != nil || count != 2 {
+		t.Fatalf("SnapshotOpenOrders = %d, %v", count, err)
+	}
+
+	filledAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	inst := &queryingProviderInstance{testProviderInstance: testProviderInstance{name: "binance"}, venue: map[string]schema.ExecReportPayload{
+		"alpha-1": {ClientOrderID: "alpha-1", State: schema.ExecReportStateFILLED, FilledQuantity: "1.0", AvgFillPrice: "100", Timestamp: filledAt},
+		"alpha-2": {ClientOrderID: "alpha-2", State: schema.ExecReportStateACK, FilledQuantity: "0.000", Timestamp: filledAt},
+	}}
+	manager.mu.Lock()
+	state := manager.states["binance"]
+	state.running = true
+	state.status = StatusRunning
+	state.instance = inst
+	manager.mu.Unlock()
+
+	held, err := manager.LoadOpenOrderSnapshots(context.Background())
+	if err != nil || held != 1 {
+		t.Fatalf("LoadOpenOrderSnapshots = %d, %v", held, err)
+	}
+	req := schema.OrderRequest{Provider: "binance", Symbol: "BTC-USDT"}
+	if err := manager.SubmitOrder(context.Background(), req); !errors.Is(err, ErrProviderResyncing) {
+		t.Fatalf("expected orders rejected while resyncing, got %v", err)
+	}
+
+	reports := manager.ResyncOpenOrders(context.Background())
+	if len(reports) != 1 || reports[0].Orders != 2 || reports[0].Updated != 1 || len(reports[0].Errors) != 0 {
+		t.Fatalf("unexpected resync reports %+v", reports)
+	}
+	if len(orders.updates) != 1 || orders.updates[0].ID != "filled-id" || orders.updates[0].State != "FILLED" || orders.updates[0].CompletedAt == nil {
+		t.Fatalf("unexpected order store updates %+v", orders.updates)
+	}
+	if len(bus.published) != 1 {
+		t.Fatalf("expected one exec report published, got %d", len(bus.published))
+	}
+	evt := bus.published[0]
+	if evt.Type != schema.EventTypeExecReport || evt.Symbol != "BTC-USDT" || evt.EventID != "binance:resync:alpha-1:FILLED:1.0" {
+		t.Fatalf("unexpected published event %+v", evt)
+	}
+	if len(snapshots.snapshots) != 0 {
+		t.Fatalf("expected reconciled snapshot deleted, got %+v", snapshots.snapshots)
+	}
+	if err := manager.SubmitOrder(context.Background(), req); err != nil {
+		t.Fatalf("expected order entry enabled after resync, got %v", err)
+	}
+}
+
+func TestManagerResyncKeepsSnapshotWhenProviderCannotQuery(t *testing.T) {
+	snapshots := &memorySnapshotStore{snapshots: map[string]orderstore.OpenOrderSnapshot{
+		"binance": {Provider: "binance", TakenAt: 1, Orders: []orderstore.OrderRecord{openOrder("BTC-USDT")}},
+	}}
+	store := &drainOrderStore{}
+	manager := newDrainTestManager(t, store, &testProviderInstance{name: "binance"})
+	manager.snapshots = snapshots
+	if _, err := manager.LoadOpenOrderSnapshots(context.Background()); err != nil {
+		t.Fatalf("LoadOpenOrderSnapshots: %v", err)
+	}
+
+	reports := manager.ResyncOpenOrders(context.Background())
+	if len(reports) != 1 || len(reports[0].Errors) != 1 {
+		t.Fatalf("expected unsupported query reported, got %+v", reports)
+	}
+	if _, ok := snapshots.snapshots["binance"]; !ok {
+		t.Fatal("expected snapshot kept for the next startup")
+	}
+	if err := manager.SubmitOrder(context.Background(), schema.OrderRequest{Provider: "binance", Symbol: "BTC-USDT"}); err != nil {
+		t.Fatalf("expected order entry enabled after resync attempt, got %v", err)
+	}
+}
@@ -30,11 +30,7 @@ func SanitizeProviderConfig(cfg map[string]any) map[string]any {
 	if len(cfg) == 0 {
 		return nil
 	}
-	clean := sanitizeProviderSettingsMap(cfg)
-	if len(clean) == 0 {
-		return nil
-	}
-	return clean
+	return sanitizeProviderConfig(cfg, nil)
 }
 
 // SanitizeProviderSpec returns a sanitised provider specification with sensitive fields removed.
@@ -46,16 +42,29 @@ func SanitizeProviderSpec(spec config.ProviderSpec) config.ProviderSpec {
 	}
 }
 
-func sanitizeProviderSettingsMap(cfg map[string]any) map[string]any {
+// sanitizeProviderConfig removes keys named in secrets, typically those an adapter schema marks
+// secret, along with keys that look like credentials.
+func sanitizeProviderConfig(cfg map[string]any, secrets map[string]struct{}) map[string]any {
+	if len(cfg) == 0 {
+		return nil
+	}
+	clean := sanitizeProviderSettingsMap(cfg, secrets)
+	if len(clean) == 0 {
+		return nil
+	}
+	return clean
+}
+
+func sanitizeProviderSettingsMap(cfg map[string]any, secrets map[string]struct{}) map[string]any {
 	if len(cfg) == 0 {
 		return nil
 	}
 	clean := make(map[string]any)
 	for key, value := range cfg {
-		if shouldOmitProviderSettingKey(key) {
+		if shouldOmitProviderSettingKey(key, secrets) {
 			continue
 		}
-		sanitized := sanitizeProviderSettingValue(value)
+		sanitized := sanitizeProviderSettingValue(value, secrets)
 		if sanitized == nil {
 			continue
 		}
@@ -67,10 +76,10 @@ func sanitizeProviderSettingsMap(cfg map[string]any) map[string]any {
 	return clean
 }
 
-func sanitizeProviderSettingValue(value any) any {
+func sanitizeProviderSettingValue(value any, secrets map[string]struct{}) any {
 	switch v := value.(type) {
 	case map[string]any:
-		clean := sanitizeProviderSettingsMap(v)
+		clean := sanitizeProviderSettingsMap(v, secrets)
 		if len(clean) == 0 {
 			return nil
 		}
@@ -80,13 +89,13 @@ func sanitizeProviderSettingValue(value any) any {
 		for _, item := range v {
 			switch typed := item.(type) {
 			case map[string]any:
-				clean := sanitizeProviderSettingsMap(typed)
+				clean := sanitizeProviderSettingsMap(typed, secrets)
 				if len(clean) == 0 {
 					continue
 				}
 				filtered = append(filtered, clean)
 			case []any:
-				nested := sanitizeProviderSettingValue(typed)
+				nested := sanitizeProviderSettingValue(typed, secrets)
 				if nested == nil {
 					continue
 				}
@@ -109,12 +118,15 @@ func sanitizeProviderSettingValue(value any) any {
 	}
 }
 
-func shouldOmitProviderSettingKey(key string) bool {
+func shouldOmitProviderSettingKey(key string, secrets map[string]struct{}) bool {
 	trimmed := strings.TrimSpace(key)
 	if trimmed == "" {
 		return false
 	}
 	normalized := strings.ToLower(trimmed)
+	if _, ok := secrets[normalized]; ok {
+		return true
+	}
 	normalized = providerSettingReplacer.Replace(normalized)
 	for _, fragment := range providerSensitiveFragments {
 		if strings.Contains(normalized, providerSettingReplacer.Replace(fragment)) {
@@ -0,0 +1,276 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
+)
+
+// Adapter setting types understood by ValidateAdapterSettings.
+const (
+	SettingTypeString   = "string"
+	SettingTypeInt      = "int"
+	SettingTypeFloat    = "float"
+	SettingTypeBool     = "bool"
+	SettingTypeDuration = "duration"
+	SettingTypeMap      = "map"
+)
+
+// SettingError describes one invalid adapter setting. Field is the dotted path within the adapter
+// config, e.g. "reconnect.jitter".
+type SettingError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// SettingsError collects every invalid setting of an adapter config.
+type SettingsError struct {
+	Adapter string
+	Fields  []SettingError
+}
+
+func (e *SettingsError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		parts = append(parts, field.Field+": "+field.Message)
+	}
+	return fmt.Sprintf("invalid %s adapter config: %s", e.Adapter, strings.Join(parts, "; "))
+}
+
+// ValidateAdapterSettings checks cfg against the adapter's settings schema: required settings must
+// be present, values must match their declared type and unknown keys are rejected. It returns a
+// *SettingsError listing every invalid field.
+func ValidateAdapterSettings(adapter string, schema []AdapterSetting, cfg map[string]any) error {
+	fields := validateSettings("", schema, cfg)
+	if len(fields) == 0 {
+		return nil
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return &SettingsError{Adapter: adapter, Fields: fields}
+}
+
+func validateSettings(prefix string, schema []AdapterSetting, cfg map[string]any) []SettingError {
+	var fields []SettingError
+	known := make(map[string]AdapterSetting, len(schema))
+	for _, setting := range schema {
+		known[setting.Name] = setting
+		if _, ok := cfg[setting.Name]; !ok && setting.Required {
+			fields = append(fields, SettingError{Field: prefix + setting.Name, Message: "required"})
+		}
+	}
+	for key, value := range cfg {
+		setting, ok := known[strings.TrimSpace(key)]
+		if !ok {
+			fields = append(fields, SettingError{Field: prefix + key, Message: "unknown setting"})
+			continue
+		}
+		fields = append(fields, validateSetting(prefix+setting.Name, setting, value)...)
+	}
+	return fields
+}
+
+func validateSetting(field string, setting AdapterSetting, value any) []SettingError {
+	if setting.Validate != nil {
+		if err := setting.Validate(value); err != nil {
+			return []SettingError{{Field: field, Message: err.Error()}}
+		}
+		return nil
+	}
+	if setting.Type == SettingTypeMap {
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return []SettingError{{Field: field, Message: fmt.Sprintf("expected a mapping, got %T", value)}}
+		}
+		if len(setting.Fields) == 0 {
+			return nil
+		}
+		if !setting.Keyed {
+			return validateSettings(field+".", setting.Fields, nested)
+		}
+		var fields []SettingError
+		for name, entry := range nested {
+			entryFields, ok := entry.(map[string]any)
+			if !ok {
+				fields = append(fields, SettingError{Field: field + "." + name, Message: fmt.Sprintf("expected a mapping, got %T", entry)})
+				continue
+			}
+			fields = append(fields, validateSettings(field+"."+name+".", setting.Fields, entryFields)...)
+		}
+		return fields
+	}
+	if message := checkSettingType(setting.Type, value); message != "" {
+		return []SettingError{{Field: field, Message: message}}
+	}
+	if text, ok := value.(string); ok && len(setting.Enum) > 0 && !slices.Contains(setting.Enum, strings.TrimSpace(text)) {
+		return []SettingError{{Field: field, Message: fmt.Sprintf("must be one of %s", strings.Join(setting.Enum, ", "))}}
+	}
+	return nil
+}
+
+// checkSettingType reports why value does not fit typ, accepting the same spellings the adapter
+// factories parse: numbers may arrive as JSON floats or strings and durations as Go duration
+// strings or seconds.
+func checkSettingType(typ string, value any) string {
+	switch typ {
+	case SettingTypeString:
+		if _, ok := value.(string); !ok {
+			return fmt.Sprintf("expected a string, got %T", value)
+		}
+	case SettingTypeInt:
+		switch v := value.(type) {
+		case int, int64:
+		case float64:
+			if v != math.Trunc(v) {
+				return fmt.Sprintf("expected an integer, got %v", v)
+			}
+		case string:
+			if _, err := strconv.Atoi(strings.TrimSpace(v)); err != nil {
+				return fmt.Sprintf("expected an integer, got %q", v)
+			}
+		default:
+			return fmt.Sprintf("expected an integer, got %T", value)
+		}
+	case SettingTypeFloat:
+		switch v := value.(type) {
+		case int, int64, float64:
+		case string:
+			if _, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+				return fmt.Sprintf("expected a number, got %q", v)
+			}
+		default:
+			return fmt.Sprintf("expected a number, got %T", value)
+		}
+	case SettingTypeBool:
+		switch v := value.(type) {
+		case bool:
+		case string:
+			if _, err := strconv.ParseBool(strings.TrimSpace(v)); err != nil {
+				return fmt.Sprintf("expected a boolean, got %q", v)
+			}
+		default:
+			return fmt.Sprintf("expected a boolean, got %T", value)
+		}
+	case SettingTypeDuration:
+		switch v := value.(type) {
+		case int, int64, float64:
+		case string:
+			if _, err := time.ParseDuration(strings.TrimSpace(v)); err != nil {
+				return fmt.Sprintf("expected a duration such as 5s, got %q", v)
+			}
+		default:
+			return fmt.Sprintf("expected a duration, got %T", value)
+		}
+	}
+	return ""
+}
+
+// secretSettingNames collects the lower-cased names of settings marked secret at any depth of the
+// schema.
+func secretSettingNames(schema []AdapterSetting) map[string]struct{} {
+	names := make(map[string]struct{})
+	var collect func([]AdapterSetting)
+	collect = func(settings []AdapterSetting) {
+		for _, setting := range settings {
+			if setting.Secret {
+				names[strings.ToLower(setting.Name)] = struct{}{}
+			}
+			collect(setting.Fields)
+		}
+	}
+	collect(schema)
+	return names
+}
+
+// ReconnectSetting describes the shared websocket reconnect policy setting, overlaid on base.
+func ReconnectSetting(base shared.ReconnectPolicy) AdapterSetting {
+	return AdapterSetting{
+		Name:        shared.ReconnectConfigKey,
+		Type:        SettingTypeMap,
+		Description: "Websocket reconnect policy: initial_interval, max_interval, multiplier, max_retries (0 retries forever) and jitter",
+		Default:     base.Settings(),
+		Enum:        nil,
+		Required:    false,
+		Secret:      false,
+		Fields: []AdapterSetting{
+			plainSetting("initial_interval", SettingTypeDuration, "Delay before the first reconnect attempt"),
+			plainSetting("max_interval", SettingTypeDuration, "Upper bound on the backoff delay"),
+			plainSetting("multiplier", SettingTypeFloat, "Backoff growth factor, at least 1"),
+			plainSetting("max_retries", SettingTypeInt, "Attempts before giving up; 0 retries forever"),
+			plainSetting("jitter", SettingTypeFloat, "Random spread applied to each delay, between 0 and 1"),
+		},
+		Keyed: false,
+		Validate: func(value any) error {
+			_, err := shared.ParseReconnectPolicy(value, base)
+			return err
+		},
+	}
+}
+
+// ProxySetting describes the shared egress proxy setting.
+func ProxySetting() AdapterSetting {
+	password := plainSetting("password", SettingTypeString, "Proxy password")
+	password.Secret = true
+	return AdapterSetting{
+		Name:        shared.ProxyConfigKey,
+		Type:        SettingTypeMap,
+		Description: "Egress proxy for REST and websocket traffic: url (http, https, socks5 or socks5h scheme), username and password",
+		Default:     nil,
+		Enum:        nil,
+		Required:    false,
+		Secret:      false,
+		Fields: []AdapterSetting{
+			plainSetting("url", SettingTypeString, "Proxy URL with scheme, host and port"),
+			plainSetting("username", SettingTypeString, "Proxy username"),
+			password,
+		},
+		Keyed: false,
+		Validate: func(value any) error {
+			_, err := shared.ParseProxyConfig(value)
+			return err
+		},
+	}
+}
+
+// EndpointsSetting describes the shared regional endpoints setting.
+func EndpointsSetting() AdapterSetting {
+	return AdapterSetting{
+		Name:        shared.EndpointsConfigKey,
+		Type:        SettingTypeMap,
+		Description: "Regional venue endpoints keyed by region name; the adapter probes their REST latency at startup and after connection failures and uses the fastest healthy one",
+		Default:     nil,
+		Enum:        nil,
+		Required:    false,
+		Secret:      false,
+		Fields: []AdapterSetting{
+			plainSetting("rest_url", SettingTypeString, "REST base URL, also used for latency probes"),
+			plainSetting("websocket_url", SettingTypeString, "Websocket base URL; empty keeps the adapter default"),
+			plainSetting("private_websocket_url", SettingTypeString, "Private websocket URL for adapters with a separate private stream; empty keeps the adapter default"),
+		},
+		Keyed: true,
+		Validate: func(value any) error {
+			_, err := shared.ParseEndpoints(value)
+			return err
+		},
+	}
+}
+
+func plainSetting(name, typ, description string) AdapterSetting {
+	return AdapterSetting{
+		Name:        name,
+		Type:        typ,
+		Description: description,
+		Default:     nil,
+		Enum:        nil,
+		Required:    false,
+		Secret:      false,
+		Fields:      nil,
+		Keyed:       false,
+		Validate:    nil,
+	}
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+func testSettingsSchema() []AdapterSetting {
+	key := plainSetting("signing_seed", SettingTypeString, "Signing seed")
+	key.Secret = true
+	key.Required = true
+	return []AdapterSetting{
+		key,
+		plainSetting("depth", SettingTypeInt, "Book depth"),
+		plainSetting("timeout", SettingTypeDuration, "Timeout"),
+		plainSetting("verbose", SettingTypeBool, "Verbose logging"),
+		ReconnectSetting(shared.DefaultReconnectPolicy(0)),
+		ProxySetting(),
+	}
+}
+
+func TestValidateAdapterSettingsReportsFieldErrors(t *testing.T) {
+	err := ValidateAdapterSettings("test", testSettingsSchema(), map[string]any{
+		"depth":     12.5,
+		"timeout":   "soon",
+		"verbose":   "maybe",
+		"reconnect": map[string]any{"jitter": 2},
+		"proxy":     map[string]any{"url": "http://proxy.internal:3128", "port": "1"},
+		"colour":    "blue",
+	})
+	var settingsErr *SettingsError
+	if !errors.As(err, &settingsErr) {
+		t.Fatalf("expected SettingsError, got %v", err)
+	}
+	got := make(map[string]string, len(settingsErr.Fields))
+	for _, field := range settingsErr.Fields {
+		got[field.Field] = field.Message
+	}
+	for _, field := range []string{"signing_seed", "depth", "timeout", "verbose", "reconnect", "proxy", "colour"} {
+		if _, ok := got[field]; !ok {
+			t.Fatalf("expected an error for %s, got %v", field, got)
+		}
+	}
+	if got["signing_seed"] != "required" || got["colour"] != "unknown setting" {
+		t.Fatalf("unexpected messages %v", got)
+	}
+}
+
+func TestValidateAdapterSettingsAcceptsParsedSpellings(t *testing.T) {
+	err := ValidateAdapterSettings("test", testSettingsSchema(), map[string]any{
+		"signing_seed": "seed",
+		"depth":        "100",
+		"timeout":      float64(5),
+		"verbose":      true,
+		"reconnect":    map[string]any{"initial_interval": "100ms", "max_interval": "1s"},
+		"proxy":        "socks5://proxy.internal:1080",
+	})
+	if err != nil {
+		t.Fatalf("expected config to validate, got %v", err)
+	}
+}
+
+func TestRegistryRedactsSchemaSecrets(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterWithMetadata("test", func(context.Context, *pool.PoolManager, map[string]any) (Instance, error) {
+		return nil, errors.New("unused")
+	}, AdapterMetadata{
+		Identifier:     "test",
+		DisplayName:    "Test",
+		Venue:          "",
+		Description:    "",
+		Capabilities:   nil,
+		SettingsSchema: testSettingsSchema(),
+	})
+
+	spec := reg.SanitizeProviderSpec(config.ProviderSpec{
+		Name:    "venue",
+		Adapter: "test",
+		Config: map[string]any{
+			"identifier": "test",
+			"config": map[string]any{
+				"signing_seed": "hidden",
+				"depth":        10,
+				"proxy":        map[string]any{"url": "http://proxy.internal:3128", "username": "gw", "password": "hidden"},
+			},
+		},
+	})
+	nested, _ := spec.Config["config"].(map[string]any)
+	if nested == nil {
+		t.Fatalf("expected nested config, got %#v", spec.Config)
+	}
+	if _, ok := nested["signing_seed"]; ok {
+		t.Fatal("expected schema secret to be redacted")
+	}
+	if nested["depth"] != 10 {
+		t.Fatalf("expected depth to be kept, got %#v", nested)
+	}
+	proxy, _ := nested["proxy"].(map[string]any)
+	if _, ok := proxy["password"]; ok || proxy["username"] != "gw" {
+		t.Fatalf("expected proxy password redacted and username kept, got %#v", proxy)
+	}
+
+	if err := reg.ValidateConfig("test", map[string]any{"depth": 1}); err == nil {
+		t.Fatal("expected missing signing_seed to fail validation")
+	}
+	if err := reg.ValidateConfig("unregistered", map[string]any{"anything": 1}); err != nil {
+		t.Fatalf("expected adapters without a schema to accept any config, got %v", err)
+	}
+}
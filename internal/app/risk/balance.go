@@ -0,0 +1,107 @@
+package risk
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// BreachTypeInsufficientBalance indicates the cached balance cannot cover the order.
+const BreachTypeInsufficientBalance BreachType = "INSUFFICIENT_BALANCE"
+
+type balanceSnapshot struct {
+	available decimal.Decimal
+	updatedAt time.Time
+}
+
+// ObserveBalance caches the available balance reported by a provider for a currency.
+func (m *Manager) ObserveBalance(provider string, payload schema.BalanceUpdatePayload) {
+	currency := schema.NormalizeCurrencyCode(payload.Currency)
+	if currency == "" {
+		return
+	}
+	available, err := decimal.NewFromString(strings.TrimSpace(payload.Available))
+	if err != nil {
+		return
+	}
+	updatedAt := payload.Timestamp
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+	m.mu.Lock()
+	m.balances[balanceKey(provider, currency)] = balanceSnapshot{available: available, updatedAt: updatedAt}
+	m.mu.Unlock()
+}
+
+// enforceBalanceLocked rejects spot orders whose cost exceeds the cached available balance less
+// reservations for open orders. Orders placed before the balance snapshot are assumed to be
+// reflected in it already. Currencies without a snapshot are not checked.
+func (m *Manager) enforceBalanceLocked(req *schema.OrderRequest, quantity, price decimal.Decimal) error {
+	if !m.limits.BalanceCheck {
+		return nil
+	}
+	base, quote, ok := spotCurrencies(req.Symbol)
+	if !ok {
+		return nil
+	}
+	currency, required := base, quantity
+	if req.Side == schema.TradeSideBuy {
+		currency, required = quote, quantity.Mul(price)
+	}
+	snapshot, ok := m.balances[balanceKey(req.Provider, currency)]
+	if !ok {
+		return nil
+	}
+	reserved := m.reservedBalanceLocked(req.Provider, currency, snapshot.updatedAt)
+	free := snapshot.available.Sub(reserved)
+	if required.LessThanOrEqual(free) {
+		return nil
+	}
+	return newBreachError(BreachTypeInsufficientBalance, "insufficient balance", nil, map[string]string{
+		"provider":  req.Provider,
+		"currency":  currency,
+		"required":  required.String(),
+		"available": snapshot.available.String(),
+		"reserved":  reserved.String(),
+	})
+}
+
+func (m *Manager) reservedBalanceLocked(provider, currency string, since time.Time) decimal.Decimal {
+	reserved := decimal.Zero
+	for _, order := range m.orders {
+		if order.provider != provider || !order.placedAt.After(since) {
+			continue
+		}
+		base, quote, ok := spotCurrencies(order.symbol)
+		if !ok {
+			continue
+		}
+		remaining := order.quantity.Sub(order.filled)
+		if !remaining.IsPositive() {
+			continue
+		}
+		switch {
+		case order.side == schema.TradeSideBuy && quote == currency:
+			reserved = reserved.Add(remaining.Mul(order.limitPx))
+		case order.side == schema.TradeSideSell && base == currency:
+			reserved = reserved.Add(remaining)
+		}
+	}
+	return reserved
+}
+
+// spotCurrencies splits a canonical spot symbol (BASE-QUOTE); derivatives are margined and skipped.
+func spotCurrencies(symbol string) (string, string, bool) {
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(symbol)), "-")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func balanceKey(provider, currency string) string {
+	return strings.ToLower(strings.TrimSpace(provider)) + "::" + currency
+}
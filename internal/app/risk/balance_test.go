@@ -0,0 +1,88 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestManager_BalanceCheckIncludesOpenOrderReservations(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(1_000_000),
+		OrderThrottle:    100,
+		OrderBurst:       100,
+		BalanceCheck:     true,
+	})
+	manager.ObserveBalance("binance-spot", schema.BalanceUpdatePayload{
+		Currency:  "usdt",
+		Available: "250",
+		Timestamp: time.Now().Add(-time.Second),
+	})
+	ctx := context.Background()
+	price := "100"
+	order := func(id, qty string) *schema.OrderRequest {
+		return &schema.OrderRequest{
+			ClientOrderID: id,
+			Provider:      "binance-spot",
+			Symbol:        "BTC-USDT",
+			Side:          schema.TradeSideBuy,
+			OrderType:     schema.OrderTypeLimit,
+			Price:         &price,
+			Quantity:      qty,
+		}
+	}
+
+	if err := manager.CheckOrder(ctx, order("buy-1", "2")); err != nil {
+		t.Fatalf("first order within balance: %v", err)
+	}
+	err := manager.CheckOrder(ctx, order("buy-2", "1"))
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach.Type != BreachTypeInsufficientBalance {
+		t.Fatalf("expected insufficient balance breach, got %v", err)
+	}
+	if breach.Details["currency"] != "USDT" || breach.Details["reserved"] != "200" {
+		t.Fatalf("unexpected breach details: %+v", breach.Details)
+	}
+
+	// A fresh snapshot already reflects the resting order's locked funds.
+	manager.ObserveBalance("binance-spot", schema.BalanceUpdatePayload{Currency: "USDT", Available: "150", Timestamp: time.Now().Add(time.Second)})
+	if err := manager.CheckOrder(ctx, order("buy-3", "1")); err != nil {
+		t.Fatalf("expected order covered by refreshed balance, got %v", err)
+	}
+}
+
+func TestManager_BalanceCheckSkipsUnknownAndDerivatives(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(1_000_000),
+		OrderThrottle:    100,
+		OrderBurst:       100,
+		BalanceCheck:     true,
+	})
+	manager.ObserveBalance("binance-spot", schema.BalanceUpdatePayload{Currency: "USDT", Available: "0"})
+	price := "100"
+	req := &schema.OrderRequest{
+		ClientOrderID: "perp-1",
+		Provider:      "binance-spot",
+		Symbol:        "BTC-USDT-PERP",
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      "1",
+	}
+	if err := manager.CheckOrder(context.Background(), req); err != nil {
+		t.Fatalf("derivatives are not balance checked: %v", err)
+	}
+	req.ClientOrderID = "sell-1"
+	req.Symbol = "BTC-USDT"
+	req.Side = schema.TradeSideSell
+	if err := manager.CheckOrder(context.Background(), req); err != nil {
+		t.Fatalf("currencies without a snapshot are not checked: %v", err)
+	}
+}
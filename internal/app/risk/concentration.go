@@ -0,0 +1,289 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// BreachTypeConcentration indicates that an order would concentrate too much of the portfolio in
+// one base asset or on one venue.
+const BreachTypeConcentration BreachType = "CONCENTRATION"
+
+var hundred = decimal.NewFromInt(100)
+
+// Concentration caps the share of the gross portfolio notional held in a single base asset or on a
+// single venue. Shares are measured across every manager sharing the same Portfolio.
+type Concentration struct {
+	// MaxAssetPercent is the largest share, in percent, one base asset may reach; zero disables it.
+	MaxAssetPercent float64
+	// MaxVenuePercent is the largest share, in percent, one provider may reach; zero disables it.
+	MaxVenuePercent float64
+	// MinPortfolioNotional is the gross notional below which shares are not enforced, so that the
+	// first positions of an empty portfolio are not rejected for being 100% of it.
+	MinPortfolioNotional decimal.Decimal
+}
+
+func (c Concentration) enabled() bool {
+	return c.MaxAssetPercent > 0 || c.MaxVenuePercent > 0
+}
+
+// ConcentrationReport summarises how the portfolio notional is spread over assets and venues.
+type ConcentrationReport struct {
+	NotionalCurrency     string               `json:"notionalCurrency"`
+	PortfolioNotional    string               `json:"portfolioNotional"`
+	MinPortfolioNotional string               `json:"minPortfolioNotional"`
+	MaxAssetPercent      float64              `json:"maxAssetPercent"`
+	MaxVenuePercent      float64              `json:"maxVenuePercent"`
+	Assets               []ConcentrationShare `json:"assets"`
+	Venues               []ConcentrationShare `json:"venues"`
+}
+
+// ConcentrationShare is the notional held in one asset or on one venue. Utilization is the share
+// as a fraction of its limit and is omitted when the limit is disabled.
+type ConcentrationShare struct {
+	Name        string   `json:"name"`
+	Notional    string   `json:"notional"`
+	Percent     float64  `json:"percent"`
+	Utilization *float64 `json:"utilization,omitempty"`
+}
+
+type exposure struct {
+	provider string
+	symbol   string
+	position decimal.Decimal
+	price    decimal.Decimal
+	// rate converts the quote currency of symbol into the notional currency.
+	rate decimal.Decimal
+}
+
+func (e *exposure) notional() decimal.Decimal {
+	return e.position.Abs().Mul(e.price).Mul(e.rate)
+}
+
+// Portfolio tracks filled positions per provider and symbol across strategy instances so that
+// concentration is measured on the whole book rather than per instance. Managers share a
+// portfolio by reference.
+type Portfolio struct {
+	mu        sync.Mutex
+	exposures map[string]*exposure
+}
+
+// NewPortfolio creates an empty portfolio.
+func NewPortfolio() *Portfolio {
+	return &Portfolio{
+		mu:        sync.Mutex{},
+		exposures: make(map[string]*exposure),
+	}
+}
+
+// apply adds a signed fill to the position of symbol on provider and marks it at price.
+func (p *Portfolio) apply(provider, symbol string, change, price, rate decimal.Decimal) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := provider + "::" + symbol
+	entry, ok := p.exposures[key]
+	if !ok {
+		entry = &exposure{
+			provider: provider,
+			symbol:   symbol,
+			position: decimal.Zero,
+			price:    decimal.Zero,
+			rate:     decimal.Zero,
+		}
+		p.exposures[key] = entry
+	}
+	entry.position = entry.position.Add(change)
+	entry.price = price
+	entry.rate = rate
+	if entry.position.IsZero() {
+		delete(p.exposures, key)
+	}
+}
+
+// mark revalues every position in symbol at price.
+func (p *Portfolio) mark(symbol string, price decimal.Decimal) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, entry := range p.exposures {
+		if entry.symbol == symbol {
+			entry.price = price
+		}
+	}
+}
+
+type concentrationTotals struct {
+	portfolio decimal.Decimal
+	assets    map[string]decimal.Decimal
+	venues    map[string]decimal.Decimal
+}
+
+// totalsLocked sums the notional per asset and venue. When override is set it replaces the
+// exposure with the same provider and symbol, which projects the effect of an order.
+func (p *Portfolio) totalsLocked(override *exposure) concentrationTotals {
+	totals := concentrationTotals{
+		portfolio: decimal.Zero,
+		assets:    make(map[string]decimal.Decimal),
+		venues:    make(map[string]decimal.Decimal),
+	}
+	add := func(entry *exposure) {
+		notional := entry.notional()
+		asset := baseCurrency(entry.symbol)
+		totals.portfolio = totals.portfolio.Add(notional)
+		totals.assets[asset] = totals.assets[asset].Add(notional)
+		totals.venues[entry.provider] = totals.venues[entry.provider].Add(notional)
+	}
+	for _, entry := range p.exposures {
+		if override != nil && entry.provider == override.provider && entry.symbol == override.symbol {
+			continue
+		}
+		add(entry)
+	}
+	if override != nil {
+		add(override)
+	}
+	return totals
+}
+
+// check rejects a signed change of the position in symbol on provider, valued at price, when it
+// raises the notional of the base asset or the venue above its share of the projected portfolio.
+func (p *Portfolio) check(provider, symbol string, change, price, rate decimal.Decimal, limits Concentration) error {
+	if !limits.enabled() {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	projected := &exposure{provider: provider, symbol: symbol, position: change, price: price, rate: rate}
+	if current, ok := p.exposures[provider+"::"+symbol]; ok {
+		projected.position = current.position.Add(change)
+	}
+	before := p.totalsLocked(nil)
+	after := p.totalsLocked(projected)
+	if !after.portfolio.IsPositive() || after.portfolio.LessThan(limits.MinPortfolioNotional) {
+		return nil
+	}
+	asset := baseCurrency(symbol)
+	if err := shareBreach("asset", asset, before.assets[asset], after.assets[asset], after.portfolio, limits.MaxAssetPercent); err != nil {
+		return err
+	}
+	return shareBreach("venue", provider, before.venues[provider], after.venues[provider], after.portfolio, limits.MaxVenuePercent)
+}
+
+func shareBreach(scope, name string, before, after, portfolio decimal.Decimal, maxPercent float64) error {
+	if maxPercent <= 0 || !after.GreaterThan(before) {
+		return nil
+	}
+	percent := after.Div(portfolio).Mul(hundred)
+	if !percent.GreaterThan(decimal.NewFromFloat(maxPercent)) {
+		return nil
+	}
+	return newBreachError(BreachTypeConcentration, fmt.Sprintf("projected %s concentration exceeds maximum", scope), nil, map[string]string{
+		"scope":             scope,
+		scope:               name,
+		"projectedNotional": after.String(),
+		"portfolioNotional": portfolio.String(),
+		"percent":           percent.StringFixed(2),
+		"maxPercent":        fmt.Sprintf("%.2f", maxPercent),
+	})
+}
+
+// report summarises the current spread of the portfolio against limits.
+func (p *Portfolio) report(limits Concentration, currency string) ConcentrationReport {
+	p.mu.Lock()
+	totals := p.totalsLocked(nil)
+	p.mu.Unlock()
+	return ConcentrationReport{
+		NotionalCurrency:     currency,
+		PortfolioNotional:    totals.portfolio.String(),
+		MinPortfolioNotional: limits.MinPortfolioNotional.String(),
+		MaxAssetPercent:      limits.MaxAssetPercent,
+		MaxVenuePercent:      limits.MaxVenuePercent,
+		Assets:               concentrationShares(totals.assets, totals.portfolio, limits.MaxAssetPercent),
+		Venues:               concentrationShares(totals.venues, totals.portfolio, limits.MaxVenuePercent),
+	}
+}
+
+// concentrationShares lists the shares largest first.
+func concentrationShares(notionals map[string]decimal.Decimal, portfolio decimal.Decimal, maxPercent float64) []ConcentrationShare {
+	shares := make([]ConcentrationShare, 0, len(notionals))
+	for name, notional := range notionals {
+		share := ConcentrationShare{Name: name, Notional: notional.String(), Percent: 0, Utilization: nil}
+		if portfolio.IsPositive() {
+			share.Percent, _ = notional.Div(portfolio).Mul(hundred).Float64()
+		}
+		if maxPercent > 0 {
+			utilization := share.Percent / maxPercent
+			share.Utilization = &utilization
+		}
+		shares = append(shares, share)
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Percent != shares[j].Percent {
+			return shares[i].Percent > shares[j].Percent
+		}
+		return shares[i].Name < shares[j].Name
+	})
+	return shares
+}
+
+// baseCurrency extracts the base asset from a canonical BASE-QUOTE symbol.
+func baseCurrency(symbol string) string {
+	trimmed := strings.TrimSpace(symbol)
+	if idx := strings.LastIndex(trimmed, "-"); idx > 0 {
+		trimmed = trimmed[:idx]
+	}
+	return strings.ToUpper(trimmed)
+}
+
+// Portfolio returns the portfolio the manager measures concentration against.
+func (m *Manager) Portfolio() *Portfolio {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.portfolio
+}
+
+// SetPortfolio replaces the portfolio of the manager, typically with one shared with the managers
+// of other strategy instances. A nil portfolio starts an empty one.
+func (m *Manager) SetPortfolio(portfolio *Portfolio) {
+	if portfolio == nil {
+		portfolio = NewPortfolio()
+	}
+	m.mu.Lock()
+	m.portfolio = portfolio
+	m.mu.Unlock()
+}
+
+// Concentration reports the spread of the portfolio against the concentration limits of the manager.
+func (m *Manager) Concentration() ConcentrationReport {
+	m.mu.RLock()
+	limits := m.limits.Concentration
+	currency := m.limits.NotionalCurrency
+	portfolio := m.portfolio
+	m.mu.RUnlock()
+	return portfolio.report(limits, currency)
+}
+
+// enforceConcentrationLocked checks the order against the concentration limits. Orders whose quote
+// currency cannot be converted are left to the notional check.
+func (m *Manager) enforceConcentrationLocked(provider, symbol string, change, price decimal.Decimal) error {
+	if !m.limits.Concentration.enabled() {
+		return nil
+	}
+	rate, ok := m.conversionRateLocked(quoteCurrency(symbol))
+	if !ok {
+		return nil
+	}
+	return m.portfolio.check(provider, symbol, change, price, rate, m.limits.Concentration)
+}
+
+// recordPortfolioFillLocked forwards a fill to the portfolio valued in the notional currency.
+func (m *Manager) recordPortfolioFillLocked(provider, symbol string, change, price decimal.Decimal) {
+	rate, ok := m.conversionRateLocked(quoteCurrency(symbol))
+	if !ok {
+		rate = decimal.Zero
+	}
+	m.portfolio.apply(provider, symbol, change, price, rate)
+}
@@ -0,0 +1,104 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func concentrationOrder(id, provider, symbol, quantity, price string) *schema.OrderRequest {
+	return &schema.OrderRequest{
+		ClientOrderID: id,
+		Provider:      provider,
+		Symbol:        symbol,
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      quantity,
+	}
+}
+
+func fillOrder(t *testing.T, manager *Manager, req *schema.OrderRequest) {
+	t.Helper()
+	if err := manager.CheckOrder(context.Background(), req); err != nil {
+		t.Fatalf("CheckOrder %s: %v", req.ClientOrderID, err)
+	}
+	manager.HandleExecution(req.Symbol, schema.ExecReportPayload{
+		ClientOrderID:  req.ClientOrderID,
+		State:          schema.ExecReportStateFILLED,
+		Side:           req.Side,
+		FilledQuantity: req.Quantity,
+		AvgFillPrice:   *req.Price,
+	})
+}
+
+func TestManager_ConcentrationAcrossSharedPortfolio(t *testing.T) {
+	limits := Limits{
+		OrderThrottle:    100,
+		OrderBurst:       10,
+		NotionalCurrency: "USDT",
+		Concentration: Concentration{
+			MaxAssetPercent:      60,
+			MaxVenuePercent:      70,
+			MinPortfolioNotional: decimal.NewFromInt(1_000),
+		},
+	}
+	first := NewManager(limits)
+	second := NewManager(limits)
+	second.SetPortfolio(first.Portfolio())
+
+	// Below the minimum portfolio notional shares are not enforced.
+	fillOrder(t, first, concentrationOrder("btc-1", "binance", "BTC-USDT", "1", "500"))
+	fillOrder(t, second, concentrationOrder("eth-1", "okx", "ETH-USDT", "1", "500"))
+
+	err := second.CheckOrder(context.Background(), concentrationOrder("btc-2", "okx", "BTC-USDT", "1", "500"))
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach.Type != BreachTypeConcentration || breach.Details["asset"] != "BTC" {
+		t.Fatalf("expected BTC concentration breach, got %v", err)
+	}
+	if breach.Details["percent"] != "66.67" {
+		t.Fatalf("unexpected breach details %+v", breach.Details)
+	}
+
+	err = first.CheckOrder(context.Background(), concentrationOrder("sol-1", "binance", "SOL-USDT", "10", "100"))
+	if !errors.As(err, &breach) || breach.Details["venue"] != "binance" {
+		t.Fatalf("expected venue concentration breach, got %v", err)
+	}
+
+	sell := concentrationOrder("btc-sell", "binance", "BTC-USDT", "0.5", "500")
+	sell.Side = schema.TradeSideSell
+	if err := first.CheckOrder(context.Background(), sell); err != nil {
+		t.Fatalf("expected reducing order to pass, got %v", err)
+	}
+
+	report := first.Concentration()
+	if report.PortfolioNotional != "1000" || len(report.Assets) != 2 || len(report.Venues) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if report.Assets[0].Percent != 50 || report.Assets[0].Utilization == nil {
+		t.Fatalf("unexpected asset share %+v", report.Assets[0])
+	}
+
+	first.ObserveMarketPrice("BTC-USDT", decimal.NewFromInt(1_500))
+	if report := first.Concentration(); report.PortfolioNotional != "2000" || report.Assets[0].Name != "BTC" {
+		t.Fatalf("expected market marks to revalue the portfolio, got %+v", report)
+	}
+}
+
+func TestManager_SimulateReportsConcentration(t *testing.T) {
+	manager := NewManager(Limits{
+		OrderThrottle: 100,
+		OrderBurst:    10,
+		Concentration: Concentration{MaxAssetPercent: 50, MinPortfolioNotional: decimal.NewFromInt(150)},
+	})
+	fillOrder(t, manager, concentrationOrder("eth-1", "binance", "ETH-USDT", "1", "100"))
+
+	sim := manager.Simulate(*concentrationOrder("eth-2", "binance", "ETH-USDT", "1", "100"))
+	if check := findCheck(t, sim, CheckConcentration); check.Passed || check.BreachType != BreachTypeConcentration {
+		t.Fatalf("expected concentration failure, got %+v", check)
+	}
+}
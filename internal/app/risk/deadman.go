@@ -0,0 +1,115 @@
+package risk
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DeadMansSwitchReason is the kill switch reason recorded when heartbeats stop arriving.
+const DeadMansSwitchReason = "dead man's switch: controller heartbeat missed"
+
+const minDeadMansSwitchCheckInterval = 10 * time.Millisecond
+
+// DeadMansSwitchStatus captures the state of the dead man's switch at a point in time.
+type DeadMansSwitchStatus struct {
+	Interval      time.Duration
+	LastHeartbeat time.Time
+	Deadline      time.Time
+	Tripped       bool
+	TrippedAt     time.Time
+}
+
+// DeadMansSwitch trips once an external controller fails to heartbeat within the interval.
+//
+// The switch is armed on construction, so a controller that never connects trips it as well.
+// After tripping, the next heartbeat re-arms the switch; resuming trading is left to the caller.
+type DeadMansSwitch struct {
+	interval time.Duration
+	clock    func() time.Time
+	onTrip   func(ctx context.Context)
+
+	mu        sync.Mutex
+	lastBeat  time.Time
+	tripped   bool
+	trippedAt time.Time
+}
+
+// NewDeadMansSwitch creates an armed switch invoking onTrip when the heartbeat deadline passes.
+func NewDeadMansSwitch(interval time.Duration, clock func() time.Time, onTrip func(ctx context.Context)) *DeadMansSwitch {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &DeadMansSwitch{
+		interval:  interval,
+		clock:     clock,
+		onTrip:    onTrip,
+		mu:        sync.Mutex{},
+		lastBeat:  clock(),
+		tripped:   false,
+		trippedAt: time.Time{},
+	}
+}
+
+// Heartbeat records controller liveness and re-arms a tripped switch.
+func (d *DeadMansSwitch) Heartbeat() DeadMansSwitchStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastBeat = d.clock()
+	d.tripped = false
+	d.trippedAt = time.Time{}
+	return d.statusLocked()
+}
+
+// Status returns the current switch state.
+func (d *DeadMansSwitch) Status() DeadMansSwitchStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.statusLocked()
+}
+
+// Check trips the switch when the heartbeat deadline has passed. It reports whether this call
+// tripped the switch; onTrip runs at most once per missed deadline.
+func (d *DeadMansSwitch) Check(ctx context.Context) bool {
+	d.mu.Lock()
+	now := d.clock()
+	if d.tripped || now.Before(d.lastBeat.Add(d.interval)) {
+		d.mu.Unlock()
+		return false
+	}
+	d.tripped = true
+	d.trippedAt = now
+	d.mu.Unlock()
+	if d.onTrip != nil {
+		d.onTrip(ctx)
+	}
+	return true
+}
+
+// Run evaluates the deadline several times per interval until ctx is cancelled.
+func (d *DeadMansSwitch) Run(ctx context.Context) {
+	every := d.interval / 4
+	if every < minDeadMansSwitchCheckInterval {
+		every = minDeadMansSwitchCheckInterval
+	}
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.Check(ctx)
+		}
+	}
+}
+
+func (d *DeadMansSwitch) statusLocked() DeadMansSwitchStatus {
+	return DeadMansSwitchStatus{
+		Interval:      d.interval,
+		LastHeartbeat: d.lastBeat,
+		Deadline:      d.lastBeat.Add(d.interval),
+		Tripped:       d.tripped,
+		TrippedAt:     d.trippedAt,
+	}
+}
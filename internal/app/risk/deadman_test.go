@@ -0,0 +1,79 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestDeadMansSwitch_TripsOnceAfterMissedHeartbeat(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	clock := func() time.Time { return now }
+	trips := 0
+	sw := NewDeadMansSwitch(10*time.Second, clock, func(context.Context) { trips++ })
+
+	now = now.Add(9 * time.Second)
+	if sw.Check(context.Background()) {
+		t.Fatal("switch tripped before the deadline")
+	}
+	now = now.Add(2 * time.Second)
+	if !sw.Check(context.Background()) {
+		t.Fatal("expected switch to trip after the deadline")
+	}
+	if sw.Check(context.Background()) {
+		t.Fatal("switch tripped twice for the same missed deadline")
+	}
+	if trips != 1 {
+		t.Fatalf("expected one trip callback, got %d", trips)
+	}
+	status := sw.Status()
+	if !status.Tripped || !status.TrippedAt.Equal(now) {
+		t.Fatalf("unexpected status after trip: %+v", status)
+	}
+
+	status = sw.Heartbeat()
+	if status.Tripped {
+		t.Fatal("heartbeat should re-arm the switch")
+	}
+	if !status.Deadline.Equal(now.Add(10 * time.Second)) {
+		t.Fatalf("unexpected deadline %s", status.Deadline)
+	}
+}
+
+func TestManager_HaltAndResume(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(100),
+		MaxNotionalValue: decimal.NewFromInt(1_000_000),
+		OrderThrottle:    100,
+		OrderBurst:       10,
+	})
+	price := "1"
+	req := &schema.OrderRequest{
+		Provider:      "binance-spot",
+		Symbol:        "BTC-USDT",
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      "1",
+		ClientOrderID: "ord-halt",
+	}
+
+	manager.Halt(DeadMansSwitchReason)
+	if err := manager.CheckOrder(context.Background(), req); !errors.Is(err, ErrKillSwitchEngaged) {
+		t.Fatalf("expected kill switch error while halted, got %v", err)
+	}
+	if manager.Resume("some other reason") {
+		t.Fatal("resume with a different reason should not lift the halt")
+	}
+	if !manager.Resume(DeadMansSwitchReason) {
+		t.Fatal("expected resume to lift the halt")
+	}
+	if err := manager.CheckOrder(context.Background(), req); err != nil {
+		t.Fatalf("expected order to pass after resume, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package risk
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// quoteCurrency extracts the quote asset from a canonical BASE-QUOTE symbol.
+func quoteCurrency(symbol string) string {
+	trimmed := strings.TrimSpace(symbol)
+	idx := strings.LastIndex(trimmed, "-")
+	if idx < 0 || idx == len(trimmed)-1 {
+		return ""
+	}
+	return strings.ToUpper(trimmed[idx+1:])
+}
+
+// conversionRateLocked returns the multiplier converting an amount in quote into the notional
+// currency. Observed market prices take precedence over static rates when enabled; symbols
+// without a recognisable quote, or limits without a notional currency, convert at par.
+func (m *Manager) conversionRateLocked(quote string) (decimal.Decimal, bool) {
+	target := strings.ToUpper(strings.TrimSpace(m.limits.NotionalCurrency))
+	if quote == "" || target == "" || quote == target {
+		return decimal.NewFromInt(1), true
+	}
+	if m.limits.UseMarketFXRates {
+		if px, ok := m.marketPrices[quote+"-"+target]; ok && px.GreaterThan(decimal.Zero) {
+			return px, true
+		}
+		if px, ok := m.marketPrices[target+"-"+quote]; ok && px.GreaterThan(decimal.Zero) {
+			return decimal.NewFromInt(1).Div(px), true
+		}
+	}
+	if rate, ok := m.limits.FXRates[quote]; ok && rate.GreaterThan(decimal.Zero) {
+		return rate, true
+	}
+	return decimal.Zero, false
+}
+
+// ConversionRate returns the multiplier converting an amount of asset from into asset to. Observed
+// prices of from-to or to-from pairs are used first; otherwise both assets are converted through the
+// notional currency. Assets without any known price or rate report false.
+func (m *Manager) ConversionRate(from, to string) (decimal.Decimal, bool) {
+	from = strings.ToUpper(strings.TrimSpace(from))
+	to = strings.ToUpper(strings.TrimSpace(to))
+	if from == "" || to == "" {
+		return decimal.Zero, false
+	}
+	if from == to {
+		return decimal.NewFromInt(1), true
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if px, ok := m.marketPrices[from+"-"+to]; ok && px.GreaterThan(decimal.Zero) {
+		return px, true
+	}
+	if px, ok := m.marketPrices[to+"-"+from]; ok && px.GreaterThan(decimal.Zero) {
+		return decimal.NewFromInt(1).Div(px), true
+	}
+	if strings.TrimSpace(m.limits.NotionalCurrency) == "" {
+		return decimal.Zero, false
+	}
+	fromRate, ok := m.conversionRateLocked(from)
+	if !ok {
+		return decimal.Zero, false
+	}
+	toRate, ok := m.conversionRateLocked(to)
+	if !ok || toRate.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero, false
+	}
+	return fromRate.Div(toRate), true
+}
+
+// normalizeFXRates upper-cases currency codes, drops non-positive rates, and copies the map.
+func normalizeFXRates(rates map[string]decimal.Decimal) map[string]decimal.Decimal {
+	if len(rates) == 0 {
+		return nil
+	}
+	out := make(map[string]decimal.Decimal, len(rates))
+	for currency, rate := range rates {
+		code := strings.ToUpper(strings.TrimSpace(currency))
+		if code == "" || rate.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+		out[code] = rate
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func fxOrder(symbol, price, quantity, id string) *schema.OrderRequest {
+	return &schema.OrderRequest{
+		Provider:      "binance-spot",
+		Symbol:        symbol,
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      quantity,
+		ClientOrderID: id,
+	}
+}
+
+func TestManager_CheckOrder_NotionalConvertedWithStaticRates(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(10_000),
+		NotionalCurrency: "USDT",
+		OrderThrottle:    100,
+		OrderBurst:       10,
+		FXRates: map[string]decimal.Decimal{
+			"btc": decimal.NewFromInt(50_000),
+			"EUR": decimal.RequireFromString("1.1"),
+		},
+	})
+
+	// 2 ETH at 0.05 BTC = 0.1 BTC = 5,000 USDT.
+	if err := manager.CheckOrder(context.Background(), fxOrder("ETH-BTC", "0.05", "2", "eth-1")); err != nil {
+		t.Fatalf("expected ETH-BTC order within converted limit, got %v", err)
+	}
+	// 1 BTC at 10,000 EUR = 11,000 USDT.
+	err := manager.CheckOrder(context.Background(), fxOrder("BTC-EUR", "10000", "1", "eur-1"))
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach.Type != BreachTypeNotionalLimit {
+		t.Fatalf("expected notional breach for EUR-quoted order, got %v", err)
+	}
+	if breach.Details["fxRate"] != "1.1" {
+		t.Fatalf("expected fx rate detail 1.1, got %q", breach.Details["fxRate"])
+	}
+}
+
+func TestManager_CheckOrder_NotionalUsesMarketRates(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(10_000),
+		NotionalCurrency: "USDT",
+		OrderThrottle:    100,
+		OrderBurst:       10,
+		FXRates:          map[string]decimal.Decimal{"BTC": decimal.NewFromInt(1_000_000)},
+		UseMarketFXRates: true,
+	})
+	manager.ObserveMarketPrice("BTC-USDT", decimal.NewFromInt(40_000))
+
+	// 5 ETH at 0.05 BTC = 0.25 BTC = 10,000 USDT at the market rate; the static rate would reject it.
+	if err := manager.CheckOrder(context.Background(), fxOrder("ETH-BTC", "0.05", "5", "eth-1")); err != nil {
+		t.Fatalf("expected market rate to be used, got %v", err)
+	}
+}
+
+func TestManager_CheckOrder_RejectsUnknownQuoteCurrency(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(10_000),
+		NotionalCurrency: "USDT",
+		OrderThrottle:    100,
+		OrderBurst:       10,
+		FXRates:          map[string]decimal.Decimal{"EUR": decimal.RequireFromString("1.1")},
+	})
+
+	err := manager.CheckOrder(context.Background(), fxOrder("ETH-BTC", "0.05", "1", "eth-1"))
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach.Type != BreachTypeNotionalLimit {
+		t.Fatalf("expected notional breach without fx rate, got %v", err)
+	}
+	if breach.Details["quoteCurrency"] != "BTC" {
+		t.Fatalf("expected quote currency detail BTC, got %q", breach.Details["quoteCurrency"])
+	}
+	if err := manager.CheckOrder(context.Background(), fxOrder("BTC-USDT", "100", "1", "btc-1")); err != nil {
+		t.Fatalf("expected same-currency order to pass, got %v", err)
+	}
+}
+
+func TestManager_CheckOrder_RejectsUnpricedQuoteWithoutFXRates(t *testing.T) {
+	// Without static rates and before any market price is seen, no quote currency other than the
+	// notional currency is taken at par.
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(10_000),
+		NotionalCurrency: "USDT",
+		OrderThrottle:    100,
+		OrderBurst:       10,
+		UseMarketFXRates: true,
+	})
+
+	err := manager.CheckOrder(context.Background(), fxOrder("BTC-USDC", "5000", "1", "usdc-1"))
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach.Type != BreachTypeNotionalLimit || breach.Details["quoteCurrency"] != "USDC" {
+		t.Fatalf("expected USDC-quoted order rejected without a rate, got %v", err)
+	}
+	manager.ObserveMarketPrice("USDC-USDT", decimal.NewFromInt(1))
+	if err := manager.CheckOrder(context.Background(), fxOrder("BTC-USDC", "5000", "1", "usdc-2")); err != nil {
+		t.Fatalf("expected USDC-quoted order priced by the market rate, got %v", err)
+	}
+}
+
+func TestManager_ConversionRateBetweenAssets(t *testing.T) {
+	manager := NewManager(Limits{
+		NotionalCurrency: "USDT",
+		OrderThrottle:    100,
+		OrderBurst:       10,
+		FXRates:          map[string]decimal.Decimal{"EUR": decimal.RequireFromString("1.25")},
+	})
+	manager.ObserveMarketPrice("BTC-USDT", decimal.NewFromInt(40_000))
+	manager.ObserveMarketPrice("ETH-BTC", decimal.RequireFromString("0.05"))
+
+	cases := []struct {
+		from, to string
+		want     string
+	}{
+		{"btc", "usdt", "40000"},
+		{"USDT", "BTC", "0.000025"},
+		{"ETH", "BTC", "0.05"},
+		{"EUR", "USDT", "1.25"},
+		{"USDT", "EUR", "0.8"},
+		{"USDT", "USDT", "1"},
+	}
+	for _, tc := range cases {
+		rate, ok := manager.ConversionRate(tc.from, tc.to)
+		if !ok || !rate.Equal(decimal.RequireFromString(tc.want)) {
+			t.Fatalf("ConversionRate(%s, %s) = %s, %v; want %s", tc.from, tc.to, rate, ok, tc.want)
+		}
+	}
+	if _, ok := manager.ConversionRate("DOGE", "USDT"); ok {
+		t.Fatal("expected no rate for an asset without price or fx rate")
+	}
+}
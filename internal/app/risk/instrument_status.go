@@ -0,0 +1,41 @@
+package risk
+
+import (
+	"strings"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// BreachTypeInstrumentStatus indicates the venue is not accepting orders for the instrument.
+const BreachTypeInstrumentStatus BreachType = "INSTRUMENT_STATUS"
+
+// ObserveInstrumentStatus records the venue trading status for a provider instrument.
+// Orders for instruments that are not tradable are rejected until a trading status is observed.
+func (m *Manager) ObserveInstrumentStatus(provider, symbol string, status schema.InstrumentStatus) {
+	key := instrumentStatusKey(provider, symbol)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if status.Tradable() {
+		delete(m.instrumentStatus, key)
+		return
+	}
+	m.instrumentStatus[key] = status
+}
+
+// enforceInstrumentStatusLocked rejects orders for halted, suspended or delisted instruments.
+// Rejections reflect venue state rather than strategy behaviour, so they are not counted as breaches.
+func (m *Manager) enforceInstrumentStatusLocked(req *schema.OrderRequest) error {
+	status, ok := m.instrumentStatus[instrumentStatusKey(req.Provider, req.Symbol)]
+	if !ok {
+		return nil
+	}
+	return newBreachError(BreachTypeInstrumentStatus, "instrument not tradable", nil, map[string]string{
+		"provider": req.Provider,
+		"symbol":   req.Symbol,
+		"status":   string(status),
+	})
+}
+
+func instrumentStatusKey(provider, symbol string) string {
+	return strings.ToLower(strings.TrimSpace(provider)) + "::" + strings.ToUpper(strings.TrimSpace(symbol))
+}
@@ -0,0 +1,49 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestManager_CheckOrder_RejectsHaltedInstrument(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(1_000_000),
+		OrderThrottle:    100,
+		OrderBurst:       100,
+	})
+	price := "1"
+	req := &schema.OrderRequest{
+		Provider:      "binance-spot",
+		Symbol:        "BTC-USDT",
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      "1",
+		ClientOrderID: "ord-1",
+	}
+
+	manager.ObserveInstrumentStatus("binance-spot", "btc-usdt", schema.InstrumentStatusHalted)
+	err := manager.CheckOrder(context.Background(), req)
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach.Type != BreachTypeInstrumentStatus {
+		t.Fatalf("expected instrument status breach, got %v", err)
+	}
+	if breach.Details["status"] != string(schema.InstrumentStatusHalted) {
+		t.Fatalf("expected halted status in details, got %+v", breach.Details)
+	}
+	if engaged, _ := manager.KillSwitchStatus(); engaged {
+		t.Fatal("venue halts must not count toward the kill switch")
+	}
+
+	manager.ObserveInstrumentStatus("binance-spot", "BTC-USDT", schema.InstrumentStatusTrading)
+	req.ClientOrderID = "ord-2"
+	if err := manager.CheckOrder(context.Background(), req); err != nil {
+		t.Fatalf("expected order accepted after trading resumed, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -40,6 +41,8 @@ const (
 	BreachTypeNotionalLimit BreachType = "NOTIONAL_LIMIT"
 	// BreachTypeConcurrency denotes concurrency limits were breached.
 	BreachTypeConcurrency BreachType = "CONCURRENCY"
+	// BreachTypeTimeInForce denotes a time in force outside the allowed list was requested.
+	BreachTypeTimeInForce BreachType = "TIME_IN_FORCE"
 	// BreachTypeKillSwitch indicates a manual or automatic kill switch activation.
 	BreachTypeKillSwitch BreachType = "KILL_SWITCH"
 )
@@ -51,7 +54,10 @@ type BreachError struct {
 	Details            map[string]string
 	KillSwitchEngaged  bool
 	CircuitBreakerOpen bool
-	Err                error
+	// RetryAfter is set on throttle breaches raised by CheckOrderNoWait and reports when a
+	// throttle token becomes available.
+	RetryAfter time.Duration
+	Err        error
 }
 
 // Error implements the error interface.
@@ -87,6 +93,7 @@ func newBreachError(t BreachType, reason string, err error, details map[string]s
 		Details:            copied,
 		KillSwitchEngaged:  false,
 		CircuitBreakerOpen: false,
+		RetryAfter:         0,
 		Err:                err,
 	}
 }
@@ -108,17 +115,55 @@ type Limits struct {
 	MaxConcurrentOrders int
 	PriceBandPercent    float64
 	AllowedOrderTypes   []schema.OrderType
-	KillSwitchEnabled   bool
-	MaxRiskBreaches     int
-	CircuitBreaker      CircuitBreaker
+	// AllowedTimeInForce restricts orders to the listed time-in-force values; empty allows all.
+	AllowedTimeInForce []schema.TimeInForce
+	KillSwitchEnabled  bool
+	MaxRiskBreaches    int
+	CircuitBreaker     CircuitBreaker
+	// FXRates maps a currency code to the value of one unit in NotionalCurrency.
+	FXRates map[string]decimal.Decimal
+	// UseMarketFXRates prefers observed market prices (e.g. BTC-USDT) over static FXRates.
+	UseMarketFXRates bool
+	// SelfTradePrevention decides what happens when instances would trade against each other.
+	SelfTradePrevention SelfTradePolicy
+	// BalanceCheck rejects spot orders that exceed cached provider balances.
+	BalanceCheck bool
+	// Concentration caps the share of the portfolio held in one asset or on one venue.
+	Concentration Concentration
+}
+
+// LimitIncreases describes the position and notional limits next raises by more than percent above
+// current. A zero limit is unlimited, so lifting a limit counts as an increase.
+func LimitIncreases(current, next Limits, percent float64) []string {
+	factor := decimal.NewFromFloat(1 + percent/100)
+	var raised []string
+	check := func(name string, limit, value decimal.Decimal) {
+		if !limit.IsPositive() {
+			return
+		}
+		switch {
+		case !value.IsPositive():
+			raised = append(raised, fmt.Sprintf("%s %s → unlimited", name, limit))
+		case value.GreaterThan(limit.Mul(factor)):
+			raised = append(raised, fmt.Sprintf("%s %s → %s", name, limit, value))
+		}
+	}
+	check("maxPositionSize", current.MaxPositionSize, next.MaxPositionSize)
+	check("maxNotionalValue", current.MaxNotionalValue, next.MaxNotionalValue)
+	return raised
 }
 
 type orderState struct {
-	symbol   string
-	side     schema.TradeSide
-	quantity decimal.Decimal
-	filled   decimal.Decimal
-	limitPx  decimal.Decimal
+	provider      string
+	consumer      string
+	symbol        string
+	side          schema.TradeSide
+	orderType     schema.OrderType
+	quantity      decimal.Decimal
+	filled        decimal.Decimal
+	limitPx       decimal.Decimal
+	placedAt      time.Time
+	cancelPending bool
 }
 
 // Manager enforces risk limits for trading strategies.
@@ -132,12 +177,24 @@ type Manager struct {
 	notionals     map[string]decimal.Decimal
 	marketPrices  map[string]decimal.Decimal
 	orders        map[string]*orderState
-	inflight      map[string]int
-	allowedTypes  map[string]struct{}
-	failureCount  int
-	killSwitch    bool
-	killReason    string
-	cooldownUntil time.Time
+	// instrumentStatus holds non-tradable venue statuses keyed by provider and symbol.
+	instrumentStatus map[string]schema.InstrumentStatus
+	balances         map[string]balanceSnapshot
+	inflight         map[string]int
+	allowedTypes     map[string]struct{}
+	failureCount     int
+	killSwitch       bool
+	killReason       string
+	cooldownUntil    time.Time
+	restingCanceller RestingOrderCanceller
+	// accepted records recently accepted orders for order-rate reporting.
+	accepted []acceptedOrder
+	// rules are the custom checks evaluated after the built-in ones.
+	rules *RuleSet
+	// portfolio aggregates fills across managers for concentration limits.
+	portfolio *Portfolio
+	// symbols bars symbols from trading on compliance grounds.
+	symbols *SymbolPolicy
 }
 
 func normalizeAllowedOrderTypes(types []schema.OrderType) []schema.OrderType {
@@ -167,6 +224,8 @@ func NewManager(limits Limits) *Manager {
 	if len(limitCopy.AllowedOrderTypes) > 0 {
 		limitCopy.AllowedOrderTypes = normalizeAllowedOrderTypes(limits.AllowedOrderTypes)
 	}
+	limitCopy.AllowedTimeInForce = slices.Clone(limits.AllowedTimeInForce)
+	limitCopy.FXRates = normalizeFXRates(limits.FXRates)
 	burst := limitCopy.OrderBurst
 	if burst <= 0 {
 		burst = 1
@@ -176,20 +235,27 @@ func NewManager(limits Limits) *Manager {
 		allowed[strings.ToLower(string(ot))] = struct{}{}
 	}
 	return &Manager{
-		limits:        limitCopy,
-		mu:            sync.RWMutex{},
-		limiter:       rate.NewLimiter(rate.Limit(limitCopy.OrderThrottle), burst),
-		symbolLimiter: make(map[string]*rate.Limiter),
-		positions:     make(map[string]decimal.Decimal),
-		notionals:     make(map[string]decimal.Decimal),
-		marketPrices:  make(map[string]decimal.Decimal),
-		orders:        make(map[string]*orderState),
-		inflight:      make(map[string]int),
-		allowedTypes:  allowed,
-		failureCount:  0,
-		killSwitch:    false,
-		killReason:    "",
-		cooldownUntil: time.Time{},
+		limits:           limitCopy,
+		mu:               sync.RWMutex{},
+		limiter:          rate.NewLimiter(rate.Limit(limitCopy.OrderThrottle), burst),
+		symbolLimiter:    make(map[string]*rate.Limiter),
+		positions:        make(map[string]decimal.Decimal),
+		notionals:        make(map[string]decimal.Decimal),
+		marketPrices:     make(map[string]decimal.Decimal),
+		orders:           make(map[string]*orderState),
+		instrumentStatus: make(map[string]schema.InstrumentStatus),
+		balances:         make(map[string]balanceSnapshot),
+		inflight:         make(map[string]int),
+		allowedTypes:     allowed,
+		failureCount:     0,
+		killSwitch:       false,
+		killReason:       "",
+		cooldownUntil:    time.Time{},
+		restingCanceller: nil,
+		accepted:         nil,
+		rules:            NewRuleSet(),
+		portfolio:        NewPortfolio(),
+		symbols:          nil,
 	}
 }
 
@@ -201,6 +267,8 @@ func (m *Manager) UpdateLimits(limits Limits) {
 	if len(limitCopy.AllowedOrderTypes) > 0 {
 		limitCopy.AllowedOrderTypes = normalizeAllowedOrderTypes(limits.AllowedOrderTypes)
 	}
+	limitCopy.AllowedTimeInForce = slices.Clone(limits.AllowedTimeInForce)
+	limitCopy.FXRates = normalizeFXRates(limits.FXRates)
 	m.limits = limitCopy
 	burst := limitCopy.OrderBurst
 	if burst <= 0 {
@@ -219,6 +287,9 @@ func (m *Manager) CheckOrder(ctx context.Context, req *schema.OrderRequest) erro
 	if req == nil {
 		return fmt.Errorf("nil order request")
 	}
+	if err := m.enforceSymbolPolicy(req); err != nil {
+		return err
+	}
 
 	if err := m.limiter.Wait(ctx); err != nil {
 		return newBreachError(BreachTypeRateLimit, "order throttle limit exceeded", err, map[string]string{
@@ -237,6 +308,15 @@ func (m *Manager) CheckOrder(ctx context.Context, req *schema.OrderRequest) erro
 		})
 	}
 
+	return m.admitOrder(ctx, req)
+}
+
+// admitOrder runs every check after the throttle and records the accepted order.
+func (m *Manager) admitOrder(ctx context.Context, req *schema.OrderRequest) error {
+	if err := m.preventSelfTrade(ctx, req); err != nil {
+		return err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -244,11 +324,20 @@ func (m *Manager) CheckOrder(ctx context.Context, req *schema.OrderRequest) erro
 		return err
 	}
 
+	if err := m.enforceInstrumentStatusLocked(req); err != nil {
+		return err
+	}
+
 	if err := m.enforceOrderTypeLocked(req.OrderType); err != nil {
 		m.recordRiskBreachLocked(err)
 		return err
 	}
 
+	if err := m.enforceTimeInForceLocked(req); err != nil {
+		m.recordRiskBreachLocked(err)
+		return err
+	}
+
 	quantity, err := decimal.NewFromString(req.Quantity)
 	if err != nil {
 		breach := newBreachError(BreachTypeOrderValidation, "invalid order quantity", err, map[string]string{
@@ -271,29 +360,23 @@ func (m *Manager) CheckOrder(ctx context.Context, req *schema.OrderRequest) erro
 		return err
 	}
 
-	signedQty := signedQuantity(req.Side, quantity)
-	currentPos := m.positions[req.Symbol]
-	projected := currentPos.Add(signedQty)
-
-	if m.limits.MaxPositionSize.GreaterThan(decimal.Zero) {
-		if projected.Abs().GreaterThan(m.limits.MaxPositionSize) {
-			breach := newBreachError(BreachTypePositionLimit, "projected position exceeds maximum", nil, map[string]string{
-				"projectedPosition": projected.Abs().String(),
-				"maxPosition":       m.limits.MaxPositionSize.String(),
-			})
-			m.recordRiskBreachLocked(breach)
-			return breach
-		}
+	projected := m.positions[req.Symbol].Add(signedQuantity(req.Side, quantity))
+	if err := m.enforcePositionLocked(projected); err != nil {
+		m.recordRiskBreachLocked(err)
+		return err
+	}
+	if _, err := m.enforceNotionalLocked(req.Symbol, projected, price); err != nil {
+		m.recordRiskBreachLocked(err)
+		return err
+	}
+	if err := m.enforceConcentrationLocked(req.Provider, req.Symbol, signedQuantity(req.Side, quantity), price); err != nil {
+		m.recordRiskBreachLocked(err)
+		return err
 	}
 
-	notional := projected.Abs().Mul(price)
-	if m.limits.MaxNotionalValue.GreaterThan(decimal.Zero) && notional.GreaterThan(m.limits.MaxNotionalValue) {
-		breach := newBreachError(BreachTypeNotionalLimit, "projected notional exceeds maximum", nil, map[string]string{
-			"projectedNotional": notional.String(),
-			"maxNotional":       m.limits.MaxNotionalValue.String(),
-		})
-		m.recordRiskBreachLocked(breach)
-		return breach
+	if err := m.enforceBalanceLocked(req, quantity, price); err != nil {
+		m.recordRiskBreachLocked(err)
+		return err
 	}
 
 	if err := m.enforceConcurrencyLocked(req.Symbol); err != nil {
@@ -301,13 +384,27 @@ func (m *Manager) CheckOrder(ctx context.Context, req *schema.OrderRequest) erro
 		return err
 	}
 
-	m.orders[req.ClientOrderID] = &orderState{
-		symbol:   req.Symbol,
-		side:     req.Side,
-		quantity: quantity,
-		filled:   decimal.Zero,
-		limitPx:  price,
+	for _, verdict := range m.rules.evaluate(ctx, *req, m.ruleStateLocked(req.Symbol, projected, price), true) {
+		if err := verdict.err(); err != nil {
+			m.recordRiskBreachLocked(err)
+			return err
+		}
 	}
+
+	now := time.Now()
+	m.orders[req.ClientOrderID] = &orderState{
+		provider:      req.Provider,
+		consumer:      req.ConsumerID,
+		symbol:        req.Symbol,
+		side:          req.Side,
+		orderType:     req.OrderType,
+		quantity:      quantity,
+		filled:        decimal.Zero,
+		limitPx:       price,
+		placedAt:      now,
+		cancelPending: false,
+	}
+	m.recordAcceptedLocked(req.ConsumerID, now)
 	return nil
 }
 
@@ -318,7 +415,9 @@ func (m *Manager) ObserveMarketPrice(symbol string, price decimal.Decimal) {
 	}
 	m.mu.Lock()
 	m.marketPrices[symbol] = price
+	portfolio := m.portfolio
 	m.mu.Unlock()
+	portfolio.mark(symbol, price)
 }
 
 // HandleExecution updates position state in response to execution reports.
@@ -346,7 +445,7 @@ func (m *Manager) HandleExecution(symbol string, payload schema.ExecReportPayloa
 				price = avg
 			}
 		}
-		m.applyFillLocked(symbol, payload.Side, delta, price)
+		m.applyFillLocked(order.provider, symbol, payload.Side, delta, price)
 		order.filled = cumFilled
 	}
 
@@ -374,6 +473,31 @@ func (m *Manager) ResetKillSwitch() {
 	m.mu.Unlock()
 }
 
+// Halt engages the kill switch with the supplied reason regardless of the configured limits.
+// The halt persists until ResetKillSwitch or Resume is called.
+func (m *Manager) Halt(reason string) {
+	m.mu.Lock()
+	m.killSwitch = true
+	m.killReason = reason
+	m.cooldownUntil = time.Time{}
+	m.mu.Unlock()
+}
+
+// Resume clears the kill switch only when it was engaged with the supplied reason.
+// It reports whether trading was resumed.
+func (m *Manager) Resume(reason string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.killSwitch || m.killReason != reason {
+		return false
+	}
+	m.killSwitch = false
+	m.killReason = ""
+	m.failureCount = 0
+	m.cooldownUntil = time.Time{}
+	return true
+}
+
 // KillSwitchStatus returns the current halt flag and reason.
 func (m *Manager) KillSwitchStatus() (bool, string) {
 	m.mu.RLock()
@@ -385,10 +509,18 @@ func (m *Manager) KillSwitchStatus() (bool, string) {
 func (m *Manager) Limits() Limits {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	limitCopy := m.limits
+	return cloneLimits(m.limits)
+}
+
+func cloneLimits(limits Limits) Limits {
+	limitCopy := limits
 	if len(limitCopy.AllowedOrderTypes) > 0 {
 		limitCopy.AllowedOrderTypes = append([]schema.OrderType(nil), limitCopy.AllowedOrderTypes...)
 	}
+	if len(limitCopy.AllowedTimeInForce) > 0 {
+		limitCopy.AllowedTimeInForce = append([]schema.TimeInForce(nil), limitCopy.AllowedTimeInForce...)
+	}
+	limitCopy.FXRates = normalizeFXRates(limitCopy.FXRates)
 	return limitCopy
 }
 
@@ -408,20 +540,63 @@ func (m *Manager) symbolLimiterFor(provider, symbol string) (*rate.Limiter, erro
 	return lim, nil
 }
 
+func (m *Manager) enforcePositionLocked(projected decimal.Decimal) error {
+	if !m.limits.MaxPositionSize.GreaterThan(decimal.Zero) || !projected.Abs().GreaterThan(m.limits.MaxPositionSize) {
+		return nil
+	}
+	return newBreachError(BreachTypePositionLimit, "projected position exceeds maximum", nil, map[string]string{
+		"projectedPosition": projected.Abs().String(),
+		"maxPosition":       m.limits.MaxPositionSize.String(),
+	})
+}
+
+// enforceNotionalLocked checks the projected position value in the notional currency and returns
+// it; the value is zero when the notional limit is disabled.
+func (m *Manager) enforceNotionalLocked(symbol string, projected, price decimal.Decimal) (decimal.Decimal, error) {
+	if !m.limits.MaxNotionalValue.GreaterThan(decimal.Zero) {
+		return decimal.Zero, nil
+	}
+	quote := quoteCurrency(symbol)
+	rate, ok := m.conversionRateLocked(quote)
+	if !ok {
+		return decimal.Zero, newBreachError(BreachTypeNotionalLimit, "no fx rate for quote currency", nil, map[string]string{
+			"quoteCurrency":    quote,
+			"notionalCurrency": m.limits.NotionalCurrency,
+		})
+	}
+	notional := projected.Abs().Mul(price).Mul(rate)
+	if notional.GreaterThan(m.limits.MaxNotionalValue) {
+		return notional, newBreachError(BreachTypeNotionalLimit, "projected notional exceeds maximum", nil, map[string]string{
+			"projectedNotional": notional.String(),
+			"maxNotional":       m.limits.MaxNotionalValue.String(),
+			"quoteCurrency":     quote,
+			"fxRate":            rate.String(),
+		})
+	}
+	return notional, nil
+}
+
 func (m *Manager) ensureTradingActiveLocked() error {
-	if m.killSwitch {
-		if m.limits.CircuitBreaker.Enabled && !m.cooldownUntil.IsZero() && time.Now().After(m.cooldownUntil) {
-			m.killSwitch = false
-			m.killReason = ""
-		}
+	if m.killSwitch && m.cooldownElapsedLocked(time.Now()) {
+		m.killSwitch = false
+		m.killReason = ""
 	}
-	if m.killSwitch {
-		if m.limits.CircuitBreaker.Enabled && time.Now().Before(m.cooldownUntil) {
-			return ErrCircuitBreakerOpen
-		}
-		return ErrKillSwitchEngaged
+	return m.tradingHaltErrorLocked(time.Now())
+}
+
+// cooldownElapsedLocked reports whether an engaged circuit breaker has cooled down at now.
+func (m *Manager) cooldownElapsedLocked(now time.Time) bool {
+	return m.limits.CircuitBreaker.Enabled && !m.cooldownUntil.IsZero() && now.After(m.cooldownUntil)
+}
+
+func (m *Manager) tradingHaltErrorLocked(now time.Time) error {
+	if !m.killSwitch {
+		return nil
 	}
-	return nil
+	if m.limits.CircuitBreaker.Enabled && now.Before(m.cooldownUntil) {
+		return ErrCircuitBreakerOpen
+	}
+	return ErrKillSwitchEngaged
 }
 
 func (m *Manager) enforceOrderTypeLocked(orderType schema.OrderType) error {
@@ -436,7 +611,35 @@ func (m *Manager) enforceOrderTypeLocked(orderType schema.OrderType) error {
 	})
 }
 
+// enforceTimeInForceLocked checks the order's time in force, defaulted by order type when empty,
+// against the allowed list.
+func (m *Manager) enforceTimeInForceLocked(req *schema.OrderRequest) error {
+	if len(m.limits.AllowedTimeInForce) == 0 {
+		return nil
+	}
+	tif := req.TIF
+	if tif == "" {
+		tif = schema.DefaultTimeInForce(req.OrderType)
+	}
+	if slices.Contains(m.limits.AllowedTimeInForce, tif) {
+		return nil
+	}
+	return newBreachError(BreachTypeTimeInForce, fmt.Sprintf("time in force %s not allowed", tif), nil, map[string]string{
+		"tif": string(tif),
+	})
+}
+
 func (m *Manager) resolveOrderPriceLocked(req *schema.OrderRequest) (decimal.Decimal, error) {
+	price, err := m.orderPriceLocked(req)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return m.validatePriceBandLocked(req.Symbol, price)
+}
+
+// orderPriceLocked parses the order price, falling back to the last market price for market
+// orders without one.
+func (m *Manager) orderPriceLocked(req *schema.OrderRequest) (decimal.Decimal, error) {
 	if req.OrderType == schema.OrderTypeLimit {
 		if req.Price == nil {
 			return decimal.Zero, newBreachError(BreachTypeOrderValidation, "limit order requires price", nil, nil)
@@ -445,7 +648,7 @@ func (m *Manager) resolveOrderPriceLocked(req *schema.OrderRequest) (decimal.Dec
 		if err != nil {
 			return decimal.Zero, newBreachError(BreachTypeOrderValidation, "invalid limit price", err, map[string]string{"price": *req.Price})
 		}
-		return m.validatePriceBandLocked(req.Symbol, price)
+		return price, nil
 	}
 
 	if req.Price != nil {
@@ -453,29 +656,42 @@ func (m *Manager) resolveOrderPriceLocked(req *schema.OrderRequest) (decimal.Dec
 		if err != nil {
 			return decimal.Zero, newBreachError(BreachTypeOrderValidation, "invalid price", err, map[string]string{"price": *req.Price})
 		}
-		return m.validatePriceBandLocked(req.Symbol, price)
+		return price, nil
 	}
 
 	market, ok := m.marketPrices[req.Symbol]
 	if !ok || market.LessThanOrEqual(decimal.Zero) {
 		return decimal.Zero, newBreachError(BreachTypeOrderValidation, "market price unavailable", nil, map[string]string{"symbol": req.Symbol})
 	}
-	return m.validatePriceBandLocked(req.Symbol, market)
+	return market, nil
 }
 
 func (m *Manager) validatePriceBandLocked(symbol string, price decimal.Decimal) (decimal.Decimal, error) {
+	if err := m.priceBandBreachLocked(symbol, price); err != nil {
+		return decimal.Zero, err
+	}
+	if m.limits.PriceBandPercent > 0 {
+		if reference, ok := m.marketPrices[symbol]; !ok || reference.LessThanOrEqual(decimal.Zero) {
+			m.marketPrices[symbol] = price
+		}
+	}
+	return price, nil
+}
+
+// priceBandBreachLocked reports whether price strays outside the band around the reference
+// price. Without a reference price every price passes.
+func (m *Manager) priceBandBreachLocked(symbol string, price decimal.Decimal) error {
 	if m.limits.PriceBandPercent <= 0 {
-		return price, nil
+		return nil
 	}
 	reference, ok := m.marketPrices[symbol]
 	if !ok || reference.LessThanOrEqual(decimal.Zero) {
-		m.marketPrices[symbol] = price
-		return price, nil
+		return nil
 	}
 	upper := reference.Mul(decimal.NewFromFloat(1 + m.limits.PriceBandPercent/100))
 	lower := reference.Mul(decimal.NewFromFloat(1 - m.limits.PriceBandPercent/100))
 	if price.GreaterThan(upper) || price.LessThan(lower) {
-		return decimal.Zero, newBreachError(BreachTypePriceBand, "price outside allowable band", nil, map[string]string{
+		return newBreachError(BreachTypePriceBand, "price outside allowable band", nil, map[string]string{
 			"price":       price.String(),
 			"bandLower":   lower.String(),
 			"bandUpper":   upper.String(),
@@ -483,12 +699,19 @@ func (m *Manager) validatePriceBandLocked(symbol string, price decimal.Decimal)
 			"bandPercent": fmt.Sprintf("%.4f", m.limits.PriceBandPercent),
 		})
 	}
-	return price, nil
+	return nil
 }
 
 func (m *Manager) enforceConcurrencyLocked(symbol string) error {
+	if err := m.concurrencyBreachLocked(symbol); err != nil {
+		return err
+	}
+	m.inflight[symbol]++
+	return nil
+}
+
+func (m *Manager) concurrencyBreachLocked(symbol string) error {
 	if m.limits.MaxConcurrentOrders <= 0 {
-		m.inflight[symbol]++
 		return nil
 	}
 	current := m.inflight[symbol]
@@ -499,7 +722,6 @@ func (m *Manager) enforceConcurrencyLocked(symbol string) error {
 			"symbol":  symbol,
 		})
 	}
-	m.inflight[symbol] = current + 1
 	return nil
 }
 
@@ -509,11 +731,12 @@ func (m *Manager) releaseOrderLocked(symbol string) {
 	}
 }
 
-func (m *Manager) applyFillLocked(symbol string, side schema.TradeSide, fillQty, fillPrice decimal.Decimal) {
+func (m *Manager) applyFillLocked(provider, symbol string, side schema.TradeSide, fillQty, fillPrice decimal.Decimal) {
 	if fillQty.LessThanOrEqual(decimal.Zero) {
 		return
 	}
 	change := signedQuantity(side, fillQty)
+	m.recordPortfolioFillLocked(provider, symbol, change, fillPrice)
 	position := m.positions[symbol]
 	position = position.Add(change)
 	m.positions[symbol] = position
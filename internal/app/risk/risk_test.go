@@ -230,3 +230,43 @@ func TestManager_AllowedOrderTypesCaseInsensitive(t *testing.T) {
 		t.Fatalf("expected breach type %s, got %s", BreachTypeOrderType, breach.Type)
 	}
 }
+
+func TestManager_AllowedTimeInForce(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:     decimal.NewFromInt(1_000),
+		MaxNotionalValue:    decimal.NewFromInt(1_000_000),
+		OrderThrottle:       100,
+		OrderBurst:          100,
+		MaxConcurrentOrders: 10,
+		AllowedTimeInForce:  []schema.TimeInForce{schema.TimeInForceGTC, schema.TimeInForceGTX},
+	})
+	price := "10"
+	req := schema.OrderRequest{
+		ClientOrderID: "ord-1",
+		Provider:      "demo",
+		Symbol:        "BTC-USDT",
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      "1",
+	}
+	// An empty time in force is checked as the limit default, GTC.
+	if err := manager.CheckOrder(context.Background(), &req); err != nil {
+		t.Fatalf("expected default time in force accepted, got %v", err)
+	}
+	req.ClientOrderID = "ord-2"
+	req.TIF = schema.TimeInForceFOK
+	var breach *BreachError
+	if err := manager.CheckOrder(context.Background(), &req); !errors.As(err, &breach) || breach.Type != BreachTypeTimeInForce {
+		t.Fatalf("expected time in force breach, got %v", err)
+	}
+	sim := manager.Simulate(req)
+	for _, check := range sim.Checks {
+		if check.Check == CheckTimeInForce && check.Passed {
+			t.Fatal("expected simulation to fail the time in force check")
+		}
+	}
+	if sim.Allowed {
+		t.Fatal("expected simulation to reject FOK")
+	}
+}
@@ -0,0 +1,345 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// BreachTypeCustomRule indicates that a registered RiskRule denied the order.
+const BreachTypeCustomRule BreachType = "CUSTOM_RULE"
+
+// ruleDecisionHistory bounds the number of recent rule decisions kept for observability.
+const ruleDecisionHistory = 256
+
+// RiskRule is a custom pre-trade check evaluated after the built-in checks have passed. Rules run
+// while the manager holds its lock, so Evaluate must be fast and must not call back into the Manager.
+//
+//nolint:revive // RiskRule reads better than risk.Rule at call sites outside the package.
+type RiskRule interface {
+	// Name identifies the rule in the API and in recorded decisions; it must be unique per RuleSet.
+	Name() string
+	// Evaluate decides whether order may be sent given the exposure it would leave.
+	Evaluate(ctx context.Context, order schema.OrderRequest, state RuleState) Decision
+}
+
+// Decision is the verdict of a RiskRule. Reason and Details are reported with denials.
+type Decision struct {
+	Allow   bool
+	Reason  string
+	Details map[string]string
+}
+
+// Allow returns a decision admitting the order.
+func Allow() Decision {
+	return Decision{Allow: true, Reason: "", Details: nil}
+}
+
+// Deny returns a decision rejecting the order for reason.
+func Deny(reason string, details map[string]string) Decision {
+	return Decision{Allow: false, Reason: reason, Details: details}
+}
+
+// RuleState is the manager state a rule sees for the order being evaluated.
+type RuleState struct {
+	// Position is the current position on the order symbol and ProjectedPosition the position
+	// once the order fills completely.
+	Position          decimal.Decimal
+	ProjectedPosition decimal.Decimal
+	// Price is the order price, or the last market price for market orders.
+	Price decimal.Decimal
+	// InFlight counts the open orders on the order symbol.
+	InFlight int
+	Limits   Limits
+}
+
+// RuleOption customises how a rule is registered.
+type RuleOption func(*registeredRule)
+
+// WithRuleOrder sets the evaluation order of a rule; lower orders run first and rules of equal
+// order run in registration order.
+func WithRuleOrder(order int) RuleOption {
+	return func(r *registeredRule) {
+		r.order = order
+	}
+}
+
+// WithRuleDisabled registers a rule switched off until it is enabled through SetEnabled.
+func WithRuleDisabled() RuleOption {
+	return func(r *registeredRule) {
+		r.enabled = false
+	}
+}
+
+// RuleStatus describes a registered rule and how often it ran.
+type RuleStatus struct {
+	Name        string `json:"name"`
+	Order       int    `json:"order"`
+	Enabled     bool   `json:"enabled"`
+	Evaluations int64  `json:"evaluations"`
+	Denials     int64  `json:"denials"`
+}
+
+// RuleDecision records the verdict a rule reached for one order.
+type RuleDecision struct {
+	Rule          string            `json:"rule"`
+	Allowed       bool              `json:"allowed"`
+	Reason        string            `json:"reason,omitempty"`
+	Details       map[string]string `json:"details,omitempty"`
+	ClientOrderID string            `json:"clientOrderId"`
+	ConsumerID    string            `json:"consumerId,omitempty"`
+	Symbol        string            `json:"symbol"`
+	At            time.Time         `json:"at"`
+}
+
+type registeredRule struct {
+	rule        RiskRule
+	name        string
+	order       int
+	seq         int
+	enabled     bool
+	evaluations int64
+	denials     int64
+}
+
+// RuleSet is an ordered collection of custom risk rules. A set may be shared by several managers
+// so that rules registered once apply to every strategy instance.
+type RuleSet struct {
+	mu        sync.Mutex
+	rules     []*registeredRule
+	seq       int
+	decisions []RuleDecision
+	next      int
+}
+
+// NewRuleSet creates an empty rule set.
+func NewRuleSet() *RuleSet {
+	return &RuleSet{
+		mu:        sync.Mutex{},
+		rules:     nil,
+		seq:       0,
+		decisions: make([]RuleDecision, 0, ruleDecisionHistory),
+		next:      0,
+	}
+}
+
+// Register adds rule to the set, enabled unless WithRuleDisabled is supplied.
+func (s *RuleSet) Register(rule RiskRule, opts ...RuleOption) error {
+	if rule == nil {
+		return fmt.Errorf("risk rule required")
+	}
+	name := strings.TrimSpace(rule.Name())
+	if name == "" {
+		return fmt.Errorf("risk rule name required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.rules {
+		if existing.name == name {
+			return fmt.Errorf("risk rule %q already registered", name)
+		}
+	}
+	s.seq++
+	registered := &registeredRule{
+		rule:        rule,
+		name:        name,
+		order:       0,
+		seq:         s.seq,
+		enabled:     true,
+		evaluations: 0,
+		denials:     0,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(registered)
+		}
+	}
+	s.rules = append(s.rules, registered)
+	sort.SliceStable(s.rules, func(i, j int) bool {
+		if s.rules[i].order != s.rules[j].order {
+			return s.rules[i].order < s.rules[j].order
+		}
+		return s.rules[i].seq < s.rules[j].seq
+	})
+	return nil
+}
+
+// SetEnabled switches the named rule on or off and returns its updated status.
+func (s *RuleSet) SetEnabled(name string, enabled bool) (RuleStatus, bool) {
+	name = strings.TrimSpace(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rule := range s.rules {
+		if rule.name == name {
+			rule.enabled = enabled
+			return rule.status(), true
+		}
+	}
+	var empty RuleStatus
+	return empty, false
+}
+
+// Rules returns the status of every registered rule in evaluation order.
+func (s *RuleSet) Rules() []RuleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]RuleStatus, 0, len(s.rules))
+	for _, rule := range s.rules {
+		out = append(out, rule.status())
+	}
+	return out
+}
+
+// Decisions returns the most recent rule decisions, oldest first.
+func (s *RuleSet) Decisions() []RuleDecision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]RuleDecision, 0, len(s.decisions))
+	if len(s.decisions) == ruleDecisionHistory {
+		out = append(out, s.decisions[s.next:]...)
+		return append(out, s.decisions[:s.next]...)
+	}
+	return append(out, s.decisions...)
+}
+
+func (r *registeredRule) status() RuleStatus {
+	return RuleStatus{
+		Name:        r.name,
+		Order:       r.order,
+		Enabled:     r.enabled,
+		Evaluations: r.evaluations,
+		Denials:     r.denials,
+	}
+}
+
+// enabledRules returns the enabled rules in evaluation order.
+func (s *RuleSet) enabledRules() []*registeredRule {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*registeredRule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		if rule.enabled {
+			out = append(out, rule)
+		}
+	}
+	return out
+}
+
+// evaluate runs the enabled rules against order and stops at the first denial. When record is set
+// every verdict is counted and kept in the decision history.
+func (s *RuleSet) evaluate(ctx context.Context, order schema.OrderRequest, state RuleState, record bool) []ruleVerdict {
+	rules := s.enabledRules()
+	verdicts := make([]ruleVerdict, 0, len(rules))
+	for _, rule := range rules {
+		decision := evaluateRule(ctx, rule.rule, order, state)
+		verdicts = append(verdicts, ruleVerdict{name: rule.name, decision: decision})
+		if record {
+			s.record(rule, order, decision)
+		}
+		if !decision.Allow {
+			break
+		}
+	}
+	return verdicts
+}
+
+type ruleVerdict struct {
+	name     string
+	decision Decision
+}
+
+// err converts a denial into the breach reported to the caller.
+func (v ruleVerdict) err() error {
+	if v.decision.Allow {
+		return nil
+	}
+	details := make(map[string]string, len(v.decision.Details)+1)
+	for key, value := range v.decision.Details {
+		details[key] = value
+	}
+	details["rule"] = v.name
+	reason := v.decision.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("denied by risk rule %s", v.name)
+	}
+	return newBreachError(BreachTypeCustomRule, reason, nil, details)
+}
+
+// evaluateRule shields the manager from panicking rules by treating a panic as a denial.
+func evaluateRule(ctx context.Context, rule RiskRule, order schema.OrderRequest, state RuleState) (decision Decision) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			decision = Deny(fmt.Sprintf("risk rule panicked: %v", recovered), nil)
+		}
+	}()
+	return rule.Evaluate(ctx, order, state)
+}
+
+func (s *RuleSet) record(rule *registeredRule, order schema.OrderRequest, decision Decision) {
+	entry := RuleDecision{
+		Rule:          rule.name,
+		Allowed:       decision.Allow,
+		Reason:        decision.Reason,
+		Details:       decision.Details,
+		ClientOrderID: order.ClientOrderID,
+		ConsumerID:    order.ConsumerID,
+		Symbol:        order.Symbol,
+		At:            time.Now(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule.evaluations++
+	if !decision.Allow {
+		rule.denials++
+	}
+	if len(s.decisions) < ruleDecisionHistory {
+		s.decisions = append(s.decisions, entry)
+		return
+	}
+	s.decisions[s.next] = entry
+	s.next = (s.next + 1) % ruleDecisionHistory
+}
+
+// RegisterRule adds a custom rule to the rules of the manager.
+func (m *Manager) RegisterRule(rule RiskRule, opts ...RuleOption) error {
+	return m.RuleSet().Register(rule, opts...)
+}
+
+// RuleSet returns the custom rules evaluated by the manager.
+func (m *Manager) RuleSet() *RuleSet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rules
+}
+
+// SetRuleSet replaces the custom rules of the manager, typically with a set shared with other
+// managers. A nil set clears the rules.
+func (m *Manager) SetRuleSet(set *RuleSet) {
+	if set == nil {
+		set = NewRuleSet()
+	}
+	m.mu.Lock()
+	m.rules = set
+	m.mu.Unlock()
+}
+
+// ruleStateLocked gathers the state custom rules evaluate an order against.
+func (m *Manager) ruleStateLocked(symbol string, projected, price decimal.Decimal) RuleState {
+	return RuleState{
+		Position:          m.positions[symbol],
+		ProjectedPosition: projected,
+		Price:             price,
+		InFlight:          m.inflight[symbol],
+		Limits:            cloneLimits(m.limits),
+	}
+}
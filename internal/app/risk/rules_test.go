@@ -0,0 +1,139 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+type funcRule struct {
+	name string
+	fn   func(order schema.OrderRequest, state RuleState) Decision
+}
+
+func (r funcRule) Name() string { return r.name }
+
+func (r funcRule) Evaluate(_ context.Context, order schema.OrderRequest, state RuleState) Decision {
+	return r.fn(order, state)
+}
+
+func ruleOrder(id, quantity string) *schema.OrderRequest {
+	price := "100"
+	return &schema.OrderRequest{
+		ClientOrderID: id,
+		ConsumerID:    "lambda-1",
+		Provider:      "binance-spot",
+		Symbol:        "BTC-USDT",
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      quantity,
+	}
+}
+
+func TestManager_EvaluatesRulesInOrderAndRecordsDecisions(t *testing.T) {
+	manager := NewManager(Limits{OrderThrottle: 100, OrderBurst: 10})
+	var calls []string
+	allow := funcRule{name: "allow-all", fn: func(schema.OrderRequest, RuleState) Decision {
+		calls = append(calls, "allow-all")
+		return Allow()
+	}}
+	maxQty := funcRule{name: "max-qty", fn: func(order schema.OrderRequest, state RuleState) Decision {
+		calls = append(calls, "max-qty")
+		if state.ProjectedPosition.GreaterThan(decimal.NewFromInt(2)) {
+			return Deny("projected position above 2", map[string]string{"projected": state.ProjectedPosition.String()})
+		}
+		return Allow()
+	}}
+	if err := manager.RegisterRule(allow, WithRuleOrder(10)); err != nil {
+		t.Fatalf("register allow-all: %v", err)
+	}
+	if err := manager.RegisterRule(maxQty, WithRuleOrder(1)); err != nil {
+		t.Fatalf("register max-qty: %v", err)
+	}
+	if err := manager.RegisterRule(maxQty); err == nil {
+		t.Fatal("expected duplicate rule names to be rejected")
+	}
+
+	if err := manager.CheckOrder(context.Background(), ruleOrder("ok", "1")); err != nil {
+		t.Fatalf("expected order to pass rules: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "max-qty" || calls[1] != "allow-all" {
+		t.Fatalf("expected rules evaluated by order, got %v", calls)
+	}
+
+	err := manager.CheckOrder(context.Background(), ruleOrder("big", "5"))
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach.Type != BreachTypeCustomRule || breach.Details["rule"] != "max-qty" {
+		t.Fatalf("expected custom rule breach, got %v", err)
+	}
+	if breach.Details["projected"] != "5" {
+		t.Fatalf("expected rule details on the breach, got %+v", breach.Details)
+	}
+
+	decisions := manager.RuleSet().Decisions()
+	last := decisions[len(decisions)-1]
+	if len(decisions) != 3 || last.Rule != "max-qty" || last.Allowed || last.ClientOrderID != "big" {
+		t.Fatalf("unexpected decisions %+v", decisions)
+	}
+	statuses := manager.RuleSet().Rules()
+	if statuses[0].Name != "max-qty" || statuses[0].Evaluations != 2 || statuses[0].Denials != 1 {
+		t.Fatalf("unexpected rule status %+v", statuses)
+	}
+}
+
+func TestRuleSet_DisabledRulesAreSkipped(t *testing.T) {
+	manager := NewManager(Limits{OrderThrottle: 100, OrderBurst: 10})
+	deny := funcRule{name: "deny", fn: func(schema.OrderRequest, RuleState) Decision { return Deny("", nil) }}
+	if err := manager.RegisterRule(deny, WithRuleDisabled()); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := manager.CheckOrder(context.Background(), ruleOrder("a", "1")); err != nil {
+		t.Fatalf("expected disabled rule to be skipped: %v", err)
+	}
+	if status, ok := manager.RuleSet().SetEnabled("deny", true); !ok || !status.Enabled {
+		t.Fatalf("expected rule to be enabled, got %+v (ok=%v)", status, ok)
+	}
+	err := manager.CheckOrder(context.Background(), ruleOrder("b", "1"))
+	if err == nil || err.Error() != "denied by risk rule deny" {
+		t.Fatalf("expected default denial reason, got %v", err)
+	}
+	if _, ok := manager.RuleSet().SetEnabled("missing", true); ok {
+		t.Fatal("expected unknown rule to be reported")
+	}
+}
+
+func TestRuleSet_PanickingRuleDenies(t *testing.T) {
+	manager := NewManager(Limits{OrderThrottle: 100, OrderBurst: 10})
+	boom := funcRule{name: "boom", fn: func(schema.OrderRequest, RuleState) Decision { panic("boom") }}
+	if err := manager.RegisterRule(boom); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	var breach *BreachError
+	if err := manager.CheckOrder(context.Background(), ruleOrder("a", "1")); !errors.As(err, &breach) || breach.Type != BreachTypeCustomRule {
+		t.Fatalf("expected panic to deny the order, got %v", err)
+	}
+}
+
+func TestManager_SimulateReportsRuleChecksWithoutRecording(t *testing.T) {
+	manager := NewManager(Limits{OrderThrottle: 100, OrderBurst: 10})
+	shared := NewRuleSet()
+	deny := funcRule{name: "deny", fn: func(schema.OrderRequest, RuleState) Decision { return Deny("no", nil) }}
+	if err := shared.Register(deny); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	manager.SetRuleSet(shared)
+
+	sim := manager.Simulate(*ruleOrder("sim", "1"))
+	check := findCheck(t, sim, CheckRulePrefix+"deny")
+	if sim.Allowed || check.Passed || check.BreachType != BreachTypeCustomRule {
+		t.Fatalf("expected simulated rule denial, got %+v", sim)
+	}
+	if decisions := shared.Decisions(); len(decisions) != 0 {
+		t.Fatalf("simulation must not record decisions, got %+v", decisions)
+	}
+}
@@ -0,0 +1,148 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// BreachTypeSelfTrade indicates that an order would cross a resting order of another strategy instance.
+const BreachTypeSelfTrade BreachType = "SELF_TRADE"
+
+// SelfTradePolicy selects how orders crossing another instance's resting order on the same account are handled.
+type SelfTradePolicy string
+
+const (
+	// SelfTradeAllow forwards crossing orders to the venue unchanged.
+	SelfTradeAllow SelfTradePolicy = "allow"
+	// SelfTradeReject rejects the incoming order and leaves the resting orders untouched.
+	SelfTradeReject SelfTradePolicy = "reject"
+	// SelfTradeCancelResting cancels the crossed resting orders before the incoming order is accepted.
+	SelfTradeCancelResting SelfTradePolicy = "cancel-resting"
+)
+
+// ParseSelfTradePolicy validates a self-trade prevention policy, defaulting to allow when empty.
+func ParseSelfTradePolicy(raw string) (SelfTradePolicy, error) {
+	switch SelfTradePolicy(strings.ToLower(strings.TrimSpace(raw))) {
+	case "", SelfTradeAllow:
+		return SelfTradeAllow, nil
+	case SelfTradeReject:
+		return SelfTradeReject, nil
+	case SelfTradeCancelResting:
+		return SelfTradeCancelResting, nil
+	default:
+		return "", fmt.Errorf("unsupported self-trade prevention policy %q", raw)
+	}
+}
+
+// RestingOrderCanceller cancels a single resting order identified by its client order ID.
+type RestingOrderCanceller func(ctx context.Context, provider, symbol, clientOrderID string) error
+
+// SetRestingOrderCanceller installs the hook used by the cancel-resting self-trade policy.
+func (m *Manager) SetRestingOrderCanceller(fn RestingOrderCanceller) {
+	m.mu.Lock()
+	m.restingCanceller = fn
+	m.mu.Unlock()
+}
+
+type selfTradeConflict struct {
+	clientOrderID string
+	consumer      string
+	price         decimal.Decimal
+}
+
+// preventSelfTrade applies the configured policy when req would cross a resting limit order placed
+// by a different strategy instance on the same provider and symbol. Venue cancellations run
+// outside the manager lock.
+func (m *Manager) preventSelfTrade(ctx context.Context, req *schema.OrderRequest) error {
+	m.mu.Lock()
+	policy := m.limits.SelfTradePrevention
+	if policy == "" || policy == SelfTradeAllow {
+		m.mu.Unlock()
+		return nil
+	}
+	if m.ensureTradingActiveLocked() != nil || m.enforceInstrumentStatusLocked(req) != nil {
+		// Orders that will be rejected anyway must not cancel other instances' orders.
+		m.mu.Unlock()
+		return nil
+	}
+	conflicts := m.selfTradeConflictsLocked(req)
+	canceller := m.restingCanceller
+	if len(conflicts) > 0 && policy == SelfTradeCancelResting && canceller != nil {
+		for _, conflict := range conflicts {
+			m.orders[conflict.clientOrderID].cancelPending = true
+		}
+	}
+	m.mu.Unlock()
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	details := map[string]string{
+		"provider":     req.Provider,
+		"symbol":       req.Symbol,
+		"restingOrder": conflicts[0].clientOrderID,
+		"restingOwner": conflicts[0].consumer,
+		"restingPrice": conflicts[0].price.String(),
+		"policy":       string(policy),
+	}
+	if policy == SelfTradeReject {
+		return newBreachError(BreachTypeSelfTrade, "order would cross resting order of another instance", nil, details)
+	}
+	if canceller == nil {
+		return newBreachError(BreachTypeSelfTrade, "resting order cancellation unavailable", nil, details)
+	}
+	for i, conflict := range conflicts {
+		if err := canceller(ctx, req.Provider, req.Symbol, conflict.clientOrderID); err != nil {
+			m.mu.Lock()
+			for _, pending := range conflicts[i:] {
+				if order, ok := m.orders[pending.clientOrderID]; ok {
+					order.cancelPending = false
+				}
+			}
+			m.mu.Unlock()
+			details["restingOrder"] = conflict.clientOrderID
+			return newBreachError(BreachTypeSelfTrade, "cancel crossed resting order", err, details)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) selfTradeConflictsLocked(req *schema.OrderRequest) []selfTradeConflict {
+	market := req.OrderType == schema.OrderTypeMarket || req.Price == nil
+	var price decimal.Decimal
+	if !market {
+		parsed, err := decimal.NewFromString(strings.TrimSpace(*req.Price))
+		if err != nil {
+			return nil
+		}
+		price = parsed
+	}
+	var conflicts []selfTradeConflict
+	for id, order := range m.orders {
+		if order.cancelPending || order.orderType != schema.OrderTypeLimit || order.side == req.Side {
+			continue
+		}
+		if order.consumer == req.ConsumerID || order.provider != req.Provider || order.symbol != req.Symbol {
+			continue
+		}
+		if !market {
+			if req.Side == schema.TradeSideBuy && price.LessThan(order.limitPx) {
+				continue
+			}
+			if req.Side == schema.TradeSideSell && price.GreaterThan(order.limitPx) {
+				continue
+			}
+		}
+		conflicts = append(conflicts, selfTradeConflict{clientOrderID: id, consumer: order.consumer, price: order.limitPx})
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].clientOrderID < conflicts[j].clientOrderID
+	})
+	return conflicts
+}
@@ -0,0 +1,113 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func newSelfTradeManager(policy SelfTradePolicy) *Manager {
+	return NewManager(Limits{
+		MaxPositionSize:     decimal.NewFromInt(1_000),
+		MaxNotionalValue:    decimal.NewFromInt(1_000_000),
+		OrderThrottle:       100,
+		OrderBurst:          100,
+		SelfTradePrevention: policy,
+	})
+}
+
+func selfTradeOrder(id, consumer string, side schema.TradeSide, price string) *schema.OrderRequest {
+	return &schema.OrderRequest{
+		ClientOrderID: id,
+		ConsumerID:    consumer,
+		Provider:      "binance-spot",
+		Symbol:        "BTC-USDT",
+		Side:          side,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      "1",
+	}
+}
+
+func TestManager_SelfTradeReject(t *testing.T) {
+	manager := newSelfTradeManager(SelfTradeReject)
+	ctx := context.Background()
+	if err := manager.CheckOrder(ctx, selfTradeOrder("sell-1", "alpha", schema.TradeSideSell, "100")); err != nil {
+		t.Fatalf("resting order: %v", err)
+	}
+
+	if err := manager.CheckOrder(ctx, selfTradeOrder("buy-same", "alpha", schema.TradeSideBuy, "101")); err != nil {
+		t.Fatalf("same instance orders are not subject to self-trade prevention: %v", err)
+	}
+	if err := manager.CheckOrder(ctx, selfTradeOrder("buy-passive", "beta", schema.TradeSideBuy, "99")); err != nil {
+		t.Fatalf("non-crossing order rejected: %v", err)
+	}
+	err := manager.CheckOrder(ctx, selfTradeOrder("buy-cross", "beta", schema.TradeSideBuy, "100"))
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach.Type != BreachTypeSelfTrade {
+		t.Fatalf("expected self-trade breach, got %v", err)
+	}
+	if breach.Details["restingOrder"] != "sell-1" || breach.Details["restingOwner"] != "alpha" {
+		t.Fatalf("unexpected breach details: %+v", breach.Details)
+	}
+}
+
+func TestManager_SelfTradeCancelResting(t *testing.T) {
+	manager := newSelfTradeManager(SelfTradeCancelResting)
+	var cancelled []string
+	manager.SetRestingOrderCanceller(func(_ context.Context, provider, symbol, clientOrderID string) error {
+		if provider != "binance-spot" || symbol != "BTC-USDT" {
+			t.Fatalf("unexpected cancel scope %s/%s", provider, symbol)
+		}
+		cancelled = append(cancelled, clientOrderID)
+		return nil
+	})
+	ctx := context.Background()
+	if err := manager.CheckOrder(ctx, selfTradeOrder("sell-1", "alpha", schema.TradeSideSell, "100")); err != nil {
+		t.Fatalf("resting order: %v", err)
+	}
+	if err := manager.CheckOrder(ctx, selfTradeOrder("buy-1", "beta", schema.TradeSideBuy, "100.5")); err != nil {
+		t.Fatalf("expected crossing order accepted after cancellation, got %v", err)
+	}
+	if len(cancelled) != 1 || cancelled[0] != "sell-1" {
+		t.Fatalf("expected resting order cancelled, got %v", cancelled)
+	}
+	if err := manager.CheckOrder(ctx, selfTradeOrder("buy-2", "beta", schema.TradeSideBuy, "100.5")); err != nil {
+		t.Fatalf("pending cancellation must not be cancelled twice: %v", err)
+	}
+	if len(cancelled) != 1 {
+		t.Fatalf("expected single cancellation, got %v", cancelled)
+	}
+}
+
+func TestManager_SelfTradeCancelRestingFailureRejects(t *testing.T) {
+	manager := newSelfTradeManager(SelfTradeCancelResting)
+	manager.SetRestingOrderCanceller(func(context.Context, string, string, string) error {
+		return errors.New("venue unavailable")
+	})
+	ctx := context.Background()
+	if err := manager.CheckOrder(ctx, selfTradeOrder("sell-1", "alpha", schema.TradeSideSell, "100")); err != nil {
+		t.Fatalf("resting order: %v", err)
+	}
+	err := manager.CheckOrder(ctx, selfTradeOrder("buy-1", "beta", schema.TradeSideBuy, "100"))
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach.Type != BreachTypeSelfTrade {
+		t.Fatalf("expected self-trade breach on cancel failure, got %v", err)
+	}
+}
+
+func TestParseSelfTradePolicy(t *testing.T) {
+	if policy, err := ParseSelfTradePolicy(" Cancel-Resting "); err != nil || policy != SelfTradeCancelResting {
+		t.Fatalf("unexpected parse result %q %v", policy, err)
+	}
+	if policy, err := ParseSelfTradePolicy(""); err != nil || policy != SelfTradeAllow {
+		t.Fatalf("expected allow default, got %q %v", policy, err)
+	}
+	if _, err := ParseSelfTradePolicy("ignore"); err == nil {
+		t.Fatal("expected error for unknown policy")
+	}
+}
@@ -0,0 +1,219 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// Check names reported by Simulate, in evaluation order.
+const (
+	CheckSymbolPolicy     = "symbol_policy"
+	CheckTradingActive    = "trading_active"
+	CheckInstrumentStatus = "instrument_status"
+	CheckThrottle         = "throttle"
+	CheckSelfTrade        = "self_trade"
+	CheckOrderType        = "order_type"
+	CheckTimeInForce      = "time_in_force"
+	CheckQuantity         = "quantity"
+	CheckPrice            = "price"
+	CheckPriceBand        = "price_band"
+	CheckPosition         = "position"
+	CheckNotional         = "notional"
+	CheckConcentration    = "concentration"
+	CheckBalance          = "balance"
+	CheckConcurrency      = "concurrency"
+	// CheckRulePrefix prefixes the checks of custom rules, which follow the built-in checks.
+	CheckRulePrefix = "rule:"
+)
+
+// CheckResult is the outcome of one risk check for a simulated order.
+type CheckResult struct {
+	Check      string            `json:"check"`
+	Passed     bool              `json:"passed"`
+	BreachType BreachType        `json:"breachType,omitempty"`
+	Reason     string            `json:"reason,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+}
+
+// Simulation reports whether an order would pass the risk checks and the exposure it would leave.
+// Checks that need a valid quantity or price are omitted when those are invalid.
+type Simulation struct {
+	Allowed     bool                  `json:"allowed"`
+	Checks      []CheckResult         `json:"checks"`
+	Utilization SimulationUtilization `json:"utilization"`
+}
+
+// SimulationUtilization describes the symbol exposure before and after the simulated order.
+// Utilization values are fractions of the limit and are omitted when the limit is disabled.
+type SimulationUtilization struct {
+	Symbol                 string   `json:"symbol"`
+	Position               string   `json:"position"`
+	ProjectedPosition      string   `json:"projectedPosition,omitempty"`
+	ProjectedNotional      string   `json:"projectedNotional,omitempty"`
+	InFlight               int      `json:"inFlight"`
+	ThrottleTokens         float64  `json:"throttleTokens"`
+	PositionUtilization    *float64 `json:"positionUtilization,omitempty"`
+	NotionalUtilization    *float64 `json:"notionalUtilization,omitempty"`
+	ConcurrencyUtilization *float64 `json:"concurrencyUtilization,omitempty"`
+}
+
+// Simulate evaluates req against every risk check without accepting it. Throttle tokens are
+// inspected rather than consumed, breaches are not counted, resting orders are never cancelled and
+// custom rule decisions are not recorded.
+func (m *Manager) Simulate(req schema.OrderRequest) Simulation {
+	now := time.Now()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sim := Simulation{
+		Allowed: true,
+		Checks:  make([]CheckResult, 0, 14),
+		Utilization: SimulationUtilization{
+			Symbol:                 req.Symbol,
+			Position:               m.positions[req.Symbol].String(),
+			ProjectedPosition:      "",
+			ProjectedNotional:      "",
+			InFlight:               m.inflight[req.Symbol],
+			ThrottleTokens:         0,
+			PositionUtilization:    nil,
+			NotionalUtilization:    nil,
+			ConcurrencyUtilization: nil,
+		},
+	}
+	record := func(check string, err error) bool {
+		sim.Checks = append(sim.Checks, checkResult(check, err))
+		if err != nil {
+			sim.Allowed = false
+		}
+		return err == nil
+	}
+
+	var haltErr error
+	if !m.cooldownElapsedLocked(now) {
+		haltErr = m.tradingHaltErrorLocked(now)
+	}
+	var symbolErr error
+	if reason := m.symbols.Evaluate(req.Provider, req.Symbol); reason != "" {
+		symbolErr = symbolPolicyBreach(&req, reason)
+	}
+	record(CheckSymbolPolicy, symbolErr)
+	record(CheckTradingActive, haltErr)
+	record(CheckInstrumentStatus, m.enforceInstrumentStatusLocked(&req))
+	tokens, throttleErr := m.throttleLocked(req, now)
+	sim.Utilization.ThrottleTokens = tokens
+	record(CheckThrottle, throttleErr)
+	record(CheckSelfTrade, m.selfTradeBreachLocked(&req))
+	record(CheckOrderType, m.enforceOrderTypeLocked(req.OrderType))
+	record(CheckTimeInForce, m.enforceTimeInForceLocked(&req))
+
+	quantity, err := decimal.NewFromString(req.Quantity)
+	if err == nil && !quantity.IsPositive() {
+		err = errors.New("quantity must be positive")
+	}
+	if err != nil {
+		err = newBreachError(BreachTypeOrderValidation, "invalid order quantity", err, map[string]string{"quantity": req.Quantity})
+	}
+	validQuantity := record(CheckQuantity, err)
+	price, err := m.orderPriceLocked(&req)
+	validPrice := record(CheckPrice, err)
+	projected := decimal.Zero
+	if validPrice {
+		record(CheckPriceBand, m.priceBandBreachLocked(req.Symbol, price))
+	}
+	if validQuantity {
+		projected = m.positions[req.Symbol].Add(signedQuantity(req.Side, quantity))
+		sim.Utilization.ProjectedPosition = projected.String()
+		sim.Utilization.PositionUtilization = utilization(projected.Abs(), m.limits.MaxPositionSize)
+		record(CheckPosition, m.enforcePositionLocked(projected))
+		if validPrice {
+			notional, err := m.enforceNotionalLocked(req.Symbol, projected, price)
+			if m.limits.MaxNotionalValue.IsPositive() && !notional.IsZero() {
+				sim.Utilization.ProjectedNotional = notional.String()
+				sim.Utilization.NotionalUtilization = utilization(notional, m.limits.MaxNotionalValue)
+			}
+			record(CheckNotional, err)
+			record(CheckConcentration, m.enforceConcentrationLocked(req.Provider, req.Symbol, signedQuantity(req.Side, quantity), price))
+			record(CheckBalance, m.enforceBalanceLocked(&req, quantity, price))
+		}
+	}
+	sim.Utilization.ConcurrencyUtilization = utilization(decimal.NewFromInt(int64(sim.Utilization.InFlight+1)), decimal.NewFromInt(int64(m.limits.MaxConcurrentOrders)))
+	record(CheckConcurrency, m.concurrencyBreachLocked(req.Symbol))
+	if validQuantity && validPrice {
+		state := m.ruleStateLocked(req.Symbol, projected, price)
+		for _, verdict := range m.rules.evaluate(context.Background(), req, state, false) {
+			record(CheckRulePrefix+verdict.name, verdict.err())
+		}
+	}
+	return sim
+}
+
+// throttleLocked reports the order tokens left in the tighter of the global and symbol limiters
+// and fails when either is empty.
+func (m *Manager) throttleLocked(req schema.OrderRequest, now time.Time) (float64, error) {
+	tokens := m.limiter.TokensAt(now)
+	scope := "global"
+	if lim, ok := m.symbolLimiter[req.Provider+"::"+req.Symbol]; ok {
+		if symbolTokens := lim.TokensAt(now); symbolTokens < tokens {
+			tokens = symbolTokens
+			scope = "symbol"
+		}
+	}
+	if tokens >= 1 {
+		return tokens, nil
+	}
+	return tokens, newBreachError(BreachTypeRateLimit, "order throttle limit exceeded", nil, map[string]string{
+		"scope":  scope,
+		"tokens": fmt.Sprintf("%.4f", tokens),
+	})
+}
+
+// selfTradeBreachLocked reports the conflict CheckOrder would raise. Under the cancel-resting
+// policy the order passes because the crossed orders would be cancelled first.
+func (m *Manager) selfTradeBreachLocked(req *schema.OrderRequest) error {
+	policy := m.limits.SelfTradePrevention
+	if policy == "" || policy == SelfTradeAllow {
+		return nil
+	}
+	conflicts := m.selfTradeConflictsLocked(req)
+	if len(conflicts) == 0 {
+		return nil
+	}
+	details := map[string]string{
+		"provider":     req.Provider,
+		"symbol":       req.Symbol,
+		"restingOrder": conflicts[0].clientOrderID,
+		"restingOwner": conflicts[0].consumer,
+		"restingPrice": conflicts[0].price.String(),
+		"policy":       string(policy),
+	}
+	switch {
+	case policy == SelfTradeReject:
+		return newBreachError(BreachTypeSelfTrade, "order would cross resting order of another instance", nil, details)
+	case m.restingCanceller == nil:
+		return newBreachError(BreachTypeSelfTrade, "resting order cancellation unavailable", nil, details)
+	default:
+		return nil
+	}
+}
+
+func checkResult(check string, err error) CheckResult {
+	result := CheckResult{Check: check, Passed: err == nil, BreachType: "", Reason: "", Details: nil}
+	if err == nil {
+		return result
+	}
+	result.Reason = err.Error()
+	var breach *BreachError
+	if errors.As(err, &breach) {
+		result.BreachType = breach.Type
+		result.Details = breach.Details
+	} else {
+		result.BreachType = BreachTypeKillSwitch
+	}
+	return result
+}
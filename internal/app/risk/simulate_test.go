@@ -0,0 +1,112 @@
+package risk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func findCheck(t *testing.T, sim Simulation, name string) CheckResult {
+	t.Helper()
+	for _, check := range sim.Checks {
+		if check.Check == name {
+			return check
+		}
+	}
+	t.Fatalf("check %s missing from %+v", name, sim.Checks)
+	return CheckResult{}
+}
+
+func TestManager_SimulateReportsBreachesWithoutSideEffects(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:     decimal.NewFromInt(10),
+		MaxNotionalValue:    decimal.NewFromInt(1_000),
+		OrderThrottle:       1,
+		OrderBurst:          1,
+		MaxConcurrentOrders: 4,
+		KillSwitchEnabled:   true,
+		MaxRiskBreaches:     1,
+	})
+
+	price := "100"
+	req := schema.OrderRequest{
+		Provider:  "binance-spot",
+		Symbol:    "BTC-USDT",
+		Side:      schema.TradeSideBuy,
+		OrderType: schema.OrderTypeLimit,
+		Price:     &price,
+		Quantity:  "12",
+	}
+	for i := 0; i < 3; i++ {
+		sim := manager.Simulate(req)
+		if sim.Allowed {
+			t.Fatalf("expected position breach, got %+v", sim)
+		}
+		position := findCheck(t, sim, CheckPosition)
+		if position.Passed || position.BreachType != BreachTypePositionLimit {
+			t.Fatalf("position check = %+v", position)
+		}
+		if notional := findCheck(t, sim, CheckNotional); notional.Passed {
+			t.Fatalf("expected notional breach, got %+v", notional)
+		}
+		if throttle := findCheck(t, sim, CheckThrottle); !throttle.Passed {
+			t.Fatalf("simulation must not consume throttle tokens: %+v", throttle)
+		}
+		if sim.Utilization.ProjectedPosition != "12" || sim.Utilization.PositionUtilization == nil || *sim.Utilization.PositionUtilization != 1.2 {
+			t.Fatalf("unexpected utilization %+v", sim.Utilization)
+		}
+	}
+	if got := manager.Snapshot().BreachCount; got != 0 {
+		t.Fatalf("simulation counted %d breaches", got)
+	}
+
+	req.Quantity = "2"
+	sim := manager.Simulate(req)
+	if !sim.Allowed {
+		t.Fatalf("expected order to pass, got %+v", sim.Checks)
+	}
+	if sim.Utilization.ProjectedNotional != "200" {
+		t.Fatalf("projected notional = %s, want 200", sim.Utilization.ProjectedNotional)
+	}
+	if err := manager.CheckOrder(context.Background(), &req); err != nil {
+		t.Fatalf("CheckOrder after simulations: %v", err)
+	}
+	if throttle := findCheck(t, manager.Simulate(req), CheckThrottle); throttle.Passed || throttle.BreachType != BreachTypeRateLimit {
+		t.Fatalf("expected exhausted throttle, got %+v", throttle)
+	}
+}
+
+func TestManager_SimulateWhileHalted(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(10),
+		MaxNotionalValue: decimal.NewFromInt(1_000),
+		OrderThrottle:    10,
+		OrderBurst:       10,
+	})
+	manager.Halt("maintenance")
+
+	sim := manager.Simulate(schema.OrderRequest{
+		Provider:  "binance-spot",
+		Symbol:    "ETH-USDT",
+		Side:      schema.TradeSideSell,
+		OrderType: schema.OrderTypeMarket,
+		Quantity:  "abc",
+	})
+	if sim.Allowed {
+		t.Fatal("expected halted manager to reject the order")
+	}
+	if active := findCheck(t, sim, CheckTradingActive); active.Passed {
+		t.Fatalf("trading_active check = %+v", active)
+	}
+	if quantity := findCheck(t, sim, CheckQuantity); quantity.Passed || quantity.BreachType != BreachTypeOrderValidation {
+		t.Fatalf("quantity check = %+v", quantity)
+	}
+	for _, check := range sim.Checks {
+		if check.Check == CheckPosition {
+			t.Fatalf("position check should be skipped for an invalid quantity")
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package risk
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// ManualHaltPrefix prefixes kill switch reasons set by an operator through the control API.
+const ManualHaltPrefix = "operator halt"
+
+const orderRateWindow = time.Minute
+
+type acceptedOrder struct {
+	consumer string
+	at       time.Time
+}
+
+// Snapshot summarises the live risk state for monitoring dashboards.
+type Snapshot struct {
+	TradingHalted       bool   `json:"tradingHalted"`
+	HaltReason          string `json:"haltReason,omitempty"`
+	CircuitBreakerOpen  bool   `json:"circuitBreakerOpen"`
+	BreachCount         int    `json:"breachCount"`
+	MaxRiskBreaches     int    `json:"maxRiskBreaches"`
+	OpenOrders          int    `json:"openOrders"`
+	MaxConcurrentOrders int    `json:"maxConcurrentOrders"`
+	MaxPositionSize     string `json:"maxPositionSize"`
+	MaxNotionalValue    string `json:"maxNotionalValue"`
+	// OrdersPerMinute counts orders accepted during the last minute by strategy instance.
+	OrdersPerMinute map[string]int     `json:"ordersPerMinute"`
+	Positions       []PositionSnapshot `json:"positions"`
+}
+
+// PositionSnapshot reports exposure on one symbol and how much of each limit it consumes.
+// Utilization values are fractions of the limit and are omitted when the limit is disabled.
+type PositionSnapshot struct {
+	Symbol                 string   `json:"symbol"`
+	Position               string   `json:"position"`
+	Notional               string   `json:"notional"`
+	InFlight               int      `json:"inFlight"`
+	PositionUtilization    *float64 `json:"positionUtilization,omitempty"`
+	NotionalUtilization    *float64 `json:"notionalUtilization,omitempty"`
+	ConcurrencyUtilization *float64 `json:"concurrencyUtilization,omitempty"`
+}
+
+// Snapshot returns the current kill switch state, order rates, positions and limit utilization.
+func (m *Manager) Snapshot() Snapshot {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pruneAcceptedLocked(now)
+
+	snapshot := Snapshot{
+		TradingHalted:       m.killSwitch,
+		HaltReason:          m.killReason,
+		CircuitBreakerOpen:  m.killSwitch && m.limits.CircuitBreaker.Enabled && now.Before(m.cooldownUntil),
+		BreachCount:         m.failureCount,
+		MaxRiskBreaches:     m.limits.MaxRiskBreaches,
+		OpenOrders:          len(m.orders),
+		MaxConcurrentOrders: m.limits.MaxConcurrentOrders,
+		MaxPositionSize:     m.limits.MaxPositionSize.String(),
+		MaxNotionalValue:    m.limits.MaxNotionalValue.String(),
+		OrdersPerMinute:     make(map[string]int),
+		Positions:           nil,
+	}
+	for _, order := range m.accepted {
+		snapshot.OrdersPerMinute[order.consumer]++
+	}
+
+	symbols := make(map[string]struct{}, len(m.positions)+len(m.inflight))
+	for symbol := range m.positions {
+		symbols[symbol] = struct{}{}
+	}
+	for symbol, count := range m.inflight {
+		if count > 0 {
+			symbols[symbol] = struct{}{}
+		}
+	}
+	for symbol := range symbols {
+		position := m.positions[symbol]
+		notional := m.notionals[symbol]
+		inflight := m.inflight[symbol]
+		snapshot.Positions = append(snapshot.Positions, PositionSnapshot{
+			Symbol:                 symbol,
+			Position:               position.String(),
+			Notional:               notional.String(),
+			InFlight:               inflight,
+			PositionUtilization:    utilization(position.Abs(), m.limits.MaxPositionSize),
+			NotionalUtilization:    utilization(notional, m.limits.MaxNotionalValue),
+			ConcurrencyUtilization: utilization(decimal.NewFromInt(int64(inflight)), decimal.NewFromInt(int64(m.limits.MaxConcurrentOrders))),
+		})
+	}
+	sort.Slice(snapshot.Positions, func(i, j int) bool {
+		return snapshot.Positions[i].Symbol < snapshot.Positions[j].Symbol
+	})
+	return snapshot
+}
+
+func (m *Manager) recordAcceptedLocked(consumer string, at time.Time) {
+	m.pruneAcceptedLocked(at)
+	m.accepted = append(m.accepted, acceptedOrder{consumer: consumer, at: at})
+}
+
+func (m *Manager) pruneAcceptedLocked(now time.Time) {
+	cutoff := now.Add(-orderRateWindow)
+	drop := 0
+	for drop < len(m.accepted) && !m.accepted[drop].at.After(cutoff) {
+		drop++
+	}
+	if drop > 0 {
+		m.accepted = append(m.accepted[:0], m.accepted[drop:]...)
+	}
+}
+
+func utilization(value, limit decimal.Decimal) *float64 {
+	if !limit.IsPositive() {
+		return nil
+	}
+	ratio, _ := value.Div(limit).Float64()
+	return &ratio
+}
+
+// LimitExcess reports live exposure that a set of proposed limits would not allow.
+type LimitExcess struct {
+	Symbol string `json:"symbol"`
+	// Limit names the limit in the risk config: maxPositionSize, maxNotionalValue,
+	// maxConcurrentOrders or allowedOrderTypes.
+	Limit    string `json:"limit"`
+	Current  string `json:"current"`
+	Proposed string `json:"proposed"`
+	// Consumers lists the strategy instances with open orders behind the excess.
+	Consumers []string `json:"consumers,omitempty"`
+}
+
+// Excesses compares the live positions and open orders against proposed limits without
+// applying them, for previews of a limits change.
+func (m *Manager) Excesses(limits Limits) []LimitExcess {
+	allowedTypes := make(map[string]struct{}, len(limits.AllowedOrderTypes))
+	for _, ot := range normalizeAllowedOrderTypes(limits.AllowedOrderTypes) {
+		allowedTypes[strings.ToLower(string(ot))] = struct{}{}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	consumers := make(map[string]map[string]struct{})
+	var excesses []LimitExcess
+	for _, order := range m.orders {
+		if _, ok := consumers[order.symbol]; !ok {
+			consumers[order.symbol] = make(map[string]struct{})
+		}
+		consumers[order.symbol][order.consumer] = struct{}{}
+		if len(allowedTypes) == 0 {
+			continue
+		}
+		if _, ok := allowedTypes[strings.ToLower(string(order.orderType))]; !ok {
+			excesses = append(excesses, LimitExcess{
+				Symbol:    order.symbol,
+				Limit:     "allowedOrderTypes",
+				Current:   string(order.orderType),
+				Proposed:  joinOrderTypes(limits.AllowedOrderTypes),
+				Consumers: []string{order.consumer},
+			})
+		}
+	}
+	symbolConsumers := func(symbol string) []string {
+		out := make([]string, 0, len(consumers[symbol]))
+		for consumer := range consumers[symbol] {
+			out = append(out, consumer)
+		}
+		sort.Strings(out)
+		return out
+	}
+	for symbol, position := range m.positions {
+		if limits.MaxPositionSize.IsPositive() && position.Abs().GreaterThan(limits.MaxPositionSize) {
+			excesses = append(excesses, LimitExcess{
+				Symbol:    symbol,
+				Limit:     "maxPositionSize",
+				Current:   position.Abs().String(),
+				Proposed:  limits.MaxPositionSize.String(),
+				Consumers: symbolConsumers(symbol),
+			})
+		}
+	}
+	for symbol, notional := range m.notionals {
+		if limits.MaxNotionalValue.IsPositive() && notional.GreaterThan(limits.MaxNotionalValue) {
+			excesses = append(excesses, LimitExcess{
+				Symbol:    symbol,
+				Limit:     "maxNotionalValue",
+				Current:   notional.String(),
+				Proposed:  limits.MaxNotionalValue.String(),
+				Consumers: symbolConsumers(symbol),
+			})
+		}
+	}
+	for symbol, inflight := range m.inflight {
+		if limits.MaxConcurrentOrders > 0 && inflight > limits.MaxConcurrentOrders {
+			excesses = append(excesses, LimitExcess{
+				Symbol:    symbol,
+				Limit:     "maxConcurrentOrders",
+				Current:   strconv.Itoa(inflight),
+				Proposed:  strconv.Itoa(limits.MaxConcurrentOrders),
+				Consumers: symbolConsumers(symbol),
+			})
+		}
+	}
+	sort.Slice(excesses, func(i, j int) bool {
+		if excesses[i].Symbol != excesses[j].Symbol {
+			return excesses[i].Symbol < excesses[j].Symbol
+		}
+		if excesses[i].Limit != excesses[j].Limit {
+			return excesses[i].Limit < excesses[j].Limit
+		}
+		return strings.Join(excesses[i].Consumers, ",") < strings.Join(excesses[j].Consumers, ",")
+	})
+	return excesses
+}
+
+func joinOrderTypes(types []schema.OrderType) string {
+	names := make([]string, 0, len(types))
+	for _, ot := range types {
+		names = append(names, string(ot))
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,144 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestManager_SnapshotReportsRatesAndUtilization(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:     decimal.NewFromInt(10),
+		MaxNotionalValue:    decimal.NewFromInt(1_000),
+		OrderThrottle:       100,
+		OrderBurst:          100,
+		MaxConcurrentOrders: 4,
+	})
+	price := "50"
+	for i, consumer := range []string{"grid", "grid", "mm"} {
+		req := &schema.OrderRequest{
+			ConsumerID:    consumer,
+			Provider:      "binance-spot",
+			Symbol:        "BTC-USDT",
+			Side:          schema.TradeSideBuy,
+			OrderType:     schema.OrderTypeLimit,
+			Price:         &price,
+			Quantity:      "1",
+			ClientOrderID: "ord-" + string(rune('a'+i)),
+		}
+		if err := manager.CheckOrder(context.Background(), req); err != nil {
+			t.Fatalf("check order %d: %v", i, err)
+		}
+	}
+	manager.HandleExecution("BTC-USDT", schema.ExecReportPayload{
+		ClientOrderID:  "ord-a",
+		Side:           schema.TradeSideBuy,
+		State:          schema.ExecReportStateFILLED,
+		FilledQuantity: "2",
+		AvgFillPrice:   "50",
+	})
+
+	snapshot := manager.Snapshot()
+	if snapshot.OrdersPerMinute["grid"] != 2 || snapshot.OrdersPerMinute["mm"] != 1 {
+		t.Fatalf("unexpected order rates: %+v", snapshot.OrdersPerMinute)
+	}
+	if snapshot.OpenOrders != 2 {
+		t.Fatalf("expected 2 open orders, got %d", snapshot.OpenOrders)
+	}
+	if len(snapshot.Positions) != 1 {
+		t.Fatalf("expected one position, got %+v", snapshot.Positions)
+	}
+	pos := snapshot.Positions[0]
+	if pos.Position != "2" || pos.Notional != "100" || pos.InFlight != 2 {
+		t.Fatalf("unexpected position snapshot: %+v", pos)
+	}
+	if pos.PositionUtilization == nil || *pos.PositionUtilization != 0.2 {
+		t.Fatalf("expected 20%% position utilization, got %v", pos.PositionUtilization)
+	}
+	if pos.NotionalUtilization == nil || *pos.NotionalUtilization != 0.1 {
+		t.Fatalf("expected 10%% notional utilization, got %v", pos.NotionalUtilization)
+	}
+	if pos.ConcurrencyUtilization == nil || *pos.ConcurrencyUtilization != 0.5 {
+		t.Fatalf("expected 50%% concurrency utilization, got %v", pos.ConcurrencyUtilization)
+	}
+}
+
+func TestManager_SnapshotPrunesOrderRateWindow(t *testing.T) {
+	manager := NewManager(Limits{OrderThrottle: 100, OrderBurst: 100})
+	manager.mu.Lock()
+	manager.recordAcceptedLocked("old", time.Now().Add(-2*orderRateWindow))
+	manager.accepted = append(manager.accepted, acceptedOrder{consumer: "fresh", at: time.Now()})
+	manager.mu.Unlock()
+
+	snapshot := manager.Snapshot()
+	if _, ok := snapshot.OrdersPerMinute["old"]; ok {
+		t.Fatalf("expected stale orders pruned, got %+v", snapshot.OrdersPerMinute)
+	}
+	if snapshot.OrdersPerMinute["fresh"] != 1 {
+		t.Fatalf("expected fresh order counted, got %+v", snapshot.OrdersPerMinute)
+	}
+	if len(snapshot.Positions) != 0 {
+		t.Fatalf("expected no positions, got %+v", snapshot.Positions)
+	}
+}
+
+func TestManager_ExcessesAgainstProposedLimits(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:     decimal.NewFromInt(10),
+		MaxNotionalValue:    decimal.NewFromInt(1_000),
+		OrderThrottle:       100,
+		OrderBurst:          100,
+		MaxConcurrentOrders: 4,
+	})
+	price := "50"
+	for i, consumer := range []string{"grid", "mm", "mm"} {
+		req := &schema.OrderRequest{
+			ConsumerID:    consumer,
+			Provider:      "binance-spot",
+			Symbol:        "BTC-USDT",
+			Side:          schema.TradeSideBuy,
+			OrderType:     schema.OrderTypeLimit,
+			Price:         &price,
+			Quantity:      "3",
+			ClientOrderID: "ord-" + string(rune('a'+i)),
+		}
+		if err := manager.CheckOrder(context.Background(), req); err != nil {
+			t.Fatalf("check order %d: %v", i, err)
+		}
+	}
+	manager.HandleExecution("BTC-USDT", schema.ExecReportPayload{
+		ClientOrderID:  "ord-a",
+		Side:           schema.TradeSideBuy,
+		State:          schema.ExecReportStateFILLED,
+		FilledQuantity: "3",
+		AvgFillPrice:   "50",
+	})
+
+	if excesses := manager.Excesses(manager.Limits()); len(excesses) != 0 {
+		t.Fatalf("expected the current limits to hold, got %+v", excesses)
+	}
+	proposed := manager.Limits()
+	proposed.MaxPositionSize = decimal.NewFromInt(2)
+	proposed.MaxConcurrentOrders = 1
+	proposed.AllowedOrderTypes = []schema.OrderType{schema.OrderTypeMarket}
+	excesses := manager.Excesses(proposed)
+	if len(excesses) != 4 {
+		t.Fatalf("expected four excesses, got %+v", excesses)
+	}
+	if got := excesses[2]; got.Limit != "maxConcurrentOrders" || got.Current != "2" || got.Proposed != "1" || len(got.Consumers) != 1 || got.Consumers[0] != "mm" {
+		t.Fatalf("unexpected concurrency excess %+v", got)
+	}
+	if got := excesses[3]; got.Limit != "maxPositionSize" || got.Current != "3" || got.Proposed != "2" {
+		t.Fatalf("unexpected position excess %+v", got)
+	}
+	if excesses[0].Limit != "allowedOrderTypes" || excesses[0].Current != string(schema.OrderTypeLimit) {
+		t.Fatalf("unexpected order type excess %+v", excesses[0])
+	}
+	if manager.Limits().MaxPositionSize.String() != "10" {
+		t.Fatal("expected the preview to leave the limits unchanged")
+	}
+}
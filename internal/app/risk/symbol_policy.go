@@ -0,0 +1,241 @@
+package risk
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// BreachTypeSymbolPolicy indicates the order symbol is barred by the configured allow and deny lists.
+const BreachTypeSymbolPolicy BreachType = "SYMBOL_POLICY"
+
+// SymbolLists is an allow list and a deny list of symbol patterns. Patterns match symbols
+// case-insensitively and may use * as a wildcard, so XMR-* covers every XMR pair.
+type SymbolLists struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// SymbolViolation counts the orders rejected for one provider symbol.
+type SymbolViolation struct {
+	Provider string    `json:"provider"`
+	Symbol   string    `json:"symbol"`
+	Reason   string    `json:"reason"`
+	Count    int64     `json:"count"`
+	LastAt   time.Time `json:"lastAt"`
+}
+
+// SymbolPolicyStatus reports the configured lists and the orders they rejected.
+type SymbolPolicyStatus struct {
+	Global     SymbolLists            `json:"global"`
+	Providers  map[string]SymbolLists `json:"providers,omitempty"`
+	Rejected   int64                  `json:"rejected"`
+	Violations []SymbolViolation      `json:"violations"`
+}
+
+// SymbolPolicy bars symbols from trading regardless of strategy limits, so compliance can guarantee
+// that certain assets never reach a venue. A deny match on the global or provider list rejects the
+// order; a non-empty allow list rejects every symbol it does not match. A policy is shared by the
+// managers of every strategy instance.
+type SymbolPolicy struct {
+	global    SymbolLists
+	providers map[string]SymbolLists
+
+	mu         sync.Mutex
+	rejected   int64
+	violations map[string]*SymbolViolation
+	notify     func(SymbolViolation)
+}
+
+// NewSymbolPolicy creates a policy from global lists and lists keyed by provider name.
+func NewSymbolPolicy(global SymbolLists, providers map[string]SymbolLists) *SymbolPolicy {
+	normalized := make(map[string]SymbolLists, len(providers))
+	for name, lists := range providers {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if key == "" {
+			continue
+		}
+		normalized[key] = normalizeSymbolLists(lists)
+	}
+	return &SymbolPolicy{
+		global:     normalizeSymbolLists(global),
+		providers:  normalized,
+		mu:         sync.Mutex{},
+		rejected:   0,
+		violations: make(map[string]*SymbolViolation),
+		notify:     nil,
+	}
+}
+
+// OnViolation registers fn to be called with the running tally of each rejected order.
+func (p *SymbolPolicy) OnViolation(fn func(SymbolViolation)) {
+	p.mu.Lock()
+	p.notify = fn
+	p.mu.Unlock()
+}
+
+// Empty reports whether the policy bars nothing.
+func (p *SymbolPolicy) Empty() bool {
+	if p == nil {
+		return true
+	}
+	if len(p.global.Allow) > 0 || len(p.global.Deny) > 0 {
+		return false
+	}
+	for _, lists := range p.providers {
+		if len(lists.Allow) > 0 || len(lists.Deny) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Evaluate reports why symbol may not be traded on provider, or an empty reason when it may.
+func (p *SymbolPolicy) Evaluate(provider, symbol string) string {
+	if p == nil {
+		return ""
+	}
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if pattern, ok := matchSymbol(p.global.Deny, symbol); ok {
+		return "symbol denied by " + pattern
+	}
+	lists, scoped := p.providers[strings.ToLower(strings.TrimSpace(provider))]
+	if pattern, ok := matchSymbol(lists.Deny, symbol); scoped && ok {
+		return "symbol denied for provider by " + pattern
+	}
+	if _, ok := matchSymbol(p.global.Allow, symbol); len(p.global.Allow) > 0 && !ok {
+		return "symbol not in allow list"
+	}
+	if _, ok := matchSymbol(lists.Allow, symbol); scoped && len(lists.Allow) > 0 && !ok {
+		return "symbol not in provider allow list"
+	}
+	return ""
+}
+
+// Status returns the configured lists and the rejected orders, most frequent first.
+func (p *SymbolPolicy) Status() SymbolPolicyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	violations := make([]SymbolViolation, 0, len(p.violations))
+	for _, violation := range p.violations {
+		violations = append(violations, *violation)
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Count != violations[j].Count {
+			return violations[i].Count > violations[j].Count
+		}
+		if violations[i].Provider != violations[j].Provider {
+			return violations[i].Provider < violations[j].Provider
+		}
+		return violations[i].Symbol < violations[j].Symbol
+	})
+	var providers map[string]SymbolLists
+	if len(p.providers) > 0 {
+		providers = make(map[string]SymbolLists, len(p.providers))
+		for name, lists := range p.providers {
+			providers[name] = lists
+		}
+	}
+	return SymbolPolicyStatus{
+		Global:     p.global,
+		Providers:  providers,
+		Rejected:   p.rejected,
+		Violations: violations,
+	}
+}
+
+// check rejects req when the policy bars its symbol and counts the rejection.
+func (p *SymbolPolicy) check(req *schema.OrderRequest) error {
+	reason := p.Evaluate(req.Provider, req.Symbol)
+	if reason == "" {
+		return nil
+	}
+	p.mu.Lock()
+	key := req.Provider + "::" + req.Symbol
+	violation, ok := p.violations[key]
+	if !ok {
+		violation = &SymbolViolation{Provider: req.Provider, Symbol: req.Symbol, Reason: "", Count: 0, LastAt: time.Time{}}
+		p.violations[key] = violation
+	}
+	violation.Reason = reason
+	violation.Count++
+	violation.LastAt = time.Now().UTC()
+	p.rejected++
+	snapshot, notify := *violation, p.notify
+	p.mu.Unlock()
+	if notify != nil {
+		notify(snapshot)
+	}
+	return symbolPolicyBreach(req, reason)
+}
+
+func symbolPolicyBreach(req *schema.OrderRequest, reason string) error {
+	return newBreachError(BreachTypeSymbolPolicy, reason, nil, map[string]string{
+		"provider": req.Provider,
+		"symbol":   req.Symbol,
+	})
+}
+
+// SetSymbolPolicy replaces the symbol policy of the manager, typically with one shared with the
+// managers of other strategy instances. A nil policy bars nothing.
+func (m *Manager) SetSymbolPolicy(policy *SymbolPolicy) {
+	m.mu.Lock()
+	m.symbols = policy
+	m.mu.Unlock()
+}
+
+// SymbolPolicy returns the symbol policy of the manager; nil when none is set.
+func (m *Manager) SymbolPolicy() *SymbolPolicy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.symbols
+}
+
+// enforceSymbolPolicy runs before any other check, so barred orders neither consume throttle
+// tokens nor cancel resting orders. Rejections reflect compliance configuration rather than
+// strategy behaviour, so they are not counted as breaches.
+func (m *Manager) enforceSymbolPolicy(req *schema.OrderRequest) error {
+	policy := m.SymbolPolicy()
+	if policy == nil {
+		return nil
+	}
+	return policy.check(req)
+}
+
+func normalizeSymbolLists(lists SymbolLists) SymbolLists {
+	return SymbolLists{Allow: normalizeSymbolPatterns(lists.Allow), Deny: normalizeSymbolPatterns(lists.Deny)}
+}
+
+func normalizeSymbolPatterns(patterns []string) []string {
+	if len(patterns) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(patterns))
+	seen := make(map[string]struct{}, len(patterns))
+	for _, raw := range patterns {
+		pattern := strings.ToUpper(strings.TrimSpace(raw))
+		if pattern == "" {
+			continue
+		}
+		if _, ok := seen[pattern]; ok {
+			continue
+		}
+		seen[pattern] = struct{}{}
+		out = append(out, pattern)
+	}
+	return out
+}
+
+// matchSymbol returns the first pattern matching symbol. Malformed patterns match nothing.
+func matchSymbol(patterns []string, symbol string) (string, bool) {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, symbol); err == nil && ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,92 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestSymbolPolicyEvaluate(t *testing.T) {
+	policy := NewSymbolPolicy(
+		SymbolLists{Deny: []string{"xmr-*", " "}},
+		map[string]SymbolLists{
+			"Binance": {Allow: []string{"BTC-*", "ETH-USDT"}, Deny: []string{"BTC-TRY"}},
+			"okx":     {},
+		},
+	)
+	cases := []struct {
+		provider, symbol string
+		barred           bool
+	}{
+		{"okx", "XMR-USDT", true},
+		{"okx", "DOGE-USDT", false},
+		{"binance", "btc-usdt", false},
+		{"binance", "BTC-TRY", true},
+		{"binance", "SOL-USDT", true},
+		{"kraken", "SOL-USDT", false},
+	}
+	for _, tc := range cases {
+		if reason := policy.Evaluate(tc.provider, tc.symbol); (reason != "") != tc.barred {
+			t.Fatalf("%s on %s: expected barred=%v, got %q", tc.symbol, tc.provider, tc.barred, reason)
+		}
+	}
+	if policy.Empty() || !NewSymbolPolicy(SymbolLists{}, map[string]SymbolLists{"okx": {}}).Empty() {
+		t.Fatal("unexpected Empty result")
+	}
+}
+
+func TestManager_CheckOrder_RejectsBarredSymbol(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(1_000_000),
+		OrderThrottle:    1,
+		OrderBurst:       1,
+	})
+	policy := NewSymbolPolicy(SymbolLists{Deny: []string{"XMR-*"}}, nil)
+	var notified []SymbolViolation
+	policy.OnViolation(func(v SymbolViolation) { notified = append(notified, v) })
+	manager.SetSymbolPolicy(policy)
+
+	price := "1"
+	req := &schema.OrderRequest{
+		Provider:      "binance",
+		Symbol:        "XMR-USDT",
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      "1",
+		ClientOrderID: "ord-1",
+	}
+	for range 2 {
+		err := manager.CheckOrderNoWait(context.Background(), req)
+		var breach *BreachError
+		if !errors.As(err, &breach) || breach.Type != BreachTypeSymbolPolicy {
+			t.Fatalf("expected symbol policy breach, got %v", err)
+		}
+	}
+	if engaged, _ := manager.KillSwitchStatus(); engaged {
+		t.Fatal("symbol policy rejections must not count toward the kill switch")
+	}
+	status := policy.Status()
+	if status.Rejected != 2 || len(status.Violations) != 1 || status.Violations[0].Count != 2 || len(notified) != 2 {
+		t.Fatalf("unexpected status %+v (notified %d)", status, len(notified))
+	}
+
+	if sim := manager.Simulate(*req); sim.Allowed || sim.Checks[0].Check != CheckSymbolPolicy || sim.Checks[0].Passed {
+		t.Fatalf("expected simulation to fail the symbol policy first, got %+v", sim.Checks)
+	}
+	if policy.Status().Rejected != 2 {
+		t.Fatal("simulations must not count as rejections")
+	}
+
+	// Barred orders took no throttle token, so the single burst token is still available.
+	req.Symbol = "BTC-USDT"
+	req.ClientOrderID = "ord-2"
+	if err := manager.CheckOrderNoWait(context.Background(), req); err != nil {
+		t.Fatalf("expected allowed symbol accepted, got %v", err)
+	}
+}
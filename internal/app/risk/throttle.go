@@ -0,0 +1,78 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// CheckOrderNoWait evaluates req like CheckOrder but fails fast with a RATE_LIMIT breach instead
+// of blocking until throttle tokens are available. The breach carries RetryAfter so callers can
+// hold the order and retry once the throttle has refilled.
+func (m *Manager) CheckOrderNoWait(ctx context.Context, req *schema.OrderRequest) error {
+	if req == nil {
+		return fmt.Errorf("nil order request")
+	}
+	if err := m.enforceSymbolPolicy(req); err != nil {
+		return err
+	}
+	if err := m.reserveThrottle(req, time.Now()); err != nil {
+		return err
+	}
+	return m.admitOrder(ctx, req)
+}
+
+// reserveThrottle takes a token from the global and symbol limiters, or takes none and reports
+// how long the caller has to wait for the tighter of the two.
+func (m *Manager) reserveThrottle(req *schema.OrderRequest, now time.Time) error {
+	global := m.limiter.ReserveN(now, 1)
+	if delay := global.DelayFrom(now); delay > 0 {
+		global.CancelAt(now)
+		return throttleBreach("order throttle limit exceeded", delay, map[string]string{
+			"scope": "global",
+		})
+	}
+	symLimiter, err := m.symbolLimiterFor(req.Provider, req.Symbol)
+	if err != nil {
+		global.CancelAt(now)
+		return err
+	}
+	symbol := symLimiter.ReserveN(now, 1)
+	if delay := symbol.DelayFrom(now); delay > 0 {
+		symbol.CancelAt(now)
+		global.CancelAt(now)
+		return throttleBreach("symbol throttle limit exceeded", delay, map[string]string{
+			"scope":  "symbol",
+			"symbol": req.Symbol,
+		})
+	}
+	return nil
+}
+
+func throttleBreach(reason string, retryAfter time.Duration, details map[string]string) *BreachError {
+	if retryAfter == rate.InfDuration {
+		retryAfter = 0
+	}
+	if retryAfter > 0 {
+		details["retryAfter"] = retryAfter.String()
+	}
+	breach := newBreachError(BreachTypeRateLimit, reason, nil, details)
+	breach.RetryAfter = retryAfter
+	return breach
+}
+
+// ThrottleRetryAfter reports whether err is a throttle breach raised by CheckOrderNoWait and, if
+// so, how long until a token is available. A zero duration means the throttle can never admit
+// the order, e.g. because it is configured with a zero rate.
+func ThrottleRetryAfter(err error) (time.Duration, bool) {
+	var breach *BreachError
+	if !errors.As(err, &breach) || breach == nil || breach.Type != BreachTypeRateLimit || breach.Err != nil {
+		return 0, false
+	}
+	return breach.RetryAfter, true
+}
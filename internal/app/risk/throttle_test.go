@@ -0,0 +1,61 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestManager_CheckOrderNoWaitReportsRetryAfter(t *testing.T) {
+	manager := NewManager(Limits{
+		MaxPositionSize:  decimal.NewFromInt(1_000),
+		MaxNotionalValue: decimal.NewFromInt(1_000_000),
+		OrderThrottle:    1,
+		OrderBurst:       1,
+	})
+	price := "1"
+	req := &schema.OrderRequest{
+		Provider:      "binance-spot",
+		Symbol:        "BTC-USDT",
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      "1",
+		ClientOrderID: "ord-1",
+	}
+	if err := manager.CheckOrderNoWait(context.Background(), req); err != nil {
+		t.Fatalf("first order should pass: %v", err)
+	}
+
+	req.ClientOrderID = "ord-2"
+	err := manager.CheckOrderNoWait(context.Background(), req)
+	retryAfter, ok := ThrottleRetryAfter(err)
+	if !ok {
+		t.Fatalf("expected throttle breach, got %v", err)
+	}
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Fatalf("unexpected retry after %s", retryAfter)
+	}
+	if _, tracked := manager.orders["ord-2"]; tracked {
+		t.Fatal("throttled order must not be tracked")
+	}
+
+	// A rejected probe must not consume the token it was waiting for.
+	if delay := manager.limiter.Reserve().Delay(); delay > retryAfter {
+		t.Fatalf("throttle probe consumed a token: next delay %s > %s", delay, retryAfter)
+	}
+}
+
+func TestThrottleRetryAfterIgnoresOtherErrors(t *testing.T) {
+	if _, ok := ThrottleRetryAfter(ErrKillSwitchEngaged); ok {
+		t.Fatal("kill switch is not a throttle breach")
+	}
+	breach := newBreachError(BreachTypePositionLimit, "too big", nil, nil)
+	if _, ok := ThrottleRetryAfter(breach); ok {
+		t.Fatal("position breach is not a throttle breach")
+	}
+}
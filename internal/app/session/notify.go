@@ -0,0 +1,40 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+// BusPublisher publishes boundaries as extension events stamped with the venue, so instances
+// trading it receive them alongside sinks and the outbox.
+func BusPublisher(bus eventbus.Bus, pools *pool.PoolManager, logger *log.Logger) Publisher {
+	return func(ctx context.Context, boundary Boundary) {
+		if bus == nil || pools == nil {
+			return
+		}
+		evt, err := pools.BorrowEventInst(ctx)
+		if err != nil {
+			if logger != nil {
+				logger.Printf("session boundary skipped: %v", err)
+			}
+			return
+		}
+		evt.EventID = fmt.Sprintf("session:%s:%d", boundary.Provider, boundary.SessionEnd.Unix())
+		evt.Provider = boundary.Provider
+		evt.Type = schema.ExtensionEventType
+		evt.IngestTS = boundary.SessionEnd
+		evt.EmitTS = boundary.SessionEnd
+		evt.Payload = boundary
+		if err := bus.Publish(ctx, evt); err != nil {
+			if logger != nil {
+				logger.Printf("publish session boundary: %v", err)
+			}
+			pools.ReturnEventInst(evt)
+		}
+	}
+}
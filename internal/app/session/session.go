@@ -0,0 +1,178 @@
+// Package session publishes trading session boundaries. At each venue's daily rollover it closes
+// the PnL session of every instance trading the venue and publishes their end-of-session
+// positions and PnL, so strategies can flatten at end of day and reporting consumers can align
+// with accounting days.
+package session
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// checkInterval is how often rollovers are evaluated.
+const checkInterval = time.Second
+
+// InstanceSummary is the end-of-session state of one instance on a venue.
+type InstanceSummary struct {
+	ID        string             `json:"id"`
+	Labels    map[string]string  `json:"labels,omitempty"`
+	Positions []core.PositionPnL `json:"positions"`
+}
+
+// Boundary is published when a venue's session rolls over.
+type Boundary struct {
+	Provider string `json:"provider"`
+	// SessionStart is the previous rollover, or the time the gateway started tracking the venue.
+	SessionStart time.Time `json:"sessionStart"`
+	SessionEnd   time.Time `json:"sessionEnd"`
+	// NextSessionEnd is the rollover that closes the session starting now.
+	NextSessionEnd time.Time         `json:"nextSessionEnd"`
+	Timezone       string            `json:"timezone"`
+	Instances      []InstanceSummary `json:"instances"`
+}
+
+// Source closes the PnL sessions of the instances trading a venue.
+type Source interface {
+	// SessionProviders lists the providers traded by running instances.
+	SessionProviders() []string
+	// CloseSessions reports the end-of-session state of the instances trading provider and starts
+	// their next session.
+	CloseSessions(provider string) []InstanceSummary
+}
+
+// Publisher delivers session boundaries.
+type Publisher func(ctx context.Context, boundary Boundary)
+
+// Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithClock overrides the time source.
+func WithClock(clock func() time.Time) Option {
+	return func(s *Scheduler) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
+// WithLogger overrides the scheduler logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(s *Scheduler) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+type venueState struct {
+	schedule     config.SessionSchedule
+	sessionStart time.Time
+	lastBoundary time.Time
+}
+
+// Scheduler tracks the rollover of every venue traded by running instances.
+type Scheduler struct {
+	cfg     config.SessionsConfig
+	source  Source
+	publish Publisher
+	clock   func() time.Time
+	logger  *log.Logger
+
+	mu     sync.Mutex
+	venues map[string]*venueState
+}
+
+// New constructs a scheduler closing sessions through source and delivering boundaries to publish.
+func New(cfg config.SessionsConfig, source Source, publish Publisher, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		cfg:     cfg,
+		source:  source,
+		publish: publish,
+		clock:   time.Now,
+		logger:  log.New(redact.Stdout, "session ", log.LstdFlags|log.Lmicroseconds),
+		mu:      sync.Mutex{},
+		venues:  make(map[string]*venueState),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// Run evaluates rollovers every second until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.Check(ctx)
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Check(ctx)
+		}
+	}
+}
+
+// Check publishes a boundary for every venue whose rollover passed since the last check. Venues
+// seen for the first time start their session without publishing.
+func (s *Scheduler) Check(ctx context.Context) {
+	if s.source == nil {
+		return
+	}
+	now := s.clock()
+	providers := s.source.SessionProviders()
+	sort.Strings(providers)
+	for _, provider := range providers {
+		venue, ok := s.venue(provider, now)
+		if !ok {
+			continue
+		}
+		boundary := venue.schedule.Boundary(now)
+		if !boundary.After(venue.lastBoundary) {
+			continue
+		}
+		published := Boundary{
+			Provider:       provider,
+			SessionStart:   venue.sessionStart.UTC(),
+			SessionEnd:     boundary.UTC(),
+			NextSessionEnd: venue.schedule.Next(boundary).UTC(),
+			Timezone:       venue.schedule.Location.String(),
+			Instances:      s.source.CloseSessions(provider),
+		}
+		s.mu.Lock()
+		venue.sessionStart = boundary
+		venue.lastBoundary = boundary
+		s.mu.Unlock()
+		s.logger.Printf("session rollover provider=%s instances=%d", provider, len(published.Instances))
+		if s.publish != nil {
+			s.publish(ctx, published)
+		}
+	}
+}
+
+func (s *Scheduler) venue(provider string, now time.Time) (*venueState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if venue, ok := s.venues[provider]; ok {
+		return venue, venue.schedule.Location != nil
+	}
+	schedule, err := s.cfg.Schedule(provider)
+	venue := &venueState{schedule: schedule, sessionStart: now, lastBoundary: time.Time{}}
+	s.venues[provider] = venue
+	if err != nil {
+		s.logger.Printf("session rollover disabled for provider %s: %v", provider, err)
+		return venue, false
+	}
+	venue.lastBoundary = schedule.Boundary(now)
+	return venue, false
+}
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/app/lambda/core"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+type stubSource struct {
+	providers []string
+	closed    []string
+}
+
+func (s *stubSource) SessionProviders() []string { return append([]string(nil), s.providers...) }
+
+func (s *stubSource) CloseSessions(provider string) []InstanceSummary {
+	s.closed = append(s.closed, provider)
+	return []InstanceSummary{{
+		ID:        "desk-" + provider,
+		Positions: []core.PositionPnL{{Symbol: "BTC-USDT", Position: decimal.NewFromInt(1), RealizedPnL: decimal.NewFromInt(5)}},
+	}}
+}
+
+func TestSchedulerPublishesPerVenueRollover(t *testing.T) {
+	cfg := config.SessionsConfig{
+		Enabled:  true,
+		Rollover: "00:00",
+		Timezone: "UTC",
+		Providers: map[string]config.SessionRolloverConfig{
+			"cme": {Rollover: "17:00", Timezone: "America/Chicago"},
+		},
+	}
+	clock := &fakeClock{now: time.Date(2026, 3, 18, 21, 0, 0, 0, time.UTC)}
+	source := &stubSource{providers: []string{"binance", "cme"}}
+	var published []Boundary
+	scheduler := New(cfg, source, func(_ context.Context, boundary Boundary) {
+		published = append(published, boundary)
+	}, WithClock(clock.Now), WithLogger(log.New(io.Discard, "", 0)))
+
+	scheduler.Check(context.Background())
+	if len(published) != 0 {
+		t.Fatalf("first check must only start sessions, got %+v", published)
+	}
+
+	// 17:00 in Chicago is 22:00 UTC during daylight saving time.
+	clock.now = time.Date(2026, 3, 18, 22, 0, 1, 0, time.UTC)
+	scheduler.Check(context.Background())
+	if len(published) != 1 || published[0].Provider != "cme" {
+		t.Fatalf("expected the cme rollover, got %+v", published)
+	}
+	cme := published[0]
+	if !cme.SessionEnd.Equal(time.Date(2026, 3, 18, 22, 0, 0, 0, time.UTC)) || !cme.NextSessionEnd.Equal(time.Date(2026, 3, 19, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected cme session bounds %+v", cme)
+	}
+	if cme.Timezone != "America/Chicago" || len(cme.Instances) != 1 || cme.Instances[0].ID != "desk-cme" {
+		t.Fatalf("unexpected cme boundary %+v", cme)
+	}
+
+	scheduler.Check(context.Background())
+	if len(published) != 1 {
+		t.Fatalf("rollover must be published once, got %d", len(published))
+	}
+
+	clock.now = time.Date(2026, 3, 19, 0, 0, 0, 0, time.UTC)
+	scheduler.Check(context.Background())
+	if len(published) != 2 || published[1].Provider != "binance" {
+		t.Fatalf("expected the binance midnight rollover, got %+v", published)
+	}
+	if !published[1].SessionStart.Equal(time.Date(2026, 3, 18, 21, 0, 0, 0, time.UTC)) {
+		t.Fatalf("first session should start when the venue was first seen, got %s", published[1].SessionStart)
+	}
+	if len(source.closed) != 2 {
+		t.Fatalf("expected two closed sessions, got %v", source.closed)
+	}
+}
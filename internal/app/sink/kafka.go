@@ -0,0 +1,413 @@
+package sink
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+const (
+	defaultKafkaPort = "9092"
+
+	kafkaAPIProduce  int16 = 0
+	kafkaAPIMetadata int16 = 3
+	// Produce v3 is the oldest version current brokers accept and the first to carry v2 record
+	// batches; Metadata v4 is the matching era and lets the request refuse topic auto-creation.
+	kafkaProduceVersion  int16 = 3
+	kafkaMetadataVersion int16 = 4
+
+	// kafkaPartition receives every batch so consumers read them in order.
+	kafkaPartition int32 = 0
+	// kafkaMaxResponse bounds the response size accepted from a broker.
+	kafkaMaxResponse = 16 << 20
+)
+
+var kafkaCastagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// kafkaTransport produces each batch as one JSON record, keyed by the sink name, to partition 0
+// of the topic. It speaks just the part of the Kafka protocol publishing needs: Metadata to find
+// the partition leader and Produce acknowledged by all in-sync replicas. The leader connection is
+// established lazily and looked up again on the next send after any failure.
+type kafkaTransport struct {
+	brokers  []string
+	topic    string
+	clientID string
+	key      []byte
+	timeout  time.Duration
+
+	mu          sync.Mutex
+	conn        net.Conn
+	correlation int32
+}
+
+func newKafkaTransport(cfg config.SinkConfig) (Transport, error) {
+	brokers, err := parseKafkaBrokers(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaTransport{
+		brokers:     brokers,
+		topic:       cfg.Subject,
+		clientID:    "meltica-sink-" + cfg.Name,
+		key:         []byte(cfg.Name),
+		timeout:     cfg.Timeout,
+		mu:          sync.Mutex{},
+		conn:        nil,
+		correlation: 0,
+	}, nil
+}
+
+// parseKafkaBrokers splits a bootstrap list such as kafka://k1:9092,k2:9092 into host:port
+// addresses.
+func parseKafkaBrokers(raw string) ([]string, error) {
+	list := strings.TrimPrefix(strings.TrimSpace(raw), "kafka://")
+	if strings.Contains(list, "://") {
+		return nil, fmt.Errorf("unsupported kafka url %q", raw)
+	}
+	var brokers []string
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSuffix(strings.TrimSpace(entry), "/")
+		if entry == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(entry); err != nil {
+			entry = net.JoinHostPort(entry, defaultKafkaPort)
+		}
+		brokers = append(brokers, entry)
+	}
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("kafka url %q lists no brokers", raw)
+	}
+	return brokers, nil
+}
+
+func (t *kafkaTransport) Send(ctx context.Context, batch Batch) error {
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("encode batch: %w", err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn == nil {
+		if err := t.connectLocked(ctx); err != nil {
+			return err
+		}
+	}
+	resp, err := t.roundTripLocked(ctx, t.conn, kafkaAPIProduce, kafkaProduceVersion, t.produceRequest(kafkaRecordBatch(t.key, payload, time.Now())))
+	if err == nil {
+		err = checkProduceResponse(resp)
+	}
+	if err != nil {
+		t.closeLocked()
+		return fmt.Errorf("kafka produce: %w", err)
+	}
+	return nil
+}
+
+// connectLocked asks the bootstrap brokers in turn for the partition leader and connects to it.
+func (t *kafkaTransport) connectLocked(ctx context.Context) error {
+	var errs []error
+	for _, broker := range t.brokers {
+		leader, err := t.lookupLeaderLocked(ctx, broker)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		conn, err := dialKafka(ctx, leader)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		t.conn = conn
+		return nil
+	}
+	return fmt.Errorf("kafka connect: %w", errors.Join(errs...))
+}
+
+func (t *kafkaTransport) lookupLeaderLocked(ctx context.Context, broker string) (string, error) {
+	conn, err := dialKafka(ctx, broker)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	var req kafkaEncoder
+	req.int32(1)
+	req.string(t.topic)
+	req.int8(0) // allow_auto_topic_creation: the topic must exist
+	resp, err := t.roundTripLocked(ctx, conn, kafkaAPIMetadata, kafkaMetadataVersion, req.buf)
+	if err != nil {
+		return "", fmt.Errorf("kafka metadata from %s: %w", broker, err)
+	}
+	leader, err := parseMetadataLeader(resp, t.topic, kafkaPartition)
+	if err != nil {
+		return "", fmt.Errorf("kafka metadata from %s: %w", broker, err)
+	}
+	return leader, nil
+}
+
+func dialKafka(ctx context.Context, addr string) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("kafka dial %s: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// roundTripLocked sends one request and reads its response, returning a decoder positioned after
+// the correlation id.
+func (t *kafkaTransport) roundTripLocked(ctx context.Context, conn net.Conn, apiKey, version int16, body []byte) (*kafkaDecoder, error) {
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+	t.correlation++
+	id := t.correlation
+	var req kafkaEncoder
+	req.int32(0) // size, set below
+	req.int16(apiKey)
+	req.int16(version)
+	req.int32(id)
+	req.string(t.clientID)
+	req.buf = append(req.buf, body...)
+	binary.BigEndian.PutUint32(req.buf, uint32(len(req.buf)-4))
+	if _, err := conn.Write(req.buf); err != nil {
+		return nil, err
+	}
+	var size [4]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n < 4 || n > kafkaMaxResponse {
+		return nil, fmt.Errorf("response of %d bytes", n)
+	}
+	resp := make([]byte, n)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+	dec := &kafkaDecoder{buf: resp, err: nil}
+	if got := dec.int32(); got != id {
+		return nil, fmt.Errorf("correlation id %d, want %d", got, id)
+	}
+	return dec, nil
+}
+
+func (t *kafkaTransport) produceRequest(records []byte) []byte {
+	var e kafkaEncoder
+	e.int16(-1) // transactional_id: none
+	e.int16(-1) // acks: all in-sync replicas
+	e.int32(int32(t.timeout / time.Millisecond))
+	e.int32(1)
+	e.string(t.topic)
+	e.int32(1)
+	e.int32(kafkaPartition)
+	e.int32(int32(len(records)))
+	e.buf = append(e.buf, records...)
+	return e.buf
+}
+
+func checkProduceResponse(d *kafkaDecoder) error {
+	for topics := d.int32(); topics > 0 && d.err == nil; topics-- {
+		d.string()
+		for partitions := d.int32(); partitions > 0 && d.err == nil; partitions-- {
+			partition := d.int32()
+			code := d.int16()
+			d.int64() // base_offset
+			d.int64() // log_append_time_ms
+			if code != 0 && d.err == nil {
+				return fmt.Errorf("partition %d: kafka error %d", partition, code)
+			}
+		}
+	}
+	if d.err != nil {
+		return fmt.Errorf("decode produce response: %w", d.err)
+	}
+	return nil
+}
+
+// parseMetadataLeader returns the address of the broker leading partition of topic.
+func parseMetadataLeader(d *kafkaDecoder, topic string, partition int32) (string, error) {
+	d.int32() // throttle_time_ms
+	brokers := make(map[int32]string)
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.string() // cluster_id
+	d.int32()  // controller_id
+	for n := d.int32(); n > 0 && d.err == nil; n-- {
+		code := d.int16()
+		name := d.string()
+		d.int8() // is_internal
+		for p := d.int32(); p > 0 && d.err == nil; p-- {
+			partitionCode := d.int16()
+			index := d.int32()
+			leader := d.int32()
+			d.skipInt32Array() // replica_nodes
+			d.skipInt32Array() // isr_nodes
+			if name != topic || index != partition || d.err != nil {
+				continue
+			}
+			if partitionCode != 0 {
+				return "", fmt.Errorf("topic %s partition %d: kafka error %d", topic, partition, partitionCode)
+			}
+			addr, ok := brokers[leader]
+			if !ok {
+				return "", fmt.Errorf("topic %s partition %d has no leader", topic, partition)
+			}
+			return addr, nil
+		}
+		if name == topic && code != 0 {
+			return "", fmt.Errorf("topic %s: kafka error %d", topic, code)
+		}
+	}
+	if d.err != nil {
+		return "", fmt.Errorf("decode metadata: %w", d.err)
+	}
+	return "", fmt.Errorf("topic %s partition %d not found", topic, partition)
+}
+
+// kafkaRecordBatch encodes a v2 record batch holding a single uncompressed record.
+func kafkaRecordBatch(key, value []byte, now time.Time) []byte {
+	record := []byte{0}                     // attributes
+	record = binary.AppendVarint(record, 0) // timestamp delta
+	record = binary.AppendVarint(record, 0) // offset delta
+	record = binary.AppendVarint(record, int64(len(key)))
+	record = append(record, key...)
+	record = binary.AppendVarint(record, int64(len(value)))
+	record = append(record, value...)
+	record = binary.AppendVarint(record, 0) // headers
+
+	ts := now.UnixMilli()
+	var e kafkaEncoder
+	e.int64(0)  // base offset
+	e.int32(0)  // batch length, set below
+	e.int32(-1) // partition leader epoch
+	e.int8(2)   // magic
+	e.int32(0)  // crc, set below
+	crcStart := len(e.buf)
+	e.int16(0)  // attributes: no compression, create time
+	e.int32(0)  // last offset delta
+	e.int64(ts) // base timestamp
+	e.int64(ts) // max timestamp
+	e.int64(-1) // producer id
+	e.int16(-1) // producer epoch
+	e.int32(-1) // base sequence
+	e.int32(1)  // records
+	e.buf = binary.AppendVarint(e.buf, int64(len(record)))
+	e.buf = append(e.buf, record...)
+	binary.BigEndian.PutUint32(e.buf[8:], uint32(len(e.buf)-12))
+	binary.BigEndian.PutUint32(e.buf[crcStart-4:], crc32.Checksum(e.buf[crcStart:], kafkaCastagnoli))
+	return e.buf
+}
+
+func (t *kafkaTransport) closeLocked() {
+	if t.conn != nil {
+		_ = t.conn.Close()
+	}
+	t.conn = nil
+}
+
+func (t *kafkaTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closeLocked()
+	return nil
+}
+
+// kafkaEncoder appends big-endian protocol primitives.
+type kafkaEncoder struct {
+	buf []byte
+}
+
+func (e *kafkaEncoder) int8(v int8) { e.buf = append(e.buf, byte(v)) }
+
+func (e *kafkaEncoder) int16(v int16) { e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v)) }
+
+func (e *kafkaEncoder) int32(v int32) { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+
+func (e *kafkaEncoder) int64(v int64) { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+
+func (e *kafkaEncoder) string(v string) {
+	e.int16(int16(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// kafkaDecoder reads big-endian protocol primitives; the first short read sticks in err and
+// every later read returns zero values.
+type kafkaDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *kafkaDecoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(d.buf) {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	out := d.buf[:n]
+	d.buf = d.buf[n:]
+	return out
+}
+
+func (d *kafkaDecoder) int8() int8 {
+	if b := d.take(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.take(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.take(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if b := d.take(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string reads a length-prefixed string; null strings read as "".
+func (d *kafkaDecoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.take(int(n)))
+}
+
+func (d *kafkaDecoder) skipInt32Array() {
+	if n := d.int32(); n > 0 {
+		d.take(int(n) * 4)
+	}
+}
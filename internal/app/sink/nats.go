@@ -0,0 +1,195 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+const defaultNATSPort = "4222"
+
+// natsTransport speaks the plain-text NATS core protocol, which is all that is required to
+// publish batches: CONNECT, PUB and answering server PINGs. The connection is established
+// lazily and re-dialled on the next send after a failure.
+type natsTransport struct {
+	addr    string
+	subject string
+	connect []byte
+
+	mu   sync.Mutex
+	conn net.Conn
+	err  error
+}
+
+type natsConnectOptions struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+	Protocol int    `json:"protocol"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+}
+
+func newNATSTransport(cfg config.SinkConfig) (Transport, error) {
+	parsed, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse nats url: %w", err)
+	}
+	if parsed.Scheme != "nats" {
+		return nil, fmt.Errorf("unsupported nats url scheme %q", parsed.Scheme)
+	}
+	host := parsed.Hostname()
+	port := parsed.Port()
+	if port == "" {
+		port = defaultNATSPort
+	}
+	opts := natsConnectOptions{
+		Verbose:  false,
+		Pedantic: false,
+		Name:     "meltica-sink-" + cfg.Name,
+		Lang:     "go",
+		Version:  "1",
+		Protocol: 0,
+		User:     "",
+		Pass:     "",
+	}
+	if parsed.User != nil {
+		opts.User = parsed.User.Username()
+		opts.Pass, _ = parsed.User.Password()
+	}
+	encoded, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("encode nats connect: %w", err)
+	}
+	return &natsTransport{
+		addr:    net.JoinHostPort(host, port),
+		subject: cfg.Subject,
+		connect: []byte("CONNECT " + string(encoded) + "\r\n"),
+		mu:      sync.Mutex{},
+		conn:    nil,
+		err:     nil,
+	}, nil
+}
+
+func (t *natsTransport) Send(ctx context.Context, batch Batch) error {
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("encode batch: %w", err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		t.closeLocked()
+	}
+	if t.conn == nil {
+		if err := t.dialLocked(ctx); err != nil {
+			return err
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = t.conn.SetWriteDeadline(deadline)
+	}
+	frame := make([]byte, 0, len(payload)+len(t.subject)+32)
+	frame = fmt.Appendf(frame, "PUB %s %d\r\n", t.subject, len(payload))
+	frame = append(frame, payload...)
+	frame = append(frame, '\r', '\n')
+	if _, err := t.conn.Write(frame); err != nil {
+		t.closeLocked()
+		return fmt.Errorf("nats publish: %w", err)
+	}
+	return nil
+}
+
+func (t *natsTransport) dialLocked(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("nats dial %s: %w", t.addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	reader := bufio.NewReader(conn)
+	info, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(info, "INFO") {
+		_ = conn.Close()
+		return fmt.Errorf("nats handshake: expected INFO: %w", errors.Join(err, errors.New(strings.TrimSpace(info))))
+	}
+	if _, err := conn.Write(append(append([]byte(nil), t.connect...), "PING\r\n"...)); err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("nats connect: %w", err)
+	}
+	reply, err := reader.ReadString('\n')
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("nats connect: %w", err)
+	}
+	if !strings.HasPrefix(reply, "PONG") {
+		_ = conn.Close()
+		return fmt.Errorf("nats connect rejected: %s", strings.TrimSpace(reply))
+	}
+	_ = conn.SetDeadline(time.Time{})
+	t.conn = conn
+	t.err = nil
+	go t.readLoop(conn, reader)
+	return nil
+}
+
+// readLoop answers server keepalives and records protocol errors so the next send reconnects.
+func (t *natsTransport) readLoop(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.fail(conn, err)
+			return
+		}
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			t.mu.Lock()
+			_, err = conn.Write([]byte("PONG\r\n"))
+			t.mu.Unlock()
+			if err != nil {
+				t.fail(conn, err)
+				return
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			t.fail(conn, errors.New(strings.TrimSpace(line)))
+			return
+		}
+	}
+}
+
+func (t *natsTransport) fail(conn net.Conn, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn == conn {
+		t.err = err
+	}
+}
+
+func (t *natsTransport) closeLocked() {
+	if t.conn != nil {
+		_ = t.conn.Close()
+	}
+	t.conn = nil
+	t.err = nil
+}
+
+func (t *natsTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closeLocked()
+	return nil
+}
@@ -0,0 +1,427 @@
+// Package sink forwards selected bus events to external systems such as webhooks and message brokers.
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/app/featureflags"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// DefaultEventTypes are forwarded when a sink does not list event types explicitly.
+var DefaultEventTypes = []schema.EventType{
+	schema.EventTypeExecReport,
+	schema.EventTypeBalanceUpdate,
+	schema.EventTypeRiskControl,
+}
+
+// Record is the wire representation of a forwarded event.
+type Record struct {
+	EventID     string           `json:"eventId"`
+	Type        schema.EventType `json:"type"`
+	Provider    string           `json:"provider"`
+	Symbol      string           `json:"symbol"`
+	SeqProvider uint64           `json:"seqProvider"`
+	EventTS     time.Time        `json:"eventTs"`
+	IngestTS    time.Time        `json:"ingestTs"`
+	EmitTS      time.Time        `json:"emitTs"`
+	Payload     any              `json:"payload"`
+}
+
+// Batch groups records delivered to a sink in a single request.
+type Batch struct {
+	Sink    string   `json:"sink"`
+	SentAt  string   `json:"sentAt"`
+	Records []Record `json:"events"`
+}
+
+// Transport delivers batches to an external system.
+type Transport interface {
+	Send(ctx context.Context, batch Batch) error
+	Close() error
+}
+
+// Factory builds a transport for a sink configuration.
+type Factory func(cfg config.SinkConfig) (Transport, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[config.SinkType]Factory{
+		config.SinkTypeWebhook: newWebhookTransport,
+		config.SinkTypeNATS:    newNATSTransport,
+		config.SinkTypeKafka:   newKafkaTransport,
+	}
+)
+
+// RegisterFactory installs the transport factory for a sink type, replacing any existing one.
+func RegisterFactory(typ config.SinkType, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[typ] = factory
+}
+
+func lookupFactory(typ config.SinkType) (Factory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	factory, ok := factories[typ]
+	return factory, ok
+}
+
+// Manager subscribes to the event bus and fans events out to the configured sinks.
+type Manager struct {
+	bus     eventbus.Bus
+	pools   *pool.PoolManager
+	logger  *log.Logger
+	workers []*worker
+	types   []schema.EventType
+	wg      sync.WaitGroup
+	counter metric.Int64Counter
+	flags   *featureflags.Flags
+}
+
+// Option configures the sink manager.
+type Option func(*Manager)
+
+// WithFeatureFlags makes batching follow the sink_batching feature flag; while it is off every
+// record is delivered on its own.
+func WithFeatureFlags(flags *featureflags.Flags) Option {
+	return func(m *Manager) {
+		m.flags = flags
+	}
+}
+
+// NewManager builds transports for every configured sink.
+func NewManager(cfgs []config.SinkConfig, bus eventbus.Bus, pools *pool.PoolManager, logger *log.Logger, opts ...Option) (*Manager, error) {
+	if logger == nil {
+		logger = log.New(redact.Stdout, "sinks ", log.LstdFlags|log.Lmicroseconds)
+	}
+	mgr := &Manager{
+		bus:     bus,
+		pools:   pools,
+		logger:  logger,
+		workers: make([]*worker, 0, len(cfgs)),
+		types:   nil,
+		wg:      sync.WaitGroup{},
+		counter: nil,
+		flags:   nil,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(mgr)
+		}
+	}
+	if counter, err := otel.Meter("sinks").Int64Counter("event_sink_records_total",
+		metric.WithDescription("Events handled by external sinks by outcome"),
+		metric.WithUnit("{event}")); err == nil {
+		mgr.counter = counter
+	}
+	typeSet := make(map[schema.EventType]struct{})
+	for _, cfg := range cfgs {
+		factory, ok := lookupFactory(cfg.Type)
+		if !ok {
+			mgr.closeTransports()
+			return nil, fmt.Errorf("sink %s: no transport registered for type %q", cfg.Name, cfg.Type)
+		}
+		transport, err := factory(cfg)
+		if err != nil {
+			mgr.closeTransports()
+			return nil, fmt.Errorf("sink %s: %w", cfg.Name, err)
+		}
+		w := newWorker(cfg, transport, mgr)
+		for typ := range w.types {
+			typeSet[typ] = struct{}{}
+		}
+		mgr.workers = append(mgr.workers, w)
+	}
+	for typ := range typeSet {
+		mgr.types = append(mgr.types, typ)
+	}
+	return mgr, nil
+}
+
+// Start subscribes to the bus and runs the sink workers until ctx is cancelled.
+func (m *Manager) Start(ctx context.Context) error {
+	if m == nil || len(m.workers) == 0 {
+		return nil
+	}
+	if m.bus == nil {
+		return errors.New("sinks: event bus not configured")
+	}
+	for _, w := range m.workers {
+		worker := w
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			worker.run(ctx)
+		}()
+	}
+	for _, typ := range m.types {
+		id, ch, err := m.bus.Subscribe(ctx, typ)
+		if err != nil {
+			return fmt.Errorf("sinks: subscribe %s: %w", typ, err)
+		}
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			defer m.bus.Unsubscribe(id)
+			m.consume(ctx, ch)
+		}()
+	}
+	m.logger.Printf("event sinks started: %d sink(s), %d event type(s)", len(m.workers), len(m.types))
+	return nil
+}
+
+// Wait blocks until the workers have flushed and stopped after ctx cancellation.
+func (m *Manager) Wait() {
+	if m == nil {
+		return
+	}
+	m.wg.Wait()
+	m.closeTransports()
+}
+
+func (m *Manager) consume(ctx context.Context, ch <-chan *schema.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-ch:
+			if !ok {
+				return
+			}
+			if evt == nil {
+				continue
+			}
+			record := recordFromEvent(evt)
+			m.recycle(evt)
+			for _, w := range m.workers {
+				w.offer(ctx, record)
+			}
+		}
+	}
+}
+
+func (m *Manager) recycle(evt *schema.Event) {
+	if m.pools != nil {
+		m.pools.TryReturnEventInst(evt)
+	}
+}
+
+func (m *Manager) closeTransports() {
+	for _, w := range m.workers {
+		if err := w.transport.Close(); err != nil {
+			m.logger.Printf("sink %s: close: %v", w.cfg.Name, err)
+		}
+	}
+}
+
+func (m *Manager) record(ctx context.Context, sinkName, outcome string, n int) {
+	if m.counter == nil || n == 0 {
+		return
+	}
+	m.counter.Add(ctx, int64(n), metric.WithAttributes(
+		attribute.String("environment", telemetry.Environment()),
+		attribute.String("sink", sinkName),
+		attribute.String("outcome", outcome),
+	))
+}
+
+func recordFromEvent(evt *schema.Event) Record {
+	return Record{
+		EventID:     evt.EventID,
+		Type:        evt.Type,
+		Provider:    evt.Provider,
+		Symbol:      evt.Symbol,
+		SeqProvider: evt.SeqProvider,
+		EventTS:     evt.EventTS,
+		IngestTS:    evt.IngestTS,
+		EmitTS:      evt.EmitTS,
+		Payload:     evt.Payload,
+	}
+}
+
+type worker struct {
+	cfg       config.SinkConfig
+	transport Transport
+	manager   *Manager
+	types     map[schema.EventType]struct{}
+	providers map[string]struct{}
+	symbols   map[string]struct{}
+	queue     chan Record
+}
+
+func newWorker(cfg config.SinkConfig, transport Transport, manager *Manager) *worker {
+	types := make(map[schema.EventType]struct{})
+	for _, raw := range cfg.EventTypes {
+		if trimmed := strings.TrimSpace(raw); trimmed != "" {
+			types[schema.EventType(trimmed)] = struct{}{}
+		}
+	}
+	if len(types) == 0 {
+		for _, typ := range DefaultEventTypes {
+			types[typ] = struct{}{}
+		}
+	}
+	return &worker{
+		cfg:       cfg,
+		transport: transport,
+		manager:   manager,
+		types:     types,
+		providers: toSet(cfg.Providers, strings.ToLower),
+		symbols:   toSet(cfg.Symbols, strings.ToUpper),
+		queue:     make(chan Record, cfg.BufferSize),
+	}
+}
+
+func (w *worker) matches(record Record) bool {
+	if _, ok := w.types[record.Type]; !ok {
+		return false
+	}
+	if len(w.providers) > 0 {
+		if _, ok := w.providers[strings.ToLower(record.Provider)]; !ok {
+			return false
+		}
+	}
+	if len(w.symbols) > 0 {
+		if _, ok := w.symbols[strings.ToUpper(record.Symbol)]; !ok {
+			return false
+		}
+	}
+	if w.cfg.InstrumentChangesOnly && record.Type == schema.EventTypeInstrumentUpdate {
+		return instrumentChanged(record.Payload)
+	}
+	return true
+}
+
+func instrumentChanged(payload any) bool {
+	switch v := payload.(type) {
+	case schema.InstrumentUpdatePayload:
+		return len(v.Changes) > 0
+	case *schema.InstrumentUpdatePayload:
+		return v != nil && len(v.Changes) > 0
+	default:
+		return false
+	}
+}
+
+// batchSize is the configured batch size, or 1 while the sink_batching flag is off.
+func (w *worker) batchSize() int {
+	if !w.manager.flags.Enabled(featureflags.SinkBatching) {
+		return 1
+	}
+	return w.cfg.BatchSize
+}
+
+// offer enqueues a record without blocking the bus; records are dropped when the buffer is full.
+func (w *worker) offer(ctx context.Context, record Record) {
+	if !w.matches(record) {
+		return
+	}
+	select {
+	case w.queue <- record:
+	default:
+		w.manager.record(ctx, w.cfg.Name, "dropped", 1)
+	}
+}
+
+func (w *worker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+	pending := make([]Record, 0, w.cfg.BatchSize)
+	flush := func(ctx context.Context) {
+		if len(pending) == 0 {
+			return
+		}
+		w.deliver(ctx, pending)
+		pending = make([]Record, 0, w.cfg.BatchSize)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			// Flush what is already buffered with a bounded grace period.
+			drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.cfg.Timeout)
+		drain:
+			for {
+				select {
+				case record := <-w.queue:
+					pending = append(pending, record)
+					if len(pending) >= w.batchSize() {
+						flush(drainCtx)
+					}
+				default:
+					break drain
+				}
+			}
+			flush(drainCtx)
+			cancel()
+			return
+		case record := <-w.queue:
+			pending = append(pending, record)
+			if len(pending) >= w.batchSize() {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
+
+// deliver sends a batch, retrying with exponential backoff up to MaxRetries times.
+func (w *worker) deliver(ctx context.Context, records []Record) {
+	batch := Batch{
+		Sink:    w.cfg.Name,
+		SentAt:  time.Now().UTC().Format(time.RFC3339Nano),
+		Records: records,
+	}
+	backoffCfg := backoff.NewExponentialBackOff()
+	var err error
+retry:
+	for attempt := 0; attempt <= w.cfg.MaxRetries; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+		err = w.transport.Send(sendCtx, batch)
+		cancel()
+		if err == nil {
+			w.manager.record(ctx, w.cfg.Name, "delivered", len(records))
+			return
+		}
+		if attempt == w.cfg.MaxRetries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			break retry
+		case <-time.After(backoffCfg.NextBackOff()):
+		}
+	}
+	w.manager.record(ctx, w.cfg.Name, "failed", len(records))
+	w.manager.logger.Printf("sink %s: dropping %d event(s) after retries: %v", w.cfg.Name, len(records), err)
+}
+
+func toSet(values []string, normalize func(string) string) map[string]struct{} {
+	if len(values) == 0 {
+		return nil
+	}
+	out := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			out[normalize(trimmed)] = struct{}{}
+		}
+	}
+	return out
+}
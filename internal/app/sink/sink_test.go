@@ -0,0 +1,415 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/featureflags"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type fakeBus struct {
+	mu   sync.Mutex
+	subs map[schema.EventType]chan *schema.Event
+}
+
+func (b *fakeBus) Publish(_ context.Context, evt *schema.Event) error {
+	b.mu.Lock()
+	ch := b.subs[evt.Type]
+	b.mu.Unlock()
+	if ch != nil {
+		ch <- evt
+	}
+	return nil
+}
+
+func (b *fakeBus) Subscribe(_ context.Context, typ schema.EventType) (eventbus.SubscriptionID, <-chan *schema.Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan *schema.Event, 16)
+	b.subs[typ] = ch
+	return eventbus.SubscriptionID(typ), ch, nil
+}
+
+func (b *fakeBus) Unsubscribe(eventbus.SubscriptionID) {}
+func (b *fakeBus) Close()                              {}
+
+type recordingTransport struct {
+	mu       sync.Mutex
+	batches  []Batch
+	failures int
+}
+
+func (t *recordingTransport) Send(_ context.Context, batch Batch) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failures > 0 {
+		t.failures--
+		return errors.New("unavailable")
+	}
+	t.batches = append(t.batches, batch)
+	return nil
+}
+
+func (t *recordingTransport) Close() error { return nil }
+
+func (t *recordingTransport) snapshot() []Batch {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Batch(nil), t.batches...)
+}
+
+func TestManagerFiltersBatchesAndRetries(t *testing.T) {
+	transport := &recordingTransport{failures: 1}
+	RegisterFactory("recording", func(config.SinkConfig) (Transport, error) { return transport, nil })
+	bus := &fakeBus{subs: make(map[schema.EventType]chan *schema.Event)}
+	mgr, err := NewManager([]config.SinkConfig{{
+		Name:          "accounting",
+		Type:          "recording",
+		Providers:     []string{"binance"},
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		MaxRetries:    2,
+		Timeout:       time.Second,
+		BufferSize:    8,
+	}}, bus, nil, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := mgr.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	publish := func(id, provider string, typ schema.EventType) {
+		_ = bus.Publish(ctx, &schema.Event{EventID: id, Provider: provider, Type: typ, Payload: schema.BalanceUpdatePayload{Currency: "USDT"}})
+	}
+	publish("e1", "binance", schema.EventTypeBalanceUpdate)
+	publish("skip-provider", "okx", schema.EventTypeBalanceUpdate)
+	publish("e2", "binance", schema.EventTypeExecReport)
+	publish("e3", "binance", schema.EventTypeBalanceUpdate)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(transport.snapshot()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	mgr.Wait()
+
+	var ids []string
+	for _, batch := range transport.snapshot() {
+		if batch.Sink != "accounting" {
+			t.Fatalf("unexpected sink name %q", batch.Sink)
+		}
+		for _, record := range batch.Records {
+			ids = append(ids, record.EventID)
+		}
+	}
+	sort.Strings(ids)
+	if strings.Join(ids, ",") != "e1,e2,e3" {
+		t.Fatalf("unexpected forwarded events %v", ids)
+	}
+	if batches := transport.snapshot(); len(batches[0].Records) != 2 {
+		t.Fatalf("expected first batch of 2 records after retry, got %d", len(batches[0].Records))
+	}
+}
+
+func TestManagerSendsSingleRecordsWhenBatchingFlagOff(t *testing.T) {
+	transport := &recordingTransport{}
+	RegisterFactory("recording-unbatched", func(config.SinkConfig) (Transport, error) { return transport, nil })
+	bus := &fakeBus{subs: make(map[schema.EventType]chan *schema.Event)}
+	flags := featureflags.New(map[string]bool{featureflags.SinkBatching: false})
+	mgr, err := NewManager([]config.SinkConfig{{
+		Name:          "accounting",
+		Type:          "recording-unbatched",
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+		Timeout:       time.Second,
+		BufferSize:    8,
+	}}, bus, nil, nil, WithFeatureFlags(flags))
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := mgr.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	_ = bus.Publish(ctx, &schema.Event{EventID: "e1", Type: schema.EventTypeBalanceUpdate, Payload: schema.BalanceUpdatePayload{Currency: "USDT"}})
+	_ = bus.Publish(ctx, &schema.Event{EventID: "e2", Type: schema.EventTypeBalanceUpdate, Payload: schema.BalanceUpdatePayload{Currency: "BTC"}})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(transport.snapshot()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	mgr.Wait()
+
+	batches := transport.snapshot()
+	if len(batches) != 2 {
+		t.Fatalf("expected each record delivered on its own, got %d batches", len(batches))
+	}
+	for _, batch := range batches {
+		if len(batch.Records) != 1 {
+			t.Fatalf("expected single-record batch, got %d records", len(batch.Records))
+		}
+	}
+}
+
+func TestWebhookTransportPostsBatch(t *testing.T) {
+	var received Batch
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	transport, err := newWebhookTransport(config.SinkConfig{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("newWebhookTransport: %v", err)
+	}
+	batch := Batch{Sink: "hook", Records: []Record{{EventID: "e1", Type: schema.EventTypeExecReport}}}
+	if err := transport.Send(context.Background(), batch); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if auth != "Bearer token" || len(received.Records) != 1 || received.Records[0].EventID != "e1" {
+		t.Fatalf("unexpected webhook delivery auth=%q batch=%+v", auth, received)
+	}
+}
+
+func TestNATSTransportPublishes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	published := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("INFO {}\r\n"))
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "PING"):
+				_, _ = conn.Write([]byte("PONG\r\n"))
+			case strings.HasPrefix(line, "PUB"):
+				payload, _ := reader.ReadString('\n')
+				published <- strings.TrimSpace(line) + "|" + strings.TrimSpace(payload)
+			}
+		}
+	}()
+
+	transport, err := newNATSTransport(config.SinkConfig{Name: "bus", URL: "nats://" + listener.Addr().String(), Subject: "meltica.events"})
+	if err != nil {
+		t.Fatalf("newNATSTransport: %v", err)
+	}
+	defer transport.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := transport.Send(ctx, Batch{Sink: "bus", Records: []Record{{EventID: "e1"}}}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	select {
+	case got := <-published:
+		if !strings.HasPrefix(got, "PUB meltica.events ") || !strings.Contains(got, `"eventId":"e1"`) {
+			t.Fatalf("unexpected publish frame %q", got)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for publish")
+	}
+}
+
+// fakeKafkaBroker answers Metadata with itself as leader of partition 0 and decodes Produce
+// requests, failing the first one with NOT_LEADER_OR_FOLLOWER.
+func fakeKafkaBroker(t *testing.T, listener net.Listener, produced chan<- []byte) {
+	t.Helper()
+	host, portText, _ := net.SplitHostPort(listener.Addr().String())
+	port, _ := strconv.Atoi(portText)
+	var failed atomic.Bool
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			for {
+				var size [4]byte
+				if _, err := io.ReadFull(conn, size[:]); err != nil {
+					return
+				}
+				frame := make([]byte, binary.BigEndian.Uint32(size[:]))
+				if _, err := io.ReadFull(conn, frame); err != nil {
+					return
+				}
+				req := &kafkaDecoder{buf: frame}
+				apiKey := req.int16()
+				req.int16() // api version
+				correlation := req.int32()
+				req.string() // client id
+				var resp kafkaEncoder
+				resp.int32(0)
+				resp.int32(correlation)
+				switch apiKey {
+				case kafkaAPIMetadata:
+					resp.int32(0) // throttle
+					resp.int32(1)
+					resp.int32(1)
+					resp.string(host)
+					resp.int32(int32(port))
+					resp.int16(-1) // rack
+					resp.int16(-1) // cluster id
+					resp.int32(1)  // controller
+					resp.int32(1)
+					resp.int16(0)
+					resp.string("meltica.fills")
+					resp.int8(0)
+					resp.int32(1)
+					resp.int16(0)
+					resp.int32(0) // partition
+					resp.int32(1) // leader
+					resp.int32(0) // replicas
+					resp.int32(0) // isr
+				case kafkaAPIProduce:
+					req.int16() // transactional id
+					req.int16() // acks
+					req.int32() // timeout
+					req.int32()
+					topic := req.string()
+					req.int32()
+					partition := req.int32()
+					records := req.take(int(req.int32()))
+					code := int16(0)
+					if !failed.Swap(true) {
+						code = 6
+					} else if req.err == nil && topic == "meltica.fills" && partition == 0 {
+						produced <- records
+					}
+					resp.int32(1)
+					resp.string(topic)
+					resp.int32(1)
+					resp.int32(partition)
+					resp.int16(code)
+					resp.int64(0)
+					resp.int64(-1)
+					resp.int32(0) // throttle
+				}
+				binary.BigEndian.PutUint32(resp.buf, uint32(len(resp.buf)-4))
+				if _, err := conn.Write(resp.buf); err != nil {
+					return
+				}
+			}
+		}(conn)
+	}
+}
+
+func TestKafkaTransportProduces(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+	produced := make(chan []byte, 1)
+	go fakeKafkaBroker(t, listener, produced)
+
+	transport, err := newKafkaTransport(config.SinkConfig{Name: "fills", URL: "kafka://" + listener.Addr().String(), Subject: "meltica.fills", Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("newKafkaTransport: %v", err)
+	}
+	defer transport.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	batch := Batch{Sink: "fills", Records: []Record{{EventID: "e1"}}}
+	if err := transport.Send(ctx, batch); err == nil || !strings.Contains(err.Error(), "kafka error 6") {
+		t.Fatalf("expected the broker error surfaced for retry, got %v", err)
+	}
+	if err := transport.Send(ctx, batch); err != nil {
+		t.Fatalf("Send after reconnect: %v", err)
+	}
+	var records []byte
+	select {
+	case records = <-produced:
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for produce")
+	}
+	if len(records) < 61 || records[16] != 2 {
+		t.Fatalf("expected a v2 record batch, got %d bytes", len(records))
+	}
+	if crc := binary.BigEndian.Uint32(records[17:21]); crc != crc32.Checksum(records[21:], kafkaCastagnoli) {
+		t.Fatal("record batch crc mismatch")
+	}
+	record := records[61:]
+	_, n := binary.Varint(record) // record length
+	record = record[n+1:]         // attributes
+	for range 2 {                 // timestamp and offset deltas
+		_, n = binary.Varint(record)
+		record = record[n:]
+	}
+	keyLen, n := binary.Varint(record)
+	key := string(record[n : n+int(keyLen)])
+	record = record[n+int(keyLen):]
+	valueLen, n := binary.Varint(record)
+	value := record[n : n+int(valueLen)]
+	if key != "fills" || !strings.Contains(string(value), `"eventId":"e1"`) {
+		t.Fatalf("unexpected record key %q value %s", key, value)
+	}
+}
+
+func TestParseKafkaBrokers(t *testing.T) {
+	brokers, err := parseKafkaBrokers("kafka://k1:9093, k2 ,")
+	if err != nil {
+		t.Fatalf("parseKafkaBrokers: %v", err)
+	}
+	if len(brokers) != 2 || brokers[0] != "k1:9093" || brokers[1] != "k2:9092" {
+		t.Fatalf("unexpected brokers %v", brokers)
+	}
+	if _, err := parseKafkaBrokers("https://k1:9092"); err == nil {
+		t.Fatal("expected non-kafka schemes to be rejected")
+	}
+}
+
+func TestWorkerForwardsOnlyChangedInstruments(t *testing.T) {
+	w := newWorker(config.SinkConfig{
+		Name:                  "alerts",
+		EventTypes:            []string{string(schema.EventTypeInstrumentUpdate)},
+		InstrumentChangesOnly: true,
+		BufferSize:            1,
+	}, nil, nil)
+	unchanged := Record{Type: schema.EventTypeInstrumentUpdate, Payload: schema.InstrumentUpdatePayload{Instrument: schema.Instrument{Symbol: "BTC-USDT"}}}
+	if w.matches(unchanged) {
+		t.Fatal("expected an unchanged instrument to be dropped")
+	}
+	changed := Record{Type: schema.EventTypeInstrumentUpdate, Payload: &schema.InstrumentUpdatePayload{
+		Instrument: schema.Instrument{Symbol: "BTC-USDT"},
+		Changes:    []schema.InstrumentChange{{Field: "priceIncrement", Previous: "0.01", Current: "0.1"}},
+	}}
+	if !w.matches(changed) {
+		t.Fatal("expected a changed instrument to be forwarded")
+	}
+}
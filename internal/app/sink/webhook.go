@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type webhookTransport struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newWebhookTransport(cfg config.SinkConfig) (Transport, error) {
+	headers := make(map[string]string, len(cfg.Headers))
+	for key, value := range cfg.Headers {
+		headers[key] = value
+	}
+	return &webhookTransport{
+		url:     cfg.URL,
+		headers: headers,
+		client: &http.Client{
+			Transport:     nil,
+			CheckRedirect: nil,
+			Jar:           nil,
+			Timeout:       cfg.Timeout,
+		},
+	}, nil
+}
+
+// Send POSTs the batch as JSON; any non-2xx response is treated as retryable.
+func (t *webhookTransport) Send(ctx context.Context, batch Batch) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("encode batch: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (t *webhookTransport) Close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
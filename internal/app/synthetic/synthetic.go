@@ -0,0 +1,348 @@
+// Package synthetic computes tickers for config-defined spread and basket instruments from
+// their component feeds and publishes them on the event bus as ordinary ticker events.
+package synthetic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// RegistrationPrefix prefixes the route registrations that keep leg feeds streaming.
+const RegistrationPrefix = "synthetic:"
+
+// RouteRegistrar activates provider routes on behalf of a named consumer.
+type RouteRegistrar interface {
+	RegisterLambda(ctx context.Context, lambdaID string, providers []string, routes []dispatcher.RouteDeclaration) error
+}
+
+// Option configures an Engine.
+type Option func(*Engine)
+
+// WithLogger overrides the engine logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(e *Engine) {
+		if logger != nil {
+			e.logger = logger
+		}
+	}
+}
+
+// WithRegistrar subscribes the leg ticker feeds through registrar when the engine starts, so
+// synthetic prices flow even when no instance streams the legs itself.
+func WithRegistrar(registrar RouteRegistrar) Option {
+	return func(e *Engine) {
+		e.registrar = registrar
+	}
+}
+
+// WithClock overrides the clock used for ingest timestamps.
+func WithClock(clock func() time.Time) Option {
+	return func(e *Engine) {
+		if clock != nil {
+			e.clock = clock
+		}
+	}
+}
+
+// Engine tracks the latest leg tickers and republishes the combined synthetic tickers.
+type Engine struct {
+	bus       eventbus.Bus
+	pools     *pool.PoolManager
+	logger    *log.Logger
+	registrar RouteRegistrar
+	clock     func() time.Time
+
+	mu          sync.Mutex
+	instruments []*instrument
+	byLeg       map[legKey][]legRef
+
+	wg sync.WaitGroup
+}
+
+type legKey struct {
+	provider string
+	symbol   string
+}
+
+type legRef struct {
+	instrument *instrument
+	index      int
+}
+
+type quote struct {
+	last decimal.Decimal
+	bid  decimal.Decimal
+	ask  decimal.Decimal
+	ts   time.Time
+}
+
+type instrument struct {
+	cfg    config.SyntheticInstrumentConfig
+	coef   []decimal.Decimal
+	quotes []*quote
+	seq    uint64
+}
+
+// New builds an engine for the configured synthetic instruments.
+func New(cfgs []config.SyntheticInstrumentConfig, bus eventbus.Bus, pools *pool.PoolManager, opts ...Option) *Engine {
+	e := &Engine{
+		bus:         bus,
+		pools:       pools,
+		logger:      log.New(redact.Stdout, "synthetic ", log.LstdFlags|log.Lmicroseconds),
+		registrar:   nil,
+		clock:       time.Now,
+		mu:          sync.Mutex{},
+		instruments: make([]*instrument, 0, len(cfgs)),
+		byLeg:       make(map[legKey][]legRef),
+		wg:          sync.WaitGroup{},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(e)
+		}
+	}
+	for _, cfg := range cfgs {
+		inst := &instrument{
+			cfg:    cfg,
+			coef:   cfg.Coefficients(),
+			quotes: make([]*quote, len(cfg.Legs)),
+			seq:    0,
+		}
+		e.instruments = append(e.instruments, inst)
+		for i, leg := range cfg.Legs {
+			key := legKey{provider: leg.Provider, symbol: leg.Symbol}
+			e.byLeg[key] = append(e.byLeg[key], legRef{instrument: inst, index: i})
+		}
+	}
+	return e
+}
+
+// Start registers the leg routes and consumes ticker events until ctx is cancelled.
+func (e *Engine) Start(ctx context.Context) error {
+	if e == nil || len(e.instruments) == 0 {
+		return nil
+	}
+	if e.bus == nil {
+		return errors.New("synthetic: event bus not configured")
+	}
+	if e.registrar != nil {
+		for _, inst := range e.instruments {
+			providers, routes := legRoutes(inst.cfg)
+			if err := e.registrar.RegisterLambda(ctx, RegistrationPrefix+inst.cfg.Symbol, providers, routes); err != nil {
+				return fmt.Errorf("synthetic %s: register leg routes: %w", inst.cfg.Symbol, err)
+			}
+		}
+	}
+	id, ch, err := e.bus.Subscribe(ctx, schema.EventTypeTicker)
+	if err != nil {
+		return fmt.Errorf("synthetic: subscribe tickers: %w", err)
+	}
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		defer e.bus.Unsubscribe(id)
+		e.consume(ctx, ch)
+	}()
+	e.logger.Printf("synthetic instruments started: %d", len(e.instruments))
+	return nil
+}
+
+// Wait blocks until the consumer stops after ctx cancellation.
+func (e *Engine) Wait() {
+	if e == nil {
+		return
+	}
+	e.wg.Wait()
+}
+
+func (e *Engine) consume(ctx context.Context, ch <-chan *schema.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-ch:
+			if !ok {
+				return
+			}
+			if evt == nil {
+				continue
+			}
+			tickers := e.Apply(evt)
+			if e.pools != nil {
+				e.pools.TryReturnEventInst(evt)
+			}
+			for _, ticker := range tickers {
+				e.publish(ctx, ticker)
+			}
+		}
+	}
+}
+
+// Ticker is a computed synthetic ticker ready to publish.
+type Ticker struct {
+	Provider string
+	Symbol   string
+	Seq      uint64
+	Payload  schema.TickerPayload
+}
+
+// Apply records a leg ticker and returns the synthetic tickers it updates. Instruments whose
+// legs have not all quoted yet produce nothing.
+func (e *Engine) Apply(evt *schema.Event) []Ticker {
+	if evt == nil || evt.Type != schema.EventTypeTicker {
+		return nil
+	}
+	payload, ok := tickerPayload(evt.Payload)
+	if !ok {
+		return nil
+	}
+	key := legKey{provider: strings.TrimSpace(evt.Provider), symbol: strings.ToUpper(strings.TrimSpace(evt.Symbol))}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	refs := e.byLeg[key]
+	if len(refs) == 0 {
+		return nil
+	}
+	q := &quote{
+		last: parseDecimal(payload.LastPrice),
+		bid:  parseDecimal(payload.BidPrice),
+		ask:  parseDecimal(payload.AskPrice),
+		ts:   payload.Timestamp,
+	}
+	if q.ts.IsZero() {
+		q.ts = evt.EmitTS
+	}
+	out := make([]Ticker, 0, len(refs))
+	for _, ref := range refs {
+		ref.instrument.quotes[ref.index] = q
+		if ticker, ok := ref.instrument.compute(); ok {
+			out = append(out, ticker)
+		}
+	}
+	return out
+}
+
+func (inst *instrument) compute() (Ticker, bool) {
+	var last, bid, ask decimal.Decimal
+	quoted := true
+	var ts time.Time
+	for i, q := range inst.quotes {
+		if q == nil {
+			return Ticker{}, false
+		}
+		price := q.last
+		if price.IsZero() && !q.bid.IsZero() && !q.ask.IsZero() {
+			price = q.bid.Add(q.ask).Div(decimal.NewFromInt(2))
+		}
+		if price.IsZero() {
+			return Ticker{}, false
+		}
+		coef := inst.coef[i]
+		last = last.Add(coef.Mul(price))
+		if q.bid.IsZero() || q.ask.IsZero() {
+			quoted = false
+		} else if coef.IsNegative() {
+			// A short leg contributes its ask to the synthetic bid and its bid to the synthetic ask.
+			bid = bid.Add(coef.Mul(q.ask))
+			ask = ask.Add(coef.Mul(q.bid))
+		} else {
+			bid = bid.Add(coef.Mul(q.bid))
+			ask = ask.Add(coef.Mul(q.ask))
+		}
+		if q.ts.After(ts) {
+			ts = q.ts
+		}
+	}
+	inst.seq++
+	payload := schema.TickerPayload{
+		LastPrice: last.String(),
+		BidPrice:  "",
+		AskPrice:  "",
+		Volume24h: "",
+		Timestamp: ts,
+	}
+	if quoted {
+		payload.BidPrice = bid.String()
+		payload.AskPrice = ask.String()
+	}
+	return Ticker{Provider: inst.cfg.Provider, Symbol: inst.cfg.Symbol, Seq: inst.seq, Payload: payload}, true
+}
+
+func (e *Engine) publish(ctx context.Context, ticker Ticker) {
+	if e.pools == nil {
+		return
+	}
+	evt, err := e.pools.BorrowEventInst(ctx)
+	if err != nil {
+		e.logger.Printf("synthetic %s: ticker skipped: %v", ticker.Symbol, err)
+		return
+	}
+	now := e.clock().UTC()
+	emit := ticker.Payload.Timestamp
+	if emit.IsZero() {
+		emit = now
+	}
+	evt.EventID = fmt.Sprintf("%s%s:%s:%d", RegistrationPrefix, ticker.Provider, ticker.Symbol, ticker.Seq)
+	evt.Provider = ticker.Provider
+	evt.Symbol = ticker.Symbol
+	evt.Type = schema.EventTypeTicker
+	evt.SeqProvider = ticker.Seq
+	evt.IngestTS = now
+	evt.EmitTS = emit
+	evt.Payload = ticker.Payload
+	if err := e.bus.Publish(ctx, evt); err != nil {
+		e.logger.Printf("synthetic %s: publish ticker: %v", ticker.Symbol, err)
+		e.pools.ReturnEventInst(evt)
+	}
+}
+
+// legRoutes builds the ticker route declaration covering every leg, keyed per provider.
+func legRoutes(cfg config.SyntheticInstrumentConfig) ([]string, []dispatcher.RouteDeclaration) {
+	symbols := make(map[string][]string)
+	providers := make([]string, 0, len(cfg.Legs))
+	for _, leg := range cfg.Legs {
+		if _, ok := symbols[leg.Provider]; !ok {
+			providers = append(providers, leg.Provider)
+		}
+		symbols[leg.Provider] = append(symbols[leg.Provider], leg.Symbol)
+	}
+	filters := make(map[string]any, len(symbols))
+	for provider, list := range symbols {
+		filters["instrument@"+strings.ToLower(provider)] = list
+	}
+	return providers, []dispatcher.RouteDeclaration{{Type: schema.RouteTypeTicker, Filters: filters, Book: dispatcher.BookOptions{Depth: 0, Interval: 0}}}
+}
+
+func tickerPayload(raw any) (schema.TickerPayload, bool) {
+	switch v := raw.(type) {
+	case schema.TickerPayload:
+		return v, true
+	case *schema.TickerPayload:
+		if v != nil {
+			return *v, true
+		}
+	}
+	return schema.TickerPayload{}, false
+}
+
+func parseDecimal(raw string) decimal.Decimal {
+	value, err := decimal.NewFromString(strings.TrimSpace(raw))
+	if err != nil {
+		return decimal.Zero
+	}
+	return value
+}
@@ -0,0 +1,168 @@
+package synthetic
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+var (
+	spreadConfig = config.SyntheticInstrumentConfig{
+		Symbol:   "BTC-ETH-SPREAD",
+		Provider: "binance",
+		Kind:     config.SyntheticKindSpread,
+		Legs: []config.SyntheticLeg{
+			{Provider: "binance", Symbol: "BTC-USDT", Weight: ""},
+			{Provider: "okx", Symbol: "ETH-USDT", Weight: "20"},
+		},
+	}
+	basketConfig = config.SyntheticInstrumentConfig{
+		Symbol:   "MAJORS",
+		Provider: "binance",
+		Kind:     config.SyntheticKindBasket,
+		Legs: []config.SyntheticLeg{
+			{Provider: "binance", Symbol: "BTC-USDT", Weight: "0.5"},
+			{Provider: "binance", Symbol: "SOL-USDT", Weight: "10"},
+		},
+	}
+)
+
+func tickerEvent(provider, symbol, last, bid, ask string, ts time.Time) *schema.Event {
+	return &schema.Event{
+		Type:     schema.EventTypeTicker,
+		Provider: provider,
+		Symbol:   symbol,
+		EmitTS:   ts,
+		Payload:  schema.TickerPayload{LastPrice: last, BidPrice: bid, AskPrice: ask, Volume24h: "1", Timestamp: ts},
+	}
+}
+
+func TestApplyComputesSpreadAndBasket(t *testing.T) {
+	engine := New([]config.SyntheticInstrumentConfig{spreadConfig, basketConfig}, nil, nil, WithLogger(log.New(io.Discard, "", 0)))
+	t0 := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := engine.Apply(tickerEvent("binance", "BTC-USDT", "60000", "59990", "60010", t0)); len(got) != 0 {
+		t.Fatalf("expected no synthetic ticker before all legs quote, got %+v", got)
+	}
+	if got := engine.Apply(tickerEvent("binance", "ETH-USDT", "3000", "2999", "3001", t0)); len(got) != 0 {
+		t.Fatalf("leg on another provider must not match, got %+v", got)
+	}
+
+	got := engine.Apply(tickerEvent("okx", "ETH-USDT", "2900", "2899", "2901", t0.Add(time.Second)))
+	if len(got) != 1 {
+		t.Fatalf("expected spread ticker, got %+v", got)
+	}
+	spread := got[0]
+	if spread.Provider != "binance" || spread.Symbol != "BTC-ETH-SPREAD" || spread.Seq != 1 {
+		t.Fatalf("unexpected spread identity %+v", spread)
+	}
+	// 60000 - 20*2900; bid = 59990 - 20*2901; ask = 60010 - 20*2899.
+	want := schema.TickerPayload{LastPrice: "2000", BidPrice: "1970", AskPrice: "2030", Volume24h: "", Timestamp: t0.Add(time.Second)}
+	if spread.Payload != want {
+		t.Fatalf("spread payload = %+v, want %+v", spread.Payload, want)
+	}
+
+	got = engine.Apply(tickerEvent("binance", "SOL-USDT", "150", "", "", t0))
+	if len(got) != 1 || got[0].Symbol != "MAJORS" {
+		t.Fatalf("expected basket ticker, got %+v", got)
+	}
+	basket := got[0].Payload
+	if basket.LastPrice != "31500" || basket.BidPrice != "" || basket.AskPrice != "" {
+		t.Fatalf("basket payload = %+v; want last 31500 without a quote", basket)
+	}
+
+	got = engine.Apply(tickerEvent("binance", "BTC-USDT", "61000", "60990", "61010", t0.Add(2*time.Second)))
+	if len(got) != 2 {
+		t.Fatalf("shared leg should update both instruments, got %+v", got)
+	}
+	if got[0].Payload.LastPrice != "3000" || got[0].Seq != 2 {
+		t.Fatalf("updated spread = %+v", got[0])
+	}
+}
+
+type fakeBus struct {
+	mu        sync.Mutex
+	sub       chan *schema.Event
+	published chan *schema.Event
+}
+
+func (b *fakeBus) Publish(_ context.Context, evt *schema.Event) error {
+	b.published <- evt
+	return nil
+}
+
+func (b *fakeBus) Subscribe(_ context.Context, _ schema.EventType) (eventbus.SubscriptionID, <-chan *schema.Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return eventbus.SubscriptionID("tickers"), b.sub, nil
+}
+
+func (b *fakeBus) Unsubscribe(eventbus.SubscriptionID) {}
+func (b *fakeBus) Close()                              {}
+
+type recordingRegistrar struct {
+	providers map[string][]string
+	routes    map[string][]dispatcher.RouteDeclaration
+}
+
+func (r *recordingRegistrar) RegisterLambda(_ context.Context, id string, providers []string, routes []dispatcher.RouteDeclaration) error {
+	r.providers[id] = providers
+	r.routes[id] = routes
+	return nil
+}
+
+func TestEngineRegistersLegsAndPublishes(t *testing.T) {
+	pools := pool.NewPoolManager()
+	if err := pools.RegisterPool("Event", 16, 0, func() any { return &schema.Event{} }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	bus := &fakeBus{mu: sync.Mutex{}, sub: make(chan *schema.Event, 4), published: make(chan *schema.Event, 4)}
+	registrar := &recordingRegistrar{providers: make(map[string][]string), routes: make(map[string][]dispatcher.RouteDeclaration)}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	engine := New([]config.SyntheticInstrumentConfig{spreadConfig}, bus, pools,
+		WithRegistrar(registrar), WithClock(func() time.Time { return now }), WithLogger(log.New(io.Discard, "", 0)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := engine.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	id := RegistrationPrefix + "BTC-ETH-SPREAD"
+	if providers := registrar.providers[id]; len(providers) != 2 || providers[0] != "binance" || providers[1] != "okx" {
+		t.Fatalf("registered providers = %v", providers)
+	}
+	routes := registrar.routes[id]
+	if len(routes) != 1 || routes[0].Type != schema.RouteTypeTicker {
+		t.Fatalf("registered routes = %+v", routes)
+	}
+	if legs, ok := routes[0].Filters["instrument@okx"].([]string); !ok || len(legs) != 1 || legs[0] != "ETH-USDT" {
+		t.Fatalf("okx leg filter = %v", routes[0].Filters)
+	}
+
+	bus.sub <- tickerEvent("binance", "BTC-USDT", "60000", "", "", now)
+	bus.sub <- tickerEvent("okx", "ETH-USDT", "2900", "", "", now)
+	select {
+	case evt := <-bus.published:
+		if evt.Type != schema.EventTypeTicker || evt.Provider != "binance" || evt.Symbol != "BTC-ETH-SPREAD" {
+			t.Fatalf("unexpected published event %+v", evt)
+		}
+		if evt.EventID != "synthetic:binance:BTC-ETH-SPREAD:1" || !evt.IngestTS.Equal(now) {
+			t.Fatalf("unexpected event identity %s at %s", evt.EventID, evt.IngestTS)
+		}
+		if payload, ok := evt.Payload.(schema.TickerPayload); !ok || payload.LastPrice != "2000" {
+			t.Fatalf("unexpected payload %+v", evt.Payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected synthetic ticker to be published")
+	}
+	cancel()
+	engine.Wait()
+}
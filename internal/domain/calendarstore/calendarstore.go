@@ -0,0 +1,35 @@
+// Package calendarstore defines persistence contracts for scheduled calendar entries.
+package calendarstore
+
+import (
+	"context"
+	"time"
+)
+
+// AuditRecord captures one lifecycle transition of a calendar entry.
+type AuditRecord struct {
+	Time    time.Time `json:"time"`
+	Stage   string    `json:"stage"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Entry is a persisted calendar entry. Action holds the scheduled action fields keyed by name.
+type Entry struct {
+	ID          string
+	Title       string
+	ScheduledAt time.Time
+	NotifyAt    *time.Time
+	Action      map[string]any
+	Status      string
+	NotifiedAt  *time.Time
+	AppliedAt   *time.Time
+	Error       string
+	Audit       []AuditRecord
+	CreatedAt   time.Time
+}
+
+// Store abstracts persistence operations for calendar entries.
+type Store interface {
+	SaveEntry(ctx context.Context, entry Entry) error
+	LoadEntries(ctx context.Context) ([]Entry, error)
+}
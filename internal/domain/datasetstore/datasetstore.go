@@ -0,0 +1,150 @@
+// Package datasetstore defines storage contracts for named historical datasets replayed by
+// backtests.
+package datasetstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Format identifies the file format of a dataset.
+type Format string
+
+const (
+	// FormatCSV marks kline or trade CSV files as written by fetchdata.
+	FormatCSV Format = "csv"
+	// FormatParquet marks Apache Parquet files.
+	FormatParquet Format = "parquet"
+)
+
+var (
+	// ErrNotFound is returned when no dataset has the requested identifier.
+	ErrNotFound = errors.New("dataset not found")
+	// ErrNameTaken is returned when another dataset already uses the name.
+	ErrNameTaken = errors.New("dataset name already in use")
+)
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// ParseFormat normalizes a format name. An empty name selects FormatCSV.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", string(FormatCSV):
+		return FormatCSV, nil
+	case string(FormatParquet):
+		return FormatParquet, nil
+	default:
+		return "", fmt.Errorf("unknown dataset format %q", name)
+	}
+}
+
+// Dataset describes a stored dataset. Provider and Symbol name the market data it holds so
+// backtests can replay it without path conventions.
+type Dataset struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Format Format `json:"format"`
+	// Kind is the market data of CSV datasets, klines or trades.
+	Kind      string    `json:"kind,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	Symbol    string    `json:"symbol,omitempty"`
+	Tags      []string  `json:"tags"`
+	SizeBytes int64     `json:"sizeBytes"`
+	SHA256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Query filters listed datasets. Empty fields match every dataset.
+type Query struct {
+	Tag      string
+	Provider string
+	Symbol   string
+}
+
+// Matches reports whether dataset passes the query.
+func (q Query) Matches(dataset Dataset) bool {
+	if q.Provider != "" && !strings.EqualFold(q.Provider, dataset.Provider) {
+		return false
+	}
+	if q.Symbol != "" && !strings.EqualFold(q.Symbol, dataset.Symbol) {
+		return false
+	}
+	if q.Tag == "" {
+		return true
+	}
+	for _, tag := range dataset.Tags {
+		if tag == q.Tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Store persists datasets and their content.
+type Store interface {
+	// Create stores content under a new identifier. Size, checksum and timestamps are filled in.
+	Create(ctx context.Context, dataset Dataset, content io.Reader) (Dataset, error)
+	List(ctx context.Context, query Query) ([]Dataset, error)
+	Get(ctx context.Context, id string) (Dataset, error)
+	// Open returns the content of a dataset; callers close it.
+	Open(ctx context.Context, id string) (io.ReadCloser, Dataset, error)
+	// SetTags replaces the tags of a dataset.
+	SetTags(ctx context.Context, id string, tags []string) (Dataset, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// Normalize validates the caller-supplied fields of dataset and trims them in place.
+func Normalize(dataset *Dataset) error {
+	dataset.Name = strings.TrimSpace(dataset.Name)
+	if !namePattern.MatchString(dataset.Name) {
+		return fmt.Errorf("dataset name must be 1-128 letters, digits, '.', '_' or '-'")
+	}
+	format, err := ParseFormat(string(dataset.Format))
+	if err != nil {
+		return err
+	}
+	dataset.Format = format
+	dataset.Provider = strings.ToLower(strings.TrimSpace(dataset.Provider))
+	dataset.Symbol = strings.ToUpper(strings.TrimSpace(dataset.Symbol))
+	dataset.Tags = NormalizeTags(dataset.Tags)
+	return nil
+}
+
+// NormalizeTags trims, de-duplicates and sorts tags, dropping empty ones.
+func NormalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		trimmed := strings.TrimSpace(tag)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		out = append(out, trimmed)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// FileName is the name of the content file of a dataset in format.
+func FileName(format Format) string {
+	return "data." + string(format)
+}
+
+// ContentType is the media type of dataset content in format.
+func ContentType(format Format) string {
+	if format == FormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv"
+}
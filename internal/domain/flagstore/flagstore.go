@@ -0,0 +1,21 @@
+// Package flagstore defines persistence contracts for runtime feature flag overrides.
+package flagstore
+
+import (
+	"context"
+	"time"
+)
+
+// Flag is a persisted runtime override of a feature flag.
+type Flag struct {
+	Name      string
+	Enabled   bool
+	UpdatedAt time.Time
+}
+
+// Store abstracts persistence operations for feature flag overrides.
+type Store interface {
+	SaveFlag(ctx context.Context, flag Flag) error
+	DeleteFlag(ctx context.Context, name string) error
+	LoadFlags(ctx context.Context) ([]Flag, error)
+}
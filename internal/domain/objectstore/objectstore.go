@@ -0,0 +1,49 @@
+// Package objectstore defines the object storage contract shared by components that keep their
+// data in an S3-compatible bucket instead of the gateway host's filesystem.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound reports a missing object.
+var ErrNotFound = errors.New("object not found")
+
+// Object describes a stored object. Keys are relative to the bucket's prefix and use forward slashes.
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Bucket reads and writes objects under one key prefix of a bucket.
+type Bucket interface {
+	// List returns the objects whose keys start with prefix, in key order.
+	List(ctx context.Context, prefix string) ([]Object, error)
+	// Get opens an object for reading; the caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	// Put stores size bytes from body under key, replacing any existing object. A negative size
+	// reads body to its end.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete removes an object; deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// ReadAll fetches a whole object.
+func ReadAll(ctx context.Context, bucket Bucket, key string) ([]byte, error) {
+	body, _, err := bucket.Get(ctx, key)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // callers see the bucket's own error, ErrNotFound included
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // read errors come straight from the bucket
+	}
+	return data, nil
+}
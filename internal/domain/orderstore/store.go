@@ -53,6 +53,47 @@ type BalanceSnapshot struct {
 	Metadata   map[string]any `json:"metadata,omitempty"`
 }
 
+// FillsMetadataKey is the order metadata key holding the order's FillSummary.
+const FillsMetadataKey = "fills"
+
+// FillSummary rolls up the executions of an order: the cumulative filled quantity, the
+// quantity-weighted average fill price and the commission paid per asset.
+type FillSummary struct {
+	FilledQuantity string            `json:"filledQuantity"`
+	AvgFillPrice   string            `json:"avgFillPrice"`
+	Commissions    map[string]string `json:"commissions,omitempty"`
+	Fills          int               `json:"fills"`
+}
+
+// FillSummaryFromMetadata reads the FillSummary stored under FillsMetadataKey, whether held as a
+// value or decoded from JSON. It returns nil when the order has no fills.
+func FillSummaryFromMetadata(metadata map[string]any) *FillSummary {
+	switch raw := metadata[FillsMetadataKey].(type) {
+	case FillSummary:
+		return &raw
+	case *FillSummary:
+		return raw
+	case map[string]any:
+		summary := FillSummary{FilledQuantity: "", AvgFillPrice: "", Commissions: nil, Fills: 0}
+		summary.FilledQuantity, _ = raw["filledQuantity"].(string)
+		summary.AvgFillPrice, _ = raw["avgFillPrice"].(string)
+		if count, ok := raw["fills"].(float64); ok {
+			summary.Fills = int(count)
+		}
+		if commissions, ok := raw["commissions"].(map[string]any); ok && len(commissions) > 0 {
+			summary.Commissions = make(map[string]string, len(commissions))
+			for asset, amount := range commissions {
+				if text, ok := amount.(string); ok {
+					summary.Commissions[asset] = text
+				}
+			}
+		}
+		return &summary
+	default:
+		return nil
+	}
+}
+
 // OrderRecord represents a stored order enriched with timestamps.
 type OrderRecord struct {
 	Order
@@ -60,6 +101,8 @@ type OrderRecord struct {
 	CompletedAt    *int64 `json:"completedAt,omitempty"`
 	CreatedAt      int64  `json:"createdAt"`
 	UpdatedAt      int64  `json:"updatedAt"`
+	// Fills is the roll-up of the order's executions, read from its metadata.
+	Fills *FillSummary `json:"fills,omitempty"`
 }
 
 // ExecutionRecord represents a stored execution enriched with metadata.
@@ -76,12 +119,24 @@ type BalanceRecord struct {
 	UpdatedAt int64 `json:"updatedAt"`
 }
 
+// OpenOrderSnapshot records the orders still open on a provider when the gateway shut down.
+type OpenOrderSnapshot struct {
+	Provider string        `json:"provider"`
+	TakenAt  int64         `json:"takenAt"`
+	Orders   []OrderRecord `json:"orders"`
+}
+
 // OrderQuery scopes order lookups.
 type OrderQuery struct {
 	StrategyInstance string   `json:"strategyInstance"`
 	Provider         string   `json:"provider,omitempty"`
 	States           []string `json:"states,omitempty"`
-	Limit            int      `json:"limit,omitempty"`
+	// Since and Until bound placement time in Unix seconds (inclusive, exclusive); zero is unbounded.
+	Since int64 `json:"since,omitempty"`
+	Until int64 `json:"until,omitempty"`
+	// Tag keeps only orders whose metadata tags contain the value.
+	Tag   string `json:"tag,omitempty"`
+	Limit int    `json:"limit,omitempty"`
 }
 
 // ExecutionQuery scopes execution lookups.
@@ -89,7 +144,12 @@ type ExecutionQuery struct {
 	StrategyInstance string `json:"strategyInstance"`
 	Provider         string `json:"provider,omitempty"`
 	OrderID          string `json:"orderId,omitempty"`
-	Limit            int    `json:"limit,omitempty"`
+	// Since and Until bound trade time in Unix seconds (inclusive, exclusive); zero is unbounded.
+	Since int64 `json:"since,omitempty"`
+	Until int64 `json:"until,omitempty"`
+	// Tag keeps only executions of orders whose metadata tags contain the value.
+	Tag   string `json:"tag,omitempty"`
+	Limit int    `json:"limit,omitempty"`
 }
 
 // BalanceQuery scopes balance lookups.
@@ -115,3 +175,11 @@ type Store interface {
 	ListExecutions(ctx context.Context, query ExecutionQuery) ([]ExecutionRecord, error)
 	ListBalances(ctx context.Context, query BalanceQuery) ([]BalanceRecord, error)
 }
+
+// SnapshotStore persists the open order snapshots taken on shutdown until they are reconciled on
+// the next startup.
+type SnapshotStore interface {
+	SaveOpenOrderSnapshot(ctx context.Context, snapshot OpenOrderSnapshot) error
+	LoadOpenOrderSnapshots(ctx context.Context) ([]OpenOrderSnapshot, error)
+	DeleteOpenOrderSnapshot(ctx context.Context, provider string) error
+}
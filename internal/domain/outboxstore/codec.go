@@ -0,0 +1,85 @@
+package outboxstore
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec marks how an outbox row stores its payload.
+type Codec string
+
+const (
+	// CodecIdentity stores the payload as plain JSON.
+	CodecIdentity Codec = "identity"
+	// CodecZstd stores the payload compressed with zstd.
+	CodecZstd Codec = "zstd"
+)
+
+// ParseCodec normalizes a configured codec name. An empty name or "none" selects CodecIdentity.
+func ParseCodec(name string) (Codec, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "none", string(CodecIdentity):
+		return CodecIdentity, nil
+	case string(CodecZstd):
+		return CodecZstd, nil
+	default:
+		return "", fmt.Errorf("unknown outbox codec %q", name)
+	}
+}
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+func zstdCoders() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// Compress encodes payload with codec. CodecIdentity returns the payload unchanged.
+func Compress(codec Codec, payload []byte) ([]byte, error) {
+	switch codec {
+	case "", CodecIdentity:
+		return payload, nil
+	case CodecZstd:
+		encoder, _, err := zstdCoders()
+		if err != nil {
+			return nil, fmt.Errorf("zstd encoder: %w", err)
+		}
+		return encoder.EncodeAll(payload, make([]byte, 0, len(payload)/2)), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox codec %q", codec)
+	}
+}
+
+// Decompress reverses Compress for a payload stored with codec.
+func Decompress(codec Codec, data []byte) ([]byte, error) {
+	switch codec {
+	case "", CodecIdentity:
+		return data, nil
+	case CodecZstd:
+		_, decoder, err := zstdCoders()
+		if err != nil {
+			return nil, fmt.Errorf("zstd decoder: %w", err)
+		}
+		payload, err := decoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("zstd decode: %w", err)
+		}
+		return payload, nil
+	default:
+		return nil, fmt.Errorf("unknown outbox codec %q", codec)
+	}
+}
@@ -3,19 +3,24 @@ package outboxstore
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	json "github.com/goccy/go-json"
 )
 
+// ErrEventNotFound reports an outbox identifier with no stored entry.
+var ErrEventNotFound = errors.New("outbox event not found")
+
 // Event encapsulates a single outbox entry ready to be enqueued.
 type Event struct {
 	AggregateType string
 	AggregateID   string
 	EventType     string
-	Payload       json.RawMessage
-	Headers       map[string]any
-	AvailableAt   time.Time
+	// Payload may live in a reused buffer; stores copy it and must not retain it after Enqueue.
+	Payload     json.RawMessage
+	Headers     map[string]any
+	AvailableAt time.Time
 }
 
 // EventRecord captures the persisted state of an outbox entry.
@@ -24,14 +29,16 @@ type EventRecord struct {
 	AggregateType string
 	AggregateID   string
 	EventType     string
-	Payload       json.RawMessage
-	Headers       map[string]any
-	AvailableAt   time.Time
-	PublishedAt   *time.Time
-	Attempts      int
-	LastError     string
-	Delivered     bool
-	CreatedAt     time.Time
+	// Payload is always the decoded JSON; PayloadCodec records how the row stores it.
+	Payload      json.RawMessage
+	PayloadCodec Codec
+	Headers      map[string]any
+	AvailableAt  time.Time
+	PublishedAt  *time.Time
+	Attempts     int
+	LastError    string
+	Delivered    bool
+	CreatedAt    time.Time
 }
 
 // Store abstracts persistence operations for the outbox.
@@ -42,3 +49,54 @@ type Store interface {
 	MarkFailed(ctx context.Context, id int64, lastError string) error
 	Delete(ctx context.Context, id int64) error
 }
+
+// EventQuery scopes outbox browsing by identifier, creation time and routing headers. Empty
+// filters match everything.
+type EventQuery struct {
+	// AfterID returns only entries with a larger identifier, for keyset pagination.
+	AfterID int64
+	// Since and Until bound the creation time (inclusive, exclusive).
+	Since      time.Time
+	Until      time.Time
+	EventTypes []string
+	// Providers matches the provider header recorded when the event was enqueued.
+	Providers []string
+	Limit     int
+}
+
+// EventLister is implemented by outbox stores that can browse historical entries.
+type EventLister interface {
+	// ListEvents returns entries matching the query ordered by identifier.
+	ListEvents(ctx context.Context, query EventQuery) ([]EventRecord, error)
+}
+
+// EventBrowser is implemented by outbox stores that let operators inspect and requeue entries.
+// Requeued entries return to pending and are re-published by the durable bus replay worker.
+type EventBrowser interface {
+	EventLister
+	// GetEvent returns the entry with the identifier or ErrEventNotFound.
+	GetEvent(ctx context.Context, id int64) (EventRecord, error)
+	// Requeue marks the entry undelivered and available now, or returns ErrEventNotFound.
+	Requeue(ctx context.Context, id int64) (EventRecord, error)
+	// RequeueEvents requeues up to query.Limit entries matching the query and returns their
+	// identifiers in ascending order.
+	RequeueEvents(ctx context.Context, query EventQuery) ([]int64, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// SubscriptionStore tracks per-subscriber offsets against the outbox so durable subscribers
+// can resume from the last event they processed.
+type SubscriptionStore interface {
+	// LoadOffset returns the last committed outbox identifier for the subscriber. The boolean
+	// reports whether an offset has been recorded.
+	LoadOffset(ctx context.Context, subscriber string) (int64, bool, error)
+	// CommitOffset advances the subscriber offset. Offsets never move backwards.
+	CommitOffset(ctx context.Context, subscriber string, offset int64) error
+	// DeleteOffset forgets the subscriber offset.
+	DeleteOffset(ctx context.Context, subscriber string) error
+	// ListAfter returns events with identifiers greater than afterID, filtered by event type
+	// and ordered by identifier.
+	ListAfter(ctx context.Context, afterID int64, eventTypes []string, limit int) ([]EventRecord, error)
+	// LatestID returns the highest identifier currently stored in the outbox.
+	LatestID(ctx context.Context) (int64, error)
+}
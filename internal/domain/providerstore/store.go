@@ -24,6 +24,9 @@ type RouteSnapshot struct {
 	WSTopics []string
 	RestFns  []RouteRestFn
 	Filters  []RouteFilter
+	// BookDepth and BookInterval mirror the dispatcher order book options.
+	BookDepth    int
+	BookInterval time.Duration
 }
 
 // RouteRestFn mirrors dispatcher REST polling configuration.
@@ -39,6 +42,8 @@ type RouteFilter struct {
 	Field string
 	Op    string
 	Value any
+	Not   bool
+	AnyOf [][]RouteFilter
 }
 
 // Store abstracts persistence operations for provider specifications.
@@ -0,0 +1,22 @@
+// Package riskstore defines persistence contracts for named risk profiles.
+package riskstore
+
+import (
+	"context"
+	"time"
+)
+
+// Profile is a stored risk preset. Limits holds the risk configuration fields keyed by name.
+type Profile struct {
+	Name        string
+	Description string
+	Limits      map[string]any
+	UpdatedAt   time.Time
+}
+
+// Store abstracts persistence operations for risk profiles.
+type Store interface {
+	SaveProfile(ctx context.Context, profile Profile) error
+	DeleteProfile(ctx context.Context, name string) error
+	LoadProfiles(ctx context.Context) ([]Profile, error)
+}
@@ -87,6 +87,9 @@ func cloneBookSnapshotPayload(payload BookSnapshotPayload) BookSnapshotPayload {
 func cloneInstrumentUpdatePayload(payload InstrumentUpdatePayload) InstrumentUpdatePayload {
 	cloned := payload
 	cloned.Instrument = CloneInstrument(payload.Instrument)
+	if len(payload.Changes) > 0 {
+		cloned.Changes = append([]InstrumentChange(nil), payload.Changes...)
+	}
 	return cloned
 }
 
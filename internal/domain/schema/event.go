@@ -48,6 +48,7 @@ var (
 	eventTypeToRoutes = map[EventType]RouteType{
 		EventTypeBalanceUpdate:    RouteTypeAccountBalance,
 		EventTypeBookSnapshot:     RouteTypeOrderbookSnapshot,
+		EventTypeBookDelta:        RouteTypeOrderbookSnapshot,
 		EventTypeTrade:            RouteTypeTrade,
 		EventTypeTicker:           RouteTypeTicker,
 		EventTypeExecReport:       RouteTypeExecutionReport,
@@ -181,9 +182,22 @@ type Event struct {
 	Symbol         string    `json:"symbol"`
 	Type           EventType `json:"type"`
 	SeqProvider    uint64    `json:"seqProvider"`
-	IngestTS       time.Time `json:"ingestTs"`
-	EmitTS         time.Time `json:"emitTs"`
-	Payload        any       `json:"payload"`
+	// EventTS is the venue timestamp of the event (event time); zero when the venue supplied none.
+	EventTS time.Time `json:"eventTs"`
+	// IngestTS is when the gateway received the event (processing time).
+	IngestTS time.Time `json:"ingestTs"`
+	// EmitTS is the timestamp strategies observe: the event time when known, otherwise the
+	// processing time.
+	EmitTS time.Time `json:"emitTs"`
+	// Watermark is the event-time watermark of the provider when the event was published. Events
+	// with an event time before it are late. Zero when the bus does not track watermarks.
+	Watermark time.Time `json:"watermark"`
+	// Late marks an event whose event time is behind the provider watermark.
+	Late    bool `json:"late,omitempty"`
+	Payload any  `json:"payload"`
+	// OutboxID carries the durable outbox sequence assigned to the event, or zero when the
+	// event was not persisted. It is used for subscription offset tracking only.
+	OutboxID int64 `json:"-"`
 }
 
 // Reset zeroes the event for pool reuse.
@@ -197,9 +211,13 @@ func (e *Event) Reset() {
 	e.Symbol = ""
 	e.Type = ""
 	e.SeqProvider = 0
+	e.EventTS = time.Time{}
 	e.IngestTS = time.Time{}
 	e.EmitTS = time.Time{}
+	e.Watermark = time.Time{}
+	e.Late = false
 	e.Payload = nil
+	e.OutboxID = 0
 }
 
 // SetReturned toggles the ownership flag for pooling.
@@ -226,6 +244,9 @@ const (
 	// NOTE: Adapters MUST always emit full orderbooks, never deltas.
 	// Exchange-specific delta handling should be done within the adapter.
 	EventTypeBookSnapshot EventType = "BookSnapshot"
+	// EventTypeBookDelta identifies incremental book updates the lambda host derives from
+	// snapshots for strategies that opt in. It is never published on the bus.
+	EventTypeBookDelta EventType = "BookDelta"
 	// EventTypeTrade identifies trade executions.
 	EventTypeTrade EventType = "Trade"
 	// EventTypeTicker identifies ticker summary events.
@@ -265,6 +286,16 @@ type BookSnapshotPayload struct {
 	FinalUpdateID uint64 `json:"finalUpdateId,omitempty"` // u - Final update ID in event
 }
 
+// BookDeltaPayload carries the price levels of an instrument that changed since the previous delta
+// delivered to the strategy; a quantity of "0" removes the level. Reset marks the first delta of an
+// instrument, whose levels are the whole book.
+type BookDeltaPayload struct {
+	Bids       []PriceLevel `json:"bids"`
+	Asks       []PriceLevel `json:"asks"`
+	Reset      bool         `json:"reset"`
+	LastUpdate time.Time    `json:"lastUpdate"`
+}
+
 // TradeSide captures the direction of a trade.
 type TradeSide string
 
@@ -335,6 +366,7 @@ type ExecReportPayload struct {
 	AvgFillPrice     string          `json:"avgFillPrice"`
 	CommissionAmount string          `json:"commissionAmount,omitempty"`
 	CommissionAsset  string          `json:"commissionAsset,omitempty"`
+	SubAccount       string          `json:"subAccount,omitempty"`
 	Timestamp        time.Time       `json:"timestamp"`
 	RejectReason     *string         `json:"rejectReason,omitempty"`
 }
@@ -353,14 +385,20 @@ type KlineSummaryPayload struct {
 // InstrumentUpdatePayload advertises an updated instrument definition for a provider.
 type InstrumentUpdatePayload struct {
 	Instrument Instrument `json:"instrument"`
+	// PreviousStatus is set when the update was triggered by a trading status change.
+	PreviousStatus InstrumentStatus `json:"previousStatus,omitempty"`
+	// Changes lists the attributes that differ from the previous catalogue refresh; it is empty
+	// for the initial catalogue and for periodic re-publication of unchanged instruments.
+	Changes []InstrumentChange `json:"changes,omitempty"`
 }
 
 // BalanceUpdatePayload reports the current account balance for a given currency.
 type BalanceUpdatePayload struct {
-	Currency  string    `json:"currency"`
-	Total     string    `json:"total"`
-	Available string    `json:"available"`
-	Timestamp time.Time `json:"timestamp"`
+	Currency   string    `json:"currency"`
+	Total      string    `json:"total"`
+	Available  string    `json:"available"`
+	SubAccount string    `json:"subAccount,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
 }
 
 // RiskControlStatus enumerates state transitions for risk notifications.
@@ -92,6 +92,10 @@ func TestEventRouteMappings(t *testing.T) {
 		}
 	}
 
+	if routes := RoutesForEvent(EventTypeBookDelta); len(routes) != 1 || routes[0] != RouteTypeOrderbookSnapshot {
+		t.Fatalf("RoutesForEvent(BookDelta) expected the orderbook route, got %v", routes)
+	}
+
 	if _, ok := EventTypeForRoute(RouteType("UNKNOWN.ROUTE")); ok {
 		t.Fatal("EventTypeForRoute should fail for unknown route")
 	}
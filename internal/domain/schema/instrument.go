@@ -41,6 +41,30 @@ type Instrument struct {
 	MinNotional       string         `json:"minNotional,omitempty"`
 	MinQuantity       string         `json:"minQuantity,omitempty"`
 	MaxQuantity       string         `json:"maxQuantity,omitempty"`
+	// Status reports the venue trading state; empty means the adapter does not track it.
+	Status InstrumentStatus `json:"status,omitempty"`
+}
+
+// InstrumentStatus describes whether an instrument currently accepts orders on its venue.
+type InstrumentStatus string
+
+const (
+	// InstrumentStatusTrading indicates continuous trading.
+	InstrumentStatusTrading InstrumentStatus = "trading"
+	// InstrumentStatusAuction indicates a pre/post-trading or auction phase without continuous matching.
+	InstrumentStatusAuction InstrumentStatus = "auction"
+	// InstrumentStatusBreak indicates the venue has temporarily paused the instrument.
+	InstrumentStatusBreak InstrumentStatus = "break"
+	// InstrumentStatusHalted indicates trading has been halted by the venue.
+	InstrumentStatusHalted InstrumentStatus = "halted"
+	// InstrumentStatusDelisted indicates the instrument is no longer listed by the venue.
+	InstrumentStatusDelisted InstrumentStatus = "delisted"
+)
+
+// Tradable reports whether orders may be routed for an instrument in this status.
+// An unknown (empty) status is treated as tradable.
+func (s InstrumentStatus) Tradable() bool {
+	return s == "" || s == InstrumentStatusTrading
 }
 
 // InstrumentType identifies the market structure for an instrument.
@@ -573,3 +597,82 @@ func CloneInstruments(list []Instrument) []Instrument {
 	}
 	return out
 }
+
+// InstrumentChangeListed is the change field reported for an instrument that first appears in a
+// refreshed catalogue.
+const InstrumentChangeListed = "listed"
+
+// InstrumentChange describes one attribute of an instrument that differs between two catalogue
+// refreshes. Field is the JSON name of the attribute; empty values mean the attribute was unset.
+type InstrumentChange struct {
+	Field    string `json:"field"`
+	Previous string `json:"previous,omitempty"`
+	Current  string `json:"current,omitempty"`
+}
+
+// String renders the change as "field previous -> current" for logs.
+func (c InstrumentChange) String() string {
+	previous, current := c.Previous, c.Current
+	if previous == "" {
+		previous = "<unset>"
+	}
+	if current == "" {
+		current = "<unset>"
+	}
+	return c.Field + " " + previous + " -> " + current
+}
+
+// DiffInstruments lists the trading rules and lifecycle attributes that differ between two
+// definitions of the same instrument, such as tick size, lot size, notional filters and status.
+// Decimal attributes are compared numerically so "0.10" and "0.1" are equal.
+func DiffInstruments(previous, current Instrument) []InstrumentChange {
+	var changes []InstrumentChange
+	add := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, InstrumentChange{Field: field, Previous: before, Current: after})
+		}
+	}
+	addDecimal := func(field, before, after string) {
+		if decimalEqual(before, after) {
+			return
+		}
+		add(field, before, after)
+	}
+	add("status", string(previous.Status), string(current.Status))
+	add("type", string(previous.Type), string(current.Type))
+	add("expiry", previous.Expiry, current.Expiry)
+	addDecimal("priceIncrement", previous.PriceIncrement, current.PriceIncrement)
+	addDecimal("quantityIncrement", previous.QuantityIncrement, current.QuantityIncrement)
+	add("pricePrecision", formatOptionalInt(previous.PricePrecision), formatOptionalInt(current.PricePrecision))
+	add("quantityPrecision", formatOptionalInt(previous.QuantityPrecision), formatOptionalInt(current.QuantityPrecision))
+	add("notionalPrecision", formatOptionalInt(previous.NotionalPrecision), formatOptionalInt(current.NotionalPrecision))
+	addDecimal("minNotional", previous.MinNotional, current.MinNotional)
+	addDecimal("minQuantity", previous.MinQuantity, current.MinQuantity)
+	addDecimal("maxQuantity", previous.MaxQuantity, current.MaxQuantity)
+	add("contractValue", formatOptionalFloat(previous.ContractValue), formatOptionalFloat(current.ContractValue))
+	return changes
+}
+
+func decimalEqual(a, b string) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if a == b {
+		return true
+	}
+	left, okLeft := new(big.Rat).SetString(a)
+	right, okRight := new(big.Rat).SetString(b)
+	return okLeft && okRight && left.Cmp(right) == 0
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
@@ -308,3 +308,43 @@ func TestInstrumentValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffInstruments(t *testing.T) {
+	previous := Instrument{
+		Symbol:            "BTC-USDT",
+		Type:              InstrumentTypeSpot,
+		PriceIncrement:    "0.01",
+		QuantityIncrement: "0.0001",
+		PricePrecision:    intPtr(2),
+		MinNotional:       "5",
+		Status:            InstrumentStatusTrading,
+	}
+	current := CloneInstrument(previous)
+	current.PriceIncrement = "0.010"
+	if changes := DiffInstruments(previous, current); len(changes) != 0 {
+		t.Fatalf("expected numerically equal increments to match, got %+v", changes)
+	}
+
+	current.PriceIncrement = "0.1"
+	current.PricePrecision = intPtr(1)
+	current.MaxQuantity = "100"
+	current.Status = InstrumentStatusHalted
+	changes := DiffInstruments(previous, current)
+	want := []InstrumentChange{
+		{Field: "status", Previous: "trading", Current: "halted"},
+		{Field: "priceIncrement", Previous: "0.01", Current: "0.1"},
+		{Field: "pricePrecision", Previous: "2", Current: "1"},
+		{Field: "maxQuantity", Previous: "", Current: "100"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("DiffInstruments() = %+v, want %+v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("change %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+	if got := changes[3].String(); got != "maxQuantity <unset> -> 100" {
+		t.Fatalf("String() = %q", got)
+	}
+}
@@ -1,20 +1,76 @@
 package schema
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // OrderRequest represents an order submission from a consumer.
 type OrderRequest struct {
 	returned      bool
-	ClientOrderID string    `json:"clientOrderId"`
-	ConsumerID    string    `json:"consumerId"`
-	Provider      string    `json:"provider"`
-	Symbol        string    `json:"symbol"`
-	Side          TradeSide `json:"side"`
-	OrderType     OrderType `json:"orderType"`
-	Price         *string   `json:"price,omitempty"`
-	Quantity      string    `json:"quantity"`
-	TIF           string    `json:"tif"`
-	Timestamp     time.Time `json:"timestamp"`
+	ClientOrderID string      `json:"clientOrderId"`
+	ConsumerID    string      `json:"consumerId"`
+	Provider      string      `json:"provider"`
+	Symbol        string      `json:"symbol"`
+	Side          TradeSide   `json:"side"`
+	OrderType     OrderType   `json:"orderType"`
+	Price         *string     `json:"price,omitempty"`
+	Quantity      string      `json:"quantity"`
+	TIF           TimeInForce `json:"tif"`
+	// PostOnly orders are rejected rather than taking liquidity. ReduceOnly orders may only shrink
+	// the position on their symbol. Venues without native support are emulated by the gateway.
+	PostOnly   bool   `json:"postOnly,omitempty"`
+	ReduceOnly bool   `json:"reduceOnly,omitempty"`
+	SubAccount string `json:"subAccount,omitempty"`
+	// Tags and Metadata attribute the order to a signal, leg or parent algo. They are persisted
+	// with the order and echoed on its executions.
+	Tags      []string          `json:"tags,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// TimeInForce controls how long an order stays on the book.
+type TimeInForce string
+
+const (
+	// TimeInForceGTC rests until filled or cancelled.
+	TimeInForceGTC TimeInForce = "GTC"
+	// TimeInForceIOC fills what it can immediately and cancels the rest.
+	TimeInForceIOC TimeInForce = "IOC"
+	// TimeInForceFOK fills completely and immediately or not at all.
+	TimeInForceFOK TimeInForce = "FOK"
+	// TimeInForceGTX (post-only) rests like GTC but is rejected instead of taking liquidity.
+	TimeInForceGTX TimeInForce = "GTX"
+)
+
+// TimeInForces lists the supported time-in-force values.
+var TimeInForces = []TimeInForce{TimeInForceGTC, TimeInForceIOC, TimeInForceFOK, TimeInForceGTX}
+
+// ParseTimeInForce normalizes a time-in-force, accepting POST_ONLY and its spellings as GTX.
+// An empty value yields an empty time-in-force, leaving the default to the order type.
+func ParseTimeInForce(raw string) (TimeInForce, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(raw))
+	switch normalized {
+	case "":
+		return "", nil
+	case "POST_ONLY", "POST-ONLY", "POSTONLY":
+		return TimeInForceGTX, nil
+	}
+	for _, tif := range TimeInForces {
+		if TimeInForce(normalized) == tif {
+			return tif, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported time in force %q", raw)
+}
+
+// DefaultTimeInForce is GTC for limit orders and IOC for market orders.
+func DefaultTimeInForce(orderType OrderType) TimeInForce {
+	if orderType == OrderTypeMarket {
+		return TimeInForceIOC
+	}
+	return TimeInForceGTC
 }
 
 // Reset zeroes the order request for pool reuse.
@@ -31,6 +87,11 @@ func (o *OrderRequest) Reset() {
 	o.Price = nil
 	o.Quantity = ""
 	o.TIF = ""
+	o.PostOnly = false
+	o.ReduceOnly = false
+	o.SubAccount = ""
+	o.Tags = nil
+	o.Metadata = nil
 	o.Timestamp = time.Time{}
 }
 
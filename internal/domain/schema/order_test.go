@@ -70,3 +70,27 @@ func TestOrderRequestNilHandling(t *testing.T) {
 		t.Error("nil order should return false for IsReturned")
 	}
 }
+
+func TestParseTimeInForce(t *testing.T) {
+	cases := map[string]TimeInForce{
+		"":          "",
+		" gtc ":     TimeInForceGTC,
+		"ioc":       TimeInForceIOC,
+		"FOK":       TimeInForceFOK,
+		"gtx":       TimeInForceGTX,
+		"post_only": TimeInForceGTX,
+		"Post-Only": TimeInForceGTX,
+	}
+	for raw, want := range cases {
+		got, err := ParseTimeInForce(raw)
+		if err != nil || got != want {
+			t.Fatalf("%q: expected %q, got %q (%v)", raw, want, got, err)
+		}
+	}
+	if _, err := ParseTimeInForce("GTD"); err == nil {
+		t.Fatal("expected GTD rejected")
+	}
+	if DefaultTimeInForce(OrderTypeMarket) != TimeInForceIOC || DefaultTimeInForce(OrderTypeLimit) != TimeInForceGTC {
+		t.Fatal("unexpected default time in force")
+	}
+}
@@ -0,0 +1,88 @@
+// Package strategylogstore defines persistence contracts for strategy console logs and
+// diagnostics, kept so incidents can be analysed after the gateway restarted.
+package strategylogstore
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Log levels, from least to most severe.
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// Sources describe where a log line originated.
+const (
+	// SourceConsole marks console.* calls and helpers.log output of the strategy.
+	SourceConsole = "console"
+	// SourceRuntime marks handler exceptions and runtime errors reported by the instance.
+	SourceRuntime = "runtime"
+	// SourceValidation marks failures to compile, validate or start the strategy.
+	SourceValidation = "validation"
+)
+
+var severities = map[string]int{
+	LevelDebug: 0,
+	LevelInfo:  1,
+	LevelWarn:  2,
+	LevelError: 3,
+}
+
+// NormalizeLevel lower-cases level and maps aliases ("warning", "log") onto the canonical
+// names. It reports false for unknown levels.
+func NormalizeLevel(level string) (string, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(level))
+	switch normalized {
+	case "warning":
+		normalized = LevelWarn
+	case "log", "":
+		normalized = LevelInfo
+	}
+	if _, ok := severities[normalized]; !ok {
+		return "", false
+	}
+	return normalized, true
+}
+
+// Severity ranks level for minimum-level filtering; unknown levels rank as info.
+func Severity(level string) int {
+	if severity, ok := severities[level]; ok {
+		return severity
+	}
+	return severities[LevelInfo]
+}
+
+// Entry is one persisted strategy log line.
+type Entry struct {
+	ID         int64
+	InstanceID string
+	Strategy   string
+	Hash       string
+	Level      string
+	Source     string
+	Message    string
+	RecordedAt time.Time
+}
+
+// Query selects the log lines of one instance. A zero From returns the most recent lines,
+// otherwise lines are returned oldest first starting at From. MinLevel filters out less
+// severe lines.
+type Query struct {
+	InstanceID string
+	From       time.Time
+	MinLevel   string
+	Limit      int
+}
+
+// Store abstracts persistence of strategy log lines.
+type Store interface {
+	AppendLogs(ctx context.Context, entries []Entry) error
+	ListLogs(ctx context.Context, query Query) ([]Entry, error)
+	// PurgeLogs deletes lines recorded before the cutoff and returns how many were removed.
+	PurgeLogs(ctx context.Context, before time.Time) (int64, error)
+}
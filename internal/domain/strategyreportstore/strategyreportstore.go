@@ -0,0 +1,35 @@
+// Package strategyreportstore defines persistence contracts for the scheduled strategy usage and
+// registry reports.
+package strategyreportstore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when no report has the requested identifier.
+var ErrNotFound = errors.New("strategy report not found")
+
+// Entry is one persisted report. Report holds the JSON document and Summary a one-line digest
+// shown in listings.
+type Entry struct {
+	ID          int64
+	GeneratedAt time.Time
+	Summary     string
+	Report      []byte
+}
+
+// Store abstracts persistence of strategy reports.
+type Store interface {
+	// SaveReport stores a report and returns its identifier.
+	SaveReport(ctx context.Context, entry Entry) (int64, error)
+	// ListReports returns up to limit reports, newest first, without their documents.
+	ListReports(ctx context.Context, limit int) ([]Entry, error)
+	// Report returns the report with the identifier or ErrNotFound.
+	Report(ctx context.Context, id int64) (Entry, error)
+	// LatestReport returns the newest report or ErrNotFound.
+	LatestReport(ctx context.Context) (Entry, error)
+	// PurgeReports deletes reports generated before the cutoff and returns how many were removed.
+	PurgeReports(ctx context.Context, before time.Time) (int64, error)
+}
@@ -12,6 +12,9 @@ type Snapshot struct {
 	Strategy        Strategy
 	Providers       []string
 	ProviderSymbols map[string][]string
+	SubAccounts     map[string]string
+	Labels          map[string]string
+	DryRun          *bool
 	Running         bool
 	Dynamic         bool
 	Baseline        bool
@@ -34,3 +37,36 @@ type Store interface {
 	Delete(ctx context.Context, id string) error
 	Load(ctx context.Context) ([]Snapshot, error)
 }
+
+// Strategy history actions recorded for module lifecycle changes.
+const (
+	HistoryActionUpload      = "upload"
+	HistoryActionTagAssigned = "tag_assigned"
+	HistoryActionTagDeleted  = "tag_deleted"
+	HistoryActionDelete      = "delete"
+	HistoryActionImport      = "import"
+	// HistoryActionTradingEnabled and HistoryActionTradingDisabled record live trading toggles of
+	// an instance running the strategy; the instance ID is in the entry metadata.
+	HistoryActionTradingEnabled  = "trading_enabled"
+	HistoryActionTradingDisabled = "trading_disabled"
+)
+
+// HistoryEntry records a single change to a strategy's revisions or tags.
+type HistoryEntry struct {
+	ID           int64
+	Strategy     string
+	Action       string
+	Hash         string
+	Tag          string
+	PreviousHash string
+	Actor        string
+	Reason       string
+	Metadata     map[string]any
+	RecordedAt   time.Time
+}
+
+// HistoryStore persists the strategy changelog. Stores may optionally implement it.
+type HistoryStore interface {
+	AppendHistory(ctx context.Context, entry HistoryEntry) error
+	ListHistory(ctx context.Context, strategy string, limit int) ([]HistoryEntry, error)
+}
@@ -0,0 +1,40 @@
+// Package usagestore defines persistence contracts for the daily resource usage of strategy
+// instances, used to charge shared gateways back to the teams running them.
+package usagestore
+
+import (
+	"context"
+	"time"
+)
+
+// Record is the usage of one instance on one UTC day.
+type Record struct {
+	// Day is midnight UTC of the day the usage was metered.
+	Day      time.Time
+	Instance string
+	Strategy string
+	// Labels are the instance labels when the usage was last recorded.
+	Labels map[string]string
+	// Events counts the events handed to the strategy and Orders the orders it submitted.
+	Events int64
+	Orders int64
+	// HandlerTime is the time spent handling those events.
+	HandlerTime time.Duration
+}
+
+// Query selects the days to list, both inclusive.
+type Query struct {
+	From time.Time
+	To   time.Time
+}
+
+// Store abstracts persistence of daily instance usage.
+type Store interface {
+	// AddUsage adds the counters of each record to the stored day of its instance and replaces the
+	// stored strategy and labels.
+	AddUsage(ctx context.Context, records []Record) error
+	// ListUsage returns the stored days within the query, ordered by day and instance.
+	ListUsage(ctx context.Context, query Query) ([]Record, error)
+	// PurgeUsage deletes days before the cutoff and returns how many records were removed.
+	PurgeUsage(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,116 @@
+package binance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// tradingAccount holds the credentials and balance cache of one Binance account. The primary
+// account is unnamed and shares the provider's balance cache; sub-accounts carry their own keys.
+type tradingAccount struct {
+	name      string
+	apiKey    string
+	apiSecret string
+
+	balanceMu *sync.Mutex
+	balances  map[string]balanceSnapshot
+}
+
+func (a *tradingAccount) hasCredentials() bool {
+	return a != nil && strings.TrimSpace(a.apiKey) != "" && strings.TrimSpace(a.apiSecret) != ""
+}
+
+// label names the account in logs and errors.
+func (a *tradingAccount) label() string {
+	if a == nil || a.name == "" {
+		return "primary"
+	}
+	return "sub-account " + a.name
+}
+
+// buildAccounts assembles the primary account and every configured sub-account.
+func (p *Provider) buildAccounts() {
+	p.primary = &tradingAccount{
+		name:      "",
+		apiKey:    p.opts.Config.APIKey,
+		apiSecret: p.opts.Config.APISecret,
+		balanceMu: &p.balanceMu,
+		balances:  p.balances,
+	}
+	p.subAccounts = make(map[string]*tradingAccount, len(p.opts.Config.SubAccounts))
+	for name, creds := range p.opts.Config.SubAccounts {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		p.subAccounts[name] = &tradingAccount{
+			name:      name,
+			apiKey:    strings.TrimSpace(creds.APIKey),
+			apiSecret: strings.TrimSpace(creds.APISecret),
+			balanceMu: &sync.Mutex{},
+			balances:  make(map[string]balanceSnapshot),
+		}
+	}
+}
+
+// account resolves the account orders for the named sub-account are routed to.
+func (p *Provider) account(name string) (*tradingAccount, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return p.primary, nil
+	}
+	acct, ok := p.subAccounts[name]
+	if !ok {
+		return nil, fmt.Errorf("binance: unknown sub-account %q", name)
+	}
+	return acct, nil
+}
+
+// tradingAccounts returns every account with complete credentials, primary first.
+func (p *Provider) tradingAccounts() []*tradingAccount {
+	out := make([]*tradingAccount, 0, len(p.subAccounts)+1)
+	if p.primary.hasCredentials() {
+		out = append(out, p.primary)
+	}
+	names := make([]string, 0, len(p.subAccounts))
+	for name := range p.subAccounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if acct := p.subAccounts[name]; acct.hasCredentials() {
+			out = append(out, acct)
+		}
+	}
+	return out
+}
+
+// allAccounts returns the primary account followed by every sub-account.
+func (p *Provider) allAccounts() []*tradingAccount {
+	out := make([]*tradingAccount, 0, len(p.subAccounts)+1)
+	out = append(out, p.primary)
+	for _, acct := range p.subAccounts {
+		out = append(out, acct)
+	}
+	return out
+}
+
+// rememberOrderAccount records the account a client order was routed to so cancels follow it.
+func (p *Provider) rememberOrderAccount(clientOrderID string, acct *tradingAccount) {
+	if clientOrderID == "" || acct == nil || acct.name == "" {
+		return
+	}
+	p.orderAccounts.Store(clientOrderID, acct)
+}
+
+// orderAccount returns the account a client order was routed to, defaulting to the primary account.
+func (p *Provider) orderAccount(clientOrderID string) *tradingAccount {
+	if raw, ok := p.orderAccounts.Load(clientOrderID); ok {
+		if acct, ok := raw.(*tradingAccount); ok {
+			return acct
+		}
+	}
+	return p.primary
+}
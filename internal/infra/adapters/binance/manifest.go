@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
 	"github.com/coachpo/meltica/internal/infra/pool"
 )
 
@@ -38,9 +39,19 @@ func RegisterFactory(reg *provider.Registry) {
 		if raw, ok := stringFromConfig(userCfg, "api_secret"); ok {
 			opts.Config.APISecret = raw
 		}
+		if accounts, ok := mapFromConfig(userCfg, "sub_accounts"); ok {
+			subAccounts, err := subAccountsFromConfig(accounts)
+			if err != nil {
+				return nil, fmt.Errorf("binance sub_accounts: %w", err)
+			}
+			opts.Config.SubAccounts = subAccounts
+		}
 		if depth, ok := intFromConfig(userCfg, "snapshot_depth"); ok {
 			opts.Config.SnapshotDepth = depth
 		}
+		if budget, ok := intFromConfig(userCfg, "max_streams_per_connection"); ok {
+			opts.Config.MaxStreamsPerConnection = budget
+		}
 		if timeout, ok := durationFromConfig(userCfg, "http_timeout"); ok {
 			opts.Config.HTTPTimeout = timeout
 		}
@@ -53,6 +64,30 @@ func RegisterFactory(reg *provider.Registry) {
 		if keepAlive, ok := durationFromConfig(userCfg, "user_stream_keepalive"); ok {
 			opts.Config.UserStreamKeepAlive = keepAlive
 		}
+		if interval, ok := durationFromConfig(userCfg, "order_reconcile_interval"); ok {
+			opts.Config.OrderReconcile = interval
+		}
+		if raw, ok := stringFromConfig(userCfg, "api_base_url"); ok {
+			opts.Config.APIBaseURL = raw
+		}
+		if raw, ok := stringFromConfig(userCfg, "websocket_base_url"); ok {
+			opts.Config.WebsocketBaseURL = raw
+		}
+		endpoints, err := shared.EndpointsFromConfig(userCfg)
+		if err != nil {
+			return nil, fmt.Errorf("binance %w", err)
+		}
+		opts.Config.Endpoints = endpoints
+		reconnect, err := shared.ReconnectPolicyFromConfig(userCfg, shared.DefaultReconnectPolicy(binanceMaxReconnectInterval))
+		if err != nil {
+			return nil, fmt.Errorf("binance %w", err)
+		}
+		opts.Config.Reconnect = reconnect
+		proxy, err := shared.ProxyConfigFromConfig(userCfg)
+		if err != nil {
+			return nil, fmt.Errorf("binance %w", err)
+		}
+		opts.Config.Proxy = proxy
 
 		provider := NewProvider(opts)
 		if err := provider.Start(ctx); err != nil {
@@ -142,3 +177,34 @@ func mapFromConfig(cfg map[string]any, key string) (map[string]any, bool) {
 	}
 	return out, true
 }
+
+func subAccountsFromConfig(raw map[string]any) (map[string]SubAccount, error) {
+	out := make(map[string]SubAccount, len(raw))
+	for name := range raw {
+		trimmed := strings.TrimSpace(name)
+		if trimmed == "" {
+			return nil, fmt.Errorf("name required")
+		}
+		entry, ok := mapFromConfig(raw, name)
+		if !ok {
+			return nil, fmt.Errorf("%s: must be a mapping", trimmed)
+		}
+		apiKey, _ := stringFromConfig(entry, "api_key")
+		apiSecret, _ := stringFromConfig(entry, "api_secret")
+		if apiKey == "" || apiSecret == "" {
+			return nil, fmt.Errorf("%s: api_key and api_secret required", trimmed)
+		}
+		out[trimmed] = SubAccount{APIKey: apiKey, APISecret: apiSecret}
+	}
+	return out, nil
+}
+
+// validateSubAccounts checks the sub_accounts setting for the adapter settings schema.
+func validateSubAccounts(value any) error {
+	accounts, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected a mapping of named sub-accounts, got %T", value)
+	}
+	_, err := subAccountsFromConfig(accounts)
+	return err
+}
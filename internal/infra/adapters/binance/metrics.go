@@ -87,12 +87,14 @@ func newProviderMetrics(p *Provider) *providerMetrics {
 		metric.WithDescription("Total balances tracked for Binance account"),
 		metric.WithUnit("USD"),
 		metric.WithFloat64Callback(func(_ context.Context, observer metric.Float64Observer) error {
-			p.balanceMu.Lock()
-			defer p.balanceMu.Unlock()
-			for currency, snapshot := range p.balances {
-				total, _ := snapshot.total().Float64()
-				attrs := telemetry.BalanceAttributes(pm.environment, pm.provider, currency)
-				observer.Observe(total, metric.WithAttributes(attrs...))
+			for _, acct := range p.allAccounts() {
+				acct.balanceMu.Lock()
+				for currency, snapshot := range acct.balances {
+					total, _ := snapshot.total().Float64()
+					attrs := append(telemetry.BalanceAttributes(pm.environment, pm.provider, currency), telemetry.AttrSubAccount.String(acct.name))
+					observer.Observe(total, metric.WithAttributes(attrs...))
+				}
+				acct.balanceMu.Unlock()
 			}
 			return nil
 		}))
@@ -101,12 +103,14 @@ func newProviderMetrics(p *Provider) *providerMetrics {
 		metric.WithDescription("Available balances tracked for Binance account"),
 		metric.WithUnit("USD"),
 		metric.WithFloat64Callback(func(_ context.Context, observer metric.Float64Observer) error {
-			p.balanceMu.Lock()
-			defer p.balanceMu.Unlock()
-			for currency, snapshot := range p.balances {
-				available, _ := snapshot.free.Float64()
-				attrs := telemetry.BalanceAttributes(pm.environment, pm.provider, currency)
-				observer.Observe(available, metric.WithAttributes(attrs...))
+			for _, acct := range p.allAccounts() {
+				acct.balanceMu.Lock()
+				for currency, snapshot := range acct.balances {
+					available, _ := snapshot.free.Float64()
+					attrs := append(telemetry.BalanceAttributes(pm.environment, pm.provider, currency), telemetry.AttrSubAccount.String(acct.name))
+					observer.Observe(available, metric.WithAttributes(attrs...))
+				}
+				acct.balanceMu.Unlock()
 			}
 			return nil
 		}))
@@ -210,8 +214,8 @@ type streamMetrics struct {
 	environment string
 	provider    string
 	stream      string
+	connection  int
 
-	reconnects       metric.Int64Counter
 	controlMessages  metric.Int64Counter
 	messagesReceived metric.Int64Counter
 	messageBytes     metric.Int64Histogram
@@ -220,7 +224,7 @@ type streamMetrics struct {
 	subscriptions    metric.Int64UpDownCounter
 }
 
-func newStreamMetrics(provider, stream string) *streamMetrics {
+func newStreamMetrics(provider, stream string, connection int) *streamMetrics {
 	meter := otel.Meter("adapter.binance")
 	env := telemetry.Environment()
 	provider = strings.TrimSpace(provider)
@@ -232,7 +236,7 @@ func newStreamMetrics(provider, stream string) *streamMetrics {
 		environment:      env,
 		provider:         provider,
 		stream:           stream,
-		reconnects:       nil,
+		connection:       connection,
 		controlMessages:  nil,
 		messagesReceived: nil,
 		messageBytes:     nil,
@@ -241,10 +245,6 @@ func newStreamMetrics(provider, stream string) *streamMetrics {
 		subscriptions:    nil,
 	}
 
-	sm.reconnects, _ = meter.Int64Counter("meltica_provider_binance_ws_reconnects",
-		metric.WithDescription("Number of Binance websocket reconnect attempts"),
-		metric.WithUnit("{reconnect}"))
-
 	sm.controlMessages, _ = meter.Int64Counter("meltica_provider_binance_ws_control_messages",
 		metric.WithDescription("Control messages sent by Binance websocket stream managers"),
 		metric.WithUnit("{message}"))
@@ -280,21 +280,10 @@ func (sm *streamMetrics) baseAttrs() []attribute.KeyValue {
 		telemetry.AttrEnvironment.String(sm.environment),
 		telemetry.AttrProvider.String(sm.provider),
 		telemetry.AttrMessageType.String(sm.stream),
+		telemetry.AttrConnectionIndex.Int(sm.connection),
 	}
 }
 
-func (sm *streamMetrics) recordReconnect(ctx context.Context, result string) {
-	if sm == nil || sm.reconnects == nil {
-		return
-	}
-	ctx = ensureContext(ctx)
-	attrs := sm.baseAttrs()
-	if result != "" {
-		attrs = append(attrs, telemetry.AttrResult.String(result))
-	}
-	sm.reconnects.Add(ctx, 1, metric.WithAttributes(attrs...))
-}
-
 func (sm *streamMetrics) recordControl(ctx context.Context, method string, count int) {
 	if sm == nil || sm.controlMessages == nil || count == 0 {
 		return
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
 	"github.com/coachpo/meltica/internal/infra/pool"
 )
 
@@ -23,6 +25,9 @@ type privateMetadata struct {
 	listenKeyPath    string
 	accountInfoPath  string
 	orderPath        string
+	openOrdersPath   string
+	myTradesPath     string
+	pingPath         string
 }
 
 var binancePublicMetadata = publicMetadata{
@@ -40,6 +45,9 @@ var binancePrivateMetadata = privateMetadata{
 	listenKeyPath:    "/api/v3/userDataStream",
 	accountInfoPath:  "/api/v3/account",
 	orderPath:        "/api/v3/order",
+	openOrdersPath:   "/api/v3/openOrders",
+	myTradesPath:     "/api/v3/myTrades",
+	pingPath:         "/api/v3/ping",
 }
 
 var binanceAdapterMetadata = provider.AdapterMetadata{
@@ -48,14 +56,37 @@ var binanceAdapterMetadata = provider.AdapterMetadata{
 	Venue:        binancePublicMetadata.venue,
 	Description:  binancePublicMetadata.description,
 	Capabilities: []string{"market-data", "orders"},
+	TimeInForce:  schema.TimeInForces,
 	SettingsSchema: []provider.AdapterSetting{
-		{Name: "api_key", Type: "string", Description: "API key used for authenticated REST and user data streams", Default: "", Required: false},
-		{Name: "api_secret", Type: "string", Description: "API secret used to sign REST requests", Default: "", Required: false},
-		{Name: "snapshot_depth", Type: "int", Description: "Order book snapshot depth used when seeding local books", Default: defaultSnapshotDepth, Required: false},
-		{Name: "http_timeout", Type: "duration", Description: "HTTP client timeout for REST requests", Default: defaultHTTPTimeout.String(), Required: false},
-		{Name: "instrument_refresh_interval", Type: "duration", Description: "Interval between instrument metadata refreshes", Default: defaultInstrumentRefresh.String(), Required: false},
-		{Name: "recv_window", Type: "duration", Description: "REST recvWindow applied to signed requests", Default: defaultRecvWindow.String(), Required: false},
-		{Name: "user_stream_keepalive", Type: "duration", Description: "Interval between user data stream keepalive heartbeats", Default: defaultUserStreamKeepAlive.String(), Required: false},
+		{Name: "api_key", Type: "string", Description: "API key used for authenticated REST and user data streams", Default: "", Enum: nil, Required: false, Secret: true, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "api_secret", Type: "string", Description: "API secret used to sign REST requests", Default: "", Enum: nil, Required: false, Secret: true, Fields: nil, Keyed: false, Validate: nil},
+		{
+			Name:        "sub_accounts",
+			Type:        "map",
+			Description: "Named sub-accounts, each with api_key and api_secret, that strategy instances can route orders to",
+			Default:     map[string]any{},
+			Enum:        nil,
+			Required:    false,
+			Secret:      false,
+			Fields: []provider.AdapterSetting{
+				{Name: "api_key", Type: "string", Description: "Sub-account API key", Default: nil, Enum: nil, Required: true, Secret: true, Fields: nil, Keyed: false, Validate: nil},
+				{Name: "api_secret", Type: "string", Description: "Sub-account API secret", Default: nil, Enum: nil, Required: true, Secret: true, Fields: nil, Keyed: false, Validate: nil},
+			},
+			Keyed:    true,
+			Validate: validateSubAccounts,
+		},
+		{Name: "snapshot_depth", Type: "int", Description: "Order book snapshot depth used when seeding local books", Default: defaultSnapshotDepth, Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "max_streams_per_connection", Type: "int", Description: "Streams carried by one market data websocket connection before another is opened; capped at Binance's limit of 1024", Default: binanceMaxStreamsPerConnection, Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "http_timeout", Type: "duration", Description: "HTTP client timeout for REST requests", Default: defaultHTTPTimeout.String(), Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "instrument_refresh_interval", Type: "duration", Description: "Interval between instrument metadata refreshes", Default: defaultInstrumentRefresh.String(), Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "recv_window", Type: "duration", Description: "REST recvWindow applied to signed requests", Default: defaultRecvWindow.String(), Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "user_stream_keepalive", Type: "duration", Description: "Interval between user data stream keepalive heartbeats", Default: defaultUserStreamKeepAlive.String(), Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "order_reconcile_interval", Type: "duration", Description: "Interval between REST queries of open orders that backfill execution reports missed by the user data stream; they also run after every user data reconnect", Default: defaultOrderReconcile.String(), Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "api_base_url", Type: "string", Description: "Override of the REST base URL, e.g. for the testnet or a simulated exchange", Default: binancePrivateMetadata.apiBaseURL, Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "websocket_base_url", Type: "string", Description: "Override of the websocket base URL used for market and user data streams", Default: binancePrivateMetadata.websocketBaseURL, Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		provider.EndpointsSetting(),
+		provider.ReconnectSetting(shared.DefaultReconnectPolicy(binanceMaxReconnectInterval)),
+		provider.ProxySetting(),
 	},
 }
 
@@ -65,18 +96,35 @@ const (
 	defaultInstrumentRefresh   = 30 * time.Minute
 	defaultRecvWindow          = 5 * time.Second
 	defaultUserStreamKeepAlive = 15 * time.Minute
+	defaultOrderReconcile      = time.Minute
 )
 
+// SubAccount carries the credentials of a named Binance sub-account.
+type SubAccount struct {
+	APIKey    string
+	APISecret string
+}
+
 // Config captures user-overridable Binance settings.
 type Config struct {
-	Name                string
-	APIKey              string
-	APISecret           string
-	SnapshotDepth       int
-	HTTPTimeout         time.Duration
-	InstrumentRefresh   time.Duration
-	RecvWindow          time.Duration
-	UserStreamKeepAlive time.Duration
+	Name          string
+	APIKey        string
+	APISecret     string
+	SubAccounts   map[string]SubAccount
+	SnapshotDepth int
+	// MaxStreamsPerConnection is the per-connection stream budget of each market data stream kind.
+	MaxStreamsPerConnection int
+	HTTPTimeout             time.Duration
+	InstrumentRefresh       time.Duration
+	RecvWindow              time.Duration
+	UserStreamKeepAlive     time.Duration
+	OrderReconcile          time.Duration
+	Reconnect               shared.ReconnectPolicy
+	Proxy                   shared.ProxyConfig
+	APIBaseURL              string
+	WebsocketBaseURL        string
+	// Endpoints lists regional endpoints; when set the fastest healthy one replaces the base URLs.
+	Endpoints []shared.Endpoint
 }
 
 // Options configure the Binance adapter.
@@ -86,17 +134,27 @@ type Options struct {
 
 	privateMeta privateMetadata
 	publicMeta  publicMetadata
+	endpoints   *shared.EndpointSelector
 }
 
 func withDefaults(in Options) Options {
 	in.privateMeta = binancePrivateMetadata
 	in.publicMeta = binancePublicMetadata
+	if base := strings.TrimSpace(in.Config.APIBaseURL); base != "" {
+		in.privateMeta.apiBaseURL = base
+	}
+	if base := strings.TrimSpace(in.Config.WebsocketBaseURL); base != "" {
+		in.privateMeta.websocketBaseURL = base
+	}
 	if strings.TrimSpace(in.Config.Name) == "" {
 		in.Config.Name = in.publicMeta.identifier
 	}
 	if in.Config.SnapshotDepth <= 0 {
 		in.Config.SnapshotDepth = defaultSnapshotDepth
 	}
+	if in.Config.MaxStreamsPerConnection <= 0 || in.Config.MaxStreamsPerConnection > binanceMaxStreamsPerConnection {
+		in.Config.MaxStreamsPerConnection = binanceMaxStreamsPerConnection
+	}
 	if in.Config.HTTPTimeout <= 0 {
 		in.Config.HTTPTimeout = defaultHTTPTimeout
 	}
@@ -109,11 +167,17 @@ func withDefaults(in Options) Options {
 	if in.Config.UserStreamKeepAlive <= 0 {
 		in.Config.UserStreamKeepAlive = defaultUserStreamKeepAlive
 	}
+	if in.Config.OrderReconcile <= 0 {
+		in.Config.OrderReconcile = defaultOrderReconcile
+	}
+	if in.Config.Reconnect.Validate() != nil {
+		in.Config.Reconnect = shared.DefaultReconnectPolicy(binanceMaxReconnectInterval)
+	}
 	return in
 }
 
 func (o Options) restEndpoint(path string) string {
-	base := strings.TrimSuffix(strings.TrimSpace(o.privateMeta.apiBaseURL), "/")
+	base := strings.TrimSuffix(strings.TrimSpace(o.restBaseURL()), "/")
 	if base == "" {
 		return ""
 	}
@@ -126,6 +190,14 @@ func (o Options) restEndpoint(path string) string {
 	return base + "/" + path
 }
 
+// restBaseURL returns the REST URL of the selected regional endpoint, or the base URL without one.
+func (o Options) restBaseURL() string {
+	if endpoint, ok := o.endpoints.Current(); ok && endpoint.REST != "" {
+		return endpoint.REST
+	}
+	return o.privateMeta.apiBaseURL
+}
+
 func (o Options) exchangeInfoEndpoint() string {
 	return o.restEndpoint(o.privateMeta.exchangeInfoPath)
 }
@@ -146,6 +218,18 @@ func (o Options) orderEndpoint() string {
 	return o.restEndpoint(o.privateMeta.orderPath)
 }
 
+func (o Options) openOrdersEndpoint() string {
+	return o.restEndpoint(o.privateMeta.openOrdersPath)
+}
+
+func (o Options) myTradesEndpoint() string {
+	return o.restEndpoint(o.privateMeta.myTradesPath)
+}
+
+func (o Options) maxStreamsPerConnection() int {
+	return o.Config.MaxStreamsPerConnection
+}
+
 func (o Options) httpTimeoutDuration() time.Duration {
 	return o.Config.HTTPTimeout
 }
@@ -162,6 +246,17 @@ func (o Options) userStreamKeepAliveDuration() time.Duration {
 	return o.Config.UserStreamKeepAlive
 }
 
+func (o Options) orderReconcileDuration() time.Duration {
+	return o.Config.OrderReconcile
+}
+
+func (o Options) reconnectPolicy() shared.ReconnectPolicy {
+	return o.Config.Reconnect
+}
+
 func (o Options) websocketURL() string {
+	if endpoint, ok := o.endpoints.Current(); ok && endpoint.Websocket != "" {
+		return endpoint.Websocket
+	}
 	return o.privateMeta.websocketBaseURL
 }
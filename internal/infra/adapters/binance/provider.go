@@ -40,6 +40,7 @@ type Provider struct {
 	pools  *pool.PoolManager
 	clock  func() time.Time
 	client *http.Client
+	egress *shared.Egress
 
 	events chan *schema.Event
 	errs   chan error
@@ -51,20 +52,22 @@ type Provider struct {
 
 	publisher *shared.Publisher
 	metrics   *providerMetrics
+	streams   *shared.StreamStatsSet
 
 	instrumentsMu sync.RWMutex
 	instruments   map[string]schema.Instrument
-	symbols       map[string]symbolMeta // canonical symbol -> meta
-	restToCanon   map[string]string     // REST symbol -> canonical
+	symbols       map[string]symbolMeta       // canonical symbol -> meta
+	restToCanon   map[string]string           // REST symbol -> canonical
+	changes       *shared.InstrumentChangeLog // catalogue differences awaiting publication
 
 	tradeMu      sync.Mutex
-	tradeManager *streamManager
+	tradeManager *streamShards
 
 	tickerMu      sync.Mutex
-	tickerManager *streamManager
+	tickerManager *streamShards
 
 	bookMu      sync.Mutex
-	bookManager *streamManager
+	bookManager *streamShards
 	bookHandles map[string]*bookHandle
 
 	userStreamMu     sync.Mutex
@@ -73,6 +76,11 @@ type Provider struct {
 
 	balanceMu sync.Mutex
 	balances  map[string]balanceSnapshot
+
+	primary       *tradingAccount
+	subAccounts   map[string]*tradingAccount
+	orderAccounts sync.Map // client order ID -> *tradingAccount for sub-account orders
+	orders        *orderTracker
 }
 
 type bookHandle struct {
@@ -149,6 +157,7 @@ func NewProvider(opts Options) *Provider {
 		pools:            opts.Pools,
 		clock:            time.Now,
 		client:           nil,
+		egress:           shared.NewEgress(opts.Config.Proxy),
 		events:           make(chan *schema.Event, 2048),
 		errs:             make(chan error, 32),
 		ctx:              nil,
@@ -156,10 +165,12 @@ func NewProvider(opts Options) *Provider {
 		started:          atomic.Bool{},
 		publisher:        nil,
 		metrics:          nil,
+		streams:          shared.NewStreamStatsSet(binancePublicMetadata.identifier, opts.Config.Name),
 		instrumentsMu:    sync.RWMutex{},
 		instruments:      make(map[string]schema.Instrument),
 		symbols:          make(map[string]symbolMeta),
 		restToCanon:      make(map[string]string),
+		changes:          shared.NewInstrumentChangeLog(),
 		tradeMu:          sync.Mutex{},
 		tradeManager:     nil,
 		tickerMu:         sync.Mutex{},
@@ -172,13 +183,23 @@ func NewProvider(opts Options) *Provider {
 		userStreamWG:     sync.WaitGroup{},
 		balanceMu:        sync.Mutex{},
 		balances:         make(map[string]balanceSnapshot),
+		primary:          nil,
+		subAccounts:      nil,
+		orderAccounts:    sync.Map{},
+		orders:           newOrderTracker(),
 	}
 	if p.pools == nil {
 		log.Printf("binance/provider: Pools not injected; provider cannot start without shared PoolManager")
 		panic("binance/provider: nil PoolManager in options")
 	}
 	p.publisher = shared.NewPublisher(p.name, p.events, p.pools, p.clock)
+	// The probe client is built before the failure hook so failed probes do not trigger re-probes.
+	p.opts.endpoints = shared.NewEndpointSelector(opts.Config.Endpoints, shared.HTTPEndpointProbe(p.egress.HTTPClient(opts.httpTimeoutDuration()), opts.privateMeta.pingPath))
+	if p.opts.endpoints != nil {
+		p.egress.OnTransportFailure(p.reprobeEndpoints)
+	}
 	p.balances = make(map[string]balanceSnapshot)
+	p.buildAccounts()
 	p.metrics = newProviderMetrics(p)
 	return p
 }
@@ -204,6 +225,8 @@ func (p *Provider) Start(ctx context.Context) error {
 	p.ctx = runCtx
 	p.cancel = cancel
 
+	p.selectEndpoint(runCtx)
+
 	if err := p.refreshInstruments(runCtx); err != nil {
 		p.started.Store(false)
 		cancel()
@@ -219,13 +242,14 @@ func (p *Provider) Start(ctx context.Context) error {
 		return fmt.Errorf("init stream managers: %w", err)
 	}
 
-	if p.hasTradingCredentials() {
-		p.startUserDataStream()
-	} else if strings.TrimSpace(p.opts.Config.APIKey) == "" {
+	if strings.TrimSpace(p.opts.Config.APIKey) == "" {
 		log.Printf("binance/provider: API key not configured; private subscriptions (balances, execution reports) need API key and secret for %s", p.name)
 	} else if strings.TrimSpace(p.opts.Config.APISecret) == "" {
 		log.Printf("binance/provider: API secret not configured; private subscriptions (balances, execution reports) require both API key and secret for %s", p.name)
 	}
+	if len(p.tradingAccounts()) > 0 {
+		p.startUserDataStream()
+	}
 
 	p.startExtensionEmitter()
 
@@ -253,13 +277,166 @@ func (p *Provider) SubmitOrder(ctx context.Context, req schema.OrderRequest) err
 	if !ok {
 		return fmt.Errorf("binance: instrument %s not found", strings.TrimSpace(req.Symbol))
 	}
-	if strings.TrimSpace(p.opts.Config.APIKey) == "" || strings.TrimSpace(p.opts.Config.APISecret) == "" {
-		return fmt.Errorf("binance: trading disabled (api credentials missing)")
+	if status := p.instrumentStatus(meta.canonical); !status.Tradable() {
+		return fmt.Errorf("binance: instrument %s not tradable (status %s)", meta.canonical, status)
+	}
+	acct, err := p.account(req.SubAccount)
+	if err != nil {
+		return err
+	}
+	if !acct.hasCredentials() {
+		return fmt.Errorf("binance: trading disabled for %s (api credentials missing)", acct.label())
 	}
 	if ctx == nil {
 		ctx = p.ctx
 	}
-	return p.submitOrder(ctx, meta, req)
+	return p.submitOrder(ctx, acct, meta, req)
+}
+
+// CancelOpenOrders cancels every resting order for the instrument on Binance across the primary
+// account and all sub-accounts.
+func (p *Provider) CancelOpenOrders(ctx context.Context, symbol string) error {
+	accounts := p.tradingAccounts()
+	if len(accounts) == 0 {
+		return p.cancelOrders(ctx, p.primary, symbol, p.opts.openOrdersEndpoint(), nil)
+	}
+	var errs []error
+	for _, acct := range accounts {
+		if err := p.cancelOrders(ctx, acct, symbol, p.opts.openOrdersEndpoint(), nil); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", acct.label(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CancelOrder cancels a single resting order identified by its client order ID.
+func (p *Provider) CancelOrder(ctx context.Context, symbol, clientOrderID string) error {
+	clientOrderID = strings.TrimSpace(clientOrderID)
+	if clientOrderID == "" {
+		return errors.New("binance: client order id required")
+	}
+	params := url.Values{}
+	params.Set("origClientOrderId", clientOrderID)
+	return p.cancelOrders(ctx, p.orderAccount(clientOrderID), symbol, p.opts.orderEndpoint(), params)
+}
+
+// QueryOrder reports the venue state of an order identified by its client order ID. The order is
+// looked up on the named sub-account, or the primary account when subAccount is empty.
+func (p *Provider) QueryOrder(ctx context.Context, symbol, subAccount, clientOrderID string) (schema.ExecReportPayload, error) {
+	var payload schema.ExecReportPayload
+	if err := p.ensureRunning(); err != nil {
+		return payload, err
+	}
+	clientOrderID = strings.TrimSpace(clientOrderID)
+	if clientOrderID == "" {
+		return payload, errors.New("binance: client order id required")
+	}
+	meta, ok := p.metaForInstrument(symbol)
+	if !ok {
+		return payload, fmt.Errorf("binance: instrument %s not found", strings.TrimSpace(symbol))
+	}
+	acct, err := p.account(subAccount)
+	if err != nil {
+		return payload, err
+	}
+	if !acct.hasCredentials() {
+		return payload, fmt.Errorf("binance: trading disabled for %s (api credentials missing)", acct.label())
+	}
+	order, err := p.fetchOrder(ctx, acct, meta, clientOrderID)
+	if err != nil {
+		return payload, err
+	}
+	p.rememberOrderAccount(clientOrderID, acct)
+	side, err := binanceSideFromString(order.Side)
+	if err != nil {
+		return payload, err
+	}
+	orderType, err := binanceOrderTypeFromString(order.Type)
+	if err != nil {
+		return payload, err
+	}
+	avgPrice := strings.TrimSpace(order.AvgPrice)
+	if avgPrice == "" {
+		cumQuote := defaultIfEmpty(strings.TrimSpace(order.CummulativeQuoteQty), strings.TrimSpace(order.CumQuote))
+		avgPrice = calculateAveragePrice(cumQuote, order.ExecutedQty)
+	}
+	updatedAt := order.UpdateTime
+	if updatedAt <= 0 {
+		updatedAt = order.TransactTime
+	}
+	payload = schema.ExecReportPayload{
+		ClientOrderID:    clientOrderID,
+		ExchangeOrderID:  strconv.FormatInt(order.OrderID, 10),
+		State:            binanceStatusToExecState(order.Status),
+		Side:             side,
+		OrderType:        orderType,
+		Price:            strings.TrimSpace(order.Price),
+		Quantity:         strings.TrimSpace(order.OrigQty),
+		FilledQuantity:   strings.TrimSpace(order.ExecutedQty),
+		RemainingQty:     calculateRemaining(order.OrigQty, order.ExecutedQty),
+		AvgFillPrice:     avgPrice,
+		CommissionAmount: "",
+		CommissionAsset:  "",
+		SubAccount:       acct.name,
+		Timestamp:        resolveTimestamp(updatedAt, p.clock),
+		RejectReason:     nil,
+	}
+	return payload, nil
+}
+
+// cancelOrders issues a signed DELETE against endpoint for the instrument. Unknown-order
+// responses are treated as success because nothing remains resting.
+func (p *Provider) cancelOrders(ctx context.Context, acct *tradingAccount, symbol, endpoint string, params url.Values) error {
+	if err := p.ensureRunning(); err != nil {
+		return err
+	}
+	meta, ok := p.metaForInstrument(symbol)
+	if !ok {
+		return fmt.Errorf("binance: instrument %s not found", strings.TrimSpace(symbol))
+	}
+	if !acct.hasCredentials() {
+		return fmt.Errorf("binance: trading disabled for %s (api credentials missing)", acct.label())
+	}
+	if ctx == nil {
+		ctx = p.ctx
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, p.opts.httpTimeoutDuration())
+	defer cancel()
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("symbol", meta.rest)
+	if p.opts.recvWindowDuration() > 0 {
+		params.Set("recvWindow", strconv.FormatInt(p.opts.recvWindowDuration().Milliseconds(), 10))
+	}
+	params.Set("timestamp", strconv.FormatInt(p.clock().UTC().UnixMilli(), 10))
+	query := params.Encode()
+	query += "&signature=" + signPayload(query, acct.apiSecret)
+	if strings.TrimSpace(endpoint) == "" {
+		return errors.New("binance: cancel endpoint not configured")
+	}
+	httpReq, err := http.NewRequestWithContext(reqCtx, http.MethodDelete, endpoint+"?"+query, nil)
+	if err != nil {
+		return fmt.Errorf("create cancel request: %w", err)
+	}
+	httpReq.Header.Set("X-MBX-APIKEY", acct.apiKey)
+	resp, err := p.httpClient().Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("cancel orders: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		var apiErr binanceError
+		if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Code == binanceErrUnknownOrder {
+			// Nothing was resting for the request.
+			return nil
+		}
+		return parseOrderError(resp.StatusCode, body)
+	}
+	return nil
 }
 
 // Instruments returns the cached instrument catalogue.
@@ -288,7 +465,7 @@ func (p *Provider) SubscribeRoute(route dispatcher.Route) error {
 		return p.configureOrderBookStreams(instruments)
 	case schema.RouteTypeAccountBalance,
 		schema.RouteTypeExecutionReport:
-		if schema.RouteRequiresAuthentication(route.Type) && !p.hasTradingCredentials() {
+		if schema.RouteRequiresAuthentication(route.Type) && len(p.tradingAccounts()) == 0 {
 			if strings.TrimSpace(p.opts.Config.APIKey) == "" {
 				log.Printf("binance/provider: skipped %s subscription for %s because API key is not configured", route.Type, p.name)
 			} else if strings.TrimSpace(p.opts.Config.APISecret) == "" {
@@ -343,17 +520,42 @@ func (p *Provider) ensureRunning() error {
 
 func (p *Provider) httpClient() *http.Client {
 	if p.client == nil {
-		client := &http.Client{
-			Transport:     nil,
-			CheckRedirect: nil,
-			Jar:           nil,
-			Timeout:       p.opts.httpTimeoutDuration(),
-		}
-		p.client = client
+		p.client = p.egress.HTTPClient(p.opts.httpTimeoutDuration())
 	}
 	return p.client
 }
 
+// ConnectionDiagnostics reports REST and websocket connectivity, including the egress proxy and
+// the regional endpoint selection.
+func (p *Provider) ConnectionDiagnostics() shared.ConnectionDiagnostics {
+	diagnostics := p.egress.Diagnostics()
+	diagnostics.Endpoints = p.opts.endpoints.Status()
+	diagnostics.Streams = p.streams.Diagnostics()
+	return diagnostics
+}
+
+// selectEndpoint probes the regional endpoints and selects the fastest healthy one.
+func (p *Provider) selectEndpoint(ctx context.Context) {
+	if p.opts.endpoints == nil {
+		return
+	}
+	p.opts.endpoints.Probe(ctx)
+	if endpoint, ok := p.opts.endpoints.Current(); ok {
+		log.Printf("binance/provider: %s using %s endpoint %s", p.name, endpoint.Region, endpoint.REST)
+	}
+}
+
+// reprobeEndpoints re-evaluates the regional endpoints after a transport failure. REST requests
+// switch immediately; websocket streams move on their next reconnect.
+func (p *Provider) reprobeEndpoints(error) {
+	if p.ctx == nil {
+		return
+	}
+	p.opts.endpoints.ReportFailure(p.ctx, func(endpoint shared.Endpoint) {
+		log.Printf("binance/provider: %s switched to %s endpoint %s", p.name, endpoint.Region, endpoint.REST)
+	})
+}
+
 func (p *Provider) refreshInstruments(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, p.opts.httpTimeoutDuration())
 	defer cancel()
@@ -362,6 +564,7 @@ func (p *Provider) refreshInstruments(ctx context.Context) error {
 		return err
 	}
 	p.instrumentsMu.Lock()
+	previous := p.instruments
 	p.instruments = make(map[string]schema.Instrument, len(list))
 	p.symbols = metas
 	p.restToCanon = make(map[string]string, len(metas))
@@ -369,6 +572,14 @@ func (p *Provider) refreshInstruments(ctx context.Context) error {
 		cloned := schema.CloneInstrument(inst)
 		p.instruments[inst.Symbol] = cloned
 	}
+	for symbol, prev := range previous {
+		if _, ok := p.instruments[symbol]; !ok {
+			// Symbols dropped from exchangeInfo are retained as delisted so consumers see the transition.
+			prev.Status = schema.InstrumentStatusDelisted
+			p.instruments[symbol] = prev
+		}
+	}
+	p.changes.Record(previous, p.instruments)
 	for canonical, meta := range metas {
 		p.restToCanon[meta.rest] = canonical
 	}
@@ -393,11 +604,23 @@ func (p *Provider) instrumentRefreshLoop() {
 	}
 }
 
-func (p *Provider) publishInstrumentUpdates() {
+func (p *Provider) instrumentStatus(symbol string) schema.InstrumentStatus {
 	p.instrumentsMu.RLock()
 	defer p.instrumentsMu.RUnlock()
+	return p.instruments[symbol].Status
+}
+
+func (p *Provider) publishInstrumentUpdates() {
+	p.instrumentsMu.Lock()
+	defer p.instrumentsMu.Unlock()
 	for _, inst := range p.instruments {
-		payload := schema.InstrumentUpdatePayload{Instrument: schema.CloneInstrument(inst)}
+		payload := schema.InstrumentUpdatePayload{Instrument: schema.CloneInstrument(inst), PreviousStatus: "", Changes: p.changes.Take(inst.Symbol)}
+		if previous, changed := shared.PreviousStatus(payload.Changes); changed {
+			payload.PreviousStatus = previous
+		}
+		for _, change := range payload.Changes {
+			log.Printf("binance/provider: %s instrument %s changed: %s", p.name, inst.Symbol, change)
+		}
 		p.publisher.PublishInstrumentUpdate(p.ctx, inst.Symbol, payload)
 		if p.metrics != nil {
 			p.metrics.recordEvent(p.ctx, telemetry.EventTypeInstrumentUpdate, inst.Symbol)
@@ -439,6 +662,9 @@ func extractInstruments(filters []dispatcher.FilterRule, provider string) []stri
 	}
 
 	for _, filter := range filters {
+		if !filter.Selects() {
+			continue
+		}
 		field := strings.TrimSpace(filter.Field)
 		switch {
 		case strings.EqualFold(field, providerField):
@@ -586,7 +812,7 @@ func (p *Provider) configureOrderBookStreams(instruments []string) error {
 		// Create book handle if not exists
 		if _, exists := p.bookHandles[meta.canonical]; !exists {
 			handle := &bookHandle{
-				assembler: shared.NewOrderBookAssembler(p.opts.Config.SnapshotDepth),
+				assembler: shared.NewOrderBookAssembler(p.publishedBookDepth()),
 				seqMu:     sync.Mutex{},
 				lastSeq:   0,
 				seeded:    atomic.Bool{},
@@ -606,6 +832,27 @@ func (p *Provider) configureOrderBookStreams(instruments []string) error {
 	return nil
 }
 
+// ConfigureBookRoute applies the depth and cadence of the order book route. Local books keep every
+// level the venue sends; only the published snapshots are trimmed.
+func (p *Provider) ConfigureBookRoute(opts dispatcher.BookOptions) {
+	p.publisher.SetBookOptions(opts)
+	p.bookMu.Lock()
+	defer p.bookMu.Unlock()
+	depth := p.publishedBookDepth()
+	for _, handle := range p.bookHandles {
+		handle.assembler.SetDepth(depth)
+	}
+}
+
+// publishedBookDepth is the configured snapshot depth, narrowed by the order book route depth.
+func (p *Provider) publishedBookDepth() int {
+	depth := p.opts.Config.SnapshotDepth
+	if route := p.publisher.BookOptions().Depth; route > 0 && (depth <= 0 || route < depth) {
+		depth = route
+	}
+	return depth
+}
+
 func (p *Provider) unsubscribeOrderBookStreams(instruments []string) error {
 	p.bookMu.Lock()
 	defer p.bookMu.Unlock()
@@ -645,13 +892,13 @@ func (p *Provider) stopAllStreams() {
 }
 
 func (p *Provider) initStreamManagers(ctx context.Context) error {
-	baseURL := strings.TrimSuffix(p.opts.websocketURL(), "/") + "/ws"
+	baseURL := func() string { return strings.TrimSuffix(p.opts.websocketURL(), "/") + "/ws" }
 
 	// Trade stream handler
 	tradeHandler := func(data []byte) error {
 		var event tradeMessage
 		if err := json.Unmarshal(data, &event); err != nil {
-			return fmt.Errorf("decode trade message: %w", err)
+			return shared.DecodeError(fmt.Errorf("decode trade message: %w", err))
 		}
 		meta, ok := p.metaForRESTSymbol(event.Symbol)
 		if !ok {
@@ -675,7 +922,7 @@ func (p *Provider) initStreamManagers(ctx context.Context) error {
 	tickerHandler := func(data []byte) error {
 		var event tickerMessage
 		if err := json.Unmarshal(data, &event); err != nil {
-			return fmt.Errorf("decode ticker message: %w", err)
+			return shared.DecodeError(fmt.Errorf("decode ticker message: %w", err))
 		}
 		meta, ok := p.metaForRESTSymbol(event.Symbol)
 		if !ok {
@@ -699,7 +946,7 @@ func (p *Provider) initStreamManagers(ctx context.Context) error {
 	bookHandler := func(data []byte) error {
 		var diff depthDiffMessage
 		if err := json.Unmarshal(data, &diff); err != nil {
-			return fmt.Errorf("decode depth message: %w", err)
+			return shared.DecodeError(fmt.Errorf("decode depth message: %w", err))
 		}
 		meta, ok := p.metaForRESTSymbol(diff.Symbol)
 		if !ok {
@@ -710,7 +957,7 @@ func (p *Provider) initStreamManagers(ctx context.Context) error {
 		handle, exists := p.bookHandles[meta.canonical]
 		if !exists {
 			handle = &bookHandle{
-				assembler: shared.NewOrderBookAssembler(p.opts.Config.SnapshotDepth),
+				assembler: shared.NewOrderBookAssembler(p.publishedBookDepth()),
 				seqMu:     sync.Mutex{},
 				lastSeq:   0,
 				seeded:    atomic.Bool{},
@@ -742,29 +989,34 @@ func (p *Provider) initStreamManagers(ctx context.Context) error {
 		return nil
 	}
 
-	// Create stream managers
-	p.tradeManager = newStreamManager(ctx, baseURL, tradeHandler, p.errs, "trade", p.name)
+	// Create stream managers, each sharding its streams across connections as the budget requires
+	newShards := func(stream string, handler func([]byte) error) *streamShards {
+		return newStreamShards(stream, p.opts.maxStreamsPerConnection(), func(connection int) streamShard {
+			manager := newStreamManager(ctx, baseURL, handler, p.errs, stream, p.name, connection, p.opts.reconnectPolicy(), p.egress.WebsocketDialOptions())
+			manager.stats = p.streams.Stream(stream)
+			return manager
+		})
+	}
+
+	p.tradeManager = newShards("trade", tradeHandler)
 	if err := p.tradeManager.start(); err != nil {
 		return fmt.Errorf("start trade manager: %w", err)
 	}
 
-	p.tickerManager = newStreamManager(ctx, baseURL, tickerHandler, p.errs, "ticker", p.name)
+	p.tickerManager = newShards("ticker", tickerHandler)
 	if err := p.tickerManager.start(); err != nil {
 		return fmt.Errorf("start ticker manager: %w", err)
 	}
 
-	p.bookManager = newStreamManager(ctx, baseURL, bookHandler, p.errs, "orderbook", p.name)
+	p.bookManager = newShards("orderbook", bookHandler)
 	if err := p.bookManager.start(); err != nil {
 		return fmt.Errorf("start book manager: %w", err)
 	}
 
+	registerStreamShardMetrics(p.name, p.tradeManager, p.tickerManager, p.bookManager)
 	return nil
 }
 
-func (p *Provider) hasTradingCredentials() bool {
-	return strings.TrimSpace(p.opts.Config.APIKey) != "" && strings.TrimSpace(p.opts.Config.APISecret) != ""
-}
-
 func (p *Provider) startUserDataStream() {
 	p.userStreamMu.Lock()
 	defer p.userStreamMu.Unlock()
@@ -773,11 +1025,18 @@ func (p *Provider) startUserDataStream() {
 	}
 	ctx, cancel := context.WithCancel(p.ctx)
 	p.userStreamCancel = cancel
-	p.userStreamWG.Add(1)
-	go func() {
-		defer p.userStreamWG.Done()
-		p.runUserDataStream(ctx)
-	}()
+	for _, acct := range p.tradingAccounts() {
+		p.userStreamWG.Add(1)
+		go func(acct *tradingAccount) {
+			defer p.userStreamWG.Done()
+			p.runUserDataStream(ctx, acct)
+		}(acct)
+		p.userStreamWG.Add(1)
+		go func(acct *tradingAccount) {
+			defer p.userStreamWG.Done()
+			p.reconcileLoop(ctx, acct)
+		}(acct)
+	}
 }
 
 func (p *Provider) startExtensionEmitter() {
@@ -819,49 +1078,63 @@ func (p *Provider) stopUserDataStream() {
 	}
 }
 
-func (p *Provider) runUserDataStream(ctx context.Context) {
-	backoffCfg := backoff.NewExponentialBackOff()
+func (p *Provider) runUserDataStream(ctx context.Context, acct *tradingAccount) {
+	reconnect := shared.NewReconnector(p.opts.reconnectPolicy(), shared.NewReconnectMetrics(binancePublicMetadata.identifier, p.name, "user_data"))
+	reconnected := false
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
 		}
-		listenKey, err := p.createListenKey(ctx)
+		listenKey, err := p.createListenKey(ctx, acct)
 		if err != nil {
-			p.reportError(fmt.Errorf("binance listen key: %w", err))
-			sleep := backoffCfg.NextBackOff()
-			select {
-			case <-ctx.Done():
+			p.reportError(fmt.Errorf("binance listen key (%s): %w", acct.label(), err))
+			if !p.waitUserDataReconnect(ctx, reconnect, acct, shared.ReconnectReasonListenKey) {
 				return
-			case <-time.After(sleep):
 			}
 			continue
 		}
-		backoffCfg.Reset()
-		if err := p.publishBalanceSnapshot(ctx); err != nil {
-			p.reportError(fmt.Errorf("binance balance snapshot: %w", err))
+		reconnect.Connected(ctx)
+		if err := p.publishBalanceSnapshot(ctx, acct); err != nil {
+			p.reportError(fmt.Errorf("binance balance snapshot (%s): %w", acct.label(), err))
+		}
+		if reconnected {
+			// Execution reports sent while the stream was down are not replayed.
+			go p.reconcileOrders(ctx, acct)
 		}
-		err = p.consumeUserDataStream(ctx, listenKey)
+		reconnected = true
+		err = p.consumeUserDataStream(ctx, acct, listenKey)
 		if errors.Is(err, context.Canceled) {
 			return
 		}
+		reason := shared.ReconnectReasonClosed
 		if err != nil {
-			p.reportError(fmt.Errorf("binance user stream: %w", err))
+			reason = shared.ReconnectReasonStreamError
+			p.reportError(fmt.Errorf("binance user stream (%s): %w", acct.label(), err))
 		}
-		sleep := backoffCfg.NextBackOff()
-		select {
-		case <-ctx.Done():
+		if !p.waitUserDataReconnect(ctx, reconnect, acct, reason) {
 			return
-		case <-time.After(sleep):
 		}
 	}
 }
 
-func (p *Provider) consumeUserDataStream(ctx context.Context, listenKey string) error {
+// waitUserDataReconnect backs off before the next user data session and reports
+// when the stream should stop instead.
+func (p *Provider) waitUserDataReconnect(ctx context.Context, reconnect *shared.Reconnector, acct *tradingAccount, reason string) bool {
+	if err := reconnect.Wait(ctx, reason); err != nil {
+		if ctx.Err() == nil {
+			p.reportError(fmt.Errorf("binance user stream (%s): %w", acct.label(), err))
+		}
+		return false
+	}
+	return true
+}
+
+func (p *Provider) consumeUserDataStream(ctx context.Context, acct *tradingAccount, listenKey string) error {
 	base := strings.TrimSuffix(p.opts.websocketURL(), "/")
 	url := base + "/" + strings.TrimSpace(listenKey)
-	conn, _, err := websocket.Dial(ctx, url, nil)
+	conn, _, err := websocket.Dial(ctx, url, p.egress.WebsocketDialOptions())
 	if err != nil {
 		return fmt.Errorf("dial %s: %w", url, err)
 	}
@@ -882,8 +1155,8 @@ func (p *Provider) consumeUserDataStream(ctx context.Context, listenKey string)
 			case <-keepCtx.Done():
 				return
 			case <-ticker.C:
-				if err := p.keepAliveListenKey(keepCtx, listenKey); err != nil {
-					p.reportError(fmt.Errorf("binance listen key keepalive: %w", err))
+				if err := p.keepAliveListenKey(keepCtx, acct, listenKey); err != nil {
+					p.reportError(fmt.Errorf("binance listen key keepalive (%s): %w", acct.label(), err))
 				}
 			}
 		}
@@ -899,11 +1172,11 @@ func (p *Provider) consumeUserDataStream(ctx context.Context, listenKey string)
 		if msgType != websocket.MessageText {
 			continue
 		}
-		p.handleUserDataMessage(data)
+		p.handleUserDataMessage(acct, data)
 	}
 }
 
-func (p *Provider) handleUserDataMessage(data []byte) {
+func (p *Provider) handleUserDataMessage(acct *tradingAccount, data []byte) {
 	var header userDataEvent
 	if err := json.Unmarshal(data, &header); err != nil {
 		p.reportError(fmt.Errorf("decode user data header: %w", err))
@@ -916,27 +1189,27 @@ func (p *Provider) handleUserDataMessage(data []byte) {
 			p.reportError(fmt.Errorf("decode account position: %w", err))
 			return
 		}
-		p.handleAccountPosition(event)
+		p.handleAccountPosition(acct, event)
 	case "balanceupdate":
 		var event balanceDeltaEvent
 		if err := json.Unmarshal(data, &event); err != nil {
 			p.reportError(fmt.Errorf("decode balance update: %w", err))
 			return
 		}
-		p.handleBalanceDelta(event)
+		p.handleBalanceDelta(acct, event)
 	case "executionreport":
 		var event executionReportEvent
 		if err := json.Unmarshal(data, &event); err != nil {
 			p.reportError(fmt.Errorf("decode execution report: %w", err))
 			return
 		}
-		p.handleExecutionReport(event)
+		p.handleExecutionReport(acct, event)
 	default:
 		// ignore other user data events for now
 	}
 }
 
-func (p *Provider) handleAccountPosition(event accountPositionEvent) {
+func (p *Provider) handleAccountPosition(acct *tradingAccount, event accountPositionEvent) {
 	timestamp := time.UnixMilli(event.EventTime.Int64()).UTC()
 	for _, bal := range event.Balances {
 		asset := strings.ToUpper(strings.TrimSpace(bal.Asset))
@@ -945,11 +1218,11 @@ func (p *Provider) handleAccountPosition(event accountPositionEvent) {
 		}
 		free, _ := parseDecimal(bal.Free)
 		locked, _ := parseDecimal(bal.Locked)
-		p.publishBalance(asset, free, locked, timestamp)
+		p.publishBalance(acct, asset, free, locked, timestamp)
 	}
 }
 
-func (p *Provider) handleBalanceDelta(event balanceDeltaEvent) {
+func (p *Provider) handleBalanceDelta(acct *tradingAccount, event balanceDeltaEvent) {
 	asset := strings.ToUpper(strings.TrimSpace(event.Asset))
 	if asset == "" {
 		return
@@ -962,17 +1235,17 @@ func (p *Provider) handleBalanceDelta(event balanceDeltaEvent) {
 		return
 	}
 	timestamp := time.UnixMilli(event.EventTime.Int64()).UTC()
-	p.balanceMu.Lock()
-	snapshot := p.balances[asset]
+	acct.balanceMu.Lock()
+	snapshot := acct.balances[asset]
 	snapshot.free = snapshot.free.Add(delta)
-	p.balances[asset] = snapshot
+	acct.balances[asset] = snapshot
 	free := snapshot.free
 	locked := snapshot.locked
-	p.balanceMu.Unlock()
-	p.publishBalance(asset, free, locked, timestamp)
+	acct.balanceMu.Unlock()
+	p.publishBalance(acct, asset, free, locked, timestamp)
 }
 
-func (p *Provider) handleExecutionReport(event executionReportEvent) {
+func (p *Provider) handleExecutionReport(acct *tradingAccount, event executionReportEvent) {
 	meta, ok := p.metaForRESTSymbol(event.Symbol)
 	if !ok {
 		return
@@ -1018,6 +1291,7 @@ func (p *Provider) handleExecutionReport(event executionReportEvent) {
 		AvgFillPrice:     avgFill,
 		CommissionAmount: strings.TrimSpace(event.Commission),
 		CommissionAsset:  commissionAsset,
+		SubAccount:       acct.name,
 		Timestamp:        timestamp,
 		RejectReason:     nil,
 	}
@@ -1025,7 +1299,16 @@ func (p *Provider) handleExecutionReport(event executionReportEvent) {
 		localReason := rejectReason
 		payload.RejectReason = &localReason
 	}
+	if !p.orders.observe(acct, meta.canonical, *payload, event.TradeID, event.CumulativeQuoteQty) {
+		// Already backfilled from REST while the stream was down.
+		return
+	}
 	p.publisher.PublishExecReport(p.ctx, meta.canonical, *payload)
+	switch payload.State {
+	case schema.ExecReportStateFILLED, schema.ExecReportStateCANCELLED, schema.ExecReportStateREJECTED, schema.ExecReportStateEXPIRED:
+		p.orderAccounts.Delete(clientOrderID)
+	case schema.ExecReportStateACK, schema.ExecReportStatePARTIAL:
+	}
 	if p.metrics != nil {
 		tif := strings.ToUpper(strings.TrimSpace(event.TimeInForce))
 		state := payload.State
@@ -1046,17 +1329,18 @@ func (p *Provider) handleExecutionReport(event executionReportEvent) {
 	}
 }
 
-func (p *Provider) publishBalance(asset string, free, locked decimal.Decimal, timestamp time.Time) {
+func (p *Provider) publishBalance(acct *tradingAccount, asset string, free, locked decimal.Decimal, timestamp time.Time) {
 	total := free.Add(locked)
 	snapshot := balanceSnapshot{free: free, locked: locked}
-	p.balanceMu.Lock()
-	p.balances[asset] = snapshot
-	p.balanceMu.Unlock()
+	acct.balanceMu.Lock()
+	acct.balances[asset] = snapshot
+	acct.balanceMu.Unlock()
 	payload := schema.BalanceUpdatePayload{
-		Currency:  asset,
-		Total:     total.String(),
-		Available: free.String(),
-		Timestamp: timestamp,
+		Currency:   asset,
+		Total:      total.String(),
+		Available:  free.String(),
+		SubAccount: acct.name,
+		Timestamp:  timestamp,
 	}
 	p.publisher.PublishBalanceUpdate(p.ctx, asset, payload)
 	if p.metrics != nil {
@@ -1082,11 +1366,11 @@ func (p *Provider) metaForRESTSymbol(symbol string) (symbolMeta, bool) {
 	return meta, ok
 }
 
-func (p *Provider) publishBalanceSnapshot(ctx context.Context) error {
-	if !p.hasTradingCredentials() {
+func (p *Provider) publishBalanceSnapshot(ctx context.Context, acct *tradingAccount) error {
+	if !acct.hasCredentials() {
 		return nil
 	}
-	balances, err := p.fetchAccountBalances(ctx)
+	balances, err := p.fetchAccountBalances(ctx, acct)
 	if err != nil {
 		return err
 	}
@@ -1098,7 +1382,7 @@ func (p *Provider) publishBalanceSnapshot(ctx context.Context) error {
 		}
 		free, _ := parseDecimal(bal.Free)
 		locked, _ := parseDecimal(bal.Locked)
-		p.publishBalance(asset, free, locked, timestamp)
+		p.publishBalance(acct, asset, free, locked, timestamp)
 	}
 	return nil
 }
@@ -1269,7 +1553,7 @@ func (p *Provider) applyDepthDiff(meta symbolMeta, handle *bookHandle, diff dept
 	return nil
 }
 
-func (p *Provider) submitOrder(ctx context.Context, meta symbolMeta, req schema.OrderRequest) error {
+func (p *Provider) submitOrder(ctx context.Context, acct *tradingAccount, meta symbolMeta, req schema.OrderRequest) error {
 	params := url.Values{}
 	params.Set("symbol", meta.rest)
 	side, err := binanceSide(req.Side)
@@ -1281,6 +1565,18 @@ func (p *Provider) submitOrder(ctx context.Context, meta symbolMeta, req schema.
 	if err != nil {
 		return err
 	}
+	tif := req.TIF
+	if req.PostOnly {
+		tif = schema.TimeInForceGTX
+	}
+	tifValue, err := binanceTimeInForce(req.OrderType, tif)
+	if err != nil {
+		return err
+	}
+	if tif == schema.TimeInForceGTX {
+		// Spot has no post-only time in force; LIMIT_MAKER orders are rejected if they would cross.
+		typeValue = "LIMIT_MAKER"
+	}
 	params.Set("type", typeValue)
 	quantity := strings.TrimSpace(req.Quantity)
 	if quantity == "" {
@@ -1296,18 +1592,13 @@ func (p *Provider) submitOrder(ctx context.Context, meta symbolMeta, req schema.
 			return fmt.Errorf("binance: limit order requires price")
 		}
 		params.Set("price", limitPrice)
-		tifValue := strings.ToUpper(strings.TrimSpace(req.TIF))
-		if tifValue == "" {
-			tifValue = "GTC"
-		}
+	}
+	if tifValue != "" {
 		params.Set("timeInForce", tifValue)
-	} else {
-		if tif := strings.ToUpper(strings.TrimSpace(req.TIF)); tif != "" {
-			params.Set("timeInForce", tif)
-		}
 	}
 	if req.ClientOrderID != "" {
 		params.Set("newClientOrderId", req.ClientOrderID)
+		p.rememberOrderAccount(req.ClientOrderID, acct)
 	}
 	params.Set("newOrderRespType", "FULL")
 	if p.opts.recvWindowDuration() > 0 {
@@ -1315,7 +1606,7 @@ func (p *Provider) submitOrder(ctx context.Context, meta symbolMeta, req schema.
 	}
 	params.Set("timestamp", strconv.FormatInt(p.clock().UTC().UnixMilli(), 10))
 	basePayload := params.Encode()
-	signature := signPayload(basePayload, p.opts.Config.APISecret)
+	signature := signPayload(basePayload, acct.apiSecret)
 	params.Set("signature", signature)
 	body := params.Encode()
 	endpoint := p.opts.orderEndpoint()
@@ -1326,7 +1617,7 @@ func (p *Provider) submitOrder(ctx context.Context, meta symbolMeta, req schema.
 	if err != nil {
 		return fmt.Errorf("create order request: %w", err)
 	}
-	httpReq.Header.Set("X-MBX-APIKEY", p.opts.Config.APIKey)
+	httpReq.Header.Set("X-MBX-APIKEY", acct.apiKey)
 	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	resp, err := p.httpClient().Do(httpReq)
 	if err != nil {
@@ -1340,17 +1631,18 @@ func (p *Provider) submitOrder(ctx context.Context, meta symbolMeta, req schema.
 		return fmt.Errorf("read order response: %w", err)
 	}
 	if resp.StatusCode >= http.StatusBadRequest {
+		p.orderAccounts.Delete(req.ClientOrderID)
 		return parseOrderError(resp.StatusCode, respBody)
 	}
 	var order orderResponse
 	if err := json.Unmarshal(respBody, &order); err != nil {
 		return fmt.Errorf("decode order response: %w", err)
 	}
-	p.publishOrderAcknowledgement(meta, req, order, quantity, limitPrice)
+	p.publishOrderAcknowledgement(acct, meta, req, order, quantity, limitPrice)
 	return nil
 }
 
-func (p *Provider) publishOrderAcknowledgement(meta symbolMeta, req schema.OrderRequest, order orderResponse, fallbackQty, fallbackPrice string) {
+func (p *Provider) publishOrderAcknowledgement(acct *tradingAccount, meta symbolMeta, req schema.OrderRequest, order orderResponse, fallbackQty, fallbackPrice string) {
 	price := strings.TrimSpace(order.Price)
 	if price == "" {
 		price = strings.TrimSpace(fallbackPrice)
@@ -1384,9 +1676,14 @@ func (p *Provider) publishOrderAcknowledgement(meta symbolMeta, req schema.Order
 		AvgFillPrice:     avgPrice,
 		CommissionAmount: "",
 		CommissionAsset:  "",
+		SubAccount:       acct.name,
 		Timestamp:        timestamp,
 		RejectReason:     nil,
 	}
+	cumQuote := defaultIfEmpty(strings.TrimSpace(order.CummulativeQuoteQty), strings.TrimSpace(order.CumQuote))
+	if !p.orders.observe(acct, meta.canonical, *payload, 0, cumQuote) {
+		return
+	}
 	p.publisher.PublishExecReport(p.ctx, meta.canonical, *payload)
 	if p.metrics != nil {
 		tif := strings.ToUpper(strings.TrimSpace(order.TimeInForce))
@@ -1404,6 +1701,9 @@ func (p *Provider) publishOrderAcknowledgement(meta symbolMeta, req schema.Order
 	}
 }
 
+// binanceErrUnknownOrder is returned when a cancel request matches no resting orders.
+const binanceErrUnknownOrder = -2011
+
 func parseOrderError(status int, body []byte) error {
 	var apiErr binanceError
 	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Msg != "" {
@@ -1567,6 +1867,7 @@ type executionReportEvent struct {
 	OrderRejectReason  string           `json:"r"`
 	TransactionTime    binanceTimestamp `json:"T"`
 	CumulativeQuoteQty string           `json:"Z"`
+	TradeID            int64            `json:"t"`
 }
 
 func levelsToPriceLevels(levels [][]string) []schema.PriceLevel {
@@ -1662,9 +1963,30 @@ func binanceOrderType(orderType schema.OrderType) (string, error) {
 	}
 }
 
+// binanceTimeInForce returns the timeInForce parameter of an order, empty when Binance does not
+// take one: market orders always execute immediately and LIMIT_MAKER orders never do.
+func binanceTimeInForce(orderType schema.OrderType, tif schema.TimeInForce) (string, error) {
+	if orderType == schema.OrderTypeMarket {
+		if tif != "" && tif != schema.TimeInForceIOC {
+			return "", fmt.Errorf("binance: market orders do not support time in force %s", tif)
+		}
+		return "", nil
+	}
+	switch tif {
+	case "":
+		return string(schema.TimeInForceGTC), nil
+	case schema.TimeInForceGTC, schema.TimeInForceIOC, schema.TimeInForceFOK:
+		return string(tif), nil
+	case schema.TimeInForceGTX:
+		return "", nil
+	default:
+		return "", fmt.Errorf("binance: unsupported time in force %q", tif)
+	}
+}
+
 func binanceOrderTypeFromString(input string) (schema.OrderType, error) {
 	switch strings.ToUpper(strings.TrimSpace(input)) {
-	case "LIMIT":
+	case "LIMIT", "LIMIT_MAKER":
 		return schema.OrderTypeLimit, nil
 	case "MARKET":
 		return schema.OrderTypeMarket, nil
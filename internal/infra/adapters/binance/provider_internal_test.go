@@ -2,6 +2,10 @@ package binance
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -41,7 +45,7 @@ func TestHandleAccountPositionPublishesBalances(t *testing.T) {
 			Locked: "1.5",
 		}},
 	}
-	prov.handleAccountPosition(event)
+	prov.handleAccountPosition(prov.primary, event)
 	select {
 	case evt := <-prov.events:
 		if evt.Type != schema.EventTypeBalanceUpdate {
@@ -89,7 +93,7 @@ func TestHandleBalanceDeltaAdjustsBalance(t *testing.T) {
 			Locked: "0.2",
 		}},
 	}
-	prov.handleAccountPosition(initial)
+	prov.handleAccountPosition(prov.primary, initial)
 	// drain initial event
 	select {
 	case evt := <-prov.events:
@@ -98,7 +102,7 @@ func TestHandleBalanceDeltaAdjustsBalance(t *testing.T) {
 		t.Fatal("expected initial balance event")
 	}
 	delta := balanceDeltaEvent{EventTime: binanceTimestamp(now.Add(time.Second).UnixMilli()), Asset: "BTC", Delta: "0.5"}
-	prov.handleBalanceDelta(delta)
+	prov.handleBalanceDelta(prov.primary, delta)
 	select {
 	case evt := <-prov.events:
 		payload, ok := evt.Payload.(schema.BalanceUpdatePayload)
@@ -143,7 +147,7 @@ func TestHandleExecutionReportPublishesExec(t *testing.T) {
 		CommissionAsset:    &commissionAsset,
 		CumulativeQuoteQty: "50.00000000",
 	}
-	prov.handleExecutionReport(event)
+	prov.handleExecutionReport(prov.primary, event)
 	select {
 	case evt := <-prov.events:
 		if evt.Type != schema.EventTypeExecReport {
@@ -173,3 +177,217 @@ func TestHandleExecutionReportPublishesExec(t *testing.T) {
 		t.Fatal("expected execution report event")
 	}
 }
+
+func TestInstrumentStatusFromBinance(t *testing.T) {
+	cases := map[string]schema.InstrumentStatus{
+		"TRADING":       schema.InstrumentStatusTrading,
+		"PRE_TRADING":   schema.InstrumentStatusAuction,
+		"AUCTION_MATCH": schema.InstrumentStatusAuction,
+		"BREAK":         schema.InstrumentStatusBreak,
+		"HALT":          schema.InstrumentStatusHalted,
+		"END_OF_DAY":    schema.InstrumentStatusHalted,
+		"DELISTED":      schema.InstrumentStatusDelisted,
+		"UNKNOWN":       schema.InstrumentStatusHalted,
+	}
+	for raw, want := range cases {
+		if got := instrumentStatusFromBinance(raw); got != want {
+			t.Fatalf("status %s: expected %s, got %s", raw, want, got)
+		}
+	}
+}
+
+func TestBinanceTimeInForce(t *testing.T) {
+	cases := []struct {
+		orderType schema.OrderType
+		tif       schema.TimeInForce
+		want      string
+		fails     bool
+	}{
+		{schema.OrderTypeLimit, "", "GTC", false},
+		{schema.OrderTypeLimit, schema.TimeInForceFOK, "FOK", false},
+		{schema.OrderTypeLimit, schema.TimeInForceGTX, "", false},
+		{schema.OrderTypeMarket, "", "", false},
+		{schema.OrderTypeMarket, schema.TimeInForceIOC, "", false},
+		{schema.OrderTypeMarket, schema.TimeInForceFOK, "", true},
+	}
+	for _, tc := range cases {
+		got, err := binanceTimeInForce(tc.orderType, tc.tif)
+		if (err != nil) != tc.fails || got != tc.want {
+			t.Fatalf("%s %q: expected %q (fails=%v), got %q (%v)", tc.orderType, tc.tif, tc.want, tc.fails, got, err)
+		}
+	}
+	if orderType, err := binanceOrderTypeFromString("LIMIT_MAKER"); err != nil || orderType != schema.OrderTypeLimit {
+		t.Fatalf("expected LIMIT_MAKER reported as a limit order, got %s (%v)", orderType, err)
+	}
+}
+
+func TestSubAccountBalancesAreTaggedAndIsolated(t *testing.T) {
+	pm := pool.NewPoolManager()
+	t.Cleanup(func() {
+		_ = pm.Shutdown(context.Background())
+	})
+	if err := pm.RegisterPool("Event", 16, 0, func() any { return &schema.Event{} }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	prov := NewProvider(Options{
+		Pools: pm,
+		Config: Config{
+			APIKey:      "key",
+			APISecret:   "secret",
+			SubAccounts: map[string]SubAccount{"desk-a": {APIKey: "sub-key", APISecret: "sub-secret"}},
+		},
+	})
+	prov.ctx = context.Background()
+
+	acct, err := prov.account("desk-a")
+	if err != nil {
+		t.Fatalf("account: %v", err)
+	}
+	if _, err := prov.account("missing"); err == nil {
+		t.Fatal("expected unknown sub-account error")
+	}
+	if got := len(prov.tradingAccounts()); got != 2 {
+		t.Fatalf("expected 2 trading accounts, got %d", got)
+	}
+
+	prov.handleAccountPosition(acct, accountPositionEvent{
+		EventTime: binanceTimestamp(time.Now().UnixMilli()),
+		Balances:  []accountPositionBalance{{Asset: "USDT", Free: "5", Locked: "0"}},
+	})
+	select {
+	case evt := <-prov.events:
+		payload, ok := evt.Payload.(schema.BalanceUpdatePayload)
+		if !ok {
+			t.Fatalf("expected BalanceUpdatePayload, got %T", evt.Payload)
+		}
+		if payload.SubAccount != "desk-a" {
+			t.Fatalf("expected sub-account desk-a, got %q", payload.SubAccount)
+		}
+		prov.pools.ReturnEventInst(evt)
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected balance update event")
+	}
+
+	prov.balanceMu.Lock()
+	_, primaryHas := prov.balances["USDT"]
+	prov.balanceMu.Unlock()
+	if primaryHas {
+		t.Fatal("sub-account balance leaked into primary account cache")
+	}
+	acct.balanceMu.Lock()
+	_, subHas := acct.balances["USDT"]
+	acct.balanceMu.Unlock()
+	if !subHas {
+		t.Fatal("expected sub-account balance cache to be updated")
+	}
+}
+
+func TestSubAccountsFromConfig(t *testing.T) {
+	accounts, err := subAccountsFromConfig(map[string]any{
+		" desk-a ": map[string]any{"api_key": "k", "api_secret": "s"},
+	})
+	if err != nil {
+		t.Fatalf("subAccountsFromConfig: %v", err)
+	}
+	if got := accounts["desk-a"]; got.APIKey != "k" || got.APISecret != "s" {
+		t.Fatalf("unexpected sub-account %+v", got)
+	}
+	if _, err := subAccountsFromConfig(map[string]any{"desk-b": map[string]any{"api_key": "k"}}); err == nil {
+		t.Fatal("expected error for missing api_secret")
+	}
+}
+
+func TestReconcileOrdersBackfillsMissedFills(t *testing.T) {
+	var status atomic.Value
+	status.Store("PARTIALLY_FILLED")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/order", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"symbol":"BTCUSDT","orderId":77,"clientOrderId":%q,"origQty":"1","executedQty":"0.6","cummulativeQuoteQty":"60","status":%q,"type":"LIMIT","side":"BUY","updateTime":1700000000000}`,
+			r.URL.Query().Get("origClientOrderId"), status.Load())
+	})
+	mux.HandleFunc("/api/v3/myTrades", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("orderId") != "77" {
+			t.Errorf("unexpected orderId %s", r.URL.Query().Get("orderId"))
+		}
+		_, _ = w.Write([]byte(`[{"id":12,"orderId":77,"price":"100","qty":"0.4","quoteQty":"40","commission":"0.0004","commissionAsset":"BNB","time":1700000000000},
+			{"id":11,"orderId":77,"price":"100","qty":"0.2","quoteQty":"20","commission":"0.0002","commissionAsset":"BNB","time":1699999999000}]`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	prov := newTestProvider(t)
+	prov.opts.privateMeta.apiBaseURL = srv.URL
+	prov.symbols["BTC-USDT"] = symbolMeta{canonical: "BTC-USDT", rest: "BTCUSDT", stream: "btcusdt"}
+	prov.restToCanon["BTCUSDT"] = "BTC-USDT"
+	report := executionReportEvent{
+		Symbol:             "BTCUSDT",
+		ClientOrderID:      "order-1",
+		Side:               "BUY",
+		OrderType:          "LIMIT",
+		OrderStatus:        "NEW",
+		OrderID:            77,
+		OriginalQuantity:   "1",
+		CumulativeQuantity: "0",
+		Price:              "100",
+	}
+	prov.handleExecutionReport(prov.primary, report)
+	report.OrderStatus = "PARTIALLY_FILLED"
+	report.CumulativeQuantity = "0.2"
+	report.CumulativeQuoteQty = "20"
+	report.TradeID = 11
+	prov.handleExecutionReport(prov.primary, report)
+	drainExecReports(t, prov, 2)
+
+	// Trade 12 was missed by the stream and is backfilled with its commission.
+	prov.reconcileOrders(context.Background(), prov.primary)
+	backfilled := drainExecReports(t, prov, 1)[0]
+	if backfilled.State != schema.ExecReportStatePARTIAL || backfilled.FilledQuantity != "0.6" || backfilled.CommissionAmount != "0.0004" || backfilled.CommissionAsset != "BNB" {
+		t.Fatalf("unexpected backfilled report %+v", backfilled)
+	}
+	if avg := decimal.RequireFromString(backfilled.AvgFillPrice); !avg.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("expected avg fill price 100, got %s", backfilled.AvgFillPrice)
+	}
+
+	// The late streamed copy of trade 12 is a duplicate.
+	report.CumulativeQuantity = "0.6"
+	report.CumulativeQuoteQty = "60"
+	report.TradeID = 12
+	prov.handleExecutionReport(prov.primary, report)
+	prov.reconcileOrders(context.Background(), prov.primary)
+	drainExecReports(t, prov, 0)
+
+	status.Store("CANCELED")
+	prov.reconcileOrders(context.Background(), prov.primary)
+	cancelled := drainExecReports(t, prov, 1)[0]
+	if cancelled.State != schema.ExecReportStateCANCELLED || cancelled.FilledQuantity != "0.6" || cancelled.RemainingQty != "0.4" {
+		t.Fatalf("unexpected cancel report %+v", cancelled)
+	}
+	report.OrderStatus = "CANCELED"
+	report.TradeID = -1
+	prov.handleExecutionReport(prov.primary, report)
+	drainExecReports(t, prov, 0)
+	if open := prov.orders.open(prov.primary); len(open) != 0 {
+		t.Fatalf("expected no open orders, got %d", len(open))
+	}
+}
+
+func drainExecReports(t *testing.T, prov *Provider, want int) []schema.ExecReportPayload {
+	t.Helper()
+	var out []schema.ExecReportPayload
+	for {
+		select {
+		case evt := <-prov.events:
+			if payload, ok := evt.Payload.(schema.ExecReportPayload); ok {
+				out = append(out, payload)
+			}
+			prov.pools.ReturnEventInst(evt)
+			continue
+		case <-time.After(50 * time.Millisecond):
+		}
+		break
+	}
+	if len(out) != want {
+		t.Fatalf("expected %d exec reports, got %d: %+v", want, len(out), out)
+	}
+	return out
+}
@@ -0,0 +1,372 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// maxClosedOrders bounds how many finished orders are remembered to drop late duplicate reports.
+const maxClosedOrders = 1024
+
+// trackedOrder is the last execution report state published for one client order.
+type trackedOrder struct {
+	clientOrderID string
+	acct          *tradingAccount
+	symbol        string
+	side          schema.TradeSide
+	orderType     schema.OrderType
+	price         string
+	quantity      decimal.Decimal
+	state         schema.ExecReportState
+	filled        decimal.Decimal
+	quote         decimal.Decimal
+	tradeIDs      map[int64]struct{}
+}
+
+// orderTracker remembers the execution reports published per client order so that reports missed
+// while the user data stream was down can be rebuilt from REST, and so that a report published by
+// one path is not published again by the other.
+type orderTracker struct {
+	mu     sync.Mutex
+	orders map[string]*trackedOrder
+	closed []string
+}
+
+func newOrderTracker() *orderTracker {
+	return &orderTracker{
+		mu:     sync.Mutex{},
+		orders: make(map[string]*trackedOrder),
+		closed: nil,
+	}
+}
+
+// observe records a report about to be published and reports whether it is new. Fills carry the
+// venue trade ID and are duplicates once that trade was published; other reports are stale once
+// the order finished, such as a REST acknowledgement arriving after the stream reported the fill.
+func (t *orderTracker) observe(acct *tradingAccount, symbol string, payload schema.ExecReportPayload, tradeID int64, cumQuote string) bool {
+	clientOrderID := strings.TrimSpace(payload.ClientOrderID)
+	if clientOrderID == "" {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	order, ok := t.orders[clientOrderID]
+	if ok {
+		if tradeID > 0 {
+			if _, seen := order.tradeIDs[tradeID]; seen {
+				return false
+			}
+		} else if terminalExecState(order.state) {
+			return false
+		}
+	} else {
+		order = &trackedOrder{
+			clientOrderID: clientOrderID,
+			acct:          acct,
+			symbol:        symbol,
+			side:          payload.Side,
+			orderType:     payload.OrderType,
+			price:         payload.Price,
+			quantity:      decimal.Zero,
+			state:         payload.State,
+			filled:        decimal.Zero,
+			quote:         decimal.Zero,
+			tradeIDs:      make(map[int64]struct{}),
+		}
+		t.orders[clientOrderID] = order
+	}
+	if tradeID > 0 {
+		order.tradeIDs[tradeID] = struct{}{}
+	}
+	if quantity, ok := parseDecimal(payload.Quantity); ok && quantity.IsPositive() {
+		order.quantity = quantity
+	}
+	if filled, ok := parseDecimal(payload.FilledQuantity); ok && filled.GreaterThanOrEqual(order.filled) {
+		order.filled = filled
+		if quote, ok := parseDecimal(cumQuote); ok {
+			order.quote = quote
+		}
+	}
+	wasTerminal := terminalExecState(order.state)
+	order.state = payload.State
+	if !wasTerminal && terminalExecState(order.state) {
+		t.closeLocked(clientOrderID)
+	}
+	return true
+}
+
+func (t *orderTracker) closeLocked(clientOrderID string) {
+	t.closed = append(t.closed, clientOrderID)
+	if len(t.closed) <= maxClosedOrders {
+		return
+	}
+	evict := t.closed[0]
+	t.closed = t.closed[1:]
+	if order, ok := t.orders[evict]; ok && terminalExecState(order.state) {
+		delete(t.orders, evict)
+	}
+}
+
+// open returns copies of the unfinished orders of acct.
+func (t *orderTracker) open(acct *tradingAccount) []trackedOrder {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]trackedOrder, 0)
+	for _, order := range t.orders {
+		if order.acct != acct || terminalExecState(order.state) {
+			continue
+		}
+		snapshot := *order
+		snapshot.tradeIDs = make(map[int64]struct{}, len(order.tradeIDs))
+		for id := range order.tradeIDs {
+			snapshot.tradeIDs[id] = struct{}{}
+		}
+		out = append(out, snapshot)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].clientOrderID < out[j].clientOrderID
+	})
+	return out
+}
+
+func terminalExecState(state schema.ExecReportState) bool {
+	switch state {
+	case schema.ExecReportStateFILLED, schema.ExecReportStateCANCELLED, schema.ExecReportStateREJECTED, schema.ExecReportStateEXPIRED:
+		return true
+	case schema.ExecReportStateACK, schema.ExecReportStatePARTIAL:
+		return false
+	default:
+		return false
+	}
+}
+
+type myTradeResponse struct {
+	ID              int64  `json:"id"`
+	OrderID         int64  `json:"orderId"`
+	Price           string `json:"price"`
+	Qty             string `json:"qty"`
+	QuoteQty        string `json:"quoteQty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            int64  `json:"time"`
+}
+
+// reconcileLoop backfills missed execution reports of acct every order reconcile interval.
+func (p *Provider) reconcileLoop(ctx context.Context, acct *tradingAccount) {
+	ticker := time.NewTicker(p.opts.orderReconcileDuration())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.reconcileOrders(ctx, acct)
+		}
+	}
+}
+
+// reconcileOrders queries the venue state of every open order of acct and publishes the fills
+// and state changes the user data stream did not deliver.
+func (p *Provider) reconcileOrders(ctx context.Context, acct *tradingAccount) {
+	for _, order := range p.orders.open(acct) {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := p.reconcileOrder(ctx, order); err != nil {
+			p.reportError(fmt.Errorf("binance order reconcile (%s, %s): %w", acct.label(), order.clientOrderID, err))
+		}
+	}
+}
+
+func (p *Provider) reconcileOrder(ctx context.Context, order trackedOrder) error {
+	meta, ok := p.metaForInstrument(order.symbol)
+	if !ok {
+		return fmt.Errorf("instrument %s not found", order.symbol)
+	}
+	venue, err := p.fetchOrder(ctx, order.acct, meta, order.clientOrderID)
+	if err != nil {
+		return err
+	}
+	if quantity, ok := parseDecimal(venue.OrigQty); ok && quantity.IsPositive() {
+		order.quantity = quantity
+	}
+	exchangeOrderID := strconv.FormatInt(venue.OrderID, 10)
+	executed, _ := parseDecimal(venue.ExecutedQty)
+	if executed.GreaterThan(order.filled) {
+		trades, err := p.fetchOrderTrades(ctx, order.acct, meta, venue.OrderID)
+		if err != nil {
+			return err
+		}
+		filled := decimal.Zero
+		quote := decimal.Zero
+		for _, trade := range trades {
+			qty, _ := parseDecimal(trade.Qty)
+			tradeQuote, ok := parseDecimal(trade.QuoteQty)
+			if !ok {
+				price, _ := parseDecimal(trade.Price)
+				tradeQuote = price.Mul(qty)
+			}
+			filled = filled.Add(qty)
+			quote = quote.Add(tradeQuote)
+			if _, seen := order.tradeIDs[trade.ID]; seen || !filled.GreaterThan(order.filled) {
+				continue
+			}
+			state := schema.ExecReportStatePARTIAL
+			if order.quantity.IsPositive() && filled.GreaterThanOrEqual(order.quantity) {
+				state = schema.ExecReportStateFILLED
+			}
+			payload := p.reconciledExecReport(order, exchangeOrderID, state, filled, quote, resolveTimestamp(trade.Time, p.clock))
+			payload.CommissionAmount = strings.TrimSpace(trade.Commission)
+			payload.CommissionAsset = strings.ToUpper(strings.TrimSpace(trade.CommissionAsset))
+			p.publishReconciledExecReport(order.acct, meta, payload, trade.ID, quote.String())
+			order.filled = filled
+			order.quote = quote
+			order.state = state
+		}
+	}
+	final := binanceStatusToExecState(venue.Status)
+	if !terminalExecState(final) || final == order.state {
+		return nil
+	}
+	filled := order.filled
+	quote := order.quote
+	if executed.GreaterThan(filled) {
+		// Fills myTrades has not caught up with yet are still reported cumulatively.
+		filled = executed
+		quote, _ = parseDecimal(defaultIfEmpty(strings.TrimSpace(venue.CummulativeQuoteQty), strings.TrimSpace(venue.CumQuote)))
+	}
+	updatedAt := venue.UpdateTime
+	if updatedAt <= 0 {
+		updatedAt = venue.TransactTime
+	}
+	payload := p.reconciledExecReport(order, exchangeOrderID, final, filled, quote, resolveTimestamp(updatedAt, p.clock))
+	p.publishReconciledExecReport(order.acct, meta, payload, 0, quote.String())
+	return nil
+}
+
+func (p *Provider) reconciledExecReport(order trackedOrder, exchangeOrderID string, state schema.ExecReportState, filled, quote decimal.Decimal, timestamp time.Time) schema.ExecReportPayload {
+	remaining := ""
+	if order.quantity.IsPositive() {
+		remaining = calculateRemaining(order.quantity.String(), filled.String())
+	}
+	return schema.ExecReportPayload{
+		ClientOrderID:    order.clientOrderID,
+		ExchangeOrderID:  exchangeOrderID,
+		State:            state,
+		Side:             order.side,
+		OrderType:        order.orderType,
+		Price:            order.price,
+		Quantity:         order.quantity.String(),
+		FilledQuantity:   filled.String(),
+		RemainingQty:     remaining,
+		AvgFillPrice:     calculateAveragePrice(quote.String(), filled.String()),
+		CommissionAmount: "",
+		CommissionAsset:  "",
+		SubAccount:       order.acct.name,
+		Timestamp:        timestamp,
+		RejectReason:     nil,
+	}
+}
+
+func (p *Provider) publishReconciledExecReport(acct *tradingAccount, meta symbolMeta, payload schema.ExecReportPayload, tradeID int64, cumQuote string) {
+	if !p.orders.observe(acct, meta.canonical, payload, tradeID, cumQuote) {
+		return
+	}
+	log.Printf("binance/provider: %s backfilled %s report for order %s from REST", acct.label(), payload.State, payload.ClientOrderID)
+	p.publisher.PublishExecReport(p.ctx, meta.canonical, payload)
+	if terminalExecState(payload.State) {
+		p.orderAccounts.Delete(payload.ClientOrderID)
+	}
+	if p.metrics != nil {
+		p.metrics.recordEvent(p.ctx, telemetry.EventTypeExecReport, meta.canonical)
+		p.metrics.recordOrder(p.ctx, meta.canonical, payload.Side, payload.OrderType, "", payload.State)
+	}
+}
+
+// fetchOrder queries the venue state of an order by its client order ID.
+func (p *Provider) fetchOrder(ctx context.Context, acct *tradingAccount, meta symbolMeta, clientOrderID string) (orderResponse, error) {
+	var order orderResponse
+	params := url.Values{}
+	params.Set("symbol", meta.rest)
+	params.Set("origClientOrderId", clientOrderID)
+	body, err := p.signedGet(ctx, acct, p.opts.orderEndpoint(), params, "order query")
+	if err != nil {
+		return order, err
+	}
+	if err := json.Unmarshal(body, &order); err != nil {
+		return order, fmt.Errorf("decode order query response: %w", err)
+	}
+	return order, nil
+}
+
+// fetchOrderTrades lists the fills of an order ordered by trade ID.
+func (p *Provider) fetchOrderTrades(ctx context.Context, acct *tradingAccount, meta symbolMeta, orderID int64) ([]myTradeResponse, error) {
+	params := url.Values{}
+	params.Set("symbol", meta.rest)
+	params.Set("orderId", strconv.FormatInt(orderID, 10))
+	body, err := p.signedGet(ctx, acct, p.opts.myTradesEndpoint(), params, "trade query")
+	if err != nil {
+		return nil, err
+	}
+	var trades []myTradeResponse
+	if err := json.Unmarshal(body, &trades); err != nil {
+		return nil, fmt.Errorf("decode trade query response: %w", err)
+	}
+	sort.Slice(trades, func(i, j int) bool {
+		return trades[i].ID < trades[j].ID
+	})
+	return trades, nil
+}
+
+// signedGet issues a signed GET against endpoint and returns the response body.
+func (p *Provider) signedGet(ctx context.Context, acct *tradingAccount, endpoint string, params url.Values, operation string) ([]byte, error) {
+	if strings.TrimSpace(endpoint) == "" {
+		return nil, fmt.Errorf("binance: %s endpoint not configured", operation)
+	}
+	if ctx == nil {
+		ctx = p.ctx
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, p.opts.httpTimeoutDuration())
+	defer cancel()
+	if p.opts.recvWindowDuration() > 0 {
+		params.Set("recvWindow", strconv.FormatInt(p.opts.recvWindowDuration().Milliseconds(), 10))
+	}
+	params.Set("timestamp", strconv.FormatInt(p.clock().UTC().UnixMilli(), 10))
+	query := params.Encode()
+	query += "&signature=" + signPayload(query, acct.apiSecret)
+	httpReq, err := http.NewRequestWithContext(reqCtx, http.MethodGet, endpoint+"?"+query, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create %s request: %w", operation, err)
+	}
+	httpReq.Header.Set("X-MBX-APIKEY", acct.apiKey)
+	resp, err := p.httpClient().Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", operation, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read %s response: %w", operation, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, parseOrderError(resp.StatusCode, body)
+	}
+	return body, nil
+}
@@ -97,10 +97,6 @@ func (p *Provider) fetchExchangeInfo(ctx context.Context) ([]schema.Instrument,
 	metas := make(map[string]symbolMeta, len(payload.Symbols))
 
 	for _, sym := range payload.Symbols {
-		status := strings.TrimSpace(sym.Status)
-		if !strings.EqualFold(status, "TRADING") {
-			continue
-		}
 		instrument, meta, err := p.buildInstrument(sym)
 		if err != nil {
 			continue
@@ -112,6 +108,24 @@ func (p *Provider) fetchExchangeInfo(ctx context.Context) ([]schema.Instrument,
 	return instruments, metas, nil
 }
 
+// instrumentStatusFromBinance maps exchangeInfo symbol statuses onto canonical trading states.
+func instrumentStatusFromBinance(status string) schema.InstrumentStatus {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "TRADING":
+		return schema.InstrumentStatusTrading
+	case "PRE_TRADING", "POST_TRADING", "AUCTION_MATCH":
+		return schema.InstrumentStatusAuction
+	case "BREAK":
+		return schema.InstrumentStatusBreak
+	case "HALT", "END_OF_DAY":
+		return schema.InstrumentStatusHalted
+	case "DELISTED":
+		return schema.InstrumentStatusDelisted
+	default:
+		return schema.InstrumentStatusHalted
+	}
+}
+
 func (p *Provider) buildInstrument(sym exchangeInfoSymbol) (schema.Instrument, symbolMeta, error) {
 	canonical := canonicalFromAssets(sym.BaseAsset, sym.QuoteAsset)
 	inst := schema.Instrument{
@@ -133,6 +147,7 @@ func (p *Provider) buildInstrument(sym exchangeInfoSymbol) (schema.Instrument, s
 		MinNotional:       "",
 		MinQuantity:       "",
 		MaxQuantity:       "",
+		Status:            instrumentStatusFromBinance(sym.Status),
 	}
 	if sym.QuoteAssetPrecision > 0 {
 		inst.PricePrecision = ptr(sym.QuoteAssetPrecision)
@@ -162,7 +177,7 @@ func (p *Provider) buildInstrument(sym exchangeInfoSymbol) (schema.Instrument, s
 			if strings.TrimSpace(filter.MaxQty) != "" {
 				inst.MaxQuantity = filter.MaxQty
 			}
-		case "MIN_NOTIONAL":
+		case "MIN_NOTIONAL", "NOTIONAL":
 			if strings.TrimSpace(filter.MinNotional) != "" {
 				inst.MinNotional = filter.MinNotional
 			}
@@ -210,8 +225,8 @@ func (p *Provider) fetchDepthSnapshot(ctx context.Context, symbol string) (depth
 	return snapshot, nil
 }
 
-func (p *Provider) createListenKey(ctx context.Context) (string, error) {
-	if !p.hasTradingCredentials() {
+func (p *Provider) createListenKey(ctx context.Context, acct *tradingAccount) (string, error) {
+	if !acct.hasCredentials() {
 		return "", errors.New("binance: missing api credentials for listen key")
 	}
 	reqCtx, cancel := context.WithTimeout(ctx, p.opts.httpTimeoutDuration())
@@ -224,7 +239,7 @@ func (p *Provider) createListenKey(ctx context.Context) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("create listen key request: %w", err)
 	}
-	req.Header.Set("X-MBX-APIKEY", p.opts.Config.APIKey)
+	req.Header.Set("X-MBX-APIKEY", acct.apiKey)
 	resp, err := p.httpClient().Do(req)
 	if err != nil {
 		return "", fmt.Errorf("request listen key: %w", err)
@@ -247,7 +262,7 @@ func (p *Provider) createListenKey(ctx context.Context) (string, error) {
 	return payload.ListenKey, nil
 }
 
-func (p *Provider) keepAliveListenKey(ctx context.Context, listenKey string) error {
+func (p *Provider) keepAliveListenKey(ctx context.Context, acct *tradingAccount, listenKey string) error {
 	if strings.TrimSpace(listenKey) == "" {
 		return errors.New("binance: empty listen key for keepalive")
 	}
@@ -264,7 +279,7 @@ func (p *Provider) keepAliveListenKey(ctx context.Context, listenKey string) err
 	if err != nil {
 		return fmt.Errorf("create keepalive request: %w", err)
 	}
-	req.Header.Set("X-MBX-APIKEY", p.opts.Config.APIKey)
+	req.Header.Set("X-MBX-APIKEY", acct.apiKey)
 	resp, err := p.httpClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("keepalive listen key: %w", err)
@@ -279,8 +294,8 @@ func (p *Provider) keepAliveListenKey(ctx context.Context, listenKey string) err
 	return nil
 }
 
-func (p *Provider) fetchAccountBalances(ctx context.Context) ([]accountBalance, error) {
-	if !p.hasTradingCredentials() {
+func (p *Provider) fetchAccountBalances(ctx context.Context, acct *tradingAccount) ([]accountBalance, error) {
+	if !acct.hasCredentials() {
 		return nil, errors.New("binance: missing api credentials for account balances")
 	}
 
@@ -292,7 +307,7 @@ func (p *Provider) fetchAccountBalances(ctx context.Context) ([]accountBalance,
 	}
 	params.Set("timestamp", strconv.FormatInt(p.clock().UTC().UnixMilli(), 10))
 	query := params.Encode()
-	signature := signPayload(query, p.opts.Config.APISecret)
+	signature := signPayload(query, acct.apiSecret)
 	if query != "" {
 		query += "&"
 	}
@@ -305,7 +320,7 @@ func (p *Provider) fetchAccountBalances(ctx context.Context) ([]accountBalance,
 	if err != nil {
 		return nil, fmt.Errorf("create account request: %w", err)
 	}
-	req.Header.Set("X-MBX-APIKEY", p.opts.Config.APIKey)
+	req.Header.Set("X-MBX-APIKEY", acct.apiKey)
 	resp, err := p.httpClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request account: %w", err)
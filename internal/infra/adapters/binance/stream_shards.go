@@ -0,0 +1,222 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// streamShard is the subset of streamManager a streamShards set drives.
+type streamShard interface {
+	start() error
+	stop()
+	subscribe(streams []string) error
+	unsubscribe(streams []string) error
+	connected() bool
+}
+
+// streamShards spreads the streams of one kind across as many websocket connections as the
+// per-connection budget requires. Streams go to the first connection with room; a new connection
+// is dialled only when every open one is full. Connections emptied by unsubscribes stay open and
+// take the next streams.
+type streamShards struct {
+	stream   string
+	budget   int
+	newShard func(connection int) streamShard
+
+	mu     sync.Mutex
+	shards []streamShard
+	counts []int
+	owners map[string]int
+}
+
+func newStreamShards(stream string, budget int, newShard func(connection int) streamShard) *streamShards {
+	if budget <= 0 || budget > binanceMaxStreamsPerConnection {
+		budget = binanceMaxStreamsPerConnection
+	}
+	return &streamShards{
+		stream:   stream,
+		budget:   budget,
+		newShard: newShard,
+		mu:       sync.Mutex{},
+		shards:   nil,
+		counts:   nil,
+		owners:   make(map[string]int),
+	}
+}
+
+// start opens the first connection.
+func (s *streamShards) start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.shards) > 0 {
+		return nil
+	}
+	_, err := s.addShardLocked()
+	return err
+}
+
+func (s *streamShards) addShardLocked() (int, error) {
+	connection := len(s.shards)
+	shard := s.newShard(connection)
+	if err := shard.start(); err != nil {
+		shard.stop()
+		return 0, fmt.Errorf("start %s connection %d: %w", s.stream, connection, err)
+	}
+	s.shards = append(s.shards, shard)
+	s.counts = append(s.counts, 0)
+	return connection, nil
+}
+
+// subscribe assigns new streams to connections with room, opening connections as needed.
+func (s *streamShards) subscribe(streams []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assigned := make(map[int][]string)
+	var startErr error
+	for _, stream := range streams {
+		if _, exists := s.owners[stream]; exists {
+			continue
+		}
+		connection := -1
+		for idx, count := range s.counts {
+			if count < s.budget {
+				connection = idx
+				break
+			}
+		}
+		if connection < 0 {
+			var err error
+			connection, err = s.addShardLocked()
+			if err != nil {
+				startErr = err
+				break
+			}
+		}
+		s.owners[stream] = connection
+		s.counts[connection]++
+		assigned[connection] = append(assigned[connection], stream)
+	}
+
+	errs := make([]error, 0, len(assigned)+1)
+	for connection, batch := range assigned {
+		if err := s.shards[connection].subscribe(batch); err != nil {
+			errs = append(errs, fmt.Errorf("%s connection %d: %w", s.stream, connection, err))
+		}
+	}
+	if startErr != nil {
+		errs = append(errs, startErr)
+	}
+	return errors.Join(errs...)
+}
+
+// unsubscribe removes streams from the connections that carry them.
+func (s *streamShards) unsubscribe(streams []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	owned := make(map[int][]string)
+	for _, stream := range streams {
+		connection, exists := s.owners[stream]
+		if !exists {
+			continue
+		}
+		delete(s.owners, stream)
+		s.counts[connection]--
+		owned[connection] = append(owned[connection], stream)
+	}
+
+	errs := make([]error, 0, len(owned))
+	for connection, batch := range owned {
+		if err := s.shards[connection].unsubscribe(batch); err != nil {
+			errs = append(errs, fmt.Errorf("%s connection %d: %w", s.stream, connection, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stop closes every connection.
+func (s *streamShards) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, shard := range s.shards {
+		shard.stop()
+	}
+}
+
+// streamShardStatus reports the stream count and connection state of one connection.
+type streamShardStatus struct {
+	connection int
+	streams    int
+	connected  bool
+}
+
+func (s *streamShards) status() []streamShardStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]streamShardStatus, 0, len(s.shards))
+	for idx, shard := range s.shards {
+		out = append(out, streamShardStatus{connection: idx, streams: s.counts[idx], connected: shard.connected()})
+	}
+	return out
+}
+
+// registerStreamShardMetrics publishes per-connection stream counts and connection state for the
+// stream kinds of a provider.
+func registerStreamShardMetrics(provider string, sets ...*streamShards) {
+	meter := otel.Meter("adapter.binance")
+	env := telemetry.Environment()
+	provider = strings.TrimSpace(provider)
+	if provider == "" {
+		provider = binancePublicMetadata.identifier
+	}
+	observe := func(report func(status streamShardStatus, attrs []attribute.KeyValue)) {
+		for _, set := range sets {
+			if set == nil {
+				continue
+			}
+			for _, status := range set.status() {
+				attrs := []attribute.KeyValue{
+					telemetry.AttrEnvironment.String(env),
+					telemetry.AttrProvider.String(provider),
+					telemetry.AttrMessageType.String(set.stream),
+					telemetry.AttrConnectionIndex.Int(status.connection),
+				}
+				report(status, attrs)
+			}
+		}
+	}
+
+	_, _ = meter.Int64ObservableGauge("meltica_provider_binance_ws_connection_streams",
+		metric.WithDescription("Streams assigned to each Binance websocket connection"),
+		metric.WithUnit("{stream}"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observe(func(status streamShardStatus, attrs []attribute.KeyValue) {
+				observer.Observe(int64(status.streams), metric.WithAttributes(attrs...))
+			})
+			return nil
+		}))
+
+	_, _ = meter.Int64ObservableGauge("meltica_provider_binance_ws_connection_up",
+		metric.WithDescription("Whether each Binance websocket connection is open (1) or reconnecting (0)"),
+		metric.WithUnit("{connection}"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observe(func(status streamShardStatus, attrs []attribute.KeyValue) {
+				up := int64(0)
+				if status.connected {
+					up = 1
+				}
+				observer.Observe(up, metric.WithAttributes(attrs...))
+			})
+			return nil
+		}))
+}
@@ -0,0 +1,75 @@
+package binance
+
+import (
+	"testing"
+)
+
+type fakeShard struct {
+	streams map[string]bool
+	stopped bool
+}
+
+func (f *fakeShard) start() error { return nil }
+func (f *fakeShard) stop()        { f.stopped = true }
+func (f *fakeShard) connected() bool {
+	return !f.stopped
+}
+
+func (f *fakeShard) subscribe(streams []string) error {
+	for _, stream := range streams {
+		f.streams[stream] = true
+	}
+	return nil
+}
+
+func (f *fakeShard) unsubscribe(streams []string) error {
+	for _, stream := range streams {
+		delete(f.streams, stream)
+	}
+	return nil
+}
+
+func TestStreamShardsSplitsStreamsAcrossConnections(t *testing.T) {
+	var shards []*fakeShard
+	set := newStreamShards("trade", 2, func(int) streamShard {
+		shard := &fakeShard{streams: map[string]bool{}}
+		shards = append(shards, shard)
+		return shard
+	})
+	if err := set.start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := set.subscribe([]string{"a@trade", "b@trade", "c@trade", "a@trade"}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if len(shards) != 2 || len(shards[0].streams) != 2 || !shards[1].streams["c@trade"] {
+		t.Fatalf("expected streams split 2/1 across two connections, got %d connections", len(shards))
+	}
+
+	if err := set.unsubscribe([]string{"a@trade"}); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if err := set.subscribe([]string{"d@trade"}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if len(shards) != 2 || !shards[0].streams["d@trade"] || shards[0].streams["a@trade"] {
+		t.Fatalf("expected the freed slot on the first connection to be reused")
+	}
+
+	status := set.status()
+	if len(status) != 2 || status[0].streams != 2 || status[1].streams != 1 || !status[1].connected {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	set.stop()
+	if !shards[0].stopped || !shards[1].stopped {
+		t.Fatal("expected every connection stopped")
+	}
+}
+
+func TestStreamShardsCapsBudgetAtVenueLimit(t *testing.T) {
+	set := newStreamShards("ticker", 5000, func(int) streamShard { return &fakeShard{streams: map[string]bool{}} })
+	if set.budget != binanceMaxStreamsPerConnection {
+		t.Fatalf("expected budget capped at %d, got %d", binanceMaxStreamsPerConnection, set.budget)
+	}
+}
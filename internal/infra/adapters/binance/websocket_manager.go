@@ -11,9 +11,11 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/cenkalti/backoff/v5"
 	"github.com/coder/websocket"
 	"github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
+	"github.com/coachpo/meltica/internal/infra/pool"
 )
 
 const (
@@ -22,6 +24,8 @@ const (
 	binanceControlMessageInterval = 250 * time.Millisecond
 	// Keep subscribe payloads modest so we can throttle between them if the stream count is large.
 	binanceMaxStreamsPerRequest = 100
+	// Binance accepts at most 1024 streams on a single connection.
+	binanceMaxStreamsPerConnection = 1024
 	// Keepalive and reconnection tuning.
 	binancePingInterval         = 30 * time.Second
 	binancePingTimeout          = 5 * time.Second
@@ -32,9 +36,11 @@ const (
 
 // streamManager manages a single WebSocket connection with live subscribe/unsubscribe support.
 type streamManager struct {
-	baseURL string
-	ctx     context.Context
-	cancel  context.CancelFunc
+	// baseURL resolves the stream URL on every dial so reconnects follow the endpoint selection.
+	baseURL  func() string
+	dialOpts *websocket.DialOptions
+	ctx      context.Context
+	cancel   context.CancelFunc
 
 	conn     *websocket.Conn
 	connMu   sync.RWMutex
@@ -43,6 +49,7 @@ type streamManager struct {
 	subscriptions map[string]struct{}
 	subsMu        sync.Mutex
 
+	// handler must not retain the message bytes; they live in a pooled buffer.
 	handler   func([]byte) error
 	errorChan chan<- error
 
@@ -52,6 +59,8 @@ type streamManager struct {
 	controlMu       sync.Mutex
 	lastControlSend time.Time
 	metrics         *streamMetrics
+	stats           *shared.StreamStats
+	reconnect       *shared.Reconnector
 	streamName      string
 	providerName    string
 }
@@ -74,7 +83,7 @@ type wsError struct {
 }
 
 // newStreamManager creates a new stream manager instance.
-func newStreamManager(ctx context.Context, baseURL string, handler func([]byte) error, errorChan chan<- error, stream, providerName string) *streamManager {
+func newStreamManager(ctx context.Context, baseURL func() string, handler func([]byte) error, errorChan chan<- error, stream, providerName string, connection int, policy shared.ReconnectPolicy, dialOpts *websocket.DialOptions) *streamManager {
 	managerCtx, cancel := context.WithCancel(ctx)
 	normalizedProvider := strings.TrimSpace(providerName)
 	if normalizedProvider == "" {
@@ -82,6 +91,7 @@ func newStreamManager(ctx context.Context, baseURL string, handler func([]byte)
 	}
 	return &streamManager{
 		baseURL:         baseURL,
+		dialOpts:        dialOpts,
 		ctx:             managerCtx,
 		cancel:          cancel,
 		conn:            nil,
@@ -95,7 +105,9 @@ func newStreamManager(ctx context.Context, baseURL string, handler func([]byte)
 		readyOnce:       sync.Once{},
 		controlMu:       sync.Mutex{},
 		lastControlSend: time.Time{},
-		metrics:         newStreamMetrics(normalizedProvider, stream),
+		metrics:         newStreamMetrics(normalizedProvider, stream, connection),
+		stats:           nil,
+		reconnect:       shared.NewReconnector(policy, shared.NewReconnectMetrics(binancePublicMetadata.identifier, normalizedProvider, stream)),
 		streamName:      stream,
 		providerName:    normalizedProvider,
 	}
@@ -131,6 +143,13 @@ func (sm *streamManager) stop() {
 	sm.connMu.Unlock()
 }
 
+// connected reports whether the manager currently holds an open connection.
+func (sm *streamManager) connected() bool {
+	sm.connMu.RLock()
+	defer sm.connMu.RUnlock()
+	return sm.conn != nil
+}
+
 // subscribe adds one or more stream subscriptions.
 func (sm *streamManager) subscribe(streams []string) error {
 	if len(streams) == 0 {
@@ -187,11 +206,8 @@ func (sm *streamManager) unsubscribe(streams []string) error {
 	return sm.sendBatchedControlRequests(sm.ctx, "UNSUBSCRIBE", existingStreams)
 }
 
-// connect maintains the WebSocket connection with automatic reconnection and exponential backoff.
+// connect maintains the WebSocket connection, reconnecting according to the provider's reconnect policy.
 func (sm *streamManager) connect() error {
-	backoffCfg := backoff.NewExponentialBackOff()
-	backoffCfg.MaxInterval = binanceMaxReconnectInterval
-
 	// Persistently attempt to keep a single websocket session alive until the parent context terminates.
 	// The loop dials, replays subscriptions, and coordinates reader/pinger goroutines for each session.
 	for {
@@ -201,27 +217,17 @@ func (sm *streamManager) connect() error {
 		default:
 		}
 
-		conn, _, err := websocket.Dial(sm.ctx, sm.baseURL, nil)
+		dialURL := sm.baseURL()
+		conn, _, err := websocket.Dial(sm.ctx, dialURL, sm.dialOpts)
 		if err != nil {
-			if sm.metrics != nil {
-				sm.metrics.recordReconnect(sm.ctx, "error")
-			}
-			sm.reportError(fmt.Errorf("dial %s: %w", sm.baseURL, err))
-			sleep := backoffCfg.NextBackOff()
-			if sleep == backoff.Stop {
-				sleep = binanceMaxReconnectInterval
-			}
-			select {
-			case <-sm.ctx.Done():
-				return context.Canceled
-			case <-time.After(sleep):
-				continue
+			sm.reportError(fmt.Errorf("dial %s: %w", dialURL, err))
+			if err := sm.waitReconnect(shared.ReconnectReasonDial); err != nil {
+				return err
 			}
+			continue
 		}
 
-		if sm.metrics != nil {
-			sm.metrics.recordReconnect(sm.ctx, "success")
-		}
+		sm.reconnect.Connected(sm.ctx)
 
 		sm.connMu.Lock()
 		sm.conn = conn
@@ -238,8 +244,6 @@ func (sm *streamManager) connect() error {
 			close(sm.ready)
 		})
 
-		backoffCfg.Reset()
-
 		// Resubscribe to all active streams after reconnection
 		if err := sm.subscribeAll(); err != nil {
 			sm.reportError(fmt.Errorf("resubscribe after reconnect: %w", err))
@@ -253,12 +257,12 @@ func (sm *streamManager) connect() error {
 
 		go func() {
 			defer wg.Done()
-			errCh <- sm.readLoop(connCtx, conn)
+			errCh <- shared.Disconnect(shared.ReconnectReasonRead, sm.readLoop(connCtx, conn))
 		}()
 
 		go func() {
 			defer wg.Done()
-			errCh <- sm.pingLoop(connCtx, conn)
+			errCh <- shared.Disconnect(shared.ReconnectReasonPing, sm.pingLoop(connCtx, conn))
 		}()
 
 		firstErr := <-errCh
@@ -286,17 +290,22 @@ func (sm *streamManager) connect() error {
 			sm.reportError(fmt.Errorf("connection loop: %w", aggregatedErr))
 		}
 
-		sleep := backoffCfg.NextBackOff()
-		if sleep == backoff.Stop {
-			sleep = binanceMaxReconnectInterval
-		}
 		// Back off before re-dialing to avoid hammering Binance when transient faults occur.
-		select {
-		case <-sm.ctx.Done():
+		if err := sm.waitReconnect(shared.DisconnectReason(aggregatedErr)); err != nil {
+			return err
+		}
+	}
+}
+
+// waitReconnect sleeps for the next reconnect interval, returning context.Canceled on shutdown.
+func (sm *streamManager) waitReconnect(reason string) error {
+	if err := sm.reconnect.Wait(sm.ctx, reason); err != nil {
+		if sm.ctx.Err() != nil {
 			return context.Canceled
-		case <-time.After(sleep):
 		}
+		return err
 	}
+	return nil
 }
 
 // subscribeAll sends a bulk SUBSCRIBE request for all active subscriptions.
@@ -486,7 +495,7 @@ func (sm *streamManager) pingLoop(ctx context.Context, conn *websocket.Conn) err
 // It distinguishes between control messages (subscribe/unsubscribe responses) and stream data.
 func (sm *streamManager) readLoop(ctx context.Context, conn *websocket.Conn) error {
 	for {
-		msgType, data, err := conn.Read(ctx)
+		msgType, message, err := readMessage(ctx, conn)
 		if err != nil {
 			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				return context.Canceled
@@ -504,27 +513,47 @@ func (sm *streamManager) readLoop(ctx context.Context, conn *websocket.Conn) err
 			return fmt.Errorf("read: %w", err)
 		}
 
-		if msgType != websocket.MessageText {
-			continue
+		if msgType == websocket.MessageText {
+			sm.handleMessage(ctx, message.Bytes())
 		}
+		message.Release()
+	}
+}
 
-		// Check if this is a response to a subscribe/unsubscribe request
-		var resp subscribeResponse
-		if err := json.Unmarshal(data, &resp); err == nil && resp.ID > 0 {
-			if resp.Error != nil {
-				sm.reportError(fmt.Errorf("websocket error (id=%d): code=%d, msg=%s", resp.ID, resp.Error.Code, resp.Error.Msg))
-			}
-			continue // Skip processing control messages
+// readMessage reads the next data message into a pooled buffer the caller releases.
+func readMessage(ctx context.Context, conn *websocket.Conn) (websocket.MessageType, *pool.JSONBuffer, error) {
+	msgType, reader, err := conn.Reader(ctx)
+	if err != nil {
+		return msgType, nil, err
+	}
+	message, err := pool.ReadJSONMessage(reader)
+	if err != nil {
+		return msgType, nil, err
+	}
+	return msgType, message, nil
+}
+
+// handleMessage dispatches one text message. data is only valid for the duration of the call.
+func (sm *streamManager) handleMessage(ctx context.Context, data []byte) {
+	// Check if this is a response to a subscribe/unsubscribe request
+	var resp subscribeResponse
+	if err := json.Unmarshal(data, &resp); err == nil && resp.ID > 0 {
+		if resp.Error != nil {
+			sm.reportError(fmt.Errorf("websocket error (id=%d): code=%d, msg=%s", resp.ID, resp.Error.Code, resp.Error.Msg))
 		}
+		return // Skip processing control messages
+	}
 
-		// Handle stream data
-		if sm.handler != nil {
-			if sm.metrics != nil {
-				sm.metrics.recordMessage(ctx, len(data))
-			}
-			if err := sm.handler(data); err != nil {
-				sm.reportError(fmt.Errorf("handle message: %w", err))
-			}
+	// Handle stream data
+	if sm.handler != nil {
+		if sm.metrics != nil {
+			sm.metrics.recordMessage(ctx, len(data))
+		}
+		start := time.Now()
+		err := sm.handler(data)
+		sm.stats.RecordMessage(ctx, len(data), time.Since(start), err)
+		if err != nil {
+			sm.reportError(fmt.Errorf("handle message: %w", err))
 		}
 	}
 }
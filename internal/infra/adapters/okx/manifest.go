@@ -4,10 +4,12 @@ package okx
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
 	"github.com/coachpo/meltica/internal/infra/pool"
 )
 
@@ -53,6 +55,27 @@ func RegisterFactory(reg *provider.Registry) {
 		if refresh, ok := durationFromConfig(userCfg, "instrument_refresh_interval"); ok {
 			opts.Config.InstrumentRefresh = refresh
 		}
+		if raw, ok := stringFromConfig(userCfg, "trade_mode"); ok {
+			if !slices.Contains(okxTradeModes, raw) {
+				return nil, fmt.Errorf("okx trade_mode: must be one of %s", strings.Join(okxTradeModes, ", "))
+			}
+			opts.Config.TradeMode = raw
+		}
+		reconnect, err := shared.ReconnectPolicyFromConfig(userCfg, shared.DefaultReconnectPolicy(okxMaxReconnectInterval))
+		if err != nil {
+			return nil, fmt.Errorf("okx %w", err)
+		}
+		opts.Config.Reconnect = reconnect
+		proxy, err := shared.ProxyConfigFromConfig(userCfg)
+		if err != nil {
+			return nil, fmt.Errorf("okx %w", err)
+		}
+		opts.Config.Proxy = proxy
+		endpoints, err := shared.EndpointsFromConfig(userCfg)
+		if err != nil {
+			return nil, fmt.Errorf("okx %w", err)
+		}
+		opts.Config.Endpoints = endpoints
 
 		provider := NewProvider(opts)
 		if err := provider.Start(ctx); err != nil {
@@ -1,10 +1,13 @@
 package okx
 
 import (
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
 	"github.com/coachpo/meltica/internal/infra/pool"
 )
 
@@ -24,6 +27,7 @@ type privateMetadata struct {
 	accountPath     string
 	orderPath       string
 	depthParam      string
+	pingPath        string
 }
 
 var okxPublicMetadata = publicMetadata{
@@ -42,6 +46,7 @@ var okxPrivateMetadata = privateMetadata{
 	accountPath:     "/api/v5/account/balance",
 	orderPath:       "/api/v5/trade/order",
 	depthParam:      "sz",
+	pingPath:        "/api/v5/public/time",
 }
 
 var okxAdapterMetadata = provider.AdapterMetadata{
@@ -53,13 +58,18 @@ var okxAdapterMetadata = provider.AdapterMetadata{
 		"market-data",
 		"trading",
 	},
+	TimeInForce: schema.TimeInForces,
 	SettingsSchema: []provider.AdapterSetting{
-		{Name: "api_key", Type: "string", Description: "API key for authenticated REST and user data streams", Default: "", Required: false},
-		{Name: "api_secret", Type: "string", Description: "API secret for signing REST requests", Default: "", Required: false},
-		{Name: "passphrase", Type: "string", Description: "API passphrase for OKX authentication", Default: "", Required: false},
-		{Name: "snapshot_depth", Type: "int", Description: "Order book snapshot depth for initial seeding", Default: defaultSnapshotDepth, Required: false},
-		{Name: "http_timeout", Type: "duration", Description: "HTTP client timeout for REST requests", Default: defaultHTTPTimeout.String(), Required: false},
-		{Name: "instrument_refresh_interval", Type: "duration", Description: "Interval between instrument metadata refreshes", Default: defaultInstrumentRefresh.String(), Required: false},
+		{Name: "api_key", Type: "string", Description: "API key for authenticated REST and user data streams", Default: "", Enum: nil, Required: false, Secret: true, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "api_secret", Type: "string", Description: "API secret for signing REST requests", Default: "", Enum: nil, Required: false, Secret: true, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "passphrase", Type: "string", Description: "API passphrase for OKX authentication", Default: "", Enum: nil, Required: false, Secret: true, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "snapshot_depth", Type: "int", Description: "Order book snapshot depth for initial seeding", Default: defaultSnapshotDepth, Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "http_timeout", Type: "duration", Description: "HTTP client timeout for REST requests", Default: defaultHTTPTimeout.String(), Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "instrument_refresh_interval", Type: "duration", Description: "Interval between instrument metadata refreshes", Default: defaultInstrumentRefresh.String(), Enum: nil, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		{Name: "trade_mode", Type: "string", Description: "Account trade mode sent as tdMode with every order: cash for spot accounts, cross or isolated for margin", Default: defaultTradeMode, Enum: okxTradeModes, Required: false, Secret: false, Fields: nil, Keyed: false, Validate: nil},
+		provider.EndpointsSetting(),
+		provider.ReconnectSetting(shared.DefaultReconnectPolicy(okxMaxReconnectInterval)),
+		provider.ProxySetting(),
 	},
 }
 
@@ -67,8 +77,12 @@ const (
 	defaultSnapshotDepth     = 100
 	defaultHTTPTimeout       = 10 * time.Second
 	defaultInstrumentRefresh = 15 * time.Minute
+	defaultTradeMode         = "cash"
 )
 
+// okxTradeModes lists the tdMode values OKX accepts for spot and margin orders.
+var okxTradeModes = []string{"cash", "cross", "isolated"}
+
 // Config captures user-overridable OKX settings.
 type Config struct {
 	Name              string
@@ -78,6 +92,11 @@ type Config struct {
 	SnapshotDepth     int
 	HTTPTimeout       time.Duration
 	InstrumentRefresh time.Duration
+	TradeMode         string
+	Reconnect         shared.ReconnectPolicy
+	Proxy             shared.ProxyConfig
+	// Endpoints lists regional endpoints; when set the fastest healthy one replaces the default URLs.
+	Endpoints []shared.Endpoint
 }
 
 // Options configure the OKX adapter.
@@ -87,6 +106,7 @@ type Options struct {
 
 	publicMeta  publicMetadata
 	privateMeta privateMetadata
+	endpoints   *shared.EndpointSelector
 }
 
 func withDefaults(in Options) Options {
@@ -104,11 +124,17 @@ func withDefaults(in Options) Options {
 	if in.Config.InstrumentRefresh <= 0 {
 		in.Config.InstrumentRefresh = defaultInstrumentRefresh
 	}
+	if !slices.Contains(okxTradeModes, in.Config.TradeMode) {
+		in.Config.TradeMode = defaultTradeMode
+	}
+	if in.Config.Reconnect.Validate() != nil {
+		in.Config.Reconnect = shared.DefaultReconnectPolicy(okxMaxReconnectInterval)
+	}
 	return in
 }
 
 func (o Options) restEndpoint(path string) string {
-	base := strings.TrimSuffix(strings.TrimSpace(o.privateMeta.apiBaseURL), "/")
+	base := strings.TrimSuffix(strings.TrimSpace(o.restBaseURL()), "/")
 	if base == "" {
 		return ""
 	}
@@ -122,6 +148,14 @@ func (o Options) restEndpoint(path string) string {
 	return base + "/" + trimmed
 }
 
+// restBaseURL returns the REST URL of the selected regional endpoint, or the default without one.
+func (o Options) restBaseURL() string {
+	if endpoint, ok := o.endpoints.Current(); ok && endpoint.REST != "" {
+		return endpoint.REST
+	}
+	return o.privateMeta.apiBaseURL
+}
+
 func (o Options) instrumentsEndpoint() string {
 	return o.restEndpoint(o.privateMeta.instrumentsPath)
 }
@@ -138,11 +172,21 @@ func (o Options) instrumentRefreshDuration() time.Duration {
 	return o.Config.InstrumentRefresh
 }
 
+func (o Options) reconnectPolicy() shared.ReconnectPolicy {
+	return o.Config.Reconnect
+}
+
 func (o Options) websocketURL() string {
+	if endpoint, ok := o.endpoints.Current(); ok && endpoint.Websocket != "" {
+		return endpoint.Websocket
+	}
 	return strings.TrimSpace(o.privateMeta.publicWSURL)
 }
 
 func (o Options) privateWebsocketURL() string {
+	if endpoint, ok := o.endpoints.Current(); ok && endpoint.PrivateWebsocket != "" {
+		return endpoint.PrivateWebsocket
+	}
 	return strings.TrimSpace(o.privateMeta.privateWSURL)
 }
 
@@ -34,6 +34,7 @@ type Provider struct {
 	pools  *pool.PoolManager
 	clock  func() time.Time
 	client *http.Client
+	egress *shared.Egress
 
 	events chan *schema.Event
 	errs   chan error
@@ -44,11 +45,13 @@ type Provider struct {
 	started atomic.Bool
 
 	publisher *shared.Publisher
+	streams   *shared.StreamStatsSet
 
 	instrumentsMu sync.RWMutex
 	instruments   map[string]schema.Instrument
 	metas         map[string]symbolMeta
 	instIDToSym   map[string]string
+	changes       *shared.InstrumentChangeLog // catalogue differences awaiting publication
 
 	wsMu sync.Mutex
 	ws   *wsManager
@@ -89,16 +92,19 @@ func NewProvider(opts Options) *Provider {
 		pools:           opts.Pools,
 		clock:           time.Now,
 		client:          nil,
+		egress:          shared.NewEgress(opts.Config.Proxy),
 		events:          make(chan *schema.Event, 2048),
 		errs:            make(chan error, 32),
 		ctx:             nil,
 		cancel:          nil,
 		started:         atomic.Bool{},
 		publisher:       nil,
+		streams:         shared.NewStreamStatsSet(okxPublicMetadata.identifier, opts.Config.Name),
 		instrumentsMu:   sync.RWMutex{},
 		instruments:     make(map[string]schema.Instrument),
 		metas:           make(map[string]symbolMeta),
 		instIDToSym:     make(map[string]string),
+		changes:         shared.NewInstrumentChangeLog(),
 		wsMu:            sync.Mutex{},
 		ws:              nil,
 		tradeMu:         sync.Mutex{},
@@ -119,6 +125,11 @@ func NewProvider(opts Options) *Provider {
 		panic("okx/provider: nil PoolManager in options")
 	}
 	p.publisher = shared.NewPublisher(p.name, p.events, p.pools, p.clock)
+	// The probe client is built before the failure hook so failed probes do not trigger re-probes.
+	p.opts.endpoints = shared.NewEndpointSelector(opts.Config.Endpoints, shared.HTTPEndpointProbe(p.egress.HTTPClient(opts.httpTimeoutDuration()), opts.privateMeta.pingPath))
+	if p.opts.endpoints != nil {
+		p.egress.OnTransportFailure(p.reprobeEndpoints)
+	}
 	return p
 }
 
@@ -143,6 +154,8 @@ func (p *Provider) Start(ctx context.Context) error {
 	p.ctx = runCtx
 	p.cancel = cancel
 
+	p.selectEndpoint(runCtx)
+
 	if err := p.refreshInstruments(runCtx); err != nil {
 		p.started.Store(false)
 		cancel()
@@ -189,6 +202,9 @@ func (p *Provider) SubmitOrder(ctx context.Context, req schema.OrderRequest) err
 	if err := p.ensureRunning(); err != nil {
 		return err
 	}
+	if subAccount := strings.TrimSpace(req.SubAccount); subAccount != "" {
+		return fmt.Errorf("okx: sub-account %q not supported", subAccount)
+	}
 	meta, ok := p.metaForInstrument(req.Symbol)
 	if !ok {
 		if err := p.refreshInstruments(p.ctx); err == nil {
@@ -299,6 +315,7 @@ func (p *Provider) refreshInstruments(ctx context.Context) error {
 		return err
 	}
 	p.instrumentsMu.Lock()
+	previous := p.instruments
 	p.instruments = make(map[string]schema.Instrument, len(list))
 	p.metas = metas
 	p.instIDToSym = make(map[string]string, len(metas))
@@ -306,6 +323,14 @@ func (p *Provider) refreshInstruments(ctx context.Context) error {
 		cloned := schema.CloneInstrument(inst)
 		p.instruments[inst.Symbol] = cloned
 	}
+	for symbol, prev := range previous {
+		if _, ok := p.instruments[symbol]; !ok {
+			// Instruments dropped from the listing are retained as delisted so consumers see the transition.
+			prev.Status = schema.InstrumentStatusDelisted
+			p.instruments[symbol] = prev
+		}
+	}
+	p.changes.Record(previous, p.instruments)
 	for symbol, meta := range metas {
 		p.instIDToSym[strings.ToUpper(meta.instID)] = symbol
 	}
@@ -330,27 +355,58 @@ func (p *Provider) instrumentRefreshLoop() {
 }
 
 func (p *Provider) publishInstrumentUpdates() {
-	p.instrumentsMu.RLock()
-	defer p.instrumentsMu.RUnlock()
+	p.instrumentsMu.Lock()
+	defer p.instrumentsMu.Unlock()
 	for _, inst := range p.instruments {
-		payload := schema.InstrumentUpdatePayload{Instrument: schema.CloneInstrument(inst)}
+		payload := schema.InstrumentUpdatePayload{Instrument: schema.CloneInstrument(inst), PreviousStatus: "", Changes: p.changes.Take(inst.Symbol)}
+		if previous, changed := shared.PreviousStatus(payload.Changes); changed {
+			payload.PreviousStatus = previous
+		}
+		for _, change := range payload.Changes {
+			log.Printf("okx/provider: %s instrument %s changed: %s", p.name, inst.Symbol, change)
+		}
 		p.publisher.PublishInstrumentUpdate(p.ctx, inst.Symbol, payload)
 	}
 }
 
 func (p *Provider) httpClient() *http.Client {
 	if p.client == nil {
-		client := &http.Client{
-			Transport:     nil,
-			CheckRedirect: nil,
-			Jar:           nil,
-			Timeout:       p.opts.httpTimeoutDuration(),
-		}
-		p.client = client
+		p.client = p.egress.HTTPClient(p.opts.httpTimeoutDuration())
 	}
 	return p.client
 }
 
+// ConnectionDiagnostics reports REST and websocket connectivity, including the egress proxy and
+// the regional endpoint selection.
+func (p *Provider) ConnectionDiagnostics() shared.ConnectionDiagnostics {
+	diagnostics := p.egress.Diagnostics()
+	diagnostics.Endpoints = p.opts.endpoints.Status()
+	diagnostics.Streams = p.streams.Diagnostics()
+	return diagnostics
+}
+
+// selectEndpoint probes the regional endpoints and selects the fastest healthy one.
+func (p *Provider) selectEndpoint(ctx context.Context) {
+	if p.opts.endpoints == nil {
+		return
+	}
+	p.opts.endpoints.Probe(ctx)
+	if endpoint, ok := p.opts.endpoints.Current(); ok {
+		log.Printf("okx/provider: %s using %s endpoint %s", p.name, endpoint.Region, endpoint.REST)
+	}
+}
+
+// reprobeEndpoints re-evaluates the regional endpoints after a transport failure. REST requests
+// switch immediately; websocket streams move on their next reconnect.
+func (p *Provider) reprobeEndpoints(error) {
+	if p.ctx == nil {
+		return
+	}
+	p.opts.endpoints.ReportFailure(p.ctx, func(endpoint shared.Endpoint) {
+		log.Printf("okx/provider: %s switched to %s endpoint %s", p.name, endpoint.Region, endpoint.REST)
+	})
+}
+
 func (p *Provider) startExtensionEmitter() {
 	if p == nil || p.publisher == nil {
 		return
@@ -517,6 +573,12 @@ func (p *Provider) configureOrderBookStreams(instruments []string) error {
 	return p.ws.subscribe(args)
 }
 
+// ConfigureBookRoute applies the depth and cadence of the order book route to published snapshots.
+// Local books stay complete so checksums keep validating against the venue.
+func (p *Provider) ConfigureBookRoute(opts dispatcher.BookOptions) {
+	p.publisher.SetBookOptions(opts)
+}
+
 func (p *Provider) unsubscribeOrderBookStreams(instruments []string) error {
 	if len(instruments) == 0 {
 		return nil
@@ -564,11 +626,11 @@ func (p *Provider) ensureWS() error {
 	if p.ws != nil {
 		return nil
 	}
-	baseURL := p.opts.websocketURL()
-	if strings.TrimSpace(baseURL) == "" {
+	if strings.TrimSpace(p.opts.websocketURL()) == "" {
 		return errors.New("okx: websocket url not configured")
 	}
-	manager := newWSManager(p.ctx, baseURL, p.handleWSMessage, p.errs)
+	manager := newWSManager(p.ctx, p.opts.websocketURL, p.handleWSMessage, p.errs, "public", p.name, p.opts.reconnectPolicy(), p.egress.WebsocketDialOptions())
+	manager.stats = p.streams.Stream("public")
 	if err := manager.start(); err != nil {
 		return fmt.Errorf("start ws manager: %w", err)
 	}
@@ -594,7 +656,7 @@ func (p *Provider) handleTrades(envelope wsEnvelope) error {
 	for _, raw := range envelope.Data {
 		var evt tradeEvent
 		if err := json.Unmarshal(raw, &evt); err != nil {
-			return fmt.Errorf("decode trade event: %w", err)
+			return shared.DecodeError(fmt.Errorf("decode trade event: %w", err))
 		}
 		instID := strings.TrimSpace(evt.InstID)
 		if instID == "" {
@@ -631,7 +693,7 @@ func (p *Provider) handleTickers(envelope wsEnvelope) error {
 	for _, raw := range envelope.Data {
 		var evt tickerEvent
 		if err := json.Unmarshal(raw, &evt); err != nil {
-			return fmt.Errorf("decode ticker event: %w", err)
+			return shared.DecodeError(fmt.Errorf("decode ticker event: %w", err))
 		}
 		instID := strings.TrimSpace(evt.InstID)
 		if instID == "" {
@@ -662,7 +724,7 @@ func (p *Provider) handleBooks(envelope wsEnvelope) error {
 	for _, raw := range envelope.Data {
 		var evt bookEvent
 		if err := json.Unmarshal(raw, &evt); err != nil {
-			return fmt.Errorf("decode book event: %w", err)
+			return shared.DecodeError(fmt.Errorf("decode book event: %w", err))
 		}
 		instID := strings.TrimSpace(evt.InstID)
 		if instID == "" {
@@ -1013,6 +1075,9 @@ func extractInstruments(filters []dispatcher.FilterRule, provider string) []stri
 	}
 
 	for _, filter := range filters {
+		if !filter.Selects() {
+			continue
+		}
 		field := strings.TrimSpace(strings.ToLower(filter.Field))
 		if field == "instrument" {
 			addValues(global, filter.Value)
@@ -1092,12 +1157,12 @@ func (p *Provider) ensurePrivateWS() error {
 		return nil
 	}
 
-	baseURL := p.opts.privateWebsocketURL()
-	if strings.TrimSpace(baseURL) == "" {
+	if strings.TrimSpace(p.opts.privateWebsocketURL()) == "" {
 		return errors.New("okx: private websocket url not configured")
 	}
 
-	manager := newWSManager(p.ctx, baseURL, p.handlePrivateWSMessage, p.errs)
+	manager := newWSManager(p.ctx, p.opts.privateWebsocketURL, p.handlePrivateWSMessage, p.errs, "private", p.name, p.opts.reconnectPolicy(), p.egress.WebsocketDialOptions())
+	manager.stats = p.streams.Stream("private")
 	manager.setAuthFunc(p.generateLoginRequest)
 
 	if err := manager.start(); err != nil {
@@ -1170,7 +1235,7 @@ func (p *Provider) handleOrders(envelope wsEnvelope) error {
 	for _, raw := range envelope.Data {
 		var order orderUpdateEvent
 		if err := json.Unmarshal(raw, &order); err != nil {
-			return fmt.Errorf("decode order event: %w", err)
+			return shared.DecodeError(fmt.Errorf("decode order event: %w", err))
 		}
 
 		symbol, ok := p.symbolForInstID(order.InstID)
@@ -1211,7 +1276,7 @@ func (p *Provider) handleAccount(envelope wsEnvelope) error {
 	for _, raw := range envelope.Data {
 		var acct accountUpdateEvent
 		if err := json.Unmarshal(raw, &acct); err != nil {
-			return fmt.Errorf("decode account event: %w", err)
+			return shared.DecodeError(fmt.Errorf("decode account event: %w", err))
 		}
 
 		for _, detail := range acct.Details {
@@ -145,6 +145,7 @@ func buildInstrument(record instrumentRecord, venue string) (schema.Instrument,
 		MinNotional:       "",
 		MinQuantity:       strings.TrimSpace(record.MinSz),
 		MaxQuantity:       "",
+		Status:            schema.InstrumentStatusTrading,
 	}
 
 	if pricePrec, ok := precisionFromStep(record.TickSz); ok {
@@ -341,7 +342,11 @@ func (p *Provider) submitOrder(ctx context.Context, meta symbolMeta, req schema.
 		return err
 	}
 
-	ordType, err := okxOrderType(req.OrderType)
+	tif := req.TIF
+	if req.PostOnly {
+		tif = schema.TimeInForceGTX
+	}
+	ordType, err := okxOrderType(req.OrderType, tif)
 	if err != nil {
 		return err
 	}
@@ -363,7 +368,7 @@ func (p *Provider) submitOrder(ctx context.Context, meta symbolMeta, req schema.
 
 	orderReq := orderRequest{
 		InstID:  meta.instID,
-		TdMode:  "cash",
+		TdMode:  p.opts.Config.TradeMode,
 		Side:    side,
 		OrdType: ordType,
 		Sz:      quantity,
@@ -501,12 +506,27 @@ func okxSide(side schema.TradeSide) (string, error) {
 	}
 }
 
-func okxOrderType(orderType schema.OrderType) (string, error) {
+// okxOrderType maps an order type and time in force onto ordType, which carries both on OKX.
+func okxOrderType(orderType schema.OrderType, tif schema.TimeInForce) (string, error) {
 	switch orderType {
 	case schema.OrderTypeMarket:
+		if tif != "" && tif != schema.TimeInForceIOC {
+			return "", fmt.Errorf("okx: market orders do not support time in force %s", tif)
+		}
 		return "market", nil
 	case schema.OrderTypeLimit:
-		return "limit", nil
+		switch tif {
+		case "", schema.TimeInForceGTC:
+			return "limit", nil
+		case schema.TimeInForceIOC:
+			return "ioc", nil
+		case schema.TimeInForceFOK:
+			return "fok", nil
+		case schema.TimeInForceGTX:
+			return "post_only", nil
+		default:
+			return "", fmt.Errorf("okx: unsupported time in force %q", tif)
+		}
 	default:
 		return "", fmt.Errorf("okx: unknown order type %v", orderType)
 	}
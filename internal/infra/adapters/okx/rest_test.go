@@ -3,6 +3,9 @@ package okx
 import (
 	"testing"
 
+	"github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/provider"
 	"github.com/coachpo/meltica/internal/domain/schema"
 )
 
@@ -81,3 +84,40 @@ func TestConvertDiffLevels(t *testing.T) {
 		t.Fatalf("unexpected second diff level: %+v", converted[1])
 	}
 }
+
+func TestTradeModeSetting(t *testing.T) {
+	if opts := withDefaults(Options{}); opts.Config.TradeMode != defaultTradeMode {
+		t.Fatalf("expected default trade mode %s, got %s", defaultTradeMode, opts.Config.TradeMode)
+	}
+	if err := provider.ValidateAdapterSettings("okx", okxAdapterMetadata.SettingsSchema, map[string]any{"trade_mode": "cross"}); err != nil {
+		t.Fatalf("expected cross to be accepted, got %v", err)
+	}
+	if err := provider.ValidateAdapterSettings("okx", okxAdapterMetadata.SettingsSchema, map[string]any{"trade_mode": "margin"}); err == nil {
+		t.Fatal("expected unknown trade mode to be rejected")
+	}
+	schema := provider.BuildConfigSchema(okxAdapterMetadata)
+	if got := schema.Properties["trade_mode"].Enum; len(got) != len(okxTradeModes) {
+		t.Fatalf("expected trade_mode choices in config schema, got %v", got)
+	}
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("marshal config schema: %v", err)
+	}
+}
+
+func TestOKXOrderTypeMapsTimeInForce(t *testing.T) {
+	cases := map[schema.TimeInForce]string{
+		"":                    "limit",
+		schema.TimeInForceGTC: "limit",
+		schema.TimeInForceIOC: "ioc",
+		schema.TimeInForceFOK: "fok",
+		schema.TimeInForceGTX: "post_only",
+	}
+	for tif, want := range cases {
+		if got, err := okxOrderType(schema.OrderTypeLimit, tif); err != nil || got != want {
+			t.Fatalf("%q: expected %s, got %s (%v)", tif, want, got, err)
+		}
+	}
+	if _, err := okxOrderType(schema.OrderTypeMarket, schema.TimeInForceGTX); err == nil {
+		t.Fatal("expected post-only market order rejected")
+	}
+}
@@ -1,6 +1,7 @@
 package okx
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,9 +11,11 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/cenkalti/backoff/v5"
 	"github.com/coder/websocket"
 	"github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/infra/adapters/shared"
+	"github.com/coachpo/meltica/internal/infra/pool"
 )
 
 const (
@@ -54,9 +57,11 @@ type wsEnvelope struct {
 type wsMessageHandler func(wsEnvelope) error
 
 type wsManager struct {
-	baseURL string
-	ctx     context.Context
-	cancel  context.CancelFunc
+	// baseURL resolves the stream URL on every dial so reconnects follow the endpoint selection.
+	baseURL  func() string
+	dialOpts *websocket.DialOptions
+	ctx      context.Context
+	cancel   context.CancelFunc
 
 	conn   *websocket.Conn
 	connMu sync.RWMutex
@@ -75,13 +80,16 @@ type wsManager struct {
 	controlMu       sync.Mutex
 	lastControlSend time.Time
 
-	authFunc func() *wsRequest
+	authFunc  func() *wsRequest
+	reconnect *shared.Reconnector
+	stats     *shared.StreamStats
 }
 
-func newWSManager(ctx context.Context, baseURL string, handler wsMessageHandler, errs chan<- error) *wsManager {
+func newWSManager(ctx context.Context, baseURL func() string, handler wsMessageHandler, errs chan<- error, stream, providerName string, policy shared.ReconnectPolicy, dialOpts *websocket.DialOptions) *wsManager {
 	managerCtx, cancel := context.WithCancel(ctx)
 	return &wsManager{
 		baseURL:         baseURL,
+		dialOpts:        dialOpts,
 		ctx:             managerCtx,
 		cancel:          cancel,
 		conn:            nil,
@@ -96,6 +104,8 @@ func newWSManager(ctx context.Context, baseURL string, handler wsMessageHandler,
 		controlMu:       sync.Mutex{},
 		lastControlSend: time.Time{},
 		authFunc:        nil,
+		reconnect:       shared.NewReconnector(policy, shared.NewReconnectMetrics(okxPublicMetadata.identifier, providerName, stream)),
+		stats:           nil,
 	}
 }
 
@@ -171,9 +181,6 @@ func (sm *wsManager) unsubscribe(subs []wsArgument) error {
 }
 
 func (sm *wsManager) connectLoop() error {
-	backoffCfg := backoff.NewExponentialBackOff()
-	backoffCfg.MaxInterval = okxMaxReconnectInterval
-
 	for {
 		select {
 		case <-sm.ctx.Done():
@@ -181,19 +188,14 @@ func (sm *wsManager) connectLoop() error {
 		default:
 		}
 
-		conn, _, err := websocket.Dial(sm.ctx, sm.baseURL, nil)
+		dialURL := sm.baseURL()
+		conn, _, err := websocket.Dial(sm.ctx, dialURL, sm.dialOpts)
 		if err != nil {
-			sm.reportError(fmt.Errorf("dial %s: %w", sm.baseURL, err))
-			sleep := backoffCfg.NextBackOff()
-			if sleep == backoff.Stop {
-				sleep = okxMaxReconnectInterval
-			}
-			select {
-			case <-sm.ctx.Done():
-				return context.Canceled
-			case <-time.After(sleep):
-				continue
+			sm.reportError(fmt.Errorf("dial %s: %w", dialURL, err))
+			if err := sm.waitReconnect(shared.ReconnectReasonDial); err != nil {
+				return err
 			}
+			continue
 		}
 
 		sm.connMu.Lock()
@@ -210,7 +212,7 @@ func (sm *wsManager) connectLoop() error {
 			close(sm.ready)
 		})
 
-		backoffCfg.Reset()
+		sm.reconnect.Connected(sm.ctx)
 
 		if err := sm.subscribeAll(); err != nil {
 			sm.reportError(fmt.Errorf("resubscribe after reconnect: %w", err))
@@ -223,12 +225,12 @@ func (sm *wsManager) connectLoop() error {
 
 		go func() {
 			defer wg.Done()
-			errCh <- sm.readLoop(connCtx, conn)
+			errCh <- shared.Disconnect(shared.ReconnectReasonRead, sm.readLoop(connCtx, conn))
 		}()
 
 		go func() {
 			defer wg.Done()
-			errCh <- sm.pingLoop(connCtx, conn)
+			errCh <- shared.Disconnect(shared.ReconnectReasonPing, sm.pingLoop(connCtx, conn))
 		}()
 
 		firstErr := <-errCh
@@ -255,16 +257,20 @@ func (sm *wsManager) connectLoop() error {
 			sm.reportError(fmt.Errorf("okx websocket connection loop: %w", aggregatedErr))
 		}
 
-		sleep := backoffCfg.NextBackOff()
-		if sleep == backoff.Stop {
-			sleep = okxMaxReconnectInterval
+		if err := sm.waitReconnect(shared.DisconnectReason(aggregatedErr)); err != nil {
+			return err
 		}
-		select {
-		case <-sm.ctx.Done():
+	}
+}
+
+func (sm *wsManager) waitReconnect(reason string) error {
+	if err := sm.reconnect.Wait(sm.ctx, reason); err != nil {
+		if sm.ctx.Err() != nil {
 			return context.Canceled
-		case <-time.After(sleep):
 		}
+		return err
 	}
+	return nil
 }
 
 func (sm *wsManager) subscribeAll() error {
@@ -406,37 +412,52 @@ func (sm *wsManager) readLoop(ctx context.Context, conn *websocket.Conn) error {
 			return context.Canceled
 		default:
 		}
-		_, data, err := conn.Read(ctx)
+		_, reader, err := conn.Reader(ctx)
 		if err != nil {
 			return fmt.Errorf("read websocket: %w", err)
 		}
-		trimmed := strings.TrimSpace(string(data))
-		if trimmed == "" {
-			continue
-		}
-		if trimmed == "pong" {
-			continue
-		}
-		if strings.Contains(trimmed, "\"event\":\"ping\"") {
-			_ = sm.writePong(ctx, conn)
-			continue
-		}
-		var envelope wsEnvelope
-		if err := json.Unmarshal(data, &envelope); err != nil {
-			sm.reportError(fmt.Errorf("decode websocket message: %w", err))
-			continue
-		}
-		if envelope.Event == "error" {
-			sm.reportError(fmt.Errorf("okx websocket error %s: %s", strings.TrimSpace(envelope.Code), strings.TrimSpace(envelope.Msg)))
-			continue
-		}
-		if len(envelope.Data) == 0 {
-			continue
+		message, err := pool.ReadJSONMessage(reader)
+		if err != nil {
+			return fmt.Errorf("read websocket: %w", err)
 		}
-		if sm.handler != nil {
-			if err := sm.handler(envelope); err != nil {
-				sm.reportError(fmt.Errorf("handle websocket message: %w", err))
-			}
+		sm.handleMessage(ctx, conn, message.Bytes())
+		message.Release()
+	}
+}
+
+// handleMessage dispatches one message. data lives in a pooled buffer and is only valid for the
+// duration of the call; the decoded envelope does not reference it.
+func (sm *wsManager) handleMessage(ctx context.Context, conn *websocket.Conn, data []byte) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return
+	}
+	if string(trimmed) == "pong" {
+		return
+	}
+	if bytes.Contains(trimmed, []byte("\"event\":\"ping\"")) {
+		_ = sm.writePong(ctx, conn)
+		return
+	}
+	var envelope wsEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		sm.stats.RecordDecodeError(ctx, len(data))
+		sm.reportError(fmt.Errorf("decode websocket message: %w", err))
+		return
+	}
+	if envelope.Event == "error" {
+		sm.reportError(fmt.Errorf("okx websocket error %s: %s", strings.TrimSpace(envelope.Code), strings.TrimSpace(envelope.Msg)))
+		return
+	}
+	if len(envelope.Data) == 0 {
+		return
+	}
+	if sm.handler != nil {
+		start := time.Now()
+		err := sm.handler(envelope)
+		sm.stats.RecordMessage(ctx, len(data), time.Since(start), err)
+		if err != nil {
+			sm.reportError(fmt.Errorf("handle websocket message: %w", err))
 		}
 	}
 }
@@ -0,0 +1,128 @@
+package shared
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+// BookRouteConfigurer is implemented by adapters that honour per-route order book options. The
+// subscription manager calls it before activating an order book route whose options changed.
+type BookRouteConfigurer interface {
+	ConfigureBookRoute(opts dispatcher.BookOptions)
+}
+
+// bookCadence holds back snapshots of an instrument published within the route interval of the
+// previous one and publishes the newest of them once the interval has elapsed.
+type bookCadence struct {
+	mu    sync.Mutex
+	opts  dispatcher.BookOptions
+	books map[string]*cadencedBook
+}
+
+type cadencedBook struct {
+	lastEmit time.Time
+	pending  *schema.BookSnapshotPayload
+	ctx      context.Context
+	timer    *time.Timer
+}
+
+func newBookCadence() *bookCadence {
+	return &bookCadence{
+		mu:    sync.Mutex{},
+		opts:  dispatcher.BookOptions{Depth: 0, Interval: 0},
+		books: make(map[string]*cadencedBook),
+	}
+}
+
+// SetBookOptions applies the depth and cadence of the active order book route to subsequent
+// snapshots. Snapshots held back under the previous interval are still published when it ends.
+func (p *Publisher) SetBookOptions(opts dispatcher.BookOptions) {
+	p.books.mu.Lock()
+	p.books.opts = opts
+	p.books.mu.Unlock()
+}
+
+// BookOptions returns the order book options currently applied.
+func (p *Publisher) BookOptions() dispatcher.BookOptions {
+	p.books.mu.Lock()
+	defer p.books.mu.Unlock()
+	return p.books.opts
+}
+
+// PublishBookSnapshot creates and emits an order book snapshot event, truncated to the route depth
+// and held back while the route interval since the previous snapshot of symbol has not elapsed.
+func (p *Publisher) PublishBookSnapshot(ctx context.Context, symbol string, payload schema.BookSnapshotPayload) {
+	p.books.mu.Lock()
+	opts := p.books.opts
+	if opts.Depth > 0 {
+		payload.Bids = truncateLevels(payload.Bids, opts.Depth)
+		payload.Asks = truncateLevels(payload.Asks, opts.Depth)
+	}
+	if opts.Interval <= 0 {
+		p.books.mu.Unlock()
+		p.emitBookSnapshot(ctx, symbol, payload)
+		return
+	}
+	book, ok := p.books.books[symbol]
+	if !ok {
+		book = &cadencedBook{lastEmit: time.Time{}, pending: nil, ctx: nil, timer: nil}
+		p.books.books[symbol] = book
+	}
+	now := p.clock()
+	if wait := book.lastEmit.Add(opts.Interval).Sub(now); wait > 0 {
+		book.pending = &payload
+		book.ctx = ctx
+		if book.timer == nil {
+			book.timer = time.AfterFunc(wait, func() { p.flushBookSnapshot(symbol) })
+		}
+		p.books.mu.Unlock()
+		return
+	}
+	book.lastEmit = now
+	book.pending = nil
+	book.ctx = nil
+	p.books.mu.Unlock()
+	p.emitBookSnapshot(ctx, symbol, payload)
+}
+
+func (p *Publisher) flushBookSnapshot(symbol string) {
+	p.books.mu.Lock()
+	book, ok := p.books.books[symbol]
+	if !ok || book.pending == nil {
+		if ok {
+			book.timer = nil
+		}
+		p.books.mu.Unlock()
+		return
+	}
+	payload, ctx := *book.pending, book.ctx
+	book.pending = nil
+	book.ctx = nil
+	book.timer = nil
+	book.lastEmit = p.clock()
+	p.books.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	p.emitBookSnapshot(ctx, symbol, payload)
+}
+
+func (p *Publisher) emitBookSnapshot(ctx context.Context, symbol string, payload schema.BookSnapshotPayload) {
+	seq := p.nextSeq(schema.EventTypeBookSnapshot, symbol)
+	evt := p.newEvent(ctx, schema.EventTypeBookSnapshot, symbol, seq, payload, payload.LastUpdate)
+	if evt == nil {
+		return
+	}
+	p.emitEvent(ctx, evt)
+}
+
+func truncateLevels(levels []schema.PriceLevel, depth int) []schema.PriceLevel {
+	if len(levels) <= depth {
+		return levels
+	}
+	return levels[:depth:depth]
+}
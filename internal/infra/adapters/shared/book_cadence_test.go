@@ -0,0 +1,83 @@
+package shared
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+func newBookTestPublisher(t *testing.T, clock func() time.Time) (*Publisher, chan *schema.Event) {
+	t.Helper()
+	pools := pool.NewPoolManager()
+	if err := pools.RegisterPool("Event", 16, 0, func() any { return new(schema.Event) }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	events := make(chan *schema.Event, 16)
+	return NewPublisher("binance", events, pools, clock), events
+}
+
+func bookLevels(count int) []schema.PriceLevel {
+	levels := make([]schema.PriceLevel, count)
+	for i := range levels {
+		levels[i] = schema.PriceLevel{Price: strconv.Itoa(100 - i), Quantity: "1"}
+	}
+	return levels
+}
+
+func TestPublisherTruncatesBookToRouteDepth(t *testing.T) {
+	publisher, events := newBookTestPublisher(t, nil)
+	publisher.SetBookOptions(dispatcher.BookOptions{Depth: 5})
+
+	publisher.PublishBookSnapshot(context.Background(), "BTC-USDT", schema.BookSnapshotPayload{Bids: bookLevels(20), Asks: bookLevels(3)})
+
+	evt := <-events
+	payload := evt.Payload.(schema.BookSnapshotPayload)
+	if len(payload.Bids) != 5 || len(payload.Asks) != 3 {
+		t.Fatalf("expected 5 bids and 3 asks, got %d and %d", len(payload.Bids), len(payload.Asks))
+	}
+}
+
+func TestPublisherHoldsBackBooksWithinInterval(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	publisher, events := newBookTestPublisher(t, clock)
+	publisher.SetBookOptions(dispatcher.BookOptions{Interval: 50 * time.Millisecond})
+	ctx := context.Background()
+
+	publisher.PublishBookSnapshot(ctx, "BTC-USDT", schema.BookSnapshotPayload{Bids: bookLevels(1)})
+	publisher.PublishBookSnapshot(ctx, "BTC-USDT", schema.BookSnapshotPayload{Bids: bookLevels(2)})
+	publisher.PublishBookSnapshot(ctx, "BTC-USDT", schema.BookSnapshotPayload{Bids: bookLevels(3)})
+	publisher.PublishBookSnapshot(ctx, "ETH-USDT", schema.BookSnapshotPayload{Bids: bookLevels(4)})
+
+	first := <-events
+	second := <-events
+	if first.Symbol != "BTC-USDT" || second.Symbol != "ETH-USDT" {
+		t.Fatalf("expected one immediate snapshot per instrument, got %s and %s", first.Symbol, second.Symbol)
+	}
+	select {
+	case evt := <-events:
+		t.Fatalf("expected the interval to hold back snapshots, got %+v", evt)
+	default:
+	}
+
+	select {
+	case evt := <-events:
+		payload := evt.Payload.(schema.BookSnapshotPayload)
+		if evt.Symbol != "BTC-USDT" || len(payload.Bids) != 3 {
+			t.Fatalf("expected the newest held-back book, got %s with %d bids", evt.Symbol, len(payload.Bids))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the held-back snapshot once the interval elapsed")
+	}
+}
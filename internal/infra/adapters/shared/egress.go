@@ -0,0 +1,309 @@
+package shared
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// ProxyConfigKey is the provider config key holding the egress proxy setting.
+const ProxyConfigKey = "proxy"
+
+// ProxyConfig routes adapter REST and websocket traffic through an egress proxy.
+type ProxyConfig struct {
+	// URL is the proxy endpoint. http and https proxies tunnel with HTTP CONNECT; socks5 and
+	// socks5h use SOCKS5, the latter resolving venue hostnames on the proxy.
+	URL      string
+	Username string
+	Password string
+}
+
+// Enabled reports whether a proxy is configured.
+func (p ProxyConfig) Enabled() bool {
+	return strings.TrimSpace(p.URL) != ""
+}
+
+// Validate reports whether the proxy URL can be used.
+func (p ProxyConfig) Validate() error {
+	_, err := p.proxyURL()
+	return err
+}
+
+// Redacted renders the proxy endpoint without credentials.
+func (p ProxyConfig) Redacted() string {
+	parsed, err := p.proxyURL()
+	if err != nil || parsed == nil {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+func (p ProxyConfig) proxyURL() (*url.URL, error) {
+	raw := strings.TrimSpace(p.URL)
+	if raw == "" {
+		return nil, nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q, expected http, https, socks5 or socks5h", parsed.Scheme)
+	}
+	if parsed.User != nil {
+		return nil, fmt.Errorf("credentials belong in username and password, not the url")
+	}
+	if parsed.Hostname() == "" || parsed.Port() == "" {
+		return nil, fmt.Errorf("url must include host and port")
+	}
+	if parsed.Path != "" && parsed.Path != "/" {
+		return nil, fmt.Errorf("url must not include a path")
+	}
+	out := &url.URL{Scheme: strings.ToLower(parsed.Scheme), Host: parsed.Host} //nolint:exhaustruct // only scheme, host and credentials apply to proxies
+	if p.Username != "" {
+		out.User = url.UserPassword(p.Username, p.Password)
+	}
+	return out, nil
+}
+
+// ProxyConfigFromConfig reads the proxy setting in cfg. It accepts a URL string or a mapping
+// with url, username and password.
+func ProxyConfigFromConfig(cfg map[string]any) (ProxyConfig, error) {
+	var empty ProxyConfig
+	raw, ok := cfg[ProxyConfigKey]
+	if !ok || raw == nil {
+		return empty, nil
+	}
+	proxy, err := ParseProxyConfig(raw)
+	if err != nil {
+		return empty, fmt.Errorf("%s: %w", ProxyConfigKey, err)
+	}
+	return proxy, nil
+}
+
+// ParseProxyConfig parses a proxy setting value: a URL string or a mapping with url, username and
+// password.
+func ParseProxyConfig(raw any) (ProxyConfig, error) {
+	var proxy, empty ProxyConfig
+	switch v := raw.(type) {
+	case string:
+		proxy.URL = strings.TrimSpace(v)
+	case map[string]any:
+		for key, value := range v {
+			text, ok := value.(string)
+			if !ok && value != nil {
+				return empty, fmt.Errorf("%s: expected string, got %T", key, value)
+			}
+			switch strings.TrimSpace(key) {
+			case "url":
+				proxy.URL = strings.TrimSpace(text)
+			case "username":
+				proxy.Username = text
+			case "password":
+				proxy.Password = text
+			default:
+				return empty, fmt.Errorf("%s: unknown setting", key)
+			}
+		}
+	default:
+		return empty, fmt.Errorf("must be a url or a mapping")
+	}
+	if !proxy.Enabled() && proxy.Username != "" {
+		return empty, fmt.Errorf("username requires url")
+	}
+	if err := proxy.Validate(); err != nil {
+		return empty, err
+	}
+	return proxy, nil
+}
+
+// ConnectionDiagnostics summarises an adapter's outbound REST and websocket connectivity.
+type ConnectionDiagnostics struct {
+	Proxy     string              `json:"proxy,omitempty"`
+	REST      EndpointDiagnostics `json:"rest"`
+	Websocket EndpointDiagnostics `json:"websocket"`
+	// Endpoints reports the regional endpoint selection of adapters configured with endpoints.
+	Endpoints *EndpointSelection `json:"endpoints,omitempty"`
+	// Streams reports the message rates of each websocket stream.
+	Streams []StreamDiagnostics `json:"streams,omitempty"`
+}
+
+// EndpointDiagnostics counts the requests or handshakes made to one kind of venue endpoint.
+// Failures are transport errors such as refused connections or proxy rejections; HTTP error
+// statuses count as successful round trips.
+type EndpointDiagnostics struct {
+	Requests      uint64     `json:"requests"`
+	Failures      uint64     `json:"failures"`
+	LastSuccessAt *time.Time `json:"lastSuccessAt,omitempty"`
+	LastFailureAt *time.Time `json:"lastFailureAt,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	LastLatencyMs float64    `json:"lastLatencyMs,omitempty"`
+}
+
+// Egress builds the HTTP clients and websocket dial options an adapter uses to reach its venue.
+// Both route through the configured proxy and record connection diagnostics.
+type Egress struct {
+	proxy     ProxyConfig
+	clock     func() time.Time
+	rest      *endpointStats
+	websocket *endpointStats
+	transport *http.Transport
+	onFailure func(error)
+}
+
+// NewEgress prepares egress for proxy. An invalid proxy fails every request rather than
+// silently connecting directly.
+func NewEgress(proxy ProxyConfig) *Egress {
+	transport := newEgressTransport()
+	proxyURL, err := proxy.proxyURL()
+	switch {
+	case err != nil:
+		proxyErr := fmt.Errorf("egress proxy: %w", err)
+		transport.Proxy = func(*http.Request) (*url.URL, error) { return nil, proxyErr }
+	case proxyURL != nil:
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return &Egress{
+		proxy:     proxy,
+		clock:     time.Now,
+		rest:      new(endpointStats),
+		websocket: new(endpointStats),
+		transport: transport,
+		onFailure: nil,
+	}
+}
+
+// OnTransportFailure registers fn to run after every failed REST request or websocket handshake.
+// It must be set before the clients are built.
+func (e *Egress) OnTransportFailure(fn func(error)) {
+	if e != nil {
+		e.onFailure = fn
+	}
+}
+
+func newEgressTransport() *http.Transport {
+	if base, ok := http.DefaultTransport.(*http.Transport); ok {
+		return base.Clone()
+	}
+	transport := new(http.Transport)
+	transport.Proxy = http.ProxyFromEnvironment
+	return transport
+}
+
+// HTTPClient returns a REST client with the given timeout.
+func (e *Egress) HTTPClient(timeout time.Duration) *http.Client {
+	var transport http.RoundTripper
+	if e != nil {
+		transport = &recordingTransport{base: e.transport, stats: e.rest, clock: e.clock, onFailure: e.onFailure}
+	}
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: nil,
+		Jar:           nil,
+		Timeout:       timeout,
+	}
+}
+
+// WebsocketDialOptions returns the dial options for websocket connections.
+func (e *Egress) WebsocketDialOptions() *websocket.DialOptions {
+	if e == nil {
+		return nil
+	}
+	opts := new(websocket.DialOptions)
+	opts.HTTPClient = &http.Client{
+		Transport:     &recordingTransport{base: e.transport, stats: e.websocket, clock: e.clock, onFailure: e.onFailure},
+		CheckRedirect: nil,
+		Jar:           nil,
+		Timeout:       0,
+	}
+	return opts
+}
+
+// Diagnostics snapshots the connection counters.
+func (e *Egress) Diagnostics() ConnectionDiagnostics {
+	if e == nil {
+		var empty ConnectionDiagnostics
+		return empty
+	}
+	return ConnectionDiagnostics{
+		Proxy:     e.proxy.Redacted(),
+		REST:      e.rest.snapshot(),
+		Websocket: e.websocket.snapshot(),
+		Endpoints: nil,
+		Streams:   nil,
+	}
+}
+
+type endpointStats struct {
+	mu          sync.Mutex
+	requests    uint64
+	failures    uint64
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   string
+	lastLatency time.Duration
+}
+
+func (s *endpointStats) record(at time.Time, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if err != nil {
+		s.failures++
+		s.lastFailure = at
+		s.lastError = redact.Error(err)
+		return
+	}
+	s.lastSuccess = at
+	s.lastLatency = latency
+}
+
+func (s *endpointStats) snapshot() EndpointDiagnostics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := EndpointDiagnostics{
+		Requests:      s.requests,
+		Failures:      s.failures,
+		LastSuccessAt: nil,
+		LastFailureAt: nil,
+		LastError:     s.lastError,
+		LastLatencyMs: float64(s.lastLatency) / float64(time.Millisecond),
+	}
+	if !s.lastSuccess.IsZero() {
+		at := s.lastSuccess
+		out.LastSuccessAt = &at
+	}
+	if !s.lastFailure.IsZero() {
+		at := s.lastFailure
+		out.LastFailureAt = &at
+	}
+	return out
+}
+
+// recordingTransport times each round trip, which for websockets covers the upgrade handshake.
+type recordingTransport struct {
+	base      http.RoundTripper
+	stats     *endpointStats
+	clock     func() time.Time
+	onFailure func(error)
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	started := t.clock()
+	resp, err := t.base.RoundTrip(req)
+	finished := t.clock()
+	t.stats.record(finished, finished.Sub(started), err)
+	if err != nil && t.onFailure != nil {
+		t.onFailure(err)
+	}
+	return resp, err //nolint:wrapcheck // transports must return errors unchanged
+}
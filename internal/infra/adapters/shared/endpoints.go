@@ -0,0 +1,341 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointsConfigKey is the provider config key listing regional venue endpoints.
+const EndpointsConfigKey = "endpoints"
+
+// endpointReprobeInterval bounds how often transport failures trigger a new latency probe.
+const endpointReprobeInterval = 30 * time.Second
+
+// Endpoint is one regional set of venue URLs. Empty websocket URLs fall back to the adapter
+// defaults.
+type Endpoint struct {
+	Region           string
+	REST             string
+	Websocket        string
+	PrivateWebsocket string
+}
+
+// EndpointsFromConfig reads the endpoints setting in cfg, a mapping of region names to their
+// rest_url, websocket_url and private_websocket_url. Regions are returned sorted by name.
+func EndpointsFromConfig(cfg map[string]any) ([]Endpoint, error) {
+	raw, ok := cfg[EndpointsConfigKey]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	endpoints, err := ParseEndpoints(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", EndpointsConfigKey, err)
+	}
+	return endpoints, nil
+}
+
+// ParseEndpoints parses an endpoints setting value: a mapping of region names to mappings with
+// rest_url, websocket_url and private_websocket_url. Every region needs a rest_url, which is
+// also where latency is probed.
+func ParseEndpoints(raw any) ([]Endpoint, error) {
+	regions, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("must be a mapping of regions")
+	}
+	endpoints := make([]Endpoint, 0, len(regions))
+	for region, value := range regions {
+		endpoint, err := parseEndpoint(strings.TrimSpace(region), value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", region, err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Region < endpoints[j].Region })
+	return endpoints, nil
+}
+
+func parseEndpoint(region string, raw any) (Endpoint, error) {
+	var empty Endpoint
+	if region == "" {
+		return empty, fmt.Errorf("region name required")
+	}
+	values, ok := raw.(map[string]any)
+	if !ok {
+		return empty, fmt.Errorf("must be a mapping")
+	}
+	endpoint := Endpoint{Region: region, REST: "", Websocket: "", PrivateWebsocket: ""}
+	for key, value := range values {
+		text, ok := value.(string)
+		if !ok && value != nil {
+			return empty, fmt.Errorf("%s: expected string, got %T", key, value)
+		}
+		text = strings.TrimSpace(text)
+		var schemes []string
+		switch strings.TrimSpace(key) {
+		case "rest_url":
+			endpoint.REST = text
+			schemes = []string{"http", "https"}
+		case "websocket_url":
+			endpoint.Websocket = text
+			schemes = []string{"ws", "wss"}
+		case "private_websocket_url":
+			endpoint.PrivateWebsocket = text
+			schemes = []string{"ws", "wss"}
+		default:
+			return empty, fmt.Errorf("%s: unknown setting", key)
+		}
+		if text == "" {
+			continue
+		}
+		if err := checkEndpointURL(text, schemes); err != nil {
+			return empty, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if endpoint.REST == "" {
+		return empty, fmt.Errorf("rest_url required")
+	}
+	return endpoint, nil
+}
+
+func checkEndpointURL(raw string, schemes []string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	for _, allowed := range schemes {
+		if scheme == allowed {
+			if parsed.Host == "" {
+				return fmt.Errorf("url must include a host")
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported scheme %q, expected %s", parsed.Scheme, strings.Join(schemes, " or "))
+}
+
+// EndpointProbe measures the latency of one endpoint. An error marks it unhealthy.
+type EndpointProbe func(ctx context.Context, endpoint Endpoint) (time.Duration, error)
+
+// HTTPEndpointProbe times a GET of path on the endpoint's REST URL. Statuses other than 2xx mark
+// the endpoint unhealthy.
+func HTTPEndpointProbe(client *http.Client, path string) EndpointProbe {
+	return func(ctx context.Context, endpoint Endpoint) (time.Duration, error) {
+		target := strings.TrimSuffix(endpoint.REST, "/") + "/" + strings.TrimPrefix(path, "/")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return 0, fmt.Errorf("build probe request: %w", err)
+		}
+		started := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, fmt.Errorf("probe %s: %w", target, err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		latency := time.Since(started)
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return latency, fmt.Errorf("probe %s: status %d", target, resp.StatusCode)
+		}
+		return latency, nil
+	}
+}
+
+// EndpointSelection reports the endpoint an adapter uses and the latest probe of each region.
+type EndpointSelection struct {
+	Current     string                `json:"current"`
+	LastProbeAt *time.Time            `json:"lastProbeAt,omitempty"`
+	Switches    uint64                `json:"switches"`
+	Probes      []EndpointProbeResult `json:"probes"`
+}
+
+// EndpointProbeResult is the latest probe of one region.
+type EndpointProbeResult struct {
+	Region    string     `json:"region"`
+	REST      string     `json:"rest"`
+	Websocket string     `json:"websocket,omitempty"`
+	Healthy   bool       `json:"healthy"`
+	LatencyMs float64    `json:"latencyMs,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	ProbedAt  *time.Time `json:"probedAt,omitempty"`
+}
+
+type endpointState struct {
+	probed   time.Time
+	healthy  bool
+	latency  time.Duration
+	lastErr  string
+	endpoint Endpoint
+}
+
+// EndpointSelector picks the fastest healthy endpoint among an adapter's regions. It probes every
+// region on Probe and again, at most every endpointReprobeInterval, after a transport failure.
+// A nil selector selects nothing, leaving the adapter on its default URLs.
+type EndpointSelector struct {
+	probe EndpointProbe
+	clock func() time.Time
+
+	mu        sync.Mutex
+	states    []endpointState
+	current   int
+	lastProbe time.Time
+	switches  uint64
+	probing   atomic.Bool
+}
+
+// NewEndpointSelector creates a selector over endpoints, starting on the first one. It returns nil
+// when no endpoints are configured.
+func NewEndpointSelector(endpoints []Endpoint, probe EndpointProbe) *EndpointSelector {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	states := make([]endpointState, len(endpoints))
+	for i, endpoint := range endpoints {
+		states[i] = endpointState{probed: time.Time{}, healthy: false, latency: 0, lastErr: "", endpoint: endpoint}
+	}
+	return &EndpointSelector{
+		probe:     probe,
+		clock:     time.Now,
+		mu:        sync.Mutex{},
+		states:    states,
+		current:   0,
+		lastProbe: time.Time{},
+		switches:  0,
+		probing:   atomic.Bool{},
+	}
+}
+
+// Current returns the selected endpoint.
+func (s *EndpointSelector) Current() (Endpoint, bool) {
+	if s == nil {
+		var empty Endpoint
+		return empty, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.states[s.current].endpoint, true
+}
+
+// Probe measures every endpoint concurrently and selects the fastest healthy one. When none is
+// healthy the current selection is kept. It reports whether the selection changed.
+func (s *EndpointSelector) Probe(ctx context.Context) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	s.lastProbe = s.clock()
+	endpoints := make([]Endpoint, len(s.states))
+	for i, state := range s.states {
+		endpoints[i] = state.endpoint
+	}
+	s.mu.Unlock()
+
+	results := make([]endpointState, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint Endpoint) {
+			defer wg.Done()
+			latency, err := s.probe(ctx, endpoint)
+			result := endpointState{probed: s.clock(), healthy: err == nil, latency: latency, lastErr: "", endpoint: endpoint}
+			if err != nil {
+				result.lastErr = err.Error()
+			}
+			results[i] = result
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states = results
+	best := -1
+	for i, result := range results {
+		if result.healthy && (best < 0 || result.latency < results[best].latency) {
+			best = i
+		}
+	}
+	if best < 0 || best == s.current {
+		return false
+	}
+	s.current = best
+	s.switches++
+	return true
+}
+
+// ReportFailure re-probes in the background after a transport failure, unless a probe is running
+// or the last one is more recent than endpointReprobeInterval. onSwitch runs when the selection
+// changes.
+func (s *EndpointSelector) ReportFailure(ctx context.Context, onSwitch func(Endpoint)) {
+	if s == nil || ctx.Err() != nil {
+		return
+	}
+	s.mu.Lock()
+	recent := !s.lastProbe.IsZero() && s.clock().Sub(s.lastProbe) < endpointReprobeInterval
+	s.mu.Unlock()
+	if recent || !s.probing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer s.probing.Store(false)
+		if s.Probe(ctx) && onSwitch != nil {
+			if endpoint, ok := s.Current(); ok {
+				onSwitch(endpoint)
+			}
+		}
+	}()
+}
+
+// Status snapshots the selection and the latest probe results.
+func (s *EndpointSelector) Status() *EndpointSelection {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := &EndpointSelection{
+		Current:     s.states[s.current].endpoint.Region,
+		LastProbeAt: nil,
+		Switches:    s.switches,
+		Probes:      make([]EndpointProbeResult, 0, len(s.states)),
+	}
+	if !s.lastProbe.IsZero() {
+		at := s.lastProbe
+		out.LastProbeAt = &at
+	}
+	for _, state := range s.states {
+		result := EndpointProbeResult{
+			Region:    state.endpoint.Region,
+			REST:      state.endpoint.REST,
+			Websocket: state.endpoint.Websocket,
+			Healthy:   state.healthy,
+			LatencyMs: float64(state.latency) / float64(time.Millisecond),
+			Error:     state.lastErr,
+			ProbedAt:  nil,
+		}
+		if !state.probed.IsZero() {
+			at := state.probed
+			result.ProbedAt = &at
+		}
+		out.Probes = append(out.Probes, result)
+	}
+	return out
+}
+
+// Clone returns a deep copy of the selection.
+func (s *EndpointSelection) Clone() *EndpointSelection {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	clone.Probes = append([]EndpointProbeResult(nil), s.Probes...)
+	return &clone
+}
@@ -0,0 +1,138 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEndpointsFromConfig(t *testing.T) {
+	endpoints, err := EndpointsFromConfig(map[string]any{"api_key": "x"})
+	if err != nil || endpoints != nil {
+		t.Fatalf("expected no endpoints without the setting, got %+v (%v)", endpoints, err)
+	}
+
+	endpoints, err = EndpointsFromConfig(map[string]any{EndpointsConfigKey: map[string]any{
+		"tokyo":     map[string]any{"rest_url": "https://api-tokyo.example.com", "websocket_url": "wss://ws-tokyo.example.com"},
+		"frankfurt": map[string]any{"rest_url": "https://api-fra.example.com"},
+	}})
+	if err != nil {
+		t.Fatalf("EndpointsFromConfig: %v", err)
+	}
+	if len(endpoints) != 2 || endpoints[0].Region != "frankfurt" || endpoints[1].Websocket != "wss://ws-tokyo.example.com" {
+		t.Fatalf("unexpected endpoints %+v", endpoints)
+	}
+
+	invalid := []any{
+		"https://api.example.com",
+		map[string]any{"tokyo": "https://api.example.com"},
+		map[string]any{"tokyo": map[string]any{"websocket_url": "wss://ws.example.com"}},
+		map[string]any{"tokyo": map[string]any{"rest_url": "wss://api.example.com"}},
+		map[string]any{"tokyo": map[string]any{"rest_url": "https://api.example.com", "websocket_url": "https://ws.example.com"}},
+		map[string]any{"tokyo": map[string]any{"rest_url": "https://api.example.com", "region": "ap"}},
+		map[string]any{" ": map[string]any{"rest_url": "https://api.example.com"}},
+	}
+	for _, raw := range invalid {
+		if _, err := EndpointsFromConfig(map[string]any{EndpointsConfigKey: raw}); err == nil {
+			t.Fatalf("expected error for %v", raw)
+		}
+	}
+}
+
+func TestEndpointSelectorPicksFastestHealthyEndpoint(t *testing.T) {
+	latencies := map[string]time.Duration{"a": 30 * time.Millisecond, "b": 10 * time.Millisecond, "c": time.Millisecond}
+	var down atomic.Value
+	down.Store("c")
+	probe := func(_ context.Context, endpoint Endpoint) (time.Duration, error) {
+		if endpoint.Region == down.Load().(string) {
+			return 0, errors.New("connection refused")
+		}
+		return latencies[endpoint.Region], nil
+	}
+	selector := NewEndpointSelector([]Endpoint{{Region: "a", REST: "https://a"}, {Region: "b", REST: "https://b"}, {Region: "c", REST: "https://c"}}, probe)
+
+	if current, _ := selector.Current(); current.Region != "a" {
+		t.Fatalf("expected the first endpoint before probing, got %s", current.Region)
+	}
+	if !selector.Probe(context.Background()) {
+		t.Fatal("expected the probe to switch endpoints")
+	}
+	status := selector.Status()
+	if status.Current != "b" || status.Switches != 1 || status.LastProbeAt == nil {
+		t.Fatalf("unexpected selection %+v", status)
+	}
+	if probe := status.Probes[2]; probe.Healthy || probe.Error != "connection refused" || probe.ProbedAt == nil {
+		t.Fatalf("expected the failed probe to be reported, got %+v", probe)
+	}
+
+	down.Store("b")
+	latencies["c"] = 50 * time.Millisecond
+	if !selector.Probe(context.Background()) {
+		t.Fatal("expected the probe to move off the failed endpoint")
+	}
+	if current, _ := selector.Current(); current.Region != "a" {
+		t.Fatalf("expected the fastest healthy endpoint, got %s", current.Region)
+	}
+
+	allDown := NewEndpointSelector([]Endpoint{{Region: "x", REST: "https://x"}, {Region: "y", REST: "https://y"}}, func(context.Context, Endpoint) (time.Duration, error) {
+		return 0, errors.New("timeout")
+	})
+	if allDown.Probe(context.Background()) {
+		t.Fatal("expected the selection to stay put when no endpoint is healthy")
+	}
+}
+
+func TestEndpointSelectorReprobesAfterFailureAtMostOncePerInterval(t *testing.T) {
+	var probes atomic.Int32
+	selector := NewEndpointSelector([]Endpoint{{Region: "a", REST: "https://a"}}, func(context.Context, Endpoint) (time.Duration, error) {
+		probes.Add(1)
+		return time.Millisecond, nil
+	})
+	now := time.Now()
+	selector.clock = func() time.Time { return now }
+	selector.Probe(context.Background())
+
+	selector.ReportFailure(context.Background(), nil)
+	if probes.Load() != 1 {
+		t.Fatalf("expected a recent probe to suppress the re-probe, got %d probes", probes.Load())
+	}
+
+	now = now.Add(endpointReprobeInterval)
+	selector.ReportFailure(context.Background(), nil)
+	deadline := time.Now().Add(time.Second)
+	for probes.Load() != 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if probes.Load() != 2 {
+		t.Fatalf("expected a re-probe after the interval, got %d probes", probes.Load())
+	}
+
+	var nilSelector *EndpointSelector
+	if _, ok := nilSelector.Current(); ok || nilSelector.Status() != nil || nilSelector.Probe(context.Background()) {
+		t.Fatal("expected a nil selector to select nothing")
+	}
+}
+
+func TestHTTPEndpointProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/ping" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	probe := HTTPEndpointProbe(server.Client(), "/api/ping")
+	if _, err := probe(context.Background(), Endpoint{Region: "local", REST: server.URL + "/"}); err != nil {
+		t.Fatalf("expected healthy endpoint, got %v", err)
+	}
+	unhealthy := HTTPEndpointProbe(server.Client(), "/other")
+	if _, err := unhealthy(context.Background(), Endpoint{Region: "local", REST: server.URL}); err == nil {
+		t.Fatal("expected non-2xx status to mark the endpoint unhealthy")
+	}
+}
@@ -24,6 +24,7 @@ type ExecReportSnapshot struct {
 	QuantityPrecision int
 	BaseCurrency      string
 	CommissionRate    float64
+	SubAccount        string
 	Timestamp         time.Time
 	RejectReason      *string
 }
@@ -62,6 +63,7 @@ func BuildExecReportPayload(s ExecReportSnapshot) schema.ExecReportPayload {
 		AvgFillPrice:     avgFillStr,
 		CommissionAmount: commissionAmount,
 		CommissionAsset:  schema.NormalizeCurrencyCode(s.BaseCurrency),
+		SubAccount:       s.SubAccount,
 		Timestamp:        s.Timestamp,
 		RejectReason:     s.RejectReason,
 	}
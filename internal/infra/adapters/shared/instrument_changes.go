@@ -0,0 +1,79 @@
+package shared
+
+import "github.com/coachpo/meltica/internal/domain/schema"
+
+// InstrumentChangeLog accumulates the differences found between instrument catalogue refreshes
+// until the adapter publishes them with its next InstrumentUpdate events. Changes to the same
+// attribute across several refreshes are merged, keeping the first previous value and the latest
+// current value. It is not safe for concurrent use; adapters guard it with their instrument lock.
+type InstrumentChangeLog struct {
+	pending map[string][]schema.InstrumentChange
+}
+
+// NewInstrumentChangeLog creates an empty change log.
+func NewInstrumentChangeLog() *InstrumentChangeLog {
+	return &InstrumentChangeLog{pending: make(map[string][]schema.InstrumentChange)}
+}
+
+// Record diffs two catalogues keyed by canonical symbol. An empty previous catalogue is the
+// initial load and records nothing; symbols missing from it are recorded as newly listed, with
+// the listing status as the current value.
+func (l *InstrumentChangeLog) Record(previous, current map[string]schema.Instrument) {
+	if len(previous) == 0 {
+		return
+	}
+	for symbol, inst := range current {
+		prev, ok := previous[symbol]
+		if !ok {
+			l.merge(symbol, []schema.InstrumentChange{{Field: schema.InstrumentChangeListed, Previous: "", Current: string(inst.Status)}})
+			continue
+		}
+		l.merge(symbol, schema.DiffInstruments(prev, inst))
+	}
+}
+
+// Take returns and clears the changes pending for symbol.
+func (l *InstrumentChangeLog) Take(symbol string) []schema.InstrumentChange {
+	changes := l.pending[symbol]
+	delete(l.pending, symbol)
+	return changes
+}
+
+func (l *InstrumentChangeLog) merge(symbol string, changes []schema.InstrumentChange) {
+	if len(changes) == 0 {
+		return
+	}
+	pending := l.pending[symbol]
+	for _, change := range changes {
+		merged := false
+		for i := range pending {
+			if pending[i].Field != change.Field {
+				continue
+			}
+			pending[i].Current = change.Current
+			if pending[i].Field != schema.InstrumentChangeListed && pending[i].Previous == pending[i].Current {
+				pending = append(pending[:i], pending[i+1:]...)
+			}
+			merged = true
+			break
+		}
+		if !merged {
+			pending = append(pending, change)
+		}
+	}
+	if len(pending) == 0 {
+		delete(l.pending, symbol)
+		return
+	}
+	l.pending[symbol] = pending
+}
+
+// PreviousStatus returns the status an instrument had before a pending status change, if any.
+func PreviousStatus(changes []schema.InstrumentChange) (schema.InstrumentStatus, bool) {
+	for _, change := range changes {
+		if change.Field == "status" {
+			return schema.InstrumentStatus(change.Previous), true
+		}
+	}
+	return "", false
+}
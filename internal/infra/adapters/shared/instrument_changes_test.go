@@ -0,0 +1,44 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func TestInstrumentChangeLogMergesRefreshes(t *testing.T) {
+	changeLog := NewInstrumentChangeLog()
+	initial := map[string]schema.Instrument{
+		"BTC-USDT": {Symbol: "BTC-USDT", PriceIncrement: "0.01", MinQuantity: "0.001", Status: schema.InstrumentStatusTrading},
+	}
+	changeLog.Record(nil, initial)
+	if changes := changeLog.Take("BTC-USDT"); len(changes) != 0 {
+		t.Fatalf("expected the initial load to record nothing, got %+v", changes)
+	}
+
+	second := map[string]schema.Instrument{
+		"BTC-USDT": {Symbol: "BTC-USDT", PriceIncrement: "0.1", MinQuantity: "0.001", Status: schema.InstrumentStatusHalted},
+		"ETH-USDT": {Symbol: "ETH-USDT", Status: schema.InstrumentStatusTrading},
+	}
+	changeLog.Record(initial, second)
+	third := map[string]schema.Instrument{
+		"BTC-USDT": {Symbol: "BTC-USDT", PriceIncrement: "0.5", MinQuantity: "0.001", Status: schema.InstrumentStatusTrading},
+		"ETH-USDT": {Symbol: "ETH-USDT", Status: schema.InstrumentStatusTrading},
+	}
+	changeLog.Record(second, third)
+
+	changes := changeLog.Take("BTC-USDT")
+	if len(changes) != 1 || changes[0] != (schema.InstrumentChange{Field: "priceIncrement", Previous: "0.01", Current: "0.5"}) {
+		t.Fatalf("expected the reverted status to cancel out and the tick size to merge, got %+v", changes)
+	}
+	if _, ok := PreviousStatus(changes); ok {
+		t.Fatal("expected no pending status change")
+	}
+	listed := changeLog.Take("ETH-USDT")
+	if len(listed) != 1 || listed[0].Field != schema.InstrumentChangeListed || listed[0].Current != "trading" {
+		t.Fatalf("expected a listing change, got %+v", listed)
+	}
+	if again := changeLog.Take("ETH-USDT"); len(again) != 0 {
+		t.Fatalf("expected Take to clear pending changes, got %+v", again)
+	}
+}
@@ -56,6 +56,14 @@ func NewOrderBookAssembler(depth int) *OrderBookAssembler {
 	}
 }
 
+// SetDepth changes how many price levels per side assembled snapshots carry (<=0 keeps full
+// depth). The book itself keeps every level so a later, deeper setting is served immediately.
+func (a *OrderBookAssembler) SetDepth(depth int) {
+	a.mu.Lock()
+	a.depth = depth
+	a.mu.Unlock()
+}
+
 // HasSnapshot reports whether the assembler has applied an initial REST snapshot.
 func (a *OrderBookAssembler) HasSnapshot() bool {
 	a.mu.Lock()
@@ -25,6 +25,7 @@ type Publisher struct {
 	clock        func() time.Time
 	seqMu        sync.Mutex
 	seq          map[string]uint64
+	books        *bookCadence
 }
 
 var (
@@ -45,6 +46,7 @@ func NewPublisher(providerName string, events chan<- *schema.Event, pools *pool.
 		clock:        clock,
 		seqMu:        sync.Mutex{},
 		seq:          make(map[string]uint64),
+		books:        newBookCadence(),
 	}
 }
 
@@ -68,16 +70,6 @@ func (p *Publisher) PublishTrade(ctx context.Context, symbol string, payload sch
 	p.emitEvent(ctx, evt)
 }
 
-// PublishBookSnapshot creates and emits an order book snapshot event.
-func (p *Publisher) PublishBookSnapshot(ctx context.Context, symbol string, payload schema.BookSnapshotPayload) {
-	seq := p.nextSeq(schema.EventTypeBookSnapshot, symbol)
-	evt := p.newEvent(ctx, schema.EventTypeBookSnapshot, symbol, seq, payload, payload.LastUpdate)
-	if evt == nil {
-		return
-	}
-	p.emitEvent(ctx, evt)
-}
-
 // PublishBalanceUpdate creates and emits a balance update event.
 func (p *Publisher) PublishBalanceUpdate(ctx context.Context, currency string, payload schema.BalanceUpdatePayload) {
 	seq := p.nextSeq(schema.EventTypeBalanceUpdate, currency)
@@ -129,9 +121,13 @@ func (p *Publisher) PublishExtension(ctx context.Context, symbol string, payload
 	p.emitEvent(ctx, evt)
 }
 
+// newEvent builds an event stamped with the venue timestamp ts as event time and the publisher
+// clock as processing time. Strategies observe ts, or the processing time when ts is zero.
 func (p *Publisher) newEvent(ctx context.Context, evtType schema.EventType, symbol string, seq uint64, payload any, ts time.Time) *schema.Event {
-	if ts.IsZero() {
-		ts = p.clock().UTC()
+	received := p.clock().UTC()
+	emit := ts
+	if emit.IsZero() {
+		emit = received
 	}
 	eventID := fmt.Sprintf("%s:%s:%s:%d", p.providerName, strings.ReplaceAll(symbol, "-", ""), evtType, seq)
 	evt, err := p.pools.BorrowEventInst(ctx)
@@ -144,8 +140,9 @@ func (p *Publisher) newEvent(ctx context.Context, evtType schema.EventType, symb
 	evt.Symbol = symbol
 	evt.Type = evtType
 	evt.SeqProvider = seq
-	evt.IngestTS = ts
-	evt.EmitTS = ts
+	evt.EventTS = ts
+	evt.IngestTS = received
+	evt.EmitTS = emit
 	evt.Payload = payload
 	return evt
 }
@@ -216,10 +213,10 @@ func extensionPayloadSize(payload any) (int, error) {
 	case json.RawMessage:
 		return len(v), nil
 	default:
-		data, err := json.Marshal(v)
+		size, err := pool.JSONSize(v)
 		if err != nil {
 			return 0, fmt.Errorf("marshal extension payload: %w", err)
 		}
-		return len(data), nil
+		return size, nil
 	}
 }
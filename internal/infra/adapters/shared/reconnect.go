@@ -0,0 +1,282 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// ReconnectConfigKey is the provider config key holding the reconnect policy mapping.
+const ReconnectConfigKey = "reconnect"
+
+// Reconnect reasons recorded on reconnect metrics.
+const (
+	ReconnectReasonDial        = "dial_error"
+	ReconnectReasonRead        = "read_error"
+	ReconnectReasonPing        = "ping_failure"
+	ReconnectReasonClosed      = "closed"
+	ReconnectReasonListenKey   = "listen_key_error"
+	ReconnectReasonStreamError = "stream_error"
+)
+
+// ErrReconnectExhausted signals that a stream gave up after max_retries consecutive failures.
+var ErrReconnectExhausted = errors.New("reconnect attempts exhausted")
+
+// ReconnectPolicy describes how adapters back off between websocket reconnect attempts.
+type ReconnectPolicy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	// MaxRetries bounds consecutive failed attempts; zero retries forever.
+	MaxRetries int
+	// Jitter is the randomisation factor applied to each interval, between 0 and 1.
+	Jitter float64
+}
+
+// DefaultReconnectPolicy returns the policy used when a provider does not override it.
+func DefaultReconnectPolicy(maxInterval time.Duration) ReconnectPolicy {
+	if maxInterval <= 0 {
+		maxInterval = backoff.DefaultMaxInterval
+	}
+	return ReconnectPolicy{
+		InitialInterval: backoff.DefaultInitialInterval,
+		MaxInterval:     maxInterval,
+		Multiplier:      backoff.DefaultMultiplier,
+		MaxRetries:      0,
+		Jitter:          backoff.DefaultRandomizationFactor,
+	}
+}
+
+// Validate reports whether the policy can drive a backoff.
+func (p ReconnectPolicy) Validate() error {
+	switch {
+	case p.InitialInterval <= 0:
+		return fmt.Errorf("initial_interval must be positive")
+	case p.MaxInterval < p.InitialInterval:
+		return fmt.Errorf("max_interval must be at least initial_interval")
+	case p.Multiplier < 1:
+		return fmt.Errorf("multiplier must be at least 1")
+	case p.MaxRetries < 0:
+		return fmt.Errorf("max_retries must not be negative")
+	case p.Jitter < 0 || p.Jitter > 1:
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// Settings renders the policy in the provider config shape.
+func (p ReconnectPolicy) Settings() map[string]any {
+	return map[string]any{
+		"initial_interval": p.InitialInterval.String(),
+		"max_interval":     p.MaxInterval.String(),
+		"multiplier":       p.Multiplier,
+		"max_retries":      p.MaxRetries,
+		"jitter":           p.Jitter,
+	}
+}
+
+// ReconnectPolicyFromConfig overlays the reconnect mapping in cfg onto base.
+func ReconnectPolicyFromConfig(cfg map[string]any, base ReconnectPolicy) (ReconnectPolicy, error) {
+	raw, ok := cfg[ReconnectConfigKey]
+	if !ok || raw == nil {
+		return base, nil
+	}
+	policy, err := ParseReconnectPolicy(raw, base)
+	if err != nil {
+		return base, fmt.Errorf("%s: %w", ReconnectConfigKey, err)
+	}
+	return policy, nil
+}
+
+// ParseReconnectPolicy overlays a reconnect setting value, a mapping of initial_interval,
+// max_interval, multiplier, max_retries and jitter, onto base and validates the result.
+func ParseReconnectPolicy(raw any, base ReconnectPolicy) (ReconnectPolicy, error) {
+	values, ok := raw.(map[string]any)
+	if !ok {
+		return base, fmt.Errorf("must be a mapping")
+	}
+	policy := base
+	for key, value := range values {
+		var err error
+		switch strings.TrimSpace(key) {
+		case "initial_interval":
+			policy.InitialInterval, err = reconnectDuration(value)
+		case "max_interval":
+			policy.MaxInterval, err = reconnectDuration(value)
+		case "multiplier":
+			policy.Multiplier, err = reconnectFloat(value)
+		case "max_retries":
+			var retries float64
+			retries, err = reconnectFloat(value)
+			policy.MaxRetries = int(retries)
+		case "jitter":
+			policy.Jitter, err = reconnectFloat(value)
+		default:
+			err = fmt.Errorf("unknown setting")
+		}
+		if err != nil {
+			return base, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if err := policy.Validate(); err != nil {
+		return base, err
+	}
+	return policy, nil
+}
+
+func reconnectDuration(raw any) (time.Duration, error) {
+	switch v := raw.(type) {
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", v)
+		}
+		return d, nil
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("expected duration, got %T", raw)
+}
+
+func reconnectFloat(raw any) (float64, error) {
+	switch v := raw.(type) {
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		var parsed float64
+		if _, err := fmt.Sscanf(strings.TrimSpace(v), "%g", &parsed); err != nil {
+			return 0, fmt.Errorf("invalid number %q", v)
+		}
+		return parsed, nil
+	}
+	return 0, fmt.Errorf("expected number, got %T", raw)
+}
+
+// Reconnector paces reconnect attempts for one stream according to a policy.
+type Reconnector struct {
+	policy   ReconnectPolicy
+	backoff  *backoff.ExponentialBackOff
+	failures int
+	metrics  *ReconnectMetrics
+}
+
+// NewReconnector builds a reconnector; metrics may be nil.
+func NewReconnector(policy ReconnectPolicy, metrics *ReconnectMetrics) *Reconnector {
+	exp := backoff.NewExponentialBackOff()
+	exp.InitialInterval = policy.InitialInterval
+	exp.MaxInterval = policy.MaxInterval
+	exp.Multiplier = policy.Multiplier
+	exp.RandomizationFactor = policy.Jitter
+	exp.Reset()
+	return &Reconnector{policy: policy, backoff: exp, failures: 0, metrics: metrics}
+}
+
+// Connected records a successful connection and resets the backoff.
+func (r *Reconnector) Connected(ctx context.Context) {
+	r.backoff.Reset()
+	r.failures = 0
+	r.metrics.record(ctx, "success", "")
+}
+
+// Wait records a reconnect for reason and sleeps for the next backoff interval.
+// It returns ErrReconnectExhausted once max_retries consecutive failures have been
+// observed, or the context error when ctx is cancelled first.
+func (r *Reconnector) Wait(ctx context.Context, reason string) error {
+	r.failures++
+	r.metrics.record(ctx, "error", reason)
+	if r.policy.MaxRetries > 0 && r.failures > r.policy.MaxRetries {
+		return fmt.Errorf("%w after %d attempts", ErrReconnectExhausted, r.policy.MaxRetries)
+	}
+	sleep := r.backoff.NextBackOff()
+	if sleep == backoff.Stop {
+		sleep = r.policy.MaxInterval
+	}
+	timer := time.NewTimer(sleep)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("reconnect wait: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Disconnect tags a connection loop error with the reconnect reason it should be recorded under.
+func Disconnect(reason string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &disconnectError{reason: reason, err: err}
+}
+
+// DisconnectReason returns the reason attached by Disconnect, or ReconnectReasonClosed.
+func DisconnectReason(err error) string {
+	var tagged *disconnectError
+	if errors.As(err, &tagged) && !errors.Is(err, context.Canceled) {
+		return tagged.reason
+	}
+	return ReconnectReasonClosed
+}
+
+type disconnectError struct {
+	reason string
+	err    error
+}
+
+func (e *disconnectError) Error() string { return e.err.Error() }
+
+func (e *disconnectError) Unwrap() error { return e.err }
+
+// ReconnectMetrics counts reconnect attempts per provider, stream and reason.
+type ReconnectMetrics struct {
+	environment string
+	provider    string
+	stream      string
+	counter     metric.Int64Counter
+}
+
+// NewReconnectMetrics registers the reconnect counter for an adapter stream.
+func NewReconnectMetrics(adapter, provider, stream string) *ReconnectMetrics {
+	adapter = strings.ToLower(strings.TrimSpace(adapter))
+	meter := otel.Meter("adapter." + adapter)
+	counter, _ := meter.Int64Counter(fmt.Sprintf("meltica_provider_%s_ws_reconnects", adapter),
+		metric.WithDescription("Number of websocket reconnect attempts by result and reason"),
+		metric.WithUnit("{reconnect}"))
+	return &ReconnectMetrics{
+		environment: telemetry.Environment(),
+		provider:    strings.TrimSpace(provider),
+		stream:      stream,
+		counter:     counter,
+	}
+}
+
+func (m *ReconnectMetrics) record(ctx context.Context, result, reason string) {
+	if m == nil || m.counter == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	attrs := telemetry.MessageAttributes(m.environment, m.provider, m.stream)
+	attrs = append(attrs, telemetry.AttrResult.String(result))
+	if reason != "" {
+		attrs = append(attrs, telemetry.AttrReason.String(reason))
+	}
+	m.counter.Add(ctx, 1, metric.WithAttributes(attrs...))
+}
@@ -0,0 +1,98 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestReconnectPolicyFromConfig(t *testing.T) {
+	base := DefaultReconnectPolicy(30 * time.Second)
+
+	policy, err := ReconnectPolicyFromConfig(map[string]any{"api_key": "x"}, base)
+	if err != nil || policy != base {
+		t.Fatalf("expected defaults without reconnect block, got %+v (%v)", policy, err)
+	}
+
+	policy, err = ReconnectPolicyFromConfig(map[string]any{
+		ReconnectConfigKey: map[string]any{
+			"initial_interval": "250ms",
+			"max_interval":     5,
+			"multiplier":       "2",
+			"max_retries":      3,
+			"jitter":           0.0,
+		},
+	}, base)
+	if err != nil {
+		t.Fatalf("ReconnectPolicyFromConfig: %v", err)
+	}
+	want := ReconnectPolicy{InitialInterval: 250 * time.Millisecond, MaxInterval: 5 * time.Second, Multiplier: 2, MaxRetries: 3, Jitter: 0}
+	if policy != want {
+		t.Fatalf("policy = %+v, want %+v", policy, want)
+	}
+
+	invalid := []map[string]any{
+		{"max_interval": "100ms"},
+		{"multiplier": 0.5},
+		{"jitter": 1.5},
+		{"max_retries": -1},
+		{"initial_interval": "soon"},
+		{"backoff": "linear"},
+	}
+	for _, block := range invalid {
+		if _, err := ReconnectPolicyFromConfig(map[string]any{ReconnectConfigKey: block}, base); err == nil {
+			t.Fatalf("expected error for %v", block)
+		}
+	}
+	if _, err := ReconnectPolicyFromConfig(map[string]any{ReconnectConfigKey: "fast"}, base); err == nil {
+		t.Fatalf("expected error for non-mapping reconnect block")
+	}
+}
+
+func TestReconnectorExhaustsAfterMaxRetries(t *testing.T) {
+	policy := ReconnectPolicy{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond, Multiplier: 1, MaxRetries: 2, Jitter: 0}
+	reconnect := NewReconnector(policy, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := reconnect.Wait(ctx, ReconnectReasonDial); err != nil {
+			t.Fatalf("attempt %d: unexpected error %v", i+1, err)
+		}
+	}
+	if err := reconnect.Wait(ctx, ReconnectReasonDial); !errors.Is(err, ErrReconnectExhausted) {
+		t.Fatalf("expected ErrReconnectExhausted, got %v", err)
+	}
+
+	reconnect.Connected(ctx)
+	if err := reconnect.Wait(ctx, ReconnectReasonRead); err != nil {
+		t.Fatalf("expected budget reset after connect, got %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	slow := NewReconnector(ReconnectPolicy{InitialInterval: time.Hour, MaxInterval: time.Hour, Multiplier: 1, MaxRetries: 0, Jitter: 0}, nil)
+	if err := slow.Wait(cancelled, ReconnectReasonRead); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDisconnectReason(t *testing.T) {
+	readErr := Disconnect(ReconnectReasonRead, fmt.Errorf("read: remote closed with status %d", 1011))
+	if got := DisconnectReason(readErr); got != ReconnectReasonRead {
+		t.Fatalf("reason = %s, want %s", got, ReconnectReasonRead)
+	}
+	if readErr.Error() != "read: remote closed with status 1011" {
+		t.Fatalf("Disconnect changed the error message: %s", readErr)
+	}
+	if Disconnect(ReconnectReasonPing, nil) != nil {
+		t.Fatalf("expected nil for nil error")
+	}
+	if got := DisconnectReason(Disconnect(ReconnectReasonPing, context.Canceled)); got != ReconnectReasonClosed {
+		t.Fatalf("cancelled loop reason = %s, want %s", got, ReconnectReasonClosed)
+	}
+	if got := DisconnectReason(nil); got != ReconnectReasonClosed {
+		t.Fatalf("nil reason = %s", got)
+	}
+}
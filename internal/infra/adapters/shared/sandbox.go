@@ -0,0 +1,518 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	json "github.com/goccy/go-json"
+)
+
+// Directions of a recorded websocket frame.
+const (
+	// FrameSent is a frame the adapter sent to the venue.
+	FrameSent = "send"
+	// FrameReceived is a frame the venue sent to the adapter.
+	FrameReceived = "recv"
+)
+
+// sandboxReadLimit lifts the websocket message size limit; venue book snapshots exceed the
+// library default.
+const sandboxReadLimit = 16 << 20
+
+// sandboxVolatileParams are request parameters that change on every call, such as request
+// signatures. They are dropped from recorded queries and ignored when replayed requests are
+// matched.
+var sandboxVolatileParams = map[string]struct{}{
+	"timestamp":  {},
+	"signature":  {},
+	"recvwindow": {},
+}
+
+// sandboxDroppedHeaders are response headers not worth replaying.
+var sandboxDroppedHeaders = map[string]struct{}{
+	"Connection":        {},
+	"Content-Encoding":  {},
+	"Content-Length":    {},
+	"Date":              {},
+	"Set-Cookie":        {},
+	"Transfer-Encoding": {},
+}
+
+// SandboxFixture holds the REST exchanges and websocket sessions captured from a live venue.
+// Request headers, and with them API keys, are never recorded.
+type SandboxFixture struct {
+	RecordedAt time.Time          `json:"recordedAt"`
+	REST       []RecordedExchange `json:"rest"`
+	Websockets []RecordedSession  `json:"websockets"`
+}
+
+// RecordedExchange is one REST request and the venue's response.
+type RecordedExchange struct {
+	Method      string      `json:"method"`
+	Path        string      `json:"path"`
+	Query       string      `json:"query,omitempty"`
+	RequestBody string      `json:"requestBody,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body"`
+}
+
+// RecordedSession is one websocket connection with its frames in order.
+type RecordedSession struct {
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Frames []RecordedFrame `json:"frames"`
+}
+
+// RecordedFrame is one websocket message. OffsetMs is its time since the connection opened.
+type RecordedFrame struct {
+	Direction string `json:"dir"`
+	OffsetMs  int64  `json:"offsetMs"`
+	Text      string `json:"text,omitempty"`
+	Binary    []byte `json:"binary,omitempty"`
+}
+
+// LoadSandboxFixture reads a fixture file written by SandboxRecorder.
+func LoadSandboxFixture(path string) (*SandboxFixture, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- fixture paths come from the developer
+	if err != nil {
+		return nil, fmt.Errorf("sandbox fixture: %w", err)
+	}
+	var fixture SandboxFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("sandbox fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// Save writes the fixture to path as indented JSON, so fixtures diff well in review.
+func (f *SandboxFixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("sandbox fixture: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("sandbox fixture: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("sandbox fixture: %w", err)
+	}
+	return nil
+}
+
+// SandboxRecorder is a reverse proxy between an adapter and a live venue that records every REST
+// exchange and websocket frame passing through it. Point the adapter's REST and websocket base
+// URLs at the recorder; request paths are appended to the upstream URLs.
+type SandboxRecorder struct {
+	restURL      *url.URL
+	websocketURL *url.URL
+	client       *http.Client
+	dialOpts     *websocket.DialOptions
+	clock        func() time.Time
+
+	mu      sync.Mutex
+	fixture SandboxFixture
+}
+
+// NewSandboxRecorder proxies REST requests to restURL and websocket connections to websocketURL
+// through egress, or directly when egress is nil. Either URL may be empty when the adapter does
+// not use it.
+func NewSandboxRecorder(restURL, websocketURL string, egress *Egress) (*SandboxRecorder, error) {
+	rest, err := parseSandboxUpstream(restURL, "http", "https")
+	if err != nil {
+		return nil, fmt.Errorf("sandbox recorder: rest url: %w", err)
+	}
+	ws, err := parseSandboxUpstream(websocketURL, "ws", "wss")
+	if err != nil {
+		return nil, fmt.Errorf("sandbox recorder: websocket url: %w", err)
+	}
+	if rest == nil && ws == nil {
+		return nil, fmt.Errorf("sandbox recorder: rest or websocket url required")
+	}
+	if egress == nil {
+		egress = NewEgress(ProxyConfig{URL: "", Username: "", Password: ""})
+	}
+	client := egress.HTTPClient(30 * time.Second)
+	// Redirects are recorded as they are, not followed.
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &SandboxRecorder{
+		restURL:      rest,
+		websocketURL: ws,
+		client:       client,
+		dialOpts:     egress.WebsocketDialOptions(),
+		clock:        time.Now,
+		mu:           sync.Mutex{},
+		fixture:      SandboxFixture{RecordedAt: time.Now().UTC(), REST: nil, Websockets: nil},
+	}, nil
+}
+
+func parseSandboxUpstream(raw string, schemes ...string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(parsed.Scheme, scheme) && parsed.Host != "" {
+			return parsed, nil
+		}
+	}
+	return nil, fmt.Errorf("%q must be an absolute %s url", raw, strings.Join(schemes, " or "))
+}
+
+// Fixture returns a copy of what was recorded so far.
+func (r *SandboxRecorder) Fixture() *SandboxFixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := SandboxFixture{
+		RecordedAt: r.fixture.RecordedAt,
+		REST:       append([]RecordedExchange(nil), r.fixture.REST...),
+		Websockets: make([]RecordedSession, len(r.fixture.Websockets)),
+	}
+	for i, session := range r.fixture.Websockets {
+		out.Websockets[i] = RecordedSession{Path: session.Path, Query: session.Query, Frames: append([]RecordedFrame(nil), session.Frames...)}
+	}
+	return &out
+}
+
+// ServeHTTP proxies a REST request or websocket upgrade to the venue and records it.
+func (r *SandboxRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if isWebsocketUpgrade(req) {
+		r.proxyWebsocket(w, req)
+		return
+	}
+	r.proxyREST(w, req)
+}
+
+func (r *SandboxRecorder) proxyREST(w http.ResponseWriter, req *http.Request) {
+	if r.restURL == nil {
+		http.Error(w, "sandbox recorder: no rest upstream", http.StatusBadGateway)
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	target := sandboxTarget(r.restURL, req.URL)
+	upstream, err := http.NewRequestWithContext(req.Context(), req.Method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	upstream.Header = req.Header.Clone()
+	// Let the transport negotiate compression so recorded bodies are plain text.
+	upstream.Header.Del("Accept-Encoding")
+	resp, err := r.client.Do(upstream)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	header := make(http.Header)
+	for key, values := range resp.Header {
+		if _, drop := sandboxDroppedHeaders[http.CanonicalHeaderKey(key)]; !drop {
+			header[key] = append([]string(nil), values...)
+		}
+	}
+	r.mu.Lock()
+	r.fixture.REST = append(r.fixture.REST, RecordedExchange{
+		Method:      req.Method,
+		Path:        req.URL.Path,
+		Query:       stableQuery(req.URL.Query()),
+		RequestBody: string(body),
+		Status:      resp.StatusCode,
+		Header:      header,
+		Body:        string(respBody),
+	})
+	r.mu.Unlock()
+
+	for key, values := range header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(respBody)
+}
+
+func (r *SandboxRecorder) proxyWebsocket(w http.ResponseWriter, req *http.Request) {
+	if r.websocketURL == nil {
+		http.Error(w, "sandbox recorder: no websocket upstream", http.StatusBadGateway)
+		return
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	upstream, _, err := websocket.Dial(ctx, sandboxTarget(r.websocketURL, req.URL).String(), r.dialOpts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() { _ = upstream.CloseNow() }()
+	client, err := websocket.Accept(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer func() { _ = client.CloseNow() }()
+	upstream.SetReadLimit(sandboxReadLimit)
+	client.SetReadLimit(sandboxReadLimit)
+
+	r.mu.Lock()
+	index := len(r.fixture.Websockets)
+	r.fixture.Websockets = append(r.fixture.Websockets, RecordedSession{Path: req.URL.Path, Query: stableQuery(req.URL.Query()), Frames: nil})
+	r.mu.Unlock()
+
+	opened := r.clock()
+	record := func(direction string, typ websocket.MessageType, data []byte) {
+		frame := RecordedFrame{Direction: direction, OffsetMs: r.clock().Sub(opened).Milliseconds(), Text: "", Binary: nil}
+		if typ == websocket.MessageText {
+			frame.Text = string(data)
+		} else {
+			frame.Binary = append([]byte(nil), data...)
+		}
+		r.mu.Lock()
+		r.fixture.Websockets[index].Frames = append(r.fixture.Websockets[index].Frames, frame)
+		r.mu.Unlock()
+	}
+	pump := func(from, to *websocket.Conn, direction string) {
+		defer cancel()
+		for {
+			typ, data, err := from.Read(ctx)
+			if err != nil {
+				return
+			}
+			record(direction, typ, data)
+			if err := to.Write(ctx, typ, data); err != nil {
+				return
+			}
+		}
+	}
+	go pump(client, upstream, FrameSent)
+	pump(upstream, client, FrameReceived)
+}
+
+// SandboxReplayer serves a fixture back to an adapter in place of the venue. REST requests are
+// answered with the recorded response of the same method, path and query, ignoring volatile
+// parameters such as signatures; repeated requests take the recorded responses in order and then
+// keep the last. Websocket connections replay the next recorded session of their path: venue
+// frames are sent in order, and at each frame the adapter sent the replayer waits for the
+// adapter's next message, so subscriptions precede the data they asked for.
+type SandboxReplayer struct {
+	fixture *SandboxFixture
+	// realtime paces venue frames by their recorded offsets instead of sending them at once.
+	realtime bool
+	// sendTimeout bounds the wait for an adapter message before replay moves on.
+	sendTimeout time.Duration
+
+	mu       sync.Mutex
+	served   map[string]int
+	sessions map[string]int
+}
+
+// NewSandboxReplayer replays fixture. With realtime set, venue frames keep their recorded pacing.
+func NewSandboxReplayer(fixture *SandboxFixture, realtime bool) *SandboxReplayer {
+	if fixture == nil {
+		fixture = &SandboxFixture{RecordedAt: time.Time{}, REST: nil, Websockets: nil}
+	}
+	return &SandboxReplayer{
+		fixture:     fixture,
+		realtime:    realtime,
+		sendTimeout: 5 * time.Second,
+		mu:          sync.Mutex{},
+		served:      make(map[string]int),
+		sessions:    make(map[string]int),
+	}
+}
+
+// ServeHTTP answers a REST request or websocket upgrade from the fixture.
+func (p *SandboxReplayer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if isWebsocketUpgrade(req) {
+		p.replayWebsocket(w, req)
+		return
+	}
+	exchange, ok := p.nextExchange(req.Method, req.URL.Path, stableQuery(req.URL.Query()))
+	if !ok {
+		http.Error(w, fmt.Sprintf("sandbox replay: no recorded response for %s %s", req.Method, req.URL.RequestURI()), http.StatusNotFound)
+		return
+	}
+	for key, values := range exchange.Header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+	w.WriteHeader(exchange.Status)
+	_, _ = io.WriteString(w, exchange.Body)
+}
+
+// nextExchange picks the recorded exchange for a request: the next unserved one with the same
+// query, else the next with the same path, repeating the last once all were served.
+func (p *SandboxReplayer) nextExchange(method, path, query string) (RecordedExchange, bool) {
+	var empty RecordedExchange
+	for _, exact := range []bool{true, false} {
+		var matches []int
+		for i, exchange := range p.fixture.REST {
+			if exchange.Method == method && exchange.Path == path && (!exact || exchange.Query == query) {
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		key := method + " " + path
+		if exact {
+			key += "?" + query
+		}
+		p.mu.Lock()
+		served := p.served[key]
+		p.served[key] = served + 1
+		p.mu.Unlock()
+		if served >= len(matches) {
+			served = len(matches) - 1
+		}
+		return p.fixture.REST[matches[served]], true
+	}
+	return empty, false
+}
+
+func (p *SandboxReplayer) nextSession(path, query string) (RecordedSession, bool) {
+	var empty RecordedSession
+	for _, exact := range []bool{true, false} {
+		var matches []int
+		for i, session := range p.fixture.Websockets {
+			if session.Path == path && (!exact || session.Query == query) {
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		key := path
+		if exact {
+			key += "?" + query
+		}
+		p.mu.Lock()
+		next := p.sessions[key]
+		p.sessions[key] = next + 1
+		p.mu.Unlock()
+		if next >= len(matches) {
+			next = len(matches) - 1
+		}
+		return p.fixture.Websockets[matches[next]], true
+	}
+	return empty, false
+}
+
+func (p *SandboxReplayer) replayWebsocket(w http.ResponseWriter, req *http.Request) {
+	session, ok := p.nextSession(req.URL.Path, stableQuery(req.URL.Query()))
+	if !ok {
+		http.Error(w, fmt.Sprintf("sandbox replay: no recorded websocket for %s", req.URL.RequestURI()), http.StatusNotFound)
+		return
+	}
+	conn, err := websocket.Accept(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.CloseNow() }()
+	conn.SetReadLimit(sandboxReadLimit)
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	// Reading continuously keeps answering the adapter's pings while frames are replayed.
+	received := make(chan struct{}, 64)
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.Read(ctx); err != nil {
+				return
+			}
+			select {
+			case received <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	start := time.Now()
+	for _, frame := range session.Frames {
+		if frame.Direction == FrameSent {
+			timer := time.NewTimer(p.sendTimeout)
+			select {
+			case <-received:
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			timer.Stop()
+			continue
+		}
+		if p.realtime {
+			if wait := time.Duration(frame.OffsetMs)*time.Millisecond - time.Since(start); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		typ, data := websocket.MessageText, []byte(frame.Text)
+		if frame.Binary != nil {
+			typ, data = websocket.MessageBinary, frame.Binary
+		}
+		if err := conn.Write(ctx, typ, data); err != nil {
+			return
+		}
+	}
+	// Hold the connection open once the recording is exhausted, as a quiet venue would.
+	<-ctx.Done()
+}
+
+func isWebsocketUpgrade(req *http.Request) bool {
+	return strings.EqualFold(req.Header.Get("Upgrade"), "websocket")
+}
+
+// sandboxTarget appends the request path and query to the upstream URL.
+func sandboxTarget(upstream, request *url.URL) *url.URL {
+	target := *upstream
+	target.Path = strings.TrimSuffix(upstream.Path, "/") + request.Path
+	target.RawPath = ""
+	target.RawQuery = request.RawQuery
+	return &target
+}
+
+// stableQuery encodes the query without volatile parameters, with keys sorted.
+func stableQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if _, volatile := sandboxVolatileParams[strings.ToLower(key)]; !volatile {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	stable := make(url.Values, len(keys))
+	for _, key := range keys {
+		stable[key] = values[key]
+	}
+	return stable.Encode()
+}
@@ -0,0 +1,120 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// sandboxVenue answers REST requests with their query and, on websocket connections, sends two
+// trades once the client subscribes.
+func sandboxVenue(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isWebsocketUpgrade(r) {
+			conn, err := websocket.Accept(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.CloseNow() }()
+			if _, _, err := conn.Read(r.Context()); err != nil {
+				return
+			}
+			for _, frame := range []string{`{"e":"trade","p":"100"}`, `{"e":"trade","p":"101"}`} {
+				if err := conn.Write(r.Context(), websocket.MessageText, []byte(frame)); err != nil {
+					return
+				}
+			}
+			_, _, _ = conn.Read(r.Context())
+			return
+		}
+		w.Header().Set("X-Mbx-Used-Weight", "1")
+		_, _ = io.WriteString(w, `{"symbol":"`+r.URL.Query().Get("symbol")+`","at":"`+r.URL.Query().Get("timestamp")+`"}`)
+	}))
+}
+
+// sandboxSession runs what an adapter would against base: a signed REST call and a websocket
+// subscription. It returns the REST body and the streamed frames.
+func sandboxSession(t *testing.T, base string, timestamp string) (string, []string) {
+	t.Helper()
+	resp, err := http.Get(base + "/api/v3/order?symbol=BTCUSDT&timestamp=" + timestamp + "&signature=abc" + timestamp)
+	if err != nil {
+		t.Fatalf("rest: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.Header.Get("X-Mbx-Used-Weight") != "1" {
+		t.Fatalf("expected the weight header to pass through, got %v", resp.Header)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(base, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.CloseNow() }()
+	if err := conn.Write(ctx, websocket.MessageText, []byte(`{"method":"SUBSCRIBE","params":["btcusdt@trade"]}`)); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	frames := make([]string, 0, 2)
+	for len(frames) < 2 {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		frames = append(frames, string(data))
+	}
+	_ = conn.Close(websocket.StatusNormalClosure, "")
+	return string(body), frames
+}
+
+func TestSandboxRecordsAndReplaysVenueTraffic(t *testing.T) {
+	venue := sandboxVenue(t)
+	defer venue.Close()
+	recorder, err := NewSandboxRecorder(venue.URL, "ws"+strings.TrimPrefix(venue.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("NewSandboxRecorder: %v", err)
+	}
+	proxy := httptest.NewServer(recorder)
+	liveBody, liveFrames := sandboxSession(t, proxy.URL, "1700000000000")
+	proxy.Close()
+
+	fixture := recorder.Fixture()
+	if len(fixture.REST) != 1 || fixture.REST[0].Query != "symbol=BTCUSDT" {
+		t.Fatalf("expected one REST exchange without volatile params, got %+v", fixture.REST)
+	}
+	if len(fixture.Websockets) != 1 || len(fixture.Websockets[0].Frames) != 3 || fixture.Websockets[0].Frames[0].Direction != FrameSent {
+		t.Fatalf("expected the subscription and two trades, got %+v", fixture.Websockets)
+	}
+	path := filepath.Join(t.TempDir(), "binance", "order.json")
+	if err := fixture.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadSandboxFixture(path)
+	if err != nil {
+		t.Fatalf("LoadSandboxFixture: %v", err)
+	}
+
+	replay := httptest.NewServer(NewSandboxReplayer(loaded, false))
+	defer replay.Close()
+	replayBody, replayFrames := sandboxSession(t, replay.URL, "1800000000000")
+	if replayBody != liveBody || strings.Join(replayFrames, "\n") != strings.Join(liveFrames, "\n") {
+		t.Fatalf("replay diverged: %s %v, recorded %s %v", replayBody, replayFrames, liveBody, liveFrames)
+	}
+	resp, err := http.Get(replay.URL + "/api/v3/account")
+	if err != nil {
+		t.Fatalf("unrecorded request: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unrecorded request, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,297 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// StreamRateWindow is the span over which stream diagnostics average message and byte rates.
+const StreamRateWindow = 10 * time.Second
+
+const streamRateSeconds = int64(StreamRateWindow / time.Second)
+
+// DecodeError marks a stream handler error caused by a message the adapter could not decode, so
+// stream stats count it as a decode error rather than a handler failure.
+func DecodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &decodeError{err: err}
+}
+
+// IsDecodeError reports whether err was marked by DecodeError.
+func IsDecodeError(err error) bool {
+	var decode *decodeError
+	return errors.As(err, &decode)
+}
+
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string { return e.err.Error() }
+
+func (e *decodeError) Unwrap() error { return e.err }
+
+// StreamDiagnostics summarises the traffic of one adapter stream. Totals count since the provider
+// started; rates and handler latencies cover the last StreamRateWindow.
+type StreamDiagnostics struct {
+	Stream         string     `json:"stream"`
+	Messages       uint64     `json:"messages"`
+	Bytes          uint64     `json:"bytes"`
+	DecodeErrors   uint64     `json:"decodeErrors"`
+	HandlerErrors  uint64     `json:"handlerErrors"`
+	MessagesPerSec float64    `json:"messagesPerSec"`
+	BytesPerSec    float64    `json:"bytesPerSec"`
+	HandlerAvgMs   float64    `json:"handlerAvgMs"`
+	HandlerMaxMs   float64    `json:"handlerMaxMs"`
+	LastMessageAt  *time.Time `json:"lastMessageAt,omitempty"`
+}
+
+// StreamStatsSet holds the stream stats of one provider, keyed by stream name. Stream managers
+// sharing a name, such as the shards of one stream, share its stats.
+type StreamStatsSet struct {
+	environment string
+	provider    string
+	clock       func() time.Time
+
+	messages      metric.Int64Counter
+	bytes         metric.Int64Counter
+	decodeErrors  metric.Int64Counter
+	handlerErrors metric.Int64Counter
+	latency       metric.Float64Histogram
+
+	mu      sync.Mutex
+	streams map[string]*StreamStats
+}
+
+// NewStreamStatsSet registers the stream instruments of an adapter for one provider.
+func NewStreamStatsSet(adapter, provider string) *StreamStatsSet {
+	adapter = strings.ToLower(strings.TrimSpace(adapter))
+	meter := otel.Meter("adapter." + adapter)
+	set := &StreamStatsSet{
+		environment:   telemetry.Environment(),
+		provider:      strings.TrimSpace(provider),
+		clock:         time.Now,
+		messages:      nil,
+		bytes:         nil,
+		decodeErrors:  nil,
+		handlerErrors: nil,
+		latency:       nil,
+		mu:            sync.Mutex{},
+		streams:       make(map[string]*StreamStats),
+	}
+	set.messages, _ = meter.Int64Counter(fmt.Sprintf("meltica_provider_%s_ws_stream_messages", adapter),
+		metric.WithDescription("Data messages received per websocket stream"),
+		metric.WithUnit("{message}"))
+	set.bytes, _ = meter.Int64Counter(fmt.Sprintf("meltica_provider_%s_ws_stream_bytes", adapter),
+		metric.WithDescription("Bytes of data messages received per websocket stream"),
+		metric.WithUnit("By"))
+	set.decodeErrors, _ = meter.Int64Counter(fmt.Sprintf("meltica_provider_%s_ws_decode_errors", adapter),
+		metric.WithDescription("Websocket stream messages that could not be decoded"),
+		metric.WithUnit("{message}"))
+	set.handlerErrors, _ = meter.Int64Counter(fmt.Sprintf("meltica_provider_%s_ws_handler_errors", adapter),
+		metric.WithDescription("Websocket stream messages whose handler failed"),
+		metric.WithUnit("{message}"))
+	set.latency, _ = meter.Float64Histogram(fmt.Sprintf("meltica_provider_%s_ws_handler_latency", adapter),
+		metric.WithDescription("Time spent handling one websocket stream message"),
+		metric.WithUnit("ms"))
+	return set
+}
+
+// Stream returns the stats of the named stream, creating them on first use.
+func (s *StreamStatsSet) Stream(name string) *StreamStats {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stats, ok := s.streams[name]; ok {
+		return stats
+	}
+	stats := &StreamStats{
+		set:           s,
+		name:          name,
+		attrs:         telemetry.MessageAttributes(s.environment, s.provider, name),
+		mu:            sync.Mutex{},
+		messages:      0,
+		bytes:         0,
+		decodeErrors:  0,
+		handlerErrors: 0,
+		lastMessageAt: time.Time{},
+		window:        [streamRateSeconds]streamSecond{},
+	}
+	s.streams[name] = stats
+	return stats
+}
+
+// Diagnostics summarises every stream, ordered by name.
+func (s *StreamStatsSet) Diagnostics() []StreamDiagnostics {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	streams := make([]*StreamStats, 0, len(s.streams))
+	for _, stats := range s.streams {
+		streams = append(streams, stats)
+	}
+	s.mu.Unlock()
+	now := s.clock()
+	out := make([]StreamDiagnostics, 0, len(streams))
+	for _, stats := range streams {
+		out = append(out, stats.diagnostics(now))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Stream < out[j].Stream })
+	return out
+}
+
+// StreamStats counts the messages of one adapter stream and exports them via telemetry.
+type StreamStats struct {
+	set   *StreamStatsSet
+	name  string
+	attrs []attribute.KeyValue
+
+	mu            sync.Mutex
+	messages      uint64
+	bytes         uint64
+	decodeErrors  uint64
+	handlerErrors uint64
+	lastMessageAt time.Time
+	window        [streamRateSeconds]streamSecond
+}
+
+// streamSecond aggregates the messages received during one wall-clock second.
+type streamSecond struct {
+	second   int64
+	messages uint64
+	bytes    uint64
+	handled  uint64
+	total    time.Duration
+	max      time.Duration
+}
+
+// RecordMessage counts a data message of size bytes that took latency to handle. err is the
+// handler's result; errors marked by DecodeError count as decode errors.
+func (s *StreamStats) RecordMessage(ctx context.Context, size int, latency time.Duration, err error) {
+	s.record(ctx, size, latency, err, true)
+}
+
+// RecordDecodeError counts a message of size bytes that could not be decoded before reaching a
+// handler.
+func (s *StreamStats) RecordDecodeError(ctx context.Context, size int) {
+	s.record(ctx, size, 0, DecodeError(errors.New("undecodable message")), false)
+}
+
+func (s *StreamStats) record(ctx context.Context, size int, latency time.Duration, err error, handled bool) {
+	if s == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if latency < 0 {
+		latency = 0
+	}
+	decode := err != nil && IsDecodeError(err)
+	now := s.set.clock()
+	s.mu.Lock()
+	s.messages++
+	s.bytes += uint64(max(size, 0))
+	s.lastMessageAt = now
+	switch {
+	case decode:
+		s.decodeErrors++
+	case err != nil:
+		s.handlerErrors++
+	}
+	bucket := s.bucketLocked(now)
+	bucket.messages++
+	bucket.bytes += uint64(max(size, 0))
+	if handled {
+		bucket.handled++
+		bucket.total += latency
+		bucket.max = max(bucket.max, latency)
+	}
+	s.mu.Unlock()
+
+	opts := metric.WithAttributes(s.attrs...)
+	if s.set.messages != nil {
+		s.set.messages.Add(ctx, 1, opts)
+	}
+	if s.set.bytes != nil && size > 0 {
+		s.set.bytes.Add(ctx, int64(size), opts)
+	}
+	if handled && s.set.latency != nil {
+		s.set.latency.Record(ctx, float64(latency)/float64(time.Millisecond), opts)
+	}
+	switch {
+	case decode && s.set.decodeErrors != nil:
+		s.set.decodeErrors.Add(ctx, 1, opts)
+	case err != nil && !decode && s.set.handlerErrors != nil:
+		s.set.handlerErrors.Add(ctx, 1, opts)
+	}
+}
+
+func (s *StreamStats) bucketLocked(now time.Time) *streamSecond {
+	second := now.Unix()
+	bucket := &s.window[second%streamRateSeconds]
+	if bucket.second != second {
+		*bucket = streamSecond{second: second, messages: 0, bytes: 0, handled: 0, total: 0, max: 0}
+	}
+	return bucket
+}
+
+func (s *StreamStats) diagnostics(now time.Time) StreamDiagnostics {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	diagnostics := StreamDiagnostics{
+		Stream:         s.name,
+		Messages:       s.messages,
+		Bytes:          s.bytes,
+		DecodeErrors:   s.decodeErrors,
+		HandlerErrors:  s.handlerErrors,
+		MessagesPerSec: 0,
+		BytesPerSec:    0,
+		HandlerAvgMs:   0,
+		HandlerMaxMs:   0,
+		LastMessageAt:  nil,
+	}
+	if !s.lastMessageAt.IsZero() {
+		last := s.lastMessageAt.UTC()
+		diagnostics.LastMessageAt = &last
+	}
+	var (
+		messages, bytes, handled uint64
+		total, peak              time.Duration
+	)
+	oldest := now.Unix() - streamRateSeconds
+	for _, bucket := range s.window {
+		if bucket.second <= oldest || bucket.second > now.Unix() {
+			continue
+		}
+		messages += bucket.messages
+		bytes += bucket.bytes
+		handled += bucket.handled
+		total += bucket.total
+		peak = max(peak, bucket.max)
+	}
+	seconds := StreamRateWindow.Seconds()
+	diagnostics.MessagesPerSec = float64(messages) / seconds
+	diagnostics.BytesPerSec = float64(bytes) / seconds
+	if handled > 0 {
+		diagnostics.HandlerAvgMs = float64(total) / float64(handled) / float64(time.Millisecond)
+	}
+	diagnostics.HandlerMaxMs = float64(peak) / float64(time.Millisecond)
+	return diagnostics
+}
@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStreamStatsDiagnostics(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	set := NewStreamStatsSet("binance", "binance-spot")
+	set.clock = func() time.Time { return now }
+	trade := set.Stream("trade")
+	if set.Stream("trade") != trade {
+		t.Fatal("expected shards of a stream to share its stats")
+	}
+
+	ctx := context.Background()
+	trade.RecordMessage(ctx, 100, 2*time.Millisecond, nil)
+	trade.RecordMessage(ctx, 300, 4*time.Millisecond, DecodeError(fmt.Errorf("decode trade message: %w", errors.New("bad json"))))
+	trade.RecordMessage(ctx, 100, 6*time.Millisecond, errors.New("publish failed"))
+	set.Stream("book").RecordDecodeError(ctx, 50)
+
+	// Messages older than the rate window only count toward the totals.
+	now = now.Add(StreamRateWindow)
+	trade.RecordMessage(ctx, 500, 8*time.Millisecond, nil)
+
+	diagnostics := set.Diagnostics()
+	if len(diagnostics) != 2 || diagnostics[0].Stream != "book" || diagnostics[1].Stream != "trade" {
+		t.Fatalf("expected book and trade diagnostics, got %+v", diagnostics)
+	}
+	book := diagnostics[0]
+	if book.Messages != 1 || book.DecodeErrors != 1 || book.HandlerAvgMs != 0 || book.MessagesPerSec != 0 {
+		t.Fatalf("unexpected book diagnostics %+v", book)
+	}
+	got := diagnostics[1]
+	if got.Messages != 4 || got.Bytes != 1000 || got.DecodeErrors != 1 || got.HandlerErrors != 1 {
+		t.Fatalf("unexpected trade totals %+v", got)
+	}
+	if got.MessagesPerSec != 0.1 || got.BytesPerSec != 50 || got.HandlerAvgMs != 8 || got.HandlerMaxMs != 8 {
+		t.Fatalf("unexpected trade rates %+v", got)
+	}
+	if got.LastMessageAt == nil || !got.LastMessageAt.Equal(now) {
+		t.Fatalf("expected last message at %v, got %v", now, got.LastMessageAt)
+	}
+}
+
+func TestStreamStatsNilSafe(t *testing.T) {
+	var set *StreamStatsSet
+	set.Stream("trade").RecordMessage(context.Background(), 10, time.Millisecond, nil)
+	if diagnostics := set.Diagnostics(); diagnostics != nil {
+		t.Fatalf("expected no diagnostics, got %+v", diagnostics)
+	}
+	if IsDecodeError(errors.New("plain")) || IsDecodeError(nil) || DecodeError(nil) != nil {
+		t.Fatal("expected only marked errors to be decode errors")
+	}
+}
@@ -46,6 +46,11 @@ func (m *SubscriptionManager) Activate(ctx context.Context, route dispatcher.Rou
 	if ok && dispatcher.EqualRoutes(existing, route) {
 		return nil
 	}
+	if route.Type == schema.RouteTypeOrderbookSnapshot && (!ok || existing.Book != route.Book) {
+		if configurer, isConfigurer := m.subscriber.(BookRouteConfigurer); isConfigurer {
+			configurer.ConfigureBookRoute(route.Book)
+		}
+	}
 
 	if !ok {
 		if m.subscriber != nil {
@@ -156,6 +161,9 @@ func sameNonFilterConfig(a, b dispatcher.Route) bool {
 	rhs := b
 	lhs.Filters = nil
 	rhs.Filters = nil
+	// Book options are applied through BookRouteConfigurer without resubscribing.
+	lhs.Book = dispatcher.BookOptions{Depth: 0, Interval: 0}
+	rhs.Book = dispatcher.BookOptions{Depth: 0, Interval: 0}
 	return dispatcher.EqualRoutes(lhs, rhs)
 }
 
@@ -170,6 +178,7 @@ func buildDeltaRoute(base dispatcher.Route, filters []dispatcher.FilterRule) dis
 		WSTopics: base.WSTopics,
 		RestFns:  base.RestFns,
 		Filters:  filters,
+		Book:     base.Book,
 	}
 	return *route
 }
@@ -225,7 +234,7 @@ func diffFilters(target, reference []dispatcher.FilterRule) []dispatcher.FilterR
 	refSets := make(map[string]map[string]struct{}, len(reference))
 	for _, filter := range reference {
 		normField := strings.TrimSpace(strings.ToLower(filter.Field))
-		if normField == "" {
+		if normField == "" || !filter.Selects() {
 			continue
 		}
 		set := refSets[normField]
@@ -244,7 +253,7 @@ func diffFilters(target, reference []dispatcher.FilterRule) []dispatcher.FilterR
 	deltas := make(map[string]*fieldDelta)
 	for _, filter := range target {
 		normField := strings.TrimSpace(strings.ToLower(filter.Field))
-		if normField == "" {
+		if normField == "" || !filter.Selects() {
 			continue
 		}
 		values := flattenFilterValues(filter.Value)
@@ -3,6 +3,7 @@ package shared
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/coachpo/meltica/internal/app/dispatcher"
 	"github.com/coachpo/meltica/internal/domain/schema"
@@ -169,3 +170,41 @@ func containsValue(value any, target string) bool {
 	}
 	return false
 }
+
+type bookStubSubscriber struct {
+	stubSubscriber
+	configured []dispatcher.BookOptions
+}
+
+func (s *bookStubSubscriber) ConfigureBookRoute(opts dispatcher.BookOptions) {
+	s.configured = append(s.configured, opts)
+}
+
+func TestSubscriptionManagerAppliesBookOptionsWithoutResubscribing(t *testing.T) {
+	subscriber := &bookStubSubscriber{}
+	manager := NewSubscriptionManager(subscriber)
+
+	route := dispatcher.Route{
+		Provider: "binance",
+		Type:     schema.RouteTypeOrderbookSnapshot,
+		Filters:  []dispatcher.FilterRule{{Field: "instrument", Op: "eq", Value: "BTC-USDT"}},
+		Book:     dispatcher.BookOptions{Depth: 20},
+	}
+	if err := manager.Activate(context.Background(), route); err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+	route.Book = dispatcher.BookOptions{Depth: 5, Interval: time.Second}
+	if err := manager.Activate(context.Background(), route); err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+
+	if len(subscriber.configured) != 2 || subscriber.configured[1] != route.Book {
+		t.Fatalf("expected book options applied on each change, got %+v", subscriber.configured)
+	}
+	if len(subscriber.subscribed) != 1 || len(subscriber.unsubscribed) != 0 {
+		t.Fatalf("expected a single subscription, got %d subscribes and %d unsubscribes", len(subscriber.subscribed), len(subscriber.unsubscribed))
+	}
+	if active := manager.Snapshot(); len(active) != 1 || active[0].Book != route.Book {
+		t.Fatalf("expected the active route to carry the new options, got %+v", active)
+	}
+}
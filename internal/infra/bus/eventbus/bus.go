@@ -22,12 +22,25 @@ type Bus interface {
 	Close()
 }
 
+// DurableSubscriber is implemented by buses that can replay persisted events to named
+// subscribers and track how far each subscriber has progressed.
+type DurableSubscriber interface {
+	// Replay delivers persisted events of the given types that were published after the
+	// subscriber's committed offset. Ownership of each event passes to deliver. It returns the
+	// identifier of the last replayed event, or the starting offset when nothing was replayed.
+	Replay(ctx context.Context, subscriber string, types []schema.EventType, deliver func(*schema.Event)) (int64, error)
+	// CommitOffset records that the subscriber has processed every event up to offset.
+	CommitOffset(ctx context.Context, subscriber string, offset int64) error
+}
+
 // MemoryConfig configures the in-memory bus buffers.
 type MemoryConfig struct {
 	BufferSize               int
 	FanoutWorkers            int
 	ExtensionPayloadCapBytes int
 	Pools                    *pool.PoolManager
+	Priority                 PriorityConfig
+	Watermark                WatermarkConfig
 }
 
 func (c MemoryConfig) normalize() MemoryConfig {
@@ -40,5 +53,8 @@ func (c MemoryConfig) normalize() MemoryConfig {
 	if c.ExtensionPayloadCapBytes <= 0 {
 		c.ExtensionPayloadCapBytes = DefaultExtensionPayloadCapBytes
 	}
+	if c.Priority.Enabled {
+		c.Priority = c.Priority.normalize(c.BufferSize)
+	}
 	return c
 }
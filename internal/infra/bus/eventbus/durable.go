@@ -2,9 +2,9 @@ package eventbus
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 	"sync"
 	"time"
@@ -12,9 +12,13 @@ import (
 	"github.com/coachpo/meltica/internal/domain/outboxstore"
 	"github.com/coachpo/meltica/internal/domain/schema"
 	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
 	json "github.com/goccy/go-json"
 )
 
+// ErrDurableSubscriptionsUnsupported indicates the configured outbox store cannot track subscriber offsets.
+var ErrDurableSubscriptionsUnsupported = errors.New("durable bus: subscription offsets unsupported")
+
 // DurableOption configures the durable bus wrapper.
 type DurableOption func(*DurableBus)
 
@@ -110,7 +114,7 @@ func NewDurableBus(inner Bus, store outboxstore.Store, opts ...DurableOption) Bu
 	durable := &DurableBus{
 		inner:                    inner,
 		store:                    store,
-		logger:                   log.New(os.Stdout, "eventbus/durable ", log.LstdFlags|log.Lmicroseconds),
+		logger:                   log.New(redact.Stdout, "eventbus/durable ", log.LstdFlags|log.Lmicroseconds),
 		replayInterval:           defaultReplayInterval,
 		replayBatchSize:          defaultReplayBatchSize,
 		replayDisabled:           false,
@@ -169,6 +173,7 @@ func (b *DurableBus) Publish(ctx context.Context, evt *schema.Event) error {
 		b.recycle(evt)
 		return err
 	}
+	evt.OutboxID = recordID
 	if err := b.inner.Publish(ctx, evt); err != nil {
 		b.markFailure(ctx, recordID, err)
 		return fmt.Errorf("durable bus publish: %w", err)
@@ -214,6 +219,84 @@ func (b *DurableBus) Close() {
 	}
 }
 
+// Replay streams outbox entries recorded after the subscriber's committed offset. Subscribers
+// without a stored offset are anchored at the current outbox head so that future restarts
+// resume from this point.
+func (b *DurableBus) Replay(ctx context.Context, subscriber string, types []schema.EventType, deliver func(*schema.Event)) (int64, error) {
+	offsets, err := b.subscriptionStore()
+	if err != nil {
+		return 0, err
+	}
+	subscriber = strings.TrimSpace(subscriber)
+	if subscriber == "" {
+		return 0, fmt.Errorf("durable bus replay: subscriber required")
+	}
+	ctx = safeContext(ctx)
+	offset, found, err := offsets.LoadOffset(ctx, subscriber)
+	if err != nil {
+		return 0, fmt.Errorf("durable bus replay: load offset: %w", err)
+	}
+	if !found {
+		latest, err := offsets.LatestID(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("durable bus replay: latest id: %w", err)
+		}
+		if err := offsets.CommitOffset(ctx, subscriber, latest); err != nil {
+			return 0, fmt.Errorf("durable bus replay: anchor offset: %w", err)
+		}
+		return latest, nil
+	}
+	if len(types) == 0 || deliver == nil {
+		return offset, nil
+	}
+	names := make([]string, 0, len(types))
+	for _, typ := range types {
+		names = append(names, string(typ))
+	}
+	for {
+		records, err := offsets.ListAfter(ctx, offset, names, b.replayBatchSize)
+		if err != nil {
+			return offset, fmt.Errorf("durable bus replay: list: %w", err)
+		}
+		for _, record := range records {
+			offset = record.ID
+			event, err := b.prepareReplayEvent(ctx, record.Payload)
+			if err != nil {
+				b.logf("subscriber %s replay decode failed (id=%d): %v", subscriber, record.ID, err)
+				continue
+			}
+			event.OutboxID = record.ID
+			deliver(event)
+		}
+		if len(records) < b.replayBatchSize {
+			return offset, nil
+		}
+	}
+}
+
+// CommitOffset persists the subscriber's progress through the outbox.
+func (b *DurableBus) CommitOffset(ctx context.Context, subscriber string, offset int64) error {
+	offsets, err := b.subscriptionStore()
+	if err != nil {
+		return err
+	}
+	if err := offsets.CommitOffset(safeContext(ctx), strings.TrimSpace(subscriber), offset); err != nil {
+		return fmt.Errorf("durable bus commit offset: %w", err)
+	}
+	return nil
+}
+
+func (b *DurableBus) subscriptionStore() (outboxstore.SubscriptionStore, error) {
+	if b == nil || b.store == nil {
+		return nil, ErrDurableSubscriptionsUnsupported
+	}
+	offsets, ok := b.store.(outboxstore.SubscriptionStore)
+	if !ok {
+		return nil, ErrDurableSubscriptionsUnsupported
+	}
+	return offsets, nil
+}
+
 func (b *DurableBus) startReplayWorker() {
 	if b.store == nil || b.inner == nil {
 		return
@@ -255,6 +338,7 @@ func (b *DurableBus) replayPendingEvents() {
 			_ = b.store.MarkFailed(ctx, record.ID, err.Error())
 			continue
 		}
+		event.OutboxID = record.ID
 		if err := b.inner.Publish(ctx, event); err != nil {
 			b.logf("outbox replay publish failed (id=%d): %v", record.ID, err)
 			_ = b.store.MarkFailed(ctx, record.ID, err.Error())
@@ -295,10 +379,12 @@ func (b *DurableBus) enqueueEvent(ctx context.Context, evt *schema.Event) (int64
 	if evt == nil {
 		return 0, fmt.Errorf("durable bus: event required")
 	}
-	payload, err := eventToJSON(evt)
+	payload, err := encodeEvent(evt)
 	if err != nil {
 		return 0, fmt.Errorf("durable bus: encode payload: %w", err)
 	}
+	// The store copies the payload into the row, so the pooled buffer is reused afterwards.
+	defer payload.Release()
 	headers := map[string]any{}
 	if trimmed := strings.TrimSpace(evt.Provider); trimmed != "" {
 		headers["provider"] = trimmed
@@ -314,7 +400,7 @@ func (b *DurableBus) enqueueEvent(ctx context.Context, evt *schema.Event) (int64
 		AggregateType: aggregateType,
 		AggregateID:   aggregateID,
 		EventType:     string(evt.Type),
-		Payload:       payload,
+		Payload:       payload.Bytes(),
 		Headers:       headers,
 		AvailableAt:   evt.EmitTS,
 	})
@@ -344,15 +430,16 @@ func (b *DurableBus) logf(format string, args ...any) {
 	b.logger.Printf(format, args...)
 }
 
-func eventToJSON(evt *schema.Event) (json.RawMessage, error) {
+// encodeEvent encodes evt into a pooled buffer the caller releases once the outbox row is written.
+func encodeEvent(evt *schema.Event) (*pool.JSONBuffer, error) {
 	if evt == nil {
 		return nil, fmt.Errorf("nil event")
 	}
-	data, err := json.Marshal(evt)
+	buf, err := pool.EncodeJSONBuffer(evt)
 	if err != nil {
 		return nil, fmt.Errorf("marshal event: %w", err)
 	}
-	return json.RawMessage(data), nil
+	return buf, nil
 }
 
 func rawToEvent(payload json.RawMessage) (*schema.Event, error) {
@@ -452,4 +539,7 @@ func rehydrateEventPayload(evt *schema.Event) {
 	}
 }
 
-var _ Bus = (*DurableBus)(nil)
+var (
+	_ Bus               = (*DurableBus)(nil)
+	_ DurableSubscriber = (*DurableBus)(nil)
+)
@@ -95,7 +95,7 @@ func TestDurableBusReplayUsesEventPool(t *testing.T) {
 		Symbol:   "BTCUSDT",
 		Type:     schema.EventTypeTrade,
 	}
-	payload, err := eventToJSON(evt)
+	payload, err := pool.EncodeJSON(evt)
 	if err != nil {
 		t.Fatalf("eventToMap failed: %v", err)
 	}
@@ -140,8 +140,9 @@ type fakeOutboxStore struct {
 
 func (s *fakeOutboxStore) Enqueue(_ context.Context, evt outboxstore.Event) (outboxstore.EventRecord, error) {
 	s.nextID++
-	s.enqueued = append(s.enqueued, evt)
 	payload := json.RawMessage(append([]byte(nil), evt.Payload...))
+	evt.Payload = payload
+	s.enqueued = append(s.enqueued, evt)
 	record := outboxstore.EventRecord{ID: s.nextID, Payload: payload, EventType: evt.EventType}
 	s.pending = append(s.pending, record)
 	return record, nil
@@ -192,7 +193,7 @@ func TestDurableBusReplayPreservesSequenceAndPayload(t *testing.T) {
 			Quantity: "1.0",
 		},
 	}
-	raw, err := eventToJSON(event)
+	raw, err := pool.EncodeJSON(event)
 	if err != nil {
 		t.Fatalf("encode event: %v", err)
 	}
@@ -305,3 +306,119 @@ func TestDurableBusPublishExtensionPayloadOverCapReturnsEventToPool(t *testing.T
 		t.Fatalf("shutdown pool: %v", err)
 	}
 }
+
+func TestDurableBus_ReplayResumesFromCommittedOffset(t *testing.T) {
+	inner := &stubBus{}
+	store := &offsetOutboxStore{fakeOutboxStore: fakeOutboxStore{}, offsets: map[string]int64{}}
+	bus := NewDurableBus(inner, store, WithReplayDisabled())
+	durable, ok := bus.(DurableSubscriber)
+	if !ok {
+		t.Fatalf("expected durable subscriber")
+	}
+	ctx := context.Background()
+
+	anchor, err := durable.Replay(ctx, "lambda:test", []schema.EventType{schema.EventTypeExecReport}, func(*schema.Event) {
+		t.Fatal("unexpected replay before anchoring")
+	})
+	if err != nil {
+		t.Fatalf("anchor replay: %v", err)
+	}
+	if anchor != 0 {
+		t.Fatalf("expected anchor at 0, got %d", anchor)
+	}
+
+	for _, evt := range []*schema.Event{
+		{EventID: "exec-1", Type: schema.EventTypeExecReport, Provider: "binance", Symbol: "BTC-USDT"},
+		{EventID: "trade-1", Type: schema.EventTypeTrade, Provider: "binance", Symbol: "BTC-USDT"},
+		{EventID: "exec-2", Type: schema.EventTypeExecReport, Provider: "binance", Symbol: "BTC-USDT"},
+	} {
+		if err := bus.Publish(ctx, evt); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	if inner.published[0].OutboxID != 1 {
+		t.Fatalf("expected outbox id stamped on published event, got %d", inner.published[0].OutboxID)
+	}
+	if err := durable.CommitOffset(ctx, "lambda:test", 1); err != nil {
+		t.Fatalf("commit offset: %v", err)
+	}
+
+	var replayed []*schema.Event
+	high, err := durable.Replay(ctx, "lambda:test", []schema.EventType{schema.EventTypeExecReport}, func(evt *schema.Event) {
+		replayed = append(replayed, evt)
+	})
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(replayed) != 1 || replayed[0].EventID != "exec-2" || replayed[0].OutboxID != 3 {
+		t.Fatalf("unexpected replayed events: %+v", replayed)
+	}
+	if high != 3 {
+		t.Fatalf("expected replay high watermark 3, got %d", high)
+	}
+}
+
+func TestDurableBus_ReplayUnsupportedStore(t *testing.T) {
+	bus := NewDurableBus(&stubBus{}, &fakeOutboxStore{}, WithReplayDisabled())
+	durable, ok := bus.(DurableSubscriber)
+	if !ok {
+		t.Fatalf("expected durable subscriber")
+	}
+	if _, err := durable.Replay(context.Background(), "lambda:test", nil, nil); !errors.Is(err, ErrDurableSubscriptionsUnsupported) {
+		t.Fatalf("expected unsupported error, got %v", err)
+	}
+}
+
+type offsetOutboxStore struct {
+	fakeOutboxStore
+	records []outboxstore.EventRecord
+	offsets map[string]int64
+}
+
+func (s *offsetOutboxStore) Enqueue(ctx context.Context, evt outboxstore.Event) (outboxstore.EventRecord, error) {
+	record, err := s.fakeOutboxStore.Enqueue(ctx, evt)
+	if err == nil {
+		s.records = append(s.records, record)
+	}
+	return record, err
+}
+
+func (s *offsetOutboxStore) LoadOffset(_ context.Context, subscriber string) (int64, bool, error) {
+	offset, ok := s.offsets[subscriber]
+	return offset, ok, nil
+}
+
+func (s *offsetOutboxStore) CommitOffset(_ context.Context, subscriber string, offset int64) error {
+	if current, ok := s.offsets[subscriber]; !ok || offset > current {
+		s.offsets[subscriber] = offset
+	}
+	return nil
+}
+
+func (s *offsetOutboxStore) DeleteOffset(_ context.Context, subscriber string) error {
+	delete(s.offsets, subscriber)
+	return nil
+}
+
+func (s *offsetOutboxStore) ListAfter(_ context.Context, afterID int64, eventTypes []string, limit int) ([]outboxstore.EventRecord, error) {
+	var out []outboxstore.EventRecord
+	for _, record := range s.records {
+		if record.ID <= afterID {
+			continue
+		}
+		for _, typ := range eventTypes {
+			if record.EventType == typ {
+				out = append(out, record)
+				break
+			}
+		}
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (s *offsetOutboxStore) LatestID(context.Context) (int64, error) {
+	return s.nextID, nil
+}
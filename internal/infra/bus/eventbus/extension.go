@@ -7,6 +7,7 @@ import (
 
 	"github.com/coachpo/meltica/internal/domain/errs"
 	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
 )
 
 func enforceExtensionPayloadCap(evt *schema.Event, capBytes int) error {
@@ -39,10 +40,10 @@ func extensionPayloadSize(payload any) (int, error) {
 	case json.RawMessage:
 		return len(v), nil
 	default:
-		data, err := json.Marshal(v)
+		size, err := pool.JSONSize(v)
 		if err != nil {
 			return 0, fmt.Errorf("marshal extension payload: %w", err)
 		}
-		return len(data), nil
+		return size, nil
 	}
 }
@@ -32,6 +32,8 @@ type MemoryBus struct {
 	shutdownOnce sync.Once
 	nextID       uint64
 	workers      int
+	lanes        *laneScheduler
+	watermarks   *watermarks
 
 	eventsPublishedCounter metric.Int64Counter
 	subscriberGauge        metric.Int64UpDownCounter
@@ -80,11 +82,22 @@ func NewMemoryBus(cfg MemoryConfig) *MemoryBus {
 		metric.WithDescription("Number of deliveries dropped due to subscriber backpressure"),
 		metric.WithUnit("{event}"))
 
+	if cfg.Watermark.Enabled {
+		bus.watermarks = newWatermarks(cfg.Watermark, meter)
+	}
+
+	if cfg.Priority.Enabled {
+		bus.lanes = newLaneScheduler(cfg.Priority, meter)
+		bus.lanes.start(ctx, cfg.FanoutWorkers, func(evt *schema.Event) {
+			_, _ = bus.fanout(ctx, evt)
+		})
+	}
+
 	return bus
 }
 
-// Publish fan-outs the event to all subscribers of its type.
-// Route-first: counts subscribers before any pool work, short-circuits when n==0.
+// Publish fan-outs the event to all subscribers of its type. With priority lanes enabled the
+// event is queued in its lane and delivered asynchronously by the fan-out workers.
 func (b *MemoryBus) Publish(ctx context.Context, evt *schema.Event) error {
 	if ctx == nil {
 		ctx = context.Background()
@@ -123,6 +136,39 @@ func (b *MemoryBus) Publish(ctx context.Context, evt *schema.Event) error {
 		return err
 	}
 
+	if b.watermarks != nil && !b.watermarks.observe(ctx, evt) {
+		result = "late_dropped"
+		b.recycle(evt)
+		return nil
+	}
+
+	if b.lanes != nil {
+		if err := b.lanes.enqueue(ctx, b.ctx, evt); err != nil {
+			result = "lane_unavailable"
+			b.recycle(evt)
+			return err
+		}
+		result = "queued"
+		return nil
+	}
+
+	var err error
+	result, err = b.fanout(ctx, evt)
+	return err
+}
+
+// Watermarks reports the event-time watermark of every provider that has published events with
+// an event time. It returns nil when watermarks are disabled.
+func (b *MemoryBus) Watermarks() []ProviderWatermark {
+	if b.watermarks == nil {
+		return nil
+	}
+	return b.watermarks.snapshot()
+}
+
+// fanout delivers the event to every current subscriber of its type and returns the publish result label.
+// Route-first: counts subscribers before any pool work, short-circuits when n==0.
+func (b *MemoryBus) fanout(ctx context.Context, evt *schema.Event) (string, error) {
 	// ROUTE FIRST: snapshot subscribers before any pool operations.
 	b.mu.RLock()
 	subMap := b.subscribers[evt.Type]
@@ -143,9 +189,8 @@ func (b *MemoryBus) Publish(ctx context.Context, evt *schema.Event) error {
 
 	// SHORT-CIRCUIT: no subscribers means no pool work, no delivery.
 	if n == 0 {
-		result = "no_subscribers"
 		b.recycle(evt)
-		return nil
+		return "no_subscribers", nil
 	}
 
 	// ALLOCATE-IF-SOME: pre-borrow exactly n clones for n subscribers.
@@ -161,8 +206,7 @@ func (b *MemoryBus) Publish(ctx context.Context, evt *schema.Event) error {
 				attribute.String("provider", evt.Provider),
 				attribute.String("symbol", evt.Symbol)))
 		}
-		result = "clone_batch_failed"
-		return err
+		return "clone_batch_failed", err
 	}
 
 	// DELIVER: dispatch with pre-borrowed clones.
@@ -179,8 +223,7 @@ func (b *MemoryBus) Publish(ctx context.Context, evt *schema.Event) error {
 				attribute.String("provider", evt.Provider),
 				attribute.String("symbol", evt.Symbol)))
 		}
-		result = "dispatch_failed"
-		return err
+		return "dispatch_failed", err
 	}
 
 	if b.eventsPublishedCounter != nil {
@@ -193,7 +236,7 @@ func (b *MemoryBus) Publish(ctx context.Context, evt *schema.Event) error {
 
 	// Source event is no longer needed; recycle it.
 	b.recycle(evt)
-	return nil
+	return "success", nil
 }
 
 // Subscribe registers for events of the given type and returns a subscription ID and channel.
@@ -259,6 +302,9 @@ func (b *MemoryBus) Unsubscribe(id SubscriptionID) {
 func (b *MemoryBus) Close() {
 	b.shutdownOnce.Do(func() {
 		b.cancel()
+		if b.lanes != nil {
+			b.lanes.stop(b.recycle)
+		}
 		b.mu.Lock()
 		for typ, subs := range b.subscribers {
 			for id, sub := range subs {
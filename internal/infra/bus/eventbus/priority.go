@@ -0,0 +1,259 @@
+package eventbus
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/domain/errs"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// Priority classifies events into fan-out lanes.
+type Priority int
+
+const (
+	// PriorityHigh carries execution reports and risk-control events.
+	PriorityHigh Priority = iota
+	// PriorityNormal carries every event type not assigned elsewhere.
+	PriorityNormal
+	// PriorityLow carries high-volume market data.
+	PriorityLow
+
+	priorityLaneCount = 3
+)
+
+const (
+	defaultHighLaneWeight   = 8
+	defaultNormalLaneWeight = 4
+	defaultLowLaneWeight    = 1
+)
+
+// String returns the lane name used in metrics and configuration.
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	default:
+		return "normal"
+	}
+}
+
+// LaneConfig sizes a single priority lane.
+type LaneConfig struct {
+	// Weight is the number of events drained from the lane per scheduling round.
+	Weight int
+	// QueueSize bounds the events waiting in the lane; publishers block when it is full.
+	QueueSize int
+}
+
+// PriorityConfig enables weighted priority lanes in front of the fan-out workers. When enabled,
+// Publish enqueues events into the lane selected by Classes and returns; the fan-out workers
+// drain lanes in weighted round-robin so a burst of market data cannot delay execution reports.
+type PriorityConfig struct {
+	Enabled bool
+	High    LaneConfig
+	Normal  LaneConfig
+	Low     LaneConfig
+	// Classes maps event types to lanes. Unlisted types use PriorityNormal; nil selects
+	// DefaultPriorityClasses.
+	Classes map[schema.EventType]Priority
+}
+
+// DefaultPriorityClasses routes order flow and risk events to the high lane and market data to the low lane.
+func DefaultPriorityClasses() map[schema.EventType]Priority {
+	return map[schema.EventType]Priority{
+		schema.EventTypeExecReport:    PriorityHigh,
+		schema.EventTypeRiskControl:   PriorityHigh,
+		schema.EventTypeBalanceUpdate: PriorityHigh,
+		schema.EventTypeTicker:        PriorityLow,
+		schema.EventTypeTrade:         PriorityLow,
+		schema.EventTypeBookSnapshot:  PriorityLow,
+	}
+}
+
+func (c PriorityConfig) normalize(bufferSize int) PriorityConfig {
+	c.High = c.High.normalize(defaultHighLaneWeight, bufferSize)
+	c.Normal = c.Normal.normalize(defaultNormalLaneWeight, bufferSize)
+	c.Low = c.Low.normalize(defaultLowLaneWeight, bufferSize)
+	if c.Classes == nil {
+		c.Classes = DefaultPriorityClasses()
+	}
+	return c
+}
+
+func (c LaneConfig) normalize(weight, queueSize int) LaneConfig {
+	if c.Weight <= 0 {
+		c.Weight = weight
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = queueSize
+	}
+	return c
+}
+
+// classify returns the lane for the event type.
+func (c PriorityConfig) classify(typ schema.EventType) Priority {
+	if p, ok := c.Classes[typ]; ok && p >= PriorityHigh && p <= PriorityLow {
+		return p
+	}
+	return PriorityNormal
+}
+
+type laneItem struct {
+	evt      *schema.Event
+	enqueued time.Time
+}
+
+type priorityLane struct {
+	priority Priority
+	weight   int
+	queue    chan laneItem
+}
+
+// laneScheduler owns the priority lanes and the fan-out workers that drain them.
+type laneScheduler struct {
+	cfg     PriorityConfig
+	lanes   [priorityLaneCount]*priorityLane
+	workers sync.WaitGroup
+
+	queueLatency metric.Float64Histogram
+	queueDepth   metric.Int64ObservableGauge
+}
+
+func newLaneScheduler(cfg PriorityConfig, meter metric.Meter) *laneScheduler {
+	s := &laneScheduler{
+		cfg:          cfg,
+		lanes:        [priorityLaneCount]*priorityLane{},
+		workers:      sync.WaitGroup{},
+		queueLatency: nil,
+		queueDepth:   nil,
+	}
+	for i, lane := range []LaneConfig{cfg.High, cfg.Normal, cfg.Low} {
+		s.lanes[i] = &priorityLane{
+			priority: Priority(i),
+			weight:   lane.Weight,
+			queue:    make(chan laneItem, lane.QueueSize),
+		}
+	}
+	s.queueLatency, _ = meter.Float64Histogram("eventbus.lane.queue_latency",
+		metric.WithDescription("Time events wait in a priority lane before fan-out"),
+		metric.WithUnit("ms"))
+	s.queueDepth, _ = meter.Int64ObservableGauge("eventbus.lane.depth",
+		metric.WithDescription("Events waiting in each priority lane"),
+		metric.WithUnit("{event}"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			for _, lane := range s.lanes {
+				observer.Observe(int64(len(lane.queue)), metric.WithAttributes(
+					attribute.String("environment", telemetry.Environment()),
+					attribute.String("lane", lane.priority.String())))
+			}
+			return nil
+		}))
+	return s
+}
+
+// enqueue places the event in its lane, blocking while the lane is full.
+func (s *laneScheduler) enqueue(ctx, busCtx context.Context, evt *schema.Event) error {
+	lane := s.lanes[s.cfg.classify(evt.Type)]
+	item := laneItem{evt: evt, enqueued: time.Now()}
+	select {
+	case lane.queue <- item:
+		return nil
+	default:
+	}
+	select {
+	case lane.queue <- item:
+		return nil
+	case <-busCtx.Done():
+		return errs.New("eventbus/publish", errs.CodeUnavailable, errs.WithMessage("bus closed"))
+	case <-ctx.Done():
+		return errs.New("eventbus/publish", errs.CodeUnavailable, errs.WithMessage("priority lane "+lane.priority.String()+" full"))
+	}
+}
+
+// start launches count fan-out workers that hand each dequeued event to fanout.
+func (s *laneScheduler) start(ctx context.Context, count int, fanout func(*schema.Event)) {
+	for i := 0; i < count; i++ {
+		s.workers.Add(1)
+		go func() {
+			defer s.workers.Done()
+			s.run(ctx, fanout)
+		}()
+	}
+}
+
+// run drains up to weight events from each lane per round, highest priority first, and blocks
+// on all lanes when a round finds them empty.
+func (s *laneScheduler) run(ctx context.Context, fanout func(*schema.Event)) {
+	for {
+		served := false
+		for _, lane := range s.lanes {
+			for n := 0; n < lane.weight; n++ {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-lane.queue:
+					s.deliver(lane, item, fanout)
+					served = true
+					continue
+				default:
+				}
+				break
+			}
+		}
+		if served {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case item := <-s.lanes[PriorityHigh].queue:
+			s.deliver(s.lanes[PriorityHigh], item, fanout)
+		case item := <-s.lanes[PriorityNormal].queue:
+			s.deliver(s.lanes[PriorityNormal], item, fanout)
+		case item := <-s.lanes[PriorityLow].queue:
+			s.deliver(s.lanes[PriorityLow], item, fanout)
+		}
+	}
+}
+
+func (s *laneScheduler) deliver(lane *priorityLane, item laneItem, fanout func(*schema.Event)) {
+	if s.queueLatency != nil {
+		s.queueLatency.Record(context.Background(), float64(time.Since(item.enqueued).Microseconds())/1000, metric.WithAttributes(
+			attribute.String("environment", telemetry.Environment()),
+			attribute.String("lane", lane.priority.String()),
+			attribute.String("event_type", string(item.evt.Type))))
+	}
+	fanout(item.evt)
+}
+
+// stop waits for the workers to exit and returns events still queued to recycle.
+func (s *laneScheduler) stop(recycle func(*schema.Event)) {
+	s.workers.Wait()
+	dropped := 0
+	for _, lane := range s.lanes {
+		for {
+			select {
+			case item := <-lane.queue:
+				recycle(item.evt)
+				dropped++
+				continue
+			default:
+			}
+			break
+		}
+	}
+	if dropped > 0 {
+		log.Printf("eventbus: discarded %d queued events on close", dropped)
+	}
+}
@@ -0,0 +1,117 @@
+package eventbus
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+func TestPriorityConfigClassifyDefaults(t *testing.T) {
+	cfg := PriorityConfig{Enabled: true}.normalize(16)
+	cases := map[schema.EventType]Priority{
+		schema.EventTypeExecReport:       PriorityHigh,
+		schema.EventTypeRiskControl:      PriorityHigh,
+		schema.EventTypeTicker:           PriorityLow,
+		schema.EventTypeInstrumentUpdate: PriorityNormal,
+		"Custom":                         PriorityNormal,
+	}
+	for typ, want := range cases {
+		if got := cfg.classify(typ); got != want {
+			t.Fatalf("classify(%s) = %s, want %s", typ, got, want)
+		}
+	}
+	if cfg.High.Weight != defaultHighLaneWeight || cfg.Low.QueueSize != 16 {
+		t.Fatalf("unexpected lane defaults: %+v", cfg)
+	}
+}
+
+func TestLaneSchedulerWeightedDrain(t *testing.T) {
+	cfg := PriorityConfig{
+		Enabled: true,
+		High:    LaneConfig{Weight: 2, QueueSize: 8},
+		Low:     LaneConfig{Weight: 1, QueueSize: 8},
+	}.normalize(8)
+	s := newLaneScheduler(cfg, otel.Meter("eventbus-test"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := 0; i < 4; i++ {
+		for _, typ := range []schema.EventType{schema.EventTypeTicker, schema.EventTypeExecReport} {
+			if err := s.enqueue(ctx, ctx, &schema.Event{Type: typ}); err != nil {
+				t.Fatalf("enqueue: %v", err)
+			}
+		}
+	}
+
+	delivered := make(chan schema.EventType, 8)
+	s.start(ctx, 1, func(evt *schema.Event) {
+		delivered <- evt.Type
+	})
+
+	var order []string
+	for i := 0; i < 8; i++ {
+		select {
+		case typ := <-delivered:
+			if typ == schema.EventTypeExecReport {
+				order = append(order, "H")
+			} else {
+				order = append(order, "L")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out after %v", order)
+		}
+	}
+	if got := strings.Join(order, ""); got != "HHLHHLLL" {
+		t.Fatalf("unexpected drain order %s", got)
+	}
+	cancel()
+	s.stop(func(*schema.Event) {})
+}
+
+func TestMemoryBusPriorityLanesDeliver(t *testing.T) {
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("Event", 16, 0, func() interface{} { return new(schema.Event) }); err != nil {
+		t.Fatalf("failed to register pool: %v", err)
+	}
+	defer poolMgr.Shutdown(context.Background())
+	bus := NewMemoryBus(MemoryConfig{
+		BufferSize:    4,
+		FanoutWorkers: 2,
+		Pools:         poolMgr,
+		Priority:      PriorityConfig{Enabled: true},
+	})
+	defer bus.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, events, err := bus.Subscribe(ctx, schema.EventTypeExecReport)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	evt, err := poolMgr.BorrowEventInst(ctx)
+	if err != nil {
+		t.Fatalf("BorrowEventInst() error = %v", err)
+	}
+	evt.Type = schema.EventTypeExecReport
+	evt.EventID = "exec-1"
+	if err := bus.Publish(ctx, evt); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	select {
+	case received := <-events:
+		if received.EventID != "exec-1" {
+			t.Fatalf("expected exec-1, got %s", received.EventID)
+		}
+		poolMgr.ReturnEventInst(received)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for event")
+	}
+}
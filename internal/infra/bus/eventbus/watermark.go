@@ -0,0 +1,154 @@
+package eventbus
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// LatePolicy selects what the bus does with events behind their provider watermark.
+type LatePolicy string
+
+const (
+	// LatePolicyDeliver delivers late events flagged with Late.
+	LatePolicyDeliver LatePolicy = "deliver"
+	// LatePolicyDrop discards late events before fan-out.
+	LatePolicyDrop LatePolicy = "drop"
+)
+
+// WatermarkConfig enables per-provider event-time watermarks. The watermark of a provider trails
+// the newest event time it has published by MaxLateness; events with an older event time are late.
+type WatermarkConfig struct {
+	Enabled     bool
+	MaxLateness time.Duration
+	LatePolicy  LatePolicy
+}
+
+func (c WatermarkConfig) normalize() WatermarkConfig {
+	if c.MaxLateness < 0 {
+		c.MaxLateness = 0
+	}
+	if c.LatePolicy != LatePolicyDrop {
+		c.LatePolicy = LatePolicyDeliver
+	}
+	return c
+}
+
+// ProviderWatermark reports the event-time progress of one provider.
+type ProviderWatermark struct {
+	Provider string `json:"provider"`
+	// MaxEventTime is the newest event time published for the provider.
+	MaxEventTime time.Time `json:"maxEventTime"`
+	Watermark    time.Time `json:"watermark"`
+	Late         uint64    `json:"late"`
+	Dropped      uint64    `json:"dropped"`
+}
+
+// watermarks tracks event-time progress per provider and classifies late events.
+type watermarks struct {
+	cfg WatermarkConfig
+
+	mu        sync.Mutex
+	providers map[string]*ProviderWatermark
+
+	latenessHistogram metric.Float64Histogram
+	delayHistogram    metric.Float64Histogram
+	lateCounter       metric.Int64Counter
+}
+
+func newWatermarks(cfg WatermarkConfig, meter metric.Meter) *watermarks {
+	w := &watermarks{
+		cfg:               cfg.normalize(),
+		mu:                sync.Mutex{},
+		providers:         make(map[string]*ProviderWatermark),
+		latenessHistogram: nil,
+		delayHistogram:    nil,
+		lateCounter:       nil,
+	}
+	w.latenessHistogram, _ = meter.Float64Histogram("eventbus.event.lateness",
+		metric.WithDescription("How far late events trail their provider watermark"),
+		metric.WithUnit("ms"))
+	w.delayHistogram, _ = meter.Float64Histogram("eventbus.event.delay",
+		metric.WithDescription("Processing time minus event time of published events"),
+		metric.WithUnit("ms"))
+	w.lateCounter, _ = meter.Int64Counter("eventbus.events.late",
+		metric.WithDescription("Number of events published behind their provider watermark"),
+		metric.WithUnit("{event}"))
+	return w
+}
+
+// observe advances the provider watermark with the event time of evt, stamps the watermark on the
+// event and reports whether the event should be delivered. Events without an event time pass
+// through unstamped.
+func (w *watermarks) observe(ctx context.Context, evt *schema.Event) bool {
+	if evt.EventTS.IsZero() {
+		return true
+	}
+	w.mu.Lock()
+	state, ok := w.providers[evt.Provider]
+	if !ok {
+		state = &ProviderWatermark{Provider: evt.Provider, MaxEventTime: time.Time{}, Watermark: time.Time{}, Late: 0, Dropped: 0}
+		w.providers[evt.Provider] = state
+	}
+	late := !state.Watermark.IsZero() && evt.EventTS.Before(state.Watermark)
+	lateness := state.Watermark.Sub(evt.EventTS)
+	// Event times ahead of the receive time, such as the close time of an open kline, advance the
+	// watermark no further than the receive time.
+	advance := evt.EventTS
+	if !evt.IngestTS.IsZero() && advance.After(evt.IngestTS) {
+		advance = evt.IngestTS
+	}
+	if advance.After(state.MaxEventTime) {
+		state.MaxEventTime = advance
+		state.Watermark = advance.Add(-w.cfg.MaxLateness)
+	}
+	drop := late && w.cfg.LatePolicy == LatePolicyDrop
+	if late {
+		state.Late++
+	}
+	if drop {
+		state.Dropped++
+	}
+	evt.Watermark = state.Watermark
+	evt.Late = late
+	w.mu.Unlock()
+
+	attrs := metric.WithAttributes(
+		attribute.String("environment", telemetry.Environment()),
+		attribute.String("provider", evt.Provider),
+		attribute.String("event_type", string(evt.Type)))
+	if w.delayHistogram != nil && !evt.IngestTS.IsZero() {
+		w.delayHistogram.Record(ctx, float64(evt.IngestTS.Sub(evt.EventTS))/float64(time.Millisecond), attrs)
+	}
+	if late {
+		if w.latenessHistogram != nil {
+			w.latenessHistogram.Record(ctx, float64(lateness)/float64(time.Millisecond), attrs)
+		}
+		if w.lateCounter != nil {
+			w.lateCounter.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("environment", telemetry.Environment()),
+				attribute.String("provider", evt.Provider),
+				attribute.String("event_type", string(evt.Type)),
+				attribute.String("policy", string(w.cfg.LatePolicy))))
+		}
+	}
+	return !drop
+}
+
+func (w *watermarks) snapshot() []ProviderWatermark {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make([]ProviderWatermark, 0, len(w.providers))
+	for _, state := range w.providers {
+		out = append(out, *state)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out
+}
@@ -0,0 +1,104 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/pool"
+)
+
+func TestWatermarksClassifyLateEvents(t *testing.T) {
+	w := newWatermarks(WatermarkConfig{Enabled: true, MaxLateness: time.Second}, otel.Meter("test"))
+	ctx := context.Background()
+	base := time.Unix(1_700_000_000, 0)
+	observe := func(eventTS time.Time) *schema.Event {
+		evt := &schema.Event{Provider: "binance", Type: schema.EventTypeTrade, EventTS: eventTS, IngestTS: base.Add(10 * time.Second)}
+		if !w.observe(ctx, evt) {
+			t.Fatal("expected the deliver policy to keep every event")
+		}
+		return evt
+	}
+
+	if evt := observe(base.Add(5 * time.Second)); evt.Late || !evt.Watermark.Equal(base.Add(4*time.Second)) {
+		t.Fatalf("unexpected first event %+v", evt)
+	}
+	if evt := observe(base.Add(4500 * time.Millisecond)); evt.Late {
+		t.Fatal("expected an event within maxLateness to be on time")
+	}
+	if evt := observe(base.Add(3 * time.Second)); !evt.Late || !evt.Watermark.Equal(base.Add(4*time.Second)) {
+		t.Fatalf("expected a late event behind the watermark, got %+v", evt)
+	}
+	if evt := w.observe(ctx, &schema.Event{Provider: "okx"}); !evt {
+		t.Fatal("expected events without an event time to pass through")
+	}
+
+	// An event time past the receive time advances the watermark only to the receive time.
+	observe(base.Add(time.Minute))
+	snapshot := w.snapshot()
+	if len(snapshot) != 1 || snapshot[0].Provider != "binance" || snapshot[0].Late != 1 || snapshot[0].Dropped != 0 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	if !snapshot[0].MaxEventTime.Equal(base.Add(10 * time.Second)) {
+		t.Fatalf("expected the receive time to cap the watermark, got %s", snapshot[0].MaxEventTime)
+	}
+}
+
+func TestMemoryBusDropsLateEvents(t *testing.T) {
+	poolMgr := pool.NewPoolManager()
+	if err := poolMgr.RegisterPool("Event", 16, 0, func() interface{} { return new(schema.Event) }); err != nil {
+		t.Fatalf("failed to register pool: %v", err)
+	}
+	defer poolMgr.Shutdown(context.Background())
+	bus := NewMemoryBus(MemoryConfig{
+		BufferSize:    4,
+		FanoutWorkers: 1,
+		Pools:         poolMgr,
+		Watermark:     WatermarkConfig{Enabled: true, MaxLateness: time.Second, LatePolicy: LatePolicyDrop},
+	})
+	defer bus.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, events, err := bus.Subscribe(ctx, schema.EventTypeTrade)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	now := time.Now()
+	publish := func(id string, eventTS time.Time) {
+		evt, err := poolMgr.BorrowEventInst(ctx)
+		if err != nil {
+			t.Fatalf("BorrowEventInst() error = %v", err)
+		}
+		evt.Type = schema.EventTypeTrade
+		evt.Provider = "binance"
+		evt.EventID = id
+		evt.EventTS = eventTS
+		evt.IngestTS = now
+		if err := bus.Publish(ctx, evt); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	publish("fresh", now)
+	publish("stale", now.Add(-5*time.Second))
+	publish("next", now.Add(-500*time.Millisecond))
+
+	for _, want := range []string{"fresh", "next"} {
+		select {
+		case received := <-events:
+			if received.EventID != want || received.Late {
+				t.Fatalf("expected on-time %s, got %s (late=%v)", want, received.EventID, received.Late)
+			}
+			poolMgr.ReturnEventInst(received)
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for %s", want)
+		}
+	}
+	if marks := bus.Watermarks(); len(marks) != 1 || marks[0].Dropped != 1 {
+		t.Fatalf("expected one dropped event, got %+v", marks)
+	}
+}
@@ -8,20 +8,119 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"gopkg.in/yaml.v3"
 
+	"github.com/coachpo/meltica/internal/domain/outboxstore"
+	"github.com/coachpo/meltica/internal/domain/schema"
 	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
 )
 
 // EventbusConfig sets in-memory event bus sizing characteristics.
 type EventbusConfig struct {
-	BufferSize               int                 `yaml:"bufferSize"`
-	FanoutWorkers            FanoutWorkerSetting `yaml:"fanoutWorkers"`
-	ExtensionPayloadCapBytes int                 `yaml:"extensionPayloadCapBytes"`
+	BufferSize               int                     `yaml:"bufferSize"`
+	FanoutWorkers            FanoutWorkerSetting     `yaml:"fanoutWorkers"`
+	ExtensionPayloadCapBytes int                     `yaml:"extensionPayloadCapBytes"`
+	PriorityLanes            EventbusPriorityConfig  `yaml:"priorityLanes"`
+	Watermarks               EventbusWatermarkConfig `yaml:"watermarks"`
+}
+
+// EventbusWatermarkConfig enables per-provider event-time watermarks. Events whose exchange
+// timestamp trails the provider's newest by more than maxLateness are late and, per latePolicy,
+// either delivered flagged as late or dropped.
+type EventbusWatermarkConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	MaxLateness time.Duration `yaml:"maxLateness"`
+	LatePolicy  string        `yaml:"latePolicy"`
+}
+
+// EventbusPriorityConfig enables weighted priority lanes drained by the fan-out workers.
+type EventbusPriorityConfig struct {
+	Enabled bool               `yaml:"enabled"`
+	High    EventbusLaneConfig `yaml:"high"`
+	Normal  EventbusLaneConfig `yaml:"normal"`
+	Low     EventbusLaneConfig `yaml:"low"`
+}
+
+// EventbusLaneConfig sizes one priority lane and lists the event types routed to it. When no lane
+// lists event types the bus defaults apply (execution, risk and balance events high; market data low).
+type EventbusLaneConfig struct {
+	Weight     int      `yaml:"weight"`
+	QueueSize  int      `yaml:"queueSize"`
+	EventTypes []string `yaml:"eventTypes"`
+}
+
+// PriorityConfig converts the lane settings into the event bus representation.
+func (c EventbusConfig) PriorityConfig() eventbus.PriorityConfig {
+	lanes := c.PriorityLanes
+	var classes map[schema.EventType]eventbus.Priority
+	for priority, lane := range map[eventbus.Priority]EventbusLaneConfig{
+		eventbus.PriorityHigh:   lanes.High,
+		eventbus.PriorityNormal: lanes.Normal,
+		eventbus.PriorityLow:    lanes.Low,
+	} {
+		for _, typ := range lane.EventTypes {
+			if classes == nil {
+				classes = make(map[schema.EventType]eventbus.Priority)
+			}
+			classes[schema.EventType(typ)] = priority
+		}
+	}
+	return eventbus.PriorityConfig{
+		Enabled: lanes.Enabled,
+		High:    eventbus.LaneConfig{Weight: lanes.High.Weight, QueueSize: lanes.High.QueueSize},
+		Normal:  eventbus.LaneConfig{Weight: lanes.Normal.Weight, QueueSize: lanes.Normal.QueueSize},
+		Low:     eventbus.LaneConfig{Weight: lanes.Low.Weight, QueueSize: lanes.Low.QueueSize},
+		Classes: classes,
+	}
+}
+
+// WatermarkConfig converts the watermark settings into the event bus representation.
+func (c EventbusConfig) WatermarkConfig() eventbus.WatermarkConfig {
+	return eventbus.WatermarkConfig{
+		Enabled:     c.Watermarks.Enabled,
+		MaxLateness: c.Watermarks.MaxLateness,
+		LatePolicy:  eventbus.LatePolicy(strings.ToLower(strings.TrimSpace(c.Watermarks.LatePolicy))),
+	}
+}
+
+func (c EventbusWatermarkConfig) validate() error {
+	if c.MaxLateness < 0 {
+		return fmt.Errorf("eventbus.watermarks.maxLateness must be >=0")
+	}
+	switch eventbus.LatePolicy(strings.ToLower(strings.TrimSpace(c.LatePolicy))) {
+	case "", eventbus.LatePolicyDeliver, eventbus.LatePolicyDrop:
+		return nil
+	default:
+		return fmt.Errorf("eventbus.watermarks.latePolicy must be deliver or drop")
+	}
+}
+
+func (c EventbusPriorityConfig) validate() error {
+	seen := make(map[string]string)
+	for name, lane := range map[string]EventbusLaneConfig{"high": c.High, "normal": c.Normal, "low": c.Low} {
+		if lane.Weight < 0 {
+			return fmt.Errorf("eventbus.priorityLanes.%s.weight must be >=0", name)
+		}
+		if lane.QueueSize < 0 {
+			return fmt.Errorf("eventbus.priorityLanes.%s.queueSize must be >=0", name)
+		}
+		for _, typ := range lane.EventTypes {
+			if typ == "" {
+				return fmt.Errorf("eventbus.priorityLanes.%s.eventTypes must not contain empty entries", name)
+			}
+			if other, ok := seen[typ]; ok {
+				return fmt.Errorf("eventbus.priorityLanes: event type %s assigned to both %s and %s", typ, other, name)
+			}
+			seen[typ] = name
+		}
+	}
+	return nil
 }
 
 type fanoutWorkerKind int
@@ -100,10 +199,49 @@ func (c EventbusConfig) FanoutWorkerCount() int {
 	return c.FanoutWorkers.resolve()
 }
 
-// ObjectPoolConfig describes sizing for a single named pool.
+// PoolExhaustionPolicy selects how borrowers are served once every pooled object is leased.
+type PoolExhaustionPolicy string
+
+const (
+	// PoolExhaustionWait blocks the borrower until an object is returned, bounded by WaitTimeout when set.
+	PoolExhaustionWait PoolExhaustionPolicy = "wait"
+	// PoolExhaustionSpill allocates a short-lived object on the heap that is discarded on return.
+	PoolExhaustionSpill PoolExhaustionPolicy = "spill"
+	// PoolExhaustionReject fails the borrow immediately.
+	PoolExhaustionReject PoolExhaustionPolicy = "reject"
+)
+
+// ObjectPoolConfig describes sizing and exhaustion handling for a single named pool.
 type ObjectPoolConfig struct {
-	Size          int `yaml:"size"`
-	WaitQueueSize int `yaml:"waitQueueSize"`
+	Size          int                  `yaml:"size"`
+	WaitQueueSize int                  `yaml:"waitQueueSize"`
+	Exhaustion    PoolExhaustionPolicy `yaml:"exhaustion"`
+	WaitTimeout   time.Duration        `yaml:"waitTimeout"`
+}
+
+func (c *ObjectPoolConfig) applyDefaults() {
+	c.Exhaustion = PoolExhaustionPolicy(strings.ToLower(strings.TrimSpace(string(c.Exhaustion))))
+	if c.Exhaustion == "" {
+		c.Exhaustion = PoolExhaustionWait
+	}
+}
+
+func (c ObjectPoolConfig) validate(name string) error {
+	if c.Size <= 0 {
+		return fmt.Errorf("pools.%s.size must be >0", name)
+	}
+	if c.WaitQueueSize < 0 {
+		return fmt.Errorf("pools.%s.waitQueueSize must be >=0", name)
+	}
+	switch c.Exhaustion {
+	case PoolExhaustionWait, PoolExhaustionSpill, PoolExhaustionReject:
+	default:
+		return fmt.Errorf("pools.%s.exhaustion must be one of wait, spill, reject", name)
+	}
+	if c.WaitTimeout < 0 {
+		return fmt.Errorf("pools.%s.waitTimeout must be >=0", name)
+	}
+	return nil
 }
 
 // PoolConfig controls pooled object capacities.
@@ -123,6 +261,35 @@ func (c ObjectPoolConfig) QueueSize() int {
 // APIServerConfig configures the gateway's HTTP control surface.
 type APIServerConfig struct {
 	Addr string `yaml:"addr"`
+	// UI serves the embedded admin console at /ui when enabled.
+	UI APIServerUIConfig `yaml:"ui"`
+	// SafeMode keeps the control API up with read-only diagnostics when startup cannot migrate the
+	// database or load persisted providers and strategies, instead of exiting.
+	SafeMode APIServerSafeModeConfig `yaml:"safeMode"`
+	// Pprof serves net/http/pprof under /debug/pprof/ when enabled.
+	Pprof APIServerPprofConfig `yaml:"pprof"`
+	// OrderEntry serves the external order entry websocket at /order-entry when enabled.
+	OrderEntry OrderEntryConfig `yaml:"orderEntry"`
+	// ReportingCurrency values the aggregated balances at /balances; empty uses the risk notional
+	// currency.
+	ReportingCurrency string `yaml:"reportingCurrency"`
+}
+
+// APIServerPprofConfig exposes the Go profiler on the control API. Profiles reveal memory
+// contents, so requests must carry Token as a bearer token; enabling requires a token.
+type APIServerPprofConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Token   string `yaml:"token"`
+}
+
+// APIServerSafeModeConfig toggles the safe mode fallback for failed startups.
+type APIServerSafeModeConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// APIServerUIConfig toggles the embedded single-page admin console.
+type APIServerUIConfig struct {
+	Enabled bool `yaml:"enabled"`
 }
 
 // RiskConfig defines risk parameters for a single strategy.
@@ -136,31 +303,339 @@ type CircuitBreakerConfig struct {
 
 // RiskConfig defines risk parameters for a single strategy.
 type RiskConfig struct {
-	MaxPositionSize     string               `yaml:"maxPositionSize"`
-	MaxNotionalValue    string               `yaml:"maxNotionalValue"`
-	NotionalCurrency    string               `yaml:"notionalCurrency"`
-	OrderThrottle       float64              `yaml:"orderThrottle"`
-	OrderBurst          int                  `yaml:"orderBurst"`
-	MaxConcurrentOrders int                  `yaml:"maxConcurrentOrders"`
-	PriceBandPercent    float64              `yaml:"priceBandPercent"`
-	AllowedOrderTypes   []string             `yaml:"allowedOrderTypes"`
-	KillSwitchEnabled   bool                 `yaml:"killSwitchEnabled"`
-	MaxRiskBreaches     int                  `yaml:"maxRiskBreaches"`
-	CircuitBreaker      CircuitBreakerConfig `yaml:"circuitBreaker"`
-}
-
-// TelemetryConfig configures OTLP exporters (metrics only).
+	MaxPositionSize     string   `yaml:"maxPositionSize"`
+	MaxNotionalValue    string   `yaml:"maxNotionalValue"`
+	NotionalCurrency    string   `yaml:"notionalCurrency"`
+	OrderThrottle       float64  `yaml:"orderThrottle"`
+	OrderBurst          int      `yaml:"orderBurst"`
+	MaxConcurrentOrders int      `yaml:"maxConcurrentOrders"`
+	PriceBandPercent    float64  `yaml:"priceBandPercent"`
+	AllowedOrderTypes   []string `yaml:"allowedOrderTypes"`
+	// AllowedTimeInForce restricts orders to the listed time-in-force values; empty allows all.
+	AllowedTimeInForce []string             `yaml:"allowedTimeInForce"`
+	KillSwitchEnabled  bool                 `yaml:"killSwitchEnabled"`
+	MaxRiskBreaches    int                  `yaml:"maxRiskBreaches"`
+	CircuitBreaker     CircuitBreakerConfig `yaml:"circuitBreaker"`
+	FX                 FXConfig             `yaml:"fx"`
+	// SelfTradePrevention is one of allow, reject or cancel-resting.
+	SelfTradePrevention string `yaml:"selfTradePrevention"`
+	// BalanceCheck rejects spot orders locally when cached balances cannot cover them.
+	BalanceCheck bool `yaml:"balanceCheck"`
+	// Concentration caps the share of the gateway-wide portfolio held in one asset or on one venue.
+	Concentration ConcentrationConfig `yaml:"concentration"`
+}
+
+// NormalizeTimeInForce canonicalizes a time-in-force allow list, accepting POST_ONLY for GTX and
+// dropping blanks and duplicates.
+func NormalizeTimeInForce(values []string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(values))
+	for _, raw := range values {
+		tif, err := schema.ParseTimeInForce(raw)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // the message names the rejected value
+		}
+		if tif != "" && !slices.Contains(normalized, string(tif)) {
+			normalized = append(normalized, string(tif))
+		}
+	}
+	return normalized, nil
+}
+
+// ConcentrationConfig limits the share of the gross portfolio notional, in percent, held in a
+// single base asset or on a single venue. Limits apply once the portfolio reaches MinPortfolioNotional.
+type ConcentrationConfig struct {
+	MaxAssetPercent      float64 `yaml:"maxAssetPercent"`
+	MaxVenuePercent      float64 `yaml:"maxVenuePercent"`
+	MinPortfolioNotional string  `yaml:"minPortfolioNotional"`
+}
+
+// FXConfig converts order notionals quoted in other currencies into the notional currency.
+// Rates map a currency code to the value of one unit expressed in the notional currency.
+type FXConfig struct {
+	Rates          map[string]string `yaml:"rates"`
+	UseMarketRates bool              `yaml:"useMarketRates"`
+}
+
+// OrderNormalizationMode selects how order prices and quantities that do not match instrument filters are handled.
+type OrderNormalizationMode string
+
+const (
+	// OrderNormalizationRound rounds price to the tick size and quantity down to the step size.
+	OrderNormalizationRound OrderNormalizationMode = "round"
+	// OrderNormalizationReject rejects orders whose price or quantity is not aligned with the filters.
+	OrderNormalizationReject OrderNormalizationMode = "reject"
+	// OrderNormalizationOff forwards orders to the venue unchanged.
+	OrderNormalizationOff OrderNormalizationMode = "off"
+)
+
+// OrdersConfig controls gateway-side processing of strategy orders before submission.
+type OrdersConfig struct {
+	Normalization OrderNormalizationMode `yaml:"normalization"`
+	// CircuitBreaker stops submitting orders to a provider that keeps failing them.
+	CircuitBreaker OrderCircuitBreakerConfig `yaml:"circuitBreaker"`
+	// Queue prioritises cancels over new orders on each provider.
+	Queue OrderQueueConfig `yaml:"queue"`
+}
+
+// DeadMansSwitchConfig halts trading when an external controller stops heartbeating.
+type DeadMansSwitchConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	Interval         time.Duration `yaml:"interval"`
+	CancelOpenOrders bool          `yaml:"cancelOpenOrders"`
+}
+
+// CalendarConfig controls the scheduler that applies operator-scheduled actions.
+type CalendarConfig struct {
+	// CheckInterval is how often due entries are evaluated.
+	CheckInterval time.Duration `yaml:"checkInterval"`
+	// NotifyBefore is the default lead time of the advance notification; 0 disables it.
+	NotifyBefore time.Duration `yaml:"notifyBefore"`
+	// MissedGrace bounds how late an entry may still be applied, e.g. after a restart.
+	MissedGrace time.Duration `yaml:"missedGrace"`
+}
+
+// TelemetryConfig configures OTLP exporters.
 type TelemetryConfig struct {
-	OTLPEndpoint  string `yaml:"otlpEndpoint"`
-	ServiceName   string `yaml:"serviceName"`
-	OTLPInsecure  bool   `yaml:"otlpInsecure"`
-	EnableMetrics bool   `yaml:"enableMetrics"`
+	OTLPEndpoint  string                 `yaml:"otlpEndpoint"`
+	ServiceName   string                 `yaml:"serviceName"`
+	OTLPInsecure  bool                   `yaml:"otlpInsecure"`
+	EnableMetrics bool                   `yaml:"enableMetrics"`
+	Tracing       TelemetryTracingConfig `yaml:"tracing"`
+	// RuntimeMetrics exports goroutine, heap and GC pause metrics of the gateway process.
+	RuntimeMetrics TelemetryRuntimeMetricsConfig `yaml:"runtimeMetrics"`
+}
+
+const defaultRuntimeMetricsInterval = 15 * time.Second
+
+// TelemetryRuntimeMetricsConfig samples the Go runtime every Interval (default 15s).
+type TelemetryRuntimeMetricsConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+const defaultTraceSampleRatio = 0.01
+
+// TelemetryTracingConfig samples strategy execution traces: each sampled event yields one trace
+// from ingestion through the strategy handler and order submission to the execution reports.
+type TelemetryTracingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// SampleRatio is the share of events traced, between 0 and 1 (default 0.01).
+	SampleRatio float64 `yaml:"sampleRatio"`
 }
 
 // StrategiesConfig defines where JavaScript strategy sources are discovered.
 type StrategiesConfig struct {
-	Directory       string `yaml:"directory"`
-	RequireRegistry bool   `yaml:"requireRegistry"`
+	Directory       string                    `yaml:"directory"`
+	RequireRegistry bool                      `yaml:"requireRegistry"`
+	AutoRefresh     StrategyAutoRefreshConfig `yaml:"autoRefresh"`
+	Scheduling      StrategySchedulingConfig  `yaml:"scheduling"`
+	Capabilities    StrategyCapabilityPolicy  `yaml:"capabilities"`
+	Archive         StrategyArchiveConfig     `yaml:"archive"`
+	Logs            StrategyLogsConfig        `yaml:"logs"`
+	Reports         StrategyReportsConfig     `yaml:"reports"`
+	WarmPool        StrategyWarmPoolConfig    `yaml:"warmPool"`
+	Usage           StrategyUsageConfig       `yaml:"usage"`
+}
+
+// StrategyLogsConfig controls the strategy console logs and diagnostics kept for post-incident
+// analysis. Lines below Level are not recorded; persisted lines older than Retention are purged.
+type StrategyLogsConfig struct {
+	Level     string        `yaml:"level"`
+	Retention time.Duration `yaml:"retention"`
+}
+
+const (
+	defaultStrategyLogLevel     = "info"
+	defaultStrategyLogRetention = 7 * 24 * time.Hour
+)
+
+func (c *StrategyLogsConfig) applyDefaults() {
+	c.Level = strings.ToLower(strings.TrimSpace(c.Level))
+	if c.Level == "" {
+		c.Level = defaultStrategyLogLevel
+	}
+	if c.Retention <= 0 {
+		c.Retention = defaultStrategyLogRetention
+	}
+}
+
+func (c StrategyLogsConfig) validate() error {
+	switch c.Level {
+	case "debug", "info", "warn", "error":
+		return nil
+	default:
+		return fmt.Errorf("level %q must be debug, info, warn or error", c.Level)
+	}
+}
+
+// StrategyReportsConfig schedules the strategy usage and registry report. When enabled a report is
+// generated every Interval and persisted; reports older than Retention are purged.
+type StrategyReportsConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Interval  time.Duration `yaml:"interval"`
+	Retention time.Duration `yaml:"retention"`
+}
+
+const (
+	defaultStrategyReportInterval  = 24 * time.Hour
+	defaultStrategyReportRetention = 90 * 24 * time.Hour
+)
+
+func (c *StrategyReportsConfig) applyDefaults() {
+	if c.Interval == 0 {
+		c.Interval = defaultStrategyReportInterval
+	}
+	if c.Retention == 0 {
+		c.Retention = defaultStrategyReportRetention
+	}
+}
+
+func (c StrategyReportsConfig) validate() error {
+	if c.Interval < time.Minute {
+		return fmt.Errorf("interval must be at least 1m")
+	}
+	if c.Retention < 0 {
+		return fmt.Errorf("retention must not be negative")
+	}
+	return nil
+}
+
+// StrategyWarmPoolConfig keeps JavaScript VMs warmed ahead of instance restarts. When enabled,
+// a refresh prepares a VM per instance moving to a new revision before stopping any of them;
+// at most Capacity warmed VMs stay idle.
+type StrategyWarmPoolConfig struct {
+	Enabled  bool `yaml:"enabled"`
+	Capacity int  `yaml:"capacity"`
+}
+
+const defaultStrategyWarmPoolCapacity = 64
+
+func (c *StrategyWarmPoolConfig) applyDefaults() {
+	if c.Capacity == 0 {
+		c.Capacity = defaultStrategyWarmPoolCapacity
+	}
+}
+
+func (c StrategyWarmPoolConfig) validate() error {
+	if c.Capacity < 0 {
+		return fmt.Errorf("capacity must not be negative")
+	}
+	return nil
+}
+
+// StrategyUsageConfig meters the events, orders and handler time of every instance per UTC day,
+// grouped by instance labels in /reports/usage. Usage is flushed every Interval and days older than
+// Retention are purged.
+type StrategyUsageConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Interval  time.Duration `yaml:"interval"`
+	Retention time.Duration `yaml:"retention"`
+	// GroupBy is the label usage reports group instances by unless a request names another.
+	GroupBy string `yaml:"groupBy"`
+}
+
+const (
+	defaultStrategyUsageInterval  = time.Minute
+	defaultStrategyUsageRetention = 400 * 24 * time.Hour
+	defaultStrategyUsageGroupBy   = "team"
+)
+
+func (c *StrategyUsageConfig) applyDefaults() {
+	if c.Interval == 0 {
+		c.Interval = defaultStrategyUsageInterval
+	}
+	if c.Retention == 0 {
+		c.Retention = defaultStrategyUsageRetention
+	}
+	c.GroupBy = strings.ToLower(strings.TrimSpace(c.GroupBy))
+	if c.GroupBy == "" {
+		c.GroupBy = defaultStrategyUsageGroupBy
+	}
+}
+
+func (c StrategyUsageConfig) validate() error {
+	if c.Interval < time.Second {
+		return fmt.Errorf("interval must be at least 1s")
+	}
+	if c.Retention < 0 {
+		return fmt.Errorf("retention must not be negative")
+	}
+	if !labelKeyPattern.MatchString(c.GroupBy) {
+		return fmt.Errorf("groupBy %q is not a valid label key", c.GroupBy)
+	}
+	return nil
+}
+
+// StrategyArchiveConfig holds the key that signs and verifies strategy registry archives. Gateways
+// exchanging archives must share it; archive export and import are disabled while it is empty.
+type StrategyArchiveConfig struct {
+	SigningKey string `yaml:"signingKey"`
+}
+
+// minArchiveKeyLength keeps archive signing keys out of brute-force range.
+const minArchiveKeyLength = 16
+
+// StrategyCapabilityPolicy lists, per environment, the capabilities (live_trading, http_access,
+// state_storage, cross_provider) a strategy revision may require. Instances of revisions that
+// require anything else are rejected. An environment without an entry allows every capability.
+type StrategyCapabilityPolicy map[Environment][]string
+
+// Allowed returns the capabilities allowed in env and whether the policy restricts env at all.
+func (p StrategyCapabilityPolicy) Allowed(env Environment) ([]string, bool) {
+	allowed, ok := p[env]
+	return allowed, ok
+}
+
+func (p StrategyCapabilityPolicy) normalize() StrategyCapabilityPolicy {
+	if len(p) == 0 {
+		return nil
+	}
+	out := make(StrategyCapabilityPolicy, len(p))
+	for env, capabilities := range p {
+		key := Environment(strings.ToLower(strings.TrimSpace(string(env))))
+		normalized := make([]string, 0, len(capabilities))
+		for _, capability := range capabilities {
+			if trimmed := strings.ToLower(strings.TrimSpace(capability)); trimmed != "" {
+				normalized = append(normalized, trimmed)
+			}
+		}
+		out[key] = append(out[key], normalized...)
+	}
+	return out
+}
+
+func (p StrategyCapabilityPolicy) validate() error {
+	for env := range p {
+		switch env {
+		case EnvDev, EnvStaging, EnvProd:
+		default:
+			return fmt.Errorf("strategies capabilities: unknown environment %q", env)
+		}
+	}
+	return nil
+}
+
+// StrategySchedulingConfig bounds strategy handlers running at once across instances. While the
+// gateway is saturated, waiting handlers are admitted by instance priority (the priority key of a
+// strategy config) in weighted round-robin, so low-priority instances lag first.
+type StrategySchedulingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Slots is the number of handlers that may run at once; 0 selects GOMAXPROCS.
+	Slots        int `yaml:"slots"`
+	HighWeight   int `yaml:"highWeight"`
+	NormalWeight int `yaml:"normalWeight"`
+	LowWeight    int `yaml:"lowWeight"`
+}
+
+// StrategyAutoRefreshConfig schedules periodic registry reloads so revisions deployed by
+// writing files directly into the strategy directory are picked up without an API call.
+type StrategyAutoRefreshConfig struct {
+	Enabled           bool          `yaml:"enabled"`
+	Interval          time.Duration `yaml:"interval"`
+	ApplyTagFollowers bool          `yaml:"applyTagFollowers"`
 }
 
 // DatabaseConfig controls PostgreSQL connectivity and migration behaviour.
@@ -172,8 +647,19 @@ type DatabaseConfig struct {
 	MaxConnIdleTime   time.Duration `yaml:"maxConnIdleTime"`
 	HealthCheckPeriod time.Duration `yaml:"healthCheckPeriod"`
 	RunMigrations     bool          `yaml:"runMigrations"`
+	// OutboxCompression compresses large event payloads stored in the durable outbox.
+	OutboxCompression OutboxCompressionConfig `yaml:"outboxCompression"`
 }
 
+// OutboxCompressionConfig selects the codec for outbox payloads of at least MinBytes. Codec is
+// "none" (default) or "zstd".
+type OutboxCompressionConfig struct {
+	Codec    string `yaml:"codec"`
+	MinBytes int    `yaml:"minBytes"`
+}
+
+const defaultOutboxCompressionMinBytes = 1024
+
 func (c *DatabaseConfig) applyDefaults() {
 	c.DSN = strings.TrimSpace(c.DSN)
 	if c.DSN == "" {
@@ -197,6 +683,10 @@ func (c *DatabaseConfig) applyDefaults() {
 	if c.HealthCheckPeriod <= 0 {
 		c.HealthCheckPeriod = 30 * time.Second
 	}
+	c.OutboxCompression.Codec = strings.ToLower(strings.TrimSpace(c.OutboxCompression.Codec))
+	if c.OutboxCompression.MinBytes <= 0 {
+		c.OutboxCompression.MinBytes = defaultOutboxCompressionMinBytes
+	}
 }
 
 func (c DatabaseConfig) validate() error {
@@ -221,22 +711,49 @@ func (c DatabaseConfig) validate() error {
 	if c.HealthCheckPeriod <= 0 {
 		return fmt.Errorf("healthCheckPeriod must be >0")
 	}
+	if _, err := outboxstore.ParseCodec(c.OutboxCompression.Codec); err != nil {
+		return fmt.Errorf("outboxCompression: %w", err)
+	}
 	return nil
 }
 
 // AppConfig is the unified Meltica application configuration sourced from YAML.
 type AppConfig struct {
-	Environment Environment                 `yaml:"environment"`
-	Providers   map[Provider]map[string]any `yaml:"providers"`
-	Eventbus    EventbusConfig              `yaml:"eventbus"`
-	Pools       PoolConfig                  `yaml:"pools"`
-	Risk        RiskConfig                  `yaml:"risk"`
-	APIServer   APIServerConfig             `yaml:"apiServer"`
-	Telemetry   TelemetryConfig             `yaml:"telemetry"`
-	Strategies  StrategiesConfig            `yaml:"strategies"`
-	Database    DatabaseConfig              `yaml:"database"`
+	Environment    Environment                 `yaml:"environment"`
+	Providers      map[Provider]map[string]any `yaml:"providers"`
+	Eventbus       EventbusConfig              `yaml:"eventbus"`
+	Pools          PoolConfig                  `yaml:"pools"`
+	Risk           RiskConfig                  `yaml:"risk"`
+	Orders         OrdersConfig                `yaml:"orders"`
+	SymbolPolicy   SymbolPolicyConfig          `yaml:"symbolPolicy"`
+	Sinks          []SinkConfig                `yaml:"sinks"`
+	DropCopy       DropCopyConfig              `yaml:"dropCopy"`
+	Synthetics     []SyntheticInstrumentConfig `yaml:"synthetics"`
+	DeadMansSwitch DeadMansSwitchConfig        `yaml:"deadMansSwitch"`
+	Calendar       CalendarConfig              `yaml:"calendar"`
+	Sessions       SessionsConfig              `yaml:"sessions"`
+	Egress         EgressConfig                `yaml:"egress"`
+	Heartbeat      HeartbeatConfig             `yaml:"heartbeat"`
+	EventAudit     EventAuditConfig            `yaml:"eventAudit"`
+	Approvals      ApprovalsConfig             `yaml:"approvals"`
+	FeatureFlags   map[string]bool             `yaml:"featureFlags"`
+	APIServer      APIServerConfig             `yaml:"apiServer"`
+	Telemetry      TelemetryConfig             `yaml:"telemetry"`
+	Redaction      RedactionConfig             `yaml:"redaction"`
+	Strategies     StrategiesConfig            `yaml:"strategies"`
+	Database       DatabaseConfig              `yaml:"database"`
+	Startup        StartupConfig               `yaml:"startup"`
+	Datasets       DatasetsConfig              `yaml:"datasets"`
+	ObjectStore    ObjectStoreConfig           `yaml:"objectStore"`
 }
 
+const (
+	defaultDeadMansSwitchInterval      = 30 * time.Second
+	defaultStrategyAutoRefreshInterval = time.Minute
+	defaultCalendarCheckInterval       = 5 * time.Second
+	defaultCalendarMissedGrace         = 5 * time.Minute
+)
+
 func defaultRiskConfig() RiskConfig {
 	return RiskConfig{
 		MaxPositionSize:     "250",
@@ -247,6 +764,7 @@ func defaultRiskConfig() RiskConfig {
 		MaxConcurrentOrders: 6,
 		PriceBandPercent:    1.0,
 		AllowedOrderTypes:   []string{"Limit", "Market"},
+		AllowedTimeInForce:  nil,
 		KillSwitchEnabled:   true,
 		MaxRiskBreaches:     3,
 		CircuitBreaker: CircuitBreakerConfig{
@@ -254,6 +772,17 @@ func defaultRiskConfig() RiskConfig {
 			Threshold: 4,
 			Cooldown:  "90s",
 		},
+		FX: FXConfig{
+			Rates:          nil,
+			UseMarketRates: true,
+		},
+		SelfTradePrevention: "reject",
+		BalanceCheck:        false,
+		Concentration: ConcentrationConfig{
+			MaxAssetPercent:      0,
+			MaxVenuePercent:      0,
+			MinPortfolioNotional: "",
+		},
 	}
 }
 
@@ -325,9 +854,32 @@ func (c *AppConfig) normalise() error {
 	c.Providers = normalised
 
 	c.Environment = Environment(strings.ToLower(strings.TrimSpace(string(c.Environment))))
+	if len(c.FeatureFlags) > 0 {
+		flags := make(map[string]bool, len(c.FeatureFlags))
+		for name, enabled := range c.FeatureFlags {
+			key := strings.ToLower(strings.TrimSpace(name))
+			if key == "" {
+				return fmt.Errorf("feature flag name required")
+			}
+			if _, exists := flags[key]; exists {
+				return fmt.Errorf("duplicate feature flag %q", key)
+			}
+			flags[key] = enabled
+		}
+		c.FeatureFlags = flags
+	}
 	c.APIServer.Addr = strings.TrimSpace(c.APIServer.Addr)
 	c.Telemetry.OTLPEndpoint = strings.TrimSpace(c.Telemetry.OTLPEndpoint)
 	c.Telemetry.ServiceName = strings.TrimSpace(c.Telemetry.ServiceName)
+	if c.Telemetry.Tracing.SampleRatio == 0 {
+		c.Telemetry.Tracing.SampleRatio = defaultTraceSampleRatio
+	}
+	if c.Telemetry.RuntimeMetrics.Interval <= 0 {
+		c.Telemetry.RuntimeMetrics.Interval = defaultRuntimeMetricsInterval
+	}
+	c.APIServer.Pprof.Token = strings.TrimSpace(c.APIServer.Pprof.Token)
+	c.APIServer.OrderEntry.applyDefaults()
+	c.APIServer.ReportingCurrency = strings.ToUpper(strings.TrimSpace(c.APIServer.ReportingCurrency))
 
 	if c.Eventbus.ExtensionPayloadCapBytes == 0 {
 		c.Eventbus.ExtensionPayloadCapBytes = eventbus.DefaultExtensionPayloadCapBytes
@@ -338,6 +890,27 @@ func (c *AppConfig) normalise() error {
 		strategyDir = "strategies"
 	}
 	c.Strategies.Directory = filepath.Clean(strategyDir)
+	if c.Strategies.AutoRefresh.Enabled && c.Strategies.AutoRefresh.Interval <= 0 {
+		c.Strategies.AutoRefresh.Interval = defaultStrategyAutoRefreshInterval
+	}
+	if c.Strategies.Scheduling.Enabled && c.Strategies.Scheduling.Slots <= 0 {
+		c.Strategies.Scheduling.Slots = runtime.GOMAXPROCS(0)
+	}
+	c.Strategies.Capabilities = c.Strategies.Capabilities.normalize()
+	c.Strategies.Logs.applyDefaults()
+	c.Strategies.Reports.applyDefaults()
+	c.Strategies.WarmPool.applyDefaults()
+	c.Strategies.Usage.applyDefaults()
+
+	c.Pools.Event.applyDefaults()
+	c.Pools.OrderRequest.applyDefaults()
+
+	c.Orders.Normalization = OrderNormalizationMode(strings.ToLower(strings.TrimSpace(string(c.Orders.Normalization))))
+	if c.Orders.Normalization == "" {
+		c.Orders.Normalization = OrderNormalizationRound
+	}
+	c.Orders.CircuitBreaker.applyDefaults()
+	c.Orders.Queue.applyDefaults()
 
 	if c.Risk.OrderBurst <= 0 {
 		c.Risk.OrderBurst = 1
@@ -348,6 +921,26 @@ func (c *AppConfig) normalise() error {
 	if c.Risk.CircuitBreaker.Threshold < 0 {
 		c.Risk.CircuitBreaker.Threshold = 0
 	}
+	if c.DeadMansSwitch.Enabled && c.DeadMansSwitch.Interval <= 0 {
+		c.DeadMansSwitch.Interval = defaultDeadMansSwitchInterval
+	}
+	if c.Calendar.CheckInterval <= 0 {
+		c.Calendar.CheckInterval = defaultCalendarCheckInterval
+	}
+	if c.Calendar.MissedGrace <= 0 {
+		c.Calendar.MissedGrace = defaultCalendarMissedGrace
+	}
+	if c.Calendar.NotifyBefore < 0 {
+		c.Calendar.NotifyBefore = 0
+	}
+	c.Sessions.applyDefaults()
+	c.Egress.applyDefaults()
+	c.Heartbeat.applyDefaults()
+	c.EventAudit.applyDefaults()
+	c.Approvals.applyDefaults()
+	c.Startup.applyDefaults()
+	c.Datasets.applyDefaults()
+	c.ObjectStore.applyDefaults()
 	if len(c.Risk.AllowedOrderTypes) > 0 {
 		normalized := make([]string, 0, len(c.Risk.AllowedOrderTypes))
 		seen := make(map[string]struct{}, len(c.Risk.AllowedOrderTypes))
@@ -365,6 +958,19 @@ func (c *AppConfig) normalise() error {
 		}
 		c.Risk.AllowedOrderTypes = normalized
 	}
+	// Invalid values are kept for Validate to report.
+	if allowedTIF, err := NormalizeTimeInForce(c.Risk.AllowedTimeInForce); err == nil {
+		c.Risk.AllowedTimeInForce = allowedTIF
+	}
+
+	for i := range c.Sinks {
+		c.Sinks[i].applyDefaults()
+	}
+	c.DropCopy.applyDefaults()
+	c.Redaction.applyDefaults()
+	for i := range c.Synthetics {
+		c.Synthetics[i].applyDefaults()
+	}
 
 	c.Database.applyDefaults()
 
@@ -388,23 +994,29 @@ func (c AppConfig) Validate() error {
 	if c.Eventbus.ExtensionPayloadCapBytes <= 0 {
 		return fmt.Errorf("eventbus extensionPayloadCapBytes must be >0")
 	}
-
-	if c.Pools.Event.Size <= 0 {
-		return fmt.Errorf("pools.event.size must be >0")
+	if err := c.Eventbus.PriorityLanes.validate(); err != nil {
+		return err
 	}
-	if c.Pools.Event.WaitQueueSize < 0 {
-		return fmt.Errorf("pools.event.waitQueueSize must be >=0")
+	if err := c.Eventbus.Watermarks.validate(); err != nil {
+		return err
 	}
-	if c.Pools.OrderRequest.Size <= 0 {
-		return fmt.Errorf("pools.orderRequest.size must be >0")
+
+	if err := c.Pools.Event.validate("event"); err != nil {
+		return err
 	}
-	if c.Pools.OrderRequest.WaitQueueSize < 0 {
-		return fmt.Errorf("pools.orderRequest.waitQueueSize must be >=0")
+	if err := c.Pools.OrderRequest.validate("orderRequest"); err != nil {
+		return err
 	}
 
 	if strings.TrimSpace(c.APIServer.Addr) == "" {
 		return fmt.Errorf("apiServer addr required")
 	}
+	if err := c.APIServer.OrderEntry.validate(); err != nil {
+		return fmt.Errorf("apiServer orderEntry: %w", err)
+	}
+	if c.APIServer.Pprof.Enabled && c.APIServer.Pprof.Token == "" {
+		return fmt.Errorf("apiServer pprof: token required when enabled")
+	}
 
 	if c.Risk.MaxPositionSize == "" {
 		return fmt.Errorf("risk maxPositionSize required")
@@ -436,13 +1048,100 @@ func (c AppConfig) Validate() error {
 	if c.Risk.CircuitBreaker.Enabled && strings.TrimSpace(c.Risk.CircuitBreaker.Cooldown) == "" {
 		return fmt.Errorf("risk circuitBreaker cooldown required when enabled")
 	}
+	if _, err := NormalizeTimeInForce(c.Risk.AllowedTimeInForce); err != nil {
+		return fmt.Errorf("risk allowedTimeInForce: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(c.Risk.SelfTradePrevention)) {
+	case "", "allow", "reject", "cancel-resting":
+	default:
+		return fmt.Errorf("risk selfTradePrevention must be one of allow, reject, cancel-resting")
+	}
+	for currency, raw := range c.Risk.FX.Rates {
+		rate, err := decimal.NewFromString(strings.TrimSpace(raw))
+		if err != nil || !rate.IsPositive() {
+			return fmt.Errorf("risk fx rate for %q must be a positive decimal", currency)
+		}
+	}
+
+	switch c.Orders.Normalization {
+	case "", OrderNormalizationRound, OrderNormalizationReject, OrderNormalizationOff:
+	default:
+		return fmt.Errorf("orders normalization must be one of round, reject, off")
+	}
+	if err := c.Orders.CircuitBreaker.validate(); err != nil {
+		return fmt.Errorf("orders circuitBreaker: %w", err)
+	}
+	if err := c.Orders.Queue.validate(); err != nil {
+		return fmt.Errorf("orders queue: %w", err)
+	}
+	if err := c.SymbolPolicy.validate(); err != nil {
+		return fmt.Errorf("symbolPolicy: %w", err)
+	}
 
 	if strings.TrimSpace(c.Telemetry.ServiceName) == "" {
 		return fmt.Errorf("telemetry serviceName required")
 	}
+	if ratio := c.Telemetry.Tracing.SampleRatio; ratio < 0 || ratio > 1 {
+		return fmt.Errorf("telemetry tracing sampleRatio must be between 0 and 1")
+	}
 	if strings.TrimSpace(c.Strategies.Directory) == "" {
 		return fmt.Errorf("strategies directory required")
 	}
+	if err := c.Strategies.Capabilities.validate(); err != nil {
+		return err
+	}
+	if key := c.Strategies.Archive.SigningKey; key != "" && len(key) < minArchiveKeyLength {
+		return fmt.Errorf("strategies archive signingKey must be at least %d characters", minArchiveKeyLength)
+	}
+	if err := c.Strategies.Logs.validate(); err != nil {
+		return fmt.Errorf("strategies logs: %w", err)
+	}
+	if err := c.Strategies.Reports.validate(); err != nil {
+		return fmt.Errorf("strategies reports: %w", err)
+	}
+	if err := c.Strategies.WarmPool.validate(); err != nil {
+		return fmt.Errorf("strategies warmPool: %w", err)
+	}
+	if err := c.Strategies.Usage.validate(); err != nil {
+		return fmt.Errorf("strategies usage: %w", err)
+	}
+
+	if err := validateSinks(c.Sinks); err != nil {
+		return err
+	}
+	if err := c.DropCopy.validate(); err != nil {
+		return fmt.Errorf("dropCopy: %w", err)
+	}
+	if err := c.Redaction.validate(); err != nil {
+		return fmt.Errorf("redaction: %w", err)
+	}
+	if err := validateSynthetics(c.Synthetics); err != nil {
+		return err
+	}
+	if err := c.Sessions.validate(); err != nil {
+		return fmt.Errorf("sessions: %w", err)
+	}
+	if err := c.Egress.validate(); err != nil {
+		return fmt.Errorf("egress: %w", err)
+	}
+	if err := c.Startup.validate(); err != nil {
+		return fmt.Errorf("startup: %w", err)
+	}
+	if err := c.Heartbeat.validate(); err != nil {
+		return fmt.Errorf("heartbeat: %w", err)
+	}
+	if err := c.EventAudit.validate(); err != nil {
+		return fmt.Errorf("eventAudit: %w", err)
+	}
+	if err := c.Datasets.validate(); err != nil {
+		return fmt.Errorf("datasets: %w", err)
+	}
+	if err := c.ObjectStore.validate(); err != nil {
+		return fmt.Errorf("objectStore: %w", err)
+	}
+	if err := c.Approvals.validate(); err != nil {
+		return fmt.Errorf("approvals: %w", err)
+	}
 
 	if err := c.Database.validate(); err != nil {
 		return fmt.Errorf("database: %w", err)
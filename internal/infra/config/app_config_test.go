@@ -157,6 +157,9 @@ database:
 	if cfg.Pools.OrderRequest.WaitQueueSize != 60 {
 		t.Fatalf("expected pool order request queue size 60, got %d", cfg.Pools.OrderRequest.WaitQueueSize)
 	}
+	if cfg.Pools.Event.Exhaustion != PoolExhaustionWait || cfg.Pools.OrderRequest.Exhaustion != PoolExhaustionWait {
+		t.Fatalf("expected default wait exhaustion policy, got %q/%q", cfg.Pools.Event.Exhaustion, cfg.Pools.OrderRequest.Exhaustion)
+	}
 
 	if cfg.Risk.OrderBurst != 1 {
 		t.Fatalf("expected default order burst 1, got %d", cfg.Risk.OrderBurst)
@@ -306,6 +309,118 @@ telemetry:
 	}
 }
 
+func TestPoolExhaustionPolicyValidation(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, exhaustion string) string {
+		path := filepath.Join(dir, name)
+		yaml := `
+environment: dev
+eventbus:
+  bufferSize: 64
+  fanoutWorkers: 2
+pools:
+  event:
+    size: 10
+    exhaustion: ` + exhaustion + `
+    waitTimeout: 250ms
+  orderRequest:
+    size: 5
+apiServer:
+  addr: ":8080"
+telemetry:
+  serviceName: svc
+`
+		if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+			t.Fatalf("write temp config: %v", err)
+		}
+		return path
+	}
+
+	cfg, err := Load(context.Background(), write("spill.yaml", "Spill"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Pools.Event.Exhaustion != PoolExhaustionSpill {
+		t.Fatalf("expected spill policy, got %q", cfg.Pools.Event.Exhaustion)
+	}
+	if cfg.Pools.Event.WaitTimeout != 250*time.Millisecond {
+		t.Fatalf("expected 250ms wait timeout, got %s", cfg.Pools.Event.WaitTimeout)
+	}
+
+	_, err = Load(context.Background(), write("bogus.yaml", "drop"))
+	if err == nil || !strings.Contains(err.Error(), "pools.event.exhaustion") {
+		t.Fatalf("expected exhaustion policy validation error, got %v", err)
+	}
+}
+
+func TestEventbusPriorityConfig(t *testing.T) {
+	cfg := EventbusConfig{
+		PriorityLanes: EventbusPriorityConfig{
+			Enabled: true,
+			High:    EventbusLaneConfig{Weight: 10, EventTypes: []string{"ExecReport"}},
+			Low:     EventbusLaneConfig{QueueSize: 32, EventTypes: []string{"Ticker"}},
+		},
+	}
+	priority := cfg.PriorityConfig()
+	if !priority.Enabled || priority.High.Weight != 10 || priority.Low.QueueSize != 32 {
+		t.Fatalf("unexpected priority config %+v", priority)
+	}
+	if priority.Classes["ExecReport"] != eventbus.PriorityHigh || priority.Classes["Ticker"] != eventbus.PriorityLow {
+		t.Fatalf("unexpected classes %+v", priority.Classes)
+	}
+	if classes := (EventbusConfig{}).PriorityConfig().Classes; classes != nil {
+		t.Fatalf("expected nil classes to select bus defaults, got %+v", classes)
+	}
+
+	cfg.PriorityLanes.Normal.EventTypes = []string{"Ticker"}
+	if err := cfg.PriorityLanes.validate(); err == nil || !strings.Contains(err.Error(), "Ticker") {
+		t.Fatalf("expected duplicate event type error, got %v", err)
+	}
+}
+
+func TestEventbusWatermarkConfig(t *testing.T) {
+	cfg := EventbusConfig{Watermarks: EventbusWatermarkConfig{Enabled: true, MaxLateness: time.Second, LatePolicy: " Drop "}}
+	watermark := cfg.WatermarkConfig()
+	if !watermark.Enabled || watermark.MaxLateness != time.Second || watermark.LatePolicy != eventbus.LatePolicyDrop {
+		t.Fatalf("unexpected watermark config %+v", watermark)
+	}
+	if err := cfg.Watermarks.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg.Watermarks.LatePolicy = "buffer"
+	if err := cfg.Watermarks.validate(); err == nil || !strings.Contains(err.Error(), "latePolicy") {
+		t.Fatalf("expected late policy error, got %v", err)
+	}
+	cfg.Watermarks = EventbusWatermarkConfig{MaxLateness: -time.Second}
+	if err := cfg.Watermarks.validate(); err == nil || !strings.Contains(err.Error(), "maxLateness") {
+		t.Fatalf("expected max lateness error, got %v", err)
+	}
+}
+
+func TestStartupConfigDefaultsAndValidation(t *testing.T) {
+	var cfg StartupConfig
+	cfg.applyDefaults()
+	if cfg.MaxWait != defaultStartupMaxWait || cfg.InitialBackoff != defaultStartupInitialBackoff || cfg.OnTimeout != StartupTimeoutFail {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg.OnTimeout = " Degrade "
+	cfg.applyDefaults()
+	if cfg.OnTimeout != StartupTimeoutDegrade {
+		t.Fatalf("expected normalized policy, got %q", cfg.OnTimeout)
+	}
+	cfg.OnTimeout = "ignore"
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "onTimeout") {
+		t.Fatalf("expected policy error, got %v", err)
+	}
+	cfg = StartupConfig{InitialBackoff: time.Minute, MaxBackoff: time.Second, OnTimeout: StartupTimeoutFail}
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "maxBackoff") {
+		t.Fatalf("expected backoff error, got %v", err)
+	}
+}
+
 func TestEventbusExtensionPayloadCapOverrides(t *testing.T) {
 	dir := t.TempDir()
 	validPath := filepath.Join(dir, "valid.yaml")
@@ -436,3 +551,475 @@ lambdaManifest:
 	}
 	return cfg
 }
+
+func TestSyntheticInstrumentsConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, synthetics string) string {
+		path := filepath.Join(dir, name)
+		yaml := `
+environment: dev
+eventbus:
+  bufferSize: 64
+  fanoutWorkers: 2
+pools:
+  event:
+    size: 10
+  orderRequest:
+    size: 5
+apiServer:
+  addr: ":8080"
+telemetry:
+  serviceName: svc
+synthetics:
+` + synthetics
+		if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+			t.Fatalf("write temp config: %v", err)
+		}
+		return path
+	}
+
+	cfg, err := Load(context.Background(), write("spread.yaml", `
+  - symbol: btc-eth-spread
+    kind: Spread
+    legs:
+      - {provider: binance, symbol: btc-usdt}
+      - {provider: okx, symbol: ETH-USDT, weight: "20"}
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Synthetics) != 1 {
+		t.Fatalf("expected 1 synthetic, got %d", len(cfg.Synthetics))
+	}
+	syn := cfg.Synthetics[0]
+	if syn.Symbol != "BTC-ETH-SPREAD" || syn.Provider != "binance" || syn.Kind != SyntheticKindSpread || syn.Legs[0].Symbol != "BTC-USDT" {
+		t.Fatalf("unexpected normalised synthetic %+v", syn)
+	}
+	coef := syn.Coefficients()
+	if coef[0].String() != "1" || coef[1].String() != "-20" {
+		t.Fatalf("spread coefficients = %v", coef)
+	}
+
+	invalid := map[string]string{
+		"one-leg.yaml":  "  - {symbol: S, kind: spread, legs: [{provider: binance, symbol: BTC-USDT}]}\n",
+		"kind.yaml":     "  - {symbol: S, kind: ratio, legs: [{provider: binance, symbol: BTC-USDT}]}\n",
+		"weight.yaml":   "  - {symbol: S, kind: basket, legs: [{provider: binance, symbol: BTC-USDT, weight: zero}]}\n",
+		"negative.yaml": "  - {symbol: S, kind: spread, legs: [{provider: binance, symbol: A-B}, {provider: binance, symbol: C-D, weight: \"-1\"}]}\n",
+		"nested.yaml":   "  - {symbol: S, kind: basket, legs: [{provider: binance, symbol: A-B}]}\n  - {symbol: T, kind: basket, legs: [{provider: binance, symbol: S}]}\n",
+	}
+	for name, synthetics := range invalid {
+		if _, err := Load(context.Background(), write(name, synthetics)); err == nil || !strings.Contains(err.Error(), "synthetics[") {
+			t.Fatalf("%s: expected synthetics validation error, got %v", name, err)
+		}
+	}
+}
+
+func TestStrategyCapabilityPolicy(t *testing.T) {
+	policy := StrategyCapabilityPolicy{
+		" PROD ":  {" Live_Trading ", ""},
+		"staging": nil,
+	}.normalize()
+	if err := policy.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if allowed, restricted := policy.Allowed(EnvProd); !restricted || len(allowed) != 1 || allowed[0] != "live_trading" {
+		t.Fatalf("unexpected prod policy %v %v", allowed, restricted)
+	}
+	if allowed, restricted := policy.Allowed(EnvStaging); !restricted || len(allowed) != 0 {
+		t.Fatalf("expected staging to allow nothing, got %v %v", allowed, restricted)
+	}
+	if _, restricted := policy.Allowed(EnvDev); restricted {
+		t.Fatal("expected dev unrestricted")
+	}
+	if err := (StrategyCapabilityPolicy{"qa": {"live_trading"}}).validate(); err == nil {
+		t.Fatal("expected unknown environment rejected")
+	}
+}
+
+func TestSessionsConfigSchedule(t *testing.T) {
+	cfg := SessionsConfig{
+		Enabled:   true,
+		Providers: map[string]SessionRolloverConfig{" okx ": {Rollover: " 08:00 "}},
+	}
+	cfg.applyDefaults()
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	schedule, err := cfg.Schedule("okx")
+	if err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	now := time.Date(2026, 5, 2, 7, 30, 0, 0, time.UTC)
+	if got := schedule.Boundary(now); !got.Equal(time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected boundary %s", got)
+	}
+	if got := schedule.Next(schedule.Boundary(now)); !got.Equal(time.Date(2026, 5, 2, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected next boundary %s", got)
+	}
+
+	cfg.Providers["okx"] = SessionRolloverConfig{Rollover: "25:00", Timezone: ""}
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected invalid rollover to fail validation")
+	}
+	cfg.Providers["okx"] = SessionRolloverConfig{Rollover: "", Timezone: "Mars/Olympus"}
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected unknown timezone to fail validation")
+	}
+}
+
+func TestEgressConfigDefaultsAndValidate(t *testing.T) {
+	cfg := EgressConfig{Enabled: true, Checkers: []string{" ", ""}, AllowList: []string{" 203.0.113.7 ", "2001:db8::/32"}}
+	cfg.applyDefaults()
+	if len(cfg.Checkers) != len(DefaultEgressCheckers) || cfg.Interval != defaultEgressInterval || cfg.Timeout != defaultEgressTimeout {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	prefixes, err := ParseEgressAllowList(cfg.AllowList)
+	if err != nil || len(prefixes) != 2 || prefixes[0].Bits() != 32 {
+		t.Fatalf("unexpected allow-list %v (%v)", prefixes, err)
+	}
+
+	cfg.Checkers = []string{"ftp://checker.example"}
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected non-http checker rejected")
+	}
+	cfg.Checkers = DefaultEgressCheckers
+	cfg.AllowList = []string{"203.0.113.300"}
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected invalid allow-list entry rejected")
+	}
+}
+
+func TestStrategyLogsConfigDefaultsAndValidate(t *testing.T) {
+	cfg := StrategyLogsConfig{Level: " WARN ", Retention: 0}
+	cfg.applyDefaults()
+	if cfg.Level != "warn" || cfg.Retention != defaultStrategyLogRetention {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg.Level = "verbose"
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected unknown level rejected")
+	}
+}
+
+func TestStrategyReportsConfigDefaultsAndValidate(t *testing.T) {
+	cfg := StrategyReportsConfig{Enabled: true}
+	cfg.applyDefaults()
+	if cfg.Interval != defaultStrategyReportInterval || cfg.Retention != defaultStrategyReportRetention {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg.Interval = time.Second
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected too short interval rejected")
+	}
+}
+
+func TestStrategyWarmPoolConfigDefaultsAndValidate(t *testing.T) {
+	cfg := StrategyWarmPoolConfig{Enabled: true}
+	cfg.applyDefaults()
+	if cfg.Capacity != defaultStrategyWarmPoolCapacity {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg.Capacity = -1
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected negative capacity rejected")
+	}
+}
+
+func TestEventAuditConfigDefaultsAndValidate(t *testing.T) {
+	cfg := EventAuditConfig{Enabled: true}
+	cfg.applyDefaults()
+	if cfg.SampleRatio != defaultEventAuditSampleRatio || cfg.Fixtures != defaultEventAuditFixtures ||
+		cfg.Window != defaultEventAuditWindow || cfg.Threshold != defaultEventAuditThreshold || cfg.MinSamples != defaultEventAuditMinSamples {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg.SampleRatio = 1.5
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected sample ratio above 1 rejected")
+	}
+}
+
+func TestStrategyUsageConfigDefaultsAndValidate(t *testing.T) {
+	cfg := StrategyUsageConfig{Enabled: true, GroupBy: " Team "}
+	cfg.applyDefaults()
+	if cfg.Interval != defaultStrategyUsageInterval || cfg.Retention != defaultStrategyUsageRetention || cfg.GroupBy != "team" {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg.GroupBy = "team name"
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected invalid label key rejected")
+	}
+}
+
+func TestOrderEntryConfigValidate(t *testing.T) {
+	cfg := OrderEntryConfig{Enabled: true, Clients: []OrderEntryClientConfig{
+		{Name: " desk ", Token: " desk-token-0123456789 ", Instance: " desk "},
+	}}
+	cfg.applyDefaults()
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if cfg.Clients[0].Name != "desk" || cfg.Clients[0].Instance != "desk" {
+		t.Fatalf("expected trimmed client, got %+v", cfg.Clients[0])
+	}
+	for name, clients := range map[string][]OrderEntryClientConfig{
+		"no clients":     nil,
+		"short token":    {{Name: "desk", Token: "short", Instance: "desk"}},
+		"no instance":    {{Name: "desk", Token: "desk-token-0123456789"}},
+		"duplicate name": {{Name: "desk", Token: "desk-token-0123456789", Instance: "a"}, {Name: "desk", Token: "other-token-0123456789", Instance: "b"}},
+		"shared token":   {{Name: "a", Token: "desk-token-0123456789", Instance: "a"}, {Name: "b", Token: "desk-token-0123456789", Instance: "b"}},
+	} {
+		if err := (OrderEntryConfig{Enabled: true, Clients: clients}).validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestDropCopyConfigDefaultsAndValidate(t *testing.T) {
+	cfg := DropCopyConfig{
+		Enabled:      true,
+		Mode:         " Initiator ",
+		Addr:         "backoffice:9878",
+		SenderCompID: "MELTICA",
+		TargetCompID: "BACKOFFICE",
+		Environments: []Environment{" Prod ", "prod", ""},
+	}
+	cfg.applyDefaults()
+	if cfg.Mode != DropCopyModeInitiator || cfg.HeartbeatInterval != defaultDropCopyHeartbeat ||
+		cfg.BufferSize != defaultDropCopyBufferSize || cfg.ResendStoreSize != defaultDropCopyResendSize ||
+		len(cfg.Environments) != 1 {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if !cfg.ActiveIn(EnvProd) || cfg.ActiveIn(EnvDev) {
+		t.Fatal("expected the session to run only in prod")
+	}
+	cfg.Environments = nil
+	if !cfg.ActiveIn(EnvDev) {
+		t.Fatal("expected the session to run everywhere without an environment list")
+	}
+	for name, mutate := range map[string]func(*DropCopyConfig){
+		"mode":      func(c *DropCopyConfig) { c.Mode = "listener" },
+		"addr":      func(c *DropCopyConfig) { c.Addr = "" },
+		"compID":    func(c *DropCopyConfig) { c.TargetCompID = "" },
+		"heartbeat": func(c *DropCopyConfig) { c.HeartbeatInterval = time.Millisecond },
+		"env":       func(c *DropCopyConfig) { c.Environments = []Environment{"qa"} },
+		"resend":    func(c *DropCopyConfig) { c.ResendStoreSize = -1 },
+	} {
+		broken := cfg
+		mutate(&broken)
+		if err := broken.validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestRedactionConfigDefaultsAndValidate(t *testing.T) {
+	cfg := RedactionConfig{
+		Keys:         []string{" accountId ", "accountId", ""},
+		Patterns:     []string{`\bACC-\d+\b`},
+		Environments: []Environment{" Prod ", "staging"},
+	}
+	cfg.applyDefaults()
+	if len(cfg.Keys) != 1 || cfg.Keys[0] != "accountId" || len(cfg.Environments) != 2 {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if !cfg.ActiveIn(EnvProd) || cfg.ActiveIn(EnvDev) {
+		t.Fatal("expected redaction only in staging and prod")
+	}
+	cfg.Environments = nil
+	if !cfg.ActiveIn(EnvDev) {
+		t.Fatal("expected redaction everywhere without an environment list")
+	}
+	for name, mutate := range map[string]func(*RedactionConfig){
+		"pattern": func(c *RedactionConfig) { c.Patterns = []string{"("} },
+		"env":     func(c *RedactionConfig) { c.Environments = []Environment{"qa"} },
+	} {
+		broken := cfg
+		mutate(&broken)
+		if err := broken.validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestHeartbeatConfigDefaultsAndValidate(t *testing.T) {
+	cfg := HeartbeatConfig{Enabled: true, Interval: 0}
+	cfg.applyDefaults()
+	if cfg.Interval != defaultHeartbeatInterval {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg.Interval = time.Millisecond
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected too short interval rejected")
+	}
+}
+
+func TestApprovalsConfigDefaultsAndValidate(t *testing.T) {
+	cfg := ApprovalsConfig{Enabled: true, Actions: []string{" ", ""}, TrustedHeader: " X-Forwarded-User "}
+	cfg.applyDefaults()
+	if len(cfg.Actions) != len(ApprovalActions) || cfg.TTL != defaultApprovalTTL {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if !cfg.Guards(ApprovalActionProviderDelete) {
+		t.Fatal("expected every action guarded by default")
+	}
+
+	cfg.Actions = []string{" Live_Trading "}
+	cfg.applyDefaults()
+	if cfg.Guards(ApprovalActionRiskLimits) || !cfg.Guards(ApprovalActionLiveTrading) {
+		t.Fatalf("unexpected guarded actions %v", cfg.Actions)
+	}
+	cfg.Actions = []string{"restart"}
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected unknown action rejected")
+	}
+	cfg.Actions = nil
+	cfg.RiskLimitIncreasePercent = -1
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected negative threshold rejected")
+	}
+	cfg.RiskLimitIncreasePercent = 0
+	if cfg.TrustedHeader != "X-Forwarded-User" {
+		t.Fatalf("expected trimmed trusted header, got %q", cfg.TrustedHeader)
+	}
+	cfg.TrustedHeader = ""
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected approvals without a way to verify operators rejected")
+	}
+	for name, operators := range map[string][]ApprovalOperatorConfig{
+		"short token":  {{Name: "alice", Token: "short"}},
+		"no name":      {{Token: "alice-token-0123456789"}},
+		"shared token": {{Name: "alice", Token: "alice-token-0123456789"}, {Name: "bob", Token: "alice-token-0123456789"}},
+	} {
+		cfg.Operators = operators
+		if err := cfg.validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+	cfg.Operators = []ApprovalOperatorConfig{{Name: "alice", Token: "alice-token-0123456789"}}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("expected operator tokens to identify approvers, got %v", err)
+	}
+}
+
+func TestObjectStoreConfigDefaultsAndValidate(t *testing.T) {
+	cfg := ObjectStoreConfig{Endpoint: " http://minio:9000/ ", Bucket: "meltica", StrategiesPrefix: "/strategies", DatasetsPrefix: "datasets/"}
+	cfg.applyDefaults()
+	if cfg.Endpoint != "minio:9000" || !cfg.Insecure || cfg.StrategiesPrefix != "strategies/" || cfg.DatasetsPrefix != "datasets/" {
+		t.Fatalf("unexpected defaults %+v", cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg.AccessKeyID = "key"
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected an access key without a secret rejected")
+	}
+	cfg.AccessKeyID = ""
+	cfg.DatasetsPrefix = cfg.StrategiesPrefix
+	if err := cfg.validate(); err == nil {
+		t.Fatal("expected a shared prefix rejected")
+	}
+	if err := (ObjectStoreConfig{}).validate(); err != nil {
+		t.Fatalf("expected a disabled object store to pass, got %v", err)
+	}
+}
+
+func TestSinkConfigValidateTypes(t *testing.T) {
+	for _, cfg := range []SinkConfig{
+		{Name: "accounting", Type: " Webhook ", URL: "https://accounting.internal/events"},
+		{Name: "risk-bus", Type: "nats", URL: "nats://nats:4222", Subject: "meltica.events"},
+		{Name: "stream", Type: "kafka", URL: "kafka://k1:9092,k2:9092", Subject: "meltica.events"},
+	} {
+		cfg.applyDefaults()
+		if err := cfg.validate(); err != nil {
+			t.Fatalf("%s: validate: %v", cfg.Name, err)
+		}
+	}
+	topicless := SinkConfig{Name: "stream", Type: "kafka", URL: "kafka:9092"}
+	topicless.applyDefaults()
+	if err := topicless.validate(); err == nil {
+		t.Fatal("expected kafka sinks without a topic to be rejected")
+	}
+	unknown := SinkConfig{Name: "queue", Type: "amqp", URL: "amqp://mq:5672"}
+	unknown.applyDefaults()
+	if err := unknown.validate(); err == nil {
+		t.Fatal("expected unknown sink types to be rejected")
+	}
+}
+
+func TestPprofRequiresToken(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, token string) string {
+		path := filepath.Join(dir, name)
+		body := `
+environment: dev
+eventbus:
+  bufferSize: 64
+  fanoutWorkers: 2
+pools:
+  event:
+    size: 10
+  orderRequest:
+    size: 5
+risk:
+  maxPositionSize: "1"
+  maxNotionalValue: "10"
+  notionalCurrency: USD
+  orderThrottle: 1
+apiServer:
+  addr: ":8080"
+  pprof:
+    enabled: true
+    token: "` + token + `"
+telemetry:
+  otlpEndpoint: http://localhost:4318
+  serviceName: svc
+  otlpInsecure: true
+`
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatalf("write temp config: %v", err)
+		}
+		return path
+	}
+
+	if _, err := Load(context.Background(), write("open.yaml", " ")); err == nil || !strings.Contains(err.Error(), "pprof") {
+		t.Fatalf("expected pprof without a token rejected, got %v", err)
+	}
+	cfg, err := Load(context.Background(), write("tokened.yaml", "s3cret"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.APIServer.Pprof.Token != "s3cret" {
+		t.Fatalf("expected pprof token loaded, got %q", cfg.APIServer.Pprof.Token)
+	}
+}
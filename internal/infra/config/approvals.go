@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Sensitive control API actions that can require a second operator's approval.
+const (
+	// ApprovalActionLiveTrading covers enabling live trading on an instance.
+	ApprovalActionLiveTrading = "live_trading"
+	// ApprovalActionRiskLimits covers raising the shared risk limits beyond the configured
+	// threshold.
+	ApprovalActionRiskLimits = "risk_limits"
+	// ApprovalActionProviderDelete covers deleting a provider.
+	ApprovalActionProviderDelete = "provider_delete"
+)
+
+const (
+	defaultApprovalTTL          = time.Hour
+	minApprovalOperatorTokenLen = 16
+)
+
+// ApprovalActions lists every action approvals can guard.
+var ApprovalActions = []string{ApprovalActionLiveTrading, ApprovalActionRiskLimits, ApprovalActionProviderDelete}
+
+// ApprovalsConfig makes high-impact control API actions wait for a second authenticated operator
+// to confirm them. Operators are identified only from verified credentials: a bearer token listed
+// in Operators, or the identity header named by TrustedHeader, which the authenticating proxy in
+// front of the gateway must set and strip from client requests.
+type ApprovalsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Operators maps bearer tokens to operator names.
+	Operators []ApprovalOperatorConfig `yaml:"operators"`
+	// TrustedHeader names the request header carrying the operator identity established by the
+	// authenticating proxy, e.g. X-Forwarded-User.
+	TrustedHeader string `yaml:"trustedHeader"`
+	// Actions lists the guarded actions. Empty guards all of them.
+	Actions []string `yaml:"actions"`
+	// RiskLimitIncreasePercent is how far maxPositionSize or maxNotionalValue may rise above the
+	// current limit without approval. Zero requires approval for any increase.
+	RiskLimitIncreasePercent float64 `yaml:"riskLimitIncreasePercent"`
+	// TTL is how long a pending approval can be confirmed before it expires.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// ApprovalOperatorConfig is an operator allowed to request and approve guarded actions.
+type ApprovalOperatorConfig struct {
+	Name  string `yaml:"name"`
+	Token string `yaml:"token"`
+}
+
+func (c *ApprovalsConfig) applyDefaults() {
+	for i := range c.Operators {
+		c.Operators[i].Name = strings.TrimSpace(c.Operators[i].Name)
+		c.Operators[i].Token = strings.TrimSpace(c.Operators[i].Token)
+	}
+	c.TrustedHeader = strings.TrimSpace(c.TrustedHeader)
+	actions := make([]string, 0, len(c.Actions))
+	for _, action := range c.Actions {
+		if normalized := strings.ToLower(strings.TrimSpace(action)); normalized != "" && !slices.Contains(actions, normalized) {
+			actions = append(actions, normalized)
+		}
+	}
+	if len(actions) == 0 {
+		actions = slices.Clone(ApprovalActions)
+	}
+	c.Actions = actions
+	if c.TTL == 0 {
+		c.TTL = defaultApprovalTTL
+	}
+}
+
+func (c ApprovalsConfig) validate() error {
+	for _, action := range c.Actions {
+		if !slices.Contains(ApprovalActions, action) {
+			return fmt.Errorf("actions: unknown action %q (expected one of %s)", action, strings.Join(ApprovalActions, ", "))
+		}
+	}
+	if c.RiskLimitIncreasePercent < 0 {
+		return fmt.Errorf("riskLimitIncreasePercent must not be negative")
+	}
+	if c.TTL <= 0 {
+		return fmt.Errorf("ttl must be positive")
+	}
+	names := make(map[string]struct{}, len(c.Operators))
+	tokens := make(map[string]struct{}, len(c.Operators))
+	for _, operator := range c.Operators {
+		if operator.Name == "" {
+			return fmt.Errorf("operator name required")
+		}
+		if _, ok := names[operator.Name]; ok {
+			return fmt.Errorf("operator %s defined twice", operator.Name)
+		}
+		names[operator.Name] = struct{}{}
+		if len(operator.Token) < minApprovalOperatorTokenLen {
+			return fmt.Errorf("operator %s: token must be at least %d characters", operator.Name, minApprovalOperatorTokenLen)
+		}
+		if _, ok := tokens[operator.Token]; ok {
+			return fmt.Errorf("operator %s: token shared with another operator", operator.Name)
+		}
+		tokens[operator.Token] = struct{}{}
+	}
+	if c.Enabled && len(c.Operators) == 0 && c.TrustedHeader == "" {
+		return fmt.Errorf("operators or trustedHeader required to identify approvers")
+	}
+	return nil
+}
+
+// Guards reports whether action requires approval.
+func (c ApprovalsConfig) Guards(action string) bool {
+	return c.Enabled && slices.Contains(c.Actions, action)
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultDatasetMaxUploadBytes = 512 << 20
+
+// DatasetsConfig enables the /datasets API, which stores named backtest datasets under Directory,
+// or in the object store when objectStore.datasetsPrefix is set. The API answers 503 without
+// either.
+type DatasetsConfig struct {
+	Directory      string `yaml:"directory"`
+	MaxUploadBytes int64  `yaml:"maxUploadBytes"`
+}
+
+func (c *DatasetsConfig) applyDefaults() {
+	c.Directory = strings.TrimSpace(c.Directory)
+	if c.MaxUploadBytes == 0 {
+		c.MaxUploadBytes = defaultDatasetMaxUploadBytes
+	}
+}
+
+func (c DatasetsConfig) validate() error {
+	if c.MaxUploadBytes < 0 {
+		return fmt.Errorf("maxUploadBytes must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DropCopyMode selects which side opens the FIX drop-copy connection.
+type DropCopyMode string
+
+const (
+	// DropCopyModeAcceptor listens on Addr for the downstream system to connect.
+	DropCopyModeAcceptor DropCopyMode = "acceptor"
+	// DropCopyModeInitiator dials the downstream system at Addr.
+	DropCopyModeInitiator DropCopyMode = "initiator"
+)
+
+const (
+	defaultDropCopyHeartbeat  = 30 * time.Second
+	defaultDropCopyReconnect  = 5 * time.Second
+	defaultDropCopyBufferSize = 10_000
+	defaultDropCopyResendSize = 10_000
+)
+
+// DropCopyConfig streams execution reports as FIX 4.4 ExecutionReport messages to a downstream
+// system over a single drop-copy session. Reports published while no session is logged on are
+// buffered up to BufferSize, oldest dropped first. Sequence numbers carry over between logons, so
+// a counterparty can ask for the reports it missed, and are reset only on the first logon after
+// start or on every logon with ResetSeqNumOnLogon.
+type DropCopyConfig struct {
+	Enabled bool         `yaml:"enabled"`
+	Mode    DropCopyMode `yaml:"mode"`
+	// Addr is the listen address of an acceptor or the host:port an initiator dials.
+	Addr         string `yaml:"addr"`
+	SenderCompID string `yaml:"senderCompID"`
+	TargetCompID string `yaml:"targetCompID"`
+	// HeartbeatInterval is the HeartBtInt proposed on logon.
+	HeartbeatInterval time.Duration `yaml:"heartbeatInterval"`
+	// ReconnectInterval is how long an initiator waits before dialling again.
+	ReconnectInterval time.Duration `yaml:"reconnectInterval"`
+	// Environments lists the environments the session runs in; empty runs it in every environment.
+	Environments []Environment `yaml:"environments"`
+	// Providers filters the reports sent; empty sends every provider's reports.
+	Providers  []string `yaml:"providers"`
+	BufferSize int      `yaml:"bufferSize"`
+	// ResendStoreSize is how many sent execution reports are kept for ResendRequests; older
+	// ones are gap-filled.
+	ResendStoreSize int `yaml:"resendStoreSize"`
+	// ResetSeqNumOnLogon sends ResetSeqNumFlag on every logon instead of continuing the sequence.
+	ResetSeqNumOnLogon bool `yaml:"resetSeqNumOnLogon"`
+}
+
+func (c *DropCopyConfig) applyDefaults() {
+	c.Mode = DropCopyMode(strings.ToLower(strings.TrimSpace(string(c.Mode))))
+	c.Addr = strings.TrimSpace(c.Addr)
+	c.SenderCompID = strings.TrimSpace(c.SenderCompID)
+	c.TargetCompID = strings.TrimSpace(c.TargetCompID)
+	if c.HeartbeatInterval == 0 {
+		c.HeartbeatInterval = defaultDropCopyHeartbeat
+	}
+	if c.ReconnectInterval == 0 {
+		c.ReconnectInterval = defaultDropCopyReconnect
+	}
+	if c.BufferSize == 0 {
+		c.BufferSize = defaultDropCopyBufferSize
+	}
+	if c.ResendStoreSize == 0 {
+		c.ResendStoreSize = defaultDropCopyResendSize
+	}
+	environments := make([]Environment, 0, len(c.Environments))
+	for _, env := range c.Environments {
+		if normalized := Environment(strings.ToLower(strings.TrimSpace(string(env)))); normalized != "" && !slices.Contains(environments, normalized) {
+			environments = append(environments, normalized)
+		}
+	}
+	c.Environments = environments
+}
+
+func (c DropCopyConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Mode {
+	case DropCopyModeAcceptor, DropCopyModeInitiator:
+	default:
+		return fmt.Errorf("mode must be acceptor or initiator")
+	}
+	if c.Addr == "" {
+		return fmt.Errorf("addr required")
+	}
+	if c.SenderCompID == "" || c.TargetCompID == "" {
+		return fmt.Errorf("senderCompID and targetCompID required")
+	}
+	if c.HeartbeatInterval < time.Second {
+		return fmt.Errorf("heartbeatInterval must be at least 1s")
+	}
+	if c.ReconnectInterval < 0 {
+		return fmt.Errorf("reconnectInterval must not be negative")
+	}
+	if c.BufferSize < 1 {
+		return fmt.Errorf("bufferSize must be positive")
+	}
+	if c.ResendStoreSize < 1 {
+		return fmt.Errorf("resendStoreSize must be positive")
+	}
+	for _, env := range c.Environments {
+		switch env {
+		case EnvDev, EnvStaging, EnvProd:
+		default:
+			return fmt.Errorf("unknown environment %q", env)
+		}
+	}
+	return nil
+}
+
+// ActiveIn reports whether the drop-copy session runs in env.
+func (c DropCopyConfig) ActiveIn(env Environment) bool {
+	if !c.Enabled {
+		return false
+	}
+	if len(c.Environments) == 0 {
+		return true
+	}
+	return slices.Contains(c.Environments, Environment(strings.ToLower(strings.TrimSpace(string(env)))))
+}
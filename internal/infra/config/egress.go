@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultEgressInterval = 5 * time.Minute
+	defaultEgressTimeout  = 5 * time.Second
+)
+
+// DefaultEgressCheckers answer with the caller's public IP as plain text.
+var DefaultEgressCheckers = []string{
+	"https://api.ipify.org",
+	"https://checkip.amazonaws.com",
+}
+
+// EgressConfig periodically looks up the gateway's public egress IPs, since exchanges enforce
+// API-key IP allow-lists and an unnoticed IP change makes signed requests fail.
+type EgressConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Checkers are URLs answering with the caller's public IP as plain text.
+	Checkers []string `yaml:"checkers"`
+	// Interval is how often the checkers are queried.
+	Interval time.Duration `yaml:"interval"`
+	// Timeout bounds each checker request.
+	Timeout time.Duration `yaml:"timeout"`
+	// AllowList holds the IPs or CIDR ranges registered with exchange API keys. Egress IPs
+	// outside it are flagged; an empty list disables the comparison.
+	AllowList []string `yaml:"allowList"`
+}
+
+func (c *EgressConfig) applyDefaults() {
+	checkers := make([]string, 0, len(c.Checkers))
+	for _, checker := range c.Checkers {
+		if trimmed := strings.TrimSpace(checker); trimmed != "" {
+			checkers = append(checkers, trimmed)
+		}
+	}
+	if len(checkers) == 0 {
+		checkers = append(checkers, DefaultEgressCheckers...)
+	}
+	c.Checkers = checkers
+	if c.Interval <= 0 {
+		c.Interval = defaultEgressInterval
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultEgressTimeout
+	}
+	allowList := make([]string, 0, len(c.AllowList))
+	for _, entry := range c.AllowList {
+		if trimmed := strings.TrimSpace(entry); trimmed != "" {
+			allowList = append(allowList, trimmed)
+		}
+	}
+	c.AllowList = allowList
+}
+
+func (c EgressConfig) validate() error {
+	for _, checker := range c.Checkers {
+		parsed, err := url.Parse(checker)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("checker %q must be an http or https url", checker)
+		}
+	}
+	if _, err := ParseEgressAllowList(c.AllowList); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ParseEgressAllowList parses allow-list entries as single IPs or CIDR ranges.
+func ParseEgressAllowList(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		trimmed := strings.TrimSpace(entry)
+		if strings.Contains(trimmed, "/") {
+			prefix, err := netip.ParsePrefix(trimmed)
+			if err != nil {
+				return nil, fmt.Errorf("allowList entry %q is not a CIDR range", entry)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("allowList entry %q is not an IP address", entry)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
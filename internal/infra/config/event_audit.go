@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultEventAuditSampleRatio = 0.01
+	defaultEventAuditFixtures    = "config/event-fixtures"
+	defaultEventAuditWindow      = 5 * time.Minute
+	defaultEventAuditThreshold   = 0.05
+	defaultEventAuditMinSamples  = 50
+	minEventAuditWindow          = time.Second
+)
+
+// EventAuditConfig samples live provider events and compares the shape of their fields with golden
+// fixtures, alerting when a venue starts sending values the adapters were not written for.
+type EventAuditConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// SampleRatio is the share of provider data events inspected.
+	SampleRatio float64 `yaml:"sampleRatio"`
+	// Fixtures is the directory holding <provider>/<EventType>.json golden payloads, with
+	// _default/<EventType>.json applying to providers without their own.
+	Fixtures string `yaml:"fixtures"`
+	// Window is how long samples accumulate before field distributions are compared.
+	Window time.Duration `yaml:"window"`
+	// Threshold is the share of a window's samples that must deviate on a field to raise an alert.
+	Threshold float64 `yaml:"threshold"`
+	// MinSamples is the number of samples a provider route needs in a window to be judged.
+	MinSamples int `yaml:"minSamples"`
+}
+
+func (c *EventAuditConfig) applyDefaults() {
+	if c.SampleRatio == 0 {
+		c.SampleRatio = defaultEventAuditSampleRatio
+	}
+	c.Fixtures = strings.TrimSpace(c.Fixtures)
+	if c.Fixtures == "" {
+		c.Fixtures = defaultEventAuditFixtures
+	}
+	if c.Window == 0 {
+		c.Window = defaultEventAuditWindow
+	}
+	if c.Threshold == 0 {
+		c.Threshold = defaultEventAuditThreshold
+	}
+	if c.MinSamples == 0 {
+		c.MinSamples = defaultEventAuditMinSamples
+	}
+}
+
+func (c EventAuditConfig) validate() error {
+	if c.SampleRatio <= 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("sampleRatio must be in (0, 1]")
+	}
+	if c.Window < minEventAuditWindow {
+		return fmt.Errorf("window must be at least %s", minEventAuditWindow)
+	}
+	if c.Threshold <= 0 || c.Threshold > 1 {
+		return fmt.Errorf("threshold must be in (0, 1]")
+	}
+	if c.MinSamples < 1 {
+		return fmt.Errorf("minSamples must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultHeartbeatInterval = 5 * time.Second
+	minHeartbeatInterval     = 100 * time.Millisecond
+)
+
+// HeartbeatConfig publishes a periodic heartbeat event per provider carrying when each of its
+// routes last delivered data, so strategies can detect stale feeds without polling the API.
+type HeartbeatConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often heartbeats are published.
+	Interval time.Duration `yaml:"interval"`
+}
+
+func (c *HeartbeatConfig) applyDefaults() {
+	if c.Interval == 0 {
+		c.Interval = defaultHeartbeatInterval
+	}
+}
+
+func (c HeartbeatConfig) validate() error {
+	if c.Interval < minHeartbeatInterval {
+		return fmt.Errorf("interval must be at least %s", minHeartbeatInterval)
+	}
+	return nil
+}
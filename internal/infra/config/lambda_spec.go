@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -38,12 +39,15 @@ func (s *LambdaStrategySpec) Normalize() {
 // ProviderSymbols defines the symbol scope supplied by a provider.
 type ProviderSymbols struct {
 	Symbols []string `yaml:"symbols" json:"symbols"`
+	// SubAccount selects the provider sub-account orders are routed to. Empty uses the primary account.
+	SubAccount string `yaml:"subAccount" json:"subAccount,omitempty"`
 }
 
 func (p *ProviderSymbols) normalize() {
 	if p == nil {
 		return
 	}
+	p.SubAccount = strings.TrimSpace(p.SubAccount)
 	if len(p.Symbols) == 0 {
 		return
 	}
@@ -74,6 +78,54 @@ type LambdaSpec struct {
 	Strategy        LambdaStrategySpec         `yaml:"strategy" json:"strategy"`
 	ProviderSymbols map[string]ProviderSymbols `yaml:"scope" json:"scope"`
 	Providers       []string                   `yaml:"-" json:"-"`
+	// Labels are free-form key/value tags such as team, desk or book used to filter and group
+	// instances. Keys are lower-cased.
+	Labels map[string]string `yaml:"labels" json:"labels,omitempty"`
+	// DryRun skips order submission when true and trades live when false. It takes precedence
+	// over the dry_run strategy config; when both are absent the instance runs dry.
+	DryRun *bool `yaml:"dryRun" json:"dryRun,omitempty"`
+}
+
+// DryRunEnabled reports whether the instance skips order submission.
+func (s LambdaSpec) DryRunEnabled() bool {
+	if s.DryRun != nil {
+		return *s.DryRun
+	}
+	if raw, ok := s.Strategy.Config["dry_run"].(bool); ok {
+		return raw
+	}
+	return true
+}
+
+const maxLabelValueLength = 128
+
+var labelKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]{0,62}$`)
+
+// NormalizeLabels trims label keys and values and lower-cases keys. Keys must start with a letter
+// or digit and may contain letters, digits, '.', '_', '/' and '-'; values must be non-empty.
+func NormalizeLabels(labels map[string]string) (map[string]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(labels))
+	for key, value := range labels {
+		normalizedKey := strings.ToLower(strings.TrimSpace(key))
+		if !labelKeyPattern.MatchString(normalizedKey) {
+			return nil, fmt.Errorf("label %q: invalid key", key)
+		}
+		normalizedValue := strings.TrimSpace(value)
+		if normalizedValue == "" {
+			return nil, fmt.Errorf("label %q: value required", normalizedKey)
+		}
+		if len(normalizedValue) > maxLabelValueLength {
+			return nil, fmt.Errorf("label %q: value exceeds %d characters", normalizedKey, maxLabelValueLength)
+		}
+		if _, exists := out[normalizedKey]; exists {
+			return nil, fmt.Errorf("label %q: duplicate key", normalizedKey)
+		}
+		out[normalizedKey] = normalizedValue
+	}
+	return out, nil
 }
 
 // UnmarshalYAML implements custom YAML decoding for LambdaSpec.
@@ -85,6 +137,8 @@ func (s *LambdaSpec) UnmarshalYAML(value *yaml.Node) error {
 	var base struct {
 		ID       string             `yaml:"id"`
 		Strategy LambdaStrategySpec `yaml:"strategy"`
+		Labels   map[string]string  `yaml:"labels"`
+		DryRun   *bool              `yaml:"dryRun"`
 	}
 	if err := value.Decode(&base); err != nil {
 		return fmt.Errorf("decode lambda spec: %w", err)
@@ -132,7 +186,14 @@ func (s *LambdaSpec) UnmarshalYAML(value *yaml.Node) error {
 		names = append(names, name)
 	}
 
+	labels, err := NormalizeLabels(base.Labels)
+	if err != nil {
+		return fmt.Errorf("labels: %w", err)
+	}
+
 	s.ID = base.ID
+	s.Labels = labels
+	s.DryRun = base.DryRun
 	base.Strategy.Normalize()
 	s.Strategy = base.Strategy
 	s.ProviderSymbols = assignments
@@ -212,6 +273,28 @@ func (s LambdaSpec) SymbolsForProvider(provider string) []string {
 	return cloned
 }
 
+// SubAccountForProvider returns the sub-account selected for a provider, or empty for the primary account.
+func (s LambdaSpec) SubAccountForProvider(provider string) string {
+	return strings.TrimSpace(s.ProviderSymbols[strings.TrimSpace(provider)].SubAccount)
+}
+
+// SubAccountMap returns the provider-to-sub-account mapping for providers that select one.
+func (s LambdaSpec) SubAccountMap() map[string]string {
+	var out map[string]string
+	for name, assignment := range s.ProviderSymbols {
+		normalizedName := strings.TrimSpace(name)
+		subAccount := strings.TrimSpace(assignment.SubAccount)
+		if normalizedName == "" || subAccount == "" {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[normalizedName] = subAccount
+	}
+	return out
+}
+
 // AllSymbols returns the unique set of symbols referenced by the spec.
 func (s LambdaSpec) AllSymbols() []string {
 	unique := make(map[string]struct{})
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLambdaSpecSubAccounts(t *testing.T) {
+	raw := `
+id: desk-a-grid
+strategy:
+  identifier: grid
+scope:
+  binance:
+    symbols: [btc-usdt]
+    subAccount: " desk-a "
+  okx:
+    symbols: [BTC-USDT]
+`
+	var spec LambdaSpec
+	if err := yaml.Unmarshal([]byte(raw), &spec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := spec.SubAccountForProvider("binance"); got != "desk-a" {
+		t.Fatalf("expected binance sub-account desk-a, got %q", got)
+	}
+	if got := spec.SubAccountForProvider("okx"); got != "" {
+		t.Fatalf("expected okx on primary account, got %q", got)
+	}
+	accounts := spec.SubAccountMap()
+	if len(accounts) != 1 || accounts["binance"] != "desk-a" {
+		t.Fatalf("unexpected sub-account map %v", accounts)
+	}
+}
+
+func TestLambdaSpecLabels(t *testing.T) {
+	raw := `
+id: desk-a-grid
+labels:
+  Team: " delta "
+  desk: futures
+strategy:
+  identifier: grid
+scope:
+  binance:
+    symbols: [BTC-USDT]
+`
+	var spec LambdaSpec
+	if err := yaml.Unmarshal([]byte(raw), &spec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(spec.Labels) != 2 || spec.Labels["team"] != "delta" || spec.Labels["desk"] != "futures" {
+		t.Fatalf("unexpected labels %v", spec.Labels)
+	}
+
+	for _, labels := range []map[string]string{
+		{"desk:eu": "futures"},
+		{"desk": ""},
+		{"Desk": "a", "desk": "b"},
+	} {
+		if _, err := NormalizeLabels(labels); err == nil {
+			t.Fatalf("expected %v rejected", labels)
+		}
+	}
+}
+
+func TestLambdaSpecDryRun(t *testing.T) {
+	raw := `
+id: desk-a-grid
+dryRun: false
+strategy:
+  identifier: grid
+  config:
+    dry_run: true
+scope:
+  binance:
+    symbols: [BTC-USDT]
+`
+	var spec LambdaSpec
+	if err := yaml.Unmarshal([]byte(raw), &spec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if spec.DryRun == nil || spec.DryRunEnabled() {
+		t.Fatalf("expected dryRun to override the strategy config, got %v", spec.DryRun)
+	}
+	spec.DryRun = nil
+	if !spec.DryRunEnabled() {
+		t.Fatal("expected dry_run strategy config to apply without dryRun")
+	}
+	spec.Strategy.Config = nil
+	if !spec.DryRunEnabled() {
+		t.Fatal("expected instances to run dry by default")
+	}
+}
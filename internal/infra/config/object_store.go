@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ObjectStoreConfig points the gateway at an S3-compatible bucket so several gateway nodes share
+// strategy code and backtest datasets without a shared filesystem. Each use is enabled by its key
+// prefix: StrategiesPrefix mirrors the strategy registry tree, with strategies.directory kept as
+// the local cache, and DatasetsPrefix stores datasets in the bucket instead of datasets.directory.
+type ObjectStoreConfig struct {
+	// Endpoint is host[:port], optionally with an http:// or https:// scheme.
+	Endpoint string `yaml:"endpoint"`
+	Region   string `yaml:"region"`
+	Bucket   string `yaml:"bucket"`
+	// AccessKeyID and SecretAccessKey fall back to the AWS_* and MINIO_* environment variables and
+	// then to instance credentials when empty.
+	AccessKeyID     string `yaml:"accessKeyId"`
+	SecretAccessKey string `yaml:"secretAccessKey"`
+	// Insecure talks plain HTTP, e.g. to a local MinIO.
+	Insecure         bool   `yaml:"insecure"`
+	StrategiesPrefix string `yaml:"strategiesPrefix"`
+	DatasetsPrefix   string `yaml:"datasetsPrefix"`
+}
+
+// Enabled reports whether any component keeps its data in the bucket.
+func (c ObjectStoreConfig) Enabled() bool {
+	return c.StrategiesPrefix != "" || c.DatasetsPrefix != ""
+}
+
+func (c *ObjectStoreConfig) applyDefaults() {
+	c.Endpoint = strings.TrimSpace(c.Endpoint)
+	if rest, ok := strings.CutPrefix(c.Endpoint, "http://"); ok {
+		c.Endpoint = rest
+		c.Insecure = true
+	}
+	c.Endpoint = strings.TrimSuffix(strings.TrimPrefix(c.Endpoint, "https://"), "/")
+	c.Region = strings.TrimSpace(c.Region)
+	c.Bucket = strings.TrimSpace(c.Bucket)
+	c.StrategiesPrefix = normalizeObjectPrefix(c.StrategiesPrefix)
+	c.DatasetsPrefix = normalizeObjectPrefix(c.DatasetsPrefix)
+}
+
+// normalizeObjectPrefix trims slashes and ends non-empty prefixes with exactly one.
+func normalizeObjectPrefix(prefix string) string {
+	prefix = strings.Trim(strings.TrimSpace(prefix), "/")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "/"
+}
+
+func (c ObjectStoreConfig) validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Endpoint == "" {
+		return fmt.Errorf("endpoint required")
+	}
+	if strings.Contains(c.Endpoint, "://") || strings.Contains(c.Endpoint, "/") {
+		return fmt.Errorf("endpoint must be host[:port]")
+	}
+	if c.Bucket == "" {
+		return fmt.Errorf("bucket required")
+	}
+	if (c.AccessKeyID == "") != (c.SecretAccessKey == "") {
+		return fmt.Errorf("accessKeyId and secretAccessKey must be set together")
+	}
+	if c.StrategiesPrefix != "" && c.StrategiesPrefix == c.DatasetsPrefix {
+		return fmt.Errorf("strategiesPrefix and datasetsPrefix must differ")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultOrderCircuitFailureThreshold = 5
+	defaultOrderCircuitCooldown         = 30 * time.Second
+)
+
+// OrderCircuitBreakerConfig fails orders fast on a provider whose order submissions keep failing.
+// FailureThreshold consecutive failures open the provider's circuit; a submission slower than
+// LatencyThreshold counts as a failure even when it succeeds, and 0 disables the latency check.
+// After Cooldown one probe order is let through: success closes the circuit, failure reopens it.
+type OrderCircuitBreakerConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	FailureThreshold int           `yaml:"failureThreshold"`
+	LatencyThreshold time.Duration `yaml:"latencyThreshold"`
+	Cooldown         time.Duration `yaml:"cooldown"`
+}
+
+func (c *OrderCircuitBreakerConfig) applyDefaults() {
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = defaultOrderCircuitFailureThreshold
+	}
+	if c.Cooldown == 0 {
+		c.Cooldown = defaultOrderCircuitCooldown
+	}
+}
+
+func (c OrderCircuitBreakerConfig) validate() error {
+	if c.FailureThreshold < 1 {
+		return fmt.Errorf("failureThreshold must be at least 1")
+	}
+	if c.LatencyThreshold < 0 {
+		return fmt.Errorf("latencyThreshold must not be negative")
+	}
+	if c.Cooldown < 0 {
+		return fmt.Errorf("cooldown must not be negative")
+	}
+	return nil
+}
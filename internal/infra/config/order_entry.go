@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const minOrderEntryTokenLength = 16
+
+// OrderEntryConfig opens a websocket on the control API through which external systems submit
+// orders and receive their execution reports. Each client authenticates with its own bearer token
+// and trades through one strategy instance, whose scope, risk limits and trading switch apply to
+// every order the client enters.
+type OrderEntryConfig struct {
+	Enabled bool                     `yaml:"enabled"`
+	Clients []OrderEntryClientConfig `yaml:"clients"`
+}
+
+// OrderEntryClientConfig authorises one external system. Instance names the strategy instance its
+// orders are attributed to, typically one running a strategy that places no orders itself.
+type OrderEntryClientConfig struct {
+	Name     string `yaml:"name"`
+	Token    string `yaml:"token"`
+	Instance string `yaml:"instance"`
+}
+
+func (c *OrderEntryConfig) applyDefaults() {
+	for i := range c.Clients {
+		c.Clients[i].Name = strings.TrimSpace(c.Clients[i].Name)
+		c.Clients[i].Token = strings.TrimSpace(c.Clients[i].Token)
+		c.Clients[i].Instance = strings.TrimSpace(c.Clients[i].Instance)
+	}
+}
+
+func (c OrderEntryConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.Clients) == 0 {
+		return fmt.Errorf("at least one client required")
+	}
+	names := make(map[string]struct{}, len(c.Clients))
+	tokens := make(map[string]struct{}, len(c.Clients))
+	for _, client := range c.Clients {
+		if client.Name == "" {
+			return fmt.Errorf("client name required")
+		}
+		if _, ok := names[client.Name]; ok {
+			return fmt.Errorf("client %s defined twice", client.Name)
+		}
+		names[client.Name] = struct{}{}
+		if len(client.Token) < minOrderEntryTokenLength {
+			return fmt.Errorf("client %s: token must be at least %d characters", client.Name, minOrderEntryTokenLength)
+		}
+		if _, ok := tokens[client.Token]; ok {
+			return fmt.Errorf("client %s: token shared with another client", client.Name)
+		}
+		tokens[client.Token] = struct{}{}
+		if client.Instance == "" {
+			return fmt.Errorf("client %s: instance required", client.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import "fmt"
+
+const (
+	defaultOrderQueueSize        = 256
+	defaultOrderQueueConcurrency = 4
+)
+
+// OrderQueueConfig queues order actions per provider so cancels run before new orders.
+// Concurrency bounds the actions in flight to one provider; Size bounds the actions waiting behind
+// them. A full queue rejects new orders, while cancels displace the most recently queued new order.
+type OrderQueueConfig struct {
+	Enabled     bool `yaml:"enabled"`
+	Size        int  `yaml:"size"`
+	Concurrency int  `yaml:"concurrency"`
+}
+
+func (c *OrderQueueConfig) applyDefaults() {
+	if c.Size == 0 {
+		c.Size = defaultOrderQueueSize
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = defaultOrderQueueConcurrency
+	}
+}
+
+func (c OrderQueueConfig) validate() error {
+	if c.Size < 1 {
+		return fmt.Errorf("size must be at least 1")
+	}
+	if c.Concurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1")
+	}
+	return nil
+}
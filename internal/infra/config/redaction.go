@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// RedactionConfig extends the credential scrubbing applied to logs and API error messages. The
+// built-in credential keys (apiKey, secret, signature, passphrase, listenKey, ...) are always
+// redacted where redaction is active.
+type RedactionConfig struct {
+	// Keys are additional JSON field, query parameter and header names whose values are masked.
+	Keys []string `yaml:"keys"`
+	// Patterns are regular expressions whose matches are masked, e.g. account numbers.
+	Patterns []string `yaml:"patterns"`
+	// Environments lists the environments redaction is active in; empty redacts in every
+	// environment.
+	Environments []Environment `yaml:"environments"`
+}
+
+func (c *RedactionConfig) applyDefaults() {
+	keys := make([]string, 0, len(c.Keys))
+	for _, key := range c.Keys {
+		if trimmed := strings.TrimSpace(key); trimmed != "" && !slices.Contains(keys, trimmed) {
+			keys = append(keys, trimmed)
+		}
+	}
+	c.Keys = keys
+	environments := make([]Environment, 0, len(c.Environments))
+	for _, env := range c.Environments {
+		if normalized := Environment(strings.ToLower(strings.TrimSpace(string(env)))); normalized != "" && !slices.Contains(environments, normalized) {
+			environments = append(environments, normalized)
+		}
+	}
+	c.Environments = environments
+}
+
+func (c RedactionConfig) validate() error {
+	for _, pattern := range c.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+	for _, env := range c.Environments {
+		switch env {
+		case EnvDev, EnvStaging, EnvProd:
+		default:
+			return fmt.Errorf("unknown environment %q", env)
+		}
+	}
+	return nil
+}
+
+// ActiveIn reports whether logs and API errors are redacted in env.
+func (c RedactionConfig) ActiveIn(env Environment) bool {
+	if len(c.Environments) == 0 {
+		return true
+	}
+	return slices.Contains(c.Environments, Environment(strings.ToLower(strings.TrimSpace(string(env)))))
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSessionRollover = "00:00"
+	defaultSessionTimezone = "UTC"
+)
+
+// SessionsConfig publishes a session-boundary event at each venue's daily rollover carrying the
+// end-of-session position and PnL of every instance trading the venue.
+type SessionsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Rollover is the default daily boundary as HH:MM in Timezone.
+	Rollover string `yaml:"rollover"`
+	// Timezone is an IANA zone name such as UTC or America/New_York.
+	Timezone string `yaml:"timezone"`
+	// Providers overrides the rollover per provider name.
+	Providers map[string]SessionRolloverConfig `yaml:"providers"`
+}
+
+// SessionRolloverConfig sets the daily rollover of one venue. Empty fields inherit the defaults.
+type SessionRolloverConfig struct {
+	Rollover string `yaml:"rollover"`
+	Timezone string `yaml:"timezone"`
+}
+
+// SessionSchedule is a resolved daily rollover.
+type SessionSchedule struct {
+	Hour     int
+	Minute   int
+	Location *time.Location
+}
+
+// Boundary returns the latest rollover at or before now.
+func (s SessionSchedule) Boundary(now time.Time) time.Time {
+	local := now.In(s.Location)
+	boundary := time.Date(local.Year(), local.Month(), local.Day(), s.Hour, s.Minute, 0, 0, s.Location)
+	if boundary.After(local) {
+		boundary = time.Date(local.Year(), local.Month(), local.Day()-1, s.Hour, s.Minute, 0, 0, s.Location)
+	}
+	return boundary
+}
+
+// Next returns the first rollover after boundary.
+func (s SessionSchedule) Next(boundary time.Time) time.Time {
+	local := boundary.In(s.Location)
+	return time.Date(local.Year(), local.Month(), local.Day()+1, s.Hour, s.Minute, 0, 0, s.Location)
+}
+
+// Schedule resolves the rollover of provider, falling back to the defaults.
+func (c SessionsConfig) Schedule(provider string) (SessionSchedule, error) {
+	rollover, timezone := c.Rollover, c.Timezone
+	if override, ok := c.Providers[strings.TrimSpace(provider)]; ok {
+		if override.Rollover != "" {
+			rollover = override.Rollover
+		}
+		if override.Timezone != "" {
+			timezone = override.Timezone
+		}
+	}
+	return parseSessionSchedule(rollover, timezone)
+}
+
+func parseSessionSchedule(rollover, timezone string) (SessionSchedule, error) {
+	var schedule SessionSchedule
+	parsed, err := time.Parse("15:04", rollover)
+	if err != nil {
+		return schedule, fmt.Errorf("rollover %q must be HH:MM", rollover)
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return schedule, fmt.Errorf("timezone %q: %w", timezone, err)
+	}
+	schedule.Hour = parsed.Hour()
+	schedule.Minute = parsed.Minute()
+	schedule.Location = location
+	return schedule, nil
+}
+
+func (c *SessionsConfig) applyDefaults() {
+	c.Rollover = strings.TrimSpace(c.Rollover)
+	if c.Rollover == "" {
+		c.Rollover = defaultSessionRollover
+	}
+	c.Timezone = strings.TrimSpace(c.Timezone)
+	if c.Timezone == "" {
+		c.Timezone = defaultSessionTimezone
+	}
+	if len(c.Providers) == 0 {
+		return
+	}
+	providers := make(map[string]SessionRolloverConfig, len(c.Providers))
+	for name, override := range c.Providers {
+		providers[strings.TrimSpace(name)] = SessionRolloverConfig{
+			Rollover: strings.TrimSpace(override.Rollover),
+			Timezone: strings.TrimSpace(override.Timezone),
+		}
+	}
+	c.Providers = providers
+}
+
+func (c SessionsConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if _, err := parseSessionSchedule(c.Rollover, c.Timezone); err != nil {
+		return err
+	}
+	for name := range c.Providers {
+		if name == "" {
+			return fmt.Errorf("provider name required")
+		}
+		if _, err := c.Schedule(name); err != nil {
+			return fmt.Errorf("provider %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SinkType identifies the transport used by an event sink.
+type SinkType string
+
+const (
+	// SinkTypeWebhook POSTs JSON batches to an HTTP endpoint.
+	SinkTypeWebhook SinkType = "webhook"
+	// SinkTypeNATS publishes JSON batches to a NATS subject.
+	SinkTypeNATS SinkType = "nats"
+	// SinkTypeKafka produces JSON batches to a Kafka topic.
+	SinkTypeKafka SinkType = "kafka"
+)
+
+const (
+	defaultSinkBatchSize     = 100
+	defaultSinkFlushInterval = time.Second
+	defaultSinkMaxRetries    = 5
+	defaultSinkTimeout       = 5 * time.Second
+	defaultSinkBufferSize    = 1024
+)
+
+// SinkConfig forwards selected bus events to an external system.
+type SinkConfig struct {
+	Name string   `yaml:"name"`
+	Type SinkType `yaml:"type"`
+	// URL is the webhook endpoint, NATS server (nats://host:port) or Kafka bootstrap address
+	// (kafka://host:port, comma-separated for several brokers).
+	URL string `yaml:"url"`
+	// Subject is the NATS subject or Kafka topic.
+	Subject string            `yaml:"subject"`
+	Headers map[string]string `yaml:"headers"`
+	// EventTypes, Providers and Symbols filter forwarded events; empty lists match everything.
+	EventTypes []string `yaml:"eventTypes"`
+	Providers  []string `yaml:"providers"`
+	Symbols    []string `yaml:"symbols"`
+	// InstrumentChangesOnly drops InstrumentUpdate events that carry no catalogue changes, so the
+	// sink only receives tick size, lot size, filter and listing status alerts.
+	InstrumentChangesOnly bool          `yaml:"instrumentChangesOnly"`
+	BatchSize             int           `yaml:"batchSize"`
+	FlushInterval         time.Duration `yaml:"flushInterval"`
+	MaxRetries            int           `yaml:"maxRetries"`
+	Timeout               time.Duration `yaml:"timeout"`
+	BufferSize            int           `yaml:"bufferSize"`
+}
+
+func (c *SinkConfig) applyDefaults() {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Type = SinkType(strings.ToLower(strings.TrimSpace(string(c.Type))))
+	c.URL = strings.TrimSpace(c.URL)
+	c.Subject = strings.TrimSpace(c.Subject)
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultSinkBatchSize
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = defaultSinkFlushInterval
+	}
+	if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	} else if c.MaxRetries == 0 {
+		c.MaxRetries = defaultSinkMaxRetries
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultSinkTimeout
+	}
+	if c.BufferSize <= 0 {
+		c.BufferSize = defaultSinkBufferSize
+	}
+}
+
+func (c SinkConfig) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("name required")
+	}
+	switch c.Type {
+	case SinkTypeWebhook:
+		if c.URL == "" {
+			return fmt.Errorf("url required")
+		}
+	case SinkTypeNATS, SinkTypeKafka:
+		if c.URL == "" {
+			return fmt.Errorf("url required")
+		}
+		if c.Subject == "" {
+			return fmt.Errorf("subject required")
+		}
+	default:
+		return fmt.Errorf("type must be one of webhook, nats, kafka")
+	}
+	return nil
+}
+
+func validateSinks(sinks []SinkConfig) error {
+	seen := make(map[string]struct{}, len(sinks))
+	for i, sink := range sinks {
+		if err := sink.validate(); err != nil {
+			return fmt.Errorf("sinks[%d]: %w", i, err)
+		}
+		if _, ok := seen[sink.Name]; ok {
+			return fmt.Errorf("sinks[%d]: duplicate name %q", i, sink.Name)
+		}
+		seen[sink.Name] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultStartupMaxWait        = 2 * time.Minute
+	defaultStartupInitialBackoff = time.Second
+	defaultStartupMaxBackoff     = 15 * time.Second
+)
+
+// StartupTimeoutPolicy selects what the gateway does when a dependency is still unreachable once
+// the startup wait budget is spent.
+type StartupTimeoutPolicy string
+
+const (
+	// StartupTimeoutFail exits, as the gateway does without waiting.
+	StartupTimeoutFail StartupTimeoutPolicy = "fail"
+	// StartupTimeoutDegrade starts without the dependency: safe mode without a database, metrics
+	// export disabled without a telemetry collector.
+	StartupTimeoutDegrade StartupTimeoutPolicy = "degrade"
+)
+
+// StartupConfig makes the gateway wait for Postgres and the OTLP collector at startup, retrying
+// with exponential backoff, so dependencies that come up shortly after the gateway do not need an
+// orchestrator restart.
+type StartupConfig struct {
+	WaitForDatabase  bool `yaml:"waitForDatabase"`
+	WaitForTelemetry bool `yaml:"waitForTelemetry"`
+	// MaxWait is the budget shared by every dependency wait.
+	MaxWait        time.Duration `yaml:"maxWait"`
+	InitialBackoff time.Duration `yaml:"initialBackoff"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff"`
+	// OnTimeout applies once MaxWait is spent: fail (default) or degrade.
+	OnTimeout StartupTimeoutPolicy `yaml:"onTimeout"`
+}
+
+func (c *StartupConfig) applyDefaults() {
+	if c.MaxWait <= 0 {
+		c.MaxWait = defaultStartupMaxWait
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = defaultStartupInitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaultStartupMaxBackoff
+	}
+	c.OnTimeout = StartupTimeoutPolicy(strings.ToLower(strings.TrimSpace(string(c.OnTimeout))))
+	if c.OnTimeout == "" {
+		c.OnTimeout = StartupTimeoutFail
+	}
+}
+
+func (c StartupConfig) validate() error {
+	if c.MaxBackoff < c.InitialBackoff {
+		return fmt.Errorf("maxBackoff must be at least initialBackoff")
+	}
+	switch c.OnTimeout {
+	case StartupTimeoutFail, StartupTimeoutDegrade:
+		return nil
+	default:
+		return fmt.Errorf("onTimeout must be fail or degrade")
+	}
+}
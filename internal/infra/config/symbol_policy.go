@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SymbolListConfig lists symbol patterns to allow and to deny. Patterns are matched
+// case-insensitively and may use * as a wildcard, e.g. XMR-* for every XMR pair.
+type SymbolListConfig struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// SymbolPolicyConfig bars symbols from trading through the gateway on compliance grounds. The
+// global lists apply to every provider and Providers adds lists for single providers. A deny match
+// always rejects the order; a non-empty allow list rejects every symbol it does not match.
+type SymbolPolicyConfig struct {
+	Allow     []string                    `yaml:"allow"`
+	Deny      []string                    `yaml:"deny"`
+	Providers map[string]SymbolListConfig `yaml:"providers"`
+}
+
+func (c SymbolPolicyConfig) validate() error {
+	if err := validateSymbolPatterns("", c.Allow, c.Deny); err != nil {
+		return err
+	}
+	for name, lists := range c.Providers {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("provider name required")
+		}
+		if err := validateSymbolPatterns(name+" ", lists.Allow, lists.Deny); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSymbolPatterns(scope string, lists ...[]string) error {
+	for _, patterns := range lists {
+		for _, pattern := range patterns {
+			if _, err := path.Match(strings.TrimSpace(pattern), ""); err != nil {
+				return fmt.Errorf("%ssymbol pattern %q is malformed", scope, pattern)
+			}
+		}
+	}
+	return nil
+}
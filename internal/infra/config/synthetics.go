@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// SyntheticKind selects how a synthetic instrument combines its legs.
+type SyntheticKind string
+
+const (
+	// SyntheticKindSpread prices the first leg minus the second, each scaled by its weight.
+	SyntheticKindSpread SyntheticKind = "spread"
+	// SyntheticKindBasket prices the weighted sum of all legs.
+	SyntheticKindBasket SyntheticKind = "basket"
+)
+
+// SyntheticInstrumentConfig defines an instrument whose tickers the gateway computes from
+// component feeds.
+type SyntheticInstrumentConfig struct {
+	Symbol string `yaml:"symbol"`
+	// Provider is stamped on the published events so instances scope the synthetic symbol
+	// under it. Defaults to the first leg's provider.
+	Provider string         `yaml:"provider"`
+	Kind     SyntheticKind  `yaml:"kind"`
+	Legs     []SyntheticLeg `yaml:"legs"`
+}
+
+// SyntheticLeg is one component feed of a synthetic instrument.
+type SyntheticLeg struct {
+	Provider string `yaml:"provider"`
+	Symbol   string `yaml:"symbol"`
+	// Weight scales the leg price; empty means 1. Basket weights may be negative.
+	Weight string `yaml:"weight"`
+}
+
+// Coefficients returns the signed multiplier applied to each leg price.
+func (c SyntheticInstrumentConfig) Coefficients() []decimal.Decimal {
+	out := make([]decimal.Decimal, len(c.Legs))
+	for i, leg := range c.Legs {
+		weight := decimal.NewFromInt(1)
+		if leg.Weight != "" {
+			if parsed, err := decimal.NewFromString(leg.Weight); err == nil {
+				weight = parsed
+			}
+		}
+		if c.Kind == SyntheticKindSpread && i == 1 {
+			weight = weight.Neg()
+		}
+		out[i] = weight
+	}
+	return out
+}
+
+func (c *SyntheticInstrumentConfig) applyDefaults() {
+	c.Symbol = strings.ToUpper(strings.TrimSpace(c.Symbol))
+	c.Provider = strings.TrimSpace(c.Provider)
+	c.Kind = SyntheticKind(strings.ToLower(strings.TrimSpace(string(c.Kind))))
+	for i := range c.Legs {
+		c.Legs[i].Provider = strings.TrimSpace(c.Legs[i].Provider)
+		c.Legs[i].Symbol = strings.ToUpper(strings.TrimSpace(c.Legs[i].Symbol))
+		c.Legs[i].Weight = strings.TrimSpace(c.Legs[i].Weight)
+	}
+	if c.Provider == "" && len(c.Legs) > 0 {
+		c.Provider = c.Legs[0].Provider
+	}
+}
+
+func (c SyntheticInstrumentConfig) validate() error {
+	if c.Symbol == "" {
+		return fmt.Errorf("symbol required")
+	}
+	switch c.Kind {
+	case SyntheticKindSpread:
+		if len(c.Legs) != 2 {
+			return fmt.Errorf("spread requires exactly 2 legs")
+		}
+	case SyntheticKindBasket:
+		if len(c.Legs) == 0 {
+			return fmt.Errorf("basket requires at least 1 leg")
+		}
+	default:
+		return fmt.Errorf("kind must be one of spread, basket")
+	}
+	for i, leg := range c.Legs {
+		if leg.Provider == "" {
+			return fmt.Errorf("legs[%d]: provider required", i)
+		}
+		if leg.Symbol == "" {
+			return fmt.Errorf("legs[%d]: symbol required", i)
+		}
+		if leg.Weight == "" {
+			continue
+		}
+		weight, err := decimal.NewFromString(leg.Weight)
+		if err != nil || weight.IsZero() {
+			return fmt.Errorf("legs[%d]: weight must be a non-zero decimal", i)
+		}
+		if c.Kind == SyntheticKindSpread && weight.IsNegative() {
+			return fmt.Errorf("legs[%d]: spread weights must be positive", i)
+		}
+	}
+	return nil
+}
+
+func validateSynthetics(synthetics []SyntheticInstrumentConfig) error {
+	seen := make(map[string]struct{}, len(synthetics))
+	for _, syn := range synthetics {
+		seen[syn.Provider+"/"+syn.Symbol] = struct{}{}
+	}
+	declared := make(map[string]struct{}, len(synthetics))
+	for i, syn := range synthetics {
+		if err := syn.validate(); err != nil {
+			return fmt.Errorf("synthetics[%d]: %w", i, err)
+		}
+		key := syn.Provider + "/" + syn.Symbol
+		if _, ok := declared[key]; ok {
+			return fmt.Errorf("synthetics[%d]: duplicate symbol %q for provider %q", i, syn.Symbol, syn.Provider)
+		}
+		declared[key] = struct{}{}
+		for j, leg := range syn.Legs {
+			if _, ok := seen[leg.Provider+"/"+leg.Symbol]; ok {
+				return fmt.Errorf("synthetics[%d]: legs[%d]: %s is itself synthetic", i, j, leg.Symbol)
+			}
+		}
+	}
+	return nil
+}
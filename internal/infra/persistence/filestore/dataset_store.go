@@ -0,0 +1,272 @@
+// Package filestore keeps datasets on the local filesystem.
+package filestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/google/uuid"
+
+	"github.com/coachpo/meltica/internal/domain/datasetstore"
+)
+
+const (
+	metadataFile = "dataset.json"
+	stagingDir   = ".staging"
+)
+
+// DatasetStore keeps each dataset in a directory named by its identifier, holding the content
+// file and a dataset.json metadata file. Uploads are staged and moved into place once complete, so
+// readers never see partial datasets.
+type DatasetStore struct {
+	dir string
+	now func() time.Time
+	// mu serializes metadata changes; content is written outside it.
+	mu sync.Mutex
+}
+
+var _ datasetstore.Store = (*DatasetStore)(nil)
+
+// NewDatasetStore creates dir when missing and returns a store rooted there.
+func NewDatasetStore(dir string) (*DatasetStore, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return nil, fmt.Errorf("dataset directory required")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, stagingDir), 0o750); err != nil {
+		return nil, fmt.Errorf("create dataset directory: %w", err)
+	}
+	return &DatasetStore{dir: dir, now: time.Now, mu: sync.Mutex{}}, nil
+}
+
+// Create stages content, then publishes it under a new identifier unless the name is taken.
+func (s *DatasetStore) Create(ctx context.Context, dataset datasetstore.Dataset, content io.Reader) (datasetstore.Dataset, error) {
+	var empty datasetstore.Dataset
+	if err := datasetstore.Normalize(&dataset); err != nil {
+		return empty, err
+	}
+	dataset.ID = uuid.NewString()
+	staging := filepath.Join(s.dir, stagingDir, dataset.ID)
+	if err := os.MkdirAll(staging, 0o750); err != nil {
+		return empty, fmt.Errorf("stage dataset: %w", err)
+	}
+	published := false
+	defer func() {
+		if !published {
+			_ = os.RemoveAll(staging)
+		}
+	}()
+
+	size, checksum, err := writeContent(ctx, filepath.Join(staging, datasetstore.FileName(dataset.Format)), content)
+	if err != nil {
+		return empty, err
+	}
+	now := s.now().UTC()
+	dataset.SizeBytes = size
+	dataset.SHA256 = checksum
+	dataset.CreatedAt = now
+	dataset.UpdatedAt = now
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, err := s.listLocked()
+	if err != nil {
+		return empty, err
+	}
+	for _, other := range existing {
+		if other.Name == dataset.Name {
+			return empty, datasetstore.ErrNameTaken
+		}
+	}
+	if err := writeMetadata(staging, dataset); err != nil {
+		return empty, err
+	}
+	if err := os.Rename(staging, filepath.Join(s.dir, dataset.ID)); err != nil {
+		return empty, fmt.Errorf("publish dataset: %w", err)
+	}
+	published = true
+	return dataset, nil
+}
+
+// List returns the datasets matching query, oldest first.
+func (s *DatasetStore) List(_ context.Context, query datasetstore.Query) ([]datasetstore.Dataset, error) {
+	s.mu.Lock()
+	all, err := s.listLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]datasetstore.Dataset, 0, len(all))
+	for _, dataset := range all {
+		if query.Matches(dataset) {
+			out = append(out, dataset)
+		}
+	}
+	return out, nil
+}
+
+// Get returns the metadata of a dataset.
+func (s *DatasetStore) Get(_ context.Context, id string) (datasetstore.Dataset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.getLocked(id)
+}
+
+// Open returns the content of a dataset.
+func (s *DatasetStore) Open(_ context.Context, id string) (io.ReadCloser, datasetstore.Dataset, error) {
+	s.mu.Lock()
+	dataset, err := s.getLocked(id)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, dataset, err
+	}
+	file, err := os.Open(filepath.Join(s.dir, dataset.ID, datasetstore.FileName(dataset.Format))) // #nosec G304 -- identifier validated as a UUID
+	if err != nil {
+		return nil, dataset, fmt.Errorf("open dataset: %w", err)
+	}
+	return file, dataset, nil
+}
+
+// SetTags replaces the tags of a dataset.
+func (s *DatasetStore) SetTags(_ context.Context, id string, tags []string) (datasetstore.Dataset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dataset, err := s.getLocked(id)
+	if err != nil {
+		return dataset, err
+	}
+	dataset.Tags = datasetstore.NormalizeTags(tags)
+	dataset.UpdatedAt = s.now().UTC()
+	if err := writeMetadata(filepath.Join(s.dir, dataset.ID), dataset); err != nil {
+		return datasetstore.Dataset{}, err
+	}
+	return dataset, nil
+}
+
+// Delete removes a dataset and its content.
+func (s *DatasetStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dataset, err := s.getLocked(id)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(s.dir, dataset.ID)); err != nil {
+		return fmt.Errorf("delete dataset: %w", err)
+	}
+	return nil
+}
+
+func (s *DatasetStore) getLocked(id string) (datasetstore.Dataset, error) {
+	var empty datasetstore.Dataset
+	parsed, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return empty, datasetstore.ErrNotFound
+	}
+	return readMetadata(filepath.Join(s.dir, parsed.String()))
+}
+
+func (s *DatasetStore) listLocked() ([]datasetstore.Dataset, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("list datasets: %w", err)
+	}
+	out := make([]datasetstore.Dataset, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		dataset, err := readMetadata(filepath.Join(s.dir, entry.Name()))
+		if errors.Is(err, datasetstore.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, dataset)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func writeContent(ctx context.Context, path string, content io.Reader) (int64, string, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640) // #nosec G304 -- path built from a generated identifier
+	if err != nil {
+		return 0, "", fmt.Errorf("create dataset file: %w", err)
+	}
+	hash := sha256.New()
+	size, copyErr := io.Copy(io.MultiWriter(file, hash), contextReader{ctx: ctx, r: content})
+	closeErr := file.Close()
+	if copyErr != nil {
+		return 0, "", fmt.Errorf("write dataset: %w", copyErr)
+	}
+	if closeErr != nil {
+		return 0, "", fmt.Errorf("close dataset file: %w", closeErr)
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func readMetadata(dir string) (datasetstore.Dataset, error) {
+	var dataset datasetstore.Dataset
+	raw, err := os.ReadFile(filepath.Join(dir, metadataFile)) // #nosec G304 -- path built from a validated identifier
+	if errors.Is(err, fs.ErrNotExist) {
+		return dataset, datasetstore.ErrNotFound
+	}
+	if err != nil {
+		return dataset, fmt.Errorf("read dataset metadata: %w", err)
+	}
+	if err := json.Unmarshal(raw, &dataset); err != nil {
+		return dataset, fmt.Errorf("decode dataset metadata: %w", err)
+	}
+	return dataset, nil
+}
+
+// writeMetadata replaces the metadata file atomically.
+func writeMetadata(dir string, dataset datasetstore.Dataset) error {
+	raw, err := json.MarshalIndent(dataset, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode dataset metadata: %w", err)
+	}
+	tmp := filepath.Join(dir, metadataFile+".tmp")
+	if err := os.WriteFile(tmp, raw, 0o640); err != nil {
+		return fmt.Errorf("write dataset metadata: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, metadataFile)); err != nil {
+		return fmt.Errorf("write dataset metadata: %w", err)
+	}
+	return nil
+}
+
+// contextReader stops a long upload once ctx ends.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := c.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, fmt.Errorf("read upload: %w", err)
+	}
+	return n, err //nolint:wrapcheck // io.EOF must reach io.Copy unwrapped
+}
@@ -0,0 +1,68 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/datasetstore"
+)
+
+func TestDatasetStoreLifecycle(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewDatasetStore(dir)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+
+	content := "trade_id,timestamp,side,price,quantity\n1,1700000000000,buy,100,1\n"
+	created, err := store.Create(ctx, datasetstore.Dataset{Name: "btc-trades", Provider: "Binance", Symbol: "btc-usdt", Tags: []string{"q4", " q4", ""}}, strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.Format != datasetstore.FormatCSV || created.SizeBytes != int64(len(content)) || created.Provider != "binance" || created.Symbol != "BTC-USDT" || len(created.Tags) != 1 {
+		t.Fatalf("unexpected dataset %+v", created)
+	}
+	if _, err := store.Create(ctx, datasetstore.Dataset{Name: "btc-trades"}, strings.NewReader("x")); !errors.Is(err, datasetstore.ErrNameTaken) {
+		t.Fatalf("expected name conflict, got %v", err)
+	}
+	if staged, _ := os.ReadDir(filepath.Join(dir, stagingDir)); len(staged) != 0 {
+		t.Fatalf("expected the rejected upload unstaged, found %d entries", len(staged))
+	}
+
+	reader, _, err := store.Open(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	raw, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(raw) != content {
+		t.Fatalf("unexpected content %q", raw)
+	}
+
+	if _, err := store.SetTags(ctx, created.ID, []string{"golden", "q4"}); err != nil {
+		t.Fatalf("set tags: %v", err)
+	}
+	listed, err := store.List(ctx, datasetstore.Query{Tag: "golden"})
+	if err != nil || len(listed) != 1 || listed[0].ID != created.ID {
+		t.Fatalf("unexpected list %+v %v", listed, err)
+	}
+	if listed, _ := store.List(ctx, datasetstore.Query{Provider: "okx"}); len(listed) != 0 {
+		t.Fatalf("expected provider filter to exclude the dataset")
+	}
+
+	if err := store.Delete(ctx, created.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, created.ID); !errors.Is(err, datasetstore.ErrNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	if _, err := store.Get(ctx, "../etc"); !errors.Is(err, datasetstore.ErrNotFound) {
+		t.Fatalf("expected invalid identifiers rejected, got %v", err)
+	}
+}
@@ -8,6 +8,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/jackc/pgx/v5"
+
+	dbmigrations "github.com/coachpo/meltica/db/migrations"
 )
 
 func TestResolveDirSuccess(t *testing.T) {
@@ -94,3 +98,51 @@ func TestRollbackValidatesPathBeforeConnecting(t *testing.T) {
 		t.Fatalf("expected missing directory error, got %v", err)
 	}
 }
+
+type stubRow struct {
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (r stubRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int64) = r.version
+	*dest[1].(*bool) = r.dirty
+	return nil
+}
+
+type stubQuerier struct{ row stubRow }
+
+func (q stubQuerier) QueryRow(context.Context, string, ...any) pgx.Row { return q.row }
+
+func TestCurrentVersion(t *testing.T) {
+	matches, err := fs.Glob(dbmigrations.Files, "*.up.sql")
+	if err != nil || len(matches) == 0 {
+		t.Fatalf("expected embedded migrations, got %v (%v)", matches, err)
+	}
+	latest, err := LatestVersion()
+	if err != nil {
+		t.Fatalf("LatestVersion: %v", err)
+	}
+	if want := uint(len(matches)); latest != want {
+		t.Fatalf("latest version = %d, want %d", latest, want)
+	}
+
+	version, err := CurrentVersion(context.Background(), stubQuerier{row: stubRow{version: 7, dirty: true}})
+	if err != nil {
+		t.Fatalf("CurrentVersion: %v", err)
+	}
+	if version.Version != 7 || !version.Dirty || version.Latest != latest {
+		t.Fatalf("unexpected version %+v", version)
+	}
+	version, err = CurrentVersion(context.Background(), stubQuerier{row: stubRow{err: pgx.ErrNoRows}})
+	if err != nil || version.Version != 0 {
+		t.Fatalf("expected unmigrated database at version 0, got %+v (%v)", version, err)
+	}
+	if _, err := CurrentVersion(context.Background(), stubQuerier{row: stubRow{err: errors.New("relation does not exist")}}); err == nil {
+		t.Fatal("expected query error")
+	}
+}
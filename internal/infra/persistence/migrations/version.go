@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	dbmigrations "github.com/coachpo/meltica/db/migrations"
+)
+
+// versionTable is the bookkeeping table golang-migrate maintains in the target database.
+const versionTable = "schema_migrations"
+
+// SchemaVersion reports the migration state of a database against the migrations bundled into
+// the binary.
+type SchemaVersion struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"`
+	Latest  uint `json:"latest"`
+}
+
+// RowQuerier is satisfied by pgx pools, connections and transactions.
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// CurrentVersion reads the applied migration version from db. A database that has never been
+// migrated reports version 0.
+func CurrentVersion(ctx context.Context, db RowQuerier) (SchemaVersion, error) {
+	latest, err := LatestVersion()
+	if err != nil {
+		return SchemaVersion{Version: 0, Dirty: false, Latest: 0}, err
+	}
+	version := SchemaVersion{Version: 0, Dirty: false, Latest: latest}
+	if db == nil {
+		return version, fmt.Errorf("schema version: database unavailable")
+	}
+	var applied int64
+	row := db.QueryRow(ctx, "SELECT version, dirty FROM "+versionTable+" LIMIT 1")
+	if err := row.Scan(&applied, &version.Dirty); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return version, nil
+		}
+		return version, fmt.Errorf("schema version: %w", err)
+	}
+	if applied > 0 {
+		version.Version = uint(applied)
+	}
+	return version, nil
+}
+
+// LatestVersion returns the highest migration version embedded in the binary.
+func LatestVersion() (uint, error) {
+	entries, err := fs.ReadDir(dbmigrations.Files, embeddedMigrationsRoot)
+	if err != nil {
+		return 0, fmt.Errorf("read embedded migrations: %w", err)
+	}
+	var latest uint
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok || !strings.HasSuffix(entry.Name(), ".up.sql") {
+			continue
+		}
+		parsed, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		latest = max(latest, uint(parsed))
+	}
+	return latest, nil
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/calendarstore"
+	"github.com/coachpo/meltica/internal/infra/persistence/postgres/sqlc"
+	json "github.com/goccy/go-json"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CalendarStore persists scheduled calendar entries in PostgreSQL.
+type CalendarStore struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+// NewCalendarStore constructs a CalendarStore backed by the provided pgx pool.
+func NewCalendarStore(pool *pgxpool.Pool) *CalendarStore {
+	if pool == nil {
+		return &CalendarStore{pool: nil, queries: nil}
+	}
+	return &CalendarStore{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+func (s *CalendarStore) ensureQueries() (*sqlc.Queries, error) {
+	if s.pool == nil || s.queries == nil {
+		return nil, fmt.Errorf("calendar store: nil pool")
+	}
+	return s.queries, nil
+}
+
+// SaveEntry upserts a calendar entry together with its audit trail.
+func (s *CalendarStore) SaveEntry(ctx context.Context, entry calendarstore.Entry) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	id := strings.TrimSpace(entry.ID)
+	if id == "" {
+		return fmt.Errorf("calendar store: entry id required")
+	}
+	action, err := encodeJSON(entry.Action)
+	if err != nil {
+		return fmt.Errorf("marshal calendar action: %w", err)
+	}
+	audit := []byte("[]")
+	if len(entry.Audit) > 0 {
+		audit, err = json.Marshal(entry.Audit)
+		if err != nil {
+			return fmt.Errorf("marshal calendar audit: %w", err)
+		}
+	}
+	if err := q.UpsertCalendarEntry(ctx, sqlc.UpsertCalendarEntryParams{
+		ID:          id,
+		Title:       strings.TrimSpace(entry.Title),
+		ScheduledAt: timestamptzFromTime(entry.ScheduledAt),
+		NotifyAt:    timestamptzFromTimePtr(entry.NotifyAt),
+		Action:      action,
+		Status:      entry.Status,
+		NotifiedAt:  timestamptzFromTimePtr(entry.NotifiedAt),
+		AppliedAt:   timestamptzFromTimePtr(entry.AppliedAt),
+		Error:       entry.Error,
+		Audit:       audit,
+		CreatedAt:   timestamptzFromTime(entry.CreatedAt),
+	}); err != nil {
+		return fmt.Errorf("upsert calendar entry: %w", err)
+	}
+	return nil
+}
+
+// LoadEntries retrieves all calendar entries ordered by scheduled time.
+func (s *CalendarStore) LoadEntries(ctx context.Context) ([]calendarstore.Entry, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.ListCalendarEntries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list calendar entries: %w", err)
+	}
+	entries := make([]calendarstore.Entry, 0, len(rows))
+	for _, row := range rows {
+		action, err := decodeJSON(row.Action)
+		if err != nil {
+			return nil, fmt.Errorf("decode calendar entry %s action: %w", row.ID, err)
+		}
+		var audit []calendarstore.AuditRecord
+		if len(row.Audit) > 0 {
+			if err := json.Unmarshal(row.Audit, &audit); err != nil {
+				return nil, fmt.Errorf("decode calendar entry %s audit: %w", row.ID, err)
+			}
+		}
+		entries = append(entries, calendarstore.Entry{
+			ID:          row.ID,
+			Title:       row.Title,
+			ScheduledAt: row.ScheduledAt.Time,
+			NotifyAt:    timePtrFromTimestamptz(row.NotifyAt),
+			Action:      action,
+			Status:      row.Status,
+			NotifiedAt:  timePtrFromTimestamptz(row.NotifiedAt),
+			AppliedAt:   timePtrFromTimestamptz(row.AppliedAt),
+			Error:       row.Error,
+			Audit:       audit,
+			CreatedAt:   row.CreatedAt.Time,
+		})
+	}
+	return entries, nil
+}
+
+func timestamptzFromTimePtr(value *time.Time) pgtype.Timestamptz {
+	if value == nil {
+		return nullTimestamptz()
+	}
+	return timestamptzFromTime(*value)
+}
+
+func timePtrFromTimestamptz(value pgtype.Timestamptz) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	out := value.Time
+	return &out
+}
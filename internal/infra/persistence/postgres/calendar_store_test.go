@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/calendarstore"
+)
+
+func TestCalendarStoreNilPool(t *testing.T) {
+	store := NewCalendarStore(nil)
+	ctx := context.Background()
+	if err := store.SaveEntry(ctx, calendarstore.Entry{ID: "fomc"}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.LoadEntries(ctx); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+}
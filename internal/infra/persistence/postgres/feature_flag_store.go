@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/domain/flagstore"
+	"github.com/coachpo/meltica/internal/infra/persistence/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FeatureFlagStore persists runtime feature flag overrides in PostgreSQL.
+type FeatureFlagStore struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+// NewFeatureFlagStore constructs a FeatureFlagStore backed by the provided pgx pool.
+func NewFeatureFlagStore(pool *pgxpool.Pool) *FeatureFlagStore {
+	if pool == nil {
+		return &FeatureFlagStore{pool: nil, queries: nil}
+	}
+	return &FeatureFlagStore{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+func (s *FeatureFlagStore) ensureQueries() (*sqlc.Queries, error) {
+	if s.pool == nil || s.queries == nil {
+		return nil, fmt.Errorf("feature flag store: nil pool")
+	}
+	return s.queries, nil
+}
+
+// SaveFlag upserts a feature flag override.
+func (s *FeatureFlagStore) SaveFlag(ctx context.Context, flag flagstore.Flag) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSpace(flag.Name)
+	if name == "" {
+		return fmt.Errorf("feature flag store: flag name required")
+	}
+	if err := q.UpsertFeatureFlag(ctx, sqlc.UpsertFeatureFlagParams{
+		Name:    name,
+		Enabled: flag.Enabled,
+	}); err != nil {
+		return fmt.Errorf("upsert feature flag: %w", err)
+	}
+	return nil
+}
+
+// DeleteFlag removes a feature flag override.
+func (s *FeatureFlagStore) DeleteFlag(ctx context.Context, name string) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return fmt.Errorf("feature flag store: flag name required")
+	}
+	if err := q.DeleteFeatureFlag(ctx, trimmed); err != nil {
+		return fmt.Errorf("delete feature flag: %w", err)
+	}
+	return nil
+}
+
+// LoadFlags retrieves all stored feature flag overrides.
+func (s *FeatureFlagStore) LoadFlags(ctx context.Context) ([]flagstore.Flag, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.ListFeatureFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list feature flags: %w", err)
+	}
+	flags := make([]flagstore.Flag, 0, len(rows))
+	for _, row := range rows {
+		flags = append(flags, flagstore.Flag{
+			Name:      row.Name,
+			Enabled:   row.Enabled,
+			UpdatedAt: row.UpdatedAt.Time,
+		})
+	}
+	return flags, nil
+}
@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/flagstore"
+)
+
+func TestFeatureFlagStoreNilPool(t *testing.T) {
+	store := NewFeatureFlagStore(nil)
+	ctx := context.Background()
+	if err := store.SaveFlag(ctx, flagstore.Flag{Name: "durable_subscriptions", Enabled: true}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if err := store.DeleteFlag(ctx, "durable_subscriptions"); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.LoadFlags(ctx); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+}
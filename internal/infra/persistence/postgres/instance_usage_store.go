@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/coachpo/meltica/internal/domain/usagestore"
+	"github.com/coachpo/meltica/internal/infra/persistence/postgres/sqlc"
+)
+
+// InstanceUsageStore persists the daily resource usage of strategy instances in PostgreSQL.
+type InstanceUsageStore struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+// NewInstanceUsageStore constructs an InstanceUsageStore backed by the provided pgx pool.
+func NewInstanceUsageStore(pool *pgxpool.Pool) *InstanceUsageStore {
+	if pool == nil {
+		return &InstanceUsageStore{pool: nil, queries: nil}
+	}
+	return &InstanceUsageStore{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+func (s *InstanceUsageStore) ensureQueries() (*sqlc.Queries, error) {
+	if s.pool == nil || s.queries == nil {
+		return nil, fmt.Errorf("instance usage store: nil pool")
+	}
+	return s.queries, nil
+}
+
+// AddUsage adds the records to the stored days in one transaction.
+func (s *InstanceUsageStore) AddUsage(ctx context.Context, records []usagestore.Record) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:       pgx.ReadCommitted,
+		AccessMode:     pgx.ReadWrite,
+		DeferrableMode: pgx.NotDeferrable,
+		BeginQuery:     "",
+		CommitQuery:    "",
+	})
+	if err != nil {
+		return fmt.Errorf("begin usage tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	qtx := q.WithTx(tx)
+	for _, record := range records {
+		labels := record.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		payload, err := json.Marshal(labels)
+		if err != nil {
+			return fmt.Errorf("marshal usage labels: %w", err)
+		}
+		if err := qtx.AddInstanceUsage(ctx, sqlc.AddInstanceUsageParams{
+			Day:        dateFromTime(record.Day),
+			InstanceID: record.Instance,
+			Strategy:   record.Strategy,
+			Labels:     payload,
+			Events:     record.Events,
+			Orders:     record.Orders,
+			HandlerUs:  record.HandlerTime.Microseconds(),
+		}); err != nil {
+			return fmt.Errorf("add usage of %s: %w", record.Instance, err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit usage tx: %w", err)
+	}
+	return nil
+}
+
+// ListUsage returns the stored days within the query.
+func (s *InstanceUsageStore) ListUsage(ctx context.Context, query usagestore.Query) ([]usagestore.Record, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.ListInstanceUsage(ctx, sqlc.ListInstanceUsageParams{
+		FromDay: dateFromTime(query.From),
+		ToDay:   dateFromTime(query.To),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list instance usage: %w", err)
+	}
+	records := make([]usagestore.Record, 0, len(rows))
+	for _, row := range rows {
+		var labels map[string]string
+		if len(row.Labels) > 0 {
+			if err := json.Unmarshal(row.Labels, &labels); err != nil {
+				return nil, fmt.Errorf("decode usage labels of %s: %w", row.InstanceID, err)
+			}
+		}
+		if len(labels) == 0 {
+			labels = nil
+		}
+		records = append(records, usagestore.Record{
+			Day:         row.Day.Time.UTC(),
+			Instance:    row.InstanceID,
+			Strategy:    row.Strategy,
+			Labels:      labels,
+			Events:      row.Events,
+			Orders:      row.Orders,
+			HandlerTime: time.Duration(row.HandlerUs) * time.Microsecond,
+		})
+	}
+	return records, nil
+}
+
+// PurgeUsage deletes days before the cutoff.
+func (s *InstanceUsageStore) PurgeUsage(ctx context.Context, before time.Time) (int64, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return 0, err
+	}
+	removed, err := q.DeleteInstanceUsageBefore(ctx, dateFromTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("purge instance usage: %w", err)
+	}
+	return removed, nil
+}
+
+func dateFromTime(value time.Time) pgtype.Date {
+	utc := value.UTC()
+	return pgtype.Date{
+		Time:             time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC),
+		InfinityModifier: pgtype.Finite,
+		Valid:            true,
+	}
+}
+
+var _ usagestore.Store = (*InstanceUsageStore)(nil)
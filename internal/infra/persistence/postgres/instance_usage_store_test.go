@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/usagestore"
+)
+
+func TestInstanceUsageStoreNilPool(t *testing.T) {
+	store := NewInstanceUsageStore(nil)
+	ctx := context.Background()
+	if err := store.AddUsage(ctx, []usagestore.Record{{Instance: "alpha", Events: 1}}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.ListUsage(ctx, usagestore.Query{From: time.Now(), To: time.Now()}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.PurgeUsage(ctx, time.Now()); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+}
+
+func TestDateFromTimeTruncatesToUTCDay(t *testing.T) {
+	local := time.Date(2026, 10, 16, 1, 30, 0, 0, time.FixedZone("UTC+3", 3*3600))
+	got := dateFromTime(local)
+	if !got.Valid || !got.Time.Equal(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected date %+v", got)
+	}
+}
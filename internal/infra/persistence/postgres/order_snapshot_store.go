@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/infra/persistence/postgres/sqlc"
+	json "github.com/goccy/go-json"
+)
+
+// SaveOpenOrderSnapshot replaces the open order snapshot stored for the provider.
+func (s *OrderStore) SaveOpenOrderSnapshot(ctx context.Context, snapshot orderstore.OpenOrderSnapshot) error {
+	queries, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	provider := strings.TrimSpace(snapshot.Provider)
+	if provider == "" {
+		return fmt.Errorf("order store: provider alias required")
+	}
+	orders := snapshot.Orders
+	if orders == nil {
+		orders = []orderstore.OrderRecord{}
+	}
+	encoded, err := json.Marshal(orders)
+	if err != nil {
+		return fmt.Errorf("order store: encode open order snapshot: %w", err)
+	}
+	if err := queries.UpsertOpenOrderSnapshot(ctx, sqlc.UpsertOpenOrderSnapshotParams{
+		Provider: provider,
+		TakenAt:  timestamptzFromUnix(snapshot.TakenAt),
+		Orders:   encoded,
+	}); err != nil {
+		return fmt.Errorf("order store: save open order snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadOpenOrderSnapshots returns every stored open order snapshot.
+func (s *OrderStore) LoadOpenOrderSnapshots(ctx context.Context) ([]orderstore.OpenOrderSnapshot, error) {
+	queries, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := queries.ListOpenOrderSnapshots(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("order store: list open order snapshots: %w", err)
+	}
+	snapshots := make([]orderstore.OpenOrderSnapshot, 0, len(rows))
+	for _, row := range rows {
+		var orders []orderstore.OrderRecord
+		if len(row.Orders) > 0 {
+			if err := json.Unmarshal(row.Orders, &orders); err != nil {
+				return nil, fmt.Errorf("order store: decode open order snapshot %s: %w", row.Provider, err)
+			}
+		}
+		snapshots = append(snapshots, orderstore.OpenOrderSnapshot{
+			Provider: row.Provider,
+			TakenAt:  timestamptzToUnix(row.TakenAt),
+			Orders:   orders,
+		})
+	}
+	return snapshots, nil
+}
+
+// DeleteOpenOrderSnapshot drops the snapshot of a provider once it has been reconciled.
+func (s *OrderStore) DeleteOpenOrderSnapshot(ctx context.Context, provider string) error {
+	queries, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	trimmed := strings.TrimSpace(provider)
+	if trimmed == "" {
+		return fmt.Errorf("order store: provider alias required")
+	}
+	if err := queries.DeleteOpenOrderSnapshot(ctx, trimmed); err != nil {
+		return fmt.Errorf("order store: delete open order snapshot: %w", err)
+	}
+	return nil
+}
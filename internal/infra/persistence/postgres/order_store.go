@@ -316,6 +316,9 @@ func (s *OrderStore) ListOrders(ctx context.Context, query orderstore.OrderQuery
 		StrategyInstance: textFromString(query.StrategyInstance),
 		ProviderAlias:    textFromString(query.Provider),
 		States:           normalizedStates(query.States),
+		PlacedSince:      timestamptzFromUnix(query.Since),
+		PlacedUntil:      timestamptzFromUnix(query.Until),
+		Tag:              textFromString(query.Tag),
 		Limit:            safeInt32(limit),
 	}
 	rows, err := queries.ListOrders(ctx, params)
@@ -358,6 +361,7 @@ func (s *OrderStore) ListOrders(ctx context.Context, query orderstore.OrderQuery
 			CompletedAt:    timestamptzToUnixPtr(row.CompletedAt),
 			CreatedAt:      row.CreatedAt.Time.Unix(),
 			UpdatedAt:      row.UpdatedAt.Time.Unix(),
+			Fills:          orderstore.FillSummaryFromMetadata(metadata),
 		}
 		if len(record.Metadata) == 0 {
 			record.Metadata = nil
@@ -382,6 +386,9 @@ func (s *OrderStore) ListExecutions(ctx context.Context, query orderstore.Execut
 		StrategyInstance: textFromString(query.StrategyInstance),
 		ProviderAlias:    textFromString(query.Provider),
 		OrderID:          orderUUID,
+		TradedSince:      timestamptzFromUnix(query.Since),
+		TradedUntil:      timestamptzFromUnix(query.Until),
+		Tag:              textFromString(query.Tag),
 		Limit:            safeInt32(limit),
 	}
 	rows, err := queries.ListExecutions(ctx, params)
@@ -40,3 +40,17 @@ func TestOrderStoreNilPool(t *testing.T) {
 		t.Fatalf("expected error when pool nil")
 	}
 }
+
+func TestOrderStoreSnapshotsNilPool(t *testing.T) {
+	store := NewOrderStore(nil)
+	ctx := context.Background()
+	if err := store.SaveOpenOrderSnapshot(ctx, orderstore.OpenOrderSnapshot{Provider: "binance", TakenAt: 1}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.LoadOpenOrderSnapshots(ctx); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if err := store.DeleteOpenOrderSnapshot(ctx, "binance"); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+}
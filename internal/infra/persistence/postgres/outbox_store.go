@@ -1,16 +1,24 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
 	json "github.com/goccy/go-json"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 
 	"github.com/coachpo/meltica/internal/domain/outboxstore"
 	"github.com/coachpo/meltica/internal/infra/persistence/postgres/sqlc"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -19,22 +27,107 @@ import (
 type OutboxStore struct {
 	pool    *pgxpool.Pool
 	queries *sqlc.Queries
+
+	codec    outboxstore.Codec
+	minBytes int
+
+	rawBytes    metric.Int64Counter
+	storedBytes metric.Int64Counter
+	savedBytes  metric.Int64Counter
+}
+
+// OutboxOption configures an OutboxStore.
+type OutboxOption func(*OutboxStore)
+
+// WithPayloadCompression stores payloads of at least minBytes compressed with codec. Rows keep
+// their codec so replay decodes them transparently, and payloads that do not shrink are stored
+// as plain JSON.
+func WithPayloadCompression(codec outboxstore.Codec, minBytes int) OutboxOption {
+	return func(s *OutboxStore) {
+		s.codec = codec
+		if minBytes > 0 {
+			s.minBytes = minBytes
+		}
+	}
 }
 
 // NewOutboxStore constructs an OutboxStore backed by the provided pool.
-func NewOutboxStore(pool *pgxpool.Pool) *OutboxStore {
-	if pool == nil {
-		return &OutboxStore{
-			pool:    nil,
-			queries: nil,
+func NewOutboxStore(pool *pgxpool.Pool, opts ...OutboxOption) *OutboxStore {
+	store := &OutboxStore{
+		pool:        pool,
+		queries:     nil,
+		codec:       outboxstore.CodecIdentity,
+		minBytes:    defaultCompressionMinBytes,
+		rawBytes:    nil,
+		storedBytes: nil,
+		savedBytes:  nil,
+	}
+	if pool != nil {
+		store.queries = sqlc.New(pool)
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(store)
 		}
 	}
-	return &OutboxStore{
-		pool:    pool,
-		queries: sqlc.New(pool),
+	store.initPayloadMetrics()
+	return store
+}
+
+const defaultCompressionMinBytes = 1024
+
+func (s *OutboxStore) initPayloadMetrics() {
+	meter := otel.Meter("postgres.outbox")
+	if counter, err := meter.Int64Counter("meltica_outbox_payload_raw_bytes",
+		metric.WithDescription("Outbox payload bytes before compression"),
+		metric.WithUnit("By")); err == nil {
+		s.rawBytes = counter
+	}
+	if counter, err := meter.Int64Counter("meltica_outbox_payload_stored_bytes",
+		metric.WithDescription("Outbox payload bytes written to Postgres"),
+		metric.WithUnit("By")); err == nil {
+		s.storedBytes = counter
+	}
+	if counter, err := meter.Int64Counter("meltica_outbox_payload_saved_bytes",
+		metric.WithDescription("Outbox payload bytes saved by compression"),
+		metric.WithUnit("By")); err == nil {
+		s.savedBytes = counter
+	}
+}
+
+func (s *OutboxStore) recordPayloadBytes(ctx context.Context, eventType string, codec outboxstore.Codec, raw, stored int) {
+	attrs := metric.WithAttributes(
+		attribute.String("environment", telemetry.Environment()),
+		attribute.String("event_type", eventType),
+		attribute.String("codec", string(codec)),
+	)
+	if s.rawBytes != nil {
+		s.rawBytes.Add(ctx, int64(raw), attrs)
+	}
+	if s.storedBytes != nil {
+		s.storedBytes.Add(ctx, int64(stored), attrs)
+	}
+	if s.savedBytes != nil && raw > stored {
+		s.savedBytes.Add(ctx, int64(raw-stored), attrs)
 	}
 }
 
+// encodePayload picks the stored form of a payload: the JSON column alone, or a JSON null
+// placeholder with the compressed bytes when compression is enabled and pays off.
+func (s *OutboxStore) encodePayload(payload []byte) ([]byte, outboxstore.Codec, []byte, error) {
+	if s.codec == outboxstore.CodecIdentity || s.codec == "" || len(payload) < s.minBytes {
+		return payload, outboxstore.CodecIdentity, nil, nil
+	}
+	compressed, err := outboxstore.Compress(s.codec, payload)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("compress payload: %w", err)
+	}
+	if len(compressed) >= len(payload) {
+		return payload, outboxstore.CodecIdentity, nil, nil
+	}
+	return []byte("null"), s.codec, compressed, nil
+}
+
 const (
 	defaultOutboxLimit = 128
 	maxOutboxLimit     = 1024
@@ -65,8 +158,8 @@ func (s *OutboxStore) Enqueue(ctx context.Context, evt outboxstore.Event) (outbo
 	if eventType == "" {
 		return outboxstore.EventRecord{}, fmt.Errorf("outbox store: event type required")
 	}
-	payload := strings.TrimSpace(string(evt.Payload))
-	if payload == "" {
+	payload := bytes.TrimSpace(evt.Payload)
+	if len(payload) == 0 {
 		return outboxstore.EventRecord{}, fmt.Errorf("outbox store: payload required")
 	}
 	headers, err := encodeMap(evt.Headers)
@@ -77,21 +170,32 @@ func (s *OutboxStore) Enqueue(ctx context.Context, evt outboxstore.Event) (outbo
 	if availableAt.IsZero() {
 		availableAt = time.Now()
 	}
+	stored, codec, compressed, err := s.encodePayload(payload)
+	if err != nil {
+		return outboxstore.EventRecord{}, fmt.Errorf("outbox store: %w", err)
+	}
 	record, err := q.EnqueueEvent(ctx, sqlc.EnqueueEventParams{
 		AggregateType: aggregateType,
 		AggregateID:   aggregateID,
 		EventType:     eventType,
-		Payload:       []byte(payload),
+		Payload:       stored,
 		Headers:       headers,
 		AvailableAt: pgtype.Timestamptz{
 			Time:             availableAt,
 			InfinityModifier: pgtype.Finite,
 			Valid:            true,
 		},
+		PayloadCodec:      string(codec),
+		PayloadCompressed: compressed,
 	})
 	if err != nil {
 		return outboxstore.EventRecord{}, fmt.Errorf("outbox store: enqueue: %w", err)
 	}
+	storedBytes := len(stored)
+	if codec != outboxstore.CodecIdentity {
+		storedBytes = len(compressed)
+	}
+	s.recordPayloadBytes(ctx, eventType, codec, len(payload), storedBytes)
 	return convertOutboxRecord(record)
 }
 
@@ -161,6 +265,222 @@ func (s *OutboxStore) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
+// LoadOffset returns the committed outbox offset for the subscriber.
+func (s *OutboxStore) LoadOffset(ctx context.Context, subscriber string) (int64, bool, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return 0, false, err
+	}
+	subscriber = strings.TrimSpace(subscriber)
+	if subscriber == "" {
+		return 0, false, fmt.Errorf("outbox store: subscriber required")
+	}
+	row, err := q.GetSubscriptionOffset(ctx, subscriber)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("outbox store: load offset: %w", err)
+	}
+	return row.LastEventID, true, nil
+}
+
+// CommitOffset advances the subscriber offset; lower offsets are ignored.
+func (s *OutboxStore) CommitOffset(ctx context.Context, subscriber string, offset int64) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	subscriber = strings.TrimSpace(subscriber)
+	if subscriber == "" {
+		return fmt.Errorf("outbox store: subscriber required")
+	}
+	if offset < 0 {
+		return fmt.Errorf("outbox store: offset must be >= 0")
+	}
+	if err := q.UpsertSubscriptionOffset(ctx, sqlc.UpsertSubscriptionOffsetParams{
+		Subscriber:  subscriber,
+		LastEventID: offset,
+	}); err != nil {
+		return fmt.Errorf("outbox store: commit offset: %w", err)
+	}
+	return nil
+}
+
+// DeleteOffset removes the stored offset for the subscriber.
+func (s *OutboxStore) DeleteOffset(ctx context.Context, subscriber string) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	if err := q.DeleteSubscriptionOffset(ctx, strings.TrimSpace(subscriber)); err != nil {
+		return fmt.Errorf("outbox store: delete offset: %w", err)
+	}
+	return nil
+}
+
+// ListAfter returns outbox entries newer than afterID for the requested event types.
+func (s *OutboxStore) ListAfter(ctx context.Context, afterID int64, eventTypes []string, limit int) ([]outboxstore.EventRecord, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	if len(eventTypes) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = defaultOutboxLimit
+	} else if limit > maxOutboxLimit {
+		limit = maxOutboxLimit
+	}
+	rows, err := q.ListEventsAfter(ctx, sqlc.ListEventsAfterParams{
+		AfterID:    afterID,
+		EventTypes: eventTypes,
+		Limit:      boundedInt32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox store: list after: %w", err)
+	}
+	records := make([]outboxstore.EventRecord, 0, len(rows))
+	for _, row := range rows {
+		record, err := convertOutboxRecord(row)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ListEvents returns outbox entries matching the query in identifier order.
+func (s *OutboxStore) ListEvents(ctx context.Context, query outboxstore.EventQuery) ([]outboxstore.EventRecord, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultOutboxLimit
+	} else if limit > maxOutboxLimit {
+		limit = maxOutboxLimit
+	}
+	rows, err := q.ListEventsInRange(ctx, sqlc.ListEventsInRangeParams{
+		AfterID:      query.AfterID,
+		CreatedSince: timestamptzFromTime(query.Since),
+		CreatedUntil: timestamptzFromTime(query.Until),
+		EventTypes:   nonEmptyStrings(query.EventTypes),
+		Providers:    nonEmptyStrings(query.Providers),
+		Limit:        boundedInt32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox store: list events: %w", err)
+	}
+	records := make([]outboxstore.EventRecord, 0, len(rows))
+	for _, row := range rows {
+		record, err := convertOutboxRecord(row)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// GetEvent returns the outbox entry with the identifier.
+func (s *OutboxStore) GetEvent(ctx context.Context, id int64) (outboxstore.EventRecord, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return outboxstore.EventRecord{}, err
+	}
+	row, err := q.GetEvent(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return outboxstore.EventRecord{}, outboxstore.ErrEventNotFound
+		}
+		return outboxstore.EventRecord{}, fmt.Errorf("outbox store: get event: %w", err)
+	}
+	return convertOutboxRecord(row)
+}
+
+// Requeue returns a delivered or failed entry to pending so the replay worker publishes it again.
+func (s *OutboxStore) Requeue(ctx context.Context, id int64) (outboxstore.EventRecord, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return outboxstore.EventRecord{}, err
+	}
+	row, err := q.RequeueEvent(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return outboxstore.EventRecord{}, outboxstore.ErrEventNotFound
+		}
+		return outboxstore.EventRecord{}, fmt.Errorf("outbox store: requeue: %w", err)
+	}
+	return convertOutboxRecord(row)
+}
+
+// RequeueEvents requeues the entries matching the query, at most query.Limit of them.
+func (s *OutboxStore) RequeueEvents(ctx context.Context, query outboxstore.EventQuery) ([]int64, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultOutboxLimit
+	} else if limit > maxOutboxLimit {
+		limit = maxOutboxLimit
+	}
+	ids, err := q.RequeueEventsInRange(ctx, sqlc.RequeueEventsInRangeParams{
+		AfterID:      query.AfterID,
+		CreatedSince: timestamptzFromTime(query.Since),
+		CreatedUntil: timestamptzFromTime(query.Until),
+		EventTypes:   nonEmptyStrings(query.EventTypes),
+		Providers:    nonEmptyStrings(query.Providers),
+		Limit:        boundedInt32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("outbox store: requeue events: %w", err)
+	}
+	// UPDATE ... RETURNING does not preserve the subquery order.
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func timestamptzFromTime(value time.Time) pgtype.Timestamptz {
+	if value.IsZero() {
+		return nullTimestamptz()
+	}
+	return pgtype.Timestamptz{
+		Time:             value.UTC(),
+		InfinityModifier: pgtype.Finite,
+		Valid:            true,
+	}
+}
+
+// nonEmptyStrings drops blank entries and returns nil when nothing remains so the filter is skipped.
+func nonEmptyStrings(values []string) []string {
+	var out []string
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+// LatestID returns the highest outbox identifier.
+func (s *OutboxStore) LatestID(ctx context.Context) (int64, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return 0, err
+	}
+	latest, err := q.LatestEventID(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("outbox store: latest id: %w", err)
+	}
+	return latest, nil
+}
+
 func convertOutboxRecord(row sqlc.EventsOutbox) (outboxstore.EventRecord, error) {
 	var (
 		payloadJSON = append([]byte(nil), row.Payload...)
@@ -172,6 +492,15 @@ func convertOutboxRecord(row sqlc.EventsOutbox) (outboxstore.EventRecord, error)
 	record.AggregateID = row.AggregateID
 	record.EventType = row.EventType
 	record.Payload = json.RawMessage(payloadJSON)
+	record.PayloadCodec = outboxstore.CodecIdentity
+	if codec := outboxstore.Codec(row.PayloadCodec); codec != "" && codec != outboxstore.CodecIdentity {
+		payload, err := outboxstore.Decompress(codec, row.PayloadCompressed)
+		if err != nil {
+			return outboxstore.EventRecord{}, fmt.Errorf("outbox store: decode payload %d: %w", row.ID, err)
+		}
+		record.Payload = json.RawMessage(payload)
+		record.PayloadCodec = codec
+	}
 	record.AvailableAt = row.AvailableAt.Time
 	record.Attempts = int(row.Attempts)
 	record.Delivered = row.Delivered
@@ -213,7 +542,12 @@ func decodeMap(raw []byte) (map[string]any, error) {
 	return out, nil
 }
 
-var _ outboxstore.Store = (*OutboxStore)(nil)
+var (
+	_ outboxstore.Store             = (*OutboxStore)(nil)
+	_ outboxstore.SubscriptionStore = (*OutboxStore)(nil)
+	_ outboxstore.EventLister       = (*OutboxStore)(nil)
+	_ outboxstore.EventBrowser      = (*OutboxStore)(nil)
+)
 
 func boundedInt32(value int) int32 {
 	if value > math.MaxInt32 {
@@ -2,11 +2,13 @@ package postgres
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	json "github.com/goccy/go-json"
 
 	"github.com/coachpo/meltica/internal/domain/outboxstore"
+	"github.com/coachpo/meltica/internal/infra/persistence/postgres/sqlc"
 )
 
 func TestOutboxStoreNilPool(t *testing.T) {
@@ -33,4 +35,73 @@ func TestOutboxStoreNilPool(t *testing.T) {
 	if err := store.Delete(ctx, 1); err == nil {
 		t.Fatalf("expected error when pool nil")
 	}
+	if _, err := store.GetEvent(ctx, 1); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.Requeue(ctx, 1); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.RequeueEvents(ctx, outboxstore.EventQuery{}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+}
+
+func TestOutboxStoreSubscriptionOffsetsNilPool(t *testing.T) {
+	store := NewOutboxStore(nil)
+	ctx := context.Background()
+	if _, _, err := store.LoadOffset(ctx, "lambda:test"); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if err := store.CommitOffset(ctx, "lambda:test", 1); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.ListAfter(ctx, 0, []string{"ExecReport"}, 10); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.LatestID(ctx); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+}
+
+func TestOutboxStorePayloadCompressionRoundTrip(t *testing.T) {
+	store := NewOutboxStore(nil, WithPayloadCompression(outboxstore.CodecZstd, 64))
+	book := []byte(`{"bids":[` + strings.Repeat(`["100.5","1.25"],`, 200) + `["100","1"]]}`)
+
+	stored, codec, compressed, err := store.encodePayload(book)
+	if err != nil {
+		t.Fatalf("encodePayload: %v", err)
+	}
+	if codec != outboxstore.CodecZstd || string(stored) != "null" || len(compressed) == 0 || len(compressed) >= len(book) {
+		t.Fatalf("expected compressed book, got codec %q stored %q (%d bytes)", codec, stored, len(compressed))
+	}
+	record, err := convertOutboxRecord(sqlc.EventsOutbox{ID: 7, EventType: "BookSnapshot", Payload: stored, PayloadCodec: string(codec), PayloadCompressed: compressed})
+	if err != nil {
+		t.Fatalf("convertOutboxRecord: %v", err)
+	}
+	if string(record.Payload) != string(book) || record.PayloadCodec != outboxstore.CodecZstd {
+		t.Fatalf("expected transparent decompression, got codec %q", record.PayloadCodec)
+	}
+
+	small := []byte(`{"eventId":"evt-1"}`)
+	if stored, codec, compressed, _ := store.encodePayload(small); codec != outboxstore.CodecIdentity || string(stored) != string(small) || compressed != nil {
+		t.Fatalf("expected small payload stored as JSON, got codec %q", codec)
+	}
+	legacy, err := convertOutboxRecord(sqlc.EventsOutbox{ID: 8, Payload: small, PayloadCodec: "identity"})
+	if err != nil || string(legacy.Payload) != string(small) {
+		t.Fatalf("expected identity row unchanged, got %s %v", legacy.Payload, err)
+	}
+	if _, err := convertOutboxRecord(sqlc.EventsOutbox{ID: 9, Payload: stored, PayloadCodec: "zstd", PayloadCompressed: []byte("garbage")}); err == nil {
+		t.Fatal("expected corrupt payload rejected")
+	}
+}
+
+func TestParseOutboxCodec(t *testing.T) {
+	for name, want := range map[string]outboxstore.Codec{"": outboxstore.CodecIdentity, "none": outboxstore.CodecIdentity, " ZSTD ": outboxstore.CodecZstd} {
+		if got, err := outboxstore.ParseCodec(name); err != nil || got != want {
+			t.Fatalf("ParseCodec(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := outboxstore.ParseCodec("gzip"); err == nil {
+		t.Fatal("expected unknown codec rejected")
+	}
 }
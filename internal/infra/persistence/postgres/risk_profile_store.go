@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/domain/riskstore"
+	"github.com/coachpo/meltica/internal/infra/persistence/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RiskProfileStore persists named risk profiles in PostgreSQL.
+type RiskProfileStore struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+// NewRiskProfileStore constructs a RiskProfileStore backed by the provided pgx pool.
+func NewRiskProfileStore(pool *pgxpool.Pool) *RiskProfileStore {
+	if pool == nil {
+		return &RiskProfileStore{pool: nil, queries: nil}
+	}
+	return &RiskProfileStore{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+func (s *RiskProfileStore) ensureQueries() (*sqlc.Queries, error) {
+	if s.pool == nil || s.queries == nil {
+		return nil, fmt.Errorf("risk profile store: nil pool")
+	}
+	return s.queries, nil
+}
+
+// SaveProfile upserts a risk profile.
+func (s *RiskProfileStore) SaveProfile(ctx context.Context, profile riskstore.Profile) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSpace(profile.Name)
+	if name == "" {
+		return fmt.Errorf("risk profile store: profile name required")
+	}
+	limits, err := encodeJSON(profile.Limits)
+	if err != nil {
+		return fmt.Errorf("marshal risk profile limits: %w", err)
+	}
+	if err := q.UpsertRiskProfile(ctx, sqlc.UpsertRiskProfileParams{
+		Name:        name,
+		Description: strings.TrimSpace(profile.Description),
+		Limits:      limits,
+	}); err != nil {
+		return fmt.Errorf("upsert risk profile: %w", err)
+	}
+	return nil
+}
+
+// DeleteProfile removes a risk profile.
+func (s *RiskProfileStore) DeleteProfile(ctx context.Context, name string) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return fmt.Errorf("risk profile store: profile name required")
+	}
+	if err := q.DeleteRiskProfile(ctx, trimmed); err != nil {
+		return fmt.Errorf("delete risk profile: %w", err)
+	}
+	return nil
+}
+
+// LoadProfiles retrieves all stored risk profiles.
+func (s *RiskProfileStore) LoadProfiles(ctx context.Context) ([]riskstore.Profile, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := q.ListRiskProfiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list risk profiles: %w", err)
+	}
+	profiles := make([]riskstore.Profile, 0, len(rows))
+	for _, row := range rows {
+		limits, err := decodeJSON(row.Limits)
+		if err != nil {
+			return nil, fmt.Errorf("decode risk profile %s: %w", row.Name, err)
+		}
+		profiles = append(profiles, riskstore.Profile{
+			Name:        row.Name,
+			Description: row.Description,
+			Limits:      limits,
+			UpdatedAt:   row.UpdatedAt.Time,
+		})
+	}
+	return profiles, nil
+}
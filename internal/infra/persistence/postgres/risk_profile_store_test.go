@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/riskstore"
+)
+
+func TestRiskProfileStoreNilPool(t *testing.T) {
+	store := NewRiskProfileStore(nil)
+	ctx := context.Background()
+	if err := store.SaveProfile(ctx, riskstore.Profile{Name: "volatile"}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if err := store.DeleteProfile(ctx, "volatile"); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.LoadProfiles(ctx); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+}
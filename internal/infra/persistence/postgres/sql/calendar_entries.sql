@@ -0,0 +1,46 @@
+-- name: UpsertCalendarEntry :exec
+INSERT INTO calendar_entries (
+    id,
+    title,
+    scheduled_at,
+    notify_at,
+    action,
+    status,
+    notified_at,
+    applied_at,
+    error,
+    audit,
+    created_at,
+    updated_at
+)
+VALUES (
+    @id::text,
+    @title::text,
+    @scheduled_at::timestamptz,
+    sqlc.narg(notify_at)::timestamptz,
+    COALESCE(@action::jsonb, '{}'::jsonb),
+    @status::text,
+    sqlc.narg(notified_at)::timestamptz,
+    sqlc.narg(applied_at)::timestamptz,
+    @error::text,
+    COALESCE(@audit::jsonb, '[]'::jsonb),
+    @created_at::timestamptz,
+    NOW()
+)
+ON CONFLICT (id) DO
+UPDATE SET
+    title = EXCLUDED.title,
+    scheduled_at = EXCLUDED.scheduled_at,
+    notify_at = EXCLUDED.notify_at,
+    action = EXCLUDED.action,
+    status = EXCLUDED.status,
+    notified_at = EXCLUDED.notified_at,
+    applied_at = EXCLUDED.applied_at,
+    error = EXCLUDED.error,
+    audit = EXCLUDED.audit,
+    updated_at = NOW();
+
+-- name: ListCalendarEntries :many
+SELECT id, title, scheduled_at, notify_at, action, status, notified_at, applied_at, error, audit, created_at, updated_at
+FROM calendar_entries
+ORDER BY scheduled_at, id;
@@ -0,0 +1,22 @@
+-- name: GetSubscriptionOffset :one
+SELECT *
+FROM event_subscription_offsets
+WHERE subscriber = @subscriber::text;
+
+-- name: UpsertSubscriptionOffset :exec
+INSERT INTO event_subscription_offsets (
+    subscriber,
+    last_event_id
+)
+VALUES (
+    @subscriber::text,
+    @last_event_id::bigint
+)
+ON CONFLICT (subscriber) DO UPDATE
+SET
+    last_event_id = GREATEST(event_subscription_offsets.last_event_id, EXCLUDED.last_event_id),
+    updated_at = NOW();
+
+-- name: DeleteSubscriptionOffset :exec
+DELETE FROM event_subscription_offsets
+WHERE subscriber = @subscriber::text;
@@ -5,7 +5,9 @@ INSERT INTO events_outbox (
     event_type,
     payload,
     headers,
-    available_at
+    available_at,
+    payload_codec,
+    payload_compressed
 )
 VALUES (
     @aggregate_type::text,
@@ -13,7 +15,9 @@ VALUES (
     @event_type::text,
     COALESCE(@payload::jsonb, '{}'::jsonb),
     COALESCE(@headers::jsonb, '{}'::jsonb),
-    COALESCE(@available_at::timestamptz, NOW())
+    COALESCE(@available_at::timestamptz, NOW()),
+    @payload_codec::text,
+    sqlc.narg('payload_compressed')::bytea
 )
 RETURNING *;
 
@@ -43,6 +47,88 @@ WHERE delivered = FALSE
 ORDER BY available_at ASC
 LIMIT sqlc.arg('limit')::int;
 
+-- name: GetEvent :one
+SELECT *
+FROM events_outbox
+WHERE id = @id::bigint;
+
+-- name: RequeueEvent :one
+UPDATE events_outbox
+SET
+    delivered = FALSE,
+    published_at = NULL,
+    last_error = NULL,
+    available_at = NOW()
+WHERE id = @id::bigint
+RETURNING *;
+
+-- name: RequeueEventsInRange :many
+UPDATE events_outbox
+SET
+    delivered = FALSE,
+    published_at = NULL,
+    last_error = NULL,
+    available_at = NOW()
+WHERE id IN (
+    SELECT matched.id
+    FROM events_outbox AS matched
+    WHERE matched.id > @after_id::bigint
+      AND (
+        sqlc.narg('created_since')::timestamptz IS NULL
+        OR matched.created_at >= sqlc.narg('created_since')::timestamptz
+      )
+      AND (
+        sqlc.narg('created_until')::timestamptz IS NULL
+        OR matched.created_at < sqlc.narg('created_until')::timestamptz
+      )
+      AND (
+        sqlc.narg('event_types')::text[] IS NULL
+        OR matched.event_type = ANY(sqlc.narg('event_types')::text[])
+      )
+      AND (
+        sqlc.narg('providers')::text[] IS NULL
+        OR matched.headers->>'provider' = ANY(sqlc.narg('providers')::text[])
+      )
+    ORDER BY matched.id ASC
+    LIMIT sqlc.arg('limit')::int
+)
+RETURNING id;
+
 -- name: DeleteEvent :exec
 DELETE FROM events_outbox
 WHERE id = @id::bigint;
+
+-- name: ListEventsAfter :many
+SELECT *
+FROM events_outbox
+WHERE id > @after_id::bigint
+  AND event_type = ANY(@event_types::text[])
+ORDER BY id ASC
+LIMIT sqlc.arg('limit')::int;
+
+-- name: ListEventsInRange :many
+SELECT *
+FROM events_outbox
+WHERE id > @after_id::bigint
+  AND (
+    sqlc.narg('created_since')::timestamptz IS NULL
+    OR created_at >= sqlc.narg('created_since')::timestamptz
+  )
+  AND (
+    sqlc.narg('created_until')::timestamptz IS NULL
+    OR created_at < sqlc.narg('created_until')::timestamptz
+  )
+  AND (
+    sqlc.narg('event_types')::text[] IS NULL
+    OR event_type = ANY(sqlc.narg('event_types')::text[])
+  )
+  AND (
+    sqlc.narg('providers')::text[] IS NULL
+    OR headers->>'provider' = ANY(sqlc.narg('providers')::text[])
+  )
+ORDER BY id ASC
+LIMIT sqlc.arg('limit')::int;
+
+-- name: LatestEventID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS latest_id
+FROM events_outbox;
@@ -69,6 +69,15 @@ WHERE (
 ) AND (
     sqlc.narg('order_id')::uuid IS NULL
     OR e.order_id = sqlc.narg('order_id')::uuid
+) AND (
+    sqlc.narg('traded_since')::timestamptz IS NULL
+    OR e.traded_at >= sqlc.narg('traded_since')::timestamptz
+) AND (
+    sqlc.narg('traded_until')::timestamptz IS NULL
+    OR e.traded_at < sqlc.narg('traded_until')::timestamptz
+) AND (
+    sqlc.narg('tag')::text IS NULL
+    OR o.metadata -> 'tags' @> jsonb_build_array(sqlc.narg('tag')::text)
 )
 ORDER BY e.traded_at DESC
 LIMIT sqlc.arg('limit')::int;
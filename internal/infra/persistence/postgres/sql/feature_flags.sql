@@ -0,0 +1,23 @@
+-- name: UpsertFeatureFlag :exec
+INSERT INTO feature_flags (
+    name,
+    enabled,
+    updated_at
+)
+VALUES (
+    @name::text,
+    @enabled::boolean,
+    NOW()
+)
+ON CONFLICT (name) DO
+UPDATE SET
+    enabled = EXCLUDED.enabled,
+    updated_at = NOW();
+
+-- name: DeleteFeatureFlag :exec
+DELETE FROM feature_flags WHERE name = @name::text;
+
+-- name: ListFeatureFlags :many
+SELECT name, enabled, created_at, updated_at
+FROM feature_flags
+ORDER BY name;
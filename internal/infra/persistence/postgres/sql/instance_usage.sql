@@ -0,0 +1,20 @@
+-- name: AddInstanceUsage :exec
+INSERT INTO instance_usage (day, instance_id, strategy, labels, events, orders, handler_us)
+VALUES (@day::date, @instance_id::text, @strategy::text, @labels::jsonb, @events::bigint, @orders::bigint, @handler_us::bigint)
+ON CONFLICT (day, instance_id) DO UPDATE
+SET strategy = EXCLUDED.strategy,
+    labels = EXCLUDED.labels,
+    events = instance_usage.events + EXCLUDED.events,
+    orders = instance_usage.orders + EXCLUDED.orders,
+    handler_us = instance_usage.handler_us + EXCLUDED.handler_us,
+    updated_at = NOW();
+
+-- name: ListInstanceUsage :many
+SELECT day, instance_id, strategy, labels, events, orders, handler_us, updated_at
+FROM instance_usage
+WHERE day BETWEEN @from_day::date AND @to_day::date
+ORDER BY day, instance_id;
+
+-- name: DeleteInstanceUsageBefore :execrows
+DELETE FROM instance_usage
+WHERE day < @cutoff::date;
@@ -0,0 +1,23 @@
+-- name: UpsertOpenOrderSnapshot :exec
+INSERT INTO open_order_snapshots (
+    provider,
+    taken_at,
+    orders
+)
+VALUES (
+    @provider::text,
+    @taken_at::timestamptz,
+    @orders::jsonb
+)
+ON CONFLICT (provider) DO
+UPDATE SET
+    taken_at = EXCLUDED.taken_at,
+    orders = EXCLUDED.orders;
+
+-- name: DeleteOpenOrderSnapshot :exec
+DELETE FROM open_order_snapshots WHERE provider = @provider::text;
+
+-- name: ListOpenOrderSnapshots :many
+SELECT provider, taken_at, orders
+FROM open_order_snapshots
+ORDER BY provider;
@@ -42,7 +42,7 @@ SET
     state = @state::text,
     acknowledged_at = COALESCE(sqlc.narg('acknowledged_at')::timestamptz, acknowledged_at),
     completed_at = COALESCE(sqlc.narg('completed_at')::timestamptz, completed_at),
-    metadata = COALESCE(sqlc.narg('metadata')::jsonb, metadata),
+    metadata = metadata || COALESCE(sqlc.narg('metadata')::jsonb, '{}'::jsonb),
     updated_at = NOW()
 WHERE id = @id::uuid
 RETURNING *;
@@ -91,6 +91,15 @@ WHERE (
 ) AND (
     sqlc.narg('states')::text[] IS NULL
     OR o.state = ANY(sqlc.narg('states')::text[])
+) AND (
+    sqlc.narg('placed_since')::timestamptz IS NULL
+    OR o.placed_at >= sqlc.narg('placed_since')::timestamptz
+) AND (
+    sqlc.narg('placed_until')::timestamptz IS NULL
+    OR o.placed_at < sqlc.narg('placed_until')::timestamptz
+) AND (
+    sqlc.narg('tag')::text IS NULL
+    OR o.metadata -> 'tags' @> jsonb_build_array(sqlc.narg('tag')::text)
 )
 ORDER BY o.placed_at DESC
 LIMIT sqlc.arg('limit')::int;
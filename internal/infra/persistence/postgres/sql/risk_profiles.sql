@@ -0,0 +1,26 @@
+-- name: UpsertRiskProfile :exec
+INSERT INTO risk_profiles (
+    name,
+    description,
+    limits,
+    updated_at
+)
+VALUES (
+    @name::text,
+    @description::text,
+    COALESCE(@limits::jsonb, '{}'::jsonb),
+    NOW()
+)
+ON CONFLICT (name) DO
+UPDATE SET
+    description = EXCLUDED.description,
+    limits = EXCLUDED.limits,
+    updated_at = NOW();
+
+-- name: DeleteRiskProfile :exec
+DELETE FROM risk_profiles WHERE name = @name::text;
+
+-- name: ListRiskProfiles :many
+SELECT name, description, limits, created_at, updated_at
+FROM risk_profiles
+ORDER BY name;
@@ -0,0 +1,43 @@
+-- name: InsertStrategyLogs :exec
+INSERT INTO strategy_logs (
+    instance_id,
+    strategy,
+    hash,
+    level,
+    severity,
+    source,
+    message,
+    recorded_at
+)
+SELECT instance_id, strategy, hash, level, severity, source, message, recorded_at
+FROM unnest(
+    @instance_ids::text[],
+    @strategies::text[],
+    @hashes::text[],
+    @levels::text[],
+    @severities::smallint[],
+    @sources::text[],
+    @messages::text[],
+    @recorded_ats::timestamptz[]
+) AS entries (instance_id, strategy, hash, level, severity, source, message, recorded_at);
+
+-- name: ListStrategyLogsFrom :many
+SELECT id, instance_id, strategy, hash, level, severity, source, message, recorded_at
+FROM strategy_logs
+WHERE instance_id = @instance_id::text
+  AND severity >= @min_severity::smallint
+  AND recorded_at >= @recorded_from::timestamptz
+ORDER BY recorded_at ASC, id ASC
+LIMIT @row_limit::int;
+
+-- name: ListRecentStrategyLogs :many
+SELECT id, instance_id, strategy, hash, level, severity, source, message, recorded_at
+FROM strategy_logs
+WHERE instance_id = @instance_id::text
+  AND severity >= @min_severity::smallint
+ORDER BY recorded_at DESC, id DESC
+LIMIT @row_limit::int;
+
+-- name: DeleteStrategyLogsBefore :execrows
+DELETE FROM strategy_logs
+WHERE recorded_at < @cutoff::timestamptz;
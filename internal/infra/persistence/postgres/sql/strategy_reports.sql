@@ -0,0 +1,25 @@
+-- name: InsertStrategyReport :one
+INSERT INTO strategy_reports (generated_at, summary, report)
+VALUES (@generated_at::timestamptz, @summary::text, @report::jsonb)
+RETURNING id;
+
+-- name: ListStrategyReports :many
+SELECT id, generated_at, summary
+FROM strategy_reports
+ORDER BY generated_at DESC, id DESC
+LIMIT @row_limit::int;
+
+-- name: GetStrategyReport :one
+SELECT id, generated_at, summary, report
+FROM strategy_reports
+WHERE id = @id::bigint;
+
+-- name: GetLatestStrategyReport :one
+SELECT id, generated_at, summary, report
+FROM strategy_reports
+ORDER BY generated_at DESC, id DESC
+LIMIT 1;
+
+-- name: DeleteStrategyReportsBefore :execrows
+DELETE FROM strategy_reports
+WHERE generated_at < @cutoff::timestamptz;
@@ -0,0 +1,28 @@
+-- name: InsertStrategyHistory :exec
+INSERT INTO strategy_revision_history (
+    strategy,
+    action,
+    hash,
+    tag,
+    previous_hash,
+    actor,
+    reason,
+    metadata
+)
+VALUES (
+    @strategy::text,
+    @action::text,
+    @hash::text,
+    @tag::text,
+    @previous_hash::text,
+    @actor::text,
+    @reason::text,
+    COALESCE(@metadata::jsonb, '{}'::jsonb)
+);
+
+-- name: ListStrategyHistory :many
+SELECT id, strategy, action, hash, tag, previous_hash, actor, reason, metadata, recorded_at
+FROM strategy_revision_history
+WHERE strategy = @strategy::text
+ORDER BY id DESC
+LIMIT @row_limit::int;
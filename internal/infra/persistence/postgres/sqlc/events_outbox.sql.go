@@ -22,7 +22,7 @@ func (q *Queries) DeleteEvent(ctx context.Context, id int64) error {
 }
 
 const dequeuePendingEvents = `-- name: DequeuePendingEvents :many
-SELECT id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at
+SELECT id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at, payload_codec, payload_compressed
 FROM events_outbox
 WHERE delivered = FALSE
   AND available_at <= NOW()
@@ -52,6 +52,8 @@ func (q *Queries) DequeuePendingEvents(ctx context.Context, limit int32) ([]Even
 			&i.LastError,
 			&i.Delivered,
 			&i.CreatedAt,
+			&i.PayloadCodec,
+			&i.PayloadCompressed,
 		); err != nil {
 			return nil, err
 		}
@@ -70,7 +72,9 @@ INSERT INTO events_outbox (
     event_type,
     payload,
     headers,
-    available_at
+    available_at,
+    payload_codec,
+    payload_compressed
 )
 VALUES (
     $1::text,
@@ -78,18 +82,22 @@ VALUES (
     $3::text,
     COALESCE($4::jsonb, '{}'::jsonb),
     COALESCE($5::jsonb, '{}'::jsonb),
-    COALESCE($6::timestamptz, NOW())
+    COALESCE($6::timestamptz, NOW()),
+    $7::text,
+    $8::bytea
 )
-RETURNING id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at
+RETURNING id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at, payload_codec, payload_compressed
 `
 
 type EnqueueEventParams struct {
-	AggregateType string             `db:"aggregate_type" json:"aggregate_type"`
-	AggregateID   string             `db:"aggregate_id" json:"aggregate_id"`
-	EventType     string             `db:"event_type" json:"event_type"`
-	Payload       []byte             `db:"payload" json:"payload"`
-	Headers       []byte             `db:"headers" json:"headers"`
-	AvailableAt   pgtype.Timestamptz `db:"available_at" json:"available_at"`
+	AggregateType     string             `db:"aggregate_type" json:"aggregate_type"`
+	AggregateID       string             `db:"aggregate_id" json:"aggregate_id"`
+	EventType         string             `db:"event_type" json:"event_type"`
+	Payload           []byte             `db:"payload" json:"payload"`
+	Headers           []byte             `db:"headers" json:"headers"`
+	AvailableAt       pgtype.Timestamptz `db:"available_at" json:"available_at"`
+	PayloadCodec      string             `db:"payload_codec" json:"payload_codec"`
+	PayloadCompressed []byte             `db:"payload_compressed" json:"payload_compressed"`
 }
 
 func (q *Queries) EnqueueEvent(ctx context.Context, arg EnqueueEventParams) (EventsOutbox, error) {
@@ -100,6 +108,8 @@ func (q *Queries) EnqueueEvent(ctx context.Context, arg EnqueueEventParams) (Eve
 		arg.Payload,
 		arg.Headers,
 		arg.AvailableAt,
+		arg.PayloadCodec,
+		arg.PayloadCompressed,
 	)
 	var i EventsOutbox
 	err := row.Scan(
@@ -115,6 +125,36 @@ func (q *Queries) EnqueueEvent(ctx context.Context, arg EnqueueEventParams) (Eve
 		&i.LastError,
 		&i.Delivered,
 		&i.CreatedAt,
+		&i.PayloadCodec,
+		&i.PayloadCompressed,
+	)
+	return i, err
+}
+
+const getEvent = `-- name: GetEvent :one
+SELECT id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at, payload_codec, payload_compressed
+FROM events_outbox
+WHERE id = $1::bigint
+`
+
+func (q *Queries) GetEvent(ctx context.Context, id int64) (EventsOutbox, error) {
+	row := q.db.QueryRow(ctx, getEvent, id)
+	var i EventsOutbox
+	err := row.Scan(
+		&i.ID,
+		&i.AggregateType,
+		&i.AggregateID,
+		&i.EventType,
+		&i.Payload,
+		&i.Headers,
+		&i.AvailableAt,
+		&i.PublishedAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.Delivered,
+		&i.CreatedAt,
+		&i.PayloadCodec,
+		&i.PayloadCompressed,
 	)
 	return i, err
 }
@@ -126,7 +166,7 @@ SET
     last_error = $1::text,
     available_at = NOW() + INTERVAL '30 seconds'
 WHERE id = $2::bigint
-RETURNING id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at
+RETURNING id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at, payload_codec, payload_compressed
 `
 
 type IncrementEventAttemptParams struct {
@@ -150,10 +190,149 @@ func (q *Queries) IncrementEventAttempt(ctx context.Context, arg IncrementEventA
 		&i.LastError,
 		&i.Delivered,
 		&i.CreatedAt,
+		&i.PayloadCodec,
+		&i.PayloadCompressed,
 	)
 	return i, err
 }
 
+const latestEventID = `-- name: LatestEventID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS latest_id
+FROM events_outbox
+`
+
+func (q *Queries) LatestEventID(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, latestEventID)
+	var latest_id int64
+	err := row.Scan(&latest_id)
+	return latest_id, err
+}
+
+const listEventsAfter = `-- name: ListEventsAfter :many
+SELECT id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at, payload_codec, payload_compressed
+FROM events_outbox
+WHERE id > $1::bigint
+  AND event_type = ANY($2::text[])
+ORDER BY id ASC
+LIMIT $3::int
+`
+
+type ListEventsAfterParams struct {
+	AfterID    int64    `db:"after_id" json:"after_id"`
+	EventTypes []string `db:"event_types" json:"event_types"`
+	Limit      int32    `db:"limit" json:"limit"`
+}
+
+func (q *Queries) ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]EventsOutbox, error) {
+	rows, err := q.db.Query(ctx, listEventsAfter, arg.AfterID, arg.EventTypes, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventsOutbox
+	for rows.Next() {
+		var i EventsOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.Headers,
+			&i.AvailableAt,
+			&i.PublishedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.Delivered,
+			&i.CreatedAt,
+			&i.PayloadCodec,
+			&i.PayloadCompressed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEventsInRange = `-- name: ListEventsInRange :many
+SELECT id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at, payload_codec, payload_compressed
+FROM events_outbox
+WHERE id > $1::bigint
+  AND (
+    $2::timestamptz IS NULL
+    OR created_at >= $2::timestamptz
+  )
+  AND (
+    $3::timestamptz IS NULL
+    OR created_at < $3::timestamptz
+  )
+  AND (
+    $4::text[] IS NULL
+    OR event_type = ANY($4::text[])
+  )
+  AND (
+    $5::text[] IS NULL
+    OR headers->>'provider' = ANY($5::text[])
+  )
+ORDER BY id ASC
+LIMIT $6::int
+`
+
+type ListEventsInRangeParams struct {
+	AfterID      int64              `db:"after_id" json:"after_id"`
+	CreatedSince pgtype.Timestamptz `db:"created_since" json:"created_since"`
+	CreatedUntil pgtype.Timestamptz `db:"created_until" json:"created_until"`
+	EventTypes   []string           `db:"event_types" json:"event_types"`
+	Providers    []string           `db:"providers" json:"providers"`
+	Limit        int32              `db:"limit" json:"limit"`
+}
+
+func (q *Queries) ListEventsInRange(ctx context.Context, arg ListEventsInRangeParams) ([]EventsOutbox, error) {
+	rows, err := q.db.Query(ctx, listEventsInRange,
+		arg.AfterID,
+		arg.CreatedSince,
+		arg.CreatedUntil,
+		arg.EventTypes,
+		arg.Providers,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EventsOutbox
+	for rows.Next() {
+		var i EventsOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.AggregateType,
+			&i.AggregateID,
+			&i.EventType,
+			&i.Payload,
+			&i.Headers,
+			&i.AvailableAt,
+			&i.PublishedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.Delivered,
+			&i.CreatedAt,
+			&i.PayloadCodec,
+			&i.PayloadCompressed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const markEventDelivered = `-- name: MarkEventDelivered :one
 UPDATE events_outbox
 SET
@@ -161,7 +340,7 @@ SET
     published_at = NOW(),
     attempts = attempts + 1
 WHERE id = $1::bigint
-RETURNING id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at
+RETURNING id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at, payload_codec, payload_compressed
 `
 
 func (q *Queries) MarkEventDelivered(ctx context.Context, id int64) (EventsOutbox, error) {
@@ -180,6 +359,110 @@ func (q *Queries) MarkEventDelivered(ctx context.Context, id int64) (EventsOutbo
 		&i.LastError,
 		&i.Delivered,
 		&i.CreatedAt,
+		&i.PayloadCodec,
+		&i.PayloadCompressed,
+	)
+	return i, err
+}
+
+const requeueEvent = `-- name: RequeueEvent :one
+UPDATE events_outbox
+SET
+    delivered = FALSE,
+    published_at = NULL,
+    last_error = NULL,
+    available_at = NOW()
+WHERE id = $1::bigint
+RETURNING id, aggregate_type, aggregate_id, event_type, payload, headers, available_at, published_at, attempts, last_error, delivered, created_at, payload_codec, payload_compressed
+`
+
+func (q *Queries) RequeueEvent(ctx context.Context, id int64) (EventsOutbox, error) {
+	row := q.db.QueryRow(ctx, requeueEvent, id)
+	var i EventsOutbox
+	err := row.Scan(
+		&i.ID,
+		&i.AggregateType,
+		&i.AggregateID,
+		&i.EventType,
+		&i.Payload,
+		&i.Headers,
+		&i.AvailableAt,
+		&i.PublishedAt,
+		&i.Attempts,
+		&i.LastError,
+		&i.Delivered,
+		&i.CreatedAt,
+		&i.PayloadCodec,
+		&i.PayloadCompressed,
 	)
 	return i, err
 }
+
+const requeueEventsInRange = `-- name: RequeueEventsInRange :many
+UPDATE events_outbox
+SET
+    delivered = FALSE,
+    published_at = NULL,
+    last_error = NULL,
+    available_at = NOW()
+WHERE id IN (
+    SELECT matched.id
+    FROM events_outbox AS matched
+    WHERE matched.id > $1::bigint
+      AND (
+        $2::timestamptz IS NULL
+        OR matched.created_at >= $2::timestamptz
+      )
+      AND (
+        $3::timestamptz IS NULL
+        OR matched.created_at < $3::timestamptz
+      )
+      AND (
+        $4::text[] IS NULL
+        OR matched.event_type = ANY($4::text[])
+      )
+      AND (
+        $5::text[] IS NULL
+        OR matched.headers->>'provider' = ANY($5::text[])
+      )
+    ORDER BY matched.id ASC
+    LIMIT $6::int
+)
+RETURNING id
+`
+
+type RequeueEventsInRangeParams struct {
+	AfterID      int64              `db:"after_id" json:"after_id"`
+	CreatedSince pgtype.Timestamptz `db:"created_since" json:"created_since"`
+	CreatedUntil pgtype.Timestamptz `db:"created_until" json:"created_until"`
+	EventTypes   []string           `db:"event_types" json:"event_types"`
+	Providers    []string           `db:"providers" json:"providers"`
+	Limit        int32              `db:"limit" json:"limit"`
+}
+
+func (q *Queries) RequeueEventsInRange(ctx context.Context, arg RequeueEventsInRangeParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, requeueEventsInRange,
+		arg.AfterID,
+		arg.CreatedSince,
+		arg.CreatedUntil,
+		arg.EventTypes,
+		arg.Providers,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
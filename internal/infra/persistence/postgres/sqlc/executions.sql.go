@@ -122,16 +122,28 @@ WHERE (
 ) AND (
     $3::uuid IS NULL
     OR e.order_id = $3::uuid
+) AND (
+    $4::timestamptz IS NULL
+    OR e.traded_at >= $4::timestamptz
+) AND (
+    $5::timestamptz IS NULL
+    OR e.traded_at < $5::timestamptz
+) AND (
+    $6::text IS NULL
+    OR o.metadata -> 'tags' @> jsonb_build_array($6::text)
 )
 ORDER BY e.traded_at DESC
-LIMIT $4::int
+LIMIT $7::int
 `
 
 type ListExecutionsParams struct {
-	StrategyInstance pgtype.Text `db:"strategy_instance" json:"strategy_instance"`
-	ProviderAlias    pgtype.Text `db:"provider_alias" json:"provider_alias"`
-	OrderID          pgtype.UUID `db:"order_id" json:"order_id"`
-	Limit            int32       `db:"limit" json:"limit"`
+	StrategyInstance pgtype.Text        `db:"strategy_instance" json:"strategy_instance"`
+	ProviderAlias    pgtype.Text        `db:"provider_alias" json:"provider_alias"`
+	OrderID          pgtype.UUID        `db:"order_id" json:"order_id"`
+	TradedSince      pgtype.Timestamptz `db:"traded_since" json:"traded_since"`
+	TradedUntil      pgtype.Timestamptz `db:"traded_until" json:"traded_until"`
+	Tag              pgtype.Text        `db:"tag" json:"tag"`
+	Limit            int32              `db:"limit" json:"limit"`
 }
 
 type ListExecutionsRow struct {
@@ -154,6 +166,9 @@ func (q *Queries) ListExecutions(ctx context.Context, arg ListExecutionsParams)
 		arg.StrategyInstance,
 		arg.ProviderAlias,
 		arg.OrderID,
+		arg.TradedSince,
+		arg.TradedUntil,
+		arg.Tag,
 		arg.Limit,
 	)
 	if err != nil {
@@ -22,19 +22,42 @@ type Balance struct {
 	UpdatedAt  pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
+type CalendarEntry struct {
+	ID          string             `db:"id" json:"id"`
+	Title       string             `db:"title" json:"title"`
+	ScheduledAt pgtype.Timestamptz `db:"scheduled_at" json:"scheduled_at"`
+	NotifyAt    pgtype.Timestamptz `db:"notify_at" json:"notify_at"`
+	Action      []byte             `db:"action" json:"action"`
+	Status      string             `db:"status" json:"status"`
+	NotifiedAt  pgtype.Timestamptz `db:"notified_at" json:"notified_at"`
+	AppliedAt   pgtype.Timestamptz `db:"applied_at" json:"applied_at"`
+	Error       string             `db:"error" json:"error"`
+	Audit       []byte             `db:"audit" json:"audit"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type EventSubscriptionOffset struct {
+	Subscriber  string             `db:"subscriber" json:"subscriber"`
+	LastEventID int64              `db:"last_event_id" json:"last_event_id"`
+	UpdatedAt   pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
 type EventsOutbox struct {
-	ID            int64              `db:"id" json:"id"`
-	AggregateType string             `db:"aggregate_type" json:"aggregate_type"`
-	AggregateID   string             `db:"aggregate_id" json:"aggregate_id"`
-	EventType     string             `db:"event_type" json:"event_type"`
-	Payload       []byte             `db:"payload" json:"payload"`
-	Headers       []byte             `db:"headers" json:"headers"`
-	AvailableAt   pgtype.Timestamptz `db:"available_at" json:"available_at"`
-	PublishedAt   pgtype.Timestamptz `db:"published_at" json:"published_at"`
-	Attempts      int32              `db:"attempts" json:"attempts"`
-	LastError     pgtype.Text        `db:"last_error" json:"last_error"`
-	Delivered     bool               `db:"delivered" json:"delivered"`
-	CreatedAt     pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ID                int64              `db:"id" json:"id"`
+	AggregateType     string             `db:"aggregate_type" json:"aggregate_type"`
+	AggregateID       string             `db:"aggregate_id" json:"aggregate_id"`
+	EventType         string             `db:"event_type" json:"event_type"`
+	Payload           []byte             `db:"payload" json:"payload"`
+	Headers           []byte             `db:"headers" json:"headers"`
+	AvailableAt       pgtype.Timestamptz `db:"available_at" json:"available_at"`
+	PublishedAt       pgtype.Timestamptz `db:"published_at" json:"published_at"`
+	Attempts          int32              `db:"attempts" json:"attempts"`
+	LastError         pgtype.Text        `db:"last_error" json:"last_error"`
+	Delivered         bool               `db:"delivered" json:"delivered"`
+	CreatedAt         pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	PayloadCodec      string             `db:"payload_codec" json:"payload_codec"`
+	PayloadCompressed []byte             `db:"payload_compressed" json:"payload_compressed"`
 }
 
 type Execution struct {
@@ -52,6 +75,30 @@ type Execution struct {
 	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"created_at"`
 }
 
+type FeatureFlag struct {
+	Name      string             `db:"name" json:"name"`
+	Enabled   bool               `db:"enabled" json:"enabled"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type InstanceUsage struct {
+	Day        pgtype.Date        `db:"day" json:"day"`
+	InstanceID string             `db:"instance_id" json:"instance_id"`
+	Strategy   string             `db:"strategy" json:"strategy"`
+	Labels     []byte             `db:"labels" json:"labels"`
+	Events     int64              `db:"events" json:"events"`
+	Orders     int64              `db:"orders" json:"orders"`
+	HandlerUs  int64              `db:"handler_us" json:"handler_us"`
+	UpdatedAt  pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type OpenOrderSnapshot struct {
+	Provider string             `db:"provider" json:"provider"`
+	TakenAt  pgtype.Timestamptz `db:"taken_at" json:"taken_at"`
+	Orders   []byte             `db:"orders" json:"orders"`
+}
+
 type Order struct {
 	ID                 pgtype.UUID        `db:"id" json:"id"`
 	ProviderID         int64              `db:"provider_id" json:"provider_id"`
@@ -95,6 +142,14 @@ type ProviderRoute struct {
 	UpdatedAt  pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
+type RiskProfile struct {
+	Name        string             `db:"name" json:"name"`
+	Description string             `db:"description" json:"description"`
+	Limits      []byte             `db:"limits" json:"limits"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
 type StrategyInstance struct {
 	ID                 pgtype.UUID        `db:"id" json:"id"`
 	StrategyIdentifier string             `db:"strategy_identifier" json:"strategy_identifier"`
@@ -107,3 +162,35 @@ type StrategyInstance struct {
 	UpdatedAt          pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 	InstanceID         string             `db:"instance_id" json:"instance_id"`
 }
+
+type StrategyLog struct {
+	ID         int64              `db:"id" json:"id"`
+	InstanceID string             `db:"instance_id" json:"instance_id"`
+	Strategy   string             `db:"strategy" json:"strategy"`
+	Hash       string             `db:"hash" json:"hash"`
+	Level      string             `db:"level" json:"level"`
+	Severity   int16              `db:"severity" json:"severity"`
+	Source     string             `db:"source" json:"source"`
+	Message    string             `db:"message" json:"message"`
+	RecordedAt pgtype.Timestamptz `db:"recorded_at" json:"recorded_at"`
+}
+
+type StrategyReport struct {
+	ID          int64              `db:"id" json:"id"`
+	GeneratedAt pgtype.Timestamptz `db:"generated_at" json:"generated_at"`
+	Summary     string             `db:"summary" json:"summary"`
+	Report      []byte             `db:"report" json:"report"`
+}
+
+type StrategyRevisionHistory struct {
+	ID           int64              `db:"id" json:"id"`
+	Strategy     string             `db:"strategy" json:"strategy"`
+	Action       string             `db:"action" json:"action"`
+	Hash         string             `db:"hash" json:"hash"`
+	Tag          string             `db:"tag" json:"tag"`
+	PreviousHash string             `db:"previous_hash" json:"previous_hash"`
+	Actor        string             `db:"actor" json:"actor"`
+	Reason       string             `db:"reason" json:"reason"`
+	Metadata     []byte             `db:"metadata" json:"metadata"`
+	RecordedAt   pgtype.Timestamptz `db:"recorded_at" json:"recorded_at"`
+}
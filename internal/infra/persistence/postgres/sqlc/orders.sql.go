@@ -178,16 +178,28 @@ WHERE (
 ) AND (
     $3::text[] IS NULL
     OR o.state = ANY($3::text[])
+) AND (
+    $4::timestamptz IS NULL
+    OR o.placed_at >= $4::timestamptz
+) AND (
+    $5::timestamptz IS NULL
+    OR o.placed_at < $5::timestamptz
+) AND (
+    $6::text IS NULL
+    OR o.metadata -> 'tags' @> jsonb_build_array($6::text)
 )
 ORDER BY o.placed_at DESC
-LIMIT $4::int
+LIMIT $7::int
 `
 
 type ListOrdersParams struct {
-	StrategyInstance pgtype.Text `db:"strategy_instance" json:"strategy_instance"`
-	ProviderAlias    pgtype.Text `db:"provider_alias" json:"provider_alias"`
-	States           []string    `db:"states" json:"states"`
-	Limit            int32       `db:"limit" json:"limit"`
+	StrategyInstance pgtype.Text        `db:"strategy_instance" json:"strategy_instance"`
+	ProviderAlias    pgtype.Text        `db:"provider_alias" json:"provider_alias"`
+	States           []string           `db:"states" json:"states"`
+	PlacedSince      pgtype.Timestamptz `db:"placed_since" json:"placed_since"`
+	PlacedUntil      pgtype.Timestamptz `db:"placed_until" json:"placed_until"`
+	Tag              pgtype.Text        `db:"tag" json:"tag"`
+	Limit            int32              `db:"limit" json:"limit"`
 }
 
 type ListOrdersRow struct {
@@ -215,6 +227,9 @@ func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]ListO
 		arg.StrategyInstance,
 		arg.ProviderAlias,
 		arg.States,
+		arg.PlacedSince,
+		arg.PlacedUntil,
+		arg.Tag,
 		arg.Limit,
 	)
 	if err != nil {
@@ -310,7 +325,7 @@ SET
     state = $1::text,
     acknowledged_at = COALESCE($2::timestamptz, acknowledged_at),
     completed_at = COALESCE($3::timestamptz, completed_at),
-    metadata = COALESCE($4::jsonb, metadata),
+    metadata = metadata || COALESCE($4::jsonb, '{}'::jsonb),
     updated_at = NOW()
 WHERE id = $5::uuid
 RETURNING id, provider_id, strategy_instance_id, client_order_id, instrument, side, order_type, quantity, price, state, external_order_ref, placed_at, acknowledged_at, completed_at, metadata, created_at, updated_at
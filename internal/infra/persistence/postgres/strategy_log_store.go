@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+	"github.com/coachpo/meltica/internal/infra/persistence/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const defaultStrategyLogLimit = 200
+
+// StrategyLogStore persists strategy console logs and diagnostics in PostgreSQL.
+type StrategyLogStore struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+// NewStrategyLogStore constructs a StrategyLogStore backed by the provided pgx pool.
+func NewStrategyLogStore(pool *pgxpool.Pool) *StrategyLogStore {
+	if pool == nil {
+		return &StrategyLogStore{pool: nil, queries: nil}
+	}
+	return &StrategyLogStore{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+func (s *StrategyLogStore) ensureQueries() (*sqlc.Queries, error) {
+	if s.pool == nil || s.queries == nil {
+		return nil, fmt.Errorf("strategy log store: nil pool")
+	}
+	return s.queries, nil
+}
+
+// AppendLogs inserts a batch of log lines in a single statement.
+func (s *StrategyLogStore) AppendLogs(ctx context.Context, entries []strategylogstore.Entry) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	params := sqlc.InsertStrategyLogsParams{
+		InstanceIds: make([]string, 0, len(entries)),
+		Strategies:  make([]string, 0, len(entries)),
+		Hashes:      make([]string, 0, len(entries)),
+		Levels:      make([]string, 0, len(entries)),
+		Severities:  make([]int16, 0, len(entries)),
+		Sources:     make([]string, 0, len(entries)),
+		Messages:    make([]string, 0, len(entries)),
+		RecordedAts: make([]pgtype.Timestamptz, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		instanceID := strings.TrimSpace(entry.InstanceID)
+		if instanceID == "" {
+			return fmt.Errorf("strategy log store: instance id required")
+		}
+		level, ok := strategylogstore.NormalizeLevel(entry.Level)
+		if !ok {
+			level = strategylogstore.LevelInfo
+		}
+		recordedAt := entry.RecordedAt
+		if recordedAt.IsZero() {
+			recordedAt = time.Now()
+		}
+		params.InstanceIds = append(params.InstanceIds, instanceID)
+		params.Strategies = append(params.Strategies, entry.Strategy)
+		params.Hashes = append(params.Hashes, entry.Hash)
+		params.Levels = append(params.Levels, level)
+		params.Severities = append(params.Severities, int16(strategylogstore.Severity(level)))
+		params.Sources = append(params.Sources, entry.Source)
+		params.Messages = append(params.Messages, strings.ToValidUTF8(strings.ReplaceAll(entry.Message, "\x00", ""), "�"))
+		params.RecordedAts = append(params.RecordedAts, timestamptzFromTime(recordedAt))
+	}
+	if err := q.InsertStrategyLogs(ctx, params); err != nil {
+		return fmt.Errorf("insert strategy logs: %w", err)
+	}
+	return nil
+}
+
+// ListLogs returns the log lines selected by query. Lines starting at query.From are returned
+// oldest first; without From the most recent lines are returned, also oldest first.
+func (s *StrategyLogStore) ListLogs(ctx context.Context, query strategylogstore.Query) ([]strategylogstore.Entry, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	instanceID := strings.TrimSpace(query.InstanceID)
+	if instanceID == "" {
+		return nil, fmt.Errorf("strategy log store: instance id required")
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultStrategyLogLimit
+	}
+	minSeverity := int16(0)
+	if query.MinLevel != "" {
+		minSeverity = int16(strategylogstore.Severity(query.MinLevel))
+	}
+	var rows []sqlc.StrategyLog
+	if query.From.IsZero() {
+		rows, err = q.ListRecentStrategyLogs(ctx, sqlc.ListRecentStrategyLogsParams{
+			InstanceID:  instanceID,
+			MinSeverity: minSeverity,
+			RowLimit:    int32(min(limit, math.MaxInt32)),
+		})
+		slices.Reverse(rows)
+	} else {
+		rows, err = q.ListStrategyLogsFrom(ctx, sqlc.ListStrategyLogsFromParams{
+			InstanceID:   instanceID,
+			MinSeverity:  minSeverity,
+			RecordedFrom: timestamptzFromTime(query.From),
+			RowLimit:     int32(min(limit, math.MaxInt32)),
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list strategy logs %s: %w", instanceID, err)
+	}
+	entries := make([]strategylogstore.Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, strategylogstore.Entry{
+			ID:         row.ID,
+			InstanceID: row.InstanceID,
+			Strategy:   row.Strategy,
+			Hash:       row.Hash,
+			Level:      row.Level,
+			Source:     row.Source,
+			Message:    row.Message,
+			RecordedAt: row.RecordedAt.Time,
+		})
+	}
+	return entries, nil
+}
+
+// PurgeLogs deletes log lines recorded before the cutoff.
+func (s *StrategyLogStore) PurgeLogs(ctx context.Context, before time.Time) (int64, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return 0, err
+	}
+	removed, err := q.DeleteStrategyLogsBefore(ctx, timestamptzFromTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("purge strategy logs: %w", err)
+	}
+	return removed, nil
+}
+
+var _ strategylogstore.Store = (*StrategyLogStore)(nil)
@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+)
+
+func TestStrategyLogStoreNilPool(t *testing.T) {
+	store := NewStrategyLogStore(nil)
+	ctx := context.Background()
+	if err := store.AppendLogs(ctx, []strategylogstore.Entry{{InstanceID: "alpha", Level: "info", Message: "hello"}}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.ListLogs(ctx, strategylogstore.Query{InstanceID: "alpha"}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.PurgeLogs(ctx, time.Now()); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+}
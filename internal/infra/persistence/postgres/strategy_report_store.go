@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/strategyreportstore"
+	"github.com/coachpo/meltica/internal/infra/persistence/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StrategyReportStore persists strategy usage and registry reports in PostgreSQL.
+type StrategyReportStore struct {
+	pool    *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+// NewStrategyReportStore constructs a StrategyReportStore backed by the provided pgx pool.
+func NewStrategyReportStore(pool *pgxpool.Pool) *StrategyReportStore {
+	if pool == nil {
+		return &StrategyReportStore{pool: nil, queries: nil}
+	}
+	return &StrategyReportStore{
+		pool:    pool,
+		queries: sqlc.New(pool),
+	}
+}
+
+func (s *StrategyReportStore) ensureQueries() (*sqlc.Queries, error) {
+	if s.pool == nil || s.queries == nil {
+		return nil, fmt.Errorf("strategy report store: nil pool")
+	}
+	return s.queries, nil
+}
+
+// SaveReport inserts a report and returns its identifier.
+func (s *StrategyReportStore) SaveReport(ctx context.Context, entry strategyreportstore.Entry) (int64, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return 0, err
+	}
+	if len(entry.Report) == 0 {
+		return 0, fmt.Errorf("strategy report store: report document required")
+	}
+	generatedAt := entry.GeneratedAt
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+	id, err := q.InsertStrategyReport(ctx, sqlc.InsertStrategyReportParams{
+		GeneratedAt: timestamptzFromTime(generatedAt),
+		Summary:     entry.Summary,
+		Report:      entry.Report,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("insert strategy report: %w", err)
+	}
+	return id, nil
+}
+
+// ListReports returns up to limit reports, newest first, without their documents.
+func (s *StrategyReportStore) ListReports(ctx context.Context, limit int) ([]strategyreportstore.Entry, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = math.MaxInt32
+	}
+	rows, err := q.ListStrategyReports(ctx, int32(min(limit, math.MaxInt32)))
+	if err != nil {
+		return nil, fmt.Errorf("list strategy reports: %w", err)
+	}
+	entries := make([]strategyreportstore.Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, strategyreportstore.Entry{
+			ID:          row.ID,
+			GeneratedAt: row.GeneratedAt.Time,
+			Summary:     row.Summary,
+			Report:      nil,
+		})
+	}
+	return entries, nil
+}
+
+// Report returns the report with the identifier.
+func (s *StrategyReportStore) Report(ctx context.Context, id int64) (strategyreportstore.Entry, error) {
+	var empty strategyreportstore.Entry
+	q, err := s.ensureQueries()
+	if err != nil {
+		return empty, err
+	}
+	row, err := q.GetStrategyReport(ctx, id)
+	return strategyReportEntry(row, err, fmt.Sprintf("strategy report %d", id))
+}
+
+// LatestReport returns the newest report.
+func (s *StrategyReportStore) LatestReport(ctx context.Context) (strategyreportstore.Entry, error) {
+	var empty strategyreportstore.Entry
+	q, err := s.ensureQueries()
+	if err != nil {
+		return empty, err
+	}
+	row, err := q.GetLatestStrategyReport(ctx)
+	return strategyReportEntry(row, err, "latest strategy report")
+}
+
+// PurgeReports deletes reports generated before the cutoff.
+func (s *StrategyReportStore) PurgeReports(ctx context.Context, before time.Time) (int64, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return 0, err
+	}
+	removed, err := q.DeleteStrategyReportsBefore(ctx, timestamptzFromTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("purge strategy reports: %w", err)
+	}
+	return removed, nil
+}
+
+func strategyReportEntry(row sqlc.StrategyReport, err error, what string) (strategyreportstore.Entry, error) {
+	var empty strategyreportstore.Entry
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return empty, fmt.Errorf("%s: %w", what, strategyreportstore.ErrNotFound)
+		}
+		return empty, fmt.Errorf("get %s: %w", what, err)
+	}
+	return strategyreportstore.Entry{
+		ID:          row.ID,
+		GeneratedAt: row.GeneratedAt.Time,
+		Summary:     row.Summary,
+		Report:      row.Report,
+	}, nil
+}
+
+var _ strategyreportstore.Store = (*StrategyReportStore)(nil)
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/strategyreportstore"
+)
+
+func TestStrategyReportStoreNilPool(t *testing.T) {
+	store := NewStrategyReportStore(nil)
+	ctx := context.Background()
+	if _, err := store.SaveReport(ctx, strategyreportstore.Entry{Report: []byte("{}")}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.ListReports(ctx, 10); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.Report(ctx, 1); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.LatestReport(ctx); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.PurgeReports(ctx, time.Now()); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/coachpo/meltica/internal/domain/strategystore"
@@ -118,6 +119,9 @@ func (s *StrategyStore) Load(ctx context.Context) ([]strategystore.Snapshot, err
 			Strategy:        decoded.Strategy,
 			Providers:       decoded.Providers,
 			ProviderSymbols: decoded.ProviderSymbols,
+			SubAccounts:     decoded.SubAccounts,
+			Labels:          decoded.Labels,
+			DryRun:          decoded.DryRun,
 			Running:         strings.EqualFold(strings.TrimSpace(row.Status), "running"),
 			Dynamic:         decoded.Dynamic,
 			Baseline:        decoded.Baseline,
@@ -135,10 +139,86 @@ func (s *StrategyStore) Load(ctx context.Context) ([]strategystore.Snapshot, err
 	return snapshots, nil
 }
 
+// AppendHistory records a strategy changelog entry.
+func (s *StrategyStore) AppendHistory(ctx context.Context, entry strategystore.HistoryEntry) error {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return err
+	}
+	strategy := strings.TrimSpace(entry.Strategy)
+	if strategy == "" {
+		return fmt.Errorf("strategy store: history strategy required")
+	}
+	metadata := []byte("{}")
+	if len(entry.Metadata) > 0 {
+		metadata, err = json.Marshal(entry.Metadata)
+		if err != nil {
+			return fmt.Errorf("strategy store: encode history metadata: %w", err)
+		}
+	}
+	params := sqlc.InsertStrategyHistoryParams{
+		Strategy:     strategy,
+		Action:       strings.TrimSpace(entry.Action),
+		Hash:         strings.TrimSpace(entry.Hash),
+		Tag:          strings.TrimSpace(entry.Tag),
+		PreviousHash: strings.TrimSpace(entry.PreviousHash),
+		Actor:        strings.TrimSpace(entry.Actor),
+		Reason:       strings.TrimSpace(entry.Reason),
+		Metadata:     metadata,
+	}
+	if err := q.InsertStrategyHistory(ctx, params); err != nil {
+		return fmt.Errorf("strategy store: insert history: %w", err)
+	}
+	return nil
+}
+
+// ListHistory returns the most recent changelog entries for a strategy, newest first.
+func (s *StrategyStore) ListHistory(ctx context.Context, strategy string, limit int) ([]strategystore.HistoryEntry, error) {
+	q, err := s.ensureQueries()
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > math.MaxInt32 {
+		limit = math.MaxInt32
+	}
+	rows, err := q.ListStrategyHistory(ctx, sqlc.ListStrategyHistoryParams{
+		Strategy: strings.TrimSpace(strategy),
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("strategy store: list history: %w", err)
+	}
+	entries := make([]strategystore.HistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		var metadata map[string]any
+		if len(row.Metadata) > 0 {
+			if err := json.Unmarshal(row.Metadata, &metadata); err != nil {
+				return nil, fmt.Errorf("strategy store: decode history metadata: %w", err)
+			}
+		}
+		entries = append(entries, strategystore.HistoryEntry{
+			ID:           row.ID,
+			Strategy:     row.Strategy,
+			Action:       row.Action,
+			Hash:         row.Hash,
+			Tag:          row.Tag,
+			PreviousHash: row.PreviousHash,
+			Actor:        row.Actor,
+			Reason:       row.Reason,
+			Metadata:     metadata,
+			RecordedAt:   row.RecordedAt.Time,
+		})
+	}
+	return entries, nil
+}
+
 type strategyMetadata struct {
 	Strategy        strategystore.Strategy `json:"strategy"`
 	Providers       []string               `json:"providers"`
 	ProviderSymbols map[string][]string    `json:"providerSymbols"`
+	SubAccounts     map[string]string      `json:"subAccounts,omitempty"`
+	Labels          map[string]string      `json:"labels,omitempty"`
+	DryRun          *bool                  `json:"dryRun,omitempty"`
 	Dynamic         bool                   `json:"dynamic"`
 	Baseline        bool                   `json:"baseline"`
 	Metadata        map[string]any         `json:"metadata"`
@@ -155,6 +235,9 @@ func encodeStrategyMetadata(snapshot strategystore.Snapshot) (strategyMetadata,
 		},
 		Providers:       cloneStringSlice(snapshot.Providers),
 		ProviderSymbols: cloneProviderSymbols(snapshot.ProviderSymbols),
+		SubAccounts:     cloneStringMap(snapshot.SubAccounts),
+		Labels:          cloneStringMap(snapshot.Labels),
+		DryRun:          snapshot.DryRun,
 		Dynamic:         snapshot.Dynamic,
 		Baseline:        snapshot.Baseline,
 		Metadata:        cloneMap(snapshot.Metadata),
@@ -174,6 +257,9 @@ func decodeStrategyMetadata(raw []byte) (strategyMetadata, error) {
 			},
 			Providers:       []string{},
 			ProviderSymbols: map[string][]string{},
+			SubAccounts:     nil,
+			Labels:          nil,
+			DryRun:          nil,
 			Dynamic:         false,
 			Baseline:        false,
 			Metadata:        make(map[string]any),
@@ -201,12 +287,14 @@ func computeStrategyConfigHash(snapshot strategystore.Snapshot) string {
 		Selector   string              `json:"selector"`
 		Providers  []string            `json:"providers"`
 		Symbols    map[string][]string `json:"symbols"`
+		Accounts   map[string]string   `json:"subAccounts,omitempty"`
 		Config     map[string]any      `json:"config"`
 	}{
 		Identifier: strings.TrimSpace(snapshot.Strategy.Identifier),
 		Selector:   strings.TrimSpace(snapshot.Strategy.Selector),
 		Providers:  cloneStringSlice(snapshot.Providers),
 		Symbols:    cloneProviderSymbols(snapshot.ProviderSymbols),
+		Accounts:   cloneStringMap(snapshot.SubAccounts),
 		Config:     cloneMap(snapshot.Strategy.Config),
 	}
 	data, err := json.Marshal(payload)
@@ -237,6 +325,17 @@ func cloneProviderSymbols(in map[string][]string) map[string][]string {
 	return out
 }
 
+func cloneStringMap(in map[string]string) map[string]string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
 func cloneMap(in map[string]any) map[string]any {
 	if len(in) == 0 {
 		return nil
@@ -247,3 +346,8 @@ func cloneMap(in map[string]any) map[string]any {
 	}
 	return out
 }
+
+var (
+	_ strategystore.Store        = (*StrategyStore)(nil)
+	_ strategystore.HistoryStore = (*StrategyStore)(nil)
+)
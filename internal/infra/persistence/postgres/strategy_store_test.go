@@ -20,4 +20,10 @@ func TestStrategyStoreNilPool(t *testing.T) {
 	if _, err := store.Load(ctx); err == nil {
 		t.Fatalf("expected error when pool nil")
 	}
+	if err := store.AppendHistory(ctx, strategystore.HistoryEntry{Strategy: "alpha"}); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
+	if _, err := store.ListHistory(ctx, "alpha", 10); err == nil {
+		t.Fatalf("expected error when pool nil")
+	}
 }
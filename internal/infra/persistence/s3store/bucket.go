@@ -0,0 +1,159 @@
+// Package s3store keeps strategy code and datasets in an S3-compatible bucket.
+package s3store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/coachpo/meltica/internal/domain/objectstore"
+)
+
+// streamPartSize bounds the memory an upload of unknown size buffers; the SDK default is sized
+// for 5 TiB objects.
+const streamPartSize = 16 << 20
+
+// Options locates the bucket and the credentials used to reach it.
+type Options struct {
+	// Endpoint is host[:port] without a scheme.
+	Endpoint string
+	Region   string
+	Bucket   string
+	// AccessKeyID and SecretAccessKey fall back to the AWS_* and MINIO_* environment variables and
+	// then to instance credentials when empty.
+	AccessKeyID     string
+	SecretAccessKey string
+	Insecure        bool
+}
+
+// Client is a connection to one bucket, shared by the prefixes handed out by Bucket.
+type Client struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewClient connects to the bucket described by opts. It does not contact the endpoint.
+func NewClient(opts Options) (*Client, error) {
+	if strings.TrimSpace(opts.Bucket) == "" {
+		return nil, fmt.Errorf("object store bucket required")
+	}
+	creds := credentials.NewStaticV4(opts.AccessKeyID, opts.SecretAccessKey, "")
+	if opts.AccessKeyID == "" {
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.EnvMinio{},
+			&credentials.IAM{},
+		})
+	}
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: !opts.Insecure,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("object store client: %w", err)
+	}
+	return &Client{client: client, bucket: opts.Bucket}, nil
+}
+
+// Ping checks that the bucket exists and the credentials can reach it.
+func (c *Client) Ping(ctx context.Context) error {
+	exists, err := c.client.BucketExists(ctx, c.bucket)
+	if err != nil {
+		return fmt.Errorf("object store: check bucket %q: %w", c.bucket, err)
+	}
+	if !exists {
+		return fmt.Errorf("object store: bucket %q does not exist", c.bucket)
+	}
+	return nil
+}
+
+// Bucket returns the objects under prefix, which ends with a slash or is empty.
+func (c *Client) Bucket(prefix string) *Bucket {
+	return &Bucket{client: c.client, bucket: c.bucket, prefix: prefix}
+}
+
+// Bucket implements objectstore.Bucket for one key prefix.
+type Bucket struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+var _ objectstore.Bucket = (*Bucket)(nil)
+
+// List returns the objects whose keys start with prefix, in key order.
+func (b *Bucket) List(ctx context.Context, prefix string) ([]objectstore.Object, error) {
+	var out []objectstore.Object
+	for info := range b.client.ListObjects(ctx, b.bucket, minio.ListObjectsOptions{Prefix: b.prefix + prefix, Recursive: true}) {
+		if info.Err != nil {
+			return nil, fmt.Errorf("object store: list %q: %w", b.prefix+prefix, info.Err)
+		}
+		out = append(out, objectstore.Object{
+			Key:     strings.TrimPrefix(info.Key, b.prefix),
+			Size:    info.Size,
+			ModTime: info.LastModified,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// Get opens an object for reading.
+func (b *Bucket) Get(ctx context.Context, key string) (io.ReadCloser, objectstore.Object, error) {
+	var empty objectstore.Object
+	object, err := b.client.GetObject(ctx, b.bucket, b.prefix+key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, empty, b.wrap("get", key, err)
+	}
+	// GetObject is lazy; Stat issues the request and surfaces missing keys.
+	info, err := object.Stat()
+	if err != nil {
+		_ = object.Close()
+		return nil, empty, b.wrap("get", key, err)
+	}
+	return object, objectstore.Object{Key: key, Size: info.Size, ModTime: info.LastModified}, nil
+}
+
+// Put stores size bytes from body under key. A negative size streams body to its end in
+// multipart chunks of streamPartSize.
+func (b *Bucket) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	opts := minio.PutObjectOptions{ContentType: contentType}
+	if size < 0 {
+		opts.PartSize = streamPartSize
+	}
+	if _, err := b.client.PutObject(ctx, b.bucket, b.prefix+key, body, size, opts); err != nil {
+		return b.wrap("put", key, err)
+	}
+	return nil
+}
+
+// Delete removes an object. S3 treats deleting a missing key as success.
+func (b *Bucket) Delete(ctx context.Context, key string) error {
+	if err := b.client.RemoveObject(ctx, b.bucket, b.prefix+key, minio.RemoveObjectOptions{}); err != nil {
+		return b.wrap("delete", key, err)
+	}
+	return nil
+}
+
+func (b *Bucket) wrap(op, key string, err error) error {
+	if isNotFound(err) {
+		return fmt.Errorf("object store: %s %q: %w", op, b.prefix+key, objectstore.ErrNotFound)
+	}
+	return fmt.Errorf("object store: %s %q: %w", op, b.prefix+key, err)
+}
+
+func isNotFound(err error) bool {
+	var response minio.ErrorResponse
+	if !errors.As(err, &response) {
+		return false
+	}
+	return response.Code == minio.NoSuchKey || response.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,229 @@
+package s3store
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/google/uuid"
+
+	"github.com/coachpo/meltica/internal/domain/datasetstore"
+	"github.com/coachpo/meltica/internal/domain/objectstore"
+)
+
+const datasetMetadataObject = "dataset.json"
+
+// DatasetStore keeps each dataset under a key prefix named by its identifier, holding the content
+// object and a dataset.json metadata object. Content is uploaded before the metadata, so listings,
+// which only see datasets with metadata, never include partial uploads. Name uniqueness is checked
+// per gateway; two nodes creating the same name at once can both succeed.
+type DatasetStore struct {
+	bucket objectstore.Bucket
+	now    func() time.Time
+	// mu serializes metadata changes made by this gateway.
+	mu sync.Mutex
+}
+
+var _ datasetstore.Store = (*DatasetStore)(nil)
+
+// NewDatasetStore returns a store keeping datasets in bucket.
+func NewDatasetStore(bucket objectstore.Bucket) *DatasetStore {
+	return &DatasetStore{bucket: bucket, now: time.Now, mu: sync.Mutex{}}
+}
+
+// Create uploads content under a new identifier, then publishes its metadata unless the name is
+// taken.
+func (s *DatasetStore) Create(ctx context.Context, dataset datasetstore.Dataset, content io.Reader) (datasetstore.Dataset, error) {
+	var empty datasetstore.Dataset
+	if err := datasetstore.Normalize(&dataset); err != nil {
+		return empty, err
+	}
+	dataset.ID = uuid.NewString()
+	contentKey := path.Join(dataset.ID, datasetstore.FileName(dataset.Format))
+	counted := &hashingReader{r: content, hash: sha256.New(), size: 0}
+	if err := s.bucket.Put(ctx, contentKey, counted, -1, datasetstore.ContentType(dataset.Format)); err != nil {
+		_ = s.bucket.Delete(context.WithoutCancel(ctx), contentKey)
+		return empty, fmt.Errorf("upload dataset: %w", err)
+	}
+	now := s.now().UTC()
+	dataset.SizeBytes = counted.size
+	dataset.SHA256 = hex.EncodeToString(counted.hash.Sum(nil))
+	dataset.CreatedAt = now
+	dataset.UpdatedAt = now
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, err := s.listLocked(ctx)
+	if err == nil {
+		for _, other := range existing {
+			if other.Name == dataset.Name {
+				err = datasetstore.ErrNameTaken
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = s.writeMetadata(ctx, dataset)
+	}
+	if err != nil {
+		_ = s.bucket.Delete(context.WithoutCancel(ctx), contentKey)
+		return empty, err
+	}
+	return dataset, nil
+}
+
+// List returns the datasets matching query, oldest first.
+func (s *DatasetStore) List(ctx context.Context, query datasetstore.Query) ([]datasetstore.Dataset, error) {
+	s.mu.Lock()
+	all, err := s.listLocked(ctx)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]datasetstore.Dataset, 0, len(all))
+	for _, dataset := range all {
+		if query.Matches(dataset) {
+			out = append(out, dataset)
+		}
+	}
+	return out, nil
+}
+
+// Get returns the metadata of a dataset.
+func (s *DatasetStore) Get(ctx context.Context, id string) (datasetstore.Dataset, error) {
+	return s.readMetadata(ctx, id)
+}
+
+// Open streams the content of a dataset from the bucket.
+func (s *DatasetStore) Open(ctx context.Context, id string) (io.ReadCloser, datasetstore.Dataset, error) {
+	dataset, err := s.readMetadata(ctx, id)
+	if err != nil {
+		return nil, dataset, err
+	}
+	body, _, err := s.bucket.Get(ctx, path.Join(dataset.ID, datasetstore.FileName(dataset.Format)))
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return nil, dataset, datasetstore.ErrNotFound
+	}
+	if err != nil {
+		return nil, dataset, fmt.Errorf("open dataset: %w", err)
+	}
+	return body, dataset, nil
+}
+
+// SetTags replaces the tags of a dataset.
+func (s *DatasetStore) SetTags(ctx context.Context, id string, tags []string) (datasetstore.Dataset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dataset, err := s.readMetadata(ctx, id)
+	if err != nil {
+		return dataset, err
+	}
+	dataset.Tags = datasetstore.NormalizeTags(tags)
+	dataset.UpdatedAt = s.now().UTC()
+	if err := s.writeMetadata(ctx, dataset); err != nil {
+		return datasetstore.Dataset{}, err
+	}
+	return dataset, nil
+}
+
+// Delete removes the metadata of a dataset, which unlists it, and then its content.
+func (s *DatasetStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dataset, err := s.readMetadata(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.bucket.Delete(ctx, path.Join(dataset.ID, datasetMetadataObject)); err != nil {
+		return fmt.Errorf("delete dataset: %w", err)
+	}
+	if err := s.bucket.Delete(ctx, path.Join(dataset.ID, datasetstore.FileName(dataset.Format))); err != nil {
+		return fmt.Errorf("delete dataset content: %w", err)
+	}
+	return nil
+}
+
+func (s *DatasetStore) listLocked(ctx context.Context) ([]datasetstore.Dataset, error) {
+	objects, err := s.bucket.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("list datasets: %w", err)
+	}
+	out := make([]datasetstore.Dataset, 0, len(objects))
+	for _, object := range objects {
+		id, name, ok := strings.Cut(object.Key, "/")
+		if !ok || name != datasetMetadataObject {
+			continue
+		}
+		dataset, err := s.readMetadata(ctx, id)
+		if errors.Is(err, datasetstore.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, dataset)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func (s *DatasetStore) readMetadata(ctx context.Context, id string) (datasetstore.Dataset, error) {
+	var dataset datasetstore.Dataset
+	parsed, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return dataset, datasetstore.ErrNotFound
+	}
+	raw, err := objectstore.ReadAll(ctx, s.bucket, path.Join(parsed.String(), datasetMetadataObject))
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return dataset, datasetstore.ErrNotFound
+	}
+	if err != nil {
+		return dataset, fmt.Errorf("read dataset metadata: %w", err)
+	}
+	if err := json.Unmarshal(raw, &dataset); err != nil {
+		return dataset, fmt.Errorf("decode dataset metadata: %w", err)
+	}
+	return dataset, nil
+}
+
+func (s *DatasetStore) writeMetadata(ctx context.Context, dataset datasetstore.Dataset) error {
+	raw, err := json.MarshalIndent(dataset, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode dataset metadata: %w", err)
+	}
+	key := path.Join(dataset.ID, datasetMetadataObject)
+	if err := s.bucket.Put(ctx, key, bytes.NewReader(raw), int64(len(raw)), "application/json"); err != nil {
+		return fmt.Errorf("write dataset metadata: %w", err)
+	}
+	return nil
+}
+
+// hashingReader counts and hashes content as the bucket consumes it.
+type hashingReader struct {
+	r    io.Reader
+	hash hash.Hash
+	size int64
+}
+
+func (h *hashingReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	h.size += int64(n)
+	_, _ = h.hash.Write(p[:n])
+	return n, err //nolint:wrapcheck // io.EOF must reach the uploader unwrapped
+}
@@ -0,0 +1,120 @@
+package s3store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/datasetstore"
+	"github.com/coachpo/meltica/internal/domain/objectstore"
+)
+
+type memBucket struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (b *memBucket) List(_ context.Context, prefix string) ([]objectstore.Object, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []objectstore.Object
+	for key, data := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			out = append(out, objectstore.Object{Key: key, Size: int64(len(data))})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+func (b *memBucket) Get(_ context.Context, key string) (io.ReadCloser, objectstore.Object, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.objects[key]
+	if !ok {
+		return nil, objectstore.Object{}, objectstore.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), objectstore.Object{Key: key, Size: int64(len(data))}, nil
+}
+
+func (b *memBucket) Put(_ context.Context, key string, body io.Reader, _ int64, _ string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = data
+	return nil
+}
+
+func (b *memBucket) Delete(_ context.Context, key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.objects, key)
+	return nil
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("client went away") }
+
+func TestDatasetStoreLifecycle(t *testing.T) {
+	ctx := context.Background()
+	bucket := &memBucket{objects: make(map[string][]byte)}
+	store := NewDatasetStore(bucket)
+
+	content := "open_time,close_time,open,high,low,close,volume\n1,2,10,11,9,10.5,3\n"
+	created, err := store.Create(ctx, datasetstore.Dataset{Name: "btc-1m", Provider: "Binance", Symbol: "btc-usdt", Tags: []string{"q4"}}, strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.SizeBytes != int64(len(content)) || created.SHA256 == "" || created.Symbol != "BTC-USDT" {
+		t.Fatalf("unexpected dataset %+v", created)
+	}
+	if _, err := store.Create(ctx, datasetstore.Dataset{Name: "btc-1m"}, strings.NewReader("x")); !errors.Is(err, datasetstore.ErrNameTaken) {
+		t.Fatalf("expected name conflict, got %v", err)
+	}
+	if _, err := store.Create(ctx, datasetstore.Dataset{Name: "broken"}, failingReader{}); err == nil {
+		t.Fatal("expected a failed upload to fail")
+	}
+	if len(bucket.objects) != 2 {
+		t.Fatalf("expected only the first dataset's objects in the bucket, got %d", len(bucket.objects))
+	}
+
+	reader, _, err := store.Open(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	raw, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(raw) != content {
+		t.Fatalf("unexpected content %q", raw)
+	}
+
+	if _, err := store.SetTags(ctx, created.ID, []string{"golden"}); err != nil {
+		t.Fatalf("set tags: %v", err)
+	}
+	listed, err := store.List(ctx, datasetstore.Query{Tag: "golden"})
+	if err != nil || len(listed) != 1 || listed[0].ID != created.ID {
+		t.Fatalf("unexpected list %+v %v", listed, err)
+	}
+
+	if err := store.Delete(ctx, created.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(bucket.objects) != 0 {
+		t.Fatalf("expected the bucket emptied, %d objects left", len(bucket.objects))
+	}
+	if _, err := store.Get(ctx, created.ID); !errors.Is(err, datasetstore.ErrNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	if _, err := store.Get(ctx, "../etc"); !errors.Is(err, datasetstore.ErrNotFound) {
+		t.Fatalf("expected invalid identifiers reported as not found, got %v", err)
+	}
+}
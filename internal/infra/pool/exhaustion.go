@@ -0,0 +1,127 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// ErrPoolExhausted indicates every pooled object is leased and the pool's policy refused to wait.
+var ErrPoolExhausted = errors.New("pool: exhausted")
+
+// ExhaustionPolicy selects how Get behaves when every pooled object is leased.
+type ExhaustionPolicy string
+
+const (
+	// ExhaustionWait blocks until an object is returned, bounded by the wait timeout when set.
+	ExhaustionWait ExhaustionPolicy = "wait"
+	// ExhaustionSpill allocates a short-lived object on the heap; it is discarded when returned.
+	ExhaustionSpill ExhaustionPolicy = "spill"
+	// ExhaustionReject fails the borrow immediately with ErrPoolExhausted.
+	ExhaustionReject ExhaustionPolicy = "reject"
+)
+
+const (
+	exhaustionOutcomeWaited   = "waited"
+	exhaustionOutcomeTimeout  = "timeout"
+	exhaustionOutcomeSpilled  = "spilled"
+	exhaustionOutcomeRejected = "rejected"
+
+	exhaustionLogInterval = 10 * time.Second
+)
+
+// PoolOption customises a pool at registration.
+//
+//nolint:revive // PoolOption mirrors PoolManager naming.
+type PoolOption func(*objectPool)
+
+// WithExhaustionPolicy sets the exhaustion policy and, for ExhaustionWait, the maximum time a
+// borrower waits before failing with ErrPoolExhausted. A zero timeout waits for the caller's context.
+func WithExhaustionPolicy(policy ExhaustionPolicy, waitTimeout time.Duration) PoolOption {
+	return func(op *objectPool) {
+		switch policy {
+		case ExhaustionSpill, ExhaustionReject:
+			op.policy = policy
+		default:
+			op.policy = ExhaustionWait
+		}
+		op.waitTimeout = max(waitTimeout, 0)
+	}
+}
+
+// exhausted reports whether every pooled object is currently leased.
+func (op *objectPool) exhausted() bool {
+	return op.getAvailable() <= 0
+}
+
+// spill allocates an untracked object outside the pool's capacity.
+func (op *objectPool) spill() PooledObject {
+	obj := op.factory()
+	obj.Reset()
+	obj.SetReturned(false)
+	op.spilled.Store(pointerKey(obj), struct{}{})
+	return obj
+}
+
+// releaseSpilled drops a heap-allocated overflow object, reporting whether obj was one.
+func (op *objectPool) releaseSpilled(obj PooledObject) bool {
+	if _, ok := op.spilled.LoadAndDelete(pointerKey(obj)); !ok {
+		return false
+	}
+	obj.Reset()
+	obj.SetReturned(true)
+	return true
+}
+
+// getExhausted serves a borrow according to the pool's exhaustion policy.
+func (pm *PoolManager) getExhausted(ctx context.Context, pool *objectPool, poolName string) (PooledObject, error) {
+	switch pool.policy {
+	case ExhaustionReject:
+		pm.recordExhaustion(ctx, pool, poolName, exhaustionOutcomeRejected)
+		return nil, fmt.Errorf("pool manager: get %s: %w", poolName, ErrPoolExhausted)
+	case ExhaustionSpill:
+		pm.recordExhaustion(ctx, pool, poolName, exhaustionOutcomeSpilled)
+		return pool.spill(), nil
+	default:
+	}
+
+	waitCtx := ctx
+	if pool.waitTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, pool.waitTimeout)
+		defer cancel()
+	}
+	obj, err := pool.get(waitCtx)
+	if err != nil {
+		if pool.waitTimeout > 0 && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			pm.recordExhaustion(ctx, pool, poolName, exhaustionOutcomeTimeout)
+			return nil, fmt.Errorf("pool manager: get %s: waited %s: %w", poolName, pool.waitTimeout, ErrPoolExhausted)
+		}
+		return nil, fmt.Errorf("pool manager: get %s: %w", poolName, err)
+	}
+	pm.recordExhaustion(ctx, pool, poolName, exhaustionOutcomeWaited)
+	return obj, nil
+}
+
+func (pm *PoolManager) recordExhaustion(ctx context.Context, pool *objectPool, poolName, outcome string) {
+	if pm.exhaustionCounter != nil {
+		pm.exhaustionCounter.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("environment", telemetry.Environment()),
+			attribute.String("pool_name", poolName),
+			attribute.String("object_type", pool.getObjectType()),
+			attribute.String("policy", string(pool.policy)),
+			attribute.String("outcome", outcome)))
+	}
+	now := time.Now().UnixNano()
+	last := pool.lastExhaustionLog.Load()
+	if now-last >= int64(exhaustionLogInterval) && pool.lastExhaustionLog.CompareAndSwap(last, now) {
+		log.Printf("pool %s: exhausted (capacity=%d policy=%s outcome=%s)", poolName, pool.getCapacity(), pool.policy, outcome)
+	}
+}
@@ -0,0 +1,88 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func newExhaustionTestPool(t *testing.T, opts ...PoolOption) (*PoolManager, PooledObject) {
+	t.Helper()
+	pm := NewPoolManager()
+	t.Cleanup(func() {
+		_ = pm.Shutdown(context.Background())
+	})
+	if err := pm.RegisterPool("test-pool", 1, 0, func() any { return &schema.Event{} }, opts...); err != nil {
+		t.Fatalf("RegisterPool failed: %v", err)
+	}
+	held, err := pm.Get(context.Background(), "test-pool")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	return pm, held
+}
+
+func TestExhaustionRejectFailsFast(t *testing.T) {
+	pm, held := newExhaustionTestPool(t, WithExhaustionPolicy(ExhaustionReject, 0))
+	defer pm.Put("test-pool", held)
+
+	start := time.Now()
+	_, err := pm.Get(context.Background(), "test-pool")
+	if !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("expected ErrPoolExhausted, got %v", err)
+	}
+	if time.Since(start) > 100*time.Millisecond {
+		t.Fatalf("reject policy should not wait")
+	}
+}
+
+func TestExhaustionWaitTimesOut(t *testing.T) {
+	pm, held := newExhaustionTestPool(t, WithExhaustionPolicy(ExhaustionWait, 20*time.Millisecond))
+	defer pm.Put("test-pool", held)
+
+	_, err := pm.Get(context.Background(), "test-pool")
+	if !errors.Is(err, ErrPoolExhausted) {
+		t.Fatalf("expected ErrPoolExhausted after wait timeout, got %v", err)
+	}
+}
+
+func TestExhaustionWaitSucceedsWhenReturned(t *testing.T) {
+	pm, held := newExhaustionTestPool(t, WithExhaustionPolicy(ExhaustionWait, time.Second))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		pm.Put("test-pool", held)
+	}()
+	obj, err := pm.Get(context.Background(), "test-pool")
+	if err != nil {
+		t.Fatalf("expected borrow after return, got %v", err)
+	}
+	pm.Put("test-pool", obj)
+}
+
+func TestExhaustionSpillAllocatesOverflow(t *testing.T) {
+	pm, held := newExhaustionTestPool(t, WithExhaustionPolicy(ExhaustionSpill, 0))
+
+	spilled, err := pm.Get(context.Background(), "test-pool")
+	if err != nil {
+		t.Fatalf("expected spilled object, got %v", err)
+	}
+	if spilled == held {
+		t.Fatalf("expected a distinct heap-allocated object")
+	}
+	if spilled.IsReturned() {
+		t.Fatalf("spilled object should be marked as borrowed")
+	}
+	pm.Put("test-pool", spilled)
+	if !spilled.IsReturned() {
+		t.Fatalf("spilled object should be released on put")
+	}
+	pm.Put("test-pool", held)
+
+	if err := pm.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected clean shutdown after returning spilled object, got %v", err)
+	}
+}
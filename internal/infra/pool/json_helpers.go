@@ -4,38 +4,142 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sync"
 
 	json "github.com/goccy/go-json"
 )
 
-// EncodeJSON marshals the value to JSON bytes without HTML escaping.
-func EncodeJSON(v any) ([]byte, error) {
-	buf := &bytes.Buffer{}
-	encoder := json.NewEncoder(buf)
-	encoder.SetEscapeHTML(false)
-	if err := encoder.Encode(v); err != nil {
-		return nil, fmt.Errorf("json encode: %w", err)
+// maxPooledJSONBuffer caps the capacity of buffers kept for reuse so one oversized payload does
+// not pin its memory in the pool.
+const maxPooledJSONBuffer = 256 << 10
+
+var jsonBuffers = sync.Pool{
+	New: func() any {
+		return bytes.NewBuffer(make([]byte, 0, 1024))
+	},
+}
+
+func borrowJSONBuffer() *bytes.Buffer {
+	buf, ok := jsonBuffers.Get().(*bytes.Buffer)
+	if !ok {
+		return bytes.NewBuffer(make([]byte, 0, 1024))
 	}
-	data := buf.Bytes()
-	if len(data) > 0 && data[len(data)-1] == '\n' {
-		data = data[:len(data)-1]
+	buf.Reset()
+	return buf
+}
+
+func returnJSONBuffer(buf *bytes.Buffer) {
+	if buf == nil || buf.Cap() > maxPooledJSONBuffer {
+		return
 	}
-	return data, nil
+	jsonBuffers.Put(buf)
 }
 
-// WriteJSON encodes and writes JSON directly to the writer without HTML escaping.
-func WriteJSON(w io.Writer, v any) error {
-	buf := &bytes.Buffer{}
+// encodeInto appends the JSON encoding of v to buf without HTML escaping or a trailing newline.
+func encodeInto(buf *bytes.Buffer, v any) error {
 	encoder := json.NewEncoder(buf)
 	encoder.SetEscapeHTML(false)
 	if err := encoder.Encode(v); err != nil {
 		return fmt.Errorf("json encode: %w", err)
 	}
-	data := buf.Bytes()
-	if len(data) > 0 && data[len(data)-1] == '\n' {
-		data = data[:len(data)-1]
+	if n := buf.Len(); n > 0 && buf.Bytes()[n-1] == '\n' {
+		buf.Truncate(n - 1)
+	}
+	return nil
+}
+
+// JSONBuffer holds bytes in a pooled buffer. Release returns the buffer to the pool; the bytes
+// must not be used afterwards.
+type JSONBuffer struct {
+	buf *bytes.Buffer
+}
+
+// Bytes returns the buffered bytes, valid until Release.
+func (b *JSONBuffer) Bytes() []byte {
+	if b == nil || b.buf == nil {
+		return nil
+	}
+	return b.buf.Bytes()
+}
+
+// Len reports the number of buffered bytes.
+func (b *JSONBuffer) Len() int {
+	if b == nil || b.buf == nil {
+		return 0
+	}
+	return b.buf.Len()
+}
+
+// Release returns the buffer to the pool. It is safe to call more than once.
+func (b *JSONBuffer) Release() {
+	if b == nil || b.buf == nil {
+		return
+	}
+	returnJSONBuffer(b.buf)
+	b.buf = nil
+}
+
+// EncodeJSONBuffer encodes v into a pooled buffer without HTML escaping. It suits payloads that
+// are consumed before the caller moves on, such as outbox rows written synchronously.
+func EncodeJSONBuffer(v any) (*JSONBuffer, error) {
+	buf := borrowJSONBuffer()
+	if err := encodeInto(buf, v); err != nil {
+		returnJSONBuffer(buf)
+		return nil, err
+	}
+	return &JSONBuffer{buf: buf}, nil
+}
+
+// ReadJSONMessage reads a whole message, such as a websocket frame reader, into a pooled buffer
+// instead of allocating a fresh slice per message.
+func ReadJSONMessage(r io.Reader) (*JSONBuffer, error) {
+	buf := borrowJSONBuffer()
+	if _, err := buf.ReadFrom(r); err != nil {
+		returnJSONBuffer(buf)
+		return nil, fmt.Errorf("read json message: %w", err)
+	}
+	return &JSONBuffer{buf: buf}, nil
+}
+
+// EncodeJSON marshals the value to JSON bytes without HTML escaping.
+func EncodeJSON(v any) ([]byte, error) {
+	buf := borrowJSONBuffer()
+	defer returnJSONBuffer(buf)
+	if err := encodeInto(buf, v); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// AppendJSON appends the JSON encoding of v to dst without HTML escaping, for callers that reuse
+// their own buffers.
+func AppendJSON(dst []byte, v any) ([]byte, error) {
+	buf := borrowJSONBuffer()
+	defer returnJSONBuffer(buf)
+	if err := encodeInto(buf, v); err != nil {
+		return dst, err
+	}
+	return append(dst, buf.Bytes()...), nil
+}
+
+// JSONSize reports the encoded size of v without keeping the encoding.
+func JSONSize(v any) (int, error) {
+	buf := borrowJSONBuffer()
+	defer returnJSONBuffer(buf)
+	if err := encodeInto(buf, v); err != nil {
+		return 0, err
+	}
+	return buf.Len(), nil
+}
+
+// WriteJSON encodes and writes JSON directly to the writer without HTML escaping.
+func WriteJSON(w io.Writer, v any) error {
+	buf := borrowJSONBuffer()
+	defer returnJSONBuffer(buf)
+	if err := encodeInto(buf, v); err != nil {
+		return err
 	}
-	if _, err := w.Write(data); err != nil {
+	if _, err := w.Write(buf.Bytes()); err != nil {
 		return fmt.Errorf("write encoded json: %w", err)
 	}
 	return nil
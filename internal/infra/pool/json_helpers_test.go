@@ -0,0 +1,191 @@
+package pool
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/domain/schema"
+)
+
+func sampleJSONEvent() *schema.Event {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &schema.Event{
+		EventID:        "binance:BTC-USDT:trade:42",
+		RoutingVersion: 3,
+		Provider:       "binance",
+		Symbol:         "BTC-USDT",
+		Type:           schema.EventTypeTrade,
+		SeqProvider:    42,
+		IngestTS:       ts,
+		EmitTS:         ts,
+		Payload: schema.TradePayload{
+			TradeID:   "42",
+			Side:      schema.TradeSideBuy,
+			Price:     "43000.12",
+			Quantity:  "0.015",
+			Timestamp: ts,
+		},
+	}
+}
+
+func TestPooledJSONHelpersMatchMarshal(t *testing.T) {
+	evt := sampleJSONEvent()
+	expected, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	encoded, err := EncodeJSON(evt)
+	if err != nil {
+		t.Fatalf("EncodeJSON: %v", err)
+	}
+	if !bytes.Equal(encoded, expected) {
+		t.Fatalf("EncodeJSON = %s, want %s", encoded, expected)
+	}
+
+	buffer, err := EncodeJSONBuffer(evt)
+	if err != nil {
+		t.Fatalf("EncodeJSONBuffer: %v", err)
+	}
+	if !bytes.Equal(buffer.Bytes(), expected) || buffer.Len() != len(expected) {
+		t.Fatalf("EncodeJSONBuffer = %s, want %s", buffer.Bytes(), expected)
+	}
+	buffer.Release()
+	buffer.Release()
+	if buffer.Bytes() != nil || buffer.Len() != 0 {
+		t.Fatal("expected released buffer to be empty")
+	}
+
+	appended, err := AppendJSON([]byte("prefix:"), evt)
+	if err != nil {
+		t.Fatalf("AppendJSON: %v", err)
+	}
+	if string(appended) != "prefix:"+string(expected) {
+		t.Fatalf("AppendJSON = %s", appended)
+	}
+
+	size, err := JSONSize(evt)
+	if err != nil {
+		t.Fatalf("JSONSize: %v", err)
+	}
+	if size != len(expected) {
+		t.Fatalf("JSONSize = %d, want %d", size, len(expected))
+	}
+}
+
+func TestPooledJSONHelpersSkipHTMLEscaping(t *testing.T) {
+	encoded, err := EncodeJSON(map[string]string{"note": "<a&b>"})
+	if err != nil {
+		t.Fatalf("EncodeJSON: %v", err)
+	}
+	if string(encoded) != `{"note":"<a&b>"}` {
+		t.Fatalf("EncodeJSON = %s", encoded)
+	}
+}
+
+func TestEncodeJSONResultSurvivesBufferReuse(t *testing.T) {
+	first, err := EncodeJSON(map[string]int{"a": 1})
+	if err != nil {
+		t.Fatalf("EncodeJSON: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := EncodeJSON(map[string]int{"zzzzzz": i}); err != nil {
+			t.Fatalf("EncodeJSON: %v", err)
+		}
+	}
+	if string(first) != `{"a":1}` {
+		t.Fatalf("expected EncodeJSON result to be detached from the pool, got %s", first)
+	}
+}
+
+func TestReadJSONMessage(t *testing.T) {
+	message, err := ReadJSONMessage(strings.NewReader(`{"e":"trade"}`))
+	if err != nil {
+		t.Fatalf("ReadJSONMessage: %v", err)
+	}
+	defer message.Release()
+	var decoded map[string]string
+	if err := json.Unmarshal(message.Bytes(), &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded["e"] != "trade" {
+		t.Fatalf("unexpected message %v", decoded)
+	}
+}
+
+func BenchmarkEncodeEvent(b *testing.B) {
+	evt := sampleJSONEvent()
+	b.Run("marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(evt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buffer, err := EncodeJSONBuffer(evt)
+			if err != nil {
+				b.Fatal(err)
+			}
+			buffer.Release()
+		}
+	})
+}
+
+func BenchmarkEventSize(b *testing.B) {
+	evt := sampleJSONEvent()
+	b.Run("marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			encoded, err := json.Marshal(evt)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = len(encoded)
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := JSONSize(evt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkReadMessage(b *testing.B) {
+	payload, err := json.Marshal(sampleJSONEvent())
+	if err != nil {
+		b.Fatal(err)
+	}
+	reader := bytes.NewReader(payload)
+	b.Run("readall", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			reader.Reset(payload)
+			if _, err := io.ReadAll(reader); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			reader.Reset(payload)
+			message, err := ReadJSONMessage(reader)
+			if err != nil {
+				b.Fatal(err)
+			}
+			message.Release()
+		}
+	})
+}
@@ -33,6 +33,7 @@ var (
 // - pool.borrow.duration: Histogram of time to acquire objects
 // - pool.capacity: Observable gauge of each pool's total capacity
 // - pool.available: Observable gauge of available objects per pool
+// - pool.exhausted: Counter of borrows that found the pool exhausted (attributes: policy, outcome)
 //
 // Note: Pool utilization can be computed as (active/capacity) in query layer.
 //
@@ -50,6 +51,7 @@ type PoolManager struct {
 	borrowDuration         metric.Float64Histogram
 	poolCapacityGauge      metric.Int64ObservableGauge
 	poolAvailableGauge     metric.Int64ObservableGauge
+	exhaustionCounter      metric.Int64Counter
 }
 
 // NewPoolManager constructs an initialized pool manager ready for pool registration.
@@ -68,6 +70,9 @@ func NewPoolManager() *PoolManager {
 	pm.borrowDuration, _ = meter.Float64Histogram("pool.borrow.duration",
 		metric.WithDescription("Time taken to borrow an object from pool"),
 		metric.WithUnit("ms"))
+	pm.exhaustionCounter, _ = meter.Int64Counter("pool.exhausted",
+		metric.WithDescription("Number of borrows that found every pooled object leased"),
+		metric.WithUnit("{borrow}"))
 
 	pm.poolCapacityGauge, _ = meter.Int64ObservableGauge("pool.capacity",
 		metric.WithDescription("Total capacity of each pool"),
@@ -107,7 +112,8 @@ func NewPoolManager() *PoolManager {
 }
 
 // RegisterPool registers a bounded pool with the provided name, capacity, and constructor.
-func (pm *PoolManager) RegisterPool(name string, capacity int, queueSize int, newFunc func() any) error {
+// Pools wait for a returned object when exhausted unless WithExhaustionPolicy says otherwise.
+func (pm *PoolManager) RegisterPool(name string, capacity int, queueSize int, newFunc func() any, opts ...PoolOption) error {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
@@ -137,7 +143,7 @@ func (pm *PoolManager) RegisterPool(name string, capacity int, queueSize int, ne
 	sampleObj := factory()
 	sampleObj.Reset()
 
-	pool, err := newObjectPool(name, objectType, capacity, queueSize, factory)
+	pool, err := newObjectPool(name, objectType, capacity, queueSize, factory, opts...)
 	if err != nil {
 		return err
 	}
@@ -160,9 +166,17 @@ func (pm *PoolManager) Get(ctx context.Context, poolName string) (PooledObject,
 		return nil, err
 	}
 
-	obj, err := pool.get(ctx)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var obj PooledObject
+	if pool.exhausted() {
+		obj, err = pm.getExhausted(ctx, pool, poolName)
+	} else if obj, err = pool.get(ctx); err != nil {
+		err = fmt.Errorf("pool manager: get %s: %w", poolName, err)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("pool manager: get %s: %w", poolName, err)
+		return nil, err
 	}
 
 	pm.inFlight.Add(1)
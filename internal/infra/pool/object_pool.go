@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	concpool "github.com/sourcegraph/conc/pool"
 )
@@ -34,6 +35,11 @@ type objectPool struct {
 	closed       atomic.Bool
 	capacity     int
 	activeLeases atomic.Int64
+
+	policy            ExhaustionPolicy
+	waitTimeout       time.Duration
+	spilled           sync.Map // map[uintptr]struct{}
+	lastExhaustionLog atomic.Int64
 }
 
 type poolRequest struct {
@@ -61,7 +67,7 @@ func newPoolRequest(ctx context.Context) *poolRequest {
 	}
 }
 
-func newObjectPool(name string, objectType string, capacity int, queueSize int, factory func() PooledObject) (*objectPool, error) {
+func newObjectPool(name string, objectType string, capacity int, queueSize int, factory func() PooledObject, opts ...PoolOption) (*objectPool, error) {
 	if capacity <= 0 {
 		return nil, fmt.Errorf("pool %s: capacity must be positive", name)
 	}
@@ -71,16 +77,23 @@ func newObjectPool(name string, objectType string, capacity int, queueSize int,
 	if queueSize <= 0 {
 		queueSize = capacity
 	}
-	//nolint:exhaustruct // zero values for leases and closed are intentional
+	//nolint:exhaustruct // zero values for leases, spilled and closed are intentional
 	op := &objectPool{
-		name:       name,
-		objectType: objectType,
-		factory:    factory,
-		requests:   make(chan *poolRequest, queueSize),
-		queueSize:  queueSize,
-		stop:       make(chan struct{}),
-		capacity:   capacity,
-		workers:    concpool.New().WithMaxGoroutines(capacity),
+		name:        name,
+		objectType:  objectType,
+		factory:     factory,
+		requests:    make(chan *poolRequest, queueSize),
+		queueSize:   queueSize,
+		stop:        make(chan struct{}),
+		capacity:    capacity,
+		workers:     concpool.New().WithMaxGoroutines(capacity),
+		policy:      ExhaustionWait,
+		waitTimeout: 0,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(op)
+		}
 	}
 	for i := 0; i < capacity; i++ {
 		op.workers.Go(op.worker)
@@ -276,6 +289,9 @@ func (op *objectPool) put(obj PooledObject) error {
 	if obj == nil {
 		return fmt.Errorf("pool %s: nil object returned", op.name)
 	}
+	if op.releaseSpilled(obj) {
+		return nil
+	}
 	key := pointerKey(obj)
 	value, ok := op.leases.Load(key)
 	if !ok {
@@ -301,6 +317,9 @@ func (op *objectPool) tryPut(obj PooledObject) (bool, error) {
 	if obj == nil {
 		return false, fmt.Errorf("pool %s: nil object returned", op.name)
 	}
+	if op.releaseSpilled(obj) {
+		return true, nil
+	}
 	key := pointerKey(obj)
 	value, ok := op.leases.Load(key)
 	if !ok {
@@ -0,0 +1,160 @@
+// Package redact scrubs credentials from text before it is logged or returned to API clients.
+// Adapter errors can embed signed query strings, request headers or JSON bodies; the redactor
+// masks the values of credential keys in those forms and any text matching a configured pattern.
+package redact
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Mask replaces every redacted value.
+const Mask = "[REDACTED]"
+
+// DefaultKeys are the credential keys always redacted, matched case-insensitively.
+var DefaultKeys = []string{
+	"apiKey",
+	"api_key",
+	"apiSecret",
+	"api_secret",
+	"secret",
+	"secretKey",
+	"secret_key",
+	"passphrase",
+	"password",
+	"signature",
+	"sign",
+	"token",
+	"accessToken",
+	"access_token",
+	"listenKey",
+	"authorization",
+	"X-MBX-APIKEY",
+	"OK-ACCESS-KEY",
+	"OK-ACCESS-SIGN",
+	"OK-ACCESS-PASSPHRASE",
+}
+
+// Rules extends the default credential keys and adds patterns whose matches are masked.
+type Rules struct {
+	// Keys are JSON field, query parameter and header names whose values are masked.
+	Keys []string
+	// Patterns are regular expressions; each match is replaced by Mask.
+	Patterns []string
+}
+
+// Redactor masks credentials in text. A nil Redactor leaves text unchanged.
+type Redactor struct {
+	json     *regexp.Regexp
+	query    *regexp.Regexp
+	header   *regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+// New compiles the default keys together with rules.
+func New(rules Rules) (*Redactor, error) {
+	keys := make(map[string]struct{}, len(DefaultKeys)+len(rules.Keys))
+	for _, key := range append(append([]string(nil), DefaultKeys...), rules.Keys...) {
+		if key = strings.TrimSpace(key); key != "" {
+			keys[strings.ToLower(key)] = struct{}{}
+		}
+	}
+	quoted := make([]string, 0, len(keys))
+	for key := range keys {
+		quoted = append(quoted, regexp.QuoteMeta(key))
+	}
+	// Longer keys first so the longest key wins where several start at the same position.
+	sort.Slice(quoted, func(i, j int) bool {
+		if len(quoted[i]) != len(quoted[j]) {
+			return len(quoted[i]) > len(quoted[j])
+		}
+		return quoted[i] < quoted[j]
+	})
+	alternatives := "(?i:" + strings.Join(quoted, "|") + ")"
+	r := &Redactor{
+		// JSON fields, also when the JSON is itself embedded in a string with escaped quotes.
+		json:     regexp.MustCompile(`("` + alternatives + `\\?"\s*:\s*)("(?:[^"\\]|\\.)*"|\\"(?:[^"\\]|\\[^"])*\\"|[^\s,}\]]+)`),
+		query:    regexp.MustCompile(`(\b` + alternatives + `=)[^&\s"',;]+`),
+		header:   regexp.MustCompile(`(\b` + alternatives + `:[ \t]*)(?:(?i:bearer|basic)[ \t]+)?[^\s,;"']+`),
+		patterns: make([]*regexp.Regexp, 0, len(rules.Patterns)),
+	}
+	for _, pattern := range rules.Patterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, compiled)
+	}
+	return r, nil
+}
+
+// String masks the credentials in s.
+func (r *Redactor) String(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	s = r.json.ReplaceAllString(s, `${1}"`+Mask+`"`)
+	s = r.query.ReplaceAllString(s, "${1}"+Mask)
+	s = r.header.ReplaceAllString(s, "${1}"+Mask)
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllString(s, Mask)
+	}
+	return s
+}
+
+var active atomic.Pointer[Redactor]
+
+func init() {
+	defaults, _ := New(Rules{Keys: nil, Patterns: nil})
+	active.Store(defaults)
+}
+
+// Configure installs the process-wide redactor; nil disables redaction. Until called, the
+// default keys are redacted.
+func Configure(r *Redactor) {
+	active.Store(r)
+}
+
+// String masks the credentials in s with the process-wide redactor.
+func String(s string) string {
+	return active.Load().String(s)
+}
+
+// Error returns the message of err with credentials masked, or "" for a nil error.
+func Error(err error) string {
+	if err == nil {
+		return ""
+	}
+	return String(err.Error())
+}
+
+// Stdout redacts with the process-wide redactor before writing to standard output. Loggers
+// should write here rather than to os.Stdout.
+var Stdout io.Writer = Writer(os.Stdout)
+
+// Writer wraps w so every write is redacted with the process-wide redactor. A log.Logger writes
+// each entry in one call, so an entry is redacted as a whole.
+func Writer(w io.Writer) io.Writer {
+	return &writer{out: w}
+}
+
+type writer struct {
+	out io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	redacted := String(string(p))
+	if _, err := io.WriteString(w.out, redacted); err != nil {
+		return 0, err
+	}
+	// Report the input length so callers do not treat a shorter or longer entry as a short write.
+	return len(p), nil
+}
@@ -0,0 +1,96 @@
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestRedactorMasksDefaultKeys(t *testing.T) {
+	r, err := New(Rules{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "signed query",
+			in:   `binance order: GET https://api.binance.com/api/v3/order?symbol=BTCUSDT&timestamp=1&signature=abc123 returned 400`,
+			want: `binance order: GET https://api.binance.com/api/v3/order?symbol=BTCUSDT&timestamp=1&signature=[REDACTED] returned 400`,
+		},
+		{
+			name: "json body",
+			in:   `okx login: {"op":"login","args":[{"apiKey":"k-1","passphrase":"p\"w","sign":"s=="}]}`,
+			want: `okx login: {"op":"login","args":[{"apiKey":"[REDACTED]","passphrase":"[REDACTED]","sign":"[REDACTED]"}]}`,
+		},
+		{
+			name: "escaped json",
+			in:   `decode failed: "{\"listenKey\":\"lk-9\",\"e\":\"x\"}"`,
+			want: `decode failed: "{\"listenKey\":"[REDACTED]",\"e\":\"x\"}"`,
+		},
+		{
+			name: "headers",
+			in:   "request headers X-MBX-APIKEY: key-1, Authorization: Bearer tok-2",
+			want: "request headers X-MBX-APIKEY: [REDACTED], Authorization: [REDACTED]",
+		},
+		{
+			name: "unrelated text",
+			in:   "order BTC-USDT rejected: insufficient balance",
+			want: "order BTC-USDT rejected: insufficient balance",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := r.String(tc.in); got != tc.want {
+				t.Fatalf("String(%q)\n got %q\nwant %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRedactorRules(t *testing.T) {
+	r, err := New(Rules{Keys: []string{"accountId"}, Patterns: []string{`\bACC-\d+\b`}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	got := r.String(`lookup accountId=42 for ACC-1234 failed`)
+	if want := `lookup accountId=[REDACTED] for [REDACTED] failed`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if _, err := New(Rules{Patterns: []string{"("}}); err == nil {
+		t.Fatal("expected an invalid pattern to fail")
+	}
+	var disabled *Redactor
+	if got := disabled.String("signature=abc"); got != "signature=abc" {
+		t.Fatalf("expected a nil redactor to leave text unchanged, got %q", got)
+	}
+}
+
+func TestWriterRedactsLogEntries(t *testing.T) {
+	defer Configure(active.Load())
+	r, err := New(Rules{})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	Configure(r)
+
+	var out bytes.Buffer
+	logger := log.New(Writer(&out), "", 0)
+	logger.Printf("provider error: %v", errors.New("GET /api/v3/account?timestamp=1&signature=deadbeef: 401"))
+	if got := out.String(); strings.Contains(got, "deadbeef") || !strings.Contains(got, "signature="+Mask) {
+		t.Fatalf("expected the signature to be masked, got %q", got)
+	}
+
+	Configure(nil)
+	if got := Error(errors.New("signature=deadbeef")); got != "signature=deadbeef" {
+		t.Fatalf("expected redaction disabled, got %q", got)
+	}
+	if Error(nil) != "" {
+		t.Fatal("expected an empty message for a nil error")
+	}
+}
@@ -0,0 +1,175 @@
+package httpserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/approvals"
+	"github.com/coachpo/meltica/internal/app/calendar"
+	"github.com/coachpo/meltica/internal/app/egressip"
+	"github.com/coachpo/meltica/internal/app/eventaudit"
+	"github.com/coachpo/meltica/internal/app/featureflags"
+	"github.com/coachpo/meltica/internal/domain/datasetstore"
+	"github.com/coachpo/meltica/internal/domain/outboxstore"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+// HandlerOption customises the control API handler.
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	accessLogger  *log.Logger
+	eventHistory  outboxstore.EventLister
+	outbox        outboxstore.EventBrowser
+	calendar      *calendar.Calendar
+	flags         *featureflags.Flags
+	egress        *egressip.Monitor
+	eventAudit    *eventaudit.Monitor
+	approvals     *approvals.Queue
+	datasets      datasetstore.Store
+	datasetBytes  int64
+	build         BuildInfo
+	startedAt     time.Time
+	schemaVersion SchemaVersionFunc
+}
+
+// WithAccessLogger writes one structured line per request to logger. Access logging is disabled
+// when no logger is supplied.
+func WithAccessLogger(logger *log.Logger) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.accessLogger = logger
+	}
+}
+
+// WithEventHistory lets instance audit exports include events read back from the outbox.
+func WithEventHistory(events outboxstore.EventLister) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.eventHistory = events
+	}
+}
+
+// WithOutbox exposes outbox browsing, payload inspection and requeueing under /outbox.
+func WithOutbox(outbox outboxstore.EventBrowser) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.outbox = outbox
+	}
+}
+
+// WithDatasets exposes backtest dataset management under /datasets. Uploads larger than maxBytes
+// are rejected; zero keeps the 512 MiB default.
+func WithDatasets(store datasetstore.Store, maxBytes int64) HandlerOption {
+	return func(opts *handlerOptions) {
+		if maxBytes <= 0 {
+			maxBytes = defaultDatasetBytes
+		}
+		opts.datasets = store
+		opts.datasetBytes = maxBytes
+	}
+}
+
+// WithCalendar exposes the scheduled action calendar under /calendar.
+func WithCalendar(cal *calendar.Calendar) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.calendar = cal
+	}
+}
+
+// WithFeatureFlags exposes the gateway feature flags under /admin/flags.
+func WithFeatureFlags(flags *featureflags.Flags) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.flags = flags
+	}
+}
+
+// WithEgressMonitor exposes the public egress IP report under /admin/egress.
+func WithEgressMonitor(monitor *egressip.Monitor) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.egress = monitor
+	}
+}
+
+// WithEventAudit exposes the event audit drift report under /admin/event-audit.
+func WithEventAudit(monitor *eventaudit.Monitor) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.eventAudit = monitor
+	}
+}
+
+// WithApprovals makes guarded actions wait for a second operator and exposes them under /approvals.
+func WithApprovals(queue *approvals.Queue) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.approvals = queue
+	}
+}
+
+// statusRecorder captures the response status and size for access logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// withRequestID assigns or propagates the request identifier, echoes it in the response headers
+// and stores it in the request context. When logger is set, each request is logged on completion.
+func withRequestID(handler http.Handler, logger *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		id := telemetry.NormalizeRequestID(r.Header.Get(telemetry.RequestIDHeader))
+		w.Header().Set(telemetry.RequestIDHeader, id)
+		r = r.WithContext(telemetry.ContextWithRequestID(r.Context(), id))
+		recorder := &statusRecorder{ResponseWriter: w, status: 0, bytes: 0}
+		handler.ServeHTTP(recorder, r)
+		if logger == nil {
+			return
+		}
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		logger.Printf("requestId=%s method=%s path=%s status=%d latencyMs=%s bytesIn=%d bytesOut=%d principal=%s remote=%s",
+			id, r.Method, strconv.Quote(r.URL.Path), status,
+			strconv.FormatFloat(float64(time.Since(started).Microseconds())/1000, 'f', 3, 64),
+			max(r.ContentLength, 0), recorder.bytes, requestPrincipal(r), r.RemoteAddr)
+	})
+}
+
+// requestPrincipal identifies the caller without logging credentials: basic-auth users by name and
+// bearer tokens by a short fingerprint. The fingerprint is not written as token:<hash>, which log
+// redaction would mask as a credential header.
+func requestPrincipal(r *http.Request) string {
+	if user, _, ok := r.BasicAuth(); ok && user != "" {
+		return "user:" + strconv.Quote(user)
+	}
+	auth := strings.TrimSpace(r.Header.Get("Authorization"))
+	if len(auth) > len("Bearer ") && strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+		sum := sha256.Sum256([]byte(strings.TrimSpace(auth[len("Bearer "):])))
+		return "tok-sha256=" + hex.EncodeToString(sum[:4])
+	}
+	return "anonymous"
+}
@@ -0,0 +1,110 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/redact"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+func TestRequestIDPropagatedToResponseAndErrorPayload(t *testing.T) {
+	handler := NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{})
+	req := httptest.NewRequest(http.MethodGet, "/strategy/instances/demo/orders?limit=bogus", nil)
+	req.Header.Set(telemetry.RequestIDHeader, "client-req-42")
+	res := httptest.NewRecorder()
+
+	handler.ServeHTTP(res, req)
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", res.Code)
+	}
+	if got := res.Header().Get(telemetry.RequestIDHeader); got != "client-req-42" {
+		t.Fatalf("expected propagated request id, got %q", got)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(res.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode error payload: %v", err)
+	}
+	if payload["requestId"] != "client-req-42" {
+		t.Fatalf("expected requestId in error payload, got %v", payload)
+	}
+}
+
+func TestRequestIDGeneratedWhenMissingOrInvalid(t *testing.T) {
+	handler := NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{})
+	for _, incoming := range []string{"", "bad id\twith spaces", strings.Repeat("a", 200)} {
+		req := httptest.NewRequest(http.MethodGet, "/strategy/instances/demo/orders?limit=bogus", nil)
+		if incoming != "" {
+			req.Header.Set(telemetry.RequestIDHeader, incoming)
+		}
+		res := httptest.NewRecorder()
+
+		handler.ServeHTTP(res, req)
+
+		got := res.Header().Get(telemetry.RequestIDHeader)
+		if got == "" || got == incoming {
+			t.Fatalf("expected generated request id for %q, got %q", incoming, got)
+		}
+	}
+}
+
+func TestAccessLogLineIsStructured(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+	handler := NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{}, WithAccessLogger(logger))
+	req := httptest.NewRequest(http.MethodGet, "/strategy/instances/demo/orders?limit=bogus", nil)
+	req.Header.Set(telemetry.RequestIDHeader, "trace-1")
+	req.Header.Set("Authorization", "Bearer secret-token")
+	res := httptest.NewRecorder()
+
+	handler.ServeHTTP(res, req)
+
+	line := buf.String()
+	for _, want := range []string{
+		"requestId=trace-1",
+		"method=GET",
+		`path="/strategy/instances/demo/orders"`,
+		"status=400",
+		"latencyMs=",
+		"bytesIn=0",
+		"principal=tok-sha256=",
+	} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected %q in access log line %q", want, line)
+		}
+	}
+	if strings.Contains(line, "secret-token") {
+		t.Fatalf("access log leaked bearer token: %q", line)
+	}
+}
+
+func TestRequestPrincipal(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if got := requestPrincipal(req); got != "anonymous" {
+		t.Fatalf("expected anonymous principal, got %q", got)
+	}
+	req.SetBasicAuth("ops", "pw")
+	if got := requestPrincipal(req); got != `user:"ops"` {
+		t.Fatalf("expected basic-auth principal, got %q", got)
+	}
+}
+
+func TestRequestPrincipalSurvivesRedaction(t *testing.T) {
+	redactor, err := redact.New(redact.Rules{})
+	if err != nil {
+		t.Fatalf("redact.New: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	line := "status=200 principal=" + requestPrincipal(req) + " remote=192.0.2.1:1234"
+	if got := redactor.String(line); got != line {
+		t.Fatalf("expected the logged principal to survive redaction, got %q from %q", got, line)
+	}
+}
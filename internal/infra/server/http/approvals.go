@@ -0,0 +1,221 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/approvals"
+	"github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+const (
+	approvalsPath         = "/approvals"
+	approvalPrefix        = approvalsPath + "/"
+	approvalApproveSuffix = "approve"
+	approvalRejectSuffix  = "reject"
+)
+
+type approvalRejectPayload struct {
+	Reason string `json:"reason"`
+}
+
+// requestApproval parks a guarded action until a second operator approves it and answers 202 with
+// the pending approval.
+func (s *httpServer) requestApproval(w http.ResponseWriter, r *http.Request, action, target, summary string, execute approvals.Executor) {
+	approval, err := s.approvals.Request(action, target, summary, s.approvalPrincipal(r), execute)
+	if err != nil {
+		s.writeApprovalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, approval)
+}
+
+// approvalPrincipal identifies the operator behind a request from verified credentials only: a
+// bearer token configured for an operator, else the identity header set by the trusted proxy. It
+// returns "" for anyone else, so unverified callers can neither request nor approve.
+func (s *httpServer) approvalPrincipal(r *http.Request) string {
+	auth := strings.TrimSpace(r.Header.Get("Authorization"))
+	if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+		if name := s.approvals.Operator(strings.TrimSpace(token)); name != "" {
+			return "operator:" + name
+		}
+	}
+	if header := s.approvals.TrustedHeader(); header != "" {
+		if user := strings.TrimSpace(r.Header.Get(header)); user != "" {
+			return "user:" + strconv.Quote(user)
+		}
+	}
+	return ""
+}
+
+func (s *httpServer) listApprovals(w http.ResponseWriter, r *http.Request) {
+	if s.approvals == nil {
+		writeError(w, http.StatusServiceUnavailable, "approvals disabled")
+		return
+	}
+	all := false
+	if raw := strings.TrimSpace(r.URL.Query().Get("all")); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "all must be a boolean")
+			return
+		}
+		all = parsed
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"approvals": s.approvals.Approvals(all)})
+}
+
+func (s *httpServer) handleApproval(w http.ResponseWriter, r *http.Request) {
+	if s.approvals == nil {
+		writeError(w, http.StatusServiceUnavailable, "approvals disabled")
+		return
+	}
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, approvalPrefix), "/")
+	id, action, _ := strings.Cut(rest, "/")
+	if id == "" || strings.Contains(action, "/") {
+		writeError(w, http.StatusNotFound, "approval id required")
+		return
+	}
+	switch action {
+	case "":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		approval, ok := s.approvals.Approval(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, approvals.ErrApprovalNotFound.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, approval)
+	case approvalApproveSuffix:
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		approval, err := s.approvals.Approve(r.Context(), id, s.approvalPrincipal(r))
+		if err != nil {
+			s.writeApprovalError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if approval.Status == approvals.StatusFailed {
+			status = http.StatusConflict
+		}
+		writeJSON(w, status, approval)
+	case approvalRejectSuffix:
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		limitRequestBody(w, r)
+		defer func() { _ = r.Body.Close() }()
+		var payload approvalRejectPayload
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+			writeDecodeError(w, err)
+			return
+		}
+		approval, err := s.approvals.Reject(id, s.approvalPrincipal(r), payload.Reason)
+		if err != nil {
+			s.writeApprovalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, approval)
+	default:
+		writeError(w, http.StatusNotFound, "unsupported action")
+	}
+}
+
+func (s *httpServer) writeApprovalError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, approvals.ErrApprovalNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, approvals.ErrApprovalClosed):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, approvals.ErrUnauthenticated):
+		writeError(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, approvals.ErrSelfApproval):
+		writeError(w, http.StatusForbidden, err.Error())
+	default:
+		writeError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+// instanceSpecApproval reports the guarded changes that creating or replacing an instance with spec
+// would make, and the approval action they fall under: trading live when the instance is new or
+// currently dry-run, or pinning it to a risk profile that raises its limits.
+func (s *httpServer) instanceSpecApproval(spec config.LambdaSpec) (string, []string) {
+	var action string
+	var reasons []string
+	current, exists := s.manager.Instance(spec.ID)
+	if !spec.DryRunEnabled() && (!exists || current.DryRun) && s.approvals.Guards(config.ApprovalActionLiveTrading) {
+		action = config.ApprovalActionLiveTrading
+		reasons = append(reasons, "live trading on instance "+spec.ID)
+	}
+	if s.approvals.Guards(config.ApprovalActionRiskLimits) {
+		profile, _ := spec.Strategy.Config[runtime.RiskProfileConfigKey].(string)
+		pinned := s.manager.RiskProfileAssignments().Instances[spec.ID]
+		if !strings.EqualFold(strings.TrimSpace(profile), pinned) {
+			// Unknown profiles fall back to the shared limits at launch and raise nothing.
+			raised, err := s.manager.RiskProfileIncreases(profile, []string{spec.ID}, s.approvals.RiskLimitIncreasePercent())
+			if err == nil && len(raised) > 0 {
+				if action == "" {
+					action = config.ApprovalActionRiskLimits
+				}
+				reasons = append(reasons, raised...)
+			}
+		}
+	}
+	return action, reasons
+}
+
+// applyRiskLimitsApproved applies risk limits once the raise was approved.
+func (s *httpServer) applyRiskLimitsApproved(cfg config.RiskConfig) approvals.Executor {
+	return func(context.Context) (any, error) {
+		limits := s.manager.ApplyRiskConfig(cfg)
+		return map[string]any{"status": "updated", "limits": riskConfigFromLimits(limits)}, nil
+	}
+}
+
+// requestProviderRemoval parks a provider deletion, optionally with a drain, until approved. The
+// provider must still be unused when the approval executes.
+func (s *httpServer) requestProviderRemoval(w http.ResponseWriter, r *http.Request, name string, drain bool) {
+	var opts provider.DrainOptions
+	summary := "delete provider " + name
+	if drain {
+		parsed, err := parseDrainOptions(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		opts = parsed
+		summary = fmt.Sprintf("drain (%s) and delete provider %s", opts.Mode, name)
+	}
+	s.requestApproval(w, r, config.ApprovalActionProviderDelete, name, summary, func(ctx context.Context) (any, error) {
+		if dependents := s.instancesUsingProvider(name); len(dependents) > 0 {
+			return nil, fmt.Errorf("provider %s is in use by instances: %s", name, strings.Join(dependents, ", "))
+		}
+		if !drain {
+			if err := s.providers.Remove(name); err != nil {
+				return nil, err
+			}
+			return map[string]string{"status": "removed", "name": name}, nil
+		}
+		report, err := s.providers.DrainAndRemove(ctx, name, opts)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"status": "removed", "name": name, "drain": report}, nil
+	})
+}
@@ -0,0 +1,304 @@
+package httpserver
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/approvals"
+	lambdaruntime "github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/infra/config"
+	strategiestest "github.com/coachpo/meltica/internal/testutil/strategies"
+)
+
+func TestRiskLimitRaiseRequiresApproval(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+		Risk:       config.RiskConfig{MaxPositionSize: "10", MaxNotionalValue: "1000", NotionalCurrency: "USDT"},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	queue := approvals.New(config.ApprovalsConfig{
+		Enabled:                  true,
+		Actions:                  config.ApprovalActions,
+		RiskLimitIncreasePercent: 20,
+		TTL:                      time.Hour,
+		TrustedHeader:            "X-Forwarded-User",
+	}, approvals.WithLogger(log.New(io.Discard, "", 0)))
+	handler := NewHandler(appCfg, manager, nil, nil, WithApprovals(queue))
+	serve := func(method, path, body, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if user != "" {
+			req.Header.Set("X-Forwarded-User", user)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve(http.MethodPut, "/risk/limits", `{"maxPositionSize":"11","maxNotionalValue":"1000","notionalCurrency":"USDT","orderThrottle":1}`, ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected raise within threshold applied, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	raise := `{"maxPositionSize":"11","maxNotionalValue":"5000","notionalCurrency":"USDT","orderThrottle":1}`
+	if rec := serve(http.MethodPut, "/risk/limits", raise, ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected anonymous raise refused, got %d", rec.Code)
+	}
+	rec := serve(http.MethodPut, "/risk/limits", raise, "alice")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected pending approval, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var pending approvals.Approval
+	if err := json.Unmarshal(rec.Body.Bytes(), &pending); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if pending.Action != config.ApprovalActionRiskLimits || !strings.Contains(pending.Summary, "maxNotionalValue 1000 → 5000") {
+		t.Fatalf("unexpected approval %+v", pending)
+	}
+	if limit := manager.RiskLimits().MaxNotionalValue.String(); limit != "1000" {
+		t.Fatalf("expected limits unchanged before approval, got %s", limit)
+	}
+
+	if rec := serve(http.MethodGet, "/approvals", "", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), pending.ID) {
+		t.Fatalf("expected pending approval listed, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodPost, "/approvals/"+pending.ID+"/approve", "", "alice"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected self-approval refused, got %d", rec.Code)
+	}
+	rec = serve(http.MethodPost, "/approvals/"+pending.ID+"/approve", "", "bob")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"executed"`) {
+		t.Fatalf("expected executed approval, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if limit := manager.RiskLimits().MaxNotionalValue.String(); limit != "5000" {
+		t.Fatalf("expected raised limit after approval, got %s", limit)
+	}
+	if rec := serve(http.MethodPost, "/approvals/"+pending.ID+"/reject", "", "carol"); rec.Code != http.StatusConflict {
+		t.Fatalf("expected decided approval closed, got %d", rec.Code)
+	}
+
+	if rec := serve(http.MethodPost, "/strategy/instances/ghost/trading", `{"enabled":true}`, "alice"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown instance rejected before approval, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/approvals/missing", "", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown approval, got %d", rec.Code)
+	}
+}
+
+func TestRiskProfileRaiseRequiresApproval(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+		Risk:       config.RiskConfig{MaxPositionSize: "10", MaxNotionalValue: "1000", NotionalCurrency: "USDT"},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if _, err := manager.Create(config.LambdaSpec{
+		ID:              "desk",
+		Strategy:        config.LambdaStrategySpec{Identifier: "noop"},
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string]config.ProviderSymbols{"binance": {Symbols: []string{"BTC-USDT"}}},
+	}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	queue := approvals.New(config.ApprovalsConfig{
+		Enabled:       true,
+		Actions:       config.ApprovalActions,
+		TTL:           time.Hour,
+		TrustedHeader: "X-Forwarded-User",
+	}, approvals.WithLogger(log.New(io.Discard, "", 0)))
+	handler := NewHandler(appCfg, manager, nil, nil, WithApprovals(queue))
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-Forwarded-User", "alice")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	pending := func(rec *httptest.ResponseRecorder, summary string) {
+		t.Helper()
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected pending approval for %q, got %d (%s)", summary, rec.Code, rec.Body.String())
+		}
+		var approval approvals.Approval
+		if err := json.Unmarshal(rec.Body.Bytes(), &approval); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if approval.Action != config.ApprovalActionRiskLimits || !strings.Contains(approval.Summary, summary) {
+			t.Fatalf("unexpected approval %+v", approval)
+		}
+	}
+	tight := `{"maxPositionSize":"5","maxNotionalValue":"500","notionalCurrency":"USDT","orderThrottle":1}`
+	loose := `{"maxPositionSize":"5","maxNotionalValue":"9000","notionalCurrency":"USDT","orderThrottle":1}`
+
+	if rec := serve(http.MethodPost, "/risk/profiles", `{"name":"tight","limits":`+tight+`}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodPost, "/risk/profiles/tight/apply", ""); rec.Code != http.StatusOK {
+		t.Fatalf("tightening apply: expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	pending(serve(http.MethodPut, "/risk/profiles/tight", `{"limits":`+loose+`}`), "update risk profile tight: maxNotionalValue 500 → 9000")
+	if limit := manager.RiskLimits().MaxNotionalValue.String(); limit != "500" {
+		t.Fatalf("expected applied profile unchanged before approval, got %s", limit)
+	}
+	if profile, _ := manager.RiskProfile("tight"); profile.Limits.MaxNotionalValue != "500" {
+		t.Fatalf("expected stored profile unchanged before approval, got %s", profile.Limits.MaxNotionalValue)
+	}
+	pending(serve(http.MethodPost, "/risk/profiles/aggressive/apply", ""), "apply risk profile aggressive: maxPositionSize 5 → 1000")
+	if assignments := manager.RiskProfileAssignments(); assignments.Global != "tight" {
+		t.Fatalf("expected global profile unchanged, got %q", assignments.Global)
+	}
+
+	if rec := serve(http.MethodPut, "/strategy/instances/desk/risk-profile", `{"profile":"tight"}`); rec.Code != http.StatusOK {
+		t.Fatalf("tightening assign: expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	pending(serve(http.MethodPost, "/risk/profiles/aggressive/apply", `{"instances":["desk"]}`), "desk maxPositionSize 5 → 1000")
+	pending(serve(http.MethodPut, "/strategy/instances/desk/risk-profile", `{"profile":"aggressive"}`), "assign risk profile aggressive to desk")
+	if assignments := manager.RiskProfileAssignments(); assignments.Instances["desk"] != "tight" {
+		t.Fatalf("expected desk still pinned to tight, got %q", assignments.Instances["desk"])
+	}
+
+	backup := `{"risk":{"maxPositionSize":"5","maxNotionalValue":"50000","notionalCurrency":"USDT"}}`
+	pending(serve(http.MethodPost, "/context/backup", backup), "restore context backup: maxNotionalValue 500 → 50000")
+	if limit := manager.RiskLimits().MaxNotionalValue.String(); limit != "500" {
+		t.Fatalf("expected restore held for approval, got %s", limit)
+	}
+}
+
+func TestLiveTradingSpecRequiresApproval(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	queue := approvals.New(config.ApprovalsConfig{
+		Enabled:       true,
+		Actions:       []string{config.ApprovalActionLiveTrading},
+		TTL:           time.Hour,
+		TrustedHeader: "X-Forwarded-User",
+	}, approvals.WithLogger(log.New(io.Discard, "", 0)))
+	handler := NewHandler(appCfg, manager, nil, nil, WithApprovals(queue))
+	serve := func(method, path, body, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-Forwarded-User", user)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	spec := func(id, dryRun string) string {
+		return `{"id":"` + id + `","strategy":{"identifier":"noop","config":{}},"scope":{"binance":{"symbols":["BTC-USDT"]}},"dryRun":` + dryRun + `}`
+	}
+	pending := func(rec *httptest.ResponseRecorder) approvals.Approval {
+		t.Helper()
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected pending approval, got %d (%s)", rec.Code, rec.Body.String())
+		}
+		var approval approvals.Approval
+		if err := json.Unmarshal(rec.Body.Bytes(), &approval); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if approval.Action != config.ApprovalActionLiveTrading {
+			t.Fatalf("expected live trading approval, got %+v", approval)
+		}
+		return approval
+	}
+
+	created := pending(serve(http.MethodPost, "/strategy/instances", spec("live", "false"), "alice"))
+	if _, exists := manager.Instance("live"); exists {
+		t.Fatal("expected live instance held for approval")
+	}
+	if rec := serve(http.MethodPost, "/approvals/"+created.ID+"/approve", "", "bob"); rec.Code != http.StatusOK {
+		t.Fatalf("approve create: got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if snapshot, ok := manager.Instance("live"); !ok || snapshot.DryRun {
+		t.Fatalf("expected live instance created after approval, got %+v", snapshot)
+	}
+
+	if rec := serve(http.MethodPost, "/strategy/instances", spec("paper", "true"), "alice"); rec.Code != http.StatusCreated {
+		t.Fatalf("dry-run create: expected 201, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	pending(serve(http.MethodPut, "/strategy/instances/paper", spec("paper", "false"), "alice"))
+	if snapshot, _ := manager.Instance("paper"); !snapshot.DryRun {
+		t.Fatal("expected paper instance to stay dry-run before approval")
+	}
+	if rec := serve(http.MethodPut, "/strategy/instances/live", spec("live", "false"), "alice"); rec.Code != http.StatusOK {
+		t.Fatalf("updating an already live instance: expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+
+	pending(serve(http.MethodPost, "/context/backup", `{"lambdas":[`+spec("restored", "false")+`]}`, "alice"))
+}
+
+func TestApprovalPrincipalRequiresVerifiedCredentials(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+		Risk:       config.RiskConfig{MaxPositionSize: "10", MaxNotionalValue: "1000", NotionalCurrency: "USDT"},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	queue := approvals.New(config.ApprovalsConfig{
+		Enabled: true,
+		Actions: config.ApprovalActions,
+		TTL:     time.Hour,
+		Operators: []config.ApprovalOperatorConfig{
+			{Name: "alice", Token: "alice-token-0123456789"},
+			{Name: "bob", Token: "bob-token-0123456789"},
+		},
+	}, approvals.WithLogger(log.New(io.Discard, "", 0)))
+	handler := NewHandler(appCfg, manager, nil, nil, WithApprovals(queue))
+	serve := func(method, path, body string, auth func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		auth(req)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	bearer := func(token string) func(*http.Request) {
+		return func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) }
+	}
+	raise := `{"maxPositionSize":"11","maxNotionalValue":"5000","notionalCurrency":"USDT","orderThrottle":1}`
+
+	// Unverified identities are anonymous: arbitrary bearer strings, basic auth users and identity
+	// headers without a trusted header configured.
+	for name, auth := range map[string]func(*http.Request){
+		"unknown bearer": bearer("a"),
+		"basic auth":     func(req *http.Request) { req.SetBasicAuth("alice", "guess") },
+		"header":         func(req *http.Request) { req.Header.Set("X-Forwarded-User", "alice") },
+	} {
+		if rec := serve(http.MethodPut, "/risk/limits", raise, auth); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected 401, got %d (%s)", name, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := serve(http.MethodPut, "/risk/limits", raise, bearer("alice-token-0123456789"))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected pending approval, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var pending approvals.Approval
+	if err := json.Unmarshal(rec.Body.Bytes(), &pending); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if pending.RequestedBy != "operator:alice" {
+		t.Fatalf("expected operator principal, got %q", pending.RequestedBy)
+	}
+	if rec := serve(http.MethodPost, "/approvals/"+pending.ID+"/approve", "", bearer("b")); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unknown bearer approval refused, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, "/approvals/"+pending.ID+"/approve", "", bearer("alice-token-0123456789")); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected self-approval refused, got %d", rec.Code)
+	}
+	rec = serve(http.MethodPost, "/approvals/"+pending.ID+"/approve", "", bearer("bob-token-0123456789"))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"decidedBy":"operator:bob"`) {
+		t.Fatalf("expected approval by bob, got %d (%s)", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,207 @@
+package httpserver
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+)
+
+const balancesPath = "/balances"
+
+// balancesReport aggregates the latest balance snapshot of every asset on every provider, valued
+// in one reporting currency.
+type balancesReport struct {
+	Currency string `json:"currency"`
+	// Total and Available sum the valued balances; assets listed in Unpriced are left out.
+	Total     string              `json:"total"`
+	Available string              `json:"available"`
+	Assets    []assetBalanceTotal `json:"assets"`
+	Balances  []providerBalance   `json:"balances"`
+	Unpriced  []string            `json:"unpriced"`
+}
+
+// assetBalanceTotal sums one asset over the providers holding it.
+type assetBalanceTotal struct {
+	Asset     string `json:"asset"`
+	Total     string `json:"total"`
+	Available string `json:"available"`
+	Rate      string `json:"rate,omitempty"`
+	Value     string `json:"value,omitempty"`
+}
+
+// providerBalance is the latest snapshot of one asset on one provider.
+type providerBalance struct {
+	Provider       string `json:"provider"`
+	Asset          string `json:"asset"`
+	Total          string `json:"total"`
+	Available      string `json:"available"`
+	SnapshotAt     int64  `json:"snapshotAt"`
+	Value          string `json:"value,omitempty"`
+	AvailableValue string `json:"availableValue,omitempty"`
+}
+
+type assetAccumulator struct {
+	total     decimal.Decimal
+	available decimal.Decimal
+	rate      decimal.Decimal
+	priced    bool
+}
+
+// getBalances reports the treasury across providers so operators do not have to query each
+// provider's balances endpoint.
+func (s *httpServer) getBalances(w http.ResponseWriter, r *http.Request) {
+	if s.orderStore == nil {
+		writeError(w, http.StatusServiceUnavailable, "order store unavailable")
+		return
+	}
+	values := r.URL.Query()
+	currency := strings.ToUpper(strings.TrimSpace(values.Get("currency")))
+	if currency == "" {
+		currency = s.balanceCurrency
+	}
+	if currency == "" && s.manager != nil {
+		currency = strings.ToUpper(strings.TrimSpace(s.manager.RiskLimits().NotionalCurrency))
+	}
+	if currency == "" {
+		writeError(w, http.StatusBadRequest, "reporting currency required")
+		return
+	}
+	asset := strings.TrimSpace(values.Get("asset"))
+
+	latest := make([]orderstore.BalanceRecord, 0)
+	for _, name := range s.balanceProviders() {
+		records, err := s.orderStore.ListBalances(r.Context(), orderstore.BalanceQuery{
+			Provider: name,
+			Asset:    asset,
+			Limit:    maxListLimit,
+		})
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		latest = append(latest, latestBalances(records)...)
+	}
+
+	report := balancesReport{
+		Currency:  currency,
+		Total:     "0",
+		Available: "0",
+		Assets:    []assetBalanceTotal{},
+		Balances:  make([]providerBalance, 0, len(latest)),
+		Unpriced:  []string{},
+	}
+	total := decimal.Zero
+	available := decimal.Zero
+	assets := make(map[string]*assetAccumulator)
+	for _, record := range latest {
+		code := strings.ToUpper(strings.TrimSpace(record.Asset))
+		acc, ok := assets[code]
+		if !ok {
+			rate, priced := s.conversionRate(code, currency)
+			acc = &assetAccumulator{total: decimal.Zero, available: decimal.Zero, rate: rate, priced: priced}
+			assets[code] = acc
+		}
+		recordTotal := parseBalanceAmount(record.Total)
+		recordAvailable := parseBalanceAmount(record.Available)
+		acc.total = acc.total.Add(recordTotal)
+		acc.available = acc.available.Add(recordAvailable)
+		row := providerBalance{
+			Provider:       record.Provider,
+			Asset:          code,
+			Total:          record.Total,
+			Available:      record.Available,
+			SnapshotAt:     record.SnapshotAt,
+			Value:          "",
+			AvailableValue: "",
+		}
+		if acc.priced {
+			row.Value = recordTotal.Mul(acc.rate).String()
+			row.AvailableValue = recordAvailable.Mul(acc.rate).String()
+			total = total.Add(recordTotal.Mul(acc.rate))
+			available = available.Add(recordAvailable.Mul(acc.rate))
+		}
+		report.Balances = append(report.Balances, row)
+	}
+	for code, acc := range assets {
+		entry := assetBalanceTotal{
+			Asset:     code,
+			Total:     acc.total.String(),
+			Available: acc.available.String(),
+			Rate:      "",
+			Value:     "",
+		}
+		if acc.priced {
+			entry.Rate = acc.rate.String()
+			entry.Value = acc.total.Mul(acc.rate).String()
+		} else {
+			report.Unpriced = append(report.Unpriced, code)
+		}
+		report.Assets = append(report.Assets, entry)
+	}
+	sort.Slice(report.Assets, func(i, j int) bool { return report.Assets[i].Asset < report.Assets[j].Asset })
+	sort.Slice(report.Balances, func(i, j int) bool {
+		if report.Balances[i].Provider != report.Balances[j].Provider {
+			return report.Balances[i].Provider < report.Balances[j].Provider
+		}
+		return report.Balances[i].Asset < report.Balances[j].Asset
+	})
+	sort.Strings(report.Unpriced)
+	report.Total = total.String()
+	report.Available = available.String()
+	writeJSON(w, http.StatusOK, report)
+}
+
+// balanceProviders lists every configured provider, running or not, since stopped providers keep
+// their last known balances.
+func (s *httpServer) balanceProviders() []string {
+	if s.providers == nil {
+		return nil
+	}
+	metadata := s.providers.ProviderMetadataSnapshot()
+	names := make([]string, 0, len(metadata))
+	for _, meta := range metadata {
+		names = append(names, meta.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *httpServer) conversionRate(asset, currency string) (decimal.Decimal, bool) {
+	if asset == currency {
+		return decimal.NewFromInt(1), true
+	}
+	if s.manager == nil {
+		return decimal.Zero, false
+	}
+	return s.manager.ConversionRate(asset, currency)
+}
+
+// latestBalances keeps the newest snapshot of each asset.
+func latestBalances(records []orderstore.BalanceRecord) []orderstore.BalanceRecord {
+	index := make(map[string]int, len(records))
+	out := make([]orderstore.BalanceRecord, 0, len(records))
+	for _, record := range records {
+		key := strings.ToUpper(strings.TrimSpace(record.Asset))
+		if i, ok := index[key]; ok {
+			if record.SnapshotAt > out[i].SnapshotAt {
+				out[i] = record
+			}
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, record)
+	}
+	return out
+}
+
+func parseBalanceAmount(raw string) decimal.Decimal {
+	amount, err := decimal.NewFromString(strings.TrimSpace(raw))
+	if err != nil {
+		return decimal.Zero
+	}
+	return amount
+}
@@ -0,0 +1,99 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	lambdaruntime "github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+	strategiestest "github.com/coachpo/meltica/internal/testutil/strategies"
+)
+
+type providerBalanceStore struct {
+	stubOrderStore
+	byProvider map[string][]orderstore.BalanceRecord
+}
+
+func (s *providerBalanceStore) ListBalances(_ context.Context, query orderstore.BalanceQuery) ([]orderstore.BalanceRecord, error) {
+	return s.byProvider[query.Provider], nil
+}
+
+func balanceRecord(provider, asset, total, available string, at int64) orderstore.BalanceRecord {
+	return orderstore.BalanceRecord{
+		BalanceSnapshot: orderstore.BalanceSnapshot{Provider: provider, Asset: asset, Total: total, Available: available, SnapshotAt: at},
+	}
+}
+
+func TestBalancesAggregatesProvidersInReportingCurrency(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+		Risk: config.RiskConfig{
+			NotionalCurrency: "USDT",
+			FX:               config.FXConfig{Rates: map[string]string{"BTC": "40000", "EUR": "1.25"}},
+		},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	providers := provider.NewManager(nil, nil, nil, dispatcher.NewTable(), log.New(io.Discard, "", 0))
+	for _, name := range []string{"binance", "okx"} {
+		spec := config.ProviderSpec{Name: name, Adapter: name, Config: map[string]any{"identifier": name}}
+		if _, err := providers.Create(context.Background(), spec, false); err != nil {
+			t.Fatalf("create provider %s: %v", name, err)
+		}
+	}
+	store := &providerBalanceStore{byProvider: map[string][]orderstore.BalanceRecord{
+		"binance": {
+			balanceRecord("binance", "BTC", "0.5", "0.25", 200),
+			balanceRecord("binance", "USDT", "1000", "800", 200),
+			balanceRecord("binance", "BTC", "9", "9", 100),
+		},
+		"okx": {
+			balanceRecord("okx", "BTC", "0.25", "0.25", 150),
+			balanceRecord("okx", "EUR", "400", "400", 150),
+			balanceRecord("okx", "DOGE", "1000", "1000", 150),
+		},
+	}}
+	handler := NewHandler(appCfg, manager, providers, store)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, balancesPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var report balancesReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	// 0.75 BTC at 40,000 + 1,000 USDT + 400 EUR at 1.25; DOGE has no rate.
+	if report.Currency != "USDT" || report.Total != "31500" || report.Available != "21300" {
+		t.Fatalf("unexpected totals %+v", report)
+	}
+	if len(report.Balances) != 5 || len(report.Assets) != 4 {
+		t.Fatalf("expected the latest snapshot per provider and asset, got %+v", report)
+	}
+	if btc := report.Assets[0]; btc.Asset != "BTC" || btc.Total != "0.75" || btc.Value != "30000" {
+		t.Fatalf("unexpected BTC total %+v", btc)
+	}
+	if len(report.Unpriced) != 1 || report.Unpriced[0] != "DOGE" {
+		t.Fatalf("expected DOGE unpriced, got %v", report.Unpriced)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, balancesPath+"?currency=eur", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.Currency != "EUR" || report.Total != "25200" {
+		t.Fatalf("expected the total converted to EUR, got %+v", report)
+	}
+}
@@ -0,0 +1,125 @@
+package httpserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/calendar"
+)
+
+type calendarEntryPayload struct {
+	Title        string          `json:"title"`
+	At           string          `json:"at"`
+	NotifyBefore *string         `json:"notifyBefore,omitempty"`
+	Action       calendar.Action `json:"action"`
+}
+
+func (s *httpServer) listCalendarEntries(w http.ResponseWriter, r *http.Request) {
+	if s.calendar == nil {
+		writeError(w, http.StatusServiceUnavailable, "calendar unavailable")
+		return
+	}
+	all := false
+	if raw := strings.TrimSpace(r.URL.Query().Get("all")); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "all must be a boolean")
+			return
+		}
+		all = parsed
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"entries": s.calendar.Entries(all)})
+}
+
+func (s *httpServer) scheduleCalendarEntry(w http.ResponseWriter, r *http.Request) {
+	if s.calendar == nil {
+		writeError(w, http.StatusServiceUnavailable, "calendar unavailable")
+		return
+	}
+	limitRequestBody(w, r)
+	req, err := decodeCalendarEntry(r)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	req.Action.RequestedBy = s.approvalPrincipal(r)
+	entry, err := s.calendar.Schedule(r.Context(), req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+func (s *httpServer) handleCalendarEntry(w http.ResponseWriter, r *http.Request) {
+	if s.calendar == nil {
+		writeError(w, http.StatusServiceUnavailable, "calendar unavailable")
+		return
+	}
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, calendarEntryPrefix), "/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "calendar entry id required")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		entry, ok := s.calendar.Entry(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, calendar.ErrEntryNotFound.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, entry)
+	case http.MethodDelete:
+		entry, err := s.calendar.Cancel(r.Context(), id, r.URL.Query().Get("reason"))
+		if err != nil {
+			switch {
+			case errors.Is(err, calendar.ErrEntryNotFound):
+				writeError(w, http.StatusNotFound, err.Error())
+			case errors.Is(err, calendar.ErrEntryClosed):
+				writeError(w, http.StatusConflict, err.Error())
+			default:
+				writeError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		writeJSON(w, http.StatusOK, entry)
+	default:
+		methodNotAllowed(w, http.MethodDelete, http.MethodGet)
+	}
+}
+
+func decodeCalendarEntry(r *http.Request) (calendar.Request, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+	var payload calendarEntryPayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		return calendar.Request{}, fmt.Errorf("decode payload: %w", err)
+	}
+	at, err := parseTimeParam("at", payload.At)
+	if err != nil {
+		return calendar.Request{}, err
+	}
+	req := calendar.Request{
+		Title:        payload.Title,
+		At:           at,
+		NotifyBefore: nil,
+		Action:       payload.Action,
+	}
+	if payload.NotifyBefore != nil {
+		lead, err := time.ParseDuration(strings.TrimSpace(*payload.NotifyBefore))
+		if err != nil {
+			return calendar.Request{}, fmt.Errorf("notifyBefore must be a duration such as 15m")
+		}
+		req.NotifyBefore = &lead
+	}
+	return req, nil
+}
@@ -0,0 +1,248 @@
+package httpserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// minCompressBytes skips compression for bodies too small to benefit from it.
+	minCompressBytes = 1024
+	// maxBufferedBytes bounds how much of a response is held back to compute its ETag. Larger
+	// responses are streamed without one.
+	maxBufferedBytes = 8 << 20
+
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// withConditionalResponses compresses GET responses with gzip or deflate when the client accepts
+// it, and tags successful ones with a weak ETag so pollers re-fetch with If-None-Match and receive
+// 304 Not Modified while nothing changed. Responses are buffered to hash them; handlers that flush,
+// such as streaming exports, are switched to streaming compression without an ETag.
+func withConditionalResponses(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead || isWebSocketUpgrade(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		buffered := &bufferedResponse{
+			w:        w,
+			r:        r,
+			encoding: negotiateEncoding(r.Header.Get("Accept-Encoding")),
+			status:   0,
+			body:     new(bytes.Buffer),
+			stream:   nil,
+		}
+		handler.ServeHTTP(buffered, r)
+		buffered.finish()
+	})
+}
+
+// isWebSocketUpgrade reports whether r asks to switch to the websocket protocol, whose handshake
+// must reach the client unbuffered.
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(strings.TrimSpace(r.Header.Get("Upgrade")), "websocket")
+}
+
+// bufferedResponse holds the response back until the handler returns, unless the handler flushes
+// or the body outgrows maxBufferedBytes, in which case it streams from then on.
+type bufferedResponse struct {
+	w        http.ResponseWriter
+	r        *http.Request
+	encoding string
+	status   int
+	body     *bytes.Buffer
+	// stream is set once the response is streamed; it compresses when encoding is set.
+	stream io.WriteCloser
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.w.Header()
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	if b.stream != nil {
+		return b.stream.Write(p)
+	}
+	if b.body.Len()+len(p) > maxBufferedBytes {
+		b.startStreaming()
+		return b.stream.Write(p)
+	}
+	return b.body.Write(p)
+}
+
+// Flush switches to streaming and pushes everything written so far to the client.
+func (b *bufferedResponse) Flush() {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	if b.stream == nil {
+		b.startStreaming()
+	}
+	if flusher, ok := b.stream.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	if flusher, ok := b.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (b *bufferedResponse) Unwrap() http.ResponseWriter {
+	return b.w
+}
+
+func (b *bufferedResponse) startStreaming() {
+	header := b.w.Header()
+	header.Del("Content-Length")
+	encoding := b.encoding
+	if !compressible(header, b.status) {
+		encoding = ""
+	}
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+		header.Add("Vary", "Accept-Encoding")
+	}
+	b.w.WriteHeader(b.status)
+	b.stream = newEncoder(b.w, encoding)
+	_, _ = b.stream.Write(b.body.Bytes())
+	b.body.Reset()
+}
+
+func (b *bufferedResponse) finish() {
+	if b.stream != nil {
+		_ = b.stream.Close()
+		return
+	}
+	status := b.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	header := b.w.Header()
+	if status == http.StatusOK && header.Get("ETag") == "" {
+		sum := sha256.Sum256(b.body.Bytes())
+		header.Set("ETag", `W/"`+hex.EncodeToString(sum[:16])+`"`)
+	}
+	if status == http.StatusOK && etagMatches(b.r.Header.Get("If-None-Match"), header.Get("ETag")) {
+		for _, key := range []string{"Content-Type", "Content-Length", "Content-Encoding"} {
+			header.Del(key)
+		}
+		b.w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	body := b.body.Bytes()
+	if b.encoding != "" && compressible(header, status) {
+		header.Add("Vary", "Accept-Encoding")
+		if len(body) >= minCompressBytes {
+			var compressed bytes.Buffer
+			encoder := newEncoder(&compressed, b.encoding)
+			_, _ = encoder.Write(body)
+			_ = encoder.Close()
+			header.Set("Content-Encoding", b.encoding)
+			body = compressed.Bytes()
+		}
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	b.w.WriteHeader(status)
+	if b.r.Method != http.MethodHead {
+		_, _ = b.w.Write(body)
+	}
+}
+
+// compressible reports whether a response may be re-encoded. Partial content, bodies the handler
+// already encoded and archives are passed through untouched.
+func compressible(header http.Header, status int) bool {
+	if status != http.StatusOK && status < http.StatusBadRequest {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	switch mediaType, _, _ := strings.Cut(header.Get("Content-Type"), ";"); strings.TrimSpace(mediaType) {
+	case "application/gzip", "application/zip", "application/zstd":
+		return false
+	default:
+		return true
+	}
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func newEncoder(w io.Writer, encoding string) io.WriteCloser {
+	switch encoding {
+	case encodingGzip:
+		return gzip.NewWriter(w)
+	case encodingDeflate:
+		return zlib.NewWriter(w)
+	default:
+		return nopWriteCloser{Writer: w}
+	}
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header, preferring gzip when
+// both are equally acceptable. Codings with q=0 are refused.
+func negotiateEncoding(accept string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		switch name {
+		case encodingGzip, encodingDeflate:
+		case "*":
+			name = encodingGzip
+		default:
+			continue
+		}
+		if q <= 0 {
+			continue
+		}
+		if q > bestQ || (q == bestQ && name == encodingGzip) {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// etagMatches applies the weak comparison of If-None-Match against etag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
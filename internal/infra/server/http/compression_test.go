@@ -0,0 +1,147 @@
+package httpserver
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func largeBodyHandler(body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	})
+}
+
+func TestConditionalResponsesCompressAndNegotiate(t *testing.T) {
+	body := strings.Repeat(`{"id":"instance"},`, 200)
+	handler := withConditionalResponses(largeBodyHandler(body))
+
+	cases := []struct {
+		accept   string
+		encoding string
+	}{
+		{accept: "gzip, deflate", encoding: "gzip"},
+		{accept: "deflate", encoding: "deflate"},
+		{accept: "gzip;q=0.5, deflate", encoding: "deflate"},
+		{accept: "gzip;q=0", encoding: ""},
+		{accept: "", encoding: ""},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/strategy/instances", nil)
+		req.Header.Set("Accept-Encoding", tc.accept)
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+
+		if got := res.Header().Get("Content-Encoding"); got != tc.encoding {
+			t.Fatalf("accept %q: expected encoding %q, got %q", tc.accept, tc.encoding, got)
+		}
+		var reader io.Reader = res.Body
+		switch tc.encoding {
+		case "gzip":
+			gz, err := gzip.NewReader(res.Body)
+			if err != nil {
+				t.Fatalf("gzip reader: %v", err)
+			}
+			reader = gz
+		case "deflate":
+			zr, err := zlib.NewReader(res.Body)
+			if err != nil {
+				t.Fatalf("zlib reader: %v", err)
+			}
+			reader = zr
+		}
+		decoded, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if string(decoded) != body {
+			t.Fatalf("accept %q: body mismatch", tc.accept)
+		}
+	}
+}
+
+func TestConditionalResponsesETagNotModified(t *testing.T) {
+	handler := withConditionalResponses(largeBodyHandler(`{"providers":[]}`))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/providers", nil))
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("expected 200 with weak etag, got %d %q", first.Code, etag)
+	}
+	if first.Header().Get("Content-Encoding") != "" {
+		t.Fatal("small bodies must not be compressed")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/providers", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, req)
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d with %d bytes", second.Code, second.Body.Len())
+	}
+	if second.Header().Get("ETag") != etag {
+		t.Fatalf("expected etag on 304, got %q", second.Header().Get("ETag"))
+	}
+}
+
+func TestConditionalResponsesSkipErrorsAndWrites(t *testing.T) {
+	failing := withConditionalResponses(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusNotFound, "missing")
+	}))
+	res := httptest.NewRecorder()
+	failing.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/providers/missing", nil))
+	if res.Code != http.StatusNotFound || res.Header().Get("ETag") != "" {
+		t.Fatalf("expected 404 without etag, got %d %q", res.Code, res.Header().Get("ETag"))
+	}
+
+	created := withConditionalResponses(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusCreated, map[string]string{"status": "ok"})
+	}))
+	res = httptest.NewRecorder()
+	created.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/providers", nil))
+	if res.Code != http.StatusCreated || res.Header().Get("ETag") != "" {
+		t.Fatalf("expected untouched 201, got %d %q", res.Code, res.Header().Get("ETag"))
+	}
+}
+
+func TestConditionalResponsesStreamWhenFlushed(t *testing.T) {
+	handler := withConditionalResponses(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("wrapped writer must implement http.Flusher")
+		}
+		for i := 0; i < 3; i++ {
+			_, _ = io.WriteString(w, "{\"seq\":1}\n")
+			flusher.Flush()
+		}
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/audit/export", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	if !res.Flushed {
+		t.Fatal("expected response to be flushed")
+	}
+	if res.Header().Get("ETag") != "" || res.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected streamed gzip without etag, got headers %v", res.Header())
+	}
+	gz, err := gzip.NewReader(res.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	decoded, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if strings.Count(string(decoded), "\n") != 3 {
+		t.Fatalf("unexpected streamed body %q", decoded)
+	}
+}
@@ -0,0 +1,236 @@
+package httpserver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/backtest"
+	"github.com/coachpo/meltica/internal/domain/datasetstore"
+)
+
+const (
+	datasetsPath        = "/datasets"
+	datasetPrefix       = datasetsPath + "/"
+	datasetContentPath  = "content"
+	datasetTagsPath     = "tags"
+	parquetMagic        = "PAR1"
+	csvHeaderPeekBytes  = 4096
+	defaultDatasetBytes = 512 << 20
+)
+
+type datasetTagsPayload struct {
+	Tags []string `json:"tags"`
+}
+
+// listDatasets lists stored datasets, filtered by tag, provider and symbol.
+func (s *httpServer) listDatasets(w http.ResponseWriter, r *http.Request) {
+	if s.datasets == nil {
+		writeError(w, http.StatusServiceUnavailable, "datasets unavailable")
+		return
+	}
+	values := r.URL.Query()
+	datasets, err := s.datasets.List(r.Context(), datasetstore.Query{
+		Tag:      strings.TrimSpace(values.Get("tag")),
+		Provider: strings.TrimSpace(values.Get("provider")),
+		Symbol:   strings.TrimSpace(values.Get("symbol")),
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"datasets": datasets,
+		"count":    len(datasets),
+	})
+}
+
+// uploadDataset stores the request body as a named dataset. Name, format, provider, symbol and
+// tags come from the query string. CSV uploads must carry a fetchdata kline or trade header and
+// Parquet uploads the Parquet magic number.
+func (s *httpServer) uploadDataset(w http.ResponseWriter, r *http.Request) {
+	if s.datasets == nil {
+		writeError(w, http.StatusServiceUnavailable, "datasets unavailable")
+		return
+	}
+	values := r.URL.Query()
+	dataset := datasetstore.Dataset{
+		ID:        "",
+		Name:      values.Get("name"),
+		Format:    datasetstore.Format(values.Get("format")),
+		Kind:      "",
+		Provider:  values.Get("provider"),
+		Symbol:    values.Get("symbol"),
+		Tags:      values["tag"],
+		SizeBytes: 0,
+		SHA256:    "",
+		CreatedAt: time.Time{},
+		UpdatedAt: time.Time{},
+	}
+	if err := datasetstore.Normalize(&dataset); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, s.datasetMaxBytes)
+	defer func() { _ = r.Body.Close() }()
+	body := bufio.NewReaderSize(r.Body, csvHeaderPeekBytes)
+	kind, err := inspectDatasetContent(dataset.Format, body)
+	if err != nil {
+		writeDatasetUploadError(w, err)
+		return
+	}
+	dataset.Kind = kind
+	created, err := s.datasets.Create(r.Context(), dataset, body)
+	if err != nil {
+		writeDatasetUploadError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *httpServer) handleDataset(w http.ResponseWriter, r *http.Request) {
+	if s.datasets == nil {
+		writeError(w, http.StatusServiceUnavailable, "datasets unavailable")
+		return
+	}
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, datasetPrefix), "/")
+	id, action, _ := strings.Cut(rest, "/")
+	if id == "" || strings.Contains(action, "/") {
+		writeError(w, http.StatusNotFound, "dataset id required")
+		return
+	}
+	switch action {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			dataset, err := s.datasets.Get(r.Context(), id)
+			if err != nil {
+				writeDatasetError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, dataset)
+		case http.MethodDelete:
+			if err := s.datasets.Delete(r.Context(), id); err != nil {
+				writeDatasetError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w, http.MethodDelete, http.MethodGet)
+		}
+	case datasetContentPath:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.downloadDataset(w, r, id)
+	case datasetTagsPath:
+		if r.Method != http.MethodPut {
+			methodNotAllowed(w, http.MethodPut)
+			return
+		}
+		s.setDatasetTags(w, r, id)
+	default:
+		writeError(w, http.StatusNotFound, "unsupported action")
+	}
+}
+
+func (s *httpServer) downloadDataset(w http.ResponseWriter, r *http.Request, id string) {
+	content, dataset, err := s.datasets.Open(r.Context(), id)
+	if err != nil {
+		writeDatasetError(w, err)
+		return
+	}
+	defer func() { _ = content.Close() }()
+	w.Header().Set("Content-Type", datasetstore.ContentType(dataset.Format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", dataset.Name+"."+string(dataset.Format)))
+	w.Header().Set("Content-Length", strconv.FormatInt(dataset.SizeBytes, 10))
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, content)
+}
+
+func (s *httpServer) setDatasetTags(w http.ResponseWriter, r *http.Request, id string) {
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload datasetTagsPayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	dataset, err := s.datasets.SetTags(r.Context(), id, payload.Tags)
+	if err != nil {
+		writeDatasetError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dataset)
+}
+
+// inspectDatasetContent checks the start of an upload against its format without consuming it
+// and returns the market data kind of CSV uploads.
+func inspectDatasetContent(format datasetstore.Format, body *bufio.Reader) (string, error) {
+	switch format {
+	case datasetstore.FormatParquet:
+		magic, err := body.Peek(len(parquetMagic))
+		if err != nil || string(magic) != parquetMagic {
+			return "", invalidDatasetError("not a parquet file")
+		}
+		return "", nil
+	case datasetstore.FormatCSV:
+		head, err := body.Peek(csvHeaderPeekBytes)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+			return "", err
+		}
+		line, _, found := bytes.Cut(head, []byte("\n"))
+		if !found && len(head) == csvHeaderPeekBytes {
+			return "", invalidDatasetError("csv header too long")
+		}
+		header, err := csv.NewReader(bytes.NewReader(line)).Read()
+		if err != nil {
+			return "", invalidDatasetError("csv header required")
+		}
+		kind, err := backtest.KindOf(header)
+		if err != nil {
+			return "", invalidDatasetError(err.Error())
+		}
+		return string(kind), nil
+	default:
+		return "", invalidDatasetError(fmt.Sprintf("unsupported format %q", format))
+	}
+}
+
+type invalidDatasetError string
+
+func (e invalidDatasetError) Error() string { return string(e) }
+
+func writeDatasetUploadError(w http.ResponseWriter, err error) {
+	var invalid invalidDatasetError
+	switch {
+	case isRequestTooLarge(err):
+		writeError(w, http.StatusRequestEntityTooLarge, "dataset too large")
+	case errors.As(err, &invalid):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, datasetstore.ErrNameTaken):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func writeDatasetError(w http.ResponseWriter, err error) {
+	if errors.Is(err, datasetstore.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,74 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/domain/datasetstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/persistence/filestore"
+)
+
+func TestDatasetEndpoints(t *testing.T) {
+	store, err := filestore.NewDatasetStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	handler := NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{}, WithDatasets(store, 1024))
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	klines := "open_time,close_time,open,high,low,close,volume\n1,2,10,11,9,10.5,3\n"
+	rec := do(http.MethodPost, datasetsPath+"?name=btc-1m&provider=binance&symbol=BTC-USDT&tag=q4", klines)
+	var created datasetstore.Dataset
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("unexpected upload response %d %s", rec.Code, rec.Body.String())
+	}
+	if created.Kind != "klines" || created.SizeBytes != int64(len(klines)) {
+		t.Fatalf("unexpected dataset %+v", created)
+	}
+	if rec := do(http.MethodPost, datasetsPath+"?name=btc-1m", klines); rec.Code != http.StatusConflict {
+		t.Fatalf("expected duplicate name rejected, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, datasetsPath+"?name=bad", "a,b\n1,2\n"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown header rejected, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, datasetsPath+"?name=pq&format=parquet", "nope"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected non-parquet content rejected, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, datasetsPath+"?name=big", klines+strings.Repeat("1,2,10,11,9,10.5,3\n", 100)); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected oversized upload rejected, got %d", rec.Code)
+	}
+
+	rec = do(http.MethodGet, datasetPrefix+created.ID+"/content", "")
+	if rec.Code != http.StatusOK || rec.Body.String() != klines || rec.Header().Get("Content-Type") != "text/csv" {
+		t.Fatalf("unexpected download %d %q", rec.Code, rec.Body.String())
+	}
+	rec = do(http.MethodPut, datasetPrefix+created.ID+"/tags", `{"tags":["golden"]}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"tags":["golden"]`) {
+		t.Fatalf("unexpected tag response %d %s", rec.Code, rec.Body.String())
+	}
+	rec = do(http.MethodGet, datasetsPath+"?tag=golden", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"count":1`) {
+		t.Fatalf("unexpected list %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodDelete, datasetPrefix+created.ID, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected delete, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, datasetPrefix+created.ID, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, datasetsPath, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a dataset store, got %d", rec.Code)
+	}
+}
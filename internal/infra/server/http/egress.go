@@ -0,0 +1,33 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"github.com/coachpo/meltica/internal/app/egressip"
+)
+
+type egressResponse struct {
+	Checked bool `json:"checked"`
+	egressip.Report
+}
+
+// getEgressReport returns the public egress IPs seen by the latest check, so operators can
+// compare them with the IP allow-lists of their exchange API keys.
+func (s *httpServer) getEgressReport(w http.ResponseWriter, _ *http.Request) {
+	if s.egress == nil {
+		writeError(w, http.StatusServiceUnavailable, "egress IP monitoring disabled")
+		return
+	}
+	report, checked := s.egress.Snapshot()
+	writeJSON(w, http.StatusOK, egressResponse{Checked: checked, Report: report})
+}
+
+// checkEgress queries the checkers immediately, e.g. after a network change.
+func (s *httpServer) checkEgress(w http.ResponseWriter, r *http.Request) {
+	if s.egress == nil {
+		writeError(w, http.StatusServiceUnavailable, "egress IP monitoring disabled")
+		return
+	}
+	report := s.egress.Check(r.Context())
+	writeJSON(w, http.StatusOK, egressResponse{Checked: true, Report: report})
+}
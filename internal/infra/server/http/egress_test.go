@@ -0,0 +1,69 @@
+package httpserver
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/egressip"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+func TestEgressReportEndpoint(t *testing.T) {
+	checker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "198.51.100.20")
+	}))
+	defer checker.Close()
+	monitor := egressip.New(config.EgressConfig{
+		Enabled:   true,
+		Checkers:  []string{checker.URL},
+		Interval:  time.Minute,
+		Timeout:   time.Second,
+		AllowList: []string{"203.0.113.7"},
+	}, egressip.WithLogger(log.New(io.Discard, "", 0)))
+	handler := NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{}, WithEgressMonitor(monitor))
+
+	request := func(method string) map[string]any {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, adminEgressPath, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s %s: expected 200, got %d (%s)", method, adminEgressPath, rec.Code, rec.Body.String())
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return payload
+	}
+	if payload := request(http.MethodGet); payload["checked"] != false {
+		t.Fatalf("expected unchecked report, got %v", payload)
+	}
+	payload := request(http.MethodPost)
+	if payload["checked"] != true || !strings.Contains(toJSON(t, payload["ips"]), "198.51.100.20") || !strings.Contains(toJSON(t, payload["notAllowed"]), "198.51.100.20") {
+		t.Fatalf("unexpected check result %v", payload)
+	}
+	if payload := request(http.MethodGet); payload["checked"] != true {
+		t.Fatalf("expected stored report, got %v", payload)
+	}
+
+	rec := httptest.NewRecorder()
+	NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, adminEgressPath, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without monitor, got %d", rec.Code)
+	}
+}
+
+func toJSON(t *testing.T, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,13 @@
+package httpserver
+
+import "net/http"
+
+// getEventAuditReport returns the field distributions of the latest event audit window and the
+// fields drifting from their golden fixtures.
+func (s *httpServer) getEventAuditReport(w http.ResponseWriter, _ *http.Request) {
+	if s.eventAudit == nil {
+		writeError(w, http.StatusServiceUnavailable, "event audit disabled")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.eventAudit.Snapshot())
+}
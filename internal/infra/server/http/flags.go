@@ -0,0 +1,72 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/featureflags"
+)
+
+type featureFlagPayload struct {
+	Enabled *bool `json:"enabled"`
+}
+
+func (s *httpServer) listFeatureFlags(w http.ResponseWriter, _ *http.Request) {
+	if s.flags == nil {
+		writeError(w, http.StatusServiceUnavailable, "feature flags unavailable")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"flags": s.flags.List()})
+}
+
+func (s *httpServer) handleFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	if s.flags == nil {
+		writeError(w, http.StatusServiceUnavailable, "feature flags unavailable")
+		return
+	}
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, adminFlagPrefix), "/")
+	if name == "" || strings.Contains(name, "/") {
+		writeError(w, http.StatusNotFound, "feature flag name required")
+		return
+	}
+	var (
+		flag featureflags.Flag
+		err  error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		flag, err = s.flags.Get(name)
+	case http.MethodPut:
+		limitRequestBody(w, r)
+		defer func() { _ = r.Body.Close() }()
+		var payload featureFlagPayload
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if decodeErr := decoder.Decode(&payload); decodeErr != nil {
+			writeDecodeError(w, decodeErr)
+			return
+		}
+		if payload.Enabled == nil {
+			writeError(w, http.StatusBadRequest, "enabled required")
+			return
+		}
+		flag, err = s.flags.Set(r.Context(), name, *payload.Enabled)
+	case http.MethodDelete:
+		flag, err = s.flags.Reset(r.Context(), name)
+	default:
+		methodNotAllowed(w, http.MethodDelete, http.MethodGet, http.MethodPut)
+		return
+	}
+	if err != nil {
+		if errors.Is(err, featureflags.ErrFlagNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, flag)
+}
@@ -0,0 +1,54 @@
+package httpserver
+
+import (
+	"net/http"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/lambda/runtime"
+)
+
+const adminFreezePath = "/admin/freeze"
+
+type freezePayload struct {
+	Frozen bool   `json:"frozen"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// freezeResponse adds the open orders snapshotted when the freeze engaged.
+type freezeResponse struct {
+	runtime.FreezeReport
+	OpenOrdersSnapshotted *int `json:"openOrdersSnapshotted,omitempty"`
+}
+
+func (s *httpServer) getFreeze(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, freezeResponse{FreezeReport: s.manager.FreezeStatus(), OpenOrdersSnapshotted: nil})
+}
+
+// setFreeze freezes the gateway for an upgrade, snapshotting open orders so the restarted gateway
+// resyncs them, or unfreezes it and replays the buffered events.
+func (s *httpServer) setFreeze(w http.ResponseWriter, r *http.Request) {
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload freezePayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if !payload.Frozen {
+		writeJSON(w, http.StatusOK, freezeResponse{FreezeReport: s.manager.Unfreeze(r.Context()), OpenOrdersSnapshotted: nil})
+		return
+	}
+	response := freezeResponse{FreezeReport: s.manager.Freeze(payload.Reason), OpenOrdersSnapshotted: nil}
+	if s.providers != nil {
+		count, err := s.providers.SnapshotOpenOrders(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "frozen, but snapshotting open orders failed: "+err.Error())
+			return
+		}
+		response.OpenOrdersSnapshotted = &count
+	}
+	writeJSON(w, http.StatusOK, response)
+}
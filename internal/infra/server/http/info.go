@@ -0,0 +1,110 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	goruntime "runtime"
+	"sort"
+	"time"
+
+	"github.com/coachpo/meltica/internal/infra/persistence/migrations"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+// BuildInfo identifies the running gateway binary. cmd/gateway fills it from values injected
+// with -ldflags "-X main.version=... -X main.commit=... -X main.buildTime=...".
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"buildTime,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// SchemaVersionFunc reports the migration state of the gateway database.
+type SchemaVersionFunc func(ctx context.Context) (migrations.SchemaVersion, error)
+
+// WithBuildInfo reports build metadata and the process start time under /admin/info.
+func WithBuildInfo(build BuildInfo, startedAt time.Time) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.build = build
+		opts.startedAt = startedAt
+	}
+}
+
+// WithSchemaVersion lets /admin/info report the applied database migration version.
+func WithSchemaVersion(fn SchemaVersionFunc) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.schemaVersion = fn
+	}
+}
+
+type adapterUsage struct {
+	Identifier string   `json:"identifier"`
+	Providers  []string `json:"providers"`
+	Running    int      `json:"running"`
+}
+
+type runtimeInfoResponse struct {
+	Build         BuildInfo                 `json:"build"`
+	StartedAt     time.Time                 `json:"startedAt"`
+	UptimeSeconds int64                     `json:"uptimeSeconds"`
+	Environment   string                    `json:"environment"`
+	SafeMode      bool                      `json:"safeMode"`
+	Adapters      []adapterUsage            `json:"adapters"`
+	Schema        *migrations.SchemaVersion `json:"schema,omitempty"`
+	SchemaError   string                    `json:"schemaError,omitempty"`
+}
+
+func (s *httpServer) getRuntimeInfo(w http.ResponseWriter, r *http.Request) {
+	build := s.build
+	if build.Version == "" {
+		build.Version = "dev"
+	}
+	if build.GoVersion == "" {
+		build.GoVersion = goruntime.Version()
+	}
+	response := runtimeInfoResponse{
+		Build:         build,
+		StartedAt:     s.startedAt.UTC(),
+		UptimeSeconds: int64(time.Since(s.startedAt).Seconds()),
+		Environment:   s.environment,
+		SafeMode:      s.safeMode != nil,
+		Adapters:      s.adapterUsage(),
+		Schema:        nil,
+		SchemaError:   "",
+	}
+	if s.schemaVersion != nil {
+		version, err := s.schemaVersion(r.Context())
+		response.Schema = &version
+		if err != nil {
+			response.SchemaError = redact.Error(err)
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// adapterUsage lists the adapters backing configured providers, with the providers using each.
+func (s *httpServer) adapterUsage() []adapterUsage {
+	out := []adapterUsage{}
+	if s.providers == nil {
+		return out
+	}
+	byAdapter := make(map[string]*adapterUsage)
+	for _, meta := range s.providers.ProviderMetadataSnapshot() {
+		usage, ok := byAdapter[meta.Adapter]
+		if !ok {
+			usage = &adapterUsage{Identifier: meta.Adapter, Providers: nil, Running: 0}
+			byAdapter[meta.Adapter] = usage
+		}
+		usage.Providers = append(usage.Providers, meta.Name)
+		if meta.Running {
+			usage.Running++
+		}
+	}
+	for _, usage := range byAdapter {
+		sort.Strings(usage.Providers)
+		out = append(out, *usage)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Identifier < out[j].Identifier })
+	return out
+}
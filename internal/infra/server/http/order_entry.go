@@ -0,0 +1,233 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/redact"
+)
+
+const (
+	orderEntryPath = "/order-entry"
+
+	// orderEntryReportBuffer bounds the execution reports waiting for a slow client; a client that
+	// falls further behind is disconnected with orderEntryOverflowReason so it reconnects and
+	// reconciles instead of missing reports.
+	orderEntryReportBuffer   = 256
+	orderEntryOverflowReason = "execution report buffer overflow"
+	orderEntryWriteTimeout   = 5 * time.Second
+)
+
+// Order entry message types. Clients send order and ping messages; the gateway answers with
+// hello, ack, reject, execReport, pong and error messages.
+const (
+	orderEntryHello      = "hello"
+	orderEntryOrder      = "order"
+	orderEntryAck        = "ack"
+	orderEntryReject     = "reject"
+	orderEntryExecReport = "execReport"
+	orderEntryPing       = "ping"
+	orderEntryPong       = "pong"
+	orderEntryError      = "error"
+)
+
+// orderEntryRequest is a message sent by an order entry client. Ref is the client's own reference,
+// echoed on the ack or reject of the order.
+type orderEntryRequest struct {
+	Type  string                        `json:"type"`
+	Ref   string                        `json:"ref,omitempty"`
+	Order *runtime.ExternalOrderRequest `json:"order,omitempty"`
+}
+
+// orderEntryMessage is a message sent to an order entry client.
+type orderEntryMessage struct {
+	Type          string                      `json:"type"`
+	Ref           string                      `json:"ref,omitempty"`
+	Client        string                      `json:"client,omitempty"`
+	Instance      string                      `json:"instance,omitempty"`
+	ClientOrderID string                      `json:"clientOrderId,omitempty"`
+	Status        string                      `json:"status,omitempty"`
+	Error         string                      `json:"error,omitempty"`
+	ExecReport    *runtime.ExternalExecReport `json:"execReport,omitempty"`
+}
+
+// mountOrderEntry serves the external order entry websocket when enabled. Like the profiler, it
+// does not trust the proxy in front of the control API: each client presents its own token, which
+// also selects the instance its orders are attributed to.
+func mountOrderEntry(mux *http.ServeMux, server *httpServer, cfg config.OrderEntryConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	clients := append([]config.OrderEntryClientConfig(nil), cfg.Clients...)
+	mux.Handle(orderEntryPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: func(w http.ResponseWriter, r *http.Request) {
+			client, ok := orderEntryClient(clients, r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="order-entry"`)
+				writeError(w, http.StatusUnauthorized, "valid order entry token required")
+				return
+			}
+			server.serveOrderEntry(w, r, client)
+		},
+	}))
+}
+
+// orderEntryClient returns the client whose token the request carries.
+func orderEntryClient(clients []config.OrderEntryClientConfig, r *http.Request) (config.OrderEntryClientConfig, bool) {
+	var empty config.OrderEntryClientConfig
+	auth := strings.TrimSpace(r.Header.Get("Authorization"))
+	presented, ok := strings.CutPrefix(auth, "Bearer ")
+	if !ok {
+		return empty, false
+	}
+	presented = strings.TrimSpace(presented)
+	for _, client := range clients {
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(client.Token)) == 1 {
+			return client, true
+		}
+	}
+	return empty, false
+}
+
+// serveOrderEntry upgrades the request to a websocket, then submits the client's orders through
+// its instance and streams the instance's execution reports until either side closes.
+func (s *httpServer) serveOrderEntry(w http.ResponseWriter, r *http.Request, client config.OrderEntryClientConfig) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	reports, unsubscribe, err := s.manager.SubscribeExecReports(client.Instance, orderEntryReportBuffer)
+	if err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	defer unsubscribe()
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		// Accept has already answered the request.
+		return
+	}
+	defer func() {
+		_ = conn.CloseNow()
+	}()
+	conn.SetReadLimit(maxJSONBodyBytes)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		streamOrderEntryReports(ctx, conn, reports)
+	}()
+
+	if writeOrderEntryMessage(ctx, conn, orderEntryMessage{
+		Type:          orderEntryHello,
+		Ref:           "",
+		Client:        client.Name,
+		Instance:      client.Instance,
+		ClientOrderID: "",
+		Status:        "",
+		Error:         "",
+		ExecReport:    nil,
+	}) != nil {
+		return
+	}
+	for {
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		reply := s.handleOrderEntryMessage(ctx, client, data)
+		if writeOrderEntryMessage(ctx, conn, reply) != nil {
+			return
+		}
+	}
+}
+
+func (s *httpServer) handleOrderEntryMessage(ctx context.Context, client config.OrderEntryClientConfig, data []byte) orderEntryMessage {
+	reply := orderEntryMessage{
+		Type:          orderEntryError,
+		Ref:           "",
+		Client:        "",
+		Instance:      "",
+		ClientOrderID: "",
+		Status:        "",
+		Error:         "",
+		ExecReport:    nil,
+	}
+	var request orderEntryRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		reply.Error = "invalid message: " + err.Error()
+		return reply
+	}
+	reply.Ref = request.Ref
+	switch request.Type {
+	case orderEntryPing:
+		reply.Type = orderEntryPong
+	case orderEntryOrder:
+		if request.Order == nil {
+			reply.Type = orderEntryReject
+			reply.Error = "order required"
+			return reply
+		}
+		result, err := s.manager.SubmitExternalOrder(ctx, client.Instance, client.Name, *request.Order)
+		if err != nil {
+			reply.Type = orderEntryReject
+			reply.Error = redact.Error(err)
+			return reply
+		}
+		reply.Type = orderEntryAck
+		reply.ClientOrderID = result.ClientOrderID
+		reply.Status = result.Status
+	default:
+		reply.Error = "unknown message type " + request.Type
+	}
+	return reply
+}
+
+// streamOrderEntryReports writes execution reports to the client until ctx ends or a write fails.
+// When the subscription is cut off for falling behind, the connection is closed with
+// orderEntryOverflowReason.
+func streamOrderEntryReports(ctx context.Context, conn *websocket.Conn, reports <-chan runtime.ExternalExecReport) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case report, ok := <-reports:
+			if !ok {
+				_ = conn.Close(websocket.StatusPolicyViolation, orderEntryOverflowReason)
+				return
+			}
+			if writeOrderEntryMessage(ctx, conn, orderEntryMessage{
+				Type:          orderEntryExecReport,
+				Ref:           "",
+				Client:        "",
+				Instance:      "",
+				ClientOrderID: "",
+				Status:        "",
+				Error:         "",
+				ExecReport:    &report,
+			}) != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeOrderEntryMessage(ctx context.Context, conn *websocket.Conn, message orderEntryMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("encode order entry message: %w", err)
+	}
+	writeCtx, cancel := context.WithTimeout(ctx, orderEntryWriteTimeout)
+	defer cancel()
+	return conn.Write(writeCtx, websocket.MessageText, data) //nolint:wrapcheck // the session ends on any write error
+}
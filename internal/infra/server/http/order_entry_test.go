@@ -0,0 +1,129 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	json "github.com/goccy/go-json"
+
+	lambdaruntime "github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/infra/config"
+	strategiestest "github.com/coachpo/meltica/internal/testutil/strategies"
+)
+
+func TestOrderEntryWebsocket(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+		APIServer: config.APIServerConfig{OrderEntry: config.OrderEntryConfig{
+			Enabled: true,
+			Clients: []config.OrderEntryClientConfig{
+				{Name: "otc-desk", Token: "desk-token-0123456789", Instance: "desk"},
+				{Name: "orphan", Token: "orphan-token-0123456789", Instance: "missing"},
+			},
+		}},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if _, err := manager.Create(config.LambdaSpec{
+		ID:              "desk",
+		Strategy:        config.LambdaStrategySpec{Identifier: "noop"},
+		Providers:       []string{"binance"},
+		ProviderSymbols: map[string]config.ProviderSymbols{"binance": {Symbols: []string{"BTC-USDT"}}},
+	}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	server := httptest.NewServer(NewHandler(appCfg, manager, nil, &stubOrderStore{}))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + orderEntryPath
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dial := func(token string) (*websocket.Conn, *http.Response, error) {
+		return websocket.Dial(ctx, url, &websocket.DialOptions{HTTPHeader: http.Header{"Authorization": {"Bearer " + token}}})
+	}
+	if _, resp, err := dial("wrong-token"); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an unknown token, got %v", err)
+	}
+	if _, resp, err := dial("orphan-token-0123456789"); err == nil || resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for a client of a missing instance, got %v", err)
+	}
+
+	conn, _, err := dial("desk-token-0123456789")
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.CloseNow() }()
+	read := func() orderEntryMessage {
+		t.Helper()
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		var message orderEntryMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			t.Fatalf("decode %s: %v", data, err)
+		}
+		return message
+	}
+	if hello := read(); hello.Type != orderEntryHello || hello.Client != "otc-desk" || hello.Instance != "desk" {
+		t.Fatalf("unexpected hello %+v", hello)
+	}
+	for _, tc := range []struct {
+		request, wantType, wantError string
+	}{
+		{`{"type":"ping","ref":"p1"}`, orderEntryPong, ""},
+		{`{"type":"order","ref":"o1","order":{"side":"buy","quantity":"1","price":"100"}}`, orderEntryReject, "not running"},
+		{`{"type":"order","ref":"o2"}`, orderEntryReject, "order required"},
+		{`{"type":"cancel","ref":"c1"}`, orderEntryError, "unknown message type"},
+	} {
+		if err := conn.Write(ctx, websocket.MessageText, []byte(tc.request)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		reply := read()
+		if reply.Type != tc.wantType || reply.Ref == "" || !strings.Contains(reply.Error, tc.wantError) {
+			t.Fatalf("%s: unexpected reply %+v", tc.request, reply)
+		}
+	}
+}
+
+func TestOrderEntryClosesOverflowingClients(t *testing.T) {
+	reports := make(chan lambdaruntime.ExternalExecReport, 1)
+	reports <- lambdaruntime.ExternalExecReport{Instance: "desk", Provider: "binance", Symbol: "BTC-USDT"}
+	close(reports)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.CloseNow() }()
+		streamOrderEntryReports(r.Context(), conn, reports)
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer func() { _ = conn.CloseNow() }()
+
+	_, data, err := conn.Read(ctx)
+	if err != nil || !strings.Contains(string(data), `"execReport"`) {
+		t.Fatalf("expected the buffered report first, got %s (%v)", data, err)
+	}
+	_, _, err = conn.Read(ctx)
+	var closeErr websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.StatusPolicyViolation || closeErr.Reason != orderEntryOverflowReason {
+		t.Fatalf("expected the overflow close, got %v", err)
+	}
+}
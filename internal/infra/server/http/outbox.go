@@ -0,0 +1,240 @@
+package httpserver
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/domain/outboxstore"
+)
+
+const (
+	outboxPath          = "/outbox"
+	outboxEntryPrefix   = outboxPath + "/"
+	outboxRequeuePath   = outboxPath + "/requeue"
+	outboxRequeueSuffix = "requeue"
+
+	defaultOutboxLimit = 100
+)
+
+// outboxEntry is the API view of an outbox row. Payload carries the event as published, already
+// decompressed, and is only filled for single-entry reads.
+type outboxEntry struct {
+	ID            int64             `json:"id"`
+	AggregateType string            `json:"aggregateType"`
+	AggregateID   string            `json:"aggregateId"`
+	EventType     string            `json:"eventType"`
+	Headers       map[string]any    `json:"headers,omitempty"`
+	PayloadCodec  outboxstore.Codec `json:"payloadCodec"`
+	Delivered     bool              `json:"delivered"`
+	Attempts      int               `json:"attempts"`
+	LastError     string            `json:"lastError,omitempty"`
+	AvailableAt   time.Time         `json:"availableAt"`
+	PublishedAt   *time.Time        `json:"publishedAt,omitempty"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	Payload       json.RawMessage   `json:"payload,omitempty"`
+}
+
+type outboxRequeuePayload struct {
+	AfterID    int64     `json:"afterId"`
+	Since      time.Time `json:"since"`
+	Until      time.Time `json:"until"`
+	EventTypes []string  `json:"eventTypes"`
+	Providers  []string  `json:"providers"`
+	Limit      int       `json:"limit"`
+}
+
+func newOutboxEntry(record outboxstore.EventRecord, withPayload bool) outboxEntry {
+	entry := outboxEntry{
+		ID:            record.ID,
+		AggregateType: record.AggregateType,
+		AggregateID:   record.AggregateID,
+		EventType:     record.EventType,
+		Headers:       record.Headers,
+		PayloadCodec:  record.PayloadCodec,
+		Delivered:     record.Delivered,
+		Attempts:      record.Attempts,
+		LastError:     record.LastError,
+		AvailableAt:   record.AvailableAt,
+		PublishedAt:   record.PublishedAt,
+		CreatedAt:     record.CreatedAt,
+		Payload:       nil,
+	}
+	if withPayload {
+		entry.Payload = record.Payload
+	}
+	return entry
+}
+
+// listOutbox pages through outbox entries by identifier, filtered by creation time, event type
+// and provider. Payloads are left out; GET /outbox/{id} returns them.
+func (s *httpServer) listOutbox(w http.ResponseWriter, r *http.Request) {
+	if s.outbox == nil {
+		writeError(w, http.StatusServiceUnavailable, "outbox unavailable")
+		return
+	}
+	values := r.URL.Query()
+	since, until, err := parseTimeRangeParams(values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := parseLimitParam(values.Get("limit"), defaultOutboxLimit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var afterID int64
+	if raw := strings.TrimSpace(values.Get("afterId")); raw != "" {
+		afterID, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || afterID < 0 {
+			writeError(w, http.StatusBadRequest, "invalid afterId")
+			return
+		}
+	}
+	records, err := s.outbox.ListEvents(r.Context(), outboxstore.EventQuery{
+		AfterID:    afterID,
+		Since:      since,
+		Until:      until,
+		EventTypes: values["eventType"],
+		Providers:  values["provider"],
+		Limit:      limit,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	entries := make([]outboxEntry, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, newOutboxEntry(record, false))
+	}
+	response := map[string]any{
+		"events": entries,
+		"count":  len(entries),
+	}
+	if len(records) == limit {
+		response["nextAfterId"] = records[len(records)-1].ID
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *httpServer) handleOutboxEntry(w http.ResponseWriter, r *http.Request) {
+	if s.outbox == nil {
+		writeError(w, http.StatusServiceUnavailable, "outbox unavailable")
+		return
+	}
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, outboxEntryPrefix), "/")
+	rawID, action, _ := strings.Cut(rest, "/")
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || id <= 0 || strings.Contains(action, "/") {
+		writeError(w, http.StatusNotFound, "outbox id required")
+		return
+	}
+	switch action {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			record, err := s.outbox.GetEvent(r.Context(), id)
+			if err != nil {
+				writeOutboxError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, newOutboxEntry(record, true))
+		case http.MethodDelete:
+			if err := s.outbox.Delete(r.Context(), id); err != nil {
+				writeOutboxError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			methodNotAllowed(w, http.MethodDelete, http.MethodGet)
+		}
+	case outboxRequeueSuffix:
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		record, err := s.outbox.Requeue(r.Context(), id)
+		if err != nil {
+			writeOutboxError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newOutboxEntry(record, false))
+	default:
+		writeError(w, http.StatusNotFound, "unsupported action")
+	}
+}
+
+// requeueOutbox returns the entries matching a filter to pending so the durable bus replay worker
+// publishes them again. At least one filter is required; callers page through larger sets with
+// afterId.
+func (s *httpServer) requeueOutbox(w http.ResponseWriter, r *http.Request) {
+	if s.outbox == nil {
+		writeError(w, http.StatusServiceUnavailable, "outbox unavailable")
+		return
+	}
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload outboxRequeuePayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	if payload.AfterID == 0 && payload.Since.IsZero() && payload.Until.IsZero() &&
+		len(payload.EventTypes) == 0 && len(payload.Providers) == 0 {
+		writeError(w, http.StatusBadRequest, "at least one filter required")
+		return
+	}
+	if payload.AfterID < 0 || payload.Limit < 0 {
+		writeError(w, http.StatusBadRequest, "afterId and limit must be >= 0")
+		return
+	}
+	if !payload.Since.IsZero() && !payload.Until.IsZero() && !payload.Since.Before(payload.Until) {
+		writeError(w, http.StatusBadRequest, "since must be before until")
+		return
+	}
+	limit := payload.Limit
+	if limit == 0 {
+		limit = defaultOutboxLimit
+	} else if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	ids, err := s.outbox.RequeueEvents(r.Context(), outboxstore.EventQuery{
+		AfterID:    payload.AfterID,
+		Since:      payload.Since,
+		Until:      payload.Until,
+		EventTypes: payload.EventTypes,
+		Providers:  payload.Providers,
+		Limit:      limit,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if ids == nil {
+		ids = []int64{}
+	}
+	response := map[string]any{
+		"requeued": ids,
+		"count":    len(ids),
+	}
+	if len(ids) == limit {
+		response["nextAfterId"] = ids[len(ids)-1]
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func writeOutboxError(w http.ResponseWriter, err error) {
+	if errors.Is(err, outboxstore.ErrEventNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,115 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/domain/outboxstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type stubOutbox struct {
+	records  map[int64]*outboxstore.EventRecord
+	requeued outboxstore.EventQuery
+}
+
+func (s *stubOutbox) ListEvents(_ context.Context, query outboxstore.EventQuery) ([]outboxstore.EventRecord, error) {
+	var out []outboxstore.EventRecord
+	for id := query.AfterID + 1; id <= int64(len(s.records)) && len(out) < query.Limit; id++ {
+		if record, ok := s.records[id]; ok {
+			out = append(out, *record)
+		}
+	}
+	return out, nil
+}
+
+func (s *stubOutbox) GetEvent(_ context.Context, id int64) (outboxstore.EventRecord, error) {
+	record, ok := s.records[id]
+	if !ok {
+		return outboxstore.EventRecord{}, outboxstore.ErrEventNotFound
+	}
+	return *record, nil
+}
+
+func (s *stubOutbox) Requeue(_ context.Context, id int64) (outboxstore.EventRecord, error) {
+	record, ok := s.records[id]
+	if !ok {
+		return outboxstore.EventRecord{}, outboxstore.ErrEventNotFound
+	}
+	record.Delivered = false
+	record.PublishedAt = nil
+	return *record, nil
+}
+
+func (s *stubOutbox) RequeueEvents(_ context.Context, query outboxstore.EventQuery) ([]int64, error) {
+	s.requeued = query
+	return []int64{1, 2}, nil
+}
+
+func (s *stubOutbox) Delete(_ context.Context, id int64) error {
+	delete(s.records, id)
+	return nil
+}
+
+func TestOutboxEndpoints(t *testing.T) {
+	published := time.Now().UTC()
+	outbox := &stubOutbox{records: map[int64]*outboxstore.EventRecord{
+		1: {ID: 1, EventType: "Trade", Payload: json.RawMessage(`{"eventId":"evt-1"}`), Delivered: true, PublishedAt: &published},
+		2: {ID: 2, EventType: "ExecReport", Payload: json.RawMessage(`{"eventId":"evt-2"}`), Attempts: 3, LastError: "bus closed"},
+	}}
+	handler := NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{}, WithOutbox(outbox))
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodGet, outboxPath+"?limit=1", "")
+	if rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), "evt-1") || !strings.Contains(rec.Body.String(), `"nextAfterId":1`) {
+		t.Fatalf("unexpected list response %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = do(http.MethodGet, outboxEntryPrefix+"1", "")
+	var entry outboxEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entry); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("unexpected entry response %d %s", rec.Code, rec.Body.String())
+	}
+	if string(entry.Payload) != `{"eventId":"evt-1"}` || !entry.Delivered {
+		t.Fatalf("expected decoded payload, got %+v", entry)
+	}
+	if rec := do(http.MethodGet, outboxEntryPrefix+"9", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown entry, got %d", rec.Code)
+	}
+
+	rec = do(http.MethodPost, outboxEntryPrefix+"1/requeue", "")
+	if rec.Code != http.StatusOK || outbox.records[1].Delivered {
+		t.Fatalf("expected entry requeued, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := do(http.MethodPost, outboxRequeuePath, `{}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected an unfiltered bulk requeue to be rejected, got %d", rec.Code)
+	}
+	rec = do(http.MethodPost, outboxRequeuePath, `{"eventTypes":["ExecReport"],"providers":["binance"],"limit":2}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"count":2`) || !strings.Contains(rec.Body.String(), `"nextAfterId":2`) {
+		t.Fatalf("unexpected bulk requeue response %d %s", rec.Code, rec.Body.String())
+	}
+	if outbox.requeued.Limit != 2 || outbox.requeued.EventTypes[0] != "ExecReport" || outbox.requeued.Providers[0] != "binance" {
+		t.Fatalf("unexpected requeue query %+v", outbox.requeued)
+	}
+
+	if rec := do(http.MethodDelete, outboxEntryPrefix+"2", ""); rec.Code != http.StatusNoContent || outbox.records[2] != nil {
+		t.Fatalf("expected entry deleted, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, outboxPath, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without an outbox, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,41 @@
+package httpserver
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+const pprofPath = "/debug/pprof/"
+
+// mountPprof serves the Go profiler when enabled. Unlike the rest of the control API, which
+// trusts the proxy in front of it, profiles expose heap contents, so every request must present
+// the configured token; without one, all requests are refused.
+func mountPprof(mux *http.ServeMux, cfg config.APIServerPprofConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	profiles := http.NewServeMux()
+	profiles.HandleFunc(pprofPath, pprof.Index)
+	profiles.HandleFunc(pprofPath+"cmdline", pprof.Cmdline)
+	profiles.HandleFunc(pprofPath+"profile", pprof.Profile)
+	profiles.HandleFunc(pprofPath+"symbol", pprof.Symbol)
+	profiles.HandleFunc(pprofPath+"trace", pprof.Trace)
+	mux.Handle(pprofPath, requirePprofAuth(cfg.Token, profiles))
+}
+
+func requirePprofAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimSpace(r.Header.Get("Authorization"))
+		presented, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(presented)), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pprof"`)
+			writeError(w, http.StatusUnauthorized, "valid pprof token required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
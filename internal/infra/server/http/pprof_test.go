@@ -0,0 +1,44 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+func TestPprofRequiresAuthentication(t *testing.T) {
+	serve := func(cfg config.APIServerPprofConfig, auth string) int {
+		mux := http.NewServeMux()
+		mountPprof(mux, cfg)
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/cmdline", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := serve(config.APIServerPprofConfig{}, "Bearer anything"); code != http.StatusNotFound {
+		t.Fatalf("expected pprof unmounted by default, got %d", code)
+	}
+	open := config.APIServerPprofConfig{Enabled: true}
+	if code := serve(open, ""); code != http.StatusUnauthorized {
+		t.Fatalf("expected anonymous request refused, got %d", code)
+	}
+	if code := serve(open, "Bearer anything"); code != http.StatusUnauthorized {
+		t.Fatalf("expected any bearer refused without a configured token, got %d", code)
+	}
+	if code := serve(open, "Bearer "); code != http.StatusUnauthorized {
+		t.Fatalf("expected empty bearer refused without a configured token, got %d", code)
+	}
+	tokened := config.APIServerPprofConfig{Enabled: true, Token: "s3cret"}
+	if code := serve(tokened, "Bearer operator"); code != http.StatusUnauthorized {
+		t.Fatalf("expected wrong token refused, got %d", code)
+	}
+	if code := serve(tokened, "Bearer s3cret"); code != http.StatusOK {
+		t.Fatalf("expected matching token accepted, got %d", code)
+	}
+}
@@ -0,0 +1,54 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/lambda/runtime"
+)
+
+type riskRulePayload struct {
+	Enabled *bool `json:"enabled"`
+}
+
+func (s *httpServer) listRiskRules(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.manager.RiskRules())
+}
+
+func (s *httpServer) handleRiskRule(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, riskRulePrefix), "/")
+	if name == "" || strings.Contains(name, "/") {
+		writeError(w, http.StatusNotFound, "risk rule name required")
+		return
+	}
+	if r.Method != http.MethodPut {
+		methodNotAllowed(w, http.MethodPut)
+		return
+	}
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload riskRulePayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if payload.Enabled == nil {
+		writeError(w, http.StatusBadRequest, "enabled required")
+		return
+	}
+	status, err := s.manager.SetRiskRuleEnabled(name, *payload.Enabled)
+	if err != nil {
+		if errors.Is(err, runtime.ErrRiskRuleNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
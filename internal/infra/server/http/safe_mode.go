@@ -0,0 +1,175 @@
+package httpserver
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/providerstore"
+	"github.com/coachpo/meltica/internal/domain/strategystore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+const (
+	safeModePath           = "/admin/safe-mode"
+	safeModeProvidersPath  = safeModePath + "/providers"
+	safeModeStrategiesPath = safeModePath + "/strategies"
+)
+
+// SafeModeFailure records a startup step whose failure put the gateway in safe mode.
+type SafeModeFailure struct {
+	Stage string    `json:"stage"`
+	Error string    `json:"error"`
+	At    time.Time `json:"at"`
+}
+
+// SafeModeState describes why the gateway is in safe mode. The stores are nil when the database
+// could not be reached.
+type SafeModeState struct {
+	Forced     bool
+	Failures   []SafeModeFailure
+	Providers  providerstore.Store
+	Strategies strategystore.Store
+}
+
+type safeModeStatusResponse struct {
+	Active    bool              `json:"active"`
+	Forced    bool              `json:"forced"`
+	StartedAt time.Time         `json:"startedAt"`
+	Failures  []SafeModeFailure `json:"failures"`
+}
+
+type persistedProviderSummary struct {
+	Name    string `json:"name"`
+	Adapter string `json:"adapter"`
+	Status  string `json:"status,omitempty"`
+}
+
+type persistedStrategySummary struct {
+	ID        string              `json:"id"`
+	Strategy  string              `json:"strategy"`
+	Tag       string              `json:"tag,omitempty"`
+	Hash      string              `json:"hash,omitempty"`
+	Providers []string            `json:"providers"`
+	Scope     map[string][]string `json:"scope"`
+	Running   bool                `json:"running"`
+	Baseline  bool                `json:"baseline"`
+	Dynamic   bool                `json:"dynamic"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+// NewSafeModeHandler serves the control API of a gateway that started without providers or
+// strategies. Only read-only diagnostics are available: /admin/info, the safe mode status and the
+// persisted provider and strategy snapshots. Every other route answers 503.
+func NewSafeModeHandler(appCfg config.AppConfig, state SafeModeState, opts ...HandlerOption) http.Handler {
+	options := handlerOptions{accessLogger: nil, eventHistory: nil, outbox: nil, calendar: nil, flags: nil, egress: nil, eventAudit: nil, datasets: nil, datasetBytes: 0, build: BuildInfo{Version: "", Commit: "", BuildTime: "", GoVersion: ""}, startedAt: time.Now(), schemaVersion: nil}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	server := &httpServer{
+		manager:         nil,
+		providers:       nil,
+		orderStore:      nil,
+		audit:           nil,
+		calendar:        nil,
+		flags:           nil,
+		egress:          nil,
+		eventAudit:      nil,
+		outbox:          nil,
+		datasets:        nil,
+		datasetMaxBytes: 0,
+		baseProviders:   map[string]struct{}{},
+		environment:     string(appCfg.Environment),
+		build:           options.build,
+		startedAt:       options.startedAt,
+		schemaVersion:   options.schemaVersion,
+		safeMode:        &state,
+		balanceCurrency: "",
+	}
+	mux := http.NewServeMux()
+	mux.Handle(adminInfoPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getRuntimeInfo,
+	}))
+	mux.Handle(safeModePath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getSafeModeStatus,
+	}))
+	mux.Handle(safeModeProvidersPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.listPersistedProviders,
+	}))
+	mux.Handle(safeModeStrategiesPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.listPersistedStrategies,
+	}))
+	mountPprof(mux, appCfg.APIServer.Pprof)
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusServiceUnavailable, "gateway is in safe mode; see "+safeModePath)
+	}))
+	return withRequestID(withCORS(withConditionalResponses(mux)), options.accessLogger)
+}
+
+func (s *httpServer) getSafeModeStatus(w http.ResponseWriter, _ *http.Request) {
+	failures := append([]SafeModeFailure{}, s.safeMode.Failures...)
+	writeJSON(w, http.StatusOK, safeModeStatusResponse{
+		Active:    true,
+		Forced:    s.safeMode.Forced,
+		StartedAt: s.startedAt.UTC(),
+		Failures:  failures,
+	})
+}
+
+// listPersistedProviders reads the provider snapshots straight from the store, so a snapshot that
+// fails to load surfaces its error here. Adapter settings are omitted since they hold credentials.
+func (s *httpServer) listPersistedProviders(w http.ResponseWriter, r *http.Request) {
+	if s.safeMode.Providers == nil {
+		writeError(w, http.StatusServiceUnavailable, "provider store unavailable")
+		return
+	}
+	snapshots, err := s.safeMode.Providers.LoadProviders(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]persistedProviderSummary, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		out = append(out, persistedProviderSummary{
+			Name:    snapshot.Name,
+			Adapter: snapshot.Adapter,
+			Status:  snapshot.Status,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name) })
+	writeJSON(w, http.StatusOK, map[string]any{"providers": out})
+}
+
+// listPersistedStrategies reads the strategy instance snapshots straight from the store. Strategy
+// configs are omitted.
+func (s *httpServer) listPersistedStrategies(w http.ResponseWriter, r *http.Request) {
+	if s.safeMode.Strategies == nil {
+		writeError(w, http.StatusServiceUnavailable, "strategy store unavailable")
+		return
+	}
+	snapshots, err := s.safeMode.Strategies.Load(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := make([]persistedStrategySummary, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		out = append(out, persistedStrategySummary{
+			ID:        snapshot.ID,
+			Strategy:  snapshot.Strategy.Identifier,
+			Tag:       snapshot.Strategy.Tag,
+			Hash:      snapshot.Strategy.Hash,
+			Providers: cloneStringSlice(snapshot.Providers),
+			Scope:     snapshot.ProviderSymbols,
+			Running:   snapshot.Running,
+			Baseline:  snapshot.Baseline,
+			Dynamic:   snapshot.Dynamic,
+			UpdatedAt: snapshot.UpdatedAt,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	writeJSON(w, http.StatusOK, map[string]any{"strategies": out})
+}
@@ -0,0 +1,91 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/domain/providerstore"
+	"github.com/coachpo/meltica/internal/domain/strategystore"
+	"github.com/coachpo/meltica/internal/infra/config"
+)
+
+type failingStrategyStore struct {
+	strategystore.Store
+}
+
+func (failingStrategyStore) Load(context.Context) ([]strategystore.Snapshot, error) {
+	return nil, errors.New("strategy store: decode metadata: unexpected end of JSON input")
+}
+
+type staticProviderStore struct {
+	providerstore.Store
+	snapshots []providerstore.Snapshot
+}
+
+func (s staticProviderStore) LoadProviders(context.Context) ([]providerstore.Snapshot, error) {
+	return s.snapshots, nil
+}
+
+func TestSafeModeHandlerServesDiagnosticsOnly(t *testing.T) {
+	state := SafeModeState{
+		Forced:   false,
+		Failures: []SafeModeFailure{{Stage: "strategy-restore", Error: "decode metadata", At: time.Now()}},
+		Providers: staticProviderStore{snapshots: []providerstore.Snapshot{
+			{Name: "binance", Adapter: "binance", Config: map[string]any{"api_key": "secret"}, Status: "running"},
+		}},
+		Strategies: failingStrategyStore{},
+	}
+	handler := NewSafeModeHandler(config.AppConfig{Environment: config.Environment("prod")}, state)
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/admin/safe-mode")
+	var status safeModeStatusResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if rec.Code != http.StatusOK || !status.Active || len(status.Failures) != 1 || status.Failures[0].Stage != "strategy-restore" {
+		t.Fatalf("unexpected status %d %+v", rec.Code, status)
+	}
+
+	rec = serve(http.MethodGet, "/admin/info")
+	var info runtimeInfoResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("decode info: %v", err)
+	}
+	if !info.SafeMode || info.Environment != "prod" {
+		t.Fatalf("unexpected info %+v", info)
+	}
+
+	rec = serve(http.MethodGet, "/admin/safe-mode/providers")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("providers: expected 200, got %d", rec.Code)
+	}
+	if body := rec.Body.String(); !json.Valid(rec.Body.Bytes()) || strings.Contains(body, "secret") {
+		t.Fatalf("expected provider settings omitted, got %s", body)
+	}
+	if rec := serve(http.MethodGet, "/admin/safe-mode/strategies"); rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "decode metadata") {
+		t.Fatalf("strategies: expected load error surfaced, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, "/strategy/instances"},
+		{http.MethodPost, "/providers"},
+	} {
+		if rec := serve(tc.method, tc.path); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("%s %s: expected 503, got %d", tc.method, tc.path, rec.Code)
+		}
+	}
+	if rec := serve(http.MethodPost, "/admin/safe-mode"); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected diagnostics to be read-only, got %d", rec.Code)
+	}
+}
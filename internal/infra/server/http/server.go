@@ -3,6 +3,8 @@ package httpserver
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -11,60 +13,127 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	json "github.com/goccy/go-json"
 	"github.com/shopspring/decimal"
 
+	"github.com/coachpo/meltica/internal/app/approvals"
+	"github.com/coachpo/meltica/internal/app/audit"
+	"github.com/coachpo/meltica/internal/app/calendar"
+	"github.com/coachpo/meltica/internal/app/egressip"
+	"github.com/coachpo/meltica/internal/app/eventaudit"
+	"github.com/coachpo/meltica/internal/app/featureflags"
 	"github.com/coachpo/meltica/internal/app/lambda/js"
 	"github.com/coachpo/meltica/internal/app/lambda/runtime"
 	"github.com/coachpo/meltica/internal/app/provider"
 	"github.com/coachpo/meltica/internal/app/risk"
+	"github.com/coachpo/meltica/internal/domain/datasetstore"
 	"github.com/coachpo/meltica/internal/domain/orderstore"
+	"github.com/coachpo/meltica/internal/domain/outboxstore"
 	"github.com/coachpo/meltica/internal/infra/config"
 	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/infra/redact"
+	"github.com/coachpo/meltica/internal/infra/server/http/ui"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
 )
 
 const (
 	maxJSONBodyBytes int64 = 1 << 20 // 1 MiB
 
-	strategiesPath       = "/strategies"
-	strategyDetailPrefix = strategiesPath + "/"
-	strategyModulesPath  = strategiesPath + "/modules"
-	strategyModulePrefix = strategyModulesPath + "/"
-	strategyRefreshPath  = strategiesPath + "/refresh"
-	strategyRegistryPath = strategiesPath + "/registry"
-	strategySourceSuffix = "/source"
-	strategyUsageSuffix  = "/usage"
+	strategiesPath        = "/strategies"
+	strategyDetailPrefix  = strategiesPath + "/"
+	strategyModulesPath   = strategiesPath + "/modules"
+	strategyModulePrefix  = strategyModulesPath + "/"
+	strategySearchPath    = strategyModulesPath + "/search"
+	strategyRefreshPath   = strategiesPath + "/refresh"
+	strategyRegistryPath  = strategiesPath + "/registry"
+	strategyArchivePath   = strategiesPath + "/archive"
+	strategySourceSuffix  = "/source"
+	strategyUsageSuffix   = "/usage"
+	strategyHistorySuffix = "/history"
 
 	providersPath        = "/providers"
 	providerDetailPrefix = providersPath + "/"
 
-	adaptersPath        = "/adapters"
-	adapterDetailPrefix = adaptersPath + "/"
+	adaptersPath              = "/adapters"
+	adapterDetailPrefix       = adaptersPath + "/"
+	adapterConfigSchemaSuffix = "config-schema"
 
 	instancesPath        = "/strategy/instances"
 	instanceDetailPrefix = instancesPath + "/"
-
-	riskLimitsPath    = "/risk/limits"
-	contextBackupPath = "/context/backup"
+	instanceGroupsPath   = "/strategy/instance-groups"
+
+	riskLimitsPath        = "/risk/limits"
+	riskHeartbeatPath     = "/risk/heartbeat"
+	riskStatusPath        = "/risk/status"
+	riskKillSwitchPath    = "/risk/kill-switch"
+	riskCheckPath         = "/risk/check"
+	riskProfilesPath      = "/risk/profiles"
+	riskProfilePrefix     = riskProfilesPath + "/"
+	riskRulesPath         = "/risk/rules"
+	riskRulePrefix        = riskRulesPath + "/"
+	riskConcentrationPath = "/risk/concentration"
+	riskSymbolPolicyPath  = "/risk/symbol-policy"
+	calendarPath          = "/calendar"
+	calendarEntryPrefix   = calendarPath + "/"
+	contextBackupPath     = "/context/backup"
+	adminInfoPath         = "/admin/info"
+	adminFlagsPath        = "/admin/flags"
+	adminFlagPrefix       = adminFlagsPath + "/"
+	adminEgressPath       = "/admin/egress"
+	adminEventAuditPath   = "/admin/event-audit"
+	uiPath                = "/ui"
 
 	instanceOrdersSuffix     = "orders"
 	instanceExecutionsSuffix = "executions"
+	instanceAlgosSuffix      = "algos"
+	instanceQueueSuffix      = "order-queue"
+	instanceCallbacksSuffix  = "order-callbacks"
+	instanceAuditSuffix      = "audit"
+	instanceFaultsSuffix     = "faults"
+	instanceLogsSuffix       = "logs"
+	instanceTradingSuffix    = "trading"
+	instanceRiskSuffix       = "risk-profile"
+	instanceShadowSuffix     = "shadow"
+	instanceSubsSuffix       = "subscriptions"
+	riskProfileApplySuffix   = "apply"
 	providerBalancesSuffix   = "balances"
+	providerCircuitSuffix    = "order-circuit"
+	providerQueueSuffix      = "order-queue"
 
 	defaultOrdersLimit     = 50
 	defaultExecutionsLimit = 100
 	defaultBalancesLimit   = 100
 	maxListLimit           = 500
+
+	defaultAuditWindow = 24 * time.Hour
+	auditFlushEvery    = 256
 )
 
 type handlerFunc func(http.ResponseWriter, *http.Request)
 
 type httpServer struct {
-	manager       *runtime.Manager
-	providers     *provider.Manager
-	orderStore    orderstore.Store
-	baseProviders map[string]struct{}
+	manager         *runtime.Manager
+	providers       *provider.Manager
+	orderStore      orderstore.Store
+	audit           *audit.Exporter
+	calendar        *calendar.Calendar
+	flags           *featureflags.Flags
+	egress          *egressip.Monitor
+	eventAudit      *eventaudit.Monitor
+	approvals       *approvals.Queue
+	outbox          outboxstore.EventBrowser
+	datasets        datasetstore.Store
+	datasetMaxBytes int64
+	baseProviders   map[string]struct{}
+	environment     string
+	build           BuildInfo
+	startedAt       time.Time
+	schemaVersion   SchemaVersionFunc
+	safeMode        *SafeModeState
+	// balanceCurrency values the aggregated balances; empty falls back to the notional currency.
+	balanceCurrency string
 }
 
 type providerPayload struct {
@@ -84,13 +153,63 @@ type contextBackup struct {
 	Risk      config.RiskConfig     `json:"risk"`
 }
 
+type riskHeartbeatPayload struct {
+	Resume bool `json:"resume"`
+}
+
+type riskProfilePayload struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Limits      config.RiskConfig `json:"limits"`
+}
+
+type riskProfileApplyPayload struct {
+	Instances []string `json:"instances"`
+}
+
+type instanceRiskProfilePayload struct {
+	Profile string `json:"profile"`
+}
+
+type instanceTradingPayload struct {
+	Enabled *bool  `json:"enabled"`
+	Actor   string `json:"actor"`
+	Reason  string `json:"reason"`
+}
+
+type instanceShadowPayload struct {
+	Candidate         string  `json:"candidate"`
+	MatchWindow       string  `json:"matchWindow"`
+	PriceToleranceBps float64 `json:"priceToleranceBps"`
+}
+
+type riskCheckPayload struct {
+	Instance  string  `json:"instance"`
+	Provider  string  `json:"provider"`
+	Symbol    string  `json:"symbol"`
+	Side      string  `json:"side"`
+	OrderType string  `json:"orderType"`
+	Quantity  string  `json:"quantity"`
+	Price     *string `json:"price"`
+	TIF       string  `json:"tif"`
+}
+
+type killSwitchPayload struct {
+	Engaged bool   `json:"engaged"`
+	Reason  string `json:"reason,omitempty"`
+}
+
 type strategyModulePayload struct {
 	Source string `json:"source"`
+	Actor  string `json:"actor,omitempty"`
+	Reason string `json:"reason,omitempty"`
 }
 
 type strategyTagPayload struct {
 	Hash    string `json:"hash"`
 	Refresh *bool  `json:"refresh,omitempty"`
+	Actor   string `json:"actor,omitempty"`
+	Reason  string `json:"reason,omitempty"`
 }
 
 type strategyRefreshPayload struct {
@@ -114,7 +233,13 @@ type instanceSnapshotResponse struct {
 }
 
 // NewHandler creates an HTTP handler for lambda management operations.
-func NewHandler(appCfg config.AppConfig, manager *runtime.Manager, providers *provider.Manager, orders orderstore.Store) http.Handler {
+func NewHandler(appCfg config.AppConfig, manager *runtime.Manager, providers *provider.Manager, orders orderstore.Store, opts ...HandlerOption) http.Handler {
+	options := handlerOptions{accessLogger: nil, eventHistory: nil, outbox: nil, calendar: nil, flags: nil, egress: nil, eventAudit: nil, approvals: nil, datasets: nil, datasetBytes: 0, build: BuildInfo{Version: "", Commit: "", BuildTime: "", GoVersion: ""}, startedAt: time.Now(), schemaVersion: nil}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
 	baseProviders := make(map[string]struct{}, len(appCfg.Providers))
 	for name := range appCfg.Providers {
 		normalized := strings.ToLower(strings.TrimSpace(string(name)))
@@ -123,10 +248,25 @@ func NewHandler(appCfg config.AppConfig, manager *runtime.Manager, providers *pr
 		}
 	}
 	server := &httpServer{
-		manager:       manager,
-		providers:     providers,
-		orderStore:    orders,
-		baseProviders: baseProviders,
+		manager:         manager,
+		providers:       providers,
+		orderStore:      orders,
+		audit:           audit.NewExporter(orders, options.eventHistory),
+		calendar:        options.calendar,
+		flags:           options.flags,
+		egress:          options.egress,
+		eventAudit:      options.eventAudit,
+		approvals:       options.approvals,
+		outbox:          options.outbox,
+		datasets:        options.datasets,
+		datasetMaxBytes: options.datasetBytes,
+		baseProviders:   baseProviders,
+		environment:     string(appCfg.Environment),
+		build:           options.build,
+		startedAt:       options.startedAt,
+		schemaVersion:   options.schemaVersion,
+		safeMode:        nil,
+		balanceCurrency: appCfg.APIServer.ReportingCurrency,
 	}
 	mux := http.NewServeMux()
 
@@ -140,19 +280,41 @@ func NewHandler(appCfg config.AppConfig, manager *runtime.Manager, providers *pr
 		http.MethodGet:  server.listStrategyModules,
 		http.MethodPost: server.createStrategyModule,
 	}))
+	mux.Handle(strategySearchPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.searchStrategyModules,
+	}))
 	mux.Handle(strategyModulePrefix, http.HandlerFunc(server.handleStrategyModule))
 	mux.Handle(strategyRefreshPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet:  server.strategyAutoRefreshStatus,
 		http.MethodPost: server.refreshStrategies,
 	}))
 	mux.Handle(strategyRegistryPath, server.methodHandlers(map[string]handlerFunc{
 		http.MethodGet: server.exportStrategyRegistry,
 	}))
+	mux.Handle(strategyArchivePath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet:  server.exportStrategyArchive,
+		http.MethodPost: server.importStrategyArchive,
+	}))
+
+	mux.Handle(strategyReportsPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet:  server.listStrategyReports,
+		http.MethodPost: server.generateStrategyReport,
+	}))
+	mux.Handle(strategyReportPrefix, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getStrategyReport,
+	}))
+	mux.Handle(usageReportPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getUsageReport,
+	}))
 
 	mux.Handle(providersPath, server.methodHandlers(map[string]handlerFunc{
 		http.MethodGet:  server.listProviders,
 		http.MethodPost: server.createProvider,
 	}))
 	mux.Handle(providerDetailPrefix, http.HandlerFunc(server.handleProvider))
+	mux.Handle(balancesPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getBalances,
+	}))
 
 	mux.Handle(adaptersPath, server.methodHandlers(map[string]handlerFunc{
 		http.MethodGet: server.listAdapters,
@@ -166,18 +328,96 @@ func NewHandler(appCfg config.AppConfig, manager *runtime.Manager, providers *pr
 		http.MethodPost: server.createInstance,
 	}))
 	mux.Handle(instanceDetailPrefix, http.HandlerFunc(server.handleInstance))
+	mux.Handle(instanceGroupsPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.listInstanceGroups,
+	}))
 
 	mux.Handle(riskLimitsPath, server.methodHandlers(map[string]handlerFunc{
-		http.MethodGet: server.getRiskLimits,
-		http.MethodPut: server.updateRiskLimits,
+		http.MethodGet:   server.getRiskLimits,
+		http.MethodPut:   server.updateRiskLimits,
+		http.MethodPatch: server.patchRiskLimits,
+	}))
+	mux.Handle(riskHeartbeatPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet:  server.getRiskHeartbeat,
+		http.MethodPost: server.recordRiskHeartbeat,
+	}))
+	mux.Handle(riskStatusPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getRiskStatus,
+	}))
+	mux.Handle(riskKillSwitchPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodPost: server.setKillSwitch,
+	}))
+	mux.Handle(riskCheckPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodPost: server.checkOrderRisk,
 	}))
 
+	mux.Handle(riskProfilesPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet:  server.listRiskProfiles,
+		http.MethodPost: server.createRiskProfile,
+	}))
+	mux.Handle(riskProfilePrefix, http.HandlerFunc(server.handleRiskProfile))
+	mux.Handle(riskRulesPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.listRiskRules,
+	}))
+	mux.Handle(riskRulePrefix, http.HandlerFunc(server.handleRiskRule))
+	mux.Handle(riskConcentrationPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getRiskConcentration,
+	}))
+	mux.Handle(riskSymbolPolicyPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getRiskSymbolPolicy,
+	}))
+	mux.Handle(calendarPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet:  server.listCalendarEntries,
+		http.MethodPost: server.scheduleCalendarEntry,
+	}))
+	mux.Handle(calendarEntryPrefix, http.HandlerFunc(server.handleCalendarEntry))
+	mux.Handle(adminInfoPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getRuntimeInfo,
+	}))
+	mux.Handle(adminFlagsPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.listFeatureFlags,
+	}))
+	mux.Handle(adminFlagPrefix, http.HandlerFunc(server.handleFeatureFlag))
+	mux.Handle(adminEgressPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet:  server.getEgressReport,
+		http.MethodPost: server.checkEgress,
+	}))
+	mux.Handle(adminEventAuditPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.getEventAuditReport,
+	}))
+	mux.Handle(adminFreezePath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet:  server.getFreeze,
+		http.MethodPost: server.setFreeze,
+	}))
+	mux.Handle(approvalsPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.listApprovals,
+	}))
+	mux.Handle(approvalPrefix, http.HandlerFunc(server.handleApproval))
+	mux.Handle(outboxPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet: server.listOutbox,
+	}))
+	mux.Handle(outboxRequeuePath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodPost: server.requeueOutbox,
+	}))
+	mux.Handle(outboxEntryPrefix, http.HandlerFunc(server.handleOutboxEntry))
+	mux.Handle(datasetsPath, server.methodHandlers(map[string]handlerFunc{
+		http.MethodGet:  server.listDatasets,
+		http.MethodPost: server.uploadDataset,
+	}))
+	mux.Handle(datasetPrefix, http.HandlerFunc(server.handleDataset))
 	mux.Handle(contextBackupPath, server.methodHandlers(map[string]handlerFunc{
 		http.MethodGet:  server.handleContextBackupExport,
 		http.MethodPost: server.handleContextBackupRestore,
 	}))
 
-	return withCORS(mux)
+	mountPprof(mux, appCfg.APIServer.Pprof)
+	mountOrderEntry(mux, server, appCfg.APIServer.OrderEntry)
+	if appCfg.APIServer.UI.Enabled {
+		mux.Handle(uiPath, http.RedirectHandler(uiPath+"/", http.StatusMovedPermanently))
+		mux.Handle(uiPath+"/", ui.Handler(uiPath+"/"))
+	}
+
+	return withRequestID(withCORS(withConditionalResponses(mux)), options.accessLogger)
 }
 
 func (s *httpServer) methodHandlers(handlers map[string]handlerFunc) http.Handler {
@@ -256,6 +496,49 @@ func (s *httpServer) listStrategyModules(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, response)
 }
 
+// searchStrategyModules ranks module revisions whose names, metadata or config fields contain every
+// term of q; source=true also scans revision source text.
+func (s *httpServer) searchStrategyModules(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query := strings.TrimSpace(values.Get("q"))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "q required")
+		return
+	}
+	includeSource := false
+	if raw := values.Get("source"); raw != "" {
+		val, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "source must be a boolean")
+			return
+		}
+		includeSource = val
+	}
+	limit := js.DefaultModuleSearchLimit
+	if raw := values.Get("limit"); raw != "" {
+		val, err := strconv.Atoi(raw)
+		if err != nil || val <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = val
+	}
+	matches := []js.ModuleSearchMatch{}
+	if s.manager != nil {
+		matches = s.manager.SearchStrategyModules(js.ModuleSearchOptions{
+			Query:         query,
+			IncludeSource: includeSource,
+			Limit:         limit,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"query":   query,
+		"source":  includeSource,
+		"matches": matches,
+		"limit":   limit,
+	})
+}
+
 func filterModuleSummaries(modules []js.ModuleSummary, values url.Values) ([]js.ModuleSummary, int, int, int, error) {
 	if len(modules) == 0 {
 		return modules, 0, 0, -1, nil
@@ -398,16 +681,21 @@ func (s *httpServer) createStrategyModule(w http.ResponseWriter, r *http.Request
 		ReassignTags:  nil,
 		PromoteLatest: true,
 	}
-	resolution, err := s.manager.UpsertStrategy([]byte(payload.Source), opts)
+	validate, err := parseValidateQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if validate {
+		s.preflightStrategyModule(w, []byte(payload.Source), opts)
+		return
+	}
+	resolution, err := s.manager.UpsertStrategy([]byte(payload.Source), opts, runtime.StrategyChange{Actor: payload.Actor, Reason: payload.Reason, RequestID: telemetry.RequestIDFromContext(r.Context())})
 	if err != nil {
 		s.writeStrategyModuleError(w, err)
 		return
 	}
-	writeJSON(w, http.StatusCreated, map[string]any{
-		"status":            "pending_refresh",
-		"strategyDirectory": s.manager.StrategyDirectory(),
-		"module":            moduleResolutionPayload(resolution),
-	})
+	writeJSON(w, http.StatusCreated, strategyUploadPayload(s.manager.StrategyDirectory(), resolution))
 }
 
 func (s *httpServer) handleStrategyModule(w http.ResponseWriter, r *http.Request) {
@@ -454,6 +742,9 @@ func (s *httpServer) handleStrategyModule(w http.ResponseWriter, r *http.Request
 		case strings.TrimPrefix(strategyUsageSuffix, "/"):
 			s.getStrategyModuleUsage(w, r, name)
 			return
+		case strings.TrimPrefix(strategyHistorySuffix, "/"):
+			s.getStrategyModuleHistory(w, r, name)
+			return
 		default:
 			writeError(w, http.StatusNotFound, "invalid module path")
 			return
@@ -470,7 +761,7 @@ func (s *httpServer) handleStrategyModule(w http.ResponseWriter, r *http.Request
 	case http.MethodPut:
 		s.updateStrategyModule(w, r)
 	case http.MethodDelete:
-		s.deleteStrategyModule(w, name)
+		s.deleteStrategyModule(w, r, name)
 	default:
 		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
 	}
@@ -639,24 +930,59 @@ func (s *httpServer) updateStrategyModule(w http.ResponseWriter, r *http.Request
 		ReassignTags:  nil,
 		PromoteLatest: true,
 	}
-	resolution, err := s.manager.UpsertStrategy([]byte(source), opts)
+	validate, err := parseValidateQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if validate {
+		s.preflightStrategyModule(w, []byte(source), opts)
+		return
+	}
+	resolution, err := s.manager.UpsertStrategy([]byte(source), opts, runtime.StrategyChange{Actor: payload.Actor, Reason: payload.Reason, RequestID: telemetry.RequestIDFromContext(r.Context())})
+	if err != nil {
+		s.writeStrategyModuleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, strategyUploadPayload(s.manager.StrategyDirectory(), resolution))
+}
+
+// preflightStrategyModule validates an upload against the running configuration without persisting it.
+func (s *httpServer) preflightStrategyModule(w http.ResponseWriter, source []byte, opts js.ModuleWriteOptions) {
+	report, err := s.manager.PreflightStrategy(source, opts)
 	if err != nil {
 		s.writeStrategyModuleError(w, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]any{
-		"status":            "pending_refresh",
-		"strategyDirectory": s.manager.StrategyDirectory(),
-		"module":            moduleResolutionPayload(resolution),
+		"status": "validated",
+		"module": map[string]any{
+			"name": report.Strategy,
+			"hash": report.Hash,
+			"tag":  report.Tag,
+		},
+		"preflight": report,
 	})
 }
 
-func (s *httpServer) deleteStrategyModule(w http.ResponseWriter, name string) {
+func parseValidateQuery(r *http.Request) (bool, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get("validate"))
+	if raw == "" {
+		return false, nil
+	}
+	validate, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("validate must be a boolean")
+	}
+	return validate, nil
+}
+
+func (s *httpServer) deleteStrategyModule(w http.ResponseWriter, r *http.Request, name string) {
 	if s.manager == nil {
 		writeError(w, http.StatusServiceUnavailable, "strategy manager unavailable")
 		return
 	}
-	if err := s.manager.RemoveStrategy(name); err != nil {
+	if err := s.manager.RemoveStrategy(name, strategyChangeFromQuery(r)); err != nil {
 		s.writeStrategyModuleError(w, err)
 		return
 	}
@@ -686,7 +1012,7 @@ func (s *httpServer) assignStrategyModuleTag(w http.ResponseWriter, r *http.Requ
 	if payload.Refresh != nil {
 		refresh = *payload.Refresh
 	}
-	previous, err := s.manager.AssignStrategyTag(r.Context(), name, tag, hash, refresh)
+	previous, err := s.manager.AssignStrategyTag(r.Context(), name, tag, hash, refresh, runtime.StrategyChange{Actor: payload.Actor, Reason: payload.Reason, RequestID: telemetry.RequestIDFromContext(r.Context())})
 	if err != nil {
 		s.writeStrategyModuleError(w, err)
 		return
@@ -715,7 +1041,7 @@ func (s *httpServer) deleteStrategyModuleTag(w http.ResponseWriter, r *http.Requ
 		}
 		allowOrphan = val
 	}
-	hash, err := s.manager.DeleteStrategyTag(name, tag, allowOrphan)
+	hash, err := s.manager.DeleteStrategyTag(name, tag, allowOrphan, strategyChangeFromQuery(r))
 	if err != nil {
 		s.writeStrategyModuleError(w, err)
 		return
@@ -729,6 +1055,60 @@ func (s *httpServer) deleteStrategyModuleTag(w http.ResponseWriter, r *http.Requ
 	})
 }
 
+func (s *httpServer) getStrategyModuleHistory(w http.ResponseWriter, r *http.Request, name string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "strategy manager unavailable")
+		return
+	}
+	limit := 0
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+	entries, err := s.manager.StrategyHistory(r.Context(), name, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	history := make([]map[string]any, 0, len(entries))
+	for _, entry := range entries {
+		history = append(history, map[string]any{
+			"id":           entry.ID,
+			"action":       entry.Action,
+			"hash":         entry.Hash,
+			"tag":          entry.Tag,
+			"previousHash": entry.PreviousHash,
+			"actor":        entry.Actor,
+			"reason":       entry.Reason,
+			"metadata":     entry.Metadata,
+			"recordedAt":   entry.RecordedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"strategy": strings.ToLower(name),
+		"history":  history,
+		"count":    len(history),
+	})
+}
+
+// strategyChangeFromQuery reads change attribution for requests without a body.
+func strategyChangeFromQuery(r *http.Request) runtime.StrategyChange {
+	query := r.URL.Query()
+	return runtime.StrategyChange{
+		Actor:     strings.TrimSpace(query.Get("actor")),
+		Reason:    strings.TrimSpace(query.Get("reason")),
+		RequestID: telemetry.RequestIDFromContext(r.Context()),
+	}
+}
+
 func (s *httpServer) getStrategyModuleSource(w http.ResponseWriter, _ *http.Request, name string) {
 	if s.manager == nil {
 		writeError(w, http.StatusServiceUnavailable, "strategy manager unavailable")
@@ -744,6 +1124,14 @@ func (s *httpServer) getStrategyModuleSource(w http.ResponseWriter, _ *http.Requ
 	_, _ = w.Write(source)
 }
 
+func (s *httpServer) strategyAutoRefreshStatus(w http.ResponseWriter, _ *http.Request) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "strategy manager unavailable")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.manager.AutoRefreshStatus())
+}
+
 func (s *httpServer) refreshStrategies(w http.ResponseWriter, r *http.Request) {
 	if s.manager == nil {
 		writeError(w, http.StatusServiceUnavailable, "strategy manager unavailable")
@@ -785,6 +1173,22 @@ func (s *httpServer) refreshStrategies(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// strategyUploadPayload describes a stored upload; lint warnings are returned as diagnostics.
+func strategyUploadPayload(directory string, res js.ModuleResolution) map[string]any {
+	payload := map[string]any{
+		"status":            "pending_refresh",
+		"strategyDirectory": directory,
+		"module":            moduleResolutionPayload(res),
+	}
+	if len(res.Warnings) > 0 {
+		payload["diagnostics"] = res.Warnings
+	}
+	if res.Tests != nil {
+		payload["tests"] = res.Tests
+	}
+	return payload
+}
+
 func moduleResolutionPayload(res js.ModuleResolution) map[string]any {
 	if res.Name == "" && res.Hash == "" {
 		return nil
@@ -840,9 +1244,9 @@ func (s *httpServer) createProvider(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "name required")
 		return
 	}
-	spec, enabled, err := buildProviderSpecFromPayload(payload)
+	spec, enabled, err := buildProviderSpecFromPayload(payload, s.providers.Registry())
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeProviderPayloadError(w, err)
 		return
 	}
 	detail, err := s.providers.Create(r.Context(), spec, false)
@@ -925,9 +1329,9 @@ func (s *httpServer) handleProviderResource(w http.ResponseWriter, r *http.Reque
 			writeError(w, http.StatusBadRequest, "provider name mismatch")
 			return
 		}
-		spec, enabled, err := buildProviderSpecFromPayload(payload)
+		spec, enabled, err := buildProviderSpecFromPayload(payload, s.providers.Registry())
 		if err != nil {
-			writeError(w, http.StatusBadRequest, err.Error())
+			writeProviderPayloadError(w, err)
 			return
 		}
 		detail, err := s.providers.Update(r.Context(), spec, enabled)
@@ -946,6 +1350,19 @@ func (s *httpServer) handleProviderResource(w http.ResponseWriter, r *http.Reque
 			writeError(w, http.StatusConflict, fmt.Sprintf("provider %s is in use by instances: %s", name, strings.Join(dependents, ", ")))
 			return
 		}
+		drain, err := parseBoolQuery(r, "drain")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if s.approvals.Guards(config.ApprovalActionProviderDelete) {
+			s.requestProviderRemoval(w, r, name, drain)
+			return
+		}
+		if drain {
+			s.drainProvider(w, r, name)
+			return
+		}
 		if err := s.providers.Remove(name); err != nil {
 			s.writeProviderError(w, err)
 			return
@@ -956,6 +1373,64 @@ func (s *httpServer) handleProviderResource(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+// drainProvider blocks new orders, settles open orders per the requested mode, then removes the provider.
+func parseDrainOptions(r *http.Request) (provider.DrainOptions, error) {
+	query := r.URL.Query()
+	mode, err := provider.ParseDrainMode(query.Get("mode"))
+	if err != nil {
+		var empty provider.DrainOptions
+		return empty, err
+	}
+	timeout := provider.DefaultDrainTimeout
+	if raw := strings.TrimSpace(query.Get("timeout")); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			var empty provider.DrainOptions
+			return empty, fmt.Errorf("timeout must be a positive duration")
+		}
+		timeout = parsed
+	}
+	return provider.DrainOptions{Mode: mode, Timeout: timeout}, nil
+}
+
+func (s *httpServer) drainProvider(w http.ResponseWriter, r *http.Request, name string) {
+	opts, err := parseDrainOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	report, err := s.providers.DrainAndRemove(r.Context(), name, opts)
+	if err != nil {
+		if errors.Is(err, provider.ErrProviderDrainTimeout) {
+			writeJSON(w, http.StatusConflict, map[string]any{
+				"status": "drain_timeout",
+				"error":  redact.Error(err),
+				"drain":  report,
+			})
+			return
+		}
+		s.writeProviderError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status": "removed",
+		"name":   name,
+		"drain":  report,
+	})
+}
+
+func parseBoolQuery(r *http.Request, key string) (bool, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean", key)
+	}
+	return value, nil
+}
+
 func (s *httpServer) handleProviderAction(w http.ResponseWriter, r *http.Request, name, action string) {
 	switch action {
 	case "start":
@@ -996,11 +1471,54 @@ func (s *httpServer) handleProviderAction(w http.ResponseWriter, r *http.Request
 			return
 		}
 		s.handleProviderBalances(w, r, name)
+	case providerCircuitSuffix:
+		s.handleProviderOrderCircuit(w, r, name)
+	case providerQueueSuffix:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		if s.providers == nil {
+			writeError(w, http.StatusServiceUnavailable, "provider manager unavailable")
+			return
+		}
+		status, err := s.providers.OrderQueue(name)
+		if err != nil {
+			s.writeProviderError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
 	default:
 		writeError(w, http.StatusNotFound, "unsupported action")
 	}
 }
 
+// handleProviderOrderCircuit reports the order circuit of a provider; DELETE closes it.
+func (s *httpServer) handleProviderOrderCircuit(w http.ResponseWriter, r *http.Request, name string) {
+	if s.providers == nil {
+		writeError(w, http.StatusServiceUnavailable, "provider manager unavailable")
+		return
+	}
+	var (
+		status provider.OrderCircuitStatus
+		err    error
+	)
+	switch r.Method {
+	case http.MethodGet:
+		status, err = s.providers.OrderCircuit(name)
+	case http.MethodDelete:
+		status, err = s.providers.ResetOrderCircuit(name)
+	default:
+		methodNotAllowed(w, http.MethodDelete, http.MethodGet)
+		return
+	}
+	if err != nil {
+		s.writeProviderError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
 func (s *httpServer) listAdapters(w http.ResponseWriter, _ *http.Request) {
 	if s.providers == nil {
 		writeJSON(w, http.StatusOK, map[string]any{"adapters": []provider.AdapterMetadata{}})
@@ -1016,7 +1534,12 @@ func (s *httpServer) listAdapters(w http.ResponseWriter, _ *http.Request) {
 }
 
 func (s *httpServer) getAdapter(w http.ResponseWriter, r *http.Request) {
-	identifier := strings.Trim(strings.TrimPrefix(r.URL.Path, adapterDetailPrefix), "/")
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, adapterDetailPrefix), "/")
+	identifier, action, _ := strings.Cut(rest, "/")
+	if action != "" && action != adapterConfigSchemaSuffix {
+		writeError(w, http.StatusNotFound, "unsupported action")
+		return
+	}
 	if identifier == "" {
 		writeError(w, http.StatusNotFound, "adapter identifier required")
 		return
@@ -1035,13 +1558,25 @@ func (s *httpServer) getAdapter(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "adapter not found")
 		return
 	}
+	if action == adapterConfigSchemaSuffix {
+		writeJSON(w, http.StatusOK, provider.BuildConfigSchema(meta))
+		return
+	}
 	writeJSON(w, http.StatusOK, meta)
 }
 
-func (s *httpServer) listInstances(w http.ResponseWriter, _ *http.Request) {
+func (s *httpServer) listInstances(w http.ResponseWriter, r *http.Request) {
+	selectors, err := parseLabelSelectors(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	instances := s.manager.Instances()
 	responses := make([]instanceSummaryResponse, 0, len(instances))
 	for _, summary := range instances {
+		if !runtime.MatchesAll(selectors, summary.Labels) {
+			continue
+		}
 		responses = append(responses, instanceSummaryResponse{
 			InstanceSummary: summary,
 			Links:           s.buildInstanceLinksFromSummary(summary),
@@ -1050,6 +1585,40 @@ func (s *httpServer) listInstances(w http.ResponseWriter, _ *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]any{"instances": responses})
 }
 
+// listInstanceGroups groups instances by the label named in "by", optionally narrowed by
+// label selectors, and reports instance counts and combined exposure per label value.
+func (s *httpServer) listInstanceGroups(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	label := strings.TrimSpace(values.Get("by"))
+	if label == "" {
+		writeError(w, http.StatusBadRequest, "by query parameter required")
+		return
+	}
+	selectors, err := parseLabelSelectors(values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"groups": s.manager.InstanceGroups(label, selectors)})
+}
+
+// parseLabelSelectors reads repeated label=key:value parameters; all selectors must match.
+func parseLabelSelectors(values url.Values) ([]runtime.LabelSelector, error) {
+	raw := values["label"]
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	selectors := make([]runtime.LabelSelector, 0, len(raw))
+	for _, entry := range raw {
+		selector, err := runtime.ParseLabelSelector(entry)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
 func (s *httpServer) createInstance(w http.ResponseWriter, r *http.Request) {
 	limitRequestBody(w, r)
 	spec, err := decodeInstanceSpec(r)
@@ -1057,14 +1626,28 @@ func (s *httpServer) createInstance(w http.ResponseWriter, r *http.Request) {
 		writeDecodeError(w, err)
 		return
 	}
-	if _, err := s.manager.Create(spec); err != nil {
-		s.writeManagerError(w, err)
+	create := func(context.Context) (any, error) {
+		if _, err := s.manager.Create(spec); err != nil {
+			return nil, err
+		}
+		snapshot, _ := s.manager.Instance(spec.ID)
+		return instanceSnapshotResponse{
+			InstanceSnapshot: snapshot,
+			Links:            s.buildInstanceLinksFromSnapshot(snapshot),
+		}, nil
+	}
+	if action, reasons := s.instanceSpecApproval(spec); len(reasons) > 0 {
+		if _, exists := s.manager.Instance(spec.ID); exists {
+			s.writeManagerError(w, runtime.ErrInstanceExists)
+			return
+		}
+		s.requestApproval(w, r, action, spec.ID, "create instance "+spec.ID+": "+strings.Join(reasons, ", "), create)
 		return
 	}
-	snapshot, _ := s.manager.Instance(spec.ID)
-	response := instanceSnapshotResponse{
-		InstanceSnapshot: snapshot,
-		Links:            s.buildInstanceLinksFromSnapshot(snapshot),
+	response, err := create(r.Context())
+	if err != nil {
+		s.writeManagerError(w, err)
+		return
 	}
 	writeJSON(w, http.StatusCreated, response)
 }
@@ -1081,28 +1664,539 @@ func (s *httpServer) updateRiskLimits(w http.ResponseWriter, r *http.Request) {
 		writeDecodeError(w, err)
 		return
 	}
+	if s.approvals.Guards(config.ApprovalActionRiskLimits) {
+		if raised := s.manager.RiskConfigIncreases(cfg, s.approvals.RiskLimitIncreasePercent()); len(raised) > 0 {
+			s.requestApproval(w, r, config.ApprovalActionRiskLimits, "", "raise risk limits: "+strings.Join(raised, ", "), s.applyRiskLimitsApproved(cfg))
+			return
+		}
+	}
 	limits := s.manager.ApplyRiskConfig(cfg)
 	writeJSON(w, http.StatusOK, map[string]any{"status": "updated", "limits": riskConfigFromLimits(limits)})
 }
 
-func (s *httpServer) handleContextBackupExport(w http.ResponseWriter, _ *http.Request) {
-	writeJSON(w, http.StatusOK, s.buildContextBackup())
-}
-
-func (s *httpServer) handleContextBackupRestore(w http.ResponseWriter, r *http.Request) {
+// patchRiskLimits merges a partial risk config onto the current limits. With preview=true it only
+// validates the result and reports the effective limits, the running instances they would put in
+// breach and a token; passing that token as confirm applies the change unless the limits moved
+// in the meantime.
+func (s *httpServer) patchRiskLimits(w http.ResponseWriter, r *http.Request) {
 	limitRequestBody(w, r)
-	defer func() { _ = r.Body.Close() }()
-	var payload contextBackup
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&payload); err != nil {
+	query := r.URL.Query()
+	preview := false
+	if raw := strings.TrimSpace(query.Get("preview")); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "preview must be a boolean")
+			return
+		}
+		preview = parsed
+	}
+	current := riskConfigFromLimits(s.manager.RiskLimits())
+	cfg, err := decodeRiskConfigPatch(r, current)
+	if err != nil {
 		writeDecodeError(w, err)
 		return
 	}
-	if err := s.applyContextBackup(r.Context(), payload); err != nil {
+	limits, breaches := s.manager.PreviewRiskConfig(cfg)
+	effective := riskConfigFromLimits(limits)
+	token := riskPreviewToken(current, effective)
+	if preview {
+		writeJSON(w, http.StatusOK, map[string]any{"status": "preview", "limits": effective, "breaches": breaches, "token": token})
+		return
+	}
+	if confirm := strings.TrimSpace(query.Get("confirm")); confirm != "" && confirm != token {
+		writeError(w, http.StatusConflict, "risk limits changed since the preview; preview again")
+		return
+	}
+	if s.approvals.Guards(config.ApprovalActionRiskLimits) {
+		if raised := s.manager.RiskConfigIncreases(cfg, s.approvals.RiskLimitIncreasePercent()); len(raised) > 0 {
+			s.requestApproval(w, r, config.ApprovalActionRiskLimits, "", "raise risk limits: "+strings.Join(raised, ", "), s.applyRiskLimitsApproved(cfg))
+			return
+		}
+	}
+	applied := s.manager.ApplyRiskConfig(cfg)
+	writeJSON(w, http.StatusOK, map[string]any{"status": "updated", "limits": riskConfigFromLimits(applied), "breaches": breaches})
+}
+
+func (s *httpServer) getRiskHeartbeat(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.manager.DeadMansSwitchStatus())
+}
+
+func (s *httpServer) recordRiskHeartbeat(w http.ResponseWriter, r *http.Request) {
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload riskHeartbeatPayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	status, err := s.manager.RecordControllerHeartbeat(payload.Resume)
+	if err != nil {
+		if errors.Is(err, runtime.ErrDeadMansSwitchDisabled) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *httpServer) getRiskStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.manager.RiskSnapshot())
+}
+
+func (s *httpServer) getRiskConcentration(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.manager.RiskConcentration())
+}
+
+func (s *httpServer) getRiskSymbolPolicy(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.manager.SymbolPolicy())
+}
+
+func (s *httpServer) setKillSwitch(w http.ResponseWriter, r *http.Request) {
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload killSwitchPayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if payload.Engaged {
+		writeJSON(w, http.StatusOK, s.manager.HaltTrading(payload.Reason))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.manager.ResumeTrading())
+}
+
+func (s *httpServer) checkOrderRisk(w http.ResponseWriter, r *http.Request) {
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload riskCheckPayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	result, err := s.manager.SimulateOrder(runtime.OrderCheck{
+		Instance:  payload.Instance,
+		Provider:  payload.Provider,
+		Symbol:    payload.Symbol,
+		Side:      payload.Side,
+		OrderType: payload.OrderType,
+		Quantity:  payload.Quantity,
+		Price:     payload.Price,
+		TIF:       payload.TIF,
+	})
+	if err != nil {
+		if errors.Is(err, runtime.ErrInstanceNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *httpServer) listRiskProfiles(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"profiles":    s.manager.RiskProfiles(),
+		"assignments": s.manager.RiskProfileAssignments(),
+	})
+}
+
+func (s *httpServer) createRiskProfile(w http.ResponseWriter, r *http.Request) {
+	limitRequestBody(w, r)
+	payload, err := decodeRiskProfile(r)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if _, exists := s.manager.RiskProfile(payload.Name); exists {
+		writeError(w, http.StatusConflict, fmt.Sprintf("risk profile %s already exists", payload.Name))
+		return
+	}
+	s.saveRiskProfile(w, r, payload, http.StatusCreated)
+}
+
+// saveRiskProfile stores the profile. New limits that raise the shared limits, when the profile is
+// applied globally, or those of instances pinned to it wait for approval.
+func (s *httpServer) saveRiskProfile(w http.ResponseWriter, r *http.Request, payload riskProfilePayload, status int) {
+	profile := runtime.RiskProfile{
+		Name:        payload.Name,
+		Description: payload.Description,
+		Limits:      payload.Limits,
+		BuiltIn:     false,
+		UpdatedAt:   nil,
+	}
+	if s.approvals.Guards(config.ApprovalActionRiskLimits) {
+		if raised := s.manager.RiskProfileSaveIncreases(profile.Name, profile.Limits, s.approvals.RiskLimitIncreasePercent()); len(raised) > 0 {
+			summary := fmt.Sprintf("update risk profile %s: %s", profile.Name, strings.Join(raised, ", "))
+			s.requestApproval(w, r, config.ApprovalActionRiskLimits, profile.Name, summary, func(ctx context.Context) (any, error) {
+				return s.manager.SaveRiskProfile(ctx, profile)
+			})
+			return
+		}
+	}
+	saved, err := s.manager.SaveRiskProfile(r.Context(), profile)
+	if err != nil {
+		s.writeRiskProfileError(w, err)
+		return
+	}
+	writeJSON(w, status, saved)
+}
+
+func (s *httpServer) handleRiskProfile(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, riskProfilePrefix), "/")
+	name, action, hasAction := strings.Cut(rest, "/")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		writeError(w, http.StatusNotFound, "risk profile name required")
+		return
+	}
+	if hasAction {
+		if strings.TrimSpace(action) != riskProfileApplySuffix {
+			writeError(w, http.StatusNotFound, "unsupported action")
+			return
+		}
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.applyRiskProfile(w, r, name)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		profile, ok := s.manager.RiskProfile(name)
+		if !ok {
+			writeError(w, http.StatusNotFound, "risk profile not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, profile)
+	case http.MethodPut:
+		limitRequestBody(w, r)
+		payload, err := decodeRiskProfile(r)
+		if err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		if payload.Name != "" && !strings.EqualFold(payload.Name, name) {
+			writeError(w, http.StatusBadRequest, "risk profile name mismatch")
+			return
+		}
+		payload.Name = name
+		s.saveRiskProfile(w, r, payload, http.StatusOK)
+	case http.MethodDelete:
+		if err := s.manager.DeleteRiskProfile(r.Context(), name); err != nil {
+			s.writeRiskProfileError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "removed", "name": name})
+	default:
+		methodNotAllowed(w, http.MethodDelete, http.MethodGet, http.MethodPut)
+	}
+}
+
+// applyRiskProfile applies the profile to the listed instances, or to the shared limits when the
+// request names no instances.
+func (s *httpServer) applyRiskProfile(w http.ResponseWriter, r *http.Request, name string) {
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload riskProfileApplyPayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	instances := make([]string, 0, len(payload.Instances))
+	for _, id := range payload.Instances {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		if _, ok := s.manager.Instance(id); !ok {
+			s.writeRiskProfileError(w, fmt.Errorf("%w: %s", runtime.ErrInstanceNotFound, id))
+			return
+		}
+		instances = append(instances, id)
+	}
+	apply := s.applyRiskProfileAction(name, instances)
+	if s.approvals.Guards(config.ApprovalActionRiskLimits) {
+		raised, err := s.manager.RiskProfileIncreases(name, instances, s.approvals.RiskLimitIncreasePercent())
+		if err != nil {
+			s.writeRiskProfileError(w, err)
+			return
+		}
+		if len(raised) > 0 {
+			s.requestApproval(w, r, config.ApprovalActionRiskLimits, name, "apply risk profile "+name+": "+strings.Join(raised, ", "), apply)
+			return
+		}
+	}
+	result, err := apply(r.Context())
+	if err != nil {
+		s.writeRiskProfileError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// applyRiskProfileAction applies the profile to the instances, or to the shared limits when there
+// are none.
+func (s *httpServer) applyRiskProfileAction(name string, instances []string) approvals.Executor {
+	return func(context.Context) (any, error) {
+		if len(instances) == 0 {
+			limits, err := s.manager.ApplyRiskProfile(name)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{
+				"status":  "applied",
+				"profile": name,
+				"scope":   "global",
+				"limits":  riskConfigFromLimits(limits),
+			}, nil
+		}
+		for _, id := range instances {
+			if err := s.manager.AssignInstanceRiskProfile(id, name); err != nil {
+				return nil, err
+			}
+		}
+		return map[string]any{
+			"status":    "applied",
+			"profile":   name,
+			"scope":     "instances",
+			"instances": instances,
+		}, nil
+	}
+}
+
+func (s *httpServer) handleInstanceRiskProfile(w http.ResponseWriter, r *http.Request, id string) {
+	switch r.Method {
+	case http.MethodPut:
+		limitRequestBody(w, r)
+		defer func() { _ = r.Body.Close() }()
+		var payload instanceRiskProfilePayload
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&payload); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		name := strings.TrimSpace(payload.Profile)
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "profile required")
+			return
+		}
+		s.assignInstanceRiskProfile(w, r, id, name, map[string]string{"status": "assigned", "id": id, "profile": strings.ToLower(name)})
+	case http.MethodDelete:
+		s.assignInstanceRiskProfile(w, r, id, "", map[string]string{"status": "cleared", "id": id})
+	default:
+		methodNotAllowed(w, http.MethodDelete, http.MethodPut)
+	}
+}
+
+// assignInstanceRiskProfile pins the instance to the profile, or back to the shared limits when name
+// is empty. A change that raises the instance's limits waits for approval.
+func (s *httpServer) assignInstanceRiskProfile(w http.ResponseWriter, r *http.Request, id, name string, result map[string]string) {
+	assign := func(context.Context) (any, error) {
+		if err := s.manager.AssignInstanceRiskProfile(id, name); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if s.approvals.Guards(config.ApprovalActionRiskLimits) {
+		if _, ok := s.manager.Instance(id); !ok {
+			s.writeRiskProfileError(w, runtime.ErrInstanceNotFound)
+			return
+		}
+		raised, err := s.manager.RiskProfileIncreases(name, []string{id}, s.approvals.RiskLimitIncreasePercent())
+		if err != nil {
+			s.writeRiskProfileError(w, err)
+			return
+		}
+		if len(raised) > 0 {
+			summary := "clear the risk profile of " + id + ": " + strings.Join(raised, ", ")
+			if name != "" {
+				summary = "assign risk profile " + strings.ToLower(name) + " to " + id + ": " + strings.Join(raised, ", ")
+			}
+			s.requestApproval(w, r, config.ApprovalActionRiskLimits, id, summary, assign)
+			return
+		}
+	}
+	if _, err := assign(r.Context()); err != nil {
+		s.writeRiskProfileError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleInstanceTrading switches a running instance between live trading and dry-run mode.
+func (s *httpServer) handleInstanceTrading(w http.ResponseWriter, r *http.Request, id string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload instanceTradingPayload
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if payload.Enabled == nil {
+		writeError(w, http.StatusBadRequest, "enabled required")
+		return
+	}
+	change := runtime.StrategyChange{Actor: payload.Actor, Reason: payload.Reason, RequestID: telemetry.RequestIDFromContext(r.Context())}
+	if *payload.Enabled && s.approvals.Guards(config.ApprovalActionLiveTrading) {
+		if _, ok := s.manager.Instance(id); !ok {
+			s.writeManagerError(w, runtime.ErrInstanceNotFound)
+			return
+		}
+		s.requestApproval(w, r, config.ApprovalActionLiveTrading, id, "enable live trading on instance "+id, func(context.Context) (any, error) {
+			if err := s.manager.SetInstanceTrading(id, true, change); err != nil {
+				return nil, err
+			}
+			return map[string]any{"status": "ok", "id": id, "tradingEnabled": true, "dryRun": false}, nil
+		})
+		return
+	}
+	if err := s.manager.SetInstanceTrading(id, *payload.Enabled, change); err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok", "id": id, "tradingEnabled": *payload.Enabled, "dryRun": !*payload.Enabled})
+}
+
+// handleInstanceShadow starts, reports on and stops the shadow run of a candidate revision next to
+// a running instance.
+func (s *httpServer) handleInstanceShadow(w http.ResponseWriter, r *http.Request, id string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		limitRequestBody(w, r)
+		defer func() { _ = r.Body.Close() }()
+		var payload instanceShadowPayload
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&payload); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		opts := runtime.ShadowOptions{Candidate: payload.Candidate, MatchWindow: 0, PriceToleranceBps: payload.PriceToleranceBps}
+		if raw := strings.TrimSpace(payload.MatchWindow); raw != "" {
+			window, err := time.ParseDuration(raw)
+			if err != nil || window <= 0 {
+				writeError(w, http.StatusBadRequest, "matchWindow must be a positive duration")
+				return
+			}
+			opts.MatchWindow = window
+		}
+		if err := s.manager.StartShadow(r.Context(), id, opts); err != nil {
+			s.writeManagerError(w, err)
+			return
+		}
+		report, err := s.manager.ShadowReport(id)
+		if err != nil {
+			s.writeManagerError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, report)
+	case http.MethodGet:
+		report, err := s.manager.ShadowReport(id)
+		if err != nil {
+			s.writeManagerError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	case http.MethodDelete:
+		report, err := s.manager.StopShadow(id)
+		if err != nil {
+			s.writeManagerError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, report)
+	default:
+		methodNotAllowed(w, http.MethodDelete, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *httpServer) handleContextBackupExport(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.buildContextBackup())
+}
+
+func (s *httpServer) handleContextBackupRestore(w http.ResponseWriter, r *http.Request) {
+	limitRequestBody(w, r)
+	defer func() { _ = r.Body.Close() }()
+	var payload contextBackup
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&payload); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	restore := func(ctx context.Context) (any, error) {
+		if err := s.applyContextBackup(ctx, payload); err != nil {
+			return nil, err
+		}
+		return map[string]any{"status": "restored"}, nil
+	}
+	if action, reasons := s.contextBackupApproval(payload); len(reasons) > 0 {
+		s.requestApproval(w, r, action, "", "restore context backup: "+strings.Join(reasons, ", "), restore)
+		return
+	}
+	result, err := restore(r.Context())
+	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]any{"status": "restored"})
+	writeJSON(w, http.StatusOK, result)
+}
+
+// contextBackupApproval reports the guarded changes restoring payload would make and the approval
+// action they fall under; no reasons means the restore may run straight away.
+func (s *httpServer) contextBackupApproval(payload contextBackup) (string, []string) {
+	if s.manager == nil {
+		return "", nil
+	}
+	var action string
+	var reasons []string
+	add := func(kind string, more []string) {
+		if len(more) == 0 {
+			return
+		}
+		if action == "" {
+			action = kind
+		}
+		reasons = append(reasons, more...)
+	}
+	for _, spec := range payload.Lambdas {
+		spec.ID = strings.TrimSpace(spec.ID)
+		if spec.ID == "" || s.isBaselineLambda(spec.ID) {
+			continue
+		}
+		add(s.instanceSpecApproval(spec))
+	}
+	if restoresRiskLimits(payload.Risk) && s.approvals.Guards(config.ApprovalActionRiskLimits) {
+		add(config.ApprovalActionRiskLimits, s.manager.RiskConfigIncreases(payload.Risk, s.approvals.RiskLimitIncreasePercent()))
+	}
+	return action, reasons
+}
+
+// restoresRiskLimits reports whether a backup carries risk limits to apply.
+func restoresRiskLimits(cfg config.RiskConfig) bool {
+	return cfg.MaxPositionSize != "" || cfg.MaxNotionalValue != "" || cfg.NotionalCurrency != ""
 }
 
 func (s *httpServer) buildContextBackup() contextBackup {
@@ -1191,14 +2285,28 @@ func (s *httpServer) handleInstanceResource(w http.ResponseWriter, r *http.Reque
 			return
 		}
 		spec.ID = id
-		if err := s.manager.Update(r.Context(), spec); err != nil {
-			s.writeManagerError(w, err)
+		update := func(ctx context.Context) (any, error) {
+			if err := s.manager.Update(ctx, spec); err != nil {
+				return nil, err
+			}
+			snapshot, _ := s.manager.Instance(id)
+			return instanceSnapshotResponse{
+				InstanceSnapshot: snapshot,
+				Links:            s.buildInstanceLinksFromSnapshot(snapshot),
+			}, nil
+		}
+		if action, reasons := s.instanceSpecApproval(spec); len(reasons) > 0 {
+			if _, exists := s.manager.Instance(id); !exists {
+				s.writeManagerError(w, runtime.ErrInstanceNotFound)
+				return
+			}
+			s.requestApproval(w, r, action, id, "update instance "+id+": "+strings.Join(reasons, ", "), update)
 			return
 		}
-		snapshot, _ := s.manager.Instance(id)
-		response := instanceSnapshotResponse{
-			InstanceSnapshot: snapshot,
-			Links:            s.buildInstanceLinksFromSnapshot(snapshot),
+		response, err := update(r.Context())
+		if err != nil {
+			s.writeManagerError(w, err)
+			return
 		}
 		writeJSON(w, http.StatusOK, response)
 	case http.MethodDelete:
@@ -1254,11 +2362,244 @@ func (s *httpServer) handleInstanceAction(w http.ResponseWriter, r *http.Request
 			return
 		}
 		s.handleInstanceExecutions(w, r, id)
+	case instanceAuditSuffix:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.handleInstanceAudit(w, r, id)
+	case instanceAlgosSuffix:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.handleInstanceAlgos(w, id)
+	case instanceFaultsSuffix:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.handleInstanceFaults(w, id)
+	case instanceLogsSuffix:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.handleInstanceLogs(w, r, id)
+	case instanceTradingSuffix:
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.handleInstanceTrading(w, r, id)
+	case instanceQueueSuffix:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.handleInstanceThrottleQueue(w, id)
+	case instanceCallbacksSuffix:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.handleInstanceOrderCallbacks(w, id)
+	case instanceSubsSuffix:
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.handleInstanceSubscriptions(w, id)
+	case instanceRiskSuffix:
+		s.handleInstanceRiskProfile(w, r, id)
+	case instanceShadowSuffix:
+		s.handleInstanceShadow(w, r, id)
 	default:
+		if algoID, ok := strings.CutPrefix(action, instanceAlgosSuffix+"/"); ok {
+			if r.Method != http.MethodDelete {
+				methodNotAllowed(w, http.MethodDelete)
+				return
+			}
+			s.handleInstanceAlgoCancel(w, id, strings.TrimSpace(algoID))
+			return
+		}
+		if orderID, ok := strings.CutPrefix(action, instanceQueueSuffix+"/"); ok {
+			if r.Method != http.MethodDelete {
+				methodNotAllowed(w, http.MethodDelete)
+				return
+			}
+			s.handleInstanceQueuedOrderCancel(w, id, strings.TrimSpace(orderID))
+			return
+		}
 		writeError(w, http.StatusNotFound, "unsupported action")
 	}
 }
 
+// handleInstanceAudit streams the instance audit trail as a JSON Lines download.
+func (s *httpServer) handleInstanceAudit(w http.ResponseWriter, r *http.Request, id string) {
+	if s.orderStore == nil {
+		writeError(w, http.StatusServiceUnavailable, "order store unavailable")
+		return
+	}
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	snapshot, ok := s.manager.Instance(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, "strategy instance not found")
+		return
+	}
+	values := r.URL.Query()
+	since, until, err := parseTimeRangeParams(values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if until.IsZero() {
+		until = time.Now().UTC()
+	}
+	if since.IsZero() {
+		since = until.Add(-defaultAuditWindow)
+	}
+	limit := audit.DefaultLimit
+	if raw := strings.TrimSpace(values.Get("limit")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = parsed
+	}
+	providerSymbols := make(map[string][]string, len(snapshot.ProviderSymbols))
+	for provider, symbols := range snapshot.ProviderSymbols {
+		providerSymbols[provider] = append([]string(nil), symbols.Symbols...)
+	}
+	scope := audit.Scope{
+		Instance:        snapshot.ID,
+		ProviderSymbols: providerSymbols,
+		Since:           since,
+		Until:           until,
+		EventTypes:      values["eventType"],
+		Limit:           limit,
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", snapshot.ID+"-audit.jsonl"))
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	written := 0
+	err = s.audit.Export(r.Context(), scope, func(entry audit.Entry) error {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("write audit entry: %w", err)
+		}
+		written++
+		if flusher != nil && written%auditFlushEvery == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Headers are already sent, so the failure is reported as the final line of the export.
+		_ = encoder.Encode(map[string]string{"kind": "error", "error": redact.Error(err)})
+	}
+}
+
+func (s *httpServer) handleInstanceAlgos(w http.ResponseWriter, id string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	algos, err := s.manager.InstanceAlgoOrders(id)
+	if err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"algos": algos,
+		"count": len(algos),
+	})
+}
+
+func (s *httpServer) handleInstanceFaults(w http.ResponseWriter, id string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	report, err := s.manager.InstanceHandlerFaults(id)
+	if err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *httpServer) handleInstanceSubscriptions(w http.ResponseWriter, id string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	subscriptions, err := s.manager.InstanceSubscriptions(id)
+	if err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, subscriptions)
+}
+
+func (s *httpServer) handleInstanceAlgoCancel(w http.ResponseWriter, id, algoID string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	progress, err := s.manager.CancelInstanceAlgoOrder(id, algoID)
+	if err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, progress)
+}
+
+func (s *httpServer) handleInstanceThrottleQueue(w http.ResponseWriter, id string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	status, err := s.manager.InstanceThrottleQueue(id)
+	if err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *httpServer) handleInstanceOrderCallbacks(w http.ResponseWriter, id string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	stats, err := s.manager.InstanceOrderCallbacks(id)
+	if err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func (s *httpServer) handleInstanceQueuedOrderCancel(w http.ResponseWriter, id, clientOrderID string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	order, err := s.manager.CancelInstanceQueuedOrder(id, clientOrderID)
+	if err != nil {
+		s.writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, order)
+}
+
 func (s *httpServer) handleInstanceOrders(w http.ResponseWriter, r *http.Request, id string) {
 	if s.orderStore == nil {
 		writeError(w, http.StatusServiceUnavailable, "order store unavailable")
@@ -1275,10 +2616,18 @@ func (s *httpServer) handleInstanceOrders(w http.ResponseWriter, r *http.Request
 		states[i] = strings.TrimSpace(state)
 	}
 	provider := strings.TrimSpace(values.Get("provider"))
+	since, until, err := parseTimeRangeParams(values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	records, err := s.orderStore.ListOrders(r.Context(), orderstore.OrderQuery{
 		StrategyInstance: id,
 		Provider:         provider,
 		States:           states,
+		Since:            unixOrZero(since),
+		Until:            unixOrZero(until),
+		Tag:              strings.TrimSpace(values.Get("tag")),
 		Limit:            limit,
 	})
 	if err != nil {
@@ -1305,10 +2654,18 @@ func (s *httpServer) handleInstanceExecutions(w http.ResponseWriter, r *http.Req
 	}
 	provider := strings.TrimSpace(values.Get("provider"))
 	orderID := strings.TrimSpace(values.Get("orderId"))
+	since, until, err := parseTimeRangeParams(values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	records, err := s.orderStore.ListExecutions(r.Context(), orderstore.ExecutionQuery{
 		StrategyInstance: id,
 		Provider:         provider,
 		OrderID:          orderID,
+		Since:            unixOrZero(since),
+		Until:            unixOrZero(until),
+		Tag:              strings.TrimSpace(values.Get("tag")),
 		Limit:            limit,
 	})
 	if err != nil {
@@ -1365,6 +2722,45 @@ func parseLimitParam(raw string, fallback int) (int, error) {
 	return value, nil
 }
 
+// parseTimeRangeParams reads the optional since/until query parameters, accepting RFC 3339
+// timestamps or Unix seconds.
+func parseTimeRangeParams(values url.Values) (time.Time, time.Time, error) {
+	since, err := parseTimeParam("since", values.Get("since"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	until, err := parseTimeParam("until", values.Get("until"))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return time.Time{}, time.Time{}, fmt.Errorf("since must be before until")
+	}
+	return since, until, nil
+}
+
+func parseTimeParam(name, raw string) (time.Time, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(trimmed, 10, 64); err == nil && seconds > 0 {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	parsed, err := time.Parse(time.RFC3339, trimmed)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: expected RFC 3339 timestamp or Unix seconds", name)
+	}
+	return parsed.UTC(), nil
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
 func (s *httpServer) writeManagerError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, runtime.ErrInstanceExists):
@@ -1375,6 +2771,31 @@ func (s *httpServer) writeManagerError(w http.ResponseWriter, err error) {
 		writeError(w, http.StatusConflict, err.Error())
 	case errors.Is(err, runtime.ErrInstanceNotFound):
 		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, runtime.ErrAlgoOrderNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, runtime.ErrQueuedOrderNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, runtime.ErrShadowNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, runtime.ErrShadowExists):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, runtime.ErrCapabilityNotAllowed):
+		writeError(w, http.StatusForbidden, err.Error())
+	default:
+		writeError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+func (s *httpServer) writeRiskProfileError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, runtime.ErrRiskProfileNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, runtime.ErrInstanceNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, runtime.ErrRiskProfileBuiltIn):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, runtime.ErrRiskProfileInUse):
+		writeError(w, http.StatusConflict, err.Error())
 	default:
 		writeError(w, http.StatusBadRequest, err.Error())
 	}
@@ -1392,6 +2813,8 @@ func (s *httpServer) writeProviderError(w http.ResponseWriter, err error) {
 		writeError(w, http.StatusConflict, err.Error())
 	case errors.Is(err, provider.ErrProviderNotRunning):
 		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, provider.ErrProviderDraining):
+		writeError(w, http.StatusConflict, err.Error())
 	default:
 		writeError(w, http.StatusBadRequest, err.Error())
 	}
@@ -1446,7 +2869,9 @@ func decodeStrategyModulePayload(r *http.Request) (strategyModulePayload, error)
 	return payload, nil
 }
 
-func buildProviderSpecFromPayload(payload providerPayload) (config.ProviderSpec, bool, error) {
+// buildProviderSpecFromPayload turns a control API payload into a provider spec, validating the
+// adapter config against the adapter's settings schema when a registry is supplied.
+func buildProviderSpecFromPayload(payload providerPayload, registry *provider.Registry) (config.ProviderSpec, bool, error) {
 	enabled := true
 	if payload.Enabled != nil {
 		enabled = *payload.Enabled
@@ -1466,6 +2891,11 @@ func buildProviderSpecFromPayload(payload providerPayload) (config.ProviderSpec,
 		"identifier": identifier,
 	}
 	cleanConfig := sanitizeAdapterConfig(payload.Adapter.Config)
+	if registry != nil {
+		if err := registry.ValidateConfig(identifier, cleanConfig); err != nil {
+			return config.ProviderSpec{}, false, err
+		}
+	}
 	if len(cleanConfig) > 0 {
 		adapterConfig["config"] = cleanConfig
 	}
@@ -1484,6 +2914,21 @@ func buildProviderSpecFromPayload(payload providerPayload) (config.ProviderSpec,
 	return specs[0], enabled, nil
 }
 
+// writeProviderPayloadError answers 400, listing the offending fields when the adapter config
+// failed schema validation.
+func writeProviderPayloadError(w http.ResponseWriter, err error) {
+	var settingsErr *provider.SettingsError
+	if !errors.As(err, &settingsErr) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload := map[string]any{"status": "error", "error": redact.Error(settingsErr), "fields": settingsErr.Fields}
+	if id := w.Header().Get(telemetry.RequestIDHeader); id != "" {
+		payload["requestId"] = id
+	}
+	writeJSON(w, http.StatusBadRequest, payload)
+}
+
 func sanitizeAdapterConfig(input map[string]any) map[string]any {
 	if len(input) == 0 {
 		return nil
@@ -1591,6 +3036,55 @@ func decodeRiskConfig(r *http.Request) (config.RiskConfig, error) {
 	if err := decoder.Decode(&cfg); err != nil {
 		return cfg, fmt.Errorf("decode payload: %w", err)
 	}
+	return normalizeRiskConfig(cfg)
+}
+
+// decodeRiskConfigPatch overlays the fields present in the request body on base. Nested objects
+// are merged field by field; lists replace the current value.
+func decodeRiskConfigPatch(r *http.Request, base config.RiskConfig) (config.RiskConfig, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+	cfg := base
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("decode payload: %w", err)
+	}
+	return normalizeRiskConfig(cfg)
+}
+
+// riskPreviewToken identifies a limits change by the limits it starts from and the ones it
+// produces, so a confirmed change fails when either moved after the preview.
+func riskPreviewToken(current, effective config.RiskConfig) string {
+	hash := sha256.New()
+	for _, cfg := range []config.RiskConfig{current, effective} {
+		raw, _ := json.Marshal(cfg)
+		_, _ = hash.Write(raw)
+		_, _ = hash.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+func decodeRiskProfile(r *http.Request) (riskProfilePayload, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+	var payload riskProfilePayload
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&payload); err != nil {
+		return payload, fmt.Errorf("decode payload: %w", err)
+	}
+	payload.Name = strings.TrimSpace(payload.Name)
+	payload.Description = strings.TrimSpace(payload.Description)
+	limits, err := normalizeRiskConfig(payload.Limits)
+	if err != nil {
+		return payload, fmt.Errorf("limits: %w", err)
+	}
+	payload.Limits = limits
+	return payload, nil
+}
+
+func normalizeRiskConfig(cfg config.RiskConfig) (config.RiskConfig, error) {
 	cfg.MaxPositionSize = strings.TrimSpace(cfg.MaxPositionSize)
 	cfg.MaxNotionalValue = strings.TrimSpace(cfg.MaxNotionalValue)
 	cfg.NotionalCurrency = strings.TrimSpace(cfg.NotionalCurrency)
@@ -1620,6 +3114,11 @@ func decodeRiskConfig(r *http.Request) (config.RiskConfig, error) {
 		}
 		cfg.AllowedOrderTypes = normalized
 	}
+	allowedTIF, err := config.NormalizeTimeInForce(cfg.AllowedTimeInForce)
+	if err != nil {
+		return cfg, fmt.Errorf("allowedTimeInForce: %w", err)
+	}
+	cfg.AllowedTimeInForce = allowedTIF
 	if err := validateRiskConfig(cfg); err != nil {
 		return cfg, err
 	}
@@ -1631,10 +3130,29 @@ func riskConfigFromLimits(limits risk.Limits) config.RiskConfig {
 	for _, ot := range limits.AllowedOrderTypes {
 		allowed = append(allowed, string(ot))
 	}
+	var allowedTIF []string
+	for _, tif := range limits.AllowedTimeInForce {
+		allowedTIF = append(allowedTIF, string(tif))
+	}
 	cooldown := ""
 	if limits.CircuitBreaker.Cooldown > 0 {
 		cooldown = limits.CircuitBreaker.Cooldown.String()
 	}
+	var fxRates map[string]string
+	if len(limits.FXRates) > 0 {
+		fxRates = make(map[string]string, len(limits.FXRates))
+		for currency, rate := range limits.FXRates {
+			fxRates[currency] = rate.String()
+		}
+	}
+	concentration := config.ConcentrationConfig{
+		MaxAssetPercent:      limits.Concentration.MaxAssetPercent,
+		MaxVenuePercent:      limits.Concentration.MaxVenuePercent,
+		MinPortfolioNotional: "",
+	}
+	if limits.Concentration.MinPortfolioNotional.IsPositive() {
+		concentration.MinPortfolioNotional = limits.Concentration.MinPortfolioNotional.String()
+	}
 	return config.RiskConfig{
 		MaxPositionSize:     limits.MaxPositionSize.String(),
 		MaxNotionalValue:    limits.MaxNotionalValue.String(),
@@ -1644,6 +3162,7 @@ func riskConfigFromLimits(limits risk.Limits) config.RiskConfig {
 		MaxConcurrentOrders: limits.MaxConcurrentOrders,
 		PriceBandPercent:    limits.PriceBandPercent,
 		AllowedOrderTypes:   allowed,
+		AllowedTimeInForce:  allowedTIF,
 		KillSwitchEnabled:   limits.KillSwitchEnabled,
 		MaxRiskBreaches:     limits.MaxRiskBreaches,
 		CircuitBreaker: config.CircuitBreakerConfig{
@@ -1651,6 +3170,13 @@ func riskConfigFromLimits(limits risk.Limits) config.RiskConfig {
 			Threshold: limits.CircuitBreaker.Threshold,
 			Cooldown:  cooldown,
 		},
+		FX: config.FXConfig{
+			Rates:          fxRates,
+			UseMarketRates: limits.UseMarketFXRates,
+		},
+		SelfTradePrevention: string(limits.SelfTradePrevention),
+		BalanceCheck:        limits.BalanceCheck,
+		Concentration:       concentration,
 	}
 }
 
@@ -1685,6 +3211,36 @@ func validateRiskConfig(cfg config.RiskConfig) error {
 	if cfg.CircuitBreaker.Enabled && strings.TrimSpace(cfg.CircuitBreaker.Cooldown) == "" {
 		return fmt.Errorf("circuitBreaker.cooldown required when enabled")
 	}
+	if _, err := risk.ParseSelfTradePolicy(cfg.SelfTradePrevention); err != nil {
+		return err
+	}
+	if err := validateConcentration(cfg.Concentration); err != nil {
+		return err
+	}
+	for currency, rate := range cfg.FX.Rates {
+		if strings.TrimSpace(currency) == "" {
+			return fmt.Errorf("fx.rates currency code required")
+		}
+		if err := ensurePositiveDecimal("fx.rates."+currency, rate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateConcentration(cfg config.ConcentrationConfig) error {
+	if cfg.MaxAssetPercent < 0 || cfg.MaxAssetPercent > 100 {
+		return fmt.Errorf("concentration.maxAssetPercent must be between 0 and 100")
+	}
+	if cfg.MaxVenuePercent < 0 || cfg.MaxVenuePercent > 100 {
+		return fmt.Errorf("concentration.maxVenuePercent must be between 0 and 100")
+	}
+	if raw := strings.TrimSpace(cfg.MinPortfolioNotional); raw != "" {
+		dec, err := decimal.NewFromString(raw)
+		if err != nil || dec.IsNegative() {
+			return fmt.Errorf("concentration.minPortfolioNotional must be a non-negative decimal number")
+		}
+	}
 	return nil
 }
 
@@ -1778,7 +3334,7 @@ func (s *httpServer) applyContextBackup(ctx context.Context, payload contextBack
 	targetProviders := make(map[string]config.ProviderSpec, len(payload.Providers))
 	orderedProviders := make([]config.ProviderSpec, 0, len(payload.Providers))
 	for _, spec := range payload.Providers {
-		sanitized := provider.SanitizeProviderSpec(spec)
+		sanitized := s.providers.Registry().SanitizeProviderSpec(spec)
 		sanitized.Name = strings.TrimSpace(sanitized.Name)
 		if sanitized.Name == "" {
 			return fmt.Errorf("provider name required")
@@ -1805,6 +3361,8 @@ func (s *httpServer) applyContextBackup(ctx context.Context, payload contextBack
 			},
 			ProviderSymbols: cloneProviderSymbolsMap(spec.ProviderSymbols),
 			Providers:       cloneStringSlice(spec.Providers),
+			Labels:          cloneLabelMap(spec.Labels),
+			DryRun:          spec.DryRun,
 		}
 		if copied.ID == "" {
 			return fmt.Errorf("lambda id required")
@@ -1887,7 +3445,7 @@ func (s *httpServer) applyContextBackup(ctx context.Context, payload contextBack
 		}
 	}
 
-	if payload.Risk.MaxPositionSize != "" || payload.Risk.MaxNotionalValue != "" || payload.Risk.NotionalCurrency != "" {
+	if restoresRiskLimits(payload.Risk) {
 		s.manager.ApplyRiskConfig(payload.Risk)
 	}
 	return nil
@@ -1905,7 +3463,20 @@ func lambdaSpecFromSnapshot(snapshot runtime.InstanceSnapshot) config.LambdaSpec
 		},
 		ProviderSymbols: cloneProviderSymbolsMap(snapshot.ProviderSymbols),
 		Providers:       cloneStringSlice(snapshot.Providers),
+		Labels:          cloneLabelMap(snapshot.Labels),
+		DryRun:          &snapshot.DryRun,
+	}
+}
+
+func cloneLabelMap(input map[string]string) map[string]string {
+	if len(input) == 0 {
+		return nil
 	}
+	out := make(map[string]string, len(input))
+	for key, value := range input {
+		out[key] = value
+	}
+	return out
 }
 
 func cloneProviderSymbolsMap(input map[string]config.ProviderSymbols) map[string]config.ProviderSymbols {
@@ -1914,7 +3485,7 @@ func cloneProviderSymbolsMap(input map[string]config.ProviderSymbols) map[string
 	}
 	out := make(map[string]config.ProviderSymbols, len(input))
 	for key, symbols := range input {
-		out[key] = config.ProviderSymbols{Symbols: cloneStringSlice(symbols.Symbols)}
+		out[key] = config.ProviderSymbols{Symbols: cloneStringSlice(symbols.Symbols), SubAccount: symbols.SubAccount}
 	}
 	return out
 }
@@ -2009,15 +3580,23 @@ func writeJSON(w http.ResponseWriter, status int, payload any) {
 	_ = pool.WriteJSON(w, payload)
 }
 
+// writeError renders the error envelope. The request ID assigned by withRequestID is already on
+// the response headers, so it is echoed without threading the request through every call site.
 func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]string{"status": "error", "error": message})
+	// Adapter errors can embed signed query strings or API keys.
+	payload := map[string]string{"status": "error", "error": redact.String(message)}
+	if id := w.Header().Get(telemetry.RequestIDHeader); id != "" {
+		payload["requestId"] = id
+	}
+	writeJSON(w, status, payload)
 }
 
 func withCORS(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", allowedCORSHeaders(r))
+		w.Header().Set("Access-Control-Expose-Headers", telemetry.RequestIDHeader+", ETag")
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
 			return
@@ -2027,7 +3606,7 @@ func withCORS(handler http.Handler) http.Handler {
 }
 
 func allowedCORSHeaders(r *http.Request) string {
-	defaults := []string{"Content-Type", "Authorization", "X-Meltica-Request-Id"}
+	defaults := []string{"Content-Type", "Authorization", telemetry.RequestIDHeader, "If-None-Match"}
 	seen := make(map[string]struct{}, len(defaults))
 	for _, header := range defaults {
 		seen[strings.ToLower(header)] = struct{}{}
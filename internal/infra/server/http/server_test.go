@@ -3,6 +3,8 @@ package httpserver
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -15,14 +17,18 @@ import (
 
 	"github.com/shopspring/decimal"
 
+	"github.com/coachpo/meltica/internal/app/calendar"
 	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/app/featureflags"
 	"github.com/coachpo/meltica/internal/app/lambda/js"
 	lambdaruntime "github.com/coachpo/meltica/internal/app/lambda/runtime"
 	"github.com/coachpo/meltica/internal/app/provider"
+	"github.com/coachpo/meltica/internal/app/risk"
 	"github.com/coachpo/meltica/internal/domain/orderstore"
 	"github.com/coachpo/meltica/internal/domain/schema"
 	"github.com/coachpo/meltica/internal/infra/bus/eventbus"
 	"github.com/coachpo/meltica/internal/infra/config"
+	"github.com/coachpo/meltica/internal/infra/persistence/migrations"
 	"github.com/coachpo/meltica/internal/infra/pool"
 	strategiestest "github.com/coachpo/meltica/internal/testutil/strategies"
 )
@@ -56,6 +62,48 @@ func TestDecodeRiskConfig_NormalizesAllowedOrderTypes(t *testing.T) {
 	}
 }
 
+func TestDecodeRiskConfig_ValidatesConcentration(t *testing.T) {
+	payload := `{
+		"maxPositionSize": "10",
+		"maxNotionalValue": "100",
+		"notionalCurrency": "USD",
+		"orderThrottle": 5,
+		"orderBurst": 1,
+		"concentration": {"maxAssetPercent": 150}
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/risk", strings.NewReader(payload))
+	if _, err := decodeRiskConfig(req); err == nil || !strings.Contains(err.Error(), "maxAssetPercent") {
+		t.Fatalf("expected maxAssetPercent validation error, got %v", err)
+	}
+}
+
+func TestRiskConcentrationRoute(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+		Risk: config.RiskConfig{
+			NotionalCurrency: "USDT",
+			Concentration:    config.ConcentrationConfig{MaxAssetPercent: 40, MaxVenuePercent: 80, MinPortfolioNotional: "1000"},
+		},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	handler := NewHandler(appCfg, manager, nil, nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/risk/concentration", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var report risk.ConcentrationReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if report.MaxAssetPercent != 40 || report.MinPortfolioNotional != "1000" || report.PortfolioNotional != "0" {
+		t.Fatalf("unexpected report %s", rec.Body.String())
+	}
+}
+
 func TestBuildContextBackup(t *testing.T) {
 	strategyDir := strategiestest.WriteStubStrategies(t)
 	appCfg := config.AppConfig{
@@ -248,6 +296,25 @@ func TestWriteStrategyModuleErrorReturnsDiagnostics(t *testing.T) {
 	if first["message"] != diag.Message {
 		t.Fatalf("expected diagnostic message %q, got %v", diag.Message, first["message"])
 	}
+	if first["severity"] != string(js.DiagnosticSeverityError) {
+		t.Fatalf("expected error severity, got %v", first["severity"])
+	}
+}
+
+func TestStrategyUploadPayloadIncludesLintWarnings(t *testing.T) {
+	resolution := js.ModuleResolution{Name: "noop", Hash: "sha256:abc", Tag: "v1", Alias: "v1"}
+	if _, ok := strategyUploadPayload("strategies", resolution)["diagnostics"]; ok {
+		t.Fatal("expected no diagnostics for a clean upload")
+	}
+	resolution.Warnings = js.Lint([]byte("module.exports = { create: function() { return { onTrade: function() { return Math.random(); } }; } };"))
+	payload := strategyUploadPayload("strategies", resolution)
+	warnings, ok := payload["diagnostics"].([]js.Diagnostic)
+	if !ok || len(warnings) != 1 || warnings[0].Stage != js.DiagnosticStageLint || warnings[0].Severity != js.DiagnosticSeverityWarning {
+		t.Fatalf("expected one lint warning, got %+v", payload["diagnostics"])
+	}
+	if payload["status"] != "pending_refresh" || payload["module"] == nil {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
 }
 
 func TestApplyContextBackupRestoresState(t *testing.T) {
@@ -367,7 +434,7 @@ func TestBuildProviderSpecFromPayload_SanitizesEmptyConfig(t *testing.T) {
 		},
 	}
 
-	spec, enabled, err := buildProviderSpecFromPayload(payload)
+	spec, enabled, err := buildProviderSpecFromPayload(payload, nil)
 	if err != nil {
 		t.Fatalf("buildProviderSpecFromPayload returned error: %v", err)
 	}
@@ -424,7 +491,7 @@ func TestBuildProviderSpecFromPayload_OmitsEmptyConfig(t *testing.T) {
 		},
 	}
 
-	spec, _, err := buildProviderSpecFromPayload(payload)
+	spec, _, err := buildProviderSpecFromPayload(payload, nil)
 	if err != nil {
 		t.Fatalf("buildProviderSpecFromPayload returned error: %v", err)
 	}
@@ -433,6 +500,54 @@ func TestBuildProviderSpecFromPayload_OmitsEmptyConfig(t *testing.T) {
 	}
 }
 
+func TestBuildProviderSpecFromPayload_ValidatesAdapterSchema(t *testing.T) {
+	reg := provider.NewRegistry()
+	reg.RegisterWithMetadata("schema-test", func(context.Context, *pool.PoolManager, map[string]any) (provider.Instance, error) {
+		return nil, fmt.Errorf("unused")
+	}, provider.AdapterMetadata{
+		Identifier: "schema-test",
+		SettingsSchema: []provider.AdapterSetting{
+			{Name: "api_key", Type: provider.SettingTypeString, Required: true, Secret: true},
+			{Name: "http_timeout", Type: provider.SettingTypeDuration},
+		},
+	})
+	payload := providerPayload{
+		Name: "schema-provider",
+		Adapter: providerAdapterPayload{
+			Identifier: "schema-test",
+			Config:     map[string]any{"http_timeout": "soon", "depth": 5},
+		},
+	}
+
+	_, _, err := buildProviderSpecFromPayload(payload, reg)
+	if err == nil {
+		t.Fatal("expected schema validation error")
+	}
+	rec := httptest.NewRecorder()
+	writeProviderPayloadError(rec, err)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	var body struct {
+		Fields []provider.SettingError `json:"fields"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error body: %v", err)
+	}
+	got := make(map[string]string, len(body.Fields))
+	for _, field := range body.Fields {
+		got[field.Field] = field.Message
+	}
+	if got["api_key"] != "required" || got["depth"] != "unknown setting" || got["http_timeout"] == "" {
+		t.Fatalf("unexpected field errors %v", got)
+	}
+
+	payload.Adapter.Config = map[string]any{"api_key": "key", "http_timeout": "5s"}
+	if _, _, err := buildProviderSpecFromPayload(payload, reg); err != nil {
+		t.Fatalf("expected valid payload, got %v", err)
+	}
+}
+
 func TestHandleProviderDeleteBlockedWhenInUse(t *testing.T) {
 	strategyDir := strategiestest.WriteStubStrategies(t)
 	appCfg := config.AppConfig{
@@ -820,6 +935,25 @@ func TestInstanceOrdersEndpointReturnsRecords(t *testing.T) {
 	}
 }
 
+func TestInstanceOrderEndpointsFilterByTag(t *testing.T) {
+	store := &stubOrderStore{}
+	handler := NewHandler(config.AppConfig{}, nil, nil, store)
+
+	for _, path := range []string{"/strategy/instances/demo/orders?tag=breakout", "/strategy/instances/demo/executions?tag=breakout"} {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+		if res.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", path, res.Code, res.Body.String())
+		}
+	}
+	if store.orderQuery.Tag != "breakout" || store.orderQuery.StrategyInstance != "demo" {
+		t.Fatalf("unexpected order query %+v", store.orderQuery)
+	}
+	if store.executionQuery.Tag != "breakout" || store.executionQuery.StrategyInstance != "demo" {
+		t.Fatalf("unexpected execution query %+v", store.executionQuery)
+	}
+}
+
 func TestInstanceOrdersEndpointInvalidLimit(t *testing.T) {
 	handler := NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{})
 	req := httptest.NewRequest(http.MethodGet, "/strategy/instances/demo/orders?limit=bogus", nil)
@@ -879,6 +1013,9 @@ type stubOrderStore struct {
 	orders     []orderstore.OrderRecord
 	executions []orderstore.ExecutionRecord
 	balances   []orderstore.BalanceRecord
+
+	orderQuery     orderstore.OrderQuery
+	executionQuery orderstore.ExecutionQuery
 }
 
 func (s *stubOrderStore) CreateOrder(context.Context, orderstore.Order) error             { return nil }
@@ -891,10 +1028,12 @@ func (s *stubOrderStore) WithTransaction(ctx context.Context, fn func(context.Co
 	}
 	return fn(ctx, s)
 }
-func (s *stubOrderStore) ListOrders(context.Context, orderstore.OrderQuery) ([]orderstore.OrderRecord, error) {
+func (s *stubOrderStore) ListOrders(_ context.Context, query orderstore.OrderQuery) ([]orderstore.OrderRecord, error) {
+	s.orderQuery = query
 	return s.orders, nil
 }
-func (s *stubOrderStore) ListExecutions(context.Context, orderstore.ExecutionQuery) ([]orderstore.ExecutionRecord, error) {
+func (s *stubOrderStore) ListExecutions(_ context.Context, query orderstore.ExecutionQuery) ([]orderstore.ExecutionRecord, error) {
+	s.executionQuery = query
 	return s.executions, nil
 }
 func (s *stubOrderStore) ListBalances(context.Context, orderstore.BalanceQuery) ([]orderstore.BalanceRecord, error) {
@@ -988,6 +1127,26 @@ func TestListStrategyModulesRejectsRunningOnly(t *testing.T) {
 	}
 }
 
+func TestSearchStrategyModulesValidatesQuery(t *testing.T) {
+	handler := NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{})
+	for _, target := range []string{
+		"/strategies/modules/search",
+		"/strategies/modules/search?q=grid&source=maybe",
+		"/strategies/modules/search?q=grid&limit=0",
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d (%s)", target, rec.Code, rec.Body.String())
+		}
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/strategies/modules/search?q=grid&source=true", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"matches":[]`) {
+		t.Fatalf("expected empty matches, got %d (%s)", rec.Code, rec.Body.String())
+	}
+}
+
 func TestBuildUsageSelector(t *testing.T) {
 	if sel := buildUsageSelector("noop@hash", "noop", "hash"); sel != "noop@hash" {
 		t.Fatalf("expected selector passthrough, got %s", sel)
@@ -1013,3 +1172,473 @@ func TestHandleStrategyModuleTagRoutes(t *testing.T) {
 		t.Fatalf("expected 503 due to nil manager, got %d (%s)", rec.Code, rec.Body.String())
 	}
 }
+
+func TestAdminUIGatedByConfig(t *testing.T) {
+	var cfg config.AppConfig
+	disabled := NewHandler(cfg, nil, nil, nil)
+	rec := httptest.NewRecorder()
+	disabled.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 with ui disabled, got %d", rec.Code)
+	}
+
+	cfg.APIServer.UI.Enabled = true
+	enabled := NewHandler(cfg, nil, nil, nil)
+	rec = httptest.NewRecorder()
+	enabled.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ui", nil))
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/ui/" {
+		t.Fatalf("expected redirect to /ui/, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	rec = httptest.NewRecorder()
+	enabled.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ui/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Meltica Console") {
+		t.Fatalf("expected console index, got %d", rec.Code)
+	}
+}
+
+func TestParseTimeRangeParams(t *testing.T) {
+	since, until, err := parseTimeRangeParams(url.Values{"since": {"1700000000"}, "until": {"2023-11-15T00:00:00Z"}})
+	if err != nil {
+		t.Fatalf("parseTimeRangeParams: %v", err)
+	}
+	if since.Unix() != 1700000000 || until.Format(time.RFC3339) != "2023-11-15T00:00:00Z" {
+		t.Fatalf("unexpected range %s - %s", since, until)
+	}
+	if _, _, err := parseTimeRangeParams(url.Values{"since": {"yesterday"}}); err == nil {
+		t.Fatal("expected invalid since to be rejected")
+	}
+	if _, _, err := parseTimeRangeParams(url.Values{"since": {"1700000100"}, "until": {"1700000000"}}); err == nil {
+		t.Fatal("expected inverted range to be rejected")
+	}
+}
+
+func TestRiskProfileRoutes(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	handler := NewHandler(appCfg, manager, nil, nil)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	limits := `{"maxPositionSize":"5","maxNotionalValue":"500","notionalCurrency":"USDT","orderThrottle":1,"orderBurst":1}`
+	if rec := serve(http.MethodPost, "/risk/profiles", `{"name":"FOMC","limits":`+limits+`}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodPost, "/risk/profiles", `{"name":"fomc","limits":`+limits+`}`); rec.Code != http.StatusConflict {
+		t.Fatalf("duplicate create: expected 409, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPut, "/risk/profiles/standard", `{"limits":`+limits+`}`); rec.Code != http.StatusConflict {
+		t.Fatalf("edit built-in: expected 409, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, "/risk/profiles/fomc/apply", ""); rec.Code != http.StatusOK {
+		t.Fatalf("apply: expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if got := manager.RiskLimits().MaxPositionSize.String(); got != "5" {
+		t.Fatalf("shared max position = %s, want 5", got)
+	}
+	if rec := serve(http.MethodDelete, "/risk/profiles/fomc", ""); rec.Code != http.StatusConflict {
+		t.Fatalf("delete applied profile: expected 409, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, "/risk/profiles/unknown/apply", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("apply unknown: expected 404, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPut, "/strategy/instances/ghost/risk-profile", `{"profile":"standard"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("assign to unknown instance: expected 404, got %d", rec.Code)
+	}
+
+	rec := serve(http.MethodGet, "/risk/profiles", "")
+	var listing struct {
+		Profiles    []lambdaruntime.RiskProfile          `json:"profiles"`
+		Assignments lambdaruntime.RiskProfileAssignments `json:"assignments"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("decode listing: %v", err)
+	}
+	if len(listing.Profiles) != 4 || listing.Assignments.Global != "fomc" {
+		t.Fatalf("unexpected listing %+v", listing)
+	}
+}
+
+func TestRiskLimitsPatchPreview(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+		Risk:       config.RiskConfig{MaxPositionSize: "10", MaxNotionalValue: "1000", NotionalCurrency: "USDT", OrderThrottle: 5, OrderBurst: 2},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	handler := NewHandler(appCfg, manager, nil, nil)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+	type previewResponse struct {
+		Status   string                          `json:"status"`
+		Limits   config.RiskConfig               `json:"limits"`
+		Breaches []lambdaruntime.RiskLimitBreach `json:"breaches"`
+		Token    string                          `json:"token"`
+	}
+
+	rec := serve(http.MethodPatch, "/risk/limits?preview=true", `{"maxPositionSize":"4","circuitBreaker":{"threshold":3}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("preview: expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var preview previewResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("decode preview: %v", err)
+	}
+	if preview.Status != "preview" || preview.Token == "" || preview.Breaches == nil || len(preview.Breaches) != 0 {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+	if preview.Limits.MaxPositionSize != "4" || preview.Limits.MaxNotionalValue != "1000" || preview.Limits.OrderBurst != 2 || preview.Limits.CircuitBreaker.Threshold != 3 {
+		t.Fatalf("expected the patch merged onto the current limits, got %+v", preview.Limits)
+	}
+	if got := manager.RiskLimits().MaxPositionSize.String(); got != "10" {
+		t.Fatalf("expected the preview not to apply, max position = %s", got)
+	}
+	if rec := serve(http.MethodPatch, "/risk/limits?preview=true", `{"orderThrottle":0}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid patch: expected 400, got %d", rec.Code)
+	}
+
+	if rec := serve(http.MethodPatch, "/risk/limits?confirm=stale", `{"maxPositionSize":"4","circuitBreaker":{"threshold":3}}`); rec.Code != http.StatusConflict {
+		t.Fatalf("stale confirm: expected 409, got %d", rec.Code)
+	}
+	rec = serve(http.MethodPatch, "/risk/limits?confirm="+preview.Token, `{"maxPositionSize":"4","circuitBreaker":{"threshold":3}}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"updated"`) {
+		t.Fatalf("confirm: expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	limits := manager.RiskLimits()
+	if limits.MaxPositionSize.String() != "4" || limits.MaxNotionalValue.String() != "1000" || limits.CircuitBreaker.Threshold != 3 {
+		t.Fatalf("unexpected applied limits %+v", limits)
+	}
+	// The applied change moved the base, so the old token no longer confirms anything.
+	if rec := serve(http.MethodPatch, "/risk/limits?confirm="+preview.Token, `{"maxPositionSize":"4","circuitBreaker":{"threshold":3}}`); rec.Code != http.StatusConflict {
+		t.Fatalf("reused token: expected 409, got %d", rec.Code)
+	}
+}
+
+func TestCalendarRoutes(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	cal := calendar.New(config.CalendarConfig{NotifyBefore: 5 * time.Minute}, calendar.NewGatewayExecutor(manager, nil, nil),
+		calendar.WithLogger(log.New(io.Discard, "", 0)),
+	)
+	handler := NewHandler(appCfg, manager, nil, nil, WithCalendar(cal))
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	at := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	rec := serve(http.MethodPost, "/calendar", `{"title":"FOMC","at":"`+at+`","notifyBefore":"15m","action":{"kind":"apply-risk-profile","profile":"conservative"}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("schedule: expected 201, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var created calendar.Entry
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	if created.ID == "" || created.Status != calendar.StatusScheduled {
+		t.Fatalf("unexpected entry %+v", created)
+	}
+	if rec := serve(http.MethodPost, "/calendar", `{"at":"`+at+`","action":{"kind":"stop-provider"}}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid action: expected 400, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, "/calendar", `{"at":"`+at+`","notifyBefore":"soon","action":{"kind":"halt-trading"}}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid lead time: expected 400, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/calendar/"+created.ID, ""); rec.Code != http.StatusOK {
+		t.Fatalf("get: expected 200, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodDelete, "/calendar/"+created.ID+"?reason=postponed", ""); rec.Code != http.StatusOK {
+		t.Fatalf("cancel: expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodDelete, "/calendar/"+created.ID, ""); rec.Code != http.StatusConflict {
+		t.Fatalf("cancel twice: expected 409, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/calendar/missing", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("missing: expected 404, got %d", rec.Code)
+	}
+
+	var listing struct {
+		Entries []calendar.Entry `json:"entries"`
+	}
+	rec = serve(http.MethodGet, "/calendar", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("decode listing: %v", err)
+	}
+	if len(listing.Entries) != 0 {
+		t.Fatalf("expected no open entries, got %d", len(listing.Entries))
+	}
+	rec = serve(http.MethodGet, "/calendar?all=true", "")
+	if err := json.Unmarshal(rec.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("decode listing: %v", err)
+	}
+	if len(listing.Entries) != 1 || listing.Entries[0].Status != calendar.StatusCancelled {
+		t.Fatalf("unexpected closed listing %+v", listing.Entries)
+	}
+}
+
+type blockAllRule struct{}
+
+func (blockAllRule) Name() string { return "block-all" }
+
+func (blockAllRule) Evaluate(context.Context, schema.OrderRequest, risk.RuleState) risk.Decision {
+	return risk.Deny("blocked", nil)
+}
+
+func TestRiskRuleRoutes(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil,
+		lambdaruntime.WithRiskRule(blockAllRule{}, risk.WithRuleDisabled()))
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	handler := NewHandler(appCfg, manager, nil, nil)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := serve(http.MethodPut, "/risk/rules/block-all", `{"enabled":true}`); rec.Code != http.StatusOK {
+		t.Fatalf("enable: expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if rec := serve(http.MethodPut, "/risk/rules/unknown", `{"enabled":true}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("enable unknown: expected 404, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPut, "/risk/rules/block-all", `{}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing enabled: expected 400, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodDelete, "/risk/rules/block-all", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("delete: expected 405, got %d", rec.Code)
+	}
+
+	rec := serve(http.MethodGet, "/risk/rules", "")
+	var listing lambdaruntime.RiskRules
+	if err := json.Unmarshal(rec.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("decode rules: %v", err)
+	}
+	if len(listing.Rules) != 1 || listing.Rules[0].Name != "block-all" || !listing.Rules[0].Enabled {
+		t.Fatalf("unexpected rules %s", rec.Body.String())
+	}
+}
+
+func TestFeatureFlagRoutes(t *testing.T) {
+	flags := featureflags.New(map[string]bool{featureflags.TagRollouts: false}, featureflags.WithLogger(log.New(io.Discard, "", 0)))
+	handler := NewHandler(config.AppConfig{}, nil, nil, &stubOrderStore{}, WithFeatureFlags(flags))
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/admin/flags", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d", rec.Code)
+	}
+	var listing struct {
+		Flags []featureflags.Flag `json:"flags"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("decode flags: %v", err)
+	}
+	if len(listing.Flags) != 3 {
+		t.Fatalf("expected 3 built-in flags, got %+v", listing.Flags)
+	}
+
+	rec = serve(http.MethodPut, "/admin/flags/tag_rollouts", `{"enabled":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("toggle: expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var flag featureflags.Flag
+	if err := json.Unmarshal(rec.Body.Bytes(), &flag); err != nil {
+		t.Fatalf("decode flag: %v", err)
+	}
+	if !flag.Enabled || flag.Source != featureflags.SourceRuntime || !flags.Enabled(featureflags.TagRollouts) {
+		t.Fatalf("unexpected toggled flag %+v", flag)
+	}
+	if rec := serve(http.MethodPut, "/admin/flags/tag_rollouts", `{}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing enabled: expected 400, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodDelete, "/admin/flags/tag_rollouts", ""); rec.Code != http.StatusOK || flags.Enabled(featureflags.TagRollouts) {
+		t.Fatalf("reset: expected 200 and config value, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPut, "/admin/flags/missing", `{"enabled":true}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown flag: expected 404, got %d", rec.Code)
+	}
+}
+
+func TestInstanceLabelFiltersAndGroups(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	handler := NewHandler(appCfg, manager, nil, nil)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	create := `{"id":%q,"labels":%s,"strategy":{"identifier":"logging","config":{}},"scope":{"okx":{"symbols":["BTC-USDT"]}}}`
+	for id, labels := range map[string]string{
+		"alpha": `{"desk":"futures","team":"delta"}`,
+		"beta":  `{"desk":"futures"}`,
+		"gamma": `{"desk":"spot","team":"delta"}`,
+	} {
+		if rec := serve(http.MethodPost, "/strategy/instances", fmt.Sprintf(create, id, labels)); rec.Code != http.StatusCreated {
+			t.Fatalf("create %s: expected 201, got %d (%s)", id, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := serve(http.MethodPost, "/strategy/instances", fmt.Sprintf(create, "delta", `{"desk:eu":"x"}`)); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid label: expected 400, got %d", rec.Code)
+	}
+
+	rec := serve(http.MethodGet, "/strategy/instances?label=desk:futures&label=team", "")
+	var listing struct {
+		Instances []lambdaruntime.InstanceSummary `json:"instances"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &listing); err != nil {
+		t.Fatalf("decode listing: %v", err)
+	}
+	if len(listing.Instances) != 1 || listing.Instances[0].ID != "alpha" || listing.Instances[0].Labels["team"] != "delta" {
+		t.Fatalf("unexpected filtered listing %+v", listing.Instances)
+	}
+	if rec := serve(http.MethodGet, "/strategy/instances?label=:futures", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid selector: expected 400, got %d", rec.Code)
+	}
+
+	if rec := serve(http.MethodGet, "/strategy/instance-groups", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing by: expected 400, got %d", rec.Code)
+	}
+	rec = serve(http.MethodGet, "/strategy/instance-groups?by=desk", "")
+	var grouped struct {
+		Groups []lambdaruntime.InstanceGroup `json:"groups"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &grouped); err != nil {
+		t.Fatalf("decode groups: %v", err)
+	}
+	if len(grouped.Groups) != 2 || grouped.Groups[0].Value != "futures" || grouped.Groups[0].Total != 2 || grouped.Groups[1].Total != 1 {
+		t.Fatalf("unexpected groups %+v", grouped.Groups)
+	}
+}
+
+func TestRuntimeInfoEndpoint(t *testing.T) {
+	appCfg := config.AppConfig{Environment: config.Environment("staging")}
+	startedAt := time.Now().Add(-90 * time.Second)
+	handler := NewHandler(appCfg, nil, nil, nil,
+		WithBuildInfo(BuildInfo{Version: "v1.2.3", Commit: "abc123", BuildTime: "", GoVersion: ""}, startedAt),
+		WithSchemaVersion(func(context.Context) (migrations.SchemaVersion, error) {
+			return migrations.SchemaVersion{Version: 11, Dirty: false, Latest: 12}, nil
+		}),
+	)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/info", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var info runtimeInfoResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("decode info: %v", err)
+	}
+	if info.Build.Version != "v1.2.3" || info.Build.Commit != "abc123" || info.Build.GoVersion == "" {
+		t.Fatalf("unexpected build %+v", info.Build)
+	}
+	if info.Environment != "staging" || info.UptimeSeconds < 90 || len(info.Adapters) != 0 {
+		t.Fatalf("unexpected info %+v", info)
+	}
+	if info.Schema == nil || info.Schema.Version != 11 || info.Schema.Latest != 12 || info.SchemaError != "" {
+		t.Fatalf("unexpected schema %+v (%s)", info.Schema, info.SchemaError)
+	}
+}
+
+func TestInstanceTradingRoute(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	handler := NewHandler(appCfg, manager, nil, nil)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := serve(http.MethodPost, "/strategy/instances/ghost/trading", `{"enabled":true}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown instance, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, "/strategy/instances/ghost/trading", `{"actor":"ops"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without enabled, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/strategy/instances/ghost/trading", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", rec.Code)
+	}
+}
+
+func TestInstanceShadowRoute(t *testing.T) {
+	appCfg := config.AppConfig{
+		Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)},
+	}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	handler := NewHandler(appCfg, manager, nil, nil)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := serve(http.MethodPost, "/strategy/instances/ghost/shadow", `{"candidate":"noop:latest"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown instance, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPost, "/strategy/instances/ghost/shadow", `{"candidate":"noop:latest","matchWindow":"soon"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a bad match window, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/strategy/instances/ghost/shadow", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a shadow, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodDelete, "/strategy/instances/ghost/shadow", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 stopping a missing shadow, got %d", rec.Code)
+	}
+	if rec := serve(http.MethodPut, "/strategy/instances/ghost/shadow", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for PUT, got %d", rec.Code)
+	}
+}
+
+func TestWriteErrorRedactsCredentials(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeError(rec, http.StatusBadGateway, "binance: POST /api/v3/order?symbol=BTCUSDT&signature=f00d: 401")
+	var payload map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if strings.Contains(payload["error"], "f00d") || !strings.Contains(payload["error"], "signature=[REDACTED]") {
+		t.Fatalf("expected the signature to be masked, got %q", payload["error"])
+	}
+}
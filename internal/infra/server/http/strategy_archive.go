@@ -0,0 +1,101 @@
+package httpserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/infra/redact"
+	"github.com/coachpo/meltica/internal/infra/telemetry"
+)
+
+const archiveContentType = "application/gzip"
+
+// exportStrategyArchive downloads the strategy registry and every registered revision as one
+// signed tarball.
+func (s *httpServer) exportStrategyArchive(w http.ResponseWriter, _ *http.Request) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "strategy manager unavailable")
+		return
+	}
+	var buf bytes.Buffer
+	manifest, err := s.manager.ExportStrategyArchive(&buf)
+	if err != nil {
+		writeStrategyArchiveError(w, err, nil)
+		return
+	}
+	filename := fmt.Sprintf("strategies-%s.tar.gz", manifest.CreatedAt.Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", archiveContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// importStrategyArchive merges an uploaded archive into the registry. conflict selects the policy
+// for tags that point elsewhere locally (fail, keep, overwrite or replace) and dryRun=true reports
+// the plan without applying it.
+func (s *httpServer) importStrategyArchive(w http.ResponseWriter, r *http.Request) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "strategy manager unavailable")
+		return
+	}
+	query := r.URL.Query()
+	policy, err := js.ParseArchiveConflictPolicy(query.Get("conflict"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	dryRun := false
+	if raw := query.Get("dryRun"); raw != "" {
+		val, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "dryRun must be a boolean")
+			return
+		}
+		dryRun = val
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, js.MaxArchiveBytes)
+	defer func() { _ = r.Body.Close() }()
+	result, err := s.manager.ImportStrategyArchive(r.Context(), r.Body, policy, dryRun, strategyChangeFromQuery(r))
+	if err != nil {
+		if isRequestTooLarge(err) {
+			writeError(w, http.StatusRequestEntityTooLarge, "archive too large")
+			return
+		}
+		writeStrategyArchiveError(w, err, &result)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status": "ok",
+		"dryRun": dryRun,
+		"result": result,
+	})
+}
+
+// writeStrategyArchiveError maps archive failures to statuses. Conflicts carry the import plan so
+// callers can see which tags or revisions blocked it.
+func writeStrategyArchiveError(w http.ResponseWriter, err error, result *js.ArchiveImportResult) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, js.ErrArchiveKeyRequired):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, js.ErrArchiveConflict), errors.Is(err, runtime.ErrArchiveRevisionInUse):
+		status = http.StatusConflict
+	case errors.Is(err, js.ErrArchiveSignature):
+		status = http.StatusForbidden
+	}
+	if status != http.StatusConflict || result == nil {
+		writeError(w, status, err.Error())
+		return
+	}
+	payload := map[string]any{"status": "error", "error": redact.Error(err), "result": result}
+	if id := w.Header().Get(telemetry.RequestIDHeader); id != "" {
+		payload["requestId"] = id
+	}
+	writeJSON(w, status, payload)
+}
@@ -0,0 +1,101 @@
+package httpserver
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	json "github.com/goccy/go-json"
+
+	"github.com/coachpo/meltica/internal/app/lambda/js"
+	"github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/infra/config"
+	strategiestest "github.com/coachpo/meltica/internal/testutil/strategies"
+)
+
+func newArchiveHandler(t *testing.T, dir, key string) http.Handler {
+	t.Helper()
+	cfg := config.AppConfig{Strategies: config.StrategiesConfig{Directory: dir, Archive: config.StrategyArchiveConfig{SigningKey: key}}}
+	manager, err := runtime.NewManager(cfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	return NewHandler(cfg, manager, nil, &stubOrderStore{})
+}
+
+func TestStrategyArchiveExportImport(t *testing.T) {
+	const key = "shared-archive-signing-key"
+	source := newArchiveHandler(t, strategiestest.WriteStubStrategies(t), key)
+	rec := httptest.NewRecorder()
+	source.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, strategyArchivePath, nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != archiveContentType {
+		t.Fatalf("export: expected gzip archive, got %d %s (%s)", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+	if !strings.Contains(rec.Header().Get("Content-Disposition"), ".tar.gz") {
+		t.Fatalf("expected attachment filename, got %q", rec.Header().Get("Content-Disposition"))
+	}
+	archive := rec.Body.Bytes()
+
+	targetDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(targetDir, "registry.json"), []byte("{}"), 0o600); err != nil {
+		t.Fatalf("write registry stub: %v", err)
+	}
+	target := newArchiveHandler(t, targetDir, key)
+	importArchive := func(query string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, strategyArchivePath+query, bytes.NewReader(archive))
+		req.Header.Set("Content-Type", archiveContentType)
+		target.ServeHTTP(rec, req)
+		var payload map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("decode import response: %v (%s)", err, rec.Body.String())
+		}
+		return rec.Code, payload
+	}
+
+	code, payload := importArchive("?dryRun=true")
+	result, _ := payload["result"].(map[string]any)
+	if code != http.StatusOK || payload["dryRun"] != true || len(result["addedRevisions"].([]any)) != 3 {
+		t.Fatalf("dry run: unexpected response %d %v", code, payload)
+	}
+	if code, _ := importArchive("?conflict=merge"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown policy, got %d", code)
+	}
+	if code, payload := importArchive("?actor=ops"); code != http.StatusOK || payload["dryRun"] != false {
+		t.Fatalf("import: unexpected response %d %v", code, payload)
+	}
+
+	rec = httptest.NewRecorder()
+	target.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/strategies/modules/logging", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected imported module, got %d (%s)", rec.Code, rec.Body.String())
+	}
+
+	forged := newArchiveHandler(t, t.TempDir(), "a-different-signing-key")
+	rec = httptest.NewRecorder()
+	forged.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, strategyArchivePath, bytes.NewReader(archive)))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for foreign signature, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	unsigned := newArchiveHandler(t, t.TempDir(), "")
+	rec = httptest.NewRecorder()
+	unsigned.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, strategyArchivePath, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without signing key, got %d (%s)", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWriteStrategyArchiveErrorIncludesConflicts(t *testing.T) {
+	result := js.ArchiveImportResult{Policy: js.ArchiveConflictFail, Conflicts: []js.ArchiveTagChange{{Strategy: "grid", Tag: "latest", Previous: "sha256:a", Hash: "sha256:b"}}}
+	rec := httptest.NewRecorder()
+	writeStrategyArchiveError(rec, js.ErrArchiveConflict, &result)
+	if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), `"conflicts":[{"strategy":"grid"`) {
+		t.Fatalf("expected 409 with conflicts, got %d (%s)", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,75 @@
+package httpserver
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+)
+
+type strategyLogLine struct {
+	ID         int64     `json:"id"`
+	Strategy   string    `json:"strategy,omitempty"`
+	Hash       string    `json:"hash,omitempty"`
+	Level      string    `json:"level"`
+	Source     string    `json:"source"`
+	Message    string    `json:"message"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// handleInstanceLogs returns the recorded console logs and diagnostics of an instance. The
+// instance does not have to exist any more: persisted lines outlive removals and restarts.
+func (s *httpServer) handleInstanceLogs(w http.ResponseWriter, r *http.Request, id string) {
+	if s.manager == nil {
+		writeError(w, http.StatusServiceUnavailable, "lambda manager unavailable")
+		return
+	}
+	values := r.URL.Query()
+	from, err := parseTimeParam("from", values.Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := parseLimitParam(values.Get("limit"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	level := strings.TrimSpace(values.Get("level"))
+	if level != "" {
+		normalized, ok := strategylogstore.NormalizeLevel(level)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "level must be debug, info, warn or error")
+			return
+		}
+		level = normalized
+	}
+	entries, err := s.manager.StrategyLogs(r.Context(), id, runtime.StrategyLogQuery{
+		From:     from,
+		MinLevel: level,
+		Limit:    limit,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	lines := make([]strategyLogLine, 0, len(entries))
+	for _, entry := range entries {
+		lines = append(lines, strategyLogLine{
+			ID:         entry.ID,
+			Strategy:   entry.Strategy,
+			Hash:       entry.Hash,
+			Level:      entry.Level,
+			Source:     entry.Source,
+			Message:    entry.Message,
+			RecordedAt: entry.RecordedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"instance": id,
+		"logs":     lines,
+		"count":    len(lines),
+	})
+}
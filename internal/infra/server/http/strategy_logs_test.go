@@ -0,0 +1,82 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	json "github.com/goccy/go-json"
+
+	lambdaruntime "github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/domain/strategylogstore"
+	"github.com/coachpo/meltica/internal/infra/config"
+	strategiestest "github.com/coachpo/meltica/internal/testutil/strategies"
+)
+
+type stubStrategyLogStore struct {
+	query strategylogstore.Query
+}
+
+func (s *stubStrategyLogStore) AppendLogs(context.Context, []strategylogstore.Entry) error {
+	return nil
+}
+
+func (s *stubStrategyLogStore) ListLogs(_ context.Context, query strategylogstore.Query) ([]strategylogstore.Entry, error) {
+	s.query = query
+	return []strategylogstore.Entry{{
+		ID:         7,
+		InstanceID: query.InstanceID,
+		Strategy:   "momentum",
+		Level:      strategylogstore.LevelError,
+		Source:     strategylogstore.SourceRuntime,
+		Message:    "onTrade: boom",
+		RecordedAt: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+	}}, nil
+}
+
+func (s *stubStrategyLogStore) PurgeLogs(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestInstanceLogsEndpoint(t *testing.T) {
+	store := &stubStrategyLogStore{}
+	appCfg := config.AppConfig{Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)}}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil, lambdaruntime.WithStrategyLogStore(store))
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	handler := NewHandler(appCfg, manager, nil, &stubOrderStore{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/strategy/instances/removed-1/logs?from=2026-10-16T08:00:00Z&level=WARNING&limit=20", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a removed instance, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	if store.query.InstanceID != "removed-1" || store.query.MinLevel != "warn" || store.query.Limit != 20 ||
+		!store.query.From.Equal(time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected store query %+v", store.query)
+	}
+	var payload struct {
+		Instance string            `json:"instance"`
+		Logs     []strategyLogLine `json:"logs"`
+		Count    int               `json:"count"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.Instance != "removed-1" || payload.Count != 1 || payload.Logs[0].Source != "runtime" || payload.Logs[0].Message != "onTrade: boom" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	for _, query := range []string{"level=verbose", "from=yesterday", "limit=-1"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/strategy/instances/removed-1/logs?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/coachpo/meltica/internal/app/lambda/runtime"
+)
+
+const (
+	strategyReportsPath      = "/reports/strategies"
+	strategyReportPrefix     = strategyReportsPath + "/"
+	strategyReportLatestName = "latest"
+)
+
+// listStrategyReports lists the generated strategy usage reports, newest first.
+func (s *httpServer) listStrategyReports(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimitParam(r.URL.Query().Get("limit"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	reports, err := s.manager.StrategyReports(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"reports": reports, "count": len(reports)})
+}
+
+// generateStrategyReport generates and stores a report outside the schedule.
+func (s *httpServer) generateStrategyReport(w http.ResponseWriter, r *http.Request) {
+	report, err := s.manager.GenerateStrategyReport(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeStrategyReport(w, r, http.StatusCreated, report)
+}
+
+// getStrategyReport returns one report by identifier, or the newest for /reports/strategies/latest.
+func (s *httpServer) getStrategyReport(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, strategyReportPrefix), "/")
+	var id int64
+	if name != strategyReportLatestName {
+		parsed, err := strconv.ParseInt(name, 10, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusNotFound, "strategy report id required")
+			return
+		}
+		id = parsed
+	}
+	report, err := s.manager.StrategyReport(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, runtime.ErrStrategyReportNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeStrategyReport(w, r, http.StatusOK, report)
+}
+
+// writeStrategyReport writes the report as JSON, or as plain text with ?format=text.
+func writeStrategyReport(w http.ResponseWriter, r *http.Request, status int, report runtime.StrategyReport) {
+	if strings.EqualFold(strings.TrimSpace(r.URL.Query().Get("format")), "text") {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(report.Text()))
+		return
+	}
+	writeJSON(w, status, report)
+}
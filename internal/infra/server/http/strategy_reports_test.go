@@ -0,0 +1,63 @@
+package httpserver
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	json "github.com/goccy/go-json"
+
+	lambdaruntime "github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/infra/config"
+	strategiestest "github.com/coachpo/meltica/internal/testutil/strategies"
+)
+
+func TestStrategyReportsEndpoints(t *testing.T) {
+	appCfg := config.AppConfig{Strategies: config.StrategiesConfig{Directory: strategiestest.WriteStubStrategies(t)}}
+	manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	handler := NewHandler(appCfg, manager, nil, &stubOrderStore{})
+	do := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	if rec := do(http.MethodGet, "/reports/strategies/latest"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 before any report, got %d", rec.Code)
+	}
+	rec := do(http.MethodPost, "/reports/strategies")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var report lambdaruntime.StrategyReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil || report.ID != 1 || report.Totals.Strategies == 0 {
+		t.Fatalf("unexpected report %+v (%v)", report, err)
+	}
+
+	rec = do(http.MethodGet, "/reports/strategies?limit=5")
+	var listing struct {
+		Reports []lambdaruntime.StrategyReportSummary `json:"reports"`
+		Count   int                                   `json:"count"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &listing); err != nil || listing.Count != 1 || listing.Reports[0].Summary != report.Summary() {
+		t.Fatalf("unexpected listing %s (%v)", rec.Body.String(), err)
+	}
+
+	rec = do(http.MethodGet, "/reports/strategies/1?format=text")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") ||
+		!strings.Contains(rec.Body.String(), report.Summary()) {
+		t.Fatalf("unexpected text report %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodGet, "/reports/strategies/2"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown report, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/reports/strategies/abc"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for malformed id, got %d", rec.Code)
+	}
+}
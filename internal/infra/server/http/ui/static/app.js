@@ -0,0 +1,279 @@
+// Meltica admin console: a thin client over the control API served from the same origin.
+(function () {
+  "use strict";
+
+  const tokenKey = "meltica.console.token";
+  const views = ["instances", "strategies", "risk"];
+  const $ = (id) => document.getElementById(id);
+
+  async function api(method, path, body) {
+    const headers = { Accept: "application/json" };
+    const token = localStorage.getItem(tokenKey);
+    if (token) {
+      headers.Authorization = "Bearer " + token;
+    }
+    if (body !== undefined) {
+      headers["Content-Type"] = "application/json";
+    }
+    const resp = await fetch(path, {
+      method,
+      headers,
+      body: body === undefined ? undefined : typeof body === "string" ? body : JSON.stringify(body),
+    });
+    const text = await resp.text();
+    let data = null;
+    try {
+      data = text ? JSON.parse(text) : null;
+    } catch (_) {
+      data = text;
+    }
+    if (!resp.ok) {
+      const message = data && data.error ? data.error : resp.status + " " + resp.statusText;
+      throw new Error(message);
+    }
+    return data;
+  }
+
+  function notify(message, isError) {
+    const el = $("notice");
+    el.textContent = message;
+    el.className = isError ? "error" : "";
+    el.hidden = !message;
+    if (message && !isError) {
+      setTimeout(() => {
+        if (el.textContent === message) el.hidden = true;
+      }, 4000);
+    }
+  }
+
+  async function run(action, success) {
+    try {
+      const result = await action();
+      if (success) notify(typeof success === "function" ? success(result) : success, false);
+      return result;
+    } catch (err) {
+      notify(err.message, true);
+      return null;
+    }
+  }
+
+  function cell(text, code) {
+    const td = document.createElement("td");
+    if (code) {
+      const c = document.createElement("code");
+      c.textContent = text;
+      td.appendChild(c);
+    } else {
+      td.textContent = text;
+    }
+    return td;
+  }
+
+  function button(label, onClick, className) {
+    const b = document.createElement("button");
+    b.textContent = label;
+    if (className) b.className = className;
+    b.addEventListener("click", onClick);
+    return b;
+  }
+
+  function fillRows(tbody, items, render, columns) {
+    tbody.replaceChildren();
+    if (!items || items.length === 0) {
+      const tr = document.createElement("tr");
+      const td = cell("none");
+      td.colSpan = columns;
+      tr.appendChild(td);
+      tbody.appendChild(tr);
+      return;
+    }
+    for (const item of items) tbody.appendChild(render(item));
+  }
+
+  const shortHash = (hash) => (hash || "").replace(/^sha256:/, "").slice(0, 12);
+  const pct = (v) => (v === undefined || v === null ? "-" : Math.round(v * 100) + "%");
+
+  // Instances ---------------------------------------------------------------
+
+  async function loadInstances() {
+    const data = await run(() => api("GET", "/strategy/instances"));
+    if (!data) return;
+    fillRows($("instances"), data.instances, (inst) => {
+      const tr = document.createElement("tr");
+      tr.append(
+        cell(inst.id),
+        cell(inst.strategyIdentifier),
+        cell(inst.strategyTag || shortHash(inst.strategyHash) || "-", true),
+        cell((inst.providers || []).join(", ")),
+        cell((inst.aggregatedSymbols || []).join(", ")),
+        cell(inst.running ? "running" : "stopped"),
+      );
+      const actions = document.createElement("td");
+      const target = "/strategy/instances/" + encodeURIComponent(inst.id);
+      if (inst.running) {
+        actions.appendChild(button("Stop", async () => {
+          if (!confirm("Stop instance " + inst.id + "?")) return;
+          await run(() => api("POST", target + "/stop"), "Stopped " + inst.id);
+          loadInstances();
+        }));
+      } else {
+        actions.appendChild(button("Start", async () => {
+          await run(() => api("POST", target + "/start"), "Started " + inst.id);
+          loadInstances();
+        }));
+      }
+      actions.appendChild(button("Delete", async () => {
+        if (!confirm("Delete instance " + inst.id + "?")) return;
+        await run(() => api("DELETE", target), "Deleted " + inst.id);
+        loadInstances();
+      }, "danger"));
+      tr.appendChild(actions);
+      return tr;
+    }, 7);
+  }
+
+  async function createInstance() {
+    let spec;
+    try {
+      spec = JSON.parse($("instance-spec").value);
+    } catch (err) {
+      notify("Instance spec is not valid JSON: " + err.message, true);
+      return;
+    }
+    const created = await run(() => api("POST", "/strategy/instances", spec), (res) => "Created " + res.id);
+    if (created) loadInstances();
+  }
+
+  // Strategies --------------------------------------------------------------
+
+  async function loadModules() {
+    const data = await run(() => api("GET", "/strategies/modules"));
+    if (!data) return;
+    fillRows($("modules"), data.modules, (mod) => {
+      const tr = document.createElement("tr");
+      tr.append(
+        cell(mod.name),
+        cell((mod.metadata && mod.metadata.version) || "-"),
+        cell(mod.selectedRevisionTag || "-"),
+        cell(shortHash(mod.selectedRevisionHash), true),
+        cell((mod.tags || []).join(", ")),
+      );
+      tr.addEventListener("click", () => {
+        $("tag-module").value = mod.name;
+        $("tag-hash").value = mod.selectedRevisionHash || "";
+      });
+      return tr;
+    }, 5);
+  }
+
+  async function uploadModule() {
+    const file = $("module-file").files[0];
+    if (!file) {
+      notify("Choose a module file first", true);
+      return;
+    }
+    const source = await file.text();
+    const validate = $("module-validate").checked;
+    const path = "/strategies/modules" + (validate ? "?validate=true" : "");
+    const res = await run(() => api("POST", path, { source }), (r) =>
+      validate ? "Module is valid" : "Uploaded " + (r.module ? r.module.name + " " + shortHash(r.module.hash) : file.name));
+    if (res && !validate) loadModules();
+  }
+
+  async function assignTag() {
+    const name = $("tag-module").value.trim();
+    const tag = $("tag-name").value.trim();
+    const hash = $("tag-hash").value.trim();
+    if (!name || !tag || !hash) {
+      notify("Module, tag and hash are required", true);
+      return;
+    }
+    const path = "/strategies/modules/" + encodeURIComponent(name) + "/tags/" + encodeURIComponent(tag);
+    const res = await run(() => api("PUT", path, { hash, refresh: true }), "Tag " + name + ":" + tag + " assigned");
+    if (res) loadModules();
+  }
+
+  // Risk --------------------------------------------------------------------
+
+  async function loadStatus() {
+    const status = await run(() => api("GET", "/risk/status"));
+    const badge = $("trading");
+    if (!status) {
+      badge.textContent = "";
+      return null;
+    }
+    badge.textContent = status.tradingHalted ? "HALTED: " + status.haltReason : "trading active";
+    badge.className = "badge " + (status.tradingHalted ? "bad" : "ok");
+    return status;
+  }
+
+  async function loadRisk() {
+    const status = await loadStatus();
+    if (status) {
+      fillRows($("positions"), status.positions, (p) => {
+        const tr = document.createElement("tr");
+        tr.append(cell(p.symbol), cell(p.position), cell(p.notional), cell(pct(p.positionUtilization)),
+          cell(pct(p.notionalUtilization)), cell(String(p.inFlight)));
+        return tr;
+      }, 6);
+    }
+    const limits = await run(() => api("GET", "/risk/limits"));
+    if (limits) $("risk-limits").value = JSON.stringify(limits.limits, null, 2);
+  }
+
+  async function saveLimits() {
+    let limits;
+    try {
+      limits = JSON.parse($("risk-limits").value);
+    } catch (err) {
+      notify("Limits are not valid JSON: " + err.message, true);
+      return;
+    }
+    const res = await run(() => api("PUT", "/risk/limits", limits), "Risk limits updated");
+    if (res) $("risk-limits").value = JSON.stringify(res.limits, null, 2);
+  }
+
+  async function setKillSwitch(engaged) {
+    const prompt = engaged ? "Halt all trading?" : "Release the kill switch and resume trading?";
+    if (!confirm(prompt)) return;
+    await run(() => api("POST", "/risk/kill-switch", { engaged, reason: "admin console" }),
+      engaged ? "Kill switch engaged" : "Kill switch released");
+    loadRisk();
+  }
+
+  // Navigation --------------------------------------------------------------
+
+  const loaders = { instances: loadInstances, strategies: loadModules, risk: loadRisk };
+
+  function show() {
+    const name = views.includes(location.hash.slice(1)) ? location.hash.slice(1) : "instances";
+    for (const view of views) {
+      $("view-" + view).hidden = view !== name;
+    }
+    document.querySelectorAll("nav a").forEach((a) => a.classList.toggle("active", a.dataset.view === name));
+    loaders[name]();
+    if (name !== "risk") loadStatus();
+  }
+
+  function init() {
+    const token = $("token");
+    token.value = localStorage.getItem(tokenKey) || "";
+    token.addEventListener("change", () => {
+      if (token.value) localStorage.setItem(tokenKey, token.value);
+      else localStorage.removeItem(tokenKey);
+      show();
+    });
+    document.querySelectorAll("[data-action=reload]").forEach((b) => b.addEventListener("click", show));
+    $("create-instance").addEventListener("click", createInstance);
+    $("upload-module").addEventListener("click", uploadModule);
+    $("assign-tag").addEventListener("click", assignTag);
+    $("refresh-strategies").addEventListener("click", () => run(() => api("POST", "/strategies/refresh"), "Strategies refreshed"));
+    $("save-limits").addEventListener("click", saveLimits);
+    $("kill-engage").addEventListener("click", () => setKillSwitch(true));
+    $("kill-release").addEventListener("click", () => setKillSwitch(false));
+    window.addEventListener("hashchange", show);
+    show();
+  }
+
+  init();
+})();
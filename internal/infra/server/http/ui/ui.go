@@ -0,0 +1,48 @@
+// Package ui embeds the single-page admin console served by the control API.
+package ui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Files contains the console's static assets.
+//
+//go:embed static
+var Files embed.FS
+
+const indexFile = "index.html"
+
+// Handler serves the console below prefix (e.g. "/ui/"). Unknown paths fall back to index.html so
+// client-side routes survive a reload.
+func Handler(prefix string) http.Handler {
+	static, err := fs.Sub(Files, "static")
+	if err != nil {
+		panic(err)
+	}
+	files := http.FileServer(http.FS(static))
+	return http.StripPrefix(strings.TrimSuffix(prefix, "/"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" {
+			name = indexFile
+		}
+		if _, err := fs.Stat(static, name); err != nil {
+			name = indexFile
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "no-cache")
+		if name == indexFile {
+			http.ServeFileFS(w, r, static, indexFile)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlerServesIndexAndAssets(t *testing.T) {
+	handler := Handler("/ui/")
+	cases := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{path: "/ui/", contentType: "text/html", contains: "<title>Meltica Console</title>"},
+		{path: "/ui/app.js", contentType: "javascript", contains: "/strategy/instances"},
+		{path: "/ui/app.css", contentType: "text/css", contains: "--accent"},
+		{path: "/ui/instances/grid", contentType: "text/html", contains: "<title>Meltica Console</title>"},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", tc.path, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, tc.contentType) {
+			t.Fatalf("%s: unexpected content type %q", tc.path, ct)
+		}
+		if !strings.Contains(rec.Body.String(), tc.contains) {
+			t.Fatalf("%s: body missing %q", tc.path, tc.contains)
+		}
+	}
+}
+
+func TestHandlerRejectsWrites(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler("/ui/").ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ui/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,60 @@
+package httpserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/lambda/runtime"
+)
+
+const usageReportPath = "/reports/usage"
+
+// getUsageReport reports the metered usage of the instances grouped by a label, for charging
+// shared gateways back to the teams running them.
+func (s *httpServer) getUsageReport(w http.ResponseWriter, r *http.Request) {
+	query := runtime.UsageQuery{
+		From:    time.Time{},
+		To:      time.Time{},
+		GroupBy: strings.TrimSpace(r.URL.Query().Get("groupBy")),
+		Daily:   false,
+	}
+	var err error
+	if query.From, err = parseDayQuery(r, "from"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if query.To, err = parseDayQuery(r, "to"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if query.Daily, err = parseBoolQuery(r, "daily"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	report, err := s.manager.UsageReport(r.Context(), query)
+	switch {
+	case errors.Is(err, runtime.ErrUsageDisabled):
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, runtime.ErrInvalidUsageRange):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, report)
+	}
+}
+
+func parseDayQuery(r *http.Request, key string) (time.Time, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get(key))
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	day, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date (YYYY-MM-DD)", key)
+	}
+	return day, nil
+}
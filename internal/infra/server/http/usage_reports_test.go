@@ -0,0 +1,48 @@
+package httpserver
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	json "github.com/goccy/go-json"
+
+	lambdaruntime "github.com/coachpo/meltica/internal/app/lambda/runtime"
+	"github.com/coachpo/meltica/internal/infra/config"
+	strategiestest "github.com/coachpo/meltica/internal/testutil/strategies"
+)
+
+func TestUsageReportEndpoint(t *testing.T) {
+	dir := strategiestest.WriteStubStrategies(t)
+	serve := func(usage config.StrategyUsageConfig, target string) *httptest.ResponseRecorder {
+		appCfg := config.AppConfig{Strategies: config.StrategiesConfig{Directory: dir, Usage: usage}}
+		manager, err := lambdaruntime.NewManager(appCfg, nil, nil, nil, log.New(io.Discard, "", 0), nil)
+		if err != nil {
+			t.Fatalf("NewManager: %v", err)
+		}
+		rec := httptest.NewRecorder()
+		NewHandler(appCfg, manager, nil, &stubOrderStore{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	if rec := serve(config.StrategyUsageConfig{}, "/reports/usage"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while disabled, got %d", rec.Code)
+	}
+	enabled := config.StrategyUsageConfig{Enabled: true, GroupBy: "team"}
+	rec := serve(enabled, "/reports/usage?from=2026-10-01&to=2026-10-16&groupBy=desk&daily=true")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%s)", rec.Code, rec.Body.String())
+	}
+	var report lambdaruntime.UsageReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil || report.From != "2026-10-01" || report.GroupBy != "desk" {
+		t.Fatalf("unexpected report %+v (%v)", report, err)
+	}
+	if rec := serve(enabled, "/reports/usage?from=yesterday"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a bad date, got %d", rec.Code)
+	}
+	if rec := serve(enabled, "/reports/usage?from=2026-10-16&to=2026-10-01"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a reversed range, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,47 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the control API request identifier in requests and responses.
+const RequestIDHeader = "X-Meltica-Request-Id"
+
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request identifier.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request identifier stored in ctx, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NormalizeRequestID accepts a caller-supplied identifier when it is short and limited to
+// characters that are safe to echo into headers and log lines; otherwise a new one is generated.
+func NormalizeRequestID(raw string) string {
+	id := strings.TrimSpace(raw)
+	if id == "" || len(id) > maxRequestIDLength {
+		return uuid.NewString()
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return uuid.NewString()
+		}
+	}
+	return id
+}
@@ -0,0 +1,27 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeRequestID(t *testing.T) {
+	if got := NormalizeRequestID(" abc-123_x.y:z "); got != "abc-123_x.y:z" {
+		t.Fatalf("expected caller id to be kept, got %q", got)
+	}
+	for _, raw := range []string{"", "has space", "new\nline"} {
+		if got := NormalizeRequestID(raw); got == "" || got == raw {
+			t.Fatalf("expected generated id for %q, got %q", raw, got)
+		}
+	}
+}
+
+func TestRequestIDContextRoundTrip(t *testing.T) {
+	if got := RequestIDFromContext(context.Background()); got != "" {
+		t.Fatalf("expected empty id, got %q", got)
+	}
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	if got := RequestIDFromContext(ctx); got != "req-1" {
+		t.Fatalf("expected req-1, got %q", got)
+	}
+}
@@ -0,0 +1,141 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	runtimeGCPauseMetric      = "process.runtime.go.gc.pause"
+	runtimeGCPauseDescription = "Garbage collector stop-the-world pause"
+	// gcPauseHistory is the size of the runtime's ring buffer of recent GC pauses.
+	gcPauseHistory = len(runtime.MemStats{}.PauseNs)
+)
+
+// RuntimeSampler records goroutine, heap and garbage collector statistics of the gateway process,
+// so regressions in adapters or the JavaScript runtime show up as growing goroutine counts, heap
+// or GC pressure.
+type RuntimeSampler struct {
+	goroutines  metric.Int64Gauge
+	heapAlloc   metric.Int64Gauge
+	heapInuse   metric.Int64Gauge
+	heapObjects metric.Int64Gauge
+	heapGoal    metric.Int64Gauge
+	gcCycles    metric.Int64Counter
+	gcPause     metric.Float64Histogram
+	attrs       metric.MeasurementOption
+
+	// lastGC is the GC cycle count at the previous sample; pauses of later cycles are recorded.
+	lastGC uint32
+}
+
+// NewRuntimeSampler creates the runtime instruments on meter.
+func NewRuntimeSampler(meter metric.Meter) (*RuntimeSampler, error) {
+	goroutines, err := meter.Int64Gauge("process.runtime.go.goroutines",
+		metric.WithDescription("Live goroutines"),
+		metric.WithUnit("{goroutine}"))
+	if err != nil {
+		return nil, fmt.Errorf("create goroutines gauge: %w", err)
+	}
+	heapAlloc, err := meter.Int64Gauge("process.runtime.go.heap.alloc",
+		metric.WithDescription("Bytes of allocated heap objects"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("create heap alloc gauge: %w", err)
+	}
+	heapInuse, err := meter.Int64Gauge("process.runtime.go.heap.inuse",
+		metric.WithDescription("Bytes in in-use heap spans"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("create heap in-use gauge: %w", err)
+	}
+	heapObjects, err := meter.Int64Gauge("process.runtime.go.heap.objects",
+		metric.WithDescription("Allocated heap objects"),
+		metric.WithUnit("{object}"))
+	if err != nil {
+		return nil, fmt.Errorf("create heap objects gauge: %w", err)
+	}
+	heapGoal, err := meter.Int64Gauge("process.runtime.go.heap.goal",
+		metric.WithDescription("Heap size at which the next GC cycle starts"),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, fmt.Errorf("create heap goal gauge: %w", err)
+	}
+	gcCycles, err := meter.Int64Counter("process.runtime.go.gc.cycles",
+		metric.WithDescription("Completed garbage collector cycles"),
+		metric.WithUnit("{cycle}"))
+	if err != nil {
+		return nil, fmt.Errorf("create gc cycles counter: %w", err)
+	}
+	gcPause, err := meter.Float64Histogram(runtimeGCPauseMetric,
+		metric.WithDescription(runtimeGCPauseDescription),
+		metric.WithUnit("ms"))
+	if err != nil {
+		return nil, fmt.Errorf("create gc pause histogram: %w", err)
+	}
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return &RuntimeSampler{
+		goroutines:  goroutines,
+		heapAlloc:   heapAlloc,
+		heapInuse:   heapInuse,
+		heapObjects: heapObjects,
+		heapGoal:    heapGoal,
+		gcCycles:    gcCycles,
+		gcPause:     gcPause,
+		attrs:       metric.WithAttributes(AttrEnvironment.String(Environment())),
+		lastGC:      stats.NumGC,
+	}, nil
+}
+
+// Run samples every interval until ctx is done.
+func (s *RuntimeSampler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sample(ctx)
+		}
+	}
+}
+
+// Sample records the current statistics and the pauses of the GC cycles completed since the
+// previous sample. When more cycles completed than the runtime remembers, only the most recent
+// pauses are recorded.
+func (s *RuntimeSampler) Sample(ctx context.Context) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	s.goroutines.Record(ctx, int64(runtime.NumGoroutine()), s.attrs)
+	s.heapAlloc.Record(ctx, clampInt64(stats.HeapAlloc), s.attrs)
+	s.heapInuse.Record(ctx, clampInt64(stats.HeapInuse), s.attrs)
+	s.heapObjects.Record(ctx, clampInt64(stats.HeapObjects), s.attrs)
+	s.heapGoal.Record(ctx, clampInt64(stats.NextGC), s.attrs)
+
+	cycles := stats.NumGC - s.lastGC
+	s.lastGC = stats.NumGC
+	if cycles == 0 {
+		return
+	}
+	s.gcCycles.Add(ctx, int64(cycles), s.attrs)
+	recorded := min(int(cycles), gcPauseHistory)
+	for i := range recorded {
+		// PauseNs holds the pause of cycle n at index (n+255)%256.
+		idx := (int(stats.NumGC) - 1 - i + gcPauseHistory) % gcPauseHistory
+		s.gcPause.Record(ctx, float64(stats.PauseNs[idx])/float64(time.Millisecond), s.attrs)
+	}
+}
+
+func clampInt64(v uint64) int64 {
+	if v > uint64(1<<63-1) {
+		return 1<<63 - 1
+	}
+	return int64(v)
+}
@@ -0,0 +1,50 @@
+package telemetry
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRuntimeSamplerRecordsRuntimeStatistics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	sampler, err := NewRuntimeSampler(provider.Meter("runtime"))
+	if err != nil {
+		t.Fatalf("NewRuntimeSampler: %v", err)
+	}
+	runtime.GC()
+	runtime.GC()
+	sampler.Sample(context.Background())
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	seen := make(map[string]metricdata.Aggregation)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			seen[m.Name] = m.Data
+		}
+	}
+	goroutines, ok := seen["process.runtime.go.goroutines"].(metricdata.Gauge[int64])
+	if !ok || len(goroutines.DataPoints) != 1 || goroutines.DataPoints[0].Value < 1 {
+		t.Fatalf("unexpected goroutines gauge %+v", seen["process.runtime.go.goroutines"])
+	}
+	if heap, ok := seen["process.runtime.go.heap.alloc"].(metricdata.Gauge[int64]); !ok || heap.DataPoints[0].Value <= 0 {
+		t.Fatalf("unexpected heap gauge %+v", seen["process.runtime.go.heap.alloc"])
+	}
+	cycles, ok := seen["process.runtime.go.gc.cycles"].(metricdata.Sum[int64])
+	if !ok || cycles.DataPoints[0].Value < 2 {
+		t.Fatalf("expected the forced GC cycles counted, got %+v", seen["process.runtime.go.gc.cycles"])
+	}
+	pauses, ok := seen[runtimeGCPauseMetric].(metricdata.Histogram[float64])
+	if !ok || pauses.DataPoints[0].Count != uint64(cycles.DataPoints[0].Value) {
+		t.Fatalf("expected one pause per cycle, got %+v", seen[runtimeGCPauseMetric])
+	}
+}
@@ -19,6 +19,8 @@ const (
 	AttrMessageType = attribute.Key("message.type")
 	// AttrCurrency stores ISO-like currency codes for balance metrics.
 	AttrCurrency = attribute.Key("currency")
+	// AttrSubAccount names the provider sub-account holding a balance; empty for the primary account.
+	AttrSubAccount = attribute.Key("sub_account")
 	// AttrOrderSide labels order telemetry with Buy/Sell intent.
 	AttrOrderSide = attribute.Key("order.side")
 	// AttrOrderType distinguishes limit vs market orders in execution metrics.
@@ -27,6 +29,12 @@ const (
 	AttrOrderTIF = attribute.Key("order.tif")
 	// AttrOrderState captures the execution lifecycle state reported (ACK, FILLED, REJECTED, ...).
 	AttrOrderState = attribute.Key("order.state")
+	// AttrClientOrderID joins order submission spans with the execution reports of the order.
+	AttrClientOrderID = attribute.Key("order.client_id")
+	// AttrEventID identifies the bus event a span handles.
+	AttrEventID = attribute.Key("event.id")
+	// AttrLambdaID identifies the strategy instance handling an event or submitting an order.
+	AttrLambdaID = attribute.Key("lambda.id")
 	// AttrPoolName labels pooled object metrics by logical pool (Event, OrderRequest, ...).
 	AttrPoolName = attribute.Key("pool.name")
 	// AttrObjectType captures the Go type being managed inside a pool.
@@ -47,6 +55,8 @@ const (
 	AttrStatus = attribute.Key("status")
 	// AttrConnectionState labels connection lifecycle signals (connected, reconnecting, ...).
 	AttrConnectionState = attribute.Key("connection.state")
+	// AttrConnectionIndex identifies one of several websocket connections sharing a stream kind.
+	AttrConnectionIndex = attribute.Key("connection.index")
 )
 
 // Event type values
@@ -4,6 +4,7 @@ package telemetry
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -11,10 +12,12 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/metric"
 	instrumentationsdk "go.opentelemetry.io/otel/sdk/instrumentation"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.32.0"
 )
 
@@ -30,10 +33,13 @@ var (
 
 // Config defines OpenTelemetry configuration parameters.
 type Config struct {
-	Enabled          bool
-	OTLPEndpoint     string
-	OTLPInsecure     bool
-	EnableMetrics    bool
+	Enabled       bool
+	OTLPEndpoint  string
+	OTLPInsecure  bool
+	EnableMetrics bool
+	// EnableTracing exports strategy execution traces for a TraceSampleRatio share of events.
+	EnableTracing    bool
+	TraceSampleRatio float64
 	MetricInterval   time.Duration
 	ShutdownTimeout  time.Duration
 	ConsoleExporter  bool
@@ -65,6 +71,8 @@ func DefaultConfig() Config {
 		OTLPEndpoint:     endpoint,
 		OTLPInsecure:     os.Getenv("OTEL_EXPORTER_OTLP_INSECURE") == "true",
 		EnableMetrics:    os.Getenv("OTEL_METRICS_ENABLED") != "false", // Default: true
+		EnableTracing:    false,
+		TraceSampleRatio: 0,
 		MetricInterval:   30 * time.Second,
 		ShutdownTimeout:  5 * time.Second,
 		ConsoleExporter:  os.Getenv("OTEL_CONSOLE_EXPORTER") == "true",
@@ -75,10 +83,11 @@ func DefaultConfig() Config {
 	}
 }
 
-// Provider manages OpenTelemetry meter provider (metrics only).
+// Provider manages the OpenTelemetry meter and tracer providers.
 type Provider struct {
-	meterProvider *sdkmetric.MeterProvider
-	config        Config
+	meterProvider  *sdkmetric.MeterProvider
+	tracerProvider *sdktrace.TracerProvider
+	config         Config
 }
 
 // NewProvider initializes a new telemetry provider with the given configuration.
@@ -88,8 +97,9 @@ func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
 
 	if !cfg.Enabled {
 		return &Provider{
-			meterProvider: nil,
-			config:        cfg,
+			meterProvider:  nil,
+			tracerProvider: nil,
+			config:         cfg,
 		}, nil
 	}
 
@@ -107,14 +117,29 @@ func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
 		}
 		otel.SetMeterProvider(mp)
 	}
+
+	var tp *sdktrace.TracerProvider
+	if cfg.EnableTracing {
+		tp, err = newTracerProvider(ctx, res, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("create tracer provider: %w", err)
+		}
+		otel.SetTracerProvider(tp)
+	}
 	return &Provider{
-		meterProvider: mp,
-		config:        cfg,
+		meterProvider:  mp,
+		tracerProvider: tp,
+		config:         cfg,
 	}, nil
 }
 
-// Shutdown gracefully shuts down the telemetry provider.
+// Shutdown gracefully shuts down the telemetry provider, flushing pending spans.
 func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.tracerProvider != nil {
+		if tErr := p.tracerProvider.Shutdown(ctx); tErr != nil {
+			return fmt.Errorf("shutdown tracer: %w", tErr)
+		}
+	}
 	if p.meterProvider == nil {
 		return nil
 	}
@@ -186,6 +211,29 @@ func newMeterProvider(ctx context.Context, res *resource.Resource, cfg Config) (
 	return mp, nil
 }
 
+// newTracerProvider exports spans over OTLP/HTTP. Root spans are sampled by trace ID at the
+// configured ratio; child spans follow their parent's decision so a sampled trace stays whole.
+func newTracerProvider(ctx context.Context, res *resource.Resource, cfg Config) (*sdktrace.TracerProvider, error) {
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(stripScheme(cfg.OTLPEndpoint)),
+	}
+	if cfg.OTLPInsecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create trace exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TraceSampleRatio))),
+	)
+	return tp, nil
+}
+
 // createHistogramViews configures explicit histogram buckets optimized for observed latency patterns.
 func createHistogramViews() []sdkmetric.View {
 	return []sdkmetric.View{
@@ -345,11 +393,62 @@ func createHistogramViews() []sdkmetric.View {
 				ExemplarReservoirProviderSelector: nil,
 			},
 		),
+		// GC pause: 0.01ms - 100ms (stop-the-world pauses are usually well under a millisecond)
+		sdkmetric.NewView(
+			sdkmetric.Instrument{
+				Name:        runtimeGCPauseMetric,
+				Description: runtimeGCPauseDescription,
+				Kind:        sdkmetric.InstrumentKindHistogram,
+				Unit:        "ms",
+				Scope: instrumentationsdk.Scope{
+					Name:       "",
+					Version:    "",
+					SchemaURL:  "",
+					Attributes: attribute.Set{},
+				},
+			},
+			sdkmetric.Stream{
+				Name:        "",
+				Description: "",
+				Unit:        "",
+				Aggregation: sdkmetric.AggregationExplicitBucketHistogram{
+					Boundaries: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 100},
+					NoMinMax:   false,
+				},
+				AttributeFilter:                   nil,
+				ExemplarReservoirProviderSelector: nil,
+			},
+		),
 	}
 }
 
 // stripScheme removes http:// or https:// prefix from endpoint URL.
 // OTLP HTTP exporters expect just host:port, not a full URL with scheme.
+// ProbeEndpoint checks that the OTLP endpoint accepts TCP connections. NewProvider never dials the
+// collector, so startup uses the probe to wait for it.
+func ProbeEndpoint(ctx context.Context, endpoint string) error {
+	hostport := stripScheme(strings.TrimSpace(endpoint))
+	if idx := strings.Index(hostport, "/"); idx >= 0 {
+		hostport = hostport[:idx]
+	}
+	if hostport == "" {
+		return fmt.Errorf("otlp endpoint required")
+	}
+	if _, _, err := net.SplitHostPort(hostport); err != nil {
+		hostport = net.JoinHostPort(hostport, defaultOTLPPort)
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", hostport)
+	if err != nil {
+		return fmt.Errorf("dial otlp endpoint %s: %w", hostport, err)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+// defaultOTLPPort is the OTLP/HTTP port assumed when the endpoint names none.
+const defaultOTLPPort = "4318"
+
 func stripScheme(endpoint string) string {
 	endpoint = strings.TrimPrefix(endpoint, "http://")
 	endpoint = strings.TrimPrefix(endpoint, "https://")
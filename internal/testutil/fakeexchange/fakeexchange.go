@@ -0,0 +1,612 @@
+// Package fakeexchange serves a Binance-compatible REST and websocket API for integration tests.
+//
+// The server implements the subset of endpoints the binance adapter uses: exchange info,
+// depth snapshots, listen keys, account balances, order entry, lookup and cancellation, account
+// trades, raw market streams under /ws/ws and user data streams under /ws/{listenKey}. Point the adapter at it with
+// the api_base_url and websocket_base_url provider settings.
+package fakeexchange
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	json "github.com/goccy/go-json"
+)
+
+// Symbol describes an instrument listed by the fake exchange.
+type Symbol struct {
+	Base     string
+	Quote    string
+	TickSize string
+	StepSize string
+}
+
+func (s Symbol) rest() string {
+	return strings.ToUpper(strings.TrimSpace(s.Base) + strings.TrimSpace(s.Quote))
+}
+
+// Order is an order accepted by the fake exchange.
+type Order struct {
+	OrderID       int64
+	ClientOrderID string
+	Symbol        string
+	Side          string
+	Type          string
+	Quantity      string
+	Price         string
+	Status        string
+	// TradeID identifies the fill of a filled order; zero while unfilled.
+	TradeID    int64
+	ReceivedAt time.Time
+}
+
+// Server is a running fake exchange.
+type Server struct {
+	http *httptest.Server
+
+	mu          sync.Mutex
+	symbols     map[string]Symbol
+	balances    map[string]string
+	orders      []Order
+	nextOrderID int64
+	nextTradeID int64
+	nextKey     int
+	fillOrders  bool
+	market      map[*marketConn]struct{}
+	users       map[*websocket.Conn]struct{}
+}
+
+type marketConn struct {
+	conn    *websocket.Conn
+	mu      sync.Mutex
+	streams map[string]struct{}
+}
+
+// New starts a fake exchange listing the provided symbols. Orders are filled immediately.
+func New(symbols ...Symbol) *Server {
+	s := &Server{
+		http:        nil,
+		mu:          sync.Mutex{},
+		symbols:     make(map[string]Symbol, len(symbols)),
+		balances:    map[string]string{"USDT": "100000", "BTC": "10"},
+		orders:      nil,
+		nextOrderID: 1000,
+		nextTradeID: 1,
+		nextKey:     0,
+		fillOrders:  true,
+		market:      make(map[*marketConn]struct{}),
+		users:       make(map[*websocket.Conn]struct{}),
+	}
+	for _, sym := range symbols {
+		if sym.TickSize == "" {
+			sym.TickSize = "0.01"
+		}
+		if sym.StepSize == "" {
+			sym.StepSize = "0.0001"
+		}
+		s.symbols[sym.rest()] = sym
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/exchangeInfo", s.handleExchangeInfo)
+	mux.HandleFunc("/api/v3/depth", s.handleDepth)
+	mux.HandleFunc("/api/v3/userDataStream", s.handleListenKey)
+	mux.HandleFunc("/api/v3/account", s.handleAccount)
+	mux.HandleFunc("/api/v3/order", s.handleOrder)
+	mux.HandleFunc("/api/v3/openOrders", s.handleOpenOrders)
+	mux.HandleFunc("/api/v3/myTrades", s.handleMyTrades)
+	// The adapter appends /ws to the websocket base URL for market streams.
+	mux.HandleFunc("/ws/ws", s.handleMarketStream)
+	mux.HandleFunc("/ws/", s.handleUserStream)
+	s.http = httptest.NewServer(mux)
+	return s
+}
+
+// RESTURL returns the base URL for the api_base_url provider setting.
+func (s *Server) RESTURL() string {
+	return s.http.URL
+}
+
+// WebsocketURL returns the base URL for the websocket_base_url provider setting.
+func (s *Server) WebsocketURL() string {
+	return "ws" + strings.TrimPrefix(s.http.URL, "http") + "/ws"
+}
+
+// SetFillOrders controls whether new orders are filled immediately or left resting.
+func (s *Server) SetFillOrders(fill bool) {
+	s.mu.Lock()
+	s.fillOrders = fill
+	s.mu.Unlock()
+}
+
+// Orders returns the orders received so far in arrival order.
+func (s *Server) Orders() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Order, len(s.orders))
+	copy(out, s.orders)
+	return out
+}
+
+// SetOrderStatus changes the status of a received order without notifying user data streams, as
+// happens to orders that fill or are cancelled while the gateway is down. It reports whether the
+// order was found.
+func (s *Server) SetOrderStatus(clientOrderID, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.orders {
+		if s.orders[i].ClientOrderID == clientOrderID {
+			s.orders[i].Status = status
+			if status == "FILLED" && s.orders[i].TradeID == 0 {
+				s.nextTradeID++
+				s.orders[i].TradeID = s.nextTradeID
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribed reports whether any market stream connection subscribes to stream (e.g. btcusdt@trade).
+func (s *Server) Subscribed(stream string) bool {
+	stream = strings.ToLower(strings.TrimSpace(stream))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for mc := range s.market {
+		mc.mu.Lock()
+		_, ok := mc.streams[stream]
+		mc.mu.Unlock()
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// PublishTrade pushes a trade to the connections subscribed to the symbol's trade stream and
+// returns how many received it.
+func (s *Server) PublishTrade(symbol, price, quantity string) int {
+	s.mu.Lock()
+	s.nextTradeID++
+	tradeID := s.nextTradeID
+	conns := s.marketConnsLocked()
+	s.mu.Unlock()
+
+	rest := strings.ToUpper(strings.TrimSpace(symbol))
+	now := time.Now().UnixMilli()
+	payload := map[string]any{
+		"e": "trade",
+		"E": now,
+		"s": rest,
+		"t": tradeID,
+		"p": price,
+		"q": quantity,
+		"T": now,
+		"m": false,
+	}
+	return s.broadcast(conns, strings.ToLower(rest)+"@trade", payload)
+}
+
+// DropConnections closes every market and user data websocket, as a venue restart would.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	conns := s.marketConnsLocked()
+	users := make([]*websocket.Conn, 0, len(s.users))
+	for conn := range s.users {
+		users = append(users, conn)
+	}
+	s.mu.Unlock()
+	for _, mc := range conns {
+		_ = mc.conn.Close(websocket.StatusGoingAway, "fake exchange restart")
+	}
+	for _, conn := range users {
+		_ = conn.Close(websocket.StatusGoingAway, "fake exchange restart")
+	}
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.DropConnections()
+	s.http.Close()
+}
+
+func (s *Server) marketConnsLocked() []*marketConn {
+	out := make([]*marketConn, 0, len(s.market))
+	for mc := range s.market {
+		out = append(out, mc)
+	}
+	return out
+}
+
+func (s *Server) broadcast(conns []*marketConn, stream string, payload any) int {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0
+	}
+	delivered := 0
+	for _, mc := range conns {
+		mc.mu.Lock()
+		_, ok := mc.streams[stream]
+		mc.mu.Unlock()
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		if err := mc.conn.Write(ctx, websocket.MessageText, data); err == nil {
+			delivered++
+		}
+		cancel()
+	}
+	return delivered
+}
+
+func (s *Server) handleExchangeInfo(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	symbols := make([]map[string]any, 0, len(s.symbols))
+	for rest, sym := range s.symbols {
+		symbols = append(symbols, map[string]any{
+			"symbol":              rest,
+			"status":              "TRADING",
+			"baseAsset":           sym.Base,
+			"quoteAsset":          sym.Quote,
+			"baseAssetPrecision":  8,
+			"quotePrecision":      8,
+			"quoteAssetPrecision": 8,
+			"orderTypes":          []string{"LIMIT", "MARKET"},
+			"filters": []map[string]any{
+				{"filterType": "PRICE_FILTER", "minPrice": sym.TickSize, "maxPrice": "1000000", "tickSize": sym.TickSize},
+				{"filterType": "LOT_SIZE", "minQty": sym.StepSize, "maxQty": "9000", "stepSize": sym.StepSize},
+				{"filterType": "NOTIONAL", "minNotional": "1"},
+			},
+			"isSpotTradingAllowed": true,
+		})
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"symbols": symbols})
+}
+
+func (s *Server) handleDepth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"lastUpdateId": 1,
+		"bids":         [][]string{{"99.00", "1.0"}},
+		"asks":         [][]string{{"101.00", "1.0"}},
+	})
+}
+
+func (s *Server) handleListenKey(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.mu.Lock()
+		s.nextKey++
+		key := fmt.Sprintf("fake-listen-key-%d", s.nextKey)
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]string{"listenKey": key})
+	default:
+		writeJSON(w, http.StatusOK, map[string]any{})
+	}
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	balances := make([]map[string]string, 0, len(s.balances))
+	for asset, free := range s.balances {
+		balances = append(balances, map[string]string{"asset": asset, "free": free, "locked": "0"})
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"balances": balances})
+}
+
+func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": -1102, "msg": err.Error()})
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		s.placeOrder(w, r)
+	case http.MethodGet:
+		s.queryOrder(w, r)
+	case http.MethodDelete:
+		s.cancelOrder(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) placeOrder(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.ToUpper(r.Form.Get("symbol"))
+	s.mu.Lock()
+	if _, ok := s.symbols[symbol]; !ok {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": -1121, "msg": "Invalid symbol."})
+		return
+	}
+	s.nextOrderID++
+	order := Order{
+		OrderID:       s.nextOrderID,
+		ClientOrderID: r.Form.Get("newClientOrderId"),
+		Symbol:        symbol,
+		Side:          strings.ToUpper(r.Form.Get("side")),
+		Type:          strings.ToUpper(r.Form.Get("type")),
+		Quantity:      r.Form.Get("quantity"),
+		Price:         r.Form.Get("price"),
+		Status:        "NEW",
+		TradeID:       0,
+		ReceivedAt:    time.Now().UTC(),
+	}
+	if order.Price == "" {
+		order.Price = "100"
+	}
+	fill := s.fillOrders
+	if fill {
+		order.Status = "FILLED"
+		s.nextTradeID++
+		order.TradeID = s.nextTradeID
+	}
+	s.orders = append(s.orders, order)
+	s.mu.Unlock()
+
+	now := time.Now().UnixMilli()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"symbol":              order.Symbol,
+		"orderId":             order.OrderID,
+		"clientOrderId":       order.ClientOrderID,
+		"transactTime":        now,
+		"price":               order.Price,
+		"origQty":             order.Quantity,
+		"executedQty":         "0",
+		"cummulativeQuoteQty": "0",
+		"status":              "NEW",
+		"timeInForce":         r.Form.Get("timeInForce"),
+		"type":                order.Type,
+		"side":                order.Side,
+	})
+	if fill {
+		go s.pushExecution(order)
+	}
+}
+
+func (s *Server) cancelOrder(w http.ResponseWriter, r *http.Request) {
+	clientOrderID := r.Form.Get("origClientOrderId")
+	s.mu.Lock()
+	var cancelled *Order
+	for i := range s.orders {
+		if s.orders[i].ClientOrderID == clientOrderID && s.orders[i].Status == "NEW" {
+			s.orders[i].Status = "CANCELED"
+			order := s.orders[i]
+			cancelled = &order
+			break
+		}
+	}
+	s.mu.Unlock()
+	if cancelled == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": -2011, "msg": "Unknown order sent."})
+		return
+	}
+	writeJSON(w, http.StatusOK, orderPayload(*cancelled))
+}
+
+func (s *Server) queryOrder(w http.ResponseWriter, r *http.Request) {
+	clientOrderID := r.Form.Get("origClientOrderId")
+	symbol := strings.ToUpper(r.Form.Get("symbol"))
+	s.mu.Lock()
+	var found *Order
+	for i := range s.orders {
+		if s.orders[i].ClientOrderID == clientOrderID && s.orders[i].Symbol == symbol {
+			order := s.orders[i]
+			found = &order
+			break
+		}
+	}
+	s.mu.Unlock()
+	if found == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": -2013, "msg": "Order does not exist."})
+		return
+	}
+	writeJSON(w, http.StatusOK, orderPayload(*found))
+}
+
+func (s *Server) handleOpenOrders(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": -1102, "msg": err.Error()})
+		return
+	}
+	symbol := strings.ToUpper(r.Form.Get("symbol"))
+	s.mu.Lock()
+	out := make([]map[string]any, 0)
+	for i := range s.orders {
+		if s.orders[i].Status != "NEW" || (symbol != "" && s.orders[i].Symbol != symbol) {
+			continue
+		}
+		if r.Method == http.MethodDelete {
+			s.orders[i].Status = "CANCELED"
+		}
+		out = append(out, orderPayload(s.orders[i]))
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleMyTrades lists the fills of filled orders, optionally narrowed to one orderId.
+func (s *Server) handleMyTrades(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"code": -1102, "msg": err.Error()})
+		return
+	}
+	symbol := strings.ToUpper(r.Form.Get("symbol"))
+	orderID, _ := strconv.ParseInt(r.Form.Get("orderId"), 10, 64)
+	s.mu.Lock()
+	out := make([]map[string]any, 0)
+	for _, order := range s.orders {
+		if order.TradeID == 0 || order.Symbol != symbol || (orderID != 0 && order.OrderID != orderID) {
+			continue
+		}
+		out = append(out, map[string]any{
+			"id":              order.TradeID,
+			"orderId":         order.OrderID,
+			"price":           order.Price,
+			"qty":             order.Quantity,
+			"quoteQty":        quoteQuantity(order),
+			"commission":      "0",
+			"commissionAsset": s.symbols[order.Symbol].Quote,
+			"time":            time.Now().UnixMilli(),
+		})
+	}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, out)
+}
+
+// pushExecution reports a full fill of order on every user data stream.
+func (s *Server) pushExecution(order Order) {
+	now := time.Now().UnixMilli()
+	quote := quoteQuantity(order)
+	data, err := json.Marshal(map[string]any{
+		"e": "executionReport",
+		"E": now,
+		"s": order.Symbol,
+		"c": order.ClientOrderID,
+		"S": order.Side,
+		"o": order.Type,
+		"f": "GTC",
+		"q": order.Quantity,
+		"p": order.Price,
+		"X": "FILLED",
+		"i": order.OrderID,
+		"t": order.TradeID,
+		"l": order.Quantity,
+		"z": order.Quantity,
+		"L": order.Price,
+		"n": "0",
+		"T": now,
+		"Z": quote,
+	})
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	users := make([]*websocket.Conn, 0, len(s.users))
+	for conn := range s.users {
+		users = append(users, conn)
+	}
+	s.mu.Unlock()
+	for _, conn := range users {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_ = conn.Write(ctx, websocket.MessageText, data)
+		cancel()
+	}
+}
+
+func (s *Server) handleMarketStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	mc := &marketConn{conn: conn, mu: sync.Mutex{}, streams: make(map[string]struct{})}
+	s.mu.Lock()
+	s.market[mc] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.market, mc)
+		s.mu.Unlock()
+		_ = conn.CloseNow()
+	}()
+
+	for {
+		_, data, err := conn.Read(r.Context())
+		if err != nil {
+			return
+		}
+		var req struct {
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+			ID     uint64   `json:"id"`
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			continue
+		}
+		mc.mu.Lock()
+		for _, stream := range req.Params {
+			stream = strings.ToLower(strings.TrimSpace(stream))
+			switch strings.ToUpper(req.Method) {
+			case "SUBSCRIBE":
+				mc.streams[stream] = struct{}{}
+			case "UNSUBSCRIBE":
+				delete(mc.streams, stream)
+			}
+		}
+		mc.mu.Unlock()
+		ack, _ := json.Marshal(map[string]any{"result": nil, "id": req.ID})
+		if err := conn.Write(r.Context(), websocket.MessageText, ack); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handleUserStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.users[conn] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.users, conn)
+		s.mu.Unlock()
+		_ = conn.CloseNow()
+	}()
+	for {
+		if _, _, err := conn.Read(r.Context()); err != nil {
+			return
+		}
+	}
+}
+
+func orderPayload(order Order) map[string]any {
+	executed, quote := "0", "0"
+	if order.Status == "FILLED" {
+		executed = order.Quantity
+		quote = quoteQuantity(order)
+	}
+	return map[string]any{
+		"symbol":              order.Symbol,
+		"orderId":             order.OrderID,
+		"clientOrderId":       order.ClientOrderID,
+		"price":               order.Price,
+		"origQty":             order.Quantity,
+		"executedQty":         executed,
+		"cummulativeQuoteQty": quote,
+		"status":              order.Status,
+		"type":                order.Type,
+		"side":                order.Side,
+		"updateTime":          time.Now().UnixMilli(),
+	}
+}
+
+// quoteQuantity is the quote value of a full fill of order.
+func quoteQuantity(order Order) string {
+	qty, err := strconv.ParseFloat(order.Quantity, 64)
+	if err != nil {
+		return order.Quantity
+	}
+	price, err := strconv.ParseFloat(order.Price, 64)
+	if err != nil {
+		return order.Quantity
+	}
+	return strconv.FormatFloat(qty*price, 'f', -1, 64)
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,180 @@
+package fakeexchange_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coachpo/meltica/internal/app/dispatcher"
+	"github.com/coachpo/meltica/internal/domain/schema"
+	"github.com/coachpo/meltica/internal/infra/adapters/binance"
+	"github.com/coachpo/meltica/internal/infra/pool"
+	"github.com/coachpo/meltica/internal/testutil/fakeexchange"
+)
+
+func TestBinanceProviderAgainstFakeExchange(t *testing.T) {
+	fake := fakeexchange.New(fakeexchange.Symbol{Base: "BTC", Quote: "USDT", TickSize: "0.01", StepSize: "0.0001"})
+	t.Cleanup(fake.Close)
+
+	pm := pool.NewPoolManager()
+	if err := pm.RegisterPool("Event", 64, 0, func() any { return &schema.Event{} }); err != nil {
+		t.Fatalf("register pool: %v", err)
+	}
+	prov := binance.NewProvider(binance.Options{
+		Pools: pm,
+		Config: binance.Config{
+			Name:             "binance",
+			APIKey:           "key",
+			APISecret:        "secret",
+			APIBaseURL:       fake.RESTURL(),
+			WebsocketBaseURL: fake.WebsocketURL(),
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := prov.Start(ctx); err != nil {
+		cancel()
+		t.Fatalf("start provider: %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		for evt := range prov.Events() {
+			pm.ReturnEventInst(evt)
+		}
+		_ = pm.Shutdown(context.Background())
+	})
+
+	route := dispatcher.Route{
+		Provider: "binance",
+		Type:     schema.RouteTypeTrade,
+		Filters:  []dispatcher.FilterRule{{Field: "instrument", Op: "eq", Value: "BTC-USDT"}},
+	}
+	if err := prov.SubscribeRoute(route); err != nil {
+		t.Fatalf("subscribe trades: %v", err)
+	}
+	waitFor(t, "trade subscription", func() bool { return fake.Subscribed("btcusdt@trade") })
+	if delivered := fake.PublishTrade("BTCUSDT", "100.5", "0.25"); delivered == 0 {
+		t.Fatalf("trade not delivered to any connection")
+	}
+	trade := nextEvent(t, pm, prov, schema.EventTypeTrade)
+	if trade.Symbol != "BTC-USDT" {
+		t.Fatalf("trade symbol = %s, want BTC-USDT", trade.Symbol)
+	}
+	pm.ReturnEventInst(trade)
+
+	price := "100"
+	err := prov.SubmitOrder(ctx, schema.OrderRequest{
+		ClientOrderID: "e2e-1",
+		Provider:      "binance",
+		Symbol:        "BTC-USDT",
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      "0.1",
+		TIF:           "GTC",
+		Timestamp:     time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("submit order: %v", err)
+	}
+	orders := fake.Orders()
+	if len(orders) != 1 || orders[0].ClientOrderID != "e2e-1" || orders[0].Symbol != "BTCUSDT" {
+		t.Fatalf("unexpected orders on fake exchange: %+v", orders)
+	}
+	report := nextEvent(t, pm, prov, schema.EventTypeExecReport)
+	payload, ok := report.Payload.(schema.ExecReportPayload)
+	if ok && payload.State == schema.ExecReportStateACK {
+		// The REST acknowledgement may arrive before the streamed fill; once the fill was
+		// published a late acknowledgement is dropped.
+		pm.ReturnEventInst(report)
+		report = nextEvent(t, pm, prov, schema.EventTypeExecReport)
+		payload, ok = report.Payload.(schema.ExecReportPayload)
+	}
+	if !ok {
+		t.Fatalf("expected ExecReportPayload, got %T", report.Payload)
+	}
+	if payload.ClientOrderID != "e2e-1" || payload.State != schema.ExecReportStateFILLED {
+		t.Fatalf("unexpected exec report %+v", payload)
+	}
+	pm.ReturnEventInst(report)
+
+	queried, err := prov.QueryOrder(ctx, "BTC-USDT", "", "e2e-1")
+	if err != nil {
+		t.Fatalf("query order: %v", err)
+	}
+	if queried.State != schema.ExecReportStateFILLED || queried.FilledQuantity != "0.1" || queried.AvgFillPrice == "" {
+		t.Fatalf("unexpected queried order %+v", queried)
+	}
+	if !fake.SetOrderStatus("e2e-1", "CANCELED") {
+		t.Fatal("expected order to be found on the fake exchange")
+	}
+	if queried, err = prov.QueryOrder(ctx, "BTC-USDT", "", "e2e-1"); err != nil || queried.State != schema.ExecReportStateCANCELLED {
+		t.Fatalf("expected cancelled order, got %+v (%v)", queried, err)
+	}
+	if _, err := prov.QueryOrder(ctx, "BTC-USDT", "", "missing"); err == nil {
+		t.Fatal("expected unknown order to fail")
+	}
+
+	fake.SetFillOrders(false)
+	if err := prov.SubmitOrder(ctx, schema.OrderRequest{
+		ClientOrderID: "e2e-2",
+		Provider:      "binance",
+		Symbol:        "BTC-USDT",
+		Side:          schema.TradeSideBuy,
+		OrderType:     schema.OrderTypeLimit,
+		Price:         &price,
+		Quantity:      "0.2",
+		TIF:           "GTC",
+		Timestamp:     time.Now(),
+	}); err != nil {
+		t.Fatalf("submit resting order: %v", err)
+	}
+	ack := nextEvent(t, pm, prov, schema.EventTypeExecReport)
+	if payload, ok := ack.Payload.(schema.ExecReportPayload); !ok || payload.ClientOrderID != "e2e-2" || payload.State != schema.ExecReportStateACK {
+		t.Fatalf("expected acknowledgement, got %+v", ack.Payload)
+	}
+	pm.ReturnEventInst(ack)
+	// The order fills while the user data stream is down; the fill is backfilled on reconnect.
+	fake.SetOrderStatus("e2e-2", "FILLED")
+
+	fake.DropConnections()
+	backfilled := nextEvent(t, pm, prov, schema.EventTypeExecReport)
+	payload, ok = backfilled.Payload.(schema.ExecReportPayload)
+	if !ok || payload.ClientOrderID != "e2e-2" || payload.State != schema.ExecReportStateFILLED || payload.FilledQuantity != "0.2" || payload.CommissionAsset != "USDT" {
+		t.Fatalf("expected backfilled fill, got %+v", backfilled.Payload)
+	}
+	pm.ReturnEventInst(backfilled)
+	waitFor(t, "trade resubscription", func() bool { return fake.Subscribed("btcusdt@trade") })
+	waitFor(t, "trade after reconnect", func() bool { return fake.PublishTrade("BTCUSDT", "101", "0.1") > 0 })
+	pm.ReturnEventInst(nextEvent(t, pm, prov, schema.EventTypeTrade))
+}
+
+func nextEvent(t *testing.T, pm *pool.PoolManager, prov *binance.Provider, typ schema.EventType) *schema.Event {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case evt, ok := <-prov.Events():
+			if !ok {
+				t.Fatalf("event stream closed waiting for %s", typ)
+			}
+			if evt != nil && evt.Type == typ {
+				return evt
+			}
+			pm.ReturnEventInst(evt)
+		case <-deadline:
+			t.Fatalf("timed out waiting for %s event", typ)
+		}
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
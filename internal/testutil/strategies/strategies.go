@@ -228,6 +228,26 @@ func WriteStubStrategies(t testing.TB) string {
 	return dir
 }
 
+// Module describes a strategy module source written by WriteModules.
+type Module struct {
+	Name   string
+	Tag    string
+	Source string
+}
+
+// WriteModules creates a temporary strategy directory holding the provided
+// modules and a registry.json that resolves each module's latest tag.
+func WriteModules(t testing.TB, modules ...Module) string {
+	t.Helper()
+	dir := t.TempDir()
+	infos := make([]moduleInfo, 0, len(modules))
+	for _, module := range modules {
+		infos = append(infos, writeStubModule(t, dir, stubModule{name: module.Name, tag: module.Tag, source: module.Source}))
+	}
+	writeRegistry(t, dir, infos)
+	return dir
+}
+
 type moduleInfo struct {
 	name    string
 	tag     string
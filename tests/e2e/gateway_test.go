@@ -0,0 +1,84 @@
+//go:build e2e
+
+package e2e_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/coachpo/meltica/internal/domain/orderstore"
+)
+
+const tradeStream = "btcusdt@trade"
+
+func TestOrderLifecyclePersisted(t *testing.T) {
+	gw := startGateway(t)
+	gw.createProvider("binance")
+	gw.startInstance("buyer", "binance")
+	gw.eventually("trade subscription", func() bool { return gw.exchange.Subscribed(tradeStream) })
+
+	gw.eventually("trade delivery", func() bool { return gw.exchange.PublishTrade("BTCUSDT", "100", "0.5") > 0 })
+	gw.eventually("order at exchange", func() bool { return len(gw.exchange.Orders()) > 0 })
+	order := gw.exchange.Orders()[0]
+	if !strings.HasPrefix(order.ClientOrderID, "buyer-") || order.Side != "BUY" || order.Symbol != "BTCUSDT" {
+		t.Fatalf("unexpected order at exchange: %+v", order)
+	}
+
+	gw.eventually("filled order via API", func() bool {
+		var resp struct {
+			Orders []orderstore.OrderRecord `json:"orders"`
+		}
+		gw.do(http.MethodGet, "/strategy/instances/buyer/orders", nil, http.StatusOK, &resp)
+		for _, rec := range resp.Orders {
+			if rec.ClientOrderID == order.ClientOrderID && rec.State == "FILLED" {
+				return true
+			}
+		}
+		return false
+	})
+	gw.eventually("execution via API", func() bool {
+		var resp struct {
+			Executions []orderstore.ExecutionRecord `json:"executions"`
+		}
+		gw.do(http.MethodGet, "/strategy/instances/buyer/executions", nil, http.StatusOK, &resp)
+		return len(resp.Executions) > 0
+	})
+
+	if n := gw.count(`SELECT COUNT(*) FROM orders WHERE client_order_id = $1 AND state = 'FILLED'`, order.ClientOrderID); n != 1 {
+		t.Fatalf("expected 1 filled order row, got %d", n)
+	}
+	gw.eventually("exec report in outbox", func() bool {
+		return gw.count(`SELECT COUNT(*) FROM events_outbox WHERE event_type = 'ExecReport'`) > 0
+	})
+}
+
+func TestProviderRestartDuringLiveStrategy(t *testing.T) {
+	gw := startGateway(t)
+	gw.createProvider("binance")
+	gw.startInstance("buyer", "binance")
+	gw.eventually("trade subscription", func() bool { return gw.exchange.Subscribed(tradeStream) })
+	gw.eventually("trade delivery", func() bool { return gw.exchange.PublishTrade("BTCUSDT", "100", "0.5") > 0 })
+	gw.eventually("first order", func() bool { return len(gw.exchange.Orders()) >= 1 })
+	before := len(gw.exchange.Orders())
+
+	gw.do(http.MethodPost, "/providers/binance/stop", nil, http.StatusOK, nil)
+	gw.eventually("trade unsubscription", func() bool { return !gw.exchange.Subscribed(tradeStream) })
+	gw.do(http.MethodPost, "/providers/binance/start", nil, http.StatusAccepted, nil)
+	gw.waitProviderRunning("binance")
+
+	// Cached routes are replayed on start, so the running instance keeps trading.
+	gw.eventually("trade resubscription", func() bool { return gw.exchange.Subscribed(tradeStream) })
+	gw.eventually("trade delivery after restart", func() bool { return gw.exchange.PublishTrade("BTCUSDT", "100", "0.5") > 0 })
+	gw.eventually("order after restart", func() bool { return len(gw.exchange.Orders()) > before })
+
+	// A venue-side disconnect is recovered by the adapter reconnect policy.
+	before = len(gw.exchange.Orders())
+	gw.exchange.DropConnections()
+	gw.eventually("trade delivery after reconnect", func() bool { return gw.exchange.PublishTrade("BTCUSDT", "100", "0.5") > 0 })
+	gw.eventually("order after reconnect", func() bool { return len(gw.exchange.Orders()) > before })
+
+	if n := gw.count(`SELECT COUNT(*) FROM orders WHERE client_order_id LIKE 'buyer-%'`); n < before+1 {
+		t.Fatalf("expected at least %d persisted orders, got %d", before+1, n)
+	}
+}
@@ -0,0 +1,437 @@
+//go:build e2e
+
+// Package e2e_test boots the full gateway binary against a Postgres container and a fake
+// exchange, then drives scenarios through the control API.
+package e2e_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/coachpo/meltica/internal/testutil/fakeexchange"
+	strategiestest "github.com/coachpo/meltica/internal/testutil/strategies"
+)
+
+const (
+	pgUser     = "postgres"
+	pgPassword = "secret"
+
+	readyTimeout = 60 * time.Second
+	waitTimeout  = 20 * time.Second
+)
+
+var (
+	pgContainer testcontainers.Container
+	adminPool   *pgxpool.Pool
+	pgHost      string
+	pgPort      string
+	gatewayBin  string
+	setupErr    error
+)
+
+// buyerModuleSource buys 0.1 at 100 on every trade when dry_run is disabled.
+const buyerModuleSource = `module.exports = {
+  metadata: {
+    name: "e2e-buyer",
+    tag: "v1.0.0",
+    displayName: "E2E Buyer",
+    description: "Submits a limit buy for every trade it receives",
+    config: [
+      { name: "dry_run", type: "bool", required: false, "default": true }
+    ],
+    events: ["Trade", "ExecReport"]
+  },
+  create: function (env) {
+    var events = (env && env.metadata && env.metadata.events) || [];
+    function noop() {}
+    return {
+      onTrade: function () {
+        env.runtime.submitOrder("", "buy", "0.1", "100");
+      },
+      onOrderFilled: noop,
+      onOrderRejected: noop,
+      onOrderPartialFill: noop,
+      onOrderCancelled: noop,
+      onOrderAcknowledged: noop,
+      onOrderExpired: noop,
+      subscribedEvents: function () {
+        return events.slice ? events.slice() : [];
+      },
+      wantsCrossProviderEvents: function () { return false; }
+    };
+  }
+};
+`
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+	setupErr = setup(ctx)
+	if setupErr != nil {
+		fmt.Fprintf(os.Stderr, "e2e tests skipped: %v\n", setupErr)
+	}
+	exitCode := m.Run()
+
+	if adminPool != nil {
+		adminPool.Close()
+	}
+	if pgContainer != nil {
+		_ = pgContainer.Terminate(ctx)
+	}
+	if gatewayBin != "" {
+		_ = os.RemoveAll(filepath.Dir(gatewayBin))
+	}
+	os.Exit(exitCode)
+}
+
+func setup(ctx context.Context) error {
+	bin, err := buildGateway(ctx)
+	if err != nil {
+		return err
+	}
+	gatewayBin = bin
+
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:16-alpine",
+		Env:          map[string]string{"POSTGRES_PASSWORD": pgPassword, "POSTGRES_USER": pgUser, "POSTGRES_DB": "meltica"},
+		ExposedPorts: []string{"5432/tcp"},
+		WaitingFor:   wait.ForListeningPort("5432/tcp").WithStartupTimeout(60 * time.Second),
+	}
+	container, err := startContainer(ctx, req)
+	if err != nil {
+		return fmt.Errorf("start postgres container: %w", err)
+	}
+	pgContainer = container
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		return fmt.Errorf("container host: %w", err)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		return fmt.Errorf("container port: %w", err)
+	}
+	pgHost, pgPort = host, port.Port()
+
+	pool, err := pgxpool.New(ctx, databaseDSN("meltica"))
+	if err != nil {
+		return fmt.Errorf("pgx pool: %w", err)
+	}
+	adminPool = pool
+	return nil
+}
+
+// startContainer converts the panic testcontainers raises when no Docker host is available
+// into an error so the suite skips instead of crashing.
+func startContainer(ctx context.Context, req testcontainers.ContainerRequest) (container testcontainers.Container, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("docker unavailable: %v", r)
+		}
+	}()
+	return testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+}
+
+func buildGateway(ctx context.Context) (string, error) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return "", fmt.Errorf("runtime caller lookup failed")
+	}
+	root := filepath.Clean(filepath.Join(filepath.Dir(file), "..", ".."))
+	dir, err := os.MkdirTemp("", "meltica-e2e-")
+	if err != nil {
+		return "", fmt.Errorf("create build dir: %w", err)
+	}
+	bin := filepath.Join(dir, "gateway")
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", bin, "./cmd/gateway")
+	cmd.Dir = root
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("build gateway: %w\n%s", err, out)
+	}
+	return bin, nil
+}
+
+func databaseDSN(name string) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", pgUser, pgPassword, pgHost, pgPort, name)
+}
+
+// gateway is a running gateway process with its own database and fake exchange.
+type gateway struct {
+	t        *testing.T
+	baseURL  string
+	db       *pgxpool.Pool
+	exchange *fakeexchange.Server
+	logs     *syncBuffer
+}
+
+// startGateway creates a fresh database, starts a fake exchange listing BTC-USDT and boots
+// the gateway binary against both. Everything is torn down when the test finishes.
+func startGateway(t *testing.T) *gateway {
+	t.Helper()
+	if setupErr != nil {
+		t.Skipf("e2e setup unavailable: %v", setupErr)
+	}
+	ctx := context.Background()
+
+	dbName := "e2e_" + strings.ToLower(strings.NewReplacer("/", "_", "-", "_").Replace(t.Name()))
+	if _, err := adminPool.Exec(ctx, "CREATE DATABASE "+dbName); err != nil {
+		t.Fatalf("create database %s: %v", dbName, err)
+	}
+	db, err := pgxpool.New(ctx, databaseDSN(dbName))
+	if err != nil {
+		t.Fatalf("connect database %s: %v", dbName, err)
+	}
+	t.Cleanup(db.Close)
+
+	exchange := fakeexchange.New(fakeexchange.Symbol{Base: "BTC", Quote: "USDT", TickSize: "0.01", StepSize: "0.0001"})
+	t.Cleanup(exchange.Close)
+
+	strategiesDir := strategiestest.WriteModules(t, strategiestest.Module{Name: "e2e-buyer", Tag: "v1.0.0", Source: buyerModuleSource})
+	addr := freeAddr(t)
+	configPath := filepath.Join(t.TempDir(), "app.yaml")
+	config := fmt.Sprintf(gatewayConfigTemplate, databaseDSN(dbName), addr, strategiesDir)
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	logs := &syncBuffer{mu: sync.Mutex{}, buf: bytes.Buffer{}}
+	cmd := exec.Command(gatewayBin, "-config", configPath)
+	cmd.Stdout = logs
+	cmd.Stderr = logs
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start gateway: %v", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		_ = cmd.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-exited:
+		case <-time.After(30 * time.Second):
+			_ = cmd.Process.Kill()
+			<-exited
+		}
+		if t.Failed() {
+			t.Logf("gateway logs:\n%s", logs.String())
+		}
+	})
+
+	gw := &gateway{t: t, baseURL: "http://" + addr, db: db, exchange: exchange, logs: logs}
+	deadline := time.Now().Add(readyTimeout)
+	for {
+		select {
+		case <-exited:
+			t.Fatalf("gateway exited during startup:\n%s", logs.String())
+		default:
+		}
+		resp, err := http.Get(gw.baseURL + "/providers")
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("gateway not ready after %s:\n%s", readyTimeout, logs.String())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return gw
+}
+
+const gatewayConfigTemplate = `environment: dev
+database:
+  dsn: %s
+  maxConns: 4
+  minConns: 1
+  runMigrations: true
+eventbus:
+  bufferSize: 1024
+  fanoutWorkers: 4
+pools:
+  event:
+    size: 1024
+    waitQueueSize: 1024
+  orderRequest:
+    size: 256
+    waitQueueSize: 256
+apiServer:
+  addr: %q
+telemetry:
+  otlpEndpoint: ""
+  serviceName: meltica-gateway-e2e
+  enableMetrics: false
+risk:
+  maxPositionSize: "1000"
+  maxNotionalValue: "1000000"
+  notionalCurrency: USDT
+  orderThrottle: 100
+  orderBurst: 100
+strategies:
+  directory: %q
+`
+
+// createProvider registers the binance adapter against the fake exchange and waits until it runs.
+func (g *gateway) createProvider(name string) {
+	g.t.Helper()
+	g.do(http.MethodPost, "/providers", map[string]any{
+		"name": name,
+		"adapter": map[string]any{
+			"identifier": "binance",
+			"config": map[string]any{
+				"api_key":            "e2e-key",
+				"api_secret":         "e2e-secret",
+				"api_base_url":       g.exchange.RESTURL(),
+				"websocket_base_url": g.exchange.WebsocketURL(),
+				"reconnect":          map[string]any{"initial_interval": "100ms", "max_interval": "1s"},
+			},
+		},
+		"enabled": true,
+	}, http.StatusAccepted, nil)
+	g.waitProviderRunning(name)
+}
+
+func (g *gateway) waitProviderRunning(name string) {
+	g.t.Helper()
+	g.eventually("provider "+name+" running", func() bool {
+		var detail struct {
+			Running bool `json:"running"`
+		}
+		status := g.request(http.MethodGet, "/providers/"+name, nil, &detail)
+		return status == http.StatusOK && detail.Running
+	})
+}
+
+// startInstance creates and starts a live e2e-buyer instance scoped to provider BTC-USDT.
+func (g *gateway) startInstance(id, provider string) {
+	g.t.Helper()
+	g.do(http.MethodPost, "/strategy/instances", map[string]any{
+		"id": id,
+		"strategy": map[string]any{
+			"identifier": "e2e-buyer",
+			"config":     map[string]any{"dry_run": false},
+		},
+		"scope": map[string]any{
+			provider: map[string]any{"symbols": []string{"BTC-USDT"}},
+		},
+	}, http.StatusCreated, nil)
+	g.do(http.MethodPost, "/strategy/instances/"+id+"/start", nil, http.StatusOK, nil)
+}
+
+// do issues a request and fails the test unless the response has the expected status.
+func (g *gateway) do(method, path string, body any, wantStatus int, out any) {
+	g.t.Helper()
+	if status := g.request(method, path, body, out); status != wantStatus {
+		g.t.Fatalf("%s %s: status %d, want %d", method, path, status, wantStatus)
+	}
+}
+
+func (g *gateway) request(method, path string, body any, out any) int {
+	g.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			g.t.Fatalf("marshal %s %s body: %v", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, g.baseURL+path, reader)
+	if err != nil {
+		g.t.Fatalf("build %s %s: %v", method, path, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		g.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		g.t.Fatalf("read %s %s response: %v", method, path, err)
+	}
+	if out != nil && resp.StatusCode < http.StatusMultipleChoices {
+		if err := json.Unmarshal(data, out); err != nil {
+			g.t.Fatalf("decode %s %s response: %v (%s)", method, path, err, data)
+		}
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		g.t.Logf("%s %s: %d %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return resp.StatusCode
+}
+
+// count runs a COUNT query against the gateway database.
+func (g *gateway) count(query string, args ...any) int {
+	g.t.Helper()
+	var n int
+	if err := g.db.QueryRow(context.Background(), query, args...).Scan(&n); err != nil {
+		g.t.Fatalf("query %q: %v", query, err)
+	}
+	return n
+}
+
+func (g *gateway) eventually(what string, cond func() bool) {
+	g.t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			g.t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("reserve port: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+	return addr
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}